@@ -0,0 +1,106 @@
+// Command radar-agent runs inside a remote cluster, keeps Radar's informer
+// caches there and streams resource changes, timeline events and topology to
+// a central Radar started with --agent-listen.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/skyhook-io/radar/internal/agent"
+	"github.com/skyhook-io/radar/internal/app"
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/timeline"
+	_ "k8s.io/client-go/plugin/pkg/client/auth" // Register all auth provider plugins (OIDC, GCP, Azure, etc.)
+	"k8s.io/klog/v2"
+)
+
+var (
+	version = "dev"
+)
+
+func main() {
+	kubeconfig := flag.String("kubeconfig", "", "Path to kubeconfig file (default: in-cluster config, then ~/.kube/config)")
+	serverAddr := flag.String("server", "", "Central Radar agent address, host:port (required)")
+	cluster := flag.String("cluster", "", "Name the cluster is shown under on the central server (required in-cluster; default: kubeconfig cluster name)")
+	insecure := flag.Bool("insecure", false, "Connect without TLS (for TLS-terminating proxies and local testing)")
+	caFile := flag.String("ca-file", "", "PEM CA bundle for verifying the central server, in addition to the system roots")
+	historyLimit := flag.Int("history-limit", 10000, "Maximum number of events to retain in the local timeline")
+	showVersion := flag.Bool("version", false, "Show version and exit")
+	flag.Parse()
+
+	if *showVersion {
+		fmt.Printf("radar-agent %s\n", version)
+		os.Exit(0)
+	}
+	if *serverAddr == "" {
+		log.Fatalf("--server is required")
+	}
+	token := os.Getenv("RADAR_AGENT_TOKEN")
+	if token == "" {
+		log.Fatalf("$RADAR_AGENT_TOKEN must be set to the token the central Radar was started with")
+	}
+
+	// Suppress verbose client-go logs (reflector errors, traces, etc.)
+	klog.InitFlags(nil)
+	_ = flag.Set("v", "0")
+	_ = flag.Set("logtostderr", "false")
+	_ = flag.Set("alsologtostderr", "false")
+	klog.SetOutput(os.Stderr)
+
+	log.Printf("Radar agent %s starting...", version)
+
+	cfg := app.AppConfig{Kubeconfig: *kubeconfig, HistoryLimit: *historyLimit, Version: version}
+	app.SetGlobals(cfg)
+	if err := app.InitializeK8s(cfg); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	name := *cluster
+	if name == "" {
+		name = k8s.GetClusterName()
+	}
+	if name == "" || name == "in-cluster" {
+		log.Fatalf("--cluster is required when running in-cluster, to tell clusters apart on the central server")
+	}
+
+	// Only the timeline is needed alongside the caches; Helm and traffic stay
+	// with the central server
+	storeCfg := app.BuildTimelineStoreConfig(cfg)
+	k8s.RegisterTimelineFuncs(timeline.ResetStore, func() error {
+		storeCfg.Context = k8s.GetContextName()
+		return timeline.ReinitStore(storeCfg)
+	})
+
+	if err := app.CheckClusterAccess(); err != nil {
+		log.Fatalf("%v", err)
+	}
+	if err := k8s.InitAllSubsystems(func(msg string) { log.Print(msg) }); err != nil {
+		log.Fatalf("Failed to initialize caches: %v", err)
+	}
+
+	a, err := agent.New(agent.Config{
+		ServerAddr: *serverAddr,
+		Cluster:    name,
+		Token:      token,
+		Insecure:   *insecure,
+		CAFile:     *caFile,
+		Version:    version,
+	})
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if err := a.Run(ctx); err != nil {
+		log.Fatalf("%v", err)
+	}
+	log.Println("Shutting down...")
+	k8s.ResetAllSubsystems()
+}
@@ -5,9 +5,14 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/skyhook-io/radar/internal/app"
+	"github.com/skyhook-io/radar/internal/instances"
 	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/server"
+	"github.com/skyhook-io/radar/internal/timeline"
+	"github.com/skyhook-io/radar/internal/traffic"
 	"github.com/skyhook-io/radar/internal/updater"
 	versionpkg "github.com/skyhook-io/radar/internal/version"
 	"github.com/wailsapp/wails/v2"
@@ -31,11 +36,30 @@ func main() {
 	showVersion := flag.Bool("version", false, "Show version and exit")
 	historyLimit := flag.Int("history-limit", 10000, "Maximum number of events to retain in timeline")
 	debugEvents := flag.Bool("debug-events", false, "Enable verbose event debugging")
+	configPath := flag.String("config", "", "File settings changed at runtime are saved to (default: ~/.radar/config.json)")
 	fakeInCluster := flag.Bool("fake-in-cluster", false, "Simulate in-cluster mode for testing")
 	disableHelmWrite := flag.Bool("disable-helm-write", false, "Simulate restricted Helm permissions")
+	demo := flag.Bool("demo", false, "Run against a synthetic in-memory cluster (no kubeconfig needed)")
+	maxEditSize := flag.Int64("max-edit-size", 2<<20, "Largest pod file, in bytes, the file editor opens or saves")
+	execIdleTimeout := flag.Duration("exec-idle-timeout", 0, "Close terminal sessions with no input or output for this long (0 = never)")
+	portForwardIdleTimeout := flag.Duration("port-forward-idle-timeout", 0, "Stop port forwards that haven't handled a new connection for this long (0 = never)")
 	timelineStorage := flag.String("timeline-storage", "memory", "Timeline storage backend: memory or sqlite")
 	timelineDBPath := flag.String("timeline-db", "", "Path to timeline database file (default: ~/.radar/timeline.db)")
 	prometheusURL := flag.String("prometheus-url", "", "Manual Prometheus/VictoriaMetrics URL (skips auto-discovery)")
+	prometheusConfig := flag.String("prometheus-config", "", "JSON file with metrics URL and auth, including per-context overrides")
+	prometheusUsername := flag.String("prometheus-username", "", "Basic auth username for the metrics URL (password from $RADAR_PROMETHEUS_PASSWORD)")
+	prometheusTokenFile := flag.String("prometheus-bearer-token-file", "", "File containing a bearer token for the metrics URL (or set $RADAR_PROMETHEUS_TOKEN)")
+	prometheusHeaders := flag.String("prometheus-headers", "", "Comma-separated Name=Value headers sent with metrics queries")
+	prometheusCAFile := flag.String("prometheus-ca-file", "", "CA certificate file for verifying the metrics URL")
+	prometheusInsecure := flag.Bool("prometheus-insecure-skip-verify", false, "Skip TLS verification for the metrics URL")
+	trafficHistory := flag.Bool("traffic-history", false, "Periodically record aggregated traffic flows for historical playback")
+	trafficInterval := flag.Duration("traffic-history-interval", time.Minute, "Interval between recorded traffic flow snapshots")
+	trafficRetention := flag.Duration("traffic-history-retention", 24*time.Hour, "How long to keep recorded traffic flow snapshots")
+	proxy := flag.String("proxy", "", "Proxy URL for outbound HTTP (update checks, registries, ArtifactHub, metrics); default from $HTTPS_PROXY/$HTTP_PROXY")
+	noProxy := flag.String("no-proxy", "", "Comma-separated hosts, domains and CIDRs that bypass the proxy; default from $NO_PROXY")
+	caFile := flag.String("ca-file", "", "PEM CA bundle trusted for outbound HTTPS in addition to the system roots (e.g. a TLS-intercepting proxy's CA)")
+	offline := flag.Bool("offline", false, "Air-gapped mode: disable update checks, self-update, registry and ArtifactHub calls")
+	newInstance := flag.Bool("new-instance", false, "Start a new instance even if Radar Desktop is already running for this context")
 	flag.Parse()
 
 	if *showVersion {
@@ -62,22 +86,50 @@ func main() {
 	}
 
 	cfg := app.AppConfig{
-		Kubeconfig:       *kubeconfig,
-		KubeconfigDirs:   app.ParseKubeconfigDirs(*kubeconfigDir),
-		Namespace:        *namespace,
-		Port:             0, // Random port — no conflicts with CLI
-		DevMode:          false,
-		HistoryLimit:     *historyLimit,
-		DebugEvents:      *debugEvents,
-		FakeInCluster:    *fakeInCluster,
-		DisableHelmWrite: *disableHelmWrite,
-		TimelineStorage:  *timelineStorage,
-		TimelineDBPath:   *timelineDBPath,
-		PrometheusURL:    *prometheusURL,
+		Kubeconfig:             *kubeconfig,
+		KubeconfigDirs:         app.ParseKubeconfigDirs(*kubeconfigDir),
+		Namespace:              *namespace,
+		Port:                   0, // Random port — no conflicts with CLI
+		DevMode:                false,
+		HistoryLimit:           *historyLimit,
+		DebugEvents:            *debugEvents,
+		FakeInCluster:          *fakeInCluster,
+		DisableHelmWrite:       *disableHelmWrite,
+		Demo:                   *demo,
+		MaxFileEditSize:        *maxEditSize,
+		ExecIdleTimeout:        *execIdleTimeout,
+		PortForwardIdleTimeout: *portForwardIdleTimeout,
+		TimelineStorage:        *timelineStorage,
+		TimelineDBPath:         *timelineDBPath,
+		PrometheusURL:          *prometheusURL,
+		PrometheusConfig:       *prometheusConfig,
+		PrometheusAuth: traffic.MetricsAuth{
+			Username:           *prometheusUsername,
+			Password:           os.Getenv("RADAR_PROMETHEUS_PASSWORD"),
+			BearerToken:        os.Getenv("RADAR_PROMETHEUS_TOKEN"),
+			BearerTokenFile:    *prometheusTokenFile,
+			Headers:            app.ParseMetricsHeaders(*prometheusHeaders),
+			CAFile:             *prometheusCAFile,
+			InsecureSkipVerify: *prometheusInsecure,
+		},
+		TrafficHistory:   *trafficHistory,
+		TrafficInterval:  *trafficInterval,
+		TrafficRetention: *trafficRetention,
+		Proxy:            *proxy,
+		NoProxy:          *noProxy,
+		CAFile:           *caFile,
+		Offline:          *offline,
+		HelmKeychain:     true,
+		ConfigPath:       *configPath,
 		Version:          version,
 	}
 
 	app.SetGlobals(cfg)
+	app.InitSettings(&cfg)
+	app.InitSchedules()
+	if err := app.ConfigureOutbound(cfg); err != nil {
+		log.Fatalf("%v", err)
+	}
 	versionpkg.SetDesktop(true)
 
 	// Clean up leftover files from previous update
@@ -87,25 +139,21 @@ func main() {
 		log.Fatalf("%v", err)
 	}
 
-	timelineStoreCfg := app.BuildTimelineStoreConfig(cfg)
-	app.RegisterCallbacks(cfg, timelineStoreCfg)
-
-	// Create server on random port and attach desktop updater
-	srv := app.CreateServer(cfg)
-	desktopUpdater := updater.New()
-	srv.SetUpdater(desktopUpdater)
-
-	// Start server and wait until it's accepting connections
-	ready := make(chan struct{})
-	go func() {
-		if err := srv.StartWithReady(ready); err != nil {
-			log.Fatalf("Server error: %v", err)
-		}
-	}()
-	<-ready
-
-	// Initialize cluster in background (browser will see progress via SSE)
-	go app.InitializeCluster()
+	// A desktop window for this context is already open: show its server rather
+	// than starting a second set of informers against the same cluster
+	var (
+		srv              *server.Server
+		timelineStoreCfg timeline.StoreConfig
+		serverAddr       string
+	)
+	if inst, ok := app.FindInstance(cfg, instances.ModeDesktop); ok && !*newInstance {
+		log.Printf("Radar Desktop is already running for %s at %s, reusing it", k8s.GetContextName(), inst.URL())
+		serverAddr = fmt.Sprintf("localhost:%d", inst.Port)
+	} else {
+		srv, timelineStoreCfg = startServer(cfg)
+		serverAddr = srv.ActualAddr()
+		app.RegisterInstance(cfg, srv.ActualPort(), instances.ModeDesktop)
+	}
 
 	// Build window title
 	windowTitle := "Radar"
@@ -125,7 +173,7 @@ func main() {
 		MinHeight: 600,
 
 		AssetServer: &assetserver.Options{
-			Handler: NewRedirectHandler(srv.ActualAddr()),
+			Handler: NewRedirectHandler(serverAddr),
 		},
 
 		Menu: createMenu(desktopApp),
@@ -154,3 +202,29 @@ func main() {
 		log.Fatalf("Wails error: %v", err)
 	}
 }
+
+// startServer creates and starts this process's server, then connects to the
+// cluster in the background (the UI shows progress via SSE)
+func startServer(cfg app.AppConfig) (*server.Server, timeline.StoreConfig) {
+	timelineStoreCfg := app.BuildTimelineStoreConfig(cfg)
+	app.RegisterCallbacks(cfg, timelineStoreCfg)
+
+	// Create server on random port and attach desktop updater
+	srv := app.CreateServer(cfg)
+	desktopUpdater := updater.New()
+	srv.SetUpdater(desktopUpdater)
+
+	// Start server and wait until it's accepting connections
+	ready := make(chan struct{})
+	go func() {
+		if err := srv.StartWithReady(ready); err != nil {
+			log.Fatalf("Server error: %v", err)
+		}
+	}()
+	<-ready
+
+	// Initialize cluster in background (browser will see progress via SSE)
+	go app.InitializeCluster()
+
+	return srv, timelineStoreCfg
+}
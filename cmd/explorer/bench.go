@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// runBench implements `radar bench <target>` and returns the exit code
+func runBench(args []string) int {
+	if len(args) == 0 || args[0] != "timeline" {
+		fmt.Fprintln(os.Stderr, "Usage: radar bench timeline [flags]")
+		return 2
+	}
+
+	defaults := timeline.DefaultBenchConfig()
+	fs := flag.NewFlagSet("bench timeline", flag.ContinueOnError)
+	events := fs.Int("events", defaults.Events, "Number of synthetic events to append")
+	batch := fs.Int("batch", defaults.BatchSize, "Events per AppendBatch call (1 = single Append)")
+	namespaces := fs.Int("namespaces", defaults.Namespaces, "Number of namespaces to spread events over")
+	queries := fs.Int("queries", defaults.Queries, "Queries per filter preset when measuring latency")
+	span := fs.Duration("span", defaults.Span, "Time range covered by event timestamps")
+	seed := fs.Int64("seed", defaults.Seed, "Seed for the event generator")
+	stores := fs.String("stores", "memory,sqlite", "Comma-separated stores to benchmark: memory, sqlite")
+	dbPath := fs.String("db", "", "SQLite database path (default: temporary file, removed afterwards)")
+	jsonOut := fs.Bool("json", false, "Print results as JSON")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	cfg := timeline.BenchConfig{
+		Events:     *events,
+		BatchSize:  *batch,
+		Namespaces: *namespaces,
+		Queries:    *queries,
+		Span:       *span,
+		Seed:       *seed,
+	}
+
+	ctx := context.Background()
+	results := make(map[string]*timeline.BenchResult)
+	for _, name := range strings.Split(*stores, ",") {
+		name = strings.TrimSpace(name)
+		store, cleanup, err := openBenchStore(name, cfg.Events, *dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if !*jsonOut {
+			fmt.Printf("Benchmarking %s store with %d events...\n", name, cfg.Events)
+		}
+		result, err := timeline.RunBenchmark(ctx, store, cfg)
+		cleanup()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s store: %v\n", name, err)
+			return 1
+		}
+		results[name] = result
+		if !*jsonOut {
+			printBenchResult(name, result)
+		}
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// openBenchStore creates an empty store for benchmarking and a cleanup func
+func openBenchStore(name string, events int, dbPath string) (timeline.EventStore, func(), error) {
+	switch name {
+	case "memory":
+		store := timeline.NewMemoryStore(events)
+		return store, func() { store.Close() }, nil
+	case "sqlite":
+		dir := ""
+		if dbPath == "" {
+			var err error
+			dir, err = os.MkdirTemp("", "radar-bench-*")
+			if err != nil {
+				return nil, nil, fmt.Errorf("create temp dir: %w", err)
+			}
+			dbPath = filepath.Join(dir, "timeline.db")
+		}
+		store, err := timeline.NewSQLiteStore(dbPath)
+		if err != nil {
+			os.RemoveAll(dir)
+			return nil, nil, err
+		}
+		return store, func() {
+			store.Close()
+			if dir != "" {
+				os.RemoveAll(dir)
+			}
+		}, nil
+	}
+	return nil, nil, fmt.Errorf("unknown store %q (expected memory or sqlite)", name)
+}
+
+func printBenchResult(name string, r *timeline.BenchResult) {
+	fmt.Printf("\n%s store\n", name)
+	fmt.Printf("  append:  %d events in %s (%.0f events/s)\n", r.Events, r.AppendDuration.Round(time.Millisecond), r.AppendPerSecond)
+	fmt.Printf("  memory:  %.1f MiB heap", float64(r.HeapBytes)/(1<<20))
+	if r.StoreStats.StorageBytes > 0 {
+		fmt.Printf(", %.1f MiB on disk", float64(r.StoreStats.StorageBytes)/(1<<20))
+	}
+	fmt.Println()
+
+	presets := make([]string, 0, len(r.Queries))
+	for preset := range r.Queries {
+		presets = append(presets, preset)
+	}
+	sort.Strings(presets)
+	fmt.Printf("  %-16s %10s %10s %10s %10s %6s\n", "query preset", "p50", "p95", "p99", "max", "rows")
+	for _, preset := range presets {
+		q := r.Queries[preset]
+		label := preset
+		if label == "" {
+			label = "(none)"
+		}
+		fmt.Printf("  %-16s %10s %10s %10s %10s %6d\n", label, fmtLatency(q.P50), fmtLatency(q.P95), fmtLatency(q.P99), fmtLatency(q.Max), q.Rows)
+	}
+	fmt.Println()
+}
+
+func fmtLatency(d time.Duration) string {
+	return d.Round(10 * time.Microsecond).String()
+}
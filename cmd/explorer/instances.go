@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/instances"
+)
+
+// runInstances implements `radar instances`: lists the Radar processes
+// running on this machine and returns the exit code
+func runInstances(args []string) int {
+	fs := flag.NewFlagSet("instances", flag.ContinueOnError)
+	jsonOut := fs.Bool("json", false, "Print instances as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	list := instances.List()
+	if *jsonOut {
+		if list == nil {
+			list = []instances.Instance{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(list); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+	if len(list) == 0 {
+		fmt.Println("No running Radar instances")
+		return 0
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PID\tMODE\tURL\tCONTEXT\tUPTIME")
+	for _, inst := range list {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", inst.PID, inst.Mode, inst.URL(), inst.Context, time.Since(inst.StartedAt).Round(time.Second))
+	}
+	w.Flush()
+	return 0
+}
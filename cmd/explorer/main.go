@@ -1,15 +1,20 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/skyhook-io/radar/internal/app"
+	"github.com/skyhook-io/radar/internal/instances"
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/traffic"
 	_ "k8s.io/client-go/plugin/pkg/client/auth" // Register all auth provider plugins (OIDC, GCP, Azure, etc.)
 	"k8s.io/klog/v2"
 )
@@ -19,23 +24,69 @@ var (
 )
 
 func main() {
+	// Subcommands run standalone, without a cluster connection
+	if len(os.Args) > 1 && os.Args[1] == "bench" {
+		os.Exit(runBench(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "instances" {
+		os.Exit(runInstances(os.Args[2:]))
+	}
+
 	// Parse flags
 	kubeconfig := flag.String("kubeconfig", "", "Path to kubeconfig file (default: ~/.kube/config)")
 	kubeconfigDir := flag.String("kubeconfig-dir", "", "Comma-separated directories containing kubeconfig files (mutually exclusive with --kubeconfig)")
 	namespace := flag.String("namespace", "", "Initial namespace filter (empty = all namespaces)")
 	port := flag.Int("port", 9280, "Server port")
+	strictPort := flag.Bool("strict-port", false, "Fail when --port is in use instead of falling back to a free port")
+	newInstance := flag.Bool("new-instance", false, "Start a new instance even if Radar is already running for this context")
 	noBrowser := flag.Bool("no-browser", false, "Don't auto-open browser")
 	devMode := flag.Bool("dev", false, "Development mode (serve frontend from filesystem)")
 	showVersion := flag.Bool("version", false, "Show version and exit")
 	historyLimit := flag.Int("history-limit", 10000, "Maximum number of events to retain in timeline")
 	debugEvents := flag.Bool("debug-events", false, "Enable verbose event debugging (logs all event drops)")
+	startupTrace := flag.Bool("startup-trace", false, "Time each initialization phase (RBAC checks, informer sync per kind, CRD discovery, Helm, traffic) and serve it at /api/debug/startup")
+	configPath := flag.String("config", "", "File settings changed at runtime are saved to (default: ~/.radar/config.json)")
+	readOnly := flag.Bool("read-only", false, "Start in read-only mode: requests that change the cluster are rejected, and the API can't turn it off")
 	fakeInCluster := flag.Bool("fake-in-cluster", false, "Simulate in-cluster mode for testing (shows kubectl copy buttons instead of port-forward)")
 	disableHelmWrite := flag.Bool("disable-helm-write", false, "Simulate restricted Helm permissions (disables install/upgrade/rollback/uninstall)")
+	demo := flag.Bool("demo", false, "Run against a synthetic in-memory cluster (no kubeconfig needed)")
+	maxDynamicInformers := flag.Int("max-dynamic-informers", 0, "Maximum CRD/dynamic informers; beyond this, resources are served by direct lists with a short cache (0 = unlimited)")
+	maxEditSize := flag.Int64("max-edit-size", 2<<20, "Largest pod file, in bytes, the file editor opens or saves; larger files can still be downloaded and uploaded")
+	// Interactive session limits
+	maxExecSessions := flag.Int("max-exec-sessions", 0, "Maximum concurrent terminal sessions per client (0 = unlimited)")
+	maxPortForwards := flag.Int("max-port-forwards", 0, "Maximum concurrent port forwards per client (0 = unlimited)")
+	execIdleTimeout := flag.Duration("exec-idle-timeout", 0, "Close terminal sessions with no input or output for this long (0 = never)")
+	portForwardIdleTimeout := flag.Duration("port-forward-idle-timeout", 0, "Stop port forwards that haven't handled a new connection for this long (0 = never)")
+	// Event stream record/replay (debugging)
+	recordPath := flag.String("record", "", "Record resource changes and timeline events to this file")
+	replayPath := flag.String("replay", "", "Replay a file written by --record to the UI instead of live events")
+	replaySpeed := flag.Float64("replay-speed", 1, "Replay speed multiplier (0 = as fast as possible)")
 	// Timeline storage options
 	timelineStorage := flag.String("timeline-storage", "memory", "Timeline storage backend: memory or sqlite")
 	timelineDBPath := flag.String("timeline-db", "", "Path to timeline database file (default: ~/.radar/timeline.db)")
 	// Traffic/metrics options
 	prometheusURL := flag.String("prometheus-url", "", "Manual Prometheus/VictoriaMetrics URL (skips auto-discovery)")
+	prometheusConfig := flag.String("prometheus-config", "", "JSON file with metrics URL and auth, including per-context overrides")
+	prometheusUsername := flag.String("prometheus-username", "", "Basic auth username for the metrics URL (password from $RADAR_PROMETHEUS_PASSWORD)")
+	prometheusTokenFile := flag.String("prometheus-bearer-token-file", "", "File containing a bearer token for the metrics URL (or set $RADAR_PROMETHEUS_TOKEN)")
+	prometheusHeaders := flag.String("prometheus-headers", "", "Comma-separated Name=Value headers sent with metrics queries")
+	prometheusCAFile := flag.String("prometheus-ca-file", "", "CA certificate file for verifying the metrics URL")
+	prometheusInsecure := flag.Bool("prometheus-insecure-skip-verify", false, "Skip TLS verification for the metrics URL")
+	trafficHistory := flag.Bool("traffic-history", false, "Periodically record aggregated traffic flows for historical playback")
+	trafficInterval := flag.Duration("traffic-history-interval", time.Minute, "Interval between recorded traffic flow snapshots")
+	trafficRetention := flag.Duration("traffic-history-retention", 24*time.Hour, "How long to keep recorded traffic flow snapshots")
+	proxy := flag.String("proxy", "", "Proxy URL for outbound HTTP (update checks, registries, ArtifactHub, metrics); default from $HTTPS_PROXY/$HTTP_PROXY")
+	noProxy := flag.String("no-proxy", "", "Comma-separated hosts, domains and CIDRs that bypass the proxy; default from $NO_PROXY")
+	caFile := flag.String("ca-file", "", "PEM CA bundle trusted for outbound HTTPS in addition to the system roots (e.g. a TLS-intercepting proxy's CA)")
+	offline := flag.Bool("offline", false, "Air-gapped mode: disable update checks, self-update, registry and ArtifactHub calls")
+	userHeader := flag.String("user-header", "", "Request header an auth proxy sets to the authenticated user, e.g. X-Forwarded-User; API calls made for that user are attributed to them in cluster audit logs")
+	userIdentity := flag.String("user-identity", "user-agent", "How the --user-header user reaches the API server: user-agent (suffix in audit logs) or impersonate (Impersonate-User; needs the impersonate verb)")
+	allowedOrigins := flag.String("allowed-origins", "", "Comma-separated browser origins, besides Radar's own, allowed to call the API, e.g. https://radar.example.com behind a proxy that rewrites Host")
+
+	// Remote cluster agents
+	agentListen := flag.String("agent-listen", "", "Accept radar-agent connections from remote clusters on this address, e.g. :9281 (token from $RADAR_AGENT_TOKEN)")
+	agentTLSCert := flag.String("agent-tls-cert", "", "TLS certificate file for the agent listener (plaintext without it)")
+	agentTLSKey := flag.String("agent-tls-key", "", "TLS key file for the agent listener")
 	flag.Parse()
 
 	if *showVersion {
@@ -56,32 +107,109 @@ func main() {
 	if *kubeconfig != "" && *kubeconfigDir != "" {
 		log.Fatalf("--kubeconfig and --kubeconfig-dir are mutually exclusive")
 	}
+	origins, err := app.ParseAllowedOrigins(*allowedOrigins)
+	if err != nil {
+		log.Fatalf("Invalid --allowed-origins: %v", err)
+	}
 
 	cfg := app.AppConfig{
-		Kubeconfig:       *kubeconfig,
-		KubeconfigDirs:   app.ParseKubeconfigDirs(*kubeconfigDir),
-		Namespace:        *namespace,
-		Port:             *port,
-		NoBrowser:        *noBrowser,
-		DevMode:          *devMode,
-		HistoryLimit:     *historyLimit,
-		DebugEvents:      *debugEvents,
-		FakeInCluster:    *fakeInCluster,
-		DisableHelmWrite: *disableHelmWrite,
-		TimelineStorage:  *timelineStorage,
-		TimelineDBPath:   *timelineDBPath,
-		PrometheusURL:    *prometheusURL,
+		Kubeconfig:             *kubeconfig,
+		KubeconfigDirs:         app.ParseKubeconfigDirs(*kubeconfigDir),
+		Namespace:              *namespace,
+		Port:                   *port,
+		NoBrowser:              *noBrowser,
+		DevMode:                *devMode,
+		HistoryLimit:           *historyLimit,
+		DebugEvents:            *debugEvents,
+		StartupTrace:           *startupTrace,
+		FakeInCluster:          *fakeInCluster,
+		DisableHelmWrite:       *disableHelmWrite,
+		Demo:                   *demo,
+		RecordPath:             *recordPath,
+		ReplayPath:             *replayPath,
+		ReplaySpeed:            *replaySpeed,
+		MaxDynamicInformers:    *maxDynamicInformers,
+		MaxFileEditSize:        *maxEditSize,
+		MaxExecSessions:        *maxExecSessions,
+		MaxPortForwards:        *maxPortForwards,
+		ExecIdleTimeout:        *execIdleTimeout,
+		PortForwardIdleTimeout: *portForwardIdleTimeout,
+		TimelineStorage:        *timelineStorage,
+		TimelineDBPath:         *timelineDBPath,
+		PrometheusURL:          *prometheusURL,
+		PrometheusConfig:       *prometheusConfig,
+		PrometheusAuth: traffic.MetricsAuth{
+			Username:           *prometheusUsername,
+			Password:           os.Getenv("RADAR_PROMETHEUS_PASSWORD"),
+			BearerToken:        os.Getenv("RADAR_PROMETHEUS_TOKEN"),
+			BearerTokenFile:    *prometheusTokenFile,
+			Headers:            app.ParseMetricsHeaders(*prometheusHeaders),
+			CAFile:             *prometheusCAFile,
+			InsecureSkipVerify: *prometheusInsecure,
+		},
+		TrafficHistory:   *trafficHistory,
+		TrafficInterval:  *trafficInterval,
+		TrafficRetention: *trafficRetention,
+		Proxy:            *proxy,
+		NoProxy:          *noProxy,
+		CAFile:           *caFile,
+		Offline:          *offline,
+		AgentListen:      *agentListen,
+		AllowedOrigins:   origins,
+		UserHeader:       *userHeader,
+		UserIdentity:     *userIdentity,
+		AgentToken:       os.Getenv("RADAR_AGENT_TOKEN"),
+		AgentTLSCert:     *agentTLSCert,
+		AgentTLSKey:      *agentTLSKey,
+		ConfigPath:       *configPath,
+		ReadOnly:         *readOnly,
 		Version:          version,
 	}
 
 	// Set global flags
 	app.SetGlobals(cfg)
+	app.InitSettings(&cfg)
+	app.InitSchedules()
+	if err := app.ConfigureOutbound(cfg); err != nil {
+		log.Fatalf("%v", err)
+	}
+	if err := app.ConfigureIdentity(cfg); err != nil {
+		log.Fatalf("%v", err)
+	}
 
 	// Initialize K8s client
 	if err := app.InitializeK8s(cfg); err != nil {
 		log.Fatalf("%v", err)
 	}
 
+	// Reuse a Radar already watching this context instead of starting a second
+	// set of informers, then bind the port (which may be another Radar's too)
+	var running *app.RunningInstanceError
+	reuseContext := k8s.GetContextName()
+	if *newInstance {
+		reuseContext = ""
+	} else if inst, ok := app.FindInstance(cfg, ""); ok {
+		running = &app.RunningInstanceError{URL: inst.URL()}
+	}
+	if running == nil {
+		listener, err := app.Listen(cfg.Port, *strictPort, reuseContext)
+		if err != nil && !errors.As(err, &running) {
+			log.Fatalf("%v", err)
+		}
+		if err == nil {
+			cfg.Listener = listener
+			cfg.Port = listener.Addr().(*net.TCPAddr).Port
+		}
+	}
+	if running != nil {
+		log.Printf("Radar is already running for %s at %s, opening it instead (use --new-instance to start another)", k8s.GetContextName(), running.URL)
+		if !*noBrowser {
+			app.OpenBrowser(browserURL(running.URL, *namespace))
+		}
+		os.Exit(0)
+	}
+	app.RegisterInstance(cfg, cfg.Port, instances.ModeCLI)
+
 	// Build timeline config and register callbacks
 	timelineStoreCfg := app.BuildTimelineStoreConfig(cfg)
 	app.RegisterCallbacks(cfg, timelineStoreCfg)
@@ -111,11 +239,7 @@ func main() {
 
 	// Open browser - it can now connect and see progress updates
 	if !cfg.NoBrowser {
-		url := fmt.Sprintf("http://localhost:%d", cfg.Port)
-		if cfg.Namespace != "" {
-			url += fmt.Sprintf("?namespace=%s", cfg.Namespace)
-		}
-		go app.OpenBrowser(url)
+		go app.OpenBrowser(browserURL(fmt.Sprintf("http://localhost:%d", cfg.Port), cfg.Namespace))
 	}
 
 	// Now initialize cluster connection and caches (browser will see progress via SSE)
@@ -124,3 +248,11 @@ func main() {
 	// Block forever (server is running in background)
 	select {}
 }
+
+// browserURL is the UI address, with the initial namespace filter if any
+func browserURL(base, namespace string) string {
+	if namespace != "" {
+		return base + fmt.Sprintf("?namespace=%s", namespace)
+	}
+	return base
+}
@@ -0,0 +1,309 @@
+package agent
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/outbound"
+	"github.com/skyhook-io/radar/internal/timeline"
+	"github.com/skyhook-io/radar/internal/topology"
+)
+
+const (
+	defaultFlushInterval = 2 * time.Second
+	// An empty batch is still sent this often so the server sees the agent is alive
+	heartbeatInterval = 30 * time.Second
+	// Topology is rebuilt at most this often, however fast resources change
+	topologyInterval = 10 * time.Second
+	// Changes and events buffered while disconnected; the oldest are dropped beyond this
+	maxPending = 5000
+
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+// Config configures an agent
+type Config struct {
+	ServerAddr    string        // host:port of the central Radar's agent listener
+	Cluster       string        // Name the cluster is shown under on the central server
+	Token         string        // Shared secret the server was started with
+	Insecure      bool          // Plaintext gRPC, for TLS-terminating proxies and local testing
+	CAFile        string        // Extra CA bundle for verifying the server
+	Version       string        // Agent version reported to the server
+	FlushInterval time.Duration // How often batches are sent (default 2s)
+}
+
+// Agent streams the local cluster's state to a central server
+type Agent struct {
+	cfg              Config
+	buildTopology    func() (*topology.Topology, error)
+	summarizeCluster func() *ClusterSummary
+
+	mu            sync.Mutex
+	changes       []Change
+	events        []timeline.TimelineEvent
+	topologyDirty bool
+	dropped       int
+}
+
+// New validates cfg and creates an agent
+func New(cfg Config) (*Agent, error) {
+	if cfg.ServerAddr == "" {
+		return nil, errors.New("server address is required")
+	}
+	if cfg.Cluster == "" {
+		return nil, errors.New("cluster name is required")
+	}
+	if cfg.Token == "" {
+		return nil, errors.New("agent token is required")
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultFlushInterval
+	}
+	return &Agent{cfg: cfg, buildTopology: buildFullTopology, summarizeCluster: summarizeLocalCluster, topologyDirty: true}, nil
+}
+
+// buildFullTopology builds the same all-namespace topology the SSE
+// broadcaster caches, leaving view filtering to the central server
+func buildFullTopology() (*topology.Topology, error) {
+	opts := topology.DefaultBuildOptions()
+	opts.ViewMode = topology.ViewModeResources
+	opts.IncludeReplicaSets = true
+	return topology.NewBuilder().Build(opts)
+}
+
+func summarizeLocalCluster() *ClusterSummary {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return CollectClusterSummary(ctx)
+}
+
+func (a *Agent) dialOptions() ([]grpc.DialOption, error) {
+	creds := insecure.NewCredentials()
+	if !a.cfg.Insecure {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if a.cfg.CAFile != "" {
+			pool, err := outbound.LoadCAFile(nil, a.cfg.CAFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = pool
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	return []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(
+			grpc.ForceCodec(jsonCodec{}),
+			grpc.UseCompressor(gzip.Name),
+			grpc.MaxCallSendMsgSize(maxMessageSize),
+		),
+	}, nil
+}
+
+// Run collects changes and streams them to the server until ctx is done,
+// reconnecting with backoff when the connection drops
+func (a *Agent) Run(ctx context.Context) error {
+	opts, err := a.dialOptions()
+	if err != nil {
+		return err
+	}
+	conn, err := grpc.NewClient(a.cfg.ServerAddr, opts...)
+	if err != nil {
+		return fmt.Errorf("connect to %s: %w", a.cfg.ServerAddr, err)
+	}
+	defer conn.Close()
+
+	go a.watchResourceChanges(ctx)
+	go a.watchTimelineEvents(ctx)
+
+	delay := minReconnectDelay
+	for {
+		started := time.Now()
+		err := a.stream(ctx, conn)
+		if ctx.Err() != nil {
+			return nil
+		}
+		// A connection that stayed up a while starts the backoff over
+		if time.Since(started) > maxReconnectDelay {
+			delay = minReconnectDelay
+		}
+		log.Printf("[agent] Stream to %s ended: %v (reconnecting in %s)", a.cfg.ServerAddr, err, delay)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+// stream sends batches over one stream until it fails or ctx is done
+func (a *Agent) stream(ctx context.Context, conn *grpc.ClientConn) error {
+	streamCtx := metadata.AppendToOutgoingContext(ctx, tokenHeader, "Bearer "+a.cfg.Token)
+	stream, err := conn.NewStream(streamCtx, streamDesc, streamPath)
+	if err != nil {
+		return err
+	}
+
+	// The server may have restarted, so every stream starts with a full topology
+	a.mu.Lock()
+	a.topologyDirty = true
+	a.mu.Unlock()
+
+	hello := &Hello{Cluster: a.cfg.Cluster, Context: k8s.GetContextName(), AgentVersion: a.cfg.Version}
+	if err := a.send(stream, a.takeBatch(hello, true)); err != nil {
+		return err
+	}
+	log.Printf("[agent] Streaming cluster %q to %s", a.cfg.Cluster, a.cfg.ServerAddr)
+
+	ticker := time.NewTicker(a.cfg.FlushInterval)
+	defer ticker.Stop()
+	lastSent, lastTopology := time.Now(), time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			_ = stream.CloseSend()
+			var ack Ack
+			_ = stream.RecvMsg(&ack)
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		withTopology := time.Since(lastTopology) >= topologyInterval
+		batch := a.takeBatch(nil, withTopology)
+		if batch.Topology != nil {
+			lastTopology = time.Now()
+		}
+		if len(batch.Changes) == 0 && len(batch.Events) == 0 && batch.Topology == nil &&
+			time.Since(lastSent) < heartbeatInterval {
+			continue
+		}
+		if err := a.send(stream, batch); err != nil {
+			return err
+		}
+		lastSent = time.Now()
+	}
+}
+
+// send writes a batch, surfacing the server's status when it closed the stream
+func (a *Agent) send(stream grpc.ClientStream, batch *Batch) error {
+	err := stream.SendMsg(batch)
+	if err == io.EOF {
+		var ack Ack
+		if recvErr := stream.RecvMsg(&ack); recvErr != nil {
+			return recvErr
+		}
+		return errors.New("server closed the stream")
+	}
+	return err
+}
+
+// takeBatch drains pending changes and events. The topology and cluster
+// summary are included when allowed and something changed since last sent.
+func (a *Agent) takeBatch(hello *Hello, allowTopology bool) *Batch {
+	a.mu.Lock()
+	batch := &Batch{Hello: hello, SentAt: time.Now(), Changes: a.changes, Events: a.events}
+	a.changes, a.events = nil, nil
+	buildTopology := allowTopology && a.topologyDirty
+	if buildTopology {
+		a.topologyDirty = false
+	}
+	if a.dropped > 0 {
+		log.Printf("[agent] Dropped %d changes and events buffered while disconnected", a.dropped)
+		a.dropped = 0
+	}
+	a.mu.Unlock()
+
+	if buildTopology {
+		topo, err := a.buildTopology()
+		if err != nil {
+			log.Printf("[agent] Error building topology: %v", err)
+			a.mu.Lock()
+			a.topologyDirty = true
+			a.mu.Unlock()
+		} else {
+			batch.Topology = topo
+			batch.Summary = a.summarizeCluster()
+		}
+	}
+	return batch
+}
+
+func (a *Agent) enqueueChange(change k8s.ResourceChange) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.changes = append(a.changes, changeFromResourceChange(change))
+	if over := len(a.changes) - maxPending; over > 0 {
+		a.changes = a.changes[over:]
+		a.dropped += over
+	}
+	a.topologyDirty = true
+}
+
+func (a *Agent) enqueueEvent(event timeline.TimelineEvent) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.events = append(a.events, event)
+	if over := len(a.events) - maxPending; over > 0 {
+		a.events = a.events[over:]
+		a.dropped += over
+	}
+}
+
+// watchResourceChanges queues resource changes. Like the SSE broadcaster, it
+// waits for the resource cache and resubscribes if the channel closes.
+func (a *Agent) watchResourceChanges(ctx context.Context) {
+	for {
+		changes := k8s.GetResourceCache().Changes()
+		if changes == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+	consume:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case change, ok := <-changes:
+				if !ok {
+					break consume
+				}
+				a.enqueueChange(change)
+			}
+		}
+	}
+}
+
+func (a *Agent) watchTimelineEvents(ctx context.Context) {
+	events, unsubscribe := timeline.Subscribe()
+	defer unsubscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			a.enqueueEvent(event)
+		}
+	}
+}
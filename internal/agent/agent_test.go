@@ -0,0 +1,118 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/timeline"
+	"github.com/skyhook-io/radar/internal/topology"
+)
+
+func startHub(t *testing.T, token string) (*Hub, string) {
+	t.Helper()
+	hub, err := NewHub(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = hub.Serve(ln, nil) }()
+	t.Cleanup(hub.Stop)
+	return hub, ln.Addr().String()
+}
+
+func runAgent(t *testing.T, cfg Config) *Agent {
+	t.Helper()
+	cfg.Insecure = true
+	cfg.FlushInterval = 20 * time.Millisecond
+	a, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.buildTopology = func() (*topology.Topology, error) {
+		return &topology.Topology{Nodes: []topology.Node{{ID: "deployment/shop/api", Kind: topology.KindDeployment, Name: "api"}}}, nil
+	}
+	a.summarizeCluster = func() *ClusterSummary {
+		return &ClusterSummary{KubernetesVersion: "v1.31.2", Nodes: 3, ReadyNodes: 3}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = a.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return a
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestAgentStreamsToHub(t *testing.T) {
+	hub, addr := startHub(t, "s3cret")
+	a := runAgent(t, Config{ServerAddr: addr, Cluster: "prod-eu", Token: "s3cret", Version: "1.2.3"})
+
+	waitFor(t, "the agent to connect", func() bool {
+		clusters := hub.Clusters()
+		return len(clusters) == 1 && clusters[0].Connected && clusters[0].Nodes == 1
+	})
+	if c := hub.Clusters()[0]; c.Name != "prod-eu" || c.AgentVersion != "1.2.3" {
+		t.Errorf("unexpected cluster summary %+v", c)
+	}
+	if snaps := hub.Snapshots(); len(snaps) != 1 || snaps[0].Summary == nil || snaps[0].Summary.KubernetesVersion != "v1.31.2" {
+		t.Errorf("expected the agent's cluster summary, got %+v", snaps)
+	}
+
+	a.enqueueChange(k8s.ResourceChange{Kind: "Deployment", Namespace: "shop", Name: "api", Operation: "update"})
+	a.enqueueEvent(timeline.TimelineEvent{ID: "e1", Kind: "Pod", Namespace: "shop", Name: "api-1", Reason: "BackOff"})
+	waitFor(t, "the batch to arrive", func() bool {
+		events, _ := hub.Events("prod-eu", 10)
+		changes, _ := hub.Changes("prod-eu", 10)
+		return len(events) == 1 && len(changes) == 1
+	})
+
+	topo, err := hub.Topology("prod-eu")
+	if err != nil || len(topo.Nodes) != 1 || topo.Nodes[0].ID != "deployment/shop/api" {
+		t.Errorf("expected the agent's topology, got %+v (%v)", topo, err)
+	}
+	if _, err := hub.Topology("staging"); !errors.Is(err, ErrUnknownCluster) {
+		t.Errorf("expected ErrUnknownCluster, got %v", err)
+	}
+}
+
+func TestHubRejectsBadToken(t *testing.T) {
+	hub, addr := startHub(t, "s3cret")
+	runAgent(t, Config{ServerAddr: addr, Cluster: "prod-eu", Token: "wrong"})
+
+	time.Sleep(200 * time.Millisecond)
+	if clusters := hub.Clusters(); len(clusters) != 0 {
+		t.Errorf("expected no clusters with a bad token, got %+v", clusters)
+	}
+}
+
+func TestAppendCapped(t *testing.T) {
+	got := appendCapped([]int{1, 2, 3}, []int{4, 5}, 3)
+	if len(got) != 3 || got[0] != 3 || got[2] != 5 {
+		t.Errorf("expected the newest 3 items, got %v", got)
+	}
+	if newest := newestFirst(got, 2); newest[0] != 5 || newest[1] != 4 {
+		t.Errorf("expected newest first, got %v", newest)
+	}
+}
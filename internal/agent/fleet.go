@@ -0,0 +1,476 @@
+package agent
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/timeline"
+	"github.com/skyhook-io/radar/internal/topology"
+)
+
+const (
+	maxFleetProblems = 50
+	maxFleetReasons  = 10
+	// Timeline events within this window count towards a cluster's top problems
+	fleetEventWindow = time.Hour
+)
+
+// ClusterSummary is what an agent reports about its cluster alongside the
+// topology: versions and node readiness
+type ClusterSummary struct {
+	Platform          string         `json:"platform,omitempty"`
+	KubernetesVersion string         `json:"kubernetesVersion,omitempty"`
+	Nodes             int            `json:"nodes"`
+	ReadyNodes        int            `json:"readyNodes"`
+	KubeletVersions   map[string]int `json:"kubeletVersions,omitempty"` // Node count per kubelet version
+	Namespaces        int            `json:"namespaces"`
+	Pods              int            `json:"pods"`
+}
+
+// CollectClusterSummary summarizes the local cluster from the resource cache
+func CollectClusterSummary(ctx context.Context) *ClusterSummary {
+	summary := &ClusterSummary{}
+	if info, err := k8s.GetClusterInfo(ctx); err == nil {
+		summary.Platform = info.Platform
+		summary.KubernetesVersion = info.KubernetesVersion
+		summary.Namespaces = info.NamespaceCount
+		summary.Pods = info.PodCount
+	}
+	cache := k8s.GetResourceCache()
+	if cache == nil || cache.Nodes() == nil {
+		return summary
+	}
+	nodes, err := cache.Nodes().List(labels.Everything())
+	if err != nil {
+		return summary
+	}
+	summary.KubeletVersions = make(map[string]int)
+	for _, node := range nodes {
+		summary.Nodes++
+		summary.KubeletVersions[node.Status.NodeInfo.KubeletVersion]++
+		for _, cond := range node.Status.Conditions {
+			if cond.Type == corev1.NodeReady && cond.Status == corev1.ConditionTrue {
+				summary.ReadyNodes++
+			}
+		}
+	}
+	return summary
+}
+
+// ClusterSnapshot is one cluster's latest state, the input to fleet views.
+// The local cluster is included alongside agent-reported ones.
+type ClusterSnapshot struct {
+	Name      string
+	Local     bool
+	Connected bool
+	LastSeen  time.Time
+	Summary   *ClusterSummary
+	Topology  *topology.Topology
+	Events    []timeline.TimelineEvent // Newest first
+}
+
+// Snapshots returns the latest state of every cluster that has connected
+func (h *Hub) Snapshots() []ClusterSnapshot {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	result := make([]ClusterSnapshot, 0, len(h.clusters))
+	for _, state := range h.clusters {
+		result = append(result, ClusterSnapshot{
+			Name:      state.summary.Name,
+			Connected: state.summary.Connected,
+			LastSeen:  state.summary.LastSeen,
+			Summary:   state.clusterSummary,
+			Topology:  state.topology,
+			Events:    newestFirst(state.events, 0),
+		})
+	}
+	return result
+}
+
+// FleetHealth counts workloads and pods by topology health status
+type FleetHealth struct {
+	Healthy   int `json:"healthy"`
+	Degraded  int `json:"degraded"`
+	Unhealthy int `json:"unhealthy"`
+	Unknown   int `json:"unknown"`
+}
+
+func (h *FleetHealth) add(other FleetHealth) {
+	h.Healthy += other.Healthy
+	h.Degraded += other.Degraded
+	h.Unhealthy += other.Unhealthy
+	h.Unknown += other.Unknown
+}
+
+// FleetCluster is one row of the fleet dashboard
+type FleetCluster struct {
+	Name      string          `json:"name"`
+	Local     bool            `json:"local,omitempty"` // The cluster Radar itself is connected to
+	Connected bool            `json:"connected"`
+	LastSeen  *time.Time      `json:"lastSeen,omitempty"`
+	Summary   *ClusterSummary `json:"summary,omitempty"`
+	Health    FleetHealth     `json:"health"`
+	Problems  int             `json:"problems"` // Degraded or unhealthy workloads
+	Warnings  int             `json:"warnings"` // Warning/critical timeline events in the last hour
+}
+
+// FleetProblem is a degraded or unhealthy workload in some cluster
+type FleetProblem struct {
+	Cluster   string                `json:"cluster"`
+	Kind      string                `json:"kind"`
+	Namespace string                `json:"namespace,omitempty"`
+	Name      string                `json:"name"`
+	Status    topology.HealthStatus `json:"status"`
+}
+
+// FleetReason is a warning reason seen across the fleet in the last hour
+type FleetReason struct {
+	Reason   string   `json:"reason"`
+	Count    int      `json:"count"`
+	Clusters []string `json:"clusters"`
+}
+
+// FleetTotals sums the fleet dashboard over all clusters
+type FleetTotals struct {
+	Clusters   int         `json:"clusters"`
+	Connected  int         `json:"connected"`
+	Nodes      int         `json:"nodes"`
+	ReadyNodes int         `json:"readyNodes"`
+	Health     FleetHealth `json:"health"`
+}
+
+// FleetDashboard aggregates health, versions and problems across clusters
+type FleetDashboard struct {
+	Clusters           []FleetCluster `json:"clusters"`
+	Totals             FleetTotals    `json:"totals"`
+	KubernetesVersions map[string]int `json:"kubernetesVersions"` // Cluster count per version
+	KubeletVersions    map[string]int `json:"kubeletVersions"`    // Node count per version, fleet-wide
+	TopProblems        []FleetProblem `json:"topProblems"`
+	TopReasons         []FleetReason  `json:"topReasons"`
+}
+
+// FleetClusterDetail is the drill-down for one cluster
+type FleetClusterDetail struct {
+	FleetCluster
+	ProblemList    []FleetProblem           `json:"problemList"`
+	RecentWarnings []timeline.TimelineEvent `json:"recentWarnings"`
+}
+
+// fleetWorkloadKinds are the topology kinds counted towards fleet health
+var fleetWorkloadKinds = map[topology.NodeKind]bool{
+	topology.KindDeployment:  true,
+	topology.KindRollout:     true,
+	topology.KindStatefulSet: true,
+	topology.KindDaemonSet:   true,
+	topology.KindJob:         true,
+	topology.KindCronJob:     true,
+	topology.KindPod:         true,
+}
+
+// BuildFleetDashboard aggregates cluster snapshots. Clusters are sorted with
+// the local one first, then by name.
+func BuildFleetDashboard(snapshots []ClusterSnapshot, now time.Time) *FleetDashboard {
+	sortSnapshots(snapshots)
+	dash := &FleetDashboard{
+		Clusters:           make([]FleetCluster, 0, len(snapshots)),
+		KubernetesVersions: make(map[string]int),
+		KubeletVersions:    make(map[string]int),
+		TopProblems:        []FleetProblem{},
+		TopReasons:         []FleetReason{},
+	}
+	reasons := make(map[string]*FleetReason)
+	for i := range snapshots {
+		snap := &snapshots[i]
+		cluster, problems := fleetCluster(snap, now)
+		dash.Clusters = append(dash.Clusters, cluster)
+		dash.TopProblems = append(dash.TopProblems, problems...)
+
+		dash.Totals.Clusters++
+		if cluster.Connected {
+			dash.Totals.Connected++
+		}
+		dash.Totals.Health.add(cluster.Health)
+		if s := snap.Summary; s != nil {
+			dash.Totals.Nodes += s.Nodes
+			dash.Totals.ReadyNodes += s.ReadyNodes
+			if s.KubernetesVersion != "" {
+				dash.KubernetesVersions[s.KubernetesVersion]++
+			}
+			for version, count := range s.KubeletVersions {
+				dash.KubeletVersions[version] += count
+			}
+		}
+
+		for _, e := range recentWarnings(snap.Events, now) {
+			r := reasons[e.Reason]
+			if r == nil {
+				r = &FleetReason{Reason: e.Reason}
+				reasons[e.Reason] = r
+			}
+			r.Count++
+			if len(r.Clusters) == 0 || r.Clusters[len(r.Clusters)-1] != snap.Name {
+				r.Clusters = append(r.Clusters, snap.Name)
+			}
+		}
+	}
+
+	sortProblems(dash.TopProblems)
+	if len(dash.TopProblems) > maxFleetProblems {
+		dash.TopProblems = dash.TopProblems[:maxFleetProblems]
+	}
+	for _, r := range reasons {
+		dash.TopReasons = append(dash.TopReasons, *r)
+	}
+	// Reasons hitting more clusters first: a fleet-wide problem beats a noisy one
+	sort.Slice(dash.TopReasons, func(i, j int) bool {
+		a, b := dash.TopReasons[i], dash.TopReasons[j]
+		if len(a.Clusters) != len(b.Clusters) {
+			return len(a.Clusters) > len(b.Clusters)
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Reason < b.Reason
+	})
+	if len(dash.TopReasons) > maxFleetReasons {
+		dash.TopReasons = dash.TopReasons[:maxFleetReasons]
+	}
+	return dash
+}
+
+// BuildFleetClusterDetail returns the drill-down for the named cluster
+func BuildFleetClusterDetail(snapshots []ClusterSnapshot, name string, now time.Time) (*FleetClusterDetail, bool) {
+	for i := range snapshots {
+		if snapshots[i].Name != name {
+			continue
+		}
+		cluster, problems := fleetCluster(&snapshots[i], now)
+		sortProblems(problems)
+		warnings := recentWarnings(snapshots[i].Events, now)
+		if warnings == nil {
+			warnings = []timeline.TimelineEvent{}
+		}
+		return &FleetClusterDetail{FleetCluster: cluster, ProblemList: problems, RecentWarnings: warnings}, true
+	}
+	return nil, false
+}
+
+func fleetCluster(snap *ClusterSnapshot, now time.Time) (FleetCluster, []FleetProblem) {
+	cluster := FleetCluster{Name: snap.Name, Local: snap.Local, Connected: snap.Connected, Summary: snap.Summary}
+	if !snap.LastSeen.IsZero() {
+		lastSeen := snap.LastSeen
+		cluster.LastSeen = &lastSeen
+	}
+	problems := []FleetProblem{}
+	if snap.Topology != nil {
+		for _, node := range snap.Topology.Nodes {
+			if !fleetWorkloadKinds[node.Kind] {
+				continue
+			}
+			switch node.Status {
+			case topology.StatusHealthy:
+				cluster.Health.Healthy++
+				continue
+			case topology.StatusDegraded:
+				cluster.Health.Degraded++
+			case topology.StatusUnhealthy:
+				cluster.Health.Unhealthy++
+			default:
+				cluster.Health.Unknown++
+				continue
+			}
+			namespace, _ := node.Data["namespace"].(string)
+			problems = append(problems, FleetProblem{
+				Cluster:   snap.Name,
+				Kind:      string(node.Kind),
+				Namespace: namespace,
+				Name:      node.Name,
+				Status:    node.Status,
+			})
+		}
+	}
+	cluster.Problems = len(problems)
+	cluster.Warnings = len(recentWarnings(snap.Events, now))
+	return cluster, problems
+}
+
+// recentWarnings returns warning and critical events within the fleet window
+func recentWarnings(events []timeline.TimelineEvent, now time.Time) []timeline.TimelineEvent {
+	var result []timeline.TimelineEvent
+	since := now.Add(-fleetEventWindow)
+	for _, e := range events {
+		if e.Timestamp.Before(since) {
+			continue
+		}
+		if e.Severity == timeline.SeverityWarning || e.Severity == timeline.SeverityCritical {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func sortSnapshots(snapshots []ClusterSnapshot) {
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		if snapshots[i].Local != snapshots[j].Local {
+			return snapshots[i].Local
+		}
+		return snapshots[i].Name < snapshots[j].Name
+	})
+}
+
+// sortProblems puts unhealthy before degraded, then orders by location
+func sortProblems(problems []FleetProblem) {
+	sort.SliceStable(problems, func(i, j int) bool {
+		a, b := problems[i], problems[j]
+		if a.Status != b.Status {
+			return a.Status == topology.StatusUnhealthy
+		}
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+}
+
+// Fleet search match weights, following the single-cluster search: name
+// matches dominate label matches
+const (
+	fleetScoreNameExact     = 100
+	fleetScoreNamePrefix    = 60
+	fleetScoreNameContains  = 40
+	fleetScoreLabelExact    = 30
+	fleetScoreLabelContains = 15
+)
+
+// FleetSearchResult is a resource matching a fleet-wide search
+type FleetSearchResult struct {
+	Cluster   string                `json:"cluster"`
+	Kind      string                `json:"kind"`
+	Namespace string                `json:"namespace,omitempty"`
+	Name      string                `json:"name"`
+	Status    topology.HealthStatus `json:"status,omitempty"`
+	Score     int                   `json:"score"`
+}
+
+// FleetSearchResponse holds ranked results plus per-cluster hit counts
+type FleetSearchResponse struct {
+	Query    string              `json:"query"`
+	Total    int                 `json:"total"`
+	Results  []FleetSearchResult `json:"results"`
+	Clusters map[string]int      `json:"clusters"`
+}
+
+// SearchFleet searches every cluster's topology by name and label values.
+// Every query term must match a resource for it to be returned. Kinds, when
+// given, are matched case-insensitively.
+func SearchFleet(snapshots []ClusterSnapshot, query string, kinds []string, limit int) *FleetSearchResponse {
+	resp := &FleetSearchResponse{Query: query, Results: []FleetSearchResult{}, Clusters: make(map[string]int)}
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return resp
+	}
+	kindFilter := make(map[string]bool, len(kinds))
+	for _, k := range kinds {
+		kindFilter[strings.ToLower(k)] = true
+	}
+
+	for _, snap := range snapshots {
+		if snap.Topology == nil {
+			continue
+		}
+		for _, node := range snap.Topology.Nodes {
+			if len(kindFilter) > 0 && !kindFilter[strings.ToLower(string(node.Kind))] {
+				continue
+			}
+			score := scoreFleetNode(node, terms)
+			if score == 0 {
+				continue
+			}
+			namespace, _ := node.Data["namespace"].(string)
+			resp.Results = append(resp.Results, FleetSearchResult{
+				Cluster:   snap.Name,
+				Kind:      string(node.Kind),
+				Namespace: namespace,
+				Name:      node.Name,
+				Status:    node.Status,
+				Score:     score,
+			})
+			resp.Clusters[snap.Name]++
+		}
+	}
+
+	sort.SliceStable(resp.Results, func(i, j int) bool {
+		a, b := resp.Results[i], resp.Results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		return a.Name < b.Name
+	})
+	resp.Total = len(resp.Results)
+	if limit > 0 && len(resp.Results) > limit {
+		resp.Results = resp.Results[:limit]
+	}
+	return resp
+}
+
+// scoreFleetNode returns 0 unless every term matches the node's name or labels
+func scoreFleetNode(node topology.Node, terms []string) int {
+	name := strings.ToLower(node.Name)
+	nodeLabels := nodeLabelValues(node)
+	total := 0
+	for _, term := range terms {
+		best := 0
+		switch {
+		case name == term:
+			best = fleetScoreNameExact
+		case strings.HasPrefix(name, term):
+			best = fleetScoreNamePrefix
+		case strings.Contains(name, term):
+			best = fleetScoreNameContains
+		}
+		for _, value := range nodeLabels {
+			switch {
+			case value == term:
+				best = max(best, fleetScoreLabelExact)
+			case strings.Contains(value, term):
+				best = max(best, fleetScoreLabelContains)
+			}
+		}
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+	return total
+}
+
+// nodeLabelValues returns a node's lowercased label values. Topologies that
+// came over the wire hold labels as map[string]any.
+func nodeLabelValues(node topology.Node) []string {
+	var values []string
+	switch l := node.Data["labels"].(type) {
+	case map[string]string:
+		for _, v := range l {
+			values = append(values, strings.ToLower(v))
+		}
+	case map[string]any:
+		for _, v := range l {
+			if s, ok := v.(string); ok {
+				values = append(values, strings.ToLower(s))
+			}
+		}
+	}
+	return values
+}
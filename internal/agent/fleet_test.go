@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+	"github.com/skyhook-io/radar/internal/topology"
+)
+
+func fleetNode(kind topology.NodeKind, ns, name string, status topology.HealthStatus, labels map[string]any) topology.Node {
+	return topology.Node{ID: string(kind) + "/" + ns + "/" + name, Kind: kind, Name: name, Status: status,
+		Data: map[string]any{"namespace": ns, "labels": labels}}
+}
+
+func fleetSnapshots(now time.Time) []ClusterSnapshot {
+	warning := func(reason string, ago time.Duration) timeline.TimelineEvent {
+		return timeline.TimelineEvent{Reason: reason, Severity: timeline.SeverityWarning, Timestamp: now.Add(-ago)}
+	}
+	return []ClusterSnapshot{
+		{
+			Name: "prod-us", Connected: true,
+			Summary: &ClusterSummary{KubernetesVersion: "v1.30.4", Nodes: 5, ReadyNodes: 4, KubeletVersions: map[string]int{"v1.30.4": 5}},
+			Topology: &topology.Topology{Nodes: []topology.Node{
+				fleetNode(topology.KindDeployment, "shop", "checkout", topology.StatusDegraded, map[string]any{"app": "checkout"}),
+				fleetNode(topology.KindDeployment, "shop", "cart", topology.StatusHealthy, nil),
+				fleetNode(topology.KindService, "shop", "checkout", topology.StatusHealthy, nil),
+			}},
+			Events: []timeline.TimelineEvent{warning("BackOff", time.Minute), warning("BackOff", 2*time.Minute), warning("Evicted", 3*time.Hour)},
+		},
+		{
+			Name: "kind", Local: true, Connected: true,
+			Summary: &ClusterSummary{KubernetesVersion: "v1.31.2", Nodes: 1, ReadyNodes: 1, KubeletVersions: map[string]int{"v1.31.2": 1}},
+			Topology: &topology.Topology{Nodes: []topology.Node{
+				fleetNode(topology.KindStatefulSet, "db", "postgres", topology.StatusUnhealthy, map[string]any{"app": "checkout-db"}),
+			}},
+			Events: []timeline.TimelineEvent{warning("FailedMount", time.Minute), warning("BackOff", time.Minute)},
+		},
+	}
+}
+
+func TestBuildFleetDashboard(t *testing.T) {
+	now := time.Now()
+	dash := BuildFleetDashboard(fleetSnapshots(now), now)
+
+	if len(dash.Clusters) != 2 || dash.Clusters[0].Name != "kind" {
+		t.Fatalf("expected the local cluster first, got %+v", dash.Clusters)
+	}
+	if want := (FleetTotals{Clusters: 2, Connected: 2, Nodes: 6, ReadyNodes: 5,
+		Health: FleetHealth{Healthy: 1, Degraded: 1, Unhealthy: 1}}); dash.Totals != want {
+		t.Errorf("expected totals %+v, got %+v", want, dash.Totals)
+	}
+	if want := map[string]int{"v1.30.4": 1, "v1.31.2": 1}; !reflect.DeepEqual(dash.KubernetesVersions, want) {
+		t.Errorf("expected versions %v, got %v", want, dash.KubernetesVersions)
+	}
+	if len(dash.TopProblems) != 2 || dash.TopProblems[0].Name != "postgres" {
+		t.Errorf("expected the unhealthy StatefulSet to lead the problems, got %+v", dash.TopProblems)
+	}
+	// BackOff hit both clusters, Evicted is outside the window
+	if len(dash.TopReasons) != 2 || dash.TopReasons[0].Reason != "BackOff" || dash.TopReasons[0].Count != 3 ||
+		!reflect.DeepEqual(dash.TopReasons[0].Clusters, []string{"kind", "prod-us"}) {
+		t.Errorf("unexpected top reasons %+v", dash.TopReasons)
+	}
+
+	detail, ok := BuildFleetClusterDetail(fleetSnapshots(now), "prod-us", now)
+	if !ok || detail.Problems != 1 || len(detail.RecentWarnings) != 2 {
+		t.Errorf("unexpected drill-down %+v", detail)
+	}
+	if _, ok := BuildFleetClusterDetail(fleetSnapshots(now), "staging", now); ok {
+		t.Error("expected no drill-down for an unknown cluster")
+	}
+}
+
+func TestSearchFleet(t *testing.T) {
+	now := time.Now()
+	resp := SearchFleet(fleetSnapshots(now), "checkout", nil, 10)
+	if resp.Total != 3 || resp.Clusters["prod-us"] != 2 || resp.Clusters["kind"] != 1 {
+		t.Fatalf("unexpected results %+v", resp)
+	}
+	// Name matches outrank the label match on postgres
+	if last := resp.Results[2]; last.Name != "postgres" {
+		t.Errorf("expected the label match last, got %+v", resp.Results)
+	}
+
+	resp = SearchFleet(fleetSnapshots(now), "checkout", []string{"service"}, 10)
+	if resp.Total != 1 || resp.Results[0].Kind != "Service" {
+		t.Errorf("expected only the Service, got %+v", resp.Results)
+	}
+	if resp := SearchFleet(fleetSnapshots(now), "checkout nope", nil, 10); resp.Total != 0 {
+		t.Errorf("expected every term to have to match, got %+v", resp.Results)
+	}
+}
@@ -0,0 +1,266 @@
+package agent
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+	"github.com/skyhook-io/radar/internal/topology"
+)
+
+const (
+	// Per-cluster history kept in memory on the central server
+	maxRemoteEvents  = 1000
+	maxRemoteChanges = 500
+)
+
+// ErrUnknownCluster is returned for clusters no agent has reported
+var ErrUnknownCluster = errors.New("has no connected agent")
+
+// RemoteCluster summarizes a cluster reporting through an agent
+type RemoteCluster struct {
+	Name         string    `json:"name"`
+	Context      string    `json:"context,omitempty"`
+	AgentVersion string    `json:"agentVersion,omitempty"`
+	Connected    bool      `json:"connected"`
+	ConnectedAt  time.Time `json:"connectedAt"`
+	LastSeen     time.Time `json:"lastSeen"`
+	Changes      int64     `json:"changes"` // Resource changes received since the hub started
+	Events       int64     `json:"events"`  // Timeline events received since the hub started
+	Nodes        int       `json:"nodes"`   // Nodes in the latest topology
+}
+
+type remoteState struct {
+	summary        RemoteCluster
+	generation     int // Bumped per connection so a stale stream can't mark a newer one disconnected
+	topology       *topology.Topology
+	clusterSummary *ClusterSummary
+	events         []timeline.TimelineEvent // Oldest first
+	changes        []Change                 // Oldest first
+}
+
+// Hub receives agent streams and keeps the latest state of each remote cluster
+type Hub struct {
+	token string
+
+	mu       sync.RWMutex
+	clusters map[string]*remoteState
+
+	server *grpc.Server
+}
+
+// NewHub creates a hub that accepts agents presenting token
+func NewHub(token string) (*Hub, error) {
+	if token == "" {
+		return nil, errors.New("an agent token is required")
+	}
+	return &Hub{token: token, clusters: make(map[string]*remoteState)}, nil
+}
+
+// Serve accepts agent connections on ln until Stop is called. Without a TLS
+// config the connection is plaintext, which is only appropriate behind a
+// TLS-terminating proxy or on a trusted network.
+func (h *Hub) Serve(ln net.Listener, tlsConfig *tls.Config) error {
+	opts := []grpc.ServerOption{
+		grpc.ForceServerCodec(jsonCodec{}),
+		grpc.MaxRecvMsgSize(maxMessageSize),
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	h.mu.Lock()
+	h.server = grpc.NewServer(opts...)
+	h.server.RegisterService(&serviceDesc, h)
+	server := h.server
+	h.mu.Unlock()
+	return server.Serve(ln)
+}
+
+// Stop closes all agent streams and the listener
+func (h *Hub) Stop() {
+	h.mu.RLock()
+	server := h.server
+	h.mu.RUnlock()
+	if server != nil {
+		server.Stop()
+	}
+}
+
+func (h *Hub) authorize(stream grpc.ServerStream) error {
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	values := md.Get(tokenHeader)
+	if len(values) == 0 {
+		return status.Error(codes.Unauthenticated, "missing agent token")
+	}
+	token, _ := strings.CutPrefix(values[0], "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		return status.Error(codes.Unauthenticated, "invalid agent token")
+	}
+	return nil
+}
+
+func (h *Hub) handleStream(stream grpc.ServerStream) error {
+	if err := h.authorize(stream); err != nil {
+		return err
+	}
+
+	var first Batch
+	if err := stream.RecvMsg(&first); err != nil {
+		return err
+	}
+	if first.Hello == nil || first.Hello.Cluster == "" {
+		return status.Error(codes.InvalidArgument, "first message must identify the cluster")
+	}
+	hello := *first.Hello
+	generation := h.connect(hello)
+	log.Printf("[agent] Cluster %q connected (agent %s)", hello.Cluster, hello.AgentVersion)
+	defer func() {
+		h.disconnect(hello.Cluster, generation)
+		log.Printf("[agent] Cluster %q disconnected", hello.Cluster)
+	}()
+
+	batches := 1
+	h.apply(hello.Cluster, &first)
+	for {
+		var batch Batch
+		if err := stream.RecvMsg(&batch); err != nil {
+			if err == io.EOF {
+				return stream.SendMsg(&Ack{Batches: batches})
+			}
+			return err
+		}
+		batches++
+		h.apply(hello.Cluster, &batch)
+	}
+}
+
+func (h *Hub) connect(hello Hello) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	state := h.clusters[hello.Cluster]
+	if state == nil {
+		state = &remoteState{}
+		h.clusters[hello.Cluster] = state
+	}
+	now := time.Now()
+	state.generation++
+	state.summary.Name = hello.Cluster
+	state.summary.Context = hello.Context
+	state.summary.AgentVersion = hello.AgentVersion
+	state.summary.Connected = true
+	state.summary.ConnectedAt = now
+	state.summary.LastSeen = now
+	return state.generation
+}
+
+func (h *Hub) disconnect(cluster string, generation int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if state := h.clusters[cluster]; state != nil && state.generation == generation {
+		state.summary.Connected = false
+	}
+}
+
+func (h *Hub) apply(cluster string, batch *Batch) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	state := h.clusters[cluster]
+	if state == nil {
+		return
+	}
+	state.summary.LastSeen = time.Now()
+	state.summary.Changes += int64(len(batch.Changes))
+	state.summary.Events += int64(len(batch.Events))
+	if batch.Topology != nil {
+		state.topology = batch.Topology
+		state.summary.Nodes = len(batch.Topology.Nodes)
+	}
+	if batch.Summary != nil {
+		state.clusterSummary = batch.Summary
+	}
+	state.changes = appendCapped(state.changes, batch.Changes, maxRemoteChanges)
+	state.events = appendCapped(state.events, batch.Events, maxRemoteEvents)
+}
+
+// appendCapped appends items and drops the oldest beyond max
+func appendCapped[T any](existing, items []T, max int) []T {
+	existing = append(existing, items...)
+	if over := len(existing) - max; over > 0 {
+		existing = append(existing[:0], existing[over:]...)
+	}
+	return existing
+}
+
+// Clusters returns all clusters that have connected, sorted by name
+func (h *Hub) Clusters() []RemoteCluster {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	result := make([]RemoteCluster, 0, len(h.clusters))
+	for _, state := range h.clusters {
+		result = append(result, state.summary)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Topology returns the latest topology an agent sent for the cluster
+func (h *Hub) Topology(cluster string) (*topology.Topology, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	state := h.clusters[cluster]
+	if state == nil {
+		return nil, fmt.Errorf("cluster %q %w", cluster, ErrUnknownCluster)
+	}
+	if state.topology == nil {
+		return &topology.Topology{Nodes: []topology.Node{}, Edges: []topology.Edge{}}, nil
+	}
+	return state.topology, nil
+}
+
+// Events returns the cluster's most recent timeline events, newest first
+func (h *Hub) Events(cluster string, limit int) ([]timeline.TimelineEvent, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	state := h.clusters[cluster]
+	if state == nil {
+		return nil, fmt.Errorf("cluster %q %w", cluster, ErrUnknownCluster)
+	}
+	return newestFirst(state.events, limit), nil
+}
+
+// Changes returns the cluster's most recent resource changes, newest first
+func (h *Hub) Changes(cluster string, limit int) ([]Change, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	state := h.clusters[cluster]
+	if state == nil {
+		return nil, fmt.Errorf("cluster %q %w", cluster, ErrUnknownCluster)
+	}
+	return newestFirst(state.changes, limit), nil
+}
+
+func newestFirst[T any](items []T, limit int) []T {
+	if limit <= 0 || limit > len(items) {
+		limit = len(items)
+	}
+	result := make([]T, 0, limit)
+	for i := len(items) - 1; i >= 0 && len(result) < limit; i-- {
+		result = append(result, items[i])
+	}
+	return result
+}
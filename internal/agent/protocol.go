@@ -0,0 +1,97 @@
+// Package agent streams a remote cluster's resource changes, timeline events
+// and topology to a central Radar server over gRPC, so the central server
+// doesn't need network access to every cluster's API server.
+//
+// The wire format is JSON over a hand-written gRPC service (no generated
+// protobuf code), gzip-compressed per message.
+package agent
+
+import (
+	"encoding/json"
+	"time"
+
+	"google.golang.org/grpc"
+	_ "google.golang.org/grpc/encoding/gzip" // Registers the gzip compressor on both ends
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/timeline"
+	"github.com/skyhook-io/radar/internal/topology"
+)
+
+const (
+	serviceName = "radar.agent.v1.Agent"
+	streamName  = "Stream"
+	streamPath  = "/" + serviceName + "/" + streamName
+
+	// tokenHeader carries the shared agent token as "Bearer <token>"
+	tokenHeader = "authorization"
+
+	// maxMessageSize bounds a single batch; full topologies of large clusters
+	// exceed gRPC's 4MB default even before compression
+	maxMessageSize = 64 << 20
+)
+
+// Hello identifies the agent. It is the first batch on every stream.
+type Hello struct {
+	Cluster      string `json:"cluster"`
+	Context      string `json:"context,omitempty"`
+	AgentVersion string `json:"agentVersion,omitempty"`
+}
+
+// Change mirrors k8s.ResourceChange with stable JSON names
+type Change struct {
+	Kind      string        `json:"kind"`
+	Namespace string        `json:"namespace,omitempty"`
+	Name      string        `json:"name"`
+	UID       string        `json:"uid,omitempty"`
+	Operation string        `json:"operation"`
+	Diff      *k8s.DiffInfo `json:"diff,omitempty"`
+}
+
+func changeFromResourceChange(c k8s.ResourceChange) Change {
+	return Change{Kind: c.Kind, Namespace: c.Namespace, Name: c.Name, UID: c.UID, Operation: c.Operation, Diff: c.Diff}
+}
+
+// Batch is one message from agent to server. Topology and Summary, when set,
+// replace the server's copies for the cluster.
+type Batch struct {
+	Hello    *Hello                   `json:"hello,omitempty"`
+	SentAt   time.Time                `json:"sentAt"`
+	Changes  []Change                 `json:"changes,omitempty"`
+	Events   []timeline.TimelineEvent `json:"events,omitempty"`
+	Topology *topology.Topology       `json:"topology,omitempty"`
+	Summary  *ClusterSummary          `json:"summary,omitempty"`
+}
+
+// Ack is the server's reply when the agent closes its stream
+type Ack struct {
+	Batches int `json:"batches"`
+}
+
+// jsonCodec encodes messages as JSON. It is forced on both ends of the agent
+// connection rather than registered globally, so other gRPC clients in the
+// process (e.g. Hubble) keep the default protobuf codec.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                       { return "json" }
+
+// streamHandler is implemented by Hub
+type streamHandler interface {
+	handleStream(stream grpc.ServerStream) error
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*streamHandler)(nil),
+	Streams: []grpc.StreamDesc{{
+		StreamName: streamName,
+		Handler: func(srv any, stream grpc.ServerStream) error {
+			return srv.(streamHandler).handleStream(stream)
+		},
+		ClientStreams: true,
+	}},
+}
+
+var streamDesc = &serviceDesc.Streams[0]
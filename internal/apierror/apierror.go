@@ -0,0 +1,149 @@
+// Package apierror defines the JSON error body every Radar API handler
+// responds with: a stable machine-readable code, the HTTP status, a
+// human-readable message and an optional remediation hint. Clients switch on
+// the code (and may localize it); the message is English and may change.
+package apierror
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Code identifies a kind of failure. Codes are stable API; add new ones
+// rather than changing existing ones.
+type Code string
+
+const (
+	BadRequest           Code = "BAD_REQUEST"
+	Unauthorized         Code = "UNAUTHORIZED"
+	RBACForbidden        Code = "RBAC_FORBIDDEN"
+	PermissionDenied     Code = "PERMISSION_DENIED" // Inside a container, not Kubernetes RBAC
+	NotFound             Code = "NOT_FOUND"
+	MethodNotAllowed     Code = "METHOD_NOT_ALLOWED"
+	Conflict             Code = "CONFLICT"
+	PreconditionFailed   Code = "PRECONDITION_FAILED"
+	PayloadTooLarge      Code = "PAYLOAD_TOO_LARGE"
+	UnsupportedMediaType Code = "UNSUPPORTED_MEDIA_TYPE"
+	ValidationFailed     Code = "VALIDATION_FAILED"
+	RateLimited          Code = "RATE_LIMITED"
+	Internal             Code = "INTERNAL"
+	NotImplemented       Code = "NOT_IMPLEMENTED"
+	UpstreamError        Code = "UPSTREAM_ERROR"
+	Unavailable          Code = "UNAVAILABLE"
+	NotConnected         Code = "NOT_CONNECTED"
+	Offline              Code = "OFFLINE"
+	ReadOnly             Code = "READ_ONLY"
+	Timeout              Code = "TIMEOUT"
+	CrossOrigin          Code = "CROSS_ORIGIN"
+)
+
+// Response is the error body. Error repeats Message for clients written
+// against the earlier {"error": "..."} bodies.
+type Response struct {
+	Code    Code   `json:"code"`
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+	Error   string `json:"error"`
+}
+
+// hints are the default remediation for codes where there's a useful one
+var hints = map[Code]string{
+	Unauthorized:     "The cluster rejected the credentials; refresh them (e.g. re-run your cloud CLI login) and retry.",
+	RBACForbidden:    "The Kubernetes identity Radar uses lacks this permission. Grant it with a Role/ClusterRole binding or switch to a context that has it.",
+	PermissionDenied: "The container user can't access this path. Try a path it owns, or a container running as a different user.",
+	NotConnected:     "Radar isn't connected to a cluster. Check the kubeconfig and network, or switch to another context.",
+	Offline:          "Radar is running with --offline; this feature needs internet access.",
+	ReadOnly:         "Radar is in read-only mode. It can only be turned off in the settings file or with --read-only=false, followed by a restart.",
+	Timeout:          "The operation took too long. Retry, or narrow the request (e.g. filter by namespace).",
+	CrossOrigin:      "Radar only accepts changes from its own UI. If this site should reach it, start Radar with --allowed-origins set to its origin.",
+}
+
+// CodeFor derives a code from a status and message, for handlers that only
+// provide those
+func CodeFor(status int, message string) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return BadRequest
+	case http.StatusUnauthorized:
+		return Unauthorized
+	case http.StatusForbidden:
+		if strings.HasPrefix(message, "permission denied") {
+			return PermissionDenied
+		}
+		return RBACForbidden
+	case http.StatusNotFound:
+		return NotFound
+	case http.StatusMethodNotAllowed:
+		return MethodNotAllowed
+	case http.StatusConflict:
+		return Conflict
+	case http.StatusPreconditionFailed:
+		return PreconditionFailed
+	case http.StatusRequestEntityTooLarge:
+		return PayloadTooLarge
+	case http.StatusUnsupportedMediaType:
+		return UnsupportedMediaType
+	case http.StatusUnprocessableEntity:
+		return ValidationFailed
+	case http.StatusTooManyRequests:
+		return RateLimited
+	case http.StatusNotImplemented:
+		return NotImplemented
+	case http.StatusBadGateway:
+		return UpstreamError
+	case http.StatusServiceUnavailable:
+		return Unavailable
+	case http.StatusGatewayTimeout:
+		return Timeout
+	}
+	if isRBACMessage(message) {
+		return RBACForbidden
+	}
+	return Internal
+}
+
+// isRBACMessage matches Kubernetes "forbidden" errors passed through as text
+// (e.g. `pods is forbidden: User "x" cannot list resource ...`)
+func isRBACMessage(message string) bool {
+	return strings.Contains(message, "is forbidden: User ") || strings.Contains(message, "is forbidden: user ")
+}
+
+// New builds an error body with the default hint for code
+func New(status int, code Code, message string) Response {
+	return Response{Code: code, Status: status, Message: message, Hint: hints[code], Error: message}
+}
+
+// Write responds with a structured error, deriving the code from the status
+// and message. Kubernetes RBAC errors passed through with a 500 are answered
+// as 403 RBAC_FORBIDDEN.
+func Write(w http.ResponseWriter, status int, message string) {
+	code := CodeFor(status, message)
+	if code == RBACForbidden {
+		status = http.StatusForbidden
+	}
+	WriteResponse(w, New(status, code, message))
+}
+
+// WriteCode responds with a structured error using an explicit code
+func WriteCode(w http.ResponseWriter, status int, code Code, message string) {
+	WriteResponse(w, New(status, code, message))
+}
+
+// Body is a Response, or a struct embedding one to add fields
+type Body interface {
+	status() int
+}
+
+func (r Response) status() int { return r.Status }
+
+// WriteResponse responds with a Response or a struct embedding one
+func WriteResponse(w http.ResponseWriter, body Body) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(body.status())
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Failed to encode error response: %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package apierror
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCodeFor(t *testing.T) {
+	tests := []struct {
+		status  int
+		message string
+		want    Code
+	}{
+		{http.StatusBadRequest, "namespace is required", BadRequest},
+		{http.StatusForbidden, "insufficient permissions to list pods", RBACForbidden},
+		{http.StatusForbidden, "permission denied: target path is not writable by the container user", PermissionDenied},
+		{http.StatusNotFound, "pod not found", NotFound},
+		{http.StatusServiceUnavailable, "Resource cache not available", Unavailable},
+		{http.StatusInternalServerError, `pods is forbidden: User "dev" cannot list resource "pods" in API group ""`, RBACForbidden},
+		{http.StatusInternalServerError, "connection reset by peer", Internal},
+	}
+	for _, tt := range tests {
+		if got := CodeFor(tt.status, tt.message); got != tt.want {
+			t.Errorf("CodeFor(%d, %q) = %s, want %s", tt.status, tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Write(rec, http.StatusInternalServerError, `secrets is forbidden: User "dev" cannot get resource "secrets"`)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected RBAC errors to be answered as 403, got %d", rec.Code)
+	}
+	var resp Response
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Code != RBACForbidden || resp.Status != http.StatusForbidden || resp.Hint == "" {
+		t.Errorf("unexpected body %+v", resp)
+	}
+	if resp.Error != resp.Message {
+		t.Errorf("expected error to repeat the message for older clients, got %q vs %q", resp.Error, resp.Message)
+	}
+}
+
+func TestWriteResponseExtraFields(t *testing.T) {
+	rec := httptest.NewRecorder()
+	WriteResponse(rec, struct {
+		Response
+		Offline bool `json:"offline"`
+	}{New(http.StatusServiceUnavailable, Offline, "offline mode"), true})
+
+	var body map[string]any
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if rec.Code != http.StatusServiceUnavailable || body["code"] != "OFFLINE" || body["offline"] != true {
+		t.Errorf("unexpected response %d %v", rec.Code, body)
+	}
+}
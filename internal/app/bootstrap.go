@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -12,7 +13,9 @@ import (
 
 	"github.com/skyhook-io/radar/internal/helm"
 	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/outbound"
 	"github.com/skyhook-io/radar/internal/server"
+	"github.com/skyhook-io/radar/internal/settings"
 	"github.com/skyhook-io/radar/internal/static"
 	"github.com/skyhook-io/radar/internal/timeline"
 	"github.com/skyhook-io/radar/internal/traffic"
@@ -21,28 +24,98 @@ import (
 
 // AppConfig holds all parsed configuration for the Radar application.
 type AppConfig struct {
-	Kubeconfig       string
-	KubeconfigDirs   []string
-	Namespace        string
-	Port             int
-	NoBrowser        bool
-	DevMode          bool
-	HistoryLimit     int
-	DebugEvents      bool
-	FakeInCluster    bool
-	DisableHelmWrite bool
-	TimelineStorage  string
-	TimelineDBPath   string
-	PrometheusURL    string
-	Version          string
+	Kubeconfig             string
+	KubeconfigDirs         []string
+	Namespace              string
+	Port                   int
+	Listener               net.Listener // Pre-bound server listener (see Listen); Port must match it
+	NoBrowser              bool
+	DevMode                bool
+	HistoryLimit           int
+	DebugEvents            bool
+	StartupTrace           bool // Time initialization phases, served at /api/debug/startup
+	FakeInCluster          bool
+	DisableHelmWrite       bool
+	Demo                   bool          // Synthetic in-memory cluster, no kubeconfig needed
+	RecordPath             string        // Capture the SSE event stream to this file
+	ReplayPath             string        // Replay a recorded event stream instead of live events
+	ReplaySpeed            float64       // Replay speed multiplier (0 = as fast as possible)
+	MaxDynamicInformers    int           // Cap on dynamic (CRD) informers, 0 = unlimited
+	MaxFileEditSize        int64         // Largest pod file the file editor opens or saves, 0 = 2 MiB
+	MaxExecSessions        int           // Concurrent terminals per client, 0 = unlimited
+	MaxPortForwards        int           // Concurrent port forwards per client, 0 = unlimited
+	ExecIdleTimeout        time.Duration // Close idle terminals after this long, 0 = never
+	PortForwardIdleTimeout time.Duration // Stop port forwards without new connections after this long, 0 = never
+	TimelineStorage        string
+	TimelineDBPath         string
+	PrometheusURL          string
+	PrometheusConfig       string              // JSON file with metrics URL/auth defaults and per-context overrides
+	PrometheusAuth         traffic.MetricsAuth // Flag-provided auth, overrides the config file defaults
+	TrafficHistory         bool
+	TrafficInterval        time.Duration
+	TrafficRetention       time.Duration
+	Proxy                  string   // Outbound HTTP proxy, overrides $HTTPS_PROXY/$HTTP_PROXY
+	NoProxy                string   // Hosts that bypass the proxy, overrides $NO_PROXY
+	CAFile                 string   // Extra CA bundle trusted for outbound HTTPS
+	Offline                bool     // Air-gapped: no update checks, registry or ArtifactHub calls
+	AgentListen            string   // Address to accept remote cluster agents on (empty = disabled)
+	AllowedOrigins         []string // Extra browser origins allowed to call the API (see ParseAllowedOrigins)
+	UserHeader             string   // Header an auth proxy sets to the authenticated user ("" = no per-user attribution)
+	UserIdentity           string   // How UserHeader users reach the API server: user-agent or impersonate
+	AgentToken             string   // Shared secret agents must present
+	AgentTLSCert           string   // TLS certificate for the agent listener
+	AgentTLSKey            string   // TLS key for the agent listener
+	HelmKeychain           bool     // Store Helm OCI registry logins in the OS keychain (desktop)
+	ConfigPath             string   // Runtime settings file (PUT /api/config), "" = ~/.radar/config.json
+	ReadOnly               bool     // Start in read-only mode; only the settings file or this flag can turn it off
+	Version                string
 }
 
 // SetGlobals applies debug/test flags to global state.
 func SetGlobals(cfg AppConfig) {
-	k8s.DebugEvents = cfg.DebugEvents
+	k8s.DebugEvents.Store(cfg.DebugEvents)
 	k8s.ForceInCluster = cfg.FakeInCluster
 	k8s.ForceDisableHelmWrite = cfg.DisableHelmWrite
+	helm.UseOSKeychain = cfg.HelmKeychain
+	k8s.SetMaxDynamicInformers(cfg.MaxDynamicInformers)
 	versionpkg.SetCurrent(cfg.Version)
+	if cfg.StartupTrace {
+		k8s.EnableStartupTrace()
+		log.Printf("Startup trace enabled, see /api/debug/startup")
+	}
+}
+
+// ConfigureIdentity enables attributing API server calls to the user an auth
+// proxy authenticated (--user-header)
+func ConfigureIdentity(cfg AppConfig) error {
+	if cfg.UserHeader == "" {
+		return nil
+	}
+	if err := k8s.SetIdentityMode(k8s.IdentityMode(cfg.UserIdentity)); err != nil {
+		return fmt.Errorf("invalid --user-identity: %w", err)
+	}
+	log.Printf("Attributing API calls to the %s request header user (%s)", cfg.UserHeader, cfg.UserIdentity)
+	return nil
+}
+
+// ConfigureOutbound applies proxy and CA settings to every outbound HTTP
+// client (update checks, ArtifactHub, registries, metrics queries) and
+// enables offline mode. Must be called before InitializeCluster.
+func ConfigureOutbound(cfg AppConfig) error {
+	outbound.SetOffline(cfg.Offline)
+	if cfg.Offline {
+		log.Printf("Offline mode: update checks, registry and ArtifactHub calls are disabled")
+	}
+	if err := outbound.Configure(outbound.Config{Proxy: cfg.Proxy, NoProxy: cfg.NoProxy, CAFile: cfg.CAFile}); err != nil {
+		return fmt.Errorf("invalid outbound HTTP settings: %w", err)
+	}
+	if cfg.Proxy != "" {
+		log.Printf("Using outbound HTTP proxy from --proxy") // URL not logged, it may carry credentials
+	}
+	if cfg.CAFile != "" {
+		log.Printf("Trusting additional CAs from %s", cfg.CAFile)
+	}
+	return nil
 }
 
 // InitializeK8s creates and configures the Kubernetes client.
@@ -50,6 +123,7 @@ func InitializeK8s(cfg AppConfig) error {
 	err := k8s.Initialize(k8s.InitOptions{
 		KubeconfigPath: cfg.Kubeconfig,
 		KubeconfigDirs: cfg.KubeconfigDirs,
+		Demo:           cfg.Demo,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to initialize K8s client: %w", err)
@@ -59,7 +133,9 @@ func InitializeK8s(cfg AppConfig) error {
 		k8s.SetFallbackNamespace(cfg.Namespace)
 	}
 
-	if len(cfg.KubeconfigDirs) > 0 {
+	if cfg.Demo {
+		log.Printf("Using synthetic demo cluster")
+	} else if len(cfg.KubeconfigDirs) > 0 {
 		log.Printf("Using kubeconfigs from directories: %v", cfg.KubeconfigDirs)
 	} else if kubepath := k8s.GetKubeconfigPath(); kubepath != "" {
 		log.Printf("Using kubeconfig: %s", kubepath)
@@ -90,26 +166,50 @@ func BuildTimelineStoreConfig(cfg AppConfig) timeline.StoreConfig {
 			dbPath = filepath.Join(homeDir, ".radar", "timeline.db")
 		}
 		storeCfg.Path = dbPath
+		storeCfg.PresetsPath = filepath.Join(filepath.Dir(dbPath), "timeline-presets.json")
+		storeCfg.SavedQueriesPath = filepath.Join(filepath.Dir(dbPath), "timeline-queries.json")
 	}
 	return storeCfg
 }
 
 // RegisterCallbacks registers Helm, timeline, and traffic reset/reinit functions
+// and configures metrics history persistence
 // used for both initial cluster initialization and context switching.
 // Must be called before InitializeCluster.
 func RegisterCallbacks(cfg AppConfig, timelineStoreCfg timeline.StoreConfig) {
-	k8s.RegisterHelmFuncs(helm.ResetClient, helm.ReinitClient)
+	// Helm talks to the apiserver directly, which the demo cluster doesn't have
+	if !cfg.Demo {
+		k8s.RegisterHelmFuncs(helm.ResetClient, helm.ReinitClient)
+	}
 
 	k8s.RegisterTimelineFuncs(timeline.ResetStore, func() error {
-		return timeline.ReinitStore(timelineStoreCfg)
+		// Runs after the client switched, so events are recorded under the new context
+		storeCfg := timelineStoreCfg
+		storeCfg.Context = k8s.GetContextName()
+		if limit := settings.Get().HistoryLimit; limit > 0 {
+			storeCfg.MaxSize = limit // May have been changed through PUT /api/config
+		}
+		return timeline.ReinitStore(storeCfg)
 	})
 
-	if cfg.PrometheusURL != "" {
-		u, err := url.Parse(cfg.PrometheusURL)
-		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
-			log.Fatalf("Invalid --prometheus-url %q: must be a valid HTTP(S) URL (e.g., http://prometheus-server.monitoring:9090)", cfg.PrometheusURL)
+	// Persist metrics history alongside the timeline so graphs survive restarts
+	if timelineStoreCfg.Type == timeline.StoreTypeSQLite {
+		k8s.SetMetricsHistoryDBPath(timelineStoreCfg.Path)
+	}
+
+	traffic.SetMetricsConfig(buildMetricsConfig(cfg))
+
+	if cfg.TrafficHistory {
+		// Share the timeline database when it's persistent, otherwise keep flows in memory
+		historyCfg := traffic.HistoryConfig{
+			Enabled:   true,
+			Interval:  cfg.TrafficInterval,
+			Retention: cfg.TrafficRetention,
+		}
+		if timelineStoreCfg.Type == timeline.StoreTypeSQLite {
+			historyCfg.DBPath = timelineStoreCfg.Path
 		}
-		traffic.SetMetricsURL(cfg.PrometheusURL)
+		traffic.SetHistoryConfig(historyCfg)
 	}
 
 	k8s.RegisterTrafficFuncs(traffic.Reset, func() error {
@@ -121,11 +221,38 @@ func RegisterCallbacks(cfg AppConfig, timelineStoreCfg timeline.StoreConfig) {
 func CreateServer(cfg AppConfig) *server.Server {
 	serverCfg := server.Config{
 		Port:       cfg.Port,
+		Listener:   cfg.Listener,
 		DevMode:    cfg.DevMode,
 		StaticFS:   static.FS,
 		StaticRoot: "dist",
+
+		MaxFileEditSize: cfg.MaxFileEditSize,
+		AllowedOrigins:  cfg.AllowedOrigins,
+		UserHeader:      cfg.UserHeader,
+		SessionLimits: server.SessionLimits{
+			MaxExecPerClient:         cfg.MaxExecSessions,
+			MaxPortForwardsPerClient: cfg.MaxPortForwards,
+			ExecIdleTimeout:          cfg.ExecIdleTimeout,
+			PortForwardIdleTimeout:   cfg.PortForwardIdleTimeout,
+		},
+	}
+	srv := server.New(serverCfg)
+	if cfg.RecordPath != "" {
+		if err := srv.RecordEvents(cfg.RecordPath); err != nil {
+			log.Fatalf("Invalid --record: %v", err)
+		}
+	}
+	if cfg.ReplayPath != "" {
+		if err := srv.ReplayEvents(cfg.ReplayPath, cfg.ReplaySpeed); err != nil {
+			log.Fatalf("Invalid --replay: %v", err)
+		}
+	}
+	if cfg.AgentListen != "" {
+		if err := srv.ServeAgents(cfg.AgentListen, cfg.AgentToken, cfg.AgentTLSCert, cfg.AgentTLSKey); err != nil {
+			log.Fatalf("Invalid --agent-listen: %v", err)
+		}
 	}
-	return server.New(serverCfg)
+	return srv
 }
 
 // InitializeCluster connects to the cluster and initializes all subsystems.
@@ -138,7 +265,10 @@ func InitializeCluster() {
 		ProgressMsg: "Testing cluster connectivity...",
 	})
 
-	if err := CheckClusterAccess(); err != nil {
+	endAccess := k8s.TraceStartupPhase("cluster-access")
+	err := CheckClusterAccess()
+	endAccess(err)
+	if err != nil {
 		k8s.SetConnectionStatus(k8s.ConnectionStatus{
 			State:     k8s.StateDisconnected,
 			Context:   k8s.GetContextName(),
@@ -176,7 +306,12 @@ func InitializeCluster() {
 // Shutdown performs graceful teardown of all subsystems and the HTTP server.
 func Shutdown(srv *server.Server) {
 	log.Println("Shutting down...")
-	srv.Stop()
+	if registration != nil {
+		registration.Close()
+	}
+	if srv != nil {
+		srv.Stop()
+	}
 	k8s.ResetAllSubsystems()
 }
 
@@ -189,6 +324,9 @@ func CheckClusterAccess() error {
 	if clientset == nil {
 		return fmt.Errorf("kubernetes client not initialized")
 	}
+	if k8s.IsDemoMode() {
+		return nil
+	}
 
 	_, err := clientset.Discovery().RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
 	if err != nil {
@@ -197,6 +335,99 @@ func CheckClusterAccess() error {
 	return nil
 }
 
+// buildMetricsConfig merges the --prometheus-config file with the metrics flags.
+// Invalid settings are fatal so misconfigured credentials surface at startup.
+func buildMetricsConfig(cfg AppConfig) traffic.MetricsConfig {
+	var metricsCfg traffic.MetricsConfig
+	if cfg.PrometheusConfig != "" {
+		loaded, err := traffic.LoadMetricsConfig(cfg.PrometheusConfig)
+		if err != nil {
+			log.Fatalf("Invalid --prometheus-config: %v", err)
+		}
+		metricsCfg = loaded
+	}
+	if cfg.PrometheusURL != "" {
+		metricsCfg.URL = cfg.PrometheusURL
+	}
+
+	// Flags override individual file settings
+	auth := &metricsCfg.Auth
+	flags := cfg.PrometheusAuth
+	if flags.Username != "" {
+		auth.Username, auth.Password = flags.Username, flags.Password
+		auth.BearerToken, auth.BearerTokenFile = "", ""
+	}
+	if flags.BearerToken != "" || flags.BearerTokenFile != "" {
+		auth.BearerToken, auth.BearerTokenFile = flags.BearerToken, flags.BearerTokenFile
+		auth.Username, auth.Password = "", ""
+	}
+	for name, value := range flags.Headers {
+		if auth.Headers == nil {
+			auth.Headers = make(map[string]string)
+		}
+		auth.Headers[name] = value
+	}
+	if flags.CAFile != "" {
+		auth.CAFile = flags.CAFile
+	}
+	auth.InsecureSkipVerify = auth.InsecureSkipVerify || flags.InsecureSkipVerify
+
+	if metricsCfg.URL != "" && !isHTTPURL(metricsCfg.URL) {
+		log.Fatalf("Invalid --prometheus-url %q: must be a valid HTTP(S) URL (e.g., http://prometheus-server.monitoring:9090)", metricsCfg.URL)
+	}
+	for name, settings := range metricsCfg.Contexts {
+		if settings.URL != "" && !isHTTPURL(settings.URL) {
+			log.Fatalf("Invalid metrics URL %q for context %q: must be a valid HTTP(S) URL", settings.URL, name)
+		}
+	}
+	if err := metricsCfg.Validate(); err != nil {
+		log.Fatalf("Invalid metrics auth settings: %v", err)
+	}
+	return metricsCfg
+}
+
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+}
+
+// ParseMetricsHeaders parses a comma-separated list of Name=Value headers
+func ParseMetricsHeaders(headers string) map[string]string {
+	if headers == "" {
+		return nil
+	}
+	result := make(map[string]string)
+	for _, pair := range strings.Split(headers, ",") {
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			log.Fatalf("Invalid --prometheus-headers entry %q: expected Name=Value", pair)
+		}
+		result[name] = strings.TrimSpace(value)
+	}
+	return result
+}
+
+// ParseAllowedOrigins parses comma-separated browser origins such as
+// https://radar.example.com. Only a scheme and host (with optional port) are
+// allowed; paths, wildcards, and trailing slashes are rejected.
+func ParseAllowedOrigins(origins string) ([]string, error) {
+	var result []string
+	for _, origin := range strings.Split(origins, ",") {
+		origin = strings.TrimSpace(origin)
+		if origin == "" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+			u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil || strings.Contains(u.Host, "*") {
+			return nil, fmt.Errorf("invalid origin %q: want scheme://host[:port]", origin)
+		}
+		result = append(result, origin)
+	}
+	return result, nil
+}
+
 // ParseKubeconfigDirs splits a comma-separated directory string into a slice.
 func ParseKubeconfigDirs(dirs string) []string {
 	if dirs == "" {
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"log"
+	"strings"
+
+	"github.com/skyhook-io/radar/internal/instances"
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// registration is this process's entry in the instance registry, removed by Shutdown
+var registration *instances.Registration
+
+// kubeconfigKey identifies where contexts come from, so same-named contexts
+// from different kubeconfigs aren't mistaken for each other
+func kubeconfigKey(cfg AppConfig) string {
+	if len(cfg.KubeconfigDirs) > 0 {
+		return strings.Join(cfg.KubeconfigDirs, ",")
+	}
+	return k8s.GetKubeconfigPath()
+}
+
+// FindInstance returns a running Radar watching the same context from the
+// same kubeconfig, if one still answers. An empty mode matches CLI and
+// desktop instances. Call after InitializeK8s.
+func FindInstance(cfg AppConfig, mode string) (instances.Instance, bool) {
+	if cfg.Demo || k8s.IsInCluster() {
+		return instances.Instance{}, false
+	}
+	inst, ok := instances.Find(k8s.GetContextName(), kubeconfigKey(cfg), mode)
+	if !ok {
+		return instances.Instance{}, false
+	}
+	// The registry can lag behind a context switch, or the port can have been reused
+	if alive, runningContext := probeRadar(inst.URL()); !alive || runningContext != k8s.GetContextName() {
+		return instances.Instance{}, false
+	}
+	return inst, true
+}
+
+// RegisterInstance records this process in the instance registry and keeps
+// its context current across switches. Failures are logged, not fatal.
+func RegisterInstance(cfg AppConfig, port int, mode string) {
+	if cfg.Demo || k8s.IsInCluster() {
+		return
+	}
+	reg, err := instances.Register(instances.Instance{
+		Port:       port,
+		Context:    k8s.GetContextName(),
+		Kubeconfig: kubeconfigKey(cfg),
+		Mode:       mode,
+		Version:    cfg.Version,
+	})
+	if err != nil {
+		log.Printf("Instance registry unavailable: %v", err)
+		return
+	}
+	registration = reg
+	k8s.OnContextSwitch(reg.SetContext)
+}
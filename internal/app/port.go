@@ -0,0 +1,85 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	portFallbackAttempts = 20 // Ports tried after the requested one before letting the OS pick
+	instanceProbeTimeout = 2 * time.Second
+)
+
+// RunningInstanceError is returned by Listen (and FindInstance callers) when
+// another Radar already serves the context, which can simply be opened
+// instead of starting a second one
+type RunningInstanceError struct {
+	URL string
+}
+
+func (e *RunningInstanceError) Error() string {
+	return fmt.Sprintf("radar is already running at %s", e.URL)
+}
+
+// Listen binds the server port. When it's taken by another Radar watching
+// reuseContext it returns a *RunningInstanceError (never when reuseContext is
+// empty). When it's taken by anything else it fails if strict is set, and
+// otherwise falls back to the next free port (or one picked by the OS).
+func Listen(port int, strict bool, reuseContext string) (net.Listener, error) {
+	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err == nil || port == 0 {
+		return ln, err
+	}
+
+	// Any failure is treated as a conflict; errno values for it differ across platforms
+	url := fmt.Sprintf("http://localhost:%d", port)
+	if ok, runningContext := probeRadar(url); ok && reuseContext != "" && (runningContext == "" || runningContext == reuseContext) {
+		return nil, &RunningInstanceError{URL: url}
+	}
+	if strict {
+		return nil, fmt.Errorf("listen on :%d: %w (drop --strict-port to fall back to a free port)", port, err)
+	}
+
+	for p := port + 1; p <= port+portFallbackAttempts && p <= 65535; p++ {
+		if ln, err := net.Listen("tcp", fmt.Sprintf(":%d", p)); err == nil {
+			log.Printf("Port %d is in use, using %d instead", port, p)
+			return ln, nil
+		}
+	}
+	ln, err = net.Listen("tcp", ":0")
+	if err != nil {
+		return nil, fmt.Errorf("port %d is in use and no free port was found: %w", port, err)
+	}
+	log.Printf("Port %d is in use, using %d instead", port, ln.Addr().(*net.TCPAddr).Port)
+	return ln, nil
+}
+
+// probeRadar reports whether a Radar server answers at baseURL, and the
+// context it's watching (empty for versions that don't report it)
+func probeRadar(baseURL string) (bool, string) {
+	client := &http.Client{Timeout: instanceProbeTimeout}
+	resp, err := client.Get(baseURL + "/api/health")
+	if err != nil {
+		return false, ""
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, ""
+	}
+	var health struct {
+		Service string         `json:"service"`
+		Context string         `json:"context"`
+		Status  string         `json:"status"`
+		Runtime map[string]any `json:"runtime"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return false, ""
+	}
+	// Versions before "service" was added are recognized by their runtime stats
+	ok := health.Service == "radar" || (health.Status != "" && health.Runtime["uptimeSeconds"] != nil)
+	return ok, health.Context
+}
@@ -0,0 +1,17 @@
+package app
+
+import (
+	"log"
+
+	"github.com/skyhook-io/radar/internal/schedules"
+	"github.com/skyhook-io/radar/internal/settings"
+)
+
+// InitSchedules loads the scale-to-zero schedules saved next to the settings
+// file and starts the scheduler. Call after InitSettings.
+func InitSchedules() {
+	if err := schedules.Load(schedules.DefaultPath(settings.Path())); err != nil {
+		log.Printf("Warning: ignoring saved scale schedules: %v", err)
+	}
+	schedules.Start()
+}
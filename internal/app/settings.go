@@ -0,0 +1,54 @@
+package app
+
+import (
+	"log"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/settings"
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// InitSettings loads the runtime settings (GET/PUT /api/config) on top of
+// the flags, updating cfg with the saved values, and applies later changes
+// without restarting informers. Call after SetGlobals and before
+// BuildTimelineStoreConfig.
+func InitSettings(cfg *AppConfig) {
+	path := cfg.ConfigPath
+	if path == "" {
+		path = settings.DefaultPath()
+	}
+	defaults := settings.Settings{HistoryLimit: cfg.HistoryLimit, LogLevel: settings.LogLevelInfo}
+	if cfg.DebugEvents {
+		defaults.LogLevel = settings.LogLevelDebug
+	}
+	s, err := settings.Init(path, defaults)
+	if err != nil {
+		log.Printf("Warning: ignoring saved settings: %v", err)
+	}
+
+	if cfg.ReadOnly {
+		// The flag wins over a saved readOnly: false
+		settings.LockReadOnly()
+		s.ReadOnly = true
+	}
+
+	cfg.HistoryLimit = s.HistoryLimit
+	cfg.DebugEvents = s.LogLevel == settings.LogLevelDebug
+	applySettings(s)
+	if s.ReadOnly {
+		log.Printf("Read-only mode: requests that change the cluster are rejected")
+	}
+
+	settings.OnChange(func(s settings.Settings) {
+		applySettings(s)
+		if !timeline.SetMaxSize(s.HistoryLimit) {
+			log.Printf("[settings] historyLimit applies to the in-memory timeline only")
+		}
+	})
+}
+
+// applySettings updates the globals the settings map to
+func applySettings(s settings.Settings) {
+	k8s.DebugEvents.Store(s.LogLevel == settings.LogLevelDebug)
+	k8s.SetExtraNoisyKinds(s.NoisyKinds)
+}
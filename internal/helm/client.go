@@ -2,10 +2,10 @@ package helm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
-	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -15,20 +15,22 @@ import (
 	"time"
 
 	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/outbound"
 
 	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/registry"
 	"helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/releaseutil"
 	"helm.sh/helm/v3/pkg/repo"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
 )
 
 // HTTP client for ArtifactHub requests
-var httpClient = &http.Client{
-	Timeout: 30 * time.Second,
-}
+var httpClient = outbound.NewClient(30 * time.Second)
 
 // Client provides access to Helm releases
 type Client struct {
@@ -122,8 +124,15 @@ func ReinitClient(kubeconfig string) error {
 	return Initialize(kubeconfig)
 }
 
-// getActionConfig creates a new action configuration for the given namespace
+// getActionConfig creates a new action configuration for the given namespace,
+// for reads made with Radar's own identity
 func (c *Client) getActionConfig(namespace string) (*action.Configuration, error) {
+	return c.getUserActionConfig(context.Background(), namespace)
+}
+
+// getUserActionConfig creates an action configuration whose API calls are
+// attributed to the Radar user of ctx (see k8s.WithUser)
+func (c *Client) getUserActionConfig(ctx context.Context, namespace string) (*action.Configuration, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
@@ -151,11 +160,22 @@ func (c *Client) getActionConfig(namespace string) (*action.Configuration, error
 	if currentContext != "" && currentContext != "in-cluster" {
 		configFlags.Context = &currentContext
 	}
+	user := k8s.UserFromContext(ctx)
+	configFlags.WrapConfigFn = func(config *rest.Config) *rest.Config {
+		return k8s.WrapConfigForUser(config, user)
+	}
 
 	if err := actionConfig.Init(configFlags, namespace, "secrets", log.Printf); err != nil {
 		return nil, fmt.Errorf("failed to initialize helm action config: %w", err)
 	}
 
+	// Needed to pull oci:// charts
+	registryClient, err := newRegistryClient(c.settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OCI registry client: %w", err)
+	}
+	actionConfig.RegistryClient = registryClient
+
 	return actionConfig, nil
 }
 
@@ -862,8 +882,8 @@ func compareVersions(v1, v2 string) int {
 }
 
 // Rollback rolls back a release to a previous revision
-func (c *Client) Rollback(namespace, name string, revision int) error {
-	actionConfig, err := c.getActionConfig(namespace)
+func (c *Client) Rollback(ctx context.Context, namespace, name string, revision int) error {
+	actionConfig, err := c.getUserActionConfig(ctx, namespace)
 	if err != nil {
 		return err
 	}
@@ -881,8 +901,8 @@ func (c *Client) Rollback(namespace, name string, revision int) error {
 }
 
 // Uninstall removes a release
-func (c *Client) Uninstall(namespace, name string) error {
-	actionConfig, err := c.getActionConfig(namespace)
+func (c *Client) Uninstall(ctx context.Context, namespace, name string) error {
+	actionConfig, err := c.getUserActionConfig(ctx, namespace)
 	if err != nil {
 		return err
 	}
@@ -899,9 +919,11 @@ func (c *Client) Uninstall(namespace, name string) error {
 	return nil
 }
 
-// Upgrade upgrades a release to a new version
-func (c *Client) Upgrade(namespace, name, targetVersion string) error {
-	actionConfig, err := c.getActionConfig(namespace)
+// Upgrade upgrades a release to a new version. Charts are looked up in the
+// local repositories unless chartRef names an oci:// chart, since Helm doesn't
+// record where a release's chart came from.
+func (c *Client) Upgrade(ctx context.Context, namespace, name, targetVersion, chartRef string) error {
+	actionConfig, err := c.getUserActionConfig(ctx, namespace)
 	if err != nil {
 		return err
 	}
@@ -914,6 +936,12 @@ func (c *Client) Upgrade(namespace, name, targetVersion string) error {
 	}
 
 	chartName := rel.Chart.Metadata.Name
+	if chartRef != "" {
+		if !registry.IsOCI(chartRef) {
+			return fmt.Errorf("chart %s is not an oci:// reference", chartRef)
+		}
+		return c.upgradeWithChart(actionConfig, rel, ociChartRef(chartRef, chartName), ociVersion(targetVersion))
+	}
 
 	// Find the chart in local repos
 	repoFile := c.settings.RepositoryConfig
@@ -959,20 +987,21 @@ func (c *Client) Upgrade(namespace, name, targetVersion string) error {
 		return fmt.Errorf("chart %s version %s not found in configured repositories", chartName, targetVersion)
 	}
 
+	return c.upgradeWithChart(actionConfig, rel, chartPath, targetVersion)
+}
+
+// upgradeWithChart downloads the chart at chartPath and upgrades rel to it,
+// keeping the release's values
+func (c *Client) upgradeWithChart(actionConfig *action.Configuration, rel *release.Release, chartPath, targetVersion string) error {
 	// Create upgrade action
 	upgradeAction := action.NewUpgrade(actionConfig)
-	upgradeAction.Namespace = namespace
+	upgradeAction.Namespace = rel.Namespace
 	upgradeAction.Wait = true
 	upgradeAction.Timeout = 300 * time.Second
 	upgradeAction.ReuseValues = true // Keep existing values
 
 	// Download and load the chart
-	// Use ChartPathOptions to locate/download the chart
-	client := action.NewInstall(actionConfig)
-	client.Version = targetVersion
-
-	// Get chart path (will download if needed)
-	cp, err := client.ChartPathOptions.LocateChart(chartPath, c.settings)
+	cp, err := c.locateChart(actionConfig, chartPath, targetVersion)
 	if err != nil {
 		return fmt.Errorf("failed to locate chart: %w", err)
 	}
@@ -982,9 +1011,13 @@ func (c *Client) Upgrade(namespace, name, targetVersion string) error {
 	if err != nil {
 		return fmt.Errorf("failed to load chart: %w", err)
 	}
+	// The reused values must still match the new version's schema
+	if err := validateValues(chart, rel.Config); err != nil {
+		return err
+	}
 
 	// Run the upgrade
-	_, err = upgradeAction.Run(name, chart, rel.Config)
+	_, err = upgradeAction.Run(rel.Name, chart, rel.Config)
 	if err != nil {
 		return fmt.Errorf("upgrade failed: %w", err)
 	}
@@ -1104,6 +1137,10 @@ func (c *Client) PreviewValuesChange(namespace, name string, newValues map[strin
 	// Get current manifest
 	currentManifest := rel.Manifest
 
+	if err := validateValues(rel.Chart, newValues); err != nil {
+		return nil, err
+	}
+
 	// Perform a dry-run upgrade with the new values
 	upgradeAction := action.NewUpgrade(actionConfig)
 	upgradeAction.Namespace = namespace
@@ -1128,8 +1165,8 @@ func (c *Client) PreviewValuesChange(namespace, name string, newValues map[strin
 }
 
 // ApplyValues upgrades a release with new values (same chart version)
-func (c *Client) ApplyValues(namespace, name string, newValues map[string]any) error {
-	actionConfig, err := c.getActionConfig(namespace)
+func (c *Client) ApplyValues(ctx context.Context, namespace, name string, newValues map[string]any) error {
+	actionConfig, err := c.getUserActionConfig(ctx, namespace)
 	if err != nil {
 		return err
 	}
@@ -1141,6 +1178,10 @@ func (c *Client) ApplyValues(namespace, name string, newValues map[string]any) e
 		return fmt.Errorf("failed to get current release: %w", err)
 	}
 
+	if err := validateValues(rel.Chart, newValues); err != nil {
+		return err
+	}
+
 	// Create upgrade action
 	upgradeAction := action.NewUpgrade(actionConfig)
 	upgradeAction.Namespace = namespace
@@ -1214,7 +1255,7 @@ func (c *Client) UpdateRepository(repoName string) error {
 	}
 
 	// Create chart repository and download index
-	chartRepo, err := repo.NewChartRepository(repoEntry, nil)
+	chartRepo, err := repo.NewChartRepository(repoEntry, c.getters())
 	if err != nil {
 		return fmt.Errorf("failed to create chart repository: %w", err)
 	}
@@ -1361,16 +1402,12 @@ func (c *Client) GetChartDetail(repoName, chartName, version string) (*ChartDeta
 		chartURL = strings.TrimSuffix(repoEntry.URL, "/") + "/" + chartURL
 	}
 
-	// Use ChartPathOptions to locate/download
 	actionConfig, err := c.getActionConfig("")
 	if err != nil {
 		return nil, err
 	}
 
-	client := action.NewInstall(actionConfig)
-	client.Version = chartVersion.Version
-
-	cp, err := client.ChartPathOptions.LocateChart(chartURL, c.settings)
+	cp, err := c.locateChart(actionConfig, chartURL, chartVersion.Version)
 	if err != nil {
 		// If we can't download, return basic info from index
 		return &ChartDetail{
@@ -1389,7 +1426,12 @@ func (c *Client) GetChartDetail(repoName, chartName, version string) (*ChartDeta
 	detail := &ChartDetail{
 		ChartInfo: chartVersionToInfo(chartVersion, repoName),
 	}
+	fillChartDetail(detail, chart)
+	return detail, nil
+}
 
+// fillChartDetail adds the README, values and metadata of a loaded chart
+func fillChartDetail(detail *ChartDetail, chart *chart.Chart) {
 	// Extract README
 	for _, f := range chart.Files {
 		name := strings.ToLower(f.Name)
@@ -1423,13 +1465,11 @@ func (c *Client) GetChartDetail(repoName, chartName, version string) (*ChartDeta
 	// Get sources and keywords
 	detail.Sources = chart.Metadata.Sources
 	detail.Keywords = chart.Metadata.Keywords
-
-	return detail, nil
 }
 
 // Install installs a new Helm release
-func (c *Client) Install(req *InstallRequest) (*HelmRelease, error) {
-	actionConfig, err := c.getActionConfig(req.Namespace)
+func (c *Client) Install(ctx context.Context, req *InstallRequest) (*HelmRelease, error) {
+	actionConfig, err := c.getUserActionConfig(ctx, req.Namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -1439,7 +1479,10 @@ func (c *Client) Install(req *InstallRequest) (*HelmRelease, error) {
 	// Check if the repository is a URL (for ArtifactHub installs) or a local repo name
 	isRepoURL := strings.HasPrefix(req.Repository, "http://") || strings.HasPrefix(req.Repository, "https://")
 
-	if isRepoURL {
+	if registry.IsOCI(req.Repository) {
+		// OCI registries have no index; Helm resolves the version from the tags
+		chartURL = ociChartRef(req.Repository, req.ChartName)
+	} else if isRepoURL {
 		// Direct URL - fetch the repository index to find the chart
 		repoURL := strings.TrimSuffix(req.Repository, "/")
 
@@ -1566,10 +1609,10 @@ func (c *Client) Install(req *InstallRequest) (*HelmRelease, error) {
 	installAction.CreateNamespace = req.CreateNamespace
 	installAction.Wait = true
 	installAction.Timeout = 300 * time.Second
-	installAction.Version = req.Version
+	installAction.Version = ociVersion(req.Version)
 
 	// Locate/download chart
-	cp, err := installAction.ChartPathOptions.LocateChart(chartURL, c.settings)
+	cp, err := c.locateChart(actionConfig, chartURL, installAction.Version)
 	if err != nil {
 		return nil, fmt.Errorf("failed to locate chart: %w", err)
 	}
@@ -1579,6 +1622,9 @@ func (c *Client) Install(req *InstallRequest) (*HelmRelease, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to load chart: %w", err)
 	}
+	if err := validateValues(chart, req.Values); err != nil {
+		return nil, err
+	}
 
 	// Run install
 	rel, err := installAction.Run(chart, req.Values)
@@ -1599,7 +1645,7 @@ func (c *Client) Install(req *InstallRequest) (*HelmRelease, error) {
 }
 
 // InstallWithProgress installs a new Helm release and streams progress updates
-func (c *Client) InstallWithProgress(req *InstallRequest, progressCh chan<- InstallProgress) (*HelmRelease, error) {
+func (c *Client) InstallWithProgress(ctx context.Context, req *InstallRequest, progressCh chan<- InstallProgress) (*HelmRelease, error) {
 	sendProgress := func(phase, message, detail string) {
 		select {
 		case progressCh <- InstallProgress{Phase: phase, Message: message, Detail: detail}:
@@ -1608,7 +1654,7 @@ func (c *Client) InstallWithProgress(req *InstallRequest, progressCh chan<- Inst
 		}
 	}
 
-	actionConfig, err := c.getActionConfig(req.Namespace)
+	actionConfig, err := c.getUserActionConfig(ctx, req.Namespace)
 	if err != nil {
 		return nil, err
 	}
@@ -1618,7 +1664,10 @@ func (c *Client) InstallWithProgress(req *InstallRequest, progressCh chan<- Inst
 	// Check if the repository is a URL (for ArtifactHub installs) or a local repo name
 	isRepoURL := strings.HasPrefix(req.Repository, "http://") || strings.HasPrefix(req.Repository, "https://")
 
-	if isRepoURL {
+	if registry.IsOCI(req.Repository) {
+		// OCI registries have no index; Helm resolves the version from the tags
+		chartURL = ociChartRef(req.Repository, req.ChartName)
+	} else if isRepoURL {
 		sendProgress("fetching", "Fetching repository index...", req.Repository)
 
 		repoURL := strings.TrimSuffix(req.Repository, "/")
@@ -1742,9 +1791,9 @@ func (c *Client) InstallWithProgress(req *InstallRequest, progressCh chan<- Inst
 	installAction.CreateNamespace = req.CreateNamespace
 	installAction.Wait = true
 	installAction.Timeout = 300 * time.Second
-	installAction.Version = req.Version
+	installAction.Version = ociVersion(req.Version)
 
-	cp, err := installAction.ChartPathOptions.LocateChart(chartURL, c.settings)
+	cp, err := c.locateChart(actionConfig, chartURL, installAction.Version)
 	if err != nil {
 		return nil, fmt.Errorf("failed to locate chart: %w", err)
 	}
@@ -1755,6 +1804,9 @@ func (c *Client) InstallWithProgress(req *InstallRequest, progressCh chan<- Inst
 	if err != nil {
 		return nil, fmt.Errorf("failed to load chart: %w", err)
 	}
+	if err := validateValues(chart, req.Values); err != nil {
+		return nil, err
+	}
 
 	sendProgress("installing", fmt.Sprintf("Installing %s to namespace %s...", req.ReleaseName, req.Namespace), "")
 
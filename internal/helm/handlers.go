@@ -2,13 +2,18 @@ package helm
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/skyhook-io/radar/internal/apierror"
 	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/outbound"
 )
 
 // IsForbiddenError checks if an error is a Kubernetes RBAC forbidden error
@@ -38,13 +43,16 @@ func (h *Handlers) RegisterRoutes(r chi.Router) {
 		r.Get("/releases/{namespace}/{name}", h.handleGetRelease)
 		r.Get("/releases/{namespace}/{name}/manifest", h.handleGetManifest)
 		r.Get("/releases/{namespace}/{name}/values", h.handleGetValues)
+		r.Get("/releases/{namespace}/{name}/schema", h.handleGetValuesSchema)
 		r.Get("/releases/{namespace}/{name}/diff", h.handleGetDiff)
 		r.Get("/releases/{namespace}/{name}/upgrade-info", h.handleCheckUpgrade)
 		r.Get("/upgrade-check", h.handleBatchUpgradeCheck)
 		// Actions (write operations)
 		r.Post("/releases/{namespace}/{name}/rollback", h.handleRollback)
+		r.Post("/releases/{namespace}/{name}/test", h.handleTest)
 		r.Post("/releases/{namespace}/{name}/upgrade", h.handleUpgrade)
 		r.Post("/releases/{namespace}/{name}/values/preview", h.handlePreviewValues)
+		r.Post("/releases/{namespace}/{name}/values/validate", h.handleValidateValues)
 		r.Put("/releases/{namespace}/{name}/values", h.handleApplyValues)
 		r.Delete("/releases/{namespace}/{name}", h.handleUninstall)
 
@@ -55,10 +63,25 @@ func (h *Handlers) RegisterRoutes(r chi.Router) {
 		r.Get("/charts/{repo}/{chart}", h.handleGetChartDetail)
 		r.Get("/charts/{repo}/{chart}/{version}", h.handleGetChartDetailVersion)
 
-		// ArtifactHub integration
-		r.Get("/artifacthub/search", h.handleArtifactHubSearch)
-		r.Get("/artifacthub/charts/{repo}/{chart}", h.handleArtifactHubChart)
-		r.Get("/artifacthub/charts/{repo}/{chart}/{version}", h.handleArtifactHubChartVersion)
+		// OCI registry credentials
+		r.Get("/registries", h.handleListRegistries)
+		r.Delete("/registries/{host}", h.handleRegistryLogout)
+
+		// OCI registries, refused in offline mode
+		r.Group(func(r chi.Router) {
+			r.Use(outbound.RequireOnline)
+			r.Post("/registries/login", h.handleRegistryLogin)
+			r.Get("/oci/chart", h.handleGetOCIChart)
+			r.Get("/oci/versions", h.handleListOCIVersions)
+		})
+
+		// ArtifactHub integration, refused in offline mode
+		r.Group(func(r chi.Router) {
+			r.Use(outbound.RequireOnline)
+			r.Get("/artifacthub/search", h.handleArtifactHubSearch)
+			r.Get("/artifacthub/charts/{repo}/{chart}", h.handleArtifactHubChart)
+			r.Get("/artifacthub/charts/{repo}/{chart}/{version}", h.handleArtifactHubChartVersion)
+		})
 	})
 }
 
@@ -266,7 +289,7 @@ func (h *Handlers) handleRollback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := client.Rollback(namespace, name, revision); err != nil {
+	if err := client.Rollback(r.Context(), namespace, name, revision); err != nil {
 		if IsForbiddenError(err) {
 			writeError(w, http.StatusForbidden, "insufficient permissions to rollback Helm release")
 			return
@@ -293,7 +316,7 @@ func (h *Handlers) handleUninstall(w http.ResponseWriter, r *http.Request) {
 	namespace := chi.URLParam(r, "namespace")
 	name := chi.URLParam(r, "name")
 
-	if err := client.Uninstall(namespace, name); err != nil {
+	if err := client.Uninstall(r.Context(), namespace, name); err != nil {
 		if IsForbiddenError(err) {
 			writeError(w, http.StatusForbidden, "insufficient permissions to uninstall Helm release")
 			return
@@ -325,8 +348,13 @@ func (h *Handlers) handleUpgrade(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "version parameter is required")
 		return
 	}
+	// Optional oci:// chart reference, for releases installed from an OCI registry
+	chartRef := r.URL.Query().Get("chart")
 
-	if err := client.Upgrade(namespace, name, version); err != nil {
+	if err := client.Upgrade(r.Context(), namespace, name, version, chartRef); err != nil {
+		if writeValuesError(w, err) {
+			return
+		}
 		if IsForbiddenError(err) {
 			writeError(w, http.StatusForbidden, "insufficient permissions to upgrade Helm release")
 			return
@@ -357,6 +385,9 @@ func (h *Handlers) handlePreviewValues(w http.ResponseWriter, r *http.Request) {
 
 	preview, err := client.PreviewValuesChange(namespace, name, req.Values)
 	if err != nil {
+		if writeValuesError(w, err) {
+			return
+		}
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -364,6 +395,58 @@ func (h *Handlers) handlePreviewValues(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, preview)
 }
 
+// handleGetValuesSchema returns the values.schema.json of a release's chart
+// and its subcharts
+func (h *Handlers) handleGetValuesSchema(w http.ResponseWriter, r *http.Request) {
+	client := GetClient()
+	if client == nil {
+		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		return
+	}
+
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	schema, err := client.ReleaseValuesSchema(namespace, name)
+	if err != nil {
+		if IsForbiddenError(err) {
+			writeError(w, http.StatusForbidden, "insufficient permissions to view Helm release")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, schema)
+}
+
+// handleValidateValues validates values against a release chart's schema
+// without applying them
+func (h *Handlers) handleValidateValues(w http.ResponseWriter, r *http.Request) {
+	client := GetClient()
+	if client == nil {
+		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		return
+	}
+
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	var req ApplyValuesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	result, err := client.ValidateReleaseValues(namespace, name, req.Values)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, result)
+}
+
 // handleApplyValues applies new values to a release
 func (h *Handlers) handleApplyValues(w http.ResponseWriter, r *http.Request) {
 	if !requireHelmWrite(w, r) {
@@ -385,7 +468,10 @@ func (h *Handlers) handleApplyValues(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := client.ApplyValues(namespace, name, req.Values); err != nil {
+	if err := client.ApplyValues(r.Context(), namespace, name, req.Values); err != nil {
+		if writeValuesError(w, err) {
+			return
+		}
 		if IsForbiddenError(err) {
 			writeError(w, http.StatusForbidden, "insufficient permissions to apply Helm values")
 			return
@@ -505,6 +591,117 @@ func (h *Handlers) handleGetChartDetailVersion(w http.ResponseWriter, r *http.Re
 	writeJSON(w, detail)
 }
 
+// handleListRegistries lists OCI registries with stored credentials
+func (h *Handlers) handleListRegistries(w http.ResponseWriter, r *http.Request) {
+	client := GetClient()
+	if client == nil {
+		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		return
+	}
+
+	logins, err := client.RegistryLogins()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, logins)
+}
+
+// handleRegistryLogin verifies and stores credentials for an OCI registry
+func (h *Handlers) handleRegistryLogin(w http.ResponseWriter, r *http.Request) {
+	client := GetClient()
+	if client == nil {
+		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		return
+	}
+
+	var req RegistryLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if registryHost(req.Host) == "" {
+		writeError(w, http.StatusBadRequest, "host is required")
+		return
+	}
+	if req.Username == "" || req.Password == "" {
+		writeError(w, http.StatusBadRequest, "username and password are required")
+		return
+	}
+
+	if err := client.RegistryLogin(req); err != nil {
+		log.Printf("[helm] Registry login to %s failed: %v", registryHost(req.Host), err)
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	writeJSON(w, map[string]string{"status": "success", "message": "Login succeeded"})
+}
+
+// handleRegistryLogout removes stored credentials for an OCI registry
+func (h *Handlers) handleRegistryLogout(w http.ResponseWriter, r *http.Request) {
+	client := GetClient()
+	if client == nil {
+		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		return
+	}
+
+	if err := client.RegistryLogout(chi.URLParam(r, "host")); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, map[string]string{"status": "success", "message": "Logged out"})
+}
+
+// handleGetOCIChart returns detailed info about an OCI chart
+// Query params: ref (oci:// chart reference, required), version (default: latest)
+func (h *Handlers) handleGetOCIChart(w http.ResponseWriter, r *http.Request) {
+	client := GetClient()
+	if client == nil {
+		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		return
+	}
+
+	ref := r.URL.Query().Get("ref")
+	if ref == "" {
+		writeError(w, http.StatusBadRequest, "ref parameter is required")
+		return
+	}
+
+	detail, err := client.GetOCIChartDetail(ref, r.URL.Query().Get("version"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, detail)
+}
+
+// handleListOCIVersions lists the versions (semver tags) of an OCI chart
+func (h *Handlers) handleListOCIVersions(w http.ResponseWriter, r *http.Request) {
+	client := GetClient()
+	if client == nil {
+		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		return
+	}
+
+	ref := r.URL.Query().Get("ref")
+	if ref == "" {
+		writeError(w, http.StatusBadRequest, "ref parameter is required")
+		return
+	}
+
+	versions, err := client.ListOCIChartVersions(ref)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, map[string]any{"ref": ref, "versions": versions})
+}
+
 // handleInstall installs a new Helm release (non-streaming version)
 func (h *Handlers) handleInstall(w http.ResponseWriter, r *http.Request) {
 	if !requireHelmWrite(w, r) {
@@ -541,8 +738,11 @@ func (h *Handlers) handleInstall(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	release, err := client.Install(&req)
+	release, err := client.Install(r.Context(), &req)
 	if err != nil {
+		if writeValuesError(w, err) {
+			return
+		}
 		if IsForbiddenError(err) {
 			writeError(w, http.StatusForbidden, "insufficient permissions to install Helm release")
 			return
@@ -609,7 +809,7 @@ func (h *Handlers) handleInstallStream(w http.ResponseWriter, r *http.Request) {
 	// Start install in goroutine
 	resultCh := make(chan installResult, 1)
 	go func() {
-		release, err := client.InstallWithProgress(&req, progressCh)
+		release, err := client.InstallWithProgress(r.Context(), &req, progressCh)
 		resultCh <- installResult{release: release, err: err}
 	}()
 
@@ -638,6 +838,10 @@ func (h *Handlers) handleInstallStream(w http.ResponseWriter, r *http.Request) {
 					"type":    "error",
 					"message": result.err.Error(),
 				}
+				var validationErr *ValuesValidationError
+				if errors.As(result.err, &validationErr) {
+					event["violations"] = validationErr.Violations
+				}
 				data, _ := json.Marshal(event)
 				w.Write([]byte("data: " + string(data) + "\n\n"))
 			} else {
@@ -662,6 +866,98 @@ type installResult struct {
 	err     error
 }
 
+// handleTest runs a release's test hooks (helm test) and streams test pod
+// logs and the final hook results via SSE.
+// Query params: timeout (seconds, default 300), filter (comma-separated hook names).
+func (h *Handlers) handleTest(w http.ResponseWriter, r *http.Request) {
+	if !requireHelmWrite(w, r) {
+		return
+	}
+
+	client := GetClient()
+	if client == nil {
+		writeError(w, http.StatusServiceUnavailable, "Helm client not initialized")
+		return
+	}
+
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	var opts TestOptions
+	if timeoutStr := r.URL.Query().Get("timeout"); timeoutStr != "" {
+		seconds, err := strconv.Atoi(timeoutStr)
+		if err != nil || seconds <= 0 {
+			writeError(w, http.StatusBadRequest, "timeout must be a positive number of seconds")
+			return
+		}
+		opts.Timeout = time.Duration(seconds) * time.Second
+	}
+	for _, hook := range strings.Split(r.URL.Query().Get("filter"), ",") {
+		if hook = strings.TrimSpace(hook); hook != "" {
+			opts.Filter = append(opts.Filter, hook)
+		}
+	}
+
+	// Set up SSE headers
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+	send := func(event map[string]any) {
+		data, _ := json.Marshal(event)
+		w.Write([]byte("data: " + string(data) + "\n\n"))
+		flusher.Flush()
+	}
+
+	send(map[string]any{"type": "progress", "phase": "testing", "message": fmt.Sprintf("Running tests for %s", name)})
+
+	logCh := make(chan TestLogLine, 100)
+	resultCh := make(chan testRunResult, 1)
+	go func() {
+		result, err := client.RunTests(r.Context(), namespace, name, opts, logCh)
+		resultCh <- testRunResult{result: result, err: err}
+	}()
+
+	// The result is only read once logCh is closed, so buffered log lines
+	// are always sent before it
+	var results <-chan testRunResult
+	for {
+		select {
+		case line, ok := <-logCh:
+			if !ok {
+				logCh, results = nil, resultCh
+				continue
+			}
+			send(map[string]any{"type": "log", "hook": line.Hook, "container": line.Container, "content": line.Content})
+
+		case run := <-results:
+			switch {
+			case run.err != nil && IsForbiddenError(run.err):
+				send(map[string]any{"type": "error", "message": "insufficient permissions to run Helm tests"})
+			case run.err != nil:
+				send(map[string]any{"type": "error", "message": run.err.Error()})
+			default:
+				send(map[string]any{"type": "complete", "result": run.result})
+			}
+			return
+
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+type testRunResult struct {
+	result *TestResult
+	err    error
+}
+
 // Helper functions
 
 // requireHelmWrite checks if the service account has Helm write permissions.
@@ -689,9 +985,24 @@ func writeJSON(w http.ResponseWriter, data any) {
 }
 
 func writeError(w http.ResponseWriter, status int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(map[string]string{"error": message})
+	apierror.Write(w, status, message)
+}
+
+// writeValuesError responds 422 with the individual violations when err is a
+// values schema failure, so the UI can flag form fields. Reports whether it did.
+func writeValuesError(w http.ResponseWriter, err error) bool {
+	var validationErr *ValuesValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+	apierror.WriteResponse(w, struct {
+		apierror.Response
+		Violations []ValuesViolation `json:"violations"`
+	}{
+		Response:   apierror.New(http.StatusUnprocessableEntity, apierror.ValidationFailed, validationErr.Error()),
+		Violations: validationErr.Violations,
+	})
+	return true
 }
 
 // ============================================================================
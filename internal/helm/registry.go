@@ -0,0 +1,311 @@
+package helm
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/cli"
+	"helm.sh/helm/v3/pkg/downloader"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/registry"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+
+	"github.com/skyhook-io/radar/internal/outbound"
+)
+
+// UseOSKeychain stores OCI registry logins in the OS keychain (through the
+// platform's docker-credential helper) instead of Helm's plaintext registry
+// config. Set by the desktop app before the Helm client is initialized.
+var UseOSKeychain bool
+
+// Credential store kinds reported by RegistryLogins
+const (
+	CredentialStoreFile     = "file"
+	CredentialStoreKeychain = "keychain"
+)
+
+var (
+	keychainOnce sync.Once
+	keychainFile string
+)
+
+// keychainCredentialsFile returns Radar's own registry config, which routes
+// credentials to the OS keychain. Helm's config isn't reused because logins
+// already stored there in plaintext keep the native store from being picked.
+// Returns "" when no keychain helper is installed.
+func keychainCredentialsFile() string {
+	keychainOnce.Do(func() {
+		if _, ok := credentials.NewDefaultNativeStore(); !ok {
+			log.Printf("[helm] No docker-credential helper for the OS keychain found on PATH, registry logins are stored in Helm's registry config")
+			return
+		}
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			log.Printf("[helm] Cannot resolve home directory for the registry config: %v", err)
+			return
+		}
+		file := filepath.Join(homeDir, ".radar", "helm-registry.json")
+		if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) {
+			// Helm can't load an empty file; ORAS adds the credsStore entry on first use
+			if err := os.MkdirAll(filepath.Dir(file), 0700); err == nil {
+				err = os.WriteFile(file, []byte("{}"), 0600)
+			}
+			if err != nil {
+				log.Printf("[helm] Failed to create %s: %v", file, err)
+				return
+			}
+		}
+		keychainFile = file
+	})
+	return keychainFile
+}
+
+// registryCredentialsFile returns the config that holds OCI registry logins
+func registryCredentialsFile(settings *cli.EnvSettings) (path, store string) {
+	if UseOSKeychain {
+		if file := keychainCredentialsFile(); file != "" {
+			return file, CredentialStoreKeychain
+		}
+	}
+	return settings.RegistryConfig, CredentialStoreFile
+}
+
+// newRegistryClient creates a client for oci:// chart references. It goes
+// through Radar's outbound proxy and CA settings. A fresh client is created
+// per operation so logins made elsewhere (e.g. `helm registry login`) apply.
+func newRegistryClient(settings *cli.EnvSettings) (*registry.Client, error) {
+	credentialsFile, _ := registryCredentialsFile(settings)
+	return registry.NewClient(
+		registry.ClientOptCredentialsFile(credentialsFile),
+		registry.ClientOptHTTPClient(&http.Client{Transport: outbound.NewTransport()}),
+		registry.ClientOptEnableCache(true),
+	)
+}
+
+// getters returns Helm's chart getters with HTTP(S) downloads going through
+// Radar's outbound proxy and CA settings. Helm's default transport only reads
+// the proxy environment, and a custom transport replaces the per-repository
+// TLS files, so repositories with their own CA need it in the outbound bundle.
+func (c *Client) getters() getter.Providers {
+	return getter.All(c.settings, getter.WithTransport(outbound.NewTransport()))
+}
+
+// locateChart downloads a chart (repository URL, repo/name or oci:// reference)
+// into the repository cache and returns its path. It replaces Helm's
+// ChartPathOptions.LocateChart, whose getters can't be given a transport.
+func (c *Client) locateChart(actionConfig *action.Configuration, ref, version string) (string, error) {
+	dl := downloader.ChartDownloader{
+		Out:              io.Discard,
+		Getters:          c.getters(),
+		RepositoryConfig: c.settings.RepositoryConfig,
+		RepositoryCache:  c.settings.RepositoryCache,
+		RegistryClient:   actionConfig.RegistryClient,
+	}
+	if registry.IsOCI(ref) {
+		dl.Options = append(dl.Options, getter.WithRegistryClient(actionConfig.RegistryClient))
+	}
+	if err := os.MkdirAll(c.settings.RepositoryCache, 0755); err != nil {
+		return "", err
+	}
+	filename, _, err := dl.DownloadTo(strings.TrimSpace(ref), strings.TrimSpace(version), c.settings.RepositoryCache)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(filename)
+}
+
+// ociChartRef joins an oci:// repository and a chart name into a chart reference
+func ociChartRef(repository, chartName string) string {
+	ref := strings.TrimSuffix(repository, "/")
+	if chartName != "" && !strings.HasSuffix(ref, "/"+chartName) {
+		ref += "/" + chartName
+	}
+	return ref
+}
+
+// ociVersion maps the UI's "latest" to an empty version, which Helm resolves
+// to the highest semver tag of an OCI chart
+func ociVersion(version string) string {
+	if version == "latest" {
+		return ""
+	}
+	return version
+}
+
+// RegistryLogin validates credentials against an OCI registry and stores them
+func (c *Client) RegistryLogin(req RegistryLoginRequest) error {
+	if outbound.Offline() {
+		return outbound.ErrOffline
+	}
+	client, err := newRegistryClient(c.settings)
+	if err != nil {
+		return fmt.Errorf("failed to create registry client: %w", err)
+	}
+	if err := client.Login(registryHost(req.Host), registry.LoginOptBasicAuth(req.Username, req.Password)); err != nil {
+		return fmt.Errorf("registry login failed: %w", err)
+	}
+	return nil
+}
+
+// RegistryLogout removes stored credentials for an OCI registry
+func (c *Client) RegistryLogout(host string) error {
+	client, err := newRegistryClient(c.settings)
+	if err != nil {
+		return fmt.Errorf("failed to create registry client: %w", err)
+	}
+	if err := client.Logout(registryHost(host)); err != nil {
+		return fmt.Errorf("registry logout failed: %w", err)
+	}
+	return nil
+}
+
+// registryHost accepts a host, an oci:// URL or a chart reference and
+// returns the registry host
+func registryHost(raw string) string {
+	host := strings.TrimPrefix(strings.TrimSpace(raw), registry.OCIScheme+"://")
+	host, _, _ = strings.Cut(host, "/")
+	return host
+}
+
+type registryConfig struct {
+	Auths map[string]struct {
+		Auth     string `json:"auth"`
+		Username string `json:"username"`
+	} `json:"auths"`
+	CredsStore string `json:"credsStore"`
+}
+
+func readRegistryConfig(path string) (*registryConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg registryConfig
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, err
+		}
+	}
+	return &cfg, nil
+}
+
+// RegistryLogins lists the registries with stored credentials. Only hosts
+// and usernames are returned, never secrets.
+func (c *Client) RegistryLogins() (*RegistryLogins, error) {
+	path, store := registryCredentialsFile(c.settings)
+	result := &RegistryLogins{Store: store, Registries: []RegistryLogin{}}
+
+	cfg, err := readRegistryConfig(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry config: %w", err)
+	}
+
+	seen := map[string]bool{}
+	for host, entry := range cfg.Auths {
+		username := entry.Username
+		if decoded, err := base64.StdEncoding.DecodeString(entry.Auth); err == nil {
+			username, _, _ = strings.Cut(string(decoded), ":")
+		}
+		seen[host] = true
+		result.Registries = append(result.Registries, RegistryLogin{Host: host, Username: username})
+	}
+	if cfg.CredsStore != "" {
+		result.Helper = cfg.CredsStore
+		for host, username := range listNativeCredentials(cfg.CredsStore) {
+			if !seen[host] {
+				result.Registries = append(result.Registries, RegistryLogin{Host: host, Username: username})
+			}
+		}
+	}
+	sort.Slice(result.Registries, func(i, j int) bool { return result.Registries[i].Host < result.Registries[j].Host })
+	return result, nil
+}
+
+// listNativeCredentials asks a docker-credential helper which servers it holds
+// credentials for. The keychain is shared with Docker, so this can include
+// image registries that were never used for charts.
+func listNativeCredentials(helper string) map[string]string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "docker-credential-"+helper, "list").Output()
+	if err != nil {
+		log.Printf("[helm] Failed to list credentials from docker-credential-%s: %v", helper, err)
+		return nil
+	}
+	var servers map[string]string
+	if err := json.Unmarshal(out, &servers); err != nil {
+		log.Printf("[helm] Unexpected output from docker-credential-%s list: %v", helper, err)
+		return nil
+	}
+	return servers
+}
+
+// GetOCIChartDetail pulls an OCI chart (e.g. oci://ghcr.io/org/charts/app)
+// and returns its README, default values and metadata
+func (c *Client) GetOCIChartDetail(ref, version string) (*ChartDetail, error) {
+	if !registry.IsOCI(ref) {
+		return nil, fmt.Errorf("%s is not an oci:// chart reference", ref)
+	}
+	actionConfig, err := c.getActionConfig("")
+	if err != nil {
+		return nil, err
+	}
+
+	cp, err := c.locateChart(actionConfig, ref, ociVersion(version))
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull chart: %w", err)
+	}
+	chart, err := loader.Load(cp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chart: %w", err)
+	}
+
+	detail := &ChartDetail{
+		ChartInfo: ChartInfo{
+			Name:        chart.Metadata.Name,
+			Version:     chart.Metadata.Version,
+			AppVersion:  chart.Metadata.AppVersion,
+			Description: chart.Metadata.Description,
+			Icon:        chart.Metadata.Icon,
+			Repository:  strings.TrimSuffix(ref, "/"+chart.Metadata.Name),
+			Home:        chart.Metadata.Home,
+			Deprecated:  chart.Metadata.Deprecated,
+		},
+	}
+	fillChartDetail(detail, chart)
+	return detail, nil
+}
+
+// ListOCIChartVersions returns the semver tags of an OCI chart, newest first
+func (c *Client) ListOCIChartVersions(ref string) ([]string, error) {
+	if !registry.IsOCI(ref) {
+		return nil, fmt.Errorf("%s is not an oci:// chart reference", ref)
+	}
+	client, err := newRegistryClient(c.settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create registry client: %w", err)
+	}
+	tags, err := client.Tags(strings.TrimPrefix(ref, registry.OCIScheme+"://"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chart versions: %w", err)
+	}
+	return tags, nil
+}
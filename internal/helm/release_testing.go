@@ -0,0 +1,182 @@
+package helm
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+const (
+	defaultTestTimeout = 300 * time.Second
+	// How long log followers get to drain after the tests finish
+	testLogGrace = 2 * time.Second
+)
+
+// RunTests runs the release's test hooks like `helm test`. While they run,
+// logs of Pod test hooks are sent to logCh, which is closed on return.
+// A failing test is reported in the result, not as an error.
+func (c *Client) RunTests(ctx context.Context, namespace, name string, opts TestOptions, logCh chan<- TestLogLine) (*TestResult, error) {
+	defer close(logCh)
+
+	actionConfig, err := c.getUserActionConfig(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := action.NewGet(actionConfig).Run(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release: %w", err)
+	}
+
+	testAction := action.NewReleaseTesting(actionConfig)
+	testAction.Namespace = namespace
+	testAction.Timeout = opts.Timeout
+	if testAction.Timeout <= 0 {
+		testAction.Timeout = defaultTestTimeout
+	}
+	if len(opts.Filter) > 0 {
+		testAction.Filters[action.IncludeNameFilter] = opts.Filter
+	}
+
+	// Follow logs of test pods; remember existing pods so a previous run's
+	// pod (kept until the hook is recreated) isn't mistaken for this one
+	logCtx, stopLogs := context.WithCancel(ctx)
+	defer stopLogs()
+	var followers sync.WaitGroup
+	if client := k8s.GetClient(); client != nil {
+		for _, hook := range testHooks(rel, opts.Filter) {
+			if hook.Kind != "Pod" {
+				continue
+			}
+			var previous types.UID
+			if pod, err := client.CoreV1().Pods(namespace).Get(ctx, hook.Name, metav1.GetOptions{}); err == nil {
+				previous = pod.UID
+			}
+			followers.Add(1)
+			go func(hookName string) {
+				defer followers.Done()
+				followTestPodLogs(logCtx, client, namespace, hookName, previous, logCh)
+			}(hook.Name)
+		}
+	}
+
+	tested, runErr := testAction.Run(name)
+
+	// Let followers finish reading completed pods, then stop the stragglers
+	done := make(chan struct{})
+	go func() {
+		followers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(testLogGrace):
+		stopLogs()
+		<-done
+	}
+
+	if tested == nil {
+		return nil, fmt.Errorf("helm test failed: %w", runErr)
+	}
+	result := &TestResult{
+		Release:   name,
+		Namespace: namespace,
+		Revision:  tested.Version,
+		Passed:    runErr == nil,
+		Hooks:     []TestHookResult{},
+	}
+	if runErr != nil {
+		result.Error = runErr.Error()
+	}
+	for _, hook := range testHooks(tested, opts.Filter) {
+		hr := TestHookResult{Name: hook.Name, Kind: hook.Kind, Phase: string(hook.LastRun.Phase)}
+		if hr.Phase == "" {
+			hr.Phase = string(release.HookPhaseUnknown)
+		}
+		if !hook.LastRun.StartedAt.IsZero() {
+			started := hook.LastRun.StartedAt.Time
+			hr.StartedAt = &started
+		}
+		if !hook.LastRun.CompletedAt.IsZero() {
+			completed := hook.LastRun.CompletedAt.Time
+			hr.CompletedAt = &completed
+		}
+		result.Hooks = append(result.Hooks, hr)
+	}
+	return result, nil
+}
+
+// testHooks returns the release's test hooks, narrowed to filter when set
+func testHooks(rel *release.Release, filter []string) []*release.Hook {
+	var hooks []*release.Hook
+	for _, h := range rel.Hooks {
+		if !slices.Contains(h.Events, release.HookTest) {
+			continue
+		}
+		if len(filter) > 0 && !slices.Contains(filter, h.Name) {
+			continue
+		}
+		hooks = append(hooks, h)
+	}
+	return hooks
+}
+
+// followTestPodLogs waits for a new test pod (one whose UID differs from
+// previous) to start, then streams all of its containers' logs until they end
+func followTestPodLogs(ctx context.Context, client kubernetes.Interface, namespace, podName string, previous types.UID, logCh chan<- TestLogLine) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	var pod *corev1.Pod
+	for pod == nil {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		p, err := client.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return
+			}
+			continue
+		}
+		if p.UID != previous && p.Status.Phase != corev1.PodPending {
+			pod = p
+		}
+	}
+
+	for _, container := range pod.Spec.Containers {
+		req := client.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{Container: container.Name, Follow: true})
+		stream, err := req.Stream(ctx)
+		if err != nil {
+			select {
+			case logCh <- TestLogLine{Hook: podName, Container: container.Name, Content: "unable to stream logs: " + err.Error()}:
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+		scanner := bufio.NewScanner(stream)
+		for scanner.Scan() {
+			select {
+			case logCh <- TestLogLine{Hook: podName, Container: container.Name, Content: scanner.Text()}:
+			case <-ctx.Done():
+				stream.Close()
+				return
+			}
+		}
+		stream.Close()
+	}
+}
@@ -1,6 +1,7 @@
 package helm
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -273,3 +274,77 @@ type InstallProgress struct {
 	Message string `json:"message"`          // Human-readable status message
 	Detail  string `json:"detail,omitempty"` // Additional detail (e.g., command output)
 }
+
+// TestOptions controls a helm test run
+type TestOptions struct {
+	Filter  []string      // Only run test hooks with these names (empty = all)
+	Timeout time.Duration // Per-hook timeout
+}
+
+// TestHookResult is the outcome of one test hook
+type TestHookResult struct {
+	Name        string     `json:"name"`
+	Kind        string     `json:"kind"`
+	Phase       string     `json:"phase"` // Succeeded, Failed, Running or Unknown
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+// TestResult is the outcome of running a release's test hooks
+type TestResult struct {
+	Release   string           `json:"release"`
+	Namespace string           `json:"namespace"`
+	Revision  int              `json:"revision"`
+	Passed    bool             `json:"passed"`
+	Error     string           `json:"error,omitempty"`
+	Hooks     []TestHookResult `json:"hooks"`
+}
+
+// TestLogLine is a log line from a test hook pod, streamed while tests run
+type TestLogLine struct {
+	Hook      string `json:"hook"`
+	Container string `json:"container"`
+	Content   string `json:"content"`
+}
+
+// RegistryLoginRequest logs in to an OCI registry (ghcr.io, <account>.dkr.ecr.<region>.amazonaws.com, ...)
+type RegistryLoginRequest struct {
+	Host     string `json:"host"` // Registry host; an oci:// chart reference is accepted too
+	Username string `json:"username"`
+	Password string `json:"password"` // Password or token, e.g. `aws ecr get-login-password` output
+}
+
+// RegistryLogin is a registry with stored credentials
+type RegistryLogin struct {
+	Host     string `json:"host"`
+	Username string `json:"username,omitempty"`
+}
+
+// RegistryLogins lists stored OCI registry credentials
+type RegistryLogins struct {
+	Store      string          `json:"store"`            // "keychain" (OS keychain) or "file" (Helm's registry config)
+	Helper     string          `json:"helper,omitempty"` // docker-credential helper backing the store, e.g. osxkeychain
+	Registries []RegistryLogin `json:"registries"`
+}
+
+// ValuesViolation is one values.schema.json violation
+type ValuesViolation struct {
+	Chart   string `json:"chart,omitempty"` // Chart or subchart whose schema was violated
+	Path    string `json:"path"`            // JSON pointer into the values, e.g. /image/tag ("" = the values root)
+	Keyword string `json:"keyword"`         // Failing schema keyword, e.g. required, type, enum
+	Message string `json:"message"`
+}
+
+// ValuesValidation is the result of validating values against a chart's schema
+type ValuesValidation struct {
+	Valid      bool              `json:"valid"`
+	Violations []ValuesViolation `json:"violations"`
+}
+
+// ValuesSchema holds a release chart's values.schema.json and its subcharts'
+type ValuesSchema struct {
+	Chart     string                     `json:"chart"`
+	Version   string                     `json:"version"`
+	Schema    json.RawMessage            `json:"schema,omitempty"`    // Absent when the chart ships no schema
+	Subcharts map[string]json.RawMessage `json:"subcharts,omitempty"` // Keyed by subchart name (its values key)
+}
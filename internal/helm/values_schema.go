@@ -0,0 +1,211 @@
+package helm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// Messages are rendered like Helm's own schema errors
+var schemaPrinter = message.NewPrinter(language.English)
+
+// ValuesValidationError reports values that don't match a chart's
+// values.schema.json, one violation per failing keyword
+type ValuesValidationError struct {
+	Violations []ValuesViolation
+}
+
+func (e *ValuesValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		path := v.Path
+		if path == "" {
+			path = "/"
+		}
+		msgs = append(msgs, fmt.Sprintf("%s: %s", path, v.Message))
+	}
+	return "values don't match the chart's values.schema.json: " + strings.Join(msgs, "; ")
+}
+
+// validateValues checks user-supplied values, merged over the chart's
+// defaults as Helm renders them, against the schemas of the chart and its
+// subcharts. Returns a *ValuesValidationError for violations.
+func validateValues(chrt *chart.Chart, userValues map[string]any) error {
+	merged, err := chartutil.CoalesceValues(chrt, userValues)
+	if err != nil {
+		return fmt.Errorf("failed to merge values: %w", err)
+	}
+	var violations []ValuesViolation
+	collectViolations(chrt, merged, "", &violations)
+	if len(violations) > 0 {
+		return &ValuesValidationError{Violations: violations}
+	}
+	return nil
+}
+
+func collectViolations(chrt *chart.Chart, values map[string]any, prefix string, violations *[]ValuesViolation) {
+	if chrt.Schema != nil {
+		for _, v := range schemaViolations(chrt.Schema, values) {
+			v.Chart = chrt.Name()
+			v.Path = prefix + v.Path
+			*violations = append(*violations, v)
+		}
+	}
+	for _, sub := range chrt.Dependencies() {
+		raw, ok := values[sub.Name()]
+		if !ok || raw == nil {
+			continue
+		}
+		subPath := prefix + "/" + escapePointer(sub.Name())
+		subValues, ok := raw.(map[string]any)
+		if !ok {
+			*violations = append(*violations, ValuesViolation{
+				Chart: sub.Name(), Path: subPath, Keyword: "type",
+				Message: fmt.Sprintf("got %T, want object", raw),
+			})
+			continue
+		}
+		collectViolations(sub, subValues, subPath, violations)
+	}
+}
+
+// schemaViolations validates values against one schema. A schema that can't
+// be compiled (e.g. it $refs a remote URL) is skipped here; Helm still
+// validates it when rendering.
+func schemaViolations(schemaJSON []byte, values map[string]any) []ValuesViolation {
+	schema, err := jsonschema.UnmarshalJSON(bytes.NewReader(schemaJSON))
+	if err != nil {
+		return []ValuesViolation{{Keyword: "schema", Message: "invalid values.schema.json: " + err.Error()}}
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("file:///values.schema.json", schema); err != nil {
+		return []ValuesViolation{{Keyword: "schema", Message: "invalid values.schema.json: " + err.Error()}}
+	}
+	validator, err := compiler.Compile("file:///values.schema.json")
+	if err != nil {
+		log.Printf("[helm] Skipping structured schema validation: %v", err)
+		return nil
+	}
+
+	// Round-trip through JSON so values decoded from YAML have JSON types
+	data, err := json.Marshal(values)
+	if err != nil {
+		return []ValuesViolation{{Message: "values are not JSON-serializable: " + err.Error()}}
+	}
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+	if err != nil {
+		return []ValuesViolation{{Message: err.Error()}}
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if err := validator.Validate(instance); !errors.As(err, &validationErr) {
+		return nil
+	}
+	var violations []ValuesViolation
+	flattenValidationError(validationErr, &violations)
+	return violations
+}
+
+// flattenValidationError turns the leaves of a validation error tree into
+// violations. Missing required properties are reported at the property's own
+// path so a form can flag the field.
+func flattenValidationError(e *jsonschema.ValidationError, violations *[]ValuesViolation) {
+	if len(e.Causes) > 0 {
+		for _, cause := range e.Causes {
+			flattenValidationError(cause, violations)
+		}
+		return
+	}
+	path := pointer(e.InstanceLocation)
+	keyword := strings.Join(e.ErrorKind.KeywordPath(), "/")
+	if required, ok := e.ErrorKind.(*kind.Required); ok {
+		for _, name := range required.Missing {
+			*violations = append(*violations, ValuesViolation{
+				Path: path + "/" + escapePointer(name), Keyword: keyword, Message: "missing required property",
+			})
+		}
+		return
+	}
+	*violations = append(*violations, ValuesViolation{
+		Path: path, Keyword: keyword, Message: e.ErrorKind.LocalizedString(schemaPrinter),
+	})
+}
+
+func pointer(tokens []string) string {
+	var sb strings.Builder
+	for _, tok := range tokens {
+		sb.WriteByte('/')
+		sb.WriteString(escapePointer(tok))
+	}
+	return sb.String()
+}
+
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// ReleaseValuesSchema returns the values schemas of a release's chart and
+// its subcharts, for building a values form
+func (c *Client) ReleaseValuesSchema(namespace, name string) (*ValuesSchema, error) {
+	actionConfig, err := c.getActionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := action.NewGet(actionConfig).Run(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release: %w", err)
+	}
+
+	result := &ValuesSchema{
+		Chart:   rel.Chart.Metadata.Name,
+		Version: rel.Chart.Metadata.Version,
+	}
+	if rel.Chart.Schema != nil {
+		result.Schema = json.RawMessage(rel.Chart.Schema)
+	}
+	for _, sub := range rel.Chart.Dependencies() {
+		if sub.Schema == nil {
+			continue
+		}
+		if result.Subcharts == nil {
+			result.Subcharts = map[string]json.RawMessage{}
+		}
+		result.Subcharts[sub.Name()] = json.RawMessage(sub.Schema)
+	}
+	return result, nil
+}
+
+// ValidateReleaseValues checks new values for a release against its chart's
+// schema without applying them
+func (c *Client) ValidateReleaseValues(namespace, name string, values map[string]any) (*ValuesValidation, error) {
+	actionConfig, err := c.getActionConfig(namespace)
+	if err != nil {
+		return nil, err
+	}
+	rel, err := action.NewGet(actionConfig).Run(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release: %w", err)
+	}
+
+	result := &ValuesValidation{Valid: true, Violations: []ValuesViolation{}}
+	if err := validateValues(rel.Chart, values); err != nil {
+		var validationErr *ValuesValidationError
+		if !errors.As(err, &validationErr) {
+			return nil, err
+		}
+		result.Valid = false
+		result.Violations = validationErr.Violations
+	}
+	return result, nil
+}
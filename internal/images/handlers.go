@@ -3,30 +3,49 @@ package images
 import (
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/apierror"
+	"github.com/skyhook-io/radar/internal/outbound"
 )
 
 // Handlers provides HTTP handlers for image inspection
 type Handlers struct {
 	inspector *Inspector
+	updates   *UpdateChecker
 }
 
 // NewHandlers creates a new Handlers instance
 func NewHandlers() *Handlers {
 	return &Handlers{
 		inspector: NewInspector(),
+		updates:   NewUpdateChecker(),
 	}
 }
 
+// Inspector returns the inspector behind the handlers, sharing its layer cache
+func (h *Handlers) Inspector() *Inspector {
+	return h.inspector
+}
+
 // RegisterRoutes registers image inspection routes
 func (h *Handlers) RegisterRoutes(r chi.Router) {
 	r.Route("/images", func(r chi.Router) {
-		r.Get("/metadata", h.handleMetadata)
-		r.Get("/inspect", h.handleInspect)
-		r.Get("/file", h.handleGetFile)
+		r.Get("/pulls", h.handlePulls)
+
+		// Registry calls, refused in offline mode
+		r.Group(func(r chi.Router) {
+			r.Use(outbound.RequireOnline)
+			r.Get("/metadata", h.handleMetadata)
+			r.Get("/inspect", h.handleInspect)
+			r.Get("/file", h.handleGetFile)
+			r.Get("/updates", h.handleUpdates)
+		})
 	})
 }
 
@@ -183,22 +202,59 @@ func (h *Handlers) handleGetFile(w http.ResponseWriter, r *http.Request) {
 	w.Write(content)
 }
 
+// handlePulls returns image pull durations, failure rates, and node cache
+// coverage aggregated from kubelet events in the timeline
+func (h *Handlers) handlePulls(w http.ResponseWriter, r *http.Request) {
+	since := time.Now().Add(-24 * time.Hour)
+	if s := r.URL.Query().Get("since"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid since parameter, expected RFC3339")
+			return
+		}
+		since = t
+	}
+
+	var namespaces []string
+	if ns := r.URL.Query().Get("namespaces"); ns != "" {
+		namespaces = strings.Split(ns, ",")
+	}
+
+	report, err := GetImagePullReport(r.Context(), namespaces, since)
+	if err != nil {
+		log.Printf("[images] Failed to build image pull report: %v", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, report)
+}
+
+// handleUpdates checks running images against their registries for newer
+// tags and digest drift. Registry results are cached unless refresh=true.
+func (h *Handlers) handleUpdates(w http.ResponseWriter, r *http.Request) {
+	var namespaces []string
+	if ns := r.URL.Query().Get("namespaces"); ns != "" {
+		namespaces = strings.Split(ns, ",")
+	}
+	refresh := r.URL.Query().Get("refresh") == "true"
+
+	writeJSON(w, h.updates.Check(r.Context(), namespaces, refresh))
+}
+
 func writeJSON(w http.ResponseWriter, data any) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(data)
 }
 
 func writeAuthError(w http.ResponseWriter, image string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusUnauthorized)
-	json.NewEncoder(w).Encode(map[string]string{
-		"error":        "Authentication required for this image",
-		"registryType": string(DetectRegistryType(image)),
-	})
+	resp := apierror.New(http.StatusUnauthorized, apierror.Unauthorized, "Authentication required for this image")
+	resp.Hint = "Provide registry credentials for this image, or add an image pull secret Radar can read."
+	apierror.WriteResponse(w, struct {
+		apierror.Response
+		RegistryType string `json:"registryType"`
+	}{resp, string(DetectRegistryType(image))})
 }
 
 func writeError(w http.ResponseWriter, status int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(map[string]string{"error": message})
+	apierror.Write(w, status, message)
 }
@@ -18,6 +18,8 @@ import (
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"github.com/skyhook-io/radar/internal/outbound"
 )
 
 const (
@@ -382,31 +384,39 @@ func (i *Inspector) fetchImageBruteForce(ctx context.Context, req InspectRequest
 		return nil, "", fmt.Errorf("invalid image reference: %w", err)
 	}
 
-	// Try anonymous first
-	img, err := remote.Image(ref,
-		remote.WithContext(ctx),
-		remote.WithAuth(authn.Anonymous),
-	)
+	var img v1.Image
+	authMethod, err := withRegistryAuth(ctx, req, func(opts ...remote.Option) error {
+		var err error
+		img, err = remote.Image(ref, opts...)
+		return err
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch image: %w", err)
+	}
+	return img, authMethod, nil
+}
+
+// withRegistryAuth runs a registry call with anonymous auth first, then
+// retries with pull secrets and local credentials if anonymous fails.
+// Returns the auth method that succeeded.
+func withRegistryAuth(ctx context.Context, req InspectRequest, call func(opts ...remote.Option) error) (string, error) {
+	err := call(remote.WithContext(ctx), remote.WithTransport(outbound.Transport), remote.WithAuth(authn.Anonymous))
 	if err == nil {
 		log.Printf("Image %s accessible with anonymous auth", req.Image)
-		return img, "anonymous", nil
+		return "anonymous", nil
 	}
 
 	// Anonymous failed, try with credentials
 	log.Printf("Anonymous auth failed for %s, trying with credentials: %v", req.Image, err)
 
 	keychain := GetAuthenticatedKeychain(req.Image, req.Namespace, req.PullSecretNames)
-	img, err = remote.Image(ref,
-		remote.WithContext(ctx),
-		remote.WithAuthFromKeychain(keychain),
-	)
-	if err != nil {
-		return nil, "", fmt.Errorf("failed to fetch image: %w", err)
+	if err := call(remote.WithContext(ctx), remote.WithTransport(outbound.Transport), remote.WithAuthFromKeychain(keychain)); err != nil {
+		return "", err
 	}
 
 	registryType := DetectRegistryType(req.Image)
 	log.Printf("Image %s accessible with %s credentials", req.Image, registryType)
-	return img, string(registryType), nil
+	return string(registryType), nil
 }
 
 // Inspect retrieves the filesystem tree for a container image
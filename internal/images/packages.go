@@ -0,0 +1,368 @@
+package images
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// Package types
+const (
+	PackageDeb = "deb"
+	PackageApk = "apk"
+	PackageRpm = "rpm"
+)
+
+// Where a package list came from
+const (
+	PackageSourceImage = "image" // Package databases read from the image layers
+	PackageSourceExec  = "exec"  // Package manager queried in the running container
+)
+
+const (
+	dpkgStatusPath   = "/var/lib/dpkg/status"
+	dpkgStatusDir    = "/var/lib/dpkg/status.d/" // One file per package in distroless images
+	apkInstalledPath = "/lib/apk/db/installed"
+	rpmDBDir         = "/var/lib/rpm/"
+)
+
+// osReleasePaths are checked in order
+var osReleasePaths = []string{"/etc/os-release", "/usr/lib/os-release"}
+
+// PackageQueryScript lists installed packages from inside a container as
+// "type<TAB>name<TAB>version<TAB>arch" lines, and os-release as "os<TAB>line"
+// lines (parsed by ParsePackageQuery). It only needs sh, and awk for Alpine.
+const PackageQueryScript = `for f in /etc/os-release /usr/lib/os-release; do
+  if [ -f "$f" ]; then
+    while IFS= read -r line; do printf 'os\t%s\n' "$line"; done < "$f"
+    break
+  fi
+done
+if command -v dpkg-query >/dev/null 2>&1; then
+  dpkg-query -W -f='deb\t${Package}\t${Version}\t${Architecture}\n' 2>/dev/null
+fi
+if [ -f /lib/apk/db/installed ] && command -v awk >/dev/null 2>&1; then
+  awk -F: '/^P:/{p=substr($0,3)} /^V:/{v=substr($0,3)} /^A:/{a=substr($0,3)} /^$/{if(p!="")print "apk\t" p "\t" v "\t" a; p=""} END{if(p!="")print "apk\t" p "\t" v "\t" a}' /lib/apk/db/installed
+fi
+if command -v rpm >/dev/null 2>&1; then
+  rpm -qa --qf 'rpm\t%{NAME}\t%{VERSION}-%{RELEASE}\t%{ARCH}\n' 2>/dev/null
+fi
+`
+
+// Package is an OS package installed in an image
+type Package struct {
+	Type    string `json:"type"` // deb, apk or rpm
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Arch    string `json:"arch,omitempty"`
+}
+
+// OSRelease identifies an image's distribution, from /etc/os-release
+type OSRelease struct {
+	ID         string `json:"id,omitempty"` // e.g. debian, alpine, rhel
+	VersionID  string `json:"versionId,omitempty"`
+	PrettyName string `json:"prettyName,omitempty"`
+}
+
+// ImagePackages lists the OS packages of one image
+type ImagePackages struct {
+	Image    string    `json:"image"`
+	Digest   string    `json:"digest,omitempty"`
+	Platform string    `json:"platform,omitempty"`
+	OS       OSRelease `json:"os"`
+	Packages []Package `json:"packages"`
+	Source   string    `json:"source"`
+	Warnings []string  `json:"warnings,omitempty"` // e.g. an RPM database that can't be read from layers
+}
+
+// PinnedRef returns image pinned to the digest of a container status
+// imageID, so the running image is inspected even if its tag moved
+func PinnedRef(image, imageID string) string {
+	digest := imageIDDigest(imageID)
+	if !strings.HasPrefix(digest, "sha256:") {
+		return image
+	}
+	ref, err := name.ParseReference(image, name.WeakValidation)
+	if err != nil {
+		return image
+	}
+	return ref.Context().Name() + "@" + digest
+}
+
+// Packages reads the package databases (dpkg, apk) and os-release from an
+// image's layers, downloading and caching them like Inspect. RPM databases
+// are binary and only reported; query those with PackageQueryScript.
+func (i *Inspector) Packages(ctx context.Context, req InspectRequest) (*ImagePackages, error) {
+	img, _, err := i.fetchImageBruteForce(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image digest: %w", err)
+	}
+	layerPaths, meta, cached := i.getCachedLayers(digest.String())
+	if !cached {
+		layerPaths, meta, err = i.cacheLayers(ctx, img, req.Image)
+		if err != nil {
+			return nil, fmt.Errorf("failed to cache layers: %w", err)
+		}
+	}
+
+	foundRPM := false
+	files, err := readMatchingFiles(ctx, layerPaths, func(path string) bool {
+		if strings.HasPrefix(path, rpmDBDir) {
+			foundRPM = true
+			return false
+		}
+		return path == dpkgStatusPath || path == apkInstalledPath ||
+			strings.HasPrefix(path, dpkgStatusDir) || path == osReleasePaths[0] || path == osReleasePaths[1]
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImagePackages{
+		Image:    req.Image,
+		Digest:   meta.Digest,
+		Platform: meta.Platform,
+		Packages: []Package{},
+		Source:   PackageSourceImage,
+	}
+	for _, p := range osReleasePaths {
+		if data, ok := files[p]; ok {
+			result.OS = parseOSRelease(data)
+			break
+		}
+	}
+	for path, data := range files {
+		switch {
+		case path == dpkgStatusPath || strings.HasPrefix(path, dpkgStatusDir):
+			result.Packages = append(result.Packages, parseDpkgStatus(data)...)
+		case path == apkInstalledPath:
+			result.Packages = append(result.Packages, parseApkInstalled(data)...)
+		}
+	}
+	if foundRPM {
+		result.Warnings = append(result.Warnings, "RPM packages can't be read from image layers; include a query in the running container (exec) to list them")
+	}
+	SortPackages(result.Packages)
+	return result, nil
+}
+
+// ParsePackageQuery parses the output of PackageQueryScript
+func ParsePackageQuery(out []byte) ([]Package, OSRelease) {
+	var pkgs []Package
+	var osRelease []byte
+	seen := make(map[Package]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if rest, ok := strings.CutPrefix(line, "os\t"); ok {
+			osRelease = append(append(osRelease, rest...), '\n')
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 3 || fields[1] == "" {
+			continue
+		}
+		switch fields[0] {
+		case PackageDeb, PackageApk, PackageRpm:
+		default:
+			continue
+		}
+		p := Package{Type: fields[0], Name: fields[1], Version: fields[2]}
+		if len(fields) > 3 && fields[3] != "(none)" {
+			p.Arch = fields[3]
+		}
+		if !seen[p] {
+			seen[p] = true
+			pkgs = append(pkgs, p)
+		}
+	}
+	SortPackages(pkgs)
+	return pkgs, parseOSRelease(osRelease)
+}
+
+// SortPackages orders packages by type, name and version
+func SortPackages(pkgs []Package) {
+	sort.Slice(pkgs, func(a, b int) bool {
+		if pkgs[a].Type != pkgs[b].Type {
+			return pkgs[a].Type < pkgs[b].Type
+		}
+		if pkgs[a].Name != pkgs[b].Name {
+			return pkgs[a].Name < pkgs[b].Name
+		}
+		return pkgs[a].Version < pkgs[b].Version
+	})
+}
+
+// parseDpkgStatus parses /var/lib/dpkg/status (or a status.d file): stanzas
+// of "Field: value" lines separated by blank lines
+func parseDpkgStatus(data []byte) []Package {
+	var pkgs []Package
+	var cur Package
+	status := ""
+	flush := func() {
+		// status.d files have no Status field; everything listed is installed
+		if cur.Name != "" && (status == "" || strings.HasSuffix(status, " installed")) {
+			cur.Type = PackageDeb
+			pkgs = append(pkgs, cur)
+		}
+		cur, status = Package{}, ""
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024) // Descriptions can be long
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			continue // Continuation of a multi-line field
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Package":
+			cur.Name = value
+		case "Version":
+			cur.Version = value
+		case "Architecture":
+			cur.Arch = value
+		case "Status":
+			status = value
+		}
+	}
+	flush()
+	return pkgs
+}
+
+// parseApkInstalled parses /lib/apk/db/installed: stanzas of "X:value" lines
+func parseApkInstalled(data []byte) []Package {
+	var pkgs []Package
+	var cur Package
+	flush := func() {
+		if cur.Name != "" {
+			cur.Type = PackageApk
+			pkgs = append(pkgs, cur)
+		}
+		cur = Package{}
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if len(line) < 2 || line[1] != ':' {
+			if strings.TrimSpace(line) == "" {
+				flush()
+			}
+			continue
+		}
+		switch line[0] {
+		case 'P':
+			cur.Name = line[2:]
+		case 'V':
+			cur.Version = line[2:]
+		case 'A':
+			cur.Arch = line[2:]
+		}
+	}
+	flush()
+	return pkgs
+}
+
+// parseOSRelease parses the KEY=value (optionally quoted) lines of os-release
+func parseOSRelease(data []byte) OSRelease {
+	var rel OSRelease
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"'`)
+		switch key {
+		case "ID":
+			rel.ID = value
+		case "VERSION_ID":
+			rel.VersionID = value
+		case "PRETTY_NAME":
+			rel.PrettyName = value
+		}
+	}
+	return rel
+}
+
+// readMatchingFiles returns the final content of every regular file whose
+// path matches, applying the layers bottom to top (including whiteouts)
+func readMatchingFiles(ctx context.Context, layerPaths []string, match func(path string) bool) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	for _, layerPath := range layerPaths {
+		if err := readMatchingFilesFromLayer(ctx, layerPath, match, files); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func readMatchingFilesFromLayer(ctx context.Context, layerPath string, match func(path string) bool, files map[string][]byte) error {
+	file, err := os.Open(layerPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	tr := tar.NewReader(file)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read layer: %w", err)
+		}
+
+		path := filepath.Clean("/" + strings.TrimPrefix(header.Name, "./"))
+		base := filepath.Base(path)
+		dir := filepath.Dir(path)
+		if base == ".wh..wh..opq" {
+			// Opaque directory: drop everything lower layers put in it
+			for p := range files {
+				if strings.HasPrefix(p, dir+"/") {
+					delete(files, p)
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(base, ".wh.") {
+			deleted := filepath.Join(dir, strings.TrimPrefix(base, ".wh."))
+			for p := range files {
+				if p == deleted || strings.HasPrefix(p, deleted+"/") {
+					delete(files, p)
+				}
+			}
+			continue
+		}
+		if header.Typeflag != tar.TypeReg || !match(path) {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		files[path] = data
+	}
+}
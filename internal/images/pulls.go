@@ -0,0 +1,301 @@
+package images
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// ImagePullStats aggregates kubelet pull events for one image
+type ImagePullStats struct {
+	Image       string   `json:"image"`
+	Registry    string   `json:"registry"`
+	Pulls       int      `json:"pulls"`     // Successful pulls from the registry
+	CacheHits   int      `json:"cacheHits"` // "already present on machine"
+	Failures    int      `json:"failures"`
+	FailureRate float64  `json:"failureRate"` // Failures / (pulls + failures)
+	P50Ms       int64    `json:"p50Ms"`
+	P95Ms       int64    `json:"p95Ms"`
+	MaxMs       int64    `json:"maxMs"`
+	PulledOn    []string `json:"pulledOn,omitempty"` // Nodes that had to pull the image
+	// Ready nodes whose image cache doesn't list the image. Kubelet only
+	// reports the 50 largest images per node by default, so this can overcount.
+	MissingOnNodes []string `json:"missingOnNodes,omitempty"`
+	LastFailure    string   `json:"lastFailure,omitempty"`
+}
+
+// RegistryPullStats aggregates pull events per registry
+type RegistryPullStats struct {
+	Registry    string  `json:"registry"`
+	Images      int     `json:"images"`
+	Pulls       int     `json:"pulls"`
+	CacheHits   int     `json:"cacheHits"`
+	Failures    int     `json:"failures"`
+	FailureRate float64 `json:"failureRate"`
+	P50Ms       int64   `json:"p50Ms"`
+	P95Ms       int64   `json:"p95Ms"`
+}
+
+// ImagePullReport summarizes image pulls recorded in the timeline
+type ImagePullReport struct {
+	Since         time.Time           `json:"since"`
+	TotalPulls    int                 `json:"totalPulls"`
+	TotalFailures int                 `json:"totalFailures"`
+	Images        []ImagePullStats    `json:"images"`     // Most failures first, then slowest
+	Registries    []RegistryPullStats `json:"registries"` // Slowest first
+}
+
+var (
+	imageInMessageRe = regexp.MustCompile(`image "([^"]+)"`)
+	// Kubelet reports e.g. `Successfully pulled image "x" in 3.2s (3.2s including waiting)`
+	pullDurationRe = regexp.MustCompile(`Successfully pulled image "[^"]+" in ([0-9.]+[a-zµ]+)`)
+)
+
+// pullEvent is a parsed kubelet image event
+type pullEvent struct {
+	image    string
+	node     string
+	cacheHit bool
+	failed   bool
+	duration time.Duration // Zero when unknown
+	message  string
+}
+
+// GetImagePullReport aggregates Pulled/Failed/BackOff pod events from the
+// timeline since the given time, resolving nodes from the pods in the cache
+func GetImagePullReport(ctx context.Context, namespaces []string, since time.Time) (*ImagePullReport, error) {
+	report := &ImagePullReport{Since: since, Images: []ImagePullStats{}, Registries: []RegistryPullStats{}}
+	store := timeline.GetStore()
+	if store == nil {
+		return report, nil
+	}
+	events, err := store.Query(ctx, timeline.QueryOptions{
+		Namespaces:       namespaces,
+		Kinds:            []string{"Pod"},
+		Sources:          []timeline.EventSource{timeline.SourceK8sEvent},
+		Since:            since,
+		Limit:            10000,
+		IncludeManaged:   true,
+		IncludeK8sEvents: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	podNodes := make(map[string]string)
+	var nodes []*corev1.Node
+	if cache := k8s.GetResourceCache(); cache != nil {
+		if podLister := cache.Pods(); podLister != nil {
+			pods, _ := podLister.List(labels.Everything())
+			for _, p := range pods {
+				podNodes[p.Namespace+"/"+p.Name] = p.Spec.NodeName
+			}
+		}
+		if nodeLister := cache.Nodes(); nodeLister != nil {
+			nodes, _ = nodeLister.List(labels.Everything())
+		}
+	}
+
+	var pulls []pullEvent
+	seen := make(map[string]bool)
+	for _, e := range events {
+		// The same K8s Event is recorded again each time its count is bumped
+		if e.ID != "" && seen[e.ID] {
+			continue
+		}
+		seen[e.ID] = true
+		if pe, ok := parsePullEvent(e.Reason, e.Message); ok {
+			pe.node = podNodes[e.Namespace+"/"+e.Name]
+			pulls = append(pulls, pe)
+		}
+	}
+
+	aggregatePulls(report, pulls, nodes)
+	return report, nil
+}
+
+// parsePullEvent extracts the image and outcome from a kubelet event
+func parsePullEvent(reason, message string) (pullEvent, bool) {
+	m := imageInMessageRe.FindStringSubmatch(message)
+	if m == nil {
+		return pullEvent{}, false
+	}
+	pe := pullEvent{image: m[1], message: message}
+	switch reason {
+	case "Pulled":
+		if strings.Contains(message, "already present on machine") {
+			pe.cacheHit = true
+		} else if d := pullDurationRe.FindStringSubmatch(message); d != nil {
+			pe.duration, _ = time.ParseDuration(d[1])
+		}
+	case "Failed", "ErrImagePull", "ImagePullBackOff", "BackOff":
+		// BackOff is also used for crash loops; only count image pull back-offs
+		if !strings.Contains(strings.ToLower(message), "pull") {
+			return pullEvent{}, false
+		}
+		pe.failed = true
+	default:
+		return pullEvent{}, false
+	}
+	return pe, true
+}
+
+// aggregatePulls fills in per-image and per-registry stats
+func aggregatePulls(report *ImagePullReport, pulls []pullEvent, nodes []*corev1.Node) {
+	type acc struct {
+		stats     ImagePullStats
+		durations []int64
+		pulledOn  map[string]bool
+	}
+	byImage := make(map[string]*acc)
+	for _, pe := range pulls {
+		a, ok := byImage[pe.image]
+		if !ok {
+			a = &acc{stats: ImagePullStats{Image: pe.image, Registry: registryOf(pe.image)}, pulledOn: make(map[string]bool)}
+			byImage[pe.image] = a
+		}
+		switch {
+		case pe.failed:
+			a.stats.Failures++
+			a.stats.LastFailure = pe.message
+		case pe.cacheHit:
+			a.stats.CacheHits++
+		default:
+			a.stats.Pulls++
+			if pe.duration > 0 {
+				a.durations = append(a.durations, pe.duration.Milliseconds())
+			}
+			if pe.node != "" {
+				a.pulledOn[pe.node] = true
+			}
+		}
+	}
+
+	nodeImages := nodeImageSets(nodes)
+	type regAcc struct {
+		stats     RegistryPullStats
+		durations []int64
+	}
+	byRegistry := make(map[string]*regAcc)
+	for _, a := range byImage {
+		s := &a.stats
+		s.FailureRate = failureRate(s.Pulls, s.Failures)
+		s.P50Ms, s.P95Ms = k8s.Percentile(a.durations, 50), k8s.Percentile(a.durations, 95)
+		if len(a.durations) > 0 {
+			s.MaxMs = slices.Max(a.durations)
+		}
+		for node := range a.pulledOn {
+			s.PulledOn = append(s.PulledOn, node)
+		}
+		sort.Strings(s.PulledOn)
+		key := normalizeImageRef(s.Image)
+		for node, images := range nodeImages {
+			if !images[key] {
+				s.MissingOnNodes = append(s.MissingOnNodes, node)
+			}
+		}
+		sort.Strings(s.MissingOnNodes)
+		report.Images = append(report.Images, *s)
+		report.TotalPulls += s.Pulls
+		report.TotalFailures += s.Failures
+
+		r, ok := byRegistry[s.Registry]
+		if !ok {
+			r = &regAcc{stats: RegistryPullStats{Registry: s.Registry}}
+			byRegistry[s.Registry] = r
+		}
+		r.stats.Images++
+		r.stats.Pulls += s.Pulls
+		r.stats.CacheHits += s.CacheHits
+		r.stats.Failures += s.Failures
+		r.durations = append(r.durations, a.durations...)
+	}
+
+	for _, r := range byRegistry {
+		r.stats.FailureRate = failureRate(r.stats.Pulls, r.stats.Failures)
+		r.stats.P50Ms, r.stats.P95Ms = k8s.Percentile(r.durations, 50), k8s.Percentile(r.durations, 95)
+		report.Registries = append(report.Registries, r.stats)
+	}
+
+	sort.Slice(report.Images, func(i, j int) bool {
+		a, b := report.Images[i], report.Images[j]
+		if a.Failures != b.Failures {
+			return a.Failures > b.Failures
+		}
+		if a.P95Ms != b.P95Ms {
+			return a.P95Ms > b.P95Ms
+		}
+		return a.Image < b.Image
+	})
+	sort.Slice(report.Registries, func(i, j int) bool {
+		a, b := report.Registries[i], report.Registries[j]
+		if a.P95Ms != b.P95Ms {
+			return a.P95Ms > b.P95Ms
+		}
+		return a.Registry < b.Registry
+	})
+}
+
+// nodeImageSets returns the normalized image names cached on each Ready node
+func nodeImageSets(nodes []*corev1.Node) map[string]map[string]bool {
+	result := make(map[string]map[string]bool)
+	for _, n := range nodes {
+		ready := false
+		for _, c := range n.Status.Conditions {
+			if c.Type == corev1.NodeReady && c.Status == corev1.ConditionTrue {
+				ready = true
+			}
+		}
+		if !ready {
+			continue
+		}
+		images := make(map[string]bool)
+		for _, img := range n.Status.Images {
+			for _, ref := range img.Names {
+				images[normalizeImageRef(ref)] = true
+			}
+		}
+		result[n.Name] = images
+	}
+	return result
+}
+
+// normalizeImageRef expands an image reference to its fully qualified form
+// (e.g. nginx -> index.docker.io/library/nginx:latest) so pod specs and node
+// image lists compare equal
+func normalizeImageRef(image string) string {
+	ref, err := name.ParseReference(image, name.WeakValidation)
+	if err != nil {
+		return image
+	}
+	return ref.Name()
+}
+
+// registryOf returns the registry host an image is pulled from
+func registryOf(image string) string {
+	ref, err := name.ParseReference(image, name.WeakValidation)
+	if err != nil {
+		return "unknown"
+	}
+	if registry := ref.Context().RegistryStr(); registry != name.DefaultRegistry {
+		return registry
+	}
+	return "docker.io"
+}
+
+func failureRate(pulls, failures int) float64 {
+	if pulls+failures == 0 {
+		return 0
+	}
+	return float64(failures) / float64(pulls+failures)
+}
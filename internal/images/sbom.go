@@ -0,0 +1,275 @@
+package images
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/uuid"
+)
+
+// SBOM formats
+const (
+	SBOMFormatSPDX      = "spdx"      // SPDX 2.3 JSON
+	SBOMFormatCycloneDX = "cyclonedx" // CycloneDX 1.5 JSON
+)
+
+// SBOMSubject is what an SBOM describes: a workload and its images' packages
+type SBOMSubject struct {
+	Name        string // e.g. "deployments/default/web"
+	Images      []*ImagePackages
+	Created     time.Time
+	ToolVersion string
+	Warnings    []string // Images or containers that couldn't be read
+}
+
+// ParseSBOMFormat validates a format name; empty means SPDX
+func ParseSBOMFormat(s string) (string, error) {
+	switch strings.ToLower(s) {
+	case "", SBOMFormatSPDX:
+		return SBOMFormatSPDX, nil
+	case SBOMFormatCycloneDX, "cdx":
+		return SBOMFormatCycloneDX, nil
+	}
+	return "", fmt.Errorf("unsupported SBOM format %q (expected spdx or cyclonedx)", s)
+}
+
+// Document builds the SBOM in the given format, ready to be JSON-encoded
+func (s SBOMSubject) Document(format string) any {
+	if format == SBOMFormatCycloneDX {
+		return s.cycloneDX()
+	}
+	return s.spdx()
+}
+
+// SPDX 2.3 (https://spdx.github.io/spdx-spec/v2.3/)
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+	Comment  string   `json:"comment,omitempty"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	PrimaryPurpose   string            `json:"primaryPackagePurpose,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	Element string `json:"spdxElementId"`
+	Type    string `json:"relationshipType"`
+	Related string `json:"relatedSpdxElement"`
+}
+
+func (s SBOMSubject) spdx() spdxDocument {
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              s.Name,
+		DocumentNamespace: "https://radar.skyhook.io/spdx/" + url.PathEscape(s.Name) + "-" + uuid.NewString(),
+		CreationInfo: spdxCreationInfo{
+			Created:  s.Created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: radar-" + s.ToolVersion},
+			Comment:  strings.Join(s.Warnings, "\n"),
+		},
+		Packages:      []spdxPackage{},
+		Relationships: []spdxRelationship{},
+	}
+	for i, img := range s.Images {
+		imageID := fmt.Sprintf("SPDXRef-Image-%d", i)
+		imagePkg := spdxPackage{
+			Name:             img.Image,
+			SPDXID:           imageID,
+			VersionInfo:      img.Digest,
+			DownloadLocation: "NOASSERTION",
+			PrimaryPurpose:   "CONTAINER",
+		}
+		if purl := imagePURL(img); purl != "" {
+			imagePkg.ExternalRefs = []spdxExternalRef{{"PACKAGE-MANAGER", "purl", purl}}
+		}
+		doc.Packages = append(doc.Packages, imagePkg)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{"SPDXRef-DOCUMENT", "DESCRIBES", imageID})
+
+		for j, p := range img.Packages {
+			pkgID := fmt.Sprintf("SPDXRef-Package-%d-%d", i, j)
+			doc.Packages = append(doc.Packages, spdxPackage{
+				Name:             p.Name,
+				SPDXID:           pkgID,
+				VersionInfo:      p.Version,
+				DownloadLocation: "NOASSERTION",
+				ExternalRefs:     []spdxExternalRef{{"PACKAGE-MANAGER", "purl", packagePURL(p, img.OS)}},
+			})
+			doc.Relationships = append(doc.Relationships, spdxRelationship{imageID, "CONTAINS", pkgID})
+		}
+	}
+	return doc
+}
+
+// CycloneDX 1.5 (https://cyclonedx.org/docs/1.5/json/)
+
+type cdxBOM struct {
+	BOMFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	SerialNumber string          `json:"serialNumber"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies"`
+}
+
+type cdxMetadata struct {
+	Timestamp  string        `json:"timestamp"`
+	Tools      cdxTools      `json:"tools"`
+	Component  cdxComponent  `json:"component"`
+	Properties []cdxProperty `json:"properties,omitempty"`
+}
+
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	BOMRef     string        `json:"bom-ref,omitempty"`
+	Type       string        `json:"type"`
+	Name       string        `json:"name"`
+	Version    string        `json:"version,omitempty"`
+	PURL       string        `json:"purl,omitempty"`
+	Properties []cdxProperty `json:"properties,omitempty"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+func (s SBOMSubject) cycloneDX() cdxBOM {
+	bom := cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + uuid.NewString(),
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: s.Created.UTC().Format(time.RFC3339),
+			Tools:     cdxTools{Components: []cdxComponent{{Type: "application", Name: "radar", Version: s.ToolVersion}}},
+			Component: cdxComponent{BOMRef: "workload", Type: "application", Name: s.Name},
+		},
+		Components:   []cdxComponent{},
+		Dependencies: []cdxDependency{},
+	}
+	for _, w := range s.Warnings {
+		bom.Metadata.Properties = append(bom.Metadata.Properties, cdxProperty{"radar:warning", w})
+	}
+	workload := cdxDependency{Ref: "workload", DependsOn: []string{}}
+	for i, img := range s.Images {
+		imageRef := fmt.Sprintf("image-%d", i)
+		image := cdxComponent{
+			BOMRef:  imageRef,
+			Type:    "container",
+			Name:    img.Image,
+			Version: img.Digest,
+			PURL:    imagePURL(img),
+		}
+		if img.OS.PrettyName != "" {
+			image.Properties = append(image.Properties, cdxProperty{"radar:os", img.OS.PrettyName})
+		}
+		image.Properties = append(image.Properties, cdxProperty{"radar:packageSource", img.Source})
+		bom.Components = append(bom.Components, image)
+		workload.DependsOn = append(workload.DependsOn, imageRef)
+
+		contains := cdxDependency{Ref: imageRef, DependsOn: []string{}}
+		for j, p := range img.Packages {
+			ref := fmt.Sprintf("%s-package-%d", imageRef, j)
+			bom.Components = append(bom.Components, cdxComponent{
+				BOMRef:  ref,
+				Type:    "library",
+				Name:    p.Name,
+				Version: p.Version,
+				PURL:    packagePURL(p, img.OS),
+			})
+			contains.DependsOn = append(contains.DependsOn, ref)
+		}
+		bom.Dependencies = append(bom.Dependencies, contains)
+	}
+	bom.Dependencies = append([]cdxDependency{workload}, bom.Dependencies...)
+	return bom
+}
+
+// packagePURL is a package URL (https://github.com/package-url/purl-spec)
+func packagePURL(p Package, rel OSRelease) string {
+	namespace := rel.ID
+	switch {
+	case p.Type == PackageApk && namespace == "":
+		namespace = "alpine"
+	case p.Type == PackageDeb && namespace == "":
+		namespace = "debian"
+	case namespace == "":
+		namespace = "unknown"
+	}
+	qualifiers := url.Values{}
+	if p.Arch != "" {
+		qualifiers.Set("arch", p.Arch)
+	}
+	if rel.ID != "" && rel.VersionID != "" {
+		qualifiers.Set("distro", rel.ID+"-"+rel.VersionID)
+	}
+	purl := fmt.Sprintf("pkg:%s/%s/%s@%s", p.Type, purlEscape(namespace), purlEscape(p.Name), purlEscape(p.Version))
+	if len(qualifiers) > 0 {
+		purl += "?" + qualifiers.Encode()
+	}
+	return purl
+}
+
+// imagePURL is an OCI package URL for an image pinned to its digest
+func imagePURL(img *ImagePackages) string {
+	if img.Digest == "" {
+		return ""
+	}
+	ref, err := name.ParseReference(img.Image, name.WeakValidation)
+	if err != nil {
+		return ""
+	}
+	repo := ref.Context()
+	last := repo.RepositoryStr()
+	if idx := strings.LastIndex(last, "/"); idx >= 0 {
+		last = last[idx+1:]
+	}
+	qualifiers := url.Values{}
+	qualifiers.Set("repository_url", repo.Name())
+	return fmt.Sprintf("pkg:oci/%s@%s?%s", purlEscape(last), purlEscape(img.Digest), qualifiers.Encode())
+}
+
+// purlEscape percent-encodes a purl segment, including the ':' of Debian
+// epochs and digests and the '+' common in Debian versions
+func purlEscape(s string) string {
+	return strings.NewReplacer(":", "%3A", "+", "%2B").Replace(url.PathEscape(s))
+}
@@ -0,0 +1,377 @@
+package images
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+const (
+	updateCheckTTL         = 30 * time.Minute // How long registry results are reused
+	updateCheckTimeout     = 20 * time.Second // Per-image registry timeout
+	updateCheckConcurrency = 4                // Parallel registry lookups
+	maxNewerTags           = 5
+)
+
+// WorkloadContainer identifies a container in a workload running an image
+type WorkloadContainer struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Container string `json:"container"`
+}
+
+// ImageUpdateStatus reports whether a running image is behind its registry
+type ImageUpdateStatus struct {
+	Image          string              `json:"image"`
+	Tag            string              `json:"tag,omitempty"`            // Empty when pinned by digest
+	RunningDigests []string            `json:"runningDigests,omitempty"` // From pod container statuses
+	RegistryDigest string              `json:"registryDigest,omitempty"` // Current digest of the tag
+	DigestDrift    bool                `json:"digestDrift"`              // Tag now points at a different digest
+	NewerTags      []string            `json:"newerTags,omitempty"`      // Newest first, same versioning scheme as Tag
+	LatestTag      string              `json:"latestTag,omitempty"`
+	Behind         bool                `json:"behind"`
+	AuthMethod     string              `json:"authMethod,omitempty"`
+	Error          string              `json:"error,omitempty"`
+	CheckedAt      time.Time           `json:"checkedAt"`
+	Workloads      []WorkloadContainer `json:"workloads"`
+}
+
+// ImageUpdatesReport lists running images with available updates first
+type ImageUpdatesReport struct {
+	Images []ImageUpdateStatus `json:"images"`
+	Behind int                 `json:"behind"`
+	Errors int                 `json:"errors"`
+}
+
+// registryCheck is the cached registry side of an update check
+type registryCheck struct {
+	registryDigest string
+	newerTags      []string
+	authMethod     string
+	err            string
+	checkedAt      time.Time
+}
+
+// runningImage collects everything known about an image from the pod cache
+type runningImage struct {
+	image       string
+	namespace   string // Where pull secrets are looked up
+	pullSecrets []string
+	digests     map[string]bool
+	workloads   map[WorkloadContainer]bool
+}
+
+// UpdateChecker queries registries for newer tags and digest drift of running
+// images. Checks only run on request and results are cached, so registries
+// aren't polled in the background.
+type UpdateChecker struct {
+	mu    sync.Mutex
+	cache map[string]*registryCheck // Keyed by image reference
+}
+
+// NewUpdateChecker creates an update checker with an empty cache
+func NewUpdateChecker() *UpdateChecker {
+	return &UpdateChecker{cache: make(map[string]*registryCheck)}
+}
+
+// Check compares images running in the given namespaces against their
+// registries. When refresh is set, cached registry results are ignored.
+func (u *UpdateChecker) Check(ctx context.Context, namespaces []string, refresh bool) *ImageUpdatesReport {
+	report := &ImageUpdatesReport{Images: []ImageUpdateStatus{}}
+	running := collectRunningImages(namespaces)
+	if len(running) == 0 {
+		return report
+	}
+
+	results := make([]*registryCheck, len(running))
+	sem := make(chan struct{}, updateCheckConcurrency)
+	var wg sync.WaitGroup
+	for idx, ri := range running {
+		if check := u.cached(ri.image, refresh); check != nil {
+			results[idx] = check
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			check := checkRegistry(ctx, ri)
+			results[idx] = check
+			// Don't remember failures caused by the client going away
+			if ctx.Err() != nil {
+				return
+			}
+			u.mu.Lock()
+			u.cache[ri.image] = check
+			u.mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	for idx, ri := range running {
+		status := buildUpdateStatus(ri, results[idx])
+		if status.Behind {
+			report.Behind++
+		}
+		if status.Error != "" {
+			report.Errors++
+		}
+		report.Images = append(report.Images, status)
+	}
+	sort.Slice(report.Images, func(i, j int) bool {
+		a, b := report.Images[i], report.Images[j]
+		if a.Behind != b.Behind {
+			return a.Behind
+		}
+		return a.Image < b.Image
+	})
+	return report
+}
+
+// cached returns a fresh cached result for an image, or nil
+func (u *UpdateChecker) cached(image string, refresh bool) *registryCheck {
+	if refresh {
+		return nil
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if check, ok := u.cache[image]; ok && time.Since(check.checkedAt) < updateCheckTTL {
+		return check
+	}
+	return nil
+}
+
+// collectRunningImages groups running containers by image, recording the
+// digests they actually run and the workloads they belong to
+func collectRunningImages(namespaces []string) []*runningImage {
+	cache := k8s.GetResourceCache()
+	if cache == nil || cache.Pods() == nil {
+		return nil
+	}
+	pods, err := cache.Pods().List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+
+	byImage := make(map[string]*runningImage)
+	var order []string
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		if len(namespaces) > 0 && !slices.Contains(namespaces, pod.Namespace) {
+			continue
+		}
+		kind, workload := k8s.PodWorkload(pod)
+		statuses := make(map[string]corev1.ContainerStatus, len(pod.Status.ContainerStatuses))
+		for _, cs := range pod.Status.ContainerStatuses {
+			statuses[cs.Name] = cs
+		}
+		for _, c := range pod.Spec.Containers {
+			ri, ok := byImage[c.Image]
+			if !ok {
+				ri = &runningImage{
+					image:       c.Image,
+					namespace:   pod.Namespace,
+					pullSecrets: GetPullSecretsFromPod(pod.Namespace, pod.Name),
+					digests:     make(map[string]bool),
+					workloads:   make(map[WorkloadContainer]bool),
+				}
+				byImage[c.Image] = ri
+				order = append(order, c.Image)
+			}
+			if digest := imageIDDigest(statuses[c.Name].ImageID); digest != "" {
+				ri.digests[digest] = true
+			}
+			ri.workloads[WorkloadContainer{Kind: kind, Namespace: pod.Namespace, Name: workload, Container: c.Name}] = true
+		}
+	}
+
+	result := make([]*runningImage, 0, len(order))
+	for _, image := range order {
+		result = append(result, byImage[image])
+	}
+	return result
+}
+
+// imageIDDigest extracts the digest from a container status imageID such as
+// docker-pullable://nginx@sha256:abc or docker.io/library/nginx@sha256:abc
+func imageIDDigest(imageID string) string {
+	if idx := strings.LastIndex(imageID, "@"); idx >= 0 {
+		return imageID[idx+1:]
+	}
+	return ""
+}
+
+// checkRegistry looks up the current digest of an image's tag and any newer
+// version tags in its repository
+func checkRegistry(ctx context.Context, ri *runningImage) *registryCheck {
+	check := &registryCheck{checkedAt: time.Now()}
+	ref, err := name.ParseReference(ri.image, name.WeakValidation)
+	if err != nil {
+		check.err = fmt.Sprintf("invalid image reference: %v", err)
+		return check
+	}
+	tag, ok := ref.(name.Tag)
+	if !ok {
+		// Pinned by digest: nothing can drift and there's no tag to compare
+		return check
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, updateCheckTimeout)
+	defer cancel()
+	req := InspectRequest{Image: ri.image, Namespace: ri.namespace, PullSecretNames: ri.pullSecrets}
+
+	check.authMethod, err = withRegistryAuth(ctx, req, func(opts ...remote.Option) error {
+		desc, err := remote.Head(tag, opts...)
+		if err != nil {
+			return err
+		}
+		check.registryDigest = desc.Digest.String()
+		return nil
+	})
+	if err != nil {
+		check.err = fmt.Sprintf("failed to resolve tag: %v", err)
+		return check
+	}
+
+	current, ok := parseVersionTag(tag.TagStr())
+	if !ok {
+		return check // e.g. "latest": only digest drift applies
+	}
+	var tags []string
+	if _, err := withRegistryAuth(ctx, req, func(opts ...remote.Option) error {
+		var err error
+		tags, err = remote.List(tag.Context(), opts...)
+		return err
+	}); err != nil {
+		check.err = fmt.Sprintf("failed to list tags: %v", err)
+		return check
+	}
+	check.newerTags = newerVersionTags(current, tags)
+	return check
+}
+
+// buildUpdateStatus merges what's running with the registry check
+func buildUpdateStatus(ri *runningImage, check *registryCheck) ImageUpdateStatus {
+	status := ImageUpdateStatus{
+		Image:          ri.image,
+		RegistryDigest: check.registryDigest,
+		NewerTags:      check.newerTags,
+		AuthMethod:     check.authMethod,
+		Error:          check.err,
+		CheckedAt:      check.checkedAt,
+		Workloads:      []WorkloadContainer{},
+	}
+	if ref, err := name.ParseReference(ri.image, name.WeakValidation); err == nil {
+		if tag, ok := ref.(name.Tag); ok {
+			status.Tag = tag.TagStr()
+		}
+	}
+	for digest := range ri.digests {
+		status.RunningDigests = append(status.RunningDigests, digest)
+		if check.registryDigest != "" && digest != check.registryDigest {
+			status.DigestDrift = true
+		}
+	}
+	sort.Strings(status.RunningDigests)
+	if len(status.NewerTags) > 0 {
+		status.LatestTag = status.NewerTags[0]
+	}
+	status.Behind = status.DigestDrift || len(status.NewerTags) > 0
+
+	for w := range ri.workloads {
+		status.Workloads = append(status.Workloads, w)
+	}
+	sort.Slice(status.Workloads, func(i, j int) bool {
+		a, b := status.Workloads[i], status.Workloads[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Container < b.Container
+	})
+	return status
+}
+
+// versionTagRe matches tags like 1.25, v2.3.1, or 1.25.3-alpine
+var versionTagRe = regexp.MustCompile(`^(v?)(\d+(?:\.\d+)*)(-[0-9A-Za-z.-]+)?$`)
+
+// versionTag is a parsed numeric version tag. Tags are only compared with
+// others using the same prefix, suffix, and number of components, so
+// 1.25-alpine is never "updated" to 1.26.1 or 2.0-rc1.
+type versionTag struct {
+	prefix string
+	parts  []int
+	suffix string
+}
+
+func parseVersionTag(tag string) (versionTag, bool) {
+	m := versionTagRe.FindStringSubmatch(tag)
+	if m == nil {
+		return versionTag{}, false
+	}
+	v := versionTag{prefix: m[1], suffix: m[3]}
+	for _, p := range strings.Split(m[2], ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return versionTag{}, false
+		}
+		v.parts = append(v.parts, n)
+	}
+	return v, true
+}
+
+func (v versionTag) comparable(other versionTag) bool {
+	return v.prefix == other.prefix && v.suffix == other.suffix && len(v.parts) == len(other.parts)
+}
+
+// less reports whether v is an older version than other
+func (v versionTag) less(other versionTag) bool {
+	for i := range v.parts {
+		if v.parts[i] != other.parts[i] {
+			return v.parts[i] < other.parts[i]
+		}
+	}
+	return false
+}
+
+// newerVersionTags returns up to maxNewerTags tags newer than current, newest first
+func newerVersionTags(current versionTag, tags []string) []string {
+	type candidate struct {
+		tag     string
+		version versionTag
+	}
+	var newer []candidate
+	for _, t := range tags {
+		v, ok := parseVersionTag(t)
+		if !ok || !current.comparable(v) || !current.less(v) {
+			continue
+		}
+		newer = append(newer, candidate{tag: t, version: v})
+	}
+	sort.Slice(newer, func(i, j int) bool { return newer[j].version.less(newer[i].version) })
+
+	result := make([]string, 0, min(len(newer), maxNewerTags))
+	for _, c := range newer[:min(len(newer), maxNewerTags)] {
+		result = append(result, c.tag)
+	}
+	return result
+}
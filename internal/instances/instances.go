@@ -0,0 +1,187 @@
+// Package instances keeps a registry of the Radar processes running on this
+// machine under ~/.radar/instances, one JSON file per process, so a new CLI
+// or desktop launch can reuse an instance already watching the same context
+// instead of starting a second set of informers against the cluster.
+package instances
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Instance modes
+const (
+	ModeCLI     = "cli"
+	ModeDesktop = "desktop"
+)
+
+// Instance is a running Radar process
+type Instance struct {
+	PID        int       `json:"pid"`
+	Port       int       `json:"port"`
+	Context    string    `json:"context"`
+	Kubeconfig string    `json:"kubeconfig,omitempty"` // Kubeconfig file or dirs, to tell same-named contexts apart
+	Mode       string    `json:"mode"`
+	Version    string    `json:"version,omitempty"`
+	StartedAt  time.Time `json:"startedAt"`
+}
+
+// URL is the address the instance serves its UI on
+func (i Instance) URL() string {
+	return fmt.Sprintf("http://localhost:%d", i.Port)
+}
+
+// Registration is this process's entry in the registry
+type Registration struct {
+	mu       sync.Mutex
+	path     string
+	instance Instance
+}
+
+// registry is a directory of <pid>.json files
+type registry struct {
+	dir string
+}
+
+// defaultRegistry returns the registry under ~/.radar/instances
+func defaultRegistry() (*registry, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return &registry{dir: filepath.Join(home, ".radar", "instances")}, nil
+}
+
+// Register records this process in the registry. PID and StartedAt are
+// filled in when unset. Close the registration on shutdown.
+func Register(inst Instance) (*Registration, error) {
+	reg, err := defaultRegistry()
+	if err != nil {
+		return nil, err
+	}
+	return reg.register(inst)
+}
+
+// List returns the live instances, oldest first. Entries of processes that
+// exited without unregistering (crashes, kill -9) are removed.
+func List() []Instance {
+	reg, err := defaultRegistry()
+	if err != nil {
+		return nil
+	}
+	return reg.list()
+}
+
+// Find returns another live instance for the same context and kubeconfig.
+// An empty mode matches any mode.
+func Find(context, kubeconfig, mode string) (Instance, bool) {
+	reg, err := defaultRegistry()
+	if err != nil {
+		return Instance{}, false
+	}
+	return reg.find(context, kubeconfig, mode)
+}
+
+func (r *registry) register(inst Instance) (*Registration, error) {
+	if err := os.MkdirAll(r.dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create instance registry: %w", err)
+	}
+	if inst.PID == 0 {
+		inst.PID = os.Getpid()
+	}
+	if inst.StartedAt.IsZero() {
+		inst.StartedAt = time.Now()
+	}
+	reg := &Registration{path: filepath.Join(r.dir, strconv.Itoa(inst.PID)+".json"), instance: inst}
+	if err := reg.write(); err != nil {
+		return nil, err
+	}
+	return reg, nil
+}
+
+func (r *registry) list() []Instance {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil
+	}
+	var result []Instance
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(r.dir, e.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var inst Instance
+		if err := json.Unmarshal(data, &inst); err != nil || inst.PID == 0 {
+			// Half-written files are replaced atomically, so this is junk
+			os.Remove(path)
+			continue
+		}
+		if !processAlive(inst.PID) {
+			os.Remove(path)
+			continue
+		}
+		result = append(result, inst)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].StartedAt.Before(result[j].StartedAt) })
+	return result
+}
+
+func (r *registry) find(context, kubeconfig, mode string) (Instance, bool) {
+	self := os.Getpid()
+	for _, inst := range r.list() {
+		if inst.PID == self || inst.Port == 0 {
+			continue
+		}
+		if inst.Context == context && inst.Kubeconfig == kubeconfig && (mode == "" || inst.Mode == mode) {
+			return inst, true
+		}
+	}
+	return Instance{}, false
+}
+
+// SetContext updates the registered context after a context switch
+func (r *Registration) SetContext(context string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.instance.Context = context
+	if err := r.write(); err != nil {
+		log.Printf("[instances] Failed to update registration: %v", err)
+	}
+}
+
+// Close removes the registration
+func (r *Registration) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	os.Remove(r.path)
+}
+
+// write replaces the registration file atomically, so readers never see a
+// partial entry. Callers hold r.mu (or own r exclusively).
+func (r *Registration) write() error {
+	data, err := json.Marshal(r.instance)
+	if err != nil {
+		return err
+	}
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write instance registration: %w", err)
+	}
+	if err := os.Rename(tmp, r.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write instance registration: %w", err)
+	}
+	return nil
+}
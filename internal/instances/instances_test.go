@@ -0,0 +1,66 @@
+package instances
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	reg := &registry{dir: t.TempDir()}
+
+	// Another live process (the test's parent) for the same context
+	other, err := reg.register(Instance{PID: os.Getppid(), Port: 9280, Context: "kind-dev", Kubeconfig: "/home/me/.kube/config", Mode: ModeCLI})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	self, err := reg.register(Instance{Port: 9281, Context: "kind-dev", Kubeconfig: "/home/me/.kube/config", Mode: ModeDesktop, StartedAt: time.Now().Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	if got := reg.list(); len(got) != 2 || got[0].Port != 9280 {
+		t.Fatalf("expected both instances, oldest first, got %+v", got)
+	}
+
+	inst, ok := reg.find("kind-dev", "/home/me/.kube/config", "")
+	if !ok || inst.Port != 9280 {
+		t.Errorf("expected to find the other instance, got %+v %v", inst, ok)
+	}
+	if _, ok := reg.find("kind-dev", "/home/me/.kube/config", ModeDesktop); ok {
+		t.Error("own registration must not be returned")
+	}
+	if _, ok := reg.find("kind-dev", "/tmp/other-kubeconfig", ""); ok {
+		t.Error("a different kubeconfig must not match")
+	}
+
+	other.SetContext("prod")
+	if _, ok := reg.find("kind-dev", "/home/me/.kube/config", ""); ok {
+		t.Error("expected no match after the other instance switched contexts")
+	}
+
+	self.Close()
+	other.Close()
+	if got := reg.list(); len(got) != 0 {
+		t.Errorf("expected an empty registry, got %+v", got)
+	}
+}
+
+func TestRegistryPrunesStaleEntries(t *testing.T) {
+	reg := &registry{dir: t.TempDir()}
+	// Well above any real pid_max
+	if _, err := reg.register(Instance{PID: 1 << 30, Port: 9280, Context: "gone"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(reg.dir, "123.json"), []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := reg.list(); len(got) != 0 {
+		t.Errorf("expected stale entries to be skipped, got %+v", got)
+	}
+	if entries, _ := os.ReadDir(reg.dir); len(entries) != 0 {
+		t.Errorf("expected stale files to be removed, %d left", len(entries))
+	}
+}
@@ -0,0 +1,21 @@
+//go:build windows
+
+package instances
+
+import "syscall"
+
+const processQueryLimitedInformation = 0x1000
+
+// processAlive reports whether a process exists
+func processAlive(pid int) bool {
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer syscall.CloseHandle(h)
+	var code uint32
+	if err := syscall.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+	return code == 259 // STILL_ACTIVE
+}
@@ -0,0 +1,888 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+)
+
+// ErrInvalidManifest is returned when the submitted manifest can't be simulated
+// (bad YAML, missing kind/name, unknown resource type)
+var ErrInvalidManifest = errors.New("invalid manifest")
+
+// Policy engines reported in AdmissionPolicyMatch
+const (
+	PolicyEngineVAP        = "ValidatingAdmissionPolicy"
+	PolicyEngineGatekeeper = "Gatekeeper"
+	PolicyEngineKyverno    = "Kyverno"
+)
+
+// AdmissionWebhookMatch is an admission webhook whose rules and selectors match the object
+type AdmissionWebhookMatch struct {
+	Name          string `json:"name"`
+	Configuration string `json:"configuration"` // Mutating/ValidatingWebhookConfiguration name
+	Type          string `json:"type"`          // mutating or validating
+	FailurePolicy string `json:"failurePolicy,omitempty"`
+	SideEffects   string `json:"sideEffects,omitempty"`
+	Backend       string `json:"backend,omitempty"`     // namespace/service or URL
+	Conditional   bool   `json:"conditional,omitempty"` // Has CEL matchConditions that weren't evaluated
+}
+
+// AdmissionPolicyMatch is a policy (built-in or policy engine) that selects the object
+type AdmissionPolicyMatch struct {
+	Engine string   `json:"engine"`
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	Action string   `json:"action,omitempty"` // deny, warn, audit, dryrun, Enforce, ...
+	Rules  []string `json:"rules,omitempty"`  // Kyverno rules that match, with their type
+}
+
+// QuotaImpact is a ResourceQuota entry that constrains the object
+type QuotaImpact struct {
+	Name      string `json:"name"`
+	Resource  string `json:"resource"`
+	Used      string `json:"used"`
+	Hard      string `json:"hard"`
+	Exhausted bool   `json:"exhausted"` // Used already at or above hard limit
+}
+
+// LimitRangeImpact is a LimitRange item that applies defaults or bounds to the object
+type LimitRangeImpact struct {
+	Name           string            `json:"name"`
+	Type           string            `json:"type"` // Container, Pod, PersistentVolumeClaim
+	Default        map[string]string `json:"default,omitempty"`
+	DefaultRequest map[string]string `json:"defaultRequest,omitempty"`
+	Min            map[string]string `json:"min,omitempty"`
+	Max            map[string]string `json:"max,omitempty"`
+}
+
+// AdmissionSimulation is a pre-flight report of what admission would do to a manifest
+type AdmissionSimulation struct {
+	APIVersion     string                  `json:"apiVersion"`
+	Kind           string                  `json:"kind"`
+	Namespace      string                  `json:"namespace,omitempty"`
+	Name           string                  `json:"name"`
+	Operation      string                  `json:"operation"` // CREATE or UPDATE (object already exists)
+	DryRun         ValidationResult        `json:"dryRun"`
+	DryRunError    string                  `json:"dryRunError,omitempty"` // Dry-run failed for a non-validation reason (RBAC, webhook unreachable)
+	Mutations      []string                `json:"mutations,omitempty"`   // Fields added or changed by defaulting and mutating admission
+	Webhooks       []AdmissionWebhookMatch `json:"webhooks"`
+	Policies       []AdmissionPolicyMatch  `json:"policies"`
+	ResourceQuotas []QuotaImpact           `json:"resourceQuotas"`
+	LimitRanges    []LimitRangeImpact      `json:"limitRanges"`
+	Warnings       []string                `json:"warnings,omitempty"` // Checks that couldn't run (usually RBAC)
+}
+
+// admissionTarget is the resolved identity of the simulated object
+type admissionTarget struct {
+	obj             *unstructured.Unstructured
+	gvr             schema.GroupVersionResource
+	namespaced      bool
+	namespaceLabels map[string]string
+}
+
+// podBearingKinds are kinds whose pods are subject to compute quotas and container limit ranges
+var podBearingKinds = map[string]bool{
+	"Pod": true, "Deployment": true, "StatefulSet": true, "DaemonSet": true,
+	"ReplicaSet": true, "ReplicationController": true, "Job": true, "CronJob": true,
+}
+
+// SimulateAdmission reports which webhooks, admission policies, quotas, and
+// limit ranges would apply to a manifest, along with the server-side dry-run
+// result. defaultNamespace is used for namespaced objects without one.
+func SimulateAdmission(ctx context.Context, manifest []byte, defaultNamespace string) (*AdmissionSimulation, error) {
+	discovery := GetResourceDiscovery()
+	if discovery == nil {
+		return nil, fmt.Errorf("resource discovery not initialized")
+	}
+	dynamicClient := GetDynamicClient()
+	client := GetClient()
+	if dynamicClient == nil || client == nil {
+		return nil, fmt.Errorf("kubernetes client not initialized")
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(manifest, &obj.Object); err != nil {
+		return nil, fmt.Errorf("%w: invalid YAML: %v", ErrInvalidManifest, err)
+	}
+	if obj.GetKind() == "" || obj.GetAPIVersion() == "" {
+		return nil, fmt.Errorf("%w: apiVersion and kind are required", ErrInvalidManifest)
+	}
+	if obj.GetName() == "" {
+		return nil, fmt.Errorf("%w: metadata.name is required", ErrInvalidManifest)
+	}
+
+	gvk := obj.GroupVersionKind()
+	res, ok := findAPIResourceForGVK(discovery, gvk)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown resource type %s", ErrInvalidManifest, gvk.String())
+	}
+	target := &admissionTarget{
+		obj:        obj,
+		gvr:        schema.GroupVersionResource{Group: res.Group, Version: res.Version, Resource: res.Name},
+		namespaced: res.Namespaced,
+	}
+	if target.namespaced {
+		if obj.GetNamespace() == "" {
+			if defaultNamespace == "" {
+				defaultNamespace = "default"
+			}
+			obj.SetNamespace(defaultNamespace)
+		}
+		target.namespaceLabels = namespaceLabels(ctx, obj.GetNamespace())
+	} else {
+		obj.SetNamespace("")
+		if gvk.Kind == "Namespace" {
+			target.namespaceLabels = obj.GetLabels()
+		}
+	}
+
+	sim := &AdmissionSimulation{
+		APIVersion:     obj.GetAPIVersion(),
+		Kind:           obj.GetKind(),
+		Namespace:      obj.GetNamespace(),
+		Name:           obj.GetName(),
+		Operation:      string(admissionregistrationv1.Create),
+		Webhooks:       []AdmissionWebhookMatch{},
+		Policies:       []AdmissionPolicyMatch{},
+		ResourceQuotas: []QuotaImpact{},
+		LimitRanges:    []LimitRangeImpact{},
+	}
+
+	resourceClient := dynamicClient.Resource(target.gvr)
+	var ri dynamic.ResourceInterface = resourceClient
+	if target.namespaced {
+		ri = resourceClient.Namespace(obj.GetNamespace())
+	}
+	existing, err := ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err == nil {
+		sim.Operation = string(admissionregistrationv1.Update)
+	} else if !apierrors.IsNotFound(err) {
+		sim.Warnings = append(sim.Warnings, fmt.Sprintf("could not check whether %s exists: %v", obj.GetName(), err))
+	}
+
+	op := admissionregistrationv1.OperationType(sim.Operation)
+	sim.Webhooks = append(sim.Webhooks, matchAdmissionWebhooks(ctx, target, op, sim)...)
+	sim.Policies = append(sim.Policies, matchValidatingAdmissionPolicies(ctx, target, op, sim)...)
+	sim.Policies = append(sim.Policies, matchGatekeeperConstraints(ctx, discovery, target, sim)...)
+	sim.Policies = append(sim.Policies, matchKyvernoPolicies(ctx, target, sim)...)
+	if target.namespaced {
+		sim.ResourceQuotas = append(sim.ResourceQuotas, quotaImpacts(ctx, target, sim)...)
+		sim.LimitRanges = append(sim.LimitRanges, limitRangeImpacts(ctx, target, sim)...)
+	}
+
+	runAdmissionDryRun(ctx, target, existing, sim)
+	return sim, nil
+}
+
+// findAPIResourceForGVK finds the discovered resource for an exact group/version/kind
+func findAPIResourceForGVK(discovery *ResourceDiscovery, gvk schema.GroupVersionKind) (APIResource, bool) {
+	resources, err := discovery.GetAPIResources()
+	if err != nil {
+		return APIResource{}, false
+	}
+	for _, res := range resources {
+		if res.Group == gvk.Group && res.Version == gvk.Version && res.Kind == gvk.Kind {
+			return res, true
+		}
+	}
+	return APIResource{}, false
+}
+
+func namespaceLabels(ctx context.Context, namespace string) map[string]string {
+	if cache := GetResourceCache(); cache != nil {
+		if nsLister := cache.Namespaces(); nsLister != nil {
+			if ns, err := nsLister.Get(namespace); err == nil {
+				return ns.Labels
+			}
+		}
+	}
+	if client := GetClient(); client != nil {
+		if ns, err := client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{}); err == nil {
+			return ns.Labels
+		}
+	}
+	// Unknown namespace: the API server adds kubernetes.io/metadata.name automatically
+	return map[string]string{corev1.LabelMetadataName: namespace}
+}
+
+// runAdmissionDryRun submits the object with dryRun=All and records the outcome
+// and any fields that defaulting or mutating admission changed.
+func runAdmissionDryRun(ctx context.Context, target *admissionTarget, existing *unstructured.Unstructured, sim *AdmissionSimulation) {
+	resourceClient := GetDynamicClient().Resource(target.gvr)
+	obj := target.obj.DeepCopy()
+
+	var result *unstructured.Unstructured
+	var err error
+	if existing != nil {
+		obj.SetResourceVersion(existing.GetResourceVersion())
+		opts := metav1.UpdateOptions{DryRun: []string{metav1.DryRunAll}}
+		if target.namespaced {
+			result, err = resourceClient.Namespace(obj.GetNamespace()).Update(ctx, obj, opts)
+		} else {
+			result, err = resourceClient.Update(ctx, obj, opts)
+		}
+	} else {
+		opts := metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}}
+		if target.namespaced {
+			result, err = resourceClient.Namespace(obj.GetNamespace()).Create(ctx, obj, opts)
+		} else {
+			result, err = resourceClient.Create(ctx, obj, opts)
+		}
+	}
+
+	if err != nil {
+		if validation := ValidationResultFromError(err); validation != nil {
+			sim.DryRun = *validation
+		} else {
+			sim.DryRunError = err.Error()
+		}
+		return
+	}
+	sim.DryRun = ValidationResult{Valid: true}
+
+	// Compare against what would be persisted for an update, or the submitted object for a create
+	before := target.obj.Object
+	if existing != nil {
+		before = existing.Object
+	}
+	sim.Mutations = mutatedPaths(before, result.Object)
+}
+
+// ignoredMutationPaths are server-managed fields that always differ after a write
+var ignoredMutationPaths = map[string]bool{
+	"metadata.uid":               true,
+	"metadata.resourceVersion":   true,
+	"metadata.creationTimestamp": true,
+	"metadata.managedFields":     true,
+	"metadata.generation":        true,
+	"metadata.selfLink":          true,
+	"status":                     true,
+}
+
+// mutatedPaths returns the leaf paths that were added or changed from before to after
+func mutatedPaths(before, after map[string]any) []string {
+	var paths []string
+	var walk func(prefix string, b, a any)
+	walk = func(prefix string, b, a any) {
+		if ignoredMutationPaths[prefix] {
+			return
+		}
+		am, aIsMap := a.(map[string]any)
+		bm, bIsMap := b.(map[string]any)
+		if aIsMap && (bIsMap || b == nil) {
+			for k, v := range am {
+				path := k
+				if prefix != "" {
+					path = prefix + "." + k
+				}
+				walk(path, bm[k], v)
+			}
+			return
+		}
+		al, aIsList := a.([]any)
+		bl, bIsList := b.([]any)
+		if aIsList && bIsList && len(al) == len(bl) {
+			for i := range al {
+				walk(fmt.Sprintf("%s[%d]", prefix, i), bl[i], al[i])
+			}
+			return
+		}
+		if !reflect.DeepEqual(b, a) {
+			paths = append(paths, prefix)
+		}
+	}
+	walk("", before, after)
+	sort.Strings(paths)
+	return paths
+}
+
+// matchAdmissionWebhooks lists mutating and validating webhooks whose rules,
+// namespace selector, and object selector match the object.
+func matchAdmissionWebhooks(ctx context.Context, target *admissionTarget, op admissionregistrationv1.OperationType, sim *AdmissionSimulation) []AdmissionWebhookMatch {
+	client := GetClient()
+	var matches []AdmissionWebhookMatch
+
+	mutating, err := client.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		sim.Warnings = append(sim.Warnings, fmt.Sprintf("could not list mutating webhooks: %v", err))
+	} else {
+		for _, cfg := range mutating.Items {
+			for _, wh := range cfg.Webhooks {
+				if webhookMatches(wh.Rules, wh.NamespaceSelector, wh.ObjectSelector, target, op) {
+					matches = append(matches, webhookMatch(wh.Name, cfg.Name, "mutating", wh.FailurePolicy, wh.SideEffects, wh.ClientConfig, len(wh.MatchConditions) > 0))
+				}
+			}
+		}
+	}
+
+	validating, err := client.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		sim.Warnings = append(sim.Warnings, fmt.Sprintf("could not list validating webhooks: %v", err))
+	} else {
+		for _, cfg := range validating.Items {
+			for _, wh := range cfg.Webhooks {
+				if webhookMatches(wh.Rules, wh.NamespaceSelector, wh.ObjectSelector, target, op) {
+					matches = append(matches, webhookMatch(wh.Name, cfg.Name, "validating", wh.FailurePolicy, wh.SideEffects, wh.ClientConfig, len(wh.MatchConditions) > 0))
+				}
+			}
+		}
+	}
+	return matches
+}
+
+func webhookMatch(name, configuration, webhookType string, failurePolicy *admissionregistrationv1.FailurePolicyType, sideEffects *admissionregistrationv1.SideEffectClass, cc admissionregistrationv1.WebhookClientConfig, conditional bool) AdmissionWebhookMatch {
+	m := AdmissionWebhookMatch{Name: name, Configuration: configuration, Type: webhookType, Conditional: conditional}
+	if failurePolicy != nil {
+		m.FailurePolicy = string(*failurePolicy)
+	}
+	if sideEffects != nil {
+		m.SideEffects = string(*sideEffects)
+	}
+	if cc.Service != nil {
+		m.Backend = cc.Service.Namespace + "/" + cc.Service.Name
+	} else if cc.URL != nil {
+		m.Backend = *cc.URL
+	}
+	return m
+}
+
+// webhookMatches applies the API server's webhook matching: rules, then
+// namespaceSelector (namespaced objects and Namespaces), then objectSelector.
+func webhookMatches(rules []admissionregistrationv1.RuleWithOperations, nsSelector, objSelector *metav1.LabelSelector, target *admissionTarget, op admissionregistrationv1.OperationType) bool {
+	if !admissionRulesMatch(rules, op, target.gvr, target.namespaced) {
+		return false
+	}
+	if target.namespaceLabels != nil && !labelSelectorMatches(nsSelector, target.namespaceLabels) {
+		return false
+	}
+	return labelSelectorMatches(objSelector, target.obj.GetLabels())
+}
+
+// admissionRulesMatch reports whether any rule covers the operation and resource
+func admissionRulesMatch(rules []admissionregistrationv1.RuleWithOperations, op admissionregistrationv1.OperationType, gvr schema.GroupVersionResource, namespaced bool) bool {
+	for _, rule := range rules {
+		if !slices.Contains(rule.Operations, op) && !slices.Contains(rule.Operations, admissionregistrationv1.OperationAll) {
+			continue
+		}
+		if !matchesWildcard(rule.APIGroups, gvr.Group) || !matchesWildcard(rule.APIVersions, gvr.Version) {
+			continue
+		}
+		// Resource rules may name subresources ("pods/status"); only the main resource is simulated
+		if !slices.ContainsFunc(rule.Resources, func(r string) bool { return r == "*" || r == "*/*" || r == gvr.Resource }) {
+			continue
+		}
+		if rule.Scope != nil {
+			switch *rule.Scope {
+			case admissionregistrationv1.NamespacedScope:
+				if !namespaced {
+					continue
+				}
+			case admissionregistrationv1.ClusterScope:
+				if namespaced {
+					continue
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func matchesWildcard(values []string, value string) bool {
+	return slices.Contains(values, "*") || slices.Contains(values, value)
+}
+
+// labelSelectorMatches treats a nil selector as match-everything, like admission does
+func labelSelectorMatches(selector *metav1.LabelSelector, objLabels map[string]string) bool {
+	if selector == nil {
+		return true
+	}
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false
+	}
+	return sel.Matches(labels.Set(objLabels))
+}
+
+// matchValidatingAdmissionPolicies lists bound ValidatingAdmissionPolicies whose match constraints cover the object
+func matchValidatingAdmissionPolicies(ctx context.Context, target *admissionTarget, op admissionregistrationv1.OperationType, sim *AdmissionSimulation) []AdmissionPolicyMatch {
+	client := GetClient()
+	policies, err := client.AdmissionregistrationV1().ValidatingAdmissionPolicies().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		// Not served before Kubernetes 1.30; only report permission problems
+		if apierrors.IsForbidden(err) {
+			sim.Warnings = append(sim.Warnings, fmt.Sprintf("could not list validating admission policies: %v", err))
+		}
+		return nil
+	}
+	bindings, err := client.AdmissionregistrationV1().ValidatingAdmissionPolicyBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		sim.Warnings = append(sim.Warnings, fmt.Sprintf("could not list validating admission policy bindings: %v", err))
+		return nil
+	}
+
+	var matches []AdmissionPolicyMatch
+	for _, policy := range policies.Items {
+		mc := policy.Spec.MatchConstraints
+		if mc == nil || !namedRulesMatch(mc.ResourceRules, op, target) || namedRulesMatch(mc.ExcludeResourceRules, op, target) {
+			continue
+		}
+		if target.namespaceLabels != nil && !labelSelectorMatches(mc.NamespaceSelector, target.namespaceLabels) {
+			continue
+		}
+		if !labelSelectorMatches(mc.ObjectSelector, target.obj.GetLabels()) {
+			continue
+		}
+		for _, binding := range bindings.Items {
+			if binding.Spec.PolicyName != policy.Name {
+				continue
+			}
+			if br := binding.Spec.MatchResources; br != nil {
+				if len(br.ResourceRules) > 0 && !namedRulesMatch(br.ResourceRules, op, target) {
+					continue
+				}
+				if target.namespaceLabels != nil && !labelSelectorMatches(br.NamespaceSelector, target.namespaceLabels) {
+					continue
+				}
+				if !labelSelectorMatches(br.ObjectSelector, target.obj.GetLabels()) {
+					continue
+				}
+			}
+			actions := make([]string, 0, len(binding.Spec.ValidationActions))
+			for _, a := range binding.Spec.ValidationActions {
+				actions = append(actions, string(a))
+			}
+			matches = append(matches, AdmissionPolicyMatch{
+				Engine: PolicyEngineVAP,
+				Kind:   "ValidatingAdmissionPolicy",
+				Name:   policy.Name,
+				Action: strings.Join(actions, ","),
+				Rules:  []string{"binding " + binding.Name},
+			})
+		}
+	}
+	return matches
+}
+
+func namedRulesMatch(rules []admissionregistrationv1.NamedRuleWithOperations, op admissionregistrationv1.OperationType, target *admissionTarget) bool {
+	for _, rule := range rules {
+		if len(rule.ResourceNames) > 0 && !slices.Contains(rule.ResourceNames, target.obj.GetName()) {
+			continue
+		}
+		if admissionRulesMatch([]admissionregistrationv1.RuleWithOperations{rule.RuleWithOperations}, op, target.gvr, target.namespaced) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGatekeeperConstraints lists Gatekeeper constraints whose spec.match selects the object.
+// Each ConstraintTemplate creates its own kind in constraints.gatekeeper.sh.
+func matchGatekeeperConstraints(ctx context.Context, discovery *ResourceDiscovery, target *admissionTarget, sim *AdmissionSimulation) []AdmissionPolicyMatch {
+	resources, err := discovery.GetAPIResources()
+	if err != nil {
+		return nil
+	}
+	dynamicClient := GetDynamicClient()
+
+	var matches []AdmissionPolicyMatch
+	for _, res := range resources {
+		if res.Group != "constraints.gatekeeper.sh" || !slices.Contains(res.Verbs, "list") {
+			continue
+		}
+		gvr := schema.GroupVersionResource{Group: res.Group, Version: res.Version, Resource: res.Name}
+		list, err := dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			sim.Warnings = append(sim.Warnings, fmt.Sprintf("could not list Gatekeeper %s constraints: %v", res.Kind, err))
+			continue
+		}
+		for _, c := range list.Items {
+			match, _, _ := unstructured.NestedMap(c.Object, "spec", "match")
+			if !gatekeeperMatches(match, target) {
+				continue
+			}
+			action, _, _ := unstructured.NestedString(c.Object, "spec", "enforcementAction")
+			if action == "" {
+				action = "deny"
+			}
+			matches = append(matches, AdmissionPolicyMatch{Engine: PolicyEngineGatekeeper, Kind: c.GetKind(), Name: c.GetName(), Action: action})
+		}
+	}
+	return matches
+}
+
+// gatekeeperMatches evaluates the common fields of a Gatekeeper constraint's spec.match
+func gatekeeperMatches(match map[string]any, target *admissionTarget) bool {
+	gvk := target.obj.GroupVersionKind()
+
+	if kinds, ok := match["kinds"].([]any); ok && len(kinds) > 0 {
+		matched := false
+		for _, k := range kinds {
+			m, ok := k.(map[string]any)
+			if !ok {
+				continue
+			}
+			if matchesWildcard(anyStrings(m["apiGroups"]), gvk.Group) && matchesWildcard(anyStrings(m["kinds"]), gvk.Kind) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if scope, _ := match["scope"].(string); scope != "" && scope != "*" {
+		if (scope == "Namespaced") != target.namespaced {
+			return false
+		}
+	}
+
+	ns := target.obj.GetNamespace()
+	if gvk.Kind == "Namespace" && gvk.Group == "" {
+		ns = target.obj.GetName()
+	}
+	if ns != "" {
+		if namespaces := anyStrings(match["namespaces"]); len(namespaces) > 0 && !slices.ContainsFunc(namespaces, func(p string) bool { return globMatches(p, ns) }) {
+			return false
+		}
+		if slices.ContainsFunc(anyStrings(match["excludedNamespaces"]), func(p string) bool { return globMatches(p, ns) }) {
+			return false
+		}
+	}
+
+	if sel, ok := match["labelSelector"].(map[string]any); ok && !unstructuredSelectorMatches(sel, target.obj.GetLabels()) {
+		return false
+	}
+	if sel, ok := match["namespaceSelector"].(map[string]any); ok && target.namespaceLabels != nil && !unstructuredSelectorMatches(sel, target.namespaceLabels) {
+		return false
+	}
+	if name, _ := match["name"].(string); name != "" && !globMatches(name, target.obj.GetName()) {
+		return false
+	}
+	return true
+}
+
+// matchKyvernoPolicies lists Kyverno ClusterPolicies and Policies with rules that select the object
+func matchKyvernoPolicies(ctx context.Context, target *admissionTarget, sim *AdmissionSimulation) []AdmissionPolicyMatch {
+	discovery := GetResourceDiscovery()
+	dynamicClient := GetDynamicClient()
+
+	var matches []AdmissionPolicyMatch
+	for _, kind := range []string{"ClusterPolicy", "Policy"} {
+		gvr, ok := discovery.GetGVRWithGroup(kind, "kyverno.io")
+		if !ok {
+			continue
+		}
+		if kind == "Policy" && !target.namespaced {
+			continue
+		}
+		var list *unstructured.UnstructuredList
+		var err error
+		if kind == "Policy" {
+			list, err = dynamicClient.Resource(gvr).Namespace(target.obj.GetNamespace()).List(ctx, metav1.ListOptions{})
+		} else {
+			list, err = dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+		}
+		if err != nil {
+			sim.Warnings = append(sim.Warnings, fmt.Sprintf("could not list Kyverno %s resources: %v", kind, err))
+			continue
+		}
+		for _, p := range list.Items {
+			ruleNames := kyvernoMatchingRules(p.Object, target)
+			if len(ruleNames) == 0 {
+				continue
+			}
+			action, _, _ := unstructured.NestedString(p.Object, "spec", "validationFailureAction")
+			matches = append(matches, AdmissionPolicyMatch{Engine: PolicyEngineKyverno, Kind: kind, Name: p.GetName(), Action: action, Rules: ruleNames})
+		}
+	}
+	return matches
+}
+
+// kyvernoRuleTypes are the mutually exclusive rule bodies a Kyverno rule can have
+var kyvernoRuleTypes = []string{"validate", "mutate", "generate", "verifyImages"}
+
+// kyvernoMatchingRules returns "name (type)" for each rule whose match selects the object
+// and whose exclude doesn't
+func kyvernoMatchingRules(policy map[string]any, target *admissionTarget) []string {
+	rules, _, _ := unstructured.NestedSlice(policy, "spec", "rules")
+	var names []string
+	for _, r := range rules {
+		rule, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		match, _ := rule["match"].(map[string]any)
+		if match == nil || !kyvernoFilterMatches(match, target) {
+			continue
+		}
+		if exclude, _ := rule["exclude"].(map[string]any); len(exclude) > 0 && kyvernoFilterMatches(exclude, target) {
+			continue
+		}
+		ruleType := ""
+		for _, t := range kyvernoRuleTypes {
+			if _, ok := rule[t]; ok {
+				ruleType = t
+				break
+			}
+		}
+		name, _ := rule["name"].(string)
+		if ruleType != "" {
+			name = fmt.Sprintf("%s (%s)", name, ruleType)
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// kyvernoFilterMatches evaluates a match/exclude block: any (OR), all (AND), or legacy resources
+func kyvernoFilterMatches(filter map[string]any, target *admissionTarget) bool {
+	if anyFilters, ok := filter["any"].([]any); ok && len(anyFilters) > 0 {
+		for _, f := range anyFilters {
+			if m, ok := f.(map[string]any); ok && kyvernoResourceFilterMatches(m, target) {
+				return true
+			}
+		}
+		return false
+	}
+	if allFilters, ok := filter["all"].([]any); ok && len(allFilters) > 0 {
+		for _, f := range allFilters {
+			if m, ok := f.(map[string]any); !ok || !kyvernoResourceFilterMatches(m, target) {
+				return false
+			}
+		}
+		return true
+	}
+	if _, ok := filter["resources"]; ok {
+		return kyvernoResourceFilterMatches(filter, target)
+	}
+	return false
+}
+
+// kyvernoResourceFilterMatches evaluates one resource filter's resources block.
+// User/role/subject filters depend on the requester and are not evaluated.
+func kyvernoResourceFilterMatches(filter map[string]any, target *admissionTarget) bool {
+	resources, ok := filter["resources"].(map[string]any)
+	if !ok {
+		return false
+	}
+	gvk := target.obj.GroupVersionKind()
+
+	if kinds := anyStrings(resources["kinds"]); len(kinds) > 0 && !slices.ContainsFunc(kinds, func(k string) bool { return kyvernoKindMatches(k, gvk) }) {
+		return false
+	}
+	if names := anyStrings(resources["names"]); len(names) > 0 && !slices.ContainsFunc(names, func(n string) bool { return globMatches(n, target.obj.GetName()) }) {
+		return false
+	}
+	if name, _ := resources["name"].(string); name != "" && !globMatches(name, target.obj.GetName()) {
+		return false
+	}
+	if namespaces := anyStrings(resources["namespaces"]); len(namespaces) > 0 {
+		ns := target.obj.GetNamespace()
+		if !slices.ContainsFunc(namespaces, func(n string) bool { return globMatches(n, ns) }) {
+			return false
+		}
+	}
+	if sel, ok := resources["selector"].(map[string]any); ok && !unstructuredSelectorMatches(sel, target.obj.GetLabels()) {
+		return false
+	}
+	if sel, ok := resources["namespaceSelector"].(map[string]any); ok && target.namespaceLabels != nil && !unstructuredSelectorMatches(sel, target.namespaceLabels) {
+		return false
+	}
+	return true
+}
+
+// kyvernoKindMatches matches Kyverno kind strings: "Pod", "apps/v1/Deployment", "batch/*/Job", "*"
+func kyvernoKindMatches(pattern string, gvk schema.GroupVersionKind) bool {
+	if pattern == "*" {
+		return true
+	}
+	parts := strings.Split(pattern, "/")
+	switch len(parts) {
+	case 1:
+		return globMatches(parts[0], gvk.Kind)
+	case 2:
+		// Version/Kind or Group/Kind; Kind/subresource never matches the main resource
+		return globMatches(parts[1], gvk.Kind) && (globMatches(parts[0], gvk.Group) || globMatches(parts[0], gvk.Version))
+	default:
+		return globMatches(parts[0], gvk.Group) && globMatches(parts[1], gvk.Version) && globMatches(parts[2], gvk.Kind)
+	}
+}
+
+// globMatches supports the "*" and "?" wildcards used by Gatekeeper and Kyverno
+func globMatches(pattern, value string) bool {
+	if pattern == "*" || pattern == value {
+		return true
+	}
+	if !strings.ContainsAny(pattern, "*?") {
+		return false
+	}
+	p, v := []rune(pattern), []rune(value)
+	var match func(i, j int) bool
+	match = func(i, j int) bool {
+		for i < len(p) {
+			switch p[i] {
+			case '*':
+				for k := j; k <= len(v); k++ {
+					if match(i+1, k) {
+						return true
+					}
+				}
+				return false
+			case '?':
+				if j >= len(v) {
+					return false
+				}
+			default:
+				if j >= len(v) || p[i] != v[j] {
+					return false
+				}
+			}
+			i++
+			j++
+		}
+		return j == len(v)
+	}
+	return match(0, 0)
+}
+
+func unstructuredSelectorMatches(selector map[string]any, objLabels map[string]string) bool {
+	var sel metav1.LabelSelector
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(selector, &sel); err != nil {
+		return false
+	}
+	return labelSelectorMatches(&sel, objLabels)
+}
+
+func anyStrings(v any) []string {
+	list, ok := v.([]any)
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// quotaImpacts lists ResourceQuota entries in the object's namespace that the object counts against
+func quotaImpacts(ctx context.Context, target *admissionTarget, sim *AdmissionSimulation) []QuotaImpact {
+	quotas, err := GetClient().CoreV1().ResourceQuotas(target.obj.GetNamespace()).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		sim.Warnings = append(sim.Warnings, fmt.Sprintf("could not list resource quotas: %v", err))
+		return nil
+	}
+	var impacts []QuotaImpact
+	for _, q := range quotas.Items {
+		names := make([]string, 0, len(q.Status.Hard))
+		for name := range q.Status.Hard {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if !quotaResourceApplies(name, target) {
+				continue
+			}
+			hard := q.Status.Hard[corev1.ResourceName(name)]
+			used := q.Status.Used[corev1.ResourceName(name)]
+			impacts = append(impacts, QuotaImpact{
+				Name:      q.Name,
+				Resource:  name,
+				Used:      used.String(),
+				Hard:      hard.String(),
+				Exhausted: used.Cmp(hard) >= 0,
+			})
+		}
+	}
+	return impacts
+}
+
+// quotaResourceApplies reports whether a quota resource name is consumed by the object
+func quotaResourceApplies(name string, target *admissionTarget) bool {
+	kind := target.obj.GetKind()
+	resource := target.gvr.Resource
+	if target.gvr.Group != "" {
+		resource += "." + target.gvr.Group
+	}
+	if name == "count/"+resource {
+		return true
+	}
+	if target.gvr.Group == "" && name == target.gvr.Resource {
+		return true
+	}
+	switch {
+	case podBearingKinds[kind]:
+		// Compute quotas apply to the pods a workload creates
+		return name == "pods" || name == "cpu" || name == "memory" ||
+			strings.HasPrefix(name, "requests.") && name != "requests.storage" ||
+			strings.HasPrefix(name, "limits.")
+	case kind == "PersistentVolumeClaim":
+		return name == "requests.storage" || strings.HasSuffix(name, ".storageclass.storage.k8s.io/requests.storage") ||
+			strings.HasSuffix(name, ".storageclass.storage.k8s.io/persistentvolumeclaims")
+	case kind == "Service":
+		return name == "services.loadbalancers" || name == "services.nodeports"
+	}
+	return false
+}
+
+// limitRangeImpacts lists LimitRange items that default or bound the object's containers, pods, or storage
+func limitRangeImpacts(ctx context.Context, target *admissionTarget, sim *AdmissionSimulation) []LimitRangeImpact {
+	kind := target.obj.GetKind()
+	if !podBearingKinds[kind] && kind != "PersistentVolumeClaim" {
+		return nil
+	}
+	ranges, err := GetClient().CoreV1().LimitRanges(target.obj.GetNamespace()).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		sim.Warnings = append(sim.Warnings, fmt.Sprintf("could not list limit ranges: %v", err))
+		return nil
+	}
+	var impacts []LimitRangeImpact
+	for _, lr := range ranges.Items {
+		for _, item := range lr.Spec.Limits {
+			isPVC := item.Type == corev1.LimitTypePersistentVolumeClaim
+			if isPVC != (kind == "PersistentVolumeClaim") {
+				continue
+			}
+			impacts = append(impacts, LimitRangeImpact{
+				Name:           lr.Name,
+				Type:           string(item.Type),
+				Default:        resourceListStrings(item.Default),
+				DefaultRequest: resourceListStrings(item.DefaultRequest),
+				Min:            resourceListStrings(item.Min),
+				Max:            resourceListStrings(item.Max),
+			})
+		}
+	}
+	return impacts
+}
+
+func resourceListStrings(list corev1.ResourceList) map[string]string {
+	if len(list) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(list))
+	for name, q := range list {
+		out[string(name)] = q.String()
+	}
+	return out
+}
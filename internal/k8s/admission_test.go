@@ -0,0 +1,182 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func admissionTestTarget() *admissionTarget {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]any{
+			"name":      "api",
+			"namespace": "shop",
+			"labels":    map[string]any{"app": "api"},
+		},
+	}}
+	return &admissionTarget{
+		obj:             obj,
+		gvr:             schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"},
+		namespaced:      true,
+		namespaceLabels: map[string]string{"kubernetes.io/metadata.name": "shop", "team": "payments"},
+	}
+}
+
+func TestAdmissionRulesMatch(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	namespacedScope := admissionregistrationv1.NamespacedScope
+	clusterScope := admissionregistrationv1.ClusterScope
+	rule := func(ops []admissionregistrationv1.OperationType, groups, resources []string, scope *admissionregistrationv1.ScopeType) admissionregistrationv1.RuleWithOperations {
+		return admissionregistrationv1.RuleWithOperations{
+			Operations: ops,
+			Rule:       admissionregistrationv1.Rule{APIGroups: groups, APIVersions: []string{"*"}, Resources: resources, Scope: scope},
+		}
+	}
+	create := []admissionregistrationv1.OperationType{admissionregistrationv1.Create}
+
+	tests := []struct {
+		name string
+		rule admissionregistrationv1.RuleWithOperations
+		want bool
+	}{
+		{"exact", rule(create, []string{"apps"}, []string{"deployments"}, nil), true},
+		{"wildcards", rule([]admissionregistrationv1.OperationType{admissionregistrationv1.OperationAll}, []string{"*"}, []string{"*"}, nil), true},
+		{"other operation", rule([]admissionregistrationv1.OperationType{admissionregistrationv1.Delete}, []string{"apps"}, []string{"deployments"}, nil), false},
+		{"subresource only", rule(create, []string{"apps"}, []string{"deployments/scale"}, nil), false},
+		{"namespaced scope", rule(create, []string{"*"}, []string{"*"}, &namespacedScope), true},
+		{"cluster scope", rule(create, []string{"*"}, []string{"*"}, &clusterScope), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := admissionRulesMatch([]admissionregistrationv1.RuleWithOperations{tt.rule}, admissionregistrationv1.Create, gvr, true)
+			if got != tt.want {
+				t.Errorf("admissionRulesMatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKyvernoKindMatches(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	tests := map[string]bool{
+		"Deployment":         true,
+		"*":                  true,
+		"apps/v1/Deployment": true,
+		"apps/*/Deployment":  true,
+		"apps/Deployment":    true,
+		"v1/Deployment":      true,
+		"Deployment/scale":   false,
+		"Pod":                false,
+		"batch/v1/Job":       false,
+	}
+	for pattern, want := range tests {
+		if got := kyvernoKindMatches(pattern, gvk); got != want {
+			t.Errorf("kyvernoKindMatches(%q) = %v, want %v", pattern, got, want)
+		}
+	}
+}
+
+func TestGlobMatches(t *testing.T) {
+	tests := []struct {
+		pattern, value string
+		want           bool
+	}{
+		{"kube-*", "kube-system", true},
+		{"kube-*", "default", false},
+		{"ap?", "api", true},
+		{"*-prod", "shop-prod", true},
+		{"shop", "shop", true},
+	}
+	for _, tt := range tests {
+		if got := globMatches(tt.pattern, tt.value); got != tt.want {
+			t.Errorf("globMatches(%q, %q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestGatekeeperMatches(t *testing.T) {
+	target := admissionTestTarget()
+	tests := []struct {
+		name  string
+		match map[string]any
+		want  bool
+	}{
+		{"empty match", map[string]any{}, true},
+		{"kind match", map[string]any{"kinds": []any{map[string]any{"apiGroups": []any{"apps"}, "kinds": []any{"Deployment"}}}}, true},
+		{"kind mismatch", map[string]any{"kinds": []any{map[string]any{"apiGroups": []any{""}, "kinds": []any{"Pod"}}}}, false},
+		{"excluded namespace", map[string]any{"excludedNamespaces": []any{"sh*"}}, false},
+		{"namespace selector", map[string]any{"namespaceSelector": map[string]any{"matchLabels": map[string]any{"team": "payments"}}}, true},
+		{"label selector mismatch", map[string]any{"labelSelector": map[string]any{"matchLabels": map[string]any{"app": "web"}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gatekeeperMatches(tt.match, target); got != tt.want {
+				t.Errorf("gatekeeperMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKyvernoMatchingRules(t *testing.T) {
+	policy := map[string]any{"spec": map[string]any{"rules": []any{
+		map[string]any{
+			"name":     "require-labels",
+			"match":    map[string]any{"any": []any{map[string]any{"resources": map[string]any{"kinds": []any{"Deployment"}}}}},
+			"validate": map[string]any{},
+		},
+		map[string]any{
+			"name":    "skip-shop",
+			"match":   map[string]any{"resources": map[string]any{"kinds": []any{"Deployment"}}},
+			"exclude": map[string]any{"any": []any{map[string]any{"resources": map[string]any{"namespaces": []any{"shop"}}}}},
+			"mutate":  map[string]any{},
+		},
+		map[string]any{
+			"name":  "pods-only",
+			"match": map[string]any{"any": []any{map[string]any{"resources": map[string]any{"kinds": []any{"Pod"}}}}},
+		},
+	}}}
+	got := kyvernoMatchingRules(policy, admissionTestTarget())
+	want := []string{"require-labels (validate)"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("kyvernoMatchingRules() = %v, want %v", got, want)
+	}
+}
+
+func TestMutatedPaths(t *testing.T) {
+	before := map[string]any{
+		"metadata": map[string]any{"name": "api"},
+		"spec":     map[string]any{"replicas": int64(1), "template": map[string]any{"spec": map[string]any{"containers": []any{map[string]any{"name": "app"}}}}},
+	}
+	after := map[string]any{
+		"metadata": map[string]any{"name": "api", "uid": "123", "labels": map[string]any{"injected": "true"}},
+		"spec": map[string]any{"replicas": int64(1), "strategy": map[string]any{"type": "RollingUpdate"},
+			"template": map[string]any{"spec": map[string]any{"containers": []any{map[string]any{"name": "app", "imagePullPolicy": "Always"}}}}},
+		"status": map[string]any{},
+	}
+	want := []string{"metadata.labels.injected", "spec.strategy.type", "spec.template.spec.containers[0].imagePullPolicy"}
+	if got := mutatedPaths(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("mutatedPaths() = %v, want %v", got, want)
+	}
+}
+
+func TestQuotaResourceApplies(t *testing.T) {
+	target := admissionTestTarget()
+	tests := map[string]bool{
+		"count/deployments.apps": true,
+		"requests.cpu":           true,
+		"limits.memory":          true,
+		"pods":                   true,
+		"requests.storage":       false,
+		"services":               false,
+	}
+	for name, want := range tests {
+		if got := quotaResourceApplies(name, target); got != want {
+			t.Errorf("quotaResourceApplies(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
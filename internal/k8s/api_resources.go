@@ -0,0 +1,198 @@
+package k8s
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Watch sources reported by APIResourceStatus
+const (
+	WatchSourceTyped    = "typed"    // Built-in informer in ResourceCache (always on)
+	WatchSourceDynamic  = "dynamic"  // On-demand informer in DynamicResourceCache
+	WatchSourceFallback = "fallback" // Direct lists with a TTL cache (dynamic informer cap reached)
+)
+
+// typedResourceGVRs maps ResourceCache enabledResources keys to the GVR their informer watches
+var typedResourceGVRs = map[string]schema.GroupVersionResource{
+	"pods":                     {Version: "v1", Resource: "pods"},
+	"services":                 {Version: "v1", Resource: "services"},
+	"configmaps":               {Version: "v1", Resource: "configmaps"},
+	"secrets":                  {Version: "v1", Resource: "secrets"},
+	"events":                   {Version: "v1", Resource: "events"},
+	"persistentvolumeclaims":   {Version: "v1", Resource: "persistentvolumeclaims"},
+	"nodes":                    {Version: "v1", Resource: "nodes"},
+	"namespaces":               {Version: "v1", Resource: "namespaces"},
+	"deployments":              {Group: "apps", Version: "v1", Resource: "deployments"},
+	"daemonsets":               {Group: "apps", Version: "v1", Resource: "daemonsets"},
+	"statefulsets":             {Group: "apps", Version: "v1", Resource: "statefulsets"},
+	"replicasets":              {Group: "apps", Version: "v1", Resource: "replicasets"},
+	"ingresses":                {Group: "networking.k8s.io", Version: "v1", Resource: "ingresses"},
+	"jobs":                     {Group: "batch", Version: "v1", Resource: "jobs"},
+	"cronjobs":                 {Group: "batch", Version: "v1", Resource: "cronjobs"},
+	"horizontalpodautoscalers": {Group: "autoscaling", Version: "v2", Resource: "horizontalpodautoscalers"},
+}
+
+// APIResourceStatus is a discovered API resource plus Radar's cache state for it
+type APIResourceStatus struct {
+	APIResource
+	Watchable   bool   `json:"watchable"`             // Supports list+watch
+	Watched     bool   `json:"watched"`               // An informer is running for this group/resource
+	WatchSource string `json:"watchSource,omitempty"` // typed, dynamic, or fallback
+	Synced      bool   `json:"synced"`                // Initial list completed
+	Count       *int   `json:"count,omitempty"`       // Cached instances (only when synced)
+
+	PrinterColumns []PrinterColumn `json:"printerColumns,omitempty"` // CRD additionalPrinterColumns for this version
+}
+
+// groupResource keys watch state by group + plural name; all served versions
+// of a resource share the same objects, so a watch on one covers the others.
+type groupResource struct{ group, resource string }
+
+type watchState struct {
+	source string
+	synced bool
+	count  int
+}
+
+// GetAPIResourceStatuses returns all discovered API resources annotated with
+// whether Radar is watching them and, where the cache is synced, instance counts.
+func GetAPIResourceStatuses() ([]APIResourceStatus, error) {
+	discovery := GetResourceDiscovery()
+	if discovery == nil {
+		return nil, fmt.Errorf("resource discovery not initialized")
+	}
+	resources, err := discovery.GetAPIResources()
+	if err != nil {
+		return nil, err
+	}
+
+	watched := make(map[groupResource]watchState)
+
+	if rc := GetResourceCache(); rc != nil {
+		for key, enabled := range rc.GetEnabledResources() {
+			gvr, ok := typedResourceGVRs[key]
+			if !enabled || !ok {
+				continue
+			}
+			generic, err := rc.factory.ForResource(gvr)
+			if err != nil {
+				continue
+			}
+			informer := generic.Informer()
+			watched[groupResource{gvr.Group, gvr.Resource}] = watchState{
+				source: WatchSourceTyped,
+				synced: informer.HasSynced(),
+				count:  len(informer.GetIndexer().ListKeys()),
+			}
+		}
+	}
+
+	if dc := GetDynamicResourceCache(); dc != nil {
+		for _, gvr := range dc.GetWatchedResources() {
+			count, synced, ok := dc.GetCount(gvr)
+			if !ok {
+				continue
+			}
+			key := groupResource{gvr.Group, gvr.Resource}
+			if _, typed := watched[key]; typed {
+				continue
+			}
+			watched[key] = watchState{source: WatchSourceDynamic, synced: synced, count: count}
+		}
+		for _, fb := range dc.GetFallbackResources() {
+			key := groupResource{fb.Group, fb.Resource}
+			if _, ok := watched[key]; ok {
+				continue
+			}
+			watched[key] = watchState{source: WatchSourceFallback, synced: fb.FetchedAt != nil, count: fb.Items}
+		}
+	}
+
+	result := make([]APIResourceStatus, 0, len(resources))
+	for _, res := range resources {
+		status := APIResourceStatus{
+			APIResource: res,
+			Watchable:   slices.Contains(res.Verbs, "list") && slices.Contains(res.Verbs, "watch"),
+		}
+		if res.IsCRD {
+			status.PrinterColumns = GetPrinterColumns(schema.GroupVersionResource{Group: res.Group, Version: res.Version, Resource: res.Name})
+		}
+		if ws, ok := watched[groupResource{res.Group, res.Name}]; ok {
+			status.Watched = true
+			status.WatchSource = ws.source
+			status.Synced = ws.synced
+			if ws.synced {
+				count := ws.count
+				status.Count = &count
+			}
+		}
+		result = append(result, status)
+	}
+	return result, nil
+}
+
+// isTypedGVR reports whether the group/resource is served by the typed ResourceCache
+func isTypedGVR(gvr schema.GroupVersionResource) bool {
+	for _, typed := range typedResourceGVRs {
+		if typed.Group == gvr.Group && typed.Resource == gvr.Resource {
+			return true
+		}
+	}
+	return false
+}
+
+// validateWatchableGVR checks that a GVR was discovered and supports list+watch
+func validateWatchableGVR(gvr schema.GroupVersionResource) error {
+	discovery := GetResourceDiscovery()
+	if discovery == nil {
+		return fmt.Errorf("resource discovery not initialized")
+	}
+	resources, err := discovery.GetAPIResources()
+	if err != nil {
+		return err
+	}
+	for _, res := range resources {
+		if res.Group == gvr.Group && res.Version == gvr.Version && res.Name == gvr.Resource {
+			if !slices.Contains(res.Verbs, "list") || !slices.Contains(res.Verbs, "watch") {
+				return fmt.Errorf("resource %s does not support list/watch", gvr.String())
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown resource %s", gvr.String())
+}
+
+// StartWatchingGVR starts a dynamic informer for a discovered resource
+func StartWatchingGVR(gvr schema.GroupVersionResource) error {
+	if isTypedGVR(gvr) {
+		return fmt.Errorf("%s is always watched by the built-in cache", gvr.Resource)
+	}
+	if err := validateWatchableGVR(gvr); err != nil {
+		return err
+	}
+	dc := GetDynamicResourceCache()
+	if dc == nil {
+		return fmt.Errorf("dynamic resource cache not initialized")
+	}
+	if err := dc.EnsureWatching(gvr); err != nil {
+		return err
+	}
+	if dc.fallbackFor(gvr) != nil {
+		return fmt.Errorf("dynamic informer limit (%d) reached; %s is served from direct lists instead", maxDynamicInformers, gvr.Resource)
+	}
+	return nil
+}
+
+// StopWatchingGVR stops the dynamic informer for a resource and frees its cached objects
+func StopWatchingGVR(gvr schema.GroupVersionResource) error {
+	if isTypedGVR(gvr) {
+		return fmt.Errorf("%s is watched by the built-in cache and cannot be stopped", gvr.Resource)
+	}
+	dc := GetDynamicResourceCache()
+	if dc == nil {
+		return fmt.Errorf("dynamic resource cache not initialized")
+	}
+	return dc.StopWatching(gvr)
+}
@@ -0,0 +1,188 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// apiServiceGVR is the aggregation layer registration resource
+var apiServiceGVR = schema.GroupVersionResource{Group: "apiregistration.k8s.io", Version: "v1", Resource: "apiservices"}
+
+// APIService timeline reasons
+const (
+	ReasonAPIServiceUnavailable = "APIServiceUnavailable"
+	ReasonAPIServiceAvailable   = "APIServiceAvailable"
+)
+
+// APIServiceStatus describes the availability of an aggregated API (one backed by a Service)
+type APIServiceStatus struct {
+	Name             string    `json:"name"`    // e.g. v1beta1.metrics.k8s.io
+	Group            string    `json:"group"`   // e.g. metrics.k8s.io
+	Version          string    `json:"version"` // e.g. v1beta1
+	Service          string    `json:"service"` // Backing Service as namespace/name
+	Available        bool      `json:"available"`
+	Reason           string    `json:"reason,omitempty"`
+	Message          string    `json:"message,omitempty"`
+	LastTransitionAt time.Time `json:"lastTransitionAt,omitempty"`
+	UID              string    `json:"-"`
+}
+
+// parseAPIService extracts availability from an APIService. Local APIServices
+// (built-in groups served by kube-apiserver itself) return ok=false.
+func parseAPIService(obj any) (*APIServiceStatus, bool) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		if tombstone, isTombstone := obj.(cache.DeletedFinalStateUnknown); isTombstone {
+			u, ok = tombstone.Obj.(*unstructured.Unstructured)
+		}
+		if !ok {
+			return nil, false
+		}
+	}
+
+	svcName, _, _ := unstructured.NestedString(u.Object, "spec", "service", "name")
+	if svcName == "" {
+		return nil, false
+	}
+	svcNamespace, _, _ := unstructured.NestedString(u.Object, "spec", "service", "namespace")
+
+	status := &APIServiceStatus{
+		Name:    u.GetName(),
+		UID:     string(u.GetUID()),
+		Service: svcNamespace + "/" + svcName,
+	}
+	status.Group, _, _ = unstructured.NestedString(u.Object, "spec", "group")
+	status.Version, _, _ = unstructured.NestedString(u.Object, "spec", "version")
+
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, c := range conditions {
+		m, ok := c.(map[string]any)
+		if !ok || m["type"] != "Available" {
+			continue
+		}
+		status.Available = m["status"] == "True"
+		status.Reason, _ = m["reason"].(string)
+		status.Message, _ = m["message"].(string)
+		if ts, ok := m["lastTransitionTime"].(string); ok {
+			status.LastTransitionAt, _ = time.Parse(time.RFC3339, ts)
+		}
+	}
+	return status, true
+}
+
+// WatchAPIServices watches APIService objects and records a timeline event
+// whenever an aggregated API (metrics.k8s.io, custom metrics adapters, ...)
+// changes availability. An unavailable aggregated API breaks discovery for
+// its group, kubectl top, and HPAs, usually without any other visible symptom.
+func (d *DynamicResourceCache) WatchAPIServices() {
+	if d == nil {
+		return
+	}
+	if err := d.probeAccess(apiServiceGVR); err != nil {
+		log.Printf("[APIService] No access to APIServices, availability monitoring disabled: %v", err)
+		return
+	}
+
+	informer := dynamicinformer.NewFilteredDynamicInformer(d.client, apiServiceGVR, metav1.NamespaceAll, 0, cache.Indexers{}, nil).Informer()
+
+	setRelistHandler(informer, "APIService")
+	var synced atomic.Bool
+	registration, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			svc, ok := parseAPIService(obj)
+			// Pre-existing outages are surfaced on the dashboard; only record new ones
+			if !ok || svc.Available || !synced.Load() {
+				return
+			}
+			if u, ok := obj.(*unstructured.Unstructured); ok && isRelistReplay("APIService", u, nil, "add") {
+				return
+			}
+			recordAPIServiceEvent(svc)
+		},
+		UpdateFunc: func(oldObj, newObj any) {
+			oldSvc, ok1 := parseAPIService(oldObj)
+			newSvc, ok2 := parseAPIService(newObj)
+			if !ok2 {
+				return
+			}
+			// A local APIService switching to a Service-backed one counts as a transition
+			if ok1 && oldSvc.Available == newSvc.Available {
+				return
+			}
+			if !ok1 && newSvc.Available {
+				return
+			}
+			recordAPIServiceEvent(newSvc)
+		},
+	})
+	if err != nil {
+		log.Printf("[APIService] Failed to register handler: %v", err)
+		return
+	}
+
+	d.mu.Lock()
+	d.apiServices = informer
+	d.mu.Unlock()
+
+	go informer.Run(d.stopCh)
+	go func() {
+		if cache.WaitForCacheSync(d.stopCh, registration.HasSynced) {
+			synced.Store(true)
+			log.Println("[APIService] Watching aggregated APIs for availability changes")
+		}
+	}()
+}
+
+// GetUnavailableAPIServices returns aggregated APIs whose Available condition
+// is not True, sorted by name. Returns nil if APIServices aren't being watched.
+func (d *DynamicResourceCache) GetUnavailableAPIServices() []APIServiceStatus {
+	if d == nil {
+		return nil
+	}
+	d.mu.RLock()
+	informer := d.apiServices
+	d.mu.RUnlock()
+	if informer == nil || !informer.HasSynced() {
+		return nil
+	}
+
+	var result []APIServiceStatus
+	for _, obj := range informer.GetStore().List() {
+		if svc, ok := parseAPIService(obj); ok && !svc.Available {
+			result = append(result, *svc)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+func recordAPIServiceEvent(svc *APIServiceStatus) {
+	eventType, reason, health := timeline.EventTypeWarning, ReasonAPIServiceUnavailable, timeline.HealthUnhealthy
+	message := fmt.Sprintf("Aggregated API %s (service %s) is unavailable", svc.Name, svc.Service)
+	if svc.Available {
+		eventType, reason, health = timeline.EventTypeNormal, ReasonAPIServiceAvailable, timeline.HealthHealthy
+		message = fmt.Sprintf("Aggregated API %s (service %s) is available again", svc.Name, svc.Service)
+	} else if svc.Reason != "" {
+		message += fmt.Sprintf(": %s", svc.Reason)
+		if svc.Message != "" {
+			message += fmt.Sprintf(" (%s)", svc.Message)
+		}
+	}
+
+	event := timeline.NewLifecycleEvent("APIService", "", svc.Name, svc.UID, eventType, reason, message, health)
+	if err := timeline.RecordEventsWithBroadcast(context.Background(), []timeline.TimelineEvent{event}); err != nil {
+		log.Printf("[APIService] Failed to record %s for %s: %v", reason, svc.Name, err)
+	}
+}
@@ -0,0 +1,43 @@
+package k8s
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseAPIService(t *testing.T) {
+	local := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "v1.apps"},
+		"spec":     map[string]any{"group": "apps", "version": "v1"},
+	}}
+	if _, ok := parseAPIService(local); ok {
+		t.Error("local APIService should be ignored")
+	}
+
+	aggregated := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "v1beta1.metrics.k8s.io"},
+		"spec": map[string]any{
+			"group":   "metrics.k8s.io",
+			"version": "v1beta1",
+			"service": map[string]any{"namespace": "kube-system", "name": "metrics-server"},
+		},
+		"status": map[string]any{"conditions": []any{map[string]any{
+			"type":               "Available",
+			"status":             "False",
+			"reason":             "FailedDiscoveryCheck",
+			"message":            "failing or missing response",
+			"lastTransitionTime": "2026-01-02T03:04:05Z",
+		}}},
+	}}
+	svc, ok := parseAPIService(aggregated)
+	if !ok {
+		t.Fatal("expected aggregated APIService to parse")
+	}
+	if svc.Available || svc.Reason != "FailedDiscoveryCheck" || svc.Service != "kube-system/metrics-server" || svc.Group != "metrics.k8s.io" {
+		t.Errorf("unexpected status: %+v", svc)
+	}
+	if svc.LastTransitionAt.IsZero() {
+		t.Error("expected lastTransitionTime to be parsed")
+	}
+}
@@ -0,0 +1,306 @@
+package k8s
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// WorkflowGVR is the Argo Workflows Workflow resource
+var WorkflowGVR = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "workflows"}
+
+const (
+	workflowLabel          = "workflows.argoproj.io/workflow"
+	workflowCompletedLabel = "workflows.argoproj.io/completed"
+	workflowPhaseLabel     = "workflows.argoproj.io/phase"
+	workflowNodeNameAnnot  = "workflows.argoproj.io/node-name"
+	workflowPodNameFormat  = "workflows.argoproj.io/pod-name-format"
+	// Argo truncates the workflow-template prefix so pod names stay under the k8s name limit
+	workflowPodNamePrefixMax = 253 - 10 - 1
+)
+
+// WorkflowNode is one step of a workflow, taken from status.nodes
+type WorkflowNode struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`        // Full node name (wf.steps[0].build)
+	DisplayName     string   `json:"displayName"` // Short name shown in the graph
+	Type            string   `json:"type"`        // Pod, DAG, Steps, StepGroup, Retry, Skipped, Suspend, ...
+	TemplateName    string   `json:"templateName,omitempty"`
+	Phase           string   `json:"phase"` // Pending, Running, Succeeded, Failed, Error, Skipped, Omitted
+	Message         string   `json:"message,omitempty"`
+	StartedAt       string   `json:"startedAt,omitempty"`
+	FinishedAt      string   `json:"finishedAt,omitempty"`
+	DurationSeconds int64    `json:"durationSeconds,omitempty"` // Elapsed so far while running
+	Progress        string   `json:"progress,omitempty"`
+	PodName         string   `json:"podName,omitempty"` // Only for Pod nodes
+	BoundaryID      string   `json:"boundaryId,omitempty"`
+	Children        []string `json:"children,omitempty"`
+}
+
+// WorkflowEdge connects a node to one of its children
+type WorkflowEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// WorkflowDAG is the step graph of a Workflow
+type WorkflowDAG struct {
+	Namespace       string         `json:"namespace"`
+	Name            string         `json:"name"`
+	Phase           string         `json:"phase"`
+	Message         string         `json:"message,omitempty"`
+	StartedAt       string         `json:"startedAt,omitempty"`
+	FinishedAt      string         `json:"finishedAt,omitempty"`
+	DurationSeconds int64          `json:"durationSeconds,omitempty"`
+	Progress        string         `json:"progress,omitempty"`
+	Shutdown        string         `json:"shutdown,omitempty"` // spec.shutdown (Terminate or Stop) if requested
+	Nodes           []WorkflowNode `json:"nodes"`
+	Edges           []WorkflowEdge `json:"edges"`
+}
+
+// ParseWorkflowDAG builds the step graph from a Workflow's status.nodes
+func ParseWorkflowDAG(u *unstructured.Unstructured, now time.Time) *WorkflowDAG {
+	dag := &WorkflowDAG{
+		Namespace: u.GetNamespace(),
+		Name:      u.GetName(),
+		Nodes:     []WorkflowNode{},
+		Edges:     []WorkflowEdge{},
+	}
+	dag.Phase, _, _ = unstructured.NestedString(u.Object, "status", "phase")
+	dag.Message, _, _ = unstructured.NestedString(u.Object, "status", "message")
+	dag.StartedAt, _, _ = unstructured.NestedString(u.Object, "status", "startedAt")
+	dag.FinishedAt, _, _ = unstructured.NestedString(u.Object, "status", "finishedAt")
+	dag.Progress, _, _ = unstructured.NestedString(u.Object, "status", "progress")
+	dag.Shutdown, _, _ = unstructured.NestedString(u.Object, "spec", "shutdown")
+	dag.DurationSeconds = workflowDuration(dag.StartedAt, dag.FinishedAt, now)
+	if dag.Phase == "" {
+		dag.Phase = "Pending"
+	}
+
+	nodes, _, _ := unstructured.NestedMap(u.Object, "status", "nodes")
+	for id, raw := range nodes {
+		m, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		node := WorkflowNode{
+			ID:           id,
+			Name:         stringField(m, "name"),
+			DisplayName:  stringField(m, "displayName"),
+			Type:         stringField(m, "type"),
+			TemplateName: workflowNodeTemplate(m),
+			Phase:        stringField(m, "phase"),
+			Message:      stringField(m, "message"),
+			StartedAt:    stringField(m, "startedAt"),
+			FinishedAt:   stringField(m, "finishedAt"),
+			Progress:     stringField(m, "progress"),
+			BoundaryID:   stringField(m, "boundaryID"),
+		}
+		if node.DisplayName == "" {
+			node.DisplayName = node.Name
+		}
+		node.DurationSeconds = workflowDuration(node.StartedAt, node.FinishedAt, now)
+		if node.Type == "Pod" {
+			node.PodName = WorkflowPodName(u, node)
+		}
+		if children, ok := m["children"].([]any); ok {
+			for _, c := range children {
+				if child, ok := c.(string); ok {
+					node.Children = append(node.Children, child)
+					dag.Edges = append(dag.Edges, WorkflowEdge{From: id, To: child})
+				}
+			}
+		}
+		dag.Nodes = append(dag.Nodes, node)
+	}
+
+	sort.Slice(dag.Nodes, func(i, j int) bool {
+		if dag.Nodes[i].StartedAt != dag.Nodes[j].StartedAt {
+			return dag.Nodes[i].StartedAt < dag.Nodes[j].StartedAt
+		}
+		return dag.Nodes[i].Name < dag.Nodes[j].Name
+	})
+	sort.Slice(dag.Edges, func(i, j int) bool {
+		if dag.Edges[i].From != dag.Edges[j].From {
+			return dag.Edges[i].From < dag.Edges[j].From
+		}
+		return dag.Edges[i].To < dag.Edges[j].To
+	})
+	return dag
+}
+
+// workflowNodeTemplate returns the template a node ran, following templateRef for WorkflowTemplates
+func workflowNodeTemplate(m map[string]any) string {
+	if name := stringField(m, "templateName"); name != "" {
+		return name
+	}
+	name, _, _ := unstructured.NestedString(m, "templateRef", "template")
+	return name
+}
+
+// workflowDuration returns elapsed seconds, counting up to now for nodes that haven't finished
+func workflowDuration(startedAt, finishedAt string, now time.Time) int64 {
+	start, err := time.Parse(time.RFC3339, startedAt)
+	if err != nil {
+		return 0
+	}
+	end := now
+	if finishedAt != "" {
+		if t, err := time.Parse(time.RFC3339, finishedAt); err == nil {
+			end = t
+		}
+	}
+	if end.Before(start) {
+		return 0
+	}
+	return int64(end.Sub(start).Seconds())
+}
+
+// WorkflowPodName mirrors Argo's pod naming for a Pod node. Workflows created with
+// pod-name-format v1 use the node ID; v2 (the default since Argo 3.4) uses
+// <workflow>-<template>-<fnv32a(node name)>.
+func WorkflowPodName(u *unstructured.Unstructured, node WorkflowNode) string {
+	if u.GetAnnotations()[workflowPodNameFormat] == "v1" {
+		return node.ID
+	}
+	workflowName := u.GetName()
+	if node.Name == workflowName {
+		return workflowName
+	}
+	prefix := workflowName
+	if !strings.Contains(node.Name, ".inline") {
+		prefix = workflowName + "-" + node.TemplateName
+	}
+	if len(prefix) > workflowPodNamePrefixMax {
+		prefix = prefix[:workflowPodNamePrefixMax]
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(node.Name))
+	return fmt.Sprintf("%s-%d", prefix, h.Sum32())
+}
+
+// FindWorkflowStepPod resolves the pod that ran a workflow step. It tries the
+// computed pod name first and falls back to matching the node-name annotation,
+// which covers pod naming schemes that differ from WorkflowPodName.
+func FindWorkflowStepPod(u *unstructured.Unstructured, nodeID string) (*corev1.Pod, error) {
+	dag := ParseWorkflowDAG(u, time.Now())
+	var node *WorkflowNode
+	for i := range dag.Nodes {
+		if dag.Nodes[i].ID == nodeID {
+			node = &dag.Nodes[i]
+			break
+		}
+	}
+	if node == nil {
+		return nil, fmt.Errorf("node %q not found in workflow %s/%s", nodeID, u.GetNamespace(), u.GetName())
+	}
+	if node.Type != "Pod" {
+		return nil, fmt.Errorf("node %q is a %s node and has no pod", nodeID, node.Type)
+	}
+
+	cache := GetResourceCache()
+	if cache == nil || cache.Pods() == nil {
+		return nil, fmt.Errorf("resource cache not available")
+	}
+	podLister := cache.Pods().Pods(u.GetNamespace())
+	if pod, err := podLister.Get(node.PodName); err == nil {
+		return pod, nil
+	}
+
+	pods, err := podLister.List(labels.SelectorFromSet(labels.Set{workflowLabel: u.GetName()}))
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods {
+		if pod.Annotations[workflowNodeNameAnnot] == node.Name || pod.Name == nodeID {
+			return pod, nil
+		}
+	}
+	return nil, fmt.Errorf("pod for step %q not found (it may have been garbage collected)", node.DisplayName)
+}
+
+// PrepareWorkflowRetry resets a failed Workflow so the controller re-runs its
+// failed steps, like `argo retry`. Succeeded steps are kept; failed Pod nodes are
+// removed and their pod names returned so the caller can delete them, and failed
+// container nodes (DAG, Steps, ...) are set back to Running.
+func PrepareWorkflowRetry(u *unstructured.Unstructured) (podsToDelete []string, err error) {
+	phase, _, _ := unstructured.NestedString(u.Object, "status", "phase")
+	if phase != "Failed" && phase != "Error" {
+		return nil, fmt.Errorf("workflow must be Failed or Error to retry (current phase: %s)", phase)
+	}
+
+	nodes, _, _ := unstructured.NestedMap(u.Object, "status", "nodes")
+	for id, raw := range nodes {
+		m, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		nodePhase := stringField(m, "phase")
+		if nodePhase != "Failed" && nodePhase != "Error" {
+			continue
+		}
+		if stringField(m, "type") == "Pod" {
+			node := WorkflowNode{ID: id, Name: stringField(m, "name"), TemplateName: workflowNodeTemplate(m)}
+			podsToDelete = append(podsToDelete, WorkflowPodName(u, node))
+			delete(nodes, id)
+			continue
+		}
+		m["phase"] = "Running"
+		delete(m, "finishedAt")
+		delete(m, "message")
+	}
+
+	// Drop references to removed nodes so the controller re-creates them
+	for _, raw := range nodes {
+		m, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		children, ok := m["children"].([]any)
+		if !ok {
+			continue
+		}
+		kept := children[:0]
+		for _, c := range children {
+			if child, ok := c.(string); ok {
+				if _, exists := nodes[child]; !exists {
+					continue
+				}
+			}
+			kept = append(kept, c)
+		}
+		if len(kept) == 0 {
+			delete(m, "children")
+		} else {
+			m["children"] = kept
+		}
+	}
+
+	if err := unstructured.SetNestedMap(u.Object, nodes, "status", "nodes"); err != nil {
+		return nil, err
+	}
+	if err := unstructured.SetNestedField(u.Object, "Running", "status", "phase"); err != nil {
+		return nil, err
+	}
+	unstructured.RemoveNestedField(u.Object, "status", "finishedAt")
+	unstructured.RemoveNestedField(u.Object, "status", "message")
+	unstructured.RemoveNestedField(u.Object, "spec", "shutdown")
+
+	workflowLabels := u.GetLabels()
+	if workflowLabels == nil {
+		workflowLabels = map[string]string{}
+	}
+	workflowLabels[workflowCompletedLabel] = "false"
+	workflowLabels[workflowPhaseLabel] = "Running"
+	u.SetLabels(workflowLabels)
+
+	sort.Strings(podsToDelete)
+	return podsToDelete, nil
+}
@@ -0,0 +1,108 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func testWorkflow(phase string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Workflow",
+		"metadata":   map[string]any{"name": "ci", "namespace": "argo", "labels": map[string]any{"workflows.argoproj.io/completed": "true"}},
+		"spec":       map[string]any{},
+		"status": map[string]any{
+			"phase":      phase,
+			"startedAt":  "2026-01-01T10:00:00Z",
+			"finishedAt": "2026-01-01T10:05:00Z",
+			"message":    "child 'ci-2' failed",
+			"nodes": map[string]any{
+				"ci": map[string]any{
+					"id": "ci", "name": "ci", "displayName": "ci", "type": "DAG", "templateName": "main", "phase": phase,
+					"startedAt": "2026-01-01T10:00:00Z", "finishedAt": "2026-01-01T10:05:00Z",
+					"children": []any{"ci-1", "ci-2"},
+				},
+				"ci-1": map[string]any{
+					"id": "ci-1", "name": "ci.build", "displayName": "build", "type": "Pod", "templateName": "build", "phase": "Succeeded",
+					"startedAt": "2026-01-01T10:00:00Z", "finishedAt": "2026-01-01T10:02:00Z",
+				},
+				"ci-2": map[string]any{
+					"id": "ci-2", "name": "ci.test", "displayName": "test", "type": "Pod", "phase": "Failed",
+					"templateRef": map[string]any{"name": "shared", "template": "unit-test"},
+					"startedAt":   "2026-01-01T10:02:00Z", "finishedAt": "2026-01-01T10:05:00Z",
+				},
+			},
+		},
+	}}
+}
+
+func TestParseWorkflowDAG(t *testing.T) {
+	dag := ParseWorkflowDAG(testWorkflow("Failed"), time.Now())
+	if dag.Phase != "Failed" || dag.DurationSeconds != 300 {
+		t.Errorf("unexpected workflow summary: phase=%s duration=%d", dag.Phase, dag.DurationSeconds)
+	}
+	if len(dag.Nodes) != 3 || len(dag.Edges) != 2 {
+		t.Fatalf("expected 3 nodes and 2 edges, got %d and %d", len(dag.Nodes), len(dag.Edges))
+	}
+	byID := map[string]WorkflowNode{}
+	for _, n := range dag.Nodes {
+		byID[n.ID] = n
+	}
+	if build := byID["ci-1"]; build.DurationSeconds != 120 || build.PodName == "" || build.PodName[:9] != "ci-build-" {
+		t.Errorf("unexpected build node: %+v", build)
+	}
+	if test := byID["ci-2"]; test.TemplateName != "unit-test" {
+		t.Errorf("templateRef template should be used, got %+v", test)
+	}
+	if root := byID["ci"]; root.PodName != "" {
+		t.Errorf("DAG node should not have a pod name: %+v", root)
+	}
+}
+
+func TestWorkflowPodName(t *testing.T) {
+	wf := testWorkflow("Running")
+	node := WorkflowNode{ID: "ci-2", Name: "ci.test", TemplateName: "unit-test"}
+	// fnv32a("ci.test")
+	if got := WorkflowPodName(wf, node); got != "ci-unit-test-2768726717" {
+		t.Errorf("unexpected v2 pod name: %s", got)
+	}
+	wf.SetAnnotations(map[string]string{"workflows.argoproj.io/pod-name-format": "v1"})
+	if got := WorkflowPodName(wf, node); got != "ci-2" {
+		t.Errorf("v1 pod name should be the node ID, got %s", got)
+	}
+}
+
+func TestPrepareWorkflowRetry(t *testing.T) {
+	if _, err := PrepareWorkflowRetry(testWorkflow("Succeeded")); err == nil {
+		t.Error("expected error retrying a succeeded workflow")
+	}
+
+	wf := testWorkflow("Failed")
+	pods, err := PrepareWorkflowRetry(wf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods) != 1 || pods[0] != "ci-unit-test-2768726717" {
+		t.Errorf("expected the failed step pod to be deleted, got %v", pods)
+	}
+
+	phase, _, _ := unstructured.NestedString(wf.Object, "status", "phase")
+	_, hasFinished, _ := unstructured.NestedString(wf.Object, "status", "finishedAt")
+	if phase != "Running" || hasFinished || wf.GetLabels()["workflows.argoproj.io/completed"] != "false" {
+		t.Errorf("workflow not reset: phase=%s finished=%v labels=%v", phase, hasFinished, wf.GetLabels())
+	}
+
+	nodes, _, _ := unstructured.NestedMap(wf.Object, "status", "nodes")
+	if _, ok := nodes["ci-2"]; ok {
+		t.Error("failed pod node should be removed")
+	}
+	if _, ok := nodes["ci-1"]; !ok {
+		t.Error("succeeded node should be kept")
+	}
+	root := nodes["ci"].(map[string]any)
+	if root["phase"] != "Running" || len(root["children"].([]any)) != 1 {
+		t.Errorf("root DAG node not reset: %+v", root)
+	}
+}
@@ -0,0 +1,179 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// maxBulkTargets caps how many resources a single bulk operation may touch
+const maxBulkTargets = 500
+
+// MetadataField selects which metadata map a bulk edit operates on
+type MetadataField string
+
+const (
+	MetadataLabels      MetadataField = "labels"
+	MetadataAnnotations MetadataField = "annotations"
+)
+
+// BulkTarget identifies a single resource in a bulk operation
+type BulkTarget struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// BulkMetadataOptions describes a bulk label/annotation edit. Targets are either
+// listed explicitly in Items, or selected by Kind (+ optional Namespace and
+// label Selector). Set adds or overwrites keys; Remove deletes keys.
+type BulkMetadataOptions struct {
+	Field     MetadataField     `json:"field"`
+	Set       map[string]string `json:"set,omitempty"`
+	Remove    []string          `json:"remove,omitempty"`
+	Items     []BulkTarget      `json:"items,omitempty"`
+	Kind      string            `json:"kind,omitempty"`
+	Namespace string            `json:"namespace,omitempty"`
+	Selector  string            `json:"selector,omitempty"`
+}
+
+// BulkItemResult is the per-resource outcome of a bulk operation
+type BulkItemResult struct {
+	BulkTarget
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BulkResult summarizes a bulk operation
+type BulkResult struct {
+	Total     int              `json:"total"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
+}
+
+// BulkEditMetadata adds and/or removes labels or annotations across a set of
+// resources, one merge patch per resource. Individual failures are reported in
+// the result rather than aborting the whole operation.
+func BulkEditMetadata(ctx context.Context, opts BulkMetadataOptions) (*BulkResult, error) {
+	if opts.Field != MetadataLabels && opts.Field != MetadataAnnotations {
+		return nil, fmt.Errorf("invalid field %q: must be labels or annotations", opts.Field)
+	}
+	if len(opts.Set) == 0 && len(opts.Remove) == 0 {
+		return nil, fmt.Errorf("nothing to change: set or remove is required")
+	}
+
+	patch, err := buildMetadataPatch(opts.Field, opts.Set, opts.Remove)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := resolveBulkTargets(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BulkResult{
+		Total:   len(targets),
+		Results: make([]BulkItemResult, 0, len(targets)),
+	}
+	for _, t := range targets {
+		item := BulkItemResult{BulkTarget: t, Success: true}
+		if _, err := PatchResource(ctx, t.Kind, t.Namespace, t.Name, types.MergePatchType, patch); err != nil {
+			item.Success = false
+			item.Error = err.Error()
+			result.Failed++
+		} else {
+			result.Succeeded++
+		}
+		result.Results = append(result.Results, item)
+	}
+
+	return result, nil
+}
+
+// buildMetadataPatch builds a JSON merge patch that sets and removes keys on
+// metadata.labels or metadata.annotations. A null value deletes the key.
+func buildMetadataPatch(field MetadataField, set map[string]string, remove []string) ([]byte, error) {
+	values := make(map[string]any, len(set)+len(remove))
+	for _, k := range remove {
+		if k == "" {
+			return nil, fmt.Errorf("empty key in remove list")
+		}
+		values[k] = nil
+	}
+	for k, v := range set {
+		if k == "" {
+			return nil, fmt.Errorf("empty key in set")
+		}
+		if _, removing := values[k]; removing {
+			return nil, fmt.Errorf("key %q is both set and removed", k)
+		}
+		values[k] = v
+	}
+
+	return json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			string(field): values,
+		},
+	})
+}
+
+// resolveBulkTargets expands the options into a concrete list of resources
+func resolveBulkTargets(ctx context.Context, opts BulkMetadataOptions) ([]BulkTarget, error) {
+	if len(opts.Items) > 0 {
+		if len(opts.Items) > maxBulkTargets {
+			return nil, fmt.Errorf("too many items: %d (max %d)", len(opts.Items), maxBulkTargets)
+		}
+		for _, t := range opts.Items {
+			if t.Kind == "" || t.Name == "" {
+				return nil, fmt.Errorf("each item requires kind and name")
+			}
+		}
+		return opts.Items, nil
+	}
+
+	if opts.Kind == "" {
+		return nil, fmt.Errorf("either items or kind is required")
+	}
+
+	discovery := GetResourceDiscovery()
+	if discovery == nil {
+		return nil, fmt.Errorf("resource discovery not initialized")
+	}
+
+	dynamicClient := GetDynamicClient()
+	if dynamicClient == nil {
+		return nil, fmt.Errorf("dynamic client not initialized")
+	}
+
+	gvr, ok := discovery.GetGVR(opts.Kind)
+	if !ok {
+		return nil, fmt.Errorf("unknown resource kind: %s", opts.Kind)
+	}
+
+	var client dynamic.ResourceInterface = dynamicClient.Resource(gvr)
+	if opts.Namespace != "" {
+		client = dynamicClient.Resource(gvr).Namespace(opts.Namespace)
+	}
+
+	list, err := client.List(ctx, metav1.ListOptions{LabelSelector: opts.Selector, Limit: maxBulkTargets + 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", opts.Kind, err)
+	}
+
+	targets := make([]BulkTarget, 0, len(list.Items))
+	for _, item := range list.Items {
+		targets = append(targets, BulkTarget{Kind: opts.Kind, Namespace: item.GetNamespace(), Name: item.GetName()})
+	}
+
+	if len(targets) > maxBulkTargets || list.GetContinue() != "" {
+		return nil, fmt.Errorf("selector matches more than %d resources; narrow it down", maxBulkTargets)
+	}
+
+	return targets, nil
+}
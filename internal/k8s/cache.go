@@ -6,6 +6,7 @@ import (
 	"log"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -29,8 +30,9 @@ import (
 	"github.com/skyhook-io/radar/internal/timeline"
 )
 
-// DebugEvents enables verbose event debugging when true (set via --debug-events flag)
-var DebugEvents bool
+// DebugEvents enables verbose event debugging when true (set via --debug-events
+// flag, or at runtime through PUT /api/config)
+var DebugEvents atomic.Bool
 
 // initialSyncComplete is set to true after the initial cache sync completes.
 // During initial sync, "add" events are skipped since they represent existing
@@ -87,6 +89,11 @@ func dropManagedFields(obj any) (any, error) {
 			Count:          event.Count,
 			FirstTimestamp: event.FirstTimestamp,
 			LastTimestamp:  event.LastTimestamp,
+			// Reporting component identifies controllers like cluster-autoscaler;
+			// the host and instance attribute kubelet events to their node
+			Source:              corev1.EventSource{Component: event.Source.Component, Host: event.Source.Host},
+			ReportingController: event.ReportingController,
+			ReportingInstance:   event.ReportingInstance,
 		}, nil
 	}
 
@@ -119,7 +126,9 @@ func InitResourceCache() error {
 
 		// Check RBAC permissions for all resource types before creating informers
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		endPerms := TraceStartupPhase("permissions")
 		permResult := CheckResourcePermissions(ctx)
+		endPerms(nil)
 		cancel()
 		perms := permResult.Perms
 
@@ -195,12 +204,14 @@ func InitResourceCache() error {
 			}
 			enabledCount++
 			inf := s.setup()
+			setRelistHandler(inf, s.kind)
 			if s.isEvent {
 				handlerErrors = append(handlerErrors, addK8sEventHandlers(inf, changes))
 			} else {
 				handlerErrors = append(handlerErrors, addChangeHandlers(inf, s.kind, changes))
 			}
 			syncFuncs = append(syncFuncs, inf.HasSynced)
+			traceInformerSync(inf, s.key, stopCh)
 		}
 
 		for _, err := range handlerErrors {
@@ -270,6 +281,8 @@ func ResetResourceCache() {
 	}
 	cacheOnce = sync.Once{}
 	initialSyncComplete = false
+	relists.reset()
+	secretValueDiffs.reset()
 }
 
 // addChangeHandlers registers event handlers for change notifications
@@ -300,7 +313,7 @@ func addK8sEventHandlers(inf cache.SharedIndexInformer, ch chan<- ResourceChange
 		AddFunc: func(obj any) {
 			// Still send to the change channel for SSE broadcasting
 			meta, ok := obj.(metav1.Object)
-			if !ok {
+			if !ok || isRelistReplay("Event", meta, nil, "add") {
 				return
 			}
 			change := ResourceChange{
@@ -316,7 +329,7 @@ func addK8sEventHandlers(inf cache.SharedIndexInformer, ch chan<- ResourceChange
 				// Channel full, drop event
 				timeline.RecordDrop("Event", meta.GetNamespace(), meta.GetName(),
 					timeline.DropReasonChannelFull, "add")
-				if DebugEvents {
+				if DebugEvents.Load() {
 					log.Printf("[DEBUG] K8s Event channel full, dropped: Event/%s/%s", meta.GetNamespace(), meta.GetName())
 				}
 			}
@@ -327,7 +340,7 @@ func addK8sEventHandlers(inf cache.SharedIndexInformer, ch chan<- ResourceChange
 		UpdateFunc: func(oldObj, newObj any) {
 			// K8s Events update when count changes - record to timeline
 			meta, ok := newObj.(metav1.Object)
-			if !ok {
+			if !ok || isRelistReplay("Event", meta, oldObj, "update") {
 				return
 			}
 			change := ResourceChange{
@@ -343,7 +356,7 @@ func addK8sEventHandlers(inf cache.SharedIndexInformer, ch chan<- ResourceChange
 				// Channel full, drop event
 				timeline.RecordDrop("Event", meta.GetNamespace(), meta.GetName(),
 					timeline.DropReasonChannelFull, "update")
-				if DebugEvents {
+				if DebugEvents.Load() {
 					log.Printf("[DEBUG] K8s Event channel full, dropped: Event/%s/%s op=update", meta.GetNamespace(), meta.GetName())
 				}
 			}
@@ -376,7 +389,7 @@ func addK8sEventHandlers(inf cache.SharedIndexInformer, ch chan<- ResourceChange
 				// Channel full, drop event
 				timeline.RecordDrop("Event", meta.GetNamespace(), meta.GetName(),
 					timeline.DropReasonChannelFull, "delete")
-				if DebugEvents {
+				if DebugEvents.Load() {
 					log.Printf("[DEBUG] K8s Event channel full, dropped: Event/%s/%s op=delete", meta.GetNamespace(), meta.GetName())
 				}
 			}
@@ -397,40 +410,21 @@ func recordK8sEventToTimeline(obj any) {
 		return
 	}
 
+	// Node-level events may explain a restart storm, even without a timeline store
+	restartStorms.observeNodeEvent(event)
+
 	store := timeline.GetStore()
 	if store == nil {
 		return
 	}
 
 	// Track K8s Event recording in metrics when debug mode is enabled
-	if DebugEvents {
+	if DebugEvents.Load() {
 		timeline.IncrementReceived("K8sEvent:" + event.InvolvedObject.Kind)
 	}
 
-	// Lookup owner reference for the involved object
-	var owner *timeline.OwnerInfo
-	cache := GetResourceCache()
-	if cache != nil {
-		if event.InvolvedObject.Kind == "Pod" && cache.Pods() != nil {
-			if pod, err := cache.Pods().Pods(event.Namespace).Get(event.InvolvedObject.Name); err == nil && pod != nil {
-				for _, ref := range pod.OwnerReferences {
-					if ref.Controller != nil && *ref.Controller {
-						owner = &timeline.OwnerInfo{Kind: ref.Kind, Name: ref.Name}
-						break
-					}
-				}
-			}
-		} else if event.InvolvedObject.Kind == "ReplicaSet" && cache.ReplicaSets() != nil {
-			if rs, err := cache.ReplicaSets().ReplicaSets(event.Namespace).Get(event.InvolvedObject.Name); err == nil && rs != nil {
-				for _, ref := range rs.OwnerReferences {
-					if ref.Controller != nil && *ref.Controller {
-						owner = &timeline.OwnerInfo{Kind: ref.Kind, Name: ref.Name}
-						break
-					}
-				}
-			}
-		}
-	}
+	// Lookup owner of the involved object, for any typed or watched dynamic kind
+	owner := GetResourceCache().involvedObjectOwner(event)
 
 	// Create timeline event using the converter
 	timelineEvent := timeline.NewK8sEventTimelineEvent(event, owner)
@@ -439,11 +433,25 @@ func recordK8sEventToTimeline(obj any) {
 	ctx := context.Background()
 	if err := timeline.RecordEventWithBroadcast(ctx, timelineEvent); err != nil {
 		log.Printf("Warning: failed to record K8s event to timeline store: %v", err)
-	} else if DebugEvents {
+	} else if DebugEvents.Load() {
 		timeline.IncrementRecorded("K8sEvent:" + event.InvolvedObject.Kind)
 	}
 }
 
+// extraNoisyKinds are kinds filtered like the built-in noisy ones, set at runtime
+var extraNoisyKinds atomic.Pointer[map[string]bool]
+
+// SetExtraNoisyKinds replaces the kinds whose updates are kept out of the
+// timeline on top of the built-in ones (Lease, Endpoints, ...). Takes effect
+// for the next update without restarting informers.
+func SetExtraNoisyKinds(kinds []string) {
+	set := make(map[string]bool, len(kinds))
+	for _, k := range kinds {
+		set[k] = true
+	}
+	extraNoisyKinds.Store(&set)
+}
+
 // isNoisyResource returns true if this resource generates constant updates that aren't interesting
 // This prevents the history buffer from being flooded with lease renewals, heartbeats, etc.
 func isNoisyResource(kind, name, op string) bool {
@@ -457,6 +465,9 @@ func isNoisyResource(kind, name, op string) bool {
 	case "Lease", "Endpoints", "EndpointSlice", "Event":
 		return true
 	}
+	if extra := extraNoisyKinds.Load(); extra != nil && (*extra)[kind] {
+		return true
+	}
 
 	// Noisy ConfigMaps (leader election, heartbeats, status tracking)
 	if kind == "ConfigMap" {
@@ -501,8 +512,12 @@ func enqueueChange(ch chan<- ResourceChange, kind string, obj any, oldObj any, o
 	// Track event received
 	timeline.IncrementReceived(kind)
 
+	if isRelistReplay(kind, meta, oldObj, op) {
+		return
+	}
+
 	// Debug: log adds for core workload resources
-	if DebugEvents && op == "add" && (kind == "Pod" || kind == "Deployment" || kind == "Service") {
+	if DebugEvents.Load() && op == "add" && (kind == "Pod" || kind == "Deployment" || kind == "Service") {
 		log.Printf("[DEBUG] enqueueChange: %s add %s/%s", kind, meta.GetNamespace(), meta.GetName())
 	}
 
@@ -511,7 +526,7 @@ func enqueueChange(ch chan<- ResourceChange, kind string, obj any, oldObj any, o
 	if skipHistory {
 		timeline.RecordDrop(kind, meta.GetNamespace(), meta.GetName(),
 			timeline.DropReasonNoisyFilter, op)
-		if DebugEvents {
+		if DebugEvents.Load() {
 			log.Printf("[DEBUG] Filtered noisy resource: %s/%s/%s op=%s", kind, meta.GetNamespace(), meta.GetName(), op)
 		}
 	}
@@ -527,6 +542,18 @@ func enqueueChange(ch chan<- ResourceChange, kind string, obj any, oldObj any, o
 		diff = ComputeDiff(kind, oldObj, obj)
 	}
 
+	if kind == "Pod" && op == "update" {
+		restartStorms.observePodUpdate(oldObj, obj)
+	}
+	if kind == "Secret" {
+		switch op {
+		case "update":
+			secretValueDiffs.record(oldObj, obj, time.Now())
+		case "delete":
+			secretValueDiffs.forget(meta.GetNamespace(), meta.GetName())
+		}
+	}
+
 	change := ResourceChange{
 		Kind:      kind,
 		Namespace: meta.GetNamespace(),
@@ -543,7 +570,7 @@ func enqueueChange(ch chan<- ResourceChange, kind string, obj any, oldObj any, o
 		// Channel full, drop event
 		timeline.RecordDrop(kind, meta.GetNamespace(), meta.GetName(),
 			timeline.DropReasonChannelFull, op)
-		if DebugEvents {
+		if DebugEvents.Load() {
 			log.Printf("[DEBUG] Change channel full, dropped: %s/%s/%s op=%s", kind, meta.GetNamespace(), meta.GetName(), op)
 		}
 	}
@@ -562,7 +589,7 @@ func recordToTimelineStore(kind, namespace, name, uid, op string, oldObj, newObj
 	if op == "add" {
 		if store.IsResourceSeen(kind, namespace, name) {
 			timeline.RecordDrop(kind, namespace, name, timeline.DropReasonAlreadySeen, op)
-			if DebugEvents {
+			if DebugEvents.Load() {
 				log.Printf("[DEBUG] Already seen, skipping: %s/%s/%s", kind, namespace, name)
 			}
 			return
@@ -627,6 +654,10 @@ func recordToTimelineStore(kind, namespace, name, uid, op string, oldObj, newObj
 		labels,
 		createdAt,
 	)
+	// A health transition (e.g. healthy -> unhealthy) can be more severe than the new state alone
+	if op == "update" && oldObj != nil {
+		event.Severity = timeline.ComputeSeverity(&event, timeline.DetermineHealthState(kind, oldObj))
+	}
 
 	// For "add" operations, also extract historical events from resource status
 	// and record them to the timeline store
@@ -656,7 +687,7 @@ func recordToTimelineStore(kind, namespace, name, uid, op string, oldObj, newObj
 				// If resource is older than 30 seconds, it's a sync, not a real create
 				if age > 30*time.Second {
 					isSyncEvent = true
-					if DebugEvents {
+					if DebugEvents.Load() {
 						log.Printf("[DEBUG] Skipping stale add event (age=%v): %s/%s/%s", age, kind, namespace, name)
 					}
 				}
@@ -664,7 +695,7 @@ func recordToTimelineStore(kind, namespace, name, uid, op string, oldObj, newObj
 		}
 
 		if isSyncEvent {
-			if DebugEvents {
+			if DebugEvents.Load() {
 				log.Printf("[DEBUG] Skipping sync add event: %s/%s/%s (extracted %d historical events)", kind, namespace, name, len(events))
 			}
 			// Only record historical events, not the add event
@@ -53,6 +53,7 @@ type Capabilities struct {
 	PortForward bool                 `json:"portForward"`         // Can create pods/portforward
 	Secrets     bool                 `json:"secrets"`             // Can list secrets
 	HelmWrite   bool                 `json:"helmWrite"`           // Helm write ops (detected via secrets/create as sentinel RBAC check)
+	ReadOnly    bool                 `json:"readOnly"`            // Read-only mode is on (set by the server, not RBAC)
 	Resources   *ResourcePermissions `json:"resources,omitempty"` // Per-resource-type permissions
 }
 
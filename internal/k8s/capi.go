@@ -0,0 +1,284 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// CAPIGroup is the API group of Cluster API's core CRDs
+const CAPIGroup = "cluster.x-k8s.io"
+
+const (
+	capiClusterNameLabel  = "cluster.x-k8s.io/cluster-name"
+	capiControlPlaneLabel = "cluster.x-k8s.io/control-plane"
+)
+
+// CAPIMachineDeployment summarizes a MachineDeployment of a workload cluster
+type CAPIMachineDeployment struct {
+	Name            string `json:"name"`
+	Phase           string `json:"phase,omitempty"` // ScalingUp, ScalingDown, Running, Failed, Unknown
+	Version         string `json:"version,omitempty"`
+	Replicas        int64  `json:"replicas"`
+	ReadyReplicas   int64  `json:"readyReplicas"`
+	UpdatedReplicas int64  `json:"updatedReplicas"`
+}
+
+// CAPIMachine summarizes a Machine of a workload cluster
+type CAPIMachine struct {
+	Name         string `json:"name"`
+	Phase        string `json:"phase,omitempty"` // Pending, Provisioning, Provisioned, Running, Deleting, Failed
+	Version      string `json:"version,omitempty"`
+	NodeName     string `json:"nodeName,omitempty"`
+	ProviderID   string `json:"providerID,omitempty"`
+	ControlPlane bool   `json:"controlPlane"`
+	Deployment   string `json:"deployment,omitempty"` // Owning MachineDeployment, if any
+}
+
+// CAPICluster summarizes a Cluster API workload cluster with its machines
+type CAPICluster struct {
+	Namespace           string                  `json:"namespace"`
+	Name                string                  `json:"name"`
+	Phase               string                  `json:"phase,omitempty"` // Pending, Provisioning, Provisioned, Deleting, Failed
+	Version             string                  `json:"version,omitempty"`
+	ClassName           string                  `json:"className,omitempty"` // ClusterClass for topology-managed clusters
+	Paused              bool                    `json:"paused,omitempty"`
+	ControlPlaneReady   bool                    `json:"controlPlaneReady"`
+	InfrastructureReady bool                    `json:"infrastructureReady"`
+	ControlPlaneKind    string                  `json:"controlPlaneKind,omitempty"`   // KubeadmControlPlane, ...
+	InfrastructureKind  string                  `json:"infrastructureKind,omitempty"` // AWSCluster, DockerCluster, ...
+	Message             string                  `json:"message,omitempty"`            // Failure message or not-ready Ready condition
+	MachineDeployments  []CAPIMachineDeployment `json:"machineDeployments"`
+	Machines            []CAPIMachine           `json:"machines"`
+}
+
+// ListCAPIClusters returns Cluster API clusters with their MachineDeployments
+// and Machines. installed is false when the Cluster API CRDs aren't present.
+func ListCAPIClusters(namespaces []string) (clusters []CAPICluster, installed bool, err error) {
+	dc := GetDynamicResourceCache()
+	discovery := GetResourceDiscovery()
+	if dc == nil || discovery == nil {
+		return nil, false, fmt.Errorf("dynamic resource cache not initialized")
+	}
+
+	clusterGVR, ok := discovery.GetGVRWithGroup("Cluster", CAPIGroup)
+	if !ok {
+		return []CAPICluster{}, false, nil
+	}
+	namespace := ""
+	if len(namespaces) == 1 {
+		namespace = namespaces[0]
+	}
+	inScope := func(u *unstructured.Unstructured) bool {
+		return len(namespaces) <= 1 || slices.Contains(namespaces, u.GetNamespace())
+	}
+
+	items, err := dc.List(clusterGVR, namespace)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to list clusters: %w", err)
+	}
+	byKey := map[string]*CAPICluster{}
+	clusters = make([]CAPICluster, 0, len(items))
+	for _, item := range items {
+		if inScope(item) {
+			clusters = append(clusters, ParseCAPICluster(item))
+		}
+	}
+	for i := range clusters {
+		byKey[clusters[i].Namespace+"/"+clusters[i].Name] = &clusters[i]
+	}
+
+	if gvr, ok := discovery.GetGVRWithGroup("MachineDeployment", CAPIGroup); ok {
+		mds, _ := dc.List(gvr, namespace)
+		for _, md := range mds {
+			if c := byKey[md.GetNamespace()+"/"+capiClusterName(md)]; c != nil {
+				c.MachineDeployments = append(c.MachineDeployments, ParseCAPIMachineDeployment(md))
+			}
+		}
+	}
+	if gvr, ok := discovery.GetGVRWithGroup("Machine", CAPIGroup); ok {
+		machines, _ := dc.List(gvr, namespace)
+		for _, m := range machines {
+			if c := byKey[m.GetNamespace()+"/"+capiClusterName(m)]; c != nil {
+				c.Machines = append(c.Machines, ParseCAPIMachine(m))
+			}
+		}
+	}
+
+	for i := range clusters {
+		c := &clusters[i]
+		sort.Slice(c.MachineDeployments, func(a, b int) bool { return c.MachineDeployments[a].Name < c.MachineDeployments[b].Name })
+		sort.Slice(c.Machines, func(a, b int) bool {
+			if c.Machines[a].ControlPlane != c.Machines[b].ControlPlane {
+				return c.Machines[a].ControlPlane
+			}
+			return c.Machines[a].Name < c.Machines[b].Name
+		})
+		// Clusters without a managed topology don't carry a version; use the control plane machines'
+		if c.Version == "" {
+			for _, m := range c.Machines {
+				if m.ControlPlane && m.Version > c.Version {
+					c.Version = m.Version
+				}
+			}
+		}
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].Namespace != clusters[j].Namespace {
+			return clusters[i].Namespace < clusters[j].Namespace
+		}
+		return clusters[i].Name < clusters[j].Name
+	})
+	return clusters, true, nil
+}
+
+// ParseCAPICluster extracts phase, readiness, and references from a Cluster.
+// Handles both v1beta1 (status.controlPlaneReady) and v1beta2 (status.initialization) status.
+func ParseCAPICluster(u *unstructured.Unstructured) CAPICluster {
+	c := CAPICluster{
+		Namespace:          u.GetNamespace(),
+		Name:               u.GetName(),
+		MachineDeployments: []CAPIMachineDeployment{},
+		Machines:           []CAPIMachine{},
+	}
+	c.Phase, _, _ = unstructured.NestedString(u.Object, "status", "phase")
+	c.Version, _, _ = unstructured.NestedString(u.Object, "spec", "topology", "version")
+	c.ClassName, _, _ = unstructured.NestedString(u.Object, "spec", "topology", "class")
+	if c.ClassName == "" {
+		c.ClassName, _, _ = unstructured.NestedString(u.Object, "spec", "topology", "classRef", "name")
+	}
+	c.Paused, _, _ = unstructured.NestedBool(u.Object, "spec", "paused")
+	c.ControlPlaneKind, _, _ = unstructured.NestedString(u.Object, "spec", "controlPlaneRef", "kind")
+	c.InfrastructureKind, _, _ = unstructured.NestedString(u.Object, "spec", "infrastructureRef", "kind")
+
+	if ready, ok, _ := unstructured.NestedBool(u.Object, "status", "controlPlaneReady"); ok {
+		c.ControlPlaneReady = ready
+	} else {
+		c.ControlPlaneReady, _, _ = unstructured.NestedBool(u.Object, "status", "initialization", "controlPlaneInitialized")
+	}
+	if ready, ok, _ := unstructured.NestedBool(u.Object, "status", "infrastructureReady"); ok {
+		c.InfrastructureReady = ready
+	} else {
+		c.InfrastructureReady, _, _ = unstructured.NestedBool(u.Object, "status", "initialization", "infrastructureProvisioned")
+	}
+
+	c.Message, _, _ = unstructured.NestedString(u.Object, "status", "failureMessage")
+	if c.Message == "" {
+		conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+		for _, cond := range conditions {
+			m, ok := cond.(map[string]any)
+			if ok && m["type"] == "Ready" && m["status"] != "True" {
+				c.Message = stringField(m, "message")
+			}
+		}
+	}
+	return c
+}
+
+// ParseCAPIMachineDeployment extracts phase, version, and replica counts
+func ParseCAPIMachineDeployment(u *unstructured.Unstructured) CAPIMachineDeployment {
+	md := CAPIMachineDeployment{Name: u.GetName()}
+	md.Phase, _, _ = unstructured.NestedString(u.Object, "status", "phase")
+	md.Version, _, _ = unstructured.NestedString(u.Object, "spec", "template", "spec", "version")
+	md.Replicas, _, _ = unstructured.NestedInt64(u.Object, "spec", "replicas")
+	md.ReadyReplicas, _, _ = unstructured.NestedInt64(u.Object, "status", "readyReplicas")
+	md.UpdatedReplicas, _, _ = unstructured.NestedInt64(u.Object, "status", "updatedReplicas")
+	return md
+}
+
+// ParseCAPIMachine extracts phase, version, and the workload cluster node of a Machine
+func ParseCAPIMachine(u *unstructured.Unstructured) CAPIMachine {
+	m := CAPIMachine{Name: u.GetName()}
+	m.Phase, _, _ = unstructured.NestedString(u.Object, "status", "phase")
+	m.Version, _, _ = unstructured.NestedString(u.Object, "spec", "version")
+	m.NodeName, _, _ = unstructured.NestedString(u.Object, "status", "nodeRef", "name")
+	m.ProviderID, _, _ = unstructured.NestedString(u.Object, "spec", "providerID")
+	_, m.ControlPlane = u.GetLabels()[capiControlPlaneLabel]
+	m.Deployment = u.GetLabels()["cluster.x-k8s.io/deployment-name"]
+	return m
+}
+
+// capiClusterName returns the cluster a MachineDeployment or Machine belongs to
+func capiClusterName(u *unstructured.Unstructured) string {
+	if name, _, _ := unstructured.NestedString(u.Object, "spec", "clusterName"); name != "" {
+		return name
+	}
+	return u.GetLabels()[capiClusterNameLabel]
+}
+
+// CAPIContextName is the kubeconfig context name used for a workload cluster
+func CAPIContextName(namespace, name string) string {
+	return "capi:" + namespace + "/" + name
+}
+
+// RegisterCAPIClusterContext reads the <cluster>-kubeconfig secret Cluster API
+// writes for each workload cluster and registers it as a switchable context.
+// Returns the context name.
+func RegisterCAPIClusterContext(ctx context.Context, namespace, name string) (string, error) {
+	client := GetClient()
+	if client == nil {
+		return "", fmt.Errorf("kubernetes client not available")
+	}
+	secret, err := client.CoreV1().Secrets(namespace).Get(ctx, name+"-kubeconfig", metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to read kubeconfig secret for cluster %s/%s: %w", namespace, name, err)
+	}
+	data := secret.Data["value"]
+	if len(data) == 0 {
+		return "", fmt.Errorf("kubeconfig secret %s/%s-kubeconfig has no value key", namespace, name)
+	}
+
+	contextName := CAPIContextName(namespace, name)
+	cfg, err := renameKubeconfigContext(data, contextName)
+	if err != nil {
+		return "", fmt.Errorf("invalid kubeconfig for cluster %s/%s: %w", namespace, name, err)
+	}
+	RegisterGeneratedContext(contextName, cfg)
+	return contextName, nil
+}
+
+// renameKubeconfigContext parses a kubeconfig and returns a copy holding only its
+// current context, with the context, cluster, and user renamed to contextName so
+// they can't collide with entries in the user's kubeconfig.
+func renameKubeconfigContext(data []byte, contextName string) (*clientcmdapi.Config, error) {
+	parsed, err := clientcmd.Load(data)
+	if err != nil {
+		return nil, err
+	}
+	current := parsed.CurrentContext
+	if _, ok := parsed.Contexts[current]; !ok {
+		// Fall back to the only context when current-context isn't set
+		if len(parsed.Contexts) != 1 {
+			return nil, fmt.Errorf("kubeconfig has %d contexts and no current-context", len(parsed.Contexts))
+		}
+		for name := range parsed.Contexts {
+			current = name
+		}
+	}
+	src := parsed.Contexts[current]
+	cluster, ok := parsed.Clusters[src.Cluster]
+	if !ok {
+		return nil, fmt.Errorf("cluster %q referenced by context %q not found", src.Cluster, current)
+	}
+	user, ok := parsed.AuthInfos[src.AuthInfo]
+	if !ok {
+		return nil, fmt.Errorf("user %q referenced by context %q not found", src.AuthInfo, current)
+	}
+
+	cfg := clientcmdapi.NewConfig()
+	cfg.Clusters[contextName] = cluster
+	cfg.AuthInfos[contextName] = user
+	cfg.Contexts[contextName] = &clientcmdapi.Context{
+		Cluster:   contextName,
+		AuthInfo:  contextName,
+		Namespace: src.Namespace,
+	}
+	cfg.CurrentContext = contextName
+	return cfg, nil
+}
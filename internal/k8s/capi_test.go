@@ -0,0 +1,114 @@
+package k8s
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestParseCAPICluster(t *testing.T) {
+	v1beta1 := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "prod", "namespace": "fleet"},
+		"spec": map[string]any{
+			"topology":          map[string]any{"version": "v1.31.2", "class": "quick-start"},
+			"controlPlaneRef":   map[string]any{"kind": "KubeadmControlPlane"},
+			"infrastructureRef": map[string]any{"kind": "AWSCluster"},
+		},
+		"status": map[string]any{
+			"phase":               "Provisioned",
+			"controlPlaneReady":   true,
+			"infrastructureReady": true,
+			"conditions": []any{
+				map[string]any{"type": "Ready", "status": "False", "message": "1 of 3 machines not ready"},
+			},
+		},
+	}}
+	c := ParseCAPICluster(v1beta1)
+	if c.Phase != "Provisioned" || c.Version != "v1.31.2" || c.ClassName != "quick-start" {
+		t.Errorf("unexpected cluster: %+v", c)
+	}
+	if !c.ControlPlaneReady || !c.InfrastructureReady || c.InfrastructureKind != "AWSCluster" {
+		t.Errorf("unexpected readiness: %+v", c)
+	}
+	if c.Message != "1 of 3 machines not ready" {
+		t.Errorf("expected Ready condition message, got %q", c.Message)
+	}
+
+	v1beta2 := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "dev", "namespace": "fleet"},
+		"status": map[string]any{
+			"phase":          "Provisioning",
+			"initialization": map[string]any{"infrastructureProvisioned": true},
+		},
+	}}
+	if c := ParseCAPICluster(v1beta2); c.ControlPlaneReady || !c.InfrastructureReady {
+		t.Errorf("v1beta2 initialization status not read: %+v", c)
+	}
+}
+
+func TestParseCAPIMachine(t *testing.T) {
+	u := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{
+			"name": "prod-cp-abc",
+			"labels": map[string]any{
+				"cluster.x-k8s.io/cluster-name":  "prod",
+				"cluster.x-k8s.io/control-plane": "",
+			},
+		},
+		"spec":   map[string]any{"version": "v1.31.2", "providerID": "aws:///us-east-1a/i-123"},
+		"status": map[string]any{"phase": "Running", "nodeRef": map[string]any{"name": "ip-10-0-0-1"}},
+	}}
+	m := ParseCAPIMachine(u)
+	if !m.ControlPlane || m.Phase != "Running" || m.NodeName != "ip-10-0-0-1" || m.Version != "v1.31.2" {
+		t.Errorf("unexpected machine: %+v", m)
+	}
+	if capiClusterName(u) != "prod" {
+		t.Errorf("cluster name should fall back to label, got %q", capiClusterName(u))
+	}
+}
+
+func TestRenameKubeconfigContext(t *testing.T) {
+	kubeconfig := []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: prod
+  cluster:
+    server: https://10.0.0.1:6443
+users:
+- name: prod-admin
+  user:
+    token: abc
+contexts:
+- name: prod-admin@prod
+  context:
+    cluster: prod
+    user: prod-admin
+current-context: prod-admin@prod
+`)
+	name := CAPIContextName("fleet", "prod")
+	cfg, err := renameKubeconfigContext(kubeconfig, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Clusters[name].Server != "https://10.0.0.1:6443" || cfg.AuthInfos[name].Token != "abc" {
+		t.Errorf("cluster/user not copied: %+v", cfg)
+	}
+
+	RegisterGeneratedContext(name, cfg)
+	defer func() {
+		generatedContextsMu.Lock()
+		delete(generatedContexts, name)
+		generatedContextsMu.Unlock()
+	}()
+	raw := clientcmdapi.NewConfig()
+	raw.Contexts["local"] = &clientcmdapi.Context{Cluster: "local"}
+	added := mergeGeneratedContexts(raw)
+	if !added[name] || raw.Contexts[name] == nil || raw.Contexts["local"] == nil {
+		t.Errorf("generated context not merged: %+v", raw.Contexts)
+	}
+
+	if _, err := renameKubeconfigContext([]byte("not: [valid"), name); err == nil {
+		t.Error("expected error for invalid kubeconfig")
+	}
+}
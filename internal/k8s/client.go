@@ -1,6 +1,7 @@
 package k8s
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -13,13 +14,14 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/client-go/util/homedir"
 )
 
 var (
-	k8sClient       *kubernetes.Clientset
+	k8sClient       kubernetes.Interface
 	k8sConfig       *rest.Config
-	discoveryClient *discovery.DiscoveryClient
+	discoveryClient discovery.DiscoveryInterface
 	dynamicClient   dynamic.Interface
 	initOnce        sync.Once
 	initErr         error
@@ -32,12 +34,18 @@ var (
 	// clientMu protects access to client variables during context switches.
 	// Readers use RLock, context switch uses Lock.
 	clientMu sync.RWMutex
+
+	// generatedContexts are contexts created at runtime (e.g. from Cluster API
+	// kubeconfig secrets). They're merged into the kubeconfig in memory only.
+	generatedContexts   = map[string]*clientcmdapi.Config{}
+	generatedContextsMu sync.RWMutex
 )
 
 // InitOptions configures the K8s client initialization
 type InitOptions struct {
 	KubeconfigPath string
 	KubeconfigDirs []string // Directories containing kubeconfig files
+	Demo           bool     // Use a synthetic in-memory cluster instead of a kubeconfig
 }
 
 // Initialize initializes the K8s client with the given options
@@ -56,6 +64,10 @@ func MustInitialize(opts InitOptions) {
 }
 
 func doInit(opts InitOptions) error {
+	if opts.Demo {
+		return initDemo()
+	}
+
 	var config *rest.Config
 	var err error
 
@@ -136,6 +148,11 @@ func doInit(opts InitOptions) error {
 	// This is safe for a read-only visibility tool
 	config.QPS = 50
 	config.Burst = 100
+	config.Wrap(credentials.wrap)
+	config.Wrap(wrapIdentity)
+	credentials.seed(config)
+	startCredentialMonitor()
+	startAPIServerMonitor()
 
 	k8sConfig = config
 
@@ -201,7 +218,7 @@ func isValidKubeconfig(path string) bool {
 }
 
 // GetClient returns the K8s clientset
-func GetClient() *kubernetes.Clientset {
+func GetClient() kubernetes.Interface {
 	clientMu.RLock()
 	defer clientMu.RUnlock()
 	return k8sClient
@@ -215,7 +232,7 @@ func GetConfig() *rest.Config {
 }
 
 // GetDiscoveryClient returns the K8s discovery client for API resource discovery
-func GetDiscoveryClient() *discovery.DiscoveryClient {
+func GetDiscoveryClient() discovery.DiscoveryInterface {
 	clientMu.RLock()
 	defer clientMu.RUnlock()
 	return discoveryClient
@@ -276,11 +293,17 @@ func GetEffectiveNamespace() string {
 	return fallbackNamespace
 }
 
+// ErrContextNotFound is returned for a context name missing from the kubeconfig
+var ErrContextNotFound = errors.New("not found in kubeconfig")
+
 // ForceInCluster overrides in-cluster detection for testing
 var ForceInCluster bool
 
 // IsInCluster returns true if running inside a Kubernetes cluster
 func IsInCluster() bool {
+	if demoMode {
+		return false
+	}
 	return ForceInCluster || (kubeconfigPath == "" && len(kubeconfigPaths) == 0)
 }
 
@@ -295,6 +318,9 @@ type ContextInfo struct {
 
 // GetAvailableContexts returns all available contexts from the kubeconfig
 func GetAvailableContexts() ([]ContextInfo, error) {
+	if demoMode {
+		return []ContextInfo{{Name: DemoContextName, Cluster: clusterName, User: "demo", IsCurrent: true}}, nil
+	}
 	if IsInCluster() {
 		// In-cluster mode - only one "context" available
 		return []ContextInfo{
@@ -328,6 +354,7 @@ func GetAvailableContexts() ([]ContextInfo, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
 	}
+	mergeGeneratedContexts(&rawConfig)
 
 	// Use Explorer's in-memory contextName to determine current context
 	// This allows Explorer to switch contexts without modifying the kubeconfig file
@@ -354,43 +381,21 @@ func GetAvailableContexts() ([]ContextInfo, error) {
 // SwitchContext switches the K8s client to use a different context
 // This reinitializes all clients (k8sClient, discoveryClient, dynamicClient)
 func SwitchContext(name string) error {
+	if demoMode {
+		return fmt.Errorf("cannot switch context in demo mode")
+	}
 	if IsInCluster() {
 		return fmt.Errorf("cannot switch context when running in-cluster")
 	}
 
-	var loadingRules *clientcmd.ClientConfigLoadingRules
-	if len(kubeconfigPaths) > 0 {
-		// Multi-kubeconfig mode
-		loadingRules = &clientcmd.ClientConfigLoadingRules{Precedence: kubeconfigPaths}
-	} else {
-		// Single kubeconfig mode
-		kubeconfig := kubeconfigPath
-		if kubeconfig == "" {
-			return fmt.Errorf("kubeconfig path not set")
-		}
-		loadingRules = &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig}
-	}
-
-	// Build config with the new context
-	configOverrides := &clientcmd.ConfigOverrides{CurrentContext: name}
-	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
-
-	// Verify the context exists
-	rawConfig, err := kubeConfig.RawConfig()
-	if err != nil {
-		return fmt.Errorf("failed to load kubeconfig: %w", err)
-	}
-
-	ctx, ok := rawConfig.Contexts[name]
-	if !ok {
-		return fmt.Errorf("context %q not found in kubeconfig", name)
-	}
-
-	// Build the REST config for the new context
-	config, err := kubeConfig.ClientConfig()
+	config, ctx, err := loadContextConfig(name)
 	if err != nil {
-		return fmt.Errorf("failed to build config for context %q: %w", name, err)
+		return err
 	}
+	config.Wrap(credentials.wrap)
+	config.Wrap(wrapIdentity)
+	credentials.reset()
+	credentials.seed(config)
 
 	// Apply the same QPS/Burst settings as initial client creation.
 	// Without this, new clients use the default 5 QPS / 10 Burst, causing
@@ -427,3 +432,112 @@ func SwitchContext(name string) error {
 
 	return nil
 }
+
+// loadContextConfig re-reads the kubeconfig and builds the REST config for a
+// context, so exec plugins, tokens and certificates are picked up fresh
+func loadContextConfig(name string) (*rest.Config, *clientcmdapi.Context, error) {
+	var loadingRules *clientcmd.ClientConfigLoadingRules
+	if len(kubeconfigPaths) > 0 {
+		// Multi-kubeconfig mode
+		loadingRules = &clientcmd.ClientConfigLoadingRules{Precedence: kubeconfigPaths}
+	} else {
+		// Single kubeconfig mode
+		kubeconfig := kubeconfigPath
+		if kubeconfig == "" {
+			return nil, nil, fmt.Errorf("kubeconfig path not set")
+		}
+		loadingRules = &clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig}
+	}
+
+	// Build config with the new context
+	configOverrides := &clientcmd.ConfigOverrides{CurrentContext: name}
+	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
+
+	// Verify the context exists
+	rawConfig, err := kubeConfig.RawConfig()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	generated := mergeGeneratedContexts(&rawConfig)
+
+	ctx, ok := rawConfig.Contexts[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("context %q %w", name, ErrContextNotFound)
+	}
+
+	// Build the REST config for the new context. Generated contexts only exist
+	// in the merged in-memory config, not in the files the loader reads.
+	var config *rest.Config
+	if generated[name] {
+		config, err = clientcmd.NewNonInteractiveClientConfig(rawConfig, name, &clientcmd.ConfigOverrides{}, nil).ClientConfig()
+	} else {
+		config, err = kubeConfig.ClientConfig()
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build config for context %q: %w", name, err)
+	}
+	return config, ctx, nil
+}
+
+// RegisterGeneratedContext adds a single-context kubeconfig that can be switched
+// to like any kubeconfig context. Registering the same name again replaces it.
+func RegisterGeneratedContext(name string, cfg *clientcmdapi.Config) {
+	generatedContextsMu.Lock()
+	defer generatedContextsMu.Unlock()
+	generatedContexts[name] = cfg
+}
+
+// RegisterKubeconfigContext registers the current context of a kubeconfig
+// (e.g. one printed by a local cluster tool) as contextName
+func RegisterKubeconfigContext(contextName string, data []byte) error {
+	cfg, err := renameKubeconfigContext(data, contextName)
+	if err != nil {
+		return fmt.Errorf("invalid kubeconfig for context %s: %w", contextName, err)
+	}
+	RegisterGeneratedContext(contextName, cfg)
+	return nil
+}
+
+// UnregisterGeneratedContext removes a context added with RegisterGeneratedContext
+func UnregisterGeneratedContext(name string) {
+	generatedContextsMu.Lock()
+	defer generatedContextsMu.Unlock()
+	delete(generatedContexts, name)
+}
+
+// mergeGeneratedContexts adds generated contexts (and their clusters and users)
+// to raw, without overriding entries from the kubeconfig files. Returns the
+// names of the contexts that were added.
+func mergeGeneratedContexts(raw *clientcmdapi.Config) map[string]bool {
+	generatedContextsMu.RLock()
+	defer generatedContextsMu.RUnlock()
+
+	added := map[string]bool{}
+	for name, cfg := range generatedContexts {
+		if _, exists := raw.Contexts[name]; exists {
+			continue
+		}
+		ctx, ok := cfg.Contexts[name]
+		if !ok {
+			continue
+		}
+		if raw.Contexts == nil {
+			raw.Contexts = map[string]*clientcmdapi.Context{}
+		}
+		if raw.Clusters == nil {
+			raw.Clusters = map[string]*clientcmdapi.Cluster{}
+		}
+		if raw.AuthInfos == nil {
+			raw.AuthInfos = map[string]*clientcmdapi.AuthInfo{}
+		}
+		raw.Contexts[name] = ctx
+		if cluster, ok := cfg.Clusters[ctx.Cluster]; ok {
+			raw.Clusters[ctx.Cluster] = cluster
+		}
+		if user, ok := cfg.AuthInfos[ctx.AuthInfo]; ok {
+			raw.AuthInfos[ctx.AuthInfo] = user
+		}
+		added[name] = true
+	}
+	return added
+}
@@ -0,0 +1,259 @@
+package k8s
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// maxAutoscalerEvents caps the recent cluster-autoscaler events returned
+const maxAutoscalerEvents = 20
+
+// Node capacity types, normalized across providers
+const (
+	CapacityOnDemand    = "on-demand"
+	CapacitySpot        = "spot"
+	CapacityPreemptible = "preemptible" // GKE preemptible VMs (predecessor of spot)
+)
+
+// First label present wins, so well-known labels come before their deprecated forms
+var (
+	instanceTypeLabels = []string{"node.kubernetes.io/instance-type", "beta.kubernetes.io/instance-type"}
+	zoneLabels         = []string{"topology.kubernetes.io/zone", "failure-domain.beta.kubernetes.io/zone"}
+	regionLabels       = []string{"topology.kubernetes.io/region", "failure-domain.beta.kubernetes.io/region"}
+	nodeGroupLabels    = []string{
+		"eks.amazonaws.com/nodegroup",    // EKS managed node groups
+		"alpha.eksctl.io/nodegroup-name", // eksctl self-managed node groups
+		"cloud.google.com/gke-nodepool",  // GKE
+		"kubernetes.azure.com/agentpool", // AKS
+		"agentpool",                      // AKS (older clusters)
+		"karpenter.sh/nodepool",          // Karpenter v1
+		"karpenter.sh/provisioner-name",  // Karpenter v1alpha5
+	}
+)
+
+// NodeCloudInfo is cloud placement metadata parsed from a node's labels
+type NodeCloudInfo struct {
+	InstanceType string `json:"instanceType,omitempty"`
+	Zone         string `json:"zone,omitempty"`
+	Region       string `json:"region,omitempty"`
+	NodeGroup    string `json:"nodeGroup,omitempty"`
+	CapacityType string `json:"capacityType,omitempty"` // on-demand, spot, preemptible
+}
+
+// NodeGroupSummary aggregates the nodes of one node group / node pool / agent pool
+type NodeGroupSummary struct {
+	Name          string         `json:"name"` // "" for nodes without a node group label
+	Nodes         int            `json:"nodes"`
+	ReadyNodes    int            `json:"readyNodes"`
+	SpotNodes     int            `json:"spotNodes"` // Spot or preemptible
+	InstanceTypes map[string]int `json:"instanceTypes"`
+	Zones         map[string]int `json:"zones"`
+}
+
+// AutoscalerEvent is one cluster-autoscaler decision reported as a K8s event
+type AutoscalerEvent struct {
+	Reason    string    `json:"reason"` // TriggeredScaleUp, ScaleDown, NotTriggerScaleUp, FailedToScaleUpGroup, ...
+	Message   string    `json:"message"`
+	Kind      string    `json:"kind"` // Involved object (Pod for scale-ups, Node for scale-downs)
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name"`
+	Count     int32     `json:"count"`
+	LastSeen  time.Time `json:"lastSeen"`
+	Warning   bool      `json:"warning"`
+}
+
+// AutoscalerActivity summarizes recent cluster-autoscaler events
+type AutoscalerActivity struct {
+	Detected      bool              `json:"detected"` // Any cluster-autoscaler events seen
+	ScaleUps      int               `json:"scaleUps"`
+	ScaleDowns    int               `json:"scaleDowns"`
+	Failures      int               `json:"failures"` // Warning events (e.g. failed or not-triggered scale-ups)
+	LastScaleUp   *time.Time        `json:"lastScaleUp,omitempty"`
+	LastScaleDown *time.Time        `json:"lastScaleDown,omitempty"`
+	Recent        []AutoscalerEvent `json:"recent"`
+}
+
+// CloudSummary is cloud enrichment for recognized managed platforms
+type CloudSummary struct {
+	NodeGroups []NodeGroupSummary       `json:"nodeGroups"`
+	Nodes      map[string]NodeCloudInfo `json:"nodes"` // Keyed by node name
+	Autoscaler AutoscalerActivity       `json:"autoscaler"`
+}
+
+// cloudPlatforms are the platforms whose node labels CloudSummary understands
+var cloudPlatforms = []string{"eks", "gke", "gke-autopilot", "aks"}
+
+// GetCloudSummary parses node labels and cluster-autoscaler events from the cache.
+// Returns nil for platforms other than EKS, GKE, and AKS.
+func GetCloudSummary(platform string) *CloudSummary {
+	if !slices.Contains(cloudPlatforms, platform) {
+		return nil
+	}
+	cache := GetResourceCache()
+	if cache == nil || cache.Nodes() == nil {
+		return nil
+	}
+	nodes, err := cache.Nodes().List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+
+	var events []*corev1.Event
+	if cache.Events() != nil {
+		events, _ = cache.Events().List(labels.Everything())
+	}
+	return buildCloudSummary(nodes, events)
+}
+
+func buildCloudSummary(nodes []*corev1.Node, events []*corev1.Event) *CloudSummary {
+	summary := &CloudSummary{
+		NodeGroups: []NodeGroupSummary{},
+		Nodes:      make(map[string]NodeCloudInfo, len(nodes)),
+		Autoscaler: summarizeAutoscalerEvents(events),
+	}
+
+	groups := map[string]*NodeGroupSummary{}
+	for _, node := range nodes {
+		info := ParseNodeCloudInfo(node)
+		summary.Nodes[node.Name] = info
+
+		group, ok := groups[info.NodeGroup]
+		if !ok {
+			group = &NodeGroupSummary{Name: info.NodeGroup, InstanceTypes: map[string]int{}, Zones: map[string]int{}}
+			groups[info.NodeGroup] = group
+		}
+		group.Nodes++
+		if isNodeConditionTrue(node, corev1.NodeReady) {
+			group.ReadyNodes++
+		}
+		if info.CapacityType == CapacitySpot || info.CapacityType == CapacityPreemptible {
+			group.SpotNodes++
+		}
+		if info.InstanceType != "" {
+			group.InstanceTypes[info.InstanceType]++
+		}
+		if info.Zone != "" {
+			group.Zones[info.Zone]++
+		}
+	}
+
+	for _, group := range groups {
+		summary.NodeGroups = append(summary.NodeGroups, *group)
+	}
+	sort.Slice(summary.NodeGroups, func(i, j int) bool {
+		// Ungrouped nodes last
+		if (summary.NodeGroups[i].Name == "") != (summary.NodeGroups[j].Name == "") {
+			return summary.NodeGroups[j].Name == ""
+		}
+		return summary.NodeGroups[i].Name < summary.NodeGroups[j].Name
+	})
+	return summary
+}
+
+// ParseNodeCloudInfo extracts instance type, placement, node group, and
+// capacity type from well-known EKS, GKE, AKS, and Karpenter node labels
+func ParseNodeCloudInfo(node *corev1.Node) NodeCloudInfo {
+	l := node.Labels
+	info := NodeCloudInfo{
+		InstanceType: firstLabel(l, instanceTypeLabels),
+		Zone:         firstLabel(l, zoneLabels),
+		Region:       firstLabel(l, regionLabels),
+		NodeGroup:    firstLabel(l, nodeGroupLabels),
+	}
+
+	switch {
+	case strings.EqualFold(l["eks.amazonaws.com/capacityType"], "SPOT"),
+		l["karpenter.sh/capacity-type"] == "spot",
+		l["cloud.google.com/gke-spot"] == "true",
+		l["kubernetes.azure.com/scalesetpriority"] == "spot":
+		info.CapacityType = CapacitySpot
+	case l["cloud.google.com/gke-preemptible"] == "true":
+		info.CapacityType = CapacityPreemptible
+	case l["eks.amazonaws.com/capacityType"] != "",
+		l["karpenter.sh/capacity-type"] != "",
+		l["cloud.google.com/gke-nodepool"] != "",
+		l["kubernetes.azure.com/agentpool"] != "":
+		// Managed node pools without a spot marker are on-demand
+		info.CapacityType = CapacityOnDemand
+	}
+	return info
+}
+
+// summarizeAutoscalerEvents counts cluster-autoscaler scale-ups, scale-downs, and failures
+func summarizeAutoscalerEvents(events []*corev1.Event) AutoscalerActivity {
+	activity := AutoscalerActivity{Recent: []AutoscalerEvent{}}
+	for _, e := range events {
+		if e.Source.Component != "cluster-autoscaler" && e.ReportingController != "cluster-autoscaler" {
+			continue
+		}
+		activity.Detected = true
+
+		lastSeen := e.LastTimestamp.Time
+		if lastSeen.IsZero() {
+			lastSeen = e.EventTime.Time
+		}
+		if lastSeen.IsZero() {
+			lastSeen = e.CreationTimestamp.Time
+		}
+
+		warning := e.Type == corev1.EventTypeWarning
+		switch {
+		case warning:
+			activity.Failures++
+		case e.Reason == "TriggeredScaleUp":
+			activity.ScaleUps++
+			if activity.LastScaleUp == nil || lastSeen.After(*activity.LastScaleUp) {
+				t := lastSeen
+				activity.LastScaleUp = &t
+			}
+		case strings.HasPrefix(e.Reason, "ScaleDown"):
+			activity.ScaleDowns++
+			if activity.LastScaleDown == nil || lastSeen.After(*activity.LastScaleDown) {
+				t := lastSeen
+				activity.LastScaleDown = &t
+			}
+		}
+
+		activity.Recent = append(activity.Recent, AutoscalerEvent{
+			Reason:    e.Reason,
+			Message:   e.Message,
+			Kind:      e.InvolvedObject.Kind,
+			Namespace: e.InvolvedObject.Namespace,
+			Name:      e.InvolvedObject.Name,
+			Count:     e.Count,
+			LastSeen:  lastSeen,
+			Warning:   warning,
+		})
+	}
+
+	sort.Slice(activity.Recent, func(i, j int) bool {
+		return activity.Recent[i].LastSeen.After(activity.Recent[j].LastSeen)
+	})
+	if len(activity.Recent) > maxAutoscalerEvents {
+		activity.Recent = activity.Recent[:maxAutoscalerEvents]
+	}
+	return activity
+}
+
+func firstLabel(l map[string]string, keys []string) string {
+	for _, k := range keys {
+		if v := l[k]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func isNodeConditionTrue(node *corev1.Node, condType corev1.NodeConditionType) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == condType {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
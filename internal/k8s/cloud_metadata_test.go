@@ -0,0 +1,105 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func cloudTestNode(name string, ready bool, l map[string]string) *corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: l},
+		Status:     corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}}},
+	}
+}
+
+func TestParseNodeCloudInfo(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   NodeCloudInfo
+	}{
+		{
+			name: "eks spot",
+			labels: map[string]string{
+				"node.kubernetes.io/instance-type": "m5.large",
+				"topology.kubernetes.io/zone":      "us-east-1a",
+				"topology.kubernetes.io/region":    "us-east-1",
+				"eks.amazonaws.com/nodegroup":      "workers",
+				"eks.amazonaws.com/capacityType":   "SPOT",
+			},
+			want: NodeCloudInfo{InstanceType: "m5.large", Zone: "us-east-1a", Region: "us-east-1", NodeGroup: "workers", CapacityType: CapacitySpot},
+		},
+		{
+			name:   "gke preemptible",
+			labels: map[string]string{"cloud.google.com/gke-nodepool": "pool-1", "cloud.google.com/gke-preemptible": "true"},
+			want:   NodeCloudInfo{NodeGroup: "pool-1", CapacityType: CapacityPreemptible},
+		},
+		{
+			name:   "aks on-demand with deprecated zone label",
+			labels: map[string]string{"kubernetes.azure.com/agentpool": "system", "failure-domain.beta.kubernetes.io/zone": "eastus-1"},
+			want:   NodeCloudInfo{NodeGroup: "system", Zone: "eastus-1", CapacityType: CapacityOnDemand},
+		},
+		{
+			name:   "karpenter",
+			labels: map[string]string{"karpenter.sh/nodepool": "default", "karpenter.sh/capacity-type": "spot"},
+			want:   NodeCloudInfo{NodeGroup: "default", CapacityType: CapacitySpot},
+		},
+		{
+			name:   "unlabeled",
+			labels: nil,
+			want:   NodeCloudInfo{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseNodeCloudInfo(cloudTestNode("n", true, tt.labels)); got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildCloudSummary(t *testing.T) {
+	nodes := []*corev1.Node{
+		cloudTestNode("a", true, map[string]string{"eks.amazonaws.com/nodegroup": "workers", "eks.amazonaws.com/capacityType": "SPOT", "topology.kubernetes.io/zone": "us-east-1a"}),
+		cloudTestNode("b", false, map[string]string{"eks.amazonaws.com/nodegroup": "workers", "eks.amazonaws.com/capacityType": "ON_DEMAND", "topology.kubernetes.io/zone": "us-east-1b"}),
+		cloudTestNode("c", true, nil),
+	}
+	now := time.Now()
+	events := []*corev1.Event{
+		{Reason: "TriggeredScaleUp", Type: corev1.EventTypeNormal, Source: corev1.EventSource{Component: "cluster-autoscaler"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web-1"}, LastTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+		{Reason: "ScaleDownEmpty", Type: corev1.EventTypeNormal, ReportingController: "cluster-autoscaler",
+			InvolvedObject: corev1.ObjectReference{Kind: "Node", Name: "c"}, LastTimestamp: metav1.NewTime(now)},
+		{Reason: "NotTriggerScaleUp", Type: corev1.EventTypeWarning, Source: corev1.EventSource{Component: "cluster-autoscaler"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "big-1"}, LastTimestamp: metav1.NewTime(now.Add(-2 * time.Minute))},
+		{Reason: "Scheduled", Type: corev1.EventTypeNormal, Source: corev1.EventSource{Component: "default-scheduler"}},
+	}
+
+	summary := buildCloudSummary(nodes, events)
+	if len(summary.NodeGroups) != 2 || summary.NodeGroups[0].Name != "workers" || summary.NodeGroups[1].Name != "" {
+		t.Fatalf("expected workers group then ungrouped nodes, got %+v", summary.NodeGroups)
+	}
+	workers := summary.NodeGroups[0]
+	if workers.Nodes != 2 || workers.ReadyNodes != 1 || workers.SpotNodes != 1 || len(workers.Zones) != 2 {
+		t.Errorf("unexpected workers summary: %+v", workers)
+	}
+	if summary.Nodes["a"].CapacityType != CapacitySpot {
+		t.Errorf("expected node a to be spot, got %+v", summary.Nodes["a"])
+	}
+
+	as := summary.Autoscaler
+	if !as.Detected || as.ScaleUps != 1 || as.ScaleDowns != 1 || as.Failures != 1 || len(as.Recent) != 3 {
+		t.Errorf("unexpected autoscaler activity: %+v", as)
+	}
+	if as.Recent[0].Reason != "ScaleDownEmpty" || as.LastScaleDown == nil || !as.LastScaleDown.Equal(now) {
+		t.Errorf("recent events should be newest first: %+v", as.Recent)
+	}
+}
@@ -51,6 +51,9 @@ type ClusterInfo struct {
 	NamespaceCount     int    `json:"namespaceCount"`
 	InCluster          bool   `json:"inCluster"`                    // true when running inside a K8s cluster
 	CRDDiscoveryStatus string `json:"crdDiscoveryStatus,omitempty"` // idle, discovering, ready
+
+	ControlPlane *ControlPlaneHealth `json:"controlPlane,omitempty"` // Only populated by the cluster-info endpoint
+	Cloud        *CloudSummary       `json:"cloud,omitempty"`        // EKS/GKE/AKS node groups and autoscaler activity; cluster-info endpoint only
 }
 
 // GetClusterInfo returns detected cluster information
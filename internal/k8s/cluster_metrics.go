@@ -0,0 +1,178 @@
+package k8s
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// clusterMetricsSize is the number of cluster samples to keep (24 hours at 30s intervals)
+	clusterMetricsSize = int(MetricsHistoryMaxDuration / MetricsPollInterval)
+	// DefaultClusterMetricsPoints is the number of points returned when the caller doesn't ask for a count
+	DefaultClusterMetricsPoints = 60
+	// MaxClusterMetricsPoints caps the number of points a series can be downsampled to
+	MaxClusterMetricsPoints = 500
+)
+
+// ClusterMetricsDataPoint is a cluster-wide usage, requests, and capacity sample
+type ClusterMetricsDataPoint struct {
+	Timestamp      time.Time `json:"timestamp"`
+	CPUUsage       int64     `json:"cpuUsage"`       // Millicores
+	CPURequests    int64     `json:"cpuRequests"`    // Millicores
+	CPUCapacity    int64     `json:"cpuCapacity"`    // Millicores
+	MemoryUsage    int64     `json:"memoryUsage"`    // Bytes
+	MemoryRequests int64     `json:"memoryRequests"` // Bytes
+	MemoryCapacity int64     `json:"memoryCapacity"` // Bytes
+}
+
+// ClusterMetricsHistory is a downsampled cluster-wide series
+type ClusterMetricsHistory struct {
+	Step       string                    `json:"step"` // Width of each point's bucket
+	DataPoints []ClusterMetricsDataPoint `json:"dataPoints"`
+}
+
+// clusterMetricsBuffer is a fixed-size circular buffer of cluster samples
+type clusterMetricsBuffer struct {
+	data  []ClusterMetricsDataPoint
+	head  int
+	count int
+}
+
+func (b *clusterMetricsBuffer) Add(point ClusterMetricsDataPoint) {
+	if b.data == nil {
+		b.data = make([]ClusterMetricsDataPoint, clusterMetricsSize)
+	}
+	b.data[b.head] = point
+	b.head = (b.head + 1) % len(b.data)
+	if b.count < len(b.data) {
+		b.count++
+	}
+}
+
+// GetAll returns the buffered samples, oldest first
+func (b *clusterMetricsBuffer) GetAll() []ClusterMetricsDataPoint {
+	result := make([]ClusterMetricsDataPoint, b.count)
+	start := (b.head - b.count + len(b.data)) % max(len(b.data), 1)
+	for i := 0; i < b.count; i++ {
+		result[i] = b.data[(start+i)%len(b.data)]
+	}
+	return result
+}
+
+// collectClusterMetrics records cluster totals from the node samples taken at
+// now plus requests and capacity from the resource cache, returning the
+// recorded sample for persistence. Caller must hold s.mu.
+func (s *MetricsHistoryStore) collectClusterMetrics(now time.Time) *ClusterMetricsDataPoint {
+	point := ClusterMetricsDataPoint{Timestamp: now}
+	sampled := false
+	for _, nodeBuf := range s.nodeMetrics {
+		raw := nodeBuf.series.raw.GetAll()
+		if len(raw) == 0 || !raw[len(raw)-1].Timestamp.Equal(now) {
+			continue
+		}
+		point.CPUUsage += raw[len(raw)-1].CPU / 1000000 // nanocores to millicores
+		point.MemoryUsage += raw[len(raw)-1].Memory
+		sampled = true
+	}
+	// Without metrics-server there's no usage to plot
+	if !sampled {
+		return nil
+	}
+
+	cache := GetResourceCache()
+	if cache == nil {
+		return nil
+	}
+	if nodeLister := cache.Nodes(); nodeLister != nil {
+		nodes, _ := nodeLister.List(labels.Everything())
+		for _, n := range nodes {
+			point.CPUCapacity += n.Status.Capacity.Cpu().MilliValue()
+			point.MemoryCapacity += n.Status.Capacity.Memory().Value()
+		}
+	}
+	if podLister := cache.Pods(); podLister != nil {
+		pods, _ := podLister.List(labels.Everything())
+		for _, pod := range pods {
+			// Completed pods no longer hold their requests
+			if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			for _, c := range pod.Spec.Containers {
+				if cpu, ok := c.Resources.Requests[corev1.ResourceCPU]; ok {
+					point.CPURequests += cpu.MilliValue()
+				}
+				if mem, ok := c.Resources.Requests[corev1.ResourceMemory]; ok {
+					point.MemoryRequests += mem.Value()
+				}
+			}
+		}
+	}
+
+	s.clusterMetrics.Add(point)
+	return &point
+}
+
+// GetClusterMetricsHistory returns cluster samples from the last window,
+// averaged into at most points evenly sized buckets
+func (s *MetricsHistoryStore) GetClusterMetricsHistory(window time.Duration, points int) *ClusterMetricsHistory {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	samples := s.clusterMetrics.GetAll()
+	s.mu.RUnlock()
+
+	step := max(window/time.Duration(max(points, 1)), MetricsPollInterval)
+	return &ClusterMetricsHistory{
+		Step:       step.String(),
+		DataPoints: downsampleClusterMetrics(samples, time.Now().Add(-window), step),
+	}
+}
+
+// downsampleClusterMetrics averages samples at or after since into step-wide
+// buckets. Buckets without samples are omitted; each point is stamped with
+// its bucket's start.
+func downsampleClusterMetrics(samples []ClusterMetricsDataPoint, since time.Time, step time.Duration) []ClusterMetricsDataPoint {
+	result := []ClusterMetricsDataPoint{}
+	var sum ClusterMetricsDataPoint
+	var n int64
+	var bucket time.Time
+
+	flush := func() {
+		if n == 0 {
+			return
+		}
+		result = append(result, ClusterMetricsDataPoint{
+			Timestamp:      bucket,
+			CPUUsage:       sum.CPUUsage / n,
+			CPURequests:    sum.CPURequests / n,
+			CPUCapacity:    sum.CPUCapacity / n,
+			MemoryUsage:    sum.MemoryUsage / n,
+			MemoryRequests: sum.MemoryRequests / n,
+			MemoryCapacity: sum.MemoryCapacity / n,
+		})
+		sum, n = ClusterMetricsDataPoint{}, 0
+	}
+
+	for _, p := range samples {
+		if p.Timestamp.Before(since) {
+			continue
+		}
+		b := since.Add(p.Timestamp.Sub(since) / step * step)
+		if !b.Equal(bucket) {
+			flush()
+			bucket = b
+		}
+		sum.CPUUsage += p.CPUUsage
+		sum.CPURequests += p.CPURequests
+		sum.CPUCapacity += p.CPUCapacity
+		sum.MemoryUsage += p.MemoryUsage
+		sum.MemoryRequests += p.MemoryRequests
+		sum.MemoryCapacity += p.MemoryCapacity
+		n++
+	}
+	flush()
+	return result
+}
@@ -0,0 +1,50 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDownsampleClusterMetrics(t *testing.T) {
+	since := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	var samples []ClusterMetricsDataPoint
+	// One sample before the window, then 20 minutes of 30s samples with a gap
+	samples = append(samples, ClusterMetricsDataPoint{Timestamp: since.Add(-time.Minute), CPUUsage: 9999})
+	for i := 0; i < 40; i++ {
+		ts := since.Add(time.Duration(i) * MetricsPollInterval)
+		if ts.Sub(since) >= 5*time.Minute && ts.Sub(since) < 10*time.Minute {
+			continue
+		}
+		samples = append(samples, ClusterMetricsDataPoint{Timestamp: ts, CPUUsage: int64(i), CPUCapacity: 4000, MemoryUsage: 1 << 30})
+	}
+
+	got := downsampleClusterMetrics(samples, since, 5*time.Minute)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 buckets (empty one omitted), got %d: %+v", len(got), got)
+	}
+	if !got[0].Timestamp.Equal(since) || got[0].CPUUsage != 4 || got[0].CPUCapacity != 4000 || got[0].MemoryUsage != 1<<30 {
+		t.Errorf("unexpected first bucket: %+v", got[0])
+	}
+	if !got[1].Timestamp.Equal(since.Add(10*time.Minute)) || got[1].CPUUsage != 24 {
+		t.Errorf("unexpected second bucket: %+v", got[1])
+	}
+}
+
+func TestClusterMetricsBufferWraps(t *testing.T) {
+	var b clusterMetricsBuffer
+	if got := b.GetAll(); len(got) != 0 {
+		t.Fatalf("expected empty buffer, got %d points", len(got))
+	}
+
+	start := time.Now()
+	for i := 0; i < clusterMetricsSize+10; i++ {
+		b.Add(ClusterMetricsDataPoint{Timestamp: start.Add(time.Duration(i) * time.Second), CPUUsage: int64(i)})
+	}
+	got := b.GetAll()
+	if len(got) != clusterMetricsSize {
+		t.Fatalf("expected %d points, got %d", clusterMetricsSize, len(got))
+	}
+	if got[0].CPUUsage != 10 || got[len(got)-1].CPUUsage != int64(clusterMetricsSize+9) {
+		t.Errorf("expected oldest points to be overwritten, got first=%d last=%d", got[0].CPUUsage, got[len(got)-1].CPUUsage)
+	}
+}
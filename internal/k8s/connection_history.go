@@ -0,0 +1,197 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	connectionHistorySize = 200              // Transitions kept across all contexts
+	flapWindow            = 10 * time.Minute // Window in which repeated drops count as flapping
+	flapThreshold         = 3                // Drops from connected within flapWindow
+
+	apiServerProbeInterval = 15 * time.Second
+	apiServerProbeTimeout  = 5 * time.Second
+	apiServerProbeFailures = 2 // Consecutive failed probes before reporting a disconnect
+)
+
+// ConnectionTransition is one change of connection state for a context
+type ConnectionTransition struct {
+	Time      time.Time       `json:"time"`
+	TimeUnix  int64           `json:"timeUnix"`
+	Context   string          `json:"context"`
+	From      ConnectionState `json:"from,omitempty"`
+	To        ConnectionState `json:"to"`
+	Reason    string          `json:"reason,omitempty"`
+	ErrorType string          `json:"errorType,omitempty"`
+}
+
+// connectionHistory keeps recent state transitions, oldest first
+type connectionHistory struct {
+	mu      sync.Mutex
+	entries []ConnectionTransition
+	last    ConnectionStatus // Last recorded status, to detect changes
+}
+
+var connHistory = &connectionHistory{}
+
+// record adds a transition when the state or context changed. Progress
+// updates while connecting aren't transitions.
+func (h *connectionHistory) record(status ConnectionStatus, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if status.State == h.last.State && status.Context == h.last.Context {
+		return
+	}
+	from := h.last.State
+	if status.Context != h.last.Context {
+		from = ""
+	}
+	h.entries = append(h.entries, ConnectionTransition{
+		Time: now, TimeUnix: now.Unix(), Context: status.Context, From: from, To: status.State,
+		Reason: status.Error, ErrorType: status.ErrorType,
+	})
+	if over := len(h.entries) - connectionHistorySize; over > 0 {
+		h.entries = append(h.entries[:0], h.entries[over:]...)
+	}
+	h.last = status
+}
+
+// list returns the transitions for a context, or all when contextName is empty
+func (h *connectionHistory) list(contextName string) []ConnectionTransition {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	result := make([]ConnectionTransition, 0, len(h.entries))
+	for _, t := range h.entries {
+		if contextName == "" || t.Context == contextName {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// flapping reports whether a context dropped from connected at least
+// flapThreshold times within flapWindow
+func (h *connectionHistory) flapping(contextName string, now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	drops := 0
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		t := h.entries[i]
+		if now.Sub(t.Time) > flapWindow {
+			break
+		}
+		if t.Context == contextName && t.From == StateConnected && t.To == StateDisconnected {
+			drops++
+		}
+	}
+	return drops >= flapThreshold
+}
+
+// GetConnectionHistory returns recent connection state transitions for a
+// context (all contexts when empty), oldest first
+func GetConnectionHistory(contextName string) []ConnectionTransition {
+	return connHistory.list(contextName)
+}
+
+// ConnectionFlapping reports whether a context's connection keeps dropping
+func ConnectionFlapping(contextName string) bool {
+	return connHistory.flapping(contextName, time.Now())
+}
+
+var (
+	apiServerMonitorOnce sync.Once
+	apiServerProbeDown   atomic.Bool // The current disconnect was reported by the monitor
+)
+
+// RecoveringInPlace reports whether the current disconnect came from the API
+// server monitor, which restores the connection itself once the API server
+// answers again (informers keep running meanwhile)
+func RecoveringInPlace() bool {
+	return apiServerProbeDown.Load()
+}
+
+// startAPIServerMonitor probes the API server while connected, so an API
+// server that stops answering shows up as a disconnect (and recovers on its
+// own) instead of the UI silently going stale. It only undoes disconnects it
+// reported itself; connects, retries and context switches stay in charge of
+// everything else.
+func startAPIServerMonitor() {
+	apiServerMonitorOnce.Do(func() {
+		go func() {
+			failures := 0
+			probeDown := false // The current disconnect was reported by the probe
+			setDown := func(down bool) {
+				probeDown = down
+				apiServerProbeDown.Store(down)
+			}
+			ticker := time.NewTicker(apiServerProbeInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				status := GetConnectionStatus()
+				if IsDemoMode() || (status.State != StateConnected && !probeDown) {
+					failures = 0
+					setDown(false)
+					continue
+				}
+				if probeDown && status.State != StateDisconnected {
+					// Someone else (retry, context switch) took over
+					failures = 0
+					setDown(false)
+					continue
+				}
+
+				err := ProbeAPIServer()
+				switch {
+				case err == nil && probeDown:
+					log.Printf("[connection] API server for %s is answering again", status.Context)
+					SetConnectionStatus(ConnectionStatus{
+						State:       StateConnected,
+						Context:     status.Context,
+						ClusterName: GetClusterName(),
+					})
+					failures = 0
+					setDown(false)
+				case err == nil:
+					failures = 0
+				case !probeDown:
+					failures++
+					if failures < apiServerProbeFailures {
+						continue
+					}
+					log.Printf("[connection] API server for %s not answering: %v", status.Context, err)
+					setDown(true)
+					SetConnectionStatus(ConnectionStatus{
+						State:       StateDisconnected,
+						Context:     status.Context,
+						ClusterName: status.ClusterName,
+						Error:       fmt.Sprintf("API server not responding: %v", err),
+						ErrorType:   ClassifyError(err),
+					})
+				}
+			}
+		}()
+	})
+}
+
+// ProbeAPIServer checks that the API server answers. A 403 still proves it's
+// reachable.
+func ProbeAPIServer() error {
+	client := GetClient()
+	if client == nil {
+		return fmt.Errorf("kubernetes client not initialized")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), apiServerProbeTimeout)
+	defer cancel()
+	_, err := client.Discovery().RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
+	if apierrors.IsForbidden(err) {
+		return nil
+	}
+	return err
+}
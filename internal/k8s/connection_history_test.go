@@ -0,0 +1,63 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConnectionHistoryRecordsTransitions(t *testing.T) {
+	h := &connectionHistory{}
+	now := time.Unix(1700000000, 0)
+
+	h.record(ConnectionStatus{State: StateConnecting, Context: "prod"}, now)
+	h.record(ConnectionStatus{State: StateConnecting, Context: "prod", ProgressMsg: "Loading CRDs"}, now)
+	h.record(ConnectionStatus{State: StateConnected, Context: "prod"}, now)
+	h.record(ConnectionStatus{State: StateDisconnected, Context: "prod", Error: "dial tcp: i/o timeout", ErrorType: "network"}, now)
+	h.record(ConnectionStatus{State: StateConnecting, Context: "dev"}, now)
+
+	got := h.list("prod")
+	if len(got) != 3 {
+		t.Fatalf("expected progress updates to be ignored, got %d transitions: %+v", len(got), got)
+	}
+	if last := got[2]; last.From != StateConnected || last.To != StateDisconnected || last.Reason != "dial tcp: i/o timeout" || last.ErrorType != "network" {
+		t.Errorf("unexpected transition %+v", last)
+	}
+	if all := h.list(""); len(all) != 4 || all[3].From != "" {
+		t.Errorf("expected a context change to start without a from state, got %+v", all)
+	}
+}
+
+func TestConnectionHistoryFlapping(t *testing.T) {
+	h := &connectionHistory{}
+	start := time.Unix(1700000000, 0)
+
+	for i := range 3 {
+		at := start.Add(time.Duration(i) * time.Minute)
+		h.record(ConnectionStatus{State: StateConnected, Context: "prod"}, at)
+		h.record(ConnectionStatus{State: StateDisconnected, Context: "prod"}, at.Add(30*time.Second))
+	}
+	if !h.flapping("prod", start.Add(3*time.Minute)) {
+		t.Error("expected three drops in three minutes to count as flapping")
+	}
+	if h.flapping("dev", start.Add(3*time.Minute)) {
+		t.Error("expected other contexts not to be flapping")
+	}
+	if h.flapping("prod", start.Add(flapWindow+2*time.Minute)) {
+		t.Error("expected drops outside the window to be forgotten")
+	}
+}
+
+func TestConnectionHistoryBounded(t *testing.T) {
+	h := &connectionHistory{}
+	now := time.Unix(1700000000, 0)
+	for i := range connectionHistorySize + 10 {
+		state := StateConnected
+		if i%2 == 1 {
+			state = StateDisconnected
+		}
+		h.record(ConnectionStatus{State: state, Context: "prod"}, now)
+	}
+	if got := len(h.list("")); got != connectionHistorySize {
+		t.Errorf("expected history to be capped at %d, got %d", connectionHistorySize, got)
+	}
+}
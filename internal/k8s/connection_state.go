@@ -3,6 +3,7 @@ package k8s
 import (
 	"strings"
 	"sync"
+	"time"
 )
 
 // ConnectionState represents the current connection status to the cluster
@@ -16,12 +17,15 @@ const (
 
 // ConnectionStatus holds detailed information about the cluster connection
 type ConnectionStatus struct {
-	State       ConnectionState `json:"state"`
-	Context     string          `json:"context"`
-	ClusterName string          `json:"clusterName,omitempty"`
-	Error       string          `json:"error,omitempty"`
-	ErrorType   string          `json:"errorType,omitempty"` // auth, network, timeout, unknown
-	ProgressMsg string          `json:"progressMessage,omitempty"`
+	State        ConnectionState `json:"state"`
+	Context      string          `json:"context"`
+	ClusterName  string          `json:"clusterName,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	ErrorType    string          `json:"errorType,omitempty"` // auth, network, timeout, unknown
+	ProgressMsg  string          `json:"progressMessage,omitempty"`
+	TokenExpiry  *time.Time      `json:"tokenExpiry,omitempty"`  // When the cluster token expires, if known
+	TokenWarning string          `json:"tokenWarning,omitempty"` // Set within credentialWarnBefore of expiry
+	Flapping     bool            `json:"flapping,omitempty"`     // Repeatedly dropped within flapWindow
 }
 
 // ConnectionChangeCallback is called when the connection status changes
@@ -37,12 +41,20 @@ var (
 // GetConnectionStatus returns the current connection status
 func GetConnectionStatus() ConnectionStatus {
 	connectionStatusMu.RLock()
-	defer connectionStatusMu.RUnlock()
-	return connectionStatus
+	status := connectionStatus
+	connectionStatusMu.RUnlock()
+	status.TokenExpiry = GetCredentialExpiry()
+	status.TokenWarning = credentialWarning(status.TokenExpiry, time.Now())
+	status.Flapping = connHistory.flapping(status.Context, time.Now())
+	return status
 }
 
 // SetConnectionStatus updates the connection status and notifies callbacks
 func SetConnectionStatus(status ConnectionStatus) {
+	status.TokenExpiry = GetCredentialExpiry()
+	status.TokenWarning = credentialWarning(status.TokenExpiry, time.Now())
+	connHistory.record(status, time.Now())
+	status.Flapping = connHistory.flapping(status.Context, time.Now())
 	connectionStatusMu.Lock()
 	connectionStatus = status
 	connectionStatusMu.Unlock()
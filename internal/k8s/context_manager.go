@@ -148,6 +148,7 @@ func PerformContextSwitch(newContext string) error {
 	// Step 1: Tear down all subsystems
 	reportProgress("Stopping caches...")
 	ResetAllSubsystems()
+	startupTrace.restart()
 
 	// Step 2: Switch the K8s client to the new context
 	reportProgress("Connecting to cluster...")
@@ -166,7 +167,10 @@ func PerformContextSwitch(newContext string) error {
 	log.Println("Testing cluster connectivity...")
 	connCtx, connCancel := context.WithTimeout(context.Background(), ConnectionTestTimeout)
 	defer connCancel()
-	if err := TestClusterConnection(connCtx); err != nil {
+	endConnect := TraceStartupPhase("cluster-access")
+	err := TestClusterConnection(connCtx)
+	endConnect(err)
+	if err != nil {
 		return fmt.Errorf("cluster connection failed: %w", err)
 	}
 	log.Println("Cluster connectivity verified")
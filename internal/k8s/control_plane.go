@@ -0,0 +1,297 @@
+package k8s
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Control plane component names reported in ControlPlaneHealth
+const (
+	ComponentAPIServer         = "apiserver"
+	ComponentScheduler         = "scheduler"
+	ComponentControllerManager = "controller-manager"
+	ComponentEtcd              = "etcd"
+)
+
+// Control plane component health states
+const (
+	ComponentHealthy   = "healthy"
+	ComponentDegraded  = "degraded"
+	ComponentUnhealthy = "unhealthy"
+	ComponentUnknown   = "unknown"
+)
+
+// ControlPlanePod is a control plane static pod (kubeadm, kind, minikube, k3s-style clusters)
+type ControlPlanePod struct {
+	Name     string `json:"name"`
+	Node     string `json:"node"`
+	Ready    bool   `json:"ready"`
+	Restarts int32  `json:"restarts"`
+}
+
+// ControlPlaneComponent is the health of one control plane component
+type ControlPlaneComponent struct {
+	Name    string            `json:"name"`
+	Status  string            `json:"status"`            // healthy, degraded, unhealthy, unknown
+	Message string            `json:"message,omitempty"` // Failure detail, if any
+	Sources []string          `json:"sources"`           // Where health came from: readyz, pods, componentstatus, lease
+	Pods    []ControlPlanePod `json:"pods,omitempty"`
+	Leader  string            `json:"leader,omitempty"` // Lease holder, for leader-elected components
+}
+
+// ControlPlaneHealth summarizes control plane health as far as it is visible
+// from the API. Managed clusters (GKE, EKS, AKS) hide control plane pods, in
+// which case only the API server and possibly leader leases are reported.
+type ControlPlaneHealth struct {
+	SelfManaged bool                    `json:"selfManaged"` // Control plane pods are visible in kube-system
+	Components  []ControlPlaneComponent `json:"components"`
+}
+
+// controlPlaneComponentLabels maps the kubeadm "component" pod label to our component names
+var controlPlaneComponentLabels = map[string]string{
+	"kube-apiserver":          ComponentAPIServer,
+	"kube-scheduler":          ComponentScheduler,
+	"kube-controller-manager": ComponentControllerManager,
+	"etcd":                    ComponentEtcd,
+}
+
+// controlPlaneLeases maps leader-elected components to their lease in kube-system
+var controlPlaneLeases = map[string]string{
+	ComponentScheduler:         "kube-scheduler",
+	ComponentControllerManager: "kube-controller-manager",
+}
+
+const controlPlaneCacheTTL = 30 * time.Second
+
+var (
+	controlPlaneMu      sync.Mutex
+	controlPlaneCached  *ControlPlaneHealth
+	controlPlaneContext string
+	controlPlaneAt      time.Time
+)
+
+// GetControlPlaneHealth returns control plane health, cached briefly because
+// it issues several API calls and cluster-info is polled during CRD discovery.
+func GetControlPlaneHealth(ctx context.Context) *ControlPlaneHealth {
+	contextName := GetContextName()
+
+	controlPlaneMu.Lock()
+	defer controlPlaneMu.Unlock()
+	if controlPlaneCached != nil && controlPlaneContext == contextName && time.Since(controlPlaneAt) < controlPlaneCacheTTL {
+		return controlPlaneCached
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	health := collectControlPlaneHealth(ctx)
+	controlPlaneCached = health
+	controlPlaneContext = contextName
+	controlPlaneAt = time.Now()
+	return health
+}
+
+func collectControlPlaneHealth(ctx context.Context) *ControlPlaneHealth {
+	components := map[string]*ControlPlaneComponent{}
+	get := func(name string) *ControlPlaneComponent {
+		if c, ok := components[name]; ok {
+			return c
+		}
+		c := &ControlPlaneComponent{Name: name, Status: ComponentUnknown, Sources: []string{}}
+		components[name] = c
+		return c
+	}
+
+	client := GetClient()
+
+	// API server: if readyz answers we're talking to a live apiserver
+	if client != nil {
+		apiserver := get(ComponentAPIServer)
+		apiserver.Sources = append(apiserver.Sources, "readyz")
+		var body []byte
+		var err error
+		// The demo cluster has no apiserver to probe and is always ready
+		if !demoMode {
+			body, err = client.Discovery().RESTClient().Get().AbsPath("/readyz").DoRaw(ctx)
+		}
+		if err != nil {
+			apiserver.Status = ComponentUnhealthy
+			apiserver.Message = strings.TrimSpace(string(body))
+			if apiserver.Message == "" {
+				apiserver.Message = err.Error()
+			}
+		} else {
+			apiserver.Status = ComponentHealthy
+		}
+	}
+
+	// Static control plane pods (kubeadm labels them component=<name>, tier=control-plane)
+	health := &ControlPlaneHealth{Components: []ControlPlaneComponent{}}
+	for _, pod := range listControlPlanePods() {
+		name, ok := controlPlaneComponentLabels[pod.Labels["component"]]
+		if !ok {
+			continue
+		}
+		health.SelfManaged = true
+		c := get(name)
+		if len(c.Pods) == 0 {
+			c.Sources = append(c.Sources, "pods")
+		}
+		c.Pods = append(c.Pods, controlPlanePodStatus(pod))
+	}
+
+	// componentstatuses is deprecated but still served by many self-managed clusters
+	if client != nil {
+		//nolint:staticcheck // ComponentStatus is deprecated but the only API-level health for scheduler/etcd on some clusters
+		if list, err := client.CoreV1().ComponentStatuses().List(ctx, metav1.ListOptions{}); err == nil {
+			for _, cs := range list.Items {
+				name := componentStatusName(cs.Name)
+				if name == "" {
+					continue
+				}
+				c := get(name)
+				if !slices.Contains(c.Sources, "componentstatus") {
+					c.Sources = append(c.Sources, "componentstatus")
+				}
+				for _, cond := range cs.Conditions {
+					if cond.Type == corev1.ComponentHealthy && cond.Status != corev1.ConditionTrue && c.Message == "" {
+						c.Message = cond.Message
+						if c.Message == "" {
+							c.Message = cond.Error
+						}
+					}
+				}
+				if c.Status == ComponentUnknown || c.Status == ComponentHealthy {
+					c.Status = componentStatusHealth(cs)
+				}
+			}
+		}
+	}
+
+	// Pod readiness feeds into status where componentstatus didn't say otherwise
+	for _, c := range components {
+		if len(c.Pods) == 0 {
+			continue
+		}
+		ready := 0
+		for _, p := range c.Pods {
+			if p.Ready {
+				ready++
+			}
+		}
+		podStatus := ComponentHealthy
+		switch {
+		case ready == 0:
+			podStatus = ComponentUnhealthy
+		case ready < len(c.Pods):
+			podStatus = ComponentDegraded
+		}
+		if worseComponentStatus(podStatus, c.Status) {
+			c.Status = podStatus
+		}
+	}
+
+	// Leader identity from coordination leases
+	if client != nil {
+		for name, lease := range controlPlaneLeases {
+			l, err := client.CoordinationV1().Leases("kube-system").Get(ctx, lease, metav1.GetOptions{})
+			if err != nil || l.Spec.HolderIdentity == nil {
+				continue
+			}
+			// Holder identity is "<node>_<uuid>" for kube-scheduler and kube-controller-manager
+			holder, _, _ := strings.Cut(*l.Spec.HolderIdentity, "_")
+			c := get(name)
+			c.Leader = holder
+			c.Sources = append(c.Sources, "lease")
+			// A recently renewed lease means the component is running, even when its pods are hidden
+			if c.Status == ComponentUnknown && l.Spec.RenewTime != nil && l.Spec.LeaseDurationSeconds != nil {
+				if time.Since(l.Spec.RenewTime.Time) < 2*time.Duration(*l.Spec.LeaseDurationSeconds)*time.Second {
+					c.Status = ComponentHealthy
+				} else {
+					c.Status = ComponentUnhealthy
+					c.Message = "leader lease has not been renewed since " + l.Spec.RenewTime.Format(time.RFC3339)
+				}
+			}
+		}
+	}
+
+	for _, name := range []string{ComponentAPIServer, ComponentEtcd, ComponentScheduler, ComponentControllerManager} {
+		if c, ok := components[name]; ok {
+			health.Components = append(health.Components, *c)
+		}
+	}
+	return health
+}
+
+func listControlPlanePods() []*corev1.Pod {
+	cache := GetResourceCache()
+	if cache == nil {
+		return nil
+	}
+	podLister := cache.Pods()
+	if podLister == nil {
+		return nil
+	}
+	pods, err := podLister.Pods("kube-system").List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+	return pods
+}
+
+func controlPlanePodStatus(pod *corev1.Pod) ControlPlanePod {
+	p := ControlPlanePod{Name: pod.Name, Node: pod.Spec.NodeName}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+			p.Ready = true
+		}
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		p.Restarts += cs.RestartCount
+	}
+	return p
+}
+
+// componentStatusName maps ComponentStatus names (scheduler, controller-manager, etcd-0) to component names
+func componentStatusName(name string) string {
+	switch {
+	case name == "scheduler":
+		return ComponentScheduler
+	case name == "controller-manager":
+		return ComponentControllerManager
+	case strings.HasPrefix(name, "etcd"):
+		return ComponentEtcd
+	}
+	return ""
+}
+
+func componentStatusHealth(cs corev1.ComponentStatus) string {
+	for _, cond := range cs.Conditions {
+		if cond.Type == corev1.ComponentHealthy {
+			if cond.Status == corev1.ConditionTrue {
+				return ComponentHealthy
+			}
+			return ComponentUnhealthy
+		}
+	}
+	return ComponentUnknown
+}
+
+var componentStatusRank = map[string]int{
+	ComponentUnknown:   0,
+	ComponentHealthy:   1,
+	ComponentDegraded:  2,
+	ComponentUnhealthy: 3,
+}
+
+// worseComponentStatus reports whether a is a worse (or more informative) status than b
+func worseComponentStatus(a, b string) bool {
+	return componentStatusRank[a] > componentStatusRank[b]
+}
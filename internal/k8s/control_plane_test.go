@@ -0,0 +1,47 @@
+package k8s
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestComponentStatusName(t *testing.T) {
+	tests := map[string]string{
+		"scheduler":          ComponentScheduler,
+		"controller-manager": ComponentControllerManager,
+		"etcd-0":             ComponentEtcd,
+		"etcd-1":             ComponentEtcd,
+		"something-else":     "",
+	}
+	for in, want := range tests {
+		if got := componentStatusName(in); got != want {
+			t.Errorf("componentStatusName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestControlPlanePodStatus(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "etcd-cp-1"},
+		Spec:       corev1.PodSpec{NodeName: "cp-1"},
+		Status: corev1.PodStatus{
+			Conditions:        []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			ContainerStatuses: []corev1.ContainerStatus{{RestartCount: 2}, {RestartCount: 1}},
+		},
+	}
+	got := controlPlanePodStatus(pod)
+	if !got.Ready || got.Restarts != 3 || got.Node != "cp-1" {
+		t.Errorf("controlPlanePodStatus() = %+v", got)
+	}
+}
+
+func TestWorseComponentStatus(t *testing.T) {
+	if !worseComponentStatus(ComponentDegraded, ComponentHealthy) {
+		t.Error("degraded should be worse than healthy")
+	}
+	if worseComponentStatus(ComponentHealthy, ComponentUnhealthy) {
+		t.Error("healthy should not override unhealthy")
+	}
+}
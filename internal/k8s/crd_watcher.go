@@ -0,0 +1,352 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// crdGVR is the CustomResourceDefinition resource itself
+var crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// CRD lifecycle timeline reasons
+const (
+	ReasonCRDInstalled = "CRDInstalled"
+	ReasonCRDUpgraded  = "CRDUpgraded"
+	ReasonCRDRemoved   = "CRDRemoved"
+)
+
+// crdVersion is the subset of a CRD version we track for lifecycle changes
+type crdVersion struct {
+	Name    string
+	Served  bool
+	Storage bool
+	Columns []PrinterColumn // additionalPrinterColumns
+}
+
+// crdInfo is the subset of a CustomResourceDefinition we care about, parsed from unstructured
+type crdInfo struct {
+	Name        string
+	UID         string
+	Group       string
+	Kind        string
+	Plural      string
+	Versions    []crdVersion
+	Established bool
+}
+
+func parseCRD(obj any) (*crdInfo, bool) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		if tombstone, isTombstone := obj.(cache.DeletedFinalStateUnknown); isTombstone {
+			u, ok = tombstone.Obj.(*unstructured.Unstructured)
+		}
+		if !ok {
+			return nil, false
+		}
+	}
+
+	info := &crdInfo{Name: u.GetName(), UID: string(u.GetUID())}
+	info.Group, _, _ = unstructured.NestedString(u.Object, "spec", "group")
+	info.Kind, _, _ = unstructured.NestedString(u.Object, "spec", "names", "kind")
+	info.Plural, _, _ = unstructured.NestedString(u.Object, "spec", "names", "plural")
+
+	versions, _, _ := unstructured.NestedSlice(u.Object, "spec", "versions")
+	for _, v := range versions {
+		m, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(m, "name")
+		served, _, _ := unstructured.NestedBool(m, "served")
+		storage, _, _ := unstructured.NestedBool(m, "storage")
+		info.Versions = append(info.Versions, crdVersion{Name: name, Served: served, Storage: storage, Columns: parsePrinterColumns(m)})
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, c := range conditions {
+		m, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		if m["type"] == "Established" && m["status"] == "True" {
+			info.Established = true
+		}
+	}
+	return info, true
+}
+
+// storageVersion returns the version persisted in etcd
+func (c *crdInfo) storageVersion() string {
+	for _, v := range c.Versions {
+		if v.Storage {
+			return v.Name
+		}
+	}
+	return ""
+}
+
+// preferredGVR returns the most stable served version, which is what Radar watches
+func (c *crdInfo) preferredGVR() (schema.GroupVersionResource, bool) {
+	best := ""
+	for _, v := range c.Versions {
+		if v.Served && (best == "" || isMoreStableVersion(v.Name, best)) {
+			best = v.Name
+		}
+	}
+	if best == "" || c.Plural == "" {
+		return schema.GroupVersionResource{}, false
+	}
+	return schema.GroupVersionResource{Group: c.Group, Version: best, Resource: c.Plural}, true
+}
+
+// versionSummary describes versions for event messages, e.g. "v1 (storage), v1beta1, v1alpha1 (not served)"
+func (c *crdInfo) versionSummary() string {
+	parts := make([]string, 0, len(c.Versions))
+	for _, v := range c.Versions {
+		p := v.Name
+		switch {
+		case v.Storage:
+			p += " (storage)"
+		case !v.Served:
+			p += " (not served)"
+		}
+		parts = append(parts, p)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// crdVersionChanges lists version-level differences between two revisions of a CRD:
+// added/removed versions, served flips, and storage version changes.
+func crdVersionChanges(oldCRD, newCRD *crdInfo) []string {
+	oldVersions := make(map[string]crdVersion, len(oldCRD.Versions))
+	for _, v := range oldCRD.Versions {
+		oldVersions[v.Name] = v
+	}
+	newVersions := make(map[string]crdVersion, len(newCRD.Versions))
+	for _, v := range newCRD.Versions {
+		newVersions[v.Name] = v
+	}
+
+	var changes []string
+	for _, v := range newCRD.Versions {
+		old, existed := oldVersions[v.Name]
+		switch {
+		case !existed:
+			changes = append(changes, fmt.Sprintf("added version %s", v.Name))
+		case old.Served && !v.Served:
+			changes = append(changes, fmt.Sprintf("version %s no longer served", v.Name))
+		case !old.Served && v.Served:
+			changes = append(changes, fmt.Sprintf("version %s now served", v.Name))
+		}
+	}
+	var removed []string
+	for name := range oldVersions {
+		if _, ok := newVersions[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		changes = append(changes, fmt.Sprintf("removed version %s", name))
+	}
+
+	if oldStorage, newStorage := oldCRD.storageVersion(), newCRD.storageVersion(); oldStorage != newStorage && oldStorage != "" && newStorage != "" {
+		changes = append(changes, fmt.Sprintf("storage version %s → %s", oldStorage, newStorage))
+	}
+	return changes
+}
+
+// WatchCRDLifecycle watches CustomResourceDefinitions and records install,
+// upgrade, and removal as timeline events. Newly installed CRDs get a dynamic
+// informer as soon as they're Established, so they show up without a restart.
+func (d *DynamicResourceCache) WatchCRDLifecycle() {
+	if d == nil {
+		return
+	}
+	printerColumns.reset() // Columns from the previous context's CRDs
+	if err := d.probeAccess(crdGVR); err != nil {
+		log.Printf("[CRD lifecycle] No access to CustomResourceDefinitions, lifecycle events disabled: %v", err)
+		return
+	}
+
+	// Cluster-scoped and independent of the dynamic informer map, so it can't be unwatched
+	informer := dynamicinformer.NewFilteredDynamicInformer(d.client, crdGVR, metav1.NamespaceAll, 0, cache.Indexers{}, nil).Informer()
+
+	setRelistHandler(informer, "CustomResourceDefinition")
+	var synced atomic.Bool
+	registration, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			crd, ok := parseCRD(obj)
+			if !ok {
+				return
+			}
+			printerColumns.set(crd)
+			if !synced.Load() {
+				recordCRDInstalledHistorical(obj, crd)
+				return
+			}
+			if u, ok := obj.(*unstructured.Unstructured); ok && isRelistReplay("CustomResourceDefinition", u, nil, "add") {
+				return
+			}
+			recordCRDEvent(crd, timeline.EventTypeNormal, ReasonCRDInstalled,
+				fmt.Sprintf("Installed %s (%s), versions: %s", crd.Name, crd.Kind, crd.versionSummary()),
+				timeline.HealthHealthy)
+			if crd.Established {
+				d.onCRDEstablished(crd)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj any) {
+			oldCRD, ok1 := parseCRD(oldObj)
+			newCRD, ok2 := parseCRD(newObj)
+			if !ok1 || !ok2 {
+				return
+			}
+			printerColumns.set(newCRD)
+			changes := crdVersionChanges(oldCRD, newCRD)
+			if len(changes) > 0 {
+				recordCRDEvent(newCRD, timeline.EventTypeNormal, ReasonCRDUpgraded,
+					fmt.Sprintf("Upgraded %s: %s", newCRD.Name, strings.Join(changes, "; ")),
+					timeline.HealthHealthy)
+			}
+			// Only react to establishment or version changes, not routine status updates
+			if newCRD.Established && (!oldCRD.Established || len(changes) > 0) {
+				d.onCRDEstablished(newCRD)
+			}
+		},
+		DeleteFunc: func(obj any) {
+			crd, ok := parseCRD(obj)
+			if !ok {
+				return
+			}
+			printerColumns.remove(crd)
+			recordCRDEvent(crd, timeline.EventTypeWarning, ReasonCRDRemoved,
+				fmt.Sprintf("Removed %s (%s); all %s resources were deleted", crd.Name, crd.Kind, crd.Kind),
+				timeline.HealthUnknown)
+			d.onCRDRemoved(crd)
+		},
+	})
+
+	if err != nil {
+		log.Printf("[CRD lifecycle] Failed to register handler: %v", err)
+		return
+	}
+
+	go informer.Run(d.stopCh)
+	go func() {
+		// The registration syncs once this handler has received the initial list
+		if cache.WaitForCacheSync(d.stopCh, registration.HasSynced) {
+			// Adds delivered before this point are treated as pre-existing CRDs; a CRD
+			// installed in that brief window is recorded with its creation time instead
+			synced.Store(true)
+			log.Println("[CRD lifecycle] Watching CustomResourceDefinitions for install/upgrade/removal")
+		}
+	}()
+}
+
+// onCRDEstablished refreshes discovery and makes sure the dynamic cache watches
+// the CRD's preferred served version (switching versions if the old one was dropped).
+func (d *DynamicResourceCache) onCRDEstablished(crd *crdInfo) {
+	gvr, ok := crd.preferredGVR()
+	if !ok {
+		return
+	}
+
+	d.mu.RLock()
+	_, alreadyWatching := d.informers[gvr]
+	var stale []schema.GroupVersionResource
+	for watched := range d.informers {
+		if watched.Group == gvr.Group && watched.Resource == gvr.Resource && watched.Version != gvr.Version {
+			stale = append(stale, watched)
+		}
+	}
+	d.mu.RUnlock()
+
+	if alreadyWatching && len(stale) == 0 {
+		return
+	}
+
+	go func() {
+		// Discovery must know the new resource before EnsureWatching (verb check, kind lookup)
+		if discovery := GetResourceDiscovery(); discovery != nil {
+			if err := discovery.refresh(); err != nil {
+				log.Printf("[CRD lifecycle] Discovery refresh failed: %v", err)
+			}
+		}
+		for _, old := range stale {
+			servedStill := slices.ContainsFunc(crd.Versions, func(v crdVersion) bool { return v.Name == old.Version && v.Served })
+			if !servedStill {
+				_ = d.StopWatching(old)
+			}
+		}
+		if err := d.EnsureWatching(gvr); err != nil {
+			log.Printf("[CRD lifecycle] Failed to watch new CRD %s: %v", crd.Name, err)
+			return
+		}
+		notifyCRDDiscoveryComplete()
+	}()
+}
+
+// onCRDRemoved stops informers for the removed CRD and refreshes discovery
+func (d *DynamicResourceCache) onCRDRemoved(crd *crdInfo) {
+	d.mu.RLock()
+	var watched []schema.GroupVersionResource
+	for gvr := range d.informers {
+		if gvr.Group == crd.Group && gvr.Resource == crd.Plural {
+			watched = append(watched, gvr)
+		}
+	}
+	d.mu.RUnlock()
+
+	for _, gvr := range watched {
+		_ = d.StopWatching(gvr)
+	}
+
+	go func() {
+		if discovery := GetResourceDiscovery(); discovery != nil {
+			if err := discovery.refresh(); err != nil {
+				log.Printf("[CRD lifecycle] Discovery refresh failed: %v", err)
+			}
+		}
+		notifyCRDDiscoveryComplete()
+	}()
+}
+
+func recordCRDEvent(crd *crdInfo, eventType timeline.EventType, reason, message string, health timeline.HealthState) {
+	event := timeline.NewLifecycleEvent("CustomResourceDefinition", "", crd.Name, crd.UID, eventType, reason, message, health)
+	if err := timeline.RecordEventsWithBroadcast(context.Background(), []timeline.TimelineEvent{event}); err != nil {
+		log.Printf("[CRD lifecycle] Failed to record %s for %s: %v", reason, crd.Name, err)
+	}
+}
+
+// recordCRDInstalledHistorical records the install time of CRDs that existed
+// before Radar started. IDs are deterministic, so restarts don't duplicate them.
+func recordCRDInstalledHistorical(obj any, crd *crdInfo) {
+	meta, ok := obj.(metav1.Object)
+	if !ok {
+		return
+	}
+	created := meta.GetCreationTimestamp()
+	if created.IsZero() {
+		return
+	}
+	event := timeline.NewHistoricalEvent("CustomResourceDefinition", "", crd.Name,
+		created.Time, ReasonCRDInstalled,
+		fmt.Sprintf("Installed %s (%s), versions: %s", crd.Name, crd.Kind, crd.versionSummary()),
+		timeline.HealthHealthy, nil, nil)
+	if err := timeline.RecordEventsWithBroadcast(context.Background(), []timeline.TimelineEvent{event}); err != nil && DebugEvents.Load() {
+		log.Printf("[DEBUG] Failed to record historical CRD install for %s: %v", crd.Name, err)
+	}
+}
@@ -0,0 +1,61 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCRDVersionChanges(t *testing.T) {
+	base := &crdInfo{Versions: []crdVersion{
+		{Name: "v1beta1", Served: true, Storage: true},
+		{Name: "v1alpha1", Served: true},
+	}}
+
+	tests := []struct {
+		name string
+		next *crdInfo
+		want []string
+	}{
+		{
+			name: "no change",
+			next: base,
+			want: nil,
+		},
+		{
+			name: "new storage version added",
+			next: &crdInfo{Versions: []crdVersion{
+				{Name: "v1", Served: true, Storage: true},
+				{Name: "v1beta1", Served: true},
+				{Name: "v1alpha1", Served: true},
+			}},
+			want: []string{"added version v1", "storage version v1beta1 → v1"},
+		},
+		{
+			name: "served flip and removal",
+			next: &crdInfo{Versions: []crdVersion{
+				{Name: "v1beta1", Served: false, Storage: true},
+			}},
+			want: []string{"version v1beta1 no longer served", "removed version v1alpha1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := crdVersionChanges(base, tt.next)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("crdVersionChanges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCRDPreferredGVR(t *testing.T) {
+	crd := &crdInfo{Group: "example.com", Plural: "widgets", Versions: []crdVersion{
+		{Name: "v1alpha1", Served: true},
+		{Name: "v1", Served: false, Storage: true},
+		{Name: "v1beta2", Served: true},
+	}}
+	gvr, ok := crd.preferredGVR()
+	if !ok || gvr.Version != "v1beta2" || gvr.Resource != "widgets" || gvr.Group != "example.com" {
+		t.Errorf("preferredGVR() = %v, %v; want example.com/v1beta2 widgets", gvr, ok)
+	}
+}
@@ -0,0 +1,378 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	execPluginTimeout = 60 * time.Second // Bounds a manual run of a kubeconfig exec credential plugin
+
+	credentialWarnBefore    = 10 * time.Minute // Warn this long before the cluster token expires
+	credentialCheckInterval = 30 * time.Second
+)
+
+// Credential sources reported by RefreshCredentials
+const (
+	CredentialExec      = "exec"      // users[].user.exec credential plugin
+	CredentialToken     = "token"     // users[].user.token
+	CredentialTokenFile = "tokenFile" // users[].user.tokenFile
+)
+
+// ErrCredentialNotRefreshable is returned for credentials that can't be swapped
+// in place (client certificates, legacy auth providers); reconnect instead
+var ErrCredentialNotRefreshable = errors.New("credentials of this kind can't be refreshed in place; reconnect to pick them up")
+
+// CredentialStatus describes the bearer token in use after a refresh
+type CredentialStatus struct {
+	Source      string     `json:"source"`
+	RefreshedAt time.Time  `json:"refreshedAt"`
+	Expiry      *time.Time `json:"expiry,omitempty"` // Unknown for opaque tokens
+}
+
+// credentialState sits under client-go's auth wrappers on every client. It
+// remembers when the token in use expires, and after RefreshCredentials it
+// replaces the Authorization header with the refreshed token, so long-running
+// informers switch credentials without being rebuilt. The override is dropped
+// once the refreshed token expires or client-go sends new credentials of its
+// own (e.g. its exec plugin ran again), so client-go's refresh takes over.
+type credentialState struct {
+	mu         sync.RWMutex
+	token      string // Refreshed token; empty = the kubeconfig's own credentials
+	expiry     time.Time
+	observed   string // Last Authorization header seen, to parse each token once
+	underlying string // client-go's header while the override is in place
+}
+
+var credentials = &credentialState{}
+
+func (c *credentialState) wrap(next http.RoundTripper) http.RoundTripper {
+	return &credentialRoundTripper{state: c, next: next}
+}
+
+func (c *credentialState) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = ""
+	c.expiry = time.Time{}
+	c.observed = ""
+	c.underlying = ""
+}
+
+// seed takes the expiry from the kubeconfig's own token, before any request
+// has been made: a static or file token, or a legacy OIDC auth provider's
+// id-token. Exec plugin tokens are learned from the first request.
+func (c *credentialState) seed(config *rest.Config) {
+	token := config.BearerToken
+	if config.BearerTokenFile != "" {
+		if data, err := os.ReadFile(config.BearerTokenFile); err == nil {
+			token = strings.TrimSpace(string(data))
+		}
+	}
+	if config.AuthProvider != nil && config.AuthProvider.Config["id-token"] != "" {
+		token = config.AuthProvider.Config["id-token"]
+	}
+	if expiry := jwtExpiry(token); !expiry.IsZero() {
+		c.mu.Lock()
+		c.expiry = expiry
+		c.mu.Unlock()
+	}
+}
+
+func (c *credentialState) set(token string, expiry time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+	c.expiry = expiry
+	c.observed = "Bearer " + token
+	c.underlying = ""
+}
+
+// override returns the refreshed token to send instead of header, or "" to
+// send client-go's credentials. It drops an expired override, and one that
+// client-go has since replaced with credentials of its own.
+func (c *credentialState) override(header string, now time.Time) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token == "" {
+		return ""
+	}
+	expired := !c.expiry.IsZero() && now.After(c.expiry)
+	replaced := c.underlying != "" && header != c.underlying && header != "Bearer "+c.token
+	if expired || replaced {
+		c.token = ""
+		c.observed = ""
+		c.underlying = ""
+		return ""
+	}
+	if c.underlying == "" {
+		c.underlying = header
+	}
+	return c.token
+}
+
+type candidateTokenKey struct{}
+
+// withCandidateToken makes requests on ctx use token without installing it,
+// to check a refreshed token before every client switches to it
+func withCandidateToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, candidateTokenKey{}, token)
+}
+
+// observe learns the expiry of tokens set by client-go (static, file or exec)
+func (c *credentialState) observe(header string) {
+	c.mu.RLock()
+	seen := header == c.observed
+	c.mu.RUnlock()
+	if seen {
+		return
+	}
+	expiry := time.Time{}
+	if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+		expiry = jwtExpiry(token)
+	}
+	c.mu.Lock()
+	c.observed = header
+	c.expiry = expiry
+	c.mu.Unlock()
+}
+
+type credentialRoundTripper struct {
+	state *credentialState
+	next  http.RoundTripper
+}
+
+func (rt *credentialRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if token, ok := req.Context().Value(candidateTokenKey{}).(string); ok {
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", "Bearer "+token)
+		return rt.next.RoundTrip(req)
+	}
+
+	header := req.Header.Get("Authorization")
+	if token := rt.state.override(header, time.Now()); token != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else if header != "" {
+		rt.state.observe(header)
+	}
+	return rt.next.RoundTrip(req)
+}
+
+// GetCredentialExpiry returns when the cluster token in use expires, or nil
+// when unknown (client certificates, opaque tokens)
+func GetCredentialExpiry() *time.Time {
+	credentials.mu.RLock()
+	defer credentials.mu.RUnlock()
+	if credentials.expiry.IsZero() {
+		return nil
+	}
+	expiry := credentials.expiry
+	return &expiry
+}
+
+// credentialWarning describes an upcoming or past token expiry, or returns
+// "" while the token has more than credentialWarnBefore left
+func credentialWarning(expiry *time.Time, now time.Time) string {
+	if expiry == nil {
+		return ""
+	}
+	remaining := expiry.Sub(now)
+	switch {
+	case remaining <= 0:
+		return fmt.Sprintf("Cluster credentials expired %s ago; requests will fail until they are refreshed", (-remaining).Round(time.Second))
+	case remaining <= credentialWarnBefore:
+		return fmt.Sprintf("Cluster credentials expire in %s; refresh them to avoid interruption", remaining.Round(time.Second))
+	}
+	return ""
+}
+
+var credentialMonitorOnce sync.Once
+
+// startCredentialMonitor re-broadcasts the connection status when the token
+// starts expiring soon or has expired, so clients can prompt for re-auth
+// before requests fail. It only broadcasts on level changes; clients count
+// down from tokenExpiry themselves.
+func startCredentialMonitor() {
+	credentialMonitorOnce.Do(func() {
+		go func() {
+			lastLevel := 0 // 0 = fine, 1 = expiring soon, 2 = expired
+			ticker := time.NewTicker(credentialCheckInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				level := 0
+				if expiry := GetCredentialExpiry(); expiry != nil {
+					switch remaining := time.Until(*expiry); {
+					case remaining <= 0:
+						level = 2
+					case remaining <= credentialWarnBefore:
+						level = 1
+					}
+				}
+				if level == lastLevel {
+					continue
+				}
+				lastLevel = level
+				status := GetConnectionStatus()
+				if status.TokenWarning != "" {
+					log.Printf("[credentials] %s", status.TokenWarning)
+				}
+				SetConnectionStatus(status)
+			}
+		}()
+	})
+}
+
+// RefreshCredentials re-reads the kubeconfig for the current context, re-runs
+// its exec credential plugin or reloads its token, and swaps the new token
+// into every client without restarting informers
+func RefreshCredentials(ctx context.Context) (*CredentialStatus, error) {
+	if demoMode {
+		return nil, fmt.Errorf("no credentials to refresh in demo mode")
+	}
+	if IsInCluster() {
+		return nil, fmt.Errorf("in-cluster service account tokens are rotated automatically")
+	}
+
+	config, _, err := loadContextConfig(GetContextName())
+	if err != nil {
+		return nil, err
+	}
+
+	status := &CredentialStatus{RefreshedAt: time.Now()}
+	var token string
+	var expiry time.Time
+	switch {
+	case config.ExecProvider != nil:
+		status.Source = CredentialExec
+		token, expiry, err = runExecPlugin(ctx, config.ExecProvider)
+		if err != nil {
+			return nil, err
+		}
+	case config.BearerTokenFile != "":
+		status.Source = CredentialTokenFile
+		data, err := os.ReadFile(config.BearerTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read token file: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	case config.BearerToken != "":
+		status.Source = CredentialToken
+		token = config.BearerToken
+	default:
+		return nil, ErrCredentialNotRefreshable
+	}
+	if token == "" {
+		return nil, ErrCredentialNotRefreshable
+	}
+	if expiry.IsZero() {
+		expiry = jwtExpiry(token)
+	}
+	if !expiry.IsZero() {
+		status.Expiry = &expiry
+	}
+
+	// Any authenticated user may create a SelfSubjectAccessReview, so a 401
+	// here means the new token itself was rejected. Only this request uses
+	// it; clients keep their current credentials unless it's accepted.
+	client := GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("k8s client not initialized")
+	}
+	review := &authorizationv1.SelfSubjectAccessReview{Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+		NonResourceAttributes: &authorizationv1.NonResourceAttributes{Path: "/version", Verb: "get"},
+	}}
+	if _, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(withCandidateToken(ctx, token), review, metav1.CreateOptions{}); err != nil {
+		return nil, fmt.Errorf("refreshed credentials were rejected: %w", err)
+	}
+
+	credentials.set(token, expiry)
+	return status, nil
+}
+
+// execCredential is the subset of client.authentication.k8s.io ExecCredential
+// that a token refresh needs
+type execCredential struct {
+	Status *struct {
+		Token               string     `json:"token"`
+		ExpirationTimestamp *time.Time `json:"expirationTimestamp"`
+	} `json:"status"`
+}
+
+// runExecPlugin runs a kubeconfig exec credential plugin directly, bypassing
+// client-go's cached credentials
+func runExecPlugin(ctx context.Context, provider *clientcmdapi.ExecConfig) (string, time.Time, error) {
+	ctx, cancel := context.WithTimeout(ctx, execPluginTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, provider.Command, provider.Args...)
+	cmd.Env = os.Environ()
+	for _, env := range provider.Env {
+		cmd.Env = append(cmd.Env, env.Name+"="+env.Value)
+	}
+	info, _ := json.Marshal(map[string]any{
+		"apiVersion": provider.APIVersion,
+		"kind":       "ExecCredential",
+		"spec":       map[string]any{"interactive": false},
+	})
+	cmd.Env = append(cmd.Env, "KUBERNETES_EXEC_INFO="+string(info))
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", time.Time{}, fmt.Errorf("exec plugin %s failed: %s", provider.Command, msg)
+		}
+		return "", time.Time{}, fmt.Errorf("exec plugin %s failed: %w", provider.Command, err)
+	}
+
+	var cred execCredential
+	if err := json.Unmarshal(stdout.Bytes(), &cred); err != nil {
+		return "", time.Time{}, fmt.Errorf("exec plugin %s returned invalid output: %w", provider.Command, err)
+	}
+	if cred.Status == nil || cred.Status.Token == "" {
+		return "", time.Time{}, ErrCredentialNotRefreshable
+	}
+	var expiry time.Time
+	if cred.Status.ExpirationTimestamp != nil {
+		expiry = *cred.Status.ExpirationTimestamp
+	}
+	return cred.Status.Token, expiry, nil
+}
+
+// jwtExpiry reads the exp claim of a JWT bearer token without verifying it.
+// Returns zero for tokens that aren't JWTs (e.g. EKS or GKE access tokens).
+func jwtExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if json.Unmarshal(payload, &claims) != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
@@ -0,0 +1,165 @@
+package k8s
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"runtime"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func testJWT(exp time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"alice","exp":%d}`, exp.Unix())))
+	return "eyJhbGciOiJSUzI1NiJ9." + payload + ".sig"
+}
+
+type recordingTransport struct{ header string }
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.header = req.Header.Get("Authorization")
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func TestCredentialRoundTripper(t *testing.T) {
+	state := &credentialState{}
+	next := &recordingTransport{}
+	rt := state.wrap(next)
+	exp := time.Unix(1900000000, 0)
+
+	// Tokens set by client-go pass through, and their expiry is learned
+	req, _ := http.NewRequest("GET", "https://cluster/api", nil)
+	req.Header.Set("Authorization", "Bearer "+testJWT(exp))
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	if next.header != "Bearer "+testJWT(exp) || !state.expiry.Equal(exp) {
+		t.Errorf("expected the original token with a learned expiry, got %q / %v", next.header, state.expiry)
+	}
+
+	// A refreshed token replaces the header without touching the caller's request
+	state.set("fresh", time.Time{})
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	if next.header != "Bearer fresh" || req.Header.Get("Authorization") != "Bearer "+testJWT(exp) {
+		t.Errorf("expected the refreshed token on the wire only, got %q", next.header)
+	}
+
+	state.reset()
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	if next.header != "Bearer "+testJWT(exp) {
+		t.Errorf("expected reset to restore the kubeconfig's credentials, got %q", next.header)
+	}
+}
+
+func TestCredentialOverrideHandsBack(t *testing.T) {
+	state := &credentialState{}
+	next := &recordingTransport{}
+	rt := state.wrap(next)
+	send := func(ctx context.Context, header string) string {
+		t.Helper()
+		req, _ := http.NewRequestWithContext(ctx, "GET", "https://cluster/api", nil)
+		req.Header.Set("Authorization", header)
+		if _, err := rt.RoundTrip(req); err != nil {
+			t.Fatal(err)
+		}
+		return next.header
+	}
+	ctx := context.Background()
+
+	// A candidate token is used for its request only
+	if got := send(withCandidateToken(ctx, "candidate"), "Bearer old"); got != "Bearer candidate" {
+		t.Errorf("expected the candidate token, got %q", got)
+	}
+	if got := send(ctx, "Bearer old"); got != "Bearer old" || state.token != "" {
+		t.Errorf("expected the candidate not to be installed, got %q", got)
+	}
+
+	// client-go sending new credentials of its own ends the override
+	state.set("fresh", time.Time{})
+	if got := send(ctx, "Bearer old"); got != "Bearer fresh" {
+		t.Errorf("expected the refreshed token, got %q", got)
+	}
+	if got := send(ctx, "Bearer exec-rerun"); got != "Bearer exec-rerun" {
+		t.Errorf("expected client-go's new token to take over, got %q", got)
+	}
+
+	// So does the refreshed token expiring
+	state.set("short", time.Now().Add(-time.Second))
+	if got := send(ctx, "Bearer exec-rerun"); got != "Bearer exec-rerun" || state.token != "" {
+		t.Errorf("expected an expired override to be dropped, got %q", got)
+	}
+}
+
+func TestJWTExpiry(t *testing.T) {
+	exp := time.Unix(1900000000, 0)
+	if got := jwtExpiry(testJWT(exp)); !got.Equal(exp) {
+		t.Errorf("expected %v, got %v", exp, got)
+	}
+	for _, opaque := range []string{"k8s-aws-v1.aHR0cHM6Ly9zdHM", "ya29.a0AfH6SM", ""} {
+		if got := jwtExpiry(opaque); !got.IsZero() {
+			t.Errorf("expected no expiry for %q, got %v", opaque, got)
+		}
+	}
+}
+
+func TestRunExecPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	provider := &clientcmdapi.ExecConfig{
+		Command:    "sh",
+		Args:       []string{"-c", `echo "{\"status\":{\"token\":\"$TOKEN\",\"expirationTimestamp\":\"2030-01-02T03:04:05Z\"}}"`},
+		Env:        []clientcmdapi.ExecEnvVar{{Name: "TOKEN", Value: "abc"}},
+		APIVersion: "client.authentication.k8s.io/v1",
+	}
+	token, expiry, err := runExecPlugin(context.Background(), provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "abc" || !expiry.Equal(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("unexpected credential %q expiring %v", token, expiry)
+	}
+
+	provider.Args = []string{"-c", "echo 'login required' >&2; exit 1"}
+	if _, _, err := runExecPlugin(context.Background(), provider); err == nil || err.Error() != "exec plugin sh failed: login required" {
+		t.Errorf("expected the plugin's stderr in the error, got %v", err)
+	}
+}
+
+func TestCredentialWarning(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time { e := now.Add(d); return &e }
+
+	if w := credentialWarning(nil, now); w != "" {
+		t.Errorf("expected no warning for an unknown expiry, got %q", w)
+	}
+	if w := credentialWarning(at(time.Hour), now); w != "" {
+		t.Errorf("expected no warning an hour out, got %q", w)
+	}
+	if w := credentialWarning(at(4*time.Minute), now); w != "Cluster credentials expire in 4m0s; refresh them to avoid interruption" {
+		t.Errorf("unexpected warning %q", w)
+	}
+	if w := credentialWarning(at(-30*time.Second), now); w != "Cluster credentials expired 30s ago; requests will fail until they are refreshed" {
+		t.Errorf("unexpected warning %q", w)
+	}
+}
+
+func TestCredentialSeed(t *testing.T) {
+	exp := time.Unix(1900000000, 0)
+	state := &credentialState{}
+	state.seed(&rest.Config{AuthProvider: &clientcmdapi.AuthProviderConfig{
+		Name:   "oidc",
+		Config: map[string]string{"id-token": testJWT(exp)},
+	}})
+	if !state.expiry.Equal(exp) {
+		t.Errorf("expected the OIDC id-token expiry, got %v", state.expiry)
+	}
+}
@@ -0,0 +1,180 @@
+package k8s
+
+import (
+	"context"
+	"log"
+
+	authv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/apimachinery/pkg/watch"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// DemoContextName is the context reported when running against the synthetic demo cluster
+const DemoContextName = "radar-demo"
+
+// demoMode is set when Initialize was called with InitOptions.Demo
+var demoMode bool
+
+// IsDemoMode returns true when running against the synthetic demo cluster
+func IsDemoMode() bool {
+	return demoMode
+}
+
+// initDemo backs the package clients with an in-memory fake cluster seeded
+// with sample workloads, and starts a script that keeps it changing
+func initDemo() error {
+	cluster := newDemoCluster()
+
+	client := fake.NewClientset(cluster.objects()...)
+	// Every permission check passes so all informers and actions are enabled
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authv1.SelfSubjectAccessReview)
+		review.Status.Allowed = true
+		return true, review, nil
+	})
+
+	disco := client.Discovery().(*fakediscovery.FakeDiscovery)
+	disco.Resources = demoAPIResources
+	disco.FakedServerVersion = &version.Info{Major: "1", Minor: "33", GitVersion: "v1.33.0-demo", Platform: "linux/amd64"}
+
+	// CRDs and APIServices are served empty so their watchers sync cleanly
+	dynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), demoDynamicListKinds)
+
+	demoMode = true
+	contextName = DemoContextName
+	clusterName = "demo"
+	k8sClient = client
+	discoveryClient = disco
+	dynamicClient = &demoDynamicClient{fake: dynClient}
+
+	script := &demoScript{cluster: cluster, client: client, metrics: dynClient.Tracker()}
+	script.refreshMetrics(context.Background())
+	go script.run(context.Background())
+
+	log.Printf("Running in demo mode with a synthetic cluster (%d objects)", len(cluster.objects()))
+	return nil
+}
+
+// demoAPIResources lists the resources served by the demo cluster. Only kinds
+// backed by the typed resource cache are advertised, plus metrics.k8s.io.
+var demoAPIResources = []*metav1.APIResourceList{
+	{GroupVersion: "v1", APIResources: []metav1.APIResource{
+		{Name: "pods", Kind: "Pod", Namespaced: true, Verbs: demoVerbs, ShortNames: []string{"po"}},
+		{Name: "services", Kind: "Service", Namespaced: true, Verbs: demoVerbs, ShortNames: []string{"svc"}},
+		{Name: "configmaps", Kind: "ConfigMap", Namespaced: true, Verbs: demoVerbs, ShortNames: []string{"cm"}},
+		{Name: "secrets", Kind: "Secret", Namespaced: true, Verbs: demoVerbs},
+		{Name: "events", Kind: "Event", Namespaced: true, Verbs: demoVerbs, ShortNames: []string{"ev"}},
+		{Name: "persistentvolumeclaims", Kind: "PersistentVolumeClaim", Namespaced: true, Verbs: demoVerbs, ShortNames: []string{"pvc"}},
+		{Name: "nodes", Kind: "Node", Verbs: demoVerbs, ShortNames: []string{"no"}},
+		{Name: "namespaces", Kind: "Namespace", Verbs: demoVerbs, ShortNames: []string{"ns"}},
+	}},
+	{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+		{Name: "deployments", Kind: "Deployment", Namespaced: true, Verbs: demoVerbs, ShortNames: []string{"deploy"}},
+		{Name: "replicasets", Kind: "ReplicaSet", Namespaced: true, Verbs: demoVerbs, ShortNames: []string{"rs"}},
+		{Name: "statefulsets", Kind: "StatefulSet", Namespaced: true, Verbs: demoVerbs, ShortNames: []string{"sts"}},
+		{Name: "daemonsets", Kind: "DaemonSet", Namespaced: true, Verbs: demoVerbs, ShortNames: []string{"ds"}},
+	}},
+	{GroupVersion: "batch/v1", APIResources: []metav1.APIResource{
+		{Name: "jobs", Kind: "Job", Namespaced: true, Verbs: demoVerbs},
+		{Name: "cronjobs", Kind: "CronJob", Namespaced: true, Verbs: demoVerbs, ShortNames: []string{"cj"}},
+	}},
+	{GroupVersion: "networking.k8s.io/v1", APIResources: []metav1.APIResource{
+		{Name: "ingresses", Kind: "Ingress", Namespaced: true, Verbs: demoVerbs, ShortNames: []string{"ing"}},
+	}},
+	{GroupVersion: "autoscaling/v2", APIResources: []metav1.APIResource{
+		{Name: "horizontalpodautoscalers", Kind: "HorizontalPodAutoscaler", Namespaced: true, Verbs: demoVerbs, ShortNames: []string{"hpa"}},
+	}},
+	{GroupVersion: "metrics.k8s.io/v1beta1", APIResources: []metav1.APIResource{
+		{Name: "pods", Kind: "PodMetrics", Namespaced: true, Verbs: []string{"get", "list"}},
+		{Name: "nodes", Kind: "NodeMetrics", Verbs: []string{"get", "list"}},
+	}},
+}
+
+var demoVerbs = metav1.Verbs{"get", "list", "watch", "create", "update", "patch", "delete"}
+
+// demoDynamicListKinds are the resources served by the demo dynamic client
+var demoDynamicListKinds = map[schema.GroupVersionResource]string{
+	podMetricsGVR:  "PodMetricsList",
+	nodeMetricsGVR: "NodeMetricsList",
+	crdGVR:         "CustomResourceDefinitionList",
+	apiServiceGVR:  "APIServiceList",
+}
+
+// demoDynamicClient serves demoDynamicListKinds from a fake dynamic client and
+// reports every other resource as not found, like a cluster without that API
+type demoDynamicClient struct {
+	fake *dynamicfake.FakeDynamicClient
+}
+
+func (c *demoDynamicClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	if _, ok := demoDynamicListKinds[gvr]; ok {
+		return c.fake.Resource(gvr)
+	}
+	return demoMissingResource{resource: gvr.GroupResource()}
+}
+
+// demoMissingResource answers every request with NotFound
+type demoMissingResource struct {
+	resource schema.GroupResource
+}
+
+func (r demoMissingResource) Namespace(string) dynamic.ResourceInterface { return r }
+
+func (r demoMissingResource) notFound(name string) error {
+	return apierrors.NewNotFound(r.resource, name)
+}
+
+func (r demoMissingResource) Create(_ context.Context, obj *unstructured.Unstructured, _ metav1.CreateOptions, _ ...string) (*unstructured.Unstructured, error) {
+	return nil, r.notFound(obj.GetName())
+}
+
+func (r demoMissingResource) Update(_ context.Context, obj *unstructured.Unstructured, _ metav1.UpdateOptions, _ ...string) (*unstructured.Unstructured, error) {
+	return nil, r.notFound(obj.GetName())
+}
+
+func (r demoMissingResource) UpdateStatus(_ context.Context, obj *unstructured.Unstructured, _ metav1.UpdateOptions) (*unstructured.Unstructured, error) {
+	return nil, r.notFound(obj.GetName())
+}
+
+func (r demoMissingResource) Delete(_ context.Context, name string, _ metav1.DeleteOptions, _ ...string) error {
+	return r.notFound(name)
+}
+
+func (r demoMissingResource) DeleteCollection(context.Context, metav1.DeleteOptions, metav1.ListOptions) error {
+	return r.notFound("")
+}
+
+func (r demoMissingResource) Get(_ context.Context, name string, _ metav1.GetOptions, _ ...string) (*unstructured.Unstructured, error) {
+	return nil, r.notFound(name)
+}
+
+func (r demoMissingResource) List(context.Context, metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	return nil, r.notFound("")
+}
+
+func (r demoMissingResource) Watch(context.Context, metav1.ListOptions) (watch.Interface, error) {
+	return nil, r.notFound("")
+}
+
+func (r demoMissingResource) Patch(_ context.Context, name string, _ types.PatchType, _ []byte, _ metav1.PatchOptions, _ ...string) (*unstructured.Unstructured, error) {
+	return nil, r.notFound(name)
+}
+
+func (r demoMissingResource) Apply(_ context.Context, name string, _ *unstructured.Unstructured, _ metav1.ApplyOptions, _ ...string) (*unstructured.Unstructured, error) {
+	return nil, r.notFound(name)
+}
+
+func (r demoMissingResource) ApplyStatus(_ context.Context, name string, _ *unstructured.Unstructured, _ metav1.ApplyOptions) (*unstructured.Unstructured, error) {
+	return nil, r.notFound(name)
+}
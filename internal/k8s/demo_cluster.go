@@ -0,0 +1,800 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// demoApp describes a Deployment in the synthetic cluster
+type demoApp struct {
+	namespace string
+	name      string
+	image     string
+	replicas  int
+	port      int32
+	cpu       string // request
+	memory    string // request and limit
+	crashLoop bool   // first pod is stuck in CrashLoopBackOff
+	host      string // ingress host, if exposed
+}
+
+var demoApps = []demoApp{
+	{namespace: "shop", name: "frontend", image: "ghcr.io/acme/frontend:2.4.1", replicas: 3, port: 8080, cpu: "200m", memory: "256Mi", host: "shop.demo.local"},
+	{namespace: "shop", name: "cart", image: "ghcr.io/acme/cart:1.9.0", replicas: 2, port: 8080, cpu: "100m", memory: "128Mi"},
+	{namespace: "shop", name: "catalog", image: "ghcr.io/acme/catalog:3.1.2", replicas: 2, port: 8080, cpu: "150m", memory: "256Mi"},
+	{namespace: "payments", name: "payments-api", image: "ghcr.io/acme/payments:0.14.0", replicas: 2, port: 9000, cpu: "250m", memory: "512Mi", crashLoop: true},
+	{namespace: "kube-system", name: "coredns", image: "registry.k8s.io/coredns/coredns:v1.11.3", replicas: 2, port: 53, cpu: "100m", memory: "70Mi"},
+}
+
+var demoNamespaces = []string{"default", "kube-system", "shop", "payments", "monitoring"}
+
+const (
+	demoNodeCount      = 3
+	demoScriptInterval = 15 * time.Second
+	demoMaxJobHistory  = 3
+)
+
+// demoCluster holds the seed objects for the synthetic cluster
+type demoCluster struct {
+	start   time.Time
+	objs    []runtime.Object
+	podSeq  int
+	nodeSeq int
+}
+
+func newDemoCluster() *demoCluster {
+	c := &demoCluster{start: time.Now()}
+	created := c.start.Add(-72 * time.Hour)
+
+	for _, ns := range demoNamespaces {
+		c.add(&corev1.Namespace{
+			ObjectMeta: demoMeta("", ns, map[string]string{"kubernetes.io/metadata.name": ns}, created),
+			Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+		})
+	}
+	for i := 1; i <= demoNodeCount; i++ {
+		c.add(demoNode(fmt.Sprintf("demo-node-%d", i), created))
+	}
+	for _, app := range demoApps {
+		c.addDeployment(app, created)
+	}
+	c.addPostgres(created)
+	c.addNodeExporter(created)
+	c.addCronJob(created)
+
+	c.add(&corev1.ConfigMap{
+		ObjectMeta: demoMeta("shop", "frontend-config", map[string]string{"app": "frontend"}, created),
+		Data:       map[string]string{"LOG_LEVEL": "info", "CATALOG_URL": "http://catalog.shop:8080", "CART_URL": "http://cart.shop:8080"},
+	})
+	c.add(&corev1.Secret{
+		ObjectMeta: demoMeta("payments", "payments-db", map[string]string{"app": "payments-api"}, created),
+		Type:       corev1.SecretTypeOpaque,
+		Data:       map[string][]byte{"username": []byte("payments"), "password": []byte("demo-password")},
+	})
+	minReplicas, targetCPU := int32(3), int32(70)
+	c.add(&autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: demoMeta("shop", "frontend", map[string]string{"app": "frontend"}, created),
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "frontend"},
+			MinReplicas:    &minReplicas,
+			MaxReplicas:    10,
+			Metrics: []autoscalingv2.MetricSpec{{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name:   corev1.ResourceCPU,
+					Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: &targetCPU},
+				},
+			}},
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{CurrentReplicas: 3, DesiredReplicas: 3},
+	})
+	return c
+}
+
+func (c *demoCluster) objects() []runtime.Object {
+	return c.objs
+}
+
+func (c *demoCluster) add(obj runtime.Object) {
+	c.objs = append(c.objs, obj)
+}
+
+func demoMeta(namespace, name string, lbls map[string]string, created time.Time) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace:         namespace,
+		Name:              name,
+		UID:               uuid.NewUUID(),
+		Labels:            lbls,
+		CreationTimestamp: metav1.NewTime(created),
+		ResourceVersion:   "1",
+	}
+}
+
+func demoOwner(apiVersion, kind string, owner metav1.ObjectMeta) metav1.OwnerReference {
+	controller := true
+	return metav1.OwnerReference{APIVersion: apiVersion, Kind: kind, Name: owner.Name, UID: owner.UID, Controller: &controller}
+}
+
+func demoNode(name string, created time.Time) *corev1.Node {
+	capacity := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("4"),
+		corev1.ResourceMemory: resource.MustParse("16Gi"),
+		corev1.ResourcePods:   resource.MustParse("110"),
+	}
+	return &corev1.Node{
+		ObjectMeta: demoMeta("", name, map[string]string{
+			"kubernetes.io/hostname":           name,
+			"kubernetes.io/os":                 "linux",
+			"kubernetes.io/arch":               "amd64",
+			"node.kubernetes.io/instance-type": "demo.xlarge",
+			"topology.kubernetes.io/zone":      "demo-zone-a",
+		}, created),
+		Spec: corev1.NodeSpec{ProviderID: "demo://" + name},
+		Status: corev1.NodeStatus{
+			Capacity:    capacity,
+			Allocatable: capacity,
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue, Reason: "KubeletReady", LastTransitionTime: metav1.NewTime(created)},
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse, Reason: "KubeletHasSufficientMemory"},
+				{Type: corev1.NodeDiskPressure, Status: corev1.ConditionFalse, Reason: "KubeletHasNoDiskPressure"},
+			},
+			NodeInfo: corev1.NodeSystemInfo{
+				KubeletVersion:          "v1.33.0",
+				ContainerRuntimeVersion: "containerd://1.7.24",
+				OSImage:                 "Demo Linux",
+				KernelVersion:           "6.8.0",
+				OperatingSystem:         "linux",
+				Architecture:            "amd64",
+			},
+			Addresses: []corev1.NodeAddress{{Type: corev1.NodeHostName, Address: name}},
+		},
+	}
+}
+
+func demoContainer(name, image string, port int32, cpu, memory string) corev1.Container {
+	return corev1.Container{
+		Name:  name,
+		Image: image,
+		Ports: []corev1.ContainerPort{{ContainerPort: port, Protocol: corev1.ProtocolTCP}},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)},
+		},
+		ReadinessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(port)}}},
+	}
+}
+
+// nextNode spreads pods across nodes round-robin
+func (c *demoCluster) nextNode() (string, int) {
+	c.nodeSeq++
+	idx := (c.nodeSeq-1)%demoNodeCount + 1
+	return fmt.Sprintf("demo-node-%d", idx), idx
+}
+
+// newPod builds a running pod from a template, owned by owner
+func (c *demoCluster) newPod(namespace, name string, template corev1.PodTemplateSpec, owner metav1.OwnerReference, started time.Time) *corev1.Pod {
+	c.podSeq++
+	node, nodeIdx := c.nextNode()
+	pod := &corev1.Pod{
+		ObjectMeta: demoMeta(namespace, name, template.Labels, started),
+		Spec:       *template.Spec.DeepCopy(),
+	}
+	pod.OwnerReferences = []metav1.OwnerReference{owner}
+	pod.Spec.NodeName = node
+
+	startedAt := metav1.NewTime(started.Add(5 * time.Second))
+	running := true
+	pod.Status = corev1.PodStatus{
+		Phase:     corev1.PodRunning,
+		HostIP:    fmt.Sprintf("192.168.0.%d", 10+nodeIdx),
+		PodIP:     fmt.Sprintf("10.244.%d.%d", nodeIdx, 2+c.podSeq%250),
+		StartTime: &startedAt,
+		QOSClass:  corev1.PodQOSBurstable,
+		Conditions: []corev1.PodCondition{
+			{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(started)},
+			{Type: corev1.PodInitialized, Status: corev1.ConditionTrue, LastTransitionTime: startedAt},
+			{Type: corev1.ContainersReady, Status: corev1.ConditionTrue, LastTransitionTime: startedAt},
+			{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: startedAt},
+		},
+	}
+	for _, container := range pod.Spec.Containers {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			Name:    container.Name,
+			Image:   container.Image,
+			ImageID: demoImageID(container.Image),
+			Ready:   true,
+			Started: &running,
+			State:   corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: startedAt}},
+		})
+	}
+	return pod
+}
+
+// demoImageID returns a stable fake digest for an image
+func demoImageID(image string) string {
+	h := fnv.New64a()
+	h.Write([]byte(image))
+	sum := h.Sum64()
+	return fmt.Sprintf("%s@sha256:%016x%016x%016x%016x", image, sum, sum^0xa5a5, sum^0x5a5a, sum^0xffff)
+}
+
+// setCrashLooping marks the pod's first container as crash looping
+func setCrashLooping(pod *corev1.Pod, restarts int32, now time.Time) {
+	status := &pod.Status.ContainerStatuses[0]
+	status.Ready = false
+	notStarted := false
+	status.Started = &notStarted
+	status.RestartCount = restarts
+	status.State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+		Reason:  "CrashLoopBackOff",
+		Message: fmt.Sprintf("back-off 5m0s restarting failed container=%s pod=%s", status.Name, pod.Name),
+	}}
+	status.LastTerminationState = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+		ExitCode:   1,
+		Reason:     "Error",
+		StartedAt:  metav1.NewTime(now.Add(-20 * time.Second)),
+		FinishedAt: metav1.NewTime(now),
+	}}
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == corev1.PodReady || pod.Status.Conditions[i].Type == corev1.ContainersReady {
+			pod.Status.Conditions[i].Status = corev1.ConditionFalse
+			pod.Status.Conditions[i].LastTransitionTime = metav1.NewTime(now)
+		}
+	}
+}
+
+func demoPodSuffix(seq int) string {
+	const chars = "bcdfghjklmnpqrstvwxz2456789"
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%d", seq)
+	v := h.Sum32()
+	suffix := make([]byte, 5)
+	for i := range suffix {
+		suffix[i] = chars[v%uint32(len(chars))]
+		v /= uint32(len(chars))
+	}
+	return string(suffix)
+}
+
+func (c *demoCluster) addDeployment(app demoApp, created time.Time) {
+	lbls := map[string]string{"app": app.name, "app.kubernetes.io/name": app.name}
+	replicas := int32(app.replicas)
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: lbls},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{demoContainer(app.name, app.image, app.port, app.cpu, app.memory)}},
+	}
+	if app.name == "frontend" {
+		template.Spec.Containers[0].EnvFrom = []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "frontend-config"}}}}
+	}
+
+	deploy := &appsv1.Deployment{
+		ObjectMeta: demoMeta(app.namespace, app.name, lbls, created),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app.name}},
+			Template: template,
+		},
+	}
+	deploy.Annotations = map[string]string{"deployment.kubernetes.io/revision": "1"}
+	ready := replicas
+	if app.crashLoop {
+		ready--
+	}
+	deploy.Status = demoDeploymentStatus(replicas, ready, created)
+	c.add(deploy)
+
+	hash := fmt.Sprintf("%x", fnv32(app.name))[:8]
+	rsLabels := map[string]string{"app": app.name, "app.kubernetes.io/name": app.name, "pod-template-hash": hash}
+	rsTemplate := *template.DeepCopy()
+	rsTemplate.Labels = rsLabels
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: demoMeta(app.namespace, app.name+"-"+hash, rsLabels, created),
+		Spec: appsv1.ReplicaSetSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: rsLabels},
+			Template: rsTemplate,
+		},
+		Status: appsv1.ReplicaSetStatus{Replicas: replicas, ReadyReplicas: ready, AvailableReplicas: ready, FullyLabeledReplicas: replicas},
+	}
+	rs.OwnerReferences = []metav1.OwnerReference{demoOwner("apps/v1", "Deployment", deploy.ObjectMeta)}
+	c.add(rs)
+
+	owner := demoOwner("apps/v1", "ReplicaSet", rs.ObjectMeta)
+	for i := 0; i < app.replicas; i++ {
+		pod := c.newPod(app.namespace, rs.Name+"-"+demoPodSuffix(c.podSeq+1), rsTemplate, owner, created.Add(time.Duration(i)*time.Minute))
+		if app.crashLoop && i == 0 {
+			setCrashLooping(pod, 7, c.start.Add(-time.Minute))
+			c.add(demoEvent(pod, corev1.EventTypeWarning, "BackOff", fmt.Sprintf("Back-off restarting failed container %s in pod %s", app.name, pod.Name), c.start.Add(-time.Minute)))
+		}
+		c.add(pod)
+	}
+
+	c.add(&corev1.Service{
+		ObjectMeta: demoMeta(app.namespace, app.name, lbls, created),
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceTypeClusterIP,
+			ClusterIP: fmt.Sprintf("10.96.%d.%d", fnv32(app.namespace)%200, fnv32(app.name)%250+1),
+			Selector:  map[string]string{"app": app.name},
+			Ports:     []corev1.ServicePort{{Name: "http", Port: app.port, TargetPort: intstr.FromInt32(app.port), Protocol: corev1.ProtocolTCP}},
+		},
+	})
+
+	if app.host != "" {
+		pathType := networkingv1.PathTypePrefix
+		className := "nginx"
+		c.add(&networkingv1.Ingress{
+			ObjectMeta: demoMeta(app.namespace, app.name, lbls, created),
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: &className,
+				Rules: []networkingv1.IngressRule{{
+					Host: app.host,
+					IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     "/",
+							PathType: &pathType,
+							Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+								Name: app.name,
+								Port: networkingv1.ServiceBackendPort{Number: app.port},
+							}},
+						}},
+					}},
+				}},
+			},
+		})
+	}
+}
+
+func demoDeploymentStatus(replicas, ready int32, since time.Time) appsv1.DeploymentStatus {
+	available := corev1.ConditionTrue
+	if ready < replicas {
+		available = corev1.ConditionFalse
+	}
+	return appsv1.DeploymentStatus{
+		ObservedGeneration: 1,
+		Replicas:           replicas,
+		UpdatedReplicas:    replicas,
+		ReadyReplicas:      ready,
+		AvailableReplicas:  ready,
+		Conditions: []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentAvailable, Status: available, Reason: "MinimumReplicasAvailable", LastUpdateTime: metav1.NewTime(since), LastTransitionTime: metav1.NewTime(since)},
+			{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: "NewReplicaSetAvailable", LastUpdateTime: metav1.NewTime(since), LastTransitionTime: metav1.NewTime(since)},
+		},
+	}
+}
+
+func (c *demoCluster) addPostgres(created time.Time) {
+	lbls := map[string]string{"app": "postgres", "app.kubernetes.io/name": "postgres"}
+	replicas := int32(1)
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: lbls},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{demoContainer("postgres", "postgres:16.4", 5432, "500m", "1Gi")}},
+	}
+	template.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{{Name: "data", MountPath: "/var/lib/postgresql/data"}}
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: demoMeta("payments", "postgres", lbls, created),
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    &replicas,
+			ServiceName: "postgres",
+			Selector:    &metav1.LabelSelector{MatchLabels: lbls},
+			Template:    template,
+		},
+		Status: appsv1.StatefulSetStatus{Replicas: 1, ReadyReplicas: 1, AvailableReplicas: 1, CurrentReplicas: 1, UpdatedReplicas: 1},
+	}
+	c.add(sts)
+
+	pod := c.newPod("payments", "postgres-0", template, demoOwner("apps/v1", "StatefulSet", sts.ObjectMeta), created)
+	pod.Spec.Volumes = []corev1.Volume{{Name: "data", VolumeSource: corev1.VolumeSource{
+		PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data-postgres-0"},
+	}}}
+	c.add(pod)
+
+	storageClass := "standard"
+	c.add(&corev1.PersistentVolumeClaim{
+		ObjectMeta: demoMeta("payments", "data-postgres-0", lbls, created),
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			StorageClassName: &storageClass,
+			VolumeName:       "pvc-" + string(uuid.NewUUID()),
+			Resources:        corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")}},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Phase:    corev1.ClaimBound,
+			Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("20Gi")},
+		},
+	})
+	c.add(&corev1.Service{
+		ObjectMeta: demoMeta("payments", "postgres", lbls, created),
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Selector:  lbls,
+			Ports:     []corev1.ServicePort{{Name: "postgres", Port: 5432, TargetPort: intstr.FromInt32(5432)}},
+		},
+	})
+}
+
+func (c *demoCluster) addNodeExporter(created time.Time) {
+	lbls := map[string]string{"app": "node-exporter", "app.kubernetes.io/name": "node-exporter"}
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: lbls},
+		Spec: corev1.PodSpec{
+			HostNetwork: true,
+			Containers:  []corev1.Container{demoContainer("node-exporter", "quay.io/prometheus/node-exporter:v1.8.2", 9100, "50m", "64Mi")},
+		},
+	}
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: demoMeta("monitoring", "node-exporter", lbls, created),
+		Spec:       appsv1.DaemonSetSpec{Selector: &metav1.LabelSelector{MatchLabels: lbls}, Template: template},
+		Status: appsv1.DaemonSetStatus{
+			CurrentNumberScheduled: demoNodeCount, DesiredNumberScheduled: demoNodeCount,
+			NumberReady: demoNodeCount, NumberAvailable: demoNodeCount, UpdatedNumberScheduled: demoNodeCount,
+		},
+	}
+	c.add(ds)
+	owner := demoOwner("apps/v1", "DaemonSet", ds.ObjectMeta)
+	for i := 0; i < demoNodeCount; i++ {
+		c.add(c.newPod("monitoring", "node-exporter-"+demoPodSuffix(c.podSeq+1), template, owner, created))
+	}
+}
+
+func (c *demoCluster) addCronJob(created time.Time) {
+	lbls := map[string]string{"app": "nightly-report"}
+	lastRun := metav1.NewTime(c.start.Add(-6 * time.Hour))
+	c.add(&batchv1.CronJob{
+		ObjectMeta: demoMeta("shop", "nightly-report", lbls, created),
+		Spec: batchv1.CronJobSpec{
+			Schedule:          "0 2 * * *",
+			ConcurrencyPolicy: batchv1.ForbidConcurrent,
+			JobTemplate: batchv1.JobTemplateSpec{Spec: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: lbls},
+					Spec: corev1.PodSpec{
+						RestartPolicy: corev1.RestartPolicyOnFailure,
+						Containers:    []corev1.Container{demoContainer("report", "ghcr.io/acme/report:1.2.0", 8080, "100m", "128Mi")},
+					},
+				},
+			}},
+		},
+		Status: batchv1.CronJobStatus{LastScheduleTime: &lastRun, LastSuccessfulTime: &lastRun},
+	})
+}
+
+func demoEvent(obj metav1.Object, eventType, reason, message string, at time.Time) *corev1.Event {
+	kind := "Pod"
+	apiVersion := "v1"
+	switch obj.(type) {
+	case *appsv1.Deployment:
+		kind, apiVersion = "Deployment", "apps/v1"
+	case *batchv1.Job:
+		kind, apiVersion = "Job", "batch/v1"
+	case *batchv1.CronJob:
+		kind, apiVersion = "CronJob", "batch/v1"
+	}
+	ts := metav1.NewTime(at)
+	return &corev1.Event{
+		ObjectMeta: demoMeta(obj.GetNamespace(), fmt.Sprintf("%s.%x", obj.GetName(), fnv32(string(uuid.NewUUID()))), nil, at),
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: apiVersion, Kind: kind, Namespace: obj.GetNamespace(), Name: obj.GetName(), UID: obj.GetUID(),
+		},
+		Type:           eventType,
+		Reason:         reason,
+		Message:        message,
+		Count:          1,
+		FirstTimestamp: ts,
+		LastTimestamp:  ts,
+		Source:         corev1.EventSource{Component: "radar-demo"},
+	}
+}
+
+func fnv32(s string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return h.Sum32()
+}
+
+// demoScript mutates the demo cluster on a loop so the timeline, topology,
+// and metrics have something to show
+type demoScript struct {
+	cluster     *demoCluster
+	client      *fake.Clientset
+	metrics     k8stesting.ObjectTracker
+	step        int
+	podsWithUse map[string]bool // ns/name of pods with PodMetrics
+}
+
+func (s *demoScript) run(ctx context.Context) {
+	ticker := time.NewTicker(demoScriptInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+// tick runs the next scripted scenario: scale up, crash, scale down, cron run
+func (s *demoScript) tick(ctx context.Context) {
+	var err error
+	switch s.step % 4 {
+	case 0:
+		err = s.scale(ctx, "shop", "cart", 3)
+	case 1:
+		err = s.crash(ctx, "payments", "payments-api")
+	case 2:
+		err = s.scale(ctx, "shop", "cart", 2)
+	case 3:
+		err = s.runCronJob(ctx, "shop", "nightly-report")
+	}
+	if err != nil {
+		log.Printf("[demo] Scripted step %d failed: %v", s.step%4, err)
+	}
+	s.step++
+	s.refreshMetrics(ctx)
+}
+
+func (s *demoScript) scale(ctx context.Context, namespace, name string, replicas int) error {
+	deploy, err := s.client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	selector := labels.SelectorFromSet(deploy.Spec.Selector.MatchLabels).String()
+	pods, err := s.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil || len(pods.Items) == 0 {
+		return fmt.Errorf("no pods for %s/%s: %v", namespace, name, err)
+	}
+	rsName := pods.Items[0].OwnerReferences[0].Name
+	rs, err := s.client.AppsV1().ReplicaSets(namespace).Get(ctx, rsName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	current := len(pods.Items)
+	for current < replicas {
+		podName := rsName + "-" + demoPodSuffix(s.cluster.podSeq+1)
+		pod := s.cluster.newPod(namespace, podName, rs.Spec.Template, pods.Items[0].OwnerReferences[0], now)
+		if _, err := s.client.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+		s.emit(ctx, demoEvent(pod, corev1.EventTypeNormal, "Scheduled", fmt.Sprintf("Successfully assigned %s/%s to %s", namespace, podName, pod.Spec.NodeName), now))
+		s.emit(ctx, demoEvent(pod, corev1.EventTypeNormal, "Started", fmt.Sprintf("Started container %s", pod.Spec.Containers[0].Name), now))
+		current++
+	}
+	for i := len(pods.Items) - 1; current > replicas && i >= 0; i-- {
+		pod := &pods.Items[i]
+		if err := s.client.CoreV1().Pods(namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+		s.emit(ctx, demoEvent(pod, corev1.EventTypeNormal, "Killing", fmt.Sprintf("Stopping container %s", pod.Spec.Containers[0].Name), now))
+		current--
+	}
+
+	count := int32(replicas)
+	rs.Spec.Replicas = &count
+	rs.Status = appsv1.ReplicaSetStatus{Replicas: count, ReadyReplicas: count, AvailableReplicas: count, FullyLabeledReplicas: count}
+	if _, err := s.client.AppsV1().ReplicaSets(namespace).Update(ctx, rs, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	deploy.Spec.Replicas = &count
+	deploy.Generation++
+	deploy.Status = demoDeploymentStatus(count, count, now)
+	deploy.Status.ObservedGeneration = deploy.Generation
+	if _, err := s.client.AppsV1().Deployments(namespace).Update(ctx, deploy, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	s.emit(ctx, demoEvent(deploy, corev1.EventTypeNormal, "ScalingReplicaSet", fmt.Sprintf("Scaled replica set %s to %d", rsName, replicas), now))
+	return nil
+}
+
+func (s *demoScript) crash(ctx context.Context, namespace, app string) error {
+	pods, err := s.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: "app=" + app})
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if len(pod.Status.ContainerStatuses) == 0 || pod.Status.ContainerStatuses[0].RestartCount == 0 {
+			continue
+		}
+		setCrashLooping(pod, pod.Status.ContainerStatuses[0].RestartCount+1, now)
+		if _, err := s.client.CoreV1().Pods(namespace).UpdateStatus(ctx, pod, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		container := pod.Spec.Containers[0].Name
+		s.emit(ctx, demoEvent(pod, corev1.EventTypeWarning, "BackOff", fmt.Sprintf("Back-off restarting failed container %s in pod %s", container, pod.Name), now))
+	}
+	return nil
+}
+
+func (s *demoScript) runCronJob(ctx context.Context, namespace, name string) error {
+	cronJob, err := s.client.BatchV1().CronJobs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	completions := int32(1)
+	job := &batchv1.Job{
+		ObjectMeta: demoMeta(namespace, fmt.Sprintf("%s-%d", name, now.Unix()/60), cronJob.Spec.JobTemplate.Spec.Template.Labels, now),
+		Spec:       *cronJob.Spec.JobTemplate.Spec.DeepCopy(),
+	}
+	job.Spec.Completions = &completions
+	job.OwnerReferences = []metav1.OwnerReference{demoOwner("batch/v1", "CronJob", cronJob.ObjectMeta)}
+	finished := metav1.NewTime(now.Add(8 * time.Second))
+	job.Status = batchv1.JobStatus{
+		Succeeded:      1,
+		StartTime:      &metav1.Time{Time: now},
+		CompletionTime: &finished,
+		Conditions:     []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue, LastTransitionTime: finished}},
+	}
+	if _, err := s.client.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+
+	pod := s.cluster.newPod(namespace, job.Name+"-"+demoPodSuffix(s.cluster.podSeq+1), job.Spec.Template, demoOwner("batch/v1", "Job", job.ObjectMeta), now)
+	pod.Status.Phase = corev1.PodSucceeded
+	for i := range pod.Status.ContainerStatuses {
+		pod.Status.ContainerStatuses[i].Ready = false
+		pod.Status.ContainerStatuses[i].State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			ExitCode: 0, Reason: "Completed", StartedAt: metav1.NewTime(now), FinishedAt: finished,
+		}}
+	}
+	if _, err := s.client.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	s.emit(ctx, demoEvent(cronJob, corev1.EventTypeNormal, "SuccessfulCreate", fmt.Sprintf("Created job %s", job.Name), now))
+	s.emit(ctx, demoEvent(job, corev1.EventTypeNormal, "Completed", "Job completed", finished.Time))
+
+	cronJob.Status.LastScheduleTime = &metav1.Time{Time: now}
+	cronJob.Status.LastSuccessfulTime = &finished
+	if _, err := s.client.BatchV1().CronJobs(namespace).UpdateStatus(ctx, cronJob, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	return s.pruneJobs(ctx, namespace, cronJob.Spec.JobTemplate.Spec.Template.Labels)
+}
+
+// pruneJobs keeps the newest demoMaxJobHistory jobs, like successfulJobsHistoryLimit
+func (s *demoScript) pruneJobs(ctx context.Context, namespace string, lbls map[string]string) error {
+	jobs, err := s.client.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{LabelSelector: labels.SelectorFromSet(lbls).String()})
+	if err != nil {
+		return err
+	}
+	if len(jobs.Items) <= demoMaxJobHistory {
+		return nil
+	}
+	items := jobs.Items
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].CreationTimestamp.Before(&items[j].CreationTimestamp)
+	})
+	for _, job := range items[:len(items)-demoMaxJobHistory] {
+		if err := s.client.BatchV1().Jobs(namespace).Delete(ctx, job.Name, metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+		pods, err := s.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for _, pod := range pods.Items {
+			if len(pod.OwnerReferences) > 0 && pod.OwnerReferences[0].UID == job.UID {
+				_ = s.client.CoreV1().Pods(namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{})
+			}
+		}
+	}
+	return nil
+}
+
+func (s *demoScript) emit(ctx context.Context, event *corev1.Event) {
+	if _, err := s.client.CoreV1().Events(event.Namespace).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+		log.Printf("[demo] Failed to record event %s: %v", event.Reason, err)
+	}
+}
+
+// refreshMetrics publishes PodMetrics and NodeMetrics for running pods.
+// Usage follows a slow wave per pod so graphs look alive but stay stable.
+func (s *demoScript) refreshMetrics(ctx context.Context) {
+	pods, err := s.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	nodeCPU := map[string]int64{}
+	nodeMem := map[string]int64{}
+	seen := map[string]bool{}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		load := demoLoad(pod.Name, now)
+		var containers []interface{}
+		for _, container := range pod.Spec.Containers {
+			cpu := int64(float64(container.Resources.Requests.Cpu().MilliValue()) * load)
+			mem := int64(float64(container.Resources.Requests.Memory().Value()) * (0.5 + load/3))
+			nodeCPU[pod.Spec.NodeName] += cpu
+			nodeMem[pod.Spec.NodeName] += mem
+			containers = append(containers, map[string]interface{}{
+				"name":  container.Name,
+				"usage": demoUsage(cpu, mem),
+			})
+		}
+		key := pod.Namespace + "/" + pod.Name
+		seen[key] = true
+		s.upsertMetrics(podMetricsGVR, pod.Namespace, &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "metrics.k8s.io/v1beta1",
+			"kind":       "PodMetrics",
+			"metadata":   map[string]interface{}{"name": pod.Name, "namespace": pod.Namespace, "creationTimestamp": now.UTC().Format(time.RFC3339)},
+			"timestamp":  now.UTC().Format(time.RFC3339),
+			"window":     "30s",
+			"containers": containers,
+		}})
+	}
+	for key := range s.podsWithUse {
+		if !seen[key] {
+			ns, name, _ := strings.Cut(key, "/")
+			_ = s.metrics.Delete(podMetricsGVR, ns, name)
+		}
+	}
+	s.podsWithUse = seen
+
+	for i := 1; i <= demoNodeCount; i++ {
+		node := fmt.Sprintf("demo-node-%d", i)
+		// System daemons and the kubelet use some baseline on every node
+		cpu := nodeCPU[node] + 250
+		mem := nodeMem[node] + 1<<30
+		s.upsertMetrics(nodeMetricsGVR, "", &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "metrics.k8s.io/v1beta1",
+			"kind":       "NodeMetrics",
+			"metadata":   map[string]interface{}{"name": node, "creationTimestamp": now.UTC().Format(time.RFC3339)},
+			"timestamp":  now.UTC().Format(time.RFC3339),
+			"window":     "30s",
+			"usage":      demoUsage(cpu, mem),
+		}})
+	}
+}
+
+func (s *demoScript) upsertMetrics(gvr schema.GroupVersionResource, namespace string, obj *unstructured.Unstructured) {
+	err := s.metrics.Update(gvr, obj, namespace)
+	if apierrors.IsNotFound(err) {
+		err = s.metrics.Create(gvr, obj, namespace)
+	}
+	if err != nil {
+		log.Printf("[demo] Failed to update %s metrics for %s: %v", gvr.Resource, obj.GetName(), err)
+	}
+}
+
+// demoLoad returns a utilization factor between 0.2 and 0.8 that drifts over ~10 minutes
+func demoLoad(name string, now time.Time) float64 {
+	phase := float64(fnv32(name)%628) / 100
+	return 0.5 + 0.3*math.Sin(2*math.Pi*float64(now.Unix())/600+phase)
+}
+
+func demoUsage(cpuMillis, memBytes int64) map[string]interface{} {
+	return map[string]interface{}{
+		"cpu":    resource.NewMilliQuantity(cpuMillis, resource.DecimalSI).String(),
+		"memory": fmt.Sprintf("%dKi", memBytes/1024),
+	}
+}
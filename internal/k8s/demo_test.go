@@ -0,0 +1,59 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDemoScript(t *testing.T) {
+	ctx := context.Background()
+	cluster := newDemoCluster()
+	client := fake.NewClientset(cluster.objects()...)
+	dynClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), demoDynamicListKinds)
+	script := &demoScript{cluster: cluster, client: client, metrics: dynClient.Tracker()}
+
+	countPods := func(selector string) int {
+		pods, err := client.CoreV1().Pods("shop").List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			t.Fatalf("list pods: %v", err)
+		}
+		return len(pods.Items)
+	}
+	if got := countPods("app=cart"); got != 2 {
+		t.Fatalf("expected 2 cart pods at start, got %d", got)
+	}
+
+	script.tick(ctx) // scale up
+	if got := countPods("app=cart"); got != 3 {
+		t.Errorf("expected 3 cart pods after scale up, got %d", got)
+	}
+	script.tick(ctx) // crash
+	script.tick(ctx) // scale down
+	if got := countPods("app=cart"); got != 2 {
+		t.Errorf("expected 2 cart pods after scale down, got %d", got)
+	}
+	deploy, err := client.AppsV1().Deployments("shop").Get(ctx, "cart", metav1.GetOptions{})
+	if err != nil || *deploy.Spec.Replicas != 2 || deploy.Status.ReadyReplicas != 2 {
+		t.Errorf("expected cart deployment at 2 ready replicas, got %+v (err %v)", deploy.Status, err)
+	}
+
+	script.tick(ctx) // cron run
+	jobs, err := client.BatchV1().Jobs("shop").List(ctx, metav1.ListOptions{LabelSelector: "app=nightly-report"})
+	if err != nil || len(jobs.Items) != 1 {
+		t.Errorf("expected one nightly-report job, got %v (err %v)", jobs, err)
+	}
+
+	nodeMetrics, err := dynClient.Resource(nodeMetricsGVR).List(ctx, metav1.ListOptions{})
+	if err != nil || len(nodeMetrics.Items) != demoNodeCount {
+		t.Errorf("expected metrics for %d nodes, got %v (err %v)", demoNodeCount, nodeMetrics, err)
+	}
+	podMetrics, err := dynClient.Resource(podMetricsGVR).Namespace("shop").List(ctx, metav1.ListOptions{})
+	if err != nil || len(podMetrics.Items) == 0 {
+		t.Errorf("expected pod metrics in shop, got %v (err %v)", podMetrics, err)
+	}
+}
@@ -21,6 +21,7 @@ type APIResource struct {
 	Namespaced bool     `json:"namespaced"`
 	IsCRD      bool     `json:"isCrd"`
 	Verbs      []string `json:"verbs"`
+	ShortNames []string `json:"shortNames,omitempty"`
 }
 
 // ResourceDiscovery manages discovery and caching of API resources
@@ -185,6 +186,7 @@ func (d *ResourceDiscovery) refresh() error {
 				Namespaced: apiRes.Namespaced,
 				IsCRD:      isCRD,
 				Verbs:      apiRes.Verbs,
+				ShortNames: apiRes.ShortNames,
 			}
 
 			d.resources = append(d.resources, resource)
@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/tools/cache"
 
@@ -30,8 +31,10 @@ const (
 
 // DynamicResourceCache provides on-demand caching for CRDs and other dynamic resources
 type DynamicResourceCache struct {
-	factory         dynamicinformer.DynamicSharedInformerFactory
+	client          dynamic.Interface
+	namespace       string // Non-empty when informers are namespace-scoped (limited RBAC)
 	informers       map[schema.GroupVersionResource]cache.SharedIndexInformer
+	informerStops   map[schema.GroupVersionResource]chan struct{} // Per-informer stop, so one GVR can be unwatched
 	syncComplete    map[schema.GroupVersionResource]bool // Track which informers have completed initial sync
 	stopCh          chan struct{}
 	stopOnce        sync.Once
@@ -40,6 +43,8 @@ type DynamicResourceCache struct {
 	discoveryStatus CRDDiscoveryStatus  // Status of CRD discovery
 	discoveryMu     sync.RWMutex        // Mutex for discovery status
 	discoveryDone   chan struct{}        // closed when DiscoverAllCRDs() completes
+	apiServices     cache.SharedIndexInformer // APIService watcher (nil until WatchAPIServices succeeds)
+	fallbacks       map[schema.GroupVersionResource]*fallbackList // Served by direct lists past maxDynamicInformers
 }
 
 var (
@@ -79,24 +84,20 @@ func InitDynamicResourceCache(changeCh chan ResourceChange) error {
 			return
 		}
 
-		// Use namespace-scoped factory if the user only has namespace-level access
-		var factory dynamicinformer.DynamicSharedInformerFactory
+		// Use namespace-scoped informers if the user only has namespace-level access
+		namespace := metav1.NamespaceAll
 		if permResult := GetCachedPermissionResult(); permResult != nil && permResult.NamespaceScoped && permResult.Namespace != "" {
-			factory = dynamicinformer.NewFilteredDynamicSharedInformerFactory(
-				client, 0, permResult.Namespace, nil,
-			)
+			namespace = permResult.Namespace
 			log.Printf("Using namespace-scoped dynamic informers for namespace %q", permResult.Namespace)
-		} else {
-			factory = dynamicinformer.NewDynamicSharedInformerFactory(
-				client,
-				0, // no resync - updates come via watch
-			)
 		}
 
 		dynamicResourceCache = &DynamicResourceCache{
-			factory:         factory,
+			client:          client,
+			namespace:       namespace,
 			informers:       make(map[schema.GroupVersionResource]cache.SharedIndexInformer),
+			informerStops:   make(map[schema.GroupVersionResource]chan struct{}),
 			syncComplete:    make(map[schema.GroupVersionResource]bool),
+			fallbacks:       make(map[schema.GroupVersionResource]*fallbackList),
 			stopCh:          make(chan struct{}),
 			changes:         changeCh,
 			discoveryStatus: CRDDiscoveryIdle,
@@ -147,6 +148,10 @@ func (d *DynamicResourceCache) EnsureWatching(gvr schema.GroupVersionResource) e
 	if exists {
 		return nil
 	}
+	// Past the informer cap, resources already served by direct lists skip the access probe
+	if d.fallbackAtCap(gvr) {
+		return nil
+	}
 
 	// If CRD discovery is in progress, wait for it to finish instead of
 	// probing independently. DiscoverAllCRDs() probes all CRDs efficiently
@@ -181,7 +186,8 @@ func (d *DynamicResourceCache) EnsureWatching(gvr schema.GroupVersionResource) e
 }
 
 // startWatching creates and starts an informer for a GVR (no access probe).
-// Callers must verify access before calling this method.
+// Callers must verify access before calling this method. At the informer cap
+// the GVR is served by direct lists instead (see dynamic_fallback.go).
 func (d *DynamicResourceCache) startWatching(gvr schema.GroupVersionResource) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
@@ -190,19 +196,41 @@ func (d *DynamicResourceCache) startWatching(gvr schema.GroupVersionResource) er
 	if _, exists := d.informers[gvr]; exists {
 		return nil
 	}
+	if d.atInformerCapLocked() {
+		d.addFallbackLocked(gvr)
+		return nil
+	}
+	delete(d.fallbacks, gvr)
 
-	// Create informer for this GVR
-	informer := d.factory.ForResource(gvr).Informer()
+	// Create informer for this GVR. Informers are created individually (not via a
+	// shared factory) so a stopped GVR can later be watched again with a fresh informer.
+	informer := dynamicinformer.NewFilteredDynamicInformer(
+		d.client, gvr, d.namespace,
+		0, // no resync - updates come via watch
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+		nil,
+	).Informer()
 	d.informers[gvr] = informer
+	stop := make(chan struct{})
+	d.informerStops[gvr] = stop
 
 	// Get the kind name from discovery (e.g., "Rollout" from "rollouts")
 	kind := gvrToKind(gvr)
 
 	// Add event handlers for change tracking (timeline + SSE)
+	setRelistHandler(informer, kind)
 	d.addDynamicChangeHandlers(informer, kind, gvr)
 
-	// Start the informer
-	go informer.Run(d.stopCh)
+	// Start the informer; it stops when either the whole cache or this GVR is stopped
+	runStop := make(chan struct{})
+	go func() {
+		select {
+		case <-d.stopCh:
+		case <-stop:
+		}
+		close(runStop)
+	}()
+	go informer.Run(runStop)
 
 	// Log the current count of dynamic informers for diagnostics
 	informerCount := len(d.informers)
@@ -219,14 +247,60 @@ func (d *DynamicResourceCache) startWatching(gvr schema.GroupVersionResource) er
 			log.Printf("Dynamic resource synced: %s.%s/%s", gvr.Resource, gvr.Group, gvr.Version)
 		}
 
-		// Mark this informer as sync complete - now we can record ADD events for it
+		// Mark this informer as sync complete - now we can record ADD events for it.
+		// Skip if the GVR was unwatched (or re-watched with a new informer) meanwhile.
 		d.mu.Lock()
-		d.syncComplete[gvr] = true
+		if d.informers[gvr] == informer {
+			d.syncComplete[gvr] = true
+		}
 		d.mu.Unlock()
 	}()
 	return nil
 }
 
+// StopWatching stops the informer for a GVR and drops its cached objects.
+// Note that listing the resource again (e.g. opening it in the UI) restarts the watch.
+func (d *DynamicResourceCache) StopWatching(gvr schema.GroupVersionResource) error {
+	if d == nil {
+		return fmt.Errorf("dynamic resource cache not initialized")
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	stop, exists := d.informerStops[gvr]
+	if !exists {
+		if _, ok := d.fallbacks[gvr]; ok {
+			delete(d.fallbacks, gvr)
+			log.Printf("Stopped serving dynamic resource from direct lists: %s.%s/%s", gvr.Resource, gvr.Group, gvr.Version)
+			return nil
+		}
+		return fmt.Errorf("not watching %s.%s/%s", gvr.Resource, gvr.Group, gvr.Version)
+	}
+	close(stop)
+	delete(d.informerStops, gvr)
+	delete(d.informers, gvr)
+	delete(d.syncComplete, gvr)
+
+	log.Printf("Stopped watching dynamic resource: %s.%s/%s (total dynamic informers: %d)", gvr.Resource, gvr.Group, gvr.Version, len(d.informers))
+	return nil
+}
+
+// GetCount returns the number of cached objects for a watched GVR and whether its initial sync completed
+func (d *DynamicResourceCache) GetCount(gvr schema.GroupVersionResource) (count int, synced bool, watching bool) {
+	if d == nil {
+		return 0, false, false
+	}
+
+	d.mu.RLock()
+	informer, exists := d.informers[gvr]
+	d.mu.RUnlock()
+	if !exists {
+		return 0, false, false
+	}
+	return len(informer.GetIndexer().ListKeys()), informer.HasSynced(), true
+}
+
 // probeAccess does a quick list with limit=1 to verify the user can access this resource.
 // This prevents creating informers/reflectors that would endlessly retry on 403/401 errors.
 func (d *DynamicResourceCache) probeAccess(gvr schema.GroupVersionResource) error {
@@ -317,6 +391,10 @@ func (d *DynamicResourceCache) enqueueDynamicChange(kind string, gvr schema.Grou
 	// Track event received
 	timeline.IncrementReceived(kind)
 
+	if isRelistReplay(kind, u, oldObj, op) {
+		return
+	}
+
 	// During initial sync, still record to timeline store (historical events)
 	// but skip SSE notification
 	isSyncAdd := false
@@ -327,7 +405,7 @@ func (d *DynamicResourceCache) enqueueDynamicChange(kind string, gvr schema.Grou
 
 		if !synced {
 			isSyncAdd = true
-			if DebugEvents {
+			if DebugEvents.Load() {
 				log.Printf("[DEBUG] Dynamic initial sync add event: %s/%s/%s (recording historical only)", kind, namespace, name)
 			}
 		}
@@ -365,7 +443,7 @@ func (d *DynamicResourceCache) enqueueDynamicChange(kind string, gvr schema.Grou
 			// Channel full, drop event
 			timeline.RecordDrop(kind, namespace, name,
 				timeline.DropReasonChannelFull, op)
-			if DebugEvents {
+			if DebugEvents.Load() {
 				log.Printf("[DEBUG] Dynamic change channel full, dropped: %s/%s/%s op=%s", kind, namespace, name, op)
 			}
 		}
@@ -421,6 +499,9 @@ func (d *DynamicResourceCache) List(gvr schema.GroupVersionResource, namespace s
 	d.mu.RUnlock()
 
 	if !exists {
+		if f := d.fallbackFor(gvr); f != nil {
+			return d.listFallback(f, gvr, namespace)
+		}
 		return nil, fmt.Errorf("informer not found for %v", gvr)
 	}
 
@@ -468,6 +549,9 @@ func (d *DynamicResourceCache) ListBlocking(gvr schema.GroupVersionResource, nam
 	d.mu.RUnlock()
 
 	if !exists {
+		if f := d.fallbackFor(gvr); f != nil {
+			return d.listFallback(f, gvr, namespace)
+		}
 		return nil, fmt.Errorf("informer not found for %v", gvr)
 	}
 
@@ -519,6 +603,9 @@ func (d *DynamicResourceCache) Get(gvr schema.GroupVersionResource, namespace, n
 	d.mu.RUnlock()
 
 	if !exists {
+		if f := d.fallbackFor(gvr); f != nil {
+			return d.getFallback(f, gvr, namespace, name)
+		}
 		return nil, fmt.Errorf("informer not found for %v", gvr)
 	}
 
@@ -562,6 +649,33 @@ func (d *DynamicResourceCache) Get(gvr schema.GroupVersionResource, namespace, n
 	return stripManagedFieldsUnstructured(u), nil
 }
 
+// GetCached returns an object only if its resource already has an informer.
+// Unlike Get it never starts watching or lists from the API server, so it is
+// safe on hot paths such as event handlers.
+func (d *DynamicResourceCache) GetCached(gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, bool) {
+	if d == nil {
+		return nil, false
+	}
+
+	d.mu.RLock()
+	informer, exists := d.informers[gvr]
+	d.mu.RUnlock()
+	if !exists {
+		return nil, false
+	}
+
+	key := name
+	if namespace != "" {
+		key = namespace + "/" + name
+	}
+	item, exists, err := informer.GetIndexer().GetByKey(key)
+	if err != nil || !exists {
+		return nil, false
+	}
+	u, ok := item.(*unstructured.Unstructured)
+	return u, ok
+}
+
 // ListWithSelector returns resources matching a label selector
 func (d *DynamicResourceCache) ListWithSelector(gvr schema.GroupVersionResource, namespace string, selector labels.Selector) ([]*unstructured.Unstructured, error) {
 	items, err := d.List(gvr, namespace)
@@ -817,7 +931,6 @@ func (d *DynamicResourceCache) Stop() {
 		d.discoveryMu.Unlock()
 
 		close(d.stopCh)
-		d.factory.Shutdown()
 	})
 }
 
@@ -870,6 +983,14 @@ func WarmupCommonCRDs() {
 		log.Printf("Warming up CRD: Application (argoproj.io)")
 	}
 
+	// KEDA scalers, so trigger activation/deactivation reaches the timeline
+	for _, kind := range []string{"ScaledObject", "ScaledJob"} {
+		if gvr, ok := discovery.GetGVRWithGroup(kind, KEDAGroup); ok {
+			gvrs = append(gvrs, gvr)
+			log.Printf("Warming up CRD: %s (%s)", kind, KEDAGroup)
+		}
+	}
+
 	if len(gvrs) > 0 {
 		cache.WarmupParallel(gvrs, 10*time.Second)
 	}
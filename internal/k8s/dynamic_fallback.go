@@ -0,0 +1,166 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// maxDynamicInformers caps the number of dynamic informers (0 = unlimited).
+// Resources requested beyond the cap are served by direct API lists with a
+// short TTL cache instead of a watch, bounding memory and watch connections
+// on clusters with hundreds of CRDs.
+var maxDynamicInformers int
+
+// dynamicFallbackTTL is how long a direct list of a fallback resource is reused
+const dynamicFallbackTTL = 15 * time.Second
+
+// SetMaxDynamicInformers sets the dynamic informer cap (0 = unlimited)
+func SetMaxDynamicInformers(n int) {
+	maxDynamicInformers = max(n, 0)
+}
+
+// GetMaxDynamicInformers returns the dynamic informer cap (0 = unlimited)
+func GetMaxDynamicInformers() int {
+	return maxDynamicInformers
+}
+
+// fallbackList caches the last direct list of a resource served without an informer
+type fallbackList struct {
+	mu        sync.Mutex
+	items     []*unstructured.Unstructured
+	fetchedAt time.Time
+	lists     int // Direct API lists issued
+	hits      int // Requests answered from the TTL cache
+}
+
+// FallbackResource describes a resource served by direct lists because the
+// dynamic informer cap was reached
+type FallbackResource struct {
+	Group       string     `json:"group"`
+	Version     string     `json:"version"`
+	Resource    string     `json:"resource"`
+	Items       int        `json:"items"`
+	FetchedAt   *time.Time `json:"fetchedAt,omitempty"`
+	DirectLists int        `json:"directLists"`
+	CacheHits   int        `json:"cacheHits"`
+}
+
+// atInformerCapLocked reports whether no more informers may be started.
+// Caller must hold d.mu.
+func (d *DynamicResourceCache) atInformerCapLocked() bool {
+	return maxDynamicInformers > 0 && len(d.informers) >= maxDynamicInformers
+}
+
+// fallbackAtCap reports whether gvr is already served by direct lists and the cap still applies
+func (d *DynamicResourceCache) fallbackAtCap(gvr schema.GroupVersionResource) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	_, ok := d.fallbacks[gvr]
+	return ok && d.atInformerCapLocked()
+}
+
+// addFallbackLocked marks gvr as served by direct lists. Caller must hold d.mu.
+func (d *DynamicResourceCache) addFallbackLocked(gvr schema.GroupVersionResource) {
+	if _, ok := d.fallbacks[gvr]; ok {
+		return
+	}
+	d.fallbacks[gvr] = &fallbackList{}
+	log.Printf("Dynamic informer limit (%d) reached, serving %s.%s/%s from direct lists (%s cache)",
+		maxDynamicInformers, gvr.Resource, gvr.Group, gvr.Version, dynamicFallbackTTL)
+}
+
+func (d *DynamicResourceCache) fallbackFor(gvr schema.GroupVersionResource) *fallbackList {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.fallbacks[gvr]
+}
+
+// listFallback returns the resource from a direct list, reusing the previous
+// list while it is younger than dynamicFallbackTTL
+func (d *DynamicResourceCache) listFallback(f *fallbackList, gvr schema.GroupVersionResource, namespace string) ([]*unstructured.Unstructured, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.fetchedAt.IsZero() && time.Since(f.fetchedAt) < dynamicFallbackTTL {
+		f.hits++
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		// List at the cache's scope and filter per request, so one list serves every namespace
+		items, err := d.ListDirect(ctx, gvr, d.namespace)
+		if err != nil {
+			return nil, err
+		}
+		f.items = items
+		f.fetchedAt = time.Now()
+		f.lists++
+	}
+
+	result := make([]*unstructured.Unstructured, 0, len(f.items))
+	for _, item := range f.items {
+		if namespace == "" || item.GetNamespace() == namespace {
+			result = append(result, item.DeepCopy())
+		}
+	}
+	return result, nil
+}
+
+// getFallback returns a single object from the fallback list
+func (d *DynamicResourceCache) getFallback(f *fallbackList, gvr schema.GroupVersionResource, namespace, name string) (*unstructured.Unstructured, error) {
+	items, err := d.listFallback(f, gvr, namespace)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if item.GetName() == name {
+			return item, nil
+		}
+	}
+	if namespace != "" {
+		return nil, fmt.Errorf("resource not found: %s/%s", namespace, name)
+	}
+	return nil, fmt.Errorf("resource not found: %s", name)
+}
+
+// GetFallbackResources returns the resources served by direct lists, sorted by group and resource
+func (d *DynamicResourceCache) GetFallbackResources() []FallbackResource {
+	if d == nil {
+		return nil
+	}
+
+	d.mu.RLock()
+	result := make([]FallbackResource, 0, len(d.fallbacks))
+	lists := make([]*fallbackList, 0, len(d.fallbacks))
+	for gvr, f := range d.fallbacks {
+		result = append(result, FallbackResource{Group: gvr.Group, Version: gvr.Version, Resource: gvr.Resource})
+		lists = append(lists, f)
+	}
+	d.mu.RUnlock()
+
+	for i, f := range lists {
+		f.mu.Lock()
+		result[i].Items = len(f.items)
+		result[i].DirectLists = f.lists
+		result[i].CacheHits = f.hits
+		if !f.fetchedAt.IsZero() {
+			fetchedAt := f.fetchedAt
+			result[i].FetchedAt = &fetchedAt
+		}
+		f.mu.Unlock()
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Group != result[j].Group {
+			return result[i].Group < result[j].Group
+		}
+		return result[i].Resource < result[j].Resource
+	})
+	return result
+}
@@ -0,0 +1,89 @@
+package k8s
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestDynamicInformerCapFallback(t *testing.T) {
+	widgets := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	gadgets := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "gadgets"}
+	obj := func(kind, namespace, name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("example.com/v1")
+		u.SetKind(kind)
+		u.SetNamespace(namespace)
+		u.SetName(name)
+		return u
+	}
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{widgets: "WidgetList", gadgets: "GadgetList"},
+		obj("Widget", "a", "w1"), obj("Gadget", "a", "g1"), obj("Gadget", "b", "g2"),
+	)
+
+	prevClient, prevMax := dynamicClient, maxDynamicInformers
+	dynamicClient = client
+	SetMaxDynamicInformers(1)
+	d := &DynamicResourceCache{
+		client:        client,
+		informers:     make(map[schema.GroupVersionResource]cache.SharedIndexInformer),
+		informerStops: make(map[schema.GroupVersionResource]chan struct{}),
+		syncComplete:  make(map[schema.GroupVersionResource]bool),
+		fallbacks:     make(map[schema.GroupVersionResource]*fallbackList),
+		stopCh:        make(chan struct{}),
+		discoveryDone: make(chan struct{}),
+	}
+	t.Cleanup(func() {
+		d.Stop()
+		dynamicClient, maxDynamicInformers = prevClient, prevMax
+	})
+
+	if err := d.startWatching(widgets); err != nil {
+		t.Fatalf("startWatching(widgets): %v", err)
+	}
+	if err := d.startWatching(gadgets); err != nil {
+		t.Fatalf("startWatching(gadgets): %v", err)
+	}
+	if got := d.GetInformerCount(); got != 1 {
+		t.Fatalf("expected 1 informer under the cap, got %d", got)
+	}
+
+	items, err := d.List(gadgets, "")
+	if err != nil || len(items) != 2 {
+		t.Fatalf("expected 2 gadgets from direct list, got %d (err %v)", len(items), err)
+	}
+	items, err = d.List(gadgets, "b")
+	if err != nil || len(items) != 1 || items[0].GetName() != "g2" {
+		t.Fatalf("expected g2 in namespace b, got %v (err %v)", items, err)
+	}
+	if got, err := d.Get(gadgets, "a", "g1"); err != nil || got.GetName() != "g1" {
+		t.Fatalf("expected g1, got %v (err %v)", got, err)
+	}
+	if _, err := d.Get(gadgets, "a", "missing"); err == nil {
+		t.Errorf("expected not found error for missing gadget")
+	}
+
+	fallbacks := d.GetFallbackResources()
+	if len(fallbacks) != 1 || fallbacks[0].Resource != "gadgets" {
+		t.Fatalf("expected gadgets as the only fallback resource, got %+v", fallbacks)
+	}
+	if fb := fallbacks[0]; fb.DirectLists != 1 || fb.CacheHits != 3 || fb.Items != 2 {
+		t.Errorf("expected 1 direct list and 3 cache hits over 2 items, got %+v", fb)
+	}
+
+	// Freeing a slot lets the fallback resource get a real informer
+	if err := d.StopWatching(widgets); err != nil {
+		t.Fatalf("StopWatching(widgets): %v", err)
+	}
+	if err := d.startWatching(gadgets); err != nil {
+		t.Fatalf("startWatching(gadgets) after freeing a slot: %v", err)
+	}
+	if len(d.GetFallbackResources()) != 0 || d.GetInformerCount() != 1 {
+		t.Errorf("expected gadgets to move from fallback to an informer")
+	}
+}
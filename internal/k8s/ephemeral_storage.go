@@ -0,0 +1,312 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// EphemeralStorageNearLimitPercent flags pods using this much of their
+// ephemeral-storage limit; the kubelet evicts them once they exceed it
+const EphemeralStorageNearLimitPercent = 80
+
+// kubeletSummaryConcurrency bounds parallel kubelet summary requests
+const kubeletSummaryConcurrency = 8
+
+// Eviction causes derived from a pod's eviction message
+const (
+	EvictionDiskPressure   = "DiskPressure"          // Node ran low on nodefs/imagefs
+	EvictionEphemeralLimit = "EphemeralStorageLimit" // Pod exceeded its own ephemeral-storage limit
+	EvictionMemoryPressure = "MemoryPressure"
+	EvictionPIDPressure    = "PIDPressure"
+	EvictionOtherCause     = "Other"
+)
+
+// FilesystemUsage is the usage of one filesystem as reported by the kubelet
+type FilesystemUsage struct {
+	UsedBytes      uint64  `json:"usedBytes"`
+	CapacityBytes  uint64  `json:"capacityBytes"`
+	AvailableBytes uint64  `json:"availableBytes"`
+	UsedPercent    float64 `json:"usedPercent"`
+}
+
+// NodeStorageUsage is a node's root (nodefs) and image filesystem usage
+type NodeStorageUsage struct {
+	Node         string           `json:"node"`
+	Filesystem   *FilesystemUsage `json:"filesystem,omitempty"` // nodefs: kubelet root, logs, emptyDirs
+	ImageFs      *FilesystemUsage `json:"imageFs,omitempty"`    // Container images and writable layers
+	DiskPressure bool             `json:"diskPressure"`
+	// Pods evicted from this node for disk pressure that are still around
+	EvictedPods int    `json:"evictedPods"`
+	Error       string `json:"error,omitempty"` // Kubelet summary unavailable for this node
+}
+
+// PodStorageUsage is a pod's ephemeral storage usage (writable layers, logs, emptyDirs)
+type PodStorageUsage struct {
+	Namespace    string  `json:"namespace"`
+	Name         string  `json:"name"`
+	Node         string  `json:"node"`
+	UsedBytes    uint64  `json:"usedBytes"`
+	LimitBytes   int64   `json:"limitBytes,omitempty"`   // Sum of container ephemeral-storage limits (0 = unlimited)
+	LimitPercent float64 `json:"limitPercent,omitempty"` // UsedBytes as a percentage of LimitBytes
+	NearLimit    bool    `json:"nearLimit"`
+}
+
+// StorageUsage is node filesystem and pod ephemeral storage usage across the cluster
+type StorageUsage struct {
+	Nodes []NodeStorageUsage `json:"nodes"`
+	Pods  []PodStorageUsage  `json:"pods"` // Sorted by limit percentage, then usage
+}
+
+// kubeletFsStats mirrors FsStats from the kubelet summary API (stats/v1alpha1)
+type kubeletFsStats struct {
+	AvailableBytes *uint64 `json:"availableBytes"`
+	CapacityBytes  *uint64 `json:"capacityBytes"`
+	UsedBytes      *uint64 `json:"usedBytes"`
+}
+
+// kubeletSummary is the subset of the kubelet /stats/summary response we use
+type kubeletSummary struct {
+	Node struct {
+		NodeName string              `json:"nodeName"`
+		CPU      *kubeletCPUStats    `json:"cpu"`
+		Memory   *kubeletMemoryStats `json:"memory"`
+		Fs       *kubeletFsStats     `json:"fs"`
+		Runtime  *struct {
+			ImageFs *kubeletFsStats `json:"imageFs"`
+		} `json:"runtime"`
+	} `json:"node"`
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"podRef"`
+		Containers []struct {
+			Name   string              `json:"name"`
+			CPU    *kubeletCPUStats    `json:"cpu"`
+			Memory *kubeletMemoryStats `json:"memory"`
+		} `json:"containers"`
+		EphemeralStorage *kubeletFsStats `json:"ephemeral-storage"`
+	} `json:"pods"`
+}
+
+// fetchKubeletSummaries reads the kubelet summary of each node through the
+// API server proxy, a few nodes at a time
+func fetchKubeletSummaries(ctx context.Context, client kubernetes.Interface, nodes []string) (map[string]*kubeletSummary, map[string]error) {
+	var mu sync.Mutex
+	summaries := make(map[string]*kubeletSummary, len(nodes))
+	errs := make(map[string]error)
+	sem := make(chan struct{}, kubeletSummaryConcurrency)
+	var wg sync.WaitGroup
+	for _, name := range nodes {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			raw, err := client.CoreV1().RESTClient().Get().
+				AbsPath("/api/v1/nodes", name, "proxy", "stats", "summary").
+				DoRaw(ctx)
+			var summary kubeletSummary
+			if err == nil {
+				err = json.Unmarshal(raw, &summary)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[name] = err
+				return
+			}
+			summaries[name] = &summary
+		}(name)
+	}
+	wg.Wait()
+	return summaries, errs
+}
+
+// GetStorageUsage reads every node's kubelet summary through the API server
+// proxy and returns node filesystem usage and per-pod ephemeral storage usage.
+// Nodes whose summary can't be read are reported with an error.
+func GetStorageUsage(ctx context.Context, namespaces []string) (*StorageUsage, error) {
+	cache := GetResourceCache()
+	if cache == nil || cache.Nodes() == nil || cache.Pods() == nil {
+		return nil, fmt.Errorf("resource cache not initialized")
+	}
+	client := GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("kubernetes client not initialized")
+	}
+
+	nodes, err := cache.Nodes().List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	pods, err := cache.Pods().List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	// The demo cluster has no kubelets to proxy to
+	if IsDemoMode() {
+		return buildStorageUsage(nodes, pods, nil, nil), nil
+	}
+
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	summaries, errs := fetchKubeletSummaries(ctx, client, names)
+
+	usage := buildStorageUsage(nodes, pods, summaries, errs)
+	if len(namespaces) > 0 {
+		usage.Pods = slices.DeleteFunc(usage.Pods, func(p PodStorageUsage) bool {
+			return !slices.Contains(namespaces, p.Namespace)
+		})
+	}
+	return usage, nil
+}
+
+// buildStorageUsage combines kubelet summaries with node conditions and pod limits
+func buildStorageUsage(nodes []*corev1.Node, pods []*corev1.Pod, summaries map[string]*kubeletSummary, errs map[string]error) *StorageUsage {
+	evictedByNode := make(map[string]int)
+	for _, pod := range pods {
+		if EvictionCause(pod) == EvictionDiskPressure {
+			evictedByNode[pod.Spec.NodeName]++
+		}
+	}
+
+	usage := &StorageUsage{Nodes: []NodeStorageUsage{}, Pods: []PodStorageUsage{}}
+	for _, node := range nodes {
+		n := NodeStorageUsage{
+			Node:         node.Name,
+			DiskPressure: HasDiskPressure(node),
+			EvictedPods:  evictedByNode[node.Name],
+		}
+		if err := errs[node.Name]; err != nil {
+			n.Error = err.Error()
+		}
+		if summary := summaries[node.Name]; summary != nil {
+			n.Filesystem = toFilesystemUsage(summary.Node.Fs)
+			if summary.Node.Runtime != nil {
+				n.ImageFs = toFilesystemUsage(summary.Node.Runtime.ImageFs)
+			}
+		}
+		usage.Nodes = append(usage.Nodes, n)
+	}
+	sort.Slice(usage.Nodes, func(i, j int) bool { return usage.Nodes[i].Node < usage.Nodes[j].Node })
+
+	podsByKey := make(map[string]*corev1.Pod, len(pods))
+	for _, pod := range pods {
+		podsByKey[pod.Namespace+"/"+pod.Name] = pod
+	}
+	for nodeName, summary := range summaries {
+		for _, ps := range summary.Pods {
+			if ps.EphemeralStorage == nil || ps.EphemeralStorage.UsedBytes == nil {
+				continue
+			}
+			p := PodStorageUsage{
+				Namespace: ps.PodRef.Namespace,
+				Name:      ps.PodRef.Name,
+				Node:      nodeName,
+				UsedBytes: *ps.EphemeralStorage.UsedBytes,
+			}
+			if pod := podsByKey[p.Namespace+"/"+p.Name]; pod != nil {
+				p.LimitBytes = EphemeralStorageLimit(pod)
+			}
+			if p.LimitBytes > 0 {
+				p.LimitPercent = float64(p.UsedBytes) / float64(p.LimitBytes) * 100
+				p.NearLimit = p.LimitPercent >= EphemeralStorageNearLimitPercent
+			}
+			usage.Pods = append(usage.Pods, p)
+		}
+	}
+	sort.Slice(usage.Pods, func(i, j int) bool {
+		if usage.Pods[i].LimitPercent != usage.Pods[j].LimitPercent {
+			return usage.Pods[i].LimitPercent > usage.Pods[j].LimitPercent
+		}
+		return usage.Pods[i].UsedBytes > usage.Pods[j].UsedBytes
+	})
+	return usage
+}
+
+func toFilesystemUsage(fs *kubeletFsStats) *FilesystemUsage {
+	if fs == nil || fs.CapacityBytes == nil || *fs.CapacityBytes == 0 {
+		return nil
+	}
+	u := &FilesystemUsage{CapacityBytes: *fs.CapacityBytes}
+	if fs.UsedBytes != nil {
+		u.UsedBytes = *fs.UsedBytes
+	}
+	if fs.AvailableBytes != nil {
+		u.AvailableBytes = *fs.AvailableBytes
+	}
+	// Like the kubelet's eviction signals, measure pressure from what's left
+	// available rather than usedBytes, which excludes reserved blocks
+	used := u.CapacityBytes - min(u.AvailableBytes, u.CapacityBytes)
+	u.UsedPercent = float64(used) / float64(u.CapacityBytes) * 100
+	return u
+}
+
+// EphemeralStorageLimit returns the sum of a pod's container ephemeral-storage
+// limits, or 0 if any container is unlimited (the pod-level limit then doesn't apply)
+func EphemeralStorageLimit(pod *corev1.Pod) int64 {
+	var total int64
+	for _, c := range pod.Spec.Containers {
+		limit, ok := c.Resources.Limits[corev1.ResourceEphemeralStorage]
+		if !ok {
+			return 0
+		}
+		total += limit.Value()
+	}
+	return total
+}
+
+// HasDiskPressure reports whether a node's DiskPressure condition is True
+func HasDiskPressure(node *corev1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeDiskPressure {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// EvictionCause classifies why the kubelet evicted a pod from its status
+// message, or returns "" if the pod wasn't evicted
+func EvictionCause(pod *corev1.Pod) string {
+	if pod.Status.Reason != "Evicted" {
+		return ""
+	}
+	msg := pod.Status.Message
+	switch {
+	// "Pod ephemeral local storage usage exceeds the total limit of containers 1Gi."
+	// "Container app exceeded its local ephemeral storage limit \"1Gi\"."
+	// "Usage of EmptyDir volume \"cache\" exceeds the limit \"500Mi\"."
+	case strings.Contains(msg, "ephemeral local storage usage exceeds"),
+		strings.Contains(msg, "local ephemeral storage limit"),
+		strings.Contains(msg, "Usage of EmptyDir volume"):
+		return EvictionEphemeralLimit
+	// "The node was low on resource: ephemeral-storage. ..." (also nodefs/imagefs inodes)
+	case strings.Contains(msg, "low on resource: ephemeral-storage"),
+		strings.Contains(msg, "DiskPressure"),
+		strings.Contains(msg, "low on resource: inodes"):
+		return EvictionDiskPressure
+	case strings.Contains(msg, "low on resource: memory"):
+		return EvictionMemoryPressure
+	case strings.Contains(msg, "low on resource: pids"):
+		return EvictionPIDPressure
+	}
+	return EvictionOtherCause
+}
@@ -0,0 +1,113 @@
+package k8s
+
+import (
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testKubeletSummary = `{
+  "node": {
+    "nodeName": "node-a",
+    "fs": {"availableBytes": 10000000000, "capacityBytes": 100000000000, "usedBytes": 88000000000},
+    "runtime": {"imageFs": {"availableBytes": 60000000000, "capacityBytes": 100000000000, "usedBytes": 40000000000}}
+  },
+  "pods": [
+    {"podRef": {"name": "cache", "namespace": "shop"}, "ephemeral-storage": {"usedBytes": 900000000}},
+    {"podRef": {"name": "api", "namespace": "shop"}, "ephemeral-storage": {"usedBytes": 5000000}},
+    {"podRef": {"name": "log-shipper", "namespace": "infra"}, "ephemeral-storage": {"usedBytes": 2000000000}}
+  ]
+}`
+
+func storageTestPod(namespace, name, node string, limits ...string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       corev1.PodSpec{NodeName: node},
+	}
+	for _, limit := range limits {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{
+			Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+				corev1.ResourceEphemeralStorage: resource.MustParse(limit),
+			}},
+		})
+	}
+	return pod
+}
+
+func TestBuildStorageUsage(t *testing.T) {
+	var summary kubeletSummary
+	if err := json.Unmarshal([]byte(testKubeletSummary), &summary); err != nil {
+		t.Fatalf("failed to parse summary: %v", err)
+	}
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue},
+		}},
+	}
+	evicted := storageTestPod("shop", "old", "node-a")
+	evicted.Status = corev1.PodStatus{
+		Phase:   corev1.PodFailed,
+		Reason:  "Evicted",
+		Message: "The node was low on resource: ephemeral-storage. Threshold quantity: 10Gi, available: 9Gi.",
+	}
+	pods := []*corev1.Pod{
+		storageTestPod("shop", "cache", "node-a", "500M", "500M"), // 900M of 1G: near the limit
+		storageTestPod("shop", "api", "node-a", "1G"),
+		storageTestPod("infra", "log-shipper", "node-a"), // unlimited
+		evicted,
+	}
+
+	usage := buildStorageUsage([]*corev1.Node{node}, pods, map[string]*kubeletSummary{"node-a": &summary}, nil)
+
+	if len(usage.Nodes) != 1 {
+		t.Fatalf("expected 1 node, got %d", len(usage.Nodes))
+	}
+	n := usage.Nodes[0]
+	if !n.DiskPressure || n.EvictedPods != 1 {
+		t.Errorf("expected disk pressure with 1 evicted pod, got %+v", n)
+	}
+	if n.Filesystem == nil || n.Filesystem.UsedPercent != 90 {
+		t.Errorf("expected nodefs 90%% used (from available bytes), got %+v", n.Filesystem)
+	}
+	if n.ImageFs == nil || n.ImageFs.UsedPercent != 40 {
+		t.Errorf("expected imagefs 40%% used, got %+v", n.ImageFs)
+	}
+
+	if len(usage.Pods) != 3 {
+		t.Fatalf("expected 3 pods with usage, got %d", len(usage.Pods))
+	}
+	if top := usage.Pods[0]; top.Name != "cache" || !top.NearLimit || top.LimitBytes != 1_000_000_000 {
+		t.Errorf("expected cache first and near its 1G limit, got %+v", top)
+	}
+	for _, p := range usage.Pods[1:] {
+		if p.NearLimit {
+			t.Errorf("expected %s not to be near its limit, got %+v", p.Name, p)
+		}
+	}
+}
+
+func TestEvictionCause(t *testing.T) {
+	tests := []struct {
+		reason, message, want string
+	}{
+		{"Evicted", "The node was low on resource: ephemeral-storage. Container app was using 2Gi.", EvictionDiskPressure},
+		{"Evicted", "The node had condition: [DiskPressure].", EvictionDiskPressure},
+		{"Evicted", "Pod ephemeral local storage usage exceeds the total limit of containers 1Gi.", EvictionEphemeralLimit},
+		{"Evicted", `Container app exceeded its local ephemeral storage limit "1Gi".`, EvictionEphemeralLimit},
+		{"Evicted", `Usage of EmptyDir volume "cache" exceeds the limit "500Mi".`, EvictionEphemeralLimit},
+		{"Evicted", "The node was low on resource: memory.", EvictionMemoryPressure},
+		{"Evicted", "Preempted by a higher priority pod", EvictionOtherCause},
+		{"", "The node was low on resource: ephemeral-storage.", ""},
+	}
+	for _, tt := range tests {
+		pod := &corev1.Pod{Status: corev1.PodStatus{Reason: tt.reason, Message: tt.message}}
+		if got := EvictionCause(pod); got != tt.want {
+			t.Errorf("EvictionCause(%q, %q) = %q, want %q", tt.reason, tt.message, got, tt.want)
+		}
+	}
+}
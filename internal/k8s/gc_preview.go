@@ -0,0 +1,325 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ParsePropagation parses a deletion propagation policy (case-insensitive).
+// Empty means the API server's default for the kind.
+func ParsePropagation(value string) (metav1.DeletionPropagation, error) {
+	for _, p := range []metav1.DeletionPropagation{metav1.DeletePropagationBackground, metav1.DeletePropagationForeground, metav1.DeletePropagationOrphan} {
+		if strings.EqualFold(value, string(p)) {
+			return p, nil
+		}
+	}
+	if value == "" {
+		return "", nil
+	}
+	return "", fmt.Errorf("invalid propagation %q (expected background, foreground or orphan)", value)
+}
+
+// DefaultPropagation is the policy the API server applies when a delete
+// doesn't set one. Jobs and ReplicationControllers orphan their pods for
+// backwards compatibility; everything else cascades in the background.
+func DefaultPropagation(kind, group string) metav1.DeletionPropagation {
+	if (kind == "Job" && group == "batch") || (kind == "ReplicationController" && group == "") {
+		return metav1.DeletePropagationOrphan
+	}
+	return metav1.DeletePropagationBackground
+}
+
+// DependentResource is a resource a delete would cascade to
+type DependentResource struct {
+	Kind                string `json:"kind"`
+	Group               string `json:"group,omitempty"`
+	Namespace           string `json:"namespace,omitempty"`
+	Name                string `json:"name"`
+	UID                 string `json:"uid"`
+	Owner               string `json:"owner"` // Kind/name of the owner it cascades from
+	Depth               int    `json:"depth"` // 1 for direct dependents
+	BlocksOwnerDeletion bool   `json:"blocksOwnerDeletion,omitempty"`
+}
+
+// DeletePreview lists what the garbage collector would do with a resource's
+// dependents (found transitively through ownerReferences) if it were deleted
+type DeletePreview struct {
+	Resource    OwnerLink                  `json:"resource"`
+	Propagation metav1.DeletionPropagation `json:"propagation"` // Effective policy
+	Deleted     []DependentResource        `json:"deleted"`     // Cascaded deletes
+	Orphaned    []DependentResource        `json:"orphaned"`    // Keep running without the owner reference
+	Kept        []DependentResource        `json:"kept"`        // Also owned by resources that aren't deleted
+	Counts      map[string]int             `json:"counts"`      // Deleted dependents per kind
+	Total       int                        `json:"total"`       // len(Deleted)
+	Finalizers  []string                   `json:"finalizers,omitempty"`
+	Notes       []string                   `json:"notes,omitempty"`
+}
+
+// gcObject is a cached resource as the garbage collector sees it
+type gcObject struct {
+	kind  string
+	group string
+	obj   metav1.Object
+}
+
+// PreviewDelete resolves everything deleting a resource would cascade to,
+// from the typed and dynamic caches. An empty propagation previews the API
+// server's default for the kind.
+func (c *ResourceCache) PreviewDelete(ctx context.Context, kind, group, namespace, name string, propagation metav1.DeletionPropagation) (*DeletePreview, error) {
+	if c == nil {
+		return nil, fmt.Errorf("resource cache not initialized")
+	}
+	obj, err := c.getObject(ctx, kind, group, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if res, ok := GetResourceDiscovery().GetResource(kind); ok && (group == "" || group == res.Group) {
+		kind, group = res.Kind, res.Group
+	}
+	if propagation == "" {
+		propagation = DefaultPropagation(kind, group)
+	}
+	preview := previewCascade(gcObject{kind: kind, group: group, obj: obj}, cachedGCObjects(c, GetDynamicResourceCache()), propagation)
+	preview.Notes = append(preview.Notes, "Only resources Radar caches are checked; dependents of other kinds (e.g. EndpointSlices, ControllerRevisions) are removed too but not listed")
+	return preview, nil
+}
+
+// previewCascade simulates the garbage collector: with background or
+// foreground propagation a dependent is deleted once every one of its owners
+// is, and with orphan propagation direct dependents only lose the reference.
+// Owners that aren't part of the cascade are assumed to stay.
+func previewCascade(target gcObject, objects []gcObject, propagation metav1.DeletionPropagation) *DeletePreview {
+	p := &DeletePreview{
+		Resource: OwnerLink{
+			Kind:      target.kind,
+			Group:     target.group,
+			Namespace: target.obj.GetNamespace(),
+			Name:      target.obj.GetName(),
+			UID:       string(target.obj.GetUID()),
+		},
+		Propagation: propagation,
+		Deleted:     []DependentResource{},
+		Orphaned:    []DependentResource{},
+		Kept:        []DependentResource{},
+		Counts:      map[string]int{},
+		Finalizers:  target.obj.GetFinalizers(),
+	}
+	children := make(map[types.UID][]*gcObject)
+	for i := range objects {
+		for _, ref := range objects[i].obj.GetOwnerReferences() {
+			children[ref.UID] = append(children[ref.UID], &objects[i])
+		}
+	}
+
+	if propagation == metav1.DeletePropagationOrphan {
+		for _, child := range children[target.obj.GetUID()] {
+			p.Orphaned = append(p.Orphaned, dependentOf(child, &target, 1))
+		}
+		p.sort()
+		return p
+	}
+
+	// deleted maps each UID in the cascade to its depth (0 for the target)
+	deleted := map[types.UID]int{target.obj.GetUID(): 0}
+	allOwnersDeleted := func(o *gcObject) bool {
+		for _, ref := range o.obj.GetOwnerReferences() {
+			if _, ok := deleted[ref.UID]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+	cascade := []*gcObject{&target}
+	for i := 0; i < len(cascade); i++ {
+		parent := cascade[i]
+		for _, child := range children[parent.obj.GetUID()] {
+			if _, done := deleted[child.obj.GetUID()]; done || !allOwnersDeleted(child) {
+				continue
+			}
+			dep := dependentOf(child, parent, deleted[parent.obj.GetUID()]+1)
+			deleted[child.obj.GetUID()] = dep.Depth
+			p.Deleted = append(p.Deleted, dep)
+			p.Counts[dep.Kind]++
+			cascade = append(cascade, child)
+		}
+	}
+	// Dependents reached but also owned by something that stays
+	kept := make(map[types.UID]bool)
+	for _, parent := range cascade {
+		for _, child := range children[parent.obj.GetUID()] {
+			uid := child.obj.GetUID()
+			if _, done := deleted[uid]; done || kept[uid] {
+				continue
+			}
+			kept[uid] = true
+			p.Kept = append(p.Kept, dependentOf(child, parent, deleted[parent.obj.GetUID()]+1))
+		}
+	}
+	p.Total = len(p.Deleted)
+	if propagation == metav1.DeletePropagationForeground {
+		p.Notes = append(p.Notes, "Foreground: the resource remains, with a deletion timestamp, until dependents that block owner deletion are gone")
+	}
+	p.sort()
+	return p
+}
+
+// sort orders dependents by depth, then kind and name
+func (p *DeletePreview) sort() {
+	for _, list := range [][]DependentResource{p.Deleted, p.Orphaned, p.Kept} {
+		sort.SliceStable(list, func(i, j int) bool {
+			a, b := list[i], list[j]
+			if a.Depth != b.Depth {
+				return a.Depth < b.Depth
+			}
+			if a.Kind != b.Kind {
+				return a.Kind < b.Kind
+			}
+			if a.Namespace != b.Namespace {
+				return a.Namespace < b.Namespace
+			}
+			return a.Name < b.Name
+		})
+	}
+}
+
+func dependentOf(child, owner *gcObject, depth int) DependentResource {
+	dep := DependentResource{
+		Kind:      child.kind,
+		Group:     child.group,
+		Namespace: child.obj.GetNamespace(),
+		Name:      child.obj.GetName(),
+		UID:       string(child.obj.GetUID()),
+		Owner:     owner.kind + "/" + owner.obj.GetName(),
+		Depth:     depth,
+	}
+	for _, ref := range child.obj.GetOwnerReferences() {
+		if ref.UID == owner.obj.GetUID() && ref.BlockOwnerDeletion != nil && *ref.BlockOwnerDeletion {
+			dep.BlocksOwnerDeletion = true
+		}
+	}
+	return dep
+}
+
+// cachedGCObjects returns every resource in the typed cache and the dynamic
+// informers, skipping dynamic copies of typed kinds
+func cachedGCObjects(c *ResourceCache, d *DynamicResourceCache) []gcObject {
+	var objects []gcObject
+	add := func(kind, group string, obj metav1.Object) {
+		objects = append(objects, gcObject{kind: kind, group: group, obj: obj})
+	}
+	all := labels.Everything()
+	if c != nil {
+		if l := c.Pods(); l != nil {
+			items, _ := l.List(all)
+			for _, o := range items {
+				add("Pod", "", o)
+			}
+		}
+		if l := c.Deployments(); l != nil {
+			items, _ := l.List(all)
+			for _, o := range items {
+				add("Deployment", "apps", o)
+			}
+		}
+		if l := c.StatefulSets(); l != nil {
+			items, _ := l.List(all)
+			for _, o := range items {
+				add("StatefulSet", "apps", o)
+			}
+		}
+		if l := c.DaemonSets(); l != nil {
+			items, _ := l.List(all)
+			for _, o := range items {
+				add("DaemonSet", "apps", o)
+			}
+		}
+		if l := c.ReplicaSets(); l != nil {
+			items, _ := l.List(all)
+			for _, o := range items {
+				add("ReplicaSet", "apps", o)
+			}
+		}
+		if l := c.Jobs(); l != nil {
+			items, _ := l.List(all)
+			for _, o := range items {
+				add("Job", "batch", o)
+			}
+		}
+		if l := c.CronJobs(); l != nil {
+			items, _ := l.List(all)
+			for _, o := range items {
+				add("CronJob", "batch", o)
+			}
+		}
+		if l := c.Services(); l != nil {
+			items, _ := l.List(all)
+			for _, o := range items {
+				add("Service", "", o)
+			}
+		}
+		if l := c.Ingresses(); l != nil {
+			items, _ := l.List(all)
+			for _, o := range items {
+				add("Ingress", "networking.k8s.io", o)
+			}
+		}
+		if l := c.ConfigMaps(); l != nil {
+			items, _ := l.List(all)
+			for _, o := range items {
+				add("ConfigMap", "", o)
+			}
+		}
+		if l := c.Secrets(); l != nil {
+			items, _ := l.List(all)
+			for _, o := range items {
+				add("Secret", "", o)
+			}
+		}
+		if l := c.PersistentVolumeClaims(); l != nil {
+			items, _ := l.List(all)
+			for _, o := range items {
+				add("PersistentVolumeClaim", "", o)
+			}
+		}
+		if l := c.HorizontalPodAutoscalers(); l != nil {
+			items, _ := l.List(all)
+			for _, o := range items {
+				add("HorizontalPodAutoscaler", "autoscaling", o)
+			}
+		}
+		if l := c.Nodes(); l != nil {
+			items, _ := l.List(all)
+			for _, o := range items {
+				add("Node", "", o)
+			}
+		}
+		if l := c.Namespaces(); l != nil {
+			items, _ := l.List(all)
+			for _, o := range items {
+				add("Namespace", "", o)
+			}
+		}
+	}
+	if d != nil {
+		for _, gvr := range d.GetWatchedResources() {
+			if typedSearchGroups[gvr.Group] && IsKnownKind(gvr.Resource) {
+				continue
+			}
+			items, err := d.List(gvr, "")
+			if err != nil {
+				continue
+			}
+			kind := gvrToKind(gvr)
+			for _, u := range items {
+				add(kind, gvr.Group, u)
+			}
+		}
+	}
+	return objects
+}
@@ -0,0 +1,129 @@
+package k8s
+
+import (
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// gcTestObject builds an object owned by the given UIDs
+func gcTestObject(kind, name string, ownerUIDs ...string) gcObject {
+	meta := &metav1.ObjectMeta{Namespace: "apps", Name: name, UID: types.UID(name)}
+	for _, uid := range ownerUIDs {
+		meta.OwnerReferences = append(meta.OwnerReferences, metav1.OwnerReference{Kind: "Owner", Name: uid, UID: types.UID(uid)})
+	}
+	return gcObject{kind: kind, obj: meta}
+}
+
+func dependentNames(deps []DependentResource) []string {
+	names := make([]string, len(deps))
+	for i, d := range deps {
+		names[i] = fmt.Sprintf("%s@%d", d.Name, d.Depth)
+	}
+	return names
+}
+
+func gcTestCluster() (gcObject, []gcObject) {
+	deploy := gcTestObject("Deployment", "api")
+	return deploy, []gcObject{
+		deploy,
+		gcTestObject("ReplicaSet", "api-1", "api"),
+		gcTestObject("ReplicaSet", "api-2", "api"),
+		gcTestObject("Pod", "api-1-a", "api-1"),
+		gcTestObject("Pod", "api-1-b", "api-1"),
+		gcTestObject("Pod", "api-2-a", "api-2"),
+		// Also owned by something that isn't deleted
+		gcTestObject("ConfigMap", "shared", "api-1", "other"),
+		gcTestObject("Pod", "unrelated"),
+	}
+}
+
+func TestPreviewCascadeBackground(t *testing.T) {
+	target, objects := gcTestCluster()
+	p := previewCascade(target, objects, metav1.DeletePropagationBackground)
+
+	want := []string{"api-1@1", "api-2@1", "api-1-a@2", "api-1-b@2", "api-2-a@2"}
+	if got := dependentNames(p.Deleted); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected deleted %v, got %v", want, got)
+	}
+	if p.Total != 5 || p.Counts["ReplicaSet"] != 2 || p.Counts["Pod"] != 3 {
+		t.Errorf("unexpected counts: total %d, %v", p.Total, p.Counts)
+	}
+	if got := dependentNames(p.Kept); fmt.Sprint(got) != "[shared@2]" {
+		t.Errorf("expected the shared ConfigMap to be kept, got %v", got)
+	}
+	if len(p.Orphaned) != 0 {
+		t.Errorf("expected nothing orphaned, got %v", dependentNames(p.Orphaned))
+	}
+	if p.Deleted[2].Owner != "ReplicaSet/api-1" {
+		t.Errorf("expected pod owner ReplicaSet/api-1, got %s", p.Deleted[2].Owner)
+	}
+}
+
+func TestPreviewCascadeOrphan(t *testing.T) {
+	target, objects := gcTestCluster()
+	p := previewCascade(target, objects, metav1.DeletePropagationOrphan)
+
+	if len(p.Deleted) != 0 || p.Total != 0 {
+		t.Fatalf("expected nothing deleted, got %v", dependentNames(p.Deleted))
+	}
+	if got := dependentNames(p.Orphaned); fmt.Sprint(got) != "[api-1@1 api-2@1]" {
+		t.Errorf("expected the ReplicaSets to be orphaned, got %v", got)
+	}
+}
+
+func TestPreviewCascadeSharedOwnersBothDeleted(t *testing.T) {
+	// A dependent of two owners is deleted once the second one is reached
+	target := gcTestObject("CronJob", "nightly")
+	objects := []gcObject{
+		target,
+		gcTestObject("Job", "run-1", "nightly"),
+		gcTestObject("Job", "run-2", "nightly"),
+		gcTestObject("ConfigMap", "report", "run-1", "run-2"),
+	}
+	p := previewCascade(target, objects, metav1.DeletePropagationForeground)
+
+	want := []string{"run-1@1", "run-2@1", "report@2"}
+	if got := dependentNames(p.Deleted); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected deleted %v, got %v", want, got)
+	}
+	if len(p.Kept) != 0 {
+		t.Errorf("expected nothing kept, got %v", dependentNames(p.Kept))
+	}
+}
+
+func TestPreviewCascadeOwnershipCycle(t *testing.T) {
+	target := gcTestObject("Widget", "a", "b")
+	objects := []gcObject{target, gcTestObject("Widget", "b", "a")}
+	p := previewCascade(target, objects, metav1.DeletePropagationBackground)
+	if got := dependentNames(p.Deleted); fmt.Sprint(got) != "[b@1]" {
+		t.Fatalf("expected only b deleted, got %v", got)
+	}
+}
+
+func TestParsePropagation(t *testing.T) {
+	for value, want := range map[string]metav1.DeletionPropagation{
+		"":           "",
+		"background": metav1.DeletePropagationBackground,
+		"Foreground": metav1.DeletePropagationForeground,
+		"ORPHAN":     metav1.DeletePropagationOrphan,
+	} {
+		if got, err := ParsePropagation(value); err != nil || got != want {
+			t.Errorf("%q: expected %q, got %q (err %v)", value, want, got, err)
+		}
+	}
+	if _, err := ParsePropagation("cascade"); err == nil {
+		t.Error("expected an error for an unknown policy")
+	}
+}
+
+func TestDefaultPropagation(t *testing.T) {
+	if got := DefaultPropagation("Job", "batch"); got != metav1.DeletePropagationOrphan {
+		t.Errorf("expected Jobs to orphan by default, got %s", got)
+	}
+	if got := DefaultPropagation("Deployment", "apps"); got != metav1.DeletePropagationBackground {
+		t.Errorf("expected Deployments to cascade in the background, got %s", got)
+	}
+}
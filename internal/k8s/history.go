@@ -1,9 +1,11 @@
 package k8s
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/skyhook-io/radar/internal/timeline"
 	appsv1 "k8s.io/api/apps/v1"
@@ -34,6 +36,8 @@ func ComputeDiff(kind string, oldObj, newObj any) *DiffInfo {
 		changes, summaryParts = diffService(oldObj, newObj)
 	case "ConfigMap":
 		changes, summaryParts = diffConfigMap(oldObj, newObj)
+	case "Secret":
+		changes, summaryParts = diffSecret(oldObj, newObj)
 	case "Ingress":
 		changes, summaryParts = diffIngress(oldObj, newObj)
 	case "ReplicaSet":
@@ -62,6 +66,8 @@ func ComputeDiff(kind string, oldObj, newObj any) *DiffInfo {
 		changes, summaryParts = diffGateway(oldObj, newObj)
 	case "HTTPRoute", "GRPCRoute", "TCPRoute", "TLSRoute":
 		changes, summaryParts = diffGatewayRoute(oldObj, newObj)
+	case "ScaledObject", "ScaledJob":
+		changes, summaryParts = diffKEDAScaler(oldObj, newObj)
 	default:
 		return nil
 	}
@@ -69,6 +75,11 @@ func ComputeDiff(kind string, oldObj, newObj any) *DiffInfo {
 	if len(changes) == 0 {
 		return nil
 	}
+	// Diffs reach the timeline and every SSE client, so Secret values never
+	// leave through one, whichever differ produced it
+	if kind == "Secret" {
+		redactSecretValues(changes)
+	}
 
 	summary := ""
 	if len(summaryParts) > 0 {
@@ -399,7 +410,9 @@ func getLBAddresses(ingress []corev1.LoadBalancerIngress) []string {
 	return addrs
 }
 
-// diffConfigMap computes diff for ConfigMap resources
+// diffConfigMap reports which ConfigMap keys were added, removed, or
+// modified. Values can hold credentials or whole config files, so only their
+// sizes are recorded.
 func diffConfigMap(oldObj, newObj any) ([]FieldChange, []string) {
 	oldCM, ok1 := oldObj.(*corev1.ConfigMap)
 	newCM, ok2 := newObj.(*corev1.ConfigMap)
@@ -407,43 +420,135 @@ func diffConfigMap(oldObj, newObj any) ([]FieldChange, []string) {
 		return nil, nil
 	}
 
+	changes, summary := diffDataKeys("data", toBytesMap(oldCM.Data), toBytesMap(newCM.Data), dataValueSize)
+	binChanges, binSummary := diffDataKeys("binaryData", oldCM.BinaryData, newCM.BinaryData, dataValueSize)
+	return append(changes, binChanges...), append(summary, binSummary...)
+}
+
+// diffSecret reports which Secret keys were added, removed, or modified
+// without exposing their values (see secretValueDiff for the values)
+func diffSecret(oldObj, newObj any) ([]FieldChange, []string) {
+	oldSecret, ok1 := oldObj.(*corev1.Secret)
+	newSecret, ok2 := newObj.(*corev1.Secret)
+	if !ok1 || !ok2 {
+		return nil, nil
+	}
+
+	changes, summary := diffDataKeys("data", oldSecret.Data, newSecret.Data, func([]byte) any {
+		return redactedValue
+	})
+	if oldSecret.Type != newSecret.Type {
+		changes = append(changes, FieldChange{Path: "type", OldValue: string(oldSecret.Type), NewValue: string(newSecret.Type)})
+		summary = append(summary, fmt.Sprintf("type: %s -> %s", oldSecret.Type, newSecret.Type))
+	}
+	return changes, summary
+}
+
+// redactSecretValues replaces data and stringData values with markers: an
+// added or removed key keeps nil on its missing side, and a modified key
+// reads "<redacted>" -> "<changed>"
+func redactSecretValues(changes []FieldChange) {
+	for i := range changes {
+		f := &changes[i]
+		if !strings.HasPrefix(f.Path, "data.") && !strings.HasPrefix(f.Path, "stringData.") {
+			continue
+		}
+		if f.OldValue != nil {
+			f.OldValue = redactedValue
+		}
+		if f.NewValue != nil {
+			f.NewValue = redactedValue
+			if f.OldValue != nil {
+				f.NewValue = changedValue
+			}
+		}
+	}
+}
+
+const (
+	redactedValue      = "<redacted>"
+	changedValue       = "<changed>"
+	maxDiffValueLength = 256
+)
+
+// diffDataKeys emits one FieldChange per added, removed, or modified key,
+// rendering values with display. Keys are reported in sorted order.
+func diffDataKeys(field string, oldData, newData map[string][]byte, display func([]byte) any) ([]FieldChange, []string) {
 	var changes []FieldChange
-	var summary []string
+	var added, removed, modified []string
+
+	keys := make(map[string]bool, len(oldData)+len(newData))
+	for k := range oldData {
+		keys[k] = true
+	}
+	for k := range newData {
+		keys[k] = true
+	}
+	for _, k := range sortedKeys(keys) {
+		oldV, inOld := oldData[k]
+		newV, inNew := newData[k]
+		path := field + "." + k
+		switch {
+		case !inOld:
+			added = append(added, k)
+			changes = append(changes, FieldChange{Path: path, OldValue: nil, NewValue: display(newV)})
+		case !inNew:
+			removed = append(removed, k)
+			changes = append(changes, FieldChange{Path: path, OldValue: display(oldV), NewValue: nil})
+		case !bytes.Equal(oldV, newV):
+			modified = append(modified, k)
+			changes = append(changes, FieldChange{Path: path, OldValue: display(oldV), NewValue: display(newV)})
+		}
+	}
 
-	// Check data keys (not values for security)
-	oldKeys := getMapKeys(oldCM.Data)
-	newKeys := getMapKeys(newCM.Data)
+	var summary []string
+	prefix := ""
+	if field != "data" {
+		prefix = field + " "
+	}
+	if len(added) > 0 {
+		summary = append(summary, fmt.Sprintf("%sadded keys: %v", prefix, added))
+	}
+	if len(removed) > 0 {
+		summary = append(summary, fmt.Sprintf("%sremoved keys: %v", prefix, removed))
+	}
+	if len(modified) > 0 {
+		summary = append(summary, fmt.Sprintf("%smodified keys: %v", prefix, modified))
+	}
+	return changes, summary
+}
 
-	addedKeys := diffStringSlices(newKeys, oldKeys)
-	removedKeys := diffStringSlices(oldKeys, newKeys)
-	modifiedKeys := getModifiedKeys(oldCM.Data, newCM.Data)
+func toBytesMap(m map[string]string) map[string][]byte {
+	result := make(map[string][]byte, len(m))
+	for k, v := range m {
+		result[k] = []byte(v)
+	}
+	return result
+}
 
-	if len(addedKeys) > 0 {
-		changes = append(changes, FieldChange{
-			Path:     "data (added keys)",
-			OldValue: nil,
-			NewValue: addedKeys,
-		})
-		summary = append(summary, fmt.Sprintf("added keys: %v", addedKeys))
+// truncateDataValue shortens long values (kept for Secret reveals) so entries
+// stay small, cutting on a rune boundary
+func truncateDataValue(v string) string {
+	if len(v) <= maxDiffValueLength {
+		return v
 	}
-	if len(removedKeys) > 0 {
-		changes = append(changes, FieldChange{
-			Path:     "data (removed keys)",
-			OldValue: removedKeys,
-			NewValue: nil,
-		})
-		summary = append(summary, fmt.Sprintf("removed keys: %v", removedKeys))
+	return fmt.Sprintf("%s... (%d bytes)", runePrefix(v, maxDiffValueLength), len(v))
+}
+
+// runePrefix returns at most n bytes of v without splitting a rune
+func runePrefix(v string, n int) string {
+	if len(v) <= n {
+		return v
 	}
-	if len(modifiedKeys) > 0 {
-		changes = append(changes, FieldChange{
-			Path:     "data (modified keys)",
-			OldValue: modifiedKeys,
-			NewValue: modifiedKeys,
-		})
-		summary = append(summary, fmt.Sprintf("modified keys: %v", modifiedKeys))
+	for n > 0 && !utf8.RuneStart(v[n]) {
+		n--
 	}
+	return v[:n]
+}
 
-	return changes, summary
+// dataValueSize renders a ConfigMap value as its size, e.g. "<12 bytes>"
+func dataValueSize(v []byte) any {
+	return fmt.Sprintf("<%d bytes>", len(v))
 }
 
 // diffIngress computes diff for Ingress resources
@@ -935,6 +1040,20 @@ func diffNode(oldObj, newObj any) ([]FieldChange, []string) {
 		summary = append(summary, fmt.Sprintf("Ready: %s→%s", oldReady, newReady))
 	}
 
+	// Check pressure conditions (flapping MemoryPressure/PIDPressure shows up here)
+	for _, condType := range nodePressureConditions {
+		oldStatus := getNodeConditionStatus(oldNode, condType)
+		newStatus := getNodeConditionStatus(newNode, condType)
+		if oldStatus != newStatus {
+			changes = append(changes, FieldChange{
+				Path:     "status.conditions[" + string(condType) + "]",
+				OldValue: oldStatus,
+				NewValue: newStatus,
+			})
+			summary = append(summary, fmt.Sprintf("%s: %s→%s", condType, oldStatus, newStatus))
+		}
+	}
+
 	return changes, summary
 }
 
@@ -1585,24 +1704,6 @@ func getServicePorts(ports []corev1.ServicePort) []string {
 	return result
 }
 
-func getMapKeys(m map[string]string) []string {
-	keys := make([]string, 0, len(m))
-	for k := range m {
-		keys = append(keys, k)
-	}
-	return keys
-}
-
-func getModifiedKeys(old, new map[string]string) []string {
-	var modified []string
-	for k, oldV := range old {
-		if newV, ok := new[k]; ok && oldV != newV {
-			modified = append(modified, k)
-		}
-	}
-	return modified
-}
-
 func diffStringSlices(a, b []string) []string {
 	bMap := make(map[string]bool)
 	for _, s := range b {
@@ -1854,3 +1955,85 @@ func countAcceptedParents(parents []any) int {
 	}
 	return count
 }
+
+// diffKEDAScaler computes diff for KEDA ScaledObject and ScaledJob resources,
+// surfacing trigger activation/deactivation, pausing, and scale bounds
+func diffKEDAScaler(oldObj, newObj any) ([]FieldChange, []string) {
+	oldU, ok1 := oldObj.(*unstructured.Unstructured)
+	newU, ok2 := newObj.(*unstructured.Unstructured)
+	if !ok1 || !ok2 {
+		return nil, nil
+	}
+	oldScaler := ParseKEDAScaler(oldU)
+	newScaler := ParseKEDAScaler(newU)
+
+	var changes []FieldChange
+	var summary []string
+
+	if oldScaler.Active != newScaler.Active {
+		changes = append(changes, FieldChange{
+			Path:     "status.conditions[Active]",
+			OldValue: oldScaler.Active,
+			NewValue: newScaler.Active,
+		})
+		if newScaler.Active {
+			summary = append(summary, "activated")
+		} else {
+			summary = append(summary, "deactivated")
+		}
+	}
+
+	if oldScaler.Paused != newScaler.Paused {
+		changes = append(changes, FieldChange{
+			Path:     "paused",
+			OldValue: oldScaler.Paused,
+			NewValue: newScaler.Paused,
+		})
+		if newScaler.Paused {
+			summary = append(summary, "paused")
+		} else {
+			summary = append(summary, "resumed")
+		}
+	}
+
+	if oldScaler.Fallback != newScaler.Fallback {
+		changes = append(changes, FieldChange{
+			Path:     "status.conditions[Fallback]",
+			OldValue: oldScaler.Fallback,
+			NewValue: newScaler.Fallback,
+		})
+		if newScaler.Fallback {
+			summary = append(summary, "using fallback replicas")
+		} else {
+			summary = append(summary, "fallback cleared")
+		}
+	}
+
+	if oldScaler.MinReplicas != newScaler.MinReplicas {
+		changes = append(changes, FieldChange{
+			Path:     "spec.minReplicaCount",
+			OldValue: oldScaler.MinReplicas,
+			NewValue: newScaler.MinReplicas,
+		})
+		summary = append(summary, fmt.Sprintf("minReplicas: %d→%d", oldScaler.MinReplicas, newScaler.MinReplicas))
+	}
+	if oldScaler.MaxReplicas != newScaler.MaxReplicas {
+		changes = append(changes, FieldChange{
+			Path:     "spec.maxReplicaCount",
+			OldValue: oldScaler.MaxReplicas,
+			NewValue: newScaler.MaxReplicas,
+		})
+		summary = append(summary, fmt.Sprintf("maxReplicas: %d→%d", oldScaler.MaxReplicas, newScaler.MaxReplicas))
+	}
+
+	if len(oldScaler.Triggers) != len(newScaler.Triggers) {
+		changes = append(changes, FieldChange{
+			Path:     "spec.triggers",
+			OldValue: len(oldScaler.Triggers),
+			NewValue: len(newScaler.Triggers),
+		})
+		summary = append(summary, fmt.Sprintf("triggers: %d→%d", len(oldScaler.Triggers), len(newScaler.Triggers)))
+	}
+
+	return changes, summary
+}
@@ -0,0 +1,134 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestComputeDiff_ConfigMapKeys(t *testing.T) {
+	oldCM := &corev1.ConfigMap{
+		Data:       map[string]string{"LOG_LEVEL": "info", "TIMEOUT": "30s", "REMOVED": "x"},
+		BinaryData: map[string][]byte{"cert.der": {1, 2, 3}},
+	}
+	newCM := &corev1.ConfigMap{
+		Data:       map[string]string{"LOG_LEVEL": "debug", "TIMEOUT": "30s", "ADDED": strings.Repeat("a", 300)},
+		BinaryData: map[string][]byte{"cert.der": {1, 2, 3, 4}},
+	}
+
+	diff := ComputeDiff("ConfigMap", oldCM, newCM)
+	if diff == nil {
+		t.Fatal("expected a diff")
+	}
+	byPath := make(map[string]FieldChange)
+	for _, f := range diff.Fields {
+		byPath[f.Path] = f
+	}
+	if len(byPath) != 4 {
+		t.Fatalf("expected 4 key changes, got %+v", diff.Fields)
+	}
+	// Values are reported by size only
+	if f := byPath["data.LOG_LEVEL"]; f.OldValue != "<4 bytes>" || f.NewValue != "<5 bytes>" {
+		t.Errorf("unexpected LOG_LEVEL change: %+v", f)
+	}
+	if f := byPath["data.REMOVED"]; f.OldValue != "<1 bytes>" || f.NewValue != nil {
+		t.Errorf("unexpected REMOVED change: %+v", f)
+	}
+	if f := byPath["data.ADDED"]; f.OldValue != nil || f.NewValue != "<300 bytes>" {
+		t.Errorf("unexpected ADDED change: %+v", f)
+	}
+	if f := byPath["binaryData.cert.der"]; f.OldValue != "<3 bytes>" || f.NewValue != "<4 bytes>" {
+		t.Errorf("binary values should be reported by size: %+v", f)
+	}
+	if !strings.Contains(diff.Summary, "modified keys: [LOG_LEVEL]") || !strings.Contains(diff.Summary, "binaryData modified keys: [cert.der]") {
+		t.Errorf("unexpected summary: %s", diff.Summary)
+	}
+}
+
+func TestComputeDiff_SecretRedacted(t *testing.T) {
+	oldSecret := &corev1.Secret{Type: corev1.SecretTypeOpaque, Data: map[string][]byte{"password": []byte("hunter2"), "user": []byte("admin")}}
+	newSecret := &corev1.Secret{Type: corev1.SecretTypeOpaque, Data: map[string][]byte{"password": []byte("correct-horse"), "user": []byte("admin"), "token": []byte("t")}}
+
+	diff := ComputeDiff("Secret", oldSecret, newSecret)
+	if diff == nil || len(diff.Fields) != 2 {
+		t.Fatalf("expected password and token changes, got %+v", diff)
+	}
+	for _, f := range diff.Fields {
+		for _, v := range []any{f.OldValue, f.NewValue} {
+			if v != nil && v != redactedValue && v != changedValue {
+				t.Errorf("secret value leaked in %s: %v", f.Path, v)
+			}
+		}
+	}
+	if diff.Fields[0].Path != "data.password" || diff.Fields[0].NewValue != changedValue {
+		t.Errorf("expected modified key marked as changed, got %+v", diff.Fields[0])
+	}
+	if diff.Fields[1].Path != "data.token" || diff.Fields[1].OldValue != nil || diff.Fields[1].NewValue != redactedValue {
+		t.Errorf("expected added key with redacted value, got %+v", diff.Fields[1])
+	}
+	for _, secret := range []string{"hunter2", "correct-horse"} {
+		if strings.Contains(diff.Summary, secret) || strings.Contains(fmt.Sprint(diff.Fields), secret) {
+			t.Errorf("secret value %q leaked into diff %+v", secret, diff)
+		}
+	}
+
+	if diff := ComputeDiff("Secret", oldSecret, oldSecret.DeepCopy()); diff != nil {
+		t.Errorf("expected no diff for an unchanged secret, got %+v", diff)
+	}
+}
+
+func TestSecretValueDiffStore(t *testing.T) {
+	store := &secretValueDiffStore{bySecret: make(map[string][]SecretValueChange)}
+	oldSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "db"}, Data: map[string][]byte{"password": []byte("hunter2")}}
+	newSecret := oldSecret.DeepCopy()
+	newSecret.Data["password"] = []byte("correct-horse")
+
+	store.record(oldSecret, oldSecret.DeepCopy(), time.Now())
+	if got := store.get("shop", "db", true); len(got) != 0 {
+		t.Fatalf("expected no change for identical data, got %+v", got)
+	}
+
+	store.record(oldSecret, newSecret, time.Now())
+	redacted := store.get("shop", "db", false)
+	if len(redacted) != 1 || redacted[0].Fields[0].OldValue != redactedValue || redacted[0].Fields[0].NewValue != changedValue {
+		t.Fatalf("expected redacted change, got %+v", redacted)
+	}
+	revealed := store.get("shop", "db", true)
+	if revealed[0].Fields[0].OldValue != "hunter2" || revealed[0].Fields[0].NewValue != "correct-horse" {
+		t.Errorf("expected values on reveal, got %+v", revealed[0].Fields)
+	}
+	if again := store.get("shop", "db", true); again[0].Fields[0].NewValue != "correct-horse" {
+		t.Error("redacted reads must not modify the kept values")
+	}
+
+	for i := range maxSecretValueChanges + 2 {
+		next := newSecret.DeepCopy()
+		next.Data["password"] = fmt.Appendf(nil, "v%d", i)
+		store.record(newSecret, next, time.Now())
+	}
+	if got := store.get("shop", "db", false); len(got) != maxSecretValueChanges {
+		t.Errorf("expected %d kept changes, got %d", maxSecretValueChanges, len(got))
+	}
+
+	store.forget("shop", "db")
+	if got := store.get("shop", "db", true); len(got) != 0 || len(store.order) != 0 {
+		t.Errorf("expected changes dropped on delete, got %+v", got)
+	}
+}
+
+func TestTruncateDataValue(t *testing.T) {
+	// A multi-byte rune straddling the limit is dropped whole
+	v := strings.Repeat("a", maxDiffValueLength-1) + "é" + "tail"
+	got := truncateDataValue(v)
+	if !utf8.ValidString(got) || !strings.HasPrefix(got, strings.Repeat("a", maxDiffValueLength-1)+"...") {
+		t.Errorf("unexpected truncation %q", got)
+	}
+	if short := truncateDataValue("short"); short != "short" {
+		t.Errorf("short values should be kept, got %q", short)
+	}
+}
@@ -0,0 +1,112 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+
+	"k8s.io/client-go/rest"
+)
+
+// Radar talks to the API server with one identity (a kubeconfig user or, in
+// cluster, its service account), so audit logs attribute every change to it.
+// When Radar sits behind an auth proxy, requests made on behalf of a Radar
+// user carry that user, either as a User-Agent suffix the audit log records
+// or by impersonating them. Informers and other background work carry no user
+// and are unchanged.
+
+// IdentityMode selects how the Radar user is passed to the API server
+type IdentityMode string
+
+const (
+	IdentityOff         IdentityMode = ""
+	IdentityUserAgent   IdentityMode = "user-agent"  // Append "radar-user/<name>" to the User-Agent
+	IdentityImpersonate IdentityMode = "impersonate" // Send Impersonate-User; needs the "impersonate" verb and the user's own RBAC applies
+)
+
+var identityMode atomic.Value // IdentityMode
+
+// SetIdentityMode sets how request users are propagated to the API server
+func SetIdentityMode(mode IdentityMode) error {
+	switch mode {
+	case IdentityOff, IdentityUserAgent, IdentityImpersonate:
+		identityMode.Store(mode)
+		return nil
+	}
+	return fmt.Errorf("unknown identity mode %q: must be %s or %s", mode, IdentityUserAgent, IdentityImpersonate)
+}
+
+// GetIdentityMode returns the configured identity propagation mode
+func GetIdentityMode() IdentityMode {
+	mode, _ := identityMode.Load().(IdentityMode)
+	return mode
+}
+
+type userContextKey struct{}
+
+// WithUser returns a context whose API server calls are attributed to user
+func WithUser(ctx context.Context, user string) context.Context {
+	if user == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// UserFromContext returns the Radar user of ctx, or "" for background work
+func UserFromContext(ctx context.Context) string {
+	user, _ := ctx.Value(userContextKey{}).(string)
+	return user
+}
+
+// wrapIdentity is a rest.Config transport wrapper adding the request user
+func wrapIdentity(next http.RoundTripper) http.RoundTripper {
+	return &identityRoundTripper{next: next}
+}
+
+// WrapConfigForUser adds the wrappers Radar's own clients have (refreshed
+// credentials, user attribution) to a REST config built elsewhere, such as
+// Helm's. Helm doesn't pass contexts down to its requests, so the user is
+// bound to the config instead.
+func WrapConfigForUser(config *rest.Config, user string) *rest.Config {
+	config.Wrap(credentials.wrap)
+	config.Wrap(func(next http.RoundTripper) http.RoundTripper {
+		return &identityRoundTripper{next: next, user: user}
+	})
+	return config
+}
+
+type identityRoundTripper struct {
+	next http.RoundTripper
+	user string // Used when the request context carries no user
+}
+
+func (rt *identityRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	user := UserFromContext(req.Context())
+	if user == "" {
+		user = rt.user
+	}
+	if user == "" {
+		return rt.next.RoundTrip(req)
+	}
+	switch GetIdentityMode() {
+	case IdentityUserAgent:
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", userAgentWithUser(req.Header.Get("User-Agent"), user))
+	case IdentityImpersonate:
+		req = req.Clone(req.Context())
+		req.Header.Set("Impersonate-User", user)
+	}
+	return rt.next.RoundTrip(req)
+}
+
+// userAgentWithUser appends the user as a product token; it's escaped so a
+// crafted name can't forge other tokens in the audit log
+func userAgentWithUser(userAgent, user string) string {
+	token := "radar-user/" + url.PathEscape(user)
+	if userAgent == "" {
+		return token
+	}
+	return userAgent + " " + token
+}
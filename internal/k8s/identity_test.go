@@ -0,0 +1,78 @@
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+type requestRecorder struct{ req *http.Request }
+
+func (t *requestRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.req = req
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func TestIdentityRoundTripper(t *testing.T) {
+	defer func() { _ = SetIdentityMode(IdentityOff) }()
+
+	send := func(ctx context.Context) *http.Request {
+		next := &requestRecorder{}
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://apiserver/api/v1/pods", nil)
+		req.Header.Set("User-Agent", "radar/1.0")
+		if _, err := wrapIdentity(next).RoundTrip(req); err != nil {
+			t.Fatal(err)
+		}
+		if req.Header.Get("User-Agent") != "radar/1.0" || req.Header.Get("Impersonate-User") != "" {
+			t.Error("the caller's request must not be modified")
+		}
+		return next.req
+	}
+	userCtx := WithUser(context.Background(), "alice@example.com (admin)")
+
+	if got := send(userCtx); got.Header.Get("User-Agent") != "radar/1.0" {
+		t.Errorf("expected no change with propagation off, got %q", got.Header.Get("User-Agent"))
+	}
+
+	if err := SetIdentityMode(IdentityUserAgent); err != nil {
+		t.Fatal(err)
+	}
+	if got := send(userCtx).Header.Get("User-Agent"); got != "radar/1.0 radar-user/alice@example.com%20%28admin%29" {
+		t.Errorf("unexpected User-Agent %q", got)
+	}
+	if got := send(context.Background()).Header.Get("User-Agent"); got != "radar/1.0" {
+		t.Errorf("background calls should keep the User-Agent, got %q", got)
+	}
+
+	if err := SetIdentityMode(IdentityImpersonate); err != nil {
+		t.Fatal(err)
+	}
+	got := send(userCtx)
+	if got.Header.Get("Impersonate-User") != "alice@example.com (admin)" || got.Header.Get("User-Agent") != "radar/1.0" {
+		t.Errorf("expected impersonation header, got %v", got.Header)
+	}
+
+	if err := SetIdentityMode("header"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
+
+func TestWrapConfigForUser(t *testing.T) {
+	defer func() { _ = SetIdentityMode(IdentityOff) }()
+	if err := SetIdentityMode(IdentityImpersonate); err != nil {
+		t.Fatal(err)
+	}
+
+	// Helm's requests carry no user in their context; the config's user applies
+	next := &requestRecorder{}
+	config := WrapConfigForUser(&rest.Config{}, "bob")
+	req, _ := http.NewRequest(http.MethodGet, "https://apiserver/api/v1/secrets", nil)
+	if _, err := config.WrapTransport(next).RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	if got := next.req.Header.Get("Impersonate-User"); got != "bob" {
+		t.Errorf("expected the bound user, got %q", got)
+	}
+}
@@ -0,0 +1,235 @@
+package k8s
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// KEDAGroup is the API group of KEDA's ScaledObject and ScaledJob CRDs
+const KEDAGroup = "keda.sh"
+
+// kedaPausedAnnotations pause autoscaling, optionally at a fixed replica count
+var kedaPausedAnnotations = []string{"autoscaling.keda.sh/paused", "autoscaling.keda.sh/paused-replicas"}
+
+// KEDATrigger is one scaler trigger of a ScaledObject or ScaledJob
+type KEDATrigger struct {
+	Type           string            `json:"type"` // prometheus, kafka, cron, aws-sqs-queue, ...
+	Name           string            `json:"name,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+	MetricType     string            `json:"metricType,omitempty"`     // AverageValue, Value, Utilization
+	AuthRef        string            `json:"authRef,omitempty"`        // TriggerAuthentication / ClusterTriggerAuthentication name
+	HealthStatus   string            `json:"healthStatus,omitempty"`   // Happy or Failing, from status.health
+	HealthFailures int64             `json:"healthFailures,omitempty"` // Consecutive failures reported by KEDA
+}
+
+// KEDAScaler summarizes a ScaledObject or ScaledJob
+type KEDAScaler struct {
+	Kind        string        `json:"kind"` // ScaledObject or ScaledJob
+	Namespace   string        `json:"namespace"`
+	Name        string        `json:"name"`
+	TargetKind  string        `json:"targetKind"` // Deployment, StatefulSet, ... (Job for ScaledJob)
+	TargetName  string        `json:"targetName,omitempty"`
+	MinReplicas int64         `json:"minReplicas"`
+	MaxReplicas int64         `json:"maxReplicas"`
+	Current     *int64        `json:"current,omitempty"` // Current replicas (ScaledObject) or running Jobs (ScaledJob)
+	Active      bool          `json:"active"`            // At least one trigger is active
+	Ready       bool          `json:"ready"`
+	Paused      bool          `json:"paused"`
+	Fallback    bool          `json:"fallback,omitempty"` // Scaling on fallback replicas because triggers are failing
+	HPAName     string        `json:"hpaName,omitempty"`  // HPA KEDA manages for a ScaledObject
+	Message     string        `json:"message,omitempty"`  // Ready condition message when not ready
+	Triggers    []KEDATrigger `json:"triggers"`
+}
+
+// ParseKEDAScaler extracts KEDA scaling state from a ScaledObject or ScaledJob
+func ParseKEDAScaler(u *unstructured.Unstructured) KEDAScaler {
+	s := KEDAScaler{
+		Kind:      u.GetKind(),
+		Namespace: u.GetNamespace(),
+		Name:      u.GetName(),
+		Triggers:  []KEDATrigger{},
+	}
+
+	if s.Kind == "ScaledJob" {
+		s.TargetKind = "Job"
+		s.MaxReplicas = 100 // KEDA default for ScaledJob
+	} else {
+		s.TargetKind, _, _ = unstructured.NestedString(u.Object, "spec", "scaleTargetRef", "kind")
+		if s.TargetKind == "" {
+			s.TargetKind = "Deployment"
+		}
+		s.TargetName, _, _ = unstructured.NestedString(u.Object, "spec", "scaleTargetRef", "name")
+		s.MaxReplicas = 100 // KEDA default for ScaledObject
+		s.HPAName, _, _ = unstructured.NestedString(u.Object, "status", "hpaName")
+	}
+	if v, ok, _ := unstructured.NestedInt64(u.Object, "spec", "minReplicaCount"); ok {
+		s.MinReplicas = v
+	}
+	if v, ok, _ := unstructured.NestedInt64(u.Object, "spec", "maxReplicaCount"); ok {
+		s.MaxReplicas = v
+	}
+
+	annotations := u.GetAnnotations()
+	for _, a := range kedaPausedAnnotations {
+		if v, ok := annotations[a]; ok && v != "false" {
+			s.Paused = true
+		}
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, c := range conditions {
+		m, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		isTrue := m["status"] == "True"
+		switch m["type"] {
+		case "Active":
+			s.Active = isTrue
+		case "Ready":
+			s.Ready = isTrue
+			if !isTrue {
+				s.Message, _ = m["message"].(string)
+			}
+		case "Paused":
+			s.Paused = s.Paused || isTrue
+		case "Fallback":
+			s.Fallback = isTrue
+		}
+	}
+
+	health, _, _ := unstructured.NestedMap(u.Object, "status", "health")
+	triggers, _, _ := unstructured.NestedSlice(u.Object, "spec", "triggers")
+	for i, t := range triggers {
+		m, ok := t.(map[string]any)
+		if !ok {
+			continue
+		}
+		trigger := KEDATrigger{}
+		trigger.Type, _ = m["type"].(string)
+		trigger.Name, _ = m["name"].(string)
+		trigger.MetricType, _ = m["metricType"].(string)
+		trigger.AuthRef, _, _ = unstructured.NestedString(m, "authenticationRef", "name")
+		if md, ok := m["metadata"].(map[string]any); ok && len(md) > 0 {
+			trigger.Metadata = make(map[string]string, len(md))
+			for k, v := range md {
+				trigger.Metadata[k] = fmt.Sprint(v)
+			}
+		}
+		// status.health is keyed by metric name: s<index>-<type>[-<name>]
+		if th, ok := health[kedaMetricName(i, trigger)].(map[string]any); ok {
+			trigger.HealthStatus, _ = th["status"].(string)
+			trigger.HealthFailures, _, _ = unstructured.NestedInt64(th, "numberOfFailures")
+		}
+		s.Triggers = append(s.Triggers, trigger)
+	}
+	return s
+}
+
+// kedaMetricName mirrors the metric name KEDA generates for a trigger
+func kedaMetricName(index int, t KEDATrigger) string {
+	name := "s" + strconv.Itoa(index) + "-" + t.Type
+	if t.Name != "" {
+		name += "-" + t.Name
+	}
+	return name
+}
+
+// ListKEDAScalers returns ScaledObjects and ScaledJobs with their current scale.
+// installed is false when the KEDA CRDs aren't present.
+func ListKEDAScalers(namespaces []string) (scalers []KEDAScaler, installed bool, err error) {
+	dc := GetDynamicResourceCache()
+	discovery := GetResourceDiscovery()
+	if dc == nil || discovery == nil {
+		return nil, false, fmt.Errorf("dynamic resource cache not initialized")
+	}
+
+	scalers = []KEDAScaler{}
+	for _, kind := range []string{"ScaledObject", "ScaledJob"} {
+		gvr, ok := discovery.GetGVRWithGroup(kind, KEDAGroup)
+		if !ok {
+			continue
+		}
+		installed = true
+
+		namespace := ""
+		if len(namespaces) == 1 {
+			namespace = namespaces[0]
+		}
+		items, listErr := dc.List(gvr, namespace)
+		if listErr != nil {
+			return nil, true, fmt.Errorf("failed to list %s: %w", kind, listErr)
+		}
+		for _, item := range items {
+			if len(namespaces) > 1 && !slices.Contains(namespaces, item.GetNamespace()) {
+				continue
+			}
+			s := ParseKEDAScaler(item)
+			s.Current = kedaCurrentScale(&s)
+			scalers = append(scalers, s)
+		}
+	}
+
+	sort.Slice(scalers, func(i, j int) bool {
+		if scalers[i].Namespace != scalers[j].Namespace {
+			return scalers[i].Namespace < scalers[j].Namespace
+		}
+		return scalers[i].Name < scalers[j].Name
+	})
+	return scalers, installed, nil
+}
+
+// kedaCurrentScale reads replicas from the KEDA-managed HPA, or counts running Jobs for a ScaledJob
+func kedaCurrentScale(s *KEDAScaler) *int64 {
+	cache := GetResourceCache()
+	if cache == nil {
+		return nil
+	}
+
+	if s.Kind == "ScaledJob" {
+		jobLister := cache.Jobs()
+		if jobLister == nil {
+			return nil
+		}
+		jobs, err := jobLister.Jobs(s.Namespace).List(labels.Everything())
+		if err != nil {
+			return nil
+		}
+		var running int64
+		for _, job := range jobs {
+			if isOwnedBy(job, "ScaledJob", s.Name) && job.Status.Active > 0 {
+				running++
+			}
+		}
+		return &running
+	}
+
+	if s.HPAName == "" {
+		return nil
+	}
+	hpaLister := cache.HorizontalPodAutoscalers()
+	if hpaLister == nil {
+		return nil
+	}
+	hpa, err := hpaLister.HorizontalPodAutoscalers(s.Namespace).Get(s.HPAName)
+	if err != nil {
+		return nil
+	}
+	current := int64(hpa.Status.CurrentReplicas)
+	return &current
+}
+
+func isOwnedBy(job *batchv1.Job, kind, name string) bool {
+	for _, ref := range job.OwnerReferences {
+		if ref.Kind == kind && ref.Name == name {
+			return true
+		}
+	}
+	return false
+}
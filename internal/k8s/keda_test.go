@@ -0,0 +1,76 @@
+package k8s
+
+import (
+	"slices"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func kedaScaledObject(active string, maxReplicas int64) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "keda.sh/v1alpha1",
+		"kind":       "ScaledObject",
+		"metadata":   map[string]any{"name": "worker", "namespace": "jobs"},
+		"spec": map[string]any{
+			"scaleTargetRef":  map[string]any{"name": "worker"},
+			"minReplicaCount": int64(0),
+			"maxReplicaCount": maxReplicas,
+			"triggers": []any{
+				map[string]any{"type": "kafka", "metadata": map[string]any{"topic": "orders", "lagThreshold": "50"}},
+				map[string]any{"type": "cron", "name": "business-hours", "authenticationRef": map[string]any{"name": "creds"}},
+			},
+		},
+		"status": map[string]any{
+			"hpaName": "keda-hpa-worker",
+			"conditions": []any{
+				map[string]any{"type": "Ready", "status": "True"},
+				map[string]any{"type": "Active", "status": active},
+				map[string]any{"type": "Fallback", "status": "False"},
+			},
+			"health": map[string]any{
+				"s0-kafka": map[string]any{"status": "Failing", "numberOfFailures": int64(3)},
+			},
+		},
+	}}
+}
+
+func TestParseKEDAScaler(t *testing.T) {
+	s := ParseKEDAScaler(kedaScaledObject("True", 20))
+	if s.TargetKind != "Deployment" || s.TargetName != "worker" || s.HPAName != "keda-hpa-worker" {
+		t.Errorf("unexpected target: %+v", s)
+	}
+	if s.MinReplicas != 0 || s.MaxReplicas != 20 || !s.Active || !s.Ready || s.Paused || s.Fallback {
+		t.Errorf("unexpected scaling state: %+v", s)
+	}
+	if len(s.Triggers) != 2 {
+		t.Fatalf("expected 2 triggers, got %d", len(s.Triggers))
+	}
+	if kafka := s.Triggers[0]; kafka.HealthStatus != "Failing" || kafka.HealthFailures != 3 || kafka.Metadata["topic"] != "orders" {
+		t.Errorf("unexpected kafka trigger: %+v", kafka)
+	}
+	if cron := s.Triggers[1]; cron.AuthRef != "creds" || cron.HealthStatus != "" {
+		t.Errorf("unexpected cron trigger: %+v", cron)
+	}
+
+	paused := kedaScaledObject("False", 20)
+	paused.SetAnnotations(map[string]string{"autoscaling.keda.sh/paused-replicas": "0"})
+	if s := ParseKEDAScaler(paused); !s.Paused || s.Active {
+		t.Errorf("expected paused, inactive scaler: %+v", s)
+	}
+
+	job := &unstructured.Unstructured{Object: map[string]any{"kind": "ScaledJob", "metadata": map[string]any{"name": "batch"}}}
+	if s := ParseKEDAScaler(job); s.TargetKind != "Job" || s.MaxReplicas != 100 {
+		t.Errorf("unexpected ScaledJob defaults: %+v", s)
+	}
+}
+
+func TestDiffKEDAScaler(t *testing.T) {
+	changes, summary := diffKEDAScaler(kedaScaledObject("False", 10), kedaScaledObject("True", 20))
+	if len(changes) != 2 {
+		t.Errorf("expected 2 changes, got %+v", changes)
+	}
+	if !slices.Contains(summary, "activated") || !slices.Contains(summary, "maxReplicas: 10→20") {
+		t.Errorf("unexpected summary: %v", summary)
+	}
+}
@@ -0,0 +1,202 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Metrics from the kubelet summary API stand in for metrics.k8s.io on
+// clusters without metrics-server (kind, k3s with it disabled, minimal
+// installs), so usage panels degrade instead of disappearing. Values are
+// converted to the metrics-server formats (nanocores, Ki) so callers don't
+// care where they came from.
+
+// MetricsSourceKubelet marks metrics derived from kubelet summaries
+const MetricsSourceKubelet = "kubelet"
+
+// metricsAPICheckInterval is how long the metrics.k8s.io availability check
+// is trusted; metrics-server may be installed while Radar runs
+const metricsAPICheckInterval = time.Minute
+
+// kubeletCPUStats mirrors CPUStats from the kubelet summary API
+type kubeletCPUStats struct {
+	UsageNanoCores *uint64 `json:"usageNanoCores"`
+}
+
+// kubeletMemoryStats mirrors MemoryStats from the kubelet summary API.
+// Working set is what metrics-server reports as memory usage.
+type kubeletMemoryStats struct {
+	WorkingSetBytes *uint64 `json:"workingSetBytes"`
+}
+
+var metricsAPI struct {
+	mu        sync.Mutex
+	available bool
+	checked   time.Time
+}
+
+// MetricsAPIAvailable reports whether the cluster serves metrics.k8s.io
+func MetricsAPIAvailable() bool {
+	metricsAPI.mu.Lock()
+	defer metricsAPI.mu.Unlock()
+	if time.Since(metricsAPI.checked) < metricsAPICheckInterval {
+		return metricsAPI.available
+	}
+	disco := GetDiscoveryClient()
+	if disco == nil {
+		return false
+	}
+	_, err := disco.ServerResourcesForGroupVersion(podMetricsGVR.GroupVersion().String())
+	available := err == nil
+	if available != metricsAPI.available || metricsAPI.checked.IsZero() {
+		if available {
+			log.Printf("[metrics] metrics.k8s.io available")
+		} else {
+			log.Printf("[metrics] metrics.k8s.io not available, deriving usage from kubelet summaries")
+		}
+	}
+	metricsAPI.available = available
+	metricsAPI.checked = time.Now()
+	return available
+}
+
+// useKubeletMetrics reports whether usage should come from kubelet summaries.
+// The demo cluster serves metrics.k8s.io itself and has no kubelets.
+func useKubeletMetrics() bool {
+	return !IsDemoMode() && !MetricsAPIAvailable()
+}
+
+func formatNanoCores(v *uint64) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%dn", *v)
+}
+
+func formatWorkingSet(v *uint64) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%dKi", *v/1024)
+}
+
+// kubeletNodeMetrics converts a node's summary to NodeMetrics
+func kubeletNodeMetrics(name string, summary *kubeletSummary, at string) NodeMetrics {
+	m := NodeMetrics{Metadata: MetricsMeta{Name: name}, Timestamp: at, Source: MetricsSourceKubelet}
+	if summary.Node.CPU != nil {
+		m.Usage.CPU = formatNanoCores(summary.Node.CPU.UsageNanoCores)
+	}
+	if summary.Node.Memory != nil {
+		m.Usage.Memory = formatWorkingSet(summary.Node.Memory.WorkingSetBytes)
+	}
+	return m
+}
+
+// kubeletPodMetrics converts the pods in a node's summary to PodMetrics
+func kubeletPodMetrics(summary *kubeletSummary, at string) []PodMetrics {
+	pods := make([]PodMetrics, 0, len(summary.Pods))
+	for _, ps := range summary.Pods {
+		pm := PodMetrics{
+			Metadata:  MetricsMeta{Name: ps.PodRef.Name, Namespace: ps.PodRef.Namespace},
+			Timestamp: at,
+			Source:    MetricsSourceKubelet,
+		}
+		for _, c := range ps.Containers {
+			cm := ContainerMetrics{Name: c.Name}
+			if c.CPU != nil {
+				cm.Usage.CPU = formatNanoCores(c.CPU.UsageNanoCores)
+			}
+			if c.Memory != nil {
+				cm.Usage.Memory = formatWorkingSet(c.Memory.WorkingSetBytes)
+			}
+			pm.Containers = append(pm.Containers, cm)
+		}
+		pods = append(pods, pm)
+	}
+	return pods
+}
+
+// listKubeletMetrics reads usage for all nodes and their pods from kubelet
+// summaries. Nodes whose kubelet can't be reached are left out; it fails
+// only when no node answers.
+func listKubeletMetrics(ctx context.Context) ([]NodeMetrics, []PodMetrics, error) {
+	cache := GetResourceCache()
+	if cache == nil || cache.Nodes() == nil {
+		return nil, nil, fmt.Errorf("resource cache not initialized")
+	}
+	client := GetClient()
+	if client == nil {
+		return nil, nil, fmt.Errorf("kubernetes client not initialized")
+	}
+	nodes, err := cache.Nodes().List(labels.Everything())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+	sort.Strings(names)
+
+	summaries, errs := fetchKubeletSummaries(ctx, client, names)
+	if len(summaries) == 0 && len(errs) > 0 {
+		return nil, nil, fmt.Errorf("no kubelet summary available: %w", errs[names[0]])
+	}
+
+	at := time.Now().UTC().Format(time.RFC3339)
+	nodeMetrics := make([]NodeMetrics, 0, len(summaries))
+	var podMetrics []PodMetrics
+	for _, name := range names {
+		summary := summaries[name]
+		if summary == nil {
+			continue
+		}
+		nodeMetrics = append(nodeMetrics, kubeletNodeMetrics(name, summary, at))
+		podMetrics = append(podMetrics, kubeletPodMetrics(summary, at)...)
+	}
+	return nodeMetrics, podMetrics, nil
+}
+
+// getKubeletNodeSummary reads one node's kubelet summary
+func getKubeletNodeSummary(ctx context.Context, node string) (*kubeletSummary, error) {
+	client := GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("kubernetes client not initialized")
+	}
+	summaries, errs := fetchKubeletSummaries(ctx, client, []string{node})
+	if err := errs[node]; err != nil {
+		return nil, fmt.Errorf("failed to read kubelet summary for node %s: %w", node, err)
+	}
+	return summaries[node], nil
+}
+
+// getKubeletPodMetrics reads a pod's usage from its node's kubelet summary
+func getKubeletPodMetrics(ctx context.Context, namespace, name string) (*PodMetrics, error) {
+	cache := GetResourceCache()
+	if cache == nil || cache.Pods() == nil {
+		return nil, fmt.Errorf("resource cache not initialized")
+	}
+	pod, err := cache.Pods().Pods(namespace).Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod metrics: pod %s/%s not found", namespace, name)
+	}
+	if pod.Spec.NodeName == "" {
+		return nil, fmt.Errorf("failed to get pod metrics: pod %s/%s not found on a node yet", namespace, name)
+	}
+	summary, err := getKubeletNodeSummary(ctx, pod.Spec.NodeName)
+	if err != nil {
+		return nil, err
+	}
+	for _, pm := range kubeletPodMetrics(summary, time.Now().UTC().Format(time.RFC3339)) {
+		if pm.Metadata.Namespace == namespace && pm.Metadata.Name == name {
+			return &pm, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to get pod metrics: pod %s/%s not found in the kubelet summary", namespace, name)
+}
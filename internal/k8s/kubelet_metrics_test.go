@@ -0,0 +1,58 @@
+package k8s
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestKubeletMetricsConversion(t *testing.T) {
+	var summary kubeletSummary
+	if err := json.Unmarshal([]byte(`{
+  "node": {
+    "nodeName": "node-a",
+    "cpu": {"usageNanoCores": 1500000000},
+    "memory": {"workingSetBytes": 2147483648}
+  },
+  "pods": [
+    {
+      "podRef": {"name": "api", "namespace": "shop"},
+      "containers": [
+        {"name": "app", "cpu": {"usageNanoCores": 250000000}, "memory": {"workingSetBytes": 104857600}},
+        {"name": "sidecar"}
+      ]
+    }
+  ]
+}`), &summary); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	node := kubeletNodeMetrics("node-a", &summary, "2026-01-01T00:00:00Z")
+	if node.Source != MetricsSourceKubelet {
+		t.Errorf("expected source %q, got %q", MetricsSourceKubelet, node.Source)
+	}
+	if got := parseCPU(node.Usage.CPU); got != 1500000000 {
+		t.Errorf("node CPU = %s (%d nanocores), want 1500000000", node.Usage.CPU, got)
+	}
+	if got := parseMemory(node.Usage.Memory); got != 2147483648 {
+		t.Errorf("node memory = %s (%d bytes), want 2147483648", node.Usage.Memory, got)
+	}
+
+	pods := kubeletPodMetrics(&summary, "2026-01-01T00:00:00Z")
+	if len(pods) != 1 || pods[0].Metadata.Namespace != "shop" || pods[0].Metadata.Name != "api" {
+		t.Fatalf("unexpected pods %+v", pods)
+	}
+	containers := pods[0].Containers
+	if len(containers) != 2 {
+		t.Fatalf("expected 2 containers, got %d", len(containers))
+	}
+	if got := parseCPU(containers[0].Usage.CPU); got != 250000000 {
+		t.Errorf("container CPU = %s (%d nanocores), want 250000000", containers[0].Usage.CPU, got)
+	}
+	if got := parseMemory(containers[0].Usage.Memory); got != 104857600 {
+		t.Errorf("container memory = %s (%d bytes), want 104857600", containers[0].Usage.Memory, got)
+	}
+	// Stats can be missing for containers that just started
+	if containers[1].Usage.CPU != "" || containers[1].Usage.Memory != "" {
+		t.Errorf("expected empty usage for a container without stats, got %+v", containers[1].Usage)
+	}
+}
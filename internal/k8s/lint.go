@@ -0,0 +1,225 @@
+package k8s
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// LintMode controls whether best-practice findings are reported or block a save
+type LintMode string
+
+const (
+	LintOff    LintMode = ""       // No lint pass
+	LintWarn   LintMode = "warn"   // Report findings alongside the save result
+	LintStrict LintMode = "strict" // Reject the save when there are findings
+)
+
+// LintFinding is a best-practice problem found in a submitted manifest
+type LintFinding struct {
+	Severity string `json:"severity"` // error, warning
+	Rule     string `json:"rule"`     // missing-probe, missing-resources, privileged, host-namespace, latest-tag, deprecated-api, deprecated-field
+	Field    string `json:"field,omitempty"`
+	Message  string `json:"message"`
+}
+
+// ParseLintMode validates the lint query flag
+func ParseLintMode(s string) (LintMode, error) {
+	switch LintMode(strings.ToLower(s)) {
+	case LintOff, "false":
+		return LintOff, nil
+	case LintWarn, "true":
+		return LintWarn, nil
+	case LintStrict:
+		return LintStrict, nil
+	}
+	return LintOff, fmt.Errorf("invalid lint mode %q: must be warn or strict", s)
+}
+
+// deprecatedAPIVersions maps removed or deprecated group versions to their replacement
+var deprecatedAPIVersions = map[string]string{
+	"extensions/v1beta1":                   "apps/v1 or networking.k8s.io/v1",
+	"apps/v1beta1":                         "apps/v1",
+	"apps/v1beta2":                         "apps/v1",
+	"batch/v1beta1":                        "batch/v1",
+	"networking.k8s.io/v1beta1":            "networking.k8s.io/v1",
+	"policy/v1beta1":                       "policy/v1",
+	"autoscaling/v2beta1":                  "autoscaling/v2",
+	"autoscaling/v2beta2":                  "autoscaling/v2",
+	"rbac.authorization.k8s.io/v1beta1":    "rbac.authorization.k8s.io/v1",
+	"admissionregistration.k8s.io/v1beta1": "admissionregistration.k8s.io/v1",
+	"apiextensions.k8s.io/v1beta1":         "apiextensions.k8s.io/v1",
+	"storage.k8s.io/v1beta1":               "storage.k8s.io/v1",
+	"discovery.k8s.io/v1beta1":             "discovery.k8s.io/v1",
+	"flowcontrol.apiserver.k8s.io/v1beta2": "flowcontrol.apiserver.k8s.io/v1",
+	"certificates.k8s.io/v1beta1":          "certificates.k8s.io/v1",
+	"scheduling.k8s.io/v1beta1":            "scheduling.k8s.io/v1",
+	"node.k8s.io/v1beta1":                  "node.k8s.io/v1",
+	"events.k8s.io/v1beta1":                "events.k8s.io/v1",
+}
+
+// deprecatedAnnotations maps deprecated annotations (or prefixes ending in /)
+// to the field that replaces them
+var deprecatedAnnotations = map[string]string{
+	"kubernetes.io/ingress.class":                     "spec.ingressClassName",
+	"seccomp.security.alpha.kubernetes.io/pod":        "securityContext.seccompProfile",
+	"container.seccomp.security.alpha.kubernetes.io/": "securityContext.seccompProfile",
+	"container.apparmor.security.beta.kubernetes.io/": "securityContext.appArmorProfile",
+	"scheduler.alpha.kubernetes.io/critical-pod":      "spec.priorityClassName",
+}
+
+// LintYAML parses a manifest and runs best-practice checks on it
+func LintYAML(manifest string) ([]LintFinding, error) {
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal([]byte(manifest), &obj.Object); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	return LintObject(obj), nil
+}
+
+// LintObject checks a manifest for missing probes and resource limits,
+// privileged or host-namespace pods, unpinned images, and deprecated APIs,
+// annotations, and fields
+func LintObject(obj *unstructured.Unstructured) []LintFinding {
+	findings := []LintFinding{}
+	add := func(severity, rule, field, format string, args ...any) {
+		findings = append(findings, LintFinding{Severity: severity, Rule: rule, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if replacement, ok := deprecatedAPIVersions[obj.GetAPIVersion()]; ok {
+		add(SeverityWarning, "deprecated-api", "apiVersion", "%s %s is deprecated or removed; use %s", obj.GetAPIVersion(), obj.GetKind(), replacement)
+	}
+	lintAnnotations(obj.GetAnnotations(), "metadata.annotations", add)
+
+	if obj.GetKind() == "Service" {
+		if ip, ok, _ := unstructured.NestedString(obj.Object, "spec", "loadBalancerIP"); ok && ip != "" {
+			add(SeverityWarning, "deprecated-field", "spec.loadBalancerIP", "spec.loadBalancerIP is deprecated; use the provider's load balancer annotations")
+		}
+	}
+
+	path, batch := podSpecPath(obj.GetKind())
+	if path == nil {
+		return findings
+	}
+	if !batch && obj.GetKind() == "Pod" {
+		policy, _, _ := unstructured.NestedString(obj.Object, "spec", "restartPolicy")
+		batch = policy == string(corev1.RestartPolicyNever) || policy == string(corev1.RestartPolicyOnFailure)
+	}
+	specMap, ok, _ := unstructured.NestedMap(obj.Object, path...)
+	if !ok {
+		return findings
+	}
+	var spec corev1.PodSpec
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(specMap, &spec); err != nil {
+		return findings
+	}
+	prefix := strings.Join(path, ".")
+	if len(path) > 1 {
+		templateMeta := slices.Concat(path[:len(path)-1], []string{"metadata", "annotations"})
+		templateAnnotations, _, _ := unstructured.NestedStringMap(obj.Object, templateMeta...)
+		lintAnnotations(templateAnnotations, strings.Join(templateMeta, "."), add)
+	}
+
+	if spec.DeprecatedServiceAccount != "" {
+		add(SeverityWarning, "deprecated-field", prefix+".serviceAccount", "serviceAccount is deprecated; use serviceAccountName")
+	}
+	if spec.HostNetwork {
+		add(SeverityWarning, "host-namespace", prefix+".hostNetwork", "Pod shares the node's network namespace")
+	}
+	if spec.HostPID {
+		add(SeverityWarning, "host-namespace", prefix+".hostPID", "Pod shares the node's process namespace")
+	}
+	if spec.HostIPC {
+		add(SeverityWarning, "host-namespace", prefix+".hostIPC", "Pod shares the node's IPC namespace")
+	}
+
+	lintContainers := func(containers []corev1.Container, field string, init bool) {
+		for i, c := range containers {
+			cpath := fmt.Sprintf("%s.%s[%d]", prefix, field, i)
+			if sc := c.SecurityContext; sc != nil {
+				if sc.Privileged != nil && *sc.Privileged {
+					add(SeverityError, "privileged", cpath+".securityContext.privileged", "Container %q runs privileged with full access to the node", c.Name)
+				}
+				if sc.Capabilities != nil {
+					for _, capability := range sc.Capabilities.Add {
+						if capability == "ALL" || capability == "SYS_ADMIN" {
+							add(SeverityError, "privileged", cpath+".securityContext.capabilities.add", "Container %q adds capability %s", c.Name, capability)
+						}
+					}
+				}
+			}
+			if imageUsesLatest(c.Image) {
+				add(SeverityWarning, "latest-tag", cpath+".image", "Container %q image %q isn't pinned to a version", c.Name, c.Image)
+			}
+			if c.Resources.Limits.Memory().IsZero() {
+				add(SeverityWarning, "missing-resources", cpath+".resources.limits.memory", "Container %q has no memory limit", c.Name)
+			}
+			if c.Resources.Requests.Cpu().IsZero() && c.Resources.Limits.Cpu().IsZero() {
+				add(SeverityWarning, "missing-resources", cpath+".resources.requests.cpu", "Container %q has no CPU request", c.Name)
+			}
+			// Init containers and run-to-completion pods don't serve traffic
+			if init || batch {
+				continue
+			}
+			if c.ReadinessProbe == nil {
+				add(SeverityWarning, "missing-probe", cpath+".readinessProbe", "Container %q has no readiness probe", c.Name)
+			}
+			if c.LivenessProbe == nil {
+				add(SeverityWarning, "missing-probe", cpath+".livenessProbe", "Container %q has no liveness probe", c.Name)
+			}
+		}
+	}
+	lintContainers(spec.InitContainers, "initContainers", true)
+	lintContainers(spec.Containers, "containers", false)
+	return findings
+}
+
+// lintAnnotations flags deprecated annotations
+func lintAnnotations(annotations map[string]string, field string, add func(severity, rule, field, format string, args ...any)) {
+	keys := make([]string, 0, len(annotations))
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for deprecated, replacement := range deprecatedAnnotations {
+			if key == deprecated || (strings.HasSuffix(deprecated, "/") && strings.HasPrefix(key, deprecated)) {
+				add(SeverityWarning, "deprecated-field", field, "Annotation %s is deprecated; use %s", key, replacement)
+			}
+		}
+	}
+}
+
+// podSpecPath returns the path to the pod spec for kinds that embed one, and
+// whether the kind runs to completion
+func podSpecPath(kind string) (path []string, batch bool) {
+	switch kind {
+	case "Pod":
+		return []string{"spec"}, false
+	case "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "ReplicationController":
+		return []string{"spec", "template", "spec"}, false
+	case "Job":
+		return []string{"spec", "template", "spec"}, true
+	case "CronJob":
+		return []string{"spec", "jobTemplate", "spec", "template", "spec"}, true
+	}
+	return nil, false
+}
+
+// imageUsesLatest reports whether an image is untagged or tagged latest
+func imageUsesLatest(image string) bool {
+	if image == "" || strings.Contains(image, "@") {
+		return false
+	}
+	idx := strings.LastIndex(image, ":")
+	if idx <= strings.LastIndex(image, "/") {
+		return true
+	}
+	return image[idx+1:] == "latest"
+}
@@ -0,0 +1,122 @@
+package k8s
+
+import (
+	"testing"
+)
+
+func lintRules(findings []LintFinding) map[string][]string {
+	rules := make(map[string][]string)
+	for _, f := range findings {
+		rules[f.Rule] = append(rules[f.Rule], f.Field)
+	}
+	return rules
+}
+
+func TestLintYAML_Deployment(t *testing.T) {
+	findings, err := LintYAML(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    metadata:
+      annotations:
+        seccomp.security.alpha.kubernetes.io/pod: runtime/default
+    spec:
+      hostNetwork: true
+      initContainers:
+      - name: init
+        image: busybox:1.36
+        resources:
+          requests: {cpu: 10m}
+          limits: {memory: 16Mi}
+      containers:
+      - name: app
+        image: nginx
+        securityContext:
+          privileged: true
+      - name: sidecar
+        image: envoy:v1.30@sha256:abc
+        resources:
+          requests: {cpu: 100m}
+          limits: {memory: 128Mi}
+        readinessProbe: {tcpSocket: {port: 8080}}
+        livenessProbe: {tcpSocket: {port: 8080}}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := lintRules(findings)
+
+	if f := rules["privileged"]; len(f) != 1 || f[0] != "spec.template.spec.containers[0].securityContext.privileged" {
+		t.Errorf("privileged: %v", f)
+	}
+	if f := rules["latest-tag"]; len(f) != 1 || f[0] != "spec.template.spec.containers[0].image" {
+		t.Errorf("latest-tag: %v", f)
+	}
+	if f := rules["missing-probe"]; len(f) != 2 {
+		t.Errorf("expected readiness and liveness findings for app only, got %v", f)
+	}
+	if f := rules["missing-resources"]; len(f) != 2 {
+		t.Errorf("expected memory and CPU findings for app only, got %v", f)
+	}
+	if f := rules["host-namespace"]; len(f) != 1 {
+		t.Errorf("host-namespace: %v", f)
+	}
+	if f := rules["deprecated-field"]; len(f) != 1 || f[0] != "spec.template.metadata.annotations" {
+		t.Errorf("deprecated-field: %v", f)
+	}
+	for _, f := range findings {
+		if f.Rule == "privileged" && f.Severity != SeverityError {
+			t.Errorf("privileged should be an error, got %s", f.Severity)
+		}
+	}
+}
+
+func TestLintYAML_BatchAndDeprecatedAPI(t *testing.T) {
+	findings, err := LintYAML(`
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: report
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: job
+            image: report:2.1
+            resources:
+              requests: {cpu: 100m}
+              limits: {memory: 64Mi}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := lintRules(findings)
+	if len(rules["missing-probe"]) != 0 {
+		t.Errorf("batch workloads shouldn't need probes: %v", rules["missing-probe"])
+	}
+	if len(rules["deprecated-api"]) != 1 || len(findings) != 1 {
+		t.Errorf("expected only a deprecated-api finding, got %+v", findings)
+	}
+
+	// Non-workload kinds only get metadata checks
+	findings, err = LintYAML("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cfg\ndata:\n  k: v\n")
+	if err != nil || len(findings) != 0 {
+		t.Errorf("expected no findings for a ConfigMap, got %+v, %v", findings, err)
+	}
+}
+
+func TestParseLintMode(t *testing.T) {
+	for in, want := range map[string]LintMode{"": LintOff, "warn": LintWarn, "true": LintWarn, "STRICT": LintStrict} {
+		if got, err := ParseLintMode(in); err != nil || got != want {
+			t.Errorf("ParseLintMode(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseLintMode("loud"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
@@ -0,0 +1,448 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// DefaultDrainTimeout bounds how long a single node's drain may take
+const DefaultDrainTimeout = 5 * time.Minute
+
+// evictionRetryInterval is how long to wait before retrying an eviction a PDB refused
+const evictionRetryInterval = 5 * time.Second
+
+// Drain progress phases
+const (
+	DrainPhaseCordoned = "cordoned"
+	DrainPhaseEvicting = "evicting"
+	DrainPhaseBlocked  = "blocked" // Eviction refused by a PodDisruptionBudget, retrying
+	DrainPhaseEvicted  = "evicted"
+	DrainPhaseDrained  = "drained"
+	DrainPhaseFailed   = "failed"
+)
+
+// MaintenanceWorkload is a workload with replicas on the nodes under maintenance
+type MaintenanceWorkload struct {
+	Kind          string `json:"kind"`
+	Namespace     string `json:"namespace"`
+	Name          string `json:"name"`
+	Replicas      int    `json:"replicas"`      // Running pods across the cluster
+	OnTargetNodes int    `json:"onTargetNodes"` // Of those, pods on nodes under maintenance
+	// Every replica is on the target nodes (bare pods are never recreated)
+	LosesAllReplicas bool `json:"losesAllReplicas"`
+}
+
+// MaintenancePDB is a PodDisruptionBudget covering pods on the nodes under maintenance
+type MaintenancePDB struct {
+	Namespace          string `json:"namespace"`
+	Name               string `json:"name"`
+	DisruptionsAllowed int32  `json:"disruptionsAllowed"`
+	PodsOnTargetNodes  int    `json:"podsOnTargetNodes"`
+	MaxPodsPerNode     int    `json:"maxPodsPerNode"`
+	// A node holds more covered pods than the budget allows at once, so its
+	// drain waits for replacements to become ready (or stalls at 0)
+	Blocking bool `json:"blocking"`
+}
+
+// MaintenanceStep is one node's drain in the proposed order
+type MaintenanceStep struct {
+	Node             string   `json:"node"`
+	Pods             int      `json:"pods"`                       // Pods that will be evicted
+	DaemonSetPods    int      `json:"daemonSetPods"`              // DaemonSet and static pods, left in place as kubectl drain does
+	LocalStoragePods int      `json:"localStoragePods,omitempty"` // Pods whose emptyDir data is lost
+	AtRiskWorkloads  []string `json:"atRiskWorkloads,omitempty"`  // kind/namespace/name losing all replicas
+	BlockingPDBs     []string `json:"blockingPdbs,omitempty"`     // namespace/name
+}
+
+// MaintenancePlan is the redundancy impact and proposed drain order for a set of nodes
+type MaintenancePlan struct {
+	Nodes     []string              `json:"nodes"`
+	Order     []MaintenanceStep     `json:"order"`
+	Workloads []MaintenanceWorkload `json:"workloads"` // Losing all replicas first
+	PDBs      []MaintenancePDB      `json:"pdbs"`      // Blocking first
+	Warnings  []string              `json:"warnings"`
+}
+
+// DrainProgress reports one step of a node drain
+type DrainProgress struct {
+	Node      string    `json:"node"`
+	Phase     string    `json:"phase"`
+	Pod       string    `json:"pod,omitempty"` // namespace/name
+	Message   string    `json:"message,omitempty"`
+	Remaining int       `json:"remaining"` // Pods still to be evicted from the node
+	Time      time.Time `json:"time"`
+}
+
+// DrainOptions controls how pods are evicted from a node
+type DrainOptions struct {
+	GracePeriodSeconds *int64        // nil = each pod's own terminationGracePeriodSeconds
+	Timeout            time.Duration // 0 = DefaultDrainTimeout
+}
+
+// PlanMaintenance computes which workloads lose redundancy and which PDBs
+// would block if the given nodes were drained, and proposes a drain order
+func PlanMaintenance(ctx context.Context, nodeNames []string) (*MaintenancePlan, error) {
+	cache := GetResourceCache()
+	if cache == nil || cache.Nodes() == nil || cache.Pods() == nil {
+		return nil, fmt.Errorf("resource cache not initialized")
+	}
+
+	nodes, err := cache.Nodes().List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	pods, err := cache.Pods().List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	var pdbs []*policyv1.PodDisruptionBudget
+	var warnings []string
+	items, err := cache.ListDynamicWithGroup(ctx, "PodDisruptionBudget", "", "policy")
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("PodDisruptionBudgets unavailable: %v", err))
+	}
+	for _, item := range items {
+		var pdb policyv1.PodDisruptionBudget
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &pdb); err == nil {
+			pdbs = append(pdbs, &pdb)
+		}
+	}
+
+	plan := buildMaintenancePlan(nodeNames, nodes, pods, pdbs)
+	plan.Warnings = append(warnings, plan.Warnings...)
+	return plan, nil
+}
+
+// buildMaintenancePlan evaluates draining the target nodes against the
+// cluster's pods and PDBs
+func buildMaintenancePlan(targets []string, nodes []*corev1.Node, pods []*corev1.Pod, pdbs []*policyv1.PodDisruptionBudget) *MaintenancePlan {
+	plan := &MaintenancePlan{
+		Nodes:     []string{},
+		Order:     []MaintenanceStep{},
+		Workloads: []MaintenanceWorkload{},
+		PDBs:      []MaintenancePDB{},
+		Warnings:  []string{},
+	}
+
+	known := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		known[node.Name] = true
+	}
+	isTarget := make(map[string]bool, len(targets))
+	for _, name := range targets {
+		if isTarget[name] {
+			continue
+		}
+		if !known[name] {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("node %s not found", name))
+			continue
+		}
+		isTarget[name] = true
+		plan.Nodes = append(plan.Nodes, name)
+	}
+	if len(isTarget) > 0 && len(isTarget) == len(known) {
+		plan.Warnings = append(plan.Warnings, "every node is under maintenance; evicted pods have nowhere to go")
+	}
+
+	steps := make(map[string]*MaintenanceStep, len(plan.Nodes))
+	for _, name := range plan.Nodes {
+		steps[name] = &MaintenanceStep{Node: name}
+	}
+
+	// Count replicas per workload across the cluster and on the targets
+	workloads := make(map[workloadKey]*MaintenanceWorkload)
+	var evicted []*corev1.Pod
+	for _, pod := range pods {
+		if podTerminated(pod) {
+			continue
+		}
+		key := podWorkload(pod)
+		w, ok := workloads[key]
+		if !ok {
+			w = &MaintenanceWorkload{Kind: key.Kind, Namespace: key.Namespace, Name: key.Name}
+			workloads[key] = w
+		}
+		w.Replicas++
+
+		step := steps[pod.Spec.NodeName]
+		if step == nil {
+			continue
+		}
+		if isDaemonSetPod(pod) || isMirrorPod(pod) {
+			step.DaemonSetPods++
+			continue
+		}
+		w.OnTargetNodes++
+		step.Pods++
+		if hasLocalStorage(pod) {
+			step.LocalStoragePods++
+		}
+		evicted = append(evicted, pod)
+	}
+
+	for key, w := range workloads {
+		if w.OnTargetNodes == 0 {
+			continue
+		}
+		w.LosesAllReplicas = w.OnTargetNodes == w.Replicas || key.Kind == "Pod"
+		plan.Workloads = append(plan.Workloads, *w)
+		if !w.LosesAllReplicas {
+			continue
+		}
+		id := key.Kind + "/" + key.Namespace + "/" + key.Name
+		for _, pod := range evicted {
+			if podWorkload(pod) == key {
+				step := steps[pod.Spec.NodeName]
+				if !slices.Contains(step.AtRiskWorkloads, id) {
+					step.AtRiskWorkloads = append(step.AtRiskWorkloads, id)
+				}
+			}
+		}
+	}
+	sort.Slice(plan.Workloads, func(i, j int) bool {
+		a, b := plan.Workloads[i], plan.Workloads[j]
+		if a.LosesAllReplicas != b.LosesAllReplicas {
+			return a.LosesAllReplicas
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Kind+"/"+a.Name < b.Kind+"/"+b.Name
+	})
+
+	for _, pdb := range pdbs {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		perNode := make(map[string]int)
+		covered := 0
+		for _, pod := range evicted {
+			if pod.Namespace == pdb.Namespace && selector.Matches(labels.Set(pod.Labels)) {
+				perNode[pod.Spec.NodeName]++
+				covered++
+			}
+		}
+		if covered == 0 {
+			continue
+		}
+		p := MaintenancePDB{
+			Namespace:          pdb.Namespace,
+			Name:               pdb.Name,
+			DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+			PodsOnTargetNodes:  covered,
+		}
+		for node, count := range perNode {
+			p.MaxPodsPerNode = max(p.MaxPodsPerNode, count)
+			if count > int(p.DisruptionsAllowed) {
+				p.Blocking = true
+				steps[node].BlockingPDBs = append(steps[node].BlockingPDBs, pdb.Namespace+"/"+pdb.Name)
+			}
+		}
+		plan.PDBs = append(plan.PDBs, p)
+	}
+	sort.Slice(plan.PDBs, func(i, j int) bool {
+		a, b := plan.PDBs[i], plan.PDBs[j]
+		if a.Blocking != b.Blocking {
+			return a.Blocking
+		}
+		return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+	})
+
+	// Drain the least disruptive nodes first: replacements for their pods
+	// come up while the riskier nodes are still serving
+	for _, name := range plan.Nodes {
+		step := steps[name]
+		sort.Strings(step.AtRiskWorkloads)
+		sort.Strings(step.BlockingPDBs)
+		plan.Order = append(plan.Order, *step)
+	}
+	sort.SliceStable(plan.Order, func(i, j int) bool {
+		a, b := plan.Order[i], plan.Order[j]
+		if len(a.BlockingPDBs) != len(b.BlockingPDBs) {
+			return len(a.BlockingPDBs) < len(b.BlockingPDBs)
+		}
+		if len(a.AtRiskWorkloads) != len(b.AtRiskWorkloads) {
+			return len(a.AtRiskWorkloads) < len(b.AtRiskWorkloads)
+		}
+		if a.Pods != b.Pods {
+			return a.Pods < b.Pods
+		}
+		return a.Node < b.Node
+	})
+	return plan
+}
+
+// CordonNode marks a node unschedulable (or schedulable again)
+func CordonNode(ctx context.Context, name string, unschedulable bool) error {
+	client := GetClient()
+	if client == nil {
+		return fmt.Errorf("kubernetes client not initialized")
+	}
+	return cordonNode(ctx, client, name, unschedulable)
+}
+
+func cordonNode(ctx context.Context, client kubernetes.Interface, name string, unschedulable bool) error {
+	patch := fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, unschedulable)
+	if _, err := client.CoreV1().Nodes().Patch(ctx, name, types.MergePatchType, []byte(patch), metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to cordon node %s: %w", name, err)
+	}
+	return nil
+}
+
+// DrainNode cordons a node and evicts its pods through the Eviction API, so
+// PodDisruptionBudgets are honoured. DaemonSet and mirror pods are left in
+// place. Evictions refused by a PDB are retried until the timeout; the drain
+// finishes once every evicted pod is gone. progress is called serially.
+func DrainNode(ctx context.Context, name string, opts DrainOptions, progress func(DrainProgress)) error {
+	client := GetClient()
+	if client == nil {
+		return fmt.Errorf("kubernetes client not initialized")
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultDrainTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	remaining := 0
+	report := func(phase, pod, message string) {
+		mu.Lock()
+		defer mu.Unlock()
+		progress(DrainProgress{Node: name, Phase: phase, Pod: pod, Message: message, Remaining: remaining, Time: time.Now()})
+	}
+
+	if err := cordonNode(ctx, client, name, true); err != nil {
+		report(DrainPhaseFailed, "", err.Error())
+		return err
+	}
+
+	list, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: "spec.nodeName=" + name})
+	if err != nil {
+		err = fmt.Errorf("failed to list pods on node %s: %w", name, err)
+		report(DrainPhaseFailed, "", err.Error())
+		return err
+	}
+	var pods []*corev1.Pod
+	for i := range list.Items {
+		pod := &list.Items[i]
+		if !podTerminated(pod) && !isDaemonSetPod(pod) && !isMirrorPod(pod) {
+			pods = append(pods, pod)
+		}
+	}
+	remaining = len(pods)
+	report(DrainPhaseCordoned, "", fmt.Sprintf("%d pods to evict", len(pods)))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(pods))
+	for _, pod := range pods {
+		wg.Add(1)
+		go func(pod *corev1.Pod) {
+			defer wg.Done()
+			id := pod.Namespace + "/" + pod.Name
+			podClient := client.CoreV1().Pods(pod.Namespace)
+			if err := evictAndWait(ctx, podClient, pod, opts.GracePeriodSeconds, func(phase, message string) { report(phase, id, message) }); err != nil {
+				errs <- fmt.Errorf("%s: %w", id, err)
+				return
+			}
+			mu.Lock()
+			remaining--
+			mu.Unlock()
+			report(DrainPhaseEvicted, id, "")
+		}(pod)
+	}
+	wg.Wait()
+	close(errs)
+
+	if err := <-errs; err != nil {
+		err = fmt.Errorf("failed to drain node %s: %w", name, err)
+		report(DrainPhaseFailed, "", err.Error())
+		return err
+	}
+	report(DrainPhaseDrained, "", "")
+	return nil
+}
+
+// evictAndWait evicts a pod, retrying while a PDB refuses, then waits for it to be deleted
+func evictAndWait(ctx context.Context, pods corev1client.PodInterface, pod *corev1.Pod, gracePeriod *int64, report func(phase, message string)) error {
+	eviction := &policyv1.Eviction{
+		ObjectMeta:    metav1.ObjectMeta{Namespace: pod.Namespace, Name: pod.Name},
+		DeleteOptions: &metav1.DeleteOptions{GracePeriodSeconds: gracePeriod},
+	}
+
+	report(DrainPhaseEvicting, "")
+	for {
+		err := pods.EvictV1(ctx, eviction)
+		if err == nil || apierrors.IsNotFound(err) {
+			break
+		}
+		if !apierrors.IsTooManyRequests(err) {
+			return err
+		}
+		report(DrainPhaseBlocked, err.Error())
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for disruption budget: %w", err)
+		case <-time.After(evictionRetryInterval):
+		}
+	}
+
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for {
+		current, err := pods.Get(ctx, pod.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) || (err == nil && current.UID != pod.UID) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for pod deletion")
+		case <-ticker.C:
+		}
+	}
+}
+
+func podTerminated(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+func isDaemonSetPod(pod *corev1.Pod) bool {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller != nil && *ref.Controller && ref.Kind == "DaemonSet" {
+			return true
+		}
+	}
+	return false
+}
+
+// isMirrorPod reports whether a pod is the API mirror of a kubelet static pod
+func isMirrorPod(pod *corev1.Pod) bool {
+	_, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]
+	return ok
+}
+
+func hasLocalStorage(pod *corev1.Pod) bool {
+	for _, v := range pod.Spec.Volumes {
+		if v.EmptyDir != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package k8s
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func maintenanceTestPod(namespace, name, node, ownerKind, ownerName string, labels map[string]string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels},
+		Spec:       corev1.PodSpec{NodeName: node},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if ownerKind != "" {
+		pod.OwnerReferences = []metav1.OwnerReference{*controllerRef("apps/v1", ownerKind, ownerName)}
+	}
+	return pod
+}
+
+func TestBuildMaintenancePlan(t *testing.T) {
+	nodes := []*corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-b"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-c"}},
+	}
+	web := map[string]string{"app": "web"}
+	pods := []*corev1.Pod{
+		// web: 2 of 3 replicas on the targets, both on node-a behind a PDB allowing 1
+		maintenanceTestPod("shop", "web-1", "node-a", "StatefulSet", "web", web),
+		maintenanceTestPod("shop", "web-2", "node-a", "StatefulSet", "web", web),
+		maintenanceTestPod("shop", "web-3", "node-c", "StatefulSet", "web", web),
+		// cache: every replica on the targets
+		maintenanceTestPod("shop", "cache-0", "node-b", "StatefulSet", "cache", nil),
+		// left in place
+		maintenanceTestPod("kube-system", "fluent-bit-x", "node-b", "DaemonSet", "fluent-bit", nil),
+	}
+	completed := maintenanceTestPod("shop", "job-x", "node-b", "Job", "job", nil)
+	completed.Status.Phase = corev1.PodSucceeded
+	pods = append(pods, completed)
+
+	pdbs := []*policyv1.PodDisruptionBudget{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web"},
+		Spec:       policyv1.PodDisruptionBudgetSpec{Selector: &metav1.LabelSelector{MatchLabels: web}},
+		Status:     policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1},
+	}}
+
+	plan := buildMaintenancePlan([]string{"node-a", "node-b", "node-x"}, nodes, pods, pdbs)
+
+	if len(plan.Nodes) != 2 || len(plan.Warnings) != 1 {
+		t.Fatalf("expected 2 target nodes and a warning for node-x, got %v / %v", plan.Nodes, plan.Warnings)
+	}
+
+	if len(plan.Workloads) != 2 {
+		t.Fatalf("expected web and cache to be affected, got %+v", plan.Workloads)
+	}
+	if w := plan.Workloads[0]; w.Name != "cache" || !w.LosesAllReplicas {
+		t.Errorf("expected cache to lose all replicas first, got %+v", w)
+	}
+	if w := plan.Workloads[1]; w.Name != "web" || w.LosesAllReplicas || w.Replicas != 3 || w.OnTargetNodes != 2 {
+		t.Errorf("expected web to keep 1 of 3 replicas, got %+v", w)
+	}
+
+	if len(plan.PDBs) != 1 || !plan.PDBs[0].Blocking || plan.PDBs[0].MaxPodsPerNode != 2 {
+		t.Fatalf("expected the web PDB to block node-a's drain, got %+v", plan.PDBs)
+	}
+
+	if len(plan.Order) != 2 {
+		t.Fatalf("expected 2 steps, got %+v", plan.Order)
+	}
+	first, second := plan.Order[0], plan.Order[1]
+	if first.Node != "node-b" || first.Pods != 1 || first.DaemonSetPods != 1 || len(first.AtRiskWorkloads) != 1 {
+		t.Errorf("expected node-b first with cache at risk, got %+v", first)
+	}
+	if second.Node != "node-a" || len(second.BlockingPDBs) != 1 {
+		t.Errorf("expected node-a last behind its blocking PDB, got %+v", second)
+	}
+}
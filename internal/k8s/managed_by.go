@@ -0,0 +1,100 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ManagedByUnmanaged is the System of resources no Helm release, Argo CD
+// Application, or Flux object manages
+const ManagedByUnmanaged = "unmanaged"
+
+// ManagedBy is the system that manages a resource, resolved through its
+// owners (a Pod is managed by whatever manages its Deployment)
+type ManagedBy struct {
+	System    string `json:"system"`         // helm, argocd, flux (the OwnerVia* values) or unmanaged
+	Kind      string `json:"kind,omitempty"` // HelmRelease, Application, Kustomization
+	Group     string `json:"group,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"` // Release, Application or Kustomization name
+}
+
+// ManagedBySystems are the values accepted by ParseManagedByFilter
+var ManagedBySystems = []string{OwnerViaHelm, OwnerViaArgoCD, OwnerViaFlux, ManagedByUnmanaged}
+
+// ParseManagedByFilter parses a comma-separated list of systems (e.g.
+// "helm,unmanaged") into a set. "all" (or an empty list) matches everything
+// and returns a nil set.
+func ParseManagedByFilter(value string) (map[string]bool, error) {
+	filter := make(map[string]bool)
+	for _, system := range strings.Split(value, ",") {
+		system = strings.ToLower(strings.TrimSpace(system))
+		switch system {
+		case "":
+			continue
+		case "all":
+			return nil, nil
+		case "argo":
+			system = OwnerViaArgoCD
+		}
+		valid := false
+		for _, known := range ManagedBySystems {
+			valid = valid || system == known
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown managedBy %q (expected %s or all)", system, strings.Join(ManagedBySystems, ", "))
+		}
+		filter[system] = true
+	}
+	if len(filter) == 0 {
+		return nil, nil
+	}
+	return filter, nil
+}
+
+// GetManagedBy resolves the system managing obj, following controller owner
+// references through the cache. It never calls the API server, so it is
+// cheap enough to run for every item of a list.
+func (c *ResourceCache) GetManagedBy(obj metav1.Object) ManagedBy {
+	return resolveManagedBy(obj, func(kind, group, namespace, name string) (metav1.Object, error) {
+		if c == nil {
+			return nil, fmt.Errorf("resource cache not initialized")
+		}
+		owner, ok := c.getCachedObject(kind, group, namespace, name)
+		if !ok {
+			return nil, fmt.Errorf("%s %s/%s not cached", kind, namespace, name)
+		}
+		return owner, nil
+	})
+}
+
+// resolveManagedBy walks up obj's owners until one carries management
+// metadata. Owners that can't be looked up end the walk as unmanaged.
+func resolveManagedBy(obj metav1.Object, lookup ownerLookup) ManagedBy {
+	seen := make(map[string]bool)
+	for depth := 0; depth <= maxOwnerChainDepth; depth++ {
+		if link, ok := managerLink(obj); ok {
+			return ManagedBy{System: link.Via, Kind: link.Kind, Group: link.Group, Namespace: link.Namespace, Name: link.Name}
+		}
+		// Pods rendered from a chart carry Helm's standard labels but not its
+		// release annotations; used when the owner can't be followed
+		labels := obj.GetLabels()
+		helmFallback := strings.EqualFold(labels["app.kubernetes.io/managed-by"], "Helm") && labels["app.kubernetes.io/instance"] != ""
+
+		next, ok := nextOwner(obj)
+		if ok && !seen[ownerLinkKey(next)] {
+			seen[ownerLinkKey(next)] = true
+			if owner, err := lookup(next.Kind, next.Group, next.Namespace, next.Name); err == nil && owner != nil {
+				obj = owner
+				continue
+			}
+		}
+		if helmFallback {
+			return ManagedBy{System: OwnerViaHelm, Kind: "HelmRelease", Group: "helm.sh", Namespace: obj.GetNamespace(), Name: labels["app.kubernetes.io/instance"]}
+		}
+		break
+	}
+	return ManagedBy{System: ManagedByUnmanaged}
+}
@@ -0,0 +1,87 @@
+package k8s
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResolveManagedByThroughOwners(t *testing.T) {
+	argo := map[string]string{"argocd.argoproj.io/tracking-id": "argocd_shop:apps/Deployment:apps/api"}
+	objects := map[string]metav1.Object{
+		"apps/ReplicaSet/apps/api-7d9f": chainTestObject("apps", "api-7d9f", controllerRef("apps/v1", "Deployment", "api"), nil, nil),
+		"apps/Deployment/apps/api":      chainTestObject("apps", "api", nil, argo, nil),
+	}
+	pod := chainTestObject("apps", "api-7d9f-x2k", controllerRef("apps/v1", "ReplicaSet", "api-7d9f"), nil, nil)
+
+	got := resolveManagedBy(pod, mapLookup(objects))
+	want := ManagedBy{System: OwnerViaArgoCD, Kind: "Application", Group: "argoproj.io", Namespace: "argocd", Name: "shop"}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestResolveManagedByFluxOverHelm(t *testing.T) {
+	// helm-controller installs with Helm, so both sets of metadata are present
+	obj := chainTestObject("apps", "api", nil,
+		map[string]string{"meta.helm.sh/release-name": "api", "meta.helm.sh/release-namespace": "apps"},
+		map[string]string{"helm.toolkit.fluxcd.io/name": "api", "helm.toolkit.fluxcd.io/namespace": "flux-system"})
+
+	got := resolveManagedBy(obj, mapLookup(nil))
+	if got.System != OwnerViaFlux || got.Kind != "HelmRelease" || got.Namespace != "flux-system" {
+		t.Fatalf("expected Flux HelmRelease flux-system/api, got %+v", got)
+	}
+}
+
+func TestResolveManagedByHelmLabelFallback(t *testing.T) {
+	// The ReplicaSet isn't cached, but the pod carries the chart's labels
+	labels := map[string]string{"app.kubernetes.io/managed-by": "Helm", "app.kubernetes.io/instance": "shop"}
+	pod := chainTestObject("apps", "api-7d9f-x2k", controllerRef("apps/v1", "ReplicaSet", "api-7d9f"), nil, labels)
+
+	got := resolveManagedBy(pod, mapLookup(nil))
+	if got.System != OwnerViaHelm || got.Name != "shop" || got.Namespace != "apps" {
+		t.Fatalf("expected Helm release apps/shop, got %+v", got)
+	}
+}
+
+func TestResolveManagedByUnmanaged(t *testing.T) {
+	objects := map[string]metav1.Object{
+		"apps/ReplicaSet/apps/api-7d9f": chainTestObject("apps", "api-7d9f", controllerRef("apps/v1", "Deployment", "api"), nil, nil),
+		"apps/Deployment/apps/api":      chainTestObject("apps", "api", nil, nil, nil),
+	}
+	pod := chainTestObject("apps", "api-7d9f-x2k", controllerRef("apps/v1", "ReplicaSet", "api-7d9f"), nil, nil)
+
+	if got := resolveManagedBy(pod, mapLookup(objects)); got != (ManagedBy{System: ManagedByUnmanaged}) {
+		t.Fatalf("expected unmanaged, got %+v", got)
+	}
+}
+
+func TestResolveManagedByOwnerCycle(t *testing.T) {
+	objects := map[string]metav1.Object{
+		"apps/ReplicaSet/apps/a": chainTestObject("apps", "a", controllerRef("apps/v1", "ReplicaSet", "b"), nil, nil),
+		"apps/ReplicaSet/apps/b": chainTestObject("apps", "b", controllerRef("apps/v1", "ReplicaSet", "a"), nil, nil),
+	}
+	if got := resolveManagedBy(objects["apps/ReplicaSet/apps/a"], mapLookup(objects)); got.System != ManagedByUnmanaged {
+		t.Fatalf("expected unmanaged for an ownership cycle, got %+v", got)
+	}
+}
+
+func TestParseManagedByFilter(t *testing.T) {
+	filter, err := ParseManagedByFilter("helm, Argo,unmanaged")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(filter) != 3 || !filter[OwnerViaHelm] || !filter[OwnerViaArgoCD] || !filter[ManagedByUnmanaged] {
+		t.Fatalf("unexpected filter %v", filter)
+	}
+
+	for _, value := range []string{"", "all", "flux,all"} {
+		if filter, err := ParseManagedByFilter(value); err != nil || filter != nil {
+			t.Errorf("%q: expected no filter, got %v (err %v)", value, filter, err)
+		}
+	}
+
+	if _, err := ParseManagedByFilter("helm,terraform"); err == nil {
+		t.Fatal("expected an error for an unknown system")
+	}
+}
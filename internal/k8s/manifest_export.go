@@ -0,0 +1,532 @@
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// ExportFormat selects how an exported manifest bundle is laid out
+type ExportFormat string
+
+const (
+	ExportFormatYAML      ExportFormat = "yaml"      // Single multi-document YAML file
+	ExportFormatKustomize ExportFormat = "kustomize" // One file per resource plus kustomization.yaml
+	ExportFormatHelm      ExportFormat = "helm"      // Minimal chart with image and replicas as values
+)
+
+var (
+	// ErrUnsupportedExportKind is returned for kinds that don't have a pod template
+	ErrUnsupportedExportKind = errors.New("unsupported kind: must be Deployment, StatefulSet, DaemonSet, Job, or CronJob")
+	// ErrUnsupportedExportFormat is returned for unknown export formats
+	ErrUnsupportedExportFormat = errors.New("unsupported format: must be yaml, kustomize, or helm")
+)
+
+// Placeholders swapped for Helm template expressions after marshaling
+const (
+	helmImagePlaceholder    = "__RADAR_HELM_IMAGE__"
+	helmReplicasPlaceholder = "__RADAR_HELM_REPLICAS__"
+)
+
+// ExportedFile is one file of an exported bundle
+type ExportedFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// ManifestExport is a cleaned manifest bundle for a workload and the
+// resources it depends on, ready to commit to a GitOps repository
+type ManifestExport struct {
+	Format    ExportFormat   `json:"format"`
+	Kind      string         `json:"kind"`
+	Namespace string         `json:"namespace"`
+	Name      string         `json:"name"`
+	Resources []string       `json:"resources"`         // Kind/name of each exported resource
+	Skipped   []string       `json:"skipped,omitempty"` // Referenced resources left out (e.g. Secrets)
+	Files     []ExportedFile `json:"files"`
+}
+
+// exportObject is a cleaned resource ready to be rendered
+type exportObject struct {
+	kind   string
+	name   string
+	object map[string]any
+}
+
+// ParseExportFormat validates a format name, defaulting to plain YAML
+func ParseExportFormat(s string) (ExportFormat, error) {
+	switch ExportFormat(strings.ToLower(s)) {
+	case "", ExportFormatYAML:
+		return ExportFormatYAML, nil
+	case ExportFormatKustomize:
+		return ExportFormatKustomize, nil
+	case ExportFormatHelm:
+		return ExportFormatHelm, nil
+	}
+	return "", fmt.Errorf("%w (got %q)", ErrUnsupportedExportFormat, s)
+}
+
+// ExportWorkload exports a workload with the Services that select its pods,
+// the ConfigMaps its pod template references, and Ingresses routing to those
+// Services. Server-populated fields are stripped so the bundle can be applied
+// to a fresh cluster. Secrets are listed as skipped rather than exported.
+func ExportWorkload(kind, namespace, name string, format ExportFormat) (*ManifestExport, error) {
+	cache := GetResourceCache()
+	if cache == nil {
+		return nil, fmt.Errorf("resource cache not initialized")
+	}
+
+	workload, template, err := getExportWorkload(cache, kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	main, err := toExportObject(workload)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []exportObject
+	configMaps, secrets := podTemplateReferences(&template.Spec)
+	if lister := cache.ConfigMaps(); lister != nil {
+		for _, cmName := range configMaps {
+			cm, err := lister.ConfigMaps(namespace).Get(cmName)
+			if err != nil {
+				continue // Optional references may not exist
+			}
+			if obj, err := toExportObject(cm); err == nil {
+				objects = append(objects, obj)
+			}
+		}
+	}
+
+	services := make(map[string]bool)
+	if lister := cache.Services(); lister != nil {
+		svcs, _ := lister.Services(namespace).List(labels.Everything())
+		sort.Slice(svcs, func(i, j int) bool { return svcs[i].Name < svcs[j].Name })
+		for _, svc := range svcs {
+			if len(svc.Spec.Selector) == 0 || !labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(template.Labels)) {
+				continue
+			}
+			if obj, err := toExportObject(svc); err == nil {
+				objects = append(objects, obj)
+				services[svc.Name] = true
+			}
+		}
+	}
+
+	objects = append(objects, main)
+
+	if lister := cache.Ingresses(); lister != nil && len(services) > 0 {
+		ingresses, _ := lister.Ingresses(namespace).List(labels.Everything())
+		sort.Slice(ingresses, func(i, j int) bool { return ingresses[i].Name < ingresses[j].Name })
+		for _, ing := range ingresses {
+			if !ingressRoutesTo(ing, services) {
+				continue
+			}
+			if obj, err := toExportObject(ing); err == nil {
+				objects = append(objects, obj)
+			}
+		}
+	}
+
+	export := &ManifestExport{Format: format, Kind: main.kind, Namespace: namespace, Name: name}
+	for _, s := range secrets {
+		export.Skipped = append(export.Skipped, "Secret/"+s)
+	}
+	export.Files, err = renderExport(format, namespace, main, objects)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objects {
+		export.Resources = append(export.Resources, obj.kind+"/"+obj.name)
+	}
+	return export, nil
+}
+
+// getExportWorkload fetches a workload from the cache along with its pod template
+func getExportWorkload(cache *ResourceCache, kind, namespace, name string) (runtime.Object, *corev1.PodTemplateSpec, error) {
+	switch strings.ToLower(kind) {
+	case "deployment", "deployments":
+		if cache.Deployments() == nil {
+			return nil, nil, fmt.Errorf("deployments not available in cache")
+		}
+		d, err := cache.Deployments().Deployments(namespace).Get(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get deployment: %w", err)
+		}
+		d = d.DeepCopy()
+		d.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+		return d, &d.Spec.Template, nil
+	case "statefulset", "statefulsets":
+		if cache.StatefulSets() == nil {
+			return nil, nil, fmt.Errorf("statefulsets not available in cache")
+		}
+		s, err := cache.StatefulSets().StatefulSets(namespace).Get(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get statefulset: %w", err)
+		}
+		s = s.DeepCopy()
+		s.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("StatefulSet"))
+		return s, &s.Spec.Template, nil
+	case "daemonset", "daemonsets":
+		if cache.DaemonSets() == nil {
+			return nil, nil, fmt.Errorf("daemonsets not available in cache")
+		}
+		ds, err := cache.DaemonSets().DaemonSets(namespace).Get(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get daemonset: %w", err)
+		}
+		ds = ds.DeepCopy()
+		ds.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("DaemonSet"))
+		return ds, &ds.Spec.Template, nil
+	case "job", "jobs":
+		if cache.Jobs() == nil {
+			return nil, nil, fmt.Errorf("jobs not available in cache")
+		}
+		j, err := cache.Jobs().Jobs(namespace).Get(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get job: %w", err)
+		}
+		j = j.DeepCopy()
+		j.SetGroupVersionKind(batchv1.SchemeGroupVersion.WithKind("Job"))
+		return j, &j.Spec.Template, nil
+	case "cronjob", "cronjobs":
+		if cache.CronJobs() == nil {
+			return nil, nil, fmt.Errorf("cronjobs not available in cache")
+		}
+		cj, err := cache.CronJobs().CronJobs(namespace).Get(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get cronjob: %w", err)
+		}
+		cj = cj.DeepCopy()
+		cj.SetGroupVersionKind(batchv1.SchemeGroupVersion.WithKind("CronJob"))
+		return cj, &cj.Spec.JobTemplate.Spec.Template, nil
+	}
+	return nil, nil, fmt.Errorf("%w (got %q)", ErrUnsupportedExportKind, kind)
+}
+
+// toExportObject converts a typed object (with its GVK set) to a cleaned map
+func toExportObject(obj runtime.Object) (exportObject, error) {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	if gvk.Kind == "" {
+		// Objects from listers don't carry TypeMeta
+		switch obj.(type) {
+		case *corev1.ConfigMap:
+			gvk = corev1.SchemeGroupVersion.WithKind("ConfigMap")
+		case *corev1.Service:
+			gvk = corev1.SchemeGroupVersion.WithKind("Service")
+		case *networkingv1.Ingress:
+			gvk = networkingv1.SchemeGroupVersion.WithKind("Ingress")
+		}
+	}
+	m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return exportObject{}, fmt.Errorf("failed to convert %s: %w", gvk.Kind, err)
+	}
+	u := &unstructured.Unstructured{Object: m}
+	u.SetGroupVersionKind(gvk)
+	cleanExportObject(u.Object)
+	return exportObject{kind: gvk.Kind, name: u.GetName(), object: u.Object}, nil
+}
+
+// exportStrippedMetadata are metadata fields populated by the API server or controllers
+var exportStrippedMetadata = []string{
+	"uid", "resourceVersion", "generation", "creationTimestamp", "deletionTimestamp",
+	"deletionGracePeriodSeconds", "managedFields", "selfLink", "ownerReferences", "generateName", "finalizers",
+}
+
+// exportStrippedAnnotations are annotations written by kubectl or controllers
+var exportStrippedAnnotations = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+	"kubectl.kubernetes.io/restartedAt",
+	"deployment.kubernetes.io/revision",
+}
+
+// exportStrippedJobLabels are labels the Job controller adds to its selector and pods
+var exportStrippedJobLabels = []string{
+	"controller-uid", "batch.kubernetes.io/controller-uid", "job-name", "batch.kubernetes.io/job-name",
+}
+
+// cleanExportObject strips status and server-populated fields in place
+func cleanExportObject(obj map[string]any) {
+	delete(obj, "status")
+	cleanExportMetadata(obj, "metadata")
+
+	kind, _, _ := unstructured.NestedString(obj, "kind")
+	switch kind {
+	case "Service":
+		unstructured.RemoveNestedField(obj, "spec", "clusterIP")
+		unstructured.RemoveNestedField(obj, "spec", "clusterIPs")
+		unstructured.RemoveNestedField(obj, "spec", "healthCheckNodePort")
+		// Node ports are allocated by the API server and may clash in another cluster
+		if ports, ok, _ := unstructured.NestedSlice(obj, "spec", "ports"); ok {
+			for _, p := range ports {
+				if pm, ok := p.(map[string]any); ok {
+					delete(pm, "nodePort")
+				}
+			}
+			_ = unstructured.SetNestedSlice(obj, ports, "spec", "ports")
+		}
+	case "Deployment", "StatefulSet", "DaemonSet":
+		cleanExportMetadata(obj, "spec", "template", "metadata")
+	case "Job":
+		cleanExportJobSpec(obj, "spec")
+	case "CronJob":
+		cleanExportMetadata(obj, "spec", "jobTemplate", "metadata")
+		cleanExportJobSpec(obj, "spec", "jobTemplate", "spec")
+	}
+}
+
+// cleanExportJobSpec removes the controller-generated selector and pod labels
+func cleanExportJobSpec(obj map[string]any, path ...string) {
+	unstructured.RemoveNestedField(obj, slices.Concat(path, []string{"selector"})...)
+	templateMeta := slices.Concat(path, []string{"template", "metadata"})
+	cleanExportMetadata(obj, templateMeta...)
+	for _, l := range exportStrippedJobLabels {
+		unstructured.RemoveNestedField(obj, slices.Concat(templateMeta, []string{"labels", l})...)
+	}
+	removeEmptyMap(obj, slices.Concat(templateMeta, []string{"labels"})...)
+	removeEmptyMap(obj, templateMeta...)
+}
+
+// cleanExportMetadata strips server fields from the metadata map at path
+func cleanExportMetadata(obj map[string]any, path ...string) {
+	meta, ok, _ := unstructured.NestedMap(obj, path...)
+	if !ok {
+		return
+	}
+	for _, f := range exportStrippedMetadata {
+		delete(meta, f)
+	}
+	if annotations, ok := meta["annotations"].(map[string]any); ok {
+		for _, a := range exportStrippedAnnotations {
+			delete(annotations, a)
+		}
+		if len(annotations) == 0 {
+			delete(meta, "annotations")
+		}
+	}
+	if len(meta) == 0 {
+		unstructured.RemoveNestedField(obj, path...)
+		return
+	}
+	_ = unstructured.SetNestedMap(obj, meta, path...)
+}
+
+func removeEmptyMap(obj map[string]any, path ...string) {
+	if m, ok, _ := unstructured.NestedMap(obj, path...); ok && len(m) == 0 {
+		unstructured.RemoveNestedField(obj, path...)
+	}
+}
+
+// podTemplateReferences returns the ConfigMaps and Secrets a pod spec uses
+// through volumes and environment variables
+func podTemplateReferences(spec *corev1.PodSpec) (configMaps, secrets []string) {
+	cms := make(map[string]bool)
+	scs := make(map[string]bool)
+	for _, v := range spec.Volumes {
+		if v.ConfigMap != nil {
+			cms[v.ConfigMap.Name] = true
+		}
+		if v.Secret != nil {
+			scs[v.Secret.SecretName] = true
+		}
+		if v.Projected != nil {
+			for _, src := range v.Projected.Sources {
+				if src.ConfigMap != nil {
+					cms[src.ConfigMap.Name] = true
+				}
+				if src.Secret != nil {
+					scs[src.Secret.Name] = true
+				}
+			}
+		}
+	}
+	containers := slices.Concat(spec.InitContainers, spec.Containers)
+	for _, c := range containers {
+		for _, ef := range c.EnvFrom {
+			if ef.ConfigMapRef != nil {
+				cms[ef.ConfigMapRef.Name] = true
+			}
+			if ef.SecretRef != nil {
+				scs[ef.SecretRef.Name] = true
+			}
+		}
+		for _, e := range c.Env {
+			if e.ValueFrom == nil {
+				continue
+			}
+			if e.ValueFrom.ConfigMapKeyRef != nil {
+				cms[e.ValueFrom.ConfigMapKeyRef.Name] = true
+			}
+			if e.ValueFrom.SecretKeyRef != nil {
+				scs[e.ValueFrom.SecretKeyRef.Name] = true
+			}
+		}
+	}
+	for _, ps := range spec.ImagePullSecrets {
+		scs[ps.Name] = true
+	}
+	// Injected into every namespace for service account tokens
+	delete(cms, "kube-root-ca.crt")
+	delete(cms, "")
+	delete(scs, "")
+	return sortedKeys(cms), sortedKeys(scs)
+}
+
+// ingressRoutesTo reports whether an Ingress sends traffic to any of the services
+func ingressRoutesTo(ing *networkingv1.Ingress, services map[string]bool) bool {
+	if b := ing.Spec.DefaultBackend; b != nil && b.Service != nil && services[b.Service.Name] {
+		return true
+	}
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, p := range rule.HTTP.Paths {
+			if p.Backend.Service != nil && services[p.Backend.Service.Name] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// renderExport lays out the cleaned objects in the requested format
+func renderExport(format ExportFormat, namespace string, main exportObject, objects []exportObject) ([]ExportedFile, error) {
+	switch format {
+	case ExportFormatYAML:
+		var docs []string
+		for _, obj := range objects {
+			data, err := yaml.Marshal(obj.object)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal %s/%s: %w", obj.kind, obj.name, err)
+			}
+			docs = append(docs, string(data))
+		}
+		return []ExportedFile{{Path: main.name + ".yaml", Content: strings.Join(docs, "---\n")}}, nil
+
+	case ExportFormatKustomize:
+		files := []ExportedFile{}
+		var resources []string
+		for _, obj := range objects {
+			// The kustomization sets the namespace so the base can be reused
+			unstructured.RemoveNestedField(obj.object, "metadata", "namespace")
+			data, err := yaml.Marshal(obj.object)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal %s/%s: %w", obj.kind, obj.name, err)
+			}
+			path := exportFileName(obj)
+			resources = append(resources, path)
+			files = append(files, ExportedFile{Path: path, Content: string(data)})
+		}
+		kustomization, err := yaml.Marshal(struct {
+			APIVersion string   `json:"apiVersion"`
+			Kind       string   `json:"kind"`
+			Namespace  string   `json:"namespace"`
+			Resources  []string `json:"resources"`
+		}{"kustomize.config.k8s.io/v1beta1", "Kustomization", namespace, resources})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal kustomization: %w", err)
+		}
+		return append([]ExportedFile{{Path: "kustomization.yaml", Content: string(kustomization)}}, files...), nil
+
+	case ExportFormatHelm:
+		return renderHelmChart(main, objects)
+	}
+	return nil, fmt.Errorf("%w (got %q)", ErrUnsupportedExportFormat, format)
+}
+
+// renderHelmChart scaffolds a chart whose templates are the cleaned manifests,
+// with the main container image and replica count lifted into values.yaml
+func renderHelmChart(main exportObject, objects []exportObject) ([]ExportedFile, error) {
+	values := map[string]any{}
+	appVersion := ""
+	containersPath := workloadContainersPath(main.kind)
+	if containers, ok, _ := unstructured.NestedSlice(main.object, containersPath...); ok && len(containers) > 0 {
+		if c, ok := containers[0].(map[string]any); ok {
+			image, _ := c["image"].(string)
+			// Digest-pinned images are left in the template as-is
+			if repo, tag, ok := splitImageTag(image); ok {
+				values["image"] = map[string]any{"repository": repo, "tag": tag}
+				appVersion = tag
+				c["image"] = helmImagePlaceholder
+				_ = unstructured.SetNestedSlice(main.object, containers, containersPath...)
+			}
+		}
+	}
+	if replicas, ok, _ := unstructured.NestedInt64(main.object, "spec", "replicas"); ok {
+		values["replicaCount"] = replicas
+		_ = unstructured.SetNestedField(main.object, helmReplicasPlaceholder, "spec", "replicas")
+	}
+
+	chart, err := yaml.Marshal(struct {
+		APIVersion  string `json:"apiVersion"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Type        string `json:"type"`
+		Version     string `json:"version"`
+		AppVersion  string `json:"appVersion,omitempty"`
+	}{"v2", main.name, fmt.Sprintf("Exported from %s %s", main.kind, main.name), "application", "0.1.0", appVersion})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Chart.yaml: %w", err)
+	}
+	valuesData, err := yaml.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal values.yaml: %w", err)
+	}
+
+	files := []ExportedFile{
+		{Path: "Chart.yaml", Content: string(chart)},
+		{Path: "values.yaml", Content: string(valuesData)},
+	}
+	replacer := strings.NewReplacer(
+		helmImagePlaceholder, `"{{ .Values.image.repository }}:{{ .Values.image.tag }}"`,
+		helmReplicasPlaceholder, "{{ .Values.replicaCount }}",
+	)
+	for _, obj := range objects {
+		// Helm installs into the release namespace
+		unstructured.RemoveNestedField(obj.object, "metadata", "namespace")
+		data, err := yaml.Marshal(obj.object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s/%s: %w", obj.kind, obj.name, err)
+		}
+		files = append(files, ExportedFile{Path: "templates/" + exportFileName(obj), Content: replacer.Replace(string(data))})
+	}
+	return files, nil
+}
+
+// workloadContainersPath returns the path to a workload's pod containers
+func workloadContainersPath(kind string) []string {
+	if kind == "CronJob" {
+		return []string{"spec", "jobTemplate", "spec", "template", "spec", "containers"}
+	}
+	return []string{"spec", "template", "spec", "containers"}
+}
+
+// splitImageTag splits an image into repository and tag, defaulting the tag
+// to latest. Returns false for digest references.
+func splitImageTag(image string) (repo, tag string, ok bool) {
+	if image == "" || strings.Contains(image, "@") {
+		return "", "", false
+	}
+	if idx := strings.LastIndex(image, ":"); idx > strings.LastIndex(image, "/") {
+		return image[:idx], image[idx+1:], true
+	}
+	return image, "latest", true
+}
+
+func exportFileName(obj exportObject) string {
+	return strings.ToLower(obj.kind) + "-" + obj.name + ".yaml"
+}
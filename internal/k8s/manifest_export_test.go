@@ -0,0 +1,168 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func exportTestDeployment() *appsv1.Deployment {
+	replicas := int32(3)
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "shop", Name: "api", UID: "uid-1", ResourceVersion: "42", Generation: 7,
+			CreationTimestamp: metav1.Now(),
+			ManagedFields:     []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+			Annotations: map[string]string{
+				"deployment.kubernetes.io/revision":                "4",
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+			},
+			Labels: map[string]string{"app": "api"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"app": "api"},
+					Annotations: map[string]string{"kubectl.kubernetes.io/restartedAt": "2025-01-01T00:00:00Z"},
+				},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "ghcr.io/acme/api:1.4.2"}}},
+			},
+		},
+		Status: appsv1.DeploymentStatus{ReadyReplicas: 3},
+	}
+	d.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+	return d
+}
+
+func TestToExportObjectStripsServerFields(t *testing.T) {
+	obj, err := toExportObject(exportTestDeployment())
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := &unstructured.Unstructured{Object: obj.object}
+	if obj.kind != "Deployment" || u.GetAPIVersion() != "apps/v1" {
+		t.Errorf("unexpected type: %s %s", u.GetAPIVersion(), obj.kind)
+	}
+	if u.GetUID() != "" || u.GetResourceVersion() != "" || u.GetGeneration() != 0 || len(u.GetManagedFields()) > 0 {
+		t.Errorf("server metadata not stripped: %+v", u.Object["metadata"])
+	}
+	if len(u.GetAnnotations()) != 0 || u.GetLabels()["app"] != "api" || u.GetNamespace() != "shop" {
+		t.Errorf("unexpected metadata: %+v", u.Object["metadata"])
+	}
+	if _, ok := u.Object["status"]; ok {
+		t.Error("status not stripped")
+	}
+	if _, ok, _ := unstructured.NestedFieldNoCopy(u.Object, "spec", "template", "metadata", "annotations"); ok {
+		t.Error("restartedAt annotation not stripped from pod template")
+	}
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "api"},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeNodePort, ClusterIP: "10.0.0.1", ClusterIPs: []string{"10.0.0.1"},
+			Selector: map[string]string{"app": "api"},
+			Ports:    []corev1.ServicePort{{Port: 80, NodePort: 30080}},
+		},
+	}
+	obj, err = toExportObject(svc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := renderYAMLForTest(t, obj)
+	if obj.kind != "Service" || strings.Contains(data, "clusterIP:") || strings.Contains(data, "nodePort") {
+		t.Errorf("service not cleaned:\n%s", data)
+	}
+}
+
+func TestRenderExportFormats(t *testing.T) {
+	build := func() (exportObject, []exportObject) {
+		main, err := toExportObject(exportTestDeployment())
+		if err != nil {
+			t.Fatal(err)
+		}
+		cm, err := toExportObject(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "api-config"}, Data: map[string]string{"k": "v"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return main, []exportObject{cm, main}
+	}
+
+	main, objects := build()
+	files, err := renderExport(ExportFormatYAML, "shop", main, objects)
+	if err != nil || len(files) != 1 || files[0].Path != "api.yaml" {
+		t.Fatalf("unexpected yaml export: %+v, %v", files, err)
+	}
+	if !strings.Contains(files[0].Content, "---\n") || !strings.Contains(files[0].Content, "namespace: shop") {
+		t.Errorf("expected a multi-document bundle with namespaces:\n%s", files[0].Content)
+	}
+
+	main, objects = build()
+	files, err = renderExport(ExportFormatKustomize, "shop", main, objects)
+	if err != nil || len(files) != 3 || files[0].Path != "kustomization.yaml" {
+		t.Fatalf("unexpected kustomize export: %+v, %v", files, err)
+	}
+	if !strings.Contains(files[0].Content, "- configmap-api-config.yaml\n- deployment-api.yaml") {
+		t.Errorf("kustomization should list resources in order:\n%s", files[0].Content)
+	}
+	if strings.Contains(files[2].Content, "namespace:") {
+		t.Errorf("kustomize resources should leave the namespace to the kustomization:\n%s", files[2].Content)
+	}
+
+	main, objects = build()
+	files, err = renderExport(ExportFormatHelm, "shop", main, objects)
+	if err != nil || len(files) != 4 {
+		t.Fatalf("unexpected helm export: %+v, %v", files, err)
+	}
+	if !strings.Contains(files[0].Content, "appVersion: 1.4.2") {
+		t.Errorf("unexpected Chart.yaml:\n%s", files[0].Content)
+	}
+	if !strings.Contains(files[1].Content, "repository: ghcr.io/acme/api") || !strings.Contains(files[1].Content, "replicaCount: 3") {
+		t.Errorf("unexpected values.yaml:\n%s", files[1].Content)
+	}
+	tmpl := files[3].Content
+	if files[3].Path != "templates/deployment-api.yaml" ||
+		!strings.Contains(tmpl, `image: "{{ .Values.image.repository }}:{{ .Values.image.tag }}"`) ||
+		!strings.Contains(tmpl, "replicas: {{ .Values.replicaCount }}") {
+		t.Errorf("unexpected deployment template %s:\n%s", files[3].Path, tmpl)
+	}
+}
+
+func TestPodTemplateReferences(t *testing.T) {
+	spec := &corev1.PodSpec{
+		ImagePullSecrets: []corev1.LocalObjectReference{{Name: "regcred"}},
+		Volumes: []corev1.Volume{
+			{Name: "cfg", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"}}}},
+			{Name: "token", VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+				{ConfigMap: &corev1.ConfigMapProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "kube-root-ca.crt"}}},
+			}}}},
+		},
+		Containers: []corev1.Container{{
+			Name:    "app",
+			EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}}}},
+			Env: []corev1.EnvVar{{Name: "MODE", ValueFrom: &corev1.EnvVarSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "flags"}, Key: "mode"},
+			}}},
+		}},
+	}
+	configMaps, secrets := podTemplateReferences(spec)
+	if strings.Join(configMaps, ",") != "app-config,flags" {
+		t.Errorf("unexpected configmaps: %v", configMaps)
+	}
+	if strings.Join(secrets, ",") != "db,regcred" {
+		t.Errorf("unexpected secrets: %v", secrets)
+	}
+}
+
+func renderYAMLForTest(t *testing.T, obj exportObject) string {
+	t.Helper()
+	files, err := renderExport(ExportFormatYAML, "", obj, []exportObject{obj})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files[0].Content
+}
@@ -3,6 +3,7 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -14,14 +15,16 @@ type PodMetrics struct {
 	Timestamp  string             `json:"timestamp"`
 	Window     string             `json:"window"`
 	Containers []ContainerMetrics `json:"containers"`
+	Source     string             `json:"source,omitempty"` // "kubelet" when derived from the kubelet summary
 }
 
 // NodeMetrics represents metrics for a single node
 type NodeMetrics struct {
-	Metadata  MetricsMeta  `json:"metadata"`
-	Timestamp string       `json:"timestamp"`
-	Window    string       `json:"window"`
+	Metadata  MetricsMeta   `json:"metadata"`
+	Timestamp string        `json:"timestamp"`
+	Window    string        `json:"window"`
 	Usage     ResourceUsage `json:"usage"`
+	Source    string        `json:"source,omitempty"` // "kubelet" when derived from the kubelet summary
 }
 
 // MetricsMeta contains metadata for metrics objects
@@ -56,8 +59,12 @@ var (
 	}
 )
 
-// GetPodMetrics fetches metrics for a specific pod from the metrics.k8s.io API
+// GetPodMetrics fetches metrics for a specific pod from the metrics.k8s.io API,
+// or from its node's kubelet summary when metrics-server isn't installed
 func GetPodMetrics(ctx context.Context, namespace, name string) (*PodMetrics, error) {
+	if useKubeletMetrics() {
+		return getKubeletPodMetrics(ctx, namespace, name)
+	}
 	client := GetDynamicClient()
 	if client == nil {
 		return nil, fmt.Errorf("dynamic client not initialized")
@@ -100,8 +107,17 @@ func GetPodMetrics(ctx context.Context, namespace, name string) (*PodMetrics, er
 	return metrics, nil
 }
 
-// GetNodeMetrics fetches metrics for a specific node from the metrics.k8s.io API
+// GetNodeMetrics fetches metrics for a specific node from the metrics.k8s.io API,
+// or from its kubelet summary when metrics-server isn't installed
 func GetNodeMetrics(ctx context.Context, name string) (*NodeMetrics, error) {
+	if useKubeletMetrics() {
+		summary, err := getKubeletNodeSummary(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		m := kubeletNodeMetrics(name, summary, time.Now().UTC().Format(time.RFC3339))
+		return &m, nil
+	}
 	client := GetDynamicClient()
 	if client == nil {
 		return nil, fmt.Errorf("dynamic client not initialized")
@@ -133,3 +149,70 @@ func GetNodeMetrics(ctx context.Context, name string) (*NodeMetrics, error) {
 
 	return metrics, nil
 }
+
+// ListPodMetrics lists metrics for all pods from the metrics.k8s.io API
+func ListPodMetrics(ctx context.Context) ([]PodMetrics, error) {
+	client := GetDynamicClient()
+	if client == nil {
+		return nil, fmt.Errorf("dynamic client not initialized")
+	}
+
+	result, err := client.Resource(podMetricsGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pod metrics: %w", err)
+	}
+
+	metrics := make([]PodMetrics, 0, len(result.Items))
+	for _, item := range result.Items {
+		m := PodMetrics{Metadata: MetricsMeta{Name: item.GetName(), Namespace: item.GetNamespace()}}
+		m.Timestamp, _ = item.Object["timestamp"].(string)
+		m.Window, _ = item.Object["window"].(string)
+		containers, _ := item.Object["containers"].([]interface{})
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			cm := ContainerMetrics{}
+			cm.Name, _ = container["name"].(string)
+			if usage, ok := container["usage"].(map[string]interface{}); ok {
+				cm.Usage.CPU, _ = usage["cpu"].(string)
+				cm.Usage.Memory, _ = usage["memory"].(string)
+			}
+			m.Containers = append(m.Containers, cm)
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, nil
+}
+
+// ListNodeMetrics lists metrics for all nodes from the metrics.k8s.io API, or
+// from kubelet summaries when metrics-server isn't installed
+func ListNodeMetrics(ctx context.Context) ([]NodeMetrics, error) {
+	if useKubeletMetrics() {
+		nodes, _, err := listKubeletMetrics(ctx)
+		return nodes, err
+	}
+	client := GetDynamicClient()
+	if client == nil {
+		return nil, fmt.Errorf("dynamic client not initialized")
+	}
+
+	result, err := client.Resource(nodeMetricsGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list node metrics: %w", err)
+	}
+
+	metrics := make([]NodeMetrics, 0, len(result.Items))
+	for _, item := range result.Items {
+		m := NodeMetrics{Metadata: MetricsMeta{Name: item.GetName()}}
+		m.Timestamp, _ = item.Object["timestamp"].(string)
+		m.Window, _ = item.Object["window"].(string)
+		if usage, ok := item.Object["usage"].(map[string]interface{}); ok {
+			m.Usage.CPU, _ = usage["cpu"].(string)
+			m.Usage.Memory, _ = usage["memory"].(string)
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, nil
+}
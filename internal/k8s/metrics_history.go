@@ -6,22 +6,51 @@ import (
 	"log"
 	"sync"
 	"time"
-
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
-	// MetricsHistorySize is the number of data points to keep (1 hour at 30s intervals)
+	// MetricsHistorySize is the number of raw data points to keep (1 hour at 30s intervals)
 	MetricsHistorySize = 120
 	// MetricsPollInterval is how often to poll metrics
 	MetricsPollInterval = 30 * time.Second
+	// MetricsRollupInterval is the bucket size raw samples are averaged into.
+	// Rollups also keep the bucket's peak memory (MemoryPeak).
+	MetricsRollupInterval = 5 * time.Minute
+	// MetricsRollupSize is the number of downsampled points to keep (24 hours at 5m intervals)
+	MetricsRollupSize = 288
+	// MetricsHistoryMaxDuration is the longest window the history can be queried for
+	MetricsHistoryMaxDuration = 24 * time.Hour
 )
 
+// ParseMetricsHistoryDuration parses the duration query parameter of the
+// history endpoints. Empty means the last hour. Returns false for anything
+// other than 1h, 6h, or 24h.
+func ParseMetricsHistoryDuration(s string) (time.Duration, bool) {
+	switch s {
+	case "", "1h":
+		return time.Hour, true
+	case "6h":
+		return 6 * time.Hour, true
+	case "24h":
+		return 24 * time.Hour, true
+	}
+	return 0, false
+}
+
 // MetricsDataPoint represents a single metrics sample
 type MetricsDataPoint struct {
 	Timestamp time.Time `json:"timestamp"`
 	CPU       int64     `json:"cpu"`       // CPU in nanocores
 	Memory    int64     `json:"memory"`    // Memory in bytes
+	// MemoryPeak is the highest memory sample in a rollup bucket, which the
+	// average can hide. Zero for raw samples.
+	MemoryPeak int64 `json:"memoryPeak,omitempty"`
+}
+
+// peakMemory returns the highest memory observed for the point: the bucket
+// peak for rollups, the sample itself otherwise
+func (p MetricsDataPoint) peakMemory() int64 {
+	return max(p.Memory, p.MemoryPeak)
 }
 
 // ContainerMetricsHistory holds historical metrics for a container
@@ -53,23 +82,119 @@ type MetricsHistoryStore struct {
 	// Node metrics: key = node name
 	nodeMetrics map[string]*nodeMetricsBuffer
 
+	// Cluster-wide totals, recorded after each node poll
+	clusterMetrics clusterMetricsBuffer
+
+	// Persistence (nil = memory only)
+	db          *metricsSampleDB
+	contextName string
+	lastPrune   time.Time
+
 	// Control
 	stopCh   chan struct{}
 	stopOnce sync.Once
 	wg       sync.WaitGroup
 }
 
-// podMetricsBuffer holds ring buffers for each container in a pod
+// podMetricsBuffer holds a metrics series for each container in a pod
 type podMetricsBuffer struct {
 	namespace  string
 	name       string
-	containers map[string]*ringBuffer // container name -> ring buffer
+	containers map[string]*metricsSeries // container name -> series
 }
 
-// nodeMetricsBuffer holds a ring buffer for a node
+// nodeMetricsBuffer holds a metrics series for a node
 type nodeMetricsBuffer struct {
 	name   string
-	buffer *ringBuffer
+	series *metricsSeries
+}
+
+// metricsSeries keeps the last hour of raw samples plus 24 hours of 5-minute
+// averages. Raw samples are accumulated into the current bucket and rolled up
+// once a sample lands in the next one.
+type metricsSeries struct {
+	raw    *ringBuffer
+	rollup *ringBuffer
+
+	bucket time.Time // Start of the bucket being accumulated
+	sumCPU int64
+	sumMem int64
+	maxMem int64
+	n      int64
+}
+
+func newMetricsSeries() *metricsSeries {
+	return &metricsSeries{
+		raw:    newRingBuffer(MetricsHistorySize),
+		rollup: newRingBuffer(MetricsRollupSize),
+	}
+}
+
+// Add records a raw sample. If it closes the previous bucket, the bucket's
+// average is returned so the caller can persist it.
+func (ms *metricsSeries) Add(point MetricsDataPoint) *MetricsDataPoint {
+	ms.raw.Add(point)
+
+	var rolled *MetricsDataPoint
+	bucket := point.Timestamp.Truncate(MetricsRollupInterval)
+	if ms.n > 0 && !bucket.Equal(ms.bucket) {
+		avg := MetricsDataPoint{
+			Timestamp:  ms.bucket,
+			CPU:        ms.sumCPU / ms.n,
+			Memory:     ms.sumMem / ms.n,
+			MemoryPeak: ms.maxMem,
+		}
+		ms.rollup.Add(avg)
+		rolled = &avg
+		ms.sumCPU, ms.sumMem, ms.maxMem, ms.n = 0, 0, 0, 0
+	}
+	ms.accumulate(bucket, point)
+	return rolled
+}
+
+// restoreRaw re-adds a persisted raw sample. Buckets it closes were already
+// rolled up and persisted before the restart, so they are dropped.
+func (ms *metricsSeries) restoreRaw(point MetricsDataPoint) {
+	ms.raw.Add(point)
+	bucket := point.Timestamp.Truncate(MetricsRollupInterval)
+	if !bucket.Equal(ms.bucket) {
+		ms.sumCPU, ms.sumMem, ms.maxMem, ms.n = 0, 0, 0, 0
+	}
+	ms.accumulate(bucket, point)
+}
+
+func (ms *metricsSeries) accumulate(bucket time.Time, point MetricsDataPoint) {
+	ms.bucket = bucket
+	ms.sumCPU += point.CPU
+	ms.sumMem += point.Memory
+	ms.maxMem = max(ms.maxMem, point.Memory)
+	ms.n++
+}
+
+// Since returns data points at or after cutoff, oldest first. Raw samples are
+// used where available; older parts of the window come from the rollups.
+func (ms *metricsSeries) Since(cutoff time.Time) []MetricsDataPoint {
+	raw := ms.raw.GetAll()
+
+	result := []MetricsDataPoint{}
+	if len(raw) == 0 || raw[0].Timestamp.After(cutoff) {
+		for _, p := range ms.rollup.GetAll() {
+			if p.Timestamp.Before(cutoff) {
+				continue
+			}
+			// Skip buckets that overlap the raw samples
+			if len(raw) > 0 && p.Timestamp.Add(MetricsRollupInterval).After(raw[0].Timestamp) {
+				break
+			}
+			result = append(result, p)
+		}
+	}
+	for _, p := range raw {
+		if !p.Timestamp.Before(cutoff) {
+			result = append(result, p)
+		}
+	}
+	return result
 }
 
 // ringBuffer is a fixed-size circular buffer for metrics
@@ -118,8 +243,37 @@ var (
 	metricsHistoryStore *MetricsHistoryStore
 	metricsHistoryOnce  sync.Once
 	metricsHistoryMu    sync.Mutex
+
+	// The sample database outlives the store so it survives context switches
+	metricsDBPath   string
+	metricsDB       *metricsSampleDB
+	metricsDBFailed bool
 )
 
+// SetMetricsHistoryDBPath persists metrics samples to the SQLite database at
+// path so graphs survive restarts. Must be called before InitMetricsHistory;
+// empty keeps history in memory only.
+func SetMetricsHistoryDBPath(path string) {
+	metricsHistoryMu.Lock()
+	defer metricsHistoryMu.Unlock()
+	metricsDBPath = path
+}
+
+// openMetricsDBLocked opens the sample database on first use. Caller must hold metricsHistoryMu.
+func openMetricsDBLocked() *metricsSampleDB {
+	if metricsDB != nil || metricsDBPath == "" || metricsDBFailed {
+		return metricsDB
+	}
+	db, err := openMetricsSampleDB(metricsDBPath)
+	if err != nil {
+		log.Printf("[metrics] Warning: metrics history won't be persisted: %v", err)
+		metricsDBFailed = true
+		return nil
+	}
+	metricsDB = db
+	return metricsDB
+}
+
 // InitMetricsHistory initializes the metrics history store and starts polling
 func InitMetricsHistory() {
 	metricsHistoryMu.Lock()
@@ -128,6 +282,8 @@ func InitMetricsHistory() {
 		metricsHistoryStore = &MetricsHistoryStore{
 			podMetrics:  make(map[string]*podMetricsBuffer),
 			nodeMetrics: make(map[string]*nodeMetricsBuffer),
+			db:          openMetricsDBLocked(),
+			contextName: GetContextName(),
 			stopCh:      make(chan struct{}),
 		}
 
@@ -169,6 +325,8 @@ func ResetMetricsHistory() {
 func (s *MetricsHistoryStore) pollLoop() {
 	defer s.wg.Done()
 
+	s.restore()
+
 	// Initial poll
 	s.collectMetrics()
 
@@ -192,32 +350,37 @@ func (s *MetricsHistoryStore) collectMetrics() {
 
 	now := time.Now()
 
-	// Collect pod metrics
-	s.collectPodMetrics(ctx, now)
+	// Without metrics-server, usage comes from the kubelet summaries
+	var podMetrics []PodMetrics
+	var nodeMetrics []NodeMetrics
+	if useKubeletMetrics() {
+		nodeMetrics, podMetrics, _ = listKubeletMetrics(ctx)
+	} else {
+		// Metrics server might not be installed, don't spam logs
+		podMetrics, _ = ListPodMetrics(ctx)
+		nodeMetrics, _ = ListNodeMetrics(ctx)
+	}
 
-	// Collect node metrics
-	s.collectNodeMetrics(ctx, now)
-}
+	samples := s.recordPodMetrics(podMetrics, now)
+	samples = append(samples, s.recordNodeMetrics(nodeMetrics, now)...)
 
-func (s *MetricsHistoryStore) collectPodMetrics(ctx context.Context, now time.Time) {
-	client := GetDynamicClient()
-	if client == nil {
-		return
-	}
+	// Aggregate cluster totals from the node samples just taken
+	s.mu.Lock()
+	cluster := s.collectClusterMetrics(now)
+	s.mu.Unlock()
 
-	// List all pod metrics
-	result, err := client.Resource(podMetricsGVR).List(ctx, metav1.ListOptions{})
-	if err != nil {
-		// Metrics server might not be installed, don't spam logs
-		return
-	}
+	s.persist(ctx, samples, cluster, now)
+}
 
+func (s *MetricsHistoryStore) recordPodMetrics(pods []PodMetrics, now time.Time) []metricsSample {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	for _, item := range result.Items {
-		namespace := item.GetNamespace()
-		name := item.GetName()
+	var samples []metricsSample
+
+	for _, pm := range pods {
+		namespace := pm.Metadata.Namespace
+		name := pm.Metadata.Name
 		key := namespace + "/" + name
 
 		// Get or create pod buffer
@@ -226,104 +389,166 @@ func (s *MetricsHistoryStore) collectPodMetrics(ctx context.Context, now time.Ti
 			podBuf = &podMetricsBuffer{
 				namespace:  namespace,
 				name:       name,
-				containers: make(map[string]*ringBuffer),
+				containers: make(map[string]*metricsSeries),
 			}
 			s.podMetrics[key] = podBuf
 		}
 
-		// Extract container metrics
-		containers, ok := item.Object["containers"].([]interface{})
-		if !ok {
-			continue
-		}
-
-		for _, c := range containers {
-			container, ok := c.(map[string]interface{})
-			if !ok {
+		for _, container := range pm.Containers {
+			if container.Name == "" {
 				continue
 			}
 
-			containerName, _ := container["name"].(string)
-			if containerName == "" {
-				continue
-			}
+			cpu := parseCPU(container.Usage.CPU)
+			mem := parseMemory(container.Usage.Memory)
 
-			usage, ok := container["usage"].(map[string]interface{})
-			if !ok {
-				continue
-			}
-
-			cpuStr, _ := usage["cpu"].(string)
-			memStr, _ := usage["memory"].(string)
-
-			cpu := parseCPU(cpuStr)
-			mem := parseMemory(memStr)
-
-			// Get or create container buffer
-			containerBuf, exists := podBuf.containers[containerName]
+			// Get or create container series
+			series, exists := podBuf.containers[container.Name]
 			if !exists {
-				containerBuf = newRingBuffer(MetricsHistorySize)
-				podBuf.containers[containerName] = containerBuf
+				series = newMetricsSeries()
+				podBuf.containers[container.Name] = series
 			}
 
-			containerBuf.Add(MetricsDataPoint{
+			point := MetricsDataPoint{
 				Timestamp: now,
 				CPU:       cpu,
 				Memory:    mem,
-			})
+			}
+			sample := metricsSample{Kind: metricsKindPod, Namespace: namespace, Name: name, Container: container.Name}
+			samples = append(samples, sample.with(point, false))
+			if rolled := series.Add(point); rolled != nil {
+				samples = append(samples, sample.with(*rolled, true))
+			}
 		}
 	}
+	return samples
 }
 
-func (s *MetricsHistoryStore) collectNodeMetrics(ctx context.Context, now time.Time) {
-	client := GetDynamicClient()
-	if client == nil {
-		return
-	}
-
-	// List all node metrics
-	result, err := client.Resource(nodeMetricsGVR).List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return
-	}
-
+func (s *MetricsHistoryStore) recordNodeMetrics(nodes []NodeMetrics, now time.Time) []metricsSample {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	for _, item := range result.Items {
-		name := item.GetName()
+	var samples []metricsSample
+
+	for _, nm := range nodes {
+		name := nm.Metadata.Name
 
 		// Get or create node buffer
 		nodeBuf, exists := s.nodeMetrics[name]
 		if !exists {
 			nodeBuf = &nodeMetricsBuffer{
 				name:   name,
-				buffer: newRingBuffer(MetricsHistorySize),
+				series: newMetricsSeries(),
 			}
 			s.nodeMetrics[name] = nodeBuf
 		}
 
-		usage, ok := item.Object["usage"].(map[string]interface{})
-		if !ok {
-			continue
-		}
-
-		cpuStr, _ := usage["cpu"].(string)
-		memStr, _ := usage["memory"].(string)
+		cpu := parseCPU(nm.Usage.CPU)
+		mem := parseMemory(nm.Usage.Memory)
 
-		cpu := parseCPU(cpuStr)
-		mem := parseMemory(memStr)
-
-		nodeBuf.buffer.Add(MetricsDataPoint{
+		point := MetricsDataPoint{
 			Timestamp: now,
 			CPU:       cpu,
 			Memory:    mem,
-		})
+		}
+		sample := metricsSample{Kind: metricsKindNode, Name: name}
+		samples = append(samples, sample.with(point, false))
+		if rolled := nodeBuf.series.Add(point); rolled != nil {
+			samples = append(samples, sample.with(*rolled, true))
+		}
+	}
+	return samples
+}
+
+// persist writes new samples and the cluster sample, if any, to the database
+// and periodically prunes old ones
+func (s *MetricsHistoryStore) persist(ctx context.Context, samples []metricsSample, cluster *ClusterMetricsDataPoint, now time.Time) {
+	if s.db == nil || len(samples) == 0 {
+		return
+	}
+	if err := s.db.Save(ctx, s.contextName, samples); err != nil {
+		log.Printf("[metrics] Failed to persist metrics samples: %v", err)
+		return
+	}
+	if cluster != nil {
+		if err := s.db.SaveCluster(ctx, s.contextName, *cluster); err != nil {
+			log.Printf("[metrics] Failed to persist cluster metrics sample: %v", err)
+		}
+	}
+	if now.Sub(s.lastPrune) >= MetricsRollupInterval {
+		s.lastPrune = now
+		rawCutoff := now.Add(-MetricsPollInterval * MetricsHistorySize)
+		if err := s.db.Prune(ctx, rawCutoff, now.Add(-MetricsHistoryMaxDuration)); err != nil {
+			log.Printf("[metrics] Failed to prune metrics samples: %v", err)
+		}
+	}
+}
+
+// restore loads samples persisted for the current context before a restart
+func (s *MetricsHistoryStore) restore() {
+	if s.db == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	samples, err := s.db.Load(ctx, s.contextName, now.Add(-MetricsPollInterval*MetricsHistorySize), now.Add(-MetricsHistoryMaxDuration))
+	if err != nil {
+		log.Printf("[metrics] Failed to load persisted metrics history: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sample := range samples {
+		var series *metricsSeries
+		switch sample.Kind {
+		case metricsKindPod:
+			key := sample.Namespace + "/" + sample.Name
+			podBuf, exists := s.podMetrics[key]
+			if !exists {
+				podBuf = &podMetricsBuffer{namespace: sample.Namespace, name: sample.Name, containers: make(map[string]*metricsSeries)}
+				s.podMetrics[key] = podBuf
+			}
+			if series = podBuf.containers[sample.Container]; series == nil {
+				series = newMetricsSeries()
+				podBuf.containers[sample.Container] = series
+			}
+		case metricsKindNode:
+			nodeBuf, exists := s.nodeMetrics[sample.Name]
+			if !exists {
+				nodeBuf = &nodeMetricsBuffer{name: sample.Name, series: newMetricsSeries()}
+				s.nodeMetrics[sample.Name] = nodeBuf
+			}
+			series = nodeBuf.series
+		default:
+			continue
+		}
+
+		if sample.Rollup {
+			series.rollup.Add(sample.Point)
+		} else {
+			series.restoreRaw(sample.Point)
+		}
+	}
+	if len(samples) > 0 {
+		log.Printf("[metrics] Restored %d persisted metrics samples", len(samples))
+	}
+
+	cluster, err := s.db.LoadCluster(ctx, s.contextName, now.Add(-MetricsHistoryMaxDuration))
+	if err != nil {
+		log.Printf("[metrics] Failed to load persisted cluster metrics: %v", err)
+		return
+	}
+	for _, p := range cluster {
+		s.clusterMetrics.Add(p)
 	}
 }
 
-// GetPodMetricsHistory returns historical metrics for a specific pod
-func (s *MetricsHistoryStore) GetPodMetricsHistory(namespace, name string) *PodMetricsHistory {
+// GetPodMetricsHistory returns historical metrics for a specific pod over the
+// given window (at most MetricsHistoryMaxDuration)
+func (s *MetricsHistoryStore) GetPodMetricsHistory(namespace, name string, duration time.Duration) *PodMetricsHistory {
 	if s == nil {
 		return nil
 	}
@@ -343,18 +568,20 @@ func (s *MetricsHistoryStore) GetPodMetricsHistory(namespace, name string) *PodM
 		Containers: make([]ContainerMetricsHistory, 0, len(podBuf.containers)),
 	}
 
-	for containerName, buf := range podBuf.containers {
+	cutoff := time.Now().Add(-duration)
+	for containerName, series := range podBuf.containers {
 		history.Containers = append(history.Containers, ContainerMetricsHistory{
 			Name:       containerName,
-			DataPoints: buf.GetAll(),
+			DataPoints: series.Since(cutoff),
 		})
 	}
 
 	return history
 }
 
-// GetNodeMetricsHistory returns historical metrics for a specific node
-func (s *MetricsHistoryStore) GetNodeMetricsHistory(name string) *NodeMetricsHistory {
+// GetNodeMetricsHistory returns historical metrics for a specific node over
+// the given window (at most MetricsHistoryMaxDuration)
+func (s *MetricsHistoryStore) GetNodeMetricsHistory(name string, duration time.Duration) *NodeMetricsHistory {
 	if s == nil {
 		return nil
 	}
@@ -369,7 +596,7 @@ func (s *MetricsHistoryStore) GetNodeMetricsHistory(name string) *NodeMetricsHis
 
 	return &NodeMetricsHistory{
 		Name:       name,
-		DataPoints: nodeBuf.buffer.GetAll(),
+		DataPoints: nodeBuf.series.Since(time.Now().Add(-duration)),
 	}
 }
 
@@ -0,0 +1,131 @@
+package k8s
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMetricsSeriesDownsampling(t *testing.T) {
+	start := time.Now().Add(-3 * time.Hour).Truncate(MetricsRollupInterval)
+	series := newMetricsSeries()
+
+	var rolled []MetricsDataPoint
+	var last time.Time
+	for i := 0; i < 3*60*2; i++ {
+		last = start.Add(time.Duration(i) * MetricsPollInterval)
+		p := MetricsDataPoint{Timestamp: last, CPU: int64(i % 10), Memory: 100}
+		if r := series.Add(p); r != nil {
+			rolled = append(rolled, *r)
+		}
+	}
+
+	// 3h of samples closes 35 of the 36 five-minute buckets
+	if len(rolled) != 35 {
+		t.Fatalf("expected 35 rollups, got %d", len(rolled))
+	}
+	if !rolled[0].Timestamp.Equal(start) || rolled[0].CPU != 4 || rolled[0].Memory != 100 || rolled[0].MemoryPeak != 100 {
+		t.Errorf("unexpected first rollup: %+v", rolled[0])
+	}
+
+	lastHour := series.Since(last.Add(-MetricsPollInterval * (MetricsHistorySize - 1)))
+	if len(lastHour) != MetricsHistorySize {
+		t.Errorf("expected %d raw points for the last hour, got %d", MetricsHistorySize, len(lastHour))
+	}
+
+	// Older part of the window comes from rollups, without overlapping raw samples
+	sixHours := series.Since(last.Add(-6 * time.Hour))
+	if len(sixHours) != MetricsHistorySize+24 {
+		t.Errorf("expected 24 rollups + %d raw points, got %d", MetricsHistorySize, len(sixHours))
+	}
+	for i := 1; i < len(sixHours); i++ {
+		if !sixHours[i].Timestamp.After(sixHours[i-1].Timestamp) {
+			t.Fatalf("points out of order at %d: %v then %v", i, sixHours[i-1].Timestamp, sixHours[i].Timestamp)
+		}
+	}
+}
+
+func TestParseMetricsHistoryDuration(t *testing.T) {
+	for in, want := range map[string]time.Duration{"": time.Hour, "1h": time.Hour, "6h": 6 * time.Hour, "24h": 24 * time.Hour} {
+		if got, ok := ParseMetricsHistoryDuration(in); !ok || got != want {
+			t.Errorf("ParseMetricsHistoryDuration(%q) = %v, %v", in, got, ok)
+		}
+	}
+	for _, in := range []string{"2h", "48h", "1d"} {
+		if _, ok := ParseMetricsHistoryDuration(in); ok {
+			t.Errorf("expected %q to be rejected", in)
+		}
+	}
+}
+
+func TestMetricsHistoryPersistence(t *testing.T) {
+	db, err := openMetricsSampleDB(filepath.Join(t.TempDir(), "timeline.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.db.Close()
+
+	ctx := context.Background()
+	now := time.Now()
+	newStore := func(contextName string) *MetricsHistoryStore {
+		return &MetricsHistoryStore{
+			podMetrics:  make(map[string]*podMetricsBuffer),
+			nodeMetrics: make(map[string]*nodeMetricsBuffer),
+			db:          db,
+			contextName: contextName,
+		}
+	}
+
+	// Record two hours of node samples and one container sample, then restart
+	store := newStore("prod")
+	series := newMetricsSeries()
+	sample := metricsSample{Kind: metricsKindNode, Name: "node-1"}
+	var samples []metricsSample
+	for ts := now.Add(-2 * time.Hour); !ts.After(now); ts = ts.Add(MetricsPollInterval) {
+		p := MetricsDataPoint{Timestamp: ts, CPU: 1000, Memory: 2000}
+		samples = append(samples, sample.with(p, false))
+		if r := series.Add(p); r != nil {
+			samples = append(samples, sample.with(*r, true))
+		}
+	}
+	pod := metricsSample{Kind: metricsKindPod, Namespace: "app", Name: "web", Container: "nginx"}
+	samples = append(samples, pod.with(MetricsDataPoint{Timestamp: now, CPU: 5, Memory: 6}, false))
+	store.persist(ctx, samples, &ClusterMetricsDataPoint{Timestamp: now, CPUUsage: 1, MemoryCapacity: 8 << 30}, now)
+
+	restored := newStore("prod")
+	restored.restore()
+	node := restored.GetNodeMetricsHistory("node-1", 6*time.Hour)
+	if node == nil || len(node.DataPoints) < MetricsHistorySize {
+		t.Fatalf("expected restored node history, got %+v", node)
+	}
+	if first := node.DataPoints[0]; first.Timestamp.After(now.Add(-time.Hour)) || first.CPU != 1000 {
+		t.Errorf("expected rollups older than an hour to be restored, first point %+v", first)
+	}
+	if cluster := restored.GetClusterMetricsHistory(time.Hour, 60); len(cluster.DataPoints) != 1 || cluster.DataPoints[0].MemoryCapacity != 8<<30 {
+		t.Errorf("expected the cluster sample to be restored, got %+v", cluster.DataPoints)
+	}
+	podHistory := restored.GetPodMetricsHistory("app", "web", time.Hour)
+	if podHistory == nil || len(podHistory.Containers) != 1 || len(podHistory.Containers[0].DataPoints) != 1 {
+		t.Errorf("unexpected restored pod history: %+v", podHistory)
+	}
+
+	// Samples are scoped to the context they were recorded in
+	other := newStore("staging")
+	other.restore()
+	if h := other.GetNodeMetricsHistory("node-1", time.Hour); h != nil {
+		t.Errorf("expected no history for another context, got %+v", h)
+	}
+
+	// Raw samples older than an hour are pruned, rollups are kept
+	loaded, err := db.Load(ctx, "prod", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawCutoff := now.Add(-MetricsPollInterval * MetricsHistorySize).UnixMilli()
+	for _, s := range loaded {
+		if !s.Rollup && s.Point.Timestamp.UnixMilli() < rawCutoff {
+			t.Fatalf("raw sample at %v should have been pruned", s.Point.Timestamp)
+		}
+	}
+}
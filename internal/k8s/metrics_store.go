@@ -0,0 +1,209 @@
+package k8s
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite" // Pure Go SQLite driver
+)
+
+const (
+	metricsKindPod  = "pod"
+	metricsKindNode = "node"
+)
+
+// metricsSample is a persisted data point for a pod container or node
+type metricsSample struct {
+	Kind      string // metricsKindPod or metricsKindNode
+	Namespace string
+	Name      string
+	Container string // Empty for nodes
+	Rollup    bool   // Downsampled average rather than a raw sample
+	Point     MetricsDataPoint
+}
+
+func (m metricsSample) with(point MetricsDataPoint, rollup bool) metricsSample {
+	m.Point = point
+	m.Rollup = rollup
+	return m
+}
+
+// metricsSampleDB persists metrics samples in SQLite, typically in the same
+// database file as the timeline store
+type metricsSampleDB struct {
+	db *sql.DB
+}
+
+func openMetricsSampleDB(dbPath string) (*metricsSampleDB, error) {
+	dir := filepath.Dir(dbPath)
+	if dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+
+	for _, pragma := range []string{"PRAGMA journal_mode=WAL", "PRAGMA busy_timeout=10000"} {
+		if _, err := db.Exec(pragma); err != nil {
+			log.Printf("Warning: failed to set %s: %v", pragma, err)
+		}
+	}
+
+	schema := `
+	CREATE TABLE IF NOT EXISTS metrics_samples (
+		context TEXT NOT NULL,
+		kind TEXT NOT NULL,
+		namespace TEXT NOT NULL DEFAULT '',
+		name TEXT NOT NULL,
+		container TEXT NOT NULL DEFAULT '',
+		rollup INTEGER NOT NULL DEFAULT 0,
+		timestamp INTEGER NOT NULL,
+		cpu INTEGER NOT NULL,
+		memory INTEGER NOT NULL,
+		memory_peak INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX IF NOT EXISTS idx_metrics_samples_ctx_ts ON metrics_samples(context, rollup, timestamp);
+
+	CREATE TABLE IF NOT EXISTS cluster_metrics_samples (
+		context TEXT NOT NULL,
+		timestamp INTEGER NOT NULL,
+		cpu_usage INTEGER NOT NULL,
+		cpu_requests INTEGER NOT NULL,
+		cpu_capacity INTEGER NOT NULL,
+		memory_usage INTEGER NOT NULL,
+		memory_requests INTEGER NOT NULL,
+		memory_capacity INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_cluster_metrics_samples_ctx_ts ON cluster_metrics_samples(context, timestamp);
+	`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	}
+	if err := migrateMetricsSchema(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate schema: %w", err)
+	}
+
+	return &metricsSampleDB{db: db}, nil
+}
+
+// migrateMetricsSchema adds memory_peak to databases created before rollups
+// kept it. Their rollups read back with a zero peak, i.e. just the average.
+func migrateMetricsSchema(db *sql.DB) error {
+	var exists int
+	if err := db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('metrics_samples') WHERE name = 'memory_peak'").Scan(&exists); err != nil {
+		return err
+	}
+	if exists > 0 {
+		return nil
+	}
+	_, err := db.Exec("ALTER TABLE metrics_samples ADD COLUMN memory_peak INTEGER NOT NULL DEFAULT 0")
+	return err
+}
+
+// Save stores samples for a context in a single transaction
+func (m *metricsSampleDB) Save(ctx context.Context, contextName string, samples []metricsSample) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx,
+		`INSERT INTO metrics_samples (context, kind, namespace, name, container, rollup, timestamp, cpu, memory, memory_peak) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, s := range samples {
+		if _, err := stmt.ExecContext(ctx, contextName, s.Kind, s.Namespace, s.Name, s.Container,
+			s.Rollup, s.Point.Timestamp.UnixMilli(), s.Point.CPU, s.Point.Memory, s.Point.MemoryPeak); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Load returns raw samples since rawSince and rollups since rollupSince for a
+// context, oldest first
+func (m *metricsSampleDB) Load(ctx context.Context, contextName string, rawSince, rollupSince time.Time) ([]metricsSample, error) {
+	rows, err := m.db.QueryContext(ctx,
+		`SELECT kind, namespace, name, container, rollup, timestamp, cpu, memory, memory_peak FROM metrics_samples
+		WHERE context = ? AND ((rollup = 0 AND timestamp >= ?) OR (rollup = 1 AND timestamp >= ?))
+		ORDER BY timestamp`,
+		contextName, rawSince.UnixMilli(), rollupSince.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var samples []metricsSample
+	for rows.Next() {
+		var s metricsSample
+		var ts int64
+		if err := rows.Scan(&s.Kind, &s.Namespace, &s.Name, &s.Container, &s.Rollup, &ts, &s.Point.CPU, &s.Point.Memory, &s.Point.MemoryPeak); err != nil {
+			return nil, err
+		}
+		s.Point.Timestamp = time.UnixMilli(ts)
+		samples = append(samples, s)
+	}
+	return samples, rows.Err()
+}
+
+// SaveCluster stores a cluster-wide sample for a context
+func (m *metricsSampleDB) SaveCluster(ctx context.Context, contextName string, p ClusterMetricsDataPoint) error {
+	_, err := m.db.ExecContext(ctx,
+		`INSERT INTO cluster_metrics_samples (context, timestamp, cpu_usage, cpu_requests, cpu_capacity, memory_usage, memory_requests, memory_capacity)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		contextName, p.Timestamp.UnixMilli(), p.CPUUsage, p.CPURequests, p.CPUCapacity, p.MemoryUsage, p.MemoryRequests, p.MemoryCapacity)
+	return err
+}
+
+// LoadCluster returns cluster-wide samples since since for a context, oldest first
+func (m *metricsSampleDB) LoadCluster(ctx context.Context, contextName string, since time.Time) ([]ClusterMetricsDataPoint, error) {
+	rows, err := m.db.QueryContext(ctx,
+		`SELECT timestamp, cpu_usage, cpu_requests, cpu_capacity, memory_usage, memory_requests, memory_capacity
+		FROM cluster_metrics_samples WHERE context = ? AND timestamp >= ? ORDER BY timestamp`,
+		contextName, since.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var points []ClusterMetricsDataPoint
+	for rows.Next() {
+		var p ClusterMetricsDataPoint
+		var ts int64
+		if err := rows.Scan(&ts, &p.CPUUsage, &p.CPURequests, &p.CPUCapacity, &p.MemoryUsage, &p.MemoryRequests, &p.MemoryCapacity); err != nil {
+			return nil, err
+		}
+		p.Timestamp = time.UnixMilli(ts)
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
+
+// Prune deletes raw samples older than rawCutoff, and rollups and cluster
+// samples older than rollupCutoff
+func (m *metricsSampleDB) Prune(ctx context.Context, rawCutoff, rollupCutoff time.Time) error {
+	if _, err := m.db.ExecContext(ctx,
+		`DELETE FROM metrics_samples WHERE (rollup = 0 AND timestamp < ?) OR (rollup = 1 AND timestamp < ?)`,
+		rawCutoff.UnixMilli(), rollupCutoff.UnixMilli()); err != nil {
+		return err
+	}
+	_, err := m.db.ExecContext(ctx, `DELETE FROM cluster_metrics_samples WHERE timestamp < ?`, rollupCutoff.UnixMilli())
+	return err
+}
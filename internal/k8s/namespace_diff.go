@@ -0,0 +1,335 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const namespaceDiffTimeout = 30 * time.Second // Per request to either cluster
+
+// NamespaceResource identifies a resource by kind and name within the compared namespace
+type NamespaceResource struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// ImageDifference is a container running a different image in each context
+type ImageDifference struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Container string `json:"container"`
+	Left      string `json:"left"`
+	Right     string `json:"right"`
+}
+
+// ReplicaDrift is a workload whose desired replica count differs between contexts.
+// Ready counts are included for context only; they don't count as drift.
+type ReplicaDrift struct {
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Left       int32  `json:"left"`
+	Right      int32  `json:"right"`
+	LeftReady  int32  `json:"leftReady"`
+	RightReady int32  `json:"rightReady"`
+}
+
+// NamespaceDiff compares one namespace across two kubeconfig contexts,
+// e.g. prod against its DR cluster
+type NamespaceDiff struct {
+	Namespace        string              `json:"namespace"`
+	LeftContext      string              `json:"leftContext"`
+	RightContext     string              `json:"rightContext"`
+	LeftMissing      bool                `json:"leftMissing,omitempty"`  // Namespace doesn't exist in the left context
+	RightMissing     bool                `json:"rightMissing,omitempty"` // Namespace doesn't exist in the right context
+	OnlyLeft         []NamespaceResource `json:"onlyLeft"`
+	OnlyRight        []NamespaceResource `json:"onlyRight"`
+	ImageDifferences []ImageDifference   `json:"imageDifferences"`
+	ReplicaDrift     []ReplicaDrift      `json:"replicaDrift"`
+	Skipped          []string            `json:"skipped,omitempty"` // Kinds that couldn't be listed, e.g. forbidden Secrets
+	InParity         bool                `json:"inParity"`
+}
+
+// namespaceSnapshot is what a namespace diff needs from one context
+type namespaceSnapshot struct {
+	missing   bool
+	resources map[NamespaceResource]bool
+	workloads map[NamespaceResource]namespaceWorkload
+	skipped   []string
+}
+
+type namespaceWorkload struct {
+	images      map[string]string // Container name -> image
+	replicas    int32
+	ready       int32
+	hasReplicas bool // DaemonSets and CronJobs have no replica count to drift
+}
+
+// CompareNamespaceAcrossContexts lists a namespace in two contexts and reports
+// resources present in only one, image differences and replica drift
+func CompareNamespaceAcrossContexts(ctx context.Context, namespace, leftContext, rightContext string) (*NamespaceDiff, error) {
+	leftClient, err := contextClient(leftContext)
+	if err != nil {
+		return nil, err
+	}
+	rightClient, err := contextClient(rightContext)
+	if err != nil {
+		return nil, err
+	}
+
+	var wg sync.WaitGroup
+	var left, right *namespaceSnapshot
+	var leftErr, rightErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		left, leftErr = snapshotNamespace(ctx, leftClient, namespace)
+	}()
+	go func() {
+		defer wg.Done()
+		right, rightErr = snapshotNamespace(ctx, rightClient, namespace)
+	}()
+	wg.Wait()
+	if leftErr != nil {
+		return nil, fmt.Errorf("context %q: %w", leftContext, leftErr)
+	}
+	if rightErr != nil {
+		return nil, fmt.Errorf("context %q: %w", rightContext, rightErr)
+	}
+
+	diff := diffNamespaceSnapshots(left, right)
+	diff.Namespace = namespace
+	diff.LeftContext = leftContext
+	diff.RightContext = rightContext
+	return diff, nil
+}
+
+// contextClient returns the shared client for the current context, or builds
+// a short-lived one for any other kubeconfig context
+func contextClient(name string) (kubernetes.Interface, error) {
+	if name == GetContextName() {
+		if client := GetClient(); client != nil {
+			return client, nil
+		}
+		return nil, fmt.Errorf("k8s client not initialized")
+	}
+	if demoMode || IsInCluster() {
+		return nil, fmt.Errorf("only the current context is available in this mode")
+	}
+	config, _, err := loadContextConfig(name)
+	if err != nil {
+		return nil, err
+	}
+	config.Timeout = namespaceDiffTimeout
+	config.QPS = 50
+	config.Burst = 100
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create k8s client for context %q: %w", name, err)
+	}
+	return client, nil
+}
+
+// snapshotNamespace lists the kinds that matter for parity. Kinds that can't
+// be listed are recorded as skipped rather than failing the diff; an error is
+// only returned when the cluster can't be reached at all.
+func snapshotNamespace(ctx context.Context, client kubernetes.Interface, namespace string) (*namespaceSnapshot, error) {
+	snap := &namespaceSnapshot{
+		resources: make(map[NamespaceResource]bool),
+		workloads: make(map[NamespaceResource]namespaceWorkload),
+	}
+	if _, err := client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{}); err != nil {
+		switch {
+		case apierrors.IsNotFound(err):
+			snap.missing = true
+			return snap, nil
+		case !apierrors.IsForbidden(err): // Namespace-scoped users may not read namespaces
+			return nil, err
+		}
+	}
+
+	opts := metav1.ListOptions{}
+	add := func(kind, name string) {
+		snap.resources[NamespaceResource{kind, name}] = true
+	}
+	skip := func(kind string, err error) {
+		snap.skipped = append(snap.skipped, fmt.Sprintf("%s: %v", kind, err))
+	}
+	addWorkload := func(kind, name string, spec *corev1.PodSpec, w namespaceWorkload) {
+		w.images = make(map[string]string)
+		for _, c := range spec.InitContainers {
+			w.images[c.Name] = c.Image
+		}
+		for _, c := range spec.Containers {
+			w.images[c.Name] = c.Image
+		}
+		add(kind, name)
+		snap.workloads[NamespaceResource{kind, name}] = w
+	}
+
+	if list, err := client.AppsV1().Deployments(namespace).List(ctx, opts); err != nil {
+		skip("Deployment", err)
+	} else {
+		for _, d := range list.Items {
+			addWorkload("Deployment", d.Name, &d.Spec.Template.Spec, namespaceWorkload{replicas: desiredReplicas(d.Spec.Replicas), ready: d.Status.ReadyReplicas, hasReplicas: true})
+		}
+	}
+	if list, err := client.AppsV1().StatefulSets(namespace).List(ctx, opts); err != nil {
+		skip("StatefulSet", err)
+	} else {
+		for _, s := range list.Items {
+			addWorkload("StatefulSet", s.Name, &s.Spec.Template.Spec, namespaceWorkload{replicas: desiredReplicas(s.Spec.Replicas), ready: s.Status.ReadyReplicas, hasReplicas: true})
+		}
+	}
+	// DaemonSet pod counts follow each cluster's node count, so only images are compared
+	if list, err := client.AppsV1().DaemonSets(namespace).List(ctx, opts); err != nil {
+		skip("DaemonSet", err)
+	} else {
+		for _, ds := range list.Items {
+			addWorkload("DaemonSet", ds.Name, &ds.Spec.Template.Spec, namespaceWorkload{})
+		}
+	}
+	if list, err := client.BatchV1().CronJobs(namespace).List(ctx, opts); err != nil {
+		skip("CronJob", err)
+	} else {
+		for _, cj := range list.Items {
+			addWorkload("CronJob", cj.Name, &cj.Spec.JobTemplate.Spec.Template.Spec, namespaceWorkload{})
+		}
+	}
+	if list, err := client.CoreV1().Services(namespace).List(ctx, opts); err != nil {
+		skip("Service", err)
+	} else {
+		for _, svc := range list.Items {
+			add("Service", svc.Name)
+		}
+	}
+	if list, err := client.NetworkingV1().Ingresses(namespace).List(ctx, opts); err != nil {
+		skip("Ingress", err)
+	} else {
+		for _, ing := range list.Items {
+			add("Ingress", ing.Name)
+		}
+	}
+	if list, err := client.CoreV1().ConfigMaps(namespace).List(ctx, opts); err != nil {
+		skip("ConfigMap", err)
+	} else {
+		for _, cm := range list.Items {
+			if cm.Name == "kube-root-ca.crt" { // Published into every namespace by the control plane
+				continue
+			}
+			add("ConfigMap", cm.Name)
+		}
+	}
+	if list, err := client.CoreV1().Secrets(namespace).List(ctx, opts); err != nil {
+		skip("Secret", err)
+	} else {
+		for _, secret := range list.Items {
+			// Token secrets are per cluster, and Helm release secrets are named by revision
+			if secret.Type == corev1.SecretTypeServiceAccountToken || secret.Type == "helm.sh/release.v1" {
+				continue
+			}
+			add("Secret", secret.Name)
+		}
+	}
+	if list, err := client.CoreV1().PersistentVolumeClaims(namespace).List(ctx, opts); err != nil {
+		skip("PersistentVolumeClaim", err)
+	} else {
+		for _, pvc := range list.Items {
+			add("PersistentVolumeClaim", pvc.Name)
+		}
+	}
+	if list, err := client.CoreV1().ServiceAccounts(namespace).List(ctx, opts); err != nil {
+		skip("ServiceAccount", err)
+	} else {
+		for _, sa := range list.Items {
+			if sa.Name == "default" {
+				continue
+			}
+			add("ServiceAccount", sa.Name)
+		}
+	}
+	return snap, nil
+}
+
+func desiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// diffNamespaceSnapshots compares two snapshots of the same namespace
+func diffNamespaceSnapshots(left, right *namespaceSnapshot) *NamespaceDiff {
+	diff := &NamespaceDiff{
+		LeftMissing:      left.missing,
+		RightMissing:     right.missing,
+		OnlyLeft:         []NamespaceResource{},
+		OnlyRight:        []NamespaceResource{},
+		ImageDifferences: []ImageDifference{},
+		ReplicaDrift:     []ReplicaDrift{},
+	}
+	for _, s := range left.skipped {
+		diff.Skipped = append(diff.Skipped, "left "+s)
+	}
+	for _, s := range right.skipped {
+		diff.Skipped = append(diff.Skipped, "right "+s)
+	}
+
+	for res := range left.resources {
+		if !right.resources[res] {
+			diff.OnlyLeft = append(diff.OnlyLeft, res)
+		}
+	}
+	for res := range right.resources {
+		if !left.resources[res] {
+			diff.OnlyRight = append(diff.OnlyRight, res)
+		}
+	}
+	sortNamespaceResources(diff.OnlyLeft)
+	sortNamespaceResources(diff.OnlyRight)
+
+	keys := slices.Collect(maps.Keys(left.workloads))
+	sortNamespaceResources(keys)
+	for _, key := range keys {
+		lw := left.workloads[key]
+		rw, ok := right.workloads[key]
+		if !ok {
+			continue
+		}
+		for _, container := range slices.Sorted(maps.Keys(lw.images)) {
+			if rImage, ok := rw.images[container]; ok && rImage != lw.images[container] {
+				diff.ImageDifferences = append(diff.ImageDifferences, ImageDifference{
+					Kind: key.Kind, Name: key.Name, Container: container, Left: lw.images[container], Right: rImage,
+				})
+			}
+		}
+		if lw.hasReplicas && rw.hasReplicas && lw.replicas != rw.replicas {
+			diff.ReplicaDrift = append(diff.ReplicaDrift, ReplicaDrift{
+				Kind: key.Kind, Name: key.Name, Left: lw.replicas, Right: rw.replicas, LeftReady: lw.ready, RightReady: rw.ready,
+			})
+		}
+	}
+
+	diff.InParity = !diff.LeftMissing && !diff.RightMissing &&
+		len(diff.OnlyLeft) == 0 && len(diff.OnlyRight) == 0 &&
+		len(diff.ImageDifferences) == 0 && len(diff.ReplicaDrift) == 0
+	return diff
+}
+
+func sortNamespaceResources(resources []NamespaceResource) {
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Kind != resources[j].Kind {
+			return resources[i].Kind < resources[j].Kind
+		}
+		return resources[i].Name < resources[j].Name
+	})
+}
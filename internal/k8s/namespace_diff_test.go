@@ -0,0 +1,65 @@
+package k8s
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNamespaceDiffAcrossContexts(t *testing.T) {
+	ctx := context.Background()
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}}
+	configMap := func(name string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: name}}
+	}
+
+	prodAPI := compareTestDeployment("shop", 3, "api:1.4", "256Mi")
+	drAPI := compareTestDeployment("shop", 1, "api:1.3", "256Mi")
+	prod := fake.NewSimpleClientset(ns, prodAPI, configMap("api-config"), configMap("kube-root-ca.crt"),
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "sh.helm.release.v1.api.v7"}, Type: "helm.sh/release.v1"})
+	dr := fake.NewSimpleClientset(ns, drAPI, configMap("kube-root-ca.crt"),
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "api-canary"}})
+
+	left, err := snapshotNamespace(ctx, prod, "shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	right, err := snapshotNamespace(ctx, dr, "shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff := diffNamespaceSnapshots(left, right)
+
+	if diff.InParity {
+		t.Fatal("expected the namespaces to differ")
+	}
+	if want := []NamespaceResource{{"ConfigMap", "api-config"}}; !reflect.DeepEqual(diff.OnlyLeft, want) {
+		t.Errorf("expected only-left %v, got %v", want, diff.OnlyLeft)
+	}
+	if want := []NamespaceResource{{"Service", "api-canary"}}; !reflect.DeepEqual(diff.OnlyRight, want) {
+		t.Errorf("expected only-right %v, got %v", want, diff.OnlyRight)
+	}
+	if want := []ImageDifference{{Kind: "Deployment", Name: "api", Container: "app", Left: "api:1.4", Right: "api:1.3"}}; !reflect.DeepEqual(diff.ImageDifferences, want) {
+		t.Errorf("expected image differences %v, got %v", want, diff.ImageDifferences)
+	}
+	if len(diff.ReplicaDrift) != 1 || diff.ReplicaDrift[0].Left != 3 || diff.ReplicaDrift[0].Right != 1 {
+		t.Errorf("expected replica drift 3 -> 1, got %v", diff.ReplicaDrift)
+	}
+
+	// A namespace missing from one context is reported rather than an error
+	missing, err := snapshotNamespace(ctx, fake.NewSimpleClientset(), "shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := diffNamespaceSnapshots(left, missing); !diff.RightMissing || diff.InParity || len(diff.OnlyLeft) != 2 {
+		t.Errorf("expected a missing right namespace, got %+v", diff)
+	}
+
+	if same := diffNamespaceSnapshots(left, left); !same.InParity {
+		t.Errorf("expected a namespace to be in parity with itself, got %+v", same)
+	}
+}
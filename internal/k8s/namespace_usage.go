@@ -0,0 +1,185 @@
+package k8s
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// DefaultNamespaceUsageTopK is the number of top consumers listed per namespace by default
+	DefaultNamespaceUsageTopK = 5
+	// MaxNamespaceUsageTopK caps the number of top consumers per namespace
+	MaxNamespaceUsageTopK = 50
+)
+
+// PodUsage is a pod's average usage over the report window alongside its requests
+type PodUsage struct {
+	Name           string `json:"name"`
+	CPUUsage       int64  `json:"cpuUsage"`       // Millicores
+	CPURequests    int64  `json:"cpuRequests"`    // Millicores
+	MemoryUsage    int64  `json:"memoryUsage"`    // Bytes
+	MemoryRequests int64  `json:"memoryRequests"` // Bytes
+}
+
+// NamespaceUsage rolls up usage, requests, and limits for the running pods in a namespace
+type NamespaceUsage struct {
+	Namespace      string     `json:"namespace"`
+	Pods           int        `json:"pods"`
+	CPUUsage       int64      `json:"cpuUsage"`       // Millicores
+	CPURequests    int64      `json:"cpuRequests"`    // Millicores
+	CPULimits      int64      `json:"cpuLimits"`      // Millicores
+	MemoryUsage    int64      `json:"memoryUsage"`    // Bytes
+	MemoryRequests int64      `json:"memoryRequests"` // Bytes
+	MemoryLimits   int64      `json:"memoryLimits"`   // Bytes
+	TopCPU         []PodUsage `json:"topCpu"`
+	TopMemory      []PodUsage `json:"topMemory"`
+}
+
+// NamespaceUsageReport is the per-namespace rollup, sorted by CPU usage descending
+type NamespaceUsageReport struct {
+	Window     string           `json:"window"`
+	HasMetrics bool             `json:"hasMetrics"` // False when no usage has been collected (e.g. no metrics-server)
+	Namespaces []NamespaceUsage `json:"namespaces"`
+}
+
+// GetNamespaceUsage aggregates usage from metrics history and requests/limits
+// from pod specs per namespace. Usage is each pod's average over window.
+func GetNamespaceUsage(namespaces []string, window time.Duration, topK int) *NamespaceUsageReport {
+	report := &NamespaceUsageReport{Window: window.String(), Namespaces: []NamespaceUsage{}}
+	cache := GetResourceCache()
+	if cache == nil {
+		return report
+	}
+	podLister := cache.Pods()
+	if podLister == nil {
+		return report
+	}
+	pods, err := podLister.List(labels.Everything())
+	if err != nil {
+		return report
+	}
+
+	usage := GetMetricsHistory().podUsageSince(time.Now().Add(-window))
+	report.HasMetrics = len(usage) > 0
+	report.Namespaces = aggregateNamespaceUsage(pods, usage, namespaces, topK)
+	return report
+}
+
+// podUsageSince returns each pod's average usage since cutoff, keyed by
+// "namespace/name". CPU is in millicores.
+func (s *MetricsHistoryStore) podUsageSince(cutoff time.Time) map[string]PodUsage {
+	usage := make(map[string]PodUsage)
+	if s == nil {
+		return usage
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for key, podBuf := range s.podMetrics {
+		var u PodUsage
+		sampled := false
+		for _, series := range podBuf.containers {
+			points := series.Since(cutoff)
+			if len(points) == 0 {
+				continue
+			}
+			var cpu, mem int64
+			for _, p := range points {
+				cpu += p.CPU
+				mem += p.Memory
+			}
+			u.CPUUsage += cpu / int64(len(points)) / 1000000 // nanocores to millicores
+			u.MemoryUsage += mem / int64(len(points))
+			sampled = true
+		}
+		if sampled {
+			usage[key] = u
+		}
+	}
+	return usage
+}
+
+// aggregateNamespaceUsage sums pod usage and requests per namespace and picks
+// the topK pods by CPU and memory usage in each
+func aggregateNamespaceUsage(pods []*corev1.Pod, usage map[string]PodUsage, namespaces []string, topK int) []NamespaceUsage {
+	byNamespace := make(map[string]*NamespaceUsage)
+	podsByNamespace := make(map[string][]PodUsage)
+
+	for _, pod := range pods {
+		if len(namespaces) > 0 && !slices.Contains(namespaces, pod.Namespace) {
+			continue
+		}
+		// Completed pods no longer consume or reserve anything
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		ns, ok := byNamespace[pod.Namespace]
+		if !ok {
+			ns = &NamespaceUsage{Namespace: pod.Namespace}
+			byNamespace[pod.Namespace] = ns
+		}
+
+		pu := usage[pod.Namespace+"/"+pod.Name]
+		pu.Name = pod.Name
+		for _, c := range pod.Spec.Containers {
+			if cpu, ok := c.Resources.Requests[corev1.ResourceCPU]; ok {
+				pu.CPURequests += cpu.MilliValue()
+			}
+			if mem, ok := c.Resources.Requests[corev1.ResourceMemory]; ok {
+				pu.MemoryRequests += mem.Value()
+			}
+			if cpu, ok := c.Resources.Limits[corev1.ResourceCPU]; ok {
+				ns.CPULimits += cpu.MilliValue()
+			}
+			if mem, ok := c.Resources.Limits[corev1.ResourceMemory]; ok {
+				ns.MemoryLimits += mem.Value()
+			}
+		}
+
+		ns.Pods++
+		ns.CPUUsage += pu.CPUUsage
+		ns.CPURequests += pu.CPURequests
+		ns.MemoryUsage += pu.MemoryUsage
+		ns.MemoryRequests += pu.MemoryRequests
+		podsByNamespace[pod.Namespace] = append(podsByNamespace[pod.Namespace], pu)
+	}
+
+	result := make([]NamespaceUsage, 0, len(byNamespace))
+	for name, ns := range byNamespace {
+		ns.TopCPU = topPodUsage(podsByNamespace[name], topK, func(p PodUsage) int64 { return p.CPUUsage })
+		ns.TopMemory = topPodUsage(podsByNamespace[name], topK, func(p PodUsage) int64 { return p.MemoryUsage })
+		result = append(result, *ns)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CPUUsage != result[j].CPUUsage {
+			return result[i].CPUUsage > result[j].CPUUsage
+		}
+		return result[i].Namespace < result[j].Namespace
+	})
+	return result
+}
+
+// topPodUsage returns up to k pods with the highest non-zero value
+func topPodUsage(pods []PodUsage, k int, value func(PodUsage) int64) []PodUsage {
+	top := make([]PodUsage, 0, k)
+	for _, p := range pods {
+		if value(p) > 0 {
+			top = append(top, p)
+		}
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if value(top[i]) != value(top[j]) {
+			return value(top[i]) > value(top[j])
+		}
+		return top[i].Name < top[j].Name
+	})
+	if len(top) > k {
+		top = top[:k]
+	}
+	return top
+}
@@ -0,0 +1,82 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAggregateNamespaceUsage(t *testing.T) {
+	pod := func(ns, name, cpuReq, memReq, cpuLim string, phase corev1.PodPhase) *corev1.Pod {
+		res := corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpuReq), corev1.ResourceMemory: resource.MustParse(memReq)},
+		}
+		if cpuLim != "" {
+			res.Limits = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpuLim)}
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Resources: res}}},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+	pods := []*corev1.Pod{
+		pod("shop", "api-1", "250m", "256Mi", "1", corev1.PodRunning),
+		pod("shop", "api-2", "250m", "256Mi", "", corev1.PodRunning),
+		pod("shop", "db-0", "1", "1Gi", "2", corev1.PodRunning),
+		pod("shop", "migrate", "500m", "128Mi", "", corev1.PodSucceeded),
+		pod("ops", "agent", "100m", "64Mi", "", corev1.PodRunning),
+	}
+	usage := map[string]PodUsage{
+		"shop/api-1":   {CPUUsage: 300, MemoryUsage: 200 << 20},
+		"shop/api-2":   {CPUUsage: 100, MemoryUsage: 150 << 20},
+		"shop/db-0":    {CPUUsage: 200, MemoryUsage: 900 << 20},
+		"shop/migrate": {CPUUsage: 999},
+		"ops/agent":    {CPUUsage: 50, MemoryUsage: 32 << 20},
+	}
+
+	got := aggregateNamespaceUsage(pods, usage, nil, 2)
+	if len(got) != 2 || got[0].Namespace != "shop" || got[1].Namespace != "ops" {
+		t.Fatalf("expected shop then ops sorted by CPU usage, got %+v", got)
+	}
+	shop := got[0]
+	if shop.Pods != 3 || shop.CPUUsage != 600 || shop.CPURequests != 1500 || shop.CPULimits != 3000 || shop.MemoryUsage != 1250<<20 {
+		t.Errorf("unexpected shop rollup (completed pod should be skipped): %+v", shop)
+	}
+	if len(shop.TopCPU) != 2 || shop.TopCPU[0].Name != "api-1" || shop.TopCPU[1].Name != "db-0" {
+		t.Errorf("unexpected top CPU consumers: %+v", shop.TopCPU)
+	}
+	if len(shop.TopMemory) != 2 || shop.TopMemory[0].Name != "db-0" || shop.TopMemory[0].MemoryRequests != 1<<30 {
+		t.Errorf("unexpected top memory consumers: %+v", shop.TopMemory)
+	}
+
+	filtered := aggregateNamespaceUsage(pods, usage, []string{"ops"}, 2)
+	if len(filtered) != 1 || filtered[0].Namespace != "ops" || filtered[0].CPURequests != 100 {
+		t.Errorf("expected only ops, got %+v", filtered)
+	}
+}
+
+func TestPodUsageSince(t *testing.T) {
+	now := time.Now()
+	series := newMetricsSeries()
+	for i := 0; i < 4; i++ {
+		series.Add(MetricsDataPoint{Timestamp: now.Add(time.Duration(i-3) * MetricsPollInterval), CPU: int64(i+1) * 100000000, Memory: 1000})
+	}
+	sidecar := newMetricsSeries()
+	sidecar.Add(MetricsDataPoint{Timestamp: now, CPU: 50000000, Memory: 500})
+
+	store := &MetricsHistoryStore{podMetrics: map[string]*podMetricsBuffer{
+		"app/web": {namespace: "app", name: "web", containers: map[string]*metricsSeries{"main": series, "proxy": sidecar}},
+	}}
+	got := store.podUsageSince(now.Add(-time.Hour))["app/web"]
+	// main averages 250m, proxy adds 50m
+	if got.CPUUsage != 300 || got.MemoryUsage != 1500 {
+		t.Errorf("unexpected usage: %+v", got)
+	}
+	if usage := (*MetricsHistoryStore)(nil).podUsageSince(now); len(usage) != 0 {
+		t.Errorf("nil store should report no usage, got %v", usage)
+	}
+}
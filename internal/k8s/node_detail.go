@@ -0,0 +1,278 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// nodePressureConditions are tracked in node history alongside Ready
+var nodePressureConditions = []corev1.NodeConditionType{
+	corev1.NodeMemoryPressure,
+	corev1.NodeDiskPressure,
+	corev1.NodePIDPressure,
+	corev1.NodeNetworkUnavailable,
+}
+
+// NodeConditionHistoryWindow is how far back node condition transitions are read from the timeline
+const NodeConditionHistoryWindow = 24 * time.Hour
+
+// nodeEventLimit caps the per-node event feed
+const nodeEventLimit = 100
+
+// NodeSystemInfo is the kernel, OS and runtime information reported by the kubelet
+type NodeSystemInfo struct {
+	KernelVersion           string `json:"kernelVersion"`
+	OSImage                 string `json:"osImage"`
+	OperatingSystem         string `json:"operatingSystem"`
+	Architecture            string `json:"architecture"`
+	ContainerRuntimeVersion string `json:"containerRuntimeVersion"` // e.g. containerd://1.7.13
+	KubeletVersion          string `json:"kubeletVersion"`
+	KubeProxyVersion        string `json:"kubeProxyVersion,omitempty"`
+}
+
+// NodeResourceReservation compares a resource's capacity with what's allocatable
+// to pods; the difference is held back for system daemons and eviction thresholds
+type NodeResourceReservation struct {
+	Resource        string  `json:"resource"`
+	Capacity        string  `json:"capacity"`
+	Allocatable     string  `json:"allocatable"`
+	Reserved        string  `json:"reserved"`
+	ReservedPercent float64 `json:"reservedPercent"`
+}
+
+// NodeConditionState is a node condition's current status
+type NodeConditionState struct {
+	Type                   string     `json:"type"`
+	Status                 string     `json:"status"`
+	Reason                 string     `json:"reason,omitempty"`
+	Message                string     `json:"message,omitempty"`
+	LastTransitionTime     *time.Time `json:"lastTransitionTime,omitempty"`
+	LastTransitionTimeUnix int64      `json:"lastTransitionTimeUnix,omitempty"`
+	// Transitions recorded in the timeline within the history window
+	Transitions int `json:"transitions"`
+}
+
+// NodeConditionTransition is one recorded change of a node condition
+type NodeConditionTransition struct {
+	Time      time.Time `json:"time"`
+	TimeUnix  int64     `json:"timeUnix"`
+	Condition string    `json:"condition"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+}
+
+// NodeEvent is a K8s event about the node or reported by its kubelet
+type NodeEvent struct {
+	Type          string    `json:"type"`
+	Reason        string    `json:"reason"`
+	Message       string    `json:"message"`
+	Count         int32     `json:"count"`
+	Timestamp     time.Time `json:"timestamp"`
+	TimestampUnix int64     `json:"timestampUnix"`
+	// Object the event is about when it isn't the node itself (e.g. a pod the kubelet evicted)
+	Object string `json:"object,omitempty"`
+}
+
+// NodeDetail is the kernel/OS level view of a node: system info, resource
+// reservations, condition history and its event feed
+type NodeDetail struct {
+	Name             string                    `json:"name"`
+	SystemInfo       NodeSystemInfo            `json:"systemInfo"`
+	Reservations     []NodeResourceReservation `json:"reservations"`
+	Conditions       []NodeConditionState      `json:"conditions"`
+	ConditionHistory []NodeConditionTransition `json:"conditionHistory"` // Newest first
+	HistorySince     time.Time                 `json:"historySince"`
+	HistorySinceUnix int64                     `json:"historySinceUnix"`
+	Events           []NodeEvent               `json:"events"` // Newest first
+}
+
+// GetNodeDetail returns the kernel/OS level detail for a node, with condition
+// transitions from the timeline and events from the K8s events cache
+func GetNodeDetail(ctx context.Context, name string) (*NodeDetail, error) {
+	cache := GetResourceCache()
+	if cache == nil || cache.Nodes() == nil {
+		return nil, fmt.Errorf("resource cache not initialized")
+	}
+	node, err := cache.Nodes().Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("node not found: %s", name)
+	}
+
+	var events []*corev1.Event
+	if eventLister := cache.Events(); eventLister != nil {
+		events, _ = eventLister.List(labels.Everything())
+	}
+
+	since := time.Now().Add(-NodeConditionHistoryWindow)
+	var history []timeline.TimelineEvent
+	if store := timeline.GetStore(); store != nil {
+		history, err = store.Query(ctx, timeline.QueryOptions{
+			Kinds: []string{"Node"},
+			Name:  name,
+			Since: since,
+			Limit: 1000,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query node history: %w", err)
+		}
+	}
+
+	return buildNodeDetail(node, events, history, since), nil
+}
+
+// buildNodeDetail assembles a node's detail from the node, the cluster's K8s
+// events and Node timeline events
+func buildNodeDetail(node *corev1.Node, events []*corev1.Event, history []timeline.TimelineEvent, since time.Time) *NodeDetail {
+	info := node.Status.NodeInfo
+	detail := &NodeDetail{
+		Name: node.Name,
+		SystemInfo: NodeSystemInfo{
+			KernelVersion:           info.KernelVersion,
+			OSImage:                 info.OSImage,
+			OperatingSystem:         info.OperatingSystem,
+			Architecture:            info.Architecture,
+			ContainerRuntimeVersion: info.ContainerRuntimeVersion,
+			KubeletVersion:          info.KubeletVersion,
+			KubeProxyVersion:        info.KubeProxyVersion,
+		},
+		Reservations:     nodeReservations(node),
+		Conditions:       []NodeConditionState{},
+		ConditionHistory: nodeConditionHistory(node.Name, history),
+		HistorySince:     since,
+		HistorySinceUnix: since.Unix(),
+		Events:           nodeEvents(node.Name, events),
+	}
+
+	transitions := make(map[string]int)
+	for _, t := range detail.ConditionHistory {
+		transitions[t.Condition]++
+	}
+	for _, cond := range node.Status.Conditions {
+		state := NodeConditionState{
+			Type:        string(cond.Type),
+			Status:      string(cond.Status),
+			Reason:      cond.Reason,
+			Message:     cond.Message,
+			Transitions: transitions[string(cond.Type)],
+		}
+		if !cond.LastTransitionTime.IsZero() {
+			t := cond.LastTransitionTime.Time
+			state.LastTransitionTime = &t
+			state.LastTransitionTimeUnix = t.Unix()
+		}
+		detail.Conditions = append(detail.Conditions, state)
+	}
+	return detail
+}
+
+// InZone renders the detail's times in loc
+func (d *NodeDetail) InZone(loc *time.Location) {
+	d.HistorySince = d.HistorySince.In(loc)
+	for i := range d.Conditions {
+		if t := d.Conditions[i].LastTransitionTime; t != nil {
+			inZone := t.In(loc)
+			d.Conditions[i].LastTransitionTime = &inZone
+		}
+	}
+	for i := range d.ConditionHistory {
+		d.ConditionHistory[i].Time = d.ConditionHistory[i].Time.In(loc)
+	}
+	for i := range d.Events {
+		d.Events[i].Timestamp = d.Events[i].Timestamp.In(loc)
+	}
+}
+
+// nodeReservations compares capacity with allocatable for the resources the
+// kubelet reserves (cpu, memory, ephemeral-storage, pods)
+func nodeReservations(node *corev1.Node) []NodeResourceReservation {
+	reservations := []NodeResourceReservation{}
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage, corev1.ResourcePods} {
+		capacity, ok := node.Status.Capacity[name]
+		if !ok {
+			continue
+		}
+		allocatable, ok := node.Status.Allocatable[name]
+		if !ok {
+			allocatable = capacity
+		}
+		reserved := capacity.DeepCopy()
+		reserved.Sub(allocatable)
+
+		r := NodeResourceReservation{
+			Resource:    string(name),
+			Capacity:    capacity.String(),
+			Allocatable: allocatable.String(),
+			Reserved:    reserved.String(),
+		}
+		if capacity.MilliValue() > 0 {
+			r.ReservedPercent = float64(reserved.MilliValue()) / float64(capacity.MilliValue()) * 100
+		}
+		reservations = append(reservations, r)
+	}
+	return reservations
+}
+
+// nodeConditionHistory extracts condition transitions from a node's update diffs
+func nodeConditionHistory(name string, history []timeline.TimelineEvent) []NodeConditionTransition {
+	transitions := []NodeConditionTransition{}
+	for _, e := range history {
+		if e.Kind != "Node" || e.Name != name || e.Diff == nil {
+			continue
+		}
+		for _, field := range e.Diff.Fields {
+			condition, ok := strings.CutPrefix(field.Path, "status.conditions[")
+			if !ok {
+				continue
+			}
+			transitions = append(transitions, NodeConditionTransition{
+				Time:      e.Timestamp,
+				TimeUnix:  e.Timestamp.Unix(),
+				Condition: strings.TrimSuffix(condition, "]"),
+				From:      fmt.Sprint(field.OldValue),
+				To:        fmt.Sprint(field.NewValue),
+			})
+		}
+	}
+	sort.SliceStable(transitions, func(i, j int) bool { return transitions[i].Time.After(transitions[j].Time) })
+	return transitions
+}
+
+// nodeEvents returns events about the node itself or reported by its kubelet,
+// newest first
+func nodeEvents(name string, events []*corev1.Event) []NodeEvent {
+	feed := []NodeEvent{}
+	for _, e := range events {
+		isNode := e.InvolvedObject.Kind == "Node" && e.InvolvedObject.Name == name
+		fromKubelet := e.Source.Host == name || e.ReportingInstance == name
+		if !isNode && !fromKubelet {
+			continue
+		}
+		ne := NodeEvent{
+			Type:      e.Type,
+			Reason:    e.Reason,
+			Message:   e.Message,
+			Count:     e.Count,
+			Timestamp: eventTime(e),
+		}
+		ne.TimestampUnix = ne.Timestamp.Unix()
+		if !isNode {
+			ne.Object = e.InvolvedObject.Kind + "/" + e.InvolvedObject.Name
+			if e.InvolvedObject.Namespace != "" {
+				ne.Object = e.InvolvedObject.Kind + "/" + e.InvolvedObject.Namespace + "/" + e.InvolvedObject.Name
+			}
+		}
+		feed = append(feed, ne)
+	}
+	sort.SliceStable(feed, func(i, j int) bool { return feed[i].Timestamp.After(feed[j].Timestamp) })
+	if len(feed) > nodeEventLimit {
+		feed = feed[:nodeEventLimit]
+	}
+	return feed
+}
@@ -0,0 +1,125 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDiffNodeTracksPressureConditions(t *testing.T) {
+	oldNode := &corev1.Node{Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+		{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+		{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+	}}}
+	newNode := oldNode.DeepCopy()
+	newNode.Status.Conditions[1].Status = corev1.ConditionTrue
+
+	diff := ComputeDiff("Node", oldNode, newNode)
+	if diff == nil || len(diff.Fields) != 1 || diff.Fields[0].Path != "status.conditions[MemoryPressure]" {
+		t.Fatalf("expected a MemoryPressure condition change, got %+v", diff)
+	}
+}
+
+func TestBuildNodeDetail(t *testing.T) {
+	now := time.Now()
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Status: corev1.NodeStatus{
+			NodeInfo: corev1.NodeSystemInfo{KernelVersion: "6.1.0", ContainerRuntimeVersion: "containerd://1.7.13"},
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("4"),
+				corev1.ResourceMemory: resource.MustParse("16Gi"),
+			},
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("3900m"),
+				corev1.ResourceMemory: resource.MustParse("12Gi"),
+			},
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+				{Type: corev1.NodePIDPressure, Status: corev1.ConditionFalse},
+			},
+		},
+	}
+
+	flap := func(ago time.Duration, name, condition, from, to string) timeline.TimelineEvent {
+		return timeline.TimelineEvent{
+			Kind: "Node", Name: name, Timestamp: now.Add(-ago),
+			Diff: &timeline.DiffInfo{Fields: []timeline.FieldChange{
+				{Path: "spec.unschedulable", OldValue: false, NewValue: true},
+				{Path: "status.conditions[" + condition + "]", OldValue: from, NewValue: to},
+			}},
+		}
+	}
+	history := []timeline.TimelineEvent{
+		flap(3*time.Hour, "node-a", "MemoryPressure", "False", "True"),
+		flap(2*time.Hour, "node-a", "MemoryPressure", "True", "False"),
+		flap(time.Hour, "node-b", "MemoryPressure", "False", "True"),
+	}
+
+	events := []*corev1.Event{
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Node", Name: "node-a"},
+			Reason:         "NodeHasSufficientMemory",
+			LastTimestamp:  metav1.NewTime(now.Add(-2 * time.Hour)),
+		},
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "shop", Name: "cache"},
+			Source:         corev1.EventSource{Component: "kubelet", Host: "node-a"},
+			Reason:         "Evicted",
+			LastTimestamp:  metav1.NewTime(now.Add(-time.Hour)),
+		},
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Node", Name: "node-b"},
+			Reason:         "NodeNotReady",
+			LastTimestamp:  metav1.NewTime(now),
+		},
+	}
+
+	detail := buildNodeDetail(node, events, history, now.Add(-NodeConditionHistoryWindow))
+
+	if detail.SystemInfo.KernelVersion != "6.1.0" || detail.SystemInfo.ContainerRuntimeVersion != "containerd://1.7.13" {
+		t.Errorf("unexpected system info %+v", detail.SystemInfo)
+	}
+	if len(detail.Reservations) != 2 {
+		t.Fatalf("expected cpu and memory reservations, got %+v", detail.Reservations)
+	}
+	if cpu := detail.Reservations[0]; cpu.Reserved != "100m" || cpu.ReservedPercent != 2.5 {
+		t.Errorf("expected 100m (2.5%%) cpu reserved, got %+v", cpu)
+	}
+	if mem := detail.Reservations[1]; mem.Reserved != "4Gi" || mem.ReservedPercent != 25 {
+		t.Errorf("expected 4Gi (25%%) memory reserved, got %+v", mem)
+	}
+
+	if len(detail.ConditionHistory) != 2 || detail.ConditionHistory[0].To != "False" {
+		t.Fatalf("expected node-a's two MemoryPressure transitions newest first, got %+v", detail.ConditionHistory)
+	}
+	if c := detail.Conditions[0]; c.Type != "MemoryPressure" || c.Transitions != 2 {
+		t.Errorf("expected MemoryPressure to have flapped twice, got %+v", c)
+	}
+	if c := detail.Conditions[1]; c.Transitions != 0 {
+		t.Errorf("expected no PIDPressure transitions, got %+v", c)
+	}
+
+	if len(detail.Events) != 2 {
+		t.Fatalf("expected 2 events for node-a, got %+v", detail.Events)
+	}
+	if e := detail.Events[0]; e.Reason != "Evicted" || e.Object != "Pod/shop/cache" {
+		t.Errorf("expected the kubelet eviction first, got %+v", e)
+	}
+	if e := detail.Events[1]; e.Object != "" {
+		t.Errorf("expected the node's own event without an object, got %+v", e)
+	}
+
+	berlin := time.FixedZone("CET", 3600)
+	detail.InZone(berlin)
+	if e := detail.Events[0]; e.Timestamp.Location() != berlin || e.TimestampUnix != now.Add(-time.Hour).Unix() {
+		t.Errorf("expected the event in the requested zone with an epoch twin, got %+v", e)
+	}
+	if h := detail.ConditionHistory[0]; h.Time.Location() != berlin || h.TimeUnix != now.Add(-2*time.Hour).Unix() {
+		t.Errorf("expected the transition in the requested zone with an epoch twin, got %+v", h)
+	}
+}
@@ -0,0 +1,356 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// maxOwnerChainDepth guards against ownership cycles and runaway chains
+const maxOwnerChainDepth = 10
+
+// How an owner link was established
+const (
+	OwnerViaReference = "ownerReference" // metadata.ownerReferences
+	OwnerViaHelm      = "helm"           // meta.helm.sh/release-* annotations
+	OwnerViaArgoCD    = "argocd"         // argocd.argoproj.io/tracking-id or instance label
+	OwnerViaFlux      = "flux"           // kustomize/helm.toolkit.fluxcd.io labels
+)
+
+// OwnerLink is one resource in an ownership chain
+type OwnerLink struct {
+	Kind      string `json:"kind"`
+	Group     string `json:"group,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	UID       string `json:"uid,omitempty"`
+	Via       string `json:"via,omitempty"`     // How this link owns the previous one (empty for the resource itself)
+	Missing   bool   `json:"missing,omitempty"` // Referenced but not found in the cache; the chain stops here
+}
+
+// OwnerChain is a resource followed by its owners, outermost last
+// (e.g. Pod → ReplicaSet → Deployment → Helm release → Argo CD Application)
+type OwnerChain struct {
+	Chain []OwnerLink `json:"chain"`
+}
+
+// ownerLookup fetches an object by kind, API group, namespace and name
+type ownerLookup func(kind, group, namespace, name string) (metav1.Object, error)
+
+// GetOwnerChain resolves the full ownership chain of a resource from the typed
+// and dynamic caches, following controller owner references first and then
+// Helm, Argo CD and Flux management metadata.
+func (c *ResourceCache) GetOwnerChain(ctx context.Context, kind, group, namespace, name string) (*OwnerChain, error) {
+	if c == nil {
+		return nil, fmt.Errorf("resource cache not initialized")
+	}
+	lookup := func(kind, group, namespace, name string) (metav1.Object, error) {
+		return c.getObject(ctx, kind, group, namespace, name)
+	}
+	obj, err := lookup(kind, group, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	// Report the resource by its Kind rather than the plural or lowercase name
+	// it may have been requested by
+	if res, ok := GetResourceDiscovery().GetResource(kind); ok && (group == "" || group == res.Group) {
+		kind, group = res.Kind, res.Group
+	}
+	return &OwnerChain{Chain: resolveOwnerChain(kind, group, obj, lookup)}, nil
+}
+
+// resolveOwnerChain walks from obj up through its owners
+func resolveOwnerChain(kind, group string, obj metav1.Object, lookup ownerLookup) []OwnerLink {
+	chain := []OwnerLink{{
+		Kind:      kind,
+		Group:     group,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		UID:       string(obj.GetUID()),
+	}}
+	seen := map[string]bool{ownerLinkKey(chain[0]): true}
+
+	for len(chain) <= maxOwnerChainDepth {
+		next, ok := nextOwner(obj)
+		if !ok || seen[ownerLinkKey(next)] {
+			break
+		}
+		seen[ownerLinkKey(next)] = true
+
+		owner, err := lookup(next.Kind, next.Group, next.Namespace, next.Name)
+		if err != nil || owner == nil {
+			next.Missing = true
+			chain = append(chain, next)
+			break
+		}
+		if next.UID == "" {
+			next.UID = string(owner.GetUID())
+		}
+		chain = append(chain, next)
+		obj = owner
+	}
+	return chain
+}
+
+// nextOwner returns the owner of obj: its controller owner reference (or first
+// reference), or failing that the Helm release, Argo CD Application, or Flux
+// object that manages it
+func nextOwner(obj metav1.Object) (OwnerLink, bool) {
+	if owner, ok := referenceOwner(obj); ok {
+		return owner, true
+	}
+	return managerLink(obj)
+}
+
+// referenceOwner returns obj's controller ownerReference (or its first one)
+func referenceOwner(obj metav1.Object) (OwnerLink, bool) {
+	if refs := obj.GetOwnerReferences(); len(refs) > 0 {
+		ref := refs[0]
+		for _, r := range refs {
+			if r.Controller != nil && *r.Controller {
+				ref = r
+				break
+			}
+		}
+		gv, _ := schema.ParseGroupVersion(ref.APIVersion)
+		return OwnerLink{
+			Kind:      ref.Kind,
+			Group:     gv.Group,
+			Namespace: obj.GetNamespace(), // Owners are always in the same namespace (or cluster-scoped)
+			Name:      ref.Name,
+			UID:       string(ref.UID),
+			Via:       OwnerViaReference,
+		}, true
+	}
+	return OwnerLink{}, false
+}
+
+// managerLink returns the Helm release, Argo CD Application, or Flux object
+// that manages obj, from its labels and annotations
+func managerLink(obj metav1.Object) (OwnerLink, bool) {
+	labels := obj.GetLabels()
+	annotations := obj.GetAnnotations()
+
+	// Flux labels resources it applies with the owning Kustomization or HelmRelease
+	if name := labels["kustomize.toolkit.fluxcd.io/name"]; name != "" {
+		return OwnerLink{
+			Kind:      "Kustomization",
+			Group:     "kustomize.toolkit.fluxcd.io",
+			Namespace: labels["kustomize.toolkit.fluxcd.io/namespace"],
+			Name:      name,
+			Via:       OwnerViaFlux,
+		}, true
+	}
+	if name := labels["helm.toolkit.fluxcd.io/name"]; name != "" {
+		return OwnerLink{
+			Kind:      "HelmRelease",
+			Group:     "helm.toolkit.fluxcd.io",
+			Namespace: labels["helm.toolkit.fluxcd.io/namespace"],
+			Name:      name,
+			Via:       OwnerViaFlux,
+		}, true
+	}
+
+	// Argo CD tracking id: <app>:<group>/<kind>:<namespace>/<name>, where <app>
+	// may be prefixed with the Application's namespace as <namespace>_<app>
+	if id := annotations["argocd.argoproj.io/tracking-id"]; id != "" {
+		app, _, _ := strings.Cut(id, ":")
+		return argoApplicationLink(app), true
+	}
+	if app := labels["argocd.argoproj.io/instance"]; app != "" {
+		return argoApplicationLink(app), true
+	}
+
+	// Helm records the release on every object it creates
+	if release := annotations["meta.helm.sh/release-name"]; release != "" {
+		ns := annotations["meta.helm.sh/release-namespace"]
+		if ns == "" {
+			ns = obj.GetNamespace()
+		}
+		return OwnerLink{Kind: "HelmRelease", Group: "helm.sh", Namespace: ns, Name: release, Via: OwnerViaHelm}, true
+	}
+
+	return OwnerLink{}, false
+}
+
+func argoApplicationLink(app string) OwnerLink {
+	link := OwnerLink{Kind: "Application", Group: "argoproj.io", Name: app, Via: OwnerViaArgoCD}
+	if ns, name, ok := strings.Cut(app, "_"); ok {
+		link.Namespace, link.Name = ns, name
+	}
+	return link
+}
+
+func ownerLinkKey(l OwnerLink) string {
+	return l.Group + "/" + l.Kind + "/" + l.Namespace + "/" + l.Name
+}
+
+// getObject returns an object from the typed cache for built-in kinds, or
+// from the dynamic cache for everything else. Helm releases are resolved from
+// their release metadata, since they only exist as storage Secrets.
+func (c *ResourceCache) getObject(ctx context.Context, kind, group, namespace, name string) (metav1.Object, error) {
+	if group == "helm.sh" && kind == "HelmRelease" {
+		return helmReleaseObject(namespace, name), nil
+	}
+	if obj, ok, err := c.getTypedObject(kind, group, namespace, name); ok {
+		return obj, err
+	}
+	u, err := c.GetDynamicWithGroup(ctx, kind, namespace, name, group)
+	if err != nil && namespace == "" {
+		// Owners known only by name (e.g. Argo CD Applications tracked without
+		// their namespace): search every namespace
+		if items, listErr := c.ListDynamicWithGroup(ctx, kind, "", group); listErr == nil {
+			for _, item := range items {
+				if item.GetName() == name {
+					return item, nil
+				}
+			}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// getCachedObject returns an object from the typed cache or an already-watched
+// dynamic resource, without starting informers or calling the API server
+func (c *ResourceCache) getCachedObject(kind, group, namespace, name string) (metav1.Object, bool) {
+	if obj, ok, err := c.getTypedObject(kind, group, namespace, name); ok {
+		return obj, err == nil
+	}
+	gvr, ok := GetResourceDiscovery().GetGVRWithGroup(kind, group)
+	if !ok {
+		return nil, false
+	}
+	u, ok := GetDynamicResourceCache().GetCached(gvr, namespace, name)
+	if !ok {
+		return nil, false
+	}
+	return u, true
+}
+
+// involvedObjectOwner returns the owner of a K8s Event's involved object, for
+// any kind in the typed cache or a watched dynamic resource (e.g. a Pod's
+// ReplicaSet, or a CR owned by another CR). Only ownerReferences count:
+// Helm/Flux/Argo CD managers may live in another namespace, which OwnerInfo
+// can't express.
+func (c *ResourceCache) involvedObjectOwner(event *corev1.Event) *timeline.OwnerInfo {
+	if c == nil {
+		return nil
+	}
+	ref := event.InvolvedObject
+	gv, _ := schema.ParseGroupVersion(ref.APIVersion)
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = event.Namespace
+	}
+	obj, ok := c.getCachedObject(ref.Kind, gv.Group, namespace, ref.Name)
+	if !ok {
+		// Cluster-scoped objects (e.g. Nodes) are reported with the event's namespace
+		if obj, ok = c.getCachedObject(ref.Kind, gv.Group, "", ref.Name); !ok {
+			return nil
+		}
+	}
+	owner, ok := referenceOwner(obj)
+	if !ok {
+		return nil
+	}
+	return &timeline.OwnerInfo{Kind: owner.Kind, Name: owner.Name}
+}
+
+// helmReleaseObject stands in for a Helm release, which only exists as
+// storage Secrets; it has no owners, so the chain ends at the release
+func helmReleaseObject(namespace, name string) metav1.Object {
+	return &metav1.ObjectMeta{Namespace: namespace, Name: name}
+}
+
+// inGroup reports whether a requested API group matches a typed kind's group.
+// An empty group means the caller didn't specify one.
+func inGroup(group, want string) bool {
+	return group == "" || group == want
+}
+
+// getTypedObject looks up built-in kinds in the typed cache. ok is false when
+// the kind isn't a typed kind (or its informer is disabled by RBAC), so the
+// caller should fall back to the dynamic cache.
+func (c *ResourceCache) getTypedObject(kind, group, namespace, name string) (obj metav1.Object, ok bool, err error) {
+	switch strings.ToLower(kind) {
+	case "pod", "pods":
+		if l := c.Pods(); l != nil && group == "" {
+			return typedObject(l.Pods(namespace).Get(name))
+		}
+	case "replicaset", "replicasets":
+		if l := c.ReplicaSets(); l != nil && inGroup(group, "apps") {
+			return typedObject(l.ReplicaSets(namespace).Get(name))
+		}
+	case "deployment", "deployments":
+		if l := c.Deployments(); l != nil && inGroup(group, "apps") {
+			return typedObject(l.Deployments(namespace).Get(name))
+		}
+	case "statefulset", "statefulsets":
+		if l := c.StatefulSets(); l != nil && inGroup(group, "apps") {
+			return typedObject(l.StatefulSets(namespace).Get(name))
+		}
+	case "daemonset", "daemonsets":
+		if l := c.DaemonSets(); l != nil && inGroup(group, "apps") {
+			return typedObject(l.DaemonSets(namespace).Get(name))
+		}
+	case "job", "jobs":
+		if l := c.Jobs(); l != nil && inGroup(group, "batch") {
+			return typedObject(l.Jobs(namespace).Get(name))
+		}
+	case "cronjob", "cronjobs":
+		if l := c.CronJobs(); l != nil && inGroup(group, "batch") {
+			return typedObject(l.CronJobs(namespace).Get(name))
+		}
+	case "service", "services":
+		if l := c.Services(); l != nil && group == "" {
+			return typedObject(l.Services(namespace).Get(name))
+		}
+	case "configmap", "configmaps":
+		if l := c.ConfigMaps(); l != nil && group == "" {
+			return typedObject(l.ConfigMaps(namespace).Get(name))
+		}
+	case "secret", "secrets":
+		if l := c.Secrets(); l != nil && group == "" {
+			return typedObject(l.Secrets(namespace).Get(name))
+		}
+	case "persistentvolumeclaim", "persistentvolumeclaims":
+		if l := c.PersistentVolumeClaims(); l != nil && group == "" {
+			return typedObject(l.PersistentVolumeClaims(namespace).Get(name))
+		}
+	case "ingress", "ingresses":
+		if l := c.Ingresses(); l != nil && inGroup(group, "networking.k8s.io") {
+			return typedObject(l.Ingresses(namespace).Get(name))
+		}
+	case "horizontalpodautoscaler", "horizontalpodautoscalers":
+		if l := c.HorizontalPodAutoscalers(); l != nil && inGroup(group, "autoscaling") {
+			return typedObject(l.HorizontalPodAutoscalers(namespace).Get(name))
+		}
+	case "node", "nodes":
+		if l := c.Nodes(); l != nil && group == "" {
+			return typedObject(l.Get(name))
+		}
+	case "namespace", "namespaces":
+		if l := c.Namespaces(); l != nil && group == "" {
+			return typedObject(l.Get(name))
+		}
+	}
+	return nil, false, nil
+}
+
+// typedObject adapts a typed lister result, avoiding a non-nil interface
+// wrapping a nil pointer on error
+func typedObject[T metav1.Object](obj T, err error) (metav1.Object, bool, error) {
+	if err != nil {
+		return nil, true, err
+	}
+	return obj, true, nil
+}
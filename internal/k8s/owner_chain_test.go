@@ -0,0 +1,165 @@
+package k8s
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func chainTestObject(namespace, name string, owner *metav1.OwnerReference, annotations, labels map[string]string) *metav1.ObjectMeta {
+	meta := &metav1.ObjectMeta{Namespace: namespace, Name: name, Annotations: annotations, Labels: labels}
+	if owner != nil {
+		meta.OwnerReferences = []metav1.OwnerReference{*owner}
+	}
+	return meta
+}
+
+func controllerRef(apiVersion, kind, name string) *metav1.OwnerReference {
+	controller := true
+	return &metav1.OwnerReference{APIVersion: apiVersion, Kind: kind, Name: name, Controller: &controller}
+}
+
+// mapLookup serves objects keyed by group/kind/namespace/name
+func mapLookup(objects map[string]metav1.Object) ownerLookup {
+	return func(kind, group, namespace, name string) (metav1.Object, error) {
+		if obj, ok := objects[group+"/"+kind+"/"+namespace+"/"+name]; ok {
+			return obj, nil
+		}
+		return nil, fmt.Errorf("resource not found: %s/%s", namespace, name)
+	}
+}
+
+func chainKinds(chain []OwnerLink) []string {
+	kinds := make([]string, len(chain))
+	for i, link := range chain {
+		kinds[i] = link.Kind
+	}
+	return kinds
+}
+
+func TestResolveOwnerChainHelm(t *testing.T) {
+	helm := map[string]string{"meta.helm.sh/release-name": "shop", "meta.helm.sh/release-namespace": "apps"}
+	objects := map[string]metav1.Object{
+		"apps/ReplicaSet/apps/api-7d9f": chainTestObject("apps", "api-7d9f", controllerRef("apps/v1", "Deployment", "api"), nil, nil),
+		"apps/Deployment/apps/api":      chainTestObject("apps", "api", nil, helm, nil),
+		"helm.sh/HelmRelease/apps/shop": helmReleaseObject("apps", "shop"),
+	}
+	pod := chainTestObject("apps", "api-7d9f-x2k", controllerRef("apps/v1", "ReplicaSet", "api-7d9f"), nil, nil)
+
+	chain := resolveOwnerChain("Pod", "", pod, mapLookup(objects))
+	want := []string{"Pod", "ReplicaSet", "Deployment", "HelmRelease"}
+	if got := chainKinds(chain); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected chain %v, got %v", want, got)
+	}
+	if last := chain[3]; last.Via != OwnerViaHelm || last.Name != "shop" || last.Missing {
+		t.Errorf("expected resolved helm release shop, got %+v", last)
+	}
+	if chain[1].Group != "apps" || chain[1].Via != OwnerViaReference {
+		t.Errorf("expected ReplicaSet linked by owner reference in group apps, got %+v", chain[1])
+	}
+}
+
+func TestResolveOwnerChainArgoCD(t *testing.T) {
+	app := &unstructured.Unstructured{}
+	app.SetNamespace("argocd")
+	app.SetName("shop")
+	objects := map[string]metav1.Object{
+		"argoproj.io/Application/argocd/shop": app,
+	}
+
+	tracked := chainTestObject("apps", "api", nil, map[string]string{
+		"argocd.argoproj.io/tracking-id": "argocd_shop:apps/Deployment:apps/api",
+	}, nil)
+	chain := resolveOwnerChain("Deployment", "apps", tracked, mapLookup(objects))
+	if len(chain) != 2 || chain[1].Kind != "Application" || chain[1].Namespace != "argocd" || chain[1].Missing {
+		t.Fatalf("expected Deployment → argocd/shop Application, got %+v", chain)
+	}
+
+	// Legacy instance label carries no namespace; the lookup can't find it here
+	labeled := chainTestObject("apps", "api", nil, nil, map[string]string{"argocd.argoproj.io/instance": "shop"})
+	chain = resolveOwnerChain("Deployment", "apps", labeled, mapLookup(objects))
+	if len(chain) != 2 || chain[1].Name != "shop" || !chain[1].Missing {
+		t.Fatalf("expected a missing shop Application link, got %+v", chain)
+	}
+}
+
+func TestResolveOwnerChainFluxAndCycles(t *testing.T) {
+	flux := map[string]string{"kustomize.toolkit.fluxcd.io/name": "infra", "kustomize.toolkit.fluxcd.io/namespace": "flux-system"}
+	objects := map[string]metav1.Object{
+		"kustomize.toolkit.fluxcd.io/Kustomization/flux-system/infra": chainTestObject("flux-system", "infra", nil, nil, nil),
+	}
+	widget := chainTestObject("apps", "w", nil, nil, flux)
+	chain := resolveOwnerChain("Widget", "example.com", widget, mapLookup(objects))
+	if len(chain) != 2 || chain[1].Kind != "Kustomization" || chain[1].Via != OwnerViaFlux {
+		t.Fatalf("expected Widget → Kustomization, got %+v", chain)
+	}
+
+	// Objects that own each other must not loop forever
+	a := chainTestObject("apps", "a", controllerRef("example.com/v1", "Widget", "b"), nil, nil)
+	b := chainTestObject("apps", "b", controllerRef("example.com/v1", "Widget", "a"), nil, nil)
+	objects = map[string]metav1.Object{
+		"example.com/Widget/apps/a": a,
+		"example.com/Widget/apps/b": b,
+	}
+	chain = resolveOwnerChain("Widget", "example.com", a, mapLookup(objects))
+	if len(chain) != 2 {
+		t.Fatalf("expected the cycle to stop after a → b, got %+v", chain)
+	}
+}
+
+func TestDynamicGetCachedOnlyServesWatchedResources(t *testing.T) {
+	kustomizations := schema.GroupVersionResource{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"}
+	child := &unstructured.Unstructured{}
+	child.SetAPIVersion("kustomize.toolkit.fluxcd.io/v1")
+	child.SetKind("Kustomization")
+	child.SetNamespace("flux-system")
+	child.SetName("apps")
+	child.SetLabels(map[string]string{"kustomize.toolkit.fluxcd.io/name": "infra", "kustomize.toolkit.fluxcd.io/namespace": "flux-system"})
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{kustomizations: "KustomizationList"}, child)
+
+	prevClient := dynamicClient
+	dynamicClient = client
+	d := &DynamicResourceCache{
+		client:        client,
+		informers:     make(map[schema.GroupVersionResource]cache.SharedIndexInformer),
+		informerStops: make(map[schema.GroupVersionResource]chan struct{}),
+		syncComplete:  make(map[schema.GroupVersionResource]bool),
+		fallbacks:     make(map[schema.GroupVersionResource]*fallbackList),
+		stopCh:        make(chan struct{}),
+		discoveryDone: make(chan struct{}),
+	}
+	t.Cleanup(func() {
+		d.Stop()
+		dynamicClient = prevClient
+	})
+
+	if _, ok := d.GetCached(kustomizations, "flux-system", "apps"); ok {
+		t.Fatalf("expected no object before the resource is watched")
+	}
+	if d.GetInformerCount() != 0 {
+		t.Fatalf("GetCached must not start an informer")
+	}
+
+	if err := d.startWatching(kustomizations); err != nil {
+		t.Fatalf("startWatching: %v", err)
+	}
+	if !d.WaitForSync(kustomizations, 5*time.Second) {
+		t.Fatalf("informer did not sync")
+	}
+	obj, ok := d.GetCached(kustomizations, "flux-system", "apps")
+	if !ok {
+		t.Fatalf("expected the watched Kustomization to be served from the informer")
+	}
+	// Event owners only follow ownerReferences, not Flux's manager labels
+	if owner, ok := referenceOwner(obj); ok {
+		t.Errorf("expected no reference owner for a label-managed Kustomization, got %+v", owner)
+	}
+}
@@ -0,0 +1,184 @@
+package k8s
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// PlacementCell is the pods one namespace has on a node, or in a zone
+type PlacementCell struct {
+	Node           string `json:"node,omitempty"`
+	Zone           string `json:"zone,omitempty"`
+	Namespace      string `json:"namespace"`
+	Pods           int    `json:"pods"`
+	CPURequests    int64  `json:"cpuRequests"`    // millicores
+	MemoryRequests int64  `json:"memoryRequests"` // bytes
+}
+
+// PlacementTotals is the pods placed on a node or zone against its allocatable resources
+type PlacementTotals struct {
+	Name              string `json:"name"` // Node or zone name ("" for nodes without a zone label)
+	Zone              string `json:"zone,omitempty"`
+	Nodes             int    `json:"nodes,omitempty"` // Zones only
+	Pods              int    `json:"pods"`
+	CPURequests       int64  `json:"cpuRequests"`
+	MemoryRequests    int64  `json:"memoryRequests"`
+	CPUAllocatable    int64  `json:"cpuAllocatable"`
+	MemoryAllocatable int64  `json:"memoryAllocatable"`
+}
+
+// PodPlacement is pod counts and requests per node×namespace and zone×namespace,
+// the data behind a placement heatmap
+type PodPlacement struct {
+	Namespaces  []string          `json:"namespaces"`
+	Nodes       []PlacementTotals `json:"nodes"`
+	Zones       []PlacementTotals `json:"zones"`
+	NodeCells   []PlacementCell   `json:"nodeCells"` // Non-empty cells only
+	ZoneCells   []PlacementCell   `json:"zoneCells"`
+	Unscheduled int               `json:"unscheduled"` // Pods not yet bound to a node
+}
+
+// GetPodPlacement aggregates running pods by node, zone and namespace
+func GetPodPlacement(namespaces []string) (*PodPlacement, error) {
+	cache := GetResourceCache()
+	if cache == nil || cache.Nodes() == nil || cache.Pods() == nil {
+		return nil, fmt.Errorf("resource cache not initialized")
+	}
+	nodes, err := cache.Nodes().List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	pods, err := cache.Pods().List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	return buildPodPlacement(nodes, pods, namespaces), nil
+}
+
+// buildPodPlacement sums pod requests into node and zone cells per namespace
+func buildPodPlacement(nodes []*corev1.Node, pods []*corev1.Pod, namespaces []string) *PodPlacement {
+	placement := &PodPlacement{
+		Namespaces: []string{},
+		Nodes:      []PlacementTotals{},
+		Zones:      []PlacementTotals{},
+		NodeCells:  []PlacementCell{},
+		ZoneCells:  []PlacementCell{},
+	}
+
+	nodeTotals := make(map[string]*PlacementTotals, len(nodes))
+	zoneTotals := make(map[string]*PlacementTotals)
+	for _, node := range nodes {
+		zone := firstLabel(node.Labels, zoneLabels)
+		t := &PlacementTotals{
+			Name:              node.Name,
+			Zone:              zone,
+			CPUAllocatable:    node.Status.Allocatable.Cpu().MilliValue(),
+			MemoryAllocatable: node.Status.Allocatable.Memory().Value(),
+		}
+		nodeTotals[node.Name] = t
+
+		z, ok := zoneTotals[zone]
+		if !ok {
+			z = &PlacementTotals{Name: zone}
+			zoneTotals[zone] = z
+		}
+		z.Nodes++
+		z.CPUAllocatable += t.CPUAllocatable
+		z.MemoryAllocatable += t.MemoryAllocatable
+	}
+
+	type cellKey struct{ location, namespace string }
+	nodeCells := make(map[cellKey]*PlacementCell)
+	zoneCells := make(map[cellKey]*PlacementCell)
+	seenNamespaces := make(map[string]bool)
+	add := func(cells map[cellKey]*PlacementCell, key cellKey, newCell func() *PlacementCell, cpu, mem int64) {
+		c, ok := cells[key]
+		if !ok {
+			c = newCell()
+			cells[key] = c
+		}
+		c.Pods++
+		c.CPURequests += cpu
+		c.MemoryRequests += mem
+	}
+
+	for _, pod := range pods {
+		if len(namespaces) > 0 && !slices.Contains(namespaces, pod.Namespace) {
+			continue
+		}
+		// Completed pods no longer reserve anything
+		if podTerminated(pod) {
+			continue
+		}
+		node := nodeTotals[pod.Spec.NodeName]
+		if node == nil {
+			placement.Unscheduled++
+			continue
+		}
+		seenNamespaces[pod.Namespace] = true
+
+		var cpu, mem int64
+		for _, c := range pod.Spec.Containers {
+			if q, ok := c.Resources.Requests[corev1.ResourceCPU]; ok {
+				cpu += q.MilliValue()
+			}
+			if q, ok := c.Resources.Requests[corev1.ResourceMemory]; ok {
+				mem += q.Value()
+			}
+		}
+
+		node.Pods++
+		node.CPURequests += cpu
+		node.MemoryRequests += mem
+		zone := zoneTotals[node.Zone]
+		zone.Pods++
+		zone.CPURequests += cpu
+		zone.MemoryRequests += mem
+
+		add(nodeCells, cellKey{node.Name, pod.Namespace}, func() *PlacementCell {
+			return &PlacementCell{Node: node.Name, Namespace: pod.Namespace}
+		}, cpu, mem)
+		add(zoneCells, cellKey{node.Zone, pod.Namespace}, func() *PlacementCell {
+			return &PlacementCell{Zone: node.Zone, Namespace: pod.Namespace}
+		}, cpu, mem)
+	}
+
+	for ns := range seenNamespaces {
+		placement.Namespaces = append(placement.Namespaces, ns)
+	}
+	sort.Strings(placement.Namespaces)
+	for _, t := range nodeTotals {
+		placement.Nodes = append(placement.Nodes, *t)
+	}
+	sort.Slice(placement.Nodes, func(i, j int) bool { return placement.Nodes[i].Name < placement.Nodes[j].Name })
+	for _, t := range zoneTotals {
+		placement.Zones = append(placement.Zones, *t)
+	}
+	sort.Slice(placement.Zones, func(i, j int) bool { return placement.Zones[i].Name < placement.Zones[j].Name })
+
+	for _, c := range nodeCells {
+		placement.NodeCells = append(placement.NodeCells, *c)
+	}
+	sort.Slice(placement.NodeCells, func(i, j int) bool {
+		a, b := placement.NodeCells[i], placement.NodeCells[j]
+		if a.Node != b.Node {
+			return a.Node < b.Node
+		}
+		return a.Namespace < b.Namespace
+	})
+	for _, c := range zoneCells {
+		placement.ZoneCells = append(placement.ZoneCells, *c)
+	}
+	sort.Slice(placement.ZoneCells, func(i, j int) bool {
+		a, b := placement.ZoneCells[i], placement.ZoneCells[j]
+		if a.Zone != b.Zone {
+			return a.Zone < b.Zone
+		}
+		return a.Namespace < b.Namespace
+	})
+	return placement
+}
@@ -0,0 +1,78 @@
+package k8s
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func placementTestNode(name, zone string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"topology.kubernetes.io/zone": zone}},
+		Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("2"),
+			corev1.ResourceMemory: resource.MustParse("4Gi"),
+		}},
+	}
+}
+
+func placementTestPod(namespace, name, node, cpu string) *corev1.Pod {
+	pod := maintenanceTestPod(namespace, name, node, "", "", nil)
+	pod.Spec.Containers = []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+		corev1.ResourceCPU: resource.MustParse(cpu),
+	}}}}
+	return pod
+}
+
+func TestBuildPodPlacement(t *testing.T) {
+	nodes := []*corev1.Node{
+		placementTestNode("node-a", "us-east-1a"),
+		placementTestNode("node-b", "us-east-1a"),
+		placementTestNode("node-c", "us-east-1b"),
+	}
+	done := placementTestPod("shop", "job", "node-c", "1")
+	done.Status.Phase = corev1.PodSucceeded
+	pods := []*corev1.Pod{
+		placementTestPod("shop", "web-1", "node-a", "500m"),
+		placementTestPod("shop", "web-2", "node-a", "500m"),
+		placementTestPod("shop", "web-3", "node-b", "250m"),
+		placementTestPod("infra", "agent", "node-c", "100m"),
+		placementTestPod("shop", "pending", "", "1"),
+		done,
+	}
+
+	p := buildPodPlacement(nodes, pods, nil)
+
+	if len(p.Namespaces) != 2 || p.Namespaces[0] != "infra" || p.Unscheduled != 1 {
+		t.Fatalf("expected namespaces [infra shop] and 1 unscheduled pod, got %v / %d", p.Namespaces, p.Unscheduled)
+	}
+	if len(p.NodeCells) != 3 {
+		t.Fatalf("expected 3 non-empty node cells, got %+v", p.NodeCells)
+	}
+	if c := p.NodeCells[0]; c.Node != "node-a" || c.Namespace != "shop" || c.Pods != 2 || c.CPURequests != 1000 {
+		t.Errorf("expected 2 shop pods requesting 1000m on node-a, got %+v", c)
+	}
+	if n := p.Nodes[2]; n.Name != "node-c" || n.Pods != 1 || n.CPUAllocatable != 2000 {
+		t.Errorf("expected node-c with only the running agent, got %+v", n)
+	}
+
+	if len(p.Zones) != 2 {
+		t.Fatalf("expected 2 zones, got %+v", p.Zones)
+	}
+	if z := p.Zones[0]; z.Name != "us-east-1a" || z.Nodes != 2 || z.Pods != 3 || z.CPURequests != 1250 || z.CPUAllocatable != 4000 {
+		t.Errorf("unexpected us-east-1a totals %+v", z)
+	}
+	// All shop pods in one zone: the skew the heatmap should expose
+	for _, c := range p.ZoneCells {
+		if c.Namespace == "shop" && c.Zone != "us-east-1a" {
+			t.Errorf("expected shop pods only in us-east-1a, got %+v", c)
+		}
+	}
+
+	filtered := buildPodPlacement(nodes, pods, []string{"infra"})
+	if len(filtered.NodeCells) != 1 || filtered.Unscheduled != 0 || len(filtered.Nodes) != 3 {
+		t.Errorf("expected only the infra cell with every node kept, got %+v", filtered)
+	}
+}
@@ -0,0 +1,249 @@
+package k8s
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Placement violation types
+const (
+	PlacementAntiAffinity   = "AntiAffinity"
+	PlacementTopologySpread = "TopologySpread"
+	PlacementSingleNode     = "SingleNode" // Every replica on one node
+	PlacementSingleZone     = "SingleZone" // Every replica in one zone of a multi-zone cluster
+)
+
+// Placement violation severities
+const (
+	PlacementViolated = "violation" // A required rule (requiredDuringScheduling, DoNotSchedule) doesn't hold
+	PlacementRisk     = "risk"      // A preferred rule doesn't hold, or replicas share a failure domain
+)
+
+// PlacementViolation is a workload whose running pods break their declared
+// anti-affinity or topology spread, or are co-located in one failure domain
+type PlacementViolation struct {
+	Kind        string `json:"kind"`
+	Namespace   string `json:"namespace"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Severity    string `json:"severity"`
+	TopologyKey string `json:"topologyKey,omitempty"`
+	Message     string `json:"message"`
+}
+
+// GetPlacementViolations checks running pods against their anti-affinity and
+// topologySpreadConstraints and flags replicas sharing a node or zone
+func GetPlacementViolations(namespaces []string) ([]PlacementViolation, error) {
+	cache := GetResourceCache()
+	if cache == nil || cache.Nodes() == nil || cache.Pods() == nil {
+		return nil, fmt.Errorf("resource cache not initialized")
+	}
+	nodes, err := cache.Nodes().List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	pods, err := cache.Pods().List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	return findPlacementViolations(nodes, pods, namespaces), nil
+}
+
+// findPlacementViolations evaluates each replicated workload's placement rules
+// against where its pods actually run. Rules are read from the first replica,
+// since a workload's pods share one template.
+func findPlacementViolations(nodes []*corev1.Node, pods []*corev1.Pod, namespaces []string) []PlacementViolation {
+	nodeLabels := make(map[string]map[string]string, len(nodes))
+	zones := make(map[string]bool)
+	for _, node := range nodes {
+		nodeLabels[node.Name] = node.Labels
+		if zone := firstLabel(node.Labels, zoneLabels); zone != "" {
+			zones[zone] = true
+		}
+	}
+
+	var scheduled []*corev1.Pod
+	workloads := make(map[workloadKey][]*corev1.Pod)
+	var order []workloadKey
+	for _, pod := range pods {
+		if podTerminated(pod) || nodeLabels[pod.Spec.NodeName] == nil {
+			continue
+		}
+		scheduled = append(scheduled, pod)
+		if isDaemonSetPod(pod) || (len(namespaces) > 0 && !slices.Contains(namespaces, pod.Namespace)) {
+			continue
+		}
+		key := podWorkload(pod)
+		if key.Kind == "Pod" || key.Kind == "Job" {
+			continue
+		}
+		if _, ok := workloads[key]; !ok {
+			order = append(order, key)
+		}
+		workloads[key] = append(workloads[key], pod)
+	}
+
+	violations := []PlacementViolation{}
+	for _, key := range order {
+		replicas := workloads[key]
+		if len(replicas) < 2 {
+			continue
+		}
+		report := func(typ, severity, topologyKey, message string) {
+			violations = append(violations, PlacementViolation{
+				Kind: key.Kind, Namespace: key.Namespace, Name: key.Name,
+				Type: typ, Severity: severity, TopologyKey: topologyKey, Message: message,
+			})
+		}
+		spec := replicas[0].Spec
+
+		explicit := len(violations)
+		if anti := podAntiAffinity(spec); anti != nil {
+			for _, term := range anti.RequiredDuringSchedulingIgnoredDuringExecution {
+				if msg, ok := antiAffinityConflict(term, replicas, scheduled, nodeLabels); ok {
+					report(PlacementAntiAffinity, PlacementViolated, term.TopologyKey, msg)
+				}
+			}
+			for _, weighted := range anti.PreferredDuringSchedulingIgnoredDuringExecution {
+				if msg, ok := antiAffinityConflict(weighted.PodAffinityTerm, replicas, scheduled, nodeLabels); ok {
+					report(PlacementAntiAffinity, PlacementRisk, weighted.PodAffinityTerm.TopologyKey, msg)
+				}
+			}
+		}
+		for _, c := range spec.TopologySpreadConstraints {
+			if msg, ok := spreadSkewExceeded(c, spec.NodeSelector, key.Namespace, scheduled, nodeLabels); ok {
+				severity := PlacementViolated
+				if c.WhenUnsatisfiable == corev1.ScheduleAnyway {
+					severity = PlacementRisk
+				}
+				report(PlacementTopologySpread, severity, c.TopologyKey, msg)
+			}
+		}
+		if len(violations) > explicit {
+			continue
+		}
+
+		// No declared rule broken, but one node or zone failure takes out every replica
+		replicaNodes := make(map[string]bool)
+		replicaZones := make(map[string]bool)
+		for _, pod := range replicas {
+			replicaNodes[pod.Spec.NodeName] = true
+			replicaZones[firstLabel(nodeLabels[pod.Spec.NodeName], zoneLabels)] = true
+		}
+		switch {
+		case len(replicaNodes) == 1 && len(nodes) > 1:
+			report(PlacementSingleNode, PlacementRisk, "kubernetes.io/hostname",
+				fmt.Sprintf("All %d replicas run on node %s", len(replicas), replicas[0].Spec.NodeName))
+		case len(replicaZones) == 1 && len(zones) > 1 && !replicaZones[""]:
+			report(PlacementSingleZone, PlacementRisk, zoneLabels[0],
+				fmt.Sprintf("All %d replicas run in zone %s", len(replicas), firstLabel(nodeLabels[replicas[0].Spec.NodeName], zoneLabels)))
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		a, b := violations[i], violations[j]
+		if a.Severity != b.Severity {
+			return a.Severity == PlacementViolated
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return violations
+}
+
+func podAntiAffinity(spec corev1.PodSpec) *corev1.PodAntiAffinity {
+	if spec.Affinity == nil {
+		return nil
+	}
+	return spec.Affinity.PodAntiAffinity
+}
+
+// antiAffinityConflict reports the first replica that shares a topology domain
+// with another pod matched by the anti-affinity term
+func antiAffinityConflict(term corev1.PodAffinityTerm, replicas, scheduled []*corev1.Pod, nodeLabels map[string]map[string]string) (string, bool) {
+	selector, err := metav1.LabelSelectorAsSelector(term.LabelSelector)
+	if err != nil || selector.Empty() || term.TopologyKey == "" {
+		return "", false
+	}
+	termNamespaces := term.Namespaces
+	if len(termNamespaces) == 0 {
+		termNamespaces = []string{replicas[0].Namespace}
+	}
+
+	for _, pod := range replicas {
+		domain, ok := nodeLabels[pod.Spec.NodeName][term.TopologyKey]
+		if !ok {
+			continue
+		}
+		for _, other := range scheduled {
+			if other == pod {
+				continue
+			}
+			if !slices.Contains(termNamespaces, other.Namespace) || !selector.Matches(labels.Set(other.Labels)) {
+				continue
+			}
+			if nodeLabels[other.Spec.NodeName][term.TopologyKey] == domain {
+				return fmt.Sprintf("Pods %s and %s/%s share %s=%s", pod.Name, other.Namespace, other.Name, term.TopologyKey, domain), true
+			}
+		}
+	}
+	return "", false
+}
+
+// spreadSkewExceeded reports when matching pods are spread across the
+// constraint's domains more unevenly than maxSkew allows. Like the scheduler,
+// only nodes matching the pod's nodeSelector contribute domains.
+func spreadSkewExceeded(c corev1.TopologySpreadConstraint, nodeSelector map[string]string, namespace string, scheduled []*corev1.Pod, nodeLabels map[string]map[string]string) (string, bool) {
+	selector, err := metav1.LabelSelectorAsSelector(c.LabelSelector)
+	if err != nil || selector.Empty() {
+		return "", false
+	}
+
+	// Every domain with a node counts, even with no matching pods
+	counts := make(map[string]int)
+	eligible := labels.SelectorFromSet(nodeSelector)
+	for _, l := range nodeLabels {
+		if !eligible.Matches(labels.Set(l)) {
+			continue
+		}
+		if domain, ok := l[c.TopologyKey]; ok {
+			counts[domain] += 0
+		}
+	}
+	if len(counts) < 2 {
+		return "", false
+	}
+	for _, pod := range scheduled {
+		if pod.Namespace != namespace || !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		if domain, ok := nodeLabels[pod.Spec.NodeName][c.TopologyKey]; ok {
+			if _, eligibleDomain := counts[domain]; eligibleDomain {
+				counts[domain]++
+			}
+		}
+	}
+
+	minDomain, maxDomain := "", ""
+	for domain, n := range counts {
+		if minDomain == "" || n < counts[minDomain] || (n == counts[minDomain] && domain < minDomain) {
+			minDomain = domain
+		}
+		if maxDomain == "" || n > counts[maxDomain] || (n == counts[maxDomain] && domain < maxDomain) {
+			maxDomain = domain
+		}
+	}
+	skew := counts[maxDomain] - counts[minDomain]
+	if skew <= int(c.MaxSkew) {
+		return "", false
+	}
+	return fmt.Sprintf("Skew %d exceeds maxSkew %d on %s (%s has %d pods, %s has %d)",
+		skew, c.MaxSkew, c.TopologyKey, maxDomain, counts[maxDomain], minDomain, counts[minDomain]), true
+}
@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func violationTestNode(name, zone string) *corev1.Node {
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{
+		"kubernetes.io/hostname":      name,
+		"topology.kubernetes.io/zone": zone,
+	}}}
+}
+
+func violationTestPods(workload string, nodes ...string) []*corev1.Pod {
+	labels := map[string]string{"app": workload}
+	pods := make([]*corev1.Pod, len(nodes))
+	for i, node := range nodes {
+		pods[i] = maintenanceTestPod("shop", workload+"-"+string(rune('a'+i)), node, "StatefulSet", workload, labels)
+	}
+	return pods
+}
+
+func TestFindPlacementViolations(t *testing.T) {
+	nodes := []*corev1.Node{
+		violationTestNode("node-a", "zone-1"),
+		violationTestNode("node-b", "zone-1"),
+		violationTestNode("node-c", "zone-2"),
+	}
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}}
+
+	// api: required hostname anti-affinity, but two replicas landed on node-a
+	api := violationTestPods("api", "node-a", "node-a", "node-c")
+	for _, pod := range api {
+		pod.Spec.Affinity = &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
+				{LabelSelector: selector, TopologyKey: "kubernetes.io/hostname"},
+			},
+		}}
+	}
+
+	// web: zone spread with maxSkew 1, but 3 pods in zone-1 and none in zone-2
+	web := violationTestPods("web", "node-a", "node-b", "node-b")
+	for _, pod := range web {
+		pod.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{
+			MaxSkew:           1,
+			TopologyKey:       "topology.kubernetes.io/zone",
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		}}
+	}
+
+	// cache: no rules, both replicas on one node; worker: no rules, both in zone-1
+	cache := violationTestPods("cache", "node-c", "node-c")
+	worker := violationTestPods("worker", "node-a", "node-b")
+	// balanced: nothing to report
+	balanced := violationTestPods("balanced", "node-a", "node-c")
+
+	var pods []*corev1.Pod
+	for _, group := range [][]*corev1.Pod{api, web, cache, worker, balanced} {
+		pods = append(pods, group...)
+	}
+
+	violations := findPlacementViolations(nodes, pods, nil)
+	if len(violations) != 4 {
+		t.Fatalf("expected 4 violations, got %+v", violations)
+	}
+	got := make(map[string]PlacementViolation)
+	for _, v := range violations {
+		got[v.Name] = v
+	}
+
+	if v := got["api"]; v.Type != PlacementAntiAffinity || v.Severity != PlacementViolated || violations[0].Name != "api" {
+		t.Errorf("expected api's required anti-affinity violation first, got %+v", v)
+	}
+	if v := got["web"]; v.Type != PlacementTopologySpread || v.Severity != PlacementRisk {
+		t.Errorf("expected web's ScheduleAnyway spread as a risk, got %+v", v)
+	}
+	if v := got["cache"]; v.Type != PlacementSingleNode {
+		t.Errorf("expected cache on a single node, got %+v", v)
+	}
+	if v := got["worker"]; v.Type != PlacementSingleZone {
+		t.Errorf("expected worker in a single zone, got %+v", v)
+	}
+	if _, ok := got["balanced"]; ok {
+		t.Errorf("expected no violation for balanced")
+	}
+
+	if filtered := findPlacementViolations(nodes, pods, []string{"other"}); len(filtered) != 0 {
+		t.Errorf("expected the namespace filter to drop every workload, got %+v", filtered)
+	}
+}
@@ -0,0 +1,569 @@
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ConfigSource says where an environment variable or mounted file comes from
+type ConfigSource struct {
+	Kind string `json:"kind"`           // Literal, ConfigMap, Secret, Field, ResourceField, EmptyDir, PersistentVolumeClaim, ...
+	Name string `json:"name,omitempty"` // Object name, or the PVC/host path/CSI driver
+	Key  string `json:"key,omitempty"`  // Data key, field path, or resource name
+}
+
+// ResolvedEnvVar is one environment variable as the container sees it.
+// Secret values are redacted unless revealed; Overridden marks definitions
+// shadowed by a later one with the same name.
+type ResolvedEnvVar struct {
+	Name       string       `json:"name"`
+	Value      string       `json:"value"`
+	Source     ConfigSource `json:"source"`
+	Secret     bool         `json:"secret,omitempty"`
+	Redacted   bool         `json:"redacted,omitempty"`
+	Overridden bool         `json:"overridden,omitempty"`
+	Note       string       `json:"note,omitempty"`
+	Error      string       `json:"error,omitempty"` // Missing source; the container won't start
+}
+
+// MountedFile is a file projected from a ConfigMap, Secret, or the downward API
+type MountedFile struct {
+	Path   string       `json:"path"`
+	Source ConfigSource `json:"source"`
+	Size   int          `json:"size,omitempty"`
+}
+
+// ConfigMount is one of the container's volume mounts
+type ConfigMount struct {
+	MountPath string         `json:"mountPath"`
+	Volume    string         `json:"volume"`
+	SubPath   string         `json:"subPath,omitempty"`
+	ReadOnly  bool           `json:"readOnly,omitempty"`
+	Sources   []ConfigSource `json:"sources"`
+	Files     []MountedFile  `json:"files,omitempty"`
+	Note      string         `json:"note,omitempty"`
+	Errors    []string       `json:"errors,omitempty"`
+}
+
+// PodConfig is a container's effective environment and mounted configuration
+type PodConfig struct {
+	Namespace  string           `json:"namespace"`
+	Pod        string           `json:"pod"`
+	Container  string           `json:"container"`
+	Containers []string         `json:"containers"`
+	Env        []ResolvedEnvVar `json:"env"`
+	Mounts     []ConfigMount    `json:"mounts"`
+}
+
+// ErrContainerNotFound is returned when a pod has no container with the requested name
+var ErrContainerNotFound = errors.New("container not found")
+
+var (
+	errUnreadable = errors.New("not readable with the current permissions")
+	errMissingKey = errors.New("missing key")
+)
+
+// configLookup fetches the pod's ConfigMaps and Secrets by name
+type configLookup struct {
+	configMap func(name string) (*corev1.ConfigMap, error)
+	secret    func(name string) (*corev1.Secret, error)
+}
+
+// GetPodConfig resolves a container's environment and mounts from the cache.
+// Secret values are only included when reveal is set. The second return is
+// false when the pod doesn't exist.
+func GetPodConfig(namespace, name, container string, reveal bool) (*PodConfig, bool, error) {
+	cache := GetResourceCache()
+	if cache == nil || cache.Pods() == nil {
+		return nil, false, nil
+	}
+	pod, err := cache.Pods().Pods(namespace).Get(name)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	lookup := configLookup{
+		configMap: func(string) (*corev1.ConfigMap, error) { return nil, errUnreadable },
+		secret:    func(string) (*corev1.Secret, error) { return nil, errUnreadable },
+	}
+	if lister := cache.ConfigMaps(); lister != nil {
+		lookup.configMap = lister.ConfigMaps(namespace).Get
+	}
+	if lister := cache.Secrets(); lister != nil {
+		lookup.secret = lister.Secrets(namespace).Get
+	}
+	config, err := resolvePodConfig(pod, container, lookup, reveal)
+	return config, true, err
+}
+
+// resolvePodConfig applies envFrom sources then env entries in order, the way
+// the kubelet does, and lists what each volume mount projects
+func resolvePodConfig(pod *corev1.Pod, containerName string, lookup configLookup, reveal bool) (*PodConfig, error) {
+	var container *corev1.Container
+	var names []string
+	all := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for i := range all {
+		names = append(names, all[i].Name)
+		if all[i].Name == containerName || (containerName == "" && container == nil && i >= len(pod.Spec.InitContainers)) {
+			container = &all[i]
+		}
+	}
+	if container == nil {
+		return nil, fmt.Errorf("%w: %s", ErrContainerNotFound, containerName)
+	}
+
+	config := &PodConfig{
+		Namespace:  pod.Namespace,
+		Pod:        pod.Name,
+		Container:  container.Name,
+		Containers: names,
+		Env:        resolveEnv(pod, container, lookup),
+		Mounts:     []ConfigMount{},
+	}
+	for i := range config.Env {
+		if config.Env[i].Secret && !reveal && config.Env[i].Value != "" {
+			config.Env[i].Value = redactedValue
+			config.Env[i].Redacted = true
+		}
+	}
+	for _, m := range container.VolumeMounts {
+		config.Mounts = append(config.Mounts, resolveMount(pod, m, lookup))
+	}
+	return config, nil
+}
+
+func resolveEnv(pod *corev1.Pod, container *corev1.Container, lookup configLookup) []ResolvedEnvVar {
+	env := []ResolvedEnvVar{}
+	latest := make(map[string]int) // Name -> index in env
+	add := func(v ResolvedEnvVar) {
+		if i, ok := latest[v.Name]; ok {
+			env[i].Overridden = true
+		}
+		latest[v.Name] = len(env)
+		env = append(env, v)
+	}
+
+	for _, from := range container.EnvFrom {
+		switch {
+		case from.ConfigMapRef != nil:
+			src := ConfigSource{Kind: "ConfigMap", Name: from.ConfigMapRef.Name}
+			cm, err := lookup.configMap(src.Name)
+			if err != nil {
+				if !isOptionalMissing(err, from.ConfigMapRef.Optional) {
+					add(envSourceFailure(ResolvedEnvVar{Name: from.Prefix + "*", Source: src}, err))
+				}
+				continue
+			}
+			for _, k := range sortedKeys(cm.Data) {
+				add(ResolvedEnvVar{Name: from.Prefix + k, Value: cm.Data[k], Source: ConfigSource{Kind: src.Kind, Name: src.Name, Key: k}})
+			}
+		case from.SecretRef != nil:
+			src := ConfigSource{Kind: "Secret", Name: from.SecretRef.Name}
+			secret, err := lookup.secret(src.Name)
+			if err != nil {
+				if !isOptionalMissing(err, from.SecretRef.Optional) {
+					add(envSourceFailure(ResolvedEnvVar{Name: from.Prefix + "*", Source: src, Secret: true}, err))
+				}
+				continue
+			}
+			for _, k := range sortedKeys(secret.Data) {
+				add(ResolvedEnvVar{Name: from.Prefix + k, Value: string(secret.Data[k]), Source: ConfigSource{Kind: src.Kind, Name: src.Name, Key: k}, Secret: true})
+			}
+		}
+	}
+
+	for _, e := range container.Env {
+		v := ResolvedEnvVar{Name: e.Name, Source: ConfigSource{Kind: "Literal"}}
+		switch {
+		case e.ValueFrom == nil:
+			// $(VAR) references expand against variables defined earlier
+			v.Value, v.Secret = expandEnvRefs(e.Value, func(name string) (string, bool, bool) {
+				i, ok := latest[name]
+				if !ok || env[i].Error != "" || env[i].Note != "" {
+					return "", false, false
+				}
+				return env[i].Value, env[i].Secret, true
+			})
+		case e.ValueFrom.ConfigMapKeyRef != nil:
+			ref := e.ValueFrom.ConfigMapKeyRef
+			v.Source = ConfigSource{Kind: "ConfigMap", Name: ref.Name, Key: ref.Key}
+			cm, err := lookup.configMap(ref.Name)
+			if err == nil {
+				var ok bool
+				if v.Value, ok = cm.Data[ref.Key]; !ok {
+					err = errMissingKey
+				}
+			}
+			if err != nil {
+				if isOptionalMissing(err, ref.Optional) {
+					continue
+				}
+				v = envSourceFailure(v, err)
+			}
+		case e.ValueFrom.SecretKeyRef != nil:
+			ref := e.ValueFrom.SecretKeyRef
+			v.Source = ConfigSource{Kind: "Secret", Name: ref.Name, Key: ref.Key}
+			v.Secret = true
+			secret, err := lookup.secret(ref.Name)
+			if err == nil {
+				data, ok := secret.Data[ref.Key]
+				if !ok {
+					err = errMissingKey
+				}
+				v.Value = string(data)
+			}
+			if err != nil {
+				if isOptionalMissing(err, ref.Optional) {
+					continue
+				}
+				v = envSourceFailure(v, err)
+			}
+		case e.ValueFrom.FieldRef != nil:
+			v.Source = ConfigSource{Kind: "Field", Key: e.ValueFrom.FieldRef.FieldPath}
+			v.Value, v.Note = podFieldValue(pod, e.ValueFrom.FieldRef.FieldPath)
+		case e.ValueFrom.ResourceFieldRef != nil:
+			ref := e.ValueFrom.ResourceFieldRef
+			v.Source = ConfigSource{Kind: "ResourceField", Name: ref.ContainerName, Key: ref.Resource}
+			v.Value, v.Note = resourceFieldValue(pod, container, ref)
+		}
+		add(v)
+	}
+	return env
+}
+
+// expandEnvRefs expands $(NAME) references like the kubelet: $$ escapes a
+// dollar sign and references to undefined variables are left as is. The bool
+// reports whether a secret value was substituted.
+func expandEnvRefs(value string, lookup func(name string) (string, bool, bool)) (string, bool) {
+	var b strings.Builder
+	secret := false
+	for i := 0; i < len(value); i++ {
+		if value[i] != '$' || i+1 >= len(value) {
+			b.WriteByte(value[i])
+			continue
+		}
+		switch value[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+		case '(':
+			end := strings.IndexByte(value[i+2:], ')')
+			if end < 0 {
+				b.WriteByte('$')
+				continue
+			}
+			name := value[i+2 : i+2+end]
+			if v, isSecret, ok := lookup(name); ok {
+				b.WriteString(v)
+				secret = secret || isSecret
+			} else {
+				b.WriteString(value[i : i+3+end])
+			}
+			i += 2 + end
+		default:
+			b.WriteByte('$')
+		}
+	}
+	return b.String(), secret
+}
+
+// podFieldValue resolves a downward API field path, returning a note when the
+// value isn't known yet
+func podFieldValue(pod *corev1.Pod, fieldPath string) (string, string) {
+	switch fieldPath {
+	case "metadata.name":
+		return pod.Name, ""
+	case "metadata.namespace":
+		return pod.Namespace, ""
+	case "metadata.uid":
+		return string(pod.UID), ""
+	case "spec.nodeName":
+		return pod.Spec.NodeName, notYet(pod.Spec.NodeName, "pod is not scheduled")
+	case "spec.serviceAccountName":
+		return pod.Spec.ServiceAccountName, ""
+	case "status.hostIP":
+		return pod.Status.HostIP, notYet(pod.Status.HostIP, "pod has no host IP yet")
+	case "status.podIP":
+		return pod.Status.PodIP, notYet(pod.Status.PodIP, "pod has no IP yet")
+	case "status.podIPs":
+		ips := make([]string, 0, len(pod.Status.PodIPs))
+		for _, ip := range pod.Status.PodIPs {
+			ips = append(ips, ip.IP)
+		}
+		return strings.Join(ips, ","), notYet(strings.Join(ips, ","), "pod has no IP yet")
+	}
+	for prefix, m := range map[string]map[string]string{"metadata.labels": pod.Labels, "metadata.annotations": pod.Annotations} {
+		if fieldPath == prefix {
+			return "", "only usable in downward API volumes"
+		}
+		if key, ok := strings.CutPrefix(fieldPath, prefix+"['"); ok {
+			return m[strings.TrimSuffix(key, "']")], ""
+		}
+	}
+	return "", "unsupported field path"
+}
+
+func notYet(value, note string) string {
+	if value == "" {
+		return note
+	}
+	return ""
+}
+
+// resourceFieldValue resolves a container resource request or limit in units
+// of the divisor, rounding up like the kubelet. Unset limits default to the
+// node's allocatable capacity, which isn't known here.
+func resourceFieldValue(pod *corev1.Pod, container *corev1.Container, ref *corev1.ResourceFieldSelector) (string, string) {
+	target := container
+	if ref.ContainerName != "" && ref.ContainerName != container.Name {
+		target = nil
+		for i := range pod.Spec.Containers {
+			if pod.Spec.Containers[i].Name == ref.ContainerName {
+				target = &pod.Spec.Containers[i]
+			}
+		}
+		if target == nil {
+			return "", "container " + ref.ContainerName + " not found"
+		}
+	}
+
+	kind, name, _ := strings.Cut(ref.Resource, ".")
+	list := target.Resources.Limits
+	if kind == "requests" {
+		list = target.Resources.Requests
+	}
+	q, ok := list[corev1.ResourceName(name)]
+	if !ok {
+		if kind == "limits" {
+			return "", "no limit set: defaults to node allocatable"
+		}
+		return "0", ""
+	}
+
+	divisor := ref.Divisor
+	if divisor.IsZero() {
+		divisor = resource.MustParse("1")
+	}
+	if name == string(corev1.ResourceCPU) {
+		return fmt.Sprint(int64(math.Ceil(float64(q.MilliValue()) / float64(divisor.MilliValue())))), ""
+	}
+	return fmt.Sprint(int64(math.Ceil(float64(q.Value()) / float64(divisor.Value())))), ""
+}
+
+func resolveMount(pod *corev1.Pod, m corev1.VolumeMount, lookup configLookup) ConfigMount {
+	mount := ConfigMount{MountPath: m.MountPath, Volume: m.Name, SubPath: m.SubPath, ReadOnly: m.ReadOnly, Sources: []ConfigSource{}}
+	var vol *corev1.Volume
+	for i := range pod.Spec.Volumes {
+		if pod.Spec.Volumes[i].Name == m.Name {
+			vol = &pod.Spec.Volumes[i]
+		}
+	}
+	if vol == nil {
+		mount.Errors = append(mount.Errors, "volume not found in pod spec")
+		return mount
+	}
+
+	var files []MountedFile
+	addFiles := func(f []MountedFile, err string) {
+		files = append(files, f...)
+		if err != "" {
+			mount.Errors = append(mount.Errors, err)
+		}
+	}
+	src := volumeSource(vol)
+	switch {
+	case vol.ConfigMap != nil:
+		addFiles(configMapFiles(vol.ConfigMap.Name, vol.ConfigMap.Items, vol.ConfigMap.Optional, lookup))
+	case vol.Secret != nil:
+		addFiles(secretFiles(vol.Secret.SecretName, vol.Secret.Items, vol.Secret.Optional, lookup))
+	case vol.DownwardAPI != nil:
+		files = append(files, downwardFiles(vol.DownwardAPI.Items)...)
+	case vol.Projected != nil:
+		src = nil
+		for _, p := range vol.Projected.Sources {
+			switch {
+			case p.ConfigMap != nil:
+				src = append(src, ConfigSource{Kind: "ConfigMap", Name: p.ConfigMap.Name})
+				addFiles(configMapFiles(p.ConfigMap.Name, p.ConfigMap.Items, p.ConfigMap.Optional, lookup))
+			case p.Secret != nil:
+				src = append(src, ConfigSource{Kind: "Secret", Name: p.Secret.Name})
+				addFiles(secretFiles(p.Secret.Name, p.Secret.Items, p.Secret.Optional, lookup))
+			case p.DownwardAPI != nil:
+				src = append(src, ConfigSource{Kind: "DownwardAPI"})
+				files = append(files, downwardFiles(p.DownwardAPI.Items)...)
+			case p.ServiceAccountToken != nil:
+				src = append(src, ConfigSource{Kind: "ServiceAccountToken", Name: pod.Spec.ServiceAccountName})
+				files = append(files, MountedFile{Path: p.ServiceAccountToken.Path, Source: ConfigSource{Kind: "ServiceAccountToken", Name: pod.Spec.ServiceAccountName}})
+			case p.ClusterTrustBundle != nil:
+				src = append(src, ConfigSource{Kind: "ClusterTrustBundle", Name: ptrString(p.ClusterTrustBundle.Name)})
+				files = append(files, MountedFile{Path: p.ClusterTrustBundle.Path, Source: ConfigSource{Kind: "ClusterTrustBundle", Name: ptrString(p.ClusterTrustBundle.Name)}})
+			}
+		}
+	}
+	mount.Sources = append(mount.Sources, src...)
+
+	// A subPath mounts a single entry of the volume, which isn't updated when its source changes
+	if m.SubPath != "" && files != nil {
+		var picked []MountedFile
+		for _, f := range files {
+			if f.Path == m.SubPath || strings.HasPrefix(f.Path, m.SubPath+"/") {
+				f.Path = strings.TrimPrefix(strings.TrimPrefix(f.Path, m.SubPath), "/")
+				picked = append(picked, f)
+			}
+		}
+		files = picked
+		mount.Note = "subPath mounts don't receive updates when the source changes"
+	}
+	for i := range files {
+		files[i].Path = path.Join(m.MountPath, files[i].Path)
+	}
+	mount.Files = files
+	return mount
+}
+
+// volumeSource describes a non-projected volume's source
+func volumeSource(vol *corev1.Volume) []ConfigSource {
+	switch {
+	case vol.ConfigMap != nil:
+		return []ConfigSource{{Kind: "ConfigMap", Name: vol.ConfigMap.Name}}
+	case vol.Secret != nil:
+		return []ConfigSource{{Kind: "Secret", Name: vol.Secret.SecretName}}
+	case vol.DownwardAPI != nil:
+		return []ConfigSource{{Kind: "DownwardAPI"}}
+	case vol.EmptyDir != nil:
+		return []ConfigSource{{Kind: "EmptyDir"}}
+	case vol.PersistentVolumeClaim != nil:
+		return []ConfigSource{{Kind: "PersistentVolumeClaim", Name: vol.PersistentVolumeClaim.ClaimName}}
+	case vol.HostPath != nil:
+		return []ConfigSource{{Kind: "HostPath", Name: vol.HostPath.Path}}
+	case vol.CSI != nil:
+		return []ConfigSource{{Kind: "CSI", Name: vol.CSI.Driver}}
+	case vol.Ephemeral != nil:
+		return []ConfigSource{{Kind: "Ephemeral"}}
+	case vol.NFS != nil:
+		return []ConfigSource{{Kind: "NFS", Name: vol.NFS.Server + ":" + vol.NFS.Path}}
+	case vol.Image != nil:
+		return []ConfigSource{{Kind: "Image", Name: vol.Image.Reference}}
+	}
+	return []ConfigSource{{Kind: "Other"}}
+}
+
+func configMapFiles(name string, items []corev1.KeyToPath, optional *bool, lookup configLookup) ([]MountedFile, string) {
+	src := ConfigSource{Kind: "ConfigMap", Name: name}
+	cm, err := lookup.configMap(name)
+	if err != nil {
+		if isOptionalMissing(err, optional) {
+			return nil, ""
+		}
+		return nil, sourceError(src, err)
+	}
+	sizes := make(map[string]int, len(cm.Data)+len(cm.BinaryData))
+	for k, v := range cm.Data {
+		sizes[k] = len(v)
+	}
+	for k, v := range cm.BinaryData {
+		sizes[k] = len(v)
+	}
+	return keyFiles(src, sizes, items, optional)
+}
+
+func secretFiles(name string, items []corev1.KeyToPath, optional *bool, lookup configLookup) ([]MountedFile, string) {
+	src := ConfigSource{Kind: "Secret", Name: name}
+	secret, err := lookup.secret(name)
+	if err != nil {
+		if errors.Is(err, errUnreadable) {
+			// Still list explicitly projected keys
+			files, _ := keyFiles(src, nil, items, optional)
+			return files, sourceError(src, err)
+		}
+		if isOptionalMissing(err, optional) {
+			return nil, ""
+		}
+		return nil, sourceError(src, err)
+	}
+	sizes := make(map[string]int, len(secret.Data))
+	for k, v := range secret.Data {
+		sizes[k] = len(v)
+	}
+	return keyFiles(src, sizes, items, optional)
+}
+
+// keyFiles maps data keys to file paths: every key by default, or the listed items
+func keyFiles(src ConfigSource, sizes map[string]int, items []corev1.KeyToPath, optional *bool) ([]MountedFile, string) {
+	var files []MountedFile
+	if len(items) == 0 {
+		for _, k := range sortedKeys(sizes) {
+			files = append(files, MountedFile{Path: k, Source: ConfigSource{Kind: src.Kind, Name: src.Name, Key: k}, Size: sizes[k]})
+		}
+		return files, ""
+	}
+	var missing []string
+	for _, item := range items {
+		size, ok := sizes[item.Key]
+		if !ok && sizes != nil {
+			missing = append(missing, item.Key)
+			continue
+		}
+		files = append(files, MountedFile{Path: item.Path, Source: ConfigSource{Kind: src.Kind, Name: src.Name, Key: item.Key}, Size: size})
+	}
+	if len(missing) > 0 && (optional == nil || !*optional) {
+		return files, fmt.Sprintf("%s %s has no key %s", src.Kind, src.Name, strings.Join(missing, ", "))
+	}
+	return files, ""
+}
+
+func downwardFiles(items []corev1.DownwardAPIVolumeFile) []MountedFile {
+	files := make([]MountedFile, 0, len(items))
+	for _, item := range items {
+		f := MountedFile{Path: item.Path, Source: ConfigSource{Kind: "Field"}}
+		if item.FieldRef != nil {
+			f.Source.Key = item.FieldRef.FieldPath
+		} else if item.ResourceFieldRef != nil {
+			f.Source = ConfigSource{Kind: "ResourceField", Name: item.ResourceFieldRef.ContainerName, Key: item.ResourceFieldRef.Resource}
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// isOptionalMissing reports whether err is a missing optional source or key,
+// which the kubelet ignores
+func isOptionalMissing(err error, optional *bool) bool {
+	return optional != nil && *optional && (apierrors.IsNotFound(err) || errors.Is(err, errMissingKey))
+}
+
+// envSourceFailure records why a variable's value is unknown. Sources Radar
+// can't read are noted; missing ones are errors that stop the container.
+func envSourceFailure(v ResolvedEnvVar, err error) ResolvedEnvVar {
+	v.Value = ""
+	if errors.Is(err, errUnreadable) {
+		v.Note = sourceError(v.Source, err)
+	} else {
+		v.Error = sourceError(v.Source, err)
+	}
+	return v
+}
+
+func sourceError(src ConfigSource, err error) string {
+	switch {
+	case errors.Is(err, errMissingKey):
+		return fmt.Sprintf("%s %s has no key %s", src.Kind, src.Name, src.Key)
+	case apierrors.IsNotFound(err):
+		return fmt.Sprintf("%s %s not found", src.Kind, src.Name)
+	}
+	return fmt.Sprintf("%s %s: %v", src.Kind, src.Name, err)
+}
+
+func ptrString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
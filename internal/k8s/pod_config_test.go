@@ -0,0 +1,191 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testConfigLookup() configLookup {
+	configMaps := map[string]*corev1.ConfigMap{
+		"app-config": {Data: map[string]string{"LOG_LEVEL": "info", "DB_HOST": "db", "app.yaml": "port: 8080"}},
+	}
+	secrets := map[string]*corev1.Secret{
+		"db-creds": {Data: map[string][]byte{"password": []byte("hunter2"), "user": []byte("app")}},
+	}
+	return configLookup{
+		configMap: func(name string) (*corev1.ConfigMap, error) {
+			if cm, ok := configMaps[name]; ok {
+				return cm, nil
+			}
+			return nil, apierrors.NewNotFound(corev1.Resource("configmaps"), name)
+		},
+		secret: func(name string) (*corev1.Secret, error) {
+			if s, ok := secrets[name]; ok {
+				return s, nil
+			}
+			return nil, apierrors.NewNotFound(corev1.Resource("secrets"), name)
+		},
+	}
+}
+
+func TestResolvePodConfigEnv(t *testing.T) {
+	optional := true
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "api-1", Labels: map[string]string{"app": "api"}},
+		Spec: corev1.PodSpec{
+			NodeName: "node-a",
+			Containers: []corev1.Container{{
+				Name: "app",
+				EnvFrom: []corev1.EnvFromSource{
+					{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"}}},
+					{Prefix: "DB_", SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "db-creds"}}},
+					{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "optional-config"}, Optional: &optional}},
+				},
+				Env: []corev1.EnvVar{
+					{Name: "LOG_LEVEL", Value: "debug"},
+					{Name: "DSN", Value: "postgres://$(DB_user):$(DB_password)@$(DB_HOST)/$(UNDEFINED) $$(DB_HOST)"},
+					{Name: "NODE", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}}},
+					{Name: "APP", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.labels['app']"}}},
+					{Name: "MISSING", ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"}, Key: "nope"}}},
+					{Name: "MEM_MB", ValueFrom: &corev1.EnvVarSource{ResourceFieldRef: &corev1.ResourceFieldSelector{Resource: "limits.memory", Divisor: resource.MustParse("1Mi")}}},
+				},
+				Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("300M")}},
+			}},
+		},
+	}
+
+	config, err := resolvePodConfig(pod, "", testConfigLookup(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]ResolvedEnvVar)
+	for _, v := range config.Env {
+		if !v.Overridden {
+			byName[v.Name] = v
+		}
+	}
+
+	if v := byName["LOG_LEVEL"]; v.Value != "debug" || v.Source.Kind != "Literal" {
+		t.Errorf("expected env to override envFrom, got %+v", v)
+	}
+	overridden := 0
+	for _, v := range config.Env {
+		if v.Overridden {
+			overridden++
+		}
+	}
+	if overridden != 1 {
+		t.Errorf("expected 1 overridden variable, got %d", overridden)
+	}
+	if v := byName["DB_password"]; !v.Secret || !v.Redacted || v.Value != redactedValue {
+		t.Errorf("expected redacted secret, got %+v", v)
+	}
+	if v := byName["DSN"]; !v.Redacted {
+		t.Errorf("expected value expanded from a secret to be redacted, got %+v", v)
+	}
+	if v := byName["NODE"]; v.Value != "node-a" {
+		t.Errorf("expected node name, got %+v", v)
+	}
+	if v := byName["APP"]; v.Value != "api" {
+		t.Errorf("expected label value, got %+v", v)
+	}
+	if v := byName["MISSING"]; !strings.Contains(v.Error, "has no key nope") {
+		t.Errorf("expected missing key error, got %+v", v)
+	}
+	if v := byName["MEM_MB"]; v.Value != "287" {
+		t.Errorf("expected 300M rounded up to 287Mi, got %+v", v)
+	}
+	if _, ok := byName["*"]; ok {
+		t.Error("missing optional envFrom source should be ignored")
+	}
+
+	revealed, _ := resolvePodConfig(pod, "app", testConfigLookup(), true)
+	for _, v := range revealed.Env {
+		if v.Name == "DSN" && v.Value != "postgres://app:hunter2@db/$(UNDEFINED) $(DB_HOST)" {
+			t.Errorf("unexpected expansion %q", v.Value)
+		}
+	}
+
+	if _, err := resolvePodConfig(pod, "sidecar", testConfigLookup(), false); err == nil {
+		t.Error("expected error for unknown container")
+	}
+}
+
+func TestResolvePodConfigMounts(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "api-1"},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: "api",
+			Containers: []corev1.Container{{
+				Name: "app",
+				VolumeMounts: []corev1.VolumeMount{
+					{Name: "config", MountPath: "/etc/app"},
+					{Name: "config", MountPath: "/app/app.yaml", SubPath: "app.yaml"},
+					{Name: "creds", MountPath: "/var/run/creds", ReadOnly: true},
+					{Name: "combined", MountPath: "/projected"},
+					{Name: "data", MountPath: "/data"},
+				},
+			}},
+			Volumes: []corev1.Volume{
+				{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "app-config"}}}},
+				{Name: "creds", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "db-creds", Items: []corev1.KeyToPath{{Key: "password", Path: "db/pass"}, {Key: "token", Path: "token"}}}}},
+				{Name: "combined", VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+					{ServiceAccountToken: &corev1.ServiceAccountTokenProjection{Path: "token"}},
+					{ConfigMap: &corev1.ConfigMapProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}}},
+				}}}},
+				{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "api-data"}}},
+			},
+		},
+	}
+
+	config, err := resolvePodConfig(pod, "app", testConfigLookup(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Mounts) != 5 {
+		t.Fatalf("expected 5 mounts, got %d", len(config.Mounts))
+	}
+
+	whole, sub, creds, projected, data := config.Mounts[0], config.Mounts[1], config.Mounts[2], config.Mounts[3], config.Mounts[4]
+	if len(whole.Files) != 3 || whole.Files[2].Path != "/etc/app/app.yaml" || whole.Files[2].Size != len("port: 8080") {
+		t.Errorf("expected every ConfigMap key as a file, got %+v", whole.Files)
+	}
+	if len(sub.Files) != 1 || sub.Files[0].Path != "/app/app.yaml" || sub.Note == "" {
+		t.Errorf("expected single subPath file with a note, got %+v", sub)
+	}
+	if len(creds.Files) != 1 || creds.Files[0].Path != "/var/run/creds/db/pass" || len(creds.Errors) != 1 {
+		t.Errorf("expected projected secret key and missing key error, got %+v", creds)
+	}
+	if len(projected.Sources) != 2 || len(projected.Files) != 1 || len(projected.Errors) != 1 || !strings.Contains(projected.Errors[0], "not found") {
+		t.Errorf("expected token file and missing ConfigMap error, got %+v", projected)
+	}
+	if len(data.Sources) != 1 || data.Sources[0].Kind != "PersistentVolumeClaim" || data.Sources[0].Name != "api-data" {
+		t.Errorf("expected PVC source, got %+v", data.Sources)
+	}
+}
+
+func TestResolvePodConfigUnreadableSecrets(t *testing.T) {
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "app",
+			Env: []corev1.EnvVar{
+				{Name: "PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "db-creds"}, Key: "password"}}},
+			},
+		}}},
+	}
+	lookup := testConfigLookup()
+	lookup.secret = func(string) (*corev1.Secret, error) { return nil, errUnreadable }
+
+	config, err := resolvePodConfig(pod, "", lookup, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := config.Env[0]; v.Error != "" || v.Note == "" || v.Value != "" {
+		t.Errorf("expected unreadable secret to be noted rather than an error, got %+v", v)
+	}
+}
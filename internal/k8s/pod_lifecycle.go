@@ -0,0 +1,309 @@
+package k8s
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// PodLifecycleTiming breaks a pod's startup into phases. Each phase is measured
+// from the previous milestone; durations are nil when a milestone is unknown.
+// Image pull events expire with the K8s event TTL (1h by default), so for older
+// pods ContainerStartMs is measured from scheduling instead.
+type PodLifecycleTiming struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+	ScheduledAt *time.Time `json:"scheduledAt,omitempty"`
+	PulledAt    *time.Time `json:"pulledAt,omitempty"` // Last image pulled
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	ReadyAt     *time.Time `json:"readyAt,omitempty"`
+
+	SchedulingMs     *int64 `json:"schedulingMs,omitempty"`     // created -> scheduled
+	ImagePullMs      *int64 `json:"imagePullMs,omitempty"`      // scheduled -> pulled (includes init containers and volume mounts)
+	ContainerStartMs *int64 `json:"containerStartMs,omitempty"` // pulled -> started
+	ReadinessMs      *int64 `json:"readinessMs,omitempty"`      // started -> ready
+	TotalMs          *int64 `json:"totalMs,omitempty"`          // created -> ready
+}
+
+// PhaseStats summarizes one startup phase across a workload's pods
+type PhaseStats struct {
+	Samples int   `json:"samples"`
+	P50Ms   int64 `json:"p50Ms"`
+	P95Ms   int64 `json:"p95Ms"`
+}
+
+// WorkloadStartupStats aggregates pod startup timings for a workload
+type WorkloadStartupStats struct {
+	Kind           string      `json:"kind"`
+	Namespace      string      `json:"namespace"`
+	Name           string      `json:"name"`
+	Pods           int         `json:"pods"`
+	Scheduling     *PhaseStats `json:"scheduling,omitempty"`
+	ImagePull      *PhaseStats `json:"imagePull,omitempty"`
+	ContainerStart *PhaseStats `json:"containerStart,omitempty"`
+	Readiness      *PhaseStats `json:"readiness,omitempty"`
+	Total          *PhaseStats `json:"total,omitempty"`
+}
+
+// podLifecycleMarks collects milestone candidates from pod status, K8s events,
+// or timeline history. Pulls and starts repeat when containers restart, so
+// the latest one before the pod became ready is used.
+type podLifecycleMarks struct {
+	created   time.Time
+	scheduled time.Time
+	ready     time.Time
+	pulled    []time.Time
+	started   []time.Time
+}
+
+// earliest sets *t to candidate if it's earlier than the current value
+func earliest(t *time.Time, candidate time.Time) {
+	if !candidate.IsZero() && (t.IsZero() || candidate.Before(*t)) {
+		*t = candidate
+	}
+}
+
+// milestone picks the latest candidate at or before ready, or the earliest
+// candidate if ready is unknown
+func milestone(candidates []time.Time, ready time.Time) time.Time {
+	var best time.Time
+	for _, c := range candidates {
+		if ready.IsZero() {
+			earliest(&best, c)
+		} else if !c.After(ready) && c.After(best) {
+			best = c
+		}
+	}
+	return best
+}
+
+func (m *podLifecycleMarks) timing(namespace, name string) PodLifecycleTiming {
+	t := PodLifecycleTiming{Namespace: namespace, Name: name}
+	pulled := milestone(m.pulled, m.ready)
+	started := milestone(m.started, m.ready)
+
+	ptr := func(ts time.Time) *time.Time {
+		if ts.IsZero() {
+			return nil
+		}
+		return &ts
+	}
+	t.CreatedAt, t.ScheduledAt, t.PulledAt, t.StartedAt, t.ReadyAt =
+		ptr(m.created), ptr(m.scheduled), ptr(pulled), ptr(started), ptr(m.ready)
+
+	t.SchedulingMs = durationMs(m.created, m.scheduled)
+	t.ImagePullMs = durationMs(m.scheduled, pulled)
+	if !pulled.IsZero() {
+		t.ContainerStartMs = durationMs(pulled, started)
+	} else {
+		t.ContainerStartMs = durationMs(m.scheduled, started)
+	}
+	t.ReadinessMs = durationMs(started, m.ready)
+	t.TotalMs = durationMs(m.created, m.ready)
+	return t
+}
+
+// durationMs returns the milliseconds from start to end, or nil if either is
+// unknown or they're out of order
+func durationMs(start, end time.Time) *int64 {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return nil
+	}
+	ms := end.Sub(start).Milliseconds()
+	return &ms
+}
+
+// GetPodLifecycle computes the startup timing breakdown for a pod from its
+// status and cached K8s events. Returns false if the pod isn't in the cache.
+func GetPodLifecycle(namespace, name string) (*PodLifecycleTiming, bool) {
+	cache := GetResourceCache()
+	if cache == nil || cache.Pods() == nil {
+		return nil, false
+	}
+	pod, err := cache.Pods().Pods(namespace).Get(name)
+	if err != nil {
+		return nil, false
+	}
+
+	var events []*corev1.Event
+	if eventLister := cache.Events(); eventLister != nil {
+		events, _ = eventLister.Events(namespace).List(labels.Everything())
+	}
+	t := podLifecycleFromStatus(pod, events)
+	return &t, true
+}
+
+// podLifecycleFromStatus derives milestones from pod conditions, container
+// states, and the pod's image pull events
+func podLifecycleFromStatus(pod *corev1.Pod, events []*corev1.Event) PodLifecycleTiming {
+	m := podLifecycleMarks{created: pod.CreationTimestamp.Time}
+	for _, c := range pod.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case corev1.PodScheduled:
+			m.scheduled = c.LastTransitionTime.Time
+		case corev1.PodReady:
+			m.ready = c.LastTransitionTime.Time
+		}
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Running != nil {
+			m.started = append(m.started, cs.State.Running.StartedAt.Time)
+		}
+	}
+
+	for _, e := range events {
+		if e.InvolvedObject.Kind != "Pod" || e.InvolvedObject.Name != pod.Name || e.InvolvedObject.Namespace != pod.Namespace {
+			continue
+		}
+		if e.InvolvedObject.UID != "" && e.InvolvedObject.UID != pod.UID {
+			continue
+		}
+		if e.Reason == "Pulled" {
+			m.pulled = append(m.pulled, eventTime(e))
+		}
+	}
+	return m.timing(pod.Namespace, pod.Name)
+}
+
+// GetWorkloadStartupStats aggregates p50/p95 startup phases per workload from
+// pod history recorded in the timeline since the given time
+func GetWorkloadStartupStats(ctx context.Context, namespaces []string, since time.Time) ([]WorkloadStartupStats, error) {
+	store := timeline.GetStore()
+	if store == nil {
+		return []WorkloadStartupStats{}, nil
+	}
+	events, err := store.Query(ctx, timeline.QueryOptions{
+		Namespaces:       namespaces,
+		Kinds:            []string{"Pod"},
+		Since:            since,
+		Limit:            10000,
+		IncludeManaged:   true,
+		IncludeK8sEvents: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return aggregateStartupStats(events, since), nil
+}
+
+// aggregateStartupStats rebuilds each pod's milestones from its timeline events
+// and groups the resulting timings by owning workload. Only pods created since
+// the given time are counted.
+func aggregateStartupStats(events []timeline.TimelineEvent, since time.Time) []WorkloadStartupStats {
+	type podHistory struct {
+		namespace, name string
+		owner           *timeline.OwnerInfo
+		templateHash    string
+		marks           podLifecycleMarks
+	}
+	pods := make(map[string]*podHistory)
+
+	for i := range events {
+		e := &events[i]
+		key := e.Namespace + "/" + e.Name
+		p, ok := pods[key]
+		if !ok {
+			p = &podHistory{namespace: e.Namespace, name: e.Name}
+			pods[key] = p
+		}
+		if p.owner == nil && e.Owner != nil {
+			p.owner = e.Owner
+		}
+		if hash := e.Labels["pod-template-hash"]; hash != "" {
+			p.templateHash = hash
+		}
+		if e.CreatedAt != nil {
+			earliest(&p.marks.created, *e.CreatedAt)
+		}
+
+		switch e.Reason {
+		case "created":
+			earliest(&p.marks.created, e.Timestamp)
+		case "Scheduled":
+			earliest(&p.marks.scheduled, e.Timestamp)
+		case string(corev1.PodScheduled):
+			if e.HealthState != timeline.HealthDegraded {
+				earliest(&p.marks.scheduled, e.Timestamp)
+			}
+		case "Pulled":
+			p.marks.pulled = append(p.marks.pulled, e.Timestamp)
+		case "Started":
+			p.marks.started = append(p.marks.started, e.Timestamp)
+		case string(corev1.PodReady):
+			if e.HealthState == timeline.HealthHealthy {
+				earliest(&p.marks.ready, e.Timestamp)
+			}
+		}
+		// Informer updates don't carry a reason; the first healthy one marks readiness
+		if e.Source == timeline.SourceInformer && e.EventType == timeline.EventTypeUpdate && e.HealthState == timeline.HealthHealthy {
+			earliest(&p.marks.ready, e.Timestamp)
+		}
+	}
+
+	byWorkload := make(map[workloadKey][]PodLifecycleTiming)
+	for _, p := range pods {
+		// Pods created before the window only have partial history
+		if p.marks.created.IsZero() || p.marks.created.Before(since) {
+			continue
+		}
+		key := workloadKey{Kind: "Pod", Namespace: p.namespace, Name: p.name}
+		if p.owner != nil {
+			key = workloadFromOwner(p.namespace, p.owner.Kind, p.owner.Name, p.templateHash)
+		}
+		byWorkload[key] = append(byWorkload[key], p.marks.timing(p.namespace, p.name))
+	}
+
+	result := make([]WorkloadStartupStats, 0, len(byWorkload))
+	for key, timings := range byWorkload {
+		phase := func(get func(PodLifecycleTiming) *int64) *PhaseStats {
+			var values []int64
+			for _, t := range timings {
+				if v := get(t); v != nil {
+					values = append(values, *v)
+				}
+			}
+			if len(values) == 0 {
+				return nil
+			}
+			return &PhaseStats{Samples: len(values), P50Ms: Percentile(values, 50), P95Ms: Percentile(values, 95)}
+		}
+		result = append(result, WorkloadStartupStats{
+			Kind:           key.Kind,
+			Namespace:      key.Namespace,
+			Name:           key.Name,
+			Pods:           len(timings),
+			Scheduling:     phase(func(t PodLifecycleTiming) *int64 { return t.SchedulingMs }),
+			ImagePull:      phase(func(t PodLifecycleTiming) *int64 { return t.ImagePullMs }),
+			ContainerStart: phase(func(t PodLifecycleTiming) *int64 { return t.ContainerStartMs }),
+			Readiness:      phase(func(t PodLifecycleTiming) *int64 { return t.ReadinessMs }),
+			Total:          phase(func(t PodLifecycleTiming) *int64 { return t.TotalMs }),
+		})
+	}
+
+	// Slowest workloads first
+	sort.Slice(result, func(i, j int) bool {
+		pi, pj := p95OrZero(result[i].Total), p95OrZero(result[j].Total)
+		if pi != pj {
+			return pi > pj
+		}
+		return result[i].Namespace+"/"+result[i].Name < result[j].Namespace+"/"+result[j].Name
+	})
+	return result
+}
+
+func p95OrZero(s *PhaseStats) int64 {
+	if s == nil {
+		return 0
+	}
+	return s.P95Ms
+}
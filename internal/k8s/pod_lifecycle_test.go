@@ -0,0 +1,121 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+func TestPodLifecycleFromStatus(t *testing.T) {
+	created := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	at := func(s int) metav1.Time { return metav1.NewTime(created.Add(time.Duration(s) * time.Second)) }
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "api-1", UID: "uid-1", CreationTimestamp: at(0)},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: at(2)},
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: at(40)},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "app", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: at(25)}}},
+				{Name: "proxy", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: at(22)}}},
+			},
+		},
+	}
+	event := func(reason string, s int, uid string) *corev1.Event {
+		return &corev1.Event{
+			Reason:         reason,
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "shop", Name: "api-1", UID: k8stypes.UID(uid)},
+			LastTimestamp:  at(s),
+		}
+	}
+	events := []*corev1.Event{
+		event("Pulled", 12, "uid-1"),
+		event("Pulled", 20, "uid-1"),
+		event("Pulled", 5, "old-uid"), // Previous pod with the same name
+		event("Scheduled", 2, "uid-1"),
+	}
+
+	got := podLifecycleFromStatus(pod, events)
+	want := map[string]*int64{"scheduling": ms(2000), "imagePull": ms(18000), "containerStart": ms(5000), "readiness": ms(15000), "total": ms(40000)}
+	checkTiming(t, got, want)
+
+	// Without pull events, container start is measured from scheduling
+	got = podLifecycleFromStatus(pod, nil)
+	if got.ImagePullMs != nil || got.ContainerStartMs == nil || *got.ContainerStartMs != 23000 {
+		t.Errorf("expected container start from scheduling, got %+v", got)
+	}
+}
+
+func TestAggregateStartupStats(t *testing.T) {
+	base := time.Now().Add(-time.Hour)
+	owner := &timeline.OwnerInfo{Kind: "ReplicaSet", Name: "api-5f6d7"}
+	podEvents := func(name string, offset, pullSecs int) []timeline.TimelineEvent {
+		created := base.Add(time.Duration(offset) * time.Minute)
+		at := func(s int) time.Time { return created.Add(time.Duration(s) * time.Second) }
+		return []timeline.TimelineEvent{
+			{Kind: "Pod", Namespace: "shop", Name: name, Source: timeline.SourceInformer, EventType: timeline.EventTypeAdd, Timestamp: at(0), CreatedAt: &created, Owner: owner, Labels: map[string]string{"pod-template-hash": "5f6d7"}},
+			{Kind: "Pod", Namespace: "shop", Name: name, Source: timeline.SourceK8sEvent, Reason: "Scheduled", Timestamp: at(1), Owner: owner},
+			{Kind: "Pod", Namespace: "shop", Name: name, Source: timeline.SourceK8sEvent, Reason: "Pulled", Timestamp: at(1 + pullSecs), Owner: owner},
+			{Kind: "Pod", Namespace: "shop", Name: name, Source: timeline.SourceK8sEvent, Reason: "Started", Timestamp: at(2 + pullSecs), Owner: owner},
+			{Kind: "Pod", Namespace: "shop", Name: name, Source: timeline.SourceInformer, EventType: timeline.EventTypeUpdate, HealthState: timeline.HealthDegraded, Timestamp: at(3 + pullSecs)},
+			{Kind: "Pod", Namespace: "shop", Name: name, Source: timeline.SourceInformer, EventType: timeline.EventTypeUpdate, HealthState: timeline.HealthHealthy, Timestamp: at(12 + pullSecs)},
+			// A later container restart shouldn't move the startup milestones
+			{Kind: "Pod", Namespace: "shop", Name: name, Source: timeline.SourceK8sEvent, Reason: "Started", Timestamp: at(600), Owner: owner},
+		}
+	}
+
+	var events []timeline.TimelineEvent
+	events = append(events, podEvents("api-5f6d7-a", 0, 10)...)
+	events = append(events, podEvents("api-5f6d7-b", 1, 20)...)
+	events = append(events, podEvents("api-5f6d7-c", 2, 60)...)
+	// A pod created before the window only has partial history and is skipped
+	old := base.Add(-48 * time.Hour)
+	events = append(events, timeline.TimelineEvent{Kind: "Pod", Namespace: "shop", Name: "old", Source: timeline.SourceInformer, EventType: timeline.EventTypeUpdate, HealthState: timeline.HealthHealthy, Timestamp: base, CreatedAt: &old})
+
+	stats := aggregateStartupStats(events, base.Add(-time.Minute))
+	if len(stats) != 1 {
+		t.Fatalf("expected one workload, got %+v", stats)
+	}
+	s := stats[0]
+	if s.Kind != "Deployment" || s.Name != "api" || s.Pods != 3 {
+		t.Errorf("unexpected workload: %+v", s)
+	}
+	if s.ImagePull == nil || s.ImagePull.P50Ms != 20000 || s.ImagePull.P95Ms != 60000 {
+		t.Errorf("unexpected image pull stats: %+v", s.ImagePull)
+	}
+	if s.Readiness == nil || s.Readiness.P50Ms != 10000 || s.Readiness.Samples != 3 {
+		t.Errorf("unexpected readiness stats: %+v", s.Readiness)
+	}
+	if s.Total == nil || s.Total.P95Ms != 72000 {
+		t.Errorf("unexpected total stats: %+v", s.Total)
+	}
+}
+
+func ms(v int64) *int64 { return &v }
+
+func checkTiming(t *testing.T, got PodLifecycleTiming, want map[string]*int64) {
+	t.Helper()
+	fields := map[string]*int64{
+		"scheduling": got.SchedulingMs, "imagePull": got.ImagePullMs, "containerStart": got.ContainerStartMs,
+		"readiness": got.ReadinessMs, "total": got.TotalMs,
+	}
+	for name, w := range want {
+		g := fields[name]
+		if (g == nil) != (w == nil) || (g != nil && *g != *w) {
+			t.Errorf("%s: got %v, want %v", name, deref(g), deref(w))
+		}
+	}
+}
+
+func deref(v *int64) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
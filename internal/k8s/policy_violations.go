@@ -0,0 +1,331 @@
+package k8s
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// maxViolationsPerPolicy caps the violations returned per policy; Total still counts all of them
+const maxViolationsPerPolicy = 100
+
+// policyReportGroups are the groups serving Kyverno-style PolicyReports, newest first
+var policyReportGroups = []string{"openreports.io", "wgpolicyk8s.io"}
+
+// PolicyViolation is a single resource failing a policy
+type PolicyViolation struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Rule      string `json:"rule,omitempty"` // Kyverno rule name
+	Message   string `json:"message"`
+	Result    string `json:"result,omitempty"`   // Kyverno: fail, warn, error
+	Severity  string `json:"severity,omitempty"` // Kyverno policy severity annotation
+}
+
+// PolicyViolationGroup aggregates the violations of one policy or constraint
+type PolicyViolationGroup struct {
+	Engine     string            `json:"engine"`     // Gatekeeper or Kyverno
+	PolicyKind string            `json:"policyKind"` // Constraint kind (K8sRequiredLabels), ClusterPolicy, or Policy
+	Policy     string            `json:"policy"`     // Constraint or policy name (namespace/name for namespaced Kyverno Policies)
+	Action     string            `json:"action,omitempty"`
+	Enforced   bool              `json:"enforced"` // Violations are blocked at admission (deny/Enforce) rather than only audited
+	Total      int               `json:"total"`
+	Namespaces map[string]int    `json:"namespaces"` // Violation count per namespace ("" for cluster-scoped resources)
+	Violations []PolicyViolation `json:"violations"`
+}
+
+// NamespaceViolationCount is the number of violations in one namespace across all policies
+type NamespaceViolationCount struct {
+	Namespace string `json:"namespace"`
+	Count     int    `json:"count"`
+}
+
+// PolicyViolationsSummary is the aggregated view of Gatekeeper and Kyverno violations
+type PolicyViolationsSummary struct {
+	Engines     []string                  `json:"engines"` // Policy engines detected in the cluster
+	Total       int                       `json:"total"`
+	ByPolicy    []PolicyViolationGroup    `json:"byPolicy"`
+	ByNamespace []NamespaceViolationCount `json:"byNamespace"`
+}
+
+// GetPolicyViolations aggregates Gatekeeper constraint audit results and
+// Kyverno policy reports from the dynamic cache. When namespaces is non-empty,
+// only violations for resources in those namespaces are included.
+func GetPolicyViolations(namespaces []string) (*PolicyViolationsSummary, error) {
+	dc := GetDynamicResourceCache()
+	if dc == nil {
+		return nil, fmt.Errorf("dynamic resource cache not initialized")
+	}
+	discovery := GetResourceDiscovery()
+	if discovery == nil {
+		return nil, fmt.Errorf("resource discovery not initialized")
+	}
+	resources, err := discovery.GetAPIResources()
+	if err != nil {
+		return nil, err
+	}
+
+	groups := map[string]*PolicyViolationGroup{}
+	var engines []string
+
+	gatekeeper := collectGatekeeperViolations(dc, resources, groups)
+	if gatekeeper {
+		engines = append(engines, PolicyEngineGatekeeper)
+	}
+	if collectKyvernoViolations(dc, discovery, resources, groups) {
+		engines = append(engines, PolicyEngineKyverno)
+	}
+
+	return summarizePolicyViolations(groups, engines, namespaces), nil
+}
+
+// collectGatekeeperViolations reads status.violations from every constraint kind.
+// Returns false if Gatekeeper isn't installed.
+func collectGatekeeperViolations(dc *DynamicResourceCache, resources []APIResource, groups map[string]*PolicyViolationGroup) bool {
+	found := false
+	for _, res := range resources {
+		if res.Group != "constraints.gatekeeper.sh" || !slices.Contains(res.Verbs, "watch") {
+			continue
+		}
+		found = true
+		gvr := schema.GroupVersionResource{Group: res.Group, Version: res.Version, Resource: res.Name}
+		constraints, err := dc.List(gvr, "")
+		if err != nil {
+			continue
+		}
+		for _, c := range constraints {
+			violations, _, _ := unstructured.NestedSlice(c.Object, "status", "violations")
+			if len(violations) == 0 {
+				continue
+			}
+			action, _, _ := unstructured.NestedString(c.Object, "spec", "enforcementAction")
+			if action == "" {
+				action = "deny"
+			}
+			group := &PolicyViolationGroup{
+				Engine:     PolicyEngineGatekeeper,
+				PolicyKind: res.Kind,
+				Policy:     c.GetName(),
+				Action:     action,
+				Enforced:   action == "deny",
+			}
+			for _, v := range violations {
+				m, ok := v.(map[string]any)
+				if !ok {
+					continue
+				}
+				group.Violations = append(group.Violations, PolicyViolation{
+					Kind:      stringField(m, "kind"),
+					Namespace: stringField(m, "namespace"),
+					Name:      stringField(m, "name"),
+					Message:   stringField(m, "message"),
+				})
+			}
+			groups[PolicyEngineGatekeeper+"/"+res.Kind+"/"+c.GetName()] = group
+		}
+	}
+	return found
+}
+
+// collectKyvernoViolations reads failing results from PolicyReports and
+// ClusterPolicyReports. Returns false if no report API is installed.
+func collectKyvernoViolations(dc *DynamicResourceCache, discovery *ResourceDiscovery, resources []APIResource, groups map[string]*PolicyViolationGroup) bool {
+	// Only use one report API: newer Kyverno versions write openreports.io and stop updating wgpolicyk8s.io
+	var reportGVRs []schema.GroupVersionResource
+	for _, group := range policyReportGroups {
+		for _, res := range resources {
+			if res.Group != group || !slices.Contains(res.Verbs, "watch") {
+				continue
+			}
+			switch res.Kind {
+			case "PolicyReport", "ClusterPolicyReport", "Report", "ClusterReport":
+				reportGVRs = append(reportGVRs, schema.GroupVersionResource{Group: res.Group, Version: res.Version, Resource: res.Name})
+			}
+		}
+		if len(reportGVRs) > 0 {
+			break
+		}
+	}
+	if len(reportGVRs) == 0 {
+		return false
+	}
+
+	actions := kyvernoPolicyActions(dc, discovery)
+	for _, gvr := range reportGVRs {
+		reports, err := dc.List(gvr, "")
+		if err != nil {
+			continue
+		}
+		for _, report := range reports {
+			addKyvernoReportViolations(report.Object, actions, groups)
+		}
+	}
+	return true
+}
+
+// kyvernoPolicyActions maps policy keys (name or namespace/name) to validationFailureAction
+func kyvernoPolicyActions(dc *DynamicResourceCache, discovery *ResourceDiscovery) map[string]string {
+	actions := map[string]string{}
+	for _, kind := range []string{"ClusterPolicy", "Policy"} {
+		gvr, ok := discovery.GetGVRWithGroup(kind, "kyverno.io")
+		if !ok {
+			continue
+		}
+		policies, err := dc.List(gvr, "")
+		if err != nil {
+			continue
+		}
+		for _, p := range policies {
+			action, _, _ := unstructured.NestedString(p.Object, "spec", "validationFailureAction")
+			key := p.GetName()
+			if p.GetNamespace() != "" {
+				key = p.GetNamespace() + "/" + p.GetName()
+			}
+			actions[key] = action
+		}
+	}
+	return actions
+}
+
+// addKyvernoReportViolations adds a report's failing results to groups
+func addKyvernoReportViolations(report map[string]any, actions map[string]string, groups map[string]*PolicyViolationGroup) {
+	// Per-resource reports (Kyverno 1.10+) put the subject in scope instead of each result
+	var scope map[string]any
+	if s, ok := report["scope"].(map[string]any); ok {
+		scope = s
+	}
+
+	results, _, _ := unstructured.NestedSlice(report, "results")
+	for _, r := range results {
+		result, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		outcome := stringField(result, "result")
+		if outcome != "fail" && outcome != "warn" && outcome != "error" {
+			continue
+		}
+		policy := stringField(result, "policy")
+		if policy == "" {
+			continue
+		}
+
+		key := PolicyEngineKyverno + "/" + policy
+		group, ok := groups[key]
+		if !ok {
+			policyKind := "ClusterPolicy"
+			if strings.Contains(policy, "/") {
+				policyKind = "Policy"
+			}
+			action := actions[policy]
+			group = &PolicyViolationGroup{
+				Engine:     PolicyEngineKyverno,
+				PolicyKind: policyKind,
+				Policy:     policy,
+				Action:     action,
+				Enforced:   strings.EqualFold(action, "Enforce"),
+			}
+			groups[key] = group
+		}
+
+		subjects := []map[string]any{}
+		if rs, ok := result["resources"].([]any); ok {
+			for _, res := range rs {
+				if m, ok := res.(map[string]any); ok {
+					subjects = append(subjects, m)
+				}
+			}
+		}
+		if len(subjects) == 0 && scope != nil {
+			subjects = append(subjects, scope)
+		}
+		for _, subject := range subjects {
+			group.Violations = append(group.Violations, PolicyViolation{
+				Kind:      stringField(subject, "kind"),
+				Namespace: stringField(subject, "namespace"),
+				Name:      stringField(subject, "name"),
+				Rule:      stringField(result, "rule"),
+				Message:   stringField(result, "message"),
+				Result:    outcome,
+				Severity:  stringField(result, "severity"),
+			})
+		}
+	}
+}
+
+// summarizePolicyViolations filters by namespace, counts, caps, and sorts the groups
+func summarizePolicyViolations(groups map[string]*PolicyViolationGroup, engines, namespaces []string) *PolicyViolationsSummary {
+	summary := &PolicyViolationsSummary{
+		Engines:     engines,
+		ByPolicy:    []PolicyViolationGroup{},
+		ByNamespace: []NamespaceViolationCount{},
+	}
+	if summary.Engines == nil {
+		summary.Engines = []string{}
+	}
+
+	nsCounts := map[string]int{}
+	for _, group := range groups {
+		filtered := group.Violations[:0]
+		for _, v := range group.Violations {
+			if len(namespaces) > 0 && !slices.Contains(namespaces, v.Namespace) {
+				continue
+			}
+			filtered = append(filtered, v)
+		}
+		if len(filtered) == 0 {
+			continue
+		}
+
+		sort.SliceStable(filtered, func(i, j int) bool {
+			if filtered[i].Namespace != filtered[j].Namespace {
+				return filtered[i].Namespace < filtered[j].Namespace
+			}
+			return filtered[i].Name < filtered[j].Name
+		})
+		group.Total = len(filtered)
+		group.Namespaces = map[string]int{}
+		for _, v := range filtered {
+			group.Namespaces[v.Namespace]++
+			nsCounts[v.Namespace]++
+		}
+		if len(filtered) > maxViolationsPerPolicy {
+			filtered = filtered[:maxViolationsPerPolicy]
+		}
+		group.Violations = filtered
+		summary.Total += group.Total
+		summary.ByPolicy = append(summary.ByPolicy, *group)
+	}
+
+	sort.Slice(summary.ByPolicy, func(i, j int) bool {
+		a, b := summary.ByPolicy[i], summary.ByPolicy[j]
+		if a.Enforced != b.Enforced {
+			return a.Enforced
+		}
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.Policy < b.Policy
+	})
+
+	for ns, count := range nsCounts {
+		summary.ByNamespace = append(summary.ByNamespace, NamespaceViolationCount{Namespace: ns, Count: count})
+	}
+	sort.Slice(summary.ByNamespace, func(i, j int) bool {
+		if summary.ByNamespace[i].Count != summary.ByNamespace[j].Count {
+			return summary.ByNamespace[i].Count > summary.ByNamespace[j].Count
+		}
+		return summary.ByNamespace[i].Namespace < summary.ByNamespace[j].Namespace
+	})
+	return summary
+}
+
+func stringField(m map[string]any, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
@@ -0,0 +1,44 @@
+package k8s
+
+import "testing"
+
+func TestAddKyvernoReportViolations(t *testing.T) {
+	groups := map[string]*PolicyViolationGroup{}
+	actions := map[string]string{"require-labels": "Enforce"}
+
+	// Per-resource report: subject in scope, results without resources
+	addKyvernoReportViolations(map[string]any{
+		"scope": map[string]any{"kind": "Deployment", "namespace": "shop", "name": "api"},
+		"results": []any{
+			map[string]any{"policy": "require-labels", "rule": "check-team", "result": "fail", "message": "label team is required"},
+			map[string]any{"policy": "require-labels", "rule": "check-owner", "result": "pass"},
+		},
+	}, actions, groups)
+	// Legacy namespace report: subjects listed per result
+	addKyvernoReportViolations(map[string]any{
+		"results": []any{
+			map[string]any{"policy": "shop/no-latest", "rule": "tag", "result": "warn", "message": "avoid latest",
+				"resources": []any{map[string]any{"kind": "Pod", "namespace": "shop", "name": "web-1"}}},
+		},
+	}, actions, groups)
+
+	summary := summarizePolicyViolations(groups, []string{PolicyEngineKyverno}, nil)
+	if summary.Total != 2 || len(summary.ByPolicy) != 2 {
+		t.Fatalf("expected 2 violations in 2 policies, got %+v", summary)
+	}
+	first := summary.ByPolicy[0]
+	if first.Policy != "require-labels" || !first.Enforced || first.Violations[0].Name != "api" {
+		t.Errorf("enforced policy should sort first with scoped subject, got %+v", first)
+	}
+	if second := summary.ByPolicy[1]; second.PolicyKind != "Policy" || second.Violations[0].Result != "warn" {
+		t.Errorf("unexpected namespaced policy group: %+v", second)
+	}
+	if len(summary.ByNamespace) != 1 || summary.ByNamespace[0].Count != 2 {
+		t.Errorf("unexpected namespace counts: %+v", summary.ByNamespace)
+	}
+
+	filtered := summarizePolicyViolations(map[string]*PolicyViolationGroup{}, nil, []string{"other"})
+	if filtered.Total != 0 || filtered.ByPolicy == nil || filtered.Engines == nil {
+		t.Errorf("empty summary should have non-nil slices, got %+v", filtered)
+	}
+}
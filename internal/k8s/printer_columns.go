@@ -0,0 +1,202 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// PrinterColumn is a CRD additionalPrinterColumns entry, the columns
+// `kubectl get` shows for custom resources
+type PrinterColumn struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"` // integer, number, string, boolean or date
+	Format      string `json:"format,omitempty"`
+	Description string `json:"description,omitempty"`
+	JSONPath    string `json:"jsonPath"`
+	Priority    int64  `json:"priority,omitempty"` // > 0 is only shown by `kubectl get -o wide`
+}
+
+// printerColumnStore holds printer columns per group/resource and version,
+// kept current by the CRD lifecycle watcher
+type printerColumnStore struct {
+	mu      sync.RWMutex
+	columns map[groupResource]map[string][]PrinterColumn
+}
+
+var printerColumns = &printerColumnStore{columns: make(map[groupResource]map[string][]PrinterColumn)}
+
+func (s *printerColumnStore) set(crd *crdInfo) {
+	byVersion := make(map[string][]PrinterColumn, len(crd.Versions))
+	for _, v := range crd.Versions {
+		if len(v.Columns) > 0 {
+			byVersion[v.Name] = v.Columns
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.columns[groupResource{crd.Group, crd.Plural}] = byVersion
+}
+
+func (s *printerColumnStore) remove(crd *crdInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.columns, groupResource{crd.Group, crd.Plural})
+}
+
+func (s *printerColumnStore) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.columns = make(map[groupResource]map[string][]PrinterColumn)
+}
+
+// GetPrinterColumns returns the additionalPrinterColumns a CRD declares for
+// the given version, or nil for built-in resources and CRDs without any
+func GetPrinterColumns(gvr schema.GroupVersionResource) []PrinterColumn {
+	printerColumns.mu.RLock()
+	defer printerColumns.mu.RUnlock()
+	return printerColumns.columns[groupResource{gvr.Group, gvr.Resource}][gvr.Version]
+}
+
+func parsePrinterColumns(version map[string]any) []PrinterColumn {
+	raw, _, _ := unstructured.NestedSlice(version, "additionalPrinterColumns")
+	columns := make([]PrinterColumn, 0, len(raw))
+	for _, c := range raw {
+		m, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		col := PrinterColumn{}
+		col.Name, _, _ = unstructured.NestedString(m, "name")
+		col.Type, _, _ = unstructured.NestedString(m, "type")
+		col.Format, _, _ = unstructured.NestedString(m, "format")
+		col.Description, _, _ = unstructured.NestedString(m, "description")
+		col.JSONPath, _, _ = unstructured.NestedString(m, "jsonPath")
+		col.Priority, _, _ = unstructured.NestedInt64(m, "priority")
+		if col.Name != "" && col.JSONPath != "" {
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
+
+// ResourceSummaryRow is one resource in summary list mode
+type ResourceSummaryRow struct {
+	Name      string     `json:"name"`
+	Namespace string     `json:"namespace,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	Cells     []any      `json:"cells"` // One value per column; nil when the path is missing
+	ManagedBy *ManagedBy `json:"managedBy,omitempty"`
+}
+
+// ResourceSummaryList is the summary list mode response: the kubectl columns
+// for a kind plus their values computed for each resource
+type ResourceSummaryList struct {
+	Columns []PrinterColumn      `json:"columns"`
+	Rows    []ResourceSummaryRow `json:"rows"`
+}
+
+// SummarizeDynamic evaluates a kind's printer columns for each item. Kinds
+// without printer columns get rows with no cells.
+func SummarizeDynamic(kind, group string, items []*unstructured.Unstructured) *ResourceSummaryList {
+	var columns []PrinterColumn
+	if discovery := GetResourceDiscovery(); discovery != nil {
+		var gvr schema.GroupVersionResource
+		var ok bool
+		if group != "" {
+			gvr, ok = discovery.GetGVRWithGroup(kind, group)
+		} else {
+			gvr, ok = discovery.GetGVR(kind)
+		}
+		if ok {
+			columns = GetPrinterColumns(gvr)
+		}
+	}
+	list := summarizeWithColumns(columns, items)
+	cache := GetResourceCache()
+	for i, item := range items {
+		managedBy := cache.GetManagedBy(item)
+		list.Rows[i].ManagedBy = &managedBy
+	}
+	return list
+}
+
+func summarizeWithColumns(columns []PrinterColumn, items []*unstructured.Unstructured) *ResourceSummaryList {
+	parsers := make([]*jsonpath.JSONPath, len(columns))
+	for i, col := range columns {
+		parser := jsonpath.New(col.Name).AllowMissingKeys(true)
+		if err := parser.Parse(relaxedJSONPath(col.JSONPath)); err == nil {
+			parsers[i] = parser
+		}
+	}
+
+	list := &ResourceSummaryList{Columns: columns, Rows: make([]ResourceSummaryRow, 0, len(items))}
+	if list.Columns == nil {
+		list.Columns = []PrinterColumn{}
+	}
+	for _, item := range items {
+		row := ResourceSummaryRow{
+			Name:      item.GetName(),
+			Namespace: item.GetNamespace(),
+			CreatedAt: item.GetCreationTimestamp().Time,
+			Cells:     make([]any, len(columns)),
+		}
+		for i, parser := range parsers {
+			if parser != nil {
+				row.Cells[i] = printerColumnValue(parser, item.Object)
+			}
+		}
+		list.Rows = append(list.Rows, row)
+	}
+	return list
+}
+
+// relaxedJSONPath wraps a printer column path in braces the way kubectl does,
+// so ".status.phase" and "{.status.phase}" are both accepted
+func relaxedJSONPath(path string) string {
+	path = strings.TrimSpace(path)
+	if strings.HasPrefix(path, "{") {
+		return path
+	}
+	if !strings.HasPrefix(path, ".") {
+		path = "." + path
+	}
+	return "{" + path + "}"
+}
+
+// printerColumnValue returns a single match as-is, so numbers and dates keep
+// their type, and joins multiple matches with commas like kubectl
+func printerColumnValue(parser *jsonpath.JSONPath, obj map[string]any) any {
+	results, err := parser.FindResults(obj)
+	if err != nil || len(results) == 0 {
+		return nil
+	}
+	var values []any
+	for _, r := range results {
+		for _, v := range r {
+			if v.IsValid() && v.CanInterface() {
+				values = append(values, v.Interface())
+			}
+		}
+	}
+	switch len(values) {
+	case 0:
+		return nil
+	case 1:
+		return values[0]
+	}
+	var buf bytes.Buffer
+	for i, v := range values {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprint(&buf, v)
+	}
+	return buf.String()
+}
@@ -0,0 +1,71 @@
+package k8s
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestPrinterColumnsFromCRD(t *testing.T) {
+	crd := &unstructured.Unstructured{Object: map[string]any{
+		"spec": map[string]any{
+			"group": "cert-manager.io",
+			"names": map[string]any{"kind": "Certificate", "plural": "certificates"},
+			"versions": []any{map[string]any{
+				"name": "v1", "served": true, "storage": true,
+				"additionalPrinterColumns": []any{
+					map[string]any{"name": "Ready", "type": "string", "jsonPath": `.status.conditions[?(@.type=="Ready")].status`},
+					map[string]any{"name": "Secret", "type": "string", "jsonPath": ".spec.secretName"},
+					map[string]any{"name": "Issuer", "type": "string", "jsonPath": ".spec.issuerRef.name", "priority": int64(1)},
+					map[string]any{"name": "Renewals", "type": "integer", "jsonPath": "status.revision"},
+					map[string]any{"name": "DNS", "type": "string", "jsonPath": ".spec.dnsNames[*]"},
+				},
+			}},
+		},
+	}}
+	info, ok := parseCRD(crd)
+	if !ok {
+		t.Fatal("expected the CRD to parse")
+	}
+	columns := info.Versions[0].Columns
+	if len(columns) != 5 || columns[2].Priority != 1 {
+		t.Fatalf("unexpected columns %+v", columns)
+	}
+
+	cert := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "web", "namespace": "shop"},
+		"spec": map[string]any{
+			"secretName": "web-tls",
+			"issuerRef":  map[string]any{"name": "letsencrypt"},
+			"dnsNames":   []any{"shop.example.com", "www.shop.example.com"},
+		},
+		"status": map[string]any{
+			"revision":   int64(3),
+			"conditions": []any{map[string]any{"type": "Ready", "status": "True"}},
+		},
+	}}
+	pending := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": "api", "namespace": "shop"},
+		"spec":     map[string]any{"secretName": "api-tls"},
+	}}
+
+	list := summarizeWithColumns(columns, []*unstructured.Unstructured{cert, pending})
+	if want := []any{"True", "web-tls", "letsencrypt", int64(3), "shop.example.com,www.shop.example.com"}; !reflect.DeepEqual(list.Rows[0].Cells, want) {
+		t.Errorf("expected cells %v, got %v", want, list.Rows[0].Cells)
+	}
+	// Missing fields yield empty cells rather than errors
+	if want := []any{nil, "api-tls", nil, nil, nil}; !reflect.DeepEqual(list.Rows[1].Cells, want) {
+		t.Errorf("expected cells %v, got %v", want, list.Rows[1].Cells)
+	}
+
+	printerColumns.set(info)
+	defer printerColumns.reset()
+	if got := GetPrinterColumns(schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}); len(got) != 5 {
+		t.Errorf("expected the stored columns, got %+v", got)
+	}
+	if got := GetPrinterColumns(schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1alpha2", Resource: "certificates"}); got != nil {
+		t.Errorf("expected no columns for an unknown version, got %+v", got)
+	}
+}
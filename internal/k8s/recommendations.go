@@ -0,0 +1,295 @@
+package k8s
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// recommendationMinSamples is the fewest data points a container needs
+	// before a recommendation is made (5 minutes of raw samples)
+	recommendationMinSamples = 10
+	// recommendationMinChange is the relative difference from the current
+	// request below which a recommendation isn't worth acting on
+	recommendationMinChange = 0.1
+
+	cpuHeadroom       = 1.15
+	memoryHeadroom    = 1.2
+	memoryLimitFactor = 1.5
+	minCPURequest     = 10               // Millicores
+	minMemoryRequest  = 32 * 1024 * 1024 // Bytes
+	cpuRoundTo        = 5                // Millicores
+	memoryRoundTo     = 1024 * 1024      // Bytes
+	bytesPerGiB       = 1024 * 1024 * 1024
+)
+
+// ContainerRecommendation compares a container's observed usage with its
+// requests. CPU values are millicores, memory values are bytes; a zero
+// current value means unset.
+type ContainerRecommendation struct {
+	Container string `json:"container"`
+	Samples   int    `json:"samples"`
+
+	CPUP95    int64 `json:"cpuP95"`
+	MemoryP99 int64 `json:"memoryP99"`
+	MemoryMax int64 `json:"memoryMax"`
+
+	CurrentCPURequest    int64 `json:"currentCpuRequest"`
+	CurrentCPULimit      int64 `json:"currentCpuLimit"`
+	CurrentMemoryRequest int64 `json:"currentMemoryRequest"`
+	CurrentMemoryLimit   int64 `json:"currentMemoryLimit"`
+
+	RecommendedCPURequest    int64 `json:"recommendedCpuRequest"`
+	RecommendedCPULimit      int64 `json:"recommendedCpuLimit,omitempty"` // Only when a CPU limit is already set
+	RecommendedMemoryRequest int64 `json:"recommendedMemoryRequest"`
+	RecommendedMemoryLimit   int64 `json:"recommendedMemoryLimit"`
+}
+
+// WorkloadRecommendation groups container recommendations for a workload.
+// Savings are per-replica differences multiplied by the observed pod count;
+// negative values mean the workload is under-provisioned.
+type WorkloadRecommendation struct {
+	Kind       string                    `json:"kind"`
+	Namespace  string                    `json:"namespace"`
+	Name       string                    `json:"name"`
+	Pods       int                       `json:"pods"`
+	Containers []ContainerRecommendation `json:"containers"`
+
+	CPUSavingsCores  float64 `json:"cpuSavingsCores"`
+	MemorySavingsGiB float64 `json:"memorySavingsGiB"`
+}
+
+// RecommendationsReport lists right-sizing recommendations, largest savings first
+type RecommendationsReport struct {
+	Window     string                   `json:"window"`
+	HasMetrics bool                     `json:"hasMetrics"`
+	Workloads  []WorkloadRecommendation `json:"workloads"`
+
+	// Totals only count reductions, so under-provisioned workloads don't offset savings
+	TotalCPUSavingsCores  float64 `json:"totalCpuSavingsCores"`
+	TotalMemorySavingsGiB float64 `json:"totalMemorySavingsGiB"`
+}
+
+// workloadKey identifies the workload a pod belongs to
+type workloadKey struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// GetRecommendations computes right-sizing recommendations from metrics
+// history collected over window for running pods in the given namespaces
+func GetRecommendations(namespaces []string, window time.Duration) *RecommendationsReport {
+	report := &RecommendationsReport{Window: window.String(), Workloads: []WorkloadRecommendation{}}
+	cache := GetResourceCache()
+	store := GetMetricsHistory()
+	if cache == nil || store == nil || cache.Pods() == nil {
+		return report
+	}
+	pods, err := cache.Pods().List(labels.Everything())
+	if err != nil {
+		return report
+	}
+
+	cutoff := time.Now().Add(-window)
+	type workloadPods struct {
+		pods    []*corev1.Pod
+		samples []map[string][]MetricsDataPoint
+	}
+	byWorkload := make(map[workloadKey]*workloadPods)
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		if len(namespaces) > 0 && !slices.Contains(namespaces, pod.Namespace) {
+			continue
+		}
+		samples := store.containerSamplesSince(pod.Namespace, pod.Name, cutoff)
+		if len(samples) == 0 {
+			continue
+		}
+		report.HasMetrics = true
+		key := podWorkload(pod)
+		wp, ok := byWorkload[key]
+		if !ok {
+			wp = &workloadPods{}
+			byWorkload[key] = wp
+		}
+		wp.pods = append(wp.pods, pod)
+		wp.samples = append(wp.samples, samples)
+	}
+
+	for key, wp := range byWorkload {
+		if rec := recommendWorkload(key, wp.pods, wp.samples); rec != nil {
+			report.Workloads = append(report.Workloads, *rec)
+			report.TotalCPUSavingsCores += max(rec.CPUSavingsCores, 0)
+			report.TotalMemorySavingsGiB += max(rec.MemorySavingsGiB, 0)
+		}
+	}
+	sort.Slice(report.Workloads, func(i, j int) bool {
+		a, b := report.Workloads[i], report.Workloads[j]
+		if a.CPUSavingsCores != b.CPUSavingsCores {
+			return a.CPUSavingsCores > b.CPUSavingsCores
+		}
+		if a.MemorySavingsGiB != b.MemorySavingsGiB {
+			return a.MemorySavingsGiB > b.MemorySavingsGiB
+		}
+		return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+	})
+	return report
+}
+
+// containerSamplesSince returns a pod's data points since cutoff, keyed by container
+func (s *MetricsHistoryStore) containerSamplesSince(namespace, name string, cutoff time.Time) map[string][]MetricsDataPoint {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	podBuf, ok := s.podMetrics[namespace+"/"+name]
+	if !ok {
+		return nil
+	}
+	samples := make(map[string][]MetricsDataPoint, len(podBuf.containers))
+	for container, series := range podBuf.containers {
+		if points := series.Since(cutoff); len(points) > 0 {
+			samples[container] = points
+		}
+	}
+	return samples
+}
+
+// podWorkload resolves the workload that owns a pod. ReplicaSets created by a
+// Deployment are attributed to the Deployment via the pod-template-hash label.
+func podWorkload(pod *corev1.Pod) workloadKey {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller != nil && *ref.Controller {
+			return workloadFromOwner(pod.Namespace, ref.Kind, ref.Name, pod.Labels["pod-template-hash"])
+		}
+	}
+	return workloadKey{Kind: "Pod", Namespace: pod.Namespace, Name: pod.Name}
+}
+
+// PodWorkload returns the kind and name of the workload that owns a pod,
+// or the pod itself if it has no controller
+func PodWorkload(pod *corev1.Pod) (kind, name string) {
+	key := podWorkload(pod)
+	return key.Kind, key.Name
+}
+
+// workloadFromOwner maps a pod's controller to its workload, resolving
+// Deployment-managed ReplicaSets via the pod-template-hash suffix
+func workloadFromOwner(namespace, kind, name, templateHash string) workloadKey {
+	if kind == "ReplicaSet" && templateHash != "" && strings.HasSuffix(name, "-"+templateHash) {
+		return workloadKey{Kind: "Deployment", Namespace: namespace, Name: strings.TrimSuffix(name, "-"+templateHash)}
+	}
+	return workloadKey{Kind: kind, Namespace: namespace, Name: name}
+}
+
+// recommendWorkload pools samples per container across a workload's pods and
+// recommends requests from the pooled percentiles. Returns nil when no
+// container has enough data or needs a meaningful change.
+func recommendWorkload(key workloadKey, pods []*corev1.Pod, samples []map[string][]MetricsDataPoint) *WorkloadRecommendation {
+	pooled := make(map[string][]MetricsDataPoint)
+	for _, s := range samples {
+		for container, points := range s {
+			pooled[container] = append(pooled[container], points...)
+		}
+	}
+
+	rec := &WorkloadRecommendation{Kind: key.Kind, Namespace: key.Namespace, Name: key.Name, Pods: len(pods)}
+	var cpuDelta, memDelta int64
+	for _, c := range pods[0].Spec.Containers {
+		points := pooled[c.Name]
+		if len(points) < recommendationMinSamples {
+			continue
+		}
+		cr := recommendContainer(c, points)
+		if !worthChanging(cr.CurrentCPURequest, cr.RecommendedCPURequest) &&
+			!worthChanging(cr.CurrentMemoryRequest, cr.RecommendedMemoryRequest) {
+			continue
+		}
+		rec.Containers = append(rec.Containers, cr)
+		// An unset request counts as under-provisioned by the full recommendation
+		cpuDelta += cr.CurrentCPURequest - cr.RecommendedCPURequest
+		memDelta += cr.CurrentMemoryRequest - cr.RecommendedMemoryRequest
+	}
+	if len(rec.Containers) == 0 {
+		return nil
+	}
+	rec.CPUSavingsCores = float64(cpuDelta*int64(len(pods))) / 1000
+	rec.MemorySavingsGiB = float64(memDelta*int64(len(pods))) / bytesPerGiB
+	return rec
+}
+
+// recommendContainer sizes CPU requests to the p95 and memory to the p99 of
+// observed usage plus headroom. Memory limits leave room above the observed
+// peak since exceeding them gets the container OOM-killed. Memory uses each
+// rollup's peak rather than its average, which would hide short spikes.
+func recommendContainer(c corev1.Container, points []MetricsDataPoint) ContainerRecommendation {
+	cpu := make([]int64, len(points))
+	mem := make([]int64, len(points))
+	for i, p := range points {
+		cpu[i] = p.CPU / 1000000 // nanocores to millicores
+		mem[i] = p.peakMemory()
+	}
+
+	cr := ContainerRecommendation{
+		Container: c.Name,
+		Samples:   len(points),
+		CPUP95:    Percentile(cpu, 95),
+		MemoryP99: Percentile(mem, 99),
+		MemoryMax: slices.Max(mem),
+	}
+	if q, ok := c.Resources.Requests[corev1.ResourceCPU]; ok {
+		cr.CurrentCPURequest = q.MilliValue()
+	}
+	if q, ok := c.Resources.Limits[corev1.ResourceCPU]; ok {
+		cr.CurrentCPULimit = q.MilliValue()
+	}
+	if q, ok := c.Resources.Requests[corev1.ResourceMemory]; ok {
+		cr.CurrentMemoryRequest = q.Value()
+	}
+	if q, ok := c.Resources.Limits[corev1.ResourceMemory]; ok {
+		cr.CurrentMemoryLimit = q.Value()
+	}
+
+	cr.RecommendedCPURequest = roundUp(max(int64(float64(cr.CPUP95)*cpuHeadroom), minCPURequest), cpuRoundTo)
+	cr.RecommendedMemoryRequest = roundUp(max(int64(float64(cr.MemoryP99)*memoryHeadroom), minMemoryRequest), memoryRoundTo)
+	cr.RecommendedMemoryLimit = roundUp(max(int64(float64(cr.MemoryMax)*memoryLimitFactor), cr.RecommendedMemoryRequest), memoryRoundTo)
+	if cr.CurrentCPULimit > 0 {
+		cr.RecommendedCPULimit = max(cr.CurrentCPULimit, 2*cr.RecommendedCPURequest)
+	}
+	return cr
+}
+
+// worthChanging reports whether recommended differs from current by enough to act on
+func worthChanging(current, recommended int64) bool {
+	if current == 0 {
+		return true
+	}
+	diff := float64(current - recommended)
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff/float64(current) >= recommendationMinChange
+}
+
+// Percentile returns the nearest-rank p-th percentile of values, leaving
+// values unsorted
+func Percentile[T int64 | float64](values []T, p int) T {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+	return sorted[max(rank-1, 0)]
+}
+
+func roundUp(v, to int64) int64 {
+	return (v + to - 1) / to * to
+}
@@ -0,0 +1,123 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodWorkload(t *testing.T) {
+	controller := true
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "shop", Name: "api-7d9f8-abcde",
+		Labels:          map[string]string{"pod-template-hash": "7d9f8"},
+		OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "api-7d9f8", Controller: &controller}},
+	}}
+	if got := podWorkload(pod); got != (workloadKey{Kind: "Deployment", Namespace: "shop", Name: "api"}) {
+		t.Errorf("ReplicaSet pod should resolve to its Deployment, got %+v", got)
+	}
+
+	pod.OwnerReferences = []metav1.OwnerReference{{Kind: "StatefulSet", Name: "db", Controller: &controller}}
+	if got := podWorkload(pod); got.Kind != "StatefulSet" || got.Name != "db" {
+		t.Errorf("unexpected StatefulSet owner: %+v", got)
+	}
+
+	pod.OwnerReferences = nil
+	if got := podWorkload(pod); got.Kind != "Pod" || got.Name != "api-7d9f8-abcde" {
+		t.Errorf("bare pod should be its own workload, got %+v", got)
+	}
+}
+
+func TestRecommendContainer(t *testing.T) {
+	c := corev1.Container{Name: "app", Resources: corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("2Gi")},
+	}}
+	// 100 samples: CPU 1m..100m, memory 101Mi..200Mi
+	var points []MetricsDataPoint
+	for i := 1; i <= 100; i++ {
+		points = append(points, MetricsDataPoint{Timestamp: time.Now(), CPU: int64(i) * 1000000, Memory: int64(100+i) << 20})
+	}
+
+	cr := recommendContainer(c, points)
+	if cr.CPUP95 != 95 || cr.MemoryP99 != 199<<20 || cr.MemoryMax != 200<<20 {
+		t.Errorf("unexpected percentiles: %+v", cr)
+	}
+	if cr.CurrentCPURequest != 1000 || cr.CurrentCPULimit != 2000 || cr.CurrentMemoryRequest != 1<<30 {
+		t.Errorf("unexpected current values: %+v", cr)
+	}
+	// 95m * 1.15 = 109.25m rounded up to 110m; 199Mi * 1.2 rounded up to 239Mi
+	if cr.RecommendedCPURequest != 110 || cr.RecommendedMemoryRequest != 239<<20 {
+		t.Errorf("unexpected recommended requests: cpu=%d mem=%d", cr.RecommendedCPURequest, cr.RecommendedMemoryRequest)
+	}
+	if cr.RecommendedMemoryLimit != 300<<20 || cr.RecommendedCPULimit != 2000 {
+		t.Errorf("unexpected recommended limits: cpu=%d mem=%d", cr.RecommendedCPULimit, cr.RecommendedMemoryLimit)
+	}
+
+	// Rollups are sized by their peak, not the average that hides spikes
+	rollups := []MetricsDataPoint{{CPU: 1000000, Memory: 100 << 20, MemoryPeak: 400 << 20}}
+	if spiky := recommendContainer(c, rollups); spiky.MemoryP99 != 400<<20 || spiky.MemoryMax != 400<<20 {
+		t.Errorf("expected rollup peaks to be used, got p99=%d max=%d", spiky.MemoryP99, spiky.MemoryMax)
+	}
+
+	// Idle containers still get the minimum request
+	idle := recommendContainer(corev1.Container{Name: "idle"}, []MetricsDataPoint{{CPU: 0, Memory: 0}})
+	if idle.RecommendedCPURequest != minCPURequest || idle.RecommendedMemoryRequest != minMemoryRequest || idle.RecommendedCPULimit != 0 {
+		t.Errorf("unexpected idle recommendation: %+v", idle)
+	}
+}
+
+func TestRecommendWorkload(t *testing.T) {
+	pod := func(cpu string) *corev1.Pod {
+		return &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse("256Mi")},
+		}}}}}
+	}
+	samples := func(cpuMillis, memMiB int64, n int) map[string][]MetricsDataPoint {
+		points := make([]MetricsDataPoint, n)
+		for i := range points {
+			points[i] = MetricsDataPoint{CPU: cpuMillis * 1000000, Memory: memMiB << 20}
+		}
+		return map[string][]MetricsDataPoint{"app": points}
+	}
+	key := workloadKey{Kind: "Deployment", Namespace: "shop", Name: "api"}
+
+	// Two replicas requesting 1 core but using 100m: 885m saved each
+	rec := recommendWorkload(key, []*corev1.Pod{pod("1"), pod("1")}, []map[string][]MetricsDataPoint{samples(100, 250, 10), samples(100, 250, 10)})
+	if rec == nil || len(rec.Containers) != 1 || rec.Pods != 2 {
+		t.Fatalf("expected a recommendation, got %+v", rec)
+	}
+	if rec.CPUSavingsCores != 1.77 {
+		t.Errorf("expected 1.77 cores saved, got %v", rec.CPUSavingsCores)
+	}
+	if rec.MemorySavingsGiB >= 0 {
+		t.Errorf("256Mi is below 250Mi * 1.2 headroom, expected negative savings, got %v", rec.MemorySavingsGiB)
+	}
+
+	// Well-sized workloads and ones without enough data get no recommendation
+	if rec := recommendWorkload(key, []*corev1.Pod{pod("115m")}, []map[string][]MetricsDataPoint{samples(100, 213, 10)}); rec != nil {
+		t.Errorf("expected no recommendation for a right-sized workload, got %+v", rec)
+	}
+	if rec := recommendWorkload(key, []*corev1.Pod{pod("1")}, []map[string][]MetricsDataPoint{samples(100, 200, 5)}); rec != nil {
+		t.Errorf("expected no recommendation with too few samples, got %+v", rec)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := []float64{40, 10, 30, 20}
+	if got := Percentile(latencies, 95); got != 40 {
+		t.Errorf("p95 = %v, want 40", got)
+	}
+	if got := Percentile(latencies, 50); got != 20 {
+		t.Errorf("p50 = %v, want 20", got)
+	}
+	if latencies[0] != 40 {
+		t.Error("Percentile shouldn't sort the caller's slice")
+	}
+	if got := Percentile([]int64{}, 99); got != 0 {
+		t.Errorf("empty p99 = %v, want 0", got)
+	}
+}
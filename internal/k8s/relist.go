@@ -0,0 +1,172 @@
+package k8s
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// Informer reflectors already request watch bookmarks, so an idle watch keeps
+// a fresh resourceVersion and a reconnect normally resumes where it left off.
+// When the apiserver has compacted past that version (or the watch fails for
+// another reason) the reflector relists, and the relist is replayed to event
+// handlers: objects already cached come back as updates with an unchanged
+// resourceVersion, and objects the cache lost come back as adds. Neither is a
+// real change, so they're kept out of the timeline and the SSE stream.
+
+// relistWindow is how long after a watch failure adds are checked for being
+// relist replays. A relist completes well within this on large clusters.
+const relistWindow = 2 * time.Minute
+
+// RelistStats describes the relists of one informer kind
+type RelistStats struct {
+	Kind              string    `json:"kind"`
+	Relists           int64     `json:"relists"`
+	Expired           int64     `json:"expired"` // Relists caused by "too old resource version"
+	LastRelist        time.Time `json:"lastRelist"`
+	LastError         string    `json:"lastError,omitempty"`
+	SuppressedAdds    int64     `json:"suppressedAdds"`
+	SuppressedUpdates int64     `json:"suppressedUpdates"`
+}
+
+type relistState struct {
+	stats     RelistStats
+	lastEvent time.Time // Last event delivered while the watch was healthy
+	lostAfter time.Time // lastEvent when the watch failed; older objects were already seen
+}
+
+type relistTracker struct {
+	mu    sync.Mutex
+	kinds map[string]*relistState
+}
+
+var relists = &relistTracker{kinds: make(map[string]*relistState)}
+
+func (t *relistTracker) state(kind string) *relistState {
+	s, ok := t.kinds[kind]
+	if !ok {
+		s = &relistState{stats: RelistStats{Kind: kind}}
+		t.kinds[kind] = s
+	}
+	return s
+}
+
+// observe records that an event for kind was delivered at now
+func (t *relistTracker) observe(kind string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state(kind).lastEvent = now
+}
+
+// watchFailed records a watch failure for kind; the reflector relists next
+func (t *relistTracker) watchFailed(kind string, err error, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.state(kind)
+	s.stats.Relists++
+	if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+		s.stats.Expired++
+	}
+	s.stats.LastRelist = now
+	s.stats.LastError = err.Error()
+	s.lostAfter = s.lastEvent
+}
+
+// relistAdd reports whether an add delivered at now is a relist replay: it
+// arrived shortly after a watch failure for an object created before the
+// last event seen on the healthy watch. Objects created later may really be
+// new (created while the watch was down) and are recorded as usual.
+func (t *relistTracker) relistAdd(kind string, created, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s, ok := t.kinds[kind]
+	if !ok || s.stats.LastRelist.IsZero() || now.Sub(s.stats.LastRelist) > relistWindow {
+		return false
+	}
+	if created.IsZero() || created.After(s.lostAfter) {
+		return false
+	}
+	s.stats.SuppressedAdds++
+	return true
+}
+
+// suppressedUpdate counts an update that didn't change the resourceVersion
+func (t *relistTracker) suppressedUpdate(kind string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state(kind).stats.SuppressedUpdates++
+}
+
+func (t *relistTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.kinds = make(map[string]*relistState)
+}
+
+// GetRelistStats returns relist counters for every kind that has relisted or
+// had relist replays suppressed, sorted by kind
+func GetRelistStats() []RelistStats {
+	relists.mu.Lock()
+	defer relists.mu.Unlock()
+	result := []RelistStats{}
+	for _, s := range relists.kinds {
+		if s.stats.Relists > 0 || s.stats.SuppressedUpdates > 0 {
+			result = append(result, s.stats)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Kind < result[j].Kind })
+	return result
+}
+
+// setRelistHandler tracks watch failures of inf as relists of kind. Must be
+// called before the informer starts.
+func setRelistHandler(inf cache.SharedIndexInformer, kind string) {
+	err := inf.SetWatchErrorHandlerWithContext(func(ctx context.Context, r *cache.Reflector, err error) {
+		cache.DefaultWatchErrorHandler(ctx, r, err)
+		relists.watchFailed(kind, err, time.Now())
+		if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+			log.Printf("[relist] %s watch expired, relisting: %v", kind, err)
+		} else if DebugEvents.Load() {
+			log.Printf("[DEBUG] %s watch failed, relisting: %v", kind, err)
+		}
+	})
+	if err != nil {
+		log.Printf("Warning: failed to set %s watch error handler: %v", kind, err)
+	}
+}
+
+// isRelistReplay reports whether an informer notification is a relist replay
+// rather than a real change, counting it as dropped if so
+func isRelistReplay(kind string, meta metav1.Object, oldObj any, op string) bool {
+	now := time.Now()
+	switch op {
+	case "add":
+		if !relists.relistAdd(kind, meta.GetCreationTimestamp().Time, now) {
+			relists.observe(kind, now)
+			return false
+		}
+		timeline.RecordDrop(kind, meta.GetNamespace(), meta.GetName(), timeline.DropReasonRelist, op)
+		if DebugEvents.Load() {
+			log.Printf("[DEBUG] Relist replay add, skipping: %s/%s/%s", kind, meta.GetNamespace(), meta.GetName())
+		}
+		return true
+	case "update":
+		// Resync is disabled, so an update with the same resourceVersion can only be a relist replay
+		if old, ok := oldObj.(metav1.Object); ok && old.GetResourceVersion() != "" && old.GetResourceVersion() == meta.GetResourceVersion() {
+			relists.suppressedUpdate(kind)
+			// Counted without a drop record: a relist of a large kind would evict every other recent drop
+			timeline.IncrementDropped(timeline.DropReasonRelist)
+			return true
+		}
+	}
+	relists.observe(kind, now)
+	return false
+}
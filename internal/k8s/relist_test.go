@@ -0,0 +1,69 @@
+package k8s
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRelistTrackerAdds(t *testing.T) {
+	tracker := &relistTracker{kinds: make(map[string]*relistState)}
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tracker.observe("Pod", base)
+	if tracker.relistAdd("Pod", base.Add(-time.Hour), base) {
+		t.Error("adds before any relist should not be suppressed")
+	}
+
+	expired := apierrors.NewResourceExpired("too old resource version: 100 (200)")
+	tracker.watchFailed("Pod", expired, base.Add(10*time.Minute))
+	failedAt := base.Add(10 * time.Minute)
+
+	if !tracker.relistAdd("Pod", base.Add(-time.Hour), failedAt.Add(time.Second)) {
+		t.Error("expected add of an object seen before the watch failed to be a relist replay")
+	}
+	if tracker.relistAdd("Pod", base.Add(time.Minute), failedAt.Add(time.Second)) {
+		t.Error("object created while the watch was down should be recorded")
+	}
+	if tracker.relistAdd("Pod", base.Add(-time.Hour), failedAt.Add(relistWindow+time.Second)) {
+		t.Error("adds after the relist window should not be suppressed")
+	}
+	if tracker.relistAdd("Service", base.Add(-time.Hour), failedAt.Add(time.Second)) {
+		t.Error("relists are tracked per kind")
+	}
+
+	tracker.watchFailed("Pod", errors.New("connection refused"), failedAt.Add(time.Hour))
+	s := tracker.kinds["Pod"].stats
+	if s.Relists != 2 || s.Expired != 1 || s.SuppressedAdds != 1 || s.LastError != "connection refused" {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
+
+func TestIsRelistReplayUpdates(t *testing.T) {
+	relists.reset()
+	defer relists.reset()
+
+	old := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "api-1", ResourceVersion: "41"}}
+	same := old.DeepCopy()
+	changed := old.DeepCopy()
+	changed.ResourceVersion = "42"
+
+	if !isRelistReplay("Pod", same, old, "update") {
+		t.Error("expected update with unchanged resourceVersion to be a relist replay")
+	}
+	if isRelistReplay("Pod", changed, old, "update") {
+		t.Error("expected real update to be recorded")
+	}
+	if isRelistReplay("Pod", changed, nil, "delete") {
+		t.Error("deletes are never relist replays")
+	}
+
+	stats := GetRelistStats()
+	if len(stats) != 1 || stats[0].Kind != "Pod" || stats[0].SuppressedUpdates != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
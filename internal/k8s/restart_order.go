@@ -0,0 +1,493 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// defaultRestartTimeout is how long an ordered restart waits for one wave to roll out
+	defaultRestartTimeout = 5 * time.Minute
+	restartPollInterval   = 2 * time.Second
+)
+
+// BulkRestartOptions describes a restart of several workloads. With Ordered,
+// workloads are restarted in waves: dependencies first, and each wave waits
+// until the previous one has rolled out.
+type BulkRestartOptions struct {
+	Items          []BulkTarget  `json:"items"`
+	Ordered        bool          `json:"ordered"`
+	TimeoutSeconds int           `json:"timeoutSeconds,omitempty"` // Per wave (default 300)
+	Calls          []ServiceCall `json:"-"`                        // Observed traffic, see PlanRestarts
+}
+
+// ServiceCall is observed traffic from one workload to another workload or a
+// Service (e.g. from traffic flows)
+type ServiceCall struct {
+	FromNamespace string
+	FromWorkload  string
+	ToNamespace   string
+	ToName        string // Workload or Service name
+}
+
+// RestartDependency records that From calls To, so To is restarted first
+type RestartDependency struct {
+	From BulkTarget `json:"from"`
+	To   BulkTarget `json:"to"`
+	Via  string     `json:"via"` // "traffic" or "env NAME" / "args"
+}
+
+// RestartPlan is the order workloads are restarted in
+type RestartPlan struct {
+	Waves        [][]BulkTarget      `json:"waves"`
+	Dependencies []RestartDependency `json:"dependencies,omitempty"`
+	Notes        []string            `json:"notes,omitempty"`
+}
+
+// RestartProgress is one step of a bulk restart
+type RestartProgress struct {
+	Wave    int        `json:"wave"` // 1-based
+	Target  BulkTarget `json:"target"`
+	Phase   string     `json:"phase"` // restarting, restarted (unordered), waiting, ready, failed
+	Message string     `json:"message,omitempty"`
+}
+
+// restartKind returns the canonical kind of a restartable workload
+func restartKind(kind string) (string, bool) {
+	switch strings.ToLower(kind) {
+	case "deployment", "deployments":
+		return "Deployment", true
+	case "statefulset", "statefulsets":
+		return "StatefulSet", true
+	case "daemonset", "daemonsets":
+		return "DaemonSet", true
+	case "rollout", "rollouts":
+		return "Rollout", true
+	}
+	return "", false
+}
+
+// restartNode is a workload to restart with its pod template (nil for kinds
+// not in the typed cache, like Rollouts)
+type restartNode struct {
+	target   BulkTarget
+	template *corev1.PodTemplateSpec
+}
+
+func (n restartNode) key() string {
+	return n.target.Kind + "/" + n.target.Namespace + "/" + n.target.Name
+}
+
+// PlanRestarts validates the targets and orders them by dependency. A
+// workload depends on another when it calls it in the observed traffic, or
+// when its env or args reference a Service selecting the other's pods.
+func (c *ResourceCache) PlanRestarts(opts BulkRestartOptions) (*RestartPlan, error) {
+	if len(opts.Items) == 0 {
+		return nil, fmt.Errorf("items is required")
+	}
+	if len(opts.Items) > maxBulkTargets {
+		return nil, fmt.Errorf("too many items: %d (max %d)", len(opts.Items), maxBulkTargets)
+	}
+
+	seen := make(map[string]bool)
+	var nodes []restartNode
+	for _, t := range opts.Items {
+		kind, ok := restartKind(t.Kind)
+		if !ok {
+			return nil, fmt.Errorf("%s %s/%s can't be restarted (only Deployments, StatefulSets, DaemonSets, and Rollouts)", t.Kind, t.Namespace, t.Name)
+		}
+		if t.Namespace == "" || t.Name == "" {
+			return nil, fmt.Errorf("each item requires namespace and name")
+		}
+		n := restartNode{target: BulkTarget{Kind: kind, Namespace: t.Namespace, Name: t.Name}}
+		if seen[n.key()] {
+			continue
+		}
+		seen[n.key()] = true
+		n.template = c.podTemplate(kind, t.Namespace, t.Name)
+		nodes = append(nodes, n)
+	}
+
+	if !opts.Ordered {
+		plan := &RestartPlan{Waves: [][]BulkTarget{make([]BulkTarget, 0, len(nodes))}}
+		for _, n := range nodes {
+			plan.Waves[0] = append(plan.Waves[0], n.target)
+		}
+		return plan, nil
+	}
+
+	var services []*corev1.Service
+	if lister := c.Services(); lister != nil {
+		services, _ = lister.List(labels.Everything())
+	}
+	return planRestarts(nodes, services, opts.Calls), nil
+}
+
+func (c *ResourceCache) podTemplate(kind, namespace, name string) *corev1.PodTemplateSpec {
+	switch kind {
+	case "Deployment":
+		if lister := c.Deployments(); lister != nil {
+			if d, err := lister.Deployments(namespace).Get(name); err == nil {
+				return &d.Spec.Template
+			}
+		}
+	case "StatefulSet":
+		if lister := c.StatefulSets(); lister != nil {
+			if ss, err := lister.StatefulSets(namespace).Get(name); err == nil {
+				return &ss.Spec.Template
+			}
+		}
+	case "DaemonSet":
+		if lister := c.DaemonSets(); lister != nil {
+			if ds, err := lister.DaemonSets(namespace).Get(name); err == nil {
+				return &ds.Spec.Template
+			}
+		}
+	}
+	return nil
+}
+
+// planRestarts orders nodes into waves with Kahn's algorithm: each wave only
+// depends on earlier ones. Workloads in a dependency cycle share a final wave.
+func planRestarts(nodes []restartNode, services []*corev1.Service, calls []ServiceCall) *RestartPlan {
+	plan := &RestartPlan{}
+
+	// Workloads selected by each Service (namespace/name)
+	selected := make(map[string][]int)
+	for _, svc := range services {
+		if len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		sel := labels.SelectorFromSet(svc.Spec.Selector)
+		for i, n := range nodes {
+			if n.template != nil && n.target.Namespace == svc.Namespace && sel.Matches(labels.Set(n.template.Labels)) {
+				selected[svc.Namespace+"/"+svc.Name] = append(selected[svc.Namespace+"/"+svc.Name], i)
+			}
+		}
+	}
+
+	dependsOn := make([]map[int]bool, len(nodes))
+	for i := range dependsOn {
+		dependsOn[i] = make(map[int]bool)
+	}
+	addDependency := func(from, to int, via string) {
+		if from == to || dependsOn[from][to] {
+			return
+		}
+		dependsOn[from][to] = true
+		plan.Dependencies = append(plan.Dependencies, RestartDependency{From: nodes[from].target, To: nodes[to].target, Via: via})
+	}
+
+	for i, n := range nodes {
+		if n.template == nil {
+			continue
+		}
+		for _, ref := range serviceReferences(n.template, n.target.Namespace) {
+			for _, j := range selected[ref.service] {
+				addDependency(i, j, ref.via)
+			}
+		}
+	}
+	for _, call := range calls {
+		for i, n := range nodes {
+			if n.target.Namespace != call.FromNamespace || n.target.Name != call.FromWorkload {
+				continue
+			}
+			for j, m := range nodes {
+				if m.target.Namespace == call.ToNamespace && m.target.Name == call.ToName {
+					addDependency(i, j, "traffic")
+				}
+			}
+			for _, j := range selected[call.ToNamespace+"/"+call.ToName] {
+				addDependency(i, j, "traffic")
+			}
+		}
+	}
+
+	done := make([]bool, len(nodes))
+	remaining := len(nodes)
+	for remaining > 0 {
+		var wave []int
+		for i := range nodes {
+			if done[i] {
+				continue
+			}
+			ready := true
+			for j := range dependsOn[i] {
+				if !done[j] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				wave = append(wave, i)
+			}
+		}
+		if len(wave) == 0 {
+			var names []string
+			for i := range nodes {
+				if !done[i] {
+					wave = append(wave, i)
+					names = append(names, nodes[i].target.Namespace+"/"+nodes[i].target.Name)
+				}
+			}
+			plan.Notes = append(plan.Notes, fmt.Sprintf("Dependency cycle among %s: restarted together", strings.Join(names, ", ")))
+		}
+		targets := make([]BulkTarget, 0, len(wave))
+		for _, i := range wave {
+			done[i] = true
+			targets = append(targets, nodes[i].target)
+		}
+		plan.Waves = append(plan.Waves, targets)
+		remaining -= len(wave)
+	}
+	return plan
+}
+
+type serviceReference struct {
+	service string // namespace/name
+	via     string
+}
+
+var dnsNameRe = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+// hostEnvSuffixes mark env vars whose plain values are host names
+var hostEnvSuffixes = []string{"HOST", "HOSTNAME", "ADDR", "ADDRESS", "SERVER", "SERVICE", "ENDPOINT", "URL", "URI"}
+
+// serviceReferences finds Services named in a pod template's env values and
+// args: "svc.ns[.svc[.cluster.local]]" anywhere, and a bare "svc" (same
+// namespace) in URLs, host:port values and *_HOST-style variables
+func serviceReferences(template *corev1.PodTemplateSpec, namespace string) []serviceReference {
+	var refs []serviceReference
+	seen := make(map[string]bool)
+	add := func(value, via string, hostHint bool) {
+		for _, svc := range serviceNames(value, namespace, hostHint) {
+			if !seen[svc] {
+				seen[svc] = true
+				refs = append(refs, serviceReference{service: svc, via: via})
+			}
+		}
+	}
+	containers := append(append([]corev1.Container(nil), template.Spec.InitContainers...), template.Spec.Containers...)
+	for _, c := range containers {
+		for _, env := range c.Env {
+			if env.Value == "" {
+				continue
+			}
+			name := strings.ToUpper(env.Name)
+			hint := false
+			for _, suffix := range hostEnvSuffixes {
+				if strings.HasSuffix(name, suffix) {
+					hint = true
+					break
+				}
+			}
+			add(env.Value, "env "+env.Name, hint)
+		}
+		for _, arg := range append(append([]string(nil), c.Command...), c.Args...) {
+			add(arg, "args", false)
+		}
+	}
+	return refs
+}
+
+// serviceNames extracts namespace/name Service keys from a value
+func serviceNames(value, namespace string, hostHint bool) []string {
+	var result []string
+	for _, part := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' || r == ';' }) {
+		if i := strings.IndexByte(part, '='); i >= 0 && !strings.Contains(part[:i], "://") {
+			part = part[i+1:] // --flag=value
+		}
+		explicit := hostHint
+		host := part
+		if strings.Contains(part, "://") {
+			u, err := url.Parse(part)
+			if err != nil {
+				continue
+			}
+			host, explicit = u.Hostname(), true
+		} else {
+			if i := strings.IndexByte(host, '/'); i >= 0 {
+				host = host[:i]
+			}
+			if i := strings.LastIndexByte(host, '@'); i >= 0 {
+				host = host[i+1:]
+			}
+			if i := strings.IndexByte(host, ':'); i >= 0 {
+				host, explicit = host[:i], true
+			}
+		}
+		host = strings.TrimSuffix(strings.ToLower(host), ".")
+		if !dnsNameRe.MatchString(host) {
+			continue
+		}
+		host = strings.TrimSuffix(strings.TrimSuffix(host, ".cluster.local"), ".svc")
+		parts := strings.Split(host, ".")
+		switch {
+		case len(parts) == 2:
+			result = append(result, parts[1]+"/"+parts[0])
+		case len(parts) == 1 && explicit:
+			result = append(result, namespace+"/"+parts[0])
+		}
+	}
+	return result
+}
+
+// RestartWorkloads restarts the planned waves in order, reporting progress.
+// An ordered restart waits for each wave to roll out and stops at the first
+// wave that fails or times out, so dependents aren't restarted against
+// unhealthy dependencies.
+func RestartWorkloads(ctx context.Context, plan *RestartPlan, opts BulkRestartOptions, progress func(RestartProgress)) (*BulkResult, error) {
+	timeout := defaultRestartTimeout
+	if opts.TimeoutSeconds > 0 {
+		timeout = time.Duration(opts.TimeoutSeconds) * time.Second
+	}
+
+	result := &BulkResult{}
+	for _, wave := range plan.Waves {
+		result.Total += len(wave)
+	}
+	failed := make(map[BulkTarget]string)
+	for w, wave := range plan.Waves {
+		var restarted []BulkTarget
+		for _, t := range wave {
+			progress(RestartProgress{Wave: w + 1, Target: t, Phase: "restarting"})
+			if err := RestartWorkload(ctx, t.Kind, t.Namespace, t.Name); err != nil {
+				failed[t] = err.Error()
+				progress(RestartProgress{Wave: w + 1, Target: t, Phase: "failed", Message: err.Error()})
+				continue
+			}
+			restarted = append(restarted, t)
+		}
+
+		if opts.Ordered {
+			waitCtx, cancel := context.WithTimeout(ctx, timeout)
+			for _, t := range restarted {
+				progress(RestartProgress{Wave: w + 1, Target: t, Phase: "waiting"})
+				if err := waitRolledOut(waitCtx, t); err != nil {
+					failed[t] = err.Error()
+					progress(RestartProgress{Wave: w + 1, Target: t, Phase: "failed", Message: err.Error()})
+					continue
+				}
+				progress(RestartProgress{Wave: w + 1, Target: t, Phase: "ready"})
+			}
+			cancel()
+		} else {
+			for _, t := range restarted {
+				progress(RestartProgress{Wave: w + 1, Target: t, Phase: "restarted"})
+			}
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		for _, t := range wave {
+			item := BulkItemResult{BulkTarget: t, Success: true}
+			if msg, ok := failed[t]; ok {
+				item.Success, item.Error = false, msg
+				result.Failed++
+			} else {
+				result.Succeeded++
+			}
+			result.Results = append(result.Results, item)
+		}
+		if opts.Ordered && len(failed) > 0 && w < len(plan.Waves)-1 {
+			for _, rest := range plan.Waves[w+1:] {
+				for _, t := range rest {
+					result.Results = append(result.Results, BulkItemResult{BulkTarget: t, Error: "skipped: a dependency failed to roll out"})
+					result.Failed++
+				}
+			}
+			break
+		}
+	}
+	return result, nil
+}
+
+// waitRolledOut polls a restarted workload until its rollout completes
+func waitRolledOut(ctx context.Context, t BulkTarget) error {
+	dynamicClient := GetDynamicClient()
+	discovery := GetResourceDiscovery()
+	if dynamicClient == nil || discovery == nil {
+		return fmt.Errorf("not connected to a cluster")
+	}
+	gvr, ok := discovery.GetGVR(t.Kind)
+	if !ok {
+		return fmt.Errorf("unknown resource kind: %s", t.Kind)
+	}
+
+	ticker := time.NewTicker(restartPollInterval)
+	defer ticker.Stop()
+	status := "not rolled out"
+	for {
+		obj, err := dynamicClient.Resource(gvr).Namespace(t.Namespace).Get(ctx, t.Name, metav1.GetOptions{})
+		if err == nil {
+			var done bool
+			if done, status = rolloutComplete(obj); done {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for rollout: %s", status)
+		case <-ticker.C:
+		}
+	}
+}
+
+// rolloutComplete reports whether a workload's latest spec has fully rolled
+// out and is available, like `kubectl rollout status`
+func rolloutComplete(obj *unstructured.Unstructured) (bool, string) {
+	generation := obj.GetGeneration()
+	observed, _, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	num := func(fields ...string) int64 {
+		v, _, _ := unstructured.NestedInt64(obj.Object, fields...)
+		return v
+	}
+
+	switch obj.GetKind() {
+	case "DaemonSet":
+		desired := num("status", "desiredNumberScheduled")
+		updated, available := num("status", "updatedNumberScheduled"), num("status", "numberAvailable")
+		if observed < generation {
+			return false, "waiting for the controller to observe the restart"
+		}
+		return updated >= desired && available >= desired, fmt.Sprintf("%d/%d updated, %d/%d available", updated, desired, available, desired)
+	case "Rollout":
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		return phase == "Healthy", "phase " + phase
+	}
+
+	replicas := int64(1)
+	if v, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas"); found {
+		replicas = v
+	}
+	if observed < generation {
+		return false, "waiting for the controller to observe the restart"
+	}
+	updated := num("status", "updatedReplicas")
+	total := num("status", "replicas")
+	ready := num("status", "availableReplicas")
+	if obj.GetKind() == "StatefulSet" {
+		ready = num("status", "readyReplicas")
+		if strategy, _, _ := unstructured.NestedString(obj.Object, "spec", "updateStrategy", "type"); strategy == "OnDelete" {
+			return ready >= replicas, fmt.Sprintf("%d/%d ready (OnDelete: pods restart when deleted)", ready, replicas)
+		}
+	}
+	if updated < replicas {
+		return false, fmt.Sprintf("%d/%d replicas updated", updated, replicas)
+	}
+	if total > updated {
+		return false, fmt.Sprintf("%d old replicas pending termination", total-updated)
+	}
+	return ready >= replicas, fmt.Sprintf("%d/%d replicas available", ready, replicas)
+}
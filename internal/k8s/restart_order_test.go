@@ -0,0 +1,135 @@
+package k8s
+
+import (
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func restartTestNode(name string, env ...corev1.EnvVar) restartNode {
+	return restartNode{
+		target: BulkTarget{Kind: "Deployment", Namespace: "shop", Name: name},
+		template: &corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": name}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Env: env}}},
+		},
+	}
+}
+
+func restartTestService(name, app string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: name},
+		Spec:       corev1.ServiceSpec{Selector: map[string]string{"app": app}},
+	}
+}
+
+func waveNames(plan *RestartPlan) string {
+	var waves [][]string
+	for _, wave := range plan.Waves {
+		var names []string
+		for _, t := range wave {
+			names = append(names, t.Name)
+		}
+		waves = append(waves, names)
+	}
+	return fmt.Sprint(waves)
+}
+
+func TestPlanRestartsOrdersDependenciesFirst(t *testing.T) {
+	nodes := []restartNode{
+		restartTestNode("frontend", corev1.EnvVar{Name: "API_URL", Value: "http://api.shop.svc.cluster.local:8080"}),
+		restartTestNode("api", corev1.EnvVar{Name: "DB_HOST", Value: "postgres"}),
+		restartTestNode("postgres"),
+		restartTestNode("worker"),
+	}
+	services := []*corev1.Service{restartTestService("api", "api"), restartTestService("postgres", "postgres")}
+	calls := []ServiceCall{{FromNamespace: "shop", FromWorkload: "worker", ToNamespace: "shop", ToName: "api"}}
+
+	plan := planRestarts(nodes, services, calls)
+	if got := waveNames(plan); got != "[[postgres] [api] [frontend worker]]" {
+		t.Errorf("unexpected waves: %s", got)
+	}
+	if len(plan.Dependencies) != 3 {
+		t.Fatalf("expected 3 dependencies, got %+v", plan.Dependencies)
+	}
+	if d := plan.Dependencies[1]; d.From.Name != "api" || d.To.Name != "postgres" || d.Via != "env DB_HOST" {
+		t.Errorf("unexpected dependency: %+v", d)
+	}
+	if d := plan.Dependencies[2]; d.From.Name != "worker" || d.Via != "traffic" {
+		t.Errorf("unexpected traffic dependency: %+v", d)
+	}
+}
+
+func TestPlanRestartsCycle(t *testing.T) {
+	nodes := []restartNode{
+		restartTestNode("a", corev1.EnvVar{Name: "PEER", Value: "b:9000"}),
+		restartTestNode("b", corev1.EnvVar{Name: "PEER", Value: "a:9000"}),
+		restartTestNode("c"),
+	}
+	services := []*corev1.Service{restartTestService("a", "a"), restartTestService("b", "b")}
+
+	plan := planRestarts(nodes, services, nil)
+	if got := waveNames(plan); got != "[[c] [a b]]" {
+		t.Errorf("unexpected waves: %s", got)
+	}
+	if len(plan.Notes) != 1 {
+		t.Errorf("expected a note about the cycle, got %v", plan.Notes)
+	}
+}
+
+func TestServiceNames(t *testing.T) {
+	tests := []struct {
+		value string
+		hint  bool
+		want  string
+	}{
+		{"redis.cache:6379", false, "[cache/redis]"},
+		{"redis.cache.svc.cluster.local", false, "[cache/redis]"},
+		{"postgres://user:pw@db:5432/app", false, "[shop/db]"},
+		{"kafka-0:9092,kafka-1:9092", false, "[shop/kafka-0 shop/kafka-1]"},
+		{"--upstream=auth:443", false, "[shop/auth]"},
+		{"auth", true, "[shop/auth]"},
+		{"production", false, "[]"}, // A bare word isn't a host unless the variable says so
+		{"10.0.0.1:80", false, "[]"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(serviceNames(tt.value, "shop", tt.hint)); got != tt.want {
+			t.Errorf("%q: expected %s, got %s", tt.value, tt.want, got)
+		}
+	}
+}
+
+func TestRolloutComplete(t *testing.T) {
+	deployment := func(generation, observed, replicas, updated, total, available int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"kind":     "Deployment",
+			"metadata": map[string]any{"generation": generation},
+			"spec":     map[string]any{"replicas": replicas},
+			"status": map[string]any{
+				"observedGeneration": observed,
+				"updatedReplicas":    updated,
+				"replicas":           total,
+				"availableReplicas":  available,
+			},
+		}}
+	}
+	tests := []struct {
+		name string
+		obj  *unstructured.Unstructured
+		want bool
+	}{
+		{"not observed", deployment(3, 2, 2, 2, 2, 2), false},
+		{"updating", deployment(3, 3, 2, 1, 3, 2), false},
+		{"old replica terminating", deployment(3, 3, 2, 2, 3, 2), false},
+		{"not available", deployment(3, 3, 2, 2, 2, 1), false},
+		{"complete", deployment(3, 3, 2, 2, 2, 2), true},
+	}
+	for _, tt := range tests {
+		if got, status := rolloutComplete(tt.obj); got != tt.want {
+			t.Errorf("%s: expected %v, got %v (%s)", tt.name, tt.want, got, status)
+		}
+	}
+}
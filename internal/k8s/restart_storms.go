@@ -0,0 +1,264 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// Restart storm detection thresholds: this many container restarts across at
+// least restartStormMinPods pods within restartStormWindow is an incident
+const (
+	restartStormWindow      = 5 * time.Minute
+	restartStormMinRestarts = 10
+	restartStormMinPods     = 3
+	restartStormCooldown    = 15 * time.Minute // One incident per ongoing storm
+	restartStormTopN        = 5
+)
+
+// ReasonRestartStorm is the timeline reason of a synthesized restart storm incident
+const ReasonRestartStorm = "RestartStorm"
+
+// nodeIncidentReasons are node-level K8s event reasons that commonly restart
+// pods en masse, with how they read in an incident summary
+var nodeIncidentReasons = map[string]string{
+	"NodeNotReady":              "node not ready",
+	"Rebooted":                  "node rebooted",
+	"Starting":                  "kubelet restarted",
+	"KubeletIsDown":             "kubelet down",
+	"SystemOOM":                 "system OOM",
+	"OOMKilling":                "kernel OOM kill",
+	"NodeHasInsufficientMemory": "memory pressure",
+	"NodeHasDiskPressure":       "disk pressure",
+	"ContainerRuntimeIsDown":    "container runtime down",
+	"ContainerRuntimeUnhealthy": "container runtime unhealthy",
+}
+
+type podRestart struct {
+	at        time.Time
+	namespace string
+	pod       string
+	workload  string // Kind namespace/name
+	node      string
+	count     int
+}
+
+type nodeSignal struct {
+	at     time.Time
+	node   string
+	reason string
+}
+
+// restartStormDetector tracks recent pod restarts and node-level events, and
+// records an incident to the timeline when restarts spike
+type restartStormDetector struct {
+	mu           sync.Mutex
+	restarts     []podRestart
+	nodeSignals  []nodeSignal
+	lastIncident time.Time
+	now          func() time.Time
+	record       func(timeline.TimelineEvent)
+}
+
+var restartStorms = newRestartStormDetector()
+
+func newRestartStormDetector() *restartStormDetector {
+	return &restartStormDetector{
+		now: time.Now,
+		record: func(event timeline.TimelineEvent) {
+			if err := timeline.RecordEventsWithBroadcast(context.Background(), []timeline.TimelineEvent{event}); err != nil {
+				log.Printf("[RestartStorm] Failed to record incident: %v", err)
+			}
+		},
+	}
+}
+
+// ResetRestartStorms drops tracked restarts and node events (on context switch)
+func ResetRestartStorms() {
+	restartStorms.mu.Lock()
+	defer restartStorms.mu.Unlock()
+	restartStorms.restarts = nil
+	restartStorms.nodeSignals = nil
+	restartStorms.lastIncident = time.Time{}
+}
+
+// observePodUpdate records container restarts between two versions of a pod
+func (d *restartStormDetector) observePodUpdate(oldObj, newObj any) {
+	oldPod, ok1 := oldObj.(*corev1.Pod)
+	newPod, ok2 := newObj.(*corev1.Pod)
+	if !ok1 || !ok2 {
+		return
+	}
+	delta := getTotalRestarts(newPod.Status.ContainerStatuses) - getTotalRestarts(oldPod.Status.ContainerStatuses)
+	if delta <= 0 {
+		return
+	}
+	key := podWorkload(newPod)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	d.restarts = append(d.restarts, podRestart{
+		at:        now,
+		namespace: newPod.Namespace,
+		pod:       newPod.Name,
+		workload:  key.Kind + " " + key.Namespace + "/" + key.Name,
+		node:      newPod.Spec.NodeName,
+		count:     int(delta),
+	})
+	d.checkLocked(now)
+}
+
+// observeNodeEvent remembers node-level events that could explain a restart storm
+func (d *restartStormDetector) observeNodeEvent(event *corev1.Event) {
+	if event.InvolvedObject.Kind != "Node" {
+		return
+	}
+	if _, ok := nodeIncidentReasons[event.Reason]; !ok {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	at := eventTime(event)
+	if d.now().Sub(at) > restartStormWindow {
+		return
+	}
+	d.nodeSignals = append(d.nodeSignals, nodeSignal{at: at, node: event.InvolvedObject.Name, reason: event.Reason})
+}
+
+// checkLocked prunes old entries and records an incident if restarts in the
+// window cross the thresholds. Caller holds d.mu.
+func (d *restartStormDetector) checkLocked(now time.Time) {
+	cutoff := now.Add(-restartStormWindow)
+	for len(d.restarts) > 0 && d.restarts[0].at.Before(cutoff) {
+		d.restarts = d.restarts[1:]
+	}
+	signals := d.nodeSignals[:0]
+	for _, s := range d.nodeSignals {
+		if !s.at.Before(cutoff) {
+			signals = append(signals, s)
+		}
+	}
+	d.nodeSignals = signals
+
+	if now.Sub(d.lastIncident) < restartStormCooldown {
+		return
+	}
+	total := 0
+	pods := make(map[string]bool)
+	for _, r := range d.restarts {
+		total += r.count
+		pods[r.namespace+"/"+r.pod] = true
+	}
+	if total < restartStormMinRestarts || len(pods) < restartStormMinPods {
+		return
+	}
+
+	d.lastIncident = now
+	d.record(summarizeRestartStorm(d.restarts, d.nodeSignals, len(pods), total))
+}
+
+// summarizeRestartStorm builds the incident timeline event: scope (namespaces,
+// workloads, nodes) and the probable cause
+func summarizeRestartStorm(restarts []podRestart, signals []nodeSignal, pods, total int) timeline.TimelineEvent {
+	byNamespace := make(map[string]int)
+	byWorkload := make(map[string]int)
+	byNode := make(map[string]int)
+	for _, r := range restarts {
+		byNamespace[r.namespace] += r.count
+		byWorkload[r.workload] += r.count
+		if r.node != "" {
+			byNode[r.node] += r.count
+		}
+	}
+	namespaces := rankCounts(byNamespace)
+	workloads := rankCounts(byWorkload)
+	nodes := rankCounts(byNode)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d container restarts across %d pods in %s", total, pods, restartStormWindow)
+	fmt.Fprintf(&b, "; namespaces: %s", formatTopCounts(namespaces, byNamespace))
+	fmt.Fprintf(&b, "; workloads: %s", formatTopCounts(workloads, byWorkload))
+	if len(nodes) > 0 {
+		fmt.Fprintf(&b, "; nodes: %s", formatTopCounts(nodes, byNode))
+	}
+	fmt.Fprintf(&b, "; probable cause: %s", probableRestartCause(signals, byNode, workloads, namespaces))
+
+	// Scope the incident to its namespace when it's confined to one, so
+	// namespace-filtered timelines still show it
+	namespace := ""
+	if len(namespaces) == 1 {
+		namespace = namespaces[0]
+	}
+	return timeline.NewLifecycleEvent("Incident", namespace, "restart-storm", "",
+		timeline.EventTypeWarning, ReasonRestartStorm, b.String(), timeline.HealthUnhealthy)
+}
+
+// probableRestartCause prefers node-level events on the affected nodes, then
+// falls back to where the restarts are concentrated
+func probableRestartCause(signals []nodeSignal, byNode map[string]int, workloads, namespaces []string) string {
+	var causes []string
+	seen := make(map[string]bool)
+	for _, s := range signals {
+		if _, affected := byNode[s.node]; !affected {
+			continue
+		}
+		cause := fmt.Sprintf("%s on %s", nodeIncidentReasons[s.reason], s.node)
+		if !seen[cause] {
+			seen[cause] = true
+			causes = append(causes, cause)
+		}
+	}
+	if len(causes) > 0 {
+		sort.Strings(causes)
+		return strings.Join(causes, ", ")
+	}
+
+	switch {
+	case len(byNode) == 1:
+		for node := range byNode {
+			return fmt.Sprintf("confined to node %s (no node events seen)", node)
+		}
+	case len(workloads) == 1:
+		return fmt.Sprintf("confined to %s, likely an application or config issue", workloads[0])
+	case len(namespaces) == 1:
+		return fmt.Sprintf("confined to namespace %s, likely a shared dependency", namespaces[0])
+	}
+	return "spread across nodes and namespaces with no node events; check the control plane and shared dependencies"
+}
+
+// rankCounts returns keys by descending count, then name
+func rankCounts(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+func formatTopCounts(keys []string, counts map[string]int) string {
+	parts := make([]string, 0, restartStormTopN+1)
+	for i, k := range keys {
+		if i == restartStormTopN {
+			parts = append(parts, fmt.Sprintf("+%d more", len(keys)-restartStormTopN))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s (%d)", k, counts[k]))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func restartTestPods(namespace, name, node string, restarts int32) (*corev1.Pod, *corev1.Pod) {
+	oldPod := maintenanceTestPod(namespace, name, node, "ReplicaSet", "api-7d9f", map[string]string{"pod-template-hash": "7d9f"})
+	oldPod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "app"}}
+	newPod := oldPod.DeepCopy()
+	newPod.Status.ContainerStatuses[0].RestartCount = restarts
+	return oldPod, newPod
+}
+
+func TestRestartStormDetector(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	var incidents []timeline.TimelineEvent
+	d := &restartStormDetector{
+		now:    func() time.Time { return now },
+		record: func(e timeline.TimelineEvent) { incidents = append(incidents, e) },
+	}
+
+	d.observeNodeEvent(&corev1.Event{
+		InvolvedObject: corev1.ObjectReference{Kind: "Node", Name: "node-a"},
+		Reason:         "NodeNotReady",
+		LastTimestamp:  metav1.NewTime(now.Add(-time.Minute)),
+	})
+	// Not an incident reason, and too old: both ignored
+	d.observeNodeEvent(&corev1.Event{InvolvedObject: corev1.ObjectReference{Kind: "Node", Name: "node-a"}, Reason: "NodeReady", LastTimestamp: metav1.NewTime(now)})
+	d.observeNodeEvent(&corev1.Event{InvolvedObject: corev1.ObjectReference{Kind: "Node", Name: "node-a"}, Reason: "Rebooted", LastTimestamp: metav1.NewTime(now.Add(-time.Hour))})
+	if len(d.nodeSignals) != 1 {
+		t.Fatalf("expected only the recent NodeNotReady to be kept, got %+v", d.nodeSignals)
+	}
+
+	// 9 restarts across 3 pods: below the threshold
+	for _, name := range []string{"api-7d9f-a", "api-7d9f-b", "api-7d9f-c"} {
+		d.observePodUpdate(restartTestPods("shop", name, "node-a", 3))
+	}
+	if len(incidents) != 0 {
+		t.Fatalf("expected no incident below %d restarts, got %+v", restartStormMinRestarts, incidents)
+	}
+
+	d.observePodUpdate(restartTestPods("shop", "api-7d9f-a", "node-a", 2))
+	if len(incidents) != 1 {
+		t.Fatalf("expected one incident, got %d", len(incidents))
+	}
+	incident := incidents[0]
+	if incident.Kind != "Incident" || incident.Reason != ReasonRestartStorm || incident.Namespace != "shop" || incident.EventType != timeline.EventTypeWarning {
+		t.Errorf("unexpected incident %+v", incident)
+	}
+	for _, want := range []string{"11 container restarts across 3 pods", "Deployment shop/api (11)", "nodes: node-a (11)", "probable cause: node not ready on node-a"} {
+		if !strings.Contains(incident.Message, want) {
+			t.Errorf("expected message to contain %q, got %q", want, incident.Message)
+		}
+	}
+
+	// An ongoing storm doesn't produce another incident within the cooldown
+	d.observePodUpdate(restartTestPods("shop", "api-7d9f-b", "node-a", 5))
+	if len(incidents) != 1 {
+		t.Errorf("expected the cooldown to suppress a second incident, got %d", len(incidents))
+	}
+}
+
+func TestProbableRestartCause(t *testing.T) {
+	tests := []struct {
+		name       string
+		byNode     map[string]int
+		workloads  []string
+		namespaces []string
+		want       string
+	}{
+		{"one node", map[string]int{"node-a": 10}, []string{"a", "b"}, []string{"x", "y"}, "confined to node node-a"},
+		{"one workload", map[string]int{"node-a": 5, "node-b": 5}, []string{"Deployment shop/api"}, []string{"shop"}, "confined to Deployment shop/api"},
+		{"one namespace", map[string]int{"node-a": 5, "node-b": 5}, []string{"a", "b"}, []string{"shop"}, "confined to namespace shop"},
+		{"everywhere", map[string]int{"node-a": 5, "node-b": 5}, []string{"a", "b"}, []string{"x", "y"}, "spread across nodes"},
+	}
+	for _, tt := range tests {
+		// A signal on an unaffected node doesn't count as the cause
+		signals := []nodeSignal{{node: "node-z", reason: "Rebooted"}}
+		if got := probableRestartCause(signals, tt.byNode, tt.workloads, tt.namespaces); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("%s: got %q, want prefix %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,388 @@
+package k8s
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Token usage of a pod
+const (
+	TokenStatusBound  = "bound"  // Only projected, expiring tokens
+	TokenStatusLegacy = "legacy" // Only long-lived token Secrets
+	TokenStatusMixed  = "mixed"  // Both
+	TokenStatusNone   = "none"   // No API token mounted
+)
+
+const (
+	// Labels the token cleaner sets on legacy token Secrets (K8s 1.26+)
+	legacyTokenLastUsedLabel     = "kubernetes.io/legacy-token-last-used"
+	legacyTokenInvalidSinceLabel = "kubernetes.io/legacy-token-invalid-since"
+
+	defaultTokenExpirationSeconds = 3600
+	maxTokenRefreshSeconds        = 24 * 60 * 60
+)
+
+// legacyTokenName matches the names of auto-generated token Secrets, used
+// when Secrets can't be read
+var legacyTokenName = regexp.MustCompile(`^.+-token-[a-z0-9]{5}$`)
+
+// ProjectedToken is a bound service account token in a projected volume.
+// The kubelet refreshes it after 80% of its TTL or 24h, whichever is sooner.
+type ProjectedToken struct {
+	Volume            string   `json:"volume"`
+	Path              string   `json:"path"`
+	Mounts            []string `json:"mounts"`             // container:/mount/path
+	Audience          string   `json:"audience,omitempty"` // Empty means the API server
+	ExpirationSeconds int64    `json:"expirationSeconds"`
+	RefreshSeconds    int64    `json:"refreshSeconds"`
+	Default           bool     `json:"default,omitempty"` // The kube-api-access volume injected by the control plane
+}
+
+// LegacyTokenMount is a long-lived token Secret used by a pod
+type LegacyTokenMount struct {
+	Secret string `json:"secret"`
+	Via    string `json:"via"`              // volume:<name>, env:<container>/<var>, or envFrom:<container>
+	ByName bool   `json:"byName,omitempty"` // Detected by name because Secrets aren't readable
+}
+
+// PodTokenReport describes how a pod authenticates to the API server
+type PodTokenReport struct {
+	Namespace       string             `json:"namespace"`
+	Pod             string             `json:"pod"`
+	ServiceAccount  string             `json:"serviceAccount"`
+	Automount       bool               `json:"automount"`
+	AutomountSource string             `json:"automountSource"` // pod, serviceAccount, or default
+	Status          string             `json:"status"`
+	Projected       []ProjectedToken   `json:"projected,omitempty"`
+	Legacy          []LegacyTokenMount `json:"legacy,omitempty"`
+	Issues          []string           `json:"issues,omitempty"`
+}
+
+// LegacyTokenSecret is a long-lived service account token Secret
+type LegacyTokenSecret struct {
+	Namespace      string    `json:"namespace"`
+	Name           string    `json:"name"`
+	ServiceAccount string    `json:"serviceAccount"`
+	CreatedAt      time.Time `json:"createdAt"`
+	LastUsed       string    `json:"lastUsed,omitempty"`     // Date from the token cleaner's label
+	InvalidSince   string    `json:"invalidSince,omitempty"` // Set once the cleaner invalidated it
+	UsedBy         []string  `json:"usedBy"`                 // Pods using it
+}
+
+// TokenReport summarizes token usage across pods
+type TokenReport struct {
+	Pods          []PodTokenReport    `json:"pods"`
+	LegacySecrets []LegacyTokenSecret `json:"legacySecrets"`
+	Summary       map[string]int      `json:"summary"` // Pods by status
+	Warnings      []string            `json:"warnings,omitempty"`
+}
+
+// TokenClaims are the unverified claims of a service account token
+type TokenClaims struct {
+	Issuer         string     `json:"issuer,omitempty"`
+	Audiences      []string   `json:"audiences,omitempty"`
+	Subject        string     `json:"subject,omitempty"`
+	IssuedAt       *time.Time `json:"issuedAt,omitempty"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"` // Nil for legacy tokens
+	WarnAfter      *time.Time `json:"warnAfter,omitempty"` // Extended expiration: the API server warns after this
+	BoundPod       string     `json:"boundPod,omitempty"`
+	ServiceAccount string     `json:"serviceAccount,omitempty"`
+}
+
+// GetServiceAccountTokenReport reports token usage for pods in namespace ("" for all)
+func GetServiceAccountTokenReport(ctx context.Context, namespace string) (*TokenReport, error) {
+	cache := GetResourceCache()
+	if cache == nil || cache.Pods() == nil {
+		return nil, fmt.Errorf("resource cache not available")
+	}
+	pods, err := cache.Pods().Pods(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	report := &TokenReport{Pods: []PodTokenReport{}, LegacySecrets: []LegacyTokenSecret{}, Summary: map[string]int{}}
+	var secrets []*corev1.Secret
+	if lister := cache.Secrets(); lister != nil {
+		secrets, _ = lister.Secrets(namespace).List(labels.Everything())
+	} else {
+		report.Warnings = append(report.Warnings, "Secrets aren't readable: legacy tokens are detected by name and unused ones aren't listed")
+	}
+
+	namespaces := make(map[string]bool)
+	for _, pod := range pods {
+		namespaces[pod.Namespace] = true
+	}
+	automount := serviceAccountAutomount(ctx, sortedKeys(namespaces))
+	report.Pods, report.LegacySecrets = buildTokenReport(pods, secrets, automount, cache.Secrets() != nil)
+	for _, p := range report.Pods {
+		report.Summary[p.Status]++
+	}
+	return report, nil
+}
+
+// GetPodTokenReport reports a single pod's token usage. The second return is
+// false when the pod doesn't exist.
+func GetPodTokenReport(ctx context.Context, namespace, name string) (*PodTokenReport, bool) {
+	cache := GetResourceCache()
+	if cache == nil || cache.Pods() == nil {
+		return nil, false
+	}
+	pod, err := cache.Pods().Pods(namespace).Get(name)
+	if err != nil {
+		return nil, false
+	}
+	var secrets []*corev1.Secret
+	if lister := cache.Secrets(); lister != nil {
+		secrets, _ = lister.Secrets(namespace).List(labels.Everything())
+	}
+	reports, _ := buildTokenReport([]*corev1.Pod{pod}, secrets, serviceAccountAutomount(ctx, []string{namespace}), cache.Secrets() != nil)
+	return &reports[0], true
+}
+
+// serviceAccountAutomount returns automountServiceAccountToken of service
+// accounts that set it, keyed by namespace/name. ServiceAccounts aren't
+// cached, so they're listed once per namespace; failures are ignored.
+func serviceAccountAutomount(ctx context.Context, namespaces []string) map[string]bool {
+	result := make(map[string]bool)
+	client := GetClient()
+	if client == nil {
+		return result
+	}
+	for _, ns := range namespaces {
+		list, err := client.CoreV1().ServiceAccounts(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			continue
+		}
+		for _, sa := range list.Items {
+			if sa.AutomountServiceAccountToken != nil {
+				result[ns+"/"+sa.Name] = *sa.AutomountServiceAccountToken
+			}
+		}
+	}
+	return result
+}
+
+// buildTokenReport analyzes pods against the token Secrets. With
+// secretsReadable false, legacy token Secrets are matched by name.
+func buildTokenReport(pods []*corev1.Pod, secrets []*corev1.Secret, saAutomount map[string]bool, secretsReadable bool) ([]PodTokenReport, []LegacyTokenSecret) {
+	tokenSecrets := make(map[string]*LegacyTokenSecret) // namespace/name
+	for _, s := range secrets {
+		if s.Type != corev1.SecretTypeServiceAccountToken {
+			continue
+		}
+		tokenSecrets[s.Namespace+"/"+s.Name] = &LegacyTokenSecret{
+			Namespace:      s.Namespace,
+			Name:           s.Name,
+			ServiceAccount: s.Annotations[corev1.ServiceAccountNameKey],
+			CreatedAt:      s.CreationTimestamp.Time,
+			LastUsed:       s.Labels[legacyTokenLastUsedLabel],
+			InvalidSince:   s.Labels[legacyTokenInvalidSinceLabel],
+			UsedBy:         []string{},
+		}
+	}
+	isTokenSecret := func(namespace, name string) (bool, bool) {
+		if !secretsReadable {
+			return legacyTokenName.MatchString(name), true
+		}
+		_, ok := tokenSecrets[namespace+"/"+name]
+		return ok, false
+	}
+
+	reports := make([]PodTokenReport, 0, len(pods))
+	for _, pod := range pods {
+		r := podTokenReport(pod, saAutomount, isTokenSecret)
+		for _, l := range r.Legacy {
+			if s, ok := tokenSecrets[pod.Namespace+"/"+l.Secret]; ok {
+				s.UsedBy = append(s.UsedBy, pod.Name)
+				if s.InvalidSince != "" {
+					r.Issues = append(r.Issues, fmt.Sprintf("Token Secret %s was invalidated on %s", l.Secret, s.InvalidSince))
+				}
+			}
+		}
+		reports = append(reports, r)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Namespace != reports[j].Namespace {
+			return reports[i].Namespace < reports[j].Namespace
+		}
+		return reports[i].Pod < reports[j].Pod
+	})
+
+	legacy := make([]LegacyTokenSecret, 0, len(tokenSecrets))
+	for _, key := range sortedKeys(tokenSecrets) {
+		s := tokenSecrets[key]
+		sort.Strings(s.UsedBy)
+		s.UsedBy = compactStrings(s.UsedBy)
+		legacy = append(legacy, *s)
+	}
+	return reports, legacy
+}
+
+func podTokenReport(pod *corev1.Pod, saAutomount map[string]bool, isTokenSecret func(namespace, name string) (bool, bool)) PodTokenReport {
+	r := PodTokenReport{Namespace: pod.Namespace, Pod: pod.Name, ServiceAccount: pod.Spec.ServiceAccountName, Automount: true, AutomountSource: "default"}
+	if r.ServiceAccount == "" {
+		r.ServiceAccount = "default"
+	}
+	if v, ok := saAutomount[pod.Namespace+"/"+r.ServiceAccount]; ok {
+		r.Automount, r.AutomountSource = v, "serviceAccount"
+	}
+	if pod.Spec.AutomountServiceAccountToken != nil {
+		r.Automount, r.AutomountSource = *pod.Spec.AutomountServiceAccountToken, "pod"
+	}
+
+	containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	mounts := func(volume string) []string {
+		var m []string
+		for _, c := range containers {
+			for _, vm := range c.VolumeMounts {
+				if vm.Name == volume {
+					m = append(m, c.Name+":"+vm.MountPath)
+				}
+			}
+		}
+		return m
+	}
+	addLegacy := func(secret, via string) {
+		if ok, byName := isTokenSecret(pod.Namespace, secret); ok {
+			r.Legacy = append(r.Legacy, LegacyTokenMount{Secret: secret, Via: via, ByName: byName})
+		}
+	}
+
+	for _, vol := range pod.Spec.Volumes {
+		switch {
+		case vol.Projected != nil:
+			for _, src := range vol.Projected.Sources {
+				if src.ServiceAccountToken == nil {
+					continue
+				}
+				t := ProjectedToken{
+					Volume:            vol.Name,
+					Path:              src.ServiceAccountToken.Path,
+					Mounts:            mounts(vol.Name),
+					Audience:          src.ServiceAccountToken.Audience,
+					ExpirationSeconds: defaultTokenExpirationSeconds,
+					Default:           strings.HasPrefix(vol.Name, "kube-api-access-"),
+				}
+				if src.ServiceAccountToken.ExpirationSeconds != nil {
+					t.ExpirationSeconds = *src.ServiceAccountToken.ExpirationSeconds
+				}
+				t.RefreshSeconds = min(t.ExpirationSeconds*8/10, maxTokenRefreshSeconds)
+				r.Projected = append(r.Projected, t)
+			}
+		case vol.Secret != nil:
+			addLegacy(vol.Secret.SecretName, "volume:"+vol.Name)
+		}
+	}
+	for _, c := range containers {
+		for _, e := range c.Env {
+			if e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil {
+				addLegacy(e.ValueFrom.SecretKeyRef.Name, "env:"+c.Name+"/"+e.Name)
+			}
+		}
+		for _, from := range c.EnvFrom {
+			if from.SecretRef != nil {
+				addLegacy(from.SecretRef.Name, "envFrom:"+c.Name)
+			}
+		}
+	}
+
+	switch {
+	case len(r.Projected) > 0 && len(r.Legacy) > 0:
+		r.Status = TokenStatusMixed
+	case len(r.Legacy) > 0:
+		r.Status = TokenStatusLegacy
+	case len(r.Projected) > 0:
+		r.Status = TokenStatusBound
+	default:
+		r.Status = TokenStatusNone
+	}
+	for _, l := range r.Legacy {
+		r.Issues = append(r.Issues, fmt.Sprintf("Uses long-lived token Secret %s (%s): switch to a projected serviceAccountToken volume", l.Secret, l.Via))
+	}
+	for _, t := range r.Projected {
+		if len(t.Mounts) == 0 {
+			r.Issues = append(r.Issues, fmt.Sprintf("Projected token volume %s isn't mounted by any container", t.Volume))
+		}
+	}
+	return r
+}
+
+func compactStrings(s []string) []string {
+	out := s[:0]
+	for i, v := range s {
+		if i == 0 || v != s[i-1] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// DecodeTokenClaims reads a service account JWT's claims without verifying
+// its signature. Only claims are returned, never the token.
+func DecodeTokenClaims(token string) (*TokenClaims, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return nil, errors.New("not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT payload: %w", err)
+	}
+
+	var raw struct {
+		Issuer    string          `json:"iss"`
+		Subject   string          `json:"sub"`
+		Audience  json.RawMessage `json:"aud"`
+		IssuedAt  int64           `json:"iat"`
+		ExpiresAt int64           `json:"exp"`
+		K8s       struct {
+			WarnAfter      int64                 `json:"warnafter"`
+			Pod            struct{ Name string } `json:"pod"`
+			ServiceAccount struct{ Name string } `json:"serviceaccount"`
+		} `json:"kubernetes.io"`
+		LegacyServiceAccount string `json:"kubernetes.io/serviceaccount/service-account.name"`
+	}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("invalid JWT claims: %w", err)
+	}
+
+	claims := &TokenClaims{
+		Issuer:         raw.Issuer,
+		Subject:        raw.Subject,
+		IssuedAt:       unixTime(raw.IssuedAt),
+		ExpiresAt:      unixTime(raw.ExpiresAt),
+		WarnAfter:      unixTime(raw.K8s.WarnAfter),
+		BoundPod:       raw.K8s.Pod.Name,
+		ServiceAccount: raw.K8s.ServiceAccount.Name,
+	}
+	if claims.ServiceAccount == "" {
+		claims.ServiceAccount = raw.LegacyServiceAccount
+	}
+	// aud is either a string or a list
+	if len(raw.Audience) > 0 && json.Unmarshal(raw.Audience, &claims.Audiences) != nil {
+		var aud string
+		if json.Unmarshal(raw.Audience, &aud) == nil {
+			claims.Audiences = []string{aud}
+		}
+	}
+	return claims, nil
+}
+
+func unixTime(sec int64) *time.Time {
+	if sec == 0 {
+		return nil
+	}
+	t := time.Unix(sec, 0).UTC()
+	return &t
+}
@@ -0,0 +1,121 @@
+package k8s
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuildTokenReport(t *testing.T) {
+	expiration := int64(7200)
+	automountOff := false
+	bound := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "api-1"},
+		Spec: corev1.PodSpec{
+			ServiceAccountName: "api",
+			Containers:         []corev1.Container{{Name: "app", VolumeMounts: []corev1.VolumeMount{{Name: "kube-api-access-x7k2p", MountPath: "/var/run/secrets/kubernetes.io/serviceaccount"}, {Name: "vault", MountPath: "/vault"}}}},
+			Volumes: []corev1.Volume{
+				{Name: "kube-api-access-x7k2p", VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+					{ServiceAccountToken: &corev1.ServiceAccountTokenProjection{Path: "token"}},
+				}}}},
+				{Name: "vault", VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+					{ServiceAccountToken: &corev1.ServiceAccountTokenProjection{Path: "token", Audience: "vault", ExpirationSeconds: &expiration}},
+				}}}},
+			},
+		},
+	}
+	legacy := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "ci-runner"},
+		Spec: corev1.PodSpec{
+			ServiceAccountName:           "ci",
+			AutomountServiceAccountToken: &automountOff,
+			Containers: []corev1.Container{{Name: "runner", Env: []corev1.EnvVar{
+				{Name: "KUBE_TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "ci-token"}, Key: "token"}}},
+				{Name: "DB_PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}, Key: "password"}}},
+			}}},
+		},
+	}
+	none := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "batch"}, Spec: corev1.PodSpec{ServiceAccountName: "batch"}}
+	secrets := []*corev1.Secret{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "ci-token", Annotations: map[string]string{corev1.ServiceAccountNameKey: "ci"}, Labels: map[string]string{legacyTokenLastUsedLabel: "2026-09-01"}}, Type: corev1.SecretTypeServiceAccountToken},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "old-token"}, Type: corev1.SecretTypeServiceAccountToken},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "db"}, Type: corev1.SecretTypeOpaque},
+	}
+
+	pods, tokens := buildTokenReport([]*corev1.Pod{bound, legacy, none}, secrets, map[string]bool{"shop/batch": false}, true)
+	byName := make(map[string]PodTokenReport)
+	for _, p := range pods {
+		byName[p.Pod] = p
+	}
+
+	api := byName["api-1"]
+	if api.Status != TokenStatusBound || len(api.Projected) != 2 || !api.Automount || api.AutomountSource != "default" {
+		t.Fatalf("unexpected bound pod report: %+v", api)
+	}
+	if def := api.Projected[0]; !def.Default || def.ExpirationSeconds != 3600 || def.RefreshSeconds != 2880 || def.Mounts[0] != "app:/var/run/secrets/kubernetes.io/serviceaccount" {
+		t.Errorf("unexpected default token: %+v", def)
+	}
+	if vault := api.Projected[1]; vault.Audience != "vault" || vault.ExpirationSeconds != 7200 || vault.Default {
+		t.Errorf("unexpected audience token: %+v", vault)
+	}
+
+	ci := byName["ci-runner"]
+	if ci.Status != TokenStatusLegacy || len(ci.Legacy) != 1 || ci.Legacy[0].Via != "env:runner/KUBE_TOKEN" || ci.Automount || ci.AutomountSource != "pod" {
+		t.Errorf("unexpected legacy pod report: %+v", ci)
+	}
+	if len(ci.Issues) != 1 {
+		t.Errorf("expected legacy token issue, got %v", ci.Issues)
+	}
+	if batch := byName["batch"]; batch.Status != TokenStatusNone || batch.Automount || batch.AutomountSource != "serviceAccount" {
+		t.Errorf("unexpected report for pod without tokens: %+v", batch)
+	}
+
+	if len(tokens) != 2 || tokens[0].Name != "ci-token" || tokens[0].ServiceAccount != "ci" || tokens[0].LastUsed != "2026-09-01" || len(tokens[0].UsedBy) != 1 {
+		t.Errorf("unexpected legacy secrets: %+v", tokens)
+	}
+	if tokens[1].Name != "old-token" || len(tokens[1].UsedBy) != 0 {
+		t.Errorf("expected unused token secret, got %+v", tokens[1])
+	}
+
+	// Without Secret access, token Secrets are matched by name
+	pods, _ = buildTokenReport([]*corev1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "old"},
+		Spec:       corev1.PodSpec{Volumes: []corev1.Volume{{Name: "token", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "default-token-8fj2k"}}}}},
+	}}, nil, nil, false)
+	if pods[0].Status != TokenStatusLegacy || !pods[0].Legacy[0].ByName {
+		t.Errorf("expected legacy token detected by name, got %+v", pods[0])
+	}
+}
+
+func TestDecodeTokenClaims(t *testing.T) {
+	payload := `{"aud":["https://kubernetes.default.svc"],"exp":1792022400,"iat":1760486400,"iss":"https://kubernetes.default.svc","sub":"system:serviceaccount:shop:api",` +
+		`"kubernetes.io":{"namespace":"shop","pod":{"name":"api-1"},"serviceaccount":{"name":"api"},"warnafter":1760490007}}`
+	token := "eyJhbGciOiJSUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".c2ln"
+
+	claims, err := DecodeTokenClaims(token + "\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.BoundPod != "api-1" || claims.ServiceAccount != "api" || len(claims.Audiences) != 1 {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+	if claims.ExpiresAt == nil || claims.ExpiresAt.Unix() != 1792022400 || claims.WarnAfter == nil || claims.WarnAfter.Unix() != 1760490007 {
+		t.Errorf("unexpected times: %+v", claims)
+	}
+
+	legacy := `{"iss":"kubernetes/serviceaccount","aud":"vault","kubernetes.io/serviceaccount/service-account.name":"ci"}`
+	claims, err = DecodeTokenClaims("e30." + base64.RawURLEncoding.EncodeToString([]byte(legacy)) + ".c2ln")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.ExpiresAt != nil || claims.ServiceAccount != "ci" || strings.Join(claims.Audiences, ",") != "vault" {
+		t.Errorf("unexpected legacy claims: %+v", claims)
+	}
+
+	if _, err := DecodeTokenClaims("not-a-token"); err == nil {
+		t.Error("expected error for malformed token")
+	}
+}
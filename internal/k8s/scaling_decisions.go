@@ -0,0 +1,526 @@
+package k8s
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+)
+
+// KarpenterGroup is the API group of Karpenter's NodePool, NodeClaim, and Provisioner CRDs
+const KarpenterGroup = "karpenter.sh"
+
+// clusterAutoscalerStatusConfigMap is written by cluster-autoscaler with --write-status-configmap (default on)
+const clusterAutoscalerStatusConfigMap = "cluster-autoscaler-status"
+
+// AutoscalerNodeGroupStatus is one node group from the cluster-autoscaler status ConfigMap
+type AutoscalerNodeGroupStatus struct {
+	Name       string `json:"name"`
+	Health     string `json:"health"`    // Healthy, Unhealthy
+	ScaleUp    string `json:"scaleUp"`   // NoActivity, InProgress, Backoff
+	ScaleDown  string `json:"scaleDown"` // NoCandidates, CandidatesPresent
+	Ready      int    `json:"ready"`
+	Registered int    `json:"registered"`
+	Target     int    `json:"target"` // cloudProviderTarget: size the autoscaler asked the cloud for
+	MinSize    int    `json:"minSize"`
+	MaxSize    int    `json:"maxSize"`
+	Candidates int    `json:"candidates"` // Scale-down candidates
+}
+
+// ClusterAutoscalerStatus is the parsed cluster-autoscaler status ConfigMap
+type ClusterAutoscalerStatus struct {
+	Namespace           string                      `json:"namespace"`
+	UpdatedAt           string                      `json:"updatedAt,omitempty"`
+	Health              string                      `json:"health"`
+	ScaleUp             string                      `json:"scaleUp"`
+	ScaleDown           string                      `json:"scaleDown"`
+	ScaleDownCandidates int                         `json:"scaleDownCandidates"`
+	NodeGroups          []AutoscalerNodeGroupStatus `json:"nodeGroups"`
+	PendingScaleUps     []string                    `json:"pendingScaleUps"` // Node groups with a scale-up in progress
+}
+
+// KarpenterPool is a NodePool (v1/v1beta1) or Provisioner (v1alpha5)
+type KarpenterPool struct {
+	Kind      string            `json:"kind"`
+	Name      string            `json:"name"`
+	Limits    map[string]string `json:"limits,omitempty"`
+	Resources map[string]string `json:"resources,omitempty"` // Provisioned resources (status.resources)
+}
+
+// KarpenterNodeClaim tracks one node Karpenter is provisioning or has provisioned
+type KarpenterNodeClaim struct {
+	Name         string `json:"name"`
+	NodePool     string `json:"nodePool,omitempty"`
+	Phase        string `json:"phase"` // Launching, Registering, Initializing, Ready, Deleting
+	InstanceType string `json:"instanceType,omitempty"`
+	CapacityType string `json:"capacityType,omitempty"`
+	Zone         string `json:"zone,omitempty"`
+	NodeName     string `json:"nodeName,omitempty"`
+	Message      string `json:"message,omitempty"` // Message of the first failing condition
+	CreatedAt    string `json:"createdAt"`
+	LaunchedAt   string `json:"launchedAt,omitempty"`
+	RegisteredAt string `json:"registeredAt,omitempty"`
+	ReadyAt      string `json:"readyAt,omitempty"` // Initialized condition
+}
+
+// KarpenterStatus summarizes Karpenter pools and in-flight NodeClaims
+type KarpenterStatus struct {
+	Pools      []KarpenterPool      `json:"pools"`
+	NodeClaims []KarpenterNodeClaim `json:"nodeClaims"`
+}
+
+// PendingPodScaling correlates an unschedulable pod with autoscaler decisions
+type PendingPodScaling struct {
+	Namespace    string `json:"namespace"`
+	Name         string `json:"name"`
+	PendingSince string `json:"pendingSince,omitempty"`
+	Reason       string `json:"reason"` // Scheduler message explaining why the pod doesn't fit
+	// Decision is what the autoscalers are doing about it:
+	// scale-up-triggered, nodeclaim-launching, no-scale-up, or waiting
+	Decision        string `json:"decision"`
+	DecisionMessage string `json:"decisionMessage,omitempty"`
+	NodeClaim       string `json:"nodeClaim,omitempty"` // Karpenter NodeClaim the pod was nominated to
+}
+
+// ScalingDecisions is the combined view of cluster-autoscaler and Karpenter activity
+type ScalingDecisions struct {
+	ClusterAutoscaler *ClusterAutoscalerStatus `json:"clusterAutoscaler,omitempty"`
+	Karpenter         *KarpenterStatus         `json:"karpenter,omitempty"`
+	PendingPods       []PendingPodScaling      `json:"pendingPods"`
+}
+
+// GetScalingDecisions parses the cluster-autoscaler status ConfigMap and Karpenter
+// CRDs and correlates both with unschedulable pods in the given namespaces.
+func GetScalingDecisions(namespaces []string) *ScalingDecisions {
+	decisions := &ScalingDecisions{PendingPods: []PendingPodScaling{}}
+	cache := GetResourceCache()
+	if cache == nil {
+		return decisions
+	}
+
+	if cmLister := cache.ConfigMaps(); cmLister != nil {
+		if cms, err := cmLister.List(labels.Everything()); err == nil {
+			for _, cm := range cms {
+				if cm.Name == clusterAutoscalerStatusConfigMap {
+					decisions.ClusterAutoscaler = ParseClusterAutoscalerStatus(cm.Data["status"])
+					decisions.ClusterAutoscaler.Namespace = cm.Namespace
+					break
+				}
+			}
+		}
+	}
+
+	decisions.Karpenter = getKarpenterStatus()
+
+	var events []*corev1.Event
+	if eventLister := cache.Events(); eventLister != nil {
+		events, _ = eventLister.List(labels.Everything())
+	}
+	if podLister := cache.Pods(); podLister != nil {
+		if pods, err := podLister.List(labels.Everything()); err == nil {
+			var claims []KarpenterNodeClaim
+			if decisions.Karpenter != nil {
+				claims = decisions.Karpenter.NodeClaims
+			}
+			decisions.PendingPods = correlatePendingPods(pods, events, claims, namespaces)
+		}
+	}
+	return decisions
+}
+
+// casStatusYAML is the structured status format (cluster-autoscaler 1.30+)
+type casStatusYAML struct {
+	Time             string `json:"time"`
+	AutoscalerStatus string `json:"autoscalerStatus"`
+	ClusterWide      struct {
+		Health    casHealthYAML    `json:"health"`
+		ScaleUp   casActivityYAML  `json:"scaleUp"`
+		ScaleDown casScaleDownYAML `json:"scaleDown"`
+	} `json:"clusterWide"`
+	NodeGroups []struct {
+		Name      string           `json:"name"`
+		Health    casHealthYAML    `json:"health"`
+		ScaleUp   casActivityYAML  `json:"scaleUp"`
+		ScaleDown casScaleDownYAML `json:"scaleDown"`
+	} `json:"nodeGroups"`
+}
+
+type casHealthYAML struct {
+	Status     string `json:"status"`
+	NodeCounts struct {
+		Registered struct {
+			Total int `json:"total"`
+			Ready int `json:"ready"`
+		} `json:"registered"`
+	} `json:"nodeCounts"`
+	CloudProviderTarget int `json:"cloudProviderTarget"`
+	MinSize             int `json:"minSize"`
+	MaxSize             int `json:"maxSize"`
+}
+
+type casActivityYAML struct {
+	Status string `json:"status"`
+}
+
+type casScaleDownYAML struct {
+	Status     string `json:"status"`
+	Candidates int    `json:"candidates"`
+}
+
+// ParseClusterAutoscalerStatus parses the status ConfigMap in either the
+// structured YAML format or the legacy human-readable format
+func ParseClusterAutoscalerStatus(status string) *ClusterAutoscalerStatus {
+	var parsed casStatusYAML
+	if err := yaml.Unmarshal([]byte(status), &parsed); err == nil && parsed.AutoscalerStatus != "" {
+		s := &ClusterAutoscalerStatus{
+			UpdatedAt:           parsed.Time,
+			Health:              parsed.ClusterWide.Health.Status,
+			ScaleUp:             parsed.ClusterWide.ScaleUp.Status,
+			ScaleDown:           parsed.ClusterWide.ScaleDown.Status,
+			ScaleDownCandidates: parsed.ClusterWide.ScaleDown.Candidates,
+			NodeGroups:          []AutoscalerNodeGroupStatus{},
+		}
+		for _, ng := range parsed.NodeGroups {
+			s.NodeGroups = append(s.NodeGroups, AutoscalerNodeGroupStatus{
+				Name:       ng.Name,
+				Health:     ng.Health.Status,
+				ScaleUp:    ng.ScaleUp.Status,
+				ScaleDown:  ng.ScaleDown.Status,
+				Ready:      ng.Health.NodeCounts.Registered.Ready,
+				Registered: ng.Health.NodeCounts.Registered.Total,
+				Target:     ng.Health.CloudProviderTarget,
+				MinSize:    ng.Health.MinSize,
+				MaxSize:    ng.Health.MaxSize,
+				Candidates: ng.ScaleDown.Candidates,
+			})
+		}
+		s.PendingScaleUps = pendingScaleUps(s.NodeGroups)
+		return s
+	}
+	return parseLegacyAutoscalerStatus(status)
+}
+
+var (
+	casStatusAtRe = regexp.MustCompile(`status at (.+):`)
+	casFieldRe    = regexp.MustCompile(`^\s*(Name|Health|ScaleUp|ScaleDown):\s+(\S+)(.*)$`)
+	casCountRe    = regexp.MustCompile(`(\w+)=(\d+)`)
+)
+
+// parseLegacyAutoscalerStatus parses the text format used before cluster-autoscaler 1.30:
+//
+//	Cluster-wide:
+//	  Health:      Healthy (ready=3 unready=0 ... registered=3 ...)
+//	  ScaleUp:     NoActivity (ready=3 registered=3)
+//	  ScaleDown:   NoCandidates (candidates=0)
+//	NodeGroups:
+//	  Name:        ng-1
+//	  Health:      Healthy (ready=1 ... cloudProviderTarget=1 (minSize=1, maxSize=3))
+func parseLegacyAutoscalerStatus(status string) *ClusterAutoscalerStatus {
+	s := &ClusterAutoscalerStatus{NodeGroups: []AutoscalerNodeGroupStatus{}}
+	var group *AutoscalerNodeGroupStatus
+	inGroups := false
+
+	for _, line := range strings.Split(status, "\n") {
+		if m := casStatusAtRe.FindStringSubmatch(line); m != nil && s.UpdatedAt == "" {
+			s.UpdatedAt = strings.TrimSpace(m[1])
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "NodeGroups:") {
+			inGroups = true
+			continue
+		}
+		m := casFieldRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		field, value, rest := m[1], m[2], m[3]
+		counts := map[string]int{}
+		for _, c := range casCountRe.FindAllStringSubmatch(rest, -1) {
+			counts[c[1]], _ = strconv.Atoi(c[2])
+		}
+
+		if !inGroups {
+			switch field {
+			case "Health":
+				s.Health = value
+			case "ScaleUp":
+				s.ScaleUp = value
+			case "ScaleDown":
+				s.ScaleDown = value
+				s.ScaleDownCandidates = counts["candidates"]
+			}
+			continue
+		}
+
+		if field == "Name" {
+			s.NodeGroups = append(s.NodeGroups, AutoscalerNodeGroupStatus{Name: value})
+			group = &s.NodeGroups[len(s.NodeGroups)-1]
+			continue
+		}
+		if group == nil {
+			continue
+		}
+		switch field {
+		case "Health":
+			group.Health = value
+			group.Ready = counts["ready"]
+			group.Registered = counts["registered"]
+			group.Target = counts["cloudProviderTarget"]
+			group.MinSize = counts["minSize"]
+			group.MaxSize = counts["maxSize"]
+		case "ScaleUp":
+			group.ScaleUp = value
+		case "ScaleDown":
+			group.ScaleDown = value
+			group.Candidates = counts["candidates"]
+		}
+	}
+	s.PendingScaleUps = pendingScaleUps(s.NodeGroups)
+	return s
+}
+
+func pendingScaleUps(groups []AutoscalerNodeGroupStatus) []string {
+	pending := []string{}
+	for _, g := range groups {
+		if g.ScaleUp == "InProgress" {
+			pending = append(pending, g.Name)
+		}
+	}
+	return pending
+}
+
+// getKarpenterStatus lists NodePools/Provisioners and NodeClaims. Returns nil if Karpenter isn't installed.
+func getKarpenterStatus() *KarpenterStatus {
+	dc := GetDynamicResourceCache()
+	discovery := GetResourceDiscovery()
+	if dc == nil || discovery == nil {
+		return nil
+	}
+
+	var status *KarpenterStatus
+	ensure := func() {
+		if status == nil {
+			status = &KarpenterStatus{Pools: []KarpenterPool{}, NodeClaims: []KarpenterNodeClaim{}}
+		}
+	}
+	for _, kind := range []string{"NodePool", "Provisioner"} {
+		gvr, ok := discovery.GetGVRWithGroup(kind, KarpenterGroup)
+		if !ok {
+			continue
+		}
+		ensure()
+		items, _ := dc.List(gvr, "")
+		for _, item := range items {
+			status.Pools = append(status.Pools, parseKarpenterPool(item))
+		}
+	}
+	if gvr, ok := discovery.GetGVRWithGroup("NodeClaim", KarpenterGroup); ok {
+		ensure()
+		items, _ := dc.List(gvr, "")
+		for _, item := range items {
+			status.NodeClaims = append(status.NodeClaims, ParseKarpenterNodeClaim(item))
+		}
+	}
+	if status == nil {
+		return nil
+	}
+
+	sort.Slice(status.Pools, func(i, j int) bool { return status.Pools[i].Name < status.Pools[j].Name })
+	// Newest first: in-flight claims are the interesting ones
+	sort.Slice(status.NodeClaims, func(i, j int) bool {
+		return status.NodeClaims[i].CreatedAt > status.NodeClaims[j].CreatedAt
+	})
+	return status
+}
+
+func parseKarpenterPool(u *unstructured.Unstructured) KarpenterPool {
+	pool := KarpenterPool{Kind: u.GetKind(), Name: u.GetName()}
+	limitsPath := []string{"spec", "limits"}
+	if pool.Kind == "Provisioner" {
+		limitsPath = []string{"spec", "limits", "resources"}
+	}
+	pool.Limits = stringMapField(u.Object, limitsPath...)
+	pool.Resources = stringMapField(u.Object, "status", "resources")
+	return pool
+}
+
+// ParseKarpenterNodeClaim derives a NodeClaim's provisioning phase and
+// timeline from its Launched/Registered/Initialized conditions
+func ParseKarpenterNodeClaim(u *unstructured.Unstructured) KarpenterNodeClaim {
+	l := u.GetLabels()
+	claim := KarpenterNodeClaim{
+		Name:         u.GetName(),
+		NodePool:     l["karpenter.sh/nodepool"],
+		InstanceType: l["node.kubernetes.io/instance-type"],
+		CapacityType: l["karpenter.sh/capacity-type"],
+		Zone:         l["topology.kubernetes.io/zone"],
+		CreatedAt:    u.GetCreationTimestamp().UTC().Format(time.RFC3339),
+	}
+	claim.NodeName, _, _ = unstructured.NestedString(u.Object, "status", "nodeName")
+
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, c := range conditions {
+		m, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		isTrue := m["status"] == "True"
+		switch m["type"] {
+		case "Launched":
+			if isTrue {
+				claim.LaunchedAt = stringField(m, "lastTransitionTime")
+			}
+		case "Registered":
+			if isTrue {
+				claim.RegisteredAt = stringField(m, "lastTransitionTime")
+			}
+		case "Initialized":
+			if isTrue {
+				claim.ReadyAt = stringField(m, "lastTransitionTime")
+			}
+		default:
+			continue
+		}
+		if !isTrue && claim.Message == "" {
+			claim.Message = stringField(m, "message")
+		}
+	}
+
+	switch {
+	case u.GetDeletionTimestamp() != nil:
+		claim.Phase = "Deleting"
+	case claim.ReadyAt != "":
+		claim.Phase = "Ready"
+	case claim.RegisteredAt != "":
+		claim.Phase = "Initializing"
+	case claim.LaunchedAt != "":
+		claim.Phase = "Registering"
+	default:
+		claim.Phase = "Launching"
+	}
+	return claim
+}
+
+// karpenterNominationRe extracts the NodeClaim from Karpenter's Nominated pod event,
+// e.g. "Pod should schedule on: nodeclaim/default-x7k2p"
+var karpenterNominationRe = regexp.MustCompile(`nodeclaim/([a-z0-9.-]+)`)
+
+// correlatePendingPods matches unschedulable pods with cluster-autoscaler and
+// Karpenter events about them and with the NodeClaim they were nominated to
+func correlatePendingPods(pods []*corev1.Pod, events []*corev1.Event, claims []KarpenterNodeClaim, namespaces []string) []PendingPodScaling {
+	// Latest autoscaler event per pod
+	latest := map[string]*corev1.Event{}
+	for _, e := range events {
+		if e.InvolvedObject.Kind != "Pod" {
+			continue
+		}
+		component := e.Source.Component
+		if component == "" {
+			component = e.ReportingController
+		}
+		if component != "cluster-autoscaler" && component != "karpenter" {
+			continue
+		}
+		key := e.InvolvedObject.Namespace + "/" + e.InvolvedObject.Name
+		if prev, ok := latest[key]; !ok || eventTime(e).After(eventTime(prev)) {
+			latest[key] = e
+		}
+	}
+	claimPhases := map[string]string{}
+	for _, c := range claims {
+		claimPhases[c.Name] = c.Phase
+	}
+
+	result := []PendingPodScaling{}
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodPending || pod.Spec.NodeName != "" {
+			continue
+		}
+		if len(namespaces) > 0 && !slices.Contains(namespaces, pod.Namespace) {
+			continue
+		}
+		var scheduled *corev1.PodCondition
+		for i := range pod.Status.Conditions {
+			if pod.Status.Conditions[i].Type == corev1.PodScheduled {
+				scheduled = &pod.Status.Conditions[i]
+			}
+		}
+		if scheduled == nil || scheduled.Status != corev1.ConditionFalse || scheduled.Reason != corev1.PodReasonUnschedulable {
+			continue
+		}
+
+		p := PendingPodScaling{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			Reason:    scheduled.Message,
+			Decision:  "waiting",
+		}
+		if !scheduled.LastTransitionTime.IsZero() {
+			p.PendingSince = scheduled.LastTransitionTime.UTC().Format(time.RFC3339)
+		}
+
+		if e, ok := latest[pod.Namespace+"/"+pod.Name]; ok {
+			p.DecisionMessage = e.Message
+			switch {
+			case e.Reason == "TriggeredScaleUp":
+				p.Decision = "scale-up-triggered"
+			case e.Reason == "Nominated":
+				p.Decision = "nodeclaim-launching"
+				if m := karpenterNominationRe.FindStringSubmatch(e.Message); m != nil {
+					p.NodeClaim = m[1]
+					if phase, ok := claimPhases[p.NodeClaim]; ok {
+						p.DecisionMessage = "NodeClaim " + p.NodeClaim + " is " + phase
+					}
+				}
+			case e.Reason == "NotTriggerScaleUp" || e.Type == corev1.EventTypeWarning:
+				p.Decision = "no-scale-up"
+			}
+		}
+		result = append(result, p)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].PendingSince != result[j].PendingSince {
+			return result[i].PendingSince < result[j].PendingSince
+		}
+		return result[i].Namespace+"/"+result[i].Name < result[j].Namespace+"/"+result[j].Name
+	})
+	return result
+}
+
+func eventTime(e *corev1.Event) time.Time {
+	if !e.LastTimestamp.IsZero() {
+		return e.LastTimestamp.Time
+	}
+	if !e.EventTime.IsZero() {
+		return e.EventTime.Time
+	}
+	return e.CreationTimestamp.Time
+}
+
+// stringMapField reads a map at path, formatting values (quantities may be strings or numbers) as strings
+func stringMapField(obj map[string]any, path ...string) map[string]string {
+	m, ok, _ := unstructured.NestedMap(obj, path...)
+	if !ok || len(m) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		switch val := v.(type) {
+		case string:
+			out[k] = val
+		case int64:
+			out[k] = strconv.FormatInt(val, 10)
+		case float64:
+			out[k] = strconv.FormatFloat(val, 'f', -1, 64)
+		}
+	}
+	return out
+}
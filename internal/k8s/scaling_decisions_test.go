@@ -0,0 +1,190 @@
+package k8s
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestParseClusterAutoscalerStatusYAML(t *testing.T) {
+	status := `time: 2025-01-10 12:00:00.123 +0000 UTC
+autoscalerStatus: Running
+clusterWide:
+  health:
+    status: Healthy
+  scaleUp:
+    status: InProgress
+  scaleDown:
+    status: NoCandidates
+nodeGroups:
+- name: ng-workers
+  health:
+    status: Healthy
+    nodeCounts:
+      registered:
+        total: 2
+        ready: 2
+    cloudProviderTarget: 4
+    minSize: 1
+    maxSize: 10
+  scaleUp:
+    status: InProgress
+  scaleDown:
+    status: NoCandidates
+- name: ng-system
+  health:
+    status: Healthy
+    nodeCounts:
+      registered:
+        total: 1
+        ready: 1
+    cloudProviderTarget: 1
+    minSize: 1
+    maxSize: 3
+  scaleUp:
+    status: NoActivity
+  scaleDown:
+    status: CandidatesPresent
+    candidates: 1
+`
+	s := ParseClusterAutoscalerStatus(status)
+	if s.Health != "Healthy" || s.ScaleUp != "InProgress" || s.UpdatedAt == "" {
+		t.Errorf("unexpected cluster-wide status: %+v", s)
+	}
+	if len(s.NodeGroups) != 2 {
+		t.Fatalf("expected 2 node groups, got %d", len(s.NodeGroups))
+	}
+	want := AutoscalerNodeGroupStatus{Name: "ng-workers", Health: "Healthy", ScaleUp: "InProgress", ScaleDown: "NoCandidates", Ready: 2, Registered: 2, Target: 4, MinSize: 1, MaxSize: 10}
+	if s.NodeGroups[0] != want {
+		t.Errorf("got %+v, want %+v", s.NodeGroups[0], want)
+	}
+	if s.NodeGroups[1].Candidates != 1 {
+		t.Errorf("expected 1 scale-down candidate, got %+v", s.NodeGroups[1])
+	}
+	if len(s.PendingScaleUps) != 1 || s.PendingScaleUps[0] != "ng-workers" {
+		t.Errorf("expected ng-workers scale-up pending, got %v", s.PendingScaleUps)
+	}
+}
+
+func TestParseClusterAutoscalerStatusLegacy(t *testing.T) {
+	status := `Cluster-autoscaler status at 2024-05-01 10:00:00.5 +0000 UTC:
+Cluster-wide:
+  Health:      Healthy (ready=3 unready=0 notStarted=0 longNotStarted=0 registered=3 longUnregistered=0)
+               LastProbeTime:      2024-05-01 10:00:00 +0000 UTC
+  ScaleUp:     InProgress (ready=3 registered=3)
+  ScaleDown:   NoCandidates (candidates=0)
+
+NodeGroups:
+  Name:        ng-1
+  Health:      Healthy (ready=3 unready=0 notStarted=0 longNotStarted=0 registered=3 longUnregistered=0 cloudProviderTarget=5 (minSize=1, maxSize=10))
+  ScaleUp:     InProgress (ready=3 cloudProviderTarget=5)
+  ScaleDown:   NoCandidates (candidates=0)
+`
+	s := ParseClusterAutoscalerStatus(status)
+	if s.UpdatedAt != "2024-05-01 10:00:00.5 +0000 UTC" || s.Health != "Healthy" || s.ScaleUp != "InProgress" {
+		t.Errorf("unexpected cluster-wide status: %+v", s)
+	}
+	want := AutoscalerNodeGroupStatus{Name: "ng-1", Health: "Healthy", ScaleUp: "InProgress", ScaleDown: "NoCandidates", Ready: 3, Registered: 3, Target: 5, MinSize: 1, MaxSize: 10}
+	if len(s.NodeGroups) != 1 || s.NodeGroups[0] != want {
+		t.Fatalf("got %+v, want [%+v]", s.NodeGroups, want)
+	}
+	if len(s.PendingScaleUps) != 1 {
+		t.Errorf("expected a pending scale-up, got %v", s.PendingScaleUps)
+	}
+}
+
+func TestParseKarpenterNodeClaim(t *testing.T) {
+	condition := func(typ, status, at, msg string) any {
+		return map[string]any{"type": typ, "status": status, "lastTransitionTime": at, "message": msg}
+	}
+	u := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "karpenter.sh/v1",
+		"kind":       "NodeClaim",
+		"metadata": map[string]any{
+			"name":   "default-x7k2p",
+			"labels": map[string]any{"karpenter.sh/nodepool": "default", "karpenter.sh/capacity-type": "spot", "node.kubernetes.io/instance-type": "m5.large"},
+		},
+		"status": map[string]any{
+			"nodeName": "ip-10-0-1-5",
+			"conditions": []any{
+				condition("Launched", "True", "2025-01-10T12:00:10Z", ""),
+				condition("Registered", "True", "2025-01-10T12:00:40Z", ""),
+				condition("Initialized", "False", "2025-01-10T12:00:40Z", "Resources not registered"),
+			},
+		},
+	}}
+
+	claim := ParseKarpenterNodeClaim(u)
+	if claim.Phase != "Initializing" || claim.NodePool != "default" || claim.CapacityType != "spot" || claim.NodeName != "ip-10-0-1-5" {
+		t.Errorf("unexpected claim: %+v", claim)
+	}
+	if claim.LaunchedAt != "2025-01-10T12:00:10Z" || claim.RegisteredAt != "2025-01-10T12:00:40Z" || claim.ReadyAt != "" {
+		t.Errorf("unexpected timeline: %+v", claim)
+	}
+	if claim.Message != "Resources not registered" {
+		t.Errorf("expected failing condition message, got %q", claim.Message)
+	}
+
+	unstructured.RemoveNestedField(u.Object, "status")
+	if got := ParseKarpenterNodeClaim(u).Phase; got != "Launching" {
+		t.Errorf("claim without conditions should be Launching, got %s", got)
+	}
+}
+
+func TestCorrelatePendingPods(t *testing.T) {
+	now := time.Now()
+	pendingPod := func(ns, name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				Conditions: []corev1.PodCondition{{
+					Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable,
+					Message: "0/3 nodes are available: 3 Insufficient cpu.", LastTransitionTime: metav1.NewTime(now.Add(-time.Minute)),
+				}},
+			},
+		}
+	}
+	podEvent := func(ns, name, component, reason, msg string, at time.Time) *corev1.Event {
+		return &corev1.Event{
+			Reason: reason, Message: msg, Type: corev1.EventTypeNormal, Source: corev1.EventSource{Component: component},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: ns, Name: name}, LastTimestamp: metav1.NewTime(at),
+		}
+	}
+
+	pods := []*corev1.Pod{
+		pendingPod("app", "web-1"),
+		pendingPod("app", "web-2"),
+		pendingPod("app", "web-3"),
+		pendingPod("other", "job-1"),
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "running"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+	}
+	events := []*corev1.Event{
+		podEvent("app", "web-1", "cluster-autoscaler", "NotTriggerScaleUp", "pod didn't trigger scale-up", now.Add(-50*time.Second)),
+		podEvent("app", "web-1", "cluster-autoscaler", "TriggeredScaleUp", "pod triggered scale-up: [{ng-workers 2->4 (max: 10)}]", now),
+		podEvent("app", "web-2", "karpenter", "Nominated", "Pod should schedule on: nodeclaim/default-x7k2p", now),
+		podEvent("app", "web-3", "default-scheduler", "FailedScheduling", "0/3 nodes are available", now),
+	}
+	claims := []KarpenterNodeClaim{{Name: "default-x7k2p", Phase: "Registering"}}
+
+	got := correlatePendingPods(pods, events, claims, []string{"app"})
+	if len(got) != 3 {
+		t.Fatalf("expected 3 pending pods in namespace app, got %+v", got)
+	}
+	byName := map[string]PendingPodScaling{}
+	for _, p := range got {
+		byName[p.Name] = p
+	}
+	if p := byName["web-1"]; p.Decision != "scale-up-triggered" || p.Reason == "" || p.PendingSince == "" {
+		t.Errorf("latest event should win for web-1: %+v", p)
+	}
+	if p := byName["web-2"]; p.Decision != "nodeclaim-launching" || p.NodeClaim != "default-x7k2p" || p.DecisionMessage != "NodeClaim default-x7k2p is Registering" {
+		t.Errorf("unexpected web-2: %+v", p)
+	}
+	if p := byName["web-3"]; p.Decision != "waiting" {
+		t.Errorf("scheduler events shouldn't count as autoscaler decisions: %+v", p)
+	}
+}
@@ -0,0 +1,403 @@
+package k8s
+
+import (
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Search match weights. Name matches dominate so that "api" ranks the api
+// Deployment above everything that merely carries app=api.
+const (
+	scoreNameExact     = 100
+	scoreNamePrefix    = 60
+	scoreNameContains  = 40
+	scoreLabelExact    = 30
+	scoreLabelContains = 15
+	scoreImage         = 25
+	scoreEnvName       = 20
+	scoreAnnotation    = 10
+)
+
+// SearchOptions controls a cross-kind resource search
+type SearchOptions struct {
+	Query      string
+	Namespaces []string // Empty = all namespaces
+	Kinds      []string // Empty = all kinds (case-insensitive Kind names, e.g. "Deployment")
+	Limit      int
+}
+
+// SearchMatch describes which field of a resource matched the query
+type SearchMatch struct {
+	Field string `json:"field"` // name, label, annotation, image, env
+	Key   string `json:"key,omitempty"`
+	Value string `json:"value"`
+}
+
+// SearchResult is a single ranked search hit
+type SearchResult struct {
+	Kind      string        `json:"kind"`
+	Group     string        `json:"group,omitempty"`
+	Namespace string        `json:"namespace,omitempty"`
+	Name      string        `json:"name"`
+	Score     int           `json:"score"`
+	Matches   []SearchMatch `json:"matches"`
+}
+
+// SearchResponse holds ranked results plus per-kind facet counts
+type SearchResponse struct {
+	Query   string         `json:"query"`
+	Total   int            `json:"total"`
+	Results []SearchResult `json:"results"`
+	Facets  map[string]int `json:"facets"`
+}
+
+// searchDoc is the searchable projection of a cached resource
+type searchDoc struct {
+	kind        string
+	group       string
+	namespace   string
+	name        string
+	labels      map[string]string
+	annotations map[string]string
+	containers  []corev1.Container
+}
+
+// typedSearchGroups lists the API groups served by the typed cache, so the
+// same resources are not indexed twice when also watched dynamically.
+var typedSearchGroups = map[string]bool{
+	"": true, "apps": true, "batch": true, "networking.k8s.io": true, "autoscaling": true,
+}
+
+// SearchResources searches all cached resources (typed and dynamic) by name,
+// label values, annotations, container images and env var names. Every query
+// term must match somewhere on a resource for it to be returned.
+func SearchResources(opts SearchOptions) *SearchResponse {
+	resp := &SearchResponse{
+		Query:   opts.Query,
+		Results: []SearchResult{},
+		Facets:  map[string]int{},
+	}
+
+	terms := strings.Fields(strings.ToLower(opts.Query))
+	if len(terms) == 0 {
+		return resp
+	}
+
+	nsFilter := make(map[string]bool, len(opts.Namespaces))
+	for _, ns := range opts.Namespaces {
+		nsFilter[ns] = true
+	}
+	kindFilter := make(map[string]bool, len(opts.Kinds))
+	for _, k := range opts.Kinds {
+		kindFilter[strings.ToLower(k)] = true
+	}
+
+	visit := func(doc searchDoc) {
+		if len(nsFilter) > 0 && doc.namespace != "" && !nsFilter[doc.namespace] {
+			return
+		}
+		if len(kindFilter) > 0 && !kindFilter[strings.ToLower(doc.kind)] {
+			return
+		}
+		if result, ok := scoreDoc(doc, terms); ok {
+			resp.Results = append(resp.Results, result)
+			resp.Facets[doc.kind]++
+		}
+	}
+
+	collectTypedSearchDocs(GetResourceCache(), visit)
+	collectDynamicSearchDocs(GetDynamicResourceCache(), visit)
+
+	sort.SliceStable(resp.Results, func(i, j int) bool {
+		a, b := resp.Results[i], resp.Results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	resp.Total = len(resp.Results)
+	if opts.Limit > 0 && len(resp.Results) > opts.Limit {
+		resp.Results = resp.Results[:opts.Limit]
+	}
+	return resp
+}
+
+// scoreDoc scores a document against all terms. Returns false if any term
+// has no match.
+func scoreDoc(doc searchDoc, terms []string) (SearchResult, bool) {
+	result := SearchResult{
+		Kind:      doc.kind,
+		Group:     doc.group,
+		Namespace: doc.namespace,
+		Name:      doc.name,
+	}
+	name := strings.ToLower(doc.name)
+
+	for _, term := range terms {
+		termScore := 0
+		addMatch := func(score int, m SearchMatch) {
+			termScore += score
+			result.Matches = append(result.Matches, m)
+		}
+
+		switch {
+		case name == term:
+			addMatch(scoreNameExact, SearchMatch{Field: "name", Value: doc.name})
+		case strings.HasPrefix(name, term):
+			addMatch(scoreNamePrefix, SearchMatch{Field: "name", Value: doc.name})
+		case strings.Contains(name, term):
+			addMatch(scoreNameContains, SearchMatch{Field: "name", Value: doc.name})
+		}
+
+		for k, v := range doc.labels {
+			lv := strings.ToLower(v)
+			if lv == term {
+				addMatch(scoreLabelExact, SearchMatch{Field: "label", Key: k, Value: v})
+			} else if strings.Contains(lv, term) {
+				addMatch(scoreLabelContains, SearchMatch{Field: "label", Key: k, Value: v})
+			}
+		}
+
+		for k, v := range doc.annotations {
+			if strings.Contains(strings.ToLower(k), term) || strings.Contains(strings.ToLower(v), term) {
+				addMatch(scoreAnnotation, SearchMatch{Field: "annotation", Key: k, Value: truncateSearchValue(v)})
+			}
+		}
+
+		for _, c := range doc.containers {
+			if strings.Contains(strings.ToLower(c.Image), term) {
+				addMatch(scoreImage, SearchMatch{Field: "image", Key: c.Name, Value: c.Image})
+			}
+			for _, env := range c.Env {
+				if strings.Contains(strings.ToLower(env.Name), term) {
+					addMatch(scoreEnvName, SearchMatch{Field: "env", Key: c.Name, Value: env.Name})
+				}
+			}
+		}
+
+		if termScore == 0 {
+			return SearchResult{}, false
+		}
+		result.Score += termScore
+	}
+
+	return result, true
+}
+
+// truncateSearchValue keeps long annotation values (e.g. embedded JSON) from
+// bloating the response
+func truncateSearchValue(v string) string {
+	const max = 200
+	if len(v) > max {
+		return runePrefix(v, max) + "…"
+	}
+	return v
+}
+
+// newSearchDoc builds a searchDoc from any object with ObjectMeta
+func newSearchDoc(kind, group string, obj any, containers []corev1.Container) (searchDoc, bool) {
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return searchDoc{}, false
+	}
+	return searchDoc{
+		kind:        kind,
+		group:       group,
+		namespace:   m.GetNamespace(),
+		name:        m.GetName(),
+		labels:      m.GetLabels(),
+		annotations: m.GetAnnotations(),
+		containers:  containers,
+	}, true
+}
+
+func withInitContainers(spec corev1.PodSpec) []corev1.Container {
+	if len(spec.InitContainers) == 0 {
+		return spec.Containers
+	}
+	all := make([]corev1.Container, 0, len(spec.Containers)+len(spec.InitContainers))
+	all = append(all, spec.InitContainers...)
+	return append(all, spec.Containers...)
+}
+
+// collectTypedSearchDocs feeds every resource in the typed cache to visit.
+// Events are skipped (too noisy); Secrets are indexed by metadata only.
+func collectTypedSearchDocs(c *ResourceCache, visit func(searchDoc)) {
+	if c == nil {
+		return
+	}
+	all := labels.Everything()
+
+	emit := func(kind, group string, obj any, containers []corev1.Container) {
+		if doc, ok := newSearchDoc(kind, group, obj, containers); ok {
+			visit(doc)
+		}
+	}
+
+	if l := c.Pods(); l != nil {
+		items, _ := l.List(all)
+		for _, o := range items {
+			emit("Pod", "", o, withInitContainers(o.Spec))
+		}
+	}
+	if l := c.Deployments(); l != nil {
+		items, _ := l.List(all)
+		for _, o := range items {
+			emit("Deployment", "apps", o, withInitContainers(o.Spec.Template.Spec))
+		}
+	}
+	if l := c.StatefulSets(); l != nil {
+		items, _ := l.List(all)
+		for _, o := range items {
+			emit("StatefulSet", "apps", o, withInitContainers(o.Spec.Template.Spec))
+		}
+	}
+	if l := c.DaemonSets(); l != nil {
+		items, _ := l.List(all)
+		for _, o := range items {
+			emit("DaemonSet", "apps", o, withInitContainers(o.Spec.Template.Spec))
+		}
+	}
+	if l := c.ReplicaSets(); l != nil {
+		items, _ := l.List(all)
+		for _, o := range items {
+			emit("ReplicaSet", "apps", o, withInitContainers(o.Spec.Template.Spec))
+		}
+	}
+	if l := c.Jobs(); l != nil {
+		items, _ := l.List(all)
+		for _, o := range items {
+			emit("Job", "batch", o, withInitContainers(o.Spec.Template.Spec))
+		}
+	}
+	if l := c.CronJobs(); l != nil {
+		items, _ := l.List(all)
+		for _, o := range items {
+			emit("CronJob", "batch", o, withInitContainers(o.Spec.JobTemplate.Spec.Template.Spec))
+		}
+	}
+	if l := c.Services(); l != nil {
+		items, _ := l.List(all)
+		for _, o := range items {
+			emit("Service", "", o, nil)
+		}
+	}
+	if l := c.Ingresses(); l != nil {
+		items, _ := l.List(all)
+		for _, o := range items {
+			emit("Ingress", "networking.k8s.io", o, nil)
+		}
+	}
+	if l := c.ConfigMaps(); l != nil {
+		items, _ := l.List(all)
+		for _, o := range items {
+			emit("ConfigMap", "", o, nil)
+		}
+	}
+	if l := c.Secrets(); l != nil {
+		items, _ := l.List(all)
+		for _, o := range items {
+			emit("Secret", "", o, nil)
+		}
+	}
+	if l := c.PersistentVolumeClaims(); l != nil {
+		items, _ := l.List(all)
+		for _, o := range items {
+			emit("PersistentVolumeClaim", "", o, nil)
+		}
+	}
+	if l := c.HorizontalPodAutoscalers(); l != nil {
+		items, _ := l.List(all)
+		for _, o := range items {
+			emit("HorizontalPodAutoscaler", "autoscaling", o, nil)
+		}
+	}
+	if l := c.Nodes(); l != nil {
+		items, _ := l.List(all)
+		for _, o := range items {
+			emit("Node", "", o, nil)
+		}
+	}
+	if l := c.Namespaces(); l != nil {
+		items, _ := l.List(all)
+		for _, o := range items {
+			emit("Namespace", "", o, nil)
+		}
+	}
+}
+
+// collectDynamicSearchDocs feeds every resource held by dynamic informers to visit
+func collectDynamicSearchDocs(d *DynamicResourceCache, visit func(searchDoc)) {
+	if d == nil {
+		return
+	}
+	for _, gvr := range d.GetWatchedResources() {
+		if typedSearchGroups[gvr.Group] && IsKnownKind(gvr.Resource) {
+			continue
+		}
+		items, err := d.List(gvr, "")
+		if err != nil {
+			continue
+		}
+		kind := gvrToKind(gvr)
+		for _, u := range items {
+			if doc, ok := newSearchDoc(kind, gvr.Group, u, unstructuredContainers(u)); ok {
+				visit(doc)
+			}
+		}
+	}
+}
+
+// podSpecPaths are the common locations of a pod spec inside workload-like CRDs
+// (Argo Rollouts, Knative, Jobs embedded in CRDs, etc.)
+var podSpecPaths = [][]string{
+	{"spec"},
+	{"spec", "template", "spec"},
+	{"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// unstructuredContainers extracts container name/image/env from well-known pod
+// spec locations of an unstructured object
+func unstructuredContainers(u *unstructured.Unstructured) []corev1.Container {
+	var result []corev1.Container
+	for _, path := range podSpecPaths {
+		for _, field := range []string{"initContainers", "containers"} {
+			list, found, err := unstructured.NestedSlice(u.Object, append(append([]string{}, path...), field)...)
+			if err != nil || !found {
+				continue
+			}
+			for _, item := range list {
+				m, ok := item.(map[string]any)
+				if !ok {
+					continue
+				}
+				c := corev1.Container{}
+				c.Name, _ = m["name"].(string)
+				c.Image, _ = m["image"].(string)
+				if envs, ok := m["env"].([]any); ok {
+					for _, e := range envs {
+						if em, ok := e.(map[string]any); ok {
+							if n, ok := em["name"].(string); ok {
+								c.Env = append(c.Env, corev1.EnvVar{Name: n})
+							}
+						}
+					}
+				}
+				result = append(result, c)
+			}
+		}
+	}
+	return result
+}
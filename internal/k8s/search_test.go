@@ -0,0 +1,59 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestScoreDoc(t *testing.T) {
+	doc := searchDoc{
+		kind:      "Deployment",
+		namespace: "payments",
+		name:      "checkout-api",
+		labels:    map[string]string{"team": "payments"},
+		containers: []corev1.Container{{
+			Name:  "app",
+			Image: "ghcr.io/acme/checkout:1.4.2",
+			Env:   []corev1.EnvVar{{Name: "FEATURE_X"}},
+		}},
+	}
+
+	tests := []struct {
+		name      string
+		terms     []string
+		wantMatch bool
+		wantScore int
+	}{
+		{"name prefix", []string{"checkout"}, true, scoreNamePrefix + scoreImage},
+		{"exact name", []string{"checkout-api"}, true, scoreNameExact},
+		{"env var name", []string{"feature_x"}, true, scoreEnvName},
+		{"label value", []string{"payments"}, true, scoreLabelExact},
+		{"all terms must match", []string{"checkout", "missing"}, false, 0},
+		{"no match", []string{"redis"}, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := scoreDoc(doc, tt.terms)
+			if ok != tt.wantMatch {
+				t.Fatalf("scoreDoc() matched = %v, want %v", ok, tt.wantMatch)
+			}
+			if ok && result.Score != tt.wantScore {
+				t.Errorf("scoreDoc() score = %d, want %d (matches: %+v)", result.Score, tt.wantScore, result.Matches)
+			}
+		})
+	}
+}
+
+func TestTruncateSearchValue(t *testing.T) {
+	// The 200-byte cut would land inside "é"; it's dropped whole
+	v := strings.Repeat("a", 199) + "é" + "tail"
+	if got := truncateSearchValue(v); !utf8.ValidString(got) || got != strings.Repeat("a", 199)+"…" {
+		t.Errorf("unexpected truncation %q", got)
+	}
+	if got := truncateSearchValue("short"); got != "short" {
+		t.Errorf("short values should be kept, got %q", got)
+	}
+}
@@ -0,0 +1,126 @@
+package k8s
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	maxSecretValueChanges = 5   // Kept per Secret, newest last
+	maxSecretValueSecrets = 500 // Secrets with kept changes; the least recently changed is evicted
+)
+
+// SecretValueChange is one Secret update. Timeline and SSE diffs only carry
+// key names; these keep the values, in memory only, for an explicit reveal.
+type SecretValueChange struct {
+	Time    time.Time     `json:"time"`
+	Fields  []FieldChange `json:"fields"`
+	Summary string        `json:"summary"`
+}
+
+// ErrSecretsNotAccessible is returned when RBAC doesn't allow reading Secrets
+var ErrSecretsNotAccessible = errors.New("secrets are not accessible with the current credentials")
+
+type secretValueDiffStore struct {
+	mu       sync.Mutex
+	bySecret map[string][]SecretValueChange
+	order    []string // Secret keys, least recently changed first
+}
+
+var secretValueDiffs = &secretValueDiffStore{bySecret: make(map[string][]SecretValueChange)}
+
+// secretValueDiff diffs Secret data like diffSecret but keeps the (truncated) values
+func secretValueDiff(oldSecret, newSecret *corev1.Secret) ([]FieldChange, []string) {
+	return diffDataKeys("data", oldSecret.Data, newSecret.Data, func(v []byte) any {
+		return truncateDataValue(string(v))
+	})
+}
+
+// record keeps the value diff of a Secret update, if its data changed
+func (s *secretValueDiffStore) record(oldObj, newObj any, now time.Time) {
+	oldSecret, ok1 := oldObj.(*corev1.Secret)
+	newSecret, ok2 := newObj.(*corev1.Secret)
+	if !ok1 || !ok2 {
+		return
+	}
+	fields, summary := secretValueDiff(oldSecret, newSecret)
+	if len(fields) == 0 {
+		return
+	}
+	key := newSecret.Namespace + "/" + newSecret.Name
+	change := SecretValueChange{Time: now, Fields: fields, Summary: strings.Join(summary, ", ")}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeOrderLocked(key)
+	s.order = append(s.order, key)
+	changes := append(s.bySecret[key], change)
+	if len(changes) > maxSecretValueChanges {
+		changes = changes[len(changes)-maxSecretValueChanges:]
+	}
+	s.bySecret[key] = changes
+	if len(s.order) > maxSecretValueSecrets {
+		delete(s.bySecret, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+// forget drops the kept changes of a deleted Secret
+func (s *secretValueDiffStore) forget(namespace, name string) {
+	key := namespace + "/" + name
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.bySecret, key)
+	s.removeOrderLocked(key)
+}
+
+func (s *secretValueDiffStore) removeOrderLocked(key string) {
+	for i, k := range s.order {
+		if k == key {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *secretValueDiffStore) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bySecret = make(map[string][]SecretValueChange)
+	s.order = nil
+}
+
+// get returns the kept changes of a Secret, oldest first, with values
+// replaced by markers unless reveal is set
+func (s *secretValueDiffStore) get(namespace, name string, reveal bool) []SecretValueChange {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := []SecretValueChange{}
+	for _, c := range s.bySecret[namespace+"/"+name] {
+		c.Fields = append([]FieldChange{}, c.Fields...)
+		if !reveal {
+			redactSecretValues(c.Fields)
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// GetSecretValueChanges returns the recent data changes of a Secret seen by
+// the cache since startup. Values are only included when reveal is set; the
+// caller is responsible for auditing reveals.
+func GetSecretValueChanges(namespace, name string, reveal bool) ([]SecretValueChange, error) {
+	cache := GetResourceCache()
+	if cache == nil {
+		return nil, fmt.Errorf("resource cache not initialized")
+	}
+	if cache.Secrets() == nil {
+		return nil, ErrSecretsNotAccessible
+	}
+	return secretValueDiffs.get(namespace, name, reveal), nil
+}
@@ -0,0 +1,637 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Finding severities, most severe first
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// ErrUnsupportedClientKind is returned when a ServiceClientRef names a kind without pods
+var ErrUnsupportedClientKind = errors.New("unsupported client kind: must be Pod, Deployment, StatefulSet, or DaemonSet")
+
+var severityRank = map[string]int{SeverityError: 0, SeverityWarning: 1, SeverityInfo: 2}
+
+// ServiceClientRef identifies the workload (or pod) expected to reach a Service
+type ServiceClientRef struct {
+	Kind      string `json:"kind"` // Pod, Deployment, StatefulSet, DaemonSet
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// ServiceFinding is a single likely misconfiguration found while diagnosing a Service
+type ServiceFinding struct {
+	Severity string   `json:"severity"` // error, warning, info
+	Check    string   `json:"check"`    // selector, readiness, endpoints, targetPort, networkPolicy
+	Message  string   `json:"message"`
+	Details  []string `json:"details,omitempty"`
+}
+
+// ServiceDiagnosis is the result of DiagnoseService. Findings are ranked most severe first.
+type ServiceDiagnosis struct {
+	Namespace      string            `json:"namespace"`
+	Name           string            `json:"name"`
+	Type           string            `json:"type"`
+	Selector       map[string]string `json:"selector,omitempty"`
+	MatchingPods   int               `json:"matchingPods"`
+	ReadyPods      int               `json:"readyPods"`
+	ReadyEndpoints int               `json:"readyEndpoints"`
+	Client         *ServiceClientRef `json:"client,omitempty"`
+	ClientPods     int               `json:"clientPods,omitempty"`
+	Healthy        bool              `json:"healthy"` // No error or warning findings
+	Findings       []ServiceFinding  `json:"findings"`
+}
+
+// serviceDiagnosticInput holds everything the checks need, so they can run without a cluster
+type serviceDiagnosticInput struct {
+	service    *corev1.Service
+	pods       []*corev1.Pod // Pods in the service's namespace
+	slices     []discoveryv1.EndpointSlice
+	noSlices   bool                         // EndpointSlices couldn't be read (e.g. RBAC); endpoint checks are skipped
+	policies   []networkingv1.NetworkPolicy // Policies in the service and client namespaces
+	noPolicies bool                         // NetworkPolicies couldn't be read (e.g. RBAC); policy checks are skipped
+	nsLabels   map[string]map[string]string
+	client     *ServiceClientRef
+	clientPods []*corev1.Pod
+}
+
+// DiagnoseService checks a Service's selector, endpoint readiness, targetPort
+// alignment, and (when a client is given) whether NetworkPolicies would block
+// the client from reaching it.
+func DiagnoseService(ctx context.Context, namespace, name string, clientRef *ServiceClientRef) (*ServiceDiagnosis, error) {
+	cache := GetResourceCache()
+	if cache == nil || cache.Services() == nil || cache.Pods() == nil {
+		return nil, fmt.Errorf("resource cache not initialized")
+	}
+	client := GetClient()
+	if client == nil {
+		return nil, fmt.Errorf("kubernetes client not initialized")
+	}
+
+	svc, err := cache.Services().Services(namespace).Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+
+	pods, err := cache.Pods().Pods(namespace).List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	in := &serviceDiagnosticInput{
+		service:  svc,
+		pods:     pods,
+		nsLabels: make(map[string]map[string]string),
+		client:   clientRef,
+	}
+
+	slices, err := client.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + name,
+	})
+	switch {
+	case apierrors.IsForbidden(err):
+		in.noSlices = true
+	case err != nil:
+		return nil, fmt.Errorf("failed to list endpoint slices: %w", err)
+	default:
+		in.slices = slices.Items
+	}
+
+	if clientRef != nil {
+		if clientRef.Namespace == "" {
+			clientRef.Namespace = namespace
+		}
+		in.clientPods, err = resolveClientPods(cache, clientRef)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// NetworkPolicies are read directly; they aren't part of the typed cache
+	policyNamespaces := []string{namespace}
+	if clientRef != nil && clientRef.Namespace != namespace {
+		policyNamespaces = append(policyNamespaces, clientRef.Namespace)
+	}
+	for _, ns := range policyNamespaces {
+		list, err := client.NetworkingV1().NetworkPolicies(ns).List(ctx, metav1.ListOptions{})
+		if apierrors.IsForbidden(err) {
+			in.noPolicies, in.policies = true, nil
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list network policies: %w", err)
+		}
+		in.policies = append(in.policies, list.Items...)
+	}
+
+	if cache.Namespaces() != nil {
+		if nsList, err := cache.Namespaces().List(labels.Everything()); err == nil {
+			for _, ns := range nsList {
+				in.nsLabels[ns.Name] = ns.Labels
+			}
+		}
+	}
+
+	return diagnoseService(in), nil
+}
+
+// resolveClientPods returns the pods backing a client reference
+func resolveClientPods(cache *ResourceCache, ref *ServiceClientRef) ([]*corev1.Pod, error) {
+	var selector *metav1.LabelSelector
+	switch strings.ToLower(ref.Kind) {
+	case "pod", "pods":
+		pod, err := cache.Pods().Pods(ref.Namespace).Get(ref.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get client pod: %w", err)
+		}
+		return []*corev1.Pod{pod}, nil
+	case "deployment", "deployments":
+		if cache.Deployments() == nil {
+			return nil, fmt.Errorf("deployments not available in cache")
+		}
+		d, err := cache.Deployments().Deployments(ref.Namespace).Get(ref.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get client deployment: %w", err)
+		}
+		selector = d.Spec.Selector
+	case "statefulset", "statefulsets":
+		if cache.StatefulSets() == nil {
+			return nil, fmt.Errorf("statefulsets not available in cache")
+		}
+		s, err := cache.StatefulSets().StatefulSets(ref.Namespace).Get(ref.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get client statefulset: %w", err)
+		}
+		selector = s.Spec.Selector
+	case "daemonset", "daemonsets":
+		if cache.DaemonSets() == nil {
+			return nil, fmt.Errorf("daemonsets not available in cache")
+		}
+		ds, err := cache.DaemonSets().DaemonSets(ref.Namespace).Get(ref.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get client daemonset: %w", err)
+		}
+		selector = ds.Spec.Selector
+	default:
+		return nil, fmt.Errorf("%w (got %q)", ErrUnsupportedClientKind, ref.Kind)
+	}
+	return cache.GetPodsForWorkload(ref.Namespace, selector), nil
+}
+
+// diagnoseService runs all checks against pre-fetched data
+func diagnoseService(in *serviceDiagnosticInput) *ServiceDiagnosis {
+	svc := in.service
+	d := &ServiceDiagnosis{
+		Namespace:  svc.Namespace,
+		Name:       svc.Name,
+		Type:       string(svc.Spec.Type),
+		Selector:   svc.Spec.Selector,
+		Client:     in.client,
+		ClientPods: len(in.clientPods),
+		Findings:   []ServiceFinding{},
+	}
+	add := func(severity, check, message string, details ...string) {
+		d.Findings = append(d.Findings, ServiceFinding{Severity: severity, Check: check, Message: message, Details: details})
+	}
+
+	d.ReadyEndpoints = countReadyEndpoints(in.slices)
+
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		add(SeverityInfo, "selector", fmt.Sprintf("ExternalName service resolves to %s via DNS; no endpoints or kube-proxy rules are involved", svc.Spec.ExternalName))
+		d.Healthy = true
+		return d
+	}
+
+	if in.noSlices {
+		add(SeverityInfo, "endpoints", "EndpointSlices are not readable with current permissions; endpoint checks skipped")
+	}
+
+	var matching []*corev1.Pod
+	if len(svc.Spec.Selector) == 0 {
+		if in.noSlices {
+			add(SeverityInfo, "selector", "Service has no selector; endpoints are managed manually")
+		} else if d.ReadyEndpoints == 0 {
+			add(SeverityError, "endpoints", "Service has no selector and no ready endpoints; endpoints must be managed manually (EndpointSlice with kubernetes.io/service-name label)")
+		} else {
+			add(SeverityInfo, "selector", fmt.Sprintf("Service has no selector; routing to %d manually managed endpoint(s)", d.ReadyEndpoints))
+		}
+	} else {
+		sel := labels.SelectorFromSet(svc.Spec.Selector)
+		for _, pod := range in.pods {
+			if pod.DeletionTimestamp == nil && sel.Matches(labels.Set(pod.Labels)) {
+				matching = append(matching, pod)
+			}
+		}
+		d.MatchingPods = len(matching)
+
+		if len(matching) == 0 {
+			add(SeverityError, "selector", "Selector matches no pods", nearMissSelectorDetails(svc.Spec.Selector, in.pods)...)
+		} else {
+			var notReady []string
+			for _, pod := range matching {
+				if isPodReady(pod) {
+					d.ReadyPods++
+				} else {
+					notReady = append(notReady, fmt.Sprintf("%s (%s)", pod.Name, podNotReadyReason(pod)))
+				}
+			}
+			switch {
+			case d.ReadyPods == 0:
+				add(SeverityError, "readiness", fmt.Sprintf("None of the %d matching pods are ready; traffic has nowhere to go", len(matching)), notReady...)
+			case len(notReady) > 0:
+				add(SeverityWarning, "readiness", fmt.Sprintf("%d of %d matching pods are not ready and receive no traffic", len(notReady), len(matching)), notReady...)
+			}
+
+			if d.ReadyPods > 0 && d.ReadyEndpoints == 0 && !in.noSlices && !svc.Spec.PublishNotReadyAddresses {
+				add(SeverityWarning, "endpoints", "Ready pods match the selector but the Service has no ready endpoints; the endpoint controller may be lagging or EndpointSlices were modified manually")
+			}
+		}
+
+		checkTargetPorts(svc, matching, add)
+	}
+
+	if in.noPolicies {
+		add(SeverityInfo, "networkPolicy", "NetworkPolicies are not readable with current permissions; policy checks skipped")
+	} else if in.client != nil {
+		if len(in.clientPods) == 0 {
+			add(SeverityWarning, "networkPolicy", fmt.Sprintf("Client %s %s/%s has no pods; NetworkPolicy evaluation skipped", in.client.Kind, in.client.Namespace, in.client.Name))
+		} else if len(matching) > 0 {
+			checkNetworkPolicies(in, matching, add)
+		}
+	} else if len(matching) > 0 {
+		if isolating := policiesSelecting(in.policies, svc.Namespace, matching[0], networkingv1.PolicyTypeIngress); len(isolating) > 0 {
+			add(SeverityInfo, "networkPolicy", fmt.Sprintf("Ingress to the Service's pods is restricted by %d NetworkPolicy(s); pass a client workload to check whether it is allowed", len(isolating)), policyNames(isolating)...)
+		}
+	}
+
+	sort.SliceStable(d.Findings, func(i, j int) bool {
+		return severityRank[d.Findings[i].Severity] < severityRank[d.Findings[j].Severity]
+	})
+	d.Healthy = true
+	for _, f := range d.Findings {
+		if f.Severity != SeverityInfo {
+			d.Healthy = false
+			break
+		}
+	}
+	return d
+}
+
+// nearMissSelectorDetails lists pods matching all but one selector label, which
+// usually points at a typo or a stale label value.
+func nearMissSelectorDetails(selector map[string]string, pods []*corev1.Pod) []string {
+	if len(selector) < 2 {
+		var details []string
+		for k, v := range selector {
+			values := make(map[string]bool)
+			for _, pod := range pods {
+				if pv, ok := pod.Labels[k]; ok && pv != v {
+					values[pv] = true
+				}
+			}
+			if len(values) > 0 {
+				details = append(details, fmt.Sprintf("pods have %s=%s, selector expects %s=%s", k, strings.Join(sortedKeys(values), "|"), k, v))
+			}
+		}
+		return details
+	}
+
+	seen := make(map[string]bool)
+	var details []string
+	for _, pod := range pods {
+		var mismatch string
+		misses := 0
+		for k, v := range selector {
+			if pod.Labels[k] != v {
+				misses++
+				if pv, ok := pod.Labels[k]; ok {
+					mismatch = fmt.Sprintf("%s=%s (selector expects %s=%s)", k, pv, k, v)
+				} else {
+					mismatch = fmt.Sprintf("missing label %s (selector expects %s=%s)", k, k, v)
+				}
+			}
+		}
+		if misses == 1 && !seen[mismatch] {
+			seen[mismatch] = true
+			details = append(details, fmt.Sprintf("pod %s matches all labels but %s", pod.Name, mismatch))
+			if len(details) >= 5 {
+				break
+			}
+		}
+	}
+	return details
+}
+
+// checkTargetPorts verifies each Service port's targetPort is exposed by the backing pods
+func checkTargetPorts(svc *corev1.Service, pods []*corev1.Pod, add func(severity, check, message string, details ...string)) {
+	if len(pods) == 0 {
+		return
+	}
+	for _, sp := range svc.Spec.Ports {
+		target := sp.TargetPort
+		if target.Type == intstr.Int && target.IntVal == 0 {
+			target = intstr.FromInt32(sp.Port)
+		}
+		portLabel := sp.Name
+		if portLabel == "" {
+			portLabel = fmt.Sprintf("%d", sp.Port)
+		}
+
+		var missing []string
+		for _, pod := range pods {
+			if _, ok := resolveContainerPort(pod, target, sp.Protocol); !ok {
+				missing = append(missing, pod.Name)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		if target.Type == intstr.String {
+			// Named ports must resolve or kube-proxy drops the endpoint for that port
+			add(SeverityError, "targetPort",
+				fmt.Sprintf("Port %s targets named port %q, which %d of %d pods don't define", portLabel, target.StrVal, len(missing), len(pods)),
+				missing...)
+			continue
+		}
+
+		// Numeric targetPorts work even if undeclared, but a mismatch with every
+		// declared containerPort is a common cause of connection refused
+		declared := declaredContainerPorts(pods[0])
+		detail := fmt.Sprintf("containerPorts declared on %s: %s", pods[0].Name, declared)
+		if declared == "" {
+			detail = fmt.Sprintf("%s declares no containerPorts", pods[0].Name)
+		}
+		add(SeverityWarning, "targetPort",
+			fmt.Sprintf("Port %s targets %d/%s, which no container declares on %d of %d pods", portLabel, target.IntVal, protocolOrTCP(sp.Protocol), len(missing), len(pods)),
+			detail)
+	}
+}
+
+// resolveContainerPort finds the container port in a pod that a targetPort refers to
+func resolveContainerPort(pod *corev1.Pod, target intstr.IntOrString, protocol corev1.Protocol) (int32, bool) {
+	protocol = protocolOrTCP(protocol)
+	for _, c := range pod.Spec.Containers {
+		for _, cp := range c.Ports {
+			if protocolOrTCP(cp.Protocol) != protocol {
+				continue
+			}
+			if target.Type == intstr.String && cp.Name == target.StrVal {
+				return cp.ContainerPort, true
+			}
+			if target.Type == intstr.Int && cp.ContainerPort == target.IntVal {
+				return cp.ContainerPort, true
+			}
+		}
+	}
+	if target.Type == intstr.Int {
+		return target.IntVal, false
+	}
+	return 0, false
+}
+
+func declaredContainerPorts(pod *corev1.Pod) string {
+	var ports []string
+	for _, c := range pod.Spec.Containers {
+		for _, cp := range c.Ports {
+			p := fmt.Sprintf("%d/%s", cp.ContainerPort, protocolOrTCP(cp.Protocol))
+			if cp.Name != "" {
+				p += " (" + cp.Name + ")"
+			}
+			ports = append(ports, p)
+		}
+	}
+	return strings.Join(ports, ", ")
+}
+
+func protocolOrTCP(p corev1.Protocol) corev1.Protocol {
+	if p == "" {
+		return corev1.ProtocolTCP
+	}
+	return p
+}
+
+// checkNetworkPolicies evaluates ingress policies on the target pods and egress
+// policies on the client pods for each Service port.
+func checkNetworkPolicies(in *serviceDiagnosticInput, targets []*corev1.Pod, add func(severity, check, message string, details ...string)) {
+	target := targets[0]
+	client := in.clientPods[0]
+	clientRef := fmt.Sprintf("%s %s/%s", in.client.Kind, in.client.Namespace, in.client.Name)
+
+	for _, sp := range in.service.Spec.Ports {
+		targetPort := sp.TargetPort
+		if targetPort.Type == intstr.Int && targetPort.IntVal == 0 {
+			targetPort = intstr.FromInt32(sp.Port)
+		}
+		portNum, _ := resolveContainerPort(target, targetPort, sp.Protocol)
+		protocol := protocolOrTCP(sp.Protocol)
+		portLabel := fmt.Sprintf("%d/%s", portNum, protocol)
+
+		ingress := policiesSelecting(in.policies, target.Namespace, target, networkingv1.PolicyTypeIngress)
+		if len(ingress) > 0 && !anyIngressAllows(ingress, in, client, target, portNum, protocol) {
+			add(SeverityError, "networkPolicy",
+				fmt.Sprintf("Ingress NetworkPolicies on the Service's pods do not allow %s on port %s", clientRef, portLabel),
+				policyNames(ingress)...)
+		}
+
+		egress := policiesSelecting(in.policies, client.Namespace, client, networkingv1.PolicyTypeEgress)
+		if len(egress) > 0 && !anyEgressAllows(egress, in, target, portNum, protocol) {
+			add(SeverityError, "networkPolicy",
+				fmt.Sprintf("Egress NetworkPolicies on %s do not allow traffic to the Service's pods on port %s", clientRef, portLabel),
+				policyNames(egress)...)
+		}
+	}
+}
+
+// policiesSelecting returns policies in namespace whose podSelector matches pod and that apply to policyType
+func policiesSelecting(policies []networkingv1.NetworkPolicy, namespace string, pod *corev1.Pod, policyType networkingv1.PolicyType) []networkingv1.NetworkPolicy {
+	var out []networkingv1.NetworkPolicy
+	for _, np := range policies {
+		if np.Namespace != namespace || !policyHasType(&np, policyType) {
+			continue
+		}
+		sel, err := metav1.LabelSelectorAsSelector(&np.Spec.PodSelector)
+		if err != nil || !sel.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		out = append(out, np)
+	}
+	return out
+}
+
+// policyHasType applies the API defaulting rules: Ingress always, Egress only if egress rules exist
+func policyHasType(np *networkingv1.NetworkPolicy, policyType networkingv1.PolicyType) bool {
+	if len(np.Spec.PolicyTypes) == 0 {
+		if policyType == networkingv1.PolicyTypeIngress {
+			return true
+		}
+		return len(np.Spec.Egress) > 0
+	}
+	for _, t := range np.Spec.PolicyTypes {
+		if t == policyType {
+			return true
+		}
+	}
+	return false
+}
+
+func anyIngressAllows(policies []networkingv1.NetworkPolicy, in *serviceDiagnosticInput, client, target *corev1.Pod, port int32, protocol corev1.Protocol) bool {
+	for _, np := range policies {
+		for _, rule := range np.Spec.Ingress {
+			if !portsAllow(rule.Ports, target, port, protocol) {
+				continue
+			}
+			if len(rule.From) == 0 || peersMatch(rule.From, np.Namespace, client, in.nsLabels) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func anyEgressAllows(policies []networkingv1.NetworkPolicy, in *serviceDiagnosticInput, target *corev1.Pod, port int32, protocol corev1.Protocol) bool {
+	for _, np := range policies {
+		for _, rule := range np.Spec.Egress {
+			if !portsAllow(rule.Ports, target, port, protocol) {
+				continue
+			}
+			if len(rule.To) == 0 || peersMatch(rule.To, np.Namespace, target, in.nsLabels) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// portsAllow reports whether a rule's port list admits the given port (empty list = all ports)
+func portsAllow(ports []networkingv1.NetworkPolicyPort, target *corev1.Pod, port int32, protocol corev1.Protocol) bool {
+	if len(ports) == 0 {
+		return true
+	}
+	for _, p := range ports {
+		if p.Protocol != nil && *p.Protocol != protocol {
+			continue
+		}
+		if p.Port == nil {
+			return true
+		}
+		if p.Port.Type == intstr.String {
+			if resolved, ok := resolveContainerPort(target, *p.Port, protocol); ok && resolved == port {
+				return true
+			}
+			continue
+		}
+		end := p.Port.IntVal
+		if p.EndPort != nil {
+			end = *p.EndPort
+		}
+		if port >= p.Port.IntVal && port <= end {
+			return true
+		}
+	}
+	return false
+}
+
+// peersMatch reports whether any peer selects the pod. ipBlock peers are ignored
+// since pod-to-pod traffic to cluster IPs is generally not matched by them.
+func peersMatch(peers []networkingv1.NetworkPolicyPeer, policyNamespace string, pod *corev1.Pod, nsLabels map[string]map[string]string) bool {
+	for _, peer := range peers {
+		if peer.PodSelector == nil && peer.NamespaceSelector == nil {
+			continue
+		}
+		if peer.NamespaceSelector != nil {
+			nsSel, err := metav1.LabelSelectorAsSelector(peer.NamespaceSelector)
+			if err != nil || !nsSel.Matches(namespaceLabelSet(pod.Namespace, nsLabels)) {
+				continue
+			}
+		} else if pod.Namespace != policyNamespace {
+			continue
+		}
+		if peer.PodSelector != nil {
+			podSel, err := metav1.LabelSelectorAsSelector(peer.PodSelector)
+			if err != nil || !podSel.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// namespaceLabelSet includes the immutable kubernetes.io/metadata.name label even if the cache lacks it
+func namespaceLabelSet(namespace string, nsLabels map[string]map[string]string) labels.Set {
+	set := labels.Set{"kubernetes.io/metadata.name": namespace}
+	for k, v := range nsLabels[namespace] {
+		set[k] = v
+	}
+	return set
+}
+
+func policyNames(policies []networkingv1.NetworkPolicy) []string {
+	names := make([]string, 0, len(policies))
+	for _, np := range policies {
+		names = append(names, "NetworkPolicy "+np.Namespace+"/"+np.Name)
+	}
+	return names
+}
+
+func countReadyEndpoints(slices []discoveryv1.EndpointSlice) int {
+	count := 0
+	for _, slice := range slices {
+		for _, ep := range slice.Endpoints {
+			if ep.Conditions.Ready == nil || *ep.Conditions.Ready {
+				count += len(ep.Addresses)
+			}
+		}
+	}
+	return count
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func podNotReadyReason(pod *corev1.Pod) string {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+			return cs.State.Waiting.Reason
+		}
+		if !cs.Ready && cs.State.Running != nil {
+			return "readiness probe failing"
+		}
+	}
+	if pod.Status.Phase != "" {
+		return string(pod.Status.Phase)
+	}
+	return "not ready"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,167 @@
+package k8s
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func diagTestPod(name string, podLabels map[string]string, ready bool, ports ...corev1.ContainerPort) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", Labels: podLabels},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Ports: ports}}},
+		Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}},
+	}
+}
+
+func diagTestService(targetPort intstr.IntOrString) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+		Spec: corev1.ServiceSpec{
+			Type:     corev1.ServiceTypeClusterIP,
+			Selector: map[string]string{"app": "api", "tier": "backend"},
+			Ports:    []corev1.ServicePort{{Name: "http", Port: 80, TargetPort: targetPort}},
+		},
+	}
+}
+
+func readySlice(addrs ...string) discoveryv1.EndpointSlice {
+	ready := true
+	return discoveryv1.EndpointSlice{Endpoints: []discoveryv1.Endpoint{{
+		Addresses:  addrs,
+		Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+	}}}
+}
+
+func findingChecks(d *ServiceDiagnosis) map[string]string {
+	out := make(map[string]string)
+	for _, f := range d.Findings {
+		if _, ok := out[f.Check]; !ok {
+			out[f.Check] = f.Severity
+		}
+	}
+	return out
+}
+
+func TestDiagnoseServiceHealthy(t *testing.T) {
+	httpPort := corev1.ContainerPort{Name: "http", ContainerPort: 8080}
+	d := diagnoseService(&serviceDiagnosticInput{
+		service: diagTestService(intstr.FromString("http")),
+		pods:    []*corev1.Pod{diagTestPod("api-1", map[string]string{"app": "api", "tier": "backend"}, true, httpPort)},
+		slices:  []discoveryv1.EndpointSlice{readySlice("10.0.0.1")},
+	})
+	if !d.Healthy || len(d.Findings) != 0 {
+		t.Fatalf("expected healthy with no findings, got %+v", d.Findings)
+	}
+	if d.MatchingPods != 1 || d.ReadyPods != 1 || d.ReadyEndpoints != 1 {
+		t.Errorf("unexpected counts: %+v", d)
+	}
+}
+
+func TestDiagnoseServiceSelectorNearMiss(t *testing.T) {
+	d := diagnoseService(&serviceDiagnosticInput{
+		service: diagTestService(intstr.FromInt32(8080)),
+		pods:    []*corev1.Pod{diagTestPod("api-1", map[string]string{"app": "api", "tier": "frontend"}, true)},
+	})
+	if d.Healthy {
+		t.Fatal("expected unhealthy")
+	}
+	f := d.Findings[0]
+	if f.Check != "selector" || f.Severity != SeverityError {
+		t.Fatalf("first finding = %+v, want selector error", f)
+	}
+	if len(f.Details) != 1 {
+		t.Fatalf("expected one near-miss detail, got %v", f.Details)
+	}
+}
+
+func TestDiagnoseServiceTargetPortAndReadiness(t *testing.T) {
+	sel := map[string]string{"app": "api", "tier": "backend"}
+	d := diagnoseService(&serviceDiagnosticInput{
+		service: diagTestService(intstr.FromString("web")),
+		pods: []*corev1.Pod{
+			diagTestPod("api-1", sel, true, corev1.ContainerPort{Name: "http", ContainerPort: 8080}),
+			diagTestPod("api-2", sel, false, corev1.ContainerPort{Name: "http", ContainerPort: 8080}),
+		},
+		slices: []discoveryv1.EndpointSlice{readySlice("10.0.0.1")},
+	})
+	checks := findingChecks(d)
+	if checks["targetPort"] != SeverityError {
+		t.Errorf("expected targetPort error for unresolved named port, got %v", checks)
+	}
+	if checks["readiness"] != SeverityWarning {
+		t.Errorf("expected readiness warning for partially ready pods, got %v", checks)
+	}
+	if d.Findings[0].Severity != SeverityError {
+		t.Errorf("findings not ranked by severity: %+v", d.Findings)
+	}
+}
+
+func TestDiagnoseServiceNetworkPolicy(t *testing.T) {
+	sel := map[string]string{"app": "api", "tier": "backend"}
+	target := diagTestPod("api-1", sel, true, corev1.ContainerPort{Name: "http", ContainerPort: 8080})
+	client := diagTestPod("web-1", map[string]string{"app": "web"}, true)
+	tcp := corev1.ProtocolTCP
+	port := intstr.FromInt32(8080)
+
+	allowFrom := func(podLabels map[string]string) networkingv1.NetworkPolicy {
+		return networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "allow", Namespace: "shop"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+				Ingress: []networkingv1.NetworkPolicyIngressRule{{
+					From:  []networkingv1.NetworkPolicyPeer{{PodSelector: &metav1.LabelSelector{MatchLabels: podLabels}}},
+					Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &port}},
+				}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		policy      networkingv1.NetworkPolicy
+		wantBlocked bool
+	}{
+		{"client allowed", allowFrom(map[string]string{"app": "web"}), false},
+		{"client not selected", allowFrom(map[string]string{"app": "admin"}), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := diagnoseService(&serviceDiagnosticInput{
+				service:    diagTestService(intstr.FromString("http")),
+				pods:       []*corev1.Pod{target},
+				slices:     []discoveryv1.EndpointSlice{readySlice("10.0.0.1")},
+				policies:   []networkingv1.NetworkPolicy{tt.policy},
+				client:     &ServiceClientRef{Kind: "Deployment", Namespace: "shop", Name: "web"},
+				clientPods: []*corev1.Pod{client},
+			})
+			blocked := findingChecks(d)["networkPolicy"] == SeverityError
+			if blocked != tt.wantBlocked {
+				t.Errorf("blocked = %v, want %v (findings: %+v)", blocked, tt.wantBlocked, d.Findings)
+			}
+		})
+	}
+}
+
+func TestDiagnoseServiceNetworkPoliciesNotPermitted(t *testing.T) {
+	sel := map[string]string{"app": "api", "tier": "backend"}
+	d := diagnoseService(&serviceDiagnosticInput{
+		service:    diagTestService(intstr.FromString("http")),
+		pods:       []*corev1.Pod{diagTestPod("api-1", sel, true, corev1.ContainerPort{Name: "http", ContainerPort: 8080})},
+		slices:     []discoveryv1.EndpointSlice{readySlice("10.0.0.1")},
+		noPolicies: true,
+		client:     &ServiceClientRef{Kind: "Deployment", Namespace: "shop", Name: "web"},
+		clientPods: []*corev1.Pod{diagTestPod("web-1", map[string]string{"app": "web"}, true)},
+	})
+	if !d.Healthy || findingChecks(d)["networkPolicy"] != SeverityInfo {
+		t.Errorf("expected the policy check to be skipped with a note, got %+v", d.Findings)
+	}
+}
@@ -0,0 +1,159 @@
+package k8s
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/client-go/tools/cache"
+)
+
+// StartupPhase is one timed initialization step
+type StartupPhase struct {
+	Name       string    `json:"name"`
+	Start      time.Time `json:"start"`
+	OffsetMs   int64     `json:"offsetMs"` // Since the trace started
+	DurationMs int64     `json:"durationMs"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// StartupTrace is the timing of the last (re)initialization. It starts when
+// the process starts and again on each context switch.
+type StartupTrace struct {
+	Enabled   bool           `json:"enabled"`
+	Context   string         `json:"context,omitempty"`
+	StartedAt time.Time      `json:"startedAt,omitzero"`
+	ReadyMs   *int64         `json:"readyMs,omitempty"` // Until subsystems finished initializing; nil while in progress
+	Running   []string       `json:"running"`           // Phases not yet finished, e.g. CRD discovery
+	Phases    []StartupPhase `json:"phases"`            // Sorted by start
+}
+
+type startupTracer struct {
+	enabled atomic.Bool
+	mu      sync.Mutex
+	started time.Time
+	ready   time.Time
+	running map[string]time.Time
+	phases  []StartupPhase
+}
+
+var startupTrace = &startupTracer{running: make(map[string]time.Time)}
+
+// EnableStartupTrace starts recording initialization phases (--startup-trace).
+// Call it before connecting so the trace covers the whole startup.
+func EnableStartupTrace() {
+	startupTrace.reset()
+	startupTrace.enabled.Store(true)
+}
+
+// TraceStartupPhase starts timing a phase and returns the function that ends
+// it with the phase's error, if any. It's a no-op unless tracing is enabled.
+func TraceStartupPhase(name string) func(err error) {
+	t := startupTrace
+	if !t.enabled.Load() {
+		return func(error) {}
+	}
+	start := time.Now()
+	t.mu.Lock()
+	t.running[name] = start
+	generation := t.started
+	t.mu.Unlock()
+
+	return func(err error) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if t.started != generation {
+			return // Ended after a context switch restarted the trace
+		}
+		delete(t.running, name)
+		phase := StartupPhase{
+			Name:       name,
+			Start:      start,
+			OffsetMs:   start.Sub(t.started).Milliseconds(),
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			phase.Error = err.Error()
+		}
+		t.phases = append(t.phases, phase)
+	}
+}
+
+// traceInformerSync times one informer's initial list. WaitForCacheSync
+// checks informers in order and stops at the first unsynced one, so each is
+// polled on its own.
+func traceInformerSync(inf cache.SharedIndexInformer, key string, stopCh <-chan struct{}) {
+	if !startupTrace.enabled.Load() {
+		return
+	}
+	end := TraceStartupPhase("informer-sync/" + key)
+	go func() {
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for !inf.HasSynced() {
+			select {
+			case <-stopCh:
+				return
+			case <-ticker.C:
+			}
+		}
+		end(nil)
+	}()
+}
+
+// markReady records that subsystem initialization finished
+func (t *startupTracer) markReady() {
+	if !t.enabled.Load() {
+		return
+	}
+	t.mu.Lock()
+	t.ready = time.Now()
+	t.mu.Unlock()
+}
+
+// restart begins a new trace for a context switch
+func (t *startupTracer) restart() {
+	if t.enabled.Load() {
+		t.reset()
+	}
+}
+
+func (t *startupTracer) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.started = time.Now()
+	t.ready = time.Time{}
+	t.running = make(map[string]time.Time)
+	t.phases = nil
+}
+
+// GetStartupTrace returns the recorded phases; Enabled is false without --startup-trace
+func GetStartupTrace() StartupTrace {
+	t := startupTrace
+	if !t.enabled.Load() {
+		return StartupTrace{Running: []string{}, Phases: []StartupPhase{}}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	trace := StartupTrace{
+		Enabled:   true,
+		Context:   GetContextName(),
+		StartedAt: t.started,
+		Running:   make([]string, 0, len(t.running)),
+		Phases:    slices.Clone(t.phases),
+	}
+	if !t.ready.IsZero() {
+		ms := t.ready.Sub(t.started).Milliseconds()
+		trace.ReadyMs = &ms
+	}
+	for name := range t.running {
+		trace.Running = append(trace.Running, name)
+	}
+	slices.Sort(trace.Running)
+	if trace.Phases == nil {
+		trace.Phases = []StartupPhase{}
+	}
+	slices.SortStableFunc(trace.Phases, func(a, b StartupPhase) int { return a.Start.Compare(b.Start) })
+	return trace
+}
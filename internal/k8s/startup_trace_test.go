@@ -0,0 +1,48 @@
+package k8s
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStartupTrace(t *testing.T) {
+	t.Cleanup(func() {
+		startupTrace.enabled.Store(false)
+		startupTrace.reset()
+	})
+
+	// Disabled: nothing is recorded
+	TraceStartupPhase("helm")(nil)
+	if trace := GetStartupTrace(); trace.Enabled || len(trace.Phases) != 0 {
+		t.Fatalf("disabled trace recorded %+v", trace)
+	}
+
+	EnableStartupTrace()
+	endPerms := TraceStartupPhase("permissions")
+	endHelm := TraceStartupPhase("helm")
+	endPerms(errors.New("timeout"))
+
+	trace := GetStartupTrace()
+	if len(trace.Phases) != 1 || trace.Phases[0].Name != "permissions" || trace.Phases[0].Error != "timeout" {
+		t.Fatalf("phases = %+v, want failed permissions", trace.Phases)
+	}
+	if len(trace.Running) != 1 || trace.Running[0] != "helm" {
+		t.Errorf("running = %v, want [helm]", trace.Running)
+	}
+	if trace.ReadyMs != nil {
+		t.Errorf("readyMs set before markReady")
+	}
+
+	// A phase ending after a context switch restarted the trace is dropped
+	startupTrace.restart()
+	endHelm(nil)
+	trace = GetStartupTrace()
+	if len(trace.Phases) != 0 || len(trace.Running) != 0 {
+		t.Errorf("after restart: phases = %+v, running = %v", trace.Phases, trace.Running)
+	}
+
+	startupTrace.markReady()
+	if trace := GetStartupTrace(); trace.ReadyMs == nil {
+		t.Errorf("readyMs not set after markReady")
+	}
+}
@@ -24,14 +24,20 @@ func InitAllSubsystems(progress func(string)) error {
 	contextSwitchMu.RUnlock()
 	if tlReinitFn != nil {
 		progress("Initializing timeline...")
-		if err := tlReinitFn(); err != nil {
+		endTrace := TraceStartupPhase("timeline")
+		err := tlReinitFn()
+		endTrace(err)
+		if err != nil {
 			log.Printf("Warning: timeline init failed: %v", err)
 		}
 	}
 
 	// 2. Resource cache (typed informers) — critical, everything depends on this
 	progress("Loading workloads...")
-	if err := InitResourceCache(); err != nil {
+	endTrace := TraceStartupPhase("resource-cache")
+	err := InitResourceCache()
+	endTrace(err)
+	if err != nil {
 		return fmt.Errorf("resource cache init failed: %w", err)
 	}
 	if cache := GetResourceCache(); cache != nil {
@@ -40,7 +46,10 @@ func InitAllSubsystems(progress func(string)) error {
 
 	// 3. API resource discovery
 	progress("Discovering API resources...")
-	if err := InitResourceDiscovery(); err != nil {
+	endTrace = TraceStartupPhase("api-discovery")
+	err = InitResourceDiscovery()
+	endTrace(err)
+	if err != nil {
 		log.Printf("Warning: resource discovery init failed: %v", err)
 	}
 
@@ -48,7 +57,10 @@ func InitAllSubsystems(progress func(string)) error {
 	progress("Loading custom resources...")
 	if cache := GetResourceCache(); cache != nil {
 		changeCh := cache.ChangesRaw()
-		if err := InitDynamicResourceCache(changeCh); err != nil {
+		endTrace := TraceStartupPhase("dynamic-cache")
+		err := InitDynamicResourceCache(changeCh)
+		endTrace(err)
+		if err != nil {
 			log.Printf("Warning: dynamic resource cache init failed: %v", err)
 		}
 
@@ -56,7 +68,10 @@ func InitAllSubsystems(progress func(string)) error {
 		// Common CRDs appear in topology quickly as they sync;
 		// remaining CRDs appear as DiscoverAllCRDs completes.
 		if dc := GetDynamicResourceCache(); dc != nil {
+			endWarmup := TraceStartupPhase("crd-warmup")
+			endDiscovery := TraceStartupPhase("crd-discovery")
 			go func() {
+				dc.WatchAPIServices()
 				// Warmup runs in its own recover so a panic there
 				// doesn't prevent full CRD discovery from running.
 				func() {
@@ -67,15 +82,20 @@ func InitAllSubsystems(progress func(string)) error {
 							log.Printf("PANIC in CRD warmup: %v\n%s", r, buf[:n])
 						}
 					}()
+					defer endWarmup(nil)
 					WarmupCommonCRDs()
 				}()
 				dc.DiscoverAllCRDs()
+				endDiscovery(nil)
+				dc.WatchCRDLifecycle()
 			}()
 		}
 	}
 
 	// 5. Metrics history
+	endTrace = TraceStartupPhase("metrics-history")
 	InitMetricsHistory()
+	endTrace(nil)
 
 	// 6. Helm
 	contextSwitchMu.RLock()
@@ -83,7 +103,10 @@ func InitAllSubsystems(progress func(string)) error {
 	contextSwitchMu.RUnlock()
 	if hReinitFn != nil {
 		progress("Loading Helm releases...")
-		if err := hReinitFn(GetKubeconfigPath()); err != nil {
+		endTrace := TraceStartupPhase("helm")
+		err := hReinitFn(GetKubeconfigPath())
+		endTrace(err)
+		if err != nil {
 			log.Printf("Warning: Helm init failed: %v", err)
 		}
 	}
@@ -94,11 +117,15 @@ func InitAllSubsystems(progress func(string)) error {
 	contextSwitchMu.RUnlock()
 	if trReinitFn != nil {
 		progress("Initializing traffic analysis...")
-		if err := trReinitFn(); err != nil {
+		endTrace := TraceStartupPhase("traffic")
+		err := trReinitFn()
+		endTrace(err)
+		if err != nil {
 			log.Printf("Warning: traffic init failed: %v", err)
 		}
 	}
 
+	startupTrace.markReady()
 	return nil
 }
 
@@ -125,6 +152,7 @@ func ResetAllSubsystems() {
 
 	// 5. Metrics history
 	safeReset("metrics history", ResetMetricsHistory)
+	safeReset("restart storm detector", ResetRestartStorms)
 
 	// 4. Dynamic cache
 	safeReset("dynamic resource cache", ResetDynamicResourceCache)
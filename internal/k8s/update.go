@@ -17,6 +17,7 @@ type UpdateResourceOptions struct {
 	Namespace string
 	Name      string
 	YAML      string // YAML content to apply
+	DryRun    bool   // Validate server-side (including admission webhooks) without persisting
 }
 
 // UpdateResource updates a Kubernetes resource from YAML
@@ -54,12 +55,17 @@ func UpdateResource(ctx context.Context, opts UpdateResourceOptions) (*unstructu
 	}
 
 	// Update the resource
+	updateOpts := metav1.UpdateOptions{}
+	if opts.DryRun {
+		updateOpts.DryRun = []string{metav1.DryRunAll}
+	}
+
 	var result *unstructured.Unstructured
 	var err error
 	if opts.Namespace != "" {
-		result, err = dynamicClient.Resource(gvr).Namespace(opts.Namespace).Update(ctx, obj, metav1.UpdateOptions{})
+		result, err = dynamicClient.Resource(gvr).Namespace(opts.Namespace).Update(ctx, obj, updateOpts)
 	} else {
-		result, err = dynamicClient.Resource(gvr).Update(ctx, obj, metav1.UpdateOptions{})
+		result, err = dynamicClient.Resource(gvr).Update(ctx, obj, updateOpts)
 	}
 
 	if err != nil {
@@ -69,8 +75,50 @@ func UpdateResource(ctx context.Context, opts UpdateResourceOptions) (*unstructu
 	return result, nil
 }
 
-// DeleteResource deletes a Kubernetes resource
-func DeleteResource(ctx context.Context, kind, namespace, name string) error {
+// PatchResource applies a JSON Patch, merge patch, or strategic merge patch to a
+// Kubernetes resource. Strategic merge patch is only supported for built-in types;
+// the API server rejects it for custom resources.
+func PatchResource(ctx context.Context, kind, namespace, name string, patchType types.PatchType, data []byte) (*unstructured.Unstructured, error) {
+	discovery := GetResourceDiscovery()
+	if discovery == nil {
+		return nil, fmt.Errorf("resource discovery not initialized")
+	}
+
+	dynamicClient := GetDynamicClient()
+	if dynamicClient == nil {
+		return nil, fmt.Errorf("dynamic client not initialized")
+	}
+
+	switch patchType {
+	case types.JSONPatchType, types.MergePatchType, types.StrategicMergePatchType:
+	default:
+		return nil, fmt.Errorf("unsupported patch type: %s", patchType)
+	}
+
+	// Get GVR for this resource kind
+	gvr, ok := discovery.GetGVR(kind)
+	if !ok {
+		return nil, fmt.Errorf("unknown resource kind: %s", kind)
+	}
+
+	var result *unstructured.Unstructured
+	var err error
+	if namespace != "" {
+		result, err = dynamicClient.Resource(gvr).Namespace(namespace).Patch(ctx, name, patchType, data, metav1.PatchOptions{})
+	} else {
+		result, err = dynamicClient.Resource(gvr).Patch(ctx, name, patchType, data, metav1.PatchOptions{})
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch resource: %w", err)
+	}
+
+	return result, nil
+}
+
+// DeleteResource deletes a Kubernetes resource. An empty propagation uses
+// the API server's default for the kind (see DefaultPropagation).
+func DeleteResource(ctx context.Context, kind, namespace, name string, propagation metav1.DeletionPropagation) error {
 	discovery := GetResourceDiscovery()
 	if discovery == nil {
 		return fmt.Errorf("resource discovery not initialized")
@@ -88,11 +136,15 @@ func DeleteResource(ctx context.Context, kind, namespace, name string) error {
 	}
 
 	// Delete the resource
+	opts := metav1.DeleteOptions{}
+	if propagation != "" {
+		opts.PropagationPolicy = &propagation
+	}
 	var err error
 	if namespace != "" {
-		err = dynamicClient.Resource(gvr).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+		err = dynamicClient.Resource(gvr).Namespace(namespace).Delete(ctx, name, opts)
 	} else {
-		err = dynamicClient.Resource(gvr).Delete(ctx, name, metav1.DeleteOptions{})
+		err = dynamicClient.Resource(gvr).Delete(ctx, name, opts)
 	}
 
 	if err != nil {
@@ -0,0 +1,90 @@
+package k8s
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ValidationIssue is a single problem reported by the API server for a resource
+type ValidationIssue struct {
+	Field   string `json:"field,omitempty"`   // JSON path of the offending field (e.g. spec.replicas)
+	Type    string `json:"type,omitempty"`    // Cause type (e.g. FieldValueInvalid, FieldValueRequired)
+	Message string `json:"message"`           // Human-readable description
+	Webhook string `json:"webhook,omitempty"` // Admission webhook that rejected the request, if any
+}
+
+// ValidationResult is the outcome of a server-side dry-run
+type ValidationResult struct {
+	Valid  bool              `json:"valid"`
+	Reason string            `json:"reason,omitempty"` // StatusReason from the API server (Invalid, Forbidden, ...)
+	Issues []ValidationIssue `json:"issues,omitempty"`
+	Lint   []LintFinding     `json:"lint,omitempty"` // Best-practice findings when linting was requested
+}
+
+// webhookDenialPattern matches the message the API server produces when an
+// admission webhook rejects a request, e.g.
+// admission webhook "validate.kyverno.svc" denied the request: ...
+var webhookDenialPattern = regexp.MustCompile(`admission webhook "([^"]+)" denied the request:?\s*(.*)`)
+
+// IsValidationError reports whether err describes a problem with the submitted
+// object (schema validation, bad request, admission rejection) as opposed to
+// a transport, RBAC, or not-found error.
+func IsValidationError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if apierrors.IsInvalid(err) || apierrors.IsBadRequest(err) {
+		return true
+	}
+	return webhookDenialPattern.MatchString(err.Error())
+}
+
+// ValidationResultFromError converts an API server error into a structured
+// ValidationResult. Returns nil if err is not a validation error.
+func ValidationResultFromError(err error) *ValidationResult {
+	if !IsValidationError(err) {
+		return nil
+	}
+
+	result := &ValidationResult{Valid: false}
+
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		s := status.Status()
+		result.Reason = string(s.Reason)
+		if s.Details != nil {
+			for _, cause := range s.Details.Causes {
+				result.Issues = append(result.Issues, issueFromCause(cause))
+			}
+		}
+		if len(result.Issues) == 0 {
+			result.Issues = append(result.Issues, issueFromMessage(s.Message))
+		}
+		return result
+	}
+
+	result.Issues = append(result.Issues, issueFromMessage(err.Error()))
+	return result
+}
+
+func issueFromCause(cause metav1.StatusCause) ValidationIssue {
+	issue := issueFromMessage(cause.Message)
+	issue.Field = cause.Field
+	issue.Type = string(cause.Type)
+	return issue
+}
+
+func issueFromMessage(message string) ValidationIssue {
+	if m := webhookDenialPattern.FindStringSubmatch(message); m != nil {
+		msg := strings.TrimSpace(m[2])
+		if msg == "" {
+			msg = message
+		}
+		return ValidationIssue{Message: msg, Webhook: m[1]}
+	}
+	return ValidationIssue{Message: message}
+}
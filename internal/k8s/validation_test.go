@@ -0,0 +1,65 @@
+package k8s
+
+import (
+	"fmt"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidationResultFromError_FieldCauses(t *testing.T) {
+	err := apierrors.NewInvalid(
+		schema.GroupKind{Group: "apps", Kind: "Deployment"},
+		"web",
+		field.ErrorList{field.Invalid(field.NewPath("spec", "replicas"), -1, "must be greater than or equal to 0")},
+	)
+
+	result := ValidationResultFromError(fmt.Errorf("failed to update resource: %w", err))
+	if result == nil {
+		t.Fatal("expected validation result for Invalid error")
+	}
+	if result.Valid {
+		t.Error("expected Valid=false")
+	}
+	if result.Reason != "Invalid" {
+		t.Errorf("Reason = %q, want Invalid", result.Reason)
+	}
+	if len(result.Issues) != 1 || result.Issues[0].Field != "spec.replicas" {
+		t.Fatalf("unexpected issues: %+v", result.Issues)
+	}
+}
+
+func TestValidationResultFromError_WebhookDenial(t *testing.T) {
+	err := apierrors.NewForbidden(
+		schema.GroupResource{Group: "apps", Resource: "deployments"},
+		"web",
+		fmt.Errorf(`admission webhook "validate.kyverno.svc" denied the request: label team is required`),
+	)
+
+	result := ValidationResultFromError(err)
+	if result == nil {
+		t.Fatal("expected validation result for webhook denial")
+	}
+	if len(result.Issues) != 1 {
+		t.Fatalf("expected 1 issue, got %d", len(result.Issues))
+	}
+	if got := result.Issues[0].Webhook; got != "validate.kyverno.svc" {
+		t.Errorf("Webhook = %q, want validate.kyverno.svc", got)
+	}
+	if got := result.Issues[0].Message; got != "label team is required" {
+		t.Errorf("Message = %q", got)
+	}
+}
+
+func TestValidationResultFromError_NotValidation(t *testing.T) {
+	rbac := apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "p", fmt.Errorf("user cannot update"))
+	notFound := apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "p")
+
+	for _, err := range []error{nil, rbac, notFound} {
+		if result := ValidationResultFromError(err); result != nil {
+			t.Errorf("ValidationResultFromError(%v) = %+v, want nil", err, result)
+		}
+	}
+}
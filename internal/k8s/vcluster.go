@@ -0,0 +1,175 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// VClusterPort is the port the vcluster API server listens on inside its pod
+const VClusterPort = 8443
+
+// vclusterSelector matches the control plane workload the vcluster chart creates
+var vclusterSelector = labels.SelectorFromSet(labels.Set{"app": "vcluster"})
+
+// VCluster is a virtual cluster running in a host namespace
+type VCluster struct {
+	Namespace     string `json:"namespace"`
+	Name          string `json:"name"`
+	WorkloadKind  string `json:"workloadKind"` // StatefulSet or Deployment
+	Version       string `json:"version,omitempty"`
+	Distro        string `json:"distro,omitempty"` // k8s, k3s, k0s, eks
+	Replicas      int32  `json:"replicas"`
+	ReadyReplicas int32  `json:"readyReplicas"`
+	Ready         bool   `json:"ready"`
+	Context       string `json:"context"`   // Context name used once connected
+	Connected     bool   `json:"connected"` // A port-forward to the vcluster API server is running
+}
+
+// ListVClusters detects vcluster instances from the StatefulSets and
+// Deployments their Helm chart creates (label app=vcluster).
+func ListVClusters(namespaces []string) ([]VCluster, error) {
+	cache := GetResourceCache()
+	if cache == nil {
+		return nil, fmt.Errorf("resource cache not initialized")
+	}
+	inScope := func(ns string) bool {
+		return len(namespaces) == 0 || slices.Contains(namespaces, ns)
+	}
+
+	vclusters := []VCluster{}
+	if lister := cache.StatefulSets(); lister != nil {
+		statefulSets, err := lister.List(vclusterSelector)
+		if err != nil {
+			return nil, err
+		}
+		for _, sts := range statefulSets {
+			if !inScope(sts.Namespace) {
+				continue
+			}
+			v := newVCluster(sts.ObjectMeta, "StatefulSet", sts.Spec.Template.Spec.Containers)
+			v.Replicas = ptrInt32(sts.Spec.Replicas, 1)
+			v.ReadyReplicas = sts.Status.ReadyReplicas
+			v.Ready = v.ReadyReplicas > 0
+			vclusters = append(vclusters, v)
+		}
+	}
+	if lister := cache.Deployments(); lister != nil {
+		deployments, err := lister.List(vclusterSelector)
+		if err != nil {
+			return nil, err
+		}
+		for _, deploy := range deployments {
+			if !inScope(deploy.Namespace) {
+				continue
+			}
+			v := newVCluster(deploy.ObjectMeta, "Deployment", deploy.Spec.Template.Spec.Containers)
+			v.Replicas = ptrInt32(deploy.Spec.Replicas, 1)
+			v.ReadyReplicas = deploy.Status.ReadyReplicas
+			v.Ready = v.ReadyReplicas > 0
+			vclusters = append(vclusters, v)
+		}
+	}
+
+	sort.Slice(vclusters, func(i, j int) bool {
+		if vclusters[i].Namespace != vclusters[j].Namespace {
+			return vclusters[i].Namespace < vclusters[j].Namespace
+		}
+		return vclusters[i].Name < vclusters[j].Name
+	})
+	return vclusters, nil
+}
+
+func newVCluster(meta metav1.ObjectMeta, kind string, containers []corev1.Container) VCluster {
+	name := meta.Labels["release"]
+	if name == "" {
+		name = meta.Name
+	}
+	v := VCluster{
+		Namespace:    meta.Namespace,
+		Name:         name,
+		WorkloadKind: kind,
+		Context:      VClusterContextName(meta.Namespace, name),
+	}
+	// chart label is vcluster-<version> (vcluster-k8s-<version> for older distro charts)
+	if chart := meta.Labels["chart"]; strings.HasPrefix(chart, "vcluster") {
+		if i := strings.LastIndex(chart, "-"); i > 0 {
+			v.Version = chart[i+1:]
+		}
+	}
+	for _, c := range containers {
+		if c.Name != "syncer" && c.Name != "vcluster" {
+			continue
+		}
+		for _, distro := range []string{"k3s", "k0s", "eks"} {
+			if strings.Contains(c.Image, distro) {
+				v.Distro = distro
+			}
+		}
+		if v.Version == "" {
+			if i := strings.LastIndex(c.Image, ":"); i > 0 {
+				v.Version = c.Image[i+1:]
+			}
+		}
+	}
+	if v.Distro == "" {
+		v.Distro = "k8s"
+	}
+	return v
+}
+
+func ptrInt32(p *int32, def int32) int32 {
+	if p == nil {
+		return def
+	}
+	return *p
+}
+
+// VClusterContextName is the kubeconfig context name used for a vcluster
+func VClusterContextName(namespace, name string) string {
+	return "vcluster:" + namespace + "/" + name
+}
+
+// RegisterVClusterContext reads the vc-<name> secret vcluster writes with its
+// admin kubeconfig, points it at server (a local port-forward to the vcluster
+// pod), and registers it as a switchable context. Returns the context name.
+func RegisterVClusterContext(ctx context.Context, namespace, name, server string) (string, error) {
+	client := GetClient()
+	if client == nil {
+		return "", fmt.Errorf("kubernetes client not available")
+	}
+	secret, err := client.CoreV1().Secrets(namespace).Get(ctx, "vc-"+name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to read kubeconfig secret for vcluster %s/%s: %w", namespace, name, err)
+	}
+	data := secret.Data["config"]
+	if len(data) == 0 {
+		return "", fmt.Errorf("kubeconfig secret %s/vc-%s has no config key", namespace, name)
+	}
+
+	contextName := VClusterContextName(namespace, name)
+	cfg, err := vclusterKubeconfig(data, contextName, server)
+	if err != nil {
+		return "", fmt.Errorf("invalid kubeconfig for vcluster %s/%s: %w", namespace, name, err)
+	}
+	RegisterGeneratedContext(contextName, cfg)
+	return contextName, nil
+}
+
+// vclusterKubeconfig renames the vcluster kubeconfig's context and replaces its
+// server (https://localhost:8443 by default) with the port-forward address.
+func vclusterKubeconfig(data []byte, contextName, server string) (*clientcmdapi.Config, error) {
+	cfg, err := renameKubeconfigContext(data, contextName)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Clusters[contextName].Server = server
+	return cfg, nil
+}
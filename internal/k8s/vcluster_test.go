@@ -0,0 +1,60 @@
+package k8s
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewVCluster(t *testing.T) {
+	meta := metav1.ObjectMeta{
+		Name:      "dev",
+		Namespace: "team-a",
+		Labels:    map[string]string{"app": "vcluster", "release": "dev", "chart": "vcluster-0.20.1"},
+	}
+	v := newVCluster(meta, "StatefulSet", []corev1.Container{{Name: "syncer", Image: "ghcr.io/loft-sh/vcluster-pro:0.20.1"}})
+	if v.Name != "dev" || v.Version != "0.20.1" || v.Distro != "k8s" || v.Context != "vcluster:team-a/dev" {
+		t.Errorf("unexpected vcluster: %+v", v)
+	}
+
+	legacy := metav1.ObjectMeta{Name: "old", Namespace: "team-b", Labels: map[string]string{"app": "vcluster"}}
+	v = newVCluster(legacy, "StatefulSet", []corev1.Container{{Name: "vcluster", Image: "rancher/k3s:v1.29.1-k3s1"}})
+	if v.Name != "old" || v.Distro != "k3s" || v.Version != "v1.29.1-k3s1" {
+		t.Errorf("unexpected legacy vcluster: %+v", v)
+	}
+}
+
+func TestVClusterKubeconfig(t *testing.T) {
+	kubeconfig := []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: my-vcluster
+  cluster:
+    server: https://localhost:8443
+    certificate-authority-data: Y2E=
+users:
+- name: my-vcluster
+  user:
+    client-certificate-data: Y2VydA==
+    client-key-data: a2V5
+contexts:
+- name: my-vcluster
+  context:
+    cluster: my-vcluster
+    user: my-vcluster
+current-context: my-vcluster
+`)
+	name := VClusterContextName("team-a", "dev")
+	cfg, err := vclusterKubeconfig(kubeconfig, name, "https://localhost:40123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cluster := cfg.Clusters[name]
+	if cluster == nil || cluster.Server != "https://localhost:40123" || string(cluster.CertificateAuthorityData) != "ca" {
+		t.Errorf("unexpected cluster: %+v", cluster)
+	}
+	if cfg.Contexts[name].AuthInfo != name || string(cfg.AuthInfos[name].ClientKeyData) != "key" {
+		t.Errorf("user not carried over: %+v", cfg.AuthInfos)
+	}
+}
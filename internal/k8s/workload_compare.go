@@ -0,0 +1,345 @@
+package k8s
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Workload comparison difference categories
+const (
+	CompareSpec      = "spec"      // Replicas, service account, scheduling
+	CompareLabels    = "labels"    // Workload and pod template labels
+	CompareContainer = "container" // Image, env, resources, probes, command, ports
+	CompareRuntime   = "runtime"   // Observed pod state
+)
+
+// WorkloadRef identifies a workload to compare
+type WorkloadRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// ParseWorkloadRef parses "kind/namespace/name"
+func ParseWorkloadRef(s string) (WorkloadRef, error) {
+	parts := strings.Split(s, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return WorkloadRef{}, fmt.Errorf("invalid workload %q, expected kind/namespace/name", s)
+	}
+	return WorkloadRef{Kind: parts[0], Namespace: parts[1], Name: parts[2]}, nil
+}
+
+// WorkloadRuntime is the observed state of a workload's pods
+type WorkloadRuntime struct {
+	Pods          int            `json:"pods"`
+	ReadyPods     int            `json:"readyPods"`
+	Restarts      int            `json:"restarts"`
+	Phases        map[string]int `json:"phases,omitempty"`
+	RunningImages []string       `json:"runningImages,omitempty"` // Resolved image digests where known
+}
+
+// WorkloadDifference is one field that differs between the two workloads.
+// A nil side means the field (e.g. a container or env var) is absent there.
+type WorkloadDifference struct {
+	Category string `json:"category"`
+	Path     string `json:"path"`
+	Left     any    `json:"left"`
+	Right    any    `json:"right"`
+}
+
+// WorkloadComparison is a field-by-field comparison of two workloads' specs
+// and runtime state
+type WorkloadComparison struct {
+	Left         WorkloadRef          `json:"left"`
+	Right        WorkloadRef          `json:"right"`
+	LeftRuntime  WorkloadRuntime      `json:"leftRuntime"`
+	RightRuntime WorkloadRuntime      `json:"rightRuntime"`
+	Differences  []WorkloadDifference `json:"differences"`
+	Identical    bool                 `json:"identical"` // No spec differences (runtime may still differ)
+}
+
+// CompareWorkloads diffs two workloads from the cache, e.g. the same
+// Deployment in staging and prod, or two variants in one namespace
+func CompareWorkloads(left, right WorkloadRef) (*WorkloadComparison, error) {
+	cache := GetResourceCache()
+	if cache == nil {
+		return nil, fmt.Errorf("resource cache not initialized")
+	}
+	leftObj, _, err := getExportWorkload(cache, left.Kind, left.Namespace, left.Name)
+	if err != nil {
+		return nil, err
+	}
+	rightObj, _, err := getExportWorkload(cache, right.Kind, right.Namespace, right.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var pods []*corev1.Pod
+	if cache.Pods() != nil {
+		pods, _ = cache.Pods().List(labels.Everything())
+	}
+	return compareWorkloads(leftObj, rightObj, pods), nil
+}
+
+// compareWorkloads builds the comparison from two workloads (as returned by
+// getExportWorkload) and the pods to derive runtime state from
+func compareWorkloads(leftObj, rightObj runtime.Object, pods []*corev1.Pod) *WorkloadComparison {
+	l, r := describeWorkload(leftObj), describeWorkload(rightObj)
+	cmp := &WorkloadComparison{Left: l.ref, Right: r.ref}
+	diff := func(category, path string, lv, rv any) {
+		if !reflect.DeepEqual(lv, rv) {
+			cmp.Differences = append(cmp.Differences, WorkloadDifference{Category: category, Path: path, Left: lv, Right: rv})
+		}
+	}
+
+	if l.ref.Kind != r.ref.Kind {
+		diff(CompareSpec, "kind", l.ref.Kind, r.ref.Kind)
+	}
+	diff(CompareSpec, "replicas", l.replicas, r.replicas)
+	diff(CompareSpec, "serviceAccountName", l.template.Spec.ServiceAccountName, r.template.Spec.ServiceAccountName)
+	diff(CompareSpec, "priorityClassName", l.template.Spec.PriorityClassName, r.template.Spec.PriorityClassName)
+	diffMaps(CompareSpec, "nodeSelector", l.template.Spec.NodeSelector, r.template.Spec.NodeSelector, diff)
+	diff(CompareSpec, "tolerations", formatTolerations(l.template.Spec.Tolerations), formatTolerations(r.template.Spec.Tolerations))
+
+	diffMaps(CompareLabels, "metadata.labels", l.labels, r.labels, diff)
+	diffMaps(CompareLabels, "template.labels", l.template.Labels, r.template.Labels, diff)
+
+	diffContainers("initContainers", l.template.Spec.InitContainers, r.template.Spec.InitContainers, diff)
+	diffContainers("containers", l.template.Spec.Containers, r.template.Spec.Containers, diff)
+	cmp.Identical = len(cmp.Differences) == 0
+
+	cmp.LeftRuntime = workloadRuntime(l.ref, pods)
+	cmp.RightRuntime = workloadRuntime(r.ref, pods)
+	lr, rr := cmp.LeftRuntime, cmp.RightRuntime
+	diff(CompareRuntime, "pods", lr.Pods, rr.Pods)
+	diff(CompareRuntime, "readyPods", lr.ReadyPods, rr.ReadyPods)
+	diff(CompareRuntime, "restarts", lr.Restarts, rr.Restarts)
+	diffMaps(CompareRuntime, "phases", lr.Phases, rr.Phases, diff)
+	diff(CompareRuntime, "runningImages", lr.RunningImages, rr.RunningImages)
+
+	if cmp.Differences == nil {
+		cmp.Differences = []WorkloadDifference{}
+	}
+	return cmp
+}
+
+type describedWorkload struct {
+	ref      WorkloadRef
+	labels   map[string]string
+	replicas *int32
+	template *corev1.PodTemplateSpec
+}
+
+func describeWorkload(obj runtime.Object) describedWorkload {
+	switch w := obj.(type) {
+	case *appsv1.Deployment:
+		return describedWorkload{WorkloadRef{"Deployment", w.Namespace, w.Name}, w.Labels, w.Spec.Replicas, &w.Spec.Template}
+	case *appsv1.StatefulSet:
+		return describedWorkload{WorkloadRef{"StatefulSet", w.Namespace, w.Name}, w.Labels, w.Spec.Replicas, &w.Spec.Template}
+	case *appsv1.DaemonSet:
+		return describedWorkload{WorkloadRef{"DaemonSet", w.Namespace, w.Name}, w.Labels, nil, &w.Spec.Template}
+	case *batchv1.Job:
+		return describedWorkload{WorkloadRef{"Job", w.Namespace, w.Name}, w.Labels, w.Spec.Parallelism, &w.Spec.Template}
+	case *batchv1.CronJob:
+		return describedWorkload{WorkloadRef{"CronJob", w.Namespace, w.Name}, w.Labels, w.Spec.JobTemplate.Spec.Parallelism, &w.Spec.JobTemplate.Spec.Template}
+	}
+	return describedWorkload{template: &corev1.PodTemplateSpec{}}
+}
+
+// diffMaps reports each key whose value differs, so label and selector
+// differences read as individual fields
+func diffMaps[V any](category, path string, l, r map[string]V, diff func(string, string, any, any)) {
+	keys := slices.Sorted(maps.Keys(l))
+	for k := range r {
+		if _, ok := l[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		var lv, rv any
+		if v, ok := l[k]; ok {
+			lv = v
+		}
+		if v, ok := r[k]; ok {
+			rv = v
+		}
+		diff(category, path+"."+k, lv, rv)
+	}
+}
+
+// diffContainers matches containers by name and compares the fields that
+// usually explain behaviour differences
+func diffContainers(field string, l, r []corev1.Container, diff func(string, string, any, any)) {
+	byName := func(cs []corev1.Container) map[string]*corev1.Container {
+		m := make(map[string]*corev1.Container, len(cs))
+		for i := range cs {
+			m[cs[i].Name] = &cs[i]
+		}
+		return m
+	}
+	lm, rm := byName(l), byName(r)
+	names := slices.Sorted(maps.Keys(lm))
+	for name := range rm {
+		if _, ok := lm[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := fmt.Sprintf("%s[%s]", field, name)
+		lc, rc := lm[name], rm[name]
+		if lc == nil || rc == nil {
+			var lv, rv any
+			if lc != nil {
+				lv = lc.Image
+			}
+			if rc != nil {
+				rv = rc.Image
+			}
+			diff(CompareContainer, path, lv, rv)
+			continue
+		}
+		diff(CompareContainer, path+".image", lc.Image, rc.Image)
+		diff(CompareContainer, path+".command", lc.Command, rc.Command)
+		diff(CompareContainer, path+".args", lc.Args, rc.Args)
+		diffMaps(CompareContainer, path+".env", containerEnv(lc), containerEnv(rc), diff)
+		diffMaps(CompareContainer, path+".resources.requests", quantityStrings(lc.Resources.Requests), quantityStrings(rc.Resources.Requests), diff)
+		diffMaps(CompareContainer, path+".resources.limits", quantityStrings(lc.Resources.Limits), quantityStrings(rc.Resources.Limits), diff)
+		diff(CompareContainer, path+".livenessProbe", formatProbe(lc.LivenessProbe), formatProbe(rc.LivenessProbe))
+		diff(CompareContainer, path+".readinessProbe", formatProbe(lc.ReadinessProbe), formatProbe(rc.ReadinessProbe))
+		diff(CompareContainer, path+".startupProbe", formatProbe(lc.StartupProbe), formatProbe(rc.StartupProbe))
+		diff(CompareContainer, path+".ports", formatPorts(lc.Ports), formatPorts(rc.Ports))
+	}
+}
+
+// containerEnv flattens env vars and envFrom sources to displayable values
+func containerEnv(c *corev1.Container) map[string]string {
+	env := make(map[string]string, len(c.Env)+len(c.EnvFrom))
+	for _, e := range c.Env {
+		env[e.Name] = formatEnvValue(e)
+	}
+	for _, src := range c.EnvFrom {
+		switch {
+		case src.ConfigMapRef != nil:
+			env["envFrom:configMap/"+src.ConfigMapRef.Name] = src.Prefix
+		case src.SecretRef != nil:
+			env["envFrom:secret/"+src.SecretRef.Name] = src.Prefix
+		}
+	}
+	return env
+}
+
+func formatEnvValue(e corev1.EnvVar) string {
+	if e.ValueFrom == nil {
+		return e.Value
+	}
+	switch from := e.ValueFrom; {
+	case from.ConfigMapKeyRef != nil:
+		return fmt.Sprintf("configMap %s/%s", from.ConfigMapKeyRef.Name, from.ConfigMapKeyRef.Key)
+	case from.SecretKeyRef != nil:
+		return fmt.Sprintf("secret %s/%s", from.SecretKeyRef.Name, from.SecretKeyRef.Key)
+	case from.FieldRef != nil:
+		return "field " + from.FieldRef.FieldPath
+	case from.ResourceFieldRef != nil:
+		return "resource " + from.ResourceFieldRef.Resource
+	}
+	return ""
+}
+
+func quantityStrings(list corev1.ResourceList) map[string]string {
+	m := make(map[string]string, len(list))
+	for name, q := range list {
+		m[string(name)] = q.String()
+	}
+	return m
+}
+
+// formatProbe summarizes a probe as "handler; timing" so equal probes compare equal
+func formatProbe(p *corev1.Probe) string {
+	if p == nil {
+		return ""
+	}
+	var handler string
+	switch {
+	case p.HTTPGet != nil:
+		handler = fmt.Sprintf("http %s:%s", p.HTTPGet.Path, p.HTTPGet.Port.String())
+	case p.TCPSocket != nil:
+		handler = "tcp " + p.TCPSocket.Port.String()
+	case p.GRPC != nil:
+		handler = fmt.Sprintf("grpc %d", p.GRPC.Port)
+	case p.Exec != nil:
+		handler = "exec " + strings.Join(p.Exec.Command, " ")
+	}
+	return fmt.Sprintf("%s; delay=%ds period=%ds timeout=%ds failure=%d",
+		handler, p.InitialDelaySeconds, p.PeriodSeconds, p.TimeoutSeconds, p.FailureThreshold)
+}
+
+func formatPorts(ports []corev1.ContainerPort) []string {
+	out := make([]string, 0, len(ports))
+	for _, p := range ports {
+		out = append(out, fmt.Sprintf("%d/%s", p.ContainerPort, p.Protocol))
+	}
+	sort.Strings(out)
+	return out
+}
+
+func formatTolerations(tolerations []corev1.Toleration) []string {
+	out := make([]string, 0, len(tolerations))
+	for _, t := range tolerations {
+		out = append(out, fmt.Sprintf("%s%s%s:%s", t.Key, t.Operator, t.Value, t.Effect))
+	}
+	sort.Strings(out)
+	return out
+}
+
+// workloadRuntime summarizes the non-terminated pods a workload owns
+func workloadRuntime(ref WorkloadRef, pods []*corev1.Pod) WorkloadRuntime {
+	rt := WorkloadRuntime{Phases: make(map[string]int)}
+	images := make(map[string]bool)
+	for _, pod := range pods {
+		if pod.Namespace != ref.Namespace || podTerminated(pod) {
+			continue
+		}
+		if key := podWorkload(pod); key.Kind != ref.Kind || key.Name != ref.Name {
+			continue
+		}
+		rt.Pods++
+		rt.Phases[string(pod.Status.Phase)]++
+		rt.Restarts += int(getTotalRestarts(pod.Status.ContainerStatuses))
+		if isPodReady(pod) {
+			rt.ReadyPods++
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			image := cs.Image
+			if digest := imageDigest(cs.ImageID); digest != "" {
+				image = image + "@" + digest
+			}
+			images[image] = true
+		}
+	}
+	rt.RunningImages = slices.Sorted(maps.Keys(images))
+	return rt
+}
+
+// imageDigest extracts the sha256 digest from a container status imageID
+func imageDigest(imageID string) string {
+	if i := strings.LastIndex(imageID, "@"); i >= 0 {
+		return imageID[i+1:]
+	}
+	if strings.HasPrefix(imageID, "sha256:") {
+		return imageID
+	}
+	return ""
+}
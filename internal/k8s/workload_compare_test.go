@@ -0,0 +1,101 @@
+package k8s
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func compareTestDeployment(namespace string, replicas int32, image, memory string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "api", Labels: map[string]string{"app": "api"}},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "api"}},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{
+					Name:  "app",
+					Image: image,
+					Env:   []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}},
+					Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse(memory),
+					}},
+					ReadinessProbe: &corev1.Probe{
+						ProbeHandler:  corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt32(8080)}},
+						PeriodSeconds: 10,
+					},
+				}}},
+			},
+		},
+	}
+}
+
+func TestCompareWorkloads(t *testing.T) {
+	staging := compareTestDeployment("staging", 1, "api:1.4", "256Mi")
+	prod := compareTestDeployment("prod", 3, "api:1.3", "256Mi")
+	prod.Spec.Template.Spec.Containers[0].Env[0].Value = "warn"
+	prod.Spec.Template.Spec.Containers[0].Env = append(prod.Spec.Template.Spec.Containers[0].Env,
+		corev1.EnvVar{Name: "DB_PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "db"}, Key: "password",
+		}}})
+	prod.Spec.Template.Spec.Containers = append(prod.Spec.Template.Spec.Containers, corev1.Container{Name: "proxy", Image: "envoy:1.30"})
+
+	ready := func(pod *corev1.Pod) *corev1.Pod {
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		return pod
+	}
+	hash := map[string]string{"pod-template-hash": "7d9f"}
+	pods := []*corev1.Pod{
+		ready(maintenanceTestPod("staging", "api-7d9f-a", "node-a", "ReplicaSet", "api-7d9f", hash)),
+		ready(maintenanceTestPod("prod", "api-7d9f-a", "node-a", "ReplicaSet", "api-7d9f", hash)),
+		maintenanceTestPod("prod", "api-7d9f-b", "node-b", "ReplicaSet", "api-7d9f", hash),
+		// Another workload's pod in the same namespace is ignored
+		maintenanceTestPod("prod", "worker-0", "node-b", "StatefulSet", "worker", nil),
+	}
+
+	cmp := compareWorkloads(staging, prod, pods)
+	if cmp.Identical {
+		t.Fatal("expected differences")
+	}
+	got := make(map[string]WorkloadDifference)
+	for _, d := range cmp.Differences {
+		got[d.Path] = d
+	}
+
+	want := map[string][2]any{
+		"containers[app].image":           {"api:1.4", "api:1.3"},
+		"containers[app].env.LOG_LEVEL":   {"info", "warn"},
+		"containers[app].env.DB_PASSWORD": {nil, "secret db/password"},
+		"containers[proxy]":               {nil, "envoy:1.30"},
+		"pods":                            {1, 2}, // Runtime: prod has an unready pod too
+	}
+	for path, values := range want {
+		d, ok := got[path]
+		if !ok {
+			t.Errorf("expected a difference at %s, got %+v", path, cmp.Differences)
+			continue
+		}
+		if d.Left != values[0] || d.Right != values[1] {
+			t.Errorf("%s: expected %v -> %v, got %v -> %v", path, values[0], values[1], d.Left, d.Right)
+		}
+	}
+	if d := got["replicas"]; d.Category != CompareSpec {
+		t.Errorf("expected a replicas difference, got %+v", d)
+	}
+	for _, same := range []string{"containers[app].resources.requests.memory", "containers[app].readinessProbe", "metadata.labels.app"} {
+		if _, ok := got[same]; ok {
+			t.Errorf("expected no difference at %s", same)
+		}
+	}
+	if cmp.LeftRuntime.ReadyPods != 1 || cmp.RightRuntime.ReadyPods != 1 || cmp.RightRuntime.Pods != 2 {
+		t.Errorf("unexpected runtime %+v / %+v", cmp.LeftRuntime, cmp.RightRuntime)
+	}
+
+	if same := compareWorkloads(staging, staging.DeepCopy(), nil); !same.Identical || len(same.Differences) != 0 {
+		t.Errorf("expected a workload to be identical to itself, got %+v", same.Differences)
+	}
+}
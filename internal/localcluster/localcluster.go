@@ -0,0 +1,331 @@
+// Package localcluster drives the kind, minikube and k3d CLIs so Radar can
+// list, create, start, stop and delete local development clusters. The tools
+// write their contexts into the kubeconfig themselves; callers point them at
+// Radar's kubeconfig with the kubeconfig argument.
+package localcluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// Tool is a local cluster CLI
+type Tool string
+
+const (
+	Kind     Tool = "kind"
+	Minikube Tool = "minikube"
+	K3d      Tool = "k3d"
+)
+
+// Tools lists the supported CLIs in display order
+var Tools = []Tool{Kind, Minikube, K3d}
+
+// Action is an operation on a local cluster
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionStart  Action = "start"
+	ActionStop   Action = "stop"
+	ActionDelete Action = "delete"
+)
+
+// Cluster states
+const (
+	StatusRunning = "running"
+	StatusStopped = "stopped"
+	StatusUnknown = "unknown"
+)
+
+// ErrNoKubeconfig is returned by Kubeconfig for tools that can't print one
+var ErrNoKubeconfig = errors.New("tool can't print a kubeconfig")
+
+// ToolStatus reports whether a CLI is installed
+type ToolStatus struct {
+	Tool      Tool   `json:"tool"`
+	Available bool   `json:"available"`
+	Path      string `json:"path,omitempty"`
+}
+
+// Cluster is a local cluster managed by one of the tools
+type Cluster struct {
+	Tool    Tool   `json:"tool"`
+	Name    string `json:"name"`
+	Context string `json:"context"` // Kubeconfig context the tool writes for it
+	Status  string `json:"status"`
+}
+
+// validName matches names all three tools accept (DNS labels)
+var validName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// ParseTool validates a tool name
+func ParseTool(s string) (Tool, bool) {
+	for _, t := range Tools {
+		if string(t) == s {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// ValidName reports whether name can be used as a cluster name
+func ValidName(name string) bool {
+	return len(name) <= 32 && validName.MatchString(name)
+}
+
+// ContextName is the kubeconfig context a tool creates for a cluster
+func ContextName(tool Tool, name string) string {
+	switch tool {
+	case Kind:
+		return "kind-" + name
+	case K3d:
+		return "k3d-" + name
+	}
+	return name // minikube names the context after the profile
+}
+
+// Detect looks up the CLIs on PATH
+func Detect() []ToolStatus {
+	result := make([]ToolStatus, 0, len(Tools))
+	for _, t := range Tools {
+		path, err := exec.LookPath(string(t))
+		result = append(result, ToolStatus{Tool: t, Available: err == nil, Path: path})
+	}
+	return result
+}
+
+// List returns a tool's clusters
+func List(ctx context.Context, tool Tool) ([]Cluster, error) {
+	switch tool {
+	case Kind:
+		out, err := run(ctx, "", "kind", "get", "clusters")
+		if err != nil {
+			return nil, err
+		}
+		clusters := parseKindClusters(out)
+		for i := range clusters {
+			clusters[i].Status = kindStatus(ctx, clusters[i].Name)
+		}
+		return clusters, nil
+	case Minikube:
+		// Exits non-zero when there are no profiles but still prints JSON
+		out, err := run(ctx, "", "minikube", "profile", "list", "-o", "json")
+		clusters, parseErr := parseMinikubeProfiles(out)
+		if parseErr != nil {
+			if err != nil {
+				return nil, err
+			}
+			return nil, parseErr
+		}
+		return clusters, nil
+	case K3d:
+		out, err := run(ctx, "", "k3d", "cluster", "list", "-o", "json")
+		if err != nil {
+			return nil, err
+		}
+		return parseK3dClusters(out)
+	}
+	return nil, fmt.Errorf("unsupported tool %q", tool)
+}
+
+// Run performs an action on a cluster. kubeconfig is the file the tool should
+// write contexts to; empty leaves the tool's default ($KUBECONFIG or
+// ~/.kube/config).
+func Run(ctx context.Context, tool Tool, action Action, name, kubeconfig string) error {
+	if !ValidName(name) {
+		return fmt.Errorf("invalid cluster name %q", name)
+	}
+	switch tool {
+	case Kind:
+		switch action {
+		case ActionCreate:
+			_, err := run(ctx, kubeconfig, "kind", "create", "cluster", "--name", name)
+			return err
+		case ActionDelete:
+			_, err := run(ctx, kubeconfig, "kind", "delete", "cluster", "--name", name)
+			return err
+		case ActionStart, ActionStop:
+			// kind has no start/stop; its nodes are plain containers
+			out, err := run(ctx, "", "kind", "get", "nodes", "--name", name)
+			if err != nil {
+				return err
+			}
+			nodes := strings.Fields(string(out))
+			if len(nodes) == 0 {
+				return fmt.Errorf("kind cluster %q has no nodes", name)
+			}
+			_, err = run(ctx, "", kindProvider(), append([]string{string(action)}, nodes...)...)
+			return err
+		}
+	case Minikube:
+		_, err := run(ctx, kubeconfig, "minikube", string(minikubeVerb(action)), "-p", name)
+		return err
+	case K3d:
+		args := []string{"cluster", string(action), name}
+		if action == ActionCreate {
+			// Add the context without making it kubectl's current one
+			args = append(args, "--kubeconfig-switch-context=false")
+		}
+		_, err := run(ctx, kubeconfig, "k3d", args...)
+		return err
+	default:
+		return fmt.Errorf("unsupported tool %q", tool)
+	}
+	return fmt.Errorf("unsupported action %q", action)
+}
+
+// Kubeconfig prints a cluster's kubeconfig, for when the tool wrote its
+// context somewhere Radar doesn't read
+func Kubeconfig(ctx context.Context, tool Tool, name string) ([]byte, error) {
+	switch tool {
+	case Kind:
+		return run(ctx, "", "kind", "get", "kubeconfig", "--name", name)
+	case K3d:
+		return run(ctx, "", "k3d", "kubeconfig", "get", name)
+	}
+	return nil, ErrNoKubeconfig
+}
+
+// minikubeVerb maps an action to the minikube command; creating a profile is
+// starting it
+func minikubeVerb(action Action) Action {
+	if action == ActionCreate {
+		return ActionStart
+	}
+	return action
+}
+
+// kindProvider is the container runtime kind uses for its nodes
+func kindProvider() string {
+	if os.Getenv("KIND_EXPERIMENTAL_PROVIDER") == "podman" {
+		return "podman"
+	}
+	return "docker"
+}
+
+// kindStatus derives a kind cluster's state from its node containers
+func kindStatus(ctx context.Context, name string) string {
+	out, err := run(ctx, "", kindProvider(), "ps", "-a",
+		"--filter", "label=io.x-k8s.kind.cluster="+name, "--format", "{{.State}}")
+	if err != nil {
+		return StatusUnknown
+	}
+	return containerStatus(strings.Fields(string(out)))
+}
+
+// containerStatus is running when every node container runs
+func containerStatus(states []string) string {
+	if len(states) == 0 {
+		return StatusUnknown
+	}
+	for _, s := range states {
+		if !strings.EqualFold(s, "running") {
+			return StatusStopped
+		}
+	}
+	return StatusRunning
+}
+
+func parseKindClusters(out []byte) []Cluster {
+	var clusters []Cluster
+	for _, line := range strings.Split(string(out), "\n") {
+		name := strings.TrimSpace(line)
+		// "No kind clusters found." goes to stderr, but be safe
+		if name == "" || strings.Contains(name, " ") {
+			continue
+		}
+		clusters = append(clusters, Cluster{Tool: Kind, Name: name, Context: ContextName(Kind, name)})
+	}
+	return clusters
+}
+
+// minikubeProfiles is the subset of `minikube profile list -o json`
+type minikubeProfiles struct {
+	Valid []struct {
+		Name   string `json:"Name"`
+		Status string `json:"Status"`
+	} `json:"valid"`
+	Invalid []struct {
+		Name string `json:"Name"`
+	} `json:"invalid"`
+}
+
+func parseMinikubeProfiles(out []byte) ([]Cluster, error) {
+	var profiles minikubeProfiles
+	if err := json.Unmarshal(out, &profiles); err != nil {
+		return nil, fmt.Errorf("unexpected minikube output: %w", err)
+	}
+	clusters := make([]Cluster, 0, len(profiles.Valid)+len(profiles.Invalid))
+	for _, p := range profiles.Valid {
+		status := StatusUnknown
+		switch strings.ToLower(p.Status) {
+		case "running", "ok":
+			status = StatusRunning
+		case "stopped":
+			status = StatusStopped
+		}
+		clusters = append(clusters, Cluster{Tool: Minikube, Name: p.Name, Context: ContextName(Minikube, p.Name), Status: status})
+	}
+	for _, p := range profiles.Invalid {
+		clusters = append(clusters, Cluster{Tool: Minikube, Name: p.Name, Context: ContextName(Minikube, p.Name), Status: StatusUnknown})
+	}
+	return clusters, nil
+}
+
+// k3dCluster is the subset of `k3d cluster list -o json`
+type k3dCluster struct {
+	Name           string `json:"name"`
+	ServersCount   int    `json:"serversCount"`
+	ServersRunning int    `json:"serversRunning"`
+}
+
+func parseK3dClusters(out []byte) ([]Cluster, error) {
+	var list []k3dCluster
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, fmt.Errorf("unexpected k3d output: %w", err)
+	}
+	clusters := make([]Cluster, 0, len(list))
+	for _, c := range list {
+		status := StatusStopped
+		if c.ServersRunning > 0 {
+			status = StatusRunning
+		}
+		clusters = append(clusters, Cluster{Tool: K3d, Name: c.Name, Context: ContextName(K3d, c.Name), Status: status})
+	}
+	return clusters, nil
+}
+
+// run executes a CLI and returns its stdout. Failures carry the last line of
+// stderr, which is where all three tools explain what went wrong.
+func run(ctx context.Context, kubeconfig, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	if kubeconfig != "" {
+		cmd.Env = append(os.Environ(), "KUBECONFIG="+kubeconfig)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return stdout.Bytes(), fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), ctx.Err())
+		}
+		if msg := lastLine(stderr.String()); msg != "" {
+			return stdout.Bytes(), fmt.Errorf("%s %s failed: %s", name, strings.Join(args, " "), msg)
+		}
+		return stdout.Bytes(), fmt.Errorf("%s %s failed: %w", name, strings.Join(args, " "), err)
+	}
+	return stdout.Bytes(), nil
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
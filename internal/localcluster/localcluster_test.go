@@ -0,0 +1,86 @@
+package localcluster
+
+import "testing"
+
+func TestValidName(t *testing.T) {
+	for name, want := range map[string]bool{
+		"dev":        true,
+		"my-cluster": true,
+		"k8s-1-31":   true,
+		"":           false,
+		"-rm":        false,
+		"Dev":        false,
+		"a_b":        false,
+		"dev;rm":     false,
+		"a-very-long-cluster-name-that-is-too-long": false,
+	} {
+		if got := ValidName(name); got != want {
+			t.Errorf("ValidName(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestParseKindClusters(t *testing.T) {
+	clusters := parseKindClusters([]byte("dev\nci\n\n"))
+	if len(clusters) != 2 || clusters[0].Name != "dev" || clusters[1].Context != "kind-ci" {
+		t.Errorf("unexpected clusters %+v", clusters)
+	}
+	if clusters := parseKindClusters([]byte("No kind clusters found.\n")); len(clusters) != 0 {
+		t.Errorf("expected no clusters, got %+v", clusters)
+	}
+}
+
+func TestParseMinikubeProfiles(t *testing.T) {
+	clusters, err := parseMinikubeProfiles([]byte(`{
+  "invalid": [{"Name": "broken"}],
+  "valid": [
+    {"Name": "minikube", "Status": "Running", "Config": {"Driver": "docker"}},
+    {"Name": "staging", "Status": "Stopped"}
+  ]
+}`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	want := []Cluster{
+		{Tool: Minikube, Name: "minikube", Context: "minikube", Status: StatusRunning},
+		{Tool: Minikube, Name: "staging", Context: "staging", Status: StatusStopped},
+		{Tool: Minikube, Name: "broken", Context: "broken", Status: StatusUnknown},
+	}
+	if len(clusters) != len(want) {
+		t.Fatalf("expected %d clusters, got %+v", len(want), clusters)
+	}
+	for i := range want {
+		if clusters[i] != want[i] {
+			t.Errorf("cluster %d = %+v, want %+v", i, clusters[i], want[i])
+		}
+	}
+
+	if _, err := parseMinikubeProfiles([]byte("not json")); err == nil {
+		t.Error("expected an error for non-JSON output")
+	}
+}
+
+func TestParseK3dClusters(t *testing.T) {
+	clusters, err := parseK3dClusters([]byte(`[
+  {"name": "dev", "serversCount": 1, "serversRunning": 1, "agentsCount": 2, "agentsRunning": 2},
+  {"name": "old", "serversCount": 1, "serversRunning": 0}
+]`))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(clusters) != 2 || clusters[0].Status != StatusRunning || clusters[1].Status != StatusStopped || clusters[0].Context != "k3d-dev" {
+		t.Errorf("unexpected clusters %+v", clusters)
+	}
+}
+
+func TestContainerStatus(t *testing.T) {
+	if got := containerStatus([]string{"running", "running"}); got != StatusRunning {
+		t.Errorf("all running = %s", got)
+	}
+	if got := containerStatus([]string{"running", "exited"}); got != StatusStopped {
+		t.Errorf("partly exited = %s", got)
+	}
+	if got := containerStatus(nil); got != StatusUnknown {
+		t.Errorf("no containers = %s", got)
+	}
+}
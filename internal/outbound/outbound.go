@@ -0,0 +1,177 @@
+// Package outbound holds the proxy and CA settings shared by every HTTP client
+// Radar uses to reach services outside the cluster (GitHub, ArtifactHub,
+// container registries, Prometheus).
+package outbound
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/skyhook-io/radar/internal/apierror"
+)
+
+// Config holds outbound proxy and trust settings. Empty fields fall back to
+// the environment (HTTPS_PROXY, HTTP_PROXY, NO_PROXY) and the system CA pool.
+type Config struct {
+	Proxy   string // Proxy URL for HTTP and HTTPS requests
+	NoProxy string // Comma-separated hosts, domains and CIDRs that bypass the proxy
+	CAFile  string // PEM bundle trusted in addition to the system roots
+}
+
+// ErrOffline is returned by features that need the internet when Radar runs
+// with --offline
+var ErrOffline = errors.New("offline mode: external network access is disabled (--offline)")
+
+var (
+	offline atomic.Bool
+
+	mu      sync.RWMutex
+	base    = http.DefaultTransport.(*http.Transport).Clone()
+	rootCAs *x509.CertPool // nil = system roots
+)
+
+// Configure validates cfg and applies it to every transport and client handed
+// out by this package, including ones created before the call
+func Configure(cfg Config) error {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.Proxy != "" || cfg.NoProxy != "" {
+		proxyCfg := httpproxy.FromEnvironment()
+		if cfg.Proxy != "" {
+			proxyURL, err := parseProxyURL(cfg.Proxy)
+			if err != nil {
+				return err
+			}
+			proxyCfg.HTTPProxy = proxyURL.String()
+			proxyCfg.HTTPSProxy = proxyURL.String()
+		}
+		if cfg.NoProxy != "" {
+			proxyCfg.NoProxy = cfg.NoProxy
+		}
+		proxyFunc := proxyCfg.ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}
+
+	var pool *x509.CertPool
+	if cfg.CAFile != "" {
+		var err error
+		if pool, err = LoadCAFile(nil, cfg.CAFile); err != nil {
+			return err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	mu.Lock()
+	base = transport
+	rootCAs = pool
+	mu.Unlock()
+	return nil
+}
+
+// parseProxyURL accepts proxy URLs with or without a scheme, like curl
+func parseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		if u2, err2 := url.Parse("http://" + raw); err2 == nil && u2.Host != "" {
+			return u2, nil
+		}
+		return nil, fmt.Errorf("invalid proxy URL %q", raw)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+		return u, nil
+	}
+	return nil, fmt.Errorf("unsupported proxy scheme %q (use http, https or socks5)", u.Scheme)
+}
+
+// LoadCAFile appends the PEM certificates in path to pool. A nil pool starts
+// from a copy of the system roots.
+func LoadCAFile(pool *x509.CertPool, path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA file: %w", err)
+	}
+	if pool == nil {
+		if pool, err = x509.SystemCertPool(); err != nil {
+			pool = x509.NewCertPool()
+		}
+	} else {
+		pool = pool.Clone()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// SetOffline enables air-gapped mode: version checks, self-update, registry
+// and ArtifactHub calls are refused instead of timing out
+func SetOffline(enabled bool) {
+	offline.Store(enabled)
+}
+
+// Offline reports whether air-gapped mode is enabled
+func Offline() bool {
+	return offline.Load()
+}
+
+// RequireOnline is middleware that answers 503 with an offline status for
+// routes that reach the internet, when air-gapped mode is enabled
+func RequireOnline(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !Offline() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		apierror.WriteResponse(w, struct {
+			apierror.Response
+			Offline bool `json:"offline"`
+		}{apierror.New(http.StatusServiceUnavailable, apierror.Offline, ErrOffline.Error()), true})
+	})
+}
+
+// RootCAs returns the configured CA pool, or nil when the system roots apply
+func RootCAs() *x509.CertPool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return rootCAs
+}
+
+// NewTransport returns a copy of the configured transport, for callers that
+// customise TLS or wrap it further. It doesn't see later Configure calls.
+func NewTransport() *http.Transport {
+	mu.RLock()
+	defer mu.RUnlock()
+	return base.Clone()
+}
+
+// Transport is a RoundTripper that always uses the current configuration,
+// so package-level clients created at init pick up settings applied later
+var Transport http.RoundTripper = roundTripper{}
+
+type roundTripper struct{}
+
+func (roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	mu.RLock()
+	t := base
+	mu.RUnlock()
+	return t.RoundTrip(req)
+}
+
+// NewClient returns an HTTP client with the given timeout that honours the
+// configured proxy and CA bundle
+func NewClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: Transport}
+}
@@ -0,0 +1,90 @@
+package outbound
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigureProxy(t *testing.T) {
+	t.Cleanup(func() { _ = Configure(Config{}) })
+
+	if err := Configure(Config{Proxy: "proxy.corp.example:3128", NoProxy: "internal.example"}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	proxy := NewTransport().Proxy
+
+	req, _ := http.NewRequest("GET", "https://api.github.com/repos", nil)
+	u, err := proxy(req)
+	if err != nil || u == nil || u.String() != "http://proxy.corp.example:3128" {
+		t.Errorf("expected GitHub requests to use the proxy, got %v (%v)", u, err)
+	}
+	req, _ = http.NewRequest("GET", "https://registry.internal.example/v2/", nil)
+	if u, _ := proxy(req); u != nil {
+		t.Errorf("expected no-proxy hosts to go direct, got %v", u)
+	}
+
+	if err := Configure(Config{Proxy: "ftp://proxy"}); err == nil {
+		t.Error("expected an unsupported proxy scheme to be rejected")
+	}
+}
+
+func TestConfigureCAFile(t *testing.T) {
+	t.Cleanup(func() { _ = Configure(Config{}) })
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	// Clients created before Configure pick up the CA too
+	client := NewClient(0)
+	if _, err := client.Get(srv.URL); err == nil {
+		t.Fatal("expected the test server's certificate to be untrusted by default")
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Configure(Config{CAFile: caFile}); err == nil {
+		t.Error("expected a CA file without certificates to be rejected")
+	}
+
+	if err := os.WriteFile(caFile, certPEM(t, srv), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := Configure(Config{CAFile: caFile}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("expected the configured CA to be trusted: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func certPEM(t *testing.T, srv *httptest.Server) []byte {
+	t.Helper()
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+}
+
+func TestRequireOnline(t *testing.T) {
+	t.Cleanup(func() { SetOffline(false) })
+	handler := RequireOnline(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/images/updates", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected requests to pass when online, got %d", rec.Code)
+	}
+
+	SetOffline(true)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/images/updates", nil))
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), `"offline":true`) {
+		t.Errorf("expected 503 with an offline status, got %d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,79 @@
+package schedules
+
+import (
+	"testing"
+	"time"
+)
+
+func cronTime(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, time.UTC)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"0 20 * *",       // Too few fields
+		"60 * * * *",     // Minute out of range
+		"0 24 * * *",     // Hour out of range
+		"0 0 0 * *",      // Day of month starts at 1
+		"0 0 * 13 *",     // Month out of range
+		"0 0 * * 8",      // Weekday out of range
+		"*/0 * * * *",    // Zero step
+		"0 18-8 * * *",   // Reversed range
+		"0 0 * * funday", // Unknown name
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("%q: expected an error", expr)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	tests := []struct {
+		expr string
+		from string
+		want string
+	}{
+		{"0 20 * * 1-5", "2025-03-07 19:59", "2025-03-07 20:00"}, // Friday evening
+		{"0 20 * * 1-5", "2025-03-07 20:00", "2025-03-10 20:00"}, // Skips the weekend
+		{"*/15 9-17 * * *", "2025-03-07 09:16", "2025-03-07 09:30"},
+		{"0 8 * * mon", "2025-03-07 12:00", "2025-03-10 08:00"},
+		{"0 0 * * 7", "2025-03-07 12:00", "2025-03-09 00:00"}, // 7 is Sunday
+		{"30 6 1 jan,jul *", "2025-03-07 12:00", "2025-07-01 06:30"},
+		{"0 0 13 * 5", "2025-03-07 12:00", "2025-03-13 00:00"}, // Day of month or weekday
+		{"5/20 * * * *", "2025-03-07 12:00", "2025-03-07 12:05"},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.expr, err)
+		}
+		if got := c.Next(cronTime(tt.from)); !got.Equal(cronTime(tt.want)) {
+			t.Errorf("%q after %s: expected %s, got %s", tt.expr, tt.from, tt.want, got.Format("2006-01-02 15:04"))
+		}
+	}
+}
+
+func TestCronPrev(t *testing.T) {
+	c, err := ParseCron("0 20 * * 1-5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Sunday: the last weekday evening was Friday's
+	if got := c.Prev(cronTime("2025-03-09 12:00")); !got.Equal(cronTime("2025-03-07 20:00")) {
+		t.Errorf("expected Friday 20:00, got %s", got)
+	}
+	// A firing minute is its own previous firing
+	if got := c.Prev(cronTime("2025-03-07 20:00")); !got.Equal(cronTime("2025-03-07 20:00")) {
+		t.Errorf("expected 20:00 itself, got %s", got)
+	}
+
+	never, _ := ParseCron("0 0 30 2 *") // February 30th
+	if got := never.Prev(cronTime("2025-03-07 12:00")); !got.IsZero() {
+		t.Errorf("expected no previous firing, got %s", got)
+	}
+}
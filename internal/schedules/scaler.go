@@ -0,0 +1,294 @@
+package schedules
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/settings"
+)
+
+// ReplicasAnnotation holds the replica count a scaled-down workload is
+// restored to
+const ReplicasAnnotation = "radar.skyhook.io/sleep-replicas"
+
+const (
+	checkInterval = time.Minute
+	scaleTimeout  = 2 * time.Minute
+)
+
+// errReadOnly is recorded on schedules that are due while read-only mode is on
+var errReadOnly = errors.New("not applied: Radar is in read-only mode")
+
+// target is a workload a schedule scales
+type target struct {
+	kind      string // Deployment or StatefulSet
+	namespace string
+	name      string
+	replicas  int32
+	saved     string // ReplicasAnnotation value ("" = not scaled down by Radar)
+}
+
+func (t target) key() string { return t.kind + "/" + t.namespace + "/" + t.name }
+
+// resolveTargets lists the Deployments and StatefulSets a schedule selects
+// from the cache. Workloads that don't exist (yet) are skipped.
+func resolveTargets(s *Schedule) ([]target, error) {
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		return nil, fmt.Errorf("not connected to a cluster")
+	}
+	deployments, statefulSets := cache.Deployments(), cache.StatefulSets()
+	if deployments == nil || statefulSets == nil {
+		return nil, fmt.Errorf("insufficient permissions to list deployments and statefulsets")
+	}
+
+	seen := make(map[string]bool)
+	var targets []target
+	add := func(kind string, obj metav1.Object, replicas *int32) {
+		t := target{kind: kind, namespace: obj.GetNamespace(), name: obj.GetName(), replicas: 1, saved: obj.GetAnnotations()[ReplicasAnnotation]}
+		if replicas != nil {
+			t.replicas = *replicas
+		}
+		if !seen[t.key()] {
+			seen[t.key()] = true
+			targets = append(targets, t)
+		}
+	}
+	for _, ns := range s.Namespaces {
+		deps, _ := deployments.Deployments(ns).List(labels.Everything())
+		for _, d := range deps {
+			add("Deployment", d, d.Spec.Replicas)
+		}
+		sets, _ := statefulSets.StatefulSets(ns).List(labels.Everything())
+		for _, ss := range sets {
+			add("StatefulSet", ss, ss.Spec.Replicas)
+		}
+	}
+	for _, w := range s.Workloads {
+		switch w.Kind {
+		case "Deployment":
+			if d, err := deployments.Deployments(w.Namespace).Get(w.Name); err == nil {
+				add("Deployment", d, d.Spec.Replicas)
+			}
+		case "StatefulSet":
+			if ss, err := statefulSets.StatefulSets(w.Namespace).Get(w.Name); err == nil {
+				add("StatefulSet", ss, ss.Spec.Replicas)
+			}
+		}
+	}
+	return targets, nil
+}
+
+// scaleDown scales targets to zero, saving their replicas in an annotation.
+// Workloads already at zero are left alone, so they stay at zero on wake.
+func scaleDown(ctx context.Context, targets []target) error {
+	var errs []string
+	for _, t := range targets {
+		if t.saved != "" || t.replicas == 0 {
+			continue
+		}
+		if err := patchWorkload(ctx, t, 0, strconv.Itoa(int(t.replicas))); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	return joinErrors(errs)
+}
+
+// scaleUp restores targets Radar scaled down and removes the annotation
+func scaleUp(ctx context.Context, targets []target) error {
+	var errs []string
+	for _, t := range targets {
+		if t.saved == "" {
+			continue
+		}
+		replicas, err := strconv.Atoi(t.saved)
+		if err != nil || replicas < 0 {
+			errs = append(errs, fmt.Sprintf("%s: invalid %s annotation %q", t.key(), ReplicasAnnotation, t.saved))
+			continue
+		}
+		if err := patchWorkload(ctx, t, int32(replicas), ""); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	return joinErrors(errs)
+}
+
+// patchWorkload sets a workload's replicas and the saved-replicas
+// annotation ("" removes it)
+func patchWorkload(ctx context.Context, t target, replicas int32, saved string) error {
+	client := k8s.GetDynamicClient()
+	discovery := k8s.GetResourceDiscovery()
+	if client == nil || discovery == nil {
+		return fmt.Errorf("not connected to a cluster")
+	}
+	gvr, ok := discovery.GetGVRWithGroup(t.kind, "apps")
+	if !ok {
+		return fmt.Errorf("unknown resource kind: %s", t.kind)
+	}
+	var annotation any
+	if saved != "" {
+		annotation = saved
+	}
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{"annotations": map[string]any{ReplicasAnnotation: annotation}},
+		"spec":     map[string]any{"replicas": replicas},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := client.Resource(gvr).Namespace(t.namespace).Patch(ctx, t.name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("%s: failed to scale to %d: %w", t.key(), replicas, err)
+	}
+	return nil
+}
+
+func joinErrors(errs []string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(errs, "; "))
+}
+
+// reconcileMu serializes transitions between the scheduler and API calls
+var reconcileMu sync.Mutex
+
+var schedulerOnce sync.Once
+
+// Start runs the scheduler, which checks schedules every minute. It only
+// acts on schedules of the connected context, so it keeps working across
+// context switches.
+func Start() {
+	schedulerOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(checkInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				Reconcile(context.Background(), time.Now())
+			}
+		}()
+	})
+}
+
+// Reconcile scales the connected context's schedules whose state doesn't
+// match what they should be at now, and ends expired overrides. Workloads
+// are only scaled on transitions, so manual changes in between are kept.
+func Reconcile(ctx context.Context, now time.Time) {
+	if !k8s.IsConnected() {
+		return
+	}
+	reconcileMu.Lock()
+	defer reconcileMu.Unlock()
+
+	contextName := k8s.GetContextName()
+	for _, st := range List(now) {
+		s := st.Schedule
+		if s.Context != contextName {
+			continue
+		}
+		if s.OverrideUntil != nil && !now.Before(*s.OverrideUntil) {
+			if _, err := SetOverride(s.Name, nil, now); err == nil {
+				s.OverrideUntil = nil
+			}
+		}
+		p, err := s.parse()
+		if err != nil {
+			continue
+		}
+		want := s.wantAsleep(p, now)
+		if want == s.Asleep {
+			if s.LastError != "" {
+				record(s.Name, s.Asleep, nil, now) // e.g. an override made a failed scale down moot
+			}
+			continue
+		}
+		if settings.Get().ReadOnly {
+			record(s.Name, s.Asleep, errReadOnly, now)
+			continue
+		}
+		err = transition(ctx, &s, want)
+		if err != nil {
+			log.Printf("[schedules] %s: %v", s.Name, err)
+		} else if want {
+			log.Printf("[schedules] %s: scaled down", s.Name)
+		} else {
+			log.Printf("[schedules] %s: scaled back up", s.Name)
+		}
+		record(s.Name, want, err, now)
+	}
+}
+
+func transition(ctx context.Context, s *Schedule, asleep bool) error {
+	ctx, cancel := context.WithTimeout(ctx, scaleTimeout)
+	defer cancel()
+	targets, err := resolveTargets(s)
+	if err != nil {
+		return err
+	}
+	if asleep {
+		return scaleDown(ctx, targets)
+	}
+	return scaleUp(ctx, targets)
+}
+
+// Retarget applies an edit of a schedule that was asleep: workloads it no
+// longer selects are scaled back up and newly selected ones scaled down
+func Retarget(ctx context.Context, previous, current Schedule) error {
+	if previous.Context != k8s.GetContextName() || !k8s.IsConnected() {
+		return nil
+	}
+	reconcileMu.Lock()
+	defer reconcileMu.Unlock()
+	ctx, cancel := context.WithTimeout(ctx, scaleTimeout)
+	defer cancel()
+
+	before, err := resolveTargets(&previous)
+	if err != nil {
+		return err
+	}
+	after, err := resolveTargets(&current)
+	if err != nil {
+		return err
+	}
+	kept := make(map[string]bool, len(after))
+	for _, t := range after {
+		kept[t.key()] = true
+	}
+	var dropped []target
+	for _, t := range before {
+		if !kept[t.key()] {
+			dropped = append(dropped, t)
+		}
+	}
+	var errs []string
+	if err := scaleUp(ctx, dropped); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if current.Context == previous.Context && current.Asleep {
+		if err := scaleDown(ctx, after); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	return joinErrors(errs)
+}
+
+// Wake scales a deleted schedule's workloads back up if it was asleep
+func Wake(ctx context.Context, s Schedule) error {
+	if !s.Asleep || s.Context != k8s.GetContextName() || !k8s.IsConnected() {
+		return nil
+	}
+	reconcileMu.Lock()
+	defer reconcileMu.Unlock()
+	return transition(ctx, &s, false)
+}
@@ -0,0 +1,326 @@
+// Package schedules scales workloads to zero on a cron schedule (e.g. dev
+// namespaces at night and on weekends) and restores them afterwards.
+// Schedules are saved to a JSON file; the replica counts to restore are kept
+// in an annotation on each workload, so they survive Radar restarts.
+package schedules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrInvalidSchedule is returned when a schedule fails validation
+	ErrInvalidSchedule = errors.New("invalid schedule")
+	// ErrNotFound is returned for a schedule that doesn't exist
+	ErrNotFound = errors.New("schedule not found")
+)
+
+var nameRe = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// WorkloadRef identifies a Deployment or StatefulSet
+type WorkloadRef struct {
+	Kind      string `json:"kind"` // Deployment or StatefulSet
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// Schedule scales its workloads to zero when ScaleDown fires and restores
+// their replicas when ScaleUp fires
+type Schedule struct {
+	Name       string        `json:"name"`
+	Context    string        `json:"context"`              // Kubeconfig context it applies to
+	Namespaces []string      `json:"namespaces,omitempty"` // Every Deployment and StatefulSet in these namespaces
+	Workloads  []WorkloadRef `json:"workloads,omitempty"`
+	ScaleDown  string        `json:"scaleDown"`          // Cron, e.g. "0 20 * * 1-5"
+	ScaleUp    string        `json:"scaleUp"`            // Cron, e.g. "0 8 * * 1-5"
+	Timezone   string        `json:"timezone,omitempty"` // IANA name (default: local time)
+	Disabled   bool          `json:"disabled,omitempty"`
+
+	// Maintained by the scheduler
+	Asleep         bool       `json:"asleep"`                  // Workloads are scaled down
+	OverrideUntil  *time.Time `json:"overrideUntil,omitempty"` // Kept awake until then
+	LastTransition *time.Time `json:"lastTransition,omitempty"`
+	LastError      string     `json:"lastError,omitempty"`
+}
+
+// Status is a schedule with its upcoming transitions
+type Status struct {
+	Schedule
+	NextScaleDown *time.Time `json:"nextScaleDown,omitempty"`
+	NextScaleUp   *time.Time `json:"nextScaleUp,omitempty"`
+}
+
+// parsed holds a schedule's parsed crons and location
+type parsed struct {
+	down, up Cron
+	loc      *time.Location
+}
+
+func (s *Schedule) parse() (parsed, error) {
+	var p parsed
+	var err error
+	if p.down, err = ParseCron(s.ScaleDown); err != nil {
+		return p, fmt.Errorf("%w: scaleDown: %v", ErrInvalidSchedule, err)
+	}
+	if p.up, err = ParseCron(s.ScaleUp); err != nil {
+		return p, fmt.Errorf("%w: scaleUp: %v", ErrInvalidSchedule, err)
+	}
+	p.loc = time.Local
+	if s.Timezone != "" {
+		if p.loc, err = time.LoadLocation(s.Timezone); err != nil {
+			return p, fmt.Errorf("%w: unknown timezone %q", ErrInvalidSchedule, s.Timezone)
+		}
+	}
+	return p, nil
+}
+
+// inSleepWindow reports whether ScaleDown fired more recently than ScaleUp
+func (p parsed) inSleepWindow(now time.Time) bool {
+	now = now.In(p.loc)
+	down := p.down.Prev(now)
+	return !down.IsZero() && down.After(p.up.Prev(now))
+}
+
+// wantAsleep is the state the schedule's workloads should be in at now
+func (s *Schedule) wantAsleep(p parsed, now time.Time) bool {
+	if s.Disabled || (s.OverrideUntil != nil && now.Before(*s.OverrideUntil)) {
+		return false
+	}
+	return p.inSleepWindow(now)
+}
+
+// defaultOverrideEnd keeps a schedule awake through the current sleep window,
+// or the next one when it's awake now
+func (p parsed) defaultOverrideEnd(now time.Time) time.Time {
+	from := now.In(p.loc)
+	if !p.inSleepWindow(now) {
+		from = p.down.Next(from)
+	}
+	return p.up.Next(from)
+}
+
+func validate(s *Schedule) (parsed, error) {
+	if !nameRe.MatchString(s.Name) {
+		return parsed{}, fmt.Errorf("%w: name must be lowercase alphanumeric or '-' (max 63 characters)", ErrInvalidSchedule)
+	}
+	if s.Context == "" {
+		return parsed{}, fmt.Errorf("%w: context is required", ErrInvalidSchedule)
+	}
+	if len(s.Namespaces) == 0 && len(s.Workloads) == 0 {
+		return parsed{}, fmt.Errorf("%w: select namespaces or workloads to scale", ErrInvalidSchedule)
+	}
+	for i, w := range s.Workloads {
+		switch strings.ToLower(w.Kind) {
+		case "deployment", "deployments":
+			s.Workloads[i].Kind = "Deployment"
+		case "statefulset", "statefulsets":
+			s.Workloads[i].Kind = "StatefulSet"
+		default:
+			return parsed{}, fmt.Errorf("%w: %s %s/%s can't be scaled (only Deployments and StatefulSets)", ErrInvalidSchedule, w.Kind, w.Namespace, w.Name)
+		}
+		if w.Namespace == "" || w.Name == "" {
+			return parsed{}, fmt.Errorf("%w: workloads need a namespace and name", ErrInvalidSchedule)
+		}
+	}
+	return s.parse()
+}
+
+// store holds the schedules of every context
+var store = struct {
+	mu        sync.Mutex
+	schedules map[string]*Schedule
+	path      string // JSON file schedules are saved to ("" = memory only)
+}{schedules: make(map[string]*Schedule)}
+
+// DefaultPath returns scale-schedules.json next to the settings file
+func DefaultPath(settingsPath string) string {
+	if settingsPath == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(settingsPath), "scale-schedules.json")
+}
+
+// Load sets the file schedules are saved to and loads the ones saved there.
+// A missing file is not an error.
+func Load(path string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.path = path
+	store.schedules = make(map[string]*Schedule)
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read scale schedules: %w", err)
+	}
+	var saved []Schedule
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("failed to parse scale schedules %s: %w", path, err)
+	}
+	for i := range saved {
+		if _, err := validate(&saved[i]); err == nil {
+			store.schedules[saved[i].Name] = &saved[i]
+		}
+	}
+	return nil
+}
+
+// List returns all schedules sorted by name, with their next transitions
+func List(now time.Time) []Status {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	result := make([]Status, 0, len(store.schedules))
+	for _, s := range store.schedules {
+		result = append(result, statusOf(s, now))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Get returns a schedule by name
+func Get(name string, now time.Time) (Status, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	s, ok := store.schedules[name]
+	if !ok {
+		return Status{}, ErrNotFound
+	}
+	return statusOf(s, now), nil
+}
+
+func statusOf(s *Schedule, now time.Time) Status {
+	st := Status{Schedule: *s}
+	st.Namespaces = append([]string(nil), s.Namespaces...)
+	st.Workloads = append([]WorkloadRef(nil), s.Workloads...)
+	p, err := s.parse()
+	if err != nil || s.Disabled {
+		return st
+	}
+	local := now.In(p.loc)
+	if next := p.down.Next(local); !next.IsZero() {
+		st.NextScaleDown = &next
+	}
+	if next := p.up.Next(local); !next.IsZero() {
+		st.NextScaleUp = &next
+	}
+	return st
+}
+
+// Save creates or replaces a schedule, keeping the state of an existing one.
+// When it replaces a schedule that is asleep, the old one is returned so the
+// caller can apply the new targets right away (see Retarget).
+func Save(s Schedule) (saved Schedule, previous *Schedule, err error) {
+	if _, err := validate(&s); err != nil {
+		return Schedule{}, nil, err
+	}
+	s.Asleep, s.OverrideUntil, s.LastTransition, s.LastError = false, nil, nil, ""
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if existing, ok := store.schedules[s.Name]; ok {
+		s.OverrideUntil, s.LastTransition = existing.OverrideUntil, existing.LastTransition
+		s.Asleep = existing.Asleep && existing.Context == s.Context
+		if existing.Asleep {
+			prev := *existing
+			previous = &prev
+		}
+	}
+	store.schedules[s.Name] = &s
+	return s, previous, persistLocked()
+}
+
+// Delete removes a schedule, returning it so the caller can wake its workloads
+func Delete(name string) (Schedule, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	s, ok := store.schedules[name]
+	if !ok {
+		return Schedule{}, ErrNotFound
+	}
+	delete(store.schedules, name)
+	return *s, persistLocked()
+}
+
+// SetOverride keeps a schedule's workloads awake until the given time; the
+// zero time means through the current (or next) sleep window. A nil until
+// ends the override.
+func SetOverride(name string, until *time.Time, now time.Time) (Status, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	s, ok := store.schedules[name]
+	if !ok {
+		return Status{}, ErrNotFound
+	}
+	if until != nil && until.IsZero() {
+		p, err := s.parse()
+		if err != nil {
+			return Status{}, err
+		}
+		end := p.defaultOverrideEnd(now)
+		if end.IsZero() {
+			return Status{}, fmt.Errorf("%w: scaleUp never fires", ErrInvalidSchedule)
+		}
+		until = &end
+	}
+	if until != nil && !until.After(now) {
+		return Status{}, fmt.Errorf("%w: override must end in the future", ErrInvalidSchedule)
+	}
+	s.OverrideUntil = until
+	return statusOf(s, now), persistLocked()
+}
+
+// record stores the outcome of a transition attempt; recording the current
+// state without an error clears a previous error
+func record(name string, asleep bool, err error, now time.Time) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	s, ok := store.schedules[name]
+	if !ok {
+		return
+	}
+	if err != nil {
+		s.LastError = err.Error()
+	} else {
+		if s.Asleep != asleep {
+			s.LastTransition = &now
+		}
+		s.Asleep, s.LastError = asleep, ""
+	}
+	_ = persistLocked()
+}
+
+// persistLocked writes schedules to disk. Caller must hold store.mu.
+func persistLocked() error {
+	if store.path == "" {
+		return nil
+	}
+	list := make([]*Schedule, 0, len(store.schedules))
+	for _, s := range store.schedules {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(store.path), 0755); err != nil {
+		return fmt.Errorf("failed to save scale schedules: %w", err)
+	}
+	if err := os.WriteFile(store.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to save scale schedules: %w", err)
+	}
+	return nil
+}
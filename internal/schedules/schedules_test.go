@@ -0,0 +1,186 @@
+package schedules
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func weeknights() Schedule {
+	return Schedule{
+		Name:       "dev-nights",
+		Context:    "dev",
+		Namespaces: []string{"dev"},
+		ScaleDown:  "0 20 * * 1-5",
+		ScaleUp:    "0 8 * * 1-5",
+		Timezone:   "UTC",
+	}
+}
+
+func TestWantAsleep(t *testing.T) {
+	s := weeknights()
+	p, err := validate(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		at   string
+		want bool
+	}{
+		{"2025-03-06 19:59", false}, // Thursday before the scale down
+		{"2025-03-06 20:00", true},
+		{"2025-03-07 07:59", true},
+		{"2025-03-07 08:00", false},
+		{"2025-03-08 12:00", true}, // Saturday: asleep since Friday evening
+		{"2025-03-10 08:30", false},
+	}
+	for _, tt := range tests {
+		if got := s.wantAsleep(p, cronTime(tt.at)); got != tt.want {
+			t.Errorf("%s: expected asleep=%v, got %v", tt.at, tt.want, got)
+		}
+	}
+
+	override := cronTime("2025-03-08 18:00")
+	s.OverrideUntil = &override
+	if s.wantAsleep(p, cronTime("2025-03-08 12:00")) {
+		t.Error("expected an override to keep the schedule awake")
+	}
+	if !s.wantAsleep(p, cronTime("2025-03-08 18:00")) {
+		t.Error("expected the schedule to apply again once the override ends")
+	}
+
+	s.OverrideUntil = nil
+	s.Disabled = true
+	if s.wantAsleep(p, cronTime("2025-03-08 12:00")) {
+		t.Error("expected a disabled schedule to stay awake")
+	}
+}
+
+func TestDefaultOverrideEnd(t *testing.T) {
+	s := weeknights()
+	p, _ := validate(&s)
+	// Asleep on Saturday: awake until Monday morning's scale up
+	if got := p.defaultOverrideEnd(cronTime("2025-03-08 12:00")); !got.Equal(cronTime("2025-03-10 08:00")) {
+		t.Errorf("expected Monday 08:00, got %s", got)
+	}
+	// Awake on Thursday afternoon: skips that night
+	if got := p.defaultOverrideEnd(cronTime("2025-03-06 15:00")); !got.Equal(cronTime("2025-03-07 08:00")) {
+		t.Errorf("expected Friday 08:00, got %s", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := map[string]func(*Schedule){
+		"bad name":     func(s *Schedule) { s.Name = "Dev Nights" },
+		"no context":   func(s *Schedule) { s.Context = "" },
+		"no targets":   func(s *Schedule) { s.Namespaces = nil },
+		"bad cron":     func(s *Schedule) { s.ScaleUp = "8am" },
+		"bad timezone": func(s *Schedule) { s.Timezone = "Mars/Olympus" },
+		"bad kind": func(s *Schedule) {
+			s.Workloads = []WorkloadRef{{Kind: "DaemonSet", Namespace: "dev", Name: "agent"}}
+		},
+	}
+	for name, mutate := range tests {
+		s := weeknights()
+		mutate(&s)
+		if _, err := validate(&s); !errors.Is(err, ErrInvalidSchedule) {
+			t.Errorf("%s: expected ErrInvalidSchedule, got %v", name, err)
+		}
+	}
+
+	s := weeknights()
+	s.Workloads = []WorkloadRef{{Kind: "deployments", Namespace: "dev", Name: "api"}}
+	if _, err := validate(&s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Workloads[0].Kind != "Deployment" {
+		t.Errorf("expected the kind normalized to Deployment, got %s", s.Workloads[0].Kind)
+	}
+}
+
+func TestSaveKeepsStateAndPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scale-schedules.json")
+	if err := Load(path); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Load("") })
+
+	if _, previous, err := Save(weeknights()); err != nil || previous != nil {
+		t.Fatalf("unexpected result: previous %v, err %v", previous, err)
+	}
+	record("dev-nights", true, nil, cronTime("2025-03-06 20:00"))
+
+	// Editing a sleeping schedule keeps it asleep and returns the old one
+	edited := weeknights()
+	edited.Namespaces = []string{"dev", "preview"}
+	edited.Asleep = false // Ignored: state is the scheduler's
+	saved, previous, err := Save(edited)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !saved.Asleep || previous == nil || len(previous.Namespaces) != 1 {
+		t.Fatalf("expected the edit to stay asleep and return the previous schedule, got %+v / %+v", saved, previous)
+	}
+
+	// Moving it to another context starts awake
+	edited.Context = "staging"
+	if saved, _, _ := Save(edited); saved.Asleep {
+		t.Error("expected a schedule moved to another context to start awake")
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected schedules saved to disk: %v", err)
+	}
+	if err := Load(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Get("dev-nights", cronTime("2025-03-06 12:00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Context != "staging" || len(got.Namespaces) != 2 || got.LastTransition == nil {
+		t.Errorf("unexpected schedule after reload: %+v", got)
+	}
+	if got.NextScaleDown == nil || !got.NextScaleDown.Equal(cronTime("2025-03-06 20:00")) {
+		t.Errorf("expected the next scale down at 20:00, got %v", got.NextScaleDown)
+	}
+
+	if _, err := Delete("dev-nights"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get("dev-nights", time.Now()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestSetOverride(t *testing.T) {
+	if err := Load(""); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Save(weeknights()); err != nil {
+		t.Fatal(err)
+	}
+	now := cronTime("2025-03-08 12:00")
+
+	status, err := SetOverride("dev-nights", &time.Time{}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.OverrideUntil == nil || !status.OverrideUntil.Equal(cronTime("2025-03-10 08:00")) {
+		t.Errorf("expected the default override to end Monday 08:00, got %v", status.OverrideUntil)
+	}
+
+	past := now.Add(-time.Hour)
+	if _, err := SetOverride("dev-nights", &past, now); !errors.Is(err, ErrInvalidSchedule) {
+		t.Errorf("expected an override ending in the past to be rejected, got %v", err)
+	}
+
+	if status, err := SetOverride("dev-nights", nil, now); err != nil || status.OverrideUntil != nil {
+		t.Errorf("expected the override cleared, got %v (err %v)", status.OverrideUntil, err)
+	}
+	if _, err := SetOverride("missing", nil, now); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
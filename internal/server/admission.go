@@ -0,0 +1,45 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// maxAdmissionManifestSize caps the manifest accepted by the admission simulator
+const maxAdmissionManifestSize = 1 << 20
+
+// handleSimulateAdmission reports what admission would do to a manifest
+// POST /api/admission/simulate?namespace=X (body: YAML or JSON manifest)
+func (s *Server) handleSimulateAdmission(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxAdmissionManifestSize+1))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+	defer r.Body.Close()
+	if len(body) > maxAdmissionManifestSize {
+		s.writeError(w, http.StatusRequestEntityTooLarge, "manifest too large")
+		return
+	}
+
+	result, err := k8s.SimulateAdmission(r.Context(), body, r.URL.Query().Get("namespace"))
+	if err != nil {
+		if errors.Is(err, k8s.ErrInvalidManifest) {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("[admission] Failed to simulate admission: %v", err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.writeJSON(w, result)
+}
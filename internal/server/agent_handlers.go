@@ -0,0 +1,126 @@
+package server
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/agent"
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// ServeAgents accepts remote cluster agents on addr, authenticated by token.
+// With certFile and keyFile the listener uses TLS, otherwise plaintext.
+// Must be called before Start.
+func (s *Server) ServeAgents(addr, token, certFile, keyFile string) error {
+	hub, err := agent.NewHub(token)
+	if err != nil {
+		return err
+	}
+	var tlsConfig *tls.Config
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("load agent TLS certificate: %w", err)
+		}
+		tlsConfig = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", addr, err)
+	}
+	s.agents = hub
+	go func() {
+		if err := hub.Serve(ln, tlsConfig); err != nil {
+			log.Printf("[agent] Listener stopped: %v", err)
+		}
+	}()
+	if tlsConfig == nil {
+		log.Printf("Accepting agents on %s (plaintext; use a TLS certificate outside trusted networks)", addr)
+	} else {
+		log.Printf("Accepting agents on %s (TLS)", addr)
+	}
+	return nil
+}
+
+// requireAgents writes a 404 and returns nil when agent mode is off
+func (s *Server) requireAgents(w http.ResponseWriter) *agent.Hub {
+	if s.agents == nil {
+		s.writeError(w, http.StatusNotFound, "agent mode is not enabled (start Radar with --agent-listen)")
+		return nil
+	}
+	return s.agents
+}
+
+func (s *Server) handleListAgentClusters(w http.ResponseWriter, r *http.Request) {
+	hub := s.requireAgents(w)
+	if hub == nil {
+		return
+	}
+	s.writeJSON(w, hub.Clusters())
+}
+
+func (s *Server) handleAgentTopology(w http.ResponseWriter, r *http.Request) {
+	hub := s.requireAgents(w)
+	if hub == nil {
+		return
+	}
+	topo, err := hub.Topology(chi.URLParam(r, "cluster"))
+	if err != nil {
+		s.writeAgentError(w, err)
+		return
+	}
+	s.writeJSON(w, topo)
+}
+
+func (s *Server) handleAgentTimeline(w http.ResponseWriter, r *http.Request) {
+	hub := s.requireAgents(w)
+	if hub == nil {
+		return
+	}
+	loc, err := parseTimezone(r.URL.Query())
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	events, err := hub.Events(chi.URLParam(r, "cluster"), agentLimit(r))
+	if err != nil {
+		s.writeAgentError(w, err)
+		return
+	}
+	timeline.InZone(events, loc)
+	s.writeJSON(w, events)
+}
+
+func (s *Server) handleAgentChanges(w http.ResponseWriter, r *http.Request) {
+	hub := s.requireAgents(w)
+	if hub == nil {
+		return
+	}
+	changes, err := hub.Changes(chi.URLParam(r, "cluster"), agentLimit(r))
+	if err != nil {
+		s.writeAgentError(w, err)
+		return
+	}
+	s.writeJSON(w, changes)
+}
+
+func (s *Server) writeAgentError(w http.ResponseWriter, err error) {
+	if errors.Is(err, agent.ErrUnknownCluster) {
+		s.writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	s.writeError(w, http.StatusInternalServerError, err.Error())
+}
+
+// agentLimit parses ?limit=, where 0 or an invalid value means everything kept
+func agentLimit(r *http.Request) int {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	return limit
+}
@@ -0,0 +1,191 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// workflowStepContainer is the container Argo runs a step's template in
+const workflowStepContainer = "main"
+
+// handleWorkflowDAG returns a Workflow's steps as a graph with phase and duration per step
+func (s *Server) handleWorkflowDAG(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	wf, err := k8s.GetDynamicResourceCache().Get(k8s.WorkflowGVR, namespace, name)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			s.writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		log.Printf("[argo-workflows] Failed to get workflow %s/%s: %v", namespace, name, err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.writeJSON(w, k8s.ParseWorkflowDAG(wf, time.Now()))
+}
+
+// handleWorkflowRetry re-runs the failed steps of a Failed or Errored Workflow
+func (s *Server) handleWorkflowRetry(w http.ResponseWriter, r *http.Request) {
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	client := k8s.GetDynamicClient()
+	clientset := k8s.GetClient()
+	if client == nil || clientset == nil {
+		log.Printf("[argo-workflows] Clients unavailable for retry Workflow %s/%s", namespace, name)
+		s.writeError(w, http.StatusServiceUnavailable, "kubernetes client not available")
+		return
+	}
+
+	wf, err := client.Resource(k8s.WorkflowGVR).Namespace(namespace).Get(r.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			s.writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		log.Printf("[argo-workflows] Failed to get workflow %s/%s for retry: %v", namespace, name, err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	podsToDelete, err := k8s.PrepareWorkflowRetry(wf)
+	if err != nil {
+		s.writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	// Delete failed step pods first so the controller doesn't adopt their old status
+	for _, pod := range podsToDelete {
+		err := clientset.CoreV1().Pods(namespace).Delete(r.Context(), pod, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			log.Printf("[argo-workflows] Failed to delete pod %s/%s for retry: %v", namespace, pod, err)
+			s.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to delete pod %s: %v", pod, err))
+			return
+		}
+	}
+
+	// Workflow status is not a subresource, so a regular update resets it
+	if _, err := client.Resource(k8s.WorkflowGVR).Namespace(namespace).Update(r.Context(), wf, metav1.UpdateOptions{}); err != nil {
+		if apierrors.IsConflict(err) {
+			s.writeError(w, http.StatusConflict, "workflow was modified, try again")
+			return
+		}
+		log.Printf("[argo-workflows] Failed to retry workflow %s/%s: %v", namespace, name, err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.writeJSON(w, GitOpsOperationResponse{
+		Message:     fmt.Sprintf("Retrying %d failed step(s)", len(podsToDelete)),
+		Operation:   "retry",
+		Tool:        "argo-workflows",
+		Resource:    GitOpsResourceRef{Kind: "Workflow", Name: name, Namespace: namespace},
+		RequestedAt: time.Now().Format(time.RFC3339Nano),
+	})
+}
+
+// handleWorkflowTerminate stops a running Workflow by setting spec.shutdown.
+// ?mode=stop lets exit handlers run; the default (terminate) skips them.
+func (s *Server) handleWorkflowTerminate(w http.ResponseWriter, r *http.Request) {
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	shutdown := "Terminate"
+	switch r.URL.Query().Get("mode") {
+	case "", "terminate":
+	case "stop":
+		shutdown = "Stop"
+	default:
+		s.writeError(w, http.StatusBadRequest, "invalid mode: must be 'terminate' or 'stop'")
+		return
+	}
+
+	client := k8s.GetDynamicClient()
+	if client == nil {
+		log.Printf("[argo-workflows] Dynamic client unavailable for terminate Workflow %s/%s", namespace, name)
+		s.writeError(w, http.StatusServiceUnavailable, "dynamic client not available")
+		return
+	}
+
+	wf, err := client.Resource(k8s.WorkflowGVR).Namespace(namespace).Get(r.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			s.writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		log.Printf("[argo-workflows] Failed to get workflow %s/%s for terminate: %v", namespace, name, err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	phase, _, _ := unstructured.NestedString(wf.Object, "status", "phase")
+	if phase != "" && phase != "Pending" && phase != "Running" {
+		s.writeError(w, http.StatusConflict, fmt.Sprintf("workflow is not running (phase: %s)", phase))
+		return
+	}
+
+	patchBytes, err := json.Marshal(map[string]any{"spec": map[string]any{"shutdown": shutdown}})
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to create patch")
+		return
+	}
+	if _, err := client.Resource(k8s.WorkflowGVR).Namespace(namespace).Patch(r.Context(), name, types.MergePatchType, patchBytes, metav1.PatchOptions{}); err != nil {
+		log.Printf("[argo-workflows] Failed to terminate workflow %s/%s: %v", namespace, name, err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.writeJSON(w, GitOpsOperationResponse{
+		Message:     fmt.Sprintf("Workflow shutdown (%s) requested", strings.ToLower(shutdown)),
+		Operation:   "terminate",
+		Tool:        "argo-workflows",
+		Resource:    GitOpsResourceRef{Kind: "Workflow", Name: name, Namespace: namespace},
+		RequestedAt: time.Now().Format(time.RFC3339Nano),
+	})
+}
+
+// handleWorkflowStepLogsStream streams logs of the pod that ran a workflow step using SSE.
+// Defaults to the step's main container; ?container= selects init/wait instead.
+func (s *Server) handleWorkflowStepLogsStream(w http.ResponseWriter, r *http.Request) {
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+	nodeID := chi.URLParam(r, "nodeId")
+
+	wf, err := k8s.GetDynamicResourceCache().Get(k8s.WorkflowGVR, namespace, name)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			s.writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	pod, err := k8s.FindWorkflowStepPod(wf, nodeID)
+	if err != nil {
+		s.writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	container := r.URL.Query().Get("container")
+	if container == "" {
+		container = workflowStepContainer
+	}
+	s.streamPodLogsSSE(w, r, namespace, pod.Name, container, false, parseTailLines(r.URL.Query().Get("tailLines"), 100))
+}
@@ -0,0 +1,17 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// handleScalingDecisions returns cluster-autoscaler and Karpenter state correlated with unschedulable pods
+// GET /api/autoscaling/decisions?namespaces=a,b
+func (s *Server) handleScalingDecisions(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	s.writeJSON(w, k8s.GetScalingDecisions(parseNamespaces(r.URL.Query())))
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// CAPIClustersResponse lists Cluster API workload clusters managed by this cluster
+type CAPIClustersResponse struct {
+	Installed bool              `json:"installed"` // Cluster API CRDs present in the cluster
+	Clusters  []k8s.CAPICluster `json:"clusters"`
+}
+
+// CAPIContextResponse names the context registered for a workload cluster
+type CAPIContextResponse struct {
+	Context string `json:"context"` // Switch to it with POST /api/contexts/{name}
+}
+
+// handleCAPIClusters returns the Cluster API fleet with MachineDeployments and Machines
+// GET /api/capi/clusters?namespaces=a,b
+func (s *Server) handleCAPIClusters(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	clusters, installed, err := k8s.ListCAPIClusters(parseNamespaces(r.URL.Query()))
+	if err != nil {
+		log.Printf("[capi] Failed to list clusters: %v", err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.writeJSON(w, CAPIClustersResponse{Installed: installed, Clusters: clusters})
+}
+
+// handleCAPIClusterContext registers a workload cluster's kubeconfig (from its
+// CAPI-generated secret) as an in-memory context the UI can switch to
+// POST /api/capi/clusters/{namespace}/{name}/context
+func (s *Server) handleCAPIClusterContext(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	if k8s.IsInCluster() {
+		s.writeError(w, http.StatusBadRequest, "cannot switch context when running in-cluster")
+		return
+	}
+
+	contextName, err := k8s.RegisterCAPIClusterContext(r.Context(), namespace, name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			s.writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if apierrors.IsForbidden(err) {
+			s.writeError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		log.Printf("[capi] Failed to register context for cluster %s/%s: %v", namespace, name, err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.writeJSON(w, CAPIContextResponse{Context: contextName})
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// handleCompareWorkloads diffs two workloads' specs and runtime state field by field
+// GET /api/workloads/compare?left=kind/namespace/name&right=kind/namespace/name
+func (s *Server) handleCompareWorkloads(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	left, err := k8s.ParseWorkloadRef(r.URL.Query().Get("left"))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	right, err := k8s.ParseWorkloadRef(r.URL.Query().Get("right"))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	comparison, err := k8s.CompareWorkloads(left, right)
+	if err != nil {
+		if errors.Is(err, k8s.ErrUnsupportedExportKind) {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if apierrors.IsNotFound(err) {
+			s.writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		log.Printf("[compare] Failed to compare %v with %v: %v", left, right, err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.writeJSON(w, comparison)
+}
+
+// handleCompareNamespaces diffs one namespace across two kubeconfig contexts:
+// resources present on one side only, image differences and replica drift
+// GET /api/namespaces/{namespace}/compare?left=context&right=context (left defaults to the current context)
+func (s *Server) handleCompareNamespaces(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	namespace := chi.URLParam(r, "namespace")
+	leftContext := r.URL.Query().Get("left")
+	if leftContext == "" {
+		leftContext = k8s.GetContextName()
+	}
+	rightContext := r.URL.Query().Get("right")
+	if rightContext == "" {
+		s.writeError(w, http.StatusBadRequest, "right context is required")
+		return
+	}
+
+	diff, err := k8s.CompareNamespaceAcrossContexts(r.Context(), namespace, leftContext, rightContext)
+	if err != nil {
+		if errors.Is(err, k8s.ErrContextNotFound) {
+			s.writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		log.Printf("[compare] Failed to compare namespace %s across %s and %s: %v", namespace, leftContext, rightContext, err)
+		s.writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	s.writeJSON(w, diff)
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/skyhook-io/radar/internal/apierror"
+	"github.com/skyhook-io/radar/internal/settings"
+)
+
+// ConfigResponse is the body of GET and PUT /api/config
+type ConfigResponse struct {
+	settings.Settings
+	Path    string `json:"path,omitempty"`    // File changes are saved to
+	Warning string `json:"warning,omitempty"` // Set when a change was applied but not saved
+}
+
+// readOnlyAllowed are mutating routes that still work in read-only mode:
+// they change Radar's own state or only simulate a change
+var readOnlyAllowed = []string{
+	"/api/config",
+	"/api/contexts/",
+	"/api/connection/",
+	"/api/timeline/",
+	"/api/api-resources/",
+	"/api/traffic/",
+	"/api/sessions/",
+	"/api/portforwards/", // Stopping; starting is POST /api/portforwards
+	"/api/admission/simulate",
+	"/api/maintenance/plan",
+	"/api/workloads/restart/plan",
+	"/api/resources/batch-get", // A read with a request body
+	"/api/pprof/",              // Saved profiles
+	"/api/desktop/",
+}
+
+// readOnlyAllowedSuffixes are dry-run routes matched by suffix
+var readOnlyAllowedSuffixes = []string{
+	"/values/preview",
+	"/values/validate",
+	"/pprof/capture", // Saved locally
+}
+
+// readOnlyGuard rejects requests that change the cluster while read-only
+// mode is on, including opening terminals
+func (s *Server) readOnlyGuard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if settings.Get().ReadOnly && !allowedWhenReadOnly(r) {
+			apierror.WriteCode(w, http.StatusForbidden, apierror.ReadOnly, "Radar is in read-only mode")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func allowedWhenReadOnly(r *http.Request) bool {
+	path := r.URL.Path
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		// Terminals are a GET (WebSocket upgrade) but can change anything in the pod
+		return !(strings.HasPrefix(path, "/api/pods/") && strings.HasSuffix(path, "/exec"))
+	}
+	for _, prefix := range readOnlyAllowed {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	for _, suffix := range readOnlyAllowedSuffixes {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleGetConfig returns the runtime-adjustable settings
+// GET /api/config
+func (s *Server) handleGetConfig(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, ConfigResponse{Settings: settings.Get(), Path: settings.Path()})
+}
+
+// handleUpdateConfig changes settings without a restart and saves them.
+// Fields left out of the body are unchanged.
+// PUT /api/config
+func (s *Server) handleUpdateConfig(w http.ResponseWriter, r *http.Request) {
+	var update settings.Update
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&update); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	// Read-only mode guards against this very API, so it can only be turned
+	// off where it can't be reached from a browser: the settings file or
+	// --read-only, with a restart
+	if update.ReadOnly != nil && !*update.ReadOnly && settings.Get().ReadOnly {
+		apierror.WriteCode(w, http.StatusForbidden, apierror.ReadOnly,
+			"read-only mode can't be turned off over the API; set readOnly to false in the settings file (and drop --read-only), then restart")
+		return
+	}
+
+	resp := ConfigResponse{Path: settings.Path()}
+	current, err := settings.Apply(update)
+	if errors.Is(err, settings.ErrNotSaved) {
+		resp.Warning = err.Error()
+	} else if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	resp.Settings = current
+	s.writeJSON(w, resp)
+}
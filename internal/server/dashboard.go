@@ -2,7 +2,6 @@ package server
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
@@ -23,16 +22,21 @@ import (
 
 // DashboardResponse is the aggregated response for the home dashboard
 type DashboardResponse struct {
-	Cluster         DashboardCluster         `json:"cluster"`
-	Health          DashboardHealth          `json:"health"`
-	Problems        []DashboardProblem       `json:"problems"`
-	ResourceCounts  DashboardResourceCounts  `json:"resourceCounts"`
-	RecentEvents    []DashboardEvent         `json:"recentEvents"`
-	RecentChanges   []DashboardChange        `json:"recentChanges"`
-	TopologySummary DashboardTopologySummary `json:"topologySummary"`
-	TrafficSummary  *DashboardTrafficSummary `json:"trafficSummary"`
-	HelmReleases    DashboardHelmSummary     `json:"helmReleases"`
-	Metrics         *DashboardMetrics        `json:"metrics"`
+	Cluster         DashboardCluster          `json:"cluster"`
+	Health          DashboardHealth           `json:"health"`
+	Problems        []DashboardProblem        `json:"problems"`
+	ResourceCounts  DashboardResourceCounts   `json:"resourceCounts"`
+	RecentEvents    []DashboardEvent          `json:"recentEvents"`
+	RecentChanges   []DashboardChange         `json:"recentChanges"`
+	TopologySummary DashboardTopologySummary  `json:"topologySummary"`
+	TrafficSummary  *DashboardTrafficSummary  `json:"trafficSummary"`
+	HelmReleases    DashboardHelmSummary      `json:"helmReleases"`
+	Metrics         *DashboardMetrics         `json:"metrics"`
+	Recommendations *DashboardRecommendations `json:"recommendations"`
+	// Anti-affinity/topology spread violations and replicas sharing one node or zone
+	PlacementWarnings []DashboardProblem `json:"placementWarnings"`
+	// Per-section timing; sections that timed out keep their empty defaults
+	Sections map[string]DashboardSectionStatus `json:"sections"`
 }
 
 // DashboardCRDsResponse is the response for CRD counts (loaded lazily)
@@ -153,16 +157,18 @@ type DashboardEvent struct {
 	Message        string `json:"message"`
 	InvolvedObject string `json:"involvedObject"`
 	Namespace      string `json:"namespace"`
-	Timestamp      string `json:"timestamp"`
+	Timestamp      string `json:"timestamp"`     // RFC3339 in the requested tz
+	TimestampUnix  int64  `json:"timestampUnix"` // Epoch seconds
 }
 
 type DashboardChange struct {
-	Kind       string `json:"kind"`
-	Namespace  string `json:"namespace"`
-	Name       string `json:"name"`
-	ChangeType string `json:"changeType"`
-	Summary    string `json:"summary"`
-	Timestamp  string `json:"timestamp"`
+	Kind          string `json:"kind"`
+	Namespace     string `json:"namespace"`
+	Name          string `json:"name"`
+	ChangeType    string `json:"changeType"`
+	Summary       string `json:"summary"`
+	Timestamp     string `json:"timestamp"`     // RFC3339 in the requested tz
+	TimestampUnix int64  `json:"timestampUnix"` // Epoch seconds
 }
 
 type DashboardTopologySummary struct {
@@ -200,10 +206,10 @@ type DashboardHelmRelease struct {
 
 func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
 	namespaces := parseNamespaces(r.URL.Query())
-	// For backward compat with single namespace string in internal functions
-	namespace := ""
-	if len(namespaces) == 1 {
-		namespace = namespaces[0]
+	loc, err := parseTimezone(r.URL.Query())
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
 	cache := k8s.GetResourceCache()
@@ -212,39 +218,190 @@ func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp := DashboardResponse{}
+	// Share one build between concurrent requests for the same filter; detach
+	// it from this request so other waiters aren't cancelled if it goes away
+	buildCtx := context.WithoutCancel(r.Context())
+	body, etag, cached, err := s.broadcaster.dashboard.get(r.Context(), namespaces, loc, func() DashboardResponse {
+		return s.buildDashboard(buildCtx, cache, namespaces, loc)
+	})
+	if r.Context().Err() != nil {
+		return // Client went away while waiting
+	}
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if cached {
+		w.Header().Set("X-Radar-Cache", "hit")
+	} else {
+		w.Header().Set("X-Radar-Cache", "miss")
+	}
+	s.writeETagged(w, r, body, etag)
+}
+
+// buildDashboard computes the dashboard payload for a namespace filter, with
+// timestamps rendered in loc
+func (s *Server) buildDashboard(ctx context.Context, cache *k8s.ResourceCache, namespaces []string, loc *time.Location) DashboardResponse {
+	// For backward compat with single namespace string in internal functions
+	namespace := ""
+	if len(namespaces) == 1 {
+		namespace = namespaces[0]
+	}
+
+	// Sections run concurrently, each bounded by its own timeout. A section that
+	// times out keeps its empty default and is flagged in resp.Sections.
+	resp := DashboardResponse{
+		Problems:          []DashboardProblem{},
+		PlacementWarnings: []DashboardProblem{},
+		RecentEvents:      []DashboardEvent{},
+		RecentChanges:     []DashboardChange{},
+		HelmReleases:      DashboardHelmSummary{Releases: []DashboardHelmRelease{}},
+	}
+	sections := newDashboardSections(ctx)
 
 	// Cluster info
-	resp.Cluster = s.getDashboardCluster(r.Context())
+	sections.run("cluster", dashboardRemoteTimeout, func(ctx context.Context) func() {
+		cluster := s.getDashboardCluster(ctx)
+		return func() { resp.Cluster = cluster }
+	})
 
-	// Pod health + workload problems
-	resp.Health, resp.Problems = s.getDashboardHealth(cache, namespace)
+	// Pod health + workload problems, and warning events for the health banner
+	sections.run("health", dashboardCacheTimeout, func(context.Context) func() {
+		health, problems := s.getDashboardHealth(cache, namespace)
+		health.WarningEvents = s.countWarningEvents(cache, namespace)
+		return func() { resp.Health, resp.Problems = health, problems }
+	})
 
 	// Resource counts
-	resp.ResourceCounts = s.getDashboardResourceCounts(cache, namespace)
+	sections.run("resourceCounts", dashboardCacheTimeout, func(context.Context) func() {
+		counts := s.getDashboardResourceCounts(cache, namespace)
+		return func() { resp.ResourceCounts = counts }
+	})
 
 	// Recent warning events
-	resp.RecentEvents = s.getDashboardRecentEvents(cache, namespace)
-
-	// Count warning events for health banner
-	resp.Health.WarningEvents = s.countWarningEvents(cache, namespace)
+	sections.run("recentEvents", dashboardCacheTimeout, func(context.Context) func() {
+		events := s.getDashboardRecentEvents(cache, namespace, loc)
+		return func() { resp.RecentEvents = events }
+	})
 
 	// Recent changes from timeline
-	resp.RecentChanges = s.getDashboardRecentChanges(r.Context(), namespaces)
+	sections.run("recentChanges", dashboardCacheTimeout, func(ctx context.Context) func() {
+		changes := s.getDashboardRecentChanges(ctx, namespaces, loc)
+		return func() { resp.RecentChanges = changes }
+	})
 
 	// Topology summary
-	resp.TopologySummary = s.getDashboardTopologySummary(namespaces)
+	sections.run("topologySummary", dashboardCacheTimeout, func(context.Context) func() {
+		summary := s.getDashboardTopologySummary(namespaces)
+		return func() { resp.TopologySummary = summary }
+	})
 
 	// Traffic summary
-	resp.TrafficSummary = s.getDashboardTrafficSummary(r.Context(), namespaces)
+	sections.run("trafficSummary", dashboardRemoteTimeout, func(ctx context.Context) func() {
+		summary := s.getDashboardTrafficSummary(ctx, namespaces)
+		return func() { resp.TrafficSummary = summary }
+	})
 
 	// Helm releases summary
-	resp.HelmReleases = s.getDashboardHelmSummary(namespace)
+	sections.run("helmReleases", dashboardRemoteTimeout, func(context.Context) func() {
+		summary := s.getDashboardHelmSummary(namespace)
+		return func() { resp.HelmReleases = summary }
+	})
 
 	// Cluster metrics (best-effort, nil if metrics-server unavailable)
-	resp.Metrics = s.getDashboardMetrics(r.Context())
+	sections.run("metrics", dashboardRemoteTimeout, func(ctx context.Context) func() {
+		metrics := s.getDashboardMetrics(ctx)
+		return func() { resp.Metrics = metrics }
+	})
 
-	s.writeJSON(w, resp)
+	// Right-sizing savings (nil until metrics history has been collected)
+	sections.run("recommendations", dashboardCacheTimeout, func(context.Context) func() {
+		recommendations := s.getDashboardRecommendations(namespaces)
+		return func() { resp.Recommendations = recommendations }
+	})
+
+	// Pod placement vs declared anti-affinity and topology spread
+	sections.run("placementWarnings", dashboardCacheTimeout, func(context.Context) func() {
+		warnings := s.getDashboardPlacementWarnings(namespaces)
+		return func() { resp.PlacementWarnings = warnings }
+	})
+
+	resp.Sections = sections.wait()
+	return resp
+}
+
+// Per-section dashboard timeouts. Cache-backed sections are normally fast and
+// only slow down on very large clusters; remote sections wait on the API
+// server, Helm storage, metrics-server, or the traffic source.
+const (
+	dashboardCacheTimeout  = 2 * time.Second
+	dashboardRemoteTimeout = 3 * time.Second
+)
+
+// DashboardSectionStatus reports how one dashboard section was computed
+type DashboardSectionStatus struct {
+	DurationMs int64 `json:"durationMs"`
+	TimedOut   bool  `json:"timedOut,omitempty"` // Section data is omitted (left at its empty default)
+}
+
+// dashboardSections runs dashboard sections concurrently with per-section timeouts
+type dashboardSections struct {
+	ctx    context.Context
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	status map[string]DashboardSectionStatus
+}
+
+func newDashboardSections(ctx context.Context) *dashboardSections {
+	return &dashboardSections{ctx: ctx, status: make(map[string]DashboardSectionStatus)}
+}
+
+// run computes a section in the background. compute returns a func that
+// stores its result; it is only called if compute finished within timeout,
+// so a late section never writes to a response that is already being sent.
+func (d *dashboardSections) run(name string, timeout time.Duration, compute func(ctx context.Context) func()) {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ctx, cancel := context.WithTimeout(d.ctx, timeout)
+		defer cancel()
+
+		start := time.Now()
+		done := make(chan func(), 1)
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("Dashboard section %s panicked: %v", name, r)
+					done <- nil
+				}
+			}()
+			done <- compute(ctx)
+		}()
+
+		var status DashboardSectionStatus
+		select {
+		case apply := <-done:
+			d.mu.Lock()
+			if apply != nil {
+				apply()
+			}
+			d.mu.Unlock()
+		case <-ctx.Done():
+			status.TimedOut = true
+			log.Printf("Dashboard section %s timed out after %s", name, timeout)
+		}
+		status.DurationMs = time.Since(start).Milliseconds()
+
+		d.mu.Lock()
+		d.status[name] = status
+		d.mu.Unlock()
+	}()
+}
+
+// wait blocks until every section finished or timed out and returns their status
+func (d *dashboardSections) wait() map[string]DashboardSectionStatus {
+	d.wg.Wait()
+	return d.status
 }
 
 // handleDashboardCRDs returns CRD counts - loaded lazily to keep main dashboard fast
@@ -418,12 +575,42 @@ func (s *Server) getDashboardHealth(cache *k8s.ResourceCache, namespace string)
 		}
 	}
 
-	// Node problems: Ready=False
+	// Pods evicted for disk pressure, per node, to correlate with DiskPressure below
+	diskEvictions := make(map[string]int)
+	for _, pod := range pods {
+		if k8s.EvictionCause(pod) == k8s.EvictionDiskPressure {
+			diskEvictions[pod.Spec.NodeName]++
+		}
+	}
+
+	// Node problems: Ready=False, DiskPressure=True
 	var nodes []*corev1.Node
 	if nodeLister := cache.Nodes(); nodeLister != nil {
 		nodes, _ = nodeLister.List(labels.Everything())
 	}
 	for _, n := range nodes {
+		if k8s.HasDiskPressure(n) {
+			message := "Node is low on disk; the kubelet is evicting pods to reclaim ephemeral storage"
+			if evicted := diskEvictions[n.Name]; evicted > 0 {
+				message = fmt.Sprintf("%s (%d pod(s) evicted)", message, evicted)
+			}
+			var ageDur time.Duration
+			for _, cond := range n.Status.Conditions {
+				if cond.Type == corev1.NodeDiskPressure && !cond.LastTransitionTime.IsZero() {
+					ageDur = now.Sub(cond.LastTransitionTime.Time)
+				}
+			}
+			problems = append(problems, DashboardProblem{
+				Kind:       "Node",
+				Name:       n.Name,
+				Status:     "warning",
+				Reason:     "DiskPressure",
+				Message:    message,
+				Age:        formatAge(ageDur),
+				AgeSeconds: int64(ageDur.Seconds()),
+			})
+		}
+
 		ready := false
 		for _, cond := range n.Status.Conditions {
 			if cond.Type == corev1.NodeReady && cond.Status == corev1.ConditionTrue {
@@ -451,6 +638,56 @@ func (s *Server) getDashboardHealth(cache *k8s.ResourceCache, namespace string)
 		}
 	}
 
+	// Unavailable aggregated APIs break discovery, kubectl top, and HPAs cluster-wide
+	for _, svc := range k8s.GetDynamicResourceCache().GetUnavailableAPIServices() {
+		reason := svc.Reason
+		if reason == "" {
+			reason = "Unavailable"
+		}
+		message := fmt.Sprintf("Aggregated API served by %s is unavailable", svc.Service)
+		if svc.Message != "" {
+			message += ": " + svc.Message
+		}
+		var ageDur time.Duration
+		if !svc.LastTransitionAt.IsZero() {
+			ageDur = now.Sub(svc.LastTransitionAt)
+		}
+		problems = append(problems, DashboardProblem{
+			Kind:       "APIService",
+			Name:       svc.Name,
+			Status:     "error",
+			Reason:     reason,
+			Message:    message,
+			Age:        formatAge(ageDur),
+			AgeSeconds: int64(ageDur.Seconds()),
+		})
+	}
+
+	// Policy violations (Gatekeeper audit, Kyverno reports): one problem per policy
+	var policyNamespaces []string
+	if namespace != "" {
+		policyNamespaces = []string{namespace}
+	}
+	if violations, err := k8s.GetPolicyViolations(policyNamespaces); err == nil {
+		for _, group := range violations.ByPolicy {
+			status := "warning"
+			if group.Enforced {
+				status = "error"
+			}
+			message := fmt.Sprintf("%d violation(s) in %d namespace(s)", group.Total, len(group.Namespaces))
+			if len(group.Violations) > 0 && group.Violations[0].Message != "" {
+				message += ": " + group.Violations[0].Message
+			}
+			problems = append(problems, DashboardProblem{
+				Kind:    group.PolicyKind,
+				Name:    group.Policy,
+				Status:  status,
+				Reason:  "PolicyViolation",
+				Message: message,
+			})
+		}
+	}
+
 	// Sort: errors first, then warnings; within each group sort by age (most recent first)
 	sort.SliceStable(problems, func(i, j int) bool {
 		if problems[i].Status != problems[j].Status {
@@ -528,8 +765,28 @@ func podToProblem(pod *corev1.Pod, severity string, now time.Time) DashboardProb
 	reason := ""
 	message := ""
 
+	// Evictions say why in the pod status; without this they look like random pod deaths
+	if cause := k8s.EvictionCause(pod); cause != "" {
+		reason = "Evicted"
+		switch cause {
+		case k8s.EvictionDiskPressure:
+			reason = "Evicted (DiskPressure)"
+		case k8s.EvictionEphemeralLimit:
+			reason = "Evicted (ephemeral-storage limit)"
+		case k8s.EvictionMemoryPressure, k8s.EvictionPIDPressure:
+			reason = "Evicted (" + cause + ")"
+		}
+		message = pod.Status.Message
+		if pod.Spec.NodeName != "" {
+			message = fmt.Sprintf("Evicted from node %s: %s", pod.Spec.NodeName, message)
+		}
+	}
+
 	// Find the most relevant issue
 	for _, cs := range pod.Status.ContainerStatuses {
+		if reason != "" {
+			break
+		}
 		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
 			reason = cs.State.Waiting.Reason
 			message = cs.State.Waiting.Message
@@ -897,7 +1154,7 @@ func (s *Server) getDashboardResourceCounts(cache *k8s.ResourceCache, namespace
 	return counts
 }
 
-func (s *Server) getDashboardRecentEvents(cache *k8s.ResourceCache, namespace string) []DashboardEvent {
+func (s *Server) getDashboardRecentEvents(cache *k8s.ResourceCache, namespace string, loc *time.Location) []DashboardEvent {
 	eventLister := cache.Events()
 	if eventLister == nil {
 		return []DashboardEvent{}
@@ -951,14 +1208,15 @@ func (s *Server) getDashboardRecentEvents(cache *k8s.ResourceCache, namespace st
 			Message:        truncate(e.Message, 200),
 			InvolvedObject: fmt.Sprintf("%s/%s", e.InvolvedObject.Kind, e.InvolvedObject.Name),
 			Namespace:      e.Namespace,
-			Timestamp:      ts.Format(time.RFC3339),
+			Timestamp:      ts.In(loc).Format(time.RFC3339),
+			TimestampUnix:  ts.Unix(),
 		})
 	}
 
 	return result
 }
 
-func (s *Server) getDashboardRecentChanges(ctx context.Context, namespaces []string) []DashboardChange {
+func (s *Server) getDashboardRecentChanges(ctx context.Context, namespaces []string, loc *time.Location) []DashboardChange {
 	store := timeline.GetStore()
 	if store == nil {
 		return []DashboardChange{}
@@ -968,7 +1226,7 @@ func (s *Server) getDashboardRecentChanges(ctx context.Context, namespaces []str
 		Namespaces:   namespaces,
 		Since:        time.Now().Add(-1 * time.Hour),
 		Limit:        5,
-		FilterPreset: "workloads",
+		FilterPreset: timeline.PresetWorkloads,
 	}
 
 	events, err := store.Query(ctx, opts)
@@ -986,12 +1244,13 @@ func (s *Server) getDashboardRecentChanges(ctx context.Context, namespaces []str
 		}
 
 		result = append(result, DashboardChange{
-			Kind:       e.Kind,
-			Namespace:  e.Namespace,
-			Name:       e.Name,
-			ChangeType: string(e.EventType),
-			Summary:    summary,
-			Timestamp:  e.Timestamp.Format(time.RFC3339),
+			Kind:          e.Kind,
+			Namespace:     e.Namespace,
+			Name:          e.Name,
+			ChangeType:    string(e.EventType),
+			Summary:       summary,
+			Timestamp:     e.Timestamp.In(loc).Format(time.RFC3339),
+			TimestampUnix: e.Timestamp.Unix(),
 		})
 	}
 
@@ -1037,10 +1296,7 @@ func (s *Server) getDashboardTrafficSummary(ctx context.Context, namespaces []st
 	}
 
 	opts := traffic.DefaultFlowOptions()
-	// Traffic only supports single namespace filter for now
-	if len(namespaces) == 1 {
-		opts.Namespace = namespaces[0]
-	}
+	opts.Namespaces = namespaces
 
 	response, err := manager.GetFlows(ctx, opts)
 	if err != nil || len(response.Flows) == 0 {
@@ -1147,41 +1403,12 @@ func (s *Server) countWarningEvents(cache *k8s.ResourceCache, namespace string)
 }
 
 func (s *Server) getDashboardMetrics(ctx context.Context) *DashboardMetrics {
-	client := k8s.GetClient()
-	if client == nil {
-		return nil
-	}
-
-	// Query metrics-server via raw REST to avoid adding k8s.io/metrics dependency.
-	// GET /apis/metrics.k8s.io/v1beta1/nodes
-	data, err := client.RESTClient().Get().
-		AbsPath("/apis/metrics.k8s.io/v1beta1/nodes").
-		DoRaw(ctx)
-	if err != nil {
+	nodeMetrics, err := k8s.ListNodeMetrics(ctx)
+	if err != nil || len(nodeMetrics) == 0 {
 		// metrics-server not installed or not accessible — that's fine
 		return nil
 	}
 
-	var nodeMetricsList struct {
-		Items []struct {
-			Metadata struct {
-				Name string `json:"name"`
-			} `json:"metadata"`
-			Usage struct {
-				CPU    string `json:"cpu"`
-				Memory string `json:"memory"`
-			} `json:"usage"`
-		} `json:"items"`
-	}
-	if err := json.Unmarshal(data, &nodeMetricsList); err != nil {
-		log.Printf("Failed to parse node metrics: %v", err)
-		return nil
-	}
-
-	if len(nodeMetricsList.Items) == 0 {
-		return nil
-	}
-
 	// Get node capacity from the cache
 	cache := k8s.GetResourceCache()
 	if cache == nil {
@@ -1207,7 +1434,7 @@ func (s *Server) getDashboardMetrics(ctx context.Context) *DashboardMetrics {
 	// Sum usage across all nodes
 	var cpuUsageMillis int64
 	var memUsageBytes int64
-	for _, item := range nodeMetricsList.Items {
+	for _, item := range nodeMetrics {
 		cpuUsageMillis += parseCPUToMillis(item.Usage.CPU)
 		memUsageBytes += parseMemoryToBytes(item.Usage.Memory)
 	}
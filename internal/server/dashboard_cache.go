@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// dashboardCacheTTL bounds how long a dashboard payload is reused. Resource
+// changes invalidate earlier; the TTL covers sources that don't emit changes
+// (metrics, traffic, Helm storage, dynamic resources).
+const dashboardCacheTTL = 5 * time.Second
+
+// dashboardCountOnlyKinds only contribute counts to the dashboard, so their
+// updates don't change the payload - only adds and deletes do
+var dashboardCountOnlyKinds = map[string]bool{
+	"ConfigMap": true,
+	"Service":   true,
+	"Ingress":   true,
+	"Namespace": true,
+}
+
+// dashboardCache shares dashboard payloads between requests with the same
+// namespace filter, so several tabs polling at once compute it only once
+type dashboardCache struct {
+	mu      sync.Mutex
+	entries map[string]*dashboardCacheEntry
+}
+
+type dashboardCacheEntry struct {
+	namespaces []string // Namespace filter (empty = all)
+	ready      chan struct{}
+	body       []byte    // Encoded payload, shared by every request it is served to
+	etag       string    // Content hash of body
+	err        error     // Encoding error, not cached
+	expires    time.Time // Zero while building; guarded by dashboardCache.mu
+}
+
+func newDashboardCache() *dashboardCache {
+	return &dashboardCache{entries: make(map[string]*dashboardCacheEntry)}
+}
+
+// dashboardCacheKey returns the cache key for a namespace filter and the
+// time zone timestamps are rendered in
+func dashboardCacheKey(namespaces []string, loc *time.Location) string {
+	sorted := slices.Clone(namespaces)
+	slices.Sort(sorted)
+	return strings.Join(sorted, ",") + "|" + loc.String()
+}
+
+// get returns the encoded payload and its ETag for namespaces, building it if
+// missing or expired. Concurrent callers for the same filter wait for a single
+// build. The third return value reports whether the payload came from the cache.
+func (c *dashboardCache) get(ctx context.Context, namespaces []string, loc *time.Location, build func() DashboardResponse) ([]byte, string, bool, error) {
+	key := dashboardCacheKey(namespaces, loc)
+
+	c.mu.Lock()
+	e := c.entries[key]
+	if e != nil && (e.expires.IsZero() || time.Now().Before(e.expires)) {
+		c.mu.Unlock()
+		select {
+		case <-e.ready:
+			return e.body, e.etag, true, e.err
+		case <-ctx.Done():
+			return nil, "", false, ctx.Err()
+		}
+	}
+	e = &dashboardCacheEntry{namespaces: namespaces, ready: make(chan struct{})}
+	c.entries[key] = e
+	c.mu.Unlock()
+
+	resp := build()
+	e.body, e.etag, e.err = encodeDashboard(resp)
+	close(e.ready)
+
+	c.mu.Lock()
+	e.expires = time.Now().Add(dashboardCacheTTL)
+	if e.err != nil {
+		e.expires = time.Now()
+	}
+	for _, status := range resp.Sections {
+		if status.TimedOut {
+			// Don't hold on to partial results; the next request retries
+			e.expires = time.Now()
+			break
+		}
+	}
+	c.mu.Unlock()
+	return e.body, e.etag, false, e.err
+}
+
+// encodeDashboard serializes a dashboard payload. The ETag ignores section
+// timings, which differ on every build, so an unchanged dashboard keeps its
+// ETag across rebuilds and polling clients still get 304s.
+func encodeDashboard(resp DashboardResponse) ([]byte, string, error) {
+	body, _, err := encodeETagged(resp)
+	if err != nil {
+		return nil, "", err
+	}
+	untimed := resp
+	untimed.Sections = make(map[string]DashboardSectionStatus, len(resp.Sections))
+	for name, status := range resp.Sections {
+		untimed.Sections[name] = DashboardSectionStatus{TimedOut: status.TimedOut}
+	}
+	_, etag, err := encodeETagged(untimed)
+	return body, etag, err
+}
+
+// invalidate drops payloads that a resource change may have affected
+func (c *dashboardCache) invalidate(change k8s.ResourceChange) {
+	if dashboardCountOnlyKinds[change.Kind] && change.Operation == "update" {
+		return
+	}
+	if change.Kind == "Secret" && change.Operation == "update" && !strings.HasPrefix(change.Name, "sh.helm.release.") {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, e := range c.entries {
+		// Cluster-scoped changes (nodes, namespaces) affect every filter
+		if change.Namespace == "" || len(e.namespaces) == 0 || slices.Contains(e.namespaces, change.Namespace) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// clear drops all cached payloads (e.g. on context switch)
+func (c *dashboardCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
@@ -2,10 +2,12 @@ package server
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
 	"time"
 
+	"github.com/skyhook-io/radar/internal/outbound"
 	"github.com/skyhook-io/radar/internal/updater"
 )
 
@@ -19,7 +21,10 @@ func (s *Server) handleDesktopUpdateStart(w http.ResponseWriter, r *http.Request
 
 	// Use background context: the download runs asynchronously and must not be
 	// cancelled when this HTTP response completes or the 60s timeout fires.
-	if err := s.updater.StartDownload(context.Background()); err != nil {
+	if err := s.updater.StartDownload(context.Background()); errors.Is(err, outbound.ErrOffline) {
+		s.writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	} else if err != nil {
 		s.writeError(w, http.StatusConflict, err.Error())
 		return
 	}
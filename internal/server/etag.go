@@ -0,0 +1,61 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// encodeETagged serializes data as JSON and returns it with a strong ETag
+// derived from the content, so identical payloads always share an ETag
+func encodeETagged(data any) ([]byte, string, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(data); err != nil {
+		return nil, "", err
+	}
+	h := fnv.New64a()
+	h.Write(buf.Bytes())
+	return buf.Bytes(), fmt.Sprintf(`"%016x"`, h.Sum64()), nil
+}
+
+// writeJSONWithETag writes data as JSON with an ETag, answering 304 Not
+// Modified when the request's If-None-Match already names the content
+func (s *Server) writeJSONWithETag(w http.ResponseWriter, r *http.Request, data any) {
+	body, etag, err := encodeETagged(data)
+	if err != nil {
+		log.Printf("Failed to encode JSON response: %v", err)
+		s.writeError(w, http.StatusInternalServerError, "failed to encode response")
+		return
+	}
+	s.writeETagged(w, r, body, etag)
+}
+
+// writeETagged writes a pre-encoded JSON body with its ETag, or 304 Not Modified
+func (s *Server) writeETagged(w http.ResponseWriter, r *http.Request, body []byte, etag string) {
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache") // Always revalidate
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(body); err != nil {
+		log.Printf("Failed to write JSON response: %v", err)
+	}
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag,
+// accepting "*", lists of tags, and weak tags (W/"...") added by proxies
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
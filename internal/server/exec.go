@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -19,19 +20,25 @@ import (
 	"github.com/skyhook-io/radar/internal/k8s"
 )
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool {
-		return true // Allow all origins for local dev
-	},
-}
-
 // ExecSession tracks an active exec WebSocket connection
 type ExecSession struct {
-	ID        string `json:"id"`
-	Namespace string `json:"namespace"`
-	Pod       string `json:"pod"`
-	Container string `json:"container"`
-	conn      *websocket.Conn
+	ID         string    `json:"id"`
+	Namespace  string    `json:"namespace"`
+	Pod        string    `json:"pod"`
+	Container  string    `json:"container"`
+	StartedAt  time.Time `json:"startedAt"`
+	Client     string    `json:"client"` // Address of the client that started it
+	conn       *websocket.Conn
+	owner      *wsWriter
+	lastActive atomic.Int64 // Unix nanos of the last input or output
+
+	// Read-only observers, see exec_share.go
+	mu         sync.Mutex
+	shared     bool
+	observers  map[string]*execObserver
+	nextObsID  int
+	scrollback []byte
+	done       chan struct{}
 }
 
 // execSessionManager tracks active exec sessions
@@ -64,12 +71,18 @@ func StopAllExecSessions() {
 	}
 }
 
-// TerminalMessage represents a message between client and server
+// TerminalMessage represents a message between client and server. Besides
+// "input", "resize", "output" and "error", the owner and observers of a
+// shared session exchange the control messages in exec_share.go.
 type TerminalMessage struct {
-	Type string `json:"type"` // "input", "resize", "output", "error"
-	Data string `json:"data,omitempty"`
-	Rows uint16 `json:"rows,omitempty"`
-	Cols uint16 `json:"cols,omitempty"`
+	Type      string             `json:"type"`
+	Data      string             `json:"data,omitempty"`
+	Rows      uint16             `json:"rows,omitempty"`
+	Cols      uint16             `json:"cols,omitempty"`
+	ErrorType string             `json:"errorType,omitempty"` // With "error": idle_timeout, session_limit, closed_by_admin, ...
+	ID        string             `json:"id,omitempty"`        // Session or observer ID
+	Name      string             `json:"name,omitempty"`      // Observer display name
+	Observers []ExecObserverInfo `json:"observers,omitempty"` // Sent to the owner when observers change
 }
 
 // wsWriter wraps a websocket connection to satisfy io.Writer
@@ -79,18 +92,22 @@ type wsWriter struct {
 }
 
 func (w *wsWriter) Write(p []byte) (int, error) {
+	if err := w.send(TerminalMessage{Type: "output", Data: string(p)}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// send writes a message as JSON, serialized with output writes
+func (w *wsWriter) send(msg TerminalMessage) error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	msg := TerminalMessage{Type: "output", Data: string(p)}
 	data, err := json.Marshal(msg)
 	if err != nil {
-		return 0, err
-	}
-	if err := w.conn.WriteMessage(websocket.TextMessage, data); err != nil {
-		return 0, err
+		return err
 	}
-	return len(p), nil
+	return w.conn.WriteMessage(websocket.TextMessage, data)
 }
 
 // terminalSizeQueue implements remotecommand.TerminalSizeQueue
@@ -119,12 +136,23 @@ func (s *Server) handlePodExec(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Upgrade to WebSocket
-	conn, err := upgrader.Upgrade(w, r, nil)
+	conn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("WebSocket upgrade error: %v", err)
 		return
 	}
 
+	// Set up stdout/stderr writer
+	wsOut := &wsWriter{conn: conn}
+
+	clientAddr := clientID(r)
+	if limit := s.sessionLimits.MaxExecPerClient; limit > 0 && countExecSessions(clientAddr) >= limit {
+		wsOut.send(TerminalMessage{Type: "error", ErrorType: "session_limit",
+			Data: fmt.Sprintf("Terminal limit reached (%d per client); close another terminal first", limit)})
+		conn.Close()
+		return
+	}
+
 	// Register the session
 	execManager.mu.Lock()
 	execManager.nextID++
@@ -134,21 +162,35 @@ func (s *Server) handlePodExec(w http.ResponseWriter, r *http.Request) {
 		Namespace: namespace,
 		Pod:       podName,
 		Container: container,
+		StartedAt: time.Now(),
+		Client:    clientAddr,
 		conn:      conn,
+		owner:     wsOut,
+		observers: make(map[string]*execObserver),
+		done:      make(chan struct{}),
 	}
+	session.touch()
 	execManager.sessions[sessionID] = session
 	execManager.mu.Unlock()
 	log.Printf("Exec session %s started (%s/%s)", sessionID, namespace, podName)
 
+	if timeout := s.sessionLimits.ExecIdleTimeout; timeout > 0 {
+		go session.closeWhenIdle(timeout)
+	}
+
 	// Ensure cleanup on exit
 	defer func() {
 		execManager.mu.Lock()
 		delete(execManager.sessions, sessionID)
 		execManager.mu.Unlock()
+		session.end()
 		conn.Close()
 		log.Printf("Exec session %s ended (%s/%s)", sessionID, namespace, podName)
 	}()
 
+	// Tell the owner the session ID, which observers attach with
+	wsOut.send(TerminalMessage{Type: "session", ID: sessionID})
+
 	// Get K8s client and config
 	client := k8s.GetClient()
 	config := k8s.GetConfig()
@@ -191,16 +233,16 @@ func (s *Server) handlePodExec(w http.ResponseWriter, r *http.Request) {
 	// Send initial size
 	sizeQueue.resizeChan <- remotecommand.TerminalSize{Width: 80, Height: 24}
 
-	// Set up stdout/stderr writer
-	wsOut := &wsWriter{conn: conn}
+	// Output goes to the owner and any approved observers
+	output := &execOutput{session: session}
 
 	// Run exec in goroutine
 	execDone := make(chan error, 1)
 	go func() {
 		err := exec.StreamWithContext(r.Context(), remotecommand.StreamOptions{
 			Stdin:             stdinReader,
-			Stdout:            wsOut,
-			Stderr:            wsOut,
+			Stdout:            output,
+			Stderr:            output,
 			Tty:               true,
 			TerminalSizeQueue: sizeQueue,
 		})
@@ -276,6 +318,7 @@ func (s *Server) handlePodExec(w http.ResponseWriter, r *http.Request) {
 
 			switch msg.Type {
 			case "input":
+				session.touch()
 				stdinWriter.Write([]byte(msg.Data))
 			case "resize":
 				select {
@@ -286,6 +329,12 @@ func (s *Server) handlePodExec(w http.ResponseWriter, r *http.Request) {
 				default:
 					// Drop resize if channel full
 				}
+			case "share", "unshare":
+				session.setShared(msg.Type == "share")
+			case "approve", "deny":
+				session.decide(msg.ID, msg.Type == "approve")
+			case "revoke":
+				session.revoke(msg.ID)
 			}
 		case <-r.Context().Done():
 			goto cleanup
@@ -297,6 +346,38 @@ cleanup:
 	stdinWriter.Close()
 }
 
+func (s *ExecSession) touch() {
+	s.lastActive.Store(time.Now().UnixNano())
+}
+
+func (s *ExecSession) lastActivity() time.Time {
+	return time.Unix(0, s.lastActive.Load())
+}
+
+// close tells the owner why and disconnects; the handler then cleans up
+func (s *ExecSession) close(errorType, msg string) {
+	s.owner.send(TerminalMessage{Type: "error", ErrorType: errorType, Data: msg})
+	s.conn.Close()
+}
+
+// closeWhenIdle closes the session after timeout without input or output
+func (s *ExecSession) closeWhenIdle(timeout time.Duration) {
+	ticker := time.NewTicker(idleCheckInterval(timeout))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if idle := time.Since(s.lastActivity()); idle > timeout {
+				log.Printf("Exec session %s idle for %s, closing", s.ID, idle.Round(time.Second))
+				s.close("idle_timeout", fmt.Sprintf("Session closed after %s without activity", timeout))
+				return
+			}
+		}
+	}
+}
+
 func sendWSError(conn *websocket.Conn, msg string) {
 	sendWSErrorWithType(conn, "exec_error", msg)
 }
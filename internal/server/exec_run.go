@@ -0,0 +1,148 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+const (
+	runDefaultTimeout = 10
+	runMaxTimeout     = 50      // Keeps the worst case under the 60s API route timeout
+	runMaxOutput      = 1 << 20 // Per stream
+	runMaxStdin       = 1 << 20
+)
+
+// RunRequest is the request body for POST /api/pods/{ns}/{name}/run. Either
+// command (argv, no shell) or script (run with sh -c) is required.
+type RunRequest struct {
+	Container      string   `json:"container,omitempty"`
+	Command        []string `json:"command,omitempty"`
+	Script         string   `json:"script,omitempty"`
+	Stdin          string   `json:"stdin,omitempty"`
+	TimeoutSeconds int      `json:"timeoutSeconds,omitempty"`
+}
+
+// RunResult is the outcome of a one-shot command. ExitCode is -1 when the
+// command didn't exit on its own (timed out).
+type RunResult struct {
+	Command    []string `json:"command"`
+	Container  string   `json:"container,omitempty"`
+	ExitCode   int      `json:"exitCode"`
+	Stdout     string   `json:"stdout"`
+	Stderr     string   `json:"stderr"`
+	DurationMs int64    `json:"durationMs"`
+	TimedOut   bool     `json:"timedOut,omitempty"`
+	Truncated  bool     `json:"truncated,omitempty"` // Output exceeded 1 MiB per stream
+}
+
+// handlePodRun runs a one-shot command in a pod (no TTY) and returns its
+// output and exit code, for quick actions that don't need a terminal
+// POST /api/pods/{namespace}/{name}/run
+func (s *Server) handlePodRun(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	namespace := chi.URLParam(r, "namespace")
+	podName := chi.URLParam(r, "name")
+
+	var req RunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	command := req.Command
+	switch {
+	case len(command) > 0 && req.Script != "":
+		s.writeError(w, http.StatusBadRequest, "command and script are mutually exclusive")
+		return
+	case req.Script != "":
+		command = []string{"sh", "-c", req.Script}
+	case len(command) == 0 || command[0] == "":
+		s.writeError(w, http.StatusBadRequest, "command or script is required")
+		return
+	}
+	if len(req.Stdin) > runMaxStdin {
+		s.writeError(w, http.StatusRequestEntityTooLarge, "stdin exceeds 1 MiB")
+		return
+	}
+
+	timeout := req.TimeoutSeconds
+	if timeout <= 0 {
+		timeout = runDefaultTimeout
+	}
+	if timeout > runMaxTimeout {
+		s.writeError(w, http.StatusBadRequest, "timeoutSeconds must be at most 50")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	var stdin io.Reader
+	if req.Stdin != "" {
+		stdin = strings.NewReader(req.Stdin)
+	}
+	stdout := &cappedBuffer{max: runMaxOutput}
+	stderr := &cappedBuffer{max: runMaxOutput}
+	result := RunResult{Command: command, Container: req.Container}
+
+	start := time.Now()
+	execErr := s.execInPod(ctx, namespace, podName, req.Container, command, stdin, stdout, stderr)
+	result.DurationMs = time.Since(start).Milliseconds()
+	result.Stdout, result.Stderr = stdout.String(), stderr.String()
+	result.Truncated = stdout.truncated || stderr.truncated
+
+	if execErr != nil {
+		var exitErr utilexec.ExitError
+		switch {
+		case errors.As(execErr, &exitErr):
+			result.ExitCode = exitErr.ExitStatus()
+		case ctx.Err() != nil && r.Context().Err() == nil:
+			result.ExitCode, result.TimedOut = -1, true
+		case isShellNotFoundError(execErr.Error()):
+			s.writeError(w, http.StatusUnprocessableEntity, "command not found in container: "+command[0])
+			return
+		case strings.Contains(execErr.Error(), "not found"):
+			s.writeError(w, http.StatusNotFound, execErr.Error())
+			return
+		default:
+			log.Printf("[run] Exec failed in %s/%s: %v", namespace, podName, execErr)
+			s.writeError(w, http.StatusInternalServerError, execErr.Error())
+			return
+		}
+	}
+
+	s.writeJSON(w, result)
+}
+
+// cappedBuffer keeps the first max bytes written to it and discards the rest,
+// so a chatty command can't exhaust memory or stall on a blocked pipe
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (c *cappedBuffer) Write(p []byte) (int, error) {
+	if room := c.max - c.buf.Len(); room < len(p) {
+		c.truncated = true
+		if room > 0 {
+			c.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return c.buf.Write(p)
+}
+
+func (c *cappedBuffer) String() string { return c.buf.String() }
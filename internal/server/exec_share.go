@@ -0,0 +1,325 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Exec session sharing: the owner of a terminal turns sharing on ("share"),
+// an observer connects to /exec/{id}/observe and waits, the owner gets an
+// "observer_request" and answers with "approve" or "deny". Approved observers
+// receive the recent scrollback and then the live output; anything they send
+// is discarded. The owner can "revoke" an observer or "unshare" at any time.
+//
+// Owner → server: share, unshare, approve {id}, deny {id}, revoke {id}
+// Server → owner: session {id}, observer_request {id, name}, observers {observers}
+// Server → observer: pending, approved, denied, ended {data: reason}, output
+
+const (
+	// execScrollbackSize is how much recent output an approved observer gets
+	execScrollbackSize = 64 << 10
+	// observerApprovalTimeout is how long an observer waits for the owner
+	observerApprovalTimeout = 2 * time.Minute
+	// observerBuffer is how many output writes an observer may fall behind
+	// before it's disconnected, so a slow observer never stalls the owner
+	observerBuffer = 256
+)
+
+var errNotShared = errors.New("the session owner hasn't enabled sharing")
+
+type execObserver struct {
+	id       string
+	name     string
+	approved bool
+	decision chan bool   // The owner's answer, sent once
+	out      chan []byte // Output for approved observers, closed on removal
+	reason   string      // Why the observer was removed, set before decision/out signal it
+}
+
+// ExecObserverInfo describes an observer to the session owner
+type ExecObserverInfo struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Approved bool   `json:"approved"`
+}
+
+// ExecSessionInfo is an exec session as listed for would-be observers
+type ExecSessionInfo struct {
+	ID           string    `json:"id"`
+	Namespace    string    `json:"namespace"`
+	Pod          string    `json:"pod"`
+	Container    string    `json:"container"`
+	StartedAt    time.Time `json:"startedAt"`
+	Client       string    `json:"client"`
+	LastActivity time.Time `json:"lastActivity"`
+	Shared       bool      `json:"shared"`
+	Observers    int       `json:"observers"`
+}
+
+// execOutput writes exec output to the session owner and its observers
+type execOutput struct {
+	session *ExecSession
+}
+
+func (o *execOutput) Write(p []byte) (int, error) {
+	o.session.touch()
+	n, err := o.session.owner.Write(p)
+	o.session.record(p)
+	return n, err
+}
+
+// record keeps output for the scrollback and fans it out to observers
+func (s *ExecSession) record(p []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.scrollback = append(s.scrollback, p...)
+	if over := len(s.scrollback) - execScrollbackSize; over > 0 {
+		s.scrollback = append(s.scrollback[:0], s.scrollback[over:]...)
+	}
+	changed := false
+	for _, obs := range s.observers {
+		if !obs.approved {
+			continue
+		}
+		select {
+		case obs.out <- append([]byte(nil), p...):
+		default:
+			s.removeObserverLocked(obs, "disconnected: not keeping up with the output")
+			changed = true
+		}
+	}
+	if changed {
+		s.notifyOwnerLocked()
+	}
+}
+
+func (s *ExecSession) setShared(shared bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shared = shared
+	if !shared {
+		for _, obs := range s.observers {
+			s.removeObserverLocked(obs, "the owner stopped sharing")
+		}
+	}
+	if shared {
+		log.Printf("Exec session %s: sharing enabled", s.ID)
+	} else {
+		log.Printf("Exec session %s: sharing disabled", s.ID)
+	}
+	s.notifyOwnerLocked()
+}
+
+func (s *ExecSession) addObserver(name string) (*execObserver, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.done:
+		return nil, errors.New("the session has ended")
+	default:
+	}
+	if !s.shared {
+		return nil, errNotShared
+	}
+	s.nextObsID++
+	obs := &execObserver{
+		id:       fmt.Sprintf("%s-obs-%d", s.ID, s.nextObsID),
+		name:     name,
+		decision: make(chan bool, 1),
+		out:      make(chan []byte, observerBuffer),
+	}
+	s.observers[obs.id] = obs
+	s.owner.send(TerminalMessage{Type: "observer_request", ID: obs.id, Name: name})
+	s.notifyOwnerLocked()
+	return obs, nil
+}
+
+// decide applies the owner's answer to a pending observer. The scrollback is
+// queued under the lock so it arrives before any newer output.
+func (s *ExecSession) decide(id string, approve bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obs, ok := s.observers[id]
+	if !ok || obs.approved {
+		return
+	}
+	if !approve {
+		s.removeObserverLocked(obs, "the owner declined")
+		s.notifyOwnerLocked()
+		return
+	}
+	obs.approved = true
+	if len(s.scrollback) > 0 {
+		obs.out <- append([]byte(nil), s.scrollback...)
+	}
+	obs.decision <- true
+	log.Printf("Exec session %s: observer %q approved", s.ID, obs.name)
+	s.notifyOwnerLocked()
+}
+
+func (s *ExecSession) revoke(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if obs, ok := s.observers[id]; ok {
+		s.removeObserverLocked(obs, "the owner removed you from the session")
+		s.notifyOwnerLocked()
+	}
+}
+
+func (s *ExecSession) leave(obs *execObserver) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.observers[obs.id]; ok {
+		s.removeObserverLocked(obs, "")
+		s.notifyOwnerLocked()
+	}
+}
+
+func (s *ExecSession) removeObserverLocked(obs *execObserver, reason string) {
+	delete(s.observers, obs.id)
+	obs.reason = reason
+	if !obs.approved {
+		select {
+		case obs.decision <- false:
+		default:
+		}
+	}
+	close(obs.out)
+}
+
+// end disconnects all observers; safe to call more than once
+func (s *ExecSession) end() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.done:
+		return
+	default:
+	}
+	close(s.done)
+	for _, obs := range s.observers {
+		s.removeObserverLocked(obs, "the session ended")
+	}
+}
+
+func (s *ExecSession) notifyOwnerLocked() {
+	infos := make([]ExecObserverInfo, 0, len(s.observers))
+	for _, obs := range s.observers {
+		infos = append(infos, ExecObserverInfo{ID: obs.id, Name: obs.name, Approved: obs.approved})
+	}
+	s.owner.send(TerminalMessage{Type: "observers", Observers: infos})
+}
+
+func (s *ExecSession) info() ExecSessionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return ExecSessionInfo{
+		ID: s.ID, Namespace: s.Namespace, Pod: s.Pod, Container: s.Container,
+		StartedAt: s.StartedAt, Client: s.Client, LastActivity: s.lastActivity(),
+		Shared: s.shared, Observers: len(s.observers),
+	}
+}
+
+// handleListExecSessions lists active exec sessions, so an observer can find
+// the one to ask for
+func (s *Server) handleListExecSessions(w http.ResponseWriter, r *http.Request) {
+	execManager.mu.RLock()
+	sessions := make([]*ExecSession, 0, len(execManager.sessions))
+	for _, session := range execManager.sessions {
+		sessions = append(sessions, session)
+	}
+	execManager.mu.RUnlock()
+
+	infos := make([]ExecSessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		infos = append(infos, session.info())
+	}
+	s.writeJSON(w, infos)
+}
+
+// handleExecObserve attaches a read-only observer to a shared exec session
+// once the owner approves. Query params: name (shown to the owner).
+func (s *Server) handleExecObserve(w http.ResponseWriter, r *http.Request) {
+	execManager.mu.RLock()
+	session := execManager.sessions[chi.URLParam(r, "id")]
+	execManager.mu.RUnlock()
+	if session == nil {
+		s.writeError(w, http.StatusNotFound, "exec session not found")
+		return
+	}
+
+	name := strings.TrimSpace(r.URL.Query().Get("name"))
+	if name == "" {
+		host, _, _ := net.SplitHostPort(r.RemoteAddr)
+		name = "observer from " + host
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("WebSocket upgrade error: %v", err)
+		return
+	}
+	defer conn.Close()
+	out := &wsWriter{conn: conn}
+
+	obs, err := session.addObserver(name)
+	if err != nil {
+		sendWSErrorWithType(conn, "not_shared", err.Error())
+		return
+	}
+	defer session.leave(obs)
+	log.Printf("Exec session %s: %q asked to observe", session.ID, name)
+
+	// Observers are read-only: input is discarded, a read error means they left
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	out.send(TerminalMessage{Type: "pending", ID: session.ID})
+	select {
+	case approved := <-obs.decision:
+		if !approved {
+			// reason is set before the answer is sent
+			out.send(TerminalMessage{Type: "denied", Data: obs.reason})
+			return
+		}
+	case <-gone:
+		return
+	case <-time.After(observerApprovalTimeout):
+		out.send(TerminalMessage{Type: "denied", Data: "the owner didn't respond"})
+		return
+	}
+	out.send(TerminalMessage{Type: "approved", ID: session.ID})
+
+	for {
+		select {
+		case data, ok := <-obs.out:
+			if !ok {
+				out.send(TerminalMessage{Type: "ended", Data: obs.reason})
+				return
+			}
+			if _, err := out.Write(data); err != nil {
+				return
+			}
+		case <-gone:
+			return
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// handleExportWorkload exports a workload and its Services, ConfigMaps, and
+// Ingresses as a cleaned manifest bundle for adoption into GitOps
+// GET /api/workloads/{kind}/{namespace}/{name}/export?format=yaml|kustomize|helm
+func (s *Server) handleExportWorkload(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	kind := chi.URLParam(r, "kind")
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	format, err := k8s.ParseExportFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	export, err := k8s.ExportWorkload(kind, namespace, name, format)
+	if err != nil {
+		if errors.Is(err, k8s.ErrUnsupportedExportKind) {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if apierrors.IsNotFound(err) {
+			s.writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		log.Printf("[export] Failed to export %s %s/%s: %v", kind, namespace, name, err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.writeJSON(w, export)
+}
@@ -5,14 +5,19 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"path"
 	"sort"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/go-chi/chi/v5"
 	corev1 "k8s.io/api/core/v1"
@@ -59,6 +64,21 @@ type podFilesystemDeleteRequest struct {
 	Recursive bool   `json:"recursive"`
 }
 
+type podFilesystemCopyRequest struct {
+	Container string                  `json:"container"`
+	Path      string                  `json:"path"`
+	Target    podFilesystemCopyTarget `json:"target"`
+}
+
+// podFilesystemCopyTarget is the destination of a copy. Path is a directory
+// the source is copied into; it's created if missing.
+type podFilesystemCopyTarget struct {
+	Namespace string `json:"namespace"` // Defaults to the source pod's namespace
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	Path      string `json:"path"`
+}
+
 func (s *Server) handlePodFilesystemList(w http.ResponseWriter, r *http.Request) {
 	if !s.requireConnected(w) {
 		return
@@ -120,13 +140,72 @@ func (s *Server) handlePodFilesystemDownload(w http.ResponseWriter, r *http.Requ
 			s.writeError(w, http.StatusBadRequest, "path is not a file")
 			return
 		}
+		if errors.Is(err, errChecksumMismatch) {
+			s.writeError(w, http.StatusBadGateway, "failed to download file: "+err.Error())
+			return
+		}
 		s.writeError(w, http.StatusInternalServerError, "failed to download file: "+err.Error())
 		return
 	}
+	setChecksumHeader(w, content)
 	_, _ = w.Write(content)
 }
 
-func (s *Server) handlePodFilesystemSave(w http.ResponseWriter, r *http.Request) {
+// defaultMaxEditSize is the largest file the pod file editor opens or saves
+// unless --max-edit-size says otherwise
+const defaultMaxEditSize = 2 << 20
+
+// binarySniffSize is how much of a file is inspected to tell text from binary
+const binarySniffSize = 8192
+
+type podFileInfo struct {
+	Path        string `json:"path"`
+	Size        int64  `json:"size"`
+	Permissions string `json:"permissions"`
+	UID         string `json:"uid"`
+	GID         string `json:"gid"`
+	Binary      bool   `json:"binary"`
+	Editable    bool   `json:"editable"`
+	Reason      string `json:"reason,omitempty"` // Why the file isn't editable
+	MaxEditSize int64  `json:"maxEditSize"`
+}
+
+// isBinaryContent reports whether content looks binary: it has NUL bytes or
+// isn't valid UTF-8. A rune cut off at the end of a sniffed prefix is allowed.
+func isBinaryContent(content []byte, truncated bool) bool {
+	if bytes.IndexByte(content, 0) >= 0 {
+		return true
+	}
+	if truncated {
+		for i := 0; i < utf8.UTFMax-1 && len(content) > 0 && !utf8.Valid(content); i++ {
+			content = content[:len(content)-1]
+		}
+	}
+	return !utf8.Valid(content)
+}
+
+// readEditBody reads a file body for the editor, rejecting it with 413 when
+// it exceeds the max edit size and 415 when it's binary
+func (s *Server) readEditBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, s.maxEditSize+1))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "failed to read request body")
+		return nil, false
+	}
+	if int64(len(body)) > s.maxEditSize {
+		s.writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("file exceeds the max edit size of %d bytes; use upload instead", s.maxEditSize))
+		return nil, false
+	}
+	if isBinaryContent(body, false) {
+		s.writeError(w, http.StatusUnsupportedMediaType, "content is binary; use upload instead")
+		return nil, false
+	}
+	return body, true
+}
+
+// handlePodFilesystemFileInfo reports whether a file can be opened in the
+// editor: its size against the max edit size, and whether it's binary
+func (s *Server) handlePodFilesystemFileInfo(w http.ResponseWriter, r *http.Request) {
 	if !s.requireConnected(w) {
 		return
 	}
@@ -140,9 +219,79 @@ func (s *Server) handlePodFilesystemSave(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	body, err := io.ReadAll(io.LimitReader(r.Body, 2<<20))
+	// First line is the ls metadata (following symlinks), then the sniffed prefix
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	script := `
+set -eu
+f="$1"
+[ -f "$f" ] || { echo "__ERR_NOT_FILE__" >&2; exit 13; }
+ls -ldnL -- "$f"
+head -c "$2" -- "$f" 2>/dev/null || true
+`
+	err := s.execInPod(r.Context(), namespace, podName, container, []string{"sh", "-c", script, "sh", filePath, strconv.Itoa(binarySniffSize)}, nil, stdout, stderr)
 	if err != nil {
-		s.writeError(w, http.StatusBadRequest, "failed to read request body")
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		if strings.Contains(msg, "__ERR_NOT_FILE__") {
+			s.writeError(w, http.StatusBadRequest, "path is not a file")
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to inspect file: "+msg)
+		return
+	}
+
+	meta, sniffed, _ := bytes.Cut(stdout.Bytes(), []byte("\n"))
+	fields := strings.Fields(string(meta))
+	if len(fields) < 5 {
+		s.writeError(w, http.StatusInternalServerError, "failed to inspect file: unexpected ls output")
+		return
+	}
+	size, _ := strconv.ParseInt(fields[4], 10, 64)
+	info := podFileInfo{
+		Path:        filePath,
+		Size:        size,
+		Permissions: fields[0],
+		UID:         fields[2],
+		GID:         fields[3],
+		Binary:      isBinaryContent(sniffed, size > int64(len(sniffed))),
+		MaxEditSize: s.maxEditSize,
+	}
+	switch {
+	case info.Binary:
+		info.Reason = "binary file"
+	case size > s.maxEditSize:
+		info.Reason = fmt.Sprintf("file is larger than the max edit size of %d bytes", s.maxEditSize)
+	default:
+		info.Editable = true
+	}
+	s.writeJSON(w, info)
+}
+
+// handlePodFilesystemSave overwrites an existing text file. The file is
+// rewritten in place, so its inode, mode and ownership are kept. Query params:
+// backup=true copies it to <path>.bak first (cp -p keeps mode and ownership
+// where the container user may), force=true overwrites a file that looks binary.
+func (s *Server) handlePodFilesystemSave(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	namespace := chi.URLParam(r, "namespace")
+	podName := chi.URLParam(r, "name")
+	container := r.URL.Query().Get("container")
+	filePath := r.URL.Query().Get("path")
+	if strings.TrimSpace(filePath) == "" {
+		s.writeError(w, http.StatusBadRequest, "path is required")
+		return
+	}
+	backup := r.URL.Query().Get("backup") == "true"
+	force := r.URL.Query().Get("force") == "true"
+
+	body, ok := s.readEditBody(w, r)
+	if !ok {
 		return
 	}
 
@@ -150,29 +299,123 @@ func (s *Server) handlePodFilesystemSave(w http.ResponseWriter, r *http.Request)
 	script := `
 set -eu
 dest="$1"
-[ -f "$dest" ] || [ -L "$dest" ] || { echo "__ERR_NOT_FILE__" >&2; exit 13; }
+backup="$2"
+force="$3"
+[ -f "$dest" ] || { echo "__ERR_NOT_FILE__" >&2; exit 13; }
 [ -w "$dest" ] || { echo "__ERR_PERMISSION__ destination file is not writable" >&2; exit 77; }
+if [ "$force" != "1" ]; then
+  total="$(head -c 8192 -- "$dest" | wc -c)"
+  text="$(head -c 8192 -- "$dest" | tr -d '\000' | wc -c)"
+  [ "$total" = "$text" ] || { echo "__ERR_BINARY__" >&2; exit 16; }
+fi
+if [ "$backup" = "1" ]; then
+  cp -p -- "$dest" "$dest.bak" 2>/dev/null || cp -- "$dest" "$dest.bak" 2>/dev/null || { echo "__ERR_BACKUP__" >&2; exit 18; }
+fi
 cat > "$dest" 2>/dev/null || { echo "__ERR_PERMISSION__ destination file is not writable" >&2; exit 77; }
 `
-	err = s.execInPod(r.Context(), namespace, podName, container, []string{"sh", "-c", script, "sh", filePath}, bytes.NewReader(body), io.Discard, stderr)
+	args := []string{"sh", "-c", script, "sh", filePath, shellFlag(backup), shellFlag(force)}
+	err := s.execInPod(r.Context(), namespace, podName, container, args, bytes.NewReader(body), io.Discard, stderr)
 	if err != nil {
 		msg := strings.TrimSpace(stderr.String())
 		if msg == "" {
 			msg = err.Error()
 		}
-		if strings.Contains(msg, "__ERR_NOT_FILE__") {
+		switch {
+		case strings.Contains(msg, "__ERR_NOT_FILE__"):
 			s.writeError(w, http.StatusBadRequest, "path is not a file")
-			return
-		}
-		if strings.Contains(msg, "__ERR_PERMISSION__") || strings.Contains(strings.ToLower(msg), "permission denied") {
+		case strings.Contains(msg, "__ERR_BINARY__"):
+			s.writeError(w, http.StatusUnsupportedMediaType, "target is a binary file; pass force=true to overwrite it")
+		case strings.Contains(msg, "__ERR_BACKUP__"):
+			s.writeError(w, http.StatusForbidden, "could not create backup "+filePath+".bak; file left unchanged")
+		case strings.Contains(msg, "__ERR_PERMISSION__") || strings.Contains(strings.ToLower(msg), "permission denied"):
 			s.writeError(w, http.StatusForbidden, "permission denied: target file is not writable by the container user")
+		default:
+			s.writeError(w, http.StatusInternalServerError, "failed to save file: "+msg)
+		}
+		return
+	}
+
+	resp := map[string]any{"ok": true}
+	if backup {
+		resp["backup"] = filePath + ".bak"
+	}
+	s.writeJSON(w, resp)
+}
+
+// handlePodFilesystemCreate creates a new text file, failing with 409 if the
+// path exists. Query params: mode (octal, e.g. 0644; default from the
+// container's umask).
+func (s *Server) handlePodFilesystemCreate(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	namespace := chi.URLParam(r, "namespace")
+	podName := chi.URLParam(r, "name")
+	container := r.URL.Query().Get("container")
+	filePath := r.URL.Query().Get("path")
+	if strings.TrimSpace(filePath) == "" {
+		s.writeError(w, http.StatusBadRequest, "path is required")
+		return
+	}
+	mode := r.URL.Query().Get("mode")
+	if mode != "" {
+		if parsed, err := strconv.ParseUint(mode, 8, 32); err != nil || parsed > 0o7777 {
+			s.writeError(w, http.StatusBadRequest, "mode must be octal permissions, e.g. 0644")
 			return
 		}
-		s.writeError(w, http.StatusInternalServerError, "failed to save file: "+msg)
+	}
+
+	body, ok := s.readEditBody(w, r)
+	if !ok {
 		return
 	}
 
-	s.writeJSON(w, map[string]bool{"ok": true})
+	// noclobber makes the create exclusive, so a file that appears
+	// concurrently isn't overwritten
+	stderr := &bytes.Buffer{}
+	script := `
+set -eu
+dest="$1"
+mode="$2"
+[ ! -e "$dest" ] && [ ! -L "$dest" ] || { echo "__ERR_EXISTS__" >&2; exit 17; }
+parent="$(dirname -- "$dest")"
+[ -d "$parent" ] || { echo "__ERR_NO_PARENT__" >&2; exit 14; }
+[ -w "$parent" ] || { echo "__ERR_PERMISSION__ target directory is not writable" >&2; exit 77; }
+( set -C; cat > "$dest" ) 2>/dev/null || { [ -e "$dest" ] && echo "__ERR_EXISTS__" >&2 || echo "__ERR_PERMISSION__ cannot create file" >&2; exit 77; }
+if [ -n "$mode" ]; then
+  chmod -- "$mode" "$dest"
+fi
+`
+	err := s.execInPod(r.Context(), namespace, podName, container, []string{"sh", "-c", script, "sh", filePath, mode}, bytes.NewReader(body), io.Discard, stderr)
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		switch {
+		case strings.Contains(msg, "__ERR_EXISTS__"):
+			s.writeError(w, http.StatusConflict, "path already exists")
+		case strings.Contains(msg, "__ERR_NO_PARENT__"):
+			s.writeError(w, http.StatusBadRequest, "parent directory does not exist")
+		case strings.Contains(msg, "__ERR_PERMISSION__") || strings.Contains(strings.ToLower(msg), "permission denied"):
+			s.writeError(w, http.StatusForbidden, "permission denied: target directory is not writable by the container user")
+		default:
+			s.writeError(w, http.StatusInternalServerError, "failed to create file: "+msg)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+}
+
+func shellFlag(enabled bool) string {
+	if enabled {
+		return "1"
+	}
+	return "0"
 }
 
 func (s *Server) handlePodFilesystemSearch(w http.ResponseWriter, r *http.Request) {
@@ -317,17 +560,21 @@ func (s *Server) handlePodFilesystemArchive(w http.ResponseWriter, r *http.Reque
 		zipName = baseName
 	}
 
-	if ok, content, err := s.buildZipFromTarInContainer(r.Context(), namespace, podName, container, targetPath); err == nil && ok {
-		w.Header().Set("Content-Type", "application/zip")
-		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", zipName))
-		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
-		_, _ = w.Write(content)
+	// A truncated transfer fails the download rather than falling back to a
+	// slower strategy that reads the same data over the same exec stream
+	ok, content, err := s.buildZipFromTarInContainer(r.Context(), namespace, podName, container, targetPath)
+	if !ok && !errors.Is(err, errChecksumMismatch) {
+		ok, content, err = s.buildZipInContainer(r.Context(), namespace, podName, container, targetPath)
+	}
+	if errors.Is(err, errChecksumMismatch) {
+		s.writeError(w, http.StatusBadGateway, "failed to create archive: "+err.Error())
 		return
 	}
-	if ok, content, err := s.buildZipInContainer(r.Context(), namespace, podName, container, targetPath); err == nil && ok {
+	if err == nil && ok {
 		w.Header().Set("Content-Type", "application/zip")
 		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", zipName))
 		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		setChecksumHeader(w, content)
 		_, _ = w.Write(content)
 		return
 	}
@@ -346,6 +593,10 @@ func (s *Server) handlePodFilesystemArchive(w http.ResponseWriter, r *http.Reque
 
 	if kind == "file" || kind == "symlink" {
 		content, err := s.readPodFile(r.Context(), namespace, podName, container, targetPath)
+		if errors.Is(err, errChecksumMismatch) {
+			s.writeError(w, http.StatusBadGateway, "failed to create archive: "+err.Error())
+			return
+		}
 		if err != nil {
 			s.writeError(w, http.StatusInternalServerError, "failed to create archive: "+err.Error())
 			return
@@ -393,6 +644,10 @@ func (s *Server) handlePodFilesystemArchive(w http.ResponseWriter, r *http.Reque
 					})
 				case "file", "symlink":
 					content, err := s.readPodFile(r.Context(), namespace, podName, container, path.Join(curPath, entry.Name))
+					if errors.Is(err, errChecksumMismatch) {
+						s.writeError(w, http.StatusBadGateway, "failed to create archive: "+err.Error())
+						return
+					}
 					if err != nil {
 						continue
 					}
@@ -418,6 +673,7 @@ func (s *Server) handlePodFilesystemArchive(w http.ResponseWriter, r *http.Reque
 	w.Header().Set("Content-Type", "application/zip")
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", zipName))
 	w.Header().Set("Content-Length", strconv.Itoa(zipBuf.Len()))
+	setChecksumHeader(w, zipBuf.Bytes())
 	_, _ = io.Copy(w, zipBuf)
 }
 
@@ -508,23 +764,80 @@ done
 }
 
 func (s *Server) readPodFile(ctx context.Context, namespace, podName, container, filePath string) ([]byte, error) {
-	stdout := &bytes.Buffer{}
-	stderr := &bytes.Buffer{}
 	script := `
 set -eu
 f="$1"
 [ -f "$f" ] || [ -L "$f" ] || { echo "__ERR_NOT_FILE__" >&2; exit 13; }
-cat "$f"
+` + sha256Pipeline(`cat "$f"`)
+	return s.execVerified(ctx, namespace, podName, container, []string{"sh", "-c", script, "sh", filePath})
+}
+
+// errChecksumMismatch means the bytes received from a pod didn't match the
+// digest computed in the container, i.e. the exec stream was cut short
+var errChecksumMismatch = errors.New("checksum mismatch: transfer from the container was incomplete")
+
+// sha256Pipeline wraps a shell command so its output is hashed in the
+// container in the same pass, without a temp file. The digest goes to stderr
+// as "__SHA256__ <hex>", and a failing command as "__ERR_EXIT__" (a pipeline
+// only reports the last command's status). Without sha256sum or /dev/fd the
+// command runs bare and the transfer is unverified.
+func sha256Pipeline(command string) string {
+	return `if command -v sha256sum >/dev/null 2>&1 && [ -e /dev/fd/1 ]; then
+  { { ` + command + ` || echo "__ERR_EXIT__" >&2; } | tee /dev/fd/3 | sha256sum | sed 's/^/__SHA256__ /' >&2; } 3>&1
+else
+  ` + command + `
+fi
 `
-	err := s.execInPod(ctx, namespace, podName, container, []string{"sh", "-c", script, "sh", filePath}, nil, stdout, stderr)
-	if err != nil {
-		msg := strings.TrimSpace(stderr.String())
-		if msg == "" {
-			msg = err.Error()
+}
+
+// execVerified runs a command built with sha256Pipeline and checks the
+// output against the container's digest. A mismatch is retried once before
+// errChecksumMismatch is returned.
+func (s *Server) execVerified(ctx context.Context, namespace, podName, container string, command []string) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		stdout := &bytes.Buffer{}
+		stderr := &bytes.Buffer{}
+		err := s.execInPod(ctx, namespace, podName, container, command, nil, stdout, stderr)
+		digest, failed, msg := parseChecksumStderr(stderr.String())
+		if err != nil || failed {
+			if msg == "" && err != nil {
+				msg = err.Error()
+			}
+			return nil, fmt.Errorf("%s", msg)
+		}
+		if digest == "" {
+			return stdout.Bytes(), nil
+		}
+		if sum := sha256.Sum256(stdout.Bytes()); hex.EncodeToString(sum[:]) == digest {
+			return stdout.Bytes(), nil
+		}
+		log.Printf("[filesystem] Checksum mismatch reading from %s/%s (attempt %d, %d bytes received)", namespace, podName, attempt, stdout.Len())
+		if attempt == 2 || ctx.Err() != nil {
+			return nil, errChecksumMismatch
+		}
+	}
+}
+
+// parseChecksumStderr splits sha256Pipeline's markers from a command's stderr
+func parseChecksumStderr(stderr string) (digest string, failed bool, msg string) {
+	var rest []string
+	for _, line := range strings.Split(stderr, "\n") {
+		switch {
+		case strings.HasPrefix(line, "__SHA256__ "):
+			digest, _, _ = strings.Cut(strings.TrimPrefix(line, "__SHA256__ "), " ")
+		case strings.TrimSpace(line) == "__ERR_EXIT__":
+			failed = true
+		default:
+			rest = append(rest, line)
 		}
-		return nil, fmt.Errorf("%s", msg)
 	}
-	return stdout.Bytes(), nil
+	return digest, failed, strings.TrimSpace(strings.Join(rest, "\n"))
+}
+
+// setChecksumHeader sets X-Content-SHA256 (hex) on a download
+func setChecksumHeader(w http.ResponseWriter, content []byte) {
+	sum := sha256.Sum256(content)
+	w.Header().Set("X-Content-SHA256", hex.EncodeToString(sum[:]))
 }
 
 func (s *Server) getPodPathType(ctx context.Context, namespace, podName, container, targetPath string) (string, error) {
@@ -555,8 +868,6 @@ fi
 }
 
 func (s *Server) buildZipInContainer(ctx context.Context, namespace, podName, container, targetPath string) (bool, []byte, error) {
-	stdout := &bytes.Buffer{}
-	stderr := &bytes.Buffer{}
 	script := `
 set -eu
 target="$1"
@@ -569,28 +880,22 @@ fi
 parent="$(dirname "$target")"
 name="$(basename "$target")"
 cd "$parent"
-zip -q -r - "$name"
-`
-	err := s.execInPod(ctx, namespace, podName, container, []string{"sh", "-c", script, "sh", targetPath}, nil, stdout, stderr)
+` + sha256Pipeline(`zip -q -r - "$name"`)
+	content, err := s.execVerified(ctx, namespace, podName, container, []string{"sh", "-c", script, "sh", targetPath})
 	if err != nil {
-		msg := strings.TrimSpace(stderr.String())
+		msg := err.Error()
 		if strings.Contains(msg, "__ERR_NO_ZIP__") {
 			return false, nil, nil
 		}
 		if strings.Contains(msg, "__ERR_NOT_FOUND__") {
 			return false, nil, fmt.Errorf("path not found")
 		}
-		if msg == "" {
-			msg = err.Error()
-		}
-		return false, nil, fmt.Errorf("%s", msg)
+		return false, nil, err
 	}
-	return true, stdout.Bytes(), nil
+	return true, content, nil
 }
 
 func (s *Server) buildZipFromTarInContainer(ctx context.Context, namespace, podName, container, targetPath string) (bool, []byte, error) {
-	stdout := &bytes.Buffer{}
-	stderr := &bytes.Buffer{}
 	script := `
 set -eu
 target="$1"
@@ -598,21 +903,16 @@ cd / || true
 [ -e "$target" ] || { echo "__ERR_NOT_FOUND__" >&2; exit 14; }
 parent="$(dirname "$target")"
 name="$(basename "$target")"
-tar -h -C "$parent" -cf - "$name"
-`
-	err := s.execInPod(ctx, namespace, podName, container, []string{"sh", "-c", script, "sh", targetPath}, nil, stdout, stderr)
+` + sha256Pipeline(`tar -h -C "$parent" -cf - "$name"`)
+	tarball, err := s.execVerified(ctx, namespace, podName, container, []string{"sh", "-c", script, "sh", targetPath})
 	if err != nil {
-		msg := strings.TrimSpace(stderr.String())
-		if strings.Contains(msg, "__ERR_NOT_FOUND__") {
+		if strings.Contains(err.Error(), "__ERR_NOT_FOUND__") {
 			return false, nil, fmt.Errorf("path not found")
 		}
-		if msg == "" {
-			msg = err.Error()
-		}
-		return false, nil, fmt.Errorf("%s", msg)
+		return false, nil, err
 	}
 
-	tr := tar.NewReader(bytes.NewReader(stdout.Bytes()))
+	tr := tar.NewReader(bytes.NewReader(tarball))
 	zipBuf := &bytes.Buffer{}
 	zw := zip.NewWriter(zipBuf)
 
@@ -833,6 +1133,131 @@ fi
 	s.writeJSON(w, map[string]bool{"ok": true})
 }
 
+// handlePodFilesystemCopy copies a file or directory from this pod into a
+// directory of another pod (or another container of the same pod). A tar
+// stream is piped between exec sessions in both containers, so the data
+// never leaves the server. Both containers need tar.
+func (s *Server) handlePodFilesystemCopy(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	namespace := chi.URLParam(r, "namespace")
+	podName := chi.URLParam(r, "name")
+
+	var req podFilesystemCopyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Path) == "" || strings.TrimSpace(req.Target.Path) == "" {
+		s.writeError(w, http.StatusBadRequest, "path and target.path are required")
+		return
+	}
+	if req.Target.Pod == "" {
+		s.writeError(w, http.StatusBadRequest, "target.pod is required")
+		return
+	}
+	if req.Target.Namespace == "" {
+		req.Target.Namespace = namespace
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	pr, pw := io.Pipe()
+	counter := &countingWriter{w: pw}
+	srcStderr := &bytes.Buffer{}
+	srcDone := make(chan error, 1)
+	go func() {
+		script := `
+set -eu
+src="$1"
+command -v tar >/dev/null 2>&1 || { echo "__ERR_NO_TAR__" >&2; exit 127; }
+[ -e "$src" ] || [ -L "$src" ] || { echo "__ERR_NOT_FOUND__" >&2; exit 14; }
+tar -C "$(dirname "$src")" -cf - "$(basename "$src")"
+`
+		err := s.execInPod(ctx, namespace, podName, req.Container, []string{"sh", "-c", script, "sh", req.Path}, nil, counter, srcStderr)
+		if err != nil {
+			// Unblock the destination's tar so it fails instead of waiting
+			pw.CloseWithError(err)
+		} else {
+			pw.Close()
+		}
+		srcDone <- err
+	}()
+
+	dstStderr := &bytes.Buffer{}
+	script := `
+set -eu
+dest="$1"
+command -v tar >/dev/null 2>&1 || { echo "__ERR_NO_TAR__" >&2; exit 127; }
+mkdir -p "$dest" 2>/dev/null || { echo "__ERR_PERMISSION__ cannot create target directory" >&2; exit 77; }
+[ -w "$dest" ] || { echo "__ERR_PERMISSION__ target directory is not writable" >&2; exit 77; }
+tar -C "$dest" -xf -
+`
+	dstErr := s.execInPod(ctx, req.Target.Namespace, req.Target.Pod, req.Target.Container, []string{"sh", "-c", script, "sh", req.Target.Path}, pr, io.Discard, dstStderr)
+	if dstErr != nil {
+		// Stop the source if the destination gave up first
+		cancel()
+	}
+	pr.Close()
+	srcErr := <-srcDone
+
+	// A source failure also breaks the destination's tar; report the cause.
+	// When the destination fails first the source is only cancelled.
+	if srcErr != nil && (dstErr == nil || srcStderr.Len() > 0) {
+		msg := strings.TrimSpace(srcStderr.String())
+		if msg == "" {
+			msg = srcErr.Error()
+		}
+		switch {
+		case strings.Contains(msg, "__ERR_NOT_FOUND__"):
+			s.writeError(w, http.StatusNotFound, "source path not found")
+		case strings.Contains(msg, "__ERR_NO_TAR__"):
+			s.writeError(w, http.StatusBadRequest, "source container has no tar binary")
+		case strings.Contains(strings.ToLower(msg), "permission denied"):
+			s.writeError(w, http.StatusForbidden, "permission denied: source path is not readable by the container user")
+		default:
+			s.writeError(w, http.StatusInternalServerError, "failed to read source: "+msg)
+		}
+		return
+	}
+	if dstErr != nil {
+		msg := strings.TrimSpace(dstStderr.String())
+		if msg == "" {
+			msg = dstErr.Error()
+		}
+		switch {
+		case strings.Contains(msg, "__ERR_NO_TAR__"):
+			s.writeError(w, http.StatusBadRequest, "target container has no tar binary")
+		case strings.Contains(msg, "__ERR_PERMISSION__") || strings.Contains(strings.ToLower(msg), "permission denied"):
+			s.writeError(w, http.StatusForbidden, "permission denied: target path is not writable by the target container user")
+		default:
+			s.writeError(w, http.StatusInternalServerError, "failed to write target: "+msg)
+		}
+		return
+	}
+
+	s.writeJSON(w, map[string]any{
+		"ok":          true,
+		"bytes":       counter.n,
+		"destination": path.Join(req.Target.Path, path.Base(path.Clean(req.Path))),
+	})
+}
+
+// countingWriter counts bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
 func (s *Server) execInPod(ctx context.Context, namespace, podName, container string, command []string, stdin io.Reader, stdout, stderr io.Writer) error {
 	client := k8s.GetClient()
 	config := k8s.GetConfig()
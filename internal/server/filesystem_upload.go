@@ -0,0 +1,498 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// maxUploadChunkSize caps one chunk so a request stays well inside the
+	// API timeout; clients send chunks of any size up to this
+	maxUploadChunkSize = 8 << 20
+	// uploadIdleTimeout drops sessions nobody touched for this long. The
+	// partial file stays in the pod and a new session resumes from it.
+	uploadIdleTimeout = time.Hour
+	// uploadPartialSuffix names the file chunks are appended to until the
+	// upload completes and it's renamed into place
+	uploadPartialSuffix = ".radar-upload"
+)
+
+// UploadState is the progress of a chunked upload of one file into a pod.
+// Offset is the size of the partial file in the pod, which is the source of
+// truth: a client resumes by sending the chunk that starts there.
+type UploadState struct {
+	ID        string    `json:"id"`
+	Namespace string    `json:"namespace"`
+	PodName   string    `json:"podName"`
+	Container string    `json:"container,omitempty"`
+	Path      string    `json:"path"`
+	Size      int64     `json:"size"`
+	Offset    int64     `json:"offset"`
+	Done      bool      `json:"done"`
+	Error     string    `json:"error,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+}
+
+type uploadSession struct {
+	UploadState
+
+	writeMu     sync.Mutex // Serializes chunk writes
+	updatedAt   time.Time
+	subscribers map[chan UploadState]struct{}
+}
+
+// UploadManager tracks chunked upload sessions. There is at most one active
+// session per destination path, since sessions share its partial file.
+type UploadManager struct {
+	sessions map[string]*uploadSession
+	starting map[string]bool // Paths whose session is being set up
+	mu       sync.Mutex
+	nextID   int
+}
+
+var uploadManager = &UploadManager{
+	sessions: make(map[string]*uploadSession),
+	starting: make(map[string]bool),
+}
+
+func uploadPathKey(namespace, podName, container, path string) string {
+	return namespace + "/" + podName + "/" + container + ":" + path
+}
+
+func (sess *uploadSession) pathKey() string {
+	return uploadPathKey(sess.Namespace, sess.PodName, sess.Container, sess.Path)
+}
+
+// reservePath claims a destination path for a new session. If an unfinished
+// session already writes to it, that session is returned instead; ok is false
+// when the path is taken, either by it or by another session being set up.
+func (m *UploadManager) reservePath(key string) (active *uploadSession, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expireLocked()
+	for _, sess := range m.sessions {
+		if !sess.Done && sess.pathKey() == key {
+			return sess, false
+		}
+	}
+	if m.starting[key] {
+		return nil, false
+	}
+	m.starting[key] = true
+	return nil, true
+}
+
+func (m *UploadManager) releasePath(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.starting, key)
+}
+
+func (m *UploadManager) get(id string) *uploadSession {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expireLocked()
+	return m.sessions[id]
+}
+
+func (m *UploadManager) expireLocked() {
+	for id, sess := range m.sessions {
+		if time.Since(sess.updatedAt) > uploadIdleTimeout {
+			sess.closeSubscribersLocked()
+			delete(m.sessions, id)
+		}
+	}
+}
+
+// update applies fn to a session and sends the new state to subscribers
+func (m *UploadManager) update(sess *uploadSession, fn func(*uploadSession)) UploadState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fn(sess)
+	sess.updatedAt = time.Now()
+	snapshot := sess.snapshotLocked()
+	for ch := range sess.subscribers {
+		select {
+		case ch <- snapshot:
+		default:
+			// Slow subscriber; it gets the next update
+		}
+	}
+	return snapshot
+}
+
+func (m *UploadManager) snapshot(sess *uploadSession) UploadState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return sess.snapshotLocked()
+}
+
+func (sess *uploadSession) snapshotLocked() UploadState {
+	return sess.UploadState
+}
+
+func (m *UploadManager) subscribe(sess *uploadSession) (chan UploadState, func()) {
+	ch := make(chan UploadState, 16)
+	m.mu.Lock()
+	sess.subscribers[ch] = struct{}{}
+	m.mu.Unlock()
+	return ch, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if _, ok := sess.subscribers[ch]; ok {
+			delete(sess.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+func (m *UploadManager) remove(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if sess, ok := m.sessions[id]; ok {
+		sess.closeSubscribersLocked()
+		delete(m.sessions, id)
+	}
+}
+
+func (sess *uploadSession) closeSubscribersLocked() {
+	for ch := range sess.subscribers {
+		close(ch)
+		delete(sess.subscribers, ch)
+	}
+}
+
+type startUploadRequest struct {
+	Container string `json:"container"`
+	Path      string `json:"path"`
+	Size      int64  `json:"size"`
+	Resume    bool   `json:"resume"` // Continue from a partial file left by an earlier session
+}
+
+// handleStartUpload starts a chunked upload. With resume, an existing partial
+// file for the path is kept and the returned offset says where to continue,
+// and an unfinished session for the path with the same size is returned as is.
+func (s *Server) handleStartUpload(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	namespace := chi.URLParam(r, "namespace")
+	podName := chi.URLParam(r, "name")
+
+	var req startUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Path) == "" {
+		s.writeError(w, http.StatusBadRequest, "path is required")
+		return
+	}
+	if req.Size < 0 {
+		s.writeError(w, http.StatusBadRequest, "size must not be negative")
+		return
+	}
+
+	key := uploadPathKey(namespace, podName, req.Container, req.Path)
+	active, ok := uploadManager.reservePath(key)
+	if !ok {
+		if active != nil && req.Resume && active.Size == req.Size {
+			s.writeJSON(w, uploadManager.snapshot(active))
+			return
+		}
+		s.writeError(w, http.StatusConflict, "another upload to this path is in progress")
+		return
+	}
+	defer uploadManager.releasePath(key)
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	script := `
+set -eu
+dest="$1"
+part="$1` + uploadPartialSuffix + `"
+resume="$2"
+parent="$(dirname -- "$dest")"
+[ ! -d "$dest" ] || { echo "__ERR_IS_DIR__" >&2; exit 21; }
+[ -d "$parent" ] || mkdir -p -- "$parent" 2>/dev/null || { echo "__ERR_PERMISSION__ cannot create parent directory" >&2; exit 77; }
+[ -w "$parent" ] || { echo "__ERR_PERMISSION__ target directory is not writable" >&2; exit 77; }
+if [ "$resume" != "1" ] || [ ! -f "$part" ]; then
+  : > "$part" 2>/dev/null || { echo "__ERR_PERMISSION__ cannot create partial file" >&2; exit 77; }
+fi
+wc -c < "$part"
+`
+	err := s.execInPod(r.Context(), namespace, podName, req.Container, []string{"sh", "-c", script, "sh", req.Path, shellFlag(req.Resume)}, nil, stdout, stderr)
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		switch {
+		case strings.Contains(msg, "__ERR_IS_DIR__"):
+			s.writeError(w, http.StatusBadRequest, "path is a directory")
+		case strings.Contains(msg, "__ERR_PERMISSION__") || strings.Contains(strings.ToLower(msg), "permission denied"):
+			s.writeError(w, http.StatusForbidden, "permission denied: target path is not writable by the container user")
+		default:
+			s.writeError(w, http.StatusInternalServerError, "failed to start upload: "+msg)
+		}
+		return
+	}
+	offset, err := strconv.ParseInt(strings.TrimSpace(stdout.String()), 10, 64)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to read partial file size")
+		return
+	}
+	if offset > req.Size {
+		s.writeError(w, http.StatusConflict, fmt.Sprintf("partial file is larger (%d bytes) than the upload; start without resume", offset))
+		return
+	}
+
+	uploadManager.mu.Lock()
+	uploadManager.expireLocked()
+	uploadManager.nextID++
+	sess := &uploadSession{
+		UploadState: UploadState{
+			ID:        fmt.Sprintf("upload-%d", uploadManager.nextID),
+			Namespace: namespace,
+			PodName:   podName,
+			Container: req.Container,
+			Path:      req.Path,
+			Size:      req.Size,
+			Offset:    offset,
+			StartedAt: time.Now(),
+		},
+		updatedAt:   time.Now(),
+		subscribers: make(map[chan UploadState]struct{}),
+	}
+	uploadManager.sessions[sess.ID] = sess
+	uploadManager.mu.Unlock()
+
+	log.Printf("[upload] Started %s: %s/%s:%s (%d bytes, resuming at %d)", sess.ID, namespace, podName, req.Path, req.Size, offset)
+
+	// An empty file (or a fully uploaded partial) completes right away
+	if offset == req.Size {
+		s.finishUpload(w, r, sess)
+		return
+	}
+	s.writeJSON(w, uploadManager.snapshot(sess))
+}
+
+// handleGetUpload returns an upload's state, e.g. the offset to resume from
+func (s *Server) handleGetUpload(w http.ResponseWriter, r *http.Request) {
+	sess := uploadManager.get(chi.URLParam(r, "id"))
+	if sess == nil {
+		s.writeError(w, http.StatusNotFound, "upload not found")
+		return
+	}
+	s.writeJSON(w, uploadManager.snapshot(sess))
+}
+
+// handleUploadChunk appends a chunk at ?offset=, which must equal the
+// session's offset. On a mismatch, 409 returns the state so the client can
+// continue from the right place; a chunk cut short by a broken exec stream
+// leaves the offset at however much reached the pod.
+func (s *Server) handleUploadChunk(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	sess := uploadManager.get(chi.URLParam(r, "id"))
+	if sess == nil {
+		s.writeError(w, http.StatusNotFound, "upload not found")
+		return
+	}
+	offset, err := strconv.ParseInt(r.URL.Query().Get("offset"), 10, 64)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "offset is required")
+		return
+	}
+
+	if !sess.writeMu.TryLock() {
+		s.writeError(w, http.StatusConflict, "another chunk is being written")
+		return
+	}
+	defer sess.writeMu.Unlock()
+
+	state := uploadManager.snapshot(sess)
+	if state.Done {
+		s.writeJSON(w, state)
+		return
+	}
+	if offset != state.Offset {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(state)
+		return
+	}
+
+	limit := min(state.Size-state.Offset, maxUploadChunkSize)
+	chunk, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "failed to read chunk: "+err.Error())
+		return
+	}
+	if int64(len(chunk)) > limit {
+		s.writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("chunk exceeds %d bytes (the max chunk size or the rest of the upload)", limit))
+		return
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	// Checking the partial file's size first catches a chunk written twice,
+	// e.g. after a retry whose response was lost
+	script := `
+set -eu
+part="$1` + uploadPartialSuffix + `"
+offset="$2"
+[ -f "$part" ] || { echo "__ERR_NO_PARTIAL__" >&2; exit 13; }
+cur="$(wc -c < "$part" | tr -d ' ')"
+if [ "$cur" != "$offset" ]; then
+  echo "$cur"
+  echo "__ERR_OFFSET__" >&2
+  exit 19
+fi
+cat >> "$part" 2>/dev/null || { echo "__ERR_PERMISSION__ partial file is not writable" >&2; exit 77; }
+wc -c < "$part"
+`
+	args := []string{"sh", "-c", script, "sh", sess.Path, strconv.FormatInt(offset, 10)}
+	execErr := s.execInPod(r.Context(), sess.Namespace, sess.PodName, sess.Container, args, bytes.NewReader(chunk), stdout, stderr)
+
+	// Whatever happened, the pod's file size is the new offset
+	if size, err := strconv.ParseInt(strings.TrimSpace(stdout.String()), 10, 64); err == nil {
+		state = uploadManager.update(sess, func(u *uploadSession) { u.Offset = size })
+	}
+	if execErr != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = execErr.Error()
+		}
+		switch {
+		case strings.Contains(msg, "__ERR_OFFSET__"):
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(state)
+		case strings.Contains(msg, "__ERR_NO_PARTIAL__"):
+			s.writeError(w, http.StatusGone, "partial file was removed from the pod; start a new upload")
+		case strings.Contains(msg, "__ERR_PERMISSION__") || strings.Contains(strings.ToLower(msg), "permission denied"):
+			s.writeError(w, http.StatusForbidden, "permission denied: target path is not writable by the container user")
+		default:
+			s.writeError(w, http.StatusInternalServerError, "failed to write chunk: "+msg)
+		}
+		return
+	}
+	if state.Offset == state.Size {
+		s.finishUpload(w, r, sess)
+		return
+	}
+	s.writeJSON(w, state)
+}
+
+// finishUpload moves the completed partial file into place
+func (s *Server) finishUpload(w http.ResponseWriter, r *http.Request, sess *uploadSession) {
+	stderr := &bytes.Buffer{}
+	script := `
+set -eu
+mv -f -- "$1` + uploadPartialSuffix + `" "$1" 2>/dev/null || { echo "__ERR_PERMISSION__ cannot move upload into place" >&2; exit 77; }
+`
+	err := s.execInPod(r.Context(), sess.Namespace, sess.PodName, sess.Container, []string{"sh", "-c", script, "sh", sess.Path}, nil, io.Discard, stderr)
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		uploadManager.update(sess, func(u *uploadSession) { u.Error = msg })
+		if strings.Contains(msg, "__ERR_PERMISSION__") {
+			s.writeError(w, http.StatusForbidden, "permission denied: target file is not writable by the container user")
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, "failed to complete upload: "+msg)
+		return
+	}
+	state := uploadManager.update(sess, func(u *uploadSession) {
+		u.Done = true
+		u.Error = ""
+	})
+	log.Printf("[upload] Completed %s: %s/%s:%s (%d bytes)", sess.ID, sess.Namespace, sess.PodName, sess.Path, sess.Size)
+	s.writeJSON(w, state)
+}
+
+// handleUploadEvents streams an upload's progress over SSE until it
+// completes or is cancelled
+func (s *Server) handleUploadEvents(w http.ResponseWriter, r *http.Request) {
+	sess := uploadManager.get(chi.URLParam(r, "id"))
+	if sess == nil {
+		s.writeError(w, http.StatusNotFound, "upload not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	updates, unsubscribe := uploadManager.subscribe(sess)
+	defer unsubscribe()
+
+	state := uploadManager.snapshot(sess)
+	sendSSEEvent(w, flusher, "progress", state)
+	if state.Done {
+		return
+	}
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case state, ok := <-updates:
+			if !ok {
+				sendSSEEvent(w, flusher, "cancelled", map[string]string{"id": sess.ID})
+				return
+			}
+			sendSSEEvent(w, flusher, "progress", state)
+			if state.Done {
+				return
+			}
+		}
+	}
+}
+
+// handleCancelUpload ends a session and removes its partial file
+func (s *Server) handleCancelUpload(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	sess := uploadManager.get(chi.URLParam(r, "id"))
+	if sess == nil {
+		s.writeError(w, http.StatusNotFound, "upload not found")
+		return
+	}
+	sess.writeMu.Lock()
+	defer sess.writeMu.Unlock()
+
+	if !uploadManager.snapshot(sess).Done {
+		stderr := &bytes.Buffer{}
+		err := s.execInPod(r.Context(), sess.Namespace, sess.PodName, sess.Container, []string{"rm", "-f", "--", sess.Path + uploadPartialSuffix}, nil, io.Discard, stderr)
+		if err != nil {
+			log.Printf("[upload] Failed to remove partial file of %s: %v %s", sess.ID, err, strings.TrimSpace(stderr.String()))
+		}
+	}
+	uploadManager.remove(sess.ID)
+	s.writeJSON(w, map[string]bool{"ok": true})
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/agent"
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// fleetSnapshots returns the connected local cluster plus every cluster
+// reporting through an agent
+func (s *Server) fleetSnapshots(ctx context.Context) []agent.ClusterSnapshot {
+	var snapshots []agent.ClusterSnapshot
+	if k8s.IsConnected() {
+		snapshots = append(snapshots, s.localFleetSnapshot(ctx))
+	}
+	if s.agents != nil {
+		snapshots = append(snapshots, s.agents.Snapshots()...)
+	}
+	return snapshots
+}
+
+func (s *Server) localFleetSnapshot(ctx context.Context) agent.ClusterSnapshot {
+	now := time.Now()
+	snap := agent.ClusterSnapshot{
+		Name:      k8s.GetContextName(),
+		Local:     true,
+		Connected: true,
+		LastSeen:  now,
+		Summary:   agent.CollectClusterSummary(ctx),
+		Topology:  s.broadcaster.GetCachedTopology(),
+	}
+	if store := timeline.GetStore(); store != nil {
+		events, err := store.Query(ctx, timeline.QueryOptions{
+			Since:            now.Add(-time.Hour),
+			Severities:       []timeline.Severity{timeline.SeverityWarning, timeline.SeverityCritical},
+			IncludeManaged:   true,
+			IncludeK8sEvents: true,
+			Limit:            1000,
+		})
+		if err != nil {
+			log.Printf("[fleet] Failed to query local timeline: %v", err)
+		}
+		snap.Events = events
+	}
+	return snap
+}
+
+// handleFleetDashboard aggregates health, versions and problems across the
+// local cluster and all agent-reported clusters
+func (s *Server) handleFleetDashboard(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, agent.BuildFleetDashboard(s.fleetSnapshots(r.Context()), time.Now()))
+}
+
+// handleFleetCluster is the per-cluster drill-down of the fleet dashboard
+func (s *Server) handleFleetCluster(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "cluster")
+	detail, ok := agent.BuildFleetClusterDetail(s.fleetSnapshots(r.Context()), name, time.Now())
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "cluster "+name+" is not part of the fleet")
+		return
+	}
+	s.writeJSON(w, detail)
+}
+
+// handleFleetSearch searches resources by name and label values across all
+// clusters. Query params: q (required), kinds (comma-separated), limit.
+func (s *Server) handleFleetSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		s.writeError(w, http.StatusBadRequest, "q query parameter is required")
+		return
+	}
+	limit := agentLimit(r)
+	if limit <= 0 {
+		limit = 50
+	}
+	limit = min(limit, 500)
+
+	var kinds []string
+	for _, part := range strings.Split(r.URL.Query().Get("kinds"), ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			kinds = append(kinds, trimmed)
+		}
+	}
+	s.writeJSON(w, agent.SearchFleet(s.fleetSnapshots(r.Context()), query, kinds, limit))
+}
@@ -10,8 +10,8 @@ type GitOpsResourceRef struct {
 // GitOpsOperationResponse is the standardized response format for all GitOps operations
 type GitOpsOperationResponse struct {
 	Message     string            `json:"message"`
-	Operation   string            `json:"operation"`             // "sync", "refresh", "terminate", "suspend", "resume", "reconcile"
-	Tool        string            `json:"tool"`                  // "argocd" or "fluxcd"
+	Operation   string            `json:"operation"`             // "sync", "refresh", "terminate", "suspend", "resume", "reconcile", "retry"
+	Tool        string            `json:"tool"`                  // "argocd", "fluxcd", or "argo-workflows"
 	Resource    GitOpsResourceRef `json:"resource"`
 	RequestedAt string            `json:"requestedAt,omitempty"`
 	Source      *GitOpsResourceRef `json:"source,omitempty"`     // For sync-with-source operations
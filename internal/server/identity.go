@@ -0,0 +1,32 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// identify attaches the user an auth proxy authenticated (--user-header) to
+// the request context, so API server calls made with it are attributed to
+// that user (see k8s.IdentityMode). The header is trusted as-is: Radar must
+// only be reachable through the proxy when it's set.
+func (s *Server) identify(next http.Handler) http.Handler {
+	if s.userHeader == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user := r.Header.Get(s.userHeader); user != "" {
+			r = r.WithContext(k8s.WithUser(r.Context(), user))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestActor names who made a request in log lines: the authenticated
+// user when there is one, else the client address
+func requestActor(r *http.Request) string {
+	if user := k8s.UserFromContext(r.Context()); user != "" {
+		return user
+	}
+	return clientID(r)
+}
@@ -0,0 +1,321 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+const (
+	javaDetectTimeout     = 10 * time.Second
+	javaThreadDumpTimeout = 30 * time.Second
+	javaHeapDumpTimeout   = 5 * time.Minute // Large heaps take a while to write
+)
+
+// javaImageHints are image name fragments of JDK/JRE base images and common
+// JVM servers
+var javaImageHints = []string{"openjdk", "jdk", "jre", "temurin", "corretto", "zulu", "liberica", "semeru", "graalvm", "java", "tomcat", "jetty", "wildfly", "payara"}
+
+// javaEnvHints are env vars set by Java base images
+var javaEnvHints = []string{"JAVA_HOME", "JAVA_VERSION", "JAVA_OPTS", "JAVA_TOOL_OPTIONS", "JDK_JAVA_OPTIONS"}
+
+// JDK tools are looked up on PATH and in $JAVA_HOME/bin, which base images
+// don't always put on PATH. Scripts run via `sh -c <script> sh <args...>` so
+// request values are only passed as positional parameters.
+const (
+	javaPathPrefix = `PATH="$PATH:${JAVA_HOME:-/nonexistent}/bin"; `
+
+	// Prints "pid command line" for each JVM, then "tools: ..." for the JDK tools found
+	javaDetectScript = javaPathPrefix + `for d in /proc/[0-9]*; do
+  c=$(tr '\0' ' ' < "$d/cmdline" 2>/dev/null) || continue
+  case "$c" in java\ *|*/java\ *) echo "${d#/proc/} $c";; esac
+done
+t=""; for x in jcmd jstack jmap; do command -v "$x" >/dev/null 2>&1 && t="$t $x"; done
+echo "tools:$t"`
+
+	// $1 pid, $2 output file. kill -3 makes the JVM print to its own stdout;
+	// SIGQUIT kills anything else, so the pid's command line is checked first.
+	javaThreadDumpScript = javaPathPrefix + `if command -v jcmd >/dev/null 2>&1 && jcmd "$1" Thread.print -l > "$2" 2>&1; then echo "tool: jcmd"
+elif command -v jstack >/dev/null 2>&1 && jstack -l "$1" > "$2" 2>&1; then echo "tool: jstack"
+else rm -f "$2"
+  case "$(tr '\0' ' ' < "/proc/$1/cmdline" 2>/dev/null)" in java\ *|*/java\ *) ;; *) echo "pid $1 is not a JVM" >&2; exit 1;; esac
+  kill -3 "$1" || exit 1; echo "tool: kill -3"; exit 0; fi
+echo "size: $(wc -c < "$2")"`
+
+	// $1 pid, $2 output file, $3 "-all" to include unreachable objects
+	javaHeapDumpScript = javaPathPrefix + `if command -v jcmd >/dev/null 2>&1; then echo "tool: jcmd"; jcmd "$1" GC.heap_dump $3 "$2" >&2 || exit 1
+elif command -v jmap >/dev/null 2>&1; then echo "tool: jmap"; live=",live"; [ -n "$3" ] && live=""; jmap -dump:format=b$live,file="$2" "$1" >&2 || exit 1
+else echo "no jcmd or jmap in the container (a JRE-only image can't take heap dumps)" >&2; exit 127; fi
+[ -s "$2" ] || { echo "heap dump was not written" >&2; exit 1; }
+echo "size: $(wc -c < "$2")"`
+)
+
+// JavaProcess is a JVM running in a container
+type JavaProcess struct {
+	PID     int    `json:"pid"`
+	Command string `json:"command"`
+}
+
+// JavaContainer is the Java detection result of one container
+type JavaContainer struct {
+	Name      string        `json:"name"`
+	Image     string        `json:"image"`
+	ImageHint bool          `json:"imageHint"` // Image name or env look like a Java image
+	Java      bool          `json:"java"`      // A JVM process is running (or the image hints, if processes couldn't be listed)
+	Processes []JavaProcess `json:"processes,omitempty"`
+	Tools     []string      `json:"tools,omitempty"` // jcmd, jstack, jmap available in the container
+	Error     string        `json:"error,omitempty"` // Why processes couldn't be listed
+}
+
+// JavaDumpRequest is the request body for thread and heap dumps
+type JavaDumpRequest struct {
+	Container string `json:"container,omitempty"`
+	PID       int    `json:"pid,omitempty"` // Default: the only JVM in the container
+	All       bool   `json:"all,omitempty"` // Heap dumps: include unreachable objects (no full GC first)
+}
+
+// JavaDumpResult is where a dump was written; download it with DownloadURL
+type JavaDumpResult struct {
+	Container   string `json:"container"`
+	PID         int    `json:"pid"`
+	Tool        string `json:"tool"`
+	Path        string `json:"path,omitempty"`
+	SizeBytes   int64  `json:"sizeBytes,omitempty"`
+	DownloadURL string `json:"downloadUrl,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+// javaImageHint reports whether a container looks like it runs Java from its
+// image name or env
+func javaImageHint(c corev1.Container) bool {
+	image := strings.ToLower(c.Image)
+	if i := strings.LastIndexByte(image, '@'); i >= 0 {
+		image = image[:i]
+	}
+	for _, hint := range javaImageHints {
+		if strings.Contains(image, hint) {
+			return true
+		}
+	}
+	for _, env := range c.Env {
+		for _, hint := range javaEnvHints {
+			if env.Name == hint {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseJavaDetect parses the output of javaDetectScript
+func parseJavaDetect(output string) ([]JavaProcess, []string) {
+	var processes []JavaProcess
+	var tools []string
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if rest, ok := strings.CutPrefix(line, "tools:"); ok {
+			tools = strings.Fields(rest)
+			continue
+		}
+		pidStr, command, _ := strings.Cut(line, " ")
+		if pid, err := strconv.Atoi(pidStr); err == nil {
+			processes = append(processes, JavaProcess{PID: pid, Command: strings.TrimSpace(command)})
+		}
+	}
+	return processes, tools
+}
+
+// handleJavaDetect finds JVMs in a pod's running containers
+// GET /api/pods/{namespace}/{name}/java
+func (s *Server) handleJavaDetect(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	namespace := chi.URLParam(r, "namespace")
+	podName := chi.URLParam(r, "name")
+	only := r.URL.Query().Get("container")
+
+	cache := k8s.GetResourceCache()
+	if cache == nil || cache.Pods() == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Resource cache not available")
+		return
+	}
+	pod, err := cache.Pods().Pods(namespace).Get(podName)
+	if err != nil {
+		s.writeError(w, http.StatusNotFound, fmt.Sprintf("Pod not found: %v", err))
+		return
+	}
+	running := make(map[string]bool)
+	for _, cs := range pod.Status.ContainerStatuses {
+		running[cs.Name] = cs.State.Running != nil
+	}
+
+	results := make([]JavaContainer, 0, len(pod.Spec.Containers))
+	for _, c := range pod.Spec.Containers {
+		if only != "" && c.Name != only {
+			continue
+		}
+		result := JavaContainer{Name: c.Name, Image: c.Image, ImageHint: javaImageHint(c)}
+		if !running[c.Name] {
+			result.Java, result.Error = result.ImageHint, "container is not running"
+			results = append(results, result)
+			continue
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), javaDetectTimeout)
+		var stdout, stderr bytes.Buffer
+		err := s.execInPod(ctx, namespace, podName, c.Name, []string{"sh", "-c", javaDetectScript}, nil, &stdout, &stderr)
+		cancel()
+		if err != nil {
+			result.Java = result.ImageHint
+			if isShellNotFoundError(err.Error()) {
+				result.Error = "container has no shell to list processes"
+			} else {
+				result.Error = err.Error()
+			}
+		} else {
+			result.Processes, result.Tools = parseJavaDetect(stdout.String())
+			result.Java = len(result.Processes) > 0
+		}
+		results = append(results, result)
+	}
+	if only != "" && len(results) == 0 {
+		s.writeError(w, http.StatusNotFound, fmt.Sprintf("container %q not found in pod", only))
+		return
+	}
+	s.writeJSON(w, map[string]any{"containers": results})
+}
+
+// resolveJavaTarget fills in the container and JVM pid of a dump request. A
+// given pid must be one of the detected JVMs: the thread dump fallback sends
+// SIGQUIT, which kills any other process.
+func (s *Server) resolveJavaTarget(ctx context.Context, namespace, podName string, req *JavaDumpRequest) error {
+	if req.Container == "" {
+		cache := k8s.GetResourceCache()
+		if cache == nil || cache.Pods() == nil {
+			return fmt.Errorf("resource cache not available")
+		}
+		pod, err := cache.Pods().Pods(namespace).Get(podName)
+		if err != nil {
+			return fmt.Errorf("pod not found: %w", err)
+		}
+		req.Container = pod.Spec.Containers[0].Name
+	}
+	ctx, cancel := context.WithTimeout(ctx, javaDetectTimeout)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	if err := s.execInPod(ctx, namespace, podName, req.Container, []string{"sh", "-c", javaDetectScript}, nil, &stdout, &stderr); err != nil {
+		return fmt.Errorf("failed to list processes: %w", err)
+	}
+	processes, _ := parseJavaDetect(stdout.String())
+	if req.PID > 0 {
+		for _, p := range processes {
+			if p.PID == req.PID {
+				return nil
+			}
+		}
+		return fmt.Errorf("pid %d is not a Java process in container %s", req.PID, req.Container)
+	}
+	switch len(processes) {
+	case 0:
+		return fmt.Errorf("no Java process found in container %s", req.Container)
+	case 1:
+		req.PID = processes[0].PID
+		return nil
+	}
+	return fmt.Errorf("%d Java processes in container %s: pick one with pid", len(processes), req.Container)
+}
+
+// javaDump resolves the target and runs a dump script, writing the result
+func (s *Server) javaDump(w http.ResponseWriter, r *http.Request, kind, script, ext string, timeout time.Duration) {
+	if !s.requireConnected(w) {
+		return
+	}
+	namespace := chi.URLParam(r, "namespace")
+	podName := chi.URLParam(r, "name")
+
+	var req JavaDumpRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+	if err := s.resolveJavaTarget(r.Context(), namespace, podName, &req); err != nil {
+		switch msg := err.Error(); {
+		case strings.Contains(msg, "not found") || strings.Contains(msg, "no Java process"):
+			s.writeError(w, http.StatusNotFound, msg)
+		case strings.Contains(msg, "pick one") || strings.Contains(msg, "is not a Java process"):
+			s.writeError(w, http.StatusBadRequest, msg)
+		default:
+			s.writeError(w, http.StatusUnprocessableEntity, msg)
+		}
+		return
+	}
+
+	file := fmt.Sprintf("/tmp/radar-%s-%d-%s.%s", kind, req.PID, time.Now().UTC().Format("20060102-150405"), ext)
+	all := ""
+	if req.All {
+		all = "-all"
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	command := []string{"sh", "-c", script, "sh", strconv.Itoa(req.PID), file, all}
+	if err := s.execInPod(ctx, namespace, podName, req.Container, command, nil, &stdout, &stderr); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		log.Printf("[java] %s of %s/%s pid %d failed: %s", kind, namespace, podName, req.PID, msg)
+		s.writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%s failed: %s", kind, msg))
+		return
+	}
+
+	output := stdout.String()
+	result := JavaDumpResult{Container: req.Container, PID: req.PID}
+	result.Tool, output = parseNetTestTool(output)
+	if result.Tool == "kill -3" {
+		result.Message = "No jcmd or jstack in the container: the JVM printed the thread dump to its stdout (see the container logs)"
+		s.writeJSON(w, result)
+		return
+	}
+	result.Path = file
+	if size, ok := strings.CutPrefix(strings.TrimSpace(output), "size: "); ok {
+		result.SizeBytes, _ = strconv.ParseInt(strings.TrimSpace(size), 10, 64)
+	}
+	query := url.Values{"container": {req.Container}, "path": {file}}
+	result.DownloadURL = path.Join("/api/pods", url.PathEscape(namespace), url.PathEscape(podName), "filesystem/archive") + "?" + query.Encode()
+	result.Message = fmt.Sprintf("Written to %s in the container; delete it when done to free the disk space", file)
+	log.Printf("[java] %s of %s/%s pid %d written to %s", kind, namespace, podName, req.PID, file)
+	s.writeJSON(w, result)
+}
+
+// handleJavaThreadDump captures a JVM thread dump into the pod filesystem
+// (jcmd, jstack, or kill -3 to the container's stdout as a fallback)
+// POST /api/pods/{namespace}/{name}/java/thread-dump
+func (s *Server) handleJavaThreadDump(w http.ResponseWriter, r *http.Request) {
+	s.javaDump(w, r, "threaddump", javaThreadDumpScript, "txt", javaThreadDumpTimeout)
+}
+
+// handleJavaHeapDump writes a JVM heap dump (hprof) into the pod filesystem
+// with jcmd or jmap
+// POST /api/pods/{namespace}/{name}/java/heap-dump
+func (s *Server) handleJavaHeapDump(w http.ResponseWriter, r *http.Request) {
+	s.javaDump(w, r, "heapdump", javaHeapDumpScript, "hprof", javaHeapDumpTimeout)
+}
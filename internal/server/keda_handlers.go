@@ -0,0 +1,34 @@
+package server
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// KEDAScalersResponse lists KEDA ScaledObjects and ScaledJobs
+type KEDAScalersResponse struct {
+	Installed bool             `json:"installed"` // KEDA CRDs present in the cluster
+	Scalers   []k8s.KEDAScaler `json:"scalers"`
+}
+
+// handleKEDAScalers returns ScaledObjects/ScaledJobs with triggers and scale relative to min/max
+// GET /api/keda/scalers?namespaces=a,b
+func (s *Server) handleKEDAScalers(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	scalers, installed, err := k8s.ListKEDAScalers(parseNamespaces(r.URL.Query()))
+	if err != nil {
+		log.Printf("[keda] Failed to list scalers: %v", err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if scalers == nil {
+		scalers = []k8s.KEDAScaler{}
+	}
+
+	s.writeJSON(w, KEDAScalersResponse{Installed: installed, Scalers: scalers})
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// handlePodLifecycle returns a pod's scheduled -> pulled -> started -> ready timing breakdown
+// GET /api/pods/{namespace}/{name}/lifecycle
+func (s *Server) handlePodLifecycle(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+	timing, ok := k8s.GetPodLifecycle(namespace, name)
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "pod not found")
+		return
+	}
+	s.writeJSON(w, timing)
+}
+
+// handleWorkloadStartup returns p50/p95 startup phases per workload from timeline history
+// GET /api/lifecycle/startup?namespaces=a,b&since=RFC3339 (default: last 24h)
+func (s *Server) handleWorkloadStartup(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	since := time.Now().Add(-24 * time.Hour)
+	if raw := r.URL.Query().Get("since"); raw != "" {
+		ts, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid since: must be an RFC3339 timestamp")
+			return
+		}
+		since = ts
+	}
+
+	stats, err := k8s.GetWorkloadStartupStats(r.Context(), parseNamespaces(r.URL.Query()), since)
+	if err != nil {
+		log.Printf("[lifecycle] Failed to aggregate startup times: %v", err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.writeJSON(w, stats)
+}
@@ -0,0 +1,290 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/localcluster"
+)
+
+const (
+	localClusterListTimeout = 20 * time.Second
+	localClusterOpTimeout   = 10 * time.Minute // Creating a cluster pulls node images
+)
+
+// LocalClusterOperation is a create/start/stop/delete running in the
+// background (they take from seconds to minutes). Failed operations stay
+// listed until the next action on the cluster; successful ones are dropped.
+type LocalClusterOperation struct {
+	Action     localcluster.Action `json:"action"`
+	StartedAt  time.Time           `json:"startedAt"`
+	FinishedAt *time.Time          `json:"finishedAt,omitempty"`
+	Error      string              `json:"error,omitempty"`
+}
+
+// localClusterOps tracks operations, keyed by tool/name
+var localClusterOps = struct {
+	mu  sync.Mutex
+	ops map[string]*LocalClusterOperation
+}{ops: make(map[string]*LocalClusterOperation)}
+
+// LocalCluster is a local cluster with any operation in flight
+type LocalCluster struct {
+	localcluster.Cluster
+	IsCurrent bool                   `json:"isCurrent"`
+	Operation *LocalClusterOperation `json:"operation,omitempty"`
+}
+
+// LocalClustersResponse lists the installed tools and their clusters
+type LocalClustersResponse struct {
+	Tools    []localcluster.ToolStatus    `json:"tools"`
+	Clusters []LocalCluster               `json:"clusters"`
+	Errors   map[localcluster.Tool]string `json:"errors,omitempty"` // Tools whose listing failed
+}
+
+// CreateLocalClusterRequest is the body of POST /api/local-clusters
+type CreateLocalClusterRequest struct {
+	Tool localcluster.Tool `json:"tool"`
+	Name string            `json:"name"`
+}
+
+func localClusterKey(tool localcluster.Tool, name string) string {
+	return string(tool) + "/" + name
+}
+
+// requireWorkstation rejects local cluster management where it makes no sense:
+// in-cluster deployments and the demo cluster
+func (s *Server) requireWorkstation(w http.ResponseWriter) bool {
+	if k8s.IsInCluster() || k8s.IsDemoMode() {
+		s.writeError(w, http.StatusBadRequest, "local clusters can only be managed when Radar runs on your machine")
+		return false
+	}
+	return true
+}
+
+// handleListLocalClusters detects kind/minikube/k3d and lists their clusters.
+// Works while disconnected, so a local cluster can be started to connect to.
+// GET /api/local-clusters
+func (s *Server) handleListLocalClusters(w http.ResponseWriter, r *http.Request) {
+	if !s.requireWorkstation(w) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), localClusterListTimeout)
+	defer cancel()
+
+	resp := LocalClustersResponse{Tools: localcluster.Detect(), Clusters: []LocalCluster{}}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, t := range resp.Tools {
+		if !t.Available {
+			continue
+		}
+		wg.Add(1)
+		go func(tool localcluster.Tool) {
+			defer wg.Done()
+			clusters, err := localcluster.List(ctx, tool)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if resp.Errors == nil {
+					resp.Errors = map[localcluster.Tool]string{}
+				}
+				resp.Errors[tool] = err.Error()
+				return
+			}
+			for _, c := range clusters {
+				resp.Clusters = append(resp.Clusters, LocalCluster{Cluster: c})
+			}
+		}(t.Tool)
+	}
+	wg.Wait()
+
+	current := k8s.GetContextName()
+	listed := make(map[string]bool, len(resp.Clusters))
+	localClusterOps.mu.Lock()
+	for i := range resp.Clusters {
+		c := &resp.Clusters[i]
+		c.IsCurrent = c.Context == current
+		key := localClusterKey(c.Tool, c.Name)
+		listed[key] = true
+		if op, ok := localClusterOps.ops[key]; ok {
+			opCopy := *op
+			c.Operation = &opCopy
+		}
+	}
+	// Clusters being created (or that failed to) aren't listed by the tool yet
+	for key, op := range localClusterOps.ops {
+		if listed[key] || op.Action != localcluster.ActionCreate {
+			continue
+		}
+		tool, name, _ := strings.Cut(key, "/")
+		opCopy := *op
+		resp.Clusters = append(resp.Clusters, LocalCluster{
+			Cluster: localcluster.Cluster{
+				Tool:    localcluster.Tool(tool),
+				Name:    name,
+				Context: localcluster.ContextName(localcluster.Tool(tool), name),
+				Status:  localcluster.StatusUnknown,
+			},
+			Operation: &opCopy,
+		})
+	}
+	localClusterOps.mu.Unlock()
+
+	sort.Slice(resp.Clusters, func(i, j int) bool {
+		if resp.Clusters[i].Tool != resp.Clusters[j].Tool {
+			return resp.Clusters[i].Tool < resp.Clusters[j].Tool
+		}
+		return resp.Clusters[i].Name < resp.Clusters[j].Name
+	})
+	s.writeJSON(w, resp)
+}
+
+// handleCreateLocalCluster creates a cluster in the background
+// POST /api/local-clusters
+func (s *Server) handleCreateLocalCluster(w http.ResponseWriter, r *http.Request) {
+	if !s.requireWorkstation(w) {
+		return
+	}
+	var req CreateLocalClusterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	s.startLocalClusterOp(w, string(req.Tool), req.Name, localcluster.ActionCreate)
+}
+
+// handleLocalClusterAction starts or stops a cluster in the background
+// POST /api/local-clusters/{tool}/{name}/{action}
+func (s *Server) handleLocalClusterAction(w http.ResponseWriter, r *http.Request) {
+	if !s.requireWorkstation(w) {
+		return
+	}
+	action := localcluster.Action(chi.URLParam(r, "action"))
+	if action != localcluster.ActionStart && action != localcluster.ActionStop {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown action %q (expected start or stop)", action))
+		return
+	}
+	s.startLocalClusterOp(w, chi.URLParam(r, "tool"), chi.URLParam(r, "name"), action)
+}
+
+// handleDeleteLocalCluster deletes a cluster in the background
+// DELETE /api/local-clusters/{tool}/{name}
+func (s *Server) handleDeleteLocalCluster(w http.ResponseWriter, r *http.Request) {
+	if !s.requireWorkstation(w) {
+		return
+	}
+	s.startLocalClusterOp(w, chi.URLParam(r, "tool"), chi.URLParam(r, "name"), localcluster.ActionDelete)
+}
+
+// startLocalClusterOp validates an operation, starts it and answers 202 with
+// its state; poll GET /api/local-clusters for completion
+func (s *Server) startLocalClusterOp(w http.ResponseWriter, toolName, name string, action localcluster.Action) {
+	tool, ok := localcluster.ParseTool(toolName)
+	if !ok {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported tool %q (expected kind, minikube or k3d)", toolName))
+		return
+	}
+	if !localcluster.ValidName(name) {
+		s.writeError(w, http.StatusBadRequest, "cluster name must be 1-32 lowercase letters, digits or '-', starting and ending with a letter or digit")
+		return
+	}
+	contextName := localcluster.ContextName(tool, name)
+	if action == localcluster.ActionDelete && contextName == k8s.GetContextName() {
+		s.writeError(w, http.StatusConflict, "switch to another context before deleting this cluster")
+		return
+	}
+
+	key := localClusterKey(tool, name)
+	localClusterOps.mu.Lock()
+	if op, ok := localClusterOps.ops[key]; ok && op.FinishedAt == nil {
+		localClusterOps.mu.Unlock()
+		s.writeError(w, http.StatusConflict, fmt.Sprintf("%s %s is already running for this cluster", tool, op.Action))
+		return
+	}
+	op := &LocalClusterOperation{Action: action, StartedAt: time.Now()}
+	localClusterOps.ops[key] = op
+	opCopy := *op
+	localClusterOps.mu.Unlock()
+
+	// The operation outlives the request, so it doesn't use the request context
+	go runLocalClusterOp(tool, name, op)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	s.writeJSON(w, LocalCluster{
+		Cluster:   localcluster.Cluster{Tool: tool, Name: name, Context: contextName, Status: localcluster.StatusUnknown},
+		Operation: &opCopy,
+	})
+}
+
+func runLocalClusterOp(tool localcluster.Tool, name string, op *LocalClusterOperation) {
+	ctx, cancel := context.WithTimeout(context.Background(), localClusterOpTimeout)
+	defer cancel()
+
+	contextName := localcluster.ContextName(tool, name)
+	log.Printf("[local-clusters] %s %s cluster %s", op.Action, tool, name)
+	// Tools write the context into the kubeconfig Radar reads (empty with --kubeconfig-dir)
+	err := localcluster.Run(ctx, tool, op.Action, name, k8s.GetKubeconfigPath())
+	if err == nil {
+		switch op.Action {
+		case localcluster.ActionCreate, localcluster.ActionStart:
+			if regErr := ensureLocalClusterContext(ctx, tool, name, contextName); regErr != nil {
+				log.Printf("[local-clusters] Context %s not available: %v", contextName, regErr)
+			}
+		case localcluster.ActionDelete:
+			k8s.UnregisterGeneratedContext(contextName)
+		}
+	}
+
+	key := localClusterKey(tool, name)
+	localClusterOps.mu.Lock()
+	defer localClusterOps.mu.Unlock()
+	if err != nil {
+		log.Printf("[local-clusters] %s %s cluster %s failed: %v", op.Action, tool, name, err)
+		now := time.Now()
+		op.FinishedAt = &now
+		op.Error = err.Error()
+		return
+	}
+	log.Printf("[local-clusters] %s %s cluster %s done", op.Action, tool, name)
+	if localClusterOps.ops[key] == op {
+		delete(localClusterOps.ops, key)
+	}
+}
+
+// ensureLocalClusterContext makes a cluster's context switchable when the tool
+// wrote it to a kubeconfig Radar doesn't read (e.g. with --kubeconfig-dir), by
+// registering the kubeconfig the tool prints
+func ensureLocalClusterContext(ctx context.Context, tool localcluster.Tool, name, contextName string) error {
+	contexts, err := k8s.GetAvailableContexts()
+	if err != nil {
+		return err
+	}
+	for _, c := range contexts {
+		if c.Name == contextName {
+			return nil
+		}
+	}
+	data, err := localcluster.Kubeconfig(ctx, tool, name)
+	if errors.Is(err, localcluster.ErrNoKubeconfig) {
+		return fmt.Errorf("%s wrote it to its default kubeconfig, which Radar isn't reading", tool)
+	}
+	if err != nil {
+		return err
+	}
+	return k8s.RegisterKubeconfigContext(contextName, data)
+}
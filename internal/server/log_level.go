@@ -0,0 +1,475 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// Pods opt in to runtime log level changes with annotations (usually set on
+// the workload's pod template):
+//
+//	radar.skyhook.io/log-level: spring | zap
+//	radar.skyhook.io/log-level-port: "8081"           (default 8080)
+//	radar.skyhook.io/log-level-path: /manage/loggers  (default /actuator/loggers or /log/level)
+const (
+	logLevelAnnotation     = "radar.skyhook.io/log-level"
+	logLevelPortAnnotation = "radar.skyhook.io/log-level-port"
+	logLevelPathAnnotation = "radar.skyhook.io/log-level-path"
+
+	logLevelDefaultPort = 8080
+	logLevelTimeout     = 10 * time.Second
+	logLevelMaxRevert   = 24 * 60 // Minutes
+)
+
+// Supported log level handlers
+const (
+	logLevelSpring = "spring" // Spring Boot actuator loggers endpoint
+	logLevelZap    = "zap"    // zap.AtomicLevel's HTTP handler
+)
+
+var logLevelDefaultPaths = map[string]string{
+	logLevelSpring: "/actuator/loggers",
+	logLevelZap:    "/log/level",
+}
+
+var logLevelValues = map[string][]string{
+	logLevelSpring: {"TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL", "OFF"},
+	logLevelZap:    {"debug", "info", "warn", "error", "dpanic", "panic", "fatal"},
+}
+
+// logLevelTarget is a pod's log level endpoint from its annotations
+type logLevelTarget struct {
+	Type string `json:"type"`
+	Port int    `json:"port"`
+	Path string `json:"path"`
+}
+
+func logLevelTargetFor(pod *corev1.Pod) (logLevelTarget, error) {
+	t := logLevelTarget{Type: strings.ToLower(pod.Annotations[logLevelAnnotation]), Port: logLevelDefaultPort}
+	if _, ok := logLevelDefaultPaths[t.Type]; !ok {
+		if t.Type == "" {
+			return t, fmt.Errorf("pod has no %s annotation (spring or zap)", logLevelAnnotation)
+		}
+		return t, fmt.Errorf("unsupported %s %q (spring or zap)", logLevelAnnotation, t.Type)
+	}
+	if v := pod.Annotations[logLevelPortAnnotation]; v != "" {
+		port, err := strconv.Atoi(v)
+		if err != nil || port < 1 || port > 65535 {
+			return t, fmt.Errorf("invalid %s %q", logLevelPortAnnotation, v)
+		}
+		t.Port = port
+	}
+	t.Path = pod.Annotations[logLevelPathAnnotation]
+	if t.Path == "" {
+		t.Path = logLevelDefaultPaths[t.Type]
+	}
+	t.Path = "/" + strings.Trim(t.Path, "/")
+	return t, nil
+}
+
+// LoggerLevel is one Spring logger's level
+type LoggerLevel struct {
+	Name            string `json:"name"`
+	ConfiguredLevel string `json:"configuredLevel,omitempty"`
+	EffectiveLevel  string `json:"effectiveLevel"`
+}
+
+// PodLogLevel is a pod's current log levels
+type PodLogLevel struct {
+	Pod string `json:"pod"`
+	logLevelTarget
+	Level    string        `json:"level,omitempty"`   // zap level, or Spring's ROOT effective level
+	Loggers  []LoggerLevel `json:"loggers,omitempty"` // Spring: configured loggers (or those matching ?logger=)
+	Levels   []string      `json:"levels,omitempty"`  // Levels that can be set
+	RevertAt *time.Time    `json:"revertAt,omitempty"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// LogLevelRequest changes a log level. Logger is the Spring logger name
+// (default ROOT); an empty Level resets a Spring logger to its configured
+// default. With RevertAfterMinutes the previous level is restored later.
+type LogLevelRequest struct {
+	Logger             string `json:"logger,omitempty"`
+	Level              string `json:"level"`
+	RevertAfterMinutes int    `json:"revertAfterMinutes,omitempty"`
+}
+
+// logLevelReverts are pending restores of previous levels, by namespace/pod/logger
+var logLevelReverts = struct {
+	mu     sync.Mutex
+	timers map[string]*logLevelRevert
+}{timers: make(map[string]*logLevelRevert)}
+
+type logLevelRevert struct {
+	timer    *time.Timer
+	previous string
+	at       time.Time
+}
+
+// logLevelRequest sends a request to a pod's log level endpoint through a port forward
+func logLevelRequest(ctx context.Context, pod *corev1.Pod, t logLevelTarget, method, suffix string, body any, out any) error {
+	base, err := podForwardURL(ctx, pod.Namespace, pod.Name, t.Port)
+	if err != nil {
+		return err
+	}
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	ctx, cancel := context.WithTimeout(ctx, logLevelTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, base.String()+t.Path+suffix, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, t.Path+suffix, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s returned %d: %s", method, t.Path+suffix, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("unexpected response from %s: %w", t.Path+suffix, err)
+		}
+	}
+	return nil
+}
+
+type springLoggers struct {
+	Levels  []string `json:"levels"`
+	Loggers map[string]struct {
+		ConfiguredLevel *string `json:"configuredLevel"`
+		EffectiveLevel  string  `json:"effectiveLevel"`
+	} `json:"loggers"`
+}
+
+type zapLevel struct {
+	Level string `json:"level"`
+}
+
+// getPodLogLevel reads a pod's levels. For Spring, loggers with a configured
+// level are returned, or all loggers starting with prefix.
+func getPodLogLevel(ctx context.Context, pod *corev1.Pod, prefix string) PodLogLevel {
+	result := PodLogLevel{Pod: pod.Name}
+	t, err := logLevelTargetFor(pod)
+	result.logLevelTarget = t
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Levels = logLevelValues[t.Type]
+	if pending := pendingRevert(pod, prefix); pending != nil {
+		result.RevertAt = &pending.at
+	}
+
+	if t.Type == logLevelZap {
+		var level zapLevel
+		if err := logLevelRequest(ctx, pod, t, http.MethodGet, "", nil, &level); err != nil {
+			result.Error = err.Error()
+		}
+		result.Level = level.Level
+		return result
+	}
+
+	var loggers springLoggers
+	if err := logLevelRequest(ctx, pod, t, http.MethodGet, "", nil, &loggers); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if len(loggers.Levels) > 0 {
+		result.Levels = loggers.Levels
+	}
+	for name, l := range loggers.Loggers {
+		if name == "ROOT" {
+			result.Level = l.EffectiveLevel
+		}
+		match := l.ConfiguredLevel != nil || name == "ROOT"
+		if prefix != "" {
+			match = strings.HasPrefix(name, prefix)
+		}
+		if !match {
+			continue
+		}
+		entry := LoggerLevel{Name: name, EffectiveLevel: l.EffectiveLevel}
+		if l.ConfiguredLevel != nil {
+			entry.ConfiguredLevel = *l.ConfiguredLevel
+		}
+		result.Loggers = append(result.Loggers, entry)
+	}
+	sort.Slice(result.Loggers, func(i, j int) bool { return result.Loggers[i].Name < result.Loggers[j].Name })
+	return result
+}
+
+// setPodLogLevel changes a pod's level, scheduling a revert if requested
+func setPodLogLevel(ctx context.Context, pod *corev1.Pod, req LogLevelRequest) PodLogLevel {
+	t, err := logLevelTargetFor(pod)
+	if err != nil {
+		return PodLogLevel{Pod: pod.Name, logLevelTarget: t, Error: err.Error()}
+	}
+	if t.Type == logLevelZap {
+		req.Logger = ""
+	} else if req.Logger == "" {
+		req.Logger = "ROOT"
+	}
+
+	previous, err := currentLogLevel(ctx, pod, t, req.Logger)
+	if err != nil {
+		return PodLogLevel{Pod: pod.Name, logLevelTarget: t, Error: err.Error()}
+	}
+	if err := writeLogLevel(ctx, pod, t, req.Logger, req.Level); err != nil {
+		return PodLogLevel{Pod: pod.Name, logLevelTarget: t, Error: err.Error()}
+	}
+	log.Printf("[loglevel] %s/%s: %s %q -> %q", pod.Namespace, pod.Name, t.Type, previous, req.Level)
+	if req.RevertAfterMinutes > 0 {
+		scheduleLogLevelRevert(ctx, pod, t, req.Logger, previous, time.Duration(req.RevertAfterMinutes)*time.Minute)
+	} else {
+		cancelLogLevelRevert(pod, req.Logger)
+	}
+	return getPodLogLevel(ctx, pod, req.Logger)
+}
+
+func currentLogLevel(ctx context.Context, pod *corev1.Pod, t logLevelTarget, logger string) (string, error) {
+	if t.Type == logLevelZap {
+		var level zapLevel
+		err := logLevelRequest(ctx, pod, t, http.MethodGet, "", nil, &level)
+		return level.Level, err
+	}
+	var l struct {
+		ConfiguredLevel *string `json:"configuredLevel"`
+	}
+	if err := logLevelRequest(ctx, pod, t, http.MethodGet, "/"+url.PathEscape(logger), nil, &l); err != nil {
+		return "", err
+	}
+	if l.ConfiguredLevel == nil {
+		return "", nil
+	}
+	return *l.ConfiguredLevel, nil
+}
+
+func writeLogLevel(ctx context.Context, pod *corev1.Pod, t logLevelTarget, logger, level string) error {
+	if t.Type == logLevelZap {
+		return logLevelRequest(ctx, pod, t, http.MethodPut, "", zapLevel{Level: level}, nil)
+	}
+	var configured *string // null resets the logger
+	if level != "" {
+		configured = &level
+	}
+	return logLevelRequest(ctx, pod, t, http.MethodPost, "/"+url.PathEscape(logger), map[string]*string{"configuredLevel": configured}, nil)
+}
+
+func revertKey(pod *corev1.Pod, logger string) string {
+	return pod.Namespace + "/" + pod.Name + "/" + logger
+}
+
+func pendingRevert(pod *corev1.Pod, logger string) *logLevelRevert {
+	if logger == "" && pod.Annotations[logLevelAnnotation] == logLevelSpring {
+		logger = "ROOT"
+	}
+	logLevelReverts.mu.Lock()
+	defer logLevelReverts.mu.Unlock()
+	return logLevelReverts.timers[revertKey(pod, logger)]
+}
+
+// scheduleLogLevelRevert restores previous after d. Repeated changes keep the
+// level from before the first one. The revert is attributed to the user of ctx.
+func scheduleLogLevelRevert(ctx context.Context, pod *corev1.Pod, t logLevelTarget, logger, previous string, d time.Duration) {
+	ctx = context.WithoutCancel(ctx)
+	key := revertKey(pod, logger)
+	logLevelReverts.mu.Lock()
+	defer logLevelReverts.mu.Unlock()
+	if existing, ok := logLevelReverts.timers[key]; ok {
+		existing.timer.Stop()
+		previous = existing.previous
+	}
+	revert := &logLevelRevert{previous: previous, at: time.Now().Add(d)}
+	podCopy := pod.DeepCopy()
+	revert.timer = time.AfterFunc(d, func() {
+		logLevelReverts.mu.Lock()
+		if logLevelReverts.timers[key] == revert {
+			delete(logLevelReverts.timers, key)
+		}
+		logLevelReverts.mu.Unlock()
+		if err := writeLogLevel(ctx, podCopy, t, logger, previous); err != nil {
+			log.Printf("[loglevel] Failed to revert %s to %q: %v", key, previous, err)
+			return
+		}
+		log.Printf("[loglevel] Reverted %s to %q", key, previous)
+	})
+	logLevelReverts.timers[key] = revert
+}
+
+func cancelLogLevelRevert(pod *corev1.Pod, logger string) {
+	logLevelReverts.mu.Lock()
+	defer logLevelReverts.mu.Unlock()
+	if existing, ok := logLevelReverts.timers[revertKey(pod, logger)]; ok {
+		existing.timer.Stop()
+		delete(logLevelReverts.timers, revertKey(pod, logger))
+	}
+}
+
+func (s *Server) decodeLogLevelRequest(w http.ResponseWriter, r *http.Request) (LogLevelRequest, bool) {
+	var req LogLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return req, false
+	}
+	if req.RevertAfterMinutes < 0 || req.RevertAfterMinutes > logLevelMaxRevert {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("revertAfterMinutes must be between 0 and %d", logLevelMaxRevert))
+		return req, false
+	}
+	return req, true
+}
+
+func (s *Server) cachedPod(w http.ResponseWriter, namespace, name string) (*corev1.Pod, bool) {
+	cache := k8s.GetResourceCache()
+	if cache == nil || cache.Pods() == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Resource cache not available")
+		return nil, false
+	}
+	pod, err := cache.Pods().Pods(namespace).Get(name)
+	if err != nil {
+		s.writeError(w, http.StatusNotFound, fmt.Sprintf("Pod not found: %v", err))
+		return nil, false
+	}
+	return pod, true
+}
+
+// handleGetPodLogLevel returns a pod's runtime log levels
+// GET /api/pods/{namespace}/{name}/log-level?logger=com.example
+func (s *Server) handleGetPodLogLevel(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	pod, ok := s.cachedPod(w, chi.URLParam(r, "namespace"), chi.URLParam(r, "name"))
+	if !ok {
+		return
+	}
+	s.writeJSON(w, getPodLogLevel(r.Context(), pod, r.URL.Query().Get("logger")))
+}
+
+// handleSetPodLogLevel changes a pod's log level at runtime
+// PUT /api/pods/{namespace}/{name}/log-level
+func (s *Server) handleSetPodLogLevel(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	req, ok := s.decodeLogLevelRequest(w, r)
+	if !ok {
+		return
+	}
+	pod, ok := s.cachedPod(w, chi.URLParam(r, "namespace"), chi.URLParam(r, "name"))
+	if !ok {
+		return
+	}
+	result := setPodLogLevel(r.Context(), pod, req)
+	if result.Error != "" {
+		s.writeError(w, http.StatusBadGateway, result.Error)
+		return
+	}
+	s.writeJSON(w, result)
+}
+
+// runningWorkloadPods returns the workload's running pods
+func (s *Server) runningWorkloadPods(w http.ResponseWriter, r *http.Request) ([]*corev1.Pod, bool) {
+	pods, werr := s.getWorkloadPods(strings.ToLower(chi.URLParam(r, "kind")), chi.URLParam(r, "namespace"), chi.URLParam(r, "name"))
+	if werr != nil {
+		s.writeWorkloadError(w, werr)
+		return nil, false
+	}
+	running := make([]*corev1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+			running = append(running, pod)
+		}
+	}
+	sort.Slice(running, func(i, j int) bool { return running[i].Name < running[j].Name })
+	return running, true
+}
+
+// handleGetWorkloadLogLevel returns the log levels of a workload's running pods
+// GET /api/workloads/{kind}/{namespace}/{name}/log-level?logger=com.example
+func (s *Server) handleGetWorkloadLogLevel(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	pods, ok := s.runningWorkloadPods(w, r)
+	if !ok {
+		return
+	}
+	results := make([]PodLogLevel, len(pods))
+	var wg sync.WaitGroup
+	for i, pod := range pods {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = getPodLogLevel(r.Context(), pod, r.URL.Query().Get("logger"))
+		}()
+	}
+	wg.Wait()
+	s.writeJSON(w, map[string]any{"pods": results})
+}
+
+// handleSetWorkloadLogLevel changes the log level of all of a workload's
+// running pods. Pods started later use their configured level.
+// PUT /api/workloads/{kind}/{namespace}/{name}/log-level
+func (s *Server) handleSetWorkloadLogLevel(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	req, ok := s.decodeLogLevelRequest(w, r)
+	if !ok {
+		return
+	}
+	pods, ok := s.runningWorkloadPods(w, r)
+	if !ok {
+		return
+	}
+	if len(pods) == 0 {
+		s.writeError(w, http.StatusNotFound, "workload has no running pods")
+		return
+	}
+	results := make([]PodLogLevel, len(pods))
+	var wg sync.WaitGroup
+	for i, pod := range pods {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = setPodLogLevel(r.Context(), pod, req)
+		}()
+	}
+	wg.Wait()
+	failed := 0
+	for _, res := range results {
+		if res.Error != "" {
+			failed++
+		}
+	}
+	s.writeJSON(w, map[string]any{"pods": results, "failed": failed})
+}
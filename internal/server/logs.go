@@ -115,6 +115,12 @@ func (s *Server) handlePodLogsStream(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	s.streamPodLogsSSE(w, r, namespace, podName, container, previous, tailLines)
+}
+
+// streamPodLogsSSE follows a pod container's logs and writes them as SSE events.
+// If container is empty, the pod's first container is used.
+func (s *Server) streamPodLogsSSE(w http.ResponseWriter, r *http.Request, namespace, podName, container string, previous bool, tailLines int64) {
 	// Set SSE headers
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
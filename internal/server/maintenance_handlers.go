@@ -0,0 +1,247 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// maxMaintenanceProgress caps the progress log kept for the current run
+const maxMaintenanceProgress = 200
+
+// Maintenance run states
+const (
+	maintenanceIdle      = "idle"
+	maintenanceRunning   = "running"
+	maintenanceSucceeded = "succeeded"
+	maintenanceFailed    = "failed"
+	maintenanceCancelled = "cancelled"
+)
+
+// MaintenanceRequest selects the nodes to plan or run maintenance on
+type MaintenanceRequest struct {
+	Nodes              []string `json:"nodes"`
+	GracePeriodSeconds *int64   `json:"gracePeriodSeconds,omitempty"`
+	TimeoutSeconds     int      `json:"timeoutSeconds,omitempty"` // Per node (default 300)
+}
+
+// MaintenanceStatus is the state of the current (or last) maintenance run
+type MaintenanceStatus struct {
+	State      string              `json:"state"`
+	Nodes      []string            `json:"nodes"` // In drain order
+	Current    string              `json:"current,omitempty"`
+	Completed  []string            `json:"completed"`
+	Error      string              `json:"error,omitempty"`
+	StartedAt  *time.Time          `json:"startedAt,omitempty"`
+	FinishedAt *time.Time          `json:"finishedAt,omitempty"`
+	Progress   []k8s.DrainProgress `json:"progress"` // Oldest first
+}
+
+// MaintenanceManager runs one cordon+drain sequence at a time
+type MaintenanceManager struct {
+	mu     sync.Mutex
+	status MaintenanceStatus
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+var maintenanceManager = &MaintenanceManager{
+	status: MaintenanceStatus{State: maintenanceIdle, Nodes: []string{}, Completed: []string{}, Progress: []k8s.DrainProgress{}},
+}
+
+// Status returns a copy of the current run's status
+func (m *MaintenanceManager) Status() MaintenanceStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	status := m.status
+	status.Nodes = append([]string{}, m.status.Nodes...)
+	status.Completed = append([]string{}, m.status.Completed...)
+	status.Progress = append([]k8s.DrainProgress{}, m.status.Progress...)
+	return status
+}
+
+// Start drains the nodes in order in the background, broadcasting progress.
+// The run keeps ctx's values (the requesting user) but not its cancellation.
+func (m *MaintenanceManager) Start(ctx context.Context, order []string, opts k8s.DrainOptions, broadcast func(MaintenanceStatus)) error {
+	// A context switch mid-run would cordon nodes in the wrong cluster
+	m.once.Do(func() {
+		k8s.OnContextSwitch(func(string) { m.Cancel() })
+	})
+
+	m.mu.Lock()
+	if m.status.State == maintenanceRunning {
+		m.mu.Unlock()
+		return fmt.Errorf("maintenance already running on %v", m.status.Nodes)
+	}
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	now := time.Now()
+	m.cancel = cancel
+	m.status = MaintenanceStatus{
+		State:     maintenanceRunning,
+		Nodes:     order,
+		Completed: []string{},
+		StartedAt: &now,
+		Progress:  []k8s.DrainProgress{},
+	}
+	m.mu.Unlock()
+
+	go func() {
+		defer cancel()
+		var runErr error
+		for _, node := range order {
+			m.update(func(s *MaintenanceStatus) { s.Current = node })
+			broadcast(m.Status())
+
+			runErr = k8s.DrainNode(ctx, node, opts, func(p k8s.DrainProgress) {
+				m.update(func(s *MaintenanceStatus) {
+					s.Progress = append(s.Progress, p)
+					if len(s.Progress) > maxMaintenanceProgress {
+						s.Progress = s.Progress[len(s.Progress)-maxMaintenanceProgress:]
+					}
+				})
+				broadcast(m.Status())
+			})
+			if runErr != nil {
+				break
+			}
+			m.update(func(s *MaintenanceStatus) { s.Completed = append(s.Completed, node) })
+		}
+
+		m.update(func(s *MaintenanceStatus) {
+			finished := time.Now()
+			s.FinishedAt = &finished
+			s.Current = ""
+			switch {
+			case ctx.Err() == context.Canceled:
+				s.State = maintenanceCancelled
+			case runErr != nil:
+				s.State = maintenanceFailed
+				s.Error = runErr.Error()
+			default:
+				s.State = maintenanceSucceeded
+			}
+		})
+		status := m.Status()
+		log.Printf("[maintenance] Run %s: drained %v of %v", status.State, status.Completed, status.Nodes)
+		broadcast(status)
+	}()
+	return nil
+}
+
+// Cancel stops the running sequence; nodes already cordoned stay cordoned
+func (m *MaintenanceManager) Cancel() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.status.State != maintenanceRunning || m.cancel == nil {
+		return false
+	}
+	m.cancel()
+	return true
+}
+
+func (m *MaintenanceManager) update(fn func(*MaintenanceStatus)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fn(&m.status)
+}
+
+func decodeMaintenanceRequest(r *http.Request) (*MaintenanceRequest, error) {
+	var req MaintenanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("invalid request body")
+	}
+	if len(req.Nodes) == 0 {
+		return nil, fmt.Errorf("nodes is required")
+	}
+	if req.TimeoutSeconds < 0 {
+		return nil, fmt.Errorf("timeoutSeconds cannot be negative")
+	}
+	return &req, nil
+}
+
+// handleMaintenancePlan reports which workloads lose redundancy and which
+// PDBs block if the nodes are drained, with a proposed drain order
+// POST /api/maintenance/plan {"nodes": ["a", "b"]}
+func (s *Server) handleMaintenancePlan(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	req, err := decodeMaintenanceRequest(r)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	plan, err := k8s.PlanMaintenance(r.Context(), req.Nodes)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.writeJSON(w, plan)
+}
+
+// handleMaintenanceExecute plans the nodes and cordons+drains them one at a
+// time in the planned order. Progress is broadcast as maintenance_progress SSE
+// events and available from the status endpoint.
+// POST /api/maintenance/execute → 202 Accepted
+func (s *Server) handleMaintenanceExecute(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	req, err := decodeMaintenanceRequest(r)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	plan, err := k8s.PlanMaintenance(r.Context(), req.Nodes)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if len(plan.Order) == 0 {
+		s.writeError(w, http.StatusBadRequest, "none of the requested nodes exist")
+		return
+	}
+	order := make([]string, len(plan.Order))
+	for i, step := range plan.Order {
+		order[i] = step.Node
+	}
+
+	opts := k8s.DrainOptions{
+		GracePeriodSeconds: req.GracePeriodSeconds,
+		Timeout:            time.Duration(req.TimeoutSeconds) * time.Second,
+	}
+	broadcast := func(status MaintenanceStatus) {
+		s.broadcaster.Broadcast(SSEEvent{Event: "maintenance_progress", Data: status})
+	}
+	if err := maintenanceManager.Start(r.Context(), order, opts, broadcast); err != nil {
+		s.writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	s.writeJSON(w, maintenanceManager.Status())
+}
+
+// handleMaintenanceStatus returns the current (or last) maintenance run
+// GET /api/maintenance/status
+func (s *Server) handleMaintenanceStatus(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, maintenanceManager.Status())
+}
+
+// handleMaintenanceCancel stops the running maintenance sequence
+// DELETE /api/maintenance/execute
+func (s *Server) handleMaintenanceCancel(w http.ResponseWriter, r *http.Request) {
+	if !maintenanceManager.Cancel() {
+		s.writeError(w, http.StatusConflict, "no maintenance run in progress")
+		return
+	}
+	s.writeJSON(w, map[string]string{"status": "cancelling"})
+}
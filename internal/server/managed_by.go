@@ -0,0 +1,61 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// listWithManagedBy adds a top-level "managedBy" field to each listed
+// resource, keeping only those whose system is in filter (nil keeps all).
+// Items are typed objects or unstructured CRs, so the field is spliced into
+// their encoded JSON.
+func listWithManagedBy(cache *k8s.ResourceCache, items any, filter map[string]bool) ([]json.RawMessage, error) {
+	result := []json.RawMessage{}
+	for _, item := range appendSlice(nil, items) {
+		obj, err := meta.Accessor(item)
+		if err != nil {
+			return nil, err
+		}
+		managedBy := cache.GetManagedBy(obj)
+		if filter != nil && !filter[managedBy.System] {
+			continue
+		}
+		itemJSON, err := json.Marshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", obj.GetName(), err)
+		}
+		managedByJSON, err := json.Marshal(managedBy)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.HasSuffix(itemJSON, []byte("}")) {
+			return nil, fmt.Errorf("%s did not encode as an object", obj.GetName())
+		}
+		itemJSON = itemJSON[:len(itemJSON)-1]
+		if len(itemJSON) > 1 {
+			itemJSON = append(itemJSON, ',')
+		}
+		itemJSON = append(append(append(itemJSON, `"managedBy":`...), managedByJSON...), '}')
+		result = append(result, itemJSON)
+	}
+	return result, nil
+}
+
+// filterSummaryManagedBy keeps the summary rows whose system is in filter
+func filterSummaryManagedBy(rows []k8s.ResourceSummaryRow, filter map[string]bool) []k8s.ResourceSummaryRow {
+	if filter == nil {
+		return rows
+	}
+	kept := make([]k8s.ResourceSummaryRow, 0, len(rows))
+	for _, row := range rows {
+		if row.ManagedBy != nil && filter[row.ManagedBy.System] {
+			kept = append(kept, row)
+		}
+	}
+	return kept
+}
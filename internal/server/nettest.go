@@ -0,0 +1,358 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/validation"
+	utilexec "k8s.io/client-go/util/exec"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// DefaultNetTestImage is the debug image used when a probe runs in an ephemeral container
+const DefaultNetTestImage = "nicolaka/netshoot:latest"
+
+const (
+	netTestDefaultTimeout    = 5
+	netTestDebugStartTimeout = 25 * time.Second
+	netTestMaxTimeout        = 15 // Keeps the worst case under the 60s API route timeout
+)
+
+// Probe scripts run via `sh -c <script> sh <args...>` so user input is only ever
+// passed as positional parameters, never interpolated into the shell command.
+// Each script prints "tool: <name>" first and falls back across common tools.
+const (
+	netTestTCPScript = `if command -v nc >/dev/null 2>&1; then echo "tool: nc"; exec nc -z -w "$3" "$1" "$2"; fi
+if command -v bash >/dev/null 2>&1; then echo "tool: bash"; exec timeout "$3" bash -c 'exec 3<>"/dev/tcp/$0/$1"' "$1" "$2"; fi
+echo "no TCP client (nc or bash) available in container" >&2; exit 127`
+
+	netTestHTTPScript = `if command -v curl >/dev/null 2>&1; then echo "tool: curl"; exec curl -sS -o /dev/null -m "$2" -w 'status: %{http_code}\n' "$1"; fi
+if command -v wget >/dev/null 2>&1; then echo "tool: wget"; exec wget -S -O /dev/null -T "$2" "$1" 2>&1; fi
+echo "no HTTP client (curl or wget) available in container" >&2; exit 127`
+
+	netTestDNSScript = `if command -v nslookup >/dev/null 2>&1; then echo "tool: nslookup"; exec nslookup "$1"; fi
+if command -v getent >/dev/null 2>&1; then echo "tool: getent"; exec getent hosts "$1"; fi
+echo "no DNS client (nslookup or getent) available in container" >&2; exit 127`
+)
+
+// NetTestRequest is the request body for POST /api/nettest
+type NetTestRequest struct {
+	// Source pod the probe runs from
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container,omitempty"`
+	// Run the probe from an ephemeral debug container instead of an existing container.
+	// Useful for distroless images. Ephemeral containers cannot be removed once added.
+	UseDebugContainer bool   `json:"useDebugContainer,omitempty"`
+	Image             string `json:"image,omitempty"` // Debug image (default: nicolaka/netshoot)
+
+	// Probe type: tcp, http, or dns
+	Type string `json:"type"`
+	// Target: either a host (DNS name or IP) or a Service in the cluster
+	Host             string `json:"host,omitempty"`
+	Service          string `json:"service,omitempty"`
+	ServiceNamespace string `json:"serviceNamespace,omitempty"` // Defaults to the source pod's namespace
+	Port             int    `json:"port,omitempty"`
+	Path             string `json:"path,omitempty"`   // HTTP path (default: /)
+	Scheme           string `json:"scheme,omitempty"` // http or https (default: http)
+	TimeoutSeconds   int    `json:"timeoutSeconds,omitempty"`
+}
+
+// NetTestResult is the structured result of a connectivity probe
+type NetTestResult struct {
+	Success    bool     `json:"success"`
+	Type       string   `json:"type"`
+	Namespace  string   `json:"namespace"`
+	Pod        string   `json:"pod"`
+	Container  string   `json:"container,omitempty"`
+	Target     string   `json:"target"` // host:port, URL, or hostname
+	Tool       string   `json:"tool,omitempty"`
+	DurationMs int64    `json:"durationMs"` // Includes exec round-trip overhead
+	ExitCode   int      `json:"exitCode"`
+	StatusCode int      `json:"statusCode,omitempty"` // HTTP probes
+	Addresses  []string `json:"addresses,omitempty"`  // DNS probes
+	Output     string   `json:"output,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// handleNetTest runs a TCP, HTTP, or DNS connectivity probe from inside a pod
+// POST /api/nettest
+func (s *Server) handleNetTest(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	var req NetTestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if req.Namespace == "" || req.Pod == "" {
+		s.writeError(w, http.StatusBadRequest, "namespace and pod are required")
+		return
+	}
+
+	host := req.Host
+	if req.Service != "" {
+		if host != "" {
+			s.writeError(w, http.StatusBadRequest, "host and service are mutually exclusive")
+			return
+		}
+		svcNs := req.ServiceNamespace
+		if svcNs == "" {
+			svcNs = req.Namespace
+		}
+		host = fmt.Sprintf("%s.%s.svc", req.Service, svcNs)
+	}
+	if host == "" {
+		s.writeError(w, http.StatusBadRequest, "host or service is required")
+		return
+	}
+	if !validNetTestHost(host) {
+		s.writeError(w, http.StatusBadRequest, "invalid host: must be a DNS name or IP address")
+		return
+	}
+	if req.Port < 0 || req.Port > 65535 {
+		s.writeError(w, http.StatusBadRequest, "port must be between 1 and 65535")
+		return
+	}
+
+	timeout := req.TimeoutSeconds
+	if timeout <= 0 {
+		timeout = netTestDefaultTimeout
+	}
+	if timeout > netTestMaxTimeout {
+		timeout = netTestMaxTimeout
+	}
+	timeoutStr := strconv.Itoa(timeout)
+
+	var script, target string
+	var args []string
+	switch req.Type {
+	case "tcp":
+		if req.Port == 0 {
+			s.writeError(w, http.StatusBadRequest, "port is required for tcp probes")
+			return
+		}
+		target = net.JoinHostPort(host, strconv.Itoa(req.Port))
+		script = netTestTCPScript
+		args = []string{host, strconv.Itoa(req.Port), timeoutStr}
+	case "http":
+		scheme := req.Scheme
+		if scheme == "" {
+			scheme = "http"
+		}
+		if scheme != "http" && scheme != "https" {
+			s.writeError(w, http.StatusBadRequest, "scheme must be http or https")
+			return
+		}
+		path := req.Path
+		if path == "" {
+			path = "/"
+		}
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		hostPort := host
+		if req.Port != 0 {
+			hostPort = net.JoinHostPort(host, strconv.Itoa(req.Port))
+		}
+		target = scheme + "://" + hostPort + path
+		script = netTestHTTPScript
+		args = []string{target, timeoutStr}
+	case "dns":
+		target = host
+		script = netTestDNSScript
+		args = []string{host}
+	default:
+		s.writeError(w, http.StatusBadRequest, "type must be one of: tcp, http, dns")
+		return
+	}
+
+	// Allow for exec setup (and debug container startup) on top of the probe timeout
+	budget := time.Duration(timeout+10) * time.Second
+	if req.UseDebugContainer {
+		budget += netTestDebugStartTimeout
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), budget)
+	defer cancel()
+
+	container := req.Container
+	if req.UseDebugContainer {
+		image := req.Image
+		if image == "" {
+			image = DefaultNetTestImage
+		}
+		ec, err := k8s.CreateEphemeralContainer(ctx, k8s.EphemeralContainerOptions{
+			Namespace: req.Namespace,
+			PodName:   req.Pod,
+			Image:     image,
+			// Random suffix like GenerateName; ephemeral containers can't use it
+			ContainerName: "nettest-" + utilrand.String(5),
+		})
+		if err != nil {
+			errMsg := err.Error()
+			if strings.Contains(errMsg, "not found") {
+				s.writeError(w, http.StatusNotFound, errMsg)
+				return
+			}
+			log.Printf("[nettest] Failed to create debug container for %s/%s: %v", req.Namespace, req.Pod, err)
+			s.writeError(w, http.StatusInternalServerError, errMsg)
+			return
+		}
+		if err := k8s.WaitForEphemeralContainer(ctx, req.Namespace, req.Pod, ec.Name, netTestDebugStartTimeout); err != nil {
+			s.writeError(w, http.StatusGatewayTimeout, fmt.Sprintf("debug container %s did not start: %v", ec.Name, err))
+			return
+		}
+		container = ec.Name
+	}
+
+	result := NetTestResult{
+		Type:      req.Type,
+		Namespace: req.Namespace,
+		Pod:       req.Pod,
+		Container: container,
+		Target:    target,
+	}
+
+	command := append([]string{"sh", "-c", script, "sh"}, args...)
+	var stdout, stderr bytes.Buffer
+	start := time.Now()
+	execErr := s.execInPod(ctx, req.Namespace, req.Pod, container, command, nil, &stdout, &stderr)
+	result.DurationMs = time.Since(start).Milliseconds()
+
+	if execErr != nil {
+		var exitErr utilexec.ExitError
+		if errors.As(execErr, &exitErr) {
+			result.ExitCode = exitErr.ExitStatus()
+		} else if isShellNotFoundError(execErr.Error()) {
+			s.writeError(w, http.StatusUnprocessableEntity, "container has no shell; retry with useDebugContainer")
+			return
+		} else {
+			log.Printf("[nettest] Exec failed in %s/%s: %v", req.Namespace, req.Pod, execErr)
+			s.writeError(w, http.StatusInternalServerError, execErr.Error())
+			return
+		}
+	}
+
+	output := stdout.String()
+	result.Tool, output = parseNetTestTool(output)
+	result.Output = strings.TrimSpace(output + stderr.String())
+
+	switch req.Type {
+	case "tcp":
+		result.Success = result.ExitCode == 0
+	case "http":
+		// Any HTTP response means the target is reachable
+		result.StatusCode = parseHTTPStatus(output)
+		result.Success = result.StatusCode > 0
+	case "dns":
+		result.Addresses = parseDNSAddresses(result.Tool, output)
+		result.Success = result.ExitCode == 0 && len(result.Addresses) > 0
+	}
+
+	if !result.Success {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			result.Error = msg
+		} else {
+			result.Error = fmt.Sprintf("%s probe to %s failed (exit code %d)", req.Type, target, result.ExitCode)
+		}
+	}
+
+	s.writeJSON(w, result)
+}
+
+// validNetTestHost reports whether host is an IP address or DNS name. Anything
+// else, e.g. a leading "-" that the probe tools would parse as a flag, is rejected.
+func validNetTestHost(host string) bool {
+	if net.ParseIP(host) != nil {
+		return true
+	}
+	return len(validation.IsDNS1123Subdomain(strings.ToLower(strings.TrimSuffix(host, ".")))) == 0
+}
+
+// parseNetTestTool strips the "tool: <name>" marker line printed by the probe scripts
+func parseNetTestTool(output string) (string, string) {
+	first, rest, _ := strings.Cut(output, "\n")
+	if tool, ok := strings.CutPrefix(first, "tool: "); ok {
+		return strings.TrimSpace(tool), rest
+	}
+	return "", output
+}
+
+// parseHTTPStatus extracts the final HTTP status from curl (-w) or wget (-S) output
+func parseHTTPStatus(output string) int {
+	status := 0
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if v, ok := strings.CutPrefix(line, "status: "); ok {
+			if code, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && code > 0 {
+				status = code
+			}
+			continue
+		}
+		// wget -S prints response headers, e.g. "HTTP/1.1 200 OK" (last one wins after redirects)
+		if strings.HasPrefix(line, "HTTP/") {
+			fields := strings.Fields(line)
+			if len(fields) >= 2 {
+				if code, err := strconv.Atoi(fields[1]); err == nil {
+					status = code
+				}
+			}
+		}
+	}
+	return status
+}
+
+// parseDNSAddresses extracts resolved addresses from nslookup or getent output
+func parseDNSAddresses(tool, output string) []string {
+	var addrs []string
+	seen := make(map[string]bool)
+	add := func(ip string) {
+		if net.ParseIP(ip) != nil && !seen[ip] {
+			seen[ip] = true
+			addrs = append(addrs, ip)
+		}
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	afterName := false
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if tool == "getent" {
+			if fields := strings.Fields(line); len(fields) > 0 {
+				add(fields[0])
+			}
+			continue
+		}
+		// nslookup lists the DNS server's address before the answer's "Name:" line
+		if strings.HasPrefix(line, "Name:") {
+			afterName = true
+			continue
+		}
+		if afterName && strings.HasPrefix(line, "Address") {
+			_, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			for _, field := range strings.Fields(value) {
+				add(field)
+			}
+		}
+	}
+	return addrs
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// handleNodeDetail returns a node's kernel/OS info, capacity vs allocatable,
+// condition history from the timeline and its K8s event feed
+// GET /api/nodes/{name}/detail
+func (s *Server) handleNodeDetail(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	loc, err := parseTimezone(r.URL.Query())
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	detail, err := k8s.GetNodeDetail(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			s.writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	detail.InZone(loc)
+	s.writeJSON(w, detail)
+}
+
+// handlePodPlacement returns pod counts and requests per node×namespace and
+// zone×namespace for a placement heatmap
+// GET /api/placement?namespaces=a,b
+func (s *Server) handlePodPlacement(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	placement, err := k8s.GetPodPlacement(parseNamespaces(r.URL.Query()))
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.writeJSON(w, placement)
+}
+
+// handlePlacementViolations returns workloads whose running pods break their
+// anti-affinity or topology spread, or share a single node or zone
+// GET /api/placement/violations?namespaces=a,b
+func (s *Server) handlePlacementViolations(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	violations, err := k8s.GetPlacementViolations(parseNamespaces(r.URL.Query()))
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.writeJSON(w, violations)
+}
+
+// getDashboardPlacementWarnings turns placement violations into dashboard warnings
+func (s *Server) getDashboardPlacementWarnings(namespaces []string) []DashboardProblem {
+	violations, err := k8s.GetPlacementViolations(namespaces)
+	if err != nil {
+		return []DashboardProblem{}
+	}
+	warnings := make([]DashboardProblem, 0, len(violations))
+	for _, v := range violations {
+		reason := v.Type
+		if v.Severity == k8s.PlacementViolated {
+			reason += " violated"
+		}
+		warnings = append(warnings, DashboardProblem{
+			Kind:      v.Kind,
+			Namespace: v.Namespace,
+			Name:      v.Name,
+			Status:    "warning",
+			Reason:    reason,
+			Message:   v.Message,
+		})
+	}
+	return warnings
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/cors"
+
+	"github.com/skyhook-io/radar/internal/apierror"
+)
+
+// Browsers attach cookies and client certificates to cross-site requests, so
+// any page a user visits could otherwise drive the API. Mutating requests and
+// WebSocket upgrades are only accepted from the UI's own origin, origins
+// listed with --allowed-origins (e.g. a custom domain in front of an in-cluster
+// Radar whose proxy rewrites Host), and non-browser clients, which send
+// neither Origin nor Sec-Fetch-Site.
+
+// contentSecurityPolicy is sent with the UI. The YAML editor loads Monaco
+// from jsDelivr; images allow https: for chart and CRD icons.
+const contentSecurityPolicy = "default-src 'self'; " +
+	"script-src 'self' https://cdn.jsdelivr.net; " +
+	"style-src 'self' 'unsafe-inline' https://cdn.jsdelivr.net; " +
+	"font-src 'self' data: https://cdn.jsdelivr.net; " +
+	"img-src 'self' data: blob: https:; " +
+	"worker-src 'self' blob:; " +
+	"connect-src 'self' ws: wss:; " +
+	"frame-ancestors 'none'; base-uri 'self'; form-action 'self'; object-src 'none'"
+
+// originPolicy decides which cross-origin browser requests are allowed
+type originPolicy struct {
+	trusted   map[string]bool // Normalized scheme://host[:port]
+	localhost bool            // Any localhost port (dev mode, for the Vite dev server)
+	csrf      *http.CrossOriginProtection
+}
+
+func newOriginPolicy(origins []string, devMode bool) *originPolicy {
+	p := &originPolicy{
+		trusted:   make(map[string]bool),
+		localhost: devMode,
+		csrf:      http.NewCrossOriginProtection(),
+	}
+	for _, origin := range origins {
+		// Origins are validated when flags are parsed (see app.ParseAllowedOrigins)
+		if err := p.csrf.AddTrustedOrigin(origin); err == nil {
+			p.trusted[strings.ToLower(origin)] = true
+		}
+	}
+	p.csrf.SetDenyHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apierror.WriteCode(w, http.StatusForbidden, apierror.CrossOrigin,
+			"cross-origin request blocked: "+r.Header.Get("Origin")+" is not an allowed origin")
+	}))
+	return p
+}
+
+// allowed reports whether a browser request from origin may call the API
+func (p *originPolicy) allowed(origin string) bool {
+	if p.trusted[strings.ToLower(origin)] {
+		return true
+	}
+	if !p.localhost {
+		return false
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Scheme != "http" {
+		return false
+	}
+	host := u.Hostname()
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
+
+// guard rejects cross-origin mutating requests (POST, PUT, PATCH, DELETE)
+func (p *originPolicy) guard(next http.Handler) http.Handler {
+	protected := p.csrf.Handler(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" && p.allowed(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		protected.ServeHTTP(w, r)
+	})
+}
+
+// checkWebSocketOrigin is the upgrader's CheckOrigin. Upgrades are GETs, so
+// the mutating-request guard doesn't cover terminals.
+func (p *originPolicy) checkWebSocketOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	switch r.Header.Get("Sec-Fetch-Site") {
+	case "same-origin", "none":
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	return p.allowed(origin)
+}
+
+// cors returns the CORS middleware. Only trusted origins (and localhost in dev
+// mode) may read responses cross-origin; the UI itself is same-origin.
+func (p *originPolicy) cors() func(http.Handler) http.Handler {
+	return cors.Handler(cors.Options{
+		AllowOriginFunc:  func(_ *http.Request, origin string) bool { return p.allowed(origin) },
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Content-Type", "If-None-Match"},
+		ExposedHeaders:   []string{"ETag", "X-Content-SHA256", "X-Next-Cursor"},
+		AllowCredentials: true,
+	})
+}
+
+// securityHeaders adds the content security policy and related headers to UI responses
+func securityHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Set("Content-Security-Policy", contentSecurityPolicy)
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("Referrer-Policy", "same-origin")
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// handlePodConfig returns a container's effective environment (envFrom and
+// env resolved against ConfigMaps and Secrets) and the files its volume
+// mounts project. Secret values are redacted unless ?reveal=true.
+// GET /api/pods/{namespace}/{name}/config?container=app&reveal=true
+func (s *Server) handlePodConfig(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+	reveal := r.URL.Query().Get("reveal") == "true"
+	config, ok, err := k8s.GetPodConfig(namespace, name, r.URL.Query().Get("container"), reveal)
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "pod not found")
+		return
+	}
+	if errors.Is(err, k8s.ErrContainerNotFound) {
+		s.writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if reveal {
+		log.Printf("[config] %s revealed secret values of %s/%s container %s", requestActor(r), namespace, name, config.Container)
+	}
+	s.writeJSON(w, config)
+}
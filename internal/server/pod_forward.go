@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	podForwardIdle  = 5 * time.Minute // Unused forwards are closed after this
+	podForwardReady = 5 * time.Second
+)
+
+// podForward is a localhost port forward Radar uses to reach an HTTP endpoint
+// in a pod (pprof, log level handlers). They're internal: not listed with
+// user port forwards or counted in limits.
+type podForward struct {
+	session  *PortForwardSession
+	lastUsed time.Time
+}
+
+var podForwards = struct {
+	mu sync.Mutex
+	m  map[string]*podForward // namespace/pod:port
+}{m: make(map[string]*podForward)}
+
+// podForwardURL returns the local URL of a port forward to the pod's port,
+// starting one if needed, and closes forwards that have been idle too long
+func podForwardURL(ctx context.Context, namespace, podName string, port int) (*url.URL, error) {
+	key := fmt.Sprintf("%s/%s:%d", namespace, podName, port)
+	now := time.Now()
+
+	podForwardJanitorOnce.Do(func() {
+		go func() {
+			for range time.Tick(time.Minute) {
+				closeIdlePodForwards(time.Now())
+			}
+		}()
+	})
+	closeIdlePodForwards(now)
+
+	podForwards.mu.Lock()
+	if f, ok := podForwards.m[key]; ok {
+		f.lastUsed = now
+		podForwards.mu.Unlock()
+		return &url.URL{Scheme: "http", Host: fmt.Sprintf("127.0.0.1:%d", f.session.LocalPort)}, nil
+	}
+	podForwards.mu.Unlock()
+
+	// Debug ports are often not declared on the container, so any port is allowed
+	localPort, err := findFreePort()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find a free port: %w", err)
+	}
+	// Outlives the request; the API calls opening it stay attributed to its user
+	fwdCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	session := &PortForwardSession{
+		ID:            "internal-" + key,
+		Namespace:     namespace,
+		PodName:       podName,
+		PodPort:       port,
+		LocalPort:     localPort,
+		ListenAddress: "127.0.0.1",
+		StartedAt:     now,
+		Status:        "starting",
+		cancel:        cancel,
+		stopCh:        make(chan struct{}),
+	}
+	go func() {
+		err := runPortForward(fwdCtx, session)
+		pfManager.mu.Lock()
+		if err != nil {
+			session.Status, session.Error = "error", err.Error()
+		} else {
+			session.Status = "stopped"
+		}
+		pfManager.mu.Unlock()
+	}()
+
+	deadline := time.Now().Add(podForwardReady)
+	for {
+		switch podForwardStatus(session) {
+		case "running":
+			podForwards.mu.Lock()
+			if existing, ok := podForwards.m[key]; ok {
+				// Lost a race with another request: use its forward
+				podForwards.mu.Unlock()
+				cancel()
+				close(session.stopCh)
+				return &url.URL{Scheme: "http", Host: fmt.Sprintf("127.0.0.1:%d", existing.session.LocalPort)}, nil
+			}
+			podForwards.m[key] = &podForward{session: session, lastUsed: time.Now()}
+			podForwards.mu.Unlock()
+			return &url.URL{Scheme: "http", Host: fmt.Sprintf("127.0.0.1:%d", localPort)}, nil
+		case "error":
+			pfManager.mu.RLock()
+			msg := session.Error
+			pfManager.mu.RUnlock()
+			return nil, fmt.Errorf("port forward to %s failed: %s", key, msg)
+		}
+		if time.Now().After(deadline) || ctx.Err() != nil {
+			cancel()
+			close(session.stopCh)
+			return nil, fmt.Errorf("port forward to %s did not start", key)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+var podForwardJanitorOnce sync.Once
+
+// closeIdlePodForwards stops forwards that are idle or have failed
+func closeIdlePodForwards(now time.Time) {
+	podForwards.mu.Lock()
+	defer podForwards.mu.Unlock()
+	for k, f := range podForwards.m {
+		if status := podForwardStatus(f.session); now.Sub(f.lastUsed) > podForwardIdle || status == "error" || status == "stopped" {
+			f.session.cancel()
+			close(f.session.stopCh)
+			delete(podForwards.m, k)
+		}
+	}
+}
+
+// podForwardStatus reads a session's status, which runPortForward updates
+// under pfManager.mu
+func podForwardStatus(session *PortForwardSession) string {
+	pfManager.mu.RLock()
+	defer pfManager.mu.RUnlock()
+	return session.Status
+}
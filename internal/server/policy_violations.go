@@ -0,0 +1,25 @@
+package server
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// handlePolicyViolations returns Gatekeeper and Kyverno violations grouped by policy and namespace
+// GET /api/policy-violations?namespaces=a,b
+func (s *Server) handlePolicyViolations(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	summary, err := k8s.GetPolicyViolations(parseNamespaces(r.URL.Query()))
+	if err != nil {
+		log.Printf("[policy] Failed to collect policy violations: %v", err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.writeJSON(w, summary)
+}
@@ -30,11 +30,13 @@ type PortForwardSession struct {
 	PodName       string    `json:"podName"`
 	PodPort       int       `json:"podPort"`
 	LocalPort     int       `json:"localPort"`
-	ListenAddress string    `json:"listenAddress"` // "127.0.0.1" or "0.0.0.0"
+	ListenAddress string    `json:"listenAddress"`         // "127.0.0.1" or "0.0.0.0"
 	ServiceName   string    `json:"serviceName,omitempty"` // If forwarding to a service
 	StartedAt     time.Time `json:"startedAt"`
 	Status        string    `json:"status"` // "running", "stopped", "error"
 	Error         string    `json:"error,omitempty"`
+	Client        string    `json:"client,omitempty"` // Address of the client that started it
+	LastActivity  time.Time `json:"lastActivity"`     // Last new connection through the forward
 
 	cancel context.CancelFunc
 	stopCh chan struct{}
@@ -163,12 +165,19 @@ func (s *Server) handleStartPortForward(w http.ResponseWriter, r *http.Request)
 		listenAddr = "127.0.0.1"
 	}
 
+	clientAddr := clientID(r)
+	if limit := s.sessionLimits.MaxPortForwardsPerClient; limit > 0 && countPortForwards(clientAddr) >= limit {
+		s.writeError(w, http.StatusTooManyRequests, fmt.Sprintf("Port forward limit reached (%d per client); stop one first", limit))
+		return
+	}
+
 	// Create session
 	pfManager.mu.Lock()
 	pfManager.nextID++
 	sessionID := fmt.Sprintf("pf-%d", pfManager.nextID)
 
-	ctx, cancel := context.WithCancel(context.Background())
+	// Outlives the request, but keeps its user for the API server's audit log
+	ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
 	stopCh := make(chan struct{})
 
 	session := &PortForwardSession{
@@ -181,6 +190,8 @@ func (s *Server) handleStartPortForward(w http.ResponseWriter, r *http.Request)
 		ServiceName:   req.ServiceName,
 		StartedAt:     time.Now(),
 		Status:        "starting",
+		Client:        clientAddr,
+		LastActivity:  time.Now(),
 		cancel:        cancel,
 		stopCh:        stopCh,
 	}
@@ -236,14 +247,32 @@ func (s *Server) handleStopPortForward(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Signal stop
+	stopPortForwardLocked(session)
+	pfManager.mu.Unlock()
+
+	s.writeJSON(w, map[string]string{"status": "stopped"})
+}
+
+// stopPortForwardLocked signals a session to stop and forgets it. The caller
+// holds pfManager.mu.
+func stopPortForwardLocked(session *PortForwardSession) {
 	session.cancel()
 	close(session.stopCh)
 	session.Status = "stopped"
-	delete(pfManager.sessions, sessionID)
-	pfManager.mu.Unlock()
+	delete(pfManager.sessions, session.ID)
+}
 
-	s.writeJSON(w, map[string]string{"status": "stopped"})
+// pfActivityWriter marks a port forward active whenever it reports a new
+// connection ("Handling connection for ...")
+type pfActivityWriter struct {
+	session *PortForwardSession
+}
+
+func (w *pfActivityWriter) Write(p []byte) (int, error) {
+	pfManager.mu.Lock()
+	w.session.LastActivity = time.Now()
+	pfManager.mu.Unlock()
+	return len(p), nil
 }
 
 func runPortForward(ctx context.Context, session *PortForwardSession) error {
@@ -271,8 +300,8 @@ func runPortForward(ctx context.Context, session *PortForwardSession) error {
 	addresses := []string{session.ListenAddress}
 	readyCh := make(chan struct{})
 
-	// Discard output - in production you might want to capture this
-	out := io.Discard
+	// Output is only used to track activity for the idle timeout
+	out := &pfActivityWriter{session: session}
 	errOut := io.Discard
 
 	pf, err := portforward.NewOnAddresses(dialer, addresses, ports, session.stopCh, readyCh, out, errOut)
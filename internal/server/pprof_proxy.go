@@ -0,0 +1,376 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/settings"
+)
+
+const (
+	pprofProbeTimeout    = 3 * time.Second
+	pprofDetectTimeout   = 10 * time.Second // All probes, including starting port forwards
+	pprofDefaultPort     = 6060
+	pprofMaxProbePorts   = 8
+	pprofDefaultSeconds  = 30
+	pprofMaxSeconds      = 120
+	pprofMaxProfiles     = 50 // Oldest saved profiles are removed beyond this
+	pprofProfilesDirName = "profiles"
+)
+
+// pprofCaptureTypes are the profiles that can be captured and saved
+var pprofCaptureTypes = map[string]bool{
+	"profile": true, "trace": true, "heap": true, "allocs": true, "goroutine": true, "block": true, "mutex": true, "threadcreate": true,
+}
+
+func parsePprofPort(w http.ResponseWriter, s *Server, value string) (int, bool) {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		s.writeError(w, http.StatusBadRequest, "invalid port")
+		return 0, false
+	}
+	return port, true
+}
+
+// handlePprofDetect probes a pod's declared TCP ports (and 6060) for a
+// net/http/pprof index
+// GET /api/pods/{namespace}/{name}/pprof
+func (s *Server) handlePprofDetect(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	namespace := chi.URLParam(r, "namespace")
+	podName := chi.URLParam(r, "name")
+
+	cache := k8s.GetResourceCache()
+	if cache == nil || cache.Pods() == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Resource cache not available")
+		return
+	}
+	pod, err := cache.Pods().Pods(namespace).Get(podName)
+	if err != nil {
+		s.writeError(w, http.StatusNotFound, fmt.Sprintf("Pod not found: %v", err))
+		return
+	}
+
+	ports := []int{pprofDefaultPort}
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			if (p.Protocol == "" || p.Protocol == "TCP") && int(p.ContainerPort) != pprofDefaultPort {
+				ports = append(ports, int(p.ContainerPort))
+			}
+		}
+	}
+	if len(ports) > pprofMaxProbePorts {
+		ports = ports[:pprofMaxProbePorts]
+	}
+
+	// Probe in parallel under one deadline so slow or closed ports don't add up
+	ctx, cancel := context.WithTimeout(r.Context(), pprofDetectTimeout)
+	defer cancel()
+	client := &http.Client{Timeout: pprofProbeTimeout}
+	hasPprof := make([]bool, len(ports))
+	var wg sync.WaitGroup
+	for i, port := range ports {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hasPprof[i] = probePprof(ctx, client, namespace, podName, port)
+		}()
+	}
+	wg.Wait()
+
+	found := []int{}
+	for i, port := range ports {
+		if hasPprof[i] {
+			found = append(found, port)
+		}
+	}
+	s.writeJSON(w, map[string]any{"ports": found, "probed": ports})
+}
+
+// probePprof reports whether a pod's port serves a net/http/pprof index
+func probePprof(ctx context.Context, client *http.Client, namespace, podName string, port int) bool {
+	base, err := podForwardURL(ctx, namespace, podName, port)
+	if err != nil {
+		return false
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base.String()+"/debug/pprof/", nil)
+	if err != nil {
+		return false
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	return resp.StatusCode == http.StatusOK && strings.Contains(string(body), "goroutine")
+}
+
+// handlePprofProxy proxies /debug/pprof on a pod's port through a port
+// forward, so the pprof index and its links work from Radar
+// GET /api/pods/{namespace}/{name}/pprof/{port}/*
+func (s *Server) handlePprofProxy(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	namespace := chi.URLParam(r, "namespace")
+	podName := chi.URLParam(r, "name")
+	port, ok := parsePprofPort(w, s, chi.URLParam(r, "port"))
+	if !ok {
+		return
+	}
+	base, err := podForwardURL(r.Context(), namespace, podName, port)
+	if err != nil {
+		s.writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	rest := strings.TrimPrefix(chi.URLParam(r, "*"), "/")
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.Out.URL.Scheme, pr.Out.URL.Host = base.Scheme, base.Host
+			pr.Out.URL.Path = "/debug/pprof/" + rest
+			pr.Out.URL.RawPath = ""
+			pr.Out.Host = base.Host
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			s.writeError(w, http.StatusBadGateway, "pprof request failed: "+err.Error())
+		},
+	}
+	proxy.ServeHTTP(w, r)
+}
+
+// handlePprofIndexRedirect adds the trailing slash the index's relative links need
+// GET /api/pods/{namespace}/{name}/pprof/{port}
+func (s *Server) handlePprofIndexRedirect(w http.ResponseWriter, r *http.Request) {
+	http.Redirect(w, r, r.URL.Path+"/", http.StatusMovedPermanently)
+}
+
+// PprofProfile is a saved profile of a pod
+type PprofProfile struct {
+	ID         string    `json:"id"`
+	Namespace  string    `json:"namespace"`
+	Pod        string    `json:"pod"`
+	Port       int       `json:"port"`
+	Type       string    `json:"type"`
+	CapturedAt time.Time `json:"capturedAt"`
+	SizeBytes  int64     `json:"sizeBytes"`
+}
+
+// Saved profiles are named namespace_pod_port_type_time.ext; Kubernetes names
+// can't contain underscores
+var pprofProfileRe = regexp.MustCompile(`^([a-z0-9.-]+)_([a-z0-9.-]+)_(\d+)_([a-z]+)_(\d{8}T\d{6}Z)\.(pb\.gz|trace)$`)
+
+func pprofProfilesDir() string {
+	if p := settings.Path(); p != "" {
+		return filepath.Join(filepath.Dir(p), pprofProfilesDirName)
+	}
+	return filepath.Join(os.TempDir(), "radar-"+pprofProfilesDirName)
+}
+
+func parsePprofProfile(name string, size int64) (PprofProfile, bool) {
+	m := pprofProfileRe.FindStringSubmatch(name)
+	if m == nil {
+		return PprofProfile{}, false
+	}
+	port, _ := strconv.Atoi(m[3])
+	at, err := time.Parse("20060102T150405Z", m[5])
+	if err != nil {
+		return PprofProfile{}, false
+	}
+	return PprofProfile{ID: name, Namespace: m[1], Pod: m[2], Port: port, Type: m[4], CapturedAt: at, SizeBytes: size}, true
+}
+
+// listPprofProfiles returns saved profiles, newest first
+func listPprofProfiles() []PprofProfile {
+	entries, err := os.ReadDir(pprofProfilesDir())
+	if err != nil {
+		return nil
+	}
+	var profiles []PprofProfile
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || e.IsDir() {
+			continue
+		}
+		if p, ok := parsePprofProfile(e.Name(), info.Size()); ok {
+			profiles = append(profiles, p)
+		}
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].CapturedAt.After(profiles[j].CapturedAt) })
+	return profiles
+}
+
+// handlePprofCapture fetches a profile (default: a 30s CPU profile) through
+// the proxy and saves it, so it can be downloaded later
+// POST /api/pods/{namespace}/{name}/pprof/capture?port=6060&type=profile&seconds=30
+func (s *Server) handlePprofCapture(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	namespace := chi.URLParam(r, "namespace")
+	podName := chi.URLParam(r, "name")
+	q := r.URL.Query()
+
+	port := pprofDefaultPort
+	if v := q.Get("port"); v != "" {
+		var ok bool
+		if port, ok = parsePprofPort(w, s, v); !ok {
+			return
+		}
+	}
+	profileType := q.Get("type")
+	if profileType == "" {
+		profileType = "profile"
+	}
+	if !pprofCaptureTypes[profileType] {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported profile type %q", profileType))
+		return
+	}
+	seconds := pprofDefaultSeconds
+	if v := q.Get("seconds"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > pprofMaxSeconds {
+			s.writeError(w, http.StatusBadRequest, fmt.Sprintf("seconds must be between 1 and %d", pprofMaxSeconds))
+			return
+		}
+		seconds = n
+	}
+
+	base, err := podForwardURL(r.Context(), namespace, podName, port)
+	if err != nil {
+		s.writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	target := base.String() + "/debug/pprof/" + profileType
+	if profileType == "profile" || profileType == "trace" {
+		target += "?seconds=" + strconv.Itoa(seconds)
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(seconds+30)*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		s.writeError(w, http.StatusBadGateway, "failed to capture profile: "+err.Error())
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		s.writeError(w, http.StatusBadGateway, fmt.Sprintf("pprof returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body))))
+		return
+	}
+
+	dir := pprofProfilesDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to save profile: "+err.Error())
+		return
+	}
+	ext := "pb.gz"
+	if profileType == "trace" {
+		ext = "trace"
+	}
+	name := fmt.Sprintf("%s_%s_%d_%s_%s.%s", namespace, podName, port, profileType, time.Now().UTC().Format("20060102T150405Z"), ext)
+	file, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "failed to save profile: "+err.Error())
+		return
+	}
+	size, copyErr := io.Copy(file, resp.Body)
+	if err := file.Close(); copyErr == nil {
+		copyErr = err
+	}
+	if copyErr != nil {
+		os.Remove(file.Name())
+		s.writeError(w, http.StatusBadGateway, "failed to save profile: "+copyErr.Error())
+		return
+	}
+
+	profiles := listPprofProfiles()
+	for _, old := range profiles[min(len(profiles), pprofMaxProfiles):] {
+		os.Remove(filepath.Join(dir, old.ID))
+	}
+	log.Printf("[pprof] Saved %s profile of %s/%s (%d bytes)", profileType, namespace, podName, size)
+	profile, _ := parsePprofProfile(name, size)
+	s.writeJSON(w, profile)
+}
+
+// handleListPprofProfiles lists saved profiles, optionally of one pod
+// GET /api/pprof/profiles?namespace=&pod=
+func (s *Server) handleListPprofProfiles(w http.ResponseWriter, r *http.Request) {
+	namespace, pod := r.URL.Query().Get("namespace"), r.URL.Query().Get("pod")
+	profiles := []PprofProfile{}
+	for _, p := range listPprofProfiles() {
+		if (namespace == "" || p.Namespace == namespace) && (pod == "" || p.Pod == pod) {
+			profiles = append(profiles, p)
+		}
+	}
+	s.writeJSON(w, profiles)
+}
+
+// pprofProfilePath resolves a profile ID to its file, rejecting anything that
+// isn't a saved profile name
+func pprofProfilePath(id string) (string, bool) {
+	if !pprofProfileRe.MatchString(id) {
+		return "", false
+	}
+	return filepath.Join(pprofProfilesDir(), id), true
+}
+
+// handleDownloadPprofProfile downloads a saved profile (open it with go tool pprof)
+// GET /api/pprof/profiles/{id}
+func (s *Server) handleDownloadPprofProfile(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	file, ok := pprofProfilePath(id)
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "profile not found")
+		return
+	}
+	if _, err := os.Stat(file); err != nil {
+		s.writeError(w, http.StatusNotFound, "profile not found")
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", id))
+	http.ServeFile(w, r, file)
+}
+
+// handleDeletePprofProfile removes a saved profile
+// DELETE /api/pprof/profiles/{id}
+func (s *Server) handleDeletePprofProfile(w http.ResponseWriter, r *http.Request) {
+	file, ok := pprofProfilePath(chi.URLParam(r, "id"))
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "profile not found")
+		return
+	}
+	if err := os.Remove(file); err != nil {
+		if os.IsNotExist(err) {
+			s.writeError(w, http.StatusNotFound, "profile not found")
+			return
+		}
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
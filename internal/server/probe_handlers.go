@@ -0,0 +1,124 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// /livez and /readyz follow the Kubernetes API server's health endpoints:
+// plain-text "ok" or a list of failed checks with a 500, ?verbose lists every
+// check, ?exclude=<name> skips one, and /readyz/<name> runs a single check.
+// /api/health stays the UI's (and instance detection's) status endpoint.
+
+// healthCheck is one named liveness or readiness check; check returns nil when it passes
+type healthCheck struct {
+	name  string
+	check func() error
+}
+
+// livezChecks only fail if the process can't serve requests, so a
+// disconnected cluster never gets Radar restarted
+var livezChecks = []healthCheck{
+	{"ping", func() error { return nil }},
+}
+
+// readyzChecks must all pass before Radar can serve cluster data
+var readyzChecks = []healthCheck{
+	{"cluster", func() error {
+		status := k8s.GetConnectionStatus()
+		if status.State == k8s.StateConnected {
+			return nil
+		}
+		if status.Error != "" {
+			return fmt.Errorf("%s: %s", status.State, status.Error)
+		}
+		return fmt.Errorf("%s", status.State)
+	}},
+	{"informers", func() error {
+		if k8s.GetResourceCache() == nil {
+			return fmt.Errorf("resource caches not synced")
+		}
+		return nil
+	}},
+	{"timeline-store", func() error {
+		if timeline.GetStore() == nil {
+			return fmt.Errorf("event store not initialized")
+		}
+		return nil
+	}},
+}
+
+// handleLivez reports whether the process is up
+// GET /livez, /livez/{check}
+func (s *Server) handleLivez(w http.ResponseWriter, r *http.Request) {
+	serveHealthChecks(w, r, "livez", livezChecks)
+}
+
+// handleReadyz reports whether Radar is connected and its caches are synced
+// GET /readyz, /readyz/{check}
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	serveHealthChecks(w, r, "readyz", readyzChecks)
+}
+
+func serveHealthChecks(w http.ResponseWriter, r *http.Request, endpoint string, checks []healthCheck) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+
+	if name := chi.URLParam(r, "check"); name != "" {
+		i := slices.IndexFunc(checks, func(c healthCheck) bool { return c.name == name })
+		if i < 0 {
+			http.Error(w, fmt.Sprintf("unknown %s check %q", endpoint, name), http.StatusNotFound)
+			return
+		}
+		if err := checks[i].check(); err != nil {
+			http.Error(w, fmt.Sprintf("[-]%s failed: %v", name, err), http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, "ok")
+		return
+	}
+
+	query := r.URL.Query()
+	_, verbose := query["verbose"]
+	excluded := query["exclude"]
+
+	var out strings.Builder
+	failed := false
+	for _, c := range checks {
+		if slices.Contains(excluded, c.name) {
+			fmt.Fprintf(&out, "[+]%s excluded: ok\n", c.name)
+			continue
+		}
+		if err := c.check(); err != nil {
+			failed = true
+			// Like Kubernetes, reasons are only shown on request
+			if verbose {
+				fmt.Fprintf(&out, "[-]%s failed: %v\n", c.name, err)
+			} else {
+				fmt.Fprintf(&out, "[-]%s failed: reason withheld\n", c.name)
+			}
+			continue
+		}
+		fmt.Fprintf(&out, "[+]%s ok\n", c.name)
+	}
+
+	if failed {
+		fmt.Fprintf(&out, "%s check failed\n", endpoint)
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, out.String())
+		return
+	}
+	if !verbose {
+		fmt.Fprint(w, "ok")
+		return
+	}
+	fmt.Fprintf(&out, "%s check passed\n", endpoint)
+	fmt.Fprint(w, out.String())
+}
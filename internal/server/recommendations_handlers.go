@@ -0,0 +1,77 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// handleRecommendations returns right-sizing recommendations computed from metrics history
+// GET /api/recommendations?namespaces=a,b&duration=24h
+func (s *Server) handleRecommendations(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	// Recommendations need as much history as possible, so default to the full window
+	window := k8s.MetricsHistoryMaxDuration
+	if raw := r.URL.Query().Get("duration"); raw != "" {
+		d, ok := k8s.ParseMetricsHistoryDuration(raw)
+		if !ok {
+			s.writeError(w, http.StatusBadRequest, "invalid duration: must be 1h, 6h, or 24h")
+			return
+		}
+		window = d
+	}
+
+	s.writeJSON(w, k8s.GetRecommendations(parseNamespaces(r.URL.Query()), window))
+}
+
+// getDashboardRecommendations summarizes potential savings for the dashboard.
+// Returns nil when no metrics history has been collected.
+func (s *Server) getDashboardRecommendations(namespaces []string) *DashboardRecommendations {
+	report := k8s.GetRecommendations(namespaces, k8s.MetricsHistoryMaxDuration)
+	if !report.HasMetrics {
+		return nil
+	}
+
+	summary := &DashboardRecommendations{
+		Workloads:        len(report.Workloads),
+		CPUSavingsCores:  report.TotalCPUSavingsCores,
+		MemorySavingsGiB: report.TotalMemorySavingsGiB,
+		Top:              []DashboardRecommendation{},
+	}
+	for _, wr := range report.Workloads {
+		if len(summary.Top) == 3 {
+			break
+		}
+		if wr.CPUSavingsCores <= 0 && wr.MemorySavingsGiB <= 0 {
+			continue
+		}
+		summary.Top = append(summary.Top, DashboardRecommendation{
+			Kind:             wr.Kind,
+			Namespace:        wr.Namespace,
+			Name:             wr.Name,
+			CPUSavingsCores:  wr.CPUSavingsCores,
+			MemorySavingsGiB: wr.MemorySavingsGiB,
+		})
+	}
+	return summary
+}
+
+// DashboardRecommendations summarizes right-sizing savings on the dashboard
+type DashboardRecommendations struct {
+	Workloads        int                       `json:"workloads"`
+	CPUSavingsCores  float64                   `json:"cpuSavingsCores"`
+	MemorySavingsGiB float64                   `json:"memorySavingsGiB"`
+	Top              []DashboardRecommendation `json:"top"`
+}
+
+// DashboardRecommendation is a workload with the largest potential savings
+type DashboardRecommendation struct {
+	Kind             string  `json:"kind"`
+	Namespace        string  `json:"namespace"`
+	Name             string  `json:"name"`
+	CPUSavingsCores  float64 `json:"cpuSavingsCores"`
+	MemorySavingsGiB float64 `json:"memorySavingsGiB"`
+}
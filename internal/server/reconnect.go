@@ -0,0 +1,113 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+const (
+	minReconnectDelay = 2 * time.Second
+	maxReconnectDelay = 2 * time.Minute
+)
+
+// reconnectMu serializes full reconnects (manual retry, context switch and
+// the automatic retry loop), which all tear down and rebuild the subsystems
+var reconnectMu sync.Mutex
+
+// reconnect stops sessions and reinitializes everything for a context, then
+// publishes the resulting connection state. Callers hold reconnectMu.
+func reconnect(contextName string) error {
+	StopAllSessions()
+	if err := k8s.PerformContextSwitch(contextName); err != nil {
+		k8s.SetConnectionStatus(k8s.ConnectionStatus{
+			State:     k8s.StateDisconnected,
+			Context:   contextName,
+			Error:     err.Error(),
+			ErrorType: k8s.ClassifyError(err),
+		})
+		return err
+	}
+	k8s.SetConnectionStatus(k8s.ConnectionStatus{
+		State:       k8s.StateConnected,
+		Context:     k8s.GetContextName(),
+		ClusterName: k8s.GetClusterName(),
+	})
+	return nil
+}
+
+// autoReconnect retries the connection in the background whenever Radar is
+// disconnected (failed startup preflight, failed retry or context switch),
+// with exponential backoff and jitter. Each attempt first probes the API
+// server cheaply and only reinitializes once it answers. Disconnects the API
+// server monitor reported recover on their own and are left alone.
+func autoReconnect() {
+	wake := make(chan struct{}, 1)
+	k8s.OnConnectionChange(func(status k8s.ConnectionStatus) {
+		if status.State == k8s.StateDisconnected {
+			select {
+			case wake <- struct{}{}:
+			default:
+			}
+		}
+	})
+	if k8s.GetConnectionStatus().State == k8s.StateDisconnected {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+
+	// Spurious wakeups (e.g. from progress updates) find the state changed and wait again
+	for range wake {
+		delay := minReconnectDelay
+		for attempt := 1; ; attempt++ {
+			status := k8s.GetConnectionStatus()
+			if status.State != k8s.StateDisconnected || k8s.RecoveringInPlace() || status.Context == "" {
+				break
+			}
+
+			wait := jitter(delay)
+			k8s.UpdateConnectionProgress(fmt.Sprintf("Reconnecting automatically in %s (attempt %d)", wait.Round(time.Second), attempt))
+			time.Sleep(wait)
+			delay = min(delay*2, maxReconnectDelay)
+
+			// The user may have retried or switched contexts meanwhile
+			status = k8s.GetConnectionStatus()
+			if status.State != k8s.StateDisconnected || k8s.RecoveringInPlace() {
+				break
+			}
+			if k8s.GetClient() != nil {
+				if err := k8s.ProbeAPIServer(); err != nil {
+					log.Printf("[reconnect] %s still unreachable: %v", status.Context, err)
+					continue
+				}
+			}
+			if !reconnectMu.TryLock() {
+				continue // A manual retry or context switch is running
+			}
+			log.Printf("[reconnect] Reconnecting to %s (attempt %d)", status.Context, attempt)
+			k8s.SetConnectionStatus(k8s.ConnectionStatus{
+				State:       k8s.StateConnecting,
+				Context:     status.Context,
+				ProgressMsg: "Cluster reachable again, reconnecting...",
+			})
+			err := reconnect(status.Context)
+			reconnectMu.Unlock()
+			if err == nil {
+				log.Printf("[reconnect] Reconnected to %s", status.Context)
+				break
+			}
+			log.Printf("[reconnect] Reconnect to %s failed: %v", status.Context, err)
+		}
+	}
+}
+
+// jitter spreads d by ±20% so many Radar instances don't retry in lockstep
+func jitter(d time.Duration) time.Duration {
+	return time.Duration(float64(d) * (0.8 + 0.4*rand.Float64()))
+}
@@ -0,0 +1,216 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// Recorded entry types
+const (
+	recordResourceChange = "resource_change"
+	recordTimelineEvent  = "timeline_event"
+)
+
+// recordedEntry is one line of an event stream recording (JSON lines).
+// OffsetMs is the time since recording started, used to pace replay.
+type recordedEntry struct {
+	OffsetMs int64                   `json:"offsetMs"`
+	Type     string                  `json:"type"`
+	Change   *recordedChange         `json:"change,omitempty"`
+	Event    *timeline.TimelineEvent `json:"event,omitempty"`
+}
+
+// recordedChange mirrors k8s.ResourceChange with stable JSON names
+type recordedChange struct {
+	Kind      string        `json:"kind"`
+	Namespace string        `json:"namespace,omitempty"`
+	Name      string        `json:"name"`
+	UID       string        `json:"uid,omitempty"`
+	Operation string        `json:"operation"`
+	Diff      *k8s.DiffInfo `json:"diff,omitempty"`
+}
+
+func (c recordedChange) toResourceChange() k8s.ResourceChange {
+	return k8s.ResourceChange{Kind: c.Kind, Namespace: c.Namespace, Name: c.Name, UID: c.UID, Operation: c.Operation, Diff: c.Diff}
+}
+
+// eventRecorder appends resource changes and timeline events to a file
+type eventRecorder struct {
+	mu    sync.Mutex
+	file  *os.File
+	w     *bufio.Writer
+	enc   *json.Encoder
+	start time.Time
+	count int
+}
+
+func newEventRecorder(path string) (*eventRecorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create recording file: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	return &eventRecorder{file: f, w: w, enc: json.NewEncoder(w), start: time.Now()}, nil
+}
+
+func (r *eventRecorder) recordChange(change k8s.ResourceChange) {
+	r.write(recordedEntry{Type: recordResourceChange, Change: &recordedChange{
+		Kind:      change.Kind,
+		Namespace: change.Namespace,
+		Name:      change.Name,
+		UID:       change.UID,
+		Operation: change.Operation,
+		Diff:      change.Diff,
+	}})
+}
+
+func (r *eventRecorder) recordTimelineEvent(event timeline.TimelineEvent) {
+	r.write(recordedEntry{Type: recordTimelineEvent, Event: &event})
+}
+
+func (r *eventRecorder) write(entry recordedEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return
+	}
+	entry.OffsetMs = time.Since(r.start).Milliseconds()
+	if err := r.enc.Encode(entry); err != nil {
+		log.Printf("[record] Failed to write %s: %v", entry.Type, err)
+		return
+	}
+	r.count++
+	// Flush every entry so a crash or kill still leaves a usable recording
+	if err := r.w.Flush(); err != nil {
+		log.Printf("[record] Failed to flush recording: %v", err)
+	}
+}
+
+// Close flushes and closes the recording file
+func (r *eventRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.w.Flush()
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	log.Printf("[record] Recorded %d events to %s", r.count, r.file.Name())
+	r.file = nil
+	return err
+}
+
+// readRecording loads all entries from a recording file
+func readRecording(path string) ([]recordedEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open recording: %w", err)
+	}
+	defer f.Close()
+
+	var entries []recordedEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry recordedEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("recording line %d: %w", line, err)
+		}
+		switch {
+		case entry.Type == recordResourceChange && entry.Change != nil:
+		case entry.Type == recordTimelineEvent && entry.Event != nil:
+		default:
+			return nil, fmt.Errorf("recording line %d: invalid entry of type %q", line, entry.Type)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read recording: %w", err)
+	}
+	return entries, nil
+}
+
+// replayEntries feeds recorded entries back through the broadcaster, keeping
+// their original spacing divided by speed. A speed of 0 replays without delays.
+func (b *SSEBroadcaster) replayEntries(entries []recordedEntry, speed float64) {
+	start := time.Now()
+	for _, entry := range entries {
+		if speed > 0 {
+			due := start.Add(time.Duration(float64(entry.OffsetMs) * float64(time.Millisecond) / speed))
+			if wait := time.Until(due); wait > 0 {
+				select {
+				case <-b.stopCh:
+					return
+				case <-time.After(wait):
+				}
+			}
+		}
+
+		switch entry.Type {
+		case recordResourceChange:
+			b.broadcastResourceChange(entry.Change.toResourceChange())
+		case recordTimelineEvent:
+			b.broadcastTimelineEvent(*entry.Event)
+		}
+	}
+}
+
+// EnableRecording captures every resource change and timeline event seen by
+// the broadcaster to a JSON lines file at path. Must be called before Start.
+func (b *SSEBroadcaster) EnableRecording(path string) error {
+	recorder, err := newEventRecorder(path)
+	if err != nil {
+		return err
+	}
+	b.recorder = recorder
+	log.Printf("[record] Recording event stream to %s", path)
+	return nil
+}
+
+// EnableReplay loads a recording made with EnableRecording and replays it to
+// clients in place of live resource changes and timeline events. Replay starts
+// when the first SSE client connects. Must be called before Start.
+func (b *SSEBroadcaster) EnableReplay(path string, speed float64) error {
+	if speed < 0 {
+		return fmt.Errorf("replay speed must not be negative, got %g", speed)
+	}
+	entries, err := readRecording(path)
+	if err != nil {
+		return err
+	}
+	b.replayLog = entries
+	b.replaySpeed = speed
+	b.replaying = true
+	log.Printf("[replay] Loaded %d events from %s", len(entries), path)
+	return nil
+}
+
+// replay waits for the first SSE client, then replays the loaded recording
+func (b *SSEBroadcaster) replay() {
+	select {
+	case <-b.stopCh:
+		return
+	case <-b.firstClient:
+	}
+
+	if b.replaySpeed > 0 {
+		log.Printf("[replay] Replaying %d events at %gx speed", len(b.replayLog), b.replaySpeed)
+	} else {
+		log.Printf("[replay] Replaying %d events without delays", len(b.replayLog))
+	}
+	b.replayEntries(b.replayLog, b.replaySpeed)
+	log.Printf("[replay] Replay finished")
+}
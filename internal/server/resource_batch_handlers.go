@@ -0,0 +1,86 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/topology"
+)
+
+// maxBatchGetItems bounds one batch-get request; detail panes need a few dozen
+const maxBatchGetItems = 200
+
+// BatchGetItem identifies one resource to get
+type BatchGetItem struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"` // Empty or "_" for cluster-scoped resources
+	Name      string `json:"name"`
+	Group     string `json:"group,omitempty"` // API group for CRD disambiguation
+}
+
+// BatchGetRequest is the body of POST /api/resources/batch-get
+type BatchGetRequest struct {
+	Items []BatchGetItem `json:"items"`
+}
+
+// BatchGetResult is one resource of a batch get. A failed item carries the
+// status and error the single-resource GET would have returned.
+type BatchGetResult struct {
+	BatchGetItem
+	Resource      any                     `json:"resource,omitempty"`
+	Relationships *topology.Relationships `json:"relationships,omitempty"`
+	Status        int                     `json:"status"`
+	Error         string                  `json:"error,omitempty"`
+}
+
+// handleBatchGetResources gets several resources in one round trip. Results
+// are in request order, and one failed item doesn't fail the others.
+// POST /api/resources/batch-get
+func (s *Server) handleBatchGetResources(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	var req BatchGetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.Items) > maxBatchGetItems {
+		s.writeError(w, http.StatusBadRequest, fmt.Sprintf("too many items: %d (max %d)", len(req.Items), maxBatchGetItems))
+		return
+	}
+
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Resource cache not available")
+		return
+	}
+
+	results := make([]BatchGetResult, len(req.Items))
+	for i, item := range req.Items {
+		if item.Namespace == "_" {
+			item.Namespace = ""
+		}
+		results[i].BatchGetItem = item
+		if item.Kind == "" || item.Name == "" {
+			results[i].Status = http.StatusBadRequest
+			results[i].Error = "kind and name are required"
+			continue
+		}
+
+		kind := normalizeKind(item.Kind)
+		resource, status, err := lookupResource(r.Context(), cache, kind, item.Namespace, item.Name, item.Group)
+		results[i].Status = status
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		results[i].Resource = resource
+		results[i].Relationships = s.resourceRelationships(kind, item.Namespace, item.Name)
+	}
+
+	s.writeJSON(w, map[string]any{"items": results})
+}
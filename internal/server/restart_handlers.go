@@ -0,0 +1,133 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/traffic"
+)
+
+// restartTrafficWindow is how far back traffic flows are used to find dependencies
+const restartTrafficWindow = time.Hour
+
+// planBulkRestart decodes a bulk restart request and orders its targets,
+// writing an error response on failure
+func (s *Server) planBulkRestart(w http.ResponseWriter, r *http.Request) (k8s.BulkRestartOptions, *k8s.RestartPlan, bool) {
+	var req k8s.BulkRestartOptions
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return req, nil, false
+	}
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "not connected to a cluster")
+		return req, nil, false
+	}
+
+	var trafficNote string
+	if req.Ordered {
+		req.Calls, trafficNote = restartTrafficCalls(r.Context(), req.Items)
+	}
+	plan, err := cache.PlanRestarts(req)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return req, nil, false
+	}
+	if trafficNote != "" {
+		plan.Notes = append(plan.Notes, trafficNote)
+	}
+	return req, plan, true
+}
+
+// restartTrafficCalls returns the calls between workloads in the targets'
+// namespaces seen by the active traffic source. Without one, ordering only
+// uses service references, which the returned note says.
+func restartTrafficCalls(ctx context.Context, targets []k8s.BulkTarget) ([]k8s.ServiceCall, string) {
+	manager := traffic.GetManager()
+	if manager == nil || manager.GetActiveSourceName() == "" {
+		return nil, "No traffic source: dependencies are derived from service references only"
+	}
+
+	seen := make(map[string]bool)
+	opts := traffic.DefaultFlowOptions()
+	opts.Since = restartTrafficWindow
+	opts.Limit = 0
+	for _, t := range targets {
+		if !seen[t.Namespace] {
+			seen[t.Namespace] = true
+			opts.Namespaces = append(opts.Namespaces, t.Namespace)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	resp, err := manager.GetFlows(ctx, opts)
+	if err != nil {
+		log.Printf("[restart] Failed to get traffic flows: %v", err)
+		return nil, "Traffic flows unavailable: dependencies are derived from service references only"
+	}
+
+	var calls []k8s.ServiceCall
+	for _, f := range resp.Flows {
+		if f.Source.Workload == "" {
+			continue
+		}
+		call := k8s.ServiceCall{FromNamespace: f.Source.Namespace, FromWorkload: f.Source.Workload, ToNamespace: f.Destination.Namespace, ToName: f.Destination.Workload}
+		if call.ToName == "" {
+			call.ToName = f.Destination.Name // A Service
+		}
+		calls = append(calls, call)
+	}
+	return calls, ""
+}
+
+// handleBulkRestartPlan returns the order a bulk restart would use
+// POST /api/workloads/restart/plan
+func (s *Server) handleBulkRestartPlan(w http.ResponseWriter, r *http.Request) {
+	_, plan, ok := s.planBulkRestart(w, r)
+	if !ok {
+		return
+	}
+	s.writeJSON(w, plan)
+}
+
+// handleBulkRestart restarts several workloads and streams progress via SSE:
+// a "plan" event, "progress" events per workload, and a final "complete"
+// event with the per-workload results
+// POST /api/workloads/restart
+func (s *Server) handleBulkRestart(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	req, plan, ok := s.planBulkRestart(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.writeError(w, http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	sendSSEEvent(w, flusher, "plan", plan)
+	result, err := k8s.RestartWorkloads(r.Context(), plan, req, func(p k8s.RestartProgress) {
+		sendSSEEvent(w, flusher, "progress", p)
+	})
+	if err != nil {
+		log.Printf("[restart] Bulk restart stopped: %v", err)
+		sendSSEError(w, flusher, err.Error())
+		return
+	}
+	log.Printf("[restart] Restarted %d/%d workloads in %d waves", result.Succeeded, result.Total, len(plan.Waves))
+	sendSSEEvent(w, flusher, "complete", result)
+}
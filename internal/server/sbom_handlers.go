@@ -0,0 +1,203 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/skyhook-io/radar/internal/images"
+	"github.com/skyhook-io/radar/internal/outbound"
+	"github.com/skyhook-io/radar/internal/version"
+)
+
+const (
+	sbomTimeout     = 5 * time.Minute // Image layers are downloaded on first use
+	sbomExecTimeout = 30 * time.Second
+)
+
+// sbomContainer is one container image of the workload
+type sbomContainer struct {
+	name    string
+	image   string // Pinned to the running digest when known
+	running bool   // Can be exec'd into
+}
+
+// handleWorkloadSBOM generates an SBOM for a workload's images from their
+// package databases, optionally refined by querying the package managers in
+// a running pod (exec=true). Answered as a file download.
+// GET /api/workloads/{kind}/{namespace}/{name}/sbom?format=spdx|cyclonedx&exec=true
+func (s *Server) handleWorkloadSBOM(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	kind := strings.ToLower(chi.URLParam(r, "kind"))
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+	format, err := images.ParseSBOMFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	useExec := r.URL.Query().Get("exec") == "true"
+	if outbound.Offline() && !useExec {
+		s.writeError(w, http.StatusBadRequest, "image layers can't be fetched in offline mode; add exec=true to query the running containers")
+		return
+	}
+
+	pods, werr := s.getWorkloadPods(kind, namespace, name)
+	if werr != nil {
+		s.writeWorkloadError(w, werr)
+		return
+	}
+	pod := pickSBOMPod(pods)
+	if pod == nil {
+		s.writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s/%s has no pods to build an SBOM from", kind, namespace, name))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), sbomTimeout)
+	defer cancel()
+
+	containers := sbomContainers(pod)
+	results := make([]*images.ImagePackages, len(containers))
+	warnings := make([][]string, len(containers))
+	pullSecrets := images.GetPullSecretsFromPod(namespace, pod.Name)
+	var wg sync.WaitGroup
+	for i, c := range containers {
+		wg.Add(1)
+		go func(i int, c sbomContainer) {
+			defer wg.Done()
+			results[i], warnings[i] = s.containerPackages(ctx, namespace, pod.Name, c, pullSecrets, useExec)
+		}(i, c)
+	}
+	wg.Wait()
+
+	subject := images.SBOMSubject{
+		Name:        fmt.Sprintf("%s/%s/%s", kind, namespace, name),
+		Created:     time.Now(),
+		ToolVersion: version.Current,
+	}
+	for i := range containers {
+		subject.Warnings = append(subject.Warnings, warnings[i]...)
+		if results[i] != nil {
+			subject.Images = append(subject.Images, results[i])
+		}
+	}
+	if len(subject.Images) == 0 {
+		s.writeError(w, http.StatusBadGateway, "no image could be read: "+strings.Join(subject.Warnings, "; "))
+		return
+	}
+
+	ext, contentType := "spdx.json", "application/spdx+json"
+	if format == images.SBOMFormatCycloneDX {
+		ext, contentType = "cdx.json", "application/vnd.cyclonedx+json"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("%s-%s-%s.%s", kind, namespace, name, ext)))
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(subject.Document(format)); err != nil {
+		log.Printf("[sbom] Failed to encode SBOM for %s: %v", subject.Name, err)
+	}
+}
+
+// containerPackages reads a container image's packages from its layers and,
+// with useExec, from the running container, which wins when it answers (it
+// also sees RPM databases and packages installed at runtime)
+func (s *Server) containerPackages(ctx context.Context, namespace, podName string, c sbomContainer, pullSecrets []string, useExec bool) (*images.ImagePackages, []string) {
+	var warnings []string
+	var result *images.ImagePackages
+	if s.imageInspector != nil && !outbound.Offline() {
+		pkgs, err := s.imageInspector.Packages(ctx, images.InspectRequest{
+			Image:           c.image,
+			Namespace:       namespace,
+			PodName:         podName,
+			PullSecretNames: pullSecrets,
+		})
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: image %s not inspected: %v", c.name, c.image, err))
+		} else {
+			result = pkgs
+		}
+	}
+	if !useExec || !c.running {
+		if result != nil {
+			for _, msg := range result.Warnings {
+				warnings = append(warnings, c.name+": "+msg)
+			}
+		}
+		return result, warnings
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, sbomExecTimeout)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	err := s.execInPod(execCtx, namespace, podName, c.name, []string{"sh", "-c", images.PackageQueryScript}, nil, &stdout, &stderr)
+	pkgs, osRelease := images.ParsePackageQuery(stdout.Bytes())
+	switch {
+	case err != nil:
+		warnings = append(warnings, fmt.Sprintf("%s: package query in the container failed: %v", c.name, err))
+	case len(pkgs) == 0:
+		warnings = append(warnings, fmt.Sprintf("%s: no package manager found in the container", c.name))
+	default:
+		if result == nil {
+			result = &images.ImagePackages{Image: c.image}
+		}
+		result.Packages = pkgs
+		result.Source = images.PackageSourceExec
+		result.Warnings = nil
+		if osRelease.ID != "" {
+			result.OS = osRelease
+		}
+	}
+	if result != nil {
+		for _, msg := range result.Warnings {
+			warnings = append(warnings, c.name+": "+msg)
+		}
+	}
+	return result, warnings
+}
+
+// pickSBOMPod prefers a running pod, so containers can be exec'd into
+func pickSBOMPod(pods []*corev1.Pod) *corev1.Pod {
+	for _, p := range pods {
+		if p.Status.Phase == corev1.PodRunning && p.DeletionTimestamp == nil {
+			return p
+		}
+	}
+	if len(pods) > 0 {
+		return pods[0]
+	}
+	return nil
+}
+
+// sbomContainers lists a pod's app and init containers, one per image,
+// pinned to the digests the kubelet reports
+func sbomContainers(pod *corev1.Pod) []sbomContainer {
+	statuses := make(map[string]corev1.ContainerStatus)
+	for _, cs := range append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+		statuses[cs.Name] = cs
+	}
+	seen := make(map[string]bool)
+	var result []sbomContainer
+	// App containers first, so an image shared with an init container is queried where it runs
+	for _, c := range append(append([]corev1.Container{}, pod.Spec.Containers...), pod.Spec.InitContainers...) {
+		cs := statuses[c.Name]
+		image := images.PinnedRef(c.Image, cs.ImageID)
+		if seen[image] {
+			continue
+		}
+		seen[image] = true
+		result = append(result, sbomContainer{name: c.Name, image: image, running: cs.State.Running != nil})
+	}
+	return result
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/schedules"
+)
+
+// ScheduleResponse is a scale schedule after a change, with any error
+// scaling its workloads right away (the scheduler retries every minute)
+type ScheduleResponse struct {
+	schedules.Status
+	Warning string `json:"warning,omitempty"`
+}
+
+// handleListSchedules returns the scale-to-zero schedules of every context
+// GET /api/schedules
+func (s *Server) handleListSchedules(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, schedules.List(time.Now()))
+}
+
+// handleGetSchedule returns one scale schedule
+// GET /api/schedules/{name}
+func (s *Server) handleGetSchedule(w http.ResponseWriter, r *http.Request) {
+	status, err := schedules.Get(chi.URLParam(r, "name"), time.Now())
+	if err != nil {
+		s.writeScheduleError(w, err)
+		return
+	}
+	s.writeJSON(w, status)
+}
+
+// handleSaveSchedule creates or replaces a scale schedule. The context
+// defaults to the connected one. It takes effect right away: workloads are
+// scaled down if the schedule is in its sleep window.
+// PUT /api/schedules/{name}
+func (s *Server) handleSaveSchedule(w http.ResponseWriter, r *http.Request) {
+	var schedule schedules.Schedule
+	if err := json.NewDecoder(r.Body).Decode(&schedule); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	schedule.Name = chi.URLParam(r, "name")
+	if schedule.Context == "" {
+		schedule.Context = k8s.GetContextName()
+	}
+
+	saved, previous, err := schedules.Save(schedule)
+	if err != nil {
+		s.writeScheduleError(w, err)
+		return
+	}
+	var warning error
+	if previous != nil {
+		warning = schedules.Retarget(r.Context(), *previous, saved)
+	}
+	s.writeScheduleResult(w, r, saved.Name, warning)
+}
+
+// handleDeleteSchedule removes a scale schedule, scaling its workloads back
+// up if it was asleep
+// DELETE /api/schedules/{name}
+func (s *Server) handleDeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	deleted, err := schedules.Delete(chi.URLParam(r, "name"))
+	if err != nil {
+		s.writeScheduleError(w, err)
+		return
+	}
+	resp := map[string]string{"status": "deleted"}
+	if err := schedules.Wake(r.Context(), deleted); err != nil {
+		resp["warning"] = err.Error()
+	}
+	s.writeJSON(w, resp)
+}
+
+// handleScheduleOverride scales a schedule's workloads back up and keeps them
+// up until ?until=RFC3339, ?hours=N, or by default through the current (or
+// next) sleep window
+// POST /api/schedules/{name}/override
+func (s *Server) handleScheduleOverride(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	now := time.Now()
+	until := &time.Time{}
+	if v := r.URL.Query().Get("until"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "until must be an RFC3339 time")
+			return
+		}
+		until = &t
+	} else if v := r.URL.Query().Get("hours"); v != "" {
+		hours, err := strconv.ParseFloat(v, 64)
+		if err != nil || hours <= 0 {
+			s.writeError(w, http.StatusBadRequest, "hours must be a positive number")
+			return
+		}
+		t := now.Add(time.Duration(hours * float64(time.Hour)))
+		until = &t
+	}
+	if _, err := schedules.SetOverride(name, until, now); err != nil {
+		s.writeScheduleError(w, err)
+		return
+	}
+	s.writeScheduleResult(w, r, name, nil)
+}
+
+// handleEndScheduleOverride ends an override, so the schedule applies again
+// DELETE /api/schedules/{name}/override
+func (s *Server) handleEndScheduleOverride(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if _, err := schedules.SetOverride(name, nil, time.Now()); err != nil {
+		s.writeScheduleError(w, err)
+		return
+	}
+	s.writeScheduleResult(w, r, name, nil)
+}
+
+// writeScheduleResult applies a changed schedule now and returns its state
+func (s *Server) writeScheduleResult(w http.ResponseWriter, r *http.Request, name string, warning error) {
+	schedules.Reconcile(r.Context(), time.Now())
+	status, err := schedules.Get(name, time.Now())
+	if err != nil {
+		s.writeScheduleError(w, err)
+		return
+	}
+	resp := ScheduleResponse{Status: status, Warning: status.LastError}
+	if warning != nil {
+		resp.Warning = warning.Error()
+	}
+	s.writeJSON(w, resp)
+}
+
+func (s *Server) writeScheduleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, schedules.ErrNotFound):
+		s.writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, schedules.ErrInvalidSchedule):
+		s.writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		log.Printf("[schedules] %v", err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// handleSearch searches across all cached resources (typed and dynamic).
+// Query params: q (required), namespaces, kinds (comma-separated Kind names), limit.
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		s.writeError(w, http.StatusBadRequest, "q query parameter is required")
+		return
+	}
+
+	// Parse limit (default 50)
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if n, err := fmt.Sscanf(limitStr, "%d", &limit); err != nil || n != 1 || limit <= 0 {
+			s.writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		if limit > 500 {
+			limit = 500
+		}
+	}
+
+	var kinds []string
+	if k := r.URL.Query().Get("kinds"); k != "" {
+		for _, part := range strings.Split(k, ",") {
+			if trimmed := strings.TrimSpace(part); trimmed != "" {
+				kinds = append(kinds, trimmed)
+			}
+		}
+	}
+
+	s.writeJSON(w, k8s.SearchResources(k8s.SearchOptions{
+		Query:      query,
+		Namespaces: parseNamespaces(r.URL.Query()),
+		Kinds:      kinds,
+		Limit:      limit,
+	}))
+}
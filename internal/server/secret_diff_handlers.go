@@ -0,0 +1,38 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// handleSecretChanges returns the recent data changes of a Secret. Timeline
+// and SSE diffs only name the changed keys; values are returned here, and
+// only with ?reveal=true.
+// GET /api/secrets/{namespace}/{name}/changes?reveal=true
+func (s *Server) handleSecretChanges(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+	reveal := r.URL.Query().Get("reveal") == "true"
+	changes, err := k8s.GetSecretValueChanges(namespace, name, reveal)
+	if errors.Is(err, k8s.ErrSecretsNotAccessible) {
+		s.writeError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		s.writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	if reveal {
+		log.Printf("[secrets] %s revealed value changes of secret %s/%s", requestActor(r), namespace, name)
+	}
+	s.writeJSON(w, changes)
+}
@@ -1,8 +1,10 @@
 package server
 
 import (
+	"context"
 	"embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -11,25 +13,34 @@ import (
 	"net/http"
 	"net/http/pprof"
 	"net/url"
+	"os"
 	"reflect"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/cors"
+	"github.com/gorilla/websocket"
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 
+	"github.com/skyhook-io/radar/internal/agent"
+	"github.com/skyhook-io/radar/internal/apierror"
 	"github.com/skyhook-io/radar/internal/helm"
 	"github.com/skyhook-io/radar/internal/images"
+	"github.com/skyhook-io/radar/internal/instances"
 	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/settings"
 	"github.com/skyhook-io/radar/internal/timeline"
 	"github.com/skyhook-io/radar/internal/topology"
 	"github.com/skyhook-io/radar/internal/updater"
@@ -38,32 +49,54 @@ import (
 
 // Server is the Explorer HTTP server
 type Server struct {
-	router      *chi.Mux
-	broadcaster *SSEBroadcaster
-	port        int
-	devMode     bool
-	staticFS    fs.FS
-	startTime   time.Time
-	listener    net.Listener
-	updater     *updater.Updater
+	router         *chi.Mux
+	broadcaster    *SSEBroadcaster
+	port           int
+	devMode        bool
+	staticFS       fs.FS
+	startTime      time.Time
+	listener       net.Listener
+	updater        *updater.Updater
+	agents         *agent.Hub // Remote cluster agents, nil unless ServeAgents was called
+	imageInspector *images.Inspector
+	maxEditSize    int64 // Largest pod file the file editor opens or saves
+	sessionLimits  SessionLimits
+	origins        *originPolicy
+	upgrader       websocket.Upgrader // Terminals; CheckOrigin is the origin policy's
+	userHeader     string
 }
 
 // Config holds server configuration
 type Config struct {
 	Port       int
-	DevMode    bool     // Serve frontend from filesystem instead of embedded
-	StaticFS   embed.FS // Embedded frontend files
-	StaticRoot string   // Path within StaticFS
+	Listener   net.Listener // Already bound listener to serve on; Port is ignored when set
+	DevMode    bool         // Serve frontend from filesystem instead of embedded
+	StaticFS   embed.FS     // Embedded frontend files
+	StaticRoot string       // Path within StaticFS
+
+	MaxFileEditSize int64         // Largest pod file the file editor opens or saves, in bytes (0 = 2 MiB)
+	SessionLimits   SessionLimits // Limits and idle timeouts for exec sessions and port forwards
+	AllowedOrigins  []string      // Extra origins (scheme://host[:port]) allowed to call the API from a browser
+	UserHeader      string        // Header an auth proxy sets to the authenticated user, see identity.go
 }
 
 // New creates a new server instance
 func New(cfg Config) *Server {
 	s := &Server{
-		router:      chi.NewRouter(),
-		broadcaster: NewSSEBroadcaster(),
-		port:        cfg.Port,
-		devMode:     cfg.DevMode,
-		startTime:   time.Now(),
+		router:        chi.NewRouter(),
+		broadcaster:   NewSSEBroadcaster(),
+		port:          cfg.Port,
+		listener:      cfg.Listener,
+		devMode:       cfg.DevMode,
+		startTime:     time.Now(),
+		maxEditSize:   cfg.MaxFileEditSize,
+		sessionLimits: cfg.SessionLimits,
+		origins:       newOriginPolicy(cfg.AllowedOrigins, cfg.DevMode),
+		userHeader:    cfg.UserHeader,
+	}
+	s.upgrader.CheckOrigin = s.origins.checkWebSocketOrigin
+	if s.maxEditSize <= 0 {
+		s.maxEditSize = defaultMaxEditSize
 	}
 
 	// Set up static file system
@@ -74,6 +107,11 @@ func New(cfg Config) *Server {
 		}
 	}
 
+	if cfg.SessionLimits.PortForwardIdleTimeout > 0 {
+		go reapIdlePortForwards(cfg.SessionLimits.PortForwardIdleTimeout)
+	}
+	go autoReconnect()
+
 	s.setupRoutes()
 	return s
 }
@@ -86,13 +124,8 @@ func (s *Server) setupRoutes() {
 	r.Use(middleware.Recoverer)
 	// Note: Timeout middleware is applied per-group below to exempt streaming endpoints
 
-	// CORS for development
-	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"http://localhost:*", "http://127.0.0.1:*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Content-Type"},
-		AllowCredentials: true,
-	}))
+	// CORS for --allowed-origins (and the Vite dev server in dev mode), see origin.go
+	r.Use(s.origins.cors())
 
 	// pprof routes for profiling (dev mode only, but always available for debugging)
 	r.Route("/debug/pprof", func(r chi.Router) {
@@ -109,34 +142,96 @@ func (s *Server) setupRoutes() {
 		r.Get("/threadcreate", pprof.Handler("threadcreate").ServeHTTP)
 	})
 
+	// Kubernetes-style probes for in-cluster deployments, see probe_handlers.go
+	r.Get("/livez", s.handleLivez)
+	r.Get("/livez/{check}", s.handleLivez)
+	r.Get("/readyz", s.handleReadyz)
+	r.Get("/readyz/{check}", s.handleReadyz)
+
 	// API routes
 	r.Route("/api", func(r chi.Router) {
+		r.Use(s.origins.guard)
+		r.Use(s.identify)
+		r.Use(s.readOnlyGuard)
+
 		// Streaming endpoints (SSE/WebSocket) - no timeout
 		r.Get("/events/stream", s.broadcaster.HandleSSE)
 		r.Get("/pods/{namespace}/{name}/logs/stream", s.handlePodLogsStream)
 		r.Get("/pods/{namespace}/{name}/exec", s.handlePodExec)
+		r.Get("/exec/{id}/observe", s.handleExecObserve)
 		r.Get("/workloads/{kind}/{namespace}/{name}/logs/stream", s.handleWorkloadLogsStream)
+		r.Get("/argo/workflows/{namespace}/{name}/nodes/{nodeId}/logs/stream", s.handleWorkflowStepLogsStream)
+		// Pod-to-pod copy runs as long as the transfer takes
+		r.Post("/pods/{namespace}/{name}/filesystem/copy", s.handlePodFilesystemCopy)
+		// SBOMs may download whole images (own timeout)
+		r.Get("/workloads/{kind}/{namespace}/{name}/sbom", s.handleWorkloadSBOM)
+		r.Get("/uploads/{id}/events", s.handleUploadEvents)
+		// pprof profiles and traces run for ?seconds
+		r.Get("/pods/{namespace}/{name}/pprof/{port}", s.handlePprofIndexRedirect)
+		r.Get("/pods/{namespace}/{name}/pprof/{port}/*", s.handlePprofProxy)
+		r.Post("/pods/{namespace}/{name}/pprof/capture", s.handlePprofCapture)
+		// Heap dumps of large JVMs take minutes to write
+		r.Post("/pods/{namespace}/{name}/java/heap-dump", s.handleJavaHeapDump)
+		// Ordered bulk restarts wait for each wave to roll out
+		r.Post("/workloads/restart", s.handleBulkRestart)
 
 		// All other API routes get a 60-second timeout
 		r.Group(func(r chi.Router) {
 			r.Use(middleware.Timeout(60 * time.Second))
 
 			r.Get("/health", s.handleHealth)
+			r.Get("/instances", s.handleListInstances)
+			r.Get("/config", s.handleGetConfig)
+			r.Put("/config", s.handleUpdateConfig)
 			r.Get("/version-check", s.handleVersionCheck)
 			r.Get("/dashboard", s.handleDashboard)
 			r.Get("/dashboard/crds", s.handleDashboardCRDs)
 			r.Get("/cluster-info", s.handleClusterInfo)
 			r.Get("/capabilities", s.handleCapabilities)
 			r.Get("/topology", s.handleTopology)
+			r.Get("/topology/neighborhood/{kind}/{namespace}/{name}", s.handleTopologyNeighborhood)
 			r.Get("/namespaces", s.handleNamespaces)
+			r.Get("/namespaces/{namespace}/compare", s.handleCompareNamespaces)
 			r.Get("/api-resources", s.handleAPIResources)
+			r.Post("/api-resources/watch", s.handleWatchAPIResource(true))
+			r.Post("/api-resources/unwatch", s.handleWatchAPIResource(false))
+			r.Get("/search", s.handleSearch)
+			r.Post("/resources/bulk/metadata", s.handleBulkEditMetadata)
+			r.Post("/resources/batch-get", s.handleBatchGetResources)
 			r.Get("/resources/{kind}", s.handleListResources)
 			r.Get("/resources/{kind}/{namespace}/{name}", s.handleGetResource)
+			r.Get("/resources/{kind}/{namespace}/{name}/owners", s.handleOwnerChain)
+			r.Get("/resources/{kind}/{namespace}/{name}/delete-preview", s.handleDeletePreview)
 			r.Put("/resources/{kind}/{namespace}/{name}", s.handleUpdateResource)
+			r.Patch("/resources/{kind}/{namespace}/{name}", s.handlePatchResource)
 			r.Delete("/resources/{kind}/{namespace}/{name}", s.handleDeleteResource)
 			r.Get("/events", s.handleEvents)
 			r.Get("/changes", s.handleChanges)
+			r.Get("/changes/aggregate", s.handleChangesAggregate)
 			r.Get("/changes/{kind}/{namespace}/{name}/children", s.handleChangeChildren)
+			r.Get("/timeline/presets", s.handleListFilterPresets)
+			r.Put("/timeline/presets/{name}", s.handleSaveFilterPreset)
+			r.Delete("/timeline/presets/{name}", s.handleDeleteFilterPreset)
+			r.Get("/timeline/queries", s.handleListSavedQueries)
+			r.Put("/timeline/queries/{name}", s.handleSaveSavedQuery)
+			r.Delete("/timeline/queries/{name}", s.handleDeleteSavedQuery)
+			r.Get("/timeline/queries/{name}/digest", s.handleSavedQueryDigest)
+			r.Post("/timeline/queries/{name}/digest", s.handleSendSavedQueryDigest)
+			r.Get("/schedules", s.handleListSchedules)
+			r.Get("/schedules/{name}", s.handleGetSchedule)
+			r.Put("/schedules/{name}", s.handleSaveSchedule)
+			r.Delete("/schedules/{name}", s.handleDeleteSchedule)
+			r.Post("/schedules/{name}/override", s.handleScheduleOverride)
+			r.Delete("/schedules/{name}/override", s.handleEndScheduleOverride)
+
+			// Remote clusters reporting through agents (--agent-listen)
+			r.Get("/agents", s.handleListAgentClusters)
+			r.Get("/agents/{cluster}/topology", s.handleAgentTopology)
+			r.Get("/agents/{cluster}/timeline", s.handleAgentTimeline)
+			r.Get("/agents/{cluster}/changes", s.handleAgentChanges)
+			r.Get("/fleet", s.handleFleetDashboard)
+			r.Get("/fleet/search", s.handleFleetSearch)
+			r.Get("/fleet/clusters/{cluster}", s.handleFleetCluster)
 
 			// Pod logs (non-streaming)
 			r.Get("/pods/{namespace}/{name}/logs", s.handlePodLogs)
@@ -144,22 +239,84 @@ func (s *Server) setupRoutes() {
 			r.Get("/pods/{namespace}/{name}/filesystem/search", s.handlePodFilesystemSearch)
 			r.Get("/pods/{namespace}/{name}/filesystem/file", s.handlePodFilesystemDownload)
 			r.Put("/pods/{namespace}/{name}/filesystem/file", s.handlePodFilesystemSave)
+			r.Post("/pods/{namespace}/{name}/filesystem/file", s.handlePodFilesystemCreate)
+			r.Get("/pods/{namespace}/{name}/filesystem/file/info", s.handlePodFilesystemFileInfo)
 			r.Get("/pods/{namespace}/{name}/filesystem/archive", s.handlePodFilesystemArchive)
 			r.Post("/pods/{namespace}/{name}/filesystem/upload", s.handlePodFilesystemUpload)
+			r.Post("/pods/{namespace}/{name}/filesystem/uploads", s.handleStartUpload)
+			r.Get("/uploads/{id}", s.handleGetUpload)
+			r.Put("/uploads/{id}", s.handleUploadChunk)
+			r.Delete("/uploads/{id}", s.handleCancelUpload)
 			r.Post("/pods/{namespace}/{name}/filesystem/mkdir", s.handlePodFilesystemMkdir)
 			r.Post("/pods/{namespace}/{name}/filesystem/rename", s.handlePodFilesystemRename)
 			r.Post("/pods/{namespace}/{name}/filesystem/delete", s.handlePodFilesystemDelete)
 
+			// One-shot command (no TTY)
+			r.Post("/pods/{namespace}/{name}/run", s.handlePodRun)
+
+			// Java diagnostics (thread dumps into the pod filesystem)
+			r.Get("/pods/{namespace}/{name}/java", s.handleJavaDetect)
+			r.Post("/pods/{namespace}/{name}/java/thread-dump", s.handleJavaThreadDump)
+
+			// Go pprof (saved profiles are Radar-local)
+			r.Get("/pods/{namespace}/{name}/pprof", s.handlePprofDetect)
+			r.Get("/pprof/profiles", s.handleListPprofProfiles)
+			r.Get("/pprof/profiles/{id}", s.handleDownloadPprofProfile)
+			r.Delete("/pprof/profiles/{id}", s.handleDeletePprofProfile)
+
+			// Runtime log levels (Spring Boot actuator, zap), opted in via pod annotations
+			r.Get("/pods/{namespace}/{name}/log-level", s.handleGetPodLogLevel)
+			r.Put("/pods/{namespace}/{name}/log-level", s.handleSetPodLogLevel)
+			r.Get("/workloads/{kind}/{namespace}/{name}/log-level", s.handleGetWorkloadLogLevel)
+			r.Put("/workloads/{kind}/{namespace}/{name}/log-level", s.handleSetWorkloadLogLevel)
+
 			// Pod debug (ephemeral container)
 			r.Post("/pods/{namespace}/{name}/debug", s.handleCreateDebugContainer)
 
+			// Pod startup timing
+			r.Get("/pods/{namespace}/{name}/lifecycle", s.handlePodLifecycle)
+			r.Get("/pods/{namespace}/{name}/config", s.handlePodConfig)
+			r.Get("/secrets/{namespace}/{name}/changes", s.handleSecretChanges)
+			r.Get("/pods/{namespace}/{name}/tokens", s.handlePodTokens)
+			r.Get("/serviceaccount-tokens", s.handleServiceAccountTokens)
+			r.Get("/lifecycle/startup", s.handleWorkloadStartup)
+
+			// Connectivity probe (exec tcp/http/dns check from a pod)
+			r.Post("/nettest", s.handleNetTest)
+			r.Get("/services/{namespace}/{name}/diagnose", s.handleDiagnoseService)
+			r.Post("/admission/simulate", s.handleSimulateAdmission)
+			r.Get("/policy-violations", s.handlePolicyViolations)
+			r.Get("/keda/scalers", s.handleKEDAScalers)
+			r.Get("/capi/clusters", s.handleCAPIClusters)
+			r.Post("/capi/clusters/{namespace}/{name}/context", s.handleCAPIClusterContext)
+			r.Get("/vclusters", s.handleListVClusters)
+			r.Post("/vclusters/{namespace}/{name}/connect", s.handleConnectVCluster)
+			r.Delete("/vclusters/{namespace}/{name}/connect", s.handleDisconnectVCluster)
+			r.Get("/local-clusters", s.handleListLocalClusters)
+			r.Post("/local-clusters", s.handleCreateLocalCluster)
+			r.Post("/local-clusters/{tool}/{name}/{action}", s.handleLocalClusterAction)
+			r.Delete("/local-clusters/{tool}/{name}", s.handleDeleteLocalCluster)
+			r.Get("/autoscaling/decisions", s.handleScalingDecisions)
+			r.Get("/recommendations", s.handleRecommendations)
+			r.Get("/storage/ephemeral", s.handleStorageUsage)
+			r.Get("/nodes/{name}/detail", s.handleNodeDetail)
+			r.Get("/placement", s.handlePodPlacement)
+			r.Get("/placement/violations", s.handlePlacementViolations)
+			r.Post("/maintenance/plan", s.handleMaintenancePlan)
+			r.Post("/maintenance/execute", s.handleMaintenanceExecute)
+			r.Delete("/maintenance/execute", s.handleMaintenanceCancel)
+			r.Get("/maintenance/status", s.handleMaintenanceStatus)
+
 			// Metrics (from metrics.k8s.io API)
 			r.Get("/metrics/pods/{namespace}/{name}", s.handlePodMetrics)
 			r.Get("/metrics/nodes/{name}", s.handleNodeMetrics)
 			r.Get("/metrics/pods/{namespace}/{name}/history", s.handlePodMetricsHistory)
 			r.Get("/metrics/nodes/{name}/history", s.handleNodeMetricsHistory)
+			r.Get("/metrics/cluster/history", s.handleClusterMetricsHistory)
+			r.Get("/metrics/namespaces", s.handleNamespaceUsage)
 
 			// Port forwarding
+			r.Get("/exec/sessions", s.handleListExecSessions)
 			r.Get("/portforwards", s.handleListPortForwards)
 			r.Post("/portforwards", s.handleStartPortForward)
 			r.Delete("/portforwards/{id}", s.handleStopPortForward)
@@ -167,6 +324,8 @@ func (s *Server) setupRoutes() {
 
 			// Active sessions (for context switch confirmation)
 			r.Get("/sessions", s.handleGetSessions)
+			r.Get("/sessions/active", s.handleListActiveSessions)
+			r.Delete("/sessions/{id}", s.handleCloseSession)
 
 			// CronJob operations
 			r.Post("/cronjobs/{namespace}/{name}/trigger", s.handleTriggerCronJob)
@@ -175,11 +334,14 @@ func (s *Server) setupRoutes() {
 
 			// Workload restart
 			r.Post("/workloads/{kind}/{namespace}/{name}/restart", s.handleRestartWorkload)
+			r.Post("/workloads/restart/plan", s.handleBulkRestartPlan)
 			r.Post("/workloads/{kind}/{namespace}/{name}/scale", s.handleScaleWorkload)
 
 			// Workload logs (non-streaming)
 			r.Get("/workloads/{kind}/{namespace}/{name}/logs", s.handleWorkloadLogs)
 			r.Get("/workloads/{kind}/{namespace}/{name}/pods", s.handleWorkloadPods)
+			r.Get("/workloads/{kind}/{namespace}/{name}/export", s.handleExportWorkload)
+			r.Get("/workloads/compare", s.handleCompareWorkloads)
 
 			// Helm routes
 			helmHandlers := helm.NewHandlers()
@@ -188,6 +350,7 @@ func (s *Server) setupRoutes() {
 			// Image inspection routes
 			imageHandlers := images.NewHandlers()
 			imageHandlers.RegisterRoutes(r)
+			s.imageInspector = imageHandlers.Inspector()
 
 			// FluxCD routes
 			r.Post("/flux/{kind}/{namespace}/{name}/reconcile", s.handleFluxReconcile)
@@ -202,10 +365,16 @@ func (s *Server) setupRoutes() {
 			r.Post("/argo/applications/{namespace}/{name}/suspend", s.handleArgoSuspend)
 			r.Post("/argo/applications/{namespace}/{name}/resume", s.handleArgoResume)
 
+			// Argo Workflows routes
+			r.Get("/argo/workflows/{namespace}/{name}/dag", s.handleWorkflowDAG)
+			r.Post("/argo/workflows/{namespace}/{name}/retry", s.handleWorkflowRetry)
+			r.Post("/argo/workflows/{namespace}/{name}/terminate", s.handleWorkflowTerminate)
+
 			// Debug routes (for event pipeline diagnostics)
 			r.Get("/debug/events", s.handleDebugEvents)
 			r.Get("/debug/events/diagnose", s.handleDebugEventsDiagnose)
 			r.Get("/debug/informers", s.handleDebugInformers)
+			r.Get("/debug/startup", s.handleDebugStartup)
 
 			// Traffic routes (non-streaming)
 			r.Get("/traffic/sources", s.handleGetTrafficSources)
@@ -214,6 +383,8 @@ func (s *Server) setupRoutes() {
 			r.Post("/traffic/source", s.handleSetTrafficSource)
 			r.Post("/traffic/connect", s.handleTrafficConnect)
 			r.Get("/traffic/connection", s.handleTrafficConnectionStatus)
+			r.Get("/traffic/history", s.handleGetTrafficHistory)
+			r.Get("/traffic/history/snapshot", s.handleGetTrafficSnapshot)
 
 			// Context routes
 			r.Get("/contexts", s.handleListContexts)
@@ -221,7 +392,9 @@ func (s *Server) setupRoutes() {
 
 			// Connection status routes (for graceful startup)
 			r.Get("/connection", s.handleConnectionStatus)
+			r.Get("/connection/history", s.handleConnectionHistory)
 			r.Post("/connection/retry", s.handleConnectionRetry)
+			r.Post("/connection/refresh-credentials", s.handleRefreshCredentials)
 
 			// Desktop update routes (only active when updater is set)
 			r.Post("/desktop/update", s.handleDesktopUpdateStart)
@@ -235,10 +408,10 @@ func (s *Server) setupRoutes() {
 
 	// Static files (frontend) - SPA fallback to index.html
 	if s.staticFS != nil {
-		r.Handle("/*", spaHandler(http.FS(s.staticFS)))
+		r.Handle("/*", securityHeaders(spaHandler(http.FS(s.staticFS))))
 	} else if s.devMode {
 		// In dev mode, serve from web/dist
-		r.Handle("/*", spaHandler(http.Dir("web/dist")))
+		r.Handle("/*", securityHeaders(spaHandler(http.Dir("web/dist"))))
 	}
 }
 
@@ -280,12 +453,15 @@ func (s *Server) Start() error {
 func (s *Server) StartWithReady(ready chan<- struct{}) error {
 	s.broadcaster.Start()
 
-	addr := fmt.Sprintf(":%d", s.port)
-	ln, err := net.Listen("tcp", addr)
-	if err != nil {
-		return fmt.Errorf("listen on %s: %w", addr, err)
+	if s.listener == nil {
+		addr := fmt.Sprintf(":%d", s.port)
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			return fmt.Errorf("listen on %s: %w", addr, err)
+		}
+		s.listener = ln
 	}
-	s.listener = ln
+	ln := s.listener
 
 	log.Printf("Starting Explorer server on http://localhost:%d", s.ActualPort())
 
@@ -316,9 +492,24 @@ func (s *Server) SetUpdater(u *updater.Updater) {
 	s.updater = u
 }
 
+// RecordEvents captures the SSE event stream to a file for later replay.
+// Must be called before Start.
+func (s *Server) RecordEvents(path string) error {
+	return s.broadcaster.EnableRecording(path)
+}
+
+// ReplayEvents replays a recorded event stream to SSE clients instead of live
+// events, at the given speed multiplier (0 = no delays). Must be called before Start.
+func (s *Server) ReplayEvents(path string, speed float64) error {
+	return s.broadcaster.EnableReplay(path, speed)
+}
+
 // Stop gracefully stops the server
 func (s *Server) Stop() {
 	s.broadcaster.Stop()
+	if s.agents != nil {
+		s.agents.Stop()
+	}
 }
 
 // Handlers
@@ -360,6 +551,8 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	runtimeStats["dynamicInformers"] = dynamicInformerCount
 
 	s.writeJSON(w, map[string]any{
+		"service":       "radar", // Lets another launch recognize (and reuse) this instance
+		"context":       k8s.GetContextName(),
 		"status":        status,
 		"resourceCount": cache.GetResourceCount(),
 		"timeline":      timelineStats,
@@ -367,6 +560,16 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleListInstances lists the Radar processes running on this machine
+// GET /api/instances
+func (s *Server) handleListInstances(w http.ResponseWriter, r *http.Request) {
+	list := instances.List()
+	if list == nil {
+		list = []instances.Instance{}
+	}
+	s.writeJSON(w, map[string]any{"instances": list, "self": os.Getpid()})
+}
+
 func (s *Server) handleVersionCheck(w http.ResponseWriter, r *http.Request) {
 	info := version.CheckForUpdate(r.Context())
 	s.writeJSON(w, info)
@@ -378,6 +581,8 @@ func (s *Server) handleClusterInfo(w http.ResponseWriter, r *http.Request) {
 		s.writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	info.ControlPlane = k8s.GetControlPlaneHealth(r.Context())
+	info.Cloud = k8s.GetCloudSummary(info.Platform)
 	s.writeJSON(w, info)
 }
 
@@ -387,6 +592,12 @@ func (s *Server) handleCapabilities(w http.ResponseWriter, r *http.Request) {
 		s.writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	if settings.Get().ReadOnly {
+		caps.ReadOnly = true
+		caps.Exec = false
+		caps.PortForward = false
+		caps.HelmWrite = false
+	}
 
 	// Include resource permissions if cache is available
 	if cache := k8s.GetResourceCache(); cache != nil {
@@ -435,6 +646,22 @@ func parseNamespaces(query url.Values) []string {
 	return nil
 }
 
+// parseTimezone parses the "tz" query parameter (an IANA zone such as
+// "Europe/Berlin", or "UTC") that a response's RFC3339 timestamps are
+// rendered in. Defaults to UTC.
+func parseTimezone(query url.Values) (*time.Location, error) {
+	tz := strings.TrimSpace(query.Get("tz"))
+	if tz == "" || tz == "Local" {
+		// "Local" would mean the server's zone, which the client can't know
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tz %q: expected an IANA time zone such as Europe/Berlin", tz)
+	}
+	return loc, nil
+}
+
 // appendSlice appends elements from a typed slice (returned as any) into a []any.
 // This is needed because K8s listers return different concrete slice types (e.g. []*corev1.Pod).
 func appendSlice(dst []any, src any) []any {
@@ -458,6 +685,25 @@ func (s *Server) handleTopology(w http.ResponseWriter, r *http.Request) {
 		opts.ViewMode = topology.ViewModeTraffic
 	}
 
+	// ?at= rewinds the graph using timeline create/delete events
+	var at time.Time
+	if atStr := r.URL.Query().Get("at"); atStr != "" {
+		ts, err := time.Parse(time.RFC3339, atStr)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "at must be an RFC3339 timestamp")
+			return
+		}
+		if ts.After(time.Now()) {
+			s.writeError(w, http.StatusBadRequest, "at must be in the past")
+			return
+		}
+		if opts.ViewMode == topology.ViewModeTraffic {
+			s.writeError(w, http.StatusBadRequest, "at is only supported for the resources view")
+			return
+		}
+		at = ts
+	}
+
 	builder := topology.NewBuilder()
 	topo, err := builder.Build(opts)
 	if err != nil {
@@ -465,7 +711,81 @@ func (s *Server) handleTopology(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.writeJSON(w, topo)
+	if !at.IsZero() {
+		const maxEvents = 10000 // The stores' per-query cap
+		store := timeline.GetStore()
+		if store == nil {
+			s.writeError(w, http.StatusServiceUnavailable, "Timeline store not available")
+			return
+		}
+		events, err := store.Query(r.Context(), timeline.QueryOptions{
+			Namespaces:     namespaces,
+			Kinds:          topology.HistoricalKinds(),
+			Since:          at,
+			Sources:        []timeline.EventSource{timeline.SourceInformer},
+			Limit:          maxEvents,
+			IncludeManaged: true,
+		})
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		topo = topology.AsOf(topo, at, events, opts)
+		if oldest := store.Stats().OldestEvent; oldest.IsZero() || at.Before(oldest) {
+			topo.Warnings = append(topo.Warnings, "Timeline history doesn't reach back to this time; resources created before it was recorded may be shown")
+		}
+		if len(events) == maxEvents {
+			topo.Warnings = append(topo.Warnings, "Too many changes since this time; the historical view may be incomplete")
+		}
+	}
+
+	s.writeJSONWithETag(w, r, topo)
+}
+
+// handleTopologyNeighborhood returns the nodes and edges within ?depth= hops
+// (default 2) of a resource, for focused views that don't need the full graph
+func (s *Server) handleTopologyNeighborhood(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	kind := chi.URLParam(r, "kind")
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+	// Handle cluster-scoped resources: "_" is used as placeholder for empty namespace
+	if namespace == "_" {
+		namespace = ""
+	}
+
+	depth := 2
+	if v := r.URL.Query().Get("depth"); v != "" {
+		d, err := strconv.Atoi(v)
+		if err != nil || d < 0 || d > topology.MaxNeighborhoodDepth {
+			s.writeError(w, http.StatusBadRequest, fmt.Sprintf("depth must be between 0 and %d", topology.MaxNeighborhoodDepth))
+			return
+		}
+		depth = d
+	}
+
+	// The cached full topology (resources view, including ReplicaSets) is the
+	// same graph relationship lookups use; build one if it isn't ready yet
+	topo := s.broadcaster.GetCachedTopology()
+	if topo == nil {
+		opts := topology.DefaultBuildOptions()
+		opts.ViewMode = topology.ViewModeResources
+		opts.IncludeReplicaSets = true
+		var err error
+		if topo, err = topology.NewBuilder().Build(opts); err != nil {
+			s.writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	subgraph := topology.Neighborhood(kind, namespace, name, depth, topo)
+	if subgraph == nil {
+		s.writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s/%s not found in topology", kind, namespace, name))
+		return
+	}
+	s.writeJSONWithETag(w, r, subgraph)
 }
 
 func (s *Server) handleNamespaces(w http.ResponseWriter, r *http.Request) {
@@ -508,7 +828,7 @@ func (s *Server) handleAPIResources(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resources, err := discovery.GetAPIResources()
+	resources, err := k8s.GetAPIResourceStatuses()
 	if err != nil {
 		s.writeError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -517,6 +837,49 @@ func (s *Server) handleAPIResources(w http.ResponseWriter, r *http.Request) {
 	s.writeJSON(w, resources)
 }
 
+// handleWatchAPIResource starts or stops the dynamic informer for a single GVR.
+// POST /api/api-resources/watch and /api/api-resources/unwatch with body {group, version, resource}
+func (s *Server) handleWatchAPIResource(watch bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.requireConnected(w) {
+			return
+		}
+
+		var gvr struct {
+			Group    string `json:"group"`
+			Version  string `json:"version"`
+			Resource string `json:"resource"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&gvr); err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		if gvr.Version == "" || gvr.Resource == "" {
+			s.writeError(w, http.StatusBadRequest, "version and resource are required")
+			return
+		}
+
+		target := schema.GroupVersionResource{Group: gvr.Group, Version: gvr.Version, Resource: gvr.Resource}
+		var err error
+		if watch {
+			err = k8s.StartWatchingGVR(target)
+		} else {
+			err = k8s.StopWatchingGVR(target)
+		}
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		s.writeJSON(w, map[string]any{
+			"group":    gvr.Group,
+			"version":  gvr.Version,
+			"resource": gvr.Resource,
+			"watched":  watch,
+		})
+	}
+}
+
 func (s *Server) handleListResources(w http.ResponseWriter, r *http.Request) {
 	if !s.requireConnected(w) {
 		return
@@ -524,6 +887,14 @@ func (s *Server) handleListResources(w http.ResponseWriter, r *http.Request) {
 	kind := chi.URLParam(r, "kind")
 	namespaces := parseNamespaces(r.URL.Query())
 	group := r.URL.Query().Get("group") // API group for CRD disambiguation
+	// managedBy adds each resource's managing system (Helm/Argo CD/Flux) and
+	// filters by it; summary rows always include it
+	withManagedBy := r.URL.Query().Has("managedBy")
+	managedByFilter, err := k8s.ParseManagedByFilter(r.URL.Query().Get("managedBy"))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	cache := k8s.GetResourceCache()
 	if cache == nil {
@@ -532,7 +903,6 @@ func (s *Server) handleListResources(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var result any
-	var err error
 
 	// listPerNs is a helper that merges results across multiple namespaces.
 	// listAll returns all items; listNs returns items for a single namespace.
@@ -706,10 +1076,10 @@ func (s *Server) handleListResources(w http.ResponseWriter, r *http.Request) {
 		result, err = cache.Namespaces().List(labels.Everything())
 	default:
 		// Fall back to dynamic cache for CRDs and other unknown resources
+		var items []*unstructured.Unstructured
 		if len(namespaces) > 0 {
-			var merged []any
 			for _, ns := range namespaces {
-				items, listErr := cache.ListDynamicWithGroup(r.Context(), kind, ns, group)
+				nsItems, listErr := cache.ListDynamicWithGroup(r.Context(), kind, ns, group)
 				if listErr != nil {
 					if strings.Contains(listErr.Error(), "unknown resource kind") {
 						s.writeError(w, http.StatusBadRequest, listErr.Error())
@@ -718,13 +1088,10 @@ func (s *Server) handleListResources(w http.ResponseWriter, r *http.Request) {
 					s.writeError(w, http.StatusInternalServerError, listErr.Error())
 					return
 				}
-				for _, item := range items {
-					merged = append(merged, item)
-				}
+				items = append(items, nsItems...)
 			}
-			result = merged
 		} else {
-			result, err = cache.ListDynamicWithGroup(r.Context(), kind, "", group)
+			items, err = cache.ListDynamicWithGroup(r.Context(), kind, "", group)
 			if err != nil {
 				if strings.Contains(err.Error(), "unknown resource kind") {
 					s.writeError(w, http.StatusBadRequest, err.Error())
@@ -734,6 +1101,14 @@ func (s *Server) handleListResources(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 		}
+		// Summary mode returns the CRD's kubectl columns instead of full objects
+		if r.URL.Query().Get("summary") == "true" {
+			summary := k8s.SummarizeDynamic(kind, group, items)
+			summary.Rows = filterSummaryManagedBy(summary.Rows, managedByFilter)
+			s.writeJSONWithETag(w, r, summary)
+			return
+		}
+		result = items
 	}
 
 	if err != nil {
@@ -741,7 +1116,14 @@ func (s *Server) handleListResources(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.writeJSON(w, result)
+	if withManagedBy {
+		result, err = listWithManagedBy(cache, result, managedByFilter)
+		if err != nil {
+			s.writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	s.writeJSONWithETag(w, r, result)
 }
 
 // normalizeKind converts K8s kind names to lowercase for case-insensitive matching
@@ -823,146 +1205,175 @@ func (s *Server) handleGetResource(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	resource, status, err := lookupResource(r.Context(), cache, kind, namespace, name, group)
+	if err != nil {
+		s.writeError(w, status, err.Error())
+		return
+	}
+
+	// Return resource with relationships
+	response := topology.ResourceWithRelationships{
+		Resource:      resource,
+		Relationships: s.resourceRelationships(kind, namespace, name),
+	}
+
+	s.writeJSON(w, response)
+}
+
+// lookupResource gets a resource from the cache with its APIVersion and Kind
+// set. On failure it also returns the HTTP status to report.
+func lookupResource(ctx context.Context, cache *k8s.ResourceCache, kind, namespace, name, group string) (any, int, error) {
 	var resource any
 	var err error
 
-	// forbiddenGet returns a 403 error for RBAC-restricted resource types
-	forbiddenGet := func(resourceKind string) {
-		s.writeError(w, http.StatusForbidden, fmt.Sprintf("insufficient permissions to access %s", resourceKind))
+	// forbiddenGet returns the error for RBAC-restricted resource types
+	forbiddenGet := func(resourceKind string) error {
+		return fmt.Errorf("insufficient permissions to access %s", resourceKind)
 	}
 
 	// Try typed cache for known resource types first
 	switch kind {
 	case "pods", "pod":
 		if cache.Pods() == nil {
-			forbiddenGet("pods")
-			return
+			return nil, http.StatusForbidden, forbiddenGet("pods")
 		}
 		resource, err = cache.Pods().Pods(namespace).Get(name)
 	case "services", "service":
 		if cache.Services() == nil {
-			forbiddenGet("services")
-			return
+			return nil, http.StatusForbidden, forbiddenGet("services")
 		}
 		resource, err = cache.Services().Services(namespace).Get(name)
 	case "deployments", "deployment":
 		if cache.Deployments() == nil {
-			forbiddenGet("deployments")
-			return
+			return nil, http.StatusForbidden, forbiddenGet("deployments")
 		}
 		resource, err = cache.Deployments().Deployments(namespace).Get(name)
 	case "daemonsets", "daemonset":
 		if cache.DaemonSets() == nil {
-			forbiddenGet("daemonsets")
-			return
+			return nil, http.StatusForbidden, forbiddenGet("daemonsets")
 		}
 		resource, err = cache.DaemonSets().DaemonSets(namespace).Get(name)
 	case "statefulsets", "statefulset":
 		if cache.StatefulSets() == nil {
-			forbiddenGet("statefulsets")
-			return
+			return nil, http.StatusForbidden, forbiddenGet("statefulsets")
 		}
 		resource, err = cache.StatefulSets().StatefulSets(namespace).Get(name)
 	case "replicasets", "replicaset":
 		if cache.ReplicaSets() == nil {
-			forbiddenGet("replicasets")
-			return
+			return nil, http.StatusForbidden, forbiddenGet("replicasets")
 		}
 		resource, err = cache.ReplicaSets().ReplicaSets(namespace).Get(name)
 	case "ingresses", "ingress":
 		if cache.Ingresses() == nil {
-			forbiddenGet("ingresses")
-			return
+			return nil, http.StatusForbidden, forbiddenGet("ingresses")
 		}
 		resource, err = cache.Ingresses().Ingresses(namespace).Get(name)
 	case "configmaps", "configmap":
 		if cache.ConfigMaps() == nil {
-			forbiddenGet("configmaps")
-			return
+			return nil, http.StatusForbidden, forbiddenGet("configmaps")
 		}
 		resource, err = cache.ConfigMaps().ConfigMaps(namespace).Get(name)
 	case "secrets", "secret":
 		lister := cache.Secrets()
 		if lister == nil {
-			forbiddenGet("secrets")
-			return
+			return nil, http.StatusForbidden, forbiddenGet("secrets")
 		}
 		resource, err = lister.Secrets(namespace).Get(name)
 	case "persistentvolumeclaims", "persistentvolumeclaim", "pvcs", "pvc":
 		if cache.PersistentVolumeClaims() == nil {
-			forbiddenGet("persistentvolumeclaims")
-			return
+			return nil, http.StatusForbidden, forbiddenGet("persistentvolumeclaims")
 		}
 		resource, err = cache.PersistentVolumeClaims().PersistentVolumeClaims(namespace).Get(name)
 	case "hpas", "hpa", "horizontalpodautoscaler", "horizontalpodautoscalers":
 		if cache.HorizontalPodAutoscalers() == nil {
-			forbiddenGet("horizontalpodautoscalers")
-			return
+			return nil, http.StatusForbidden, forbiddenGet("horizontalpodautoscalers")
 		}
 		resource, err = cache.HorizontalPodAutoscalers().HorizontalPodAutoscalers(namespace).Get(name)
 	case "jobs", "job":
 		if cache.Jobs() == nil {
-			forbiddenGet("jobs")
-			return
+			return nil, http.StatusForbidden, forbiddenGet("jobs")
 		}
 		resource, err = cache.Jobs().Jobs(namespace).Get(name)
 	case "cronjobs", "cronjob":
 		if cache.CronJobs() == nil {
-			forbiddenGet("cronjobs")
-			return
+			return nil, http.StatusForbidden, forbiddenGet("cronjobs")
 		}
 		resource, err = cache.CronJobs().CronJobs(namespace).Get(name)
 	case "nodes", "node":
 		if cache.Nodes() == nil {
-			forbiddenGet("nodes")
-			return
+			return nil, http.StatusForbidden, forbiddenGet("nodes")
 		}
 		resource, err = cache.Nodes().Get(name)
 	case "namespaces", "namespace":
 		if cache.Namespaces() == nil {
-			forbiddenGet("namespaces")
-			return
+			return nil, http.StatusForbidden, forbiddenGet("namespaces")
 		}
 		resource, err = cache.Namespaces().Get(name)
 	default:
 		// Fall back to dynamic cache for CRDs and other unknown resources
 		// Use group to disambiguate when multiple API groups have similar resource names
-		resource, err = cache.GetDynamicWithGroup(r.Context(), kind, namespace, name, group)
+		resource, err = cache.GetDynamicWithGroup(ctx, kind, namespace, name, group)
 		if err != nil {
 			if strings.Contains(err.Error(), "unknown resource kind") {
-				s.writeError(w, http.StatusBadRequest, err.Error())
-				return
+				return nil, http.StatusBadRequest, err
 			}
 			if strings.Contains(err.Error(), "not found") {
-				s.writeError(w, http.StatusNotFound, err.Error())
-				return
+				return nil, http.StatusNotFound, err
 			}
-			s.writeError(w, http.StatusInternalServerError, err.Error())
-			return
+			return nil, http.StatusInternalServerError, err
 		}
 	}
 
 	if err != nil {
-		s.writeError(w, http.StatusNotFound, err.Error())
-		return
+		return nil, http.StatusNotFound, err
 	}
 
 	// Set APIVersion and Kind for typed resources (informers don't populate these)
 	setTypeMeta(resource)
+	return resource, http.StatusOK, nil
+}
 
-	// Get relationships from cached topology
-	var relationships *topology.Relationships
+// resourceRelationships returns a resource's relationships from the cached
+// topology, or nil before the first topology build
+func (s *Server) resourceRelationships(kind, namespace, name string) *topology.Relationships {
 	if cachedTopo := s.broadcaster.GetCachedTopology(); cachedTopo != nil {
-		relationships = topology.GetRelationships(kind, namespace, name, cachedTopo)
+		return topology.GetRelationships(kind, namespace, name, cachedTopo)
 	}
+	return nil
+}
 
-	// Return resource with relationships
-	response := topology.ResourceWithRelationships{
-		Resource:      resource,
-		Relationships: relationships,
+// handleOwnerChain returns a resource's ownership chain, e.g.
+// Pod → ReplicaSet → Deployment → HelmRelease or Argo CD Application
+func (s *Server) handleOwnerChain(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
 	}
+	kind := chi.URLParam(r, "kind")
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+	group := r.URL.Query().Get("group") // API group for CRD disambiguation
 
-	s.writeJSON(w, response)
+	// Handle cluster-scoped resources: "_" is used as placeholder for empty namespace
+	if namespace == "_" {
+		namespace = ""
+	}
+
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Resource cache not available")
+		return
+	}
+
+	chain, err := cache.GetOwnerChain(r.Context(), kind, group, namespace, name)
+	if err != nil {
+		if strings.Contains(err.Error(), "unknown resource kind") {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	s.writeJSON(w, chain)
 }
 
 // handlePodMetrics fetches metrics for a specific pod from the metrics.k8s.io API
@@ -1004,6 +1415,11 @@ func (s *Server) handleNodeMetrics(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handlePodMetricsHistory(w http.ResponseWriter, r *http.Request) {
 	namespace := chi.URLParam(r, "namespace")
 	name := chi.URLParam(r, "name")
+	duration, ok := k8s.ParseMetricsHistoryDuration(r.URL.Query().Get("duration"))
+	if !ok {
+		s.writeError(w, http.StatusBadRequest, "invalid duration: must be 1h, 6h, or 24h")
+		return
+	}
 
 	store := k8s.GetMetricsHistory()
 	if store == nil {
@@ -1011,7 +1427,7 @@ func (s *Server) handlePodMetricsHistory(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	history := store.GetPodMetricsHistory(namespace, name)
+	history := store.GetPodMetricsHistory(namespace, name, duration)
 	if history == nil {
 		// Return empty history instead of error - metrics may not have been collected yet
 		history = &k8s.PodMetricsHistory{
@@ -1027,6 +1443,11 @@ func (s *Server) handlePodMetricsHistory(w http.ResponseWriter, r *http.Request)
 // handleNodeMetricsHistory returns historical metrics for a specific node
 func (s *Server) handleNodeMetricsHistory(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
+	duration, ok := k8s.ParseMetricsHistoryDuration(r.URL.Query().Get("duration"))
+	if !ok {
+		s.writeError(w, http.StatusBadRequest, "invalid duration: must be 1h, 6h, or 24h")
+		return
+	}
 
 	store := k8s.GetMetricsHistory()
 	if store == nil {
@@ -1034,7 +1455,7 @@ func (s *Server) handleNodeMetricsHistory(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	history := store.GetNodeMetricsHistory(name)
+	history := store.GetNodeMetricsHistory(name, duration)
 	if history == nil {
 		// Return empty history instead of error
 		history = &k8s.NodeMetricsHistory{
@@ -1046,6 +1467,58 @@ func (s *Server) handleNodeMetricsHistory(w http.ResponseWriter, r *http.Request
 	s.writeJSON(w, history)
 }
 
+// handleClusterMetricsHistory returns a downsampled series of cluster-wide
+// CPU/memory usage and requests for dashboard sparklines
+func (s *Server) handleClusterMetricsHistory(w http.ResponseWriter, r *http.Request) {
+	duration, ok := k8s.ParseMetricsHistoryDuration(r.URL.Query().Get("duration"))
+	if !ok {
+		s.writeError(w, http.StatusBadRequest, "invalid duration: must be 1h, 6h, or 24h")
+		return
+	}
+	points := k8s.DefaultClusterMetricsPoints
+	if raw := r.URL.Query().Get("points"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > k8s.MaxClusterMetricsPoints {
+			s.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid points: must be between 1 and %d", k8s.MaxClusterMetricsPoints))
+			return
+		}
+		points = parsed
+	}
+
+	store := k8s.GetMetricsHistory()
+	if store == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Metrics history not available")
+		return
+	}
+
+	s.writeJSON(w, store.GetClusterMetricsHistory(duration, points))
+}
+
+// handleNamespaceUsage returns CPU/memory usage, requests, and limits rolled up
+// per namespace, with the top consumers in each
+func (s *Server) handleNamespaceUsage(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	duration, ok := k8s.ParseMetricsHistoryDuration(r.URL.Query().Get("duration"))
+	if !ok {
+		s.writeError(w, http.StatusBadRequest, "invalid duration: must be 1h, 6h, or 24h")
+		return
+	}
+	topK := k8s.DefaultNamespaceUsageTopK
+	if raw := r.URL.Query().Get("top"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > k8s.MaxNamespaceUsageTopK {
+			s.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid top: must be between 1 and %d", k8s.MaxNamespaceUsageTopK))
+			return
+		}
+		topK = parsed
+	}
+
+	s.writeJSON(w, k8s.GetNamespaceUsage(parseNamespaces(r.URL.Query()), duration, topK))
+}
+
 func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
 	if !s.requireConnected(w) {
 		return
@@ -1094,35 +1567,49 @@ func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
 
 // handleChanges returns timeline events using the unified timeline.TimelineEvent format.
 // This is the main timeline API endpoint - it queries the timeline store directly.
+// Events are newest first; when the page is full, the X-Next-Cursor header
+// holds a cursor to pass as ?cursor= for the next (older) page.
 func (s *Server) handleChanges(w http.ResponseWriter, r *http.Request) {
 	if !s.requireConnected(w) {
 		return
 	}
-	namespaces := parseNamespaces(r.URL.Query())
-	kind := r.URL.Query().Get("kind")
-	sinceStr := r.URL.Query().Get("since")
-	limitStr := r.URL.Query().Get("limit")
-	filterPreset := r.URL.Query().Get("filter")
-	includeK8sEvents := r.URL.Query().Get("include_k8s_events") != "false" // default true
-	includeManaged := r.URL.Query().Get("include_managed") == "true"       // default false
+	opts, err := parseChangesFilters(r.URL.Query())
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	loc, err := parseTimezone(r.URL.Query())
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	// Parse since timestamp
-	var since time.Time
-	if sinceStr != "" {
+	if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
 		if ts, err := time.Parse(time.RFC3339, sinceStr); err == nil {
-			since = ts
+			opts.Since = ts
+		}
+	}
+	if untilStr := r.URL.Query().Get("until"); untilStr != "" {
+		ts, err := time.Parse(time.RFC3339, untilStr)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid until: expected RFC3339")
+			return
 		}
+		opts.Until = ts
 	}
 
 	// Parse limit (default 200)
 	limit := 200
-	if limitStr != "" {
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
 		if l, err := fmt.Sscanf(limitStr, "%d", &limit); err == nil && l > 0 {
 			if limit > 10000 {
 				limit = 10000
 			}
 		}
 	}
+	opts.Limit = limit
+	opts.Cursor = r.URL.Query().Get("cursor")
 
 	store := timeline.GetStore()
 	if store == nil {
@@ -1130,28 +1617,20 @@ func (s *Server) handleChanges(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Build query options
-	if filterPreset == "" {
-		filterPreset = "default"
-	}
-	opts := timeline.QueryOptions{
-		Namespaces:       namespaces,
-		Since:            since,
-		Limit:            limit,
-		IncludeManaged:   includeManaged,
-		IncludeK8sEvents: includeK8sEvents,
-		FilterPreset:     filterPreset,
-	}
-	if kind != "" {
-		opts.Kinds = []string{kind}
-	}
-
 	events, err := store.Query(r.Context(), opts)
+	if errors.Is(err, timeline.ErrInvalidCursor) {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		s.writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	if cursor := timeline.NextCursor(events, limit); cursor != "" {
+		w.Header().Set("X-Next-Cursor", cursor)
+	}
+	timeline.InZone(events, loc)
 	s.writeJSON(w, events)
 }
 
@@ -1161,6 +1640,11 @@ func (s *Server) handleChangeChildren(w http.ResponseWriter, r *http.Request) {
 	namespace := chi.URLParam(r, "namespace")
 	ownerName := chi.URLParam(r, "name")
 	sinceStr := r.URL.Query().Get("since")
+	loc, err := parseTimezone(r.URL.Query())
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	var since time.Time
 	if sinceStr != "" {
@@ -1184,14 +1668,25 @@ func (s *Server) handleChangeChildren(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	timeline.InZone(children, loc)
 	s.writeJSON(w, children)
 }
 
-// handleUpdateResource updates a Kubernetes resource from YAML
+// handleUpdateResource updates a Kubernetes resource from YAML.
+// With ?validateOnly=true the update is sent as a server-side dry-run and the
+// response is a k8s.ValidationResult describing any schema or admission errors.
+// With ?lint=warn best-practice findings are returned alongside the result;
+// ?lint=strict rejects the save with 422 when there are any.
 func (s *Server) handleUpdateResource(w http.ResponseWriter, r *http.Request) {
 	kind := chi.URLParam(r, "kind")
 	namespace := chi.URLParam(r, "namespace")
 	name := chi.URLParam(r, "name")
+	validateOnly := r.URL.Query().Get("validateOnly") == "true"
+	lintMode, err := k8s.ParseLintMode(r.URL.Query().Get("lint"))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	// Read request body (YAML content)
 	body, err := io.ReadAll(r.Body)
@@ -1201,13 +1696,50 @@ func (s *Server) handleUpdateResource(w http.ResponseWriter, r *http.Request) {
 	}
 	defer r.Body.Close()
 
+	// Lint before saving so strict mode can reject without writing.
+	// Unparseable YAML is reported by UpdateResource below.
+	var lint []k8s.LintFinding
+	if lintMode != k8s.LintOff {
+		lint, _ = k8s.LintYAML(string(body))
+		if lintMode == k8s.LintStrict && len(lint) > 0 {
+			if validateOnly {
+				s.writeJSON(w, k8s.ValidationResult{Valid: false, Reason: "LintFailed", Lint: lint})
+				return
+			}
+			apierror.WriteResponse(w, struct {
+				apierror.Response
+				Lint []k8s.LintFinding `json:"lint"`
+			}{
+				Response: apierror.New(http.StatusUnprocessableEntity, apierror.ValidationFailed,
+					fmt.Sprintf("lint found %d issue(s); fix them or save without lint=strict", len(lint))),
+				Lint: lint,
+			})
+			return
+		}
+	}
+
 	// Update the resource
 	result, err := k8s.UpdateResource(r.Context(), k8s.UpdateResourceOptions{
 		Kind:      kind,
 		Namespace: namespace,
 		Name:      name,
 		YAML:      string(body),
+		DryRun:    validateOnly,
 	})
+	if validateOnly {
+		if result := k8s.ValidationResultFromError(err); result != nil {
+			result.Lint = lint
+			s.writeJSON(w, result)
+			return
+		}
+		if err != nil && (strings.Contains(err.Error(), "invalid YAML") || strings.Contains(err.Error(), "mismatch")) {
+			s.writeJSON(w, k8s.ValidationResult{
+				Valid:  false,
+				Issues: []k8s.ValidationIssue{{Message: err.Error()}},
+			})
+			return
+		}
+	}
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			s.writeError(w, http.StatusNotFound, err.Error())
@@ -1229,16 +1761,129 @@ func (s *Server) handleUpdateResource(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if validateOnly {
+		s.writeJSON(w, k8s.ValidationResult{Valid: true, Lint: lint})
+		return
+	}
+
+	// With linting on, findings are returned next to the saved resource
+	if lintMode != k8s.LintOff {
+		s.writeJSON(w, map[string]any{"resource": result, "lint": lint})
+		return
+	}
+	s.writeJSON(w, result)
+}
+
+// patchContentTypes maps request Content-Type values to Kubernetes patch types
+var patchContentTypes = map[string]types.PatchType{
+	"application/json-patch+json":            types.JSONPatchType,
+	"application/merge-patch+json":           types.MergePatchType,
+	"application/strategic-merge-patch+json": types.StrategicMergePatchType,
+}
+
+// handlePatchResource applies a JSON Patch, merge patch, or strategic merge patch
+// to a resource. The patch type is selected by the request Content-Type.
+func (s *Server) handlePatchResource(w http.ResponseWriter, r *http.Request) {
+	kind := chi.URLParam(r, "kind")
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	contentType := r.Header.Get("Content-Type")
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	patchType, ok := patchContentTypes[strings.TrimSpace(strings.ToLower(contentType))]
+	if !ok {
+		s.writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json-patch+json, application/merge-patch+json, or application/strategic-merge-patch+json")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+	defer r.Body.Close()
+
+	if !json.Valid(body) {
+		s.writeError(w, http.StatusBadRequest, "patch body must be valid JSON")
+		return
+	}
+
+	result, err := k8s.PatchResource(r.Context(), kind, namespace, name, patchType, body)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			s.writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if apierrors.IsForbidden(err) {
+			s.writeError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if apierrors.IsConflict(err) {
+			s.writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		if apierrors.IsUnsupportedMediaType(err) {
+			s.writeError(w, http.StatusUnsupportedMediaType, err.Error())
+			return
+		}
+		if k8s.IsValidationError(err) || strings.Contains(err.Error(), "unknown resource kind") {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("[patch] Failed to patch %s %s/%s: %v", kind, namespace, name, err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.writeJSON(w, result)
+}
+
+// handleBulkEditMetadata adds or removes labels/annotations across many resources.
+// Per-item failures are reported in the response body, not as an HTTP error.
+func (s *Server) handleBulkEditMetadata(w http.ResponseWriter, r *http.Request) {
+	var req k8s.BulkMetadataOptions
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	result, err := k8s.BulkEditMetadata(r.Context(), req)
+	if err != nil {
+		if apierrors.IsForbidden(err) {
+			s.writeError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "not initialized") {
+			s.writeError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "failed to list") {
+			log.Printf("[bulk] Failed to resolve targets for %s: %v", req.Kind, err)
+			s.writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	s.writeJSON(w, result)
 }
 
-// handleDeleteResource deletes a Kubernetes resource
+// handleDeleteResource deletes a Kubernetes resource, cascading to its
+// dependents per ?propagation=background|foreground|orphan
 func (s *Server) handleDeleteResource(w http.ResponseWriter, r *http.Request) {
 	kind := chi.URLParam(r, "kind")
 	namespace := chi.URLParam(r, "namespace")
 	name := chi.URLParam(r, "name")
+	propagation, err := k8s.ParsePropagation(r.URL.Query().Get("propagation"))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	err := k8s.DeleteResource(r.Context(), kind, namespace, name)
+	err = k8s.DeleteResource(r.Context(), kind, namespace, name, propagation)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			s.writeError(w, http.StatusNotFound, err.Error())
@@ -1255,6 +1900,44 @@ func (s *Server) handleDeleteResource(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// handleDeletePreview lists the dependents deleting a resource would cascade
+// to, or orphan, under the given propagation policy (default: the API
+// server's default for the kind)
+func (s *Server) handleDeletePreview(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	kind := chi.URLParam(r, "kind")
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+	group := r.URL.Query().Get("group") // API group for CRD disambiguation
+	if namespace == "_" {
+		namespace = ""
+	}
+	propagation, err := k8s.ParsePropagation(r.URL.Query().Get("propagation"))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Resource cache not available")
+		return
+	}
+
+	preview, err := cache.PreviewDelete(r.Context(), kind, group, namespace, name, propagation)
+	if err != nil {
+		if strings.Contains(err.Error(), "unknown resource kind") {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	s.writeJSON(w, preview)
+}
+
 // handleTriggerCronJob creates a Job from a CronJob
 func (s *Server) handleTriggerCronJob(w http.ResponseWriter, r *http.Request) {
 	namespace := chi.URLParam(r, "namespace")
@@ -1456,28 +2139,15 @@ func (s *Server) handleSwitchContext(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Stop all active sessions before switching
-	StopAllSessions()
-
-	// Perform the context switch
-	if err := k8s.PerformContextSwitch(name); err != nil {
-		k8s.SetConnectionStatus(k8s.ConnectionStatus{
-			State:     k8s.StateDisconnected,
-			Context:   name,
-			Error:     err.Error(),
-			ErrorType: k8s.ClassifyError(err),
-		})
+	// Stop all active sessions and perform the context switch
+	reconnectMu.Lock()
+	err = reconnect(name)
+	reconnectMu.Unlock()
+	if err != nil {
 		s.writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	// Set connected state after successful switch
-	k8s.SetConnectionStatus(k8s.ConnectionStatus{
-		State:       k8s.StateConnected,
-		Context:     k8s.GetContextName(),
-		ClusterName: k8s.GetClusterName(),
-	})
-
 	// Return the new cluster info
 	info, err := k8s.GetClusterInfo(r.Context())
 	if err != nil {
@@ -1495,14 +2165,47 @@ func (s *Server) handleConnectionStatus(w http.ResponseWriter, r *http.Request)
 	status := k8s.GetConnectionStatus()
 	contexts, _ := k8s.GetAvailableContexts() // Always works (reads kubeconfig)
 
-	s.writeJSON(w, map[string]any{
+	resp := map[string]any{
 		"state":           status.State,
 		"context":         status.Context,
 		"clusterName":     status.ClusterName,
 		"error":           status.Error,
 		"errorType":       status.ErrorType,
 		"progressMessage": status.ProgressMsg,
+		"flapping":        status.Flapping,
 		"contexts":        contexts,
+	}
+	if status.TokenExpiry != nil {
+		resp["tokenExpiry"] = status.TokenExpiry
+		resp["tokenExpiresIn"] = max(0, int(time.Until(*status.TokenExpiry).Seconds()))
+		resp["tokenWarning"] = status.TokenWarning
+	}
+	s.writeJSON(w, resp)
+}
+
+// handleConnectionHistory returns connection state transitions for a context
+// (default: the current one; "*" for all) and whether it's flapping.
+func (s *Server) handleConnectionHistory(w http.ResponseWriter, r *http.Request) {
+	loc, err := parseTimezone(r.URL.Query())
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	contextName := r.URL.Query().Get("context")
+	if contextName == "" {
+		contextName = k8s.GetConnectionStatus().Context
+	}
+	if contextName == "*" {
+		contextName = ""
+	}
+	transitions := k8s.GetConnectionHistory(contextName)
+	for i := range transitions {
+		transitions[i].Time = transitions[i].Time.In(loc)
+	}
+	s.writeJSON(w, map[string]any{
+		"context":     contextName,
+		"flapping":    contextName != "" && k8s.ConnectionFlapping(contextName),
+		"transitions": transitions,
 	})
 }
 
@@ -1513,32 +2216,46 @@ func (s *Server) handleConnectionRetry(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Stop all active sessions before retrying
-	StopAllSessions()
-
 	// Reconnect to the same context (reuses PerformContextSwitch which handles full reinit)
-	if err := k8s.PerformContextSwitch(ctx); err != nil {
-		// Set disconnected state with error
-		k8s.SetConnectionStatus(k8s.ConnectionStatus{
-			State:     k8s.StateDisconnected,
-			Context:   ctx,
-			Error:     err.Error(),
-			ErrorType: k8s.ClassifyError(err),
-		})
+	reconnectMu.Lock()
+	err := reconnect(ctx)
+	reconnectMu.Unlock()
+	if err != nil {
 		s.writeError(w, http.StatusServiceUnavailable, err.Error())
 		return
 	}
 
-	// Set connected state after successful reconnection
-	k8s.SetConnectionStatus(k8s.ConnectionStatus{
-		State:       k8s.StateConnected,
-		Context:     k8s.GetContextName(),
-		ClusterName: k8s.GetClusterName(),
-	})
-
 	s.writeJSON(w, k8s.GetConnectionStatus())
 }
 
+// handleRefreshCredentials re-runs the kubeconfig's exec credential plugin or
+// reloads its token, e.g. after an expiry or an RBAC elevation, without
+// restarting informers. When disconnected it falls back to a full reconnect.
+func (s *Server) handleRefreshCredentials(w http.ResponseWriter, r *http.Request) {
+	if !k8s.IsConnected() {
+		s.handleConnectionRetry(w, r)
+		return
+	}
+
+	cred, err := k8s.RefreshCredentials(r.Context())
+	if errors.Is(err, k8s.ErrCredentialNotRefreshable) {
+		s.writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		s.writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	log.Printf("[credentials] Refreshed %s credentials for context %s", cred.Source, k8s.GetContextName())
+
+	// Re-broadcast the connection status so clients see the new expiry
+	k8s.SetConnectionStatus(k8s.GetConnectionStatus())
+	s.writeJSON(w, map[string]any{
+		"credentials": cred,
+		"connection":  k8s.GetConnectionStatus(),
+	})
+}
+
 // Helper methods
 
 func (s *Server) writeJSON(w http.ResponseWriter, data any) {
@@ -1550,11 +2267,12 @@ func (s *Server) writeJSON(w http.ResponseWriter, data any) {
 }
 
 func (s *Server) writeError(w http.ResponseWriter, status int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	if err := json.NewEncoder(w).Encode(map[string]string{"error": message}); err != nil {
-		log.Printf("Failed to encode error response: %v", err)
+	// Most 503s ("... not available") come down to a missing cluster connection
+	if status == http.StatusServiceUnavailable && !k8s.IsConnected() {
+		apierror.WriteCode(w, status, apierror.NotConnected, message)
+		return
 	}
+	apierror.Write(w, status, message)
 }
 
 // requireConnected returns false and writes a 503 error if not connected to cluster.
@@ -1590,14 +2308,24 @@ func (s *Server) handleDebugEventsDiagnose(w http.ResponseWriter, r *http.Reques
 	s.writeJSON(w, response)
 }
 
+// handleDebugStartup returns the duration of each initialization phase of the
+// last startup or context switch (requires --startup-trace)
+func (s *Server) handleDebugStartup(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, k8s.GetStartupTrace())
+}
+
 // handleDebugInformers returns the list of dynamic informers currently running
+// and watch relist counters per kind
 func (s *Server) handleDebugInformers(w http.ResponseWriter, r *http.Request) {
 	dynCache := k8s.GetDynamicResourceCache()
 	if dynCache == nil {
 		s.writeJSON(w, map[string]any{
-			"typedInformers":   16,
-			"dynamicInformers": 0,
-			"watchedResources": []string{},
+			"typedInformers":      16,
+			"dynamicInformers":    0,
+			"maxDynamicInformers": k8s.GetMaxDynamicInformers(),
+			"watchedResources":    []string{},
+			"fallbackResources":   []k8s.FallbackResource{},
+			"relists":             k8s.GetRelistStats(),
 		})
 		return
 	}
@@ -1613,8 +2341,11 @@ func (s *Server) handleDebugInformers(w http.ResponseWriter, r *http.Request) {
 	}
 
 	s.writeJSON(w, map[string]any{
-		"typedInformers":   16,
-		"dynamicInformers": len(gvrs),
-		"watchedResources": resources,
+		"typedInformers":      16,
+		"dynamicInformers":    len(gvrs),
+		"maxDynamicInformers": k8s.GetMaxDynamicInformers(), // 0 = unlimited
+		"watchedResources":    resources,
+		"fallbackResources":   dynCache.GetFallbackResources(),
+		"relists":             k8s.GetRelistStats(),
 	})
 }
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// handleDiagnoseService checks a Service for routing misconfigurations
+// GET /api/services/{namespace}/{name}/diagnose?clientKind=Deployment&clientNamespace=X&clientName=Y
+func (s *Server) handleDiagnoseService(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	var clientRef *k8s.ServiceClientRef
+	q := r.URL.Query()
+	if clientName := q.Get("clientName"); clientName != "" {
+		clientRef = &k8s.ServiceClientRef{
+			Kind:      q.Get("clientKind"),
+			Namespace: q.Get("clientNamespace"),
+			Name:      clientName,
+		}
+		if clientRef.Kind == "" {
+			clientRef.Kind = "Deployment"
+		}
+	}
+
+	diagnosis, err := k8s.DiagnoseService(r.Context(), namespace, name, clientRef)
+	if err != nil {
+		if errors.Is(err, k8s.ErrUnsupportedClientKind) {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if apierrors.IsNotFound(err) {
+			s.writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if apierrors.IsForbidden(err) {
+			s.writeError(w, http.StatusForbidden, err.Error())
+			return
+		}
+		log.Printf("[diagnose] Failed to diagnose service %s/%s: %v", namespace, name, err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.writeJSON(w, diagnosis)
+}
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SessionLimits bounds interactive sessions (exec terminals and port
+// forwards). Zero values disable a limit.
+type SessionLimits struct {
+	MaxExecPerClient         int           // Concurrent exec sessions per client
+	MaxPortForwardsPerClient int           // Concurrent port forwards per client
+	ExecIdleTimeout          time.Duration // Close terminals with no input or output for this long
+	PortForwardIdleTimeout   time.Duration // Stop port forwards with no new connections for this long
+}
+
+// clientID identifies the browser/user a session belongs to, for per-client
+// limits. Behind a proxy (in-cluster with an ingress) the forwarded address
+// is used; it's a fairness limit, not an access control.
+func clientID(r *http.Request) string {
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		first, _, _ := strings.Cut(fwd, ",")
+		return strings.TrimSpace(first)
+	}
+	if real := r.Header.Get("X-Real-IP"); real != "" {
+		return strings.TrimSpace(real)
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// idleCheckInterval is how often a session is checked against its idle timeout
+func idleCheckInterval(timeout time.Duration) time.Duration {
+	return min(max(timeout/4, time.Second), 30*time.Second)
+}
+
+func countExecSessions(client string) int {
+	execManager.mu.RLock()
+	defer execManager.mu.RUnlock()
+	n := 0
+	for _, session := range execManager.sessions {
+		if session.Client == client {
+			n++
+		}
+	}
+	return n
+}
+
+func countPortForwards(client string) int {
+	pfManager.mu.RLock()
+	defer pfManager.mu.RUnlock()
+	n := 0
+	for _, session := range pfManager.sessions {
+		if session.Client == client {
+			n++
+		}
+	}
+	return n
+}
+
+// reapIdlePortForwards stops port forwards that haven't handled a new
+// connection within the idle timeout. Runs for the server's lifetime.
+func reapIdlePortForwards(timeout time.Duration) {
+	ticker := time.NewTicker(idleCheckInterval(timeout))
+	defer ticker.Stop()
+	for range ticker.C {
+		pfManager.mu.Lock()
+		for id, session := range pfManager.sessions {
+			if session.Status == "running" && time.Since(session.LastActivity) > timeout {
+				log.Printf("Port forward %s idle for %s, stopping", id, timeout)
+				stopPortForwardLocked(session)
+			}
+		}
+		pfManager.mu.Unlock()
+	}
+}
+
+// ActiveSessions lists interactive sessions for the management API
+type ActiveSessions struct {
+	Exec         []ExecSessionInfo     `json:"exec"`
+	PortForwards []*PortForwardSession `json:"portForwards"`
+	Limits       SessionLimitsInfo     `json:"limits"`
+}
+
+// SessionLimitsInfo reports the configured limits (0 = unlimited)
+type SessionLimitsInfo struct {
+	MaxExecPerClient         int    `json:"maxExecPerClient"`
+	MaxPortForwardsPerClient int    `json:"maxPortForwardsPerClient"`
+	ExecIdleTimeout          string `json:"execIdleTimeout,omitempty"`
+	PortForwardIdleTimeout   string `json:"portForwardIdleTimeout,omitempty"`
+}
+
+// handleListActiveSessions lists all exec sessions and port forwards with
+// their owning client and last activity
+func (s *Server) handleListActiveSessions(w http.ResponseWriter, r *http.Request) {
+	execManager.mu.RLock()
+	execSessions := make([]*ExecSession, 0, len(execManager.sessions))
+	for _, session := range execManager.sessions {
+		execSessions = append(execSessions, session)
+	}
+	execManager.mu.RUnlock()
+
+	resp := ActiveSessions{
+		Exec:         make([]ExecSessionInfo, 0, len(execSessions)),
+		PortForwards: []*PortForwardSession{},
+		Limits: SessionLimitsInfo{
+			MaxExecPerClient:         s.sessionLimits.MaxExecPerClient,
+			MaxPortForwardsPerClient: s.sessionLimits.MaxPortForwardsPerClient,
+		},
+	}
+	if s.sessionLimits.ExecIdleTimeout > 0 {
+		resp.Limits.ExecIdleTimeout = s.sessionLimits.ExecIdleTimeout.String()
+	}
+	if s.sessionLimits.PortForwardIdleTimeout > 0 {
+		resp.Limits.PortForwardIdleTimeout = s.sessionLimits.PortForwardIdleTimeout.String()
+	}
+	for _, session := range execSessions {
+		resp.Exec = append(resp.Exec, session.info())
+	}
+
+	pfManager.mu.RLock()
+	for _, session := range pfManager.sessions {
+		copied := *session
+		resp.PortForwards = append(resp.PortForwards, &copied)
+	}
+	pfManager.mu.RUnlock()
+
+	s.writeJSON(w, resp)
+}
+
+// handleCloseSession force-closes an exec session or port forward by ID
+func (s *Server) handleCloseSession(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if strings.HasPrefix(id, "pf-") {
+		pfManager.mu.Lock()
+		session, ok := pfManager.sessions[id]
+		if ok {
+			stopPortForwardLocked(session)
+		}
+		pfManager.mu.Unlock()
+		if !ok {
+			s.writeError(w, http.StatusNotFound, "session not found")
+			return
+		}
+		log.Printf("Port forward %s force-closed by %s", id, clientID(r))
+		s.writeJSON(w, map[string]string{"status": "stopped"})
+		return
+	}
+
+	execManager.mu.RLock()
+	session, ok := execManager.sessions[id]
+	execManager.mu.RUnlock()
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "session not found")
+		return
+	}
+	log.Printf("Exec session %s force-closed by %s", id, clientID(r))
+	session.close("closed_by_admin", fmt.Sprintf("Session closed by %s", clientID(r)))
+	s.writeJSON(w, map[string]string{"status": "stopped"})
+}
@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/skyhook-io/radar/internal/apierror"
 	"github.com/skyhook-io/radar/internal/k8s"
+	"github.com/skyhook-io/radar/internal/timeline"
 	"github.com/skyhook-io/radar/internal/topology"
 )
 
@@ -28,23 +31,38 @@ type SSEBroadcaster struct {
 	// Cached topology for relationship lookups (updated on each topology rebuild)
 	cachedTopology   *topology.Topology
 	cachedTopologyMu sync.RWMutex
+
+	// Cached dashboard payloads, invalidated by resource changes
+	dashboard *dashboardCache
+
+	// Event stream recording and replay (see recording.go)
+	recorder        *eventRecorder
+	replayLog       []recordedEntry
+	replaySpeed     float64
+	replaying       bool // Live changes and timeline events are not sent while replaying
+	firstClient     chan struct{}
+	firstClientOnce sync.Once
 }
 
 // ClientInfo stores information about a connected client
 type ClientInfo struct {
 	Namespaces []string // Filter to specific namespaces (empty = all)
 	ViewMode   string   // "full" or "traffic"
+	// Severities opts the client into timeline_event messages at these severities
+	// (empty = no timeline events, keeping the default stream lean)
+	Severities []timeline.Severity
 }
 
 type clientRegistration struct {
 	ch         chan SSEEvent
 	namespaces []string
 	viewMode   string
+	severities []timeline.Severity
 }
 
 // SSEEvent represents an event to send to clients
 type SSEEvent struct {
-	Event string `json:"event"` // "topology", "k8s_event", "heartbeat"
+	Event string `json:"event"` // "topology", "k8s_event", "timeline_event", "heartbeat"
 	Data  any    `json:"data"`
 }
 
@@ -63,10 +81,12 @@ func safeSend(ch chan SSEEvent, event SSEEvent) {
 // NewSSEBroadcaster creates a new SSE broadcaster
 func NewSSEBroadcaster() *SSEBroadcaster {
 	return &SSEBroadcaster{
-		clients:    make(map[chan SSEEvent]ClientInfo),
-		register:   make(chan clientRegistration),
-		unregister: make(chan chan SSEEvent),
-		stopCh:     make(chan struct{}),
+		clients:     make(map[chan SSEEvent]ClientInfo),
+		register:    make(chan clientRegistration),
+		unregister:  make(chan chan SSEEvent),
+		stopCh:      make(chan struct{}),
+		dashboard:   newDashboardCache(),
+		firstClient: make(chan struct{}),
 	}
 }
 
@@ -88,7 +108,11 @@ func (b *SSEBroadcaster) Start() {
 
 	go b.run()
 	go b.watchResourceChanges()
+	go b.watchTimelineEvents()
 	go b.heartbeat()
+	if b.replaying {
+		go b.replay()
+	}
 }
 
 // registerCRDDiscoveryCallback registers for CRD discovery completion
@@ -116,6 +140,7 @@ func (b *SSEBroadcaster) registerConnectionStateCallback() {
 				"error":           status.Error,
 				"errorType":       status.ErrorType,
 				"progressMessage": status.ProgressMsg,
+				"flapping":        status.Flapping,
 			},
 		})
 
@@ -148,6 +173,7 @@ func (b *SSEBroadcaster) registerContextSwitchCallback() {
 		b.cachedTopologyMu.Lock()
 		b.cachedTopology = nil
 		b.cachedTopologyMu.Unlock()
+		b.dashboard.clear()
 
 		// Broadcast context_changed event to all clients
 		b.mu.RLock()
@@ -188,6 +214,11 @@ func (b *SSEBroadcaster) initCachedTopology() {
 // Stop gracefully shuts down the broadcaster
 func (b *SSEBroadcaster) Stop() {
 	close(b.stopCh)
+	if b.recorder != nil {
+		if err := b.recorder.Close(); err != nil {
+			log.Printf("[record] Failed to close recording: %v", err)
+		}
+	}
 }
 
 func (b *SSEBroadcaster) run() {
@@ -211,8 +242,9 @@ func (b *SSEBroadcaster) run() {
 				close(reg.ch) // Signal rejection by closing the channel
 				continue
 			}
-			b.clients[reg.ch] = ClientInfo{Namespaces: reg.namespaces, ViewMode: reg.viewMode}
+			b.clients[reg.ch] = ClientInfo{Namespaces: reg.namespaces, ViewMode: reg.viewMode, Severities: reg.severities}
 			b.mu.Unlock()
+			b.firstClientOnce.Do(func() { close(b.firstClient) })
 			log.Printf("SSE client connected (namespaces=%v, view=%s), total clients: %d", reg.namespaces, reg.viewMode, len(b.clients))
 
 		case ch := <-b.unregister:
@@ -227,55 +259,57 @@ func (b *SSEBroadcaster) run() {
 	}
 }
 
-// watchResourceChanges listens for K8s resource changes and broadcasts topology updates
+// watchResourceChanges listens for K8s resource changes and broadcasts topology updates.
+// The resource cache may not exist yet at startup and is replaced on context
+// switch, so it waits for a cache and resubscribes whenever the channel closes.
 func (b *SSEBroadcaster) watchResourceChanges() {
-	cache := k8s.GetResourceCache()
-	if cache == nil {
-		log.Println("Warning: Resource cache not available for SSE broadcasts")
-		return
-	}
-
-	changes := cache.Changes()
-	if changes == nil {
-		return
+	waitLogged := false
+	for {
+		changes := k8s.GetResourceCache().Changes()
+		if changes == nil {
+			if !waitLogged {
+				log.Println("SSE broadcaster: waiting for resource cache")
+				waitLogged = true
+			}
+			select {
+			case <-b.stopCh:
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		waitLogged = false
+		if !b.consumeResourceChanges(changes) {
+			return
+		}
 	}
+}
 
+// consumeResourceChanges broadcasts changes until the channel closes (returns
+// true) or the broadcaster stops (returns false)
+func (b *SSEBroadcaster) consumeResourceChanges(changes <-chan k8s.ResourceChange) bool {
 	// Debounce changes - wait for 100ms of quiet before sending topology update
 	debounceTimer := time.NewTimer(0)
 	<-debounceTimer.C // drain initial timer
+	defer debounceTimer.Stop()
 	pendingUpdate := false
 
 	for {
 		select {
 		case <-b.stopCh:
-			return
+			return false
 
 		case change, ok := <-changes:
 			if !ok {
-				return
+				return true
 			}
 
-			// Broadcast K8s event immediately for important events
-			if change.Kind == "Event" || change.Operation == "delete" ||
-				(change.Kind == "Pod" && change.Operation != "update") ||
-				change.Diff != nil { // Also broadcast updates with meaningful diffs
-				eventData := map[string]any{
-					"kind":      change.Kind,
-					"namespace": change.Namespace,
-					"name":      change.Name,
-					"operation": change.Operation,
-				}
-				// Include diff info if available
-				if change.Diff != nil {
-					eventData["diff"] = map[string]any{
-						"fields":  change.Diff.Fields,
-						"summary": change.Diff.Summary,
-					}
-				}
-				b.Broadcast(SSEEvent{
-					Event: "k8s_event",
-					Data:  eventData,
-				})
+			b.dashboard.invalidate(change)
+			if b.recorder != nil {
+				b.recorder.recordChange(change)
+			}
+			if !b.replaying {
+				b.broadcastResourceChange(change)
 			}
 
 			// Schedule debounced topology update (500ms to reduce UI thrashing)
@@ -293,6 +327,73 @@ func (b *SSEBroadcaster) watchResourceChanges() {
 	}
 }
 
+// broadcastResourceChange sends a k8s_event for changes the UI reacts to immediately
+func (b *SSEBroadcaster) broadcastResourceChange(change k8s.ResourceChange) {
+	// Broadcast K8s event immediately for important events
+	if change.Kind == "Event" || change.Operation == "delete" ||
+		(change.Kind == "Pod" && change.Operation != "update") ||
+		change.Diff != nil { // Also broadcast updates with meaningful diffs
+		eventData := map[string]any{
+			"kind":      change.Kind,
+			"namespace": change.Namespace,
+			"name":      change.Name,
+			"operation": change.Operation,
+		}
+		// Include diff info if available
+		if change.Diff != nil {
+			eventData["diff"] = map[string]any{
+				"fields":  change.Diff.Fields,
+				"summary": change.Diff.Summary,
+			}
+		}
+		b.Broadcast(SSEEvent{
+			Event: "k8s_event",
+			Data:  eventData,
+		})
+	}
+}
+
+// watchTimelineEvents forwards recorded timeline events, which carry a severity,
+// to clients that asked for them
+func (b *SSEBroadcaster) watchTimelineEvents() {
+	events, unsubscribe := timeline.Subscribe()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-b.stopCh:
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if b.recorder != nil {
+				b.recorder.recordTimelineEvent(event)
+			}
+			if !b.replaying {
+				b.broadcastTimelineEvent(event)
+			}
+		}
+	}
+}
+
+// broadcastTimelineEvent sends a timeline event to clients whose namespace and severity filters match
+func (b *SSEBroadcaster) broadcastTimelineEvent(event timeline.TimelineEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch, info := range b.clients {
+		if len(info.Severities) == 0 || !event.MatchesSeverity(info.Severities) {
+			continue
+		}
+		// Cluster-scoped events (no namespace) go to every client
+		if event.Namespace != "" && len(info.Namespaces) > 0 && !slices.Contains(info.Namespaces, event.Namespace) {
+			continue
+		}
+		safeSend(ch, SSEEvent{Event: "timeline_event", Data: event})
+	}
+}
+
 // broadcastTopologyUpdate sends the current topology to all clients
 func (b *SSEBroadcaster) broadcastTopologyUpdate() {
 	b.mu.RLock()
@@ -401,7 +502,7 @@ func (b *SSEBroadcaster) Broadcast(event SSEEvent) {
 }
 
 // Subscribe adds a new SSE client. Returns nil if max clients reached.
-func (b *SSEBroadcaster) Subscribe(namespaces []string, viewMode string) chan SSEEvent {
+func (b *SSEBroadcaster) Subscribe(namespaces []string, viewMode string, severities []timeline.Severity) chan SSEEvent {
 	// Check client count before creating the channel to fail fast
 	b.mu.RLock()
 	clientCount := len(b.clients)
@@ -418,7 +519,7 @@ func (b *SSEBroadcaster) Subscribe(namespaces []string, viewMode string) chan SS
 	sort.Strings(sortedNs)
 
 	ch := make(chan SSEEvent, 10)
-	b.register <- clientRegistration{ch: ch, namespaces: sortedNs, viewMode: viewMode}
+	b.register <- clientRegistration{ch: ch, namespaces: sortedNs, viewMode: viewMode, severities: severities}
 	return ch
 }
 
@@ -456,6 +557,11 @@ func (b *SSEBroadcaster) HandleSSE(w http.ResponseWriter, r *http.Request) {
 	if viewMode == "" {
 		viewMode = "full"
 	}
+	severities, err := parseSeverities(r.URL.Query())
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	// Ensure we can flush
 	flusher, ok := w.(http.Flusher)
@@ -465,9 +571,9 @@ func (b *SSEBroadcaster) HandleSSE(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Subscribe to events
-	eventCh := b.Subscribe(namespaces, viewMode)
+	eventCh := b.Subscribe(namespaces, viewMode, severities)
 	if eventCh == nil {
-		http.Error(w, "Too many SSE connections", http.StatusServiceUnavailable)
+		apierror.Write(w, http.StatusServiceUnavailable, "Too many SSE connections")
 		return
 	}
 	defer b.Unsubscribe(eventCh)
@@ -0,0 +1,25 @@
+package server
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// handleStorageUsage returns node filesystem/imagefs usage and per-pod
+// ephemeral storage usage from the kubelet summary API
+// GET /api/storage/ephemeral?namespaces=a,b
+func (s *Server) handleStorageUsage(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	usage, err := k8s.GetStorageUsage(r.Context(), parseNamespaces(r.URL.Query()))
+	if err != nil {
+		log.Printf("[storage] Failed to get storage usage: %v", err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.writeJSON(w, usage)
+}
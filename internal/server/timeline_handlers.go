@@ -0,0 +1,300 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/outbound"
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// parseSeverities parses the comma-separated "severity" query parameter
+func parseSeverities(query url.Values) ([]timeline.Severity, error) {
+	param := query.Get("severity")
+	if param == "" {
+		return nil, nil
+	}
+	var severities []timeline.Severity
+	for _, part := range strings.Split(param, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		sev, ok := timeline.ParseSeverity(part)
+		if !ok {
+			return nil, fmt.Errorf("invalid severity %q: must be info, warning, or critical", part)
+		}
+		severities = append(severities, sev)
+	}
+	return severities, nil
+}
+
+// parseChangesFilters parses the event filters shared by the changes endpoints:
+// namespaces, kind, filter (preset, default "default"), severity, context,
+// include_k8s_events (default true), and include_managed (default false)
+func parseChangesFilters(query url.Values) (timeline.QueryOptions, error) {
+	severities, err := parseSeverities(query)
+	if err != nil {
+		return timeline.QueryOptions{}, err
+	}
+	opts := timeline.QueryOptions{
+		Namespaces:       parseNamespaces(query),
+		IncludeManaged:   query.Get("include_managed") == "true",
+		IncludeK8sEvents: query.Get("include_k8s_events") != "false",
+		FilterPreset:     query.Get("filter"),
+		Severities:       severities,
+		Context:          query.Get("context"), // "" = current context, "*" = all
+	}
+	if opts.FilterPreset == "" {
+		opts.FilterPreset = timeline.PresetDefault
+	}
+	if kind := query.Get("kind"); kind != "" {
+		opts.Kinds = []string{kind}
+	}
+	return opts, nil
+}
+
+// handleChangesAggregate returns time-bucketed event counts grouped by
+// dimensions, for charting event volume without fetching raw events. Accepts
+// the /api/changes filters plus since (default 6h ago), until (default now),
+// groupBy (comma-separated: kind, namespace, severity, source, eventType,
+// reason, context), bucket (Go duration, default ~60 buckets), and top.
+// GET /api/changes/aggregate?groupBy=namespace&since=...&bucket=5m&top=10
+func (s *Server) handleChangesAggregate(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	q := r.URL.Query()
+	query, err := parseChangesFilters(q)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	loc, err := parseTimezone(q)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	opts := timeline.AggregateOptions{Query: query}
+
+	opts.Query.Until = time.Now()
+	if v := q.Get("until"); v != "" {
+		if opts.Query.Until, err = time.Parse(time.RFC3339, v); err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid until: expected RFC3339")
+			return
+		}
+	}
+	opts.Query.Since = opts.Query.Until.Add(-6 * time.Hour)
+	if v := q.Get("since"); v != "" {
+		if opts.Query.Since, err = time.Parse(time.RFC3339, v); err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid since: expected RFC3339")
+			return
+		}
+	}
+	if v := q.Get("groupBy"); v != "" {
+		for _, dim := range strings.Split(v, ",") {
+			if dim = strings.TrimSpace(dim); dim != "" {
+				opts.GroupBy = append(opts.GroupBy, dim)
+			}
+		}
+	}
+	if v := q.Get("bucket"); v != "" {
+		if opts.Bucket, err = time.ParseDuration(v); err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid bucket: expected a duration like 5m")
+			return
+		}
+	}
+	if v := q.Get("top"); v != "" {
+		if opts.Top, err = strconv.Atoi(v); err != nil || opts.Top < 0 {
+			s.writeError(w, http.StatusBadRequest, "invalid top: expected a non-negative integer")
+			return
+		}
+	}
+
+	store := timeline.GetStore()
+	if store == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Timeline store not available")
+		return
+	}
+	result, err := timeline.Aggregate(r.Context(), store, opts)
+	if errors.Is(err, timeline.ErrInvalidAggregate) {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("[timeline] Failed to aggregate events: %v", err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	result.InZone(loc)
+	s.writeJSON(w, result)
+}
+
+// handleListFilterPresets returns built-in and custom timeline filter presets
+// GET /api/timeline/presets
+func (s *Server) handleListFilterPresets(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, timeline.ListFilterPresets())
+}
+
+// handleSaveFilterPreset creates or replaces a custom timeline filter preset
+// PUT /api/timeline/presets/{name}
+func (s *Server) handleSaveFilterPreset(w http.ResponseWriter, r *http.Request) {
+	var preset timeline.FilterPreset
+	if err := json.NewDecoder(r.Body).Decode(&preset); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	preset.Name = chi.URLParam(r, "name")
+
+	if err := timeline.SaveFilterPreset(preset); err != nil {
+		switch {
+		case errors.Is(err, timeline.ErrInvalidPreset):
+			s.writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, timeline.ErrBuiltinPreset):
+			s.writeError(w, http.StatusConflict, err.Error())
+		default:
+			log.Printf("[timeline] Failed to save filter preset %q: %v", preset.Name, err)
+			s.writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	saved, _ := timeline.LookupFilterPreset(preset.Name)
+	s.writeJSON(w, saved)
+}
+
+// handleDeleteFilterPreset removes a custom timeline filter preset
+// DELETE /api/timeline/presets/{name}
+func (s *Server) handleDeleteFilterPreset(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if err := timeline.DeleteFilterPreset(name); err != nil {
+		switch {
+		case errors.Is(err, timeline.ErrPresetNotFound):
+			s.writeError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, timeline.ErrBuiltinPreset):
+			s.writeError(w, http.StatusConflict, err.Error())
+		default:
+			log.Printf("[timeline] Failed to delete filter preset %q: %v", name, err)
+			s.writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	s.writeJSON(w, map[string]string{"status": "deleted"})
+}
+
+// handleListSavedQueries returns saved timeline queries
+// GET /api/timeline/queries
+func (s *Server) handleListSavedQueries(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, timeline.ListSavedQueries())
+}
+
+// handleSaveSavedQuery creates or replaces a saved timeline query
+// PUT /api/timeline/queries/{name}
+func (s *Server) handleSaveSavedQuery(w http.ResponseWriter, r *http.Request) {
+	var query timeline.SavedQuery
+	if err := json.NewDecoder(r.Body).Decode(&query); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	query.Name = chi.URLParam(r, "name")
+
+	if err := timeline.SaveSavedQuery(query); err != nil {
+		if errors.Is(err, timeline.ErrInvalidSavedQuery) {
+			s.writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("[timeline] Failed to save query %q: %v", query.Name, err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	saved, _ := timeline.LookupSavedQuery(query.Name)
+	s.writeJSON(w, saved)
+}
+
+// handleDeleteSavedQuery removes a saved timeline query
+// DELETE /api/timeline/queries/{name}
+func (s *Server) handleDeleteSavedQuery(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if err := timeline.DeleteSavedQuery(name); err != nil {
+		if errors.Is(err, timeline.ErrSavedQueryNotFound) {
+			s.writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		log.Printf("[timeline] Failed to delete query %q: %v", name, err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.writeJSON(w, map[string]string{"status": "deleted"})
+}
+
+// handleSavedQueryDigest previews a saved query's digest for the period ending
+// now (?schedule=daily|weekly overrides the query's own schedule)
+// GET /api/timeline/queries/{name}/digest
+func (s *Server) handleSavedQueryDigest(w http.ResponseWriter, r *http.Request) {
+	query, digest, ok := s.buildSavedQueryDigest(w, r)
+	if !ok {
+		return
+	}
+	s.writeJSON(w, map[string]any{"digest": digest, "text": digest.Text(query.Digest)})
+}
+
+// handleSendSavedQueryDigest sends a saved query's digest to its webhook now
+// POST /api/timeline/queries/{name}/digest
+func (s *Server) handleSendSavedQueryDigest(w http.ResponseWriter, r *http.Request) {
+	query, digest, ok := s.buildSavedQueryDigest(w, r)
+	if !ok {
+		return
+	}
+	if err := timeline.SendDigest(r.Context(), query, digest); err != nil {
+		switch {
+		case errors.Is(err, timeline.ErrNoDigestWebhook):
+			s.writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, outbound.ErrOffline):
+			s.writeError(w, http.StatusServiceUnavailable, err.Error())
+		default:
+			s.writeError(w, http.StatusBadGateway, err.Error())
+		}
+		return
+	}
+	s.writeJSON(w, digest)
+}
+
+func (s *Server) buildSavedQueryDigest(w http.ResponseWriter, r *http.Request) (timeline.SavedQuery, *timeline.Digest, bool) {
+	query, ok := timeline.LookupSavedQuery(chi.URLParam(r, "name"))
+	if !ok {
+		s.writeError(w, http.StatusNotFound, timeline.ErrSavedQueryNotFound.Error())
+		return query, nil, false
+	}
+	switch schedule := timeline.DigestSchedule(r.URL.Query().Get("schedule")); schedule {
+	case timeline.DigestDaily, timeline.DigestWeekly:
+		query.Digest = schedule
+	case timeline.DigestNone:
+		if query.Digest == timeline.DigestNone {
+			query.Digest = timeline.DigestDaily
+		}
+	default:
+		s.writeError(w, http.StatusBadRequest, "schedule must be daily or weekly")
+		return query, nil, false
+	}
+
+	store := timeline.GetStore()
+	if store == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Timeline store not available")
+		return query, nil, false
+	}
+	digest, err := timeline.BuildDigest(r.Context(), store, query, time.Now())
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return query, nil, false
+	}
+	return query, digest, true
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// tokenReadTimeout bounds reading one token file from a pod
+const tokenReadTimeout = 10 * time.Second
+
+// LiveToken is the decoded claims of a projected token read from the pod
+type LiveToken struct {
+	Volume    string           `json:"volume"`
+	Container string           `json:"container"`
+	Path      string           `json:"path"`
+	Claims    *k8s.TokenClaims `json:"claims,omitempty"`
+	Error     string           `json:"error,omitempty"`
+}
+
+// handleServiceAccountTokens reports bound vs legacy token usage per pod and
+// the cluster's long-lived token Secrets
+// GET /api/serviceaccount-tokens?namespace=shop
+func (s *Server) handleServiceAccountTokens(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	report, err := k8s.GetServiceAccountTokenReport(r.Context(), r.URL.Query().Get("namespace"))
+	if err != nil {
+		s.writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	s.writeJSON(w, report)
+}
+
+// handlePodTokens reports a pod's service account tokens. With ?live=true each
+// projected token is read from the pod and its claims (audiences, expiry) are
+// decoded; the token itself is never returned.
+// GET /api/pods/{namespace}/{name}/tokens
+func (s *Server) handlePodTokens(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+	report, ok := k8s.GetPodTokenReport(r.Context(), namespace, name)
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "pod not found")
+		return
+	}
+	if r.URL.Query().Get("live") != "true" {
+		s.writeJSON(w, report)
+		return
+	}
+
+	live := []LiveToken{}
+	for _, t := range report.Projected {
+		if len(t.Mounts) == 0 {
+			continue
+		}
+		container, mountPath, _ := strings.Cut(t.Mounts[0], ":")
+		lt := LiveToken{Volume: t.Volume, Container: container, Path: path.Join(mountPath, t.Path)}
+		lt.Claims, lt.Error = s.readPodToken(r.Context(), namespace, name, container, lt.Path)
+		live = append(live, lt)
+	}
+	s.writeJSON(w, map[string]any{"report": report, "live": live})
+}
+
+// readPodToken reads a token file in the container and decodes its claims
+func (s *Server) readPodToken(ctx context.Context, namespace, pod, container, file string) (*k8s.TokenClaims, string) {
+	ctx, cancel := context.WithTimeout(ctx, tokenReadTimeout)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	if err := s.execInPod(ctx, namespace, pod, container, []string{"cat", file}, nil, &stdout, &stderr); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, msg
+		}
+		if isShellNotFoundError(err.Error()) {
+			return nil, "cat not available in container " + container
+		}
+		return nil, err.Error()
+	}
+	claims, err := k8s.DecodeTokenClaims(stdout.String())
+	if err != nil {
+		return nil, err.Error()
+	}
+	return claims, ""
+}
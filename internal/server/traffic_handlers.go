@@ -46,12 +46,16 @@ func (s *Server) handleGetTrafficFlows(w http.ResponseWriter, r *http.Request) {
 	namespaces := parseNamespaces(r.URL.Query())
 	sinceStr := r.URL.Query().Get("since")
 
-	opts := traffic.DefaultFlowOptions()
-	// Traffic only supports single namespace filter
-	if len(namespaces) == 1 {
-		opts.Namespace = namespaces[0]
+	direction, err := traffic.ParseFlowDirection(r.URL.Query().Get("direction"))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
+	opts := traffic.DefaultFlowOptions()
+	opts.Namespaces = namespaces
+	opts.Direction = direction
+
 	if sinceStr != "" {
 		duration, err := time.ParseDuration(sinceStr)
 		if err != nil {
@@ -69,7 +73,7 @@ func (s *Server) handleGetTrafficFlows(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Aggregate flows by service pair
-	aggregated := traffic.AggregateFlows(response.Flows)
+	aggregated := traffic.AggregateFlowsOverWindow(response.Flows, opts.Since)
 
 	result := map[string]interface{}{
 		"source":     response.Source,
@@ -95,11 +99,16 @@ func (s *Server) handleTrafficFlowsStream(w http.ResponseWriter, r *http.Request
 	}
 
 	// Parse query parameters
-	namespace := r.URL.Query().Get("namespace")
+	direction, err := traffic.ParseFlowDirection(r.URL.Query().Get("direction"))
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	opts := traffic.FlowOptions{
-		Namespace: namespace,
-		Follow:    true,
+		Namespaces: parseNamespaces(r.URL.Query()),
+		Direction:  direction,
+		Follow:     true,
 	}
 
 	flowCh, err := manager.StreamFlows(ctx, opts)
@@ -249,3 +258,102 @@ func (s *Server) handleTrafficConnectionStatus(w http.ResponseWriter, r *http.Re
 	connInfo := manager.GetConnectionInfo()
 	s.writeJSON(w, connInfo)
 }
+
+// handleGetTrafficHistory lists recorded flow snapshots for the current context
+// GET /api/traffic/history?since=RFC3339&until=RFC3339 (default: last 24h)
+func (s *Server) handleGetTrafficHistory(w http.ResponseWriter, r *http.Request) {
+	manager := traffic.GetManager()
+	if manager == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Traffic manager not initialized")
+		return
+	}
+
+	cfg := traffic.GetHistoryConfig()
+	store := traffic.GetHistoryStore()
+	if store == nil {
+		s.writeJSON(w, map[string]any{
+			"enabled":   false,
+			"snapshots": []traffic.SnapshotSummary{},
+		})
+		return
+	}
+
+	until := time.Now()
+	since := until.Add(-24 * time.Hour)
+	if v := r.URL.Query().Get("since"); v != "" {
+		ts, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid 'since' timestamp (expected RFC3339)")
+			return
+		}
+		since = ts
+	}
+	if v := r.URL.Query().Get("until"); v != "" {
+		ts, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, "invalid 'until' timestamp (expected RFC3339)")
+			return
+		}
+		until = ts
+	}
+
+	snapshots, err := store.List(r.Context(), manager.ContextName(), since, until)
+	if err != nil {
+		log.Printf("[traffic] Failed to list flow history: %v", err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	s.writeJSON(w, map[string]any{
+		"enabled":   true,
+		"interval":  cfg.Interval.String(),
+		"retention": cfg.Retention.String(),
+		"snapshots": snapshots,
+	})
+}
+
+// handleGetTrafficSnapshot returns the recorded flows closest to (at or before) a timestamp
+// GET /api/traffic/history/snapshot?at=RFC3339
+func (s *Server) handleGetTrafficSnapshot(w http.ResponseWriter, r *http.Request) {
+	manager := traffic.GetManager()
+	if manager == nil {
+		s.writeError(w, http.StatusServiceUnavailable, "Traffic manager not initialized")
+		return
+	}
+
+	store := traffic.GetHistoryStore()
+	if store == nil {
+		s.writeError(w, http.StatusNotFound, "Traffic history recording is disabled (start with --traffic-history)")
+		return
+	}
+
+	atStr := r.URL.Query().Get("at")
+	if atStr == "" {
+		s.writeError(w, http.StatusBadRequest, "'at' query parameter is required")
+		return
+	}
+	at, err := time.Parse(time.RFC3339, atStr)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid 'at' timestamp (expected RFC3339)")
+		return
+	}
+
+	snap, err := store.At(r.Context(), manager.ContextName(), at)
+	if err != nil {
+		log.Printf("[traffic] Failed to load flow snapshot at %s: %v", atStr, err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	// Don't return a stale snapshot from long before the requested time
+	if snap == nil || at.Sub(snap.Timestamp) > 2*traffic.GetHistoryConfig().Interval {
+		s.writeError(w, http.StatusNotFound, fmt.Sprintf("no flow snapshot recorded near %s", atStr))
+		return
+	}
+
+	s.writeJSON(w, map[string]any{
+		"source":     snap.Source,
+		"timestamp":  snap.Timestamp,
+		"window":     snap.Window.String(),
+		"aggregated": snap.Flows,
+	})
+}
@@ -0,0 +1,210 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+)
+
+// vclusterTunnelTimeout bounds how long connect waits for the port-forward to be ready
+const vclusterTunnelTimeout = 10 * time.Second
+
+// vclusterTunnels are port-forwards to vcluster API servers, keyed by context name.
+// They're kept out of pfManager so StopAllPortForwards (run on every context
+// switch) doesn't tear down the tunnel the vcluster context depends on.
+var vclusterTunnels = struct {
+	mu       sync.Mutex
+	sessions map[string]*PortForwardSession
+}{sessions: make(map[string]*PortForwardSession)}
+
+// VClustersResponse lists detected vcluster instances
+type VClustersResponse struct {
+	VClusters []k8s.VCluster `json:"vclusters"`
+}
+
+// VClusterConnectResponse describes the context registered for a vcluster
+type VClusterConnectResponse struct {
+	Context   string `json:"context"` // Switch to it with POST /api/contexts/{name}
+	LocalPort int    `json:"localPort"`
+	PodName   string `json:"podName"`
+}
+
+// handleListVClusters returns vcluster instances and whether each is connected
+// GET /api/vclusters?namespaces=a,b
+func (s *Server) handleListVClusters(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+
+	vclusters, err := k8s.ListVClusters(parseNamespaces(r.URL.Query()))
+	if err != nil {
+		log.Printf("[vcluster] Failed to list vclusters: %v", err)
+		s.writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for i := range vclusters {
+		vclusters[i].Connected = vclusterTunnelRunning(vclusters[i].Context)
+	}
+
+	s.writeJSON(w, VClustersResponse{VClusters: vclusters})
+}
+
+// handleConnectVCluster port-forwards to a vcluster's API server and registers
+// its kubeconfig (from the vc-<name> secret) as a context pointing at the tunnel
+// POST /api/vclusters/{namespace}/{name}/connect
+func (s *Server) handleConnectVCluster(w http.ResponseWriter, r *http.Request) {
+	if !s.requireConnected(w) {
+		return
+	}
+	namespace := chi.URLParam(r, "namespace")
+	name := chi.URLParam(r, "name")
+
+	if k8s.IsInCluster() {
+		s.writeError(w, http.StatusBadRequest, "cannot switch context when running in-cluster")
+		return
+	}
+	contextName := k8s.VClusterContextName(namespace, name)
+	if contextName == k8s.GetContextName() {
+		s.writeError(w, http.StatusConflict, "already connected to this vcluster")
+		return
+	}
+
+	// Replace any previous tunnel; the vcluster pod may have been rescheduled
+	stopVClusterTunnel(contextName)
+
+	podName, err := findPodForService(r.Context(), namespace, name, k8s.VClusterPort)
+	if err != nil {
+		s.writeError(w, http.StatusNotFound, fmt.Sprintf("No running vcluster pod found for %s/%s: %v", namespace, name, err))
+		return
+	}
+	localPort, err := findFreePort()
+	if err != nil {
+		s.writeError(w, http.StatusInternalServerError, "Failed to find free port")
+		return
+	}
+
+	// The tunnel must outlive the request, so it isn't canceled with it
+	ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+	session := &PortForwardSession{
+		ID:            "vcluster-" + namespace + "-" + name,
+		Namespace:     namespace,
+		PodName:       podName,
+		PodPort:       k8s.VClusterPort,
+		LocalPort:     localPort,
+		ListenAddress: "127.0.0.1",
+		ServiceName:   name,
+		StartedAt:     time.Now(),
+		Status:        "starting",
+		cancel:        cancel,
+		stopCh:        make(chan struct{}),
+	}
+	vclusterTunnels.mu.Lock()
+	vclusterTunnels.sessions[contextName] = session
+	vclusterTunnels.mu.Unlock()
+
+	go func() {
+		err := runPortForward(ctx, session)
+		pfManager.mu.Lock()
+		if err != nil {
+			session.Status = "error"
+			session.Error = err.Error()
+			log.Printf("[vcluster] Tunnel to %s/%s failed: %v", namespace, name, err)
+		} else {
+			session.Status = "stopped"
+		}
+		pfManager.mu.Unlock()
+	}()
+
+	if err := waitForTunnel(session); err != nil {
+		stopVClusterTunnel(contextName)
+		s.writeError(w, http.StatusBadGateway, fmt.Sprintf("Failed to port-forward to vcluster: %v", err))
+		return
+	}
+
+	// vcluster certificates are issued for localhost, so keep that host name
+	server := fmt.Sprintf("https://localhost:%d", localPort)
+	if _, err := k8s.RegisterVClusterContext(r.Context(), namespace, name, server); err != nil {
+		stopVClusterTunnel(contextName)
+		switch {
+		case apierrors.IsNotFound(err):
+			s.writeError(w, http.StatusNotFound, err.Error())
+		case apierrors.IsForbidden(err):
+			s.writeError(w, http.StatusForbidden, err.Error())
+		default:
+			log.Printf("[vcluster] Failed to register context for %s/%s: %v", namespace, name, err)
+			s.writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	log.Printf("[vcluster] Connected %s/%s via localhost:%d (context %q)", namespace, name, localPort, contextName)
+	s.writeJSON(w, VClusterConnectResponse{Context: contextName, LocalPort: localPort, PodName: podName})
+}
+
+// handleDisconnectVCluster stops a vcluster tunnel and removes its context
+// DELETE /api/vclusters/{namespace}/{name}/connect
+func (s *Server) handleDisconnectVCluster(w http.ResponseWriter, r *http.Request) {
+	contextName := k8s.VClusterContextName(chi.URLParam(r, "namespace"), chi.URLParam(r, "name"))
+	if contextName == k8s.GetContextName() {
+		s.writeError(w, http.StatusConflict, "switch to another context before disconnecting this vcluster")
+		return
+	}
+	if !stopVClusterTunnel(contextName) {
+		s.writeError(w, http.StatusNotFound, "vcluster is not connected")
+		return
+	}
+	k8s.UnregisterGeneratedContext(contextName)
+	s.writeJSON(w, map[string]string{"status": "disconnected"})
+}
+
+// waitForTunnel waits until runPortForward reports the session running or failed
+func waitForTunnel(session *PortForwardSession) error {
+	deadline := time.Now().Add(vclusterTunnelTimeout)
+	for time.Now().Before(deadline) {
+		pfManager.mu.RLock()
+		status, errMsg := session.Status, session.Error
+		pfManager.mu.RUnlock()
+		switch status {
+		case "running":
+			return nil
+		case "error", "stopped":
+			return fmt.Errorf("%s", errMsg)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return fmt.Errorf("timed out after %s", vclusterTunnelTimeout)
+}
+
+func vclusterTunnelRunning(contextName string) bool {
+	vclusterTunnels.mu.Lock()
+	session, ok := vclusterTunnels.sessions[contextName]
+	vclusterTunnels.mu.Unlock()
+	if !ok {
+		return false
+	}
+	pfManager.mu.RLock()
+	defer pfManager.mu.RUnlock()
+	return session.Status == "running"
+}
+
+// stopVClusterTunnel stops the tunnel for a context. Returns false if there was none.
+func stopVClusterTunnel(contextName string) bool {
+	vclusterTunnels.mu.Lock()
+	defer vclusterTunnels.mu.Unlock()
+	session, ok := vclusterTunnels.sessions[contextName]
+	if !ok {
+		return false
+	}
+	session.cancel()
+	close(session.stopCh)
+	delete(vclusterTunnels.sessions, contextName)
+	return true
+}
@@ -270,7 +270,7 @@ func (s *Server) handleWorkloadLogsStream(w http.ResponseWriter, r *http.Request
 }
 
 // streamPodLogs streams logs from a single pod/container to the log channel
-func streamPodLogs(ctx context.Context, client *kubernetes.Clientset, namespace, podName, containerName string, tailLines int64, logCh chan<- workloadLogEntry) {
+func streamPodLogs(ctx context.Context, client kubernetes.Interface, namespace, podName, containerName string, tailLines int64, logCh chan<- workloadLogEntry) {
 	opts := &corev1.PodLogOptions{
 		Container:  containerName,
 		Follow:     true,
@@ -468,7 +468,7 @@ func parseTailLines(str string, defaultVal int64) int64 {
 }
 
 // collectLogsFromPods fetches logs from all pods concurrently
-func collectLogsFromPods(ctx context.Context, client *kubernetes.Clientset, namespace string, pods []*corev1.Pod, container string, tailLines int64) []workloadLogEntry {
+func collectLogsFromPods(ctx context.Context, client kubernetes.Interface, namespace string, pods []*corev1.Pod, container string, tailLines int64) []workloadLogEntry {
 	var allLogs []workloadLogEntry
 	var mu sync.Mutex
 	var wg sync.WaitGroup
@@ -495,7 +495,7 @@ func collectLogsFromPods(ctx context.Context, client *kubernetes.Clientset, name
 }
 
 // fetchPodContainerLogs fetches logs for a single pod/container
-func fetchPodContainerLogs(ctx context.Context, client *kubernetes.Clientset, namespace, podName, containerName string, tailLines int64) []workloadLogEntry {
+func fetchPodContainerLogs(ctx context.Context, client kubernetes.Interface, namespace, podName, containerName string, tailLines int64) []workloadLogEntry {
 	opts := &corev1.PodLogOptions{
 		Container:  containerName,
 		TailLines:  &tailLines,
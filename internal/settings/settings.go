@@ -0,0 +1,232 @@
+// Package settings holds the settings that can be changed while Radar runs
+// (GET/PUT /api/config) and persists them to a JSON file, ~/.radar/config.json
+// by default. Only settings changed at runtime are written; at startup they
+// override the matching command-line flags.
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// Log levels
+const (
+	LogLevelInfo  = "info"
+	LogLevelDebug = "debug" // Verbose event pipeline logging, as with --debug-events
+)
+
+// ErrNotSaved is returned by Apply when the update was applied but couldn't
+// be written to the settings file
+var ErrNotSaved = errors.New("settings applied but not saved")
+
+const (
+	minHistoryLimit = 100
+	maxHistoryLimit = 1_000_000
+)
+
+// Settings are the runtime-adjustable settings
+type Settings struct {
+	HistoryLimit int      `json:"historyLimit"` // Events the in-memory timeline keeps
+	NoisyKinds   []string `json:"noisyKinds"`   // Kinds whose updates stay out of the timeline, on top of the built-in ones
+	LogLevel     string   `json:"logLevel"`
+	ReadOnly     bool     `json:"readOnly"` // Reject requests that change the cluster
+}
+
+// Update is a partial change to Settings; nil fields are left as they are.
+// It's also the file format, so the file only holds what was changed.
+type Update struct {
+	HistoryLimit *int      `json:"historyLimit,omitempty"`
+	NoisyKinds   *[]string `json:"noisyKinds,omitempty"`
+	LogLevel     *string   `json:"logLevel,omitempty"`
+	ReadOnly     *bool     `json:"readOnly,omitempty"`
+}
+
+var (
+	mu        sync.RWMutex
+	current   Settings
+	saved     Update // What the file holds
+	path      string
+	listeners []func(Settings)
+)
+
+// DefaultPath returns ~/.radar/config.json
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".radar", "config.json")
+}
+
+// Init sets the flag-provided settings and applies the ones saved in file on
+// top. An empty file keeps changes in memory only. A missing file is fine; an
+// unreadable one is reported and ignored.
+func Init(file string, defaults Settings) (Settings, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	path = file
+	current = normalize(defaults)
+	saved = Update{}
+	if file == "" {
+		return current, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return current, nil
+	}
+	if err != nil {
+		return current, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	var u Update
+	if err := json.Unmarshal(data, &u); err != nil {
+		return current, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	next, err := merge(current, u)
+	if err != nil {
+		return current, fmt.Errorf("invalid settings in %s: %w", file, err)
+	}
+	current, saved = next, u
+	return current, nil
+}
+
+// LockReadOnly turns read-only mode on for this run regardless of the file
+// (--read-only). It isn't saved.
+func LockReadOnly() {
+	mu.Lock()
+	defer mu.Unlock()
+	current.ReadOnly = true
+}
+
+// Get returns the current settings
+func Get() Settings {
+	mu.RLock()
+	defer mu.RUnlock()
+	return clone(current)
+}
+
+// Path returns the file settings are persisted to ("" = not persisted)
+func Path() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return path
+}
+
+// OnChange registers a function called with the new settings after every
+// successful Apply
+func OnChange(fn func(Settings)) {
+	mu.Lock()
+	defer mu.Unlock()
+	listeners = append(listeners, fn)
+}
+
+// Apply validates and applies an update, notifies OnChange listeners and
+// persists the changed fields. When persisting fails the update stays
+// applied for this run and the error wraps ErrNotSaved.
+func Apply(u Update) (Settings, error) {
+	mu.Lock()
+	next, err := merge(current, u)
+	if err != nil {
+		mu.Unlock()
+		return Settings{}, err
+	}
+	current = next
+	saved = overlay(saved, u)
+	saveErr := save(path, saved)
+	fns := slices.Clone(listeners)
+	mu.Unlock()
+
+	for _, fn := range fns {
+		fn(clone(next))
+	}
+	if saveErr != nil {
+		return clone(next), fmt.Errorf("%w: %v", ErrNotSaved, saveErr)
+	}
+	return clone(next), nil
+}
+
+// merge applies u to s, validating the result
+func merge(s Settings, u Update) (Settings, error) {
+	s = clone(s)
+	if u.HistoryLimit != nil {
+		if *u.HistoryLimit < minHistoryLimit || *u.HistoryLimit > maxHistoryLimit {
+			return s, fmt.Errorf("historyLimit must be between %d and %d", minHistoryLimit, maxHistoryLimit)
+		}
+		s.HistoryLimit = *u.HistoryLimit
+	}
+	if u.NoisyKinds != nil {
+		s.NoisyKinds = slices.Clone(*u.NoisyKinds)
+	}
+	if u.LogLevel != nil {
+		if *u.LogLevel != LogLevelInfo && *u.LogLevel != LogLevelDebug {
+			return s, fmt.Errorf("logLevel must be %q or %q", LogLevelInfo, LogLevelDebug)
+		}
+		s.LogLevel = *u.LogLevel
+	}
+	if u.ReadOnly != nil {
+		s.ReadOnly = *u.ReadOnly
+	}
+	return normalize(s), nil
+}
+
+// overlay returns base with the fields set in u replaced
+func overlay(base, u Update) Update {
+	if u.HistoryLimit != nil {
+		base.HistoryLimit = u.HistoryLimit
+	}
+	if u.NoisyKinds != nil {
+		base.NoisyKinds = u.NoisyKinds
+	}
+	if u.LogLevel != nil {
+		base.LogLevel = u.LogLevel
+	}
+	if u.ReadOnly != nil {
+		base.ReadOnly = u.ReadOnly
+	}
+	return base
+}
+
+func normalize(s Settings) Settings {
+	if s.LogLevel == "" {
+		s.LogLevel = LogLevelInfo
+	}
+	if s.NoisyKinds == nil {
+		s.NoisyKinds = []string{}
+	}
+	return s
+}
+
+func clone(s Settings) Settings {
+	s.NoisyKinds = slices.Clone(s.NoisyKinds)
+	return s
+}
+
+// save replaces the file atomically
+func save(file string, u Update) error {
+	if file == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(u, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return err
+	}
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	log.Printf("[settings] Saved to %s", file)
+	return nil
+}
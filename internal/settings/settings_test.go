@@ -0,0 +1,122 @@
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestInitAppliesSavedSettings(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(file, []byte(`{"historyLimit": 5000, "readOnly": true}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Init(file, Settings{HistoryLimit: 10000, NoisyKinds: []string{"Foo"}})
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if s.HistoryLimit != 5000 || !s.ReadOnly {
+		t.Errorf("saved settings not applied: %+v", s)
+	}
+	if len(s.NoisyKinds) != 1 || s.LogLevel != LogLevelInfo {
+		t.Errorf("unsaved settings should keep their defaults: %+v", s)
+	}
+}
+
+func TestInitMissingFile(t *testing.T) {
+	s, err := Init(filepath.Join(t.TempDir(), "config.json"), Settings{HistoryLimit: 10000})
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if s.HistoryLimit != 10000 || s.NoisyKinds == nil {
+		t.Errorf("got %+v", s)
+	}
+}
+
+func TestApplyPersistsOnlyChangedFields(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "radar", "config.json")
+	if _, err := Init(file, Settings{HistoryLimit: 10000}); err != nil {
+		t.Fatal(err)
+	}
+	var notified Settings
+	OnChange(func(s Settings) { notified = s })
+	defer func() { listeners = nil }()
+
+	s, err := Apply(Update{LogLevel: ptr(LogLevelDebug)})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if s.LogLevel != LogLevelDebug || notified.LogLevel != LogLevelDebug {
+		t.Errorf("got %+v, notified %+v", s, notified)
+	}
+	if _, err := Apply(Update{NoisyKinds: &[]string{"Pod"}}); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var onDisk map[string]any
+	if err := json.Unmarshal(data, &onDisk); err != nil {
+		t.Fatal(err)
+	}
+	if len(onDisk) != 2 || onDisk["logLevel"] != LogLevelDebug || onDisk["historyLimit"] != nil {
+		t.Errorf("file holds %v", onDisk)
+	}
+}
+
+func TestApplyValidates(t *testing.T) {
+	if _, err := Init("", Settings{HistoryLimit: 10000}); err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range []Update{
+		{HistoryLimit: ptr(10)},
+		{LogLevel: ptr("trace")},
+	} {
+		if _, err := Apply(u); err == nil {
+			t.Errorf("Apply(%+v) should fail", u)
+		}
+	}
+	if Get().HistoryLimit != 10000 {
+		t.Errorf("rejected update was applied: %+v", Get())
+	}
+}
+
+func TestApplyNotSaved(t *testing.T) {
+	// A directory where the file should be makes the write fail
+	file := t.TempDir()
+	if _, err := Init(file, Settings{HistoryLimit: 10000}); err == nil {
+		t.Fatal("Init should report the unreadable file")
+	}
+	s, err := Apply(Update{ReadOnly: ptr(true)})
+	if !errors.Is(err, ErrNotSaved) {
+		t.Fatalf("got %v, want ErrNotSaved", err)
+	}
+	if !s.ReadOnly || !Get().ReadOnly {
+		t.Error("update should stay applied when saving fails")
+	}
+}
+
+func TestLockReadOnly(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(file, []byte(`{"readOnly": false}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Init(file, Settings{HistoryLimit: 10000}); err != nil {
+		t.Fatal(err)
+	}
+	LockReadOnly()
+	if !Get().ReadOnly {
+		t.Fatal("LockReadOnly didn't override the saved readOnly: false")
+	}
+	data, _ := os.ReadFile(file)
+	if string(data) != `{"readOnly": false}` {
+		t.Errorf("LockReadOnly changed the file: %s", data)
+	}
+}
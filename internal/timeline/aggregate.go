@@ -0,0 +1,224 @@
+package timeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	aggregatePageSize  = 10000
+	aggregateMaxEvents = 500000 // Stop scanning (and report Truncated) beyond this
+	aggregateMaxBucket = 1000
+	aggregateOther     = "(other)"
+)
+
+// AggregateDimensions are the event fields counts can be grouped by
+var AggregateDimensions = []string{"kind", "namespace", "severity", "source", "eventType", "reason", "context"}
+
+// ErrInvalidAggregate is returned for unknown dimensions or bad bucket sizes
+var ErrInvalidAggregate = errors.New("invalid aggregation")
+
+// AggregateOptions configures an aggregation over Query.Since..Query.Until
+type AggregateOptions struct {
+	Query   QueryOptions  // Filters; Limit, Offset, and Cursor are ignored
+	GroupBy []string      // Dimensions (empty = a single total series)
+	Bucket  time.Duration // Bucket width (0 = about 60 buckets over the window)
+	Top     int           // Keep the Top largest series and fold the rest into "(other)" (0 = all)
+}
+
+// AggregateSeries is the per-bucket event count of one combination of dimension values
+type AggregateSeries struct {
+	Key    map[string]string `json:"key"`
+	Counts []int             `json:"counts"` // One per bucket
+	Total  int               `json:"total"`
+}
+
+// AggregateResult is a time-bucketed event count, oldest bucket first
+type AggregateResult struct {
+	Since         time.Time         `json:"since"`
+	SinceUnix     int64             `json:"sinceUnix"`
+	Until         time.Time         `json:"until"`
+	UntilUnix     int64             `json:"untilUnix"`
+	BucketSeconds int64             `json:"bucketSeconds"`
+	Buckets       []time.Time       `json:"buckets"`     // Bucket start times
+	BucketsUnix   []int64           `json:"bucketsUnix"` // Bucket start times in epoch seconds
+	GroupBy       []string          `json:"groupBy"`
+	Series        []AggregateSeries `json:"series"` // Largest total first
+	Total         int               `json:"total"`
+	Truncated     bool              `json:"truncated,omitempty"` // More than 500k events matched; older ones weren't counted
+}
+
+// InZone renders the result's times in loc
+func (r *AggregateResult) InZone(loc *time.Location) {
+	r.Since = r.Since.In(loc)
+	r.Until = r.Until.In(loc)
+	for i := range r.Buckets {
+		r.Buckets[i] = r.Buckets[i].In(loc)
+	}
+}
+
+// niceBuckets are the automatic bucket widths
+var niceBuckets = []time.Duration{
+	time.Minute, 2 * time.Minute, 5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 2 * time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour, 24 * time.Hour,
+}
+
+// autoBucket picks the smallest nice width giving at most 60 buckets
+func autoBucket(window time.Duration) time.Duration {
+	for _, b := range niceBuckets {
+		if window/b <= 60 {
+			return b
+		}
+	}
+	return niceBuckets[len(niceBuckets)-1]
+}
+
+func dimensionValue(e *TimelineEvent, dim string) string {
+	switch dim {
+	case "kind":
+		return e.Kind
+	case "namespace":
+		return e.Namespace
+	case "severity":
+		return string(e.Severity)
+	case "source":
+		return string(e.Source)
+	case "eventType":
+		return string(e.EventType)
+	case "reason":
+		return e.Reason
+	case "context":
+		return e.Context
+	}
+	return ""
+}
+
+// Aggregate counts matching events per time bucket and dimension values.
+// Events are paged through the store newest first, so Truncated drops the
+// oldest events rather than a random sample.
+func Aggregate(ctx context.Context, store EventStore, opts AggregateOptions) (*AggregateResult, error) {
+	for _, dim := range opts.GroupBy {
+		if !slices.Contains(AggregateDimensions, dim) {
+			return nil, fmt.Errorf("%w: unknown dimension %q (one of %s)", ErrInvalidAggregate, dim, strings.Join(AggregateDimensions, ", "))
+		}
+	}
+
+	until := opts.Query.Until
+	if until.IsZero() {
+		until = time.Now()
+	}
+	since := opts.Query.Since
+	if since.IsZero() || !since.Before(until) {
+		return nil, fmt.Errorf("%w: since must be before until", ErrInvalidAggregate)
+	}
+	bucket := opts.Bucket
+	if bucket == 0 {
+		bucket = autoBucket(until.Sub(since))
+	}
+	if bucket < time.Second {
+		return nil, fmt.Errorf("%w: bucket must be at least 1s", ErrInvalidAggregate)
+	}
+
+	// Buckets are aligned to multiples of the width so charts don't shift as time passes
+	start := since.Truncate(bucket)
+	n := int(until.Sub(start)/bucket) + 1
+	if n > aggregateMaxBucket {
+		return nil, fmt.Errorf("%w: %d buckets exceeds the maximum of %d", ErrInvalidAggregate, n, aggregateMaxBucket)
+	}
+
+	result := &AggregateResult{
+		Since:         since,
+		SinceUnix:     since.Unix(),
+		Until:         until,
+		UntilUnix:     until.Unix(),
+		BucketSeconds: int64(bucket / time.Second),
+		Buckets:       make([]time.Time, n),
+		BucketsUnix:   make([]int64, n),
+		GroupBy:       append([]string{}, opts.GroupBy...),
+		Series:        []AggregateSeries{},
+	}
+	for i := range result.Buckets {
+		result.Buckets[i] = start.Add(time.Duration(i) * bucket)
+		result.BucketsUnix[i] = result.Buckets[i].Unix()
+	}
+
+	series := make(map[string]*AggregateSeries)
+	query := opts.Query
+	query.Until = until
+	query.Limit = aggregatePageSize
+	query.Offset = 0
+	query.Cursor = ""
+	for {
+		events, err := store.Query(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		for i := range events {
+			e := &events[i]
+			idx := int(e.Timestamp.Sub(start) / bucket)
+			if idx < 0 || idx >= n {
+				continue
+			}
+			values := make([]string, len(opts.GroupBy))
+			for j, dim := range opts.GroupBy {
+				values[j] = dimensionValue(e, dim)
+			}
+			key := strings.Join(values, "\x00")
+			s, ok := series[key]
+			if !ok {
+				s = &AggregateSeries{Key: make(map[string]string, len(values)), Counts: make([]int, n)}
+				for j, dim := range opts.GroupBy {
+					s.Key[dim] = values[j]
+				}
+				series[key] = s
+			}
+			s.Counts[idx]++
+			s.Total++
+			result.Total++
+		}
+		if query.Cursor = NextCursor(events, query.Limit); query.Cursor == "" {
+			break
+		}
+		if result.Total >= aggregateMaxEvents {
+			result.Truncated = true
+			break
+		}
+	}
+
+	for _, s := range series {
+		result.Series = append(result.Series, *s)
+	}
+	sort.Slice(result.Series, func(i, j int) bool {
+		if result.Series[i].Total != result.Series[j].Total {
+			return result.Series[i].Total > result.Series[j].Total
+		}
+		return seriesLabel(result.Series[i], opts.GroupBy) < seriesLabel(result.Series[j], opts.GroupBy)
+	})
+	if opts.Top > 0 && len(result.Series) > opts.Top {
+		other := AggregateSeries{Key: make(map[string]string), Counts: make([]int, n)}
+		for _, dim := range opts.GroupBy {
+			other.Key[dim] = aggregateOther
+		}
+		for _, s := range result.Series[opts.Top:] {
+			for i, c := range s.Counts {
+				other.Counts[i] += c
+			}
+			other.Total += s.Total
+		}
+		result.Series = append(result.Series[:opts.Top], other)
+	}
+	return result, nil
+}
+
+func seriesLabel(s AggregateSeries, dims []string) string {
+	parts := make([]string, len(dims))
+	for i, dim := range dims {
+		parts[i] = s.Key[dim]
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,71 @@
+package timeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAggregate(t *testing.T) {
+	store := NewMemoryStore(100)
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	add := func(id string, minute int, ns string, sev Severity) {
+		_ = store.Append(ctx, TimelineEvent{ID: id, Timestamp: base.Add(time.Duration(minute) * time.Minute), Kind: "Deployment", Namespace: ns, Name: "api", EventType: EventTypeUpdate, Source: SourceInformer, Severity: sev})
+	}
+	add("a1", 1, "shop", SeverityInfo)
+	add("a2", 2, "shop", SeverityWarning)
+	add("a3", 16, "shop", SeverityInfo)
+	add("b1", 3, "auth", SeverityInfo)
+	add("c1", 31, "billing", SeverityCritical)
+	add("old", -30, "shop", SeverityInfo) // Before the window
+
+	opts := AggregateOptions{
+		Query:   QueryOptions{Since: base, Until: base.Add(45 * time.Minute), IncludeK8sEvents: true},
+		GroupBy: []string{"namespace"},
+		Bucket:  15 * time.Minute,
+	}
+	result, err := Aggregate(ctx, store, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Buckets) != 4 || result.BucketSeconds != 900 || result.Total != 5 {
+		t.Fatalf("unexpected result shape: %d buckets, %ds, %d total", len(result.Buckets), result.BucketSeconds, result.Total)
+	}
+	shop := result.Series[0]
+	if shop.Key["namespace"] != "shop" || shop.Total != 3 || shop.Counts[0] != 2 || shop.Counts[1] != 1 {
+		t.Errorf("unexpected shop series: %+v", shop)
+	}
+	if result.Series[1].Key["namespace"] != "auth" || result.Series[2].Key["namespace"] != "billing" || result.Series[2].Counts[2] != 1 {
+		t.Errorf("expected ties ordered by key, got %+v", result.Series)
+	}
+
+	// Top folds smaller series into "(other)"
+	opts.GroupBy = []string{"namespace", "severity"}
+	opts.Top = 1
+	result, err = Aggregate(ctx, store, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Series) != 2 || result.Series[1].Key["namespace"] != aggregateOther || result.Series[1].Total != 3 {
+		t.Errorf("expected top series plus other, got %+v", result.Series)
+	}
+
+	// Automatic buckets: 45m fits in 60 one-minute buckets
+	opts.Bucket = 0
+	result, _ = Aggregate(ctx, store, opts)
+	if result.BucketSeconds != 60 {
+		t.Errorf("expected 1m auto bucket, got %ds", result.BucketSeconds)
+	}
+
+	opts.GroupBy = []string{"color"}
+	if _, err := Aggregate(ctx, store, opts); !errors.Is(err, ErrInvalidAggregate) {
+		t.Errorf("expected ErrInvalidAggregate for unknown dimension, got %v", err)
+	}
+	opts.GroupBy = nil
+	opts.Bucket = time.Second
+	if _, err := Aggregate(ctx, store, opts); !errors.Is(err, ErrInvalidAggregate) {
+		t.Errorf("expected ErrInvalidAggregate for too many buckets, got %v", err)
+	}
+}
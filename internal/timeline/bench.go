@@ -0,0 +1,229 @@
+package timeline
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// BenchConfig configures a synthetic load run against an EventStore
+type BenchConfig struct {
+	Events     int           // Total events to append
+	BatchSize  int           // Events per AppendBatch call (1 = Append)
+	Namespaces int           // Distinct namespaces to spread events over
+	Queries    int           // Queries per preset when measuring latency
+	Span       time.Duration // Time range the event timestamps cover, ending now
+	Seed       int64         // Seed for the event generator, for repeatable runs
+}
+
+// DefaultBenchConfig returns a configuration sized for a quick local run
+func DefaultBenchConfig() BenchConfig {
+	return BenchConfig{
+		Events:     1_000_000,
+		BatchSize:  500,
+		Namespaces: 50,
+		Queries:    50,
+		Span:       24 * time.Hour,
+		Seed:       1,
+	}
+}
+
+// BenchResult holds the measurements of a benchmark run
+type BenchResult struct {
+	Events          int                     `json:"events"`
+	AppendDuration  time.Duration           `json:"appendDuration"`
+	AppendPerSecond float64                 `json:"appendPerSecond"`
+	Queries         map[string]LatencyStats `json:"queries"`    // keyed by preset name ("" = no preset)
+	HeapBytes       int64                   `json:"heapBytes"`  // Heap growth after appending (live objects)
+	StoreStats      StoreStats              `json:"storeStats"` // Store's own view after appending
+}
+
+// LatencyStats summarizes query latencies
+type LatencyStats struct {
+	Count int           `json:"count"`
+	P50   time.Duration `json:"p50"`
+	P95   time.Duration `json:"p95"`
+	P99   time.Duration `json:"p99"`
+	Max   time.Duration `json:"max"`
+	Rows  int           `json:"rows"` // Rows returned by the last query
+}
+
+// benchKinds is weighted roughly like a busy cluster: mostly pods and events
+var benchKinds = []string{
+	"Pod", "Pod", "Pod", "Pod", "Event", "Event", "Event",
+	"ReplicaSet", "Deployment", "StatefulSet", "DaemonSet", "Job", "CronJob",
+	"Service", "ConfigMap", "Secret", "Lease", "EndpointSlice",
+}
+
+var benchReasons = []string{"Scheduled", "Pulled", "Started", "Killing", "BackOff", "Unhealthy", "FailedScheduling", "OOMKilling"}
+
+// GenerateBenchEvents returns n synthetic events spread over namespaces and a
+// time span ending now, in timestamp order. The same seed yields the same events.
+func GenerateBenchEvents(n, namespaces int, span time.Duration, seed int64) []TimelineEvent {
+	if namespaces <= 0 {
+		namespaces = 1
+	}
+	rng := rand.New(rand.NewSource(seed))
+	start := time.Now().Add(-span)
+	step := span / time.Duration(max(n, 1))
+
+	events := make([]TimelineEvent, n)
+	for i := range events {
+		kind := benchKinds[rng.Intn(len(benchKinds))]
+		ns := fmt.Sprintf("ns-%d", rng.Intn(namespaces))
+		app := fmt.Sprintf("app-%d", rng.Intn(200))
+		e := TimelineEvent{
+			ID:        fmt.Sprintf("bench-%d-%d", seed, i),
+			Timestamp: start.Add(time.Duration(i) * step),
+			Source:    SourceInformer,
+			Kind:      kind,
+			Namespace: ns,
+			Name:      fmt.Sprintf("%s-%x", app, rng.Intn(1<<16)),
+			UID:       fmt.Sprintf("uid-%d", rng.Int63()),
+			Labels:    map[string]string{"app": app},
+		}
+		switch kind {
+		case "Event":
+			e.Source = SourceK8sEvent
+			e.EventType = EventTypeNormal
+			e.Reason = benchReasons[rng.Intn(len(benchReasons))]
+			if rng.Intn(5) == 0 {
+				e.EventType = EventTypeWarning
+			}
+			e.Message = "synthetic event " + e.Reason
+			e.Count = int32(rng.Intn(10) + 1)
+		case "Pod", "ReplicaSet":
+			e.EventType = []EventType{EventTypeAdd, EventTypeUpdate, EventTypeUpdate, EventTypeDelete}[rng.Intn(4)]
+			e.Owner = &OwnerInfo{Kind: "Deployment", Name: app}
+			e.HealthState = []HealthState{HealthHealthy, HealthHealthy, HealthHealthy, HealthDegraded, HealthUnhealthy}[rng.Intn(5)]
+		default:
+			e.EventType = []EventType{EventTypeAdd, EventTypeUpdate, EventTypeUpdate, EventTypeDelete}[rng.Intn(4)]
+			e.HealthState = HealthHealthy
+		}
+		if e.EventType == EventTypeUpdate && rng.Intn(3) == 0 {
+			e.Diff = &DiffInfo{
+				Fields:  []FieldChange{{Path: "spec.replicas", OldValue: 2, NewValue: 3}},
+				Summary: "replicas 2→3",
+			}
+		}
+		events[i] = e
+	}
+	return events
+}
+
+// RunBenchmark appends synthetic events to store and measures append
+// throughput, query latency for every built-in preset, and heap growth
+func RunBenchmark(ctx context.Context, store EventStore, cfg BenchConfig) (*BenchResult, error) {
+	if cfg.Events <= 0 {
+		return nil, fmt.Errorf("events must be positive")
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 1
+	}
+	if cfg.Queries <= 0 {
+		cfg.Queries = 1
+	}
+	if cfg.Span <= 0 {
+		cfg.Span = time.Hour
+	}
+
+	heapBefore := heapInUse()
+	events := GenerateBenchEvents(cfg.Events, cfg.Namespaces, cfg.Span, cfg.Seed)
+
+	start := time.Now()
+	for i := 0; i < len(events); i += cfg.BatchSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		end := min(i+cfg.BatchSize, len(events))
+		var err error
+		if cfg.BatchSize == 1 {
+			err = store.Append(ctx, events[i])
+		} else {
+			err = store.AppendBatch(ctx, events[i:end])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("append events %d-%d: %w", i, end, err)
+		}
+	}
+	appendDuration := time.Since(start)
+
+	// Drop the generator's copy so the heap delta reflects what the store holds
+	events = nil
+	heapAfter := heapInUse()
+
+	result := &BenchResult{
+		Events:          cfg.Events,
+		AppendDuration:  appendDuration,
+		AppendPerSecond: float64(cfg.Events) / appendDuration.Seconds(),
+		Queries:         make(map[string]LatencyStats),
+		HeapBytes:       heapAfter - heapBefore,
+		StoreStats:      store.Stats(),
+	}
+
+	presets := []string{""}
+	for name := range DefaultFilterPresets() {
+		presets = append(presets, name)
+	}
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	for _, preset := range presets {
+		stats, err := measureQueries(ctx, store, preset, cfg, rng)
+		if err != nil {
+			return nil, fmt.Errorf("query preset %q: %w", preset, err)
+		}
+		result.Queries[preset] = stats
+	}
+	return result, nil
+}
+
+// measureQueries runs cfg.Queries timeline-style queries with the preset,
+// alternating between the whole range, a recent window, and one namespace
+func measureQueries(ctx context.Context, store EventStore, preset string, cfg BenchConfig, rng *rand.Rand) (LatencyStats, error) {
+	latencies := make([]time.Duration, 0, cfg.Queries)
+	var rows int
+	for i := 0; i < cfg.Queries; i++ {
+		opts := DefaultQueryOptions()
+		opts.FilterPreset = preset
+		switch i % 3 {
+		case 1:
+			opts.Since = time.Now().Add(-cfg.Span / 24)
+		case 2:
+			opts.Namespaces = []string{fmt.Sprintf("ns-%d", rng.Intn(max(cfg.Namespaces, 1)))}
+		}
+
+		start := time.Now()
+		events, err := store.Query(ctx, opts)
+		if err != nil {
+			return LatencyStats{}, err
+		}
+		latencies = append(latencies, time.Since(start))
+		rows = len(events)
+	}
+	return latencyStats(latencies, rows), nil
+}
+
+func latencyStats(latencies []time.Duration, rows int) LatencyStats {
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	at := func(p float64) time.Duration {
+		return latencies[int(p*float64(len(latencies)-1))]
+	}
+	return LatencyStats{
+		Count: len(latencies),
+		P50:   at(0.50),
+		P95:   at(0.95),
+		P99:   at(0.99),
+		Max:   latencies[len(latencies)-1],
+		Rows:  rows,
+	}
+}
+
+// heapInUse returns live heap bytes after a full collection
+func heapInUse() int64 {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return int64(m.HeapAlloc)
+}
@@ -0,0 +1,119 @@
+package timeline
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// storeBudget is the minimum performance a store must keep on a modest
+// dataset. The floors are well below typical results so the gate catches
+// regressions (a lost index, per-row commits, O(n²) filtering) without
+// flaking on slow CI machines.
+type storeBudget struct {
+	minAppendPerSecond float64
+	maxQueryP95        time.Duration
+}
+
+var storeBudgets = map[string]storeBudget{
+	"memory": {minAppendPerSecond: 100_000, maxQueryP95: 100 * time.Millisecond},
+	"sqlite": {minAppendPerSecond: 5_000, maxQueryP95: 500 * time.Millisecond},
+}
+
+func newBenchStore(tb testing.TB, name string, size int) EventStore {
+	tb.Helper()
+	if name == "memory" {
+		return NewMemoryStore(size)
+	}
+	store, err := NewSQLiteStore(filepath.Join(tb.TempDir(), "bench.db"))
+	if err != nil {
+		tb.Fatalf("Failed to create SQLite store: %v", err)
+	}
+	tb.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestStorePerformanceBudget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping store performance gate in short mode")
+	}
+
+	cfg := DefaultBenchConfig()
+	cfg.Events = 50_000
+	cfg.Queries = 15
+
+	for name, budget := range storeBudgets {
+		t.Run(name, func(t *testing.T) {
+			result, err := RunBenchmark(context.Background(), newBenchStore(t, name, cfg.Events), cfg)
+			if err != nil {
+				t.Fatalf("RunBenchmark failed: %v", err)
+			}
+			if result.StoreStats.TotalEvents != int64(cfg.Events) {
+				t.Errorf("expected %d stored events, got %d", cfg.Events, result.StoreStats.TotalEvents)
+			}
+			if result.AppendPerSecond < budget.minAppendPerSecond {
+				t.Errorf("append throughput %.0f events/s is below the %.0f events/s budget", result.AppendPerSecond, budget.minAppendPerSecond)
+			}
+			for preset, q := range result.Queries {
+				if q.P95 > budget.maxQueryP95 {
+					t.Errorf("preset %q query p95 %s exceeds the %s budget", preset, q.P95, budget.maxQueryP95)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateBenchEventsDeterministic(t *testing.T) {
+	a := GenerateBenchEvents(100, 5, time.Hour, 42)
+	b := GenerateBenchEvents(100, 5, time.Hour, 42)
+	for i := range a {
+		if a[i].Kind != b[i].Kind || a[i].Namespace != b[i].Namespace || a[i].Name != b[i].Name || a[i].EventType != b[i].EventType {
+			t.Fatalf("event %d differs between runs with the same seed: %+v vs %+v", i, a[i], b[i])
+		}
+		if i > 0 && a[i].Timestamp.Before(a[i-1].Timestamp) {
+			t.Fatalf("event %d is out of timestamp order", i)
+		}
+	}
+}
+
+func benchmarkAppendBatch(b *testing.B, name string) {
+	events := GenerateBenchEvents(b.N, 50, time.Hour, 1)
+	store := newBenchStore(b, name, b.N)
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < len(events); i += 500 {
+		if err := store.AppendBatch(ctx, events[i:min(i+500, len(events))]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMemoryStoreAppendBatch(b *testing.B) { benchmarkAppendBatch(b, "memory") }
+func BenchmarkSQLiteStoreAppendBatch(b *testing.B) { benchmarkAppendBatch(b, "sqlite") }
+
+func benchmarkQueryPreset(b *testing.B, name, preset string) {
+	const size = 100_000
+	store := newBenchStore(b, name, size)
+	ctx := context.Background()
+	if err := store.AppendBatch(ctx, GenerateBenchEvents(size, 50, 24*time.Hour, 1)); err != nil {
+		b.Fatal(err)
+	}
+	opts := DefaultQueryOptions()
+	opts.FilterPreset = preset
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := store.Query(ctx, opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMemoryStoreQueryDefault(b *testing.B) { benchmarkQueryPreset(b, "memory", PresetDefault) }
+func BenchmarkMemoryStoreQueryWarnings(b *testing.B) {
+	benchmarkQueryPreset(b, "memory", PresetWarningsOnly)
+}
+func BenchmarkSQLiteStoreQueryDefault(b *testing.B) { benchmarkQueryPreset(b, "sqlite", PresetDefault) }
+func BenchmarkSQLiteStoreQueryWarnings(b *testing.B) {
+	benchmarkQueryPreset(b, "sqlite", PresetWarningsOnly)
+}
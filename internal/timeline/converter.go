@@ -14,7 +14,7 @@ import (
 // NewInformerEvent creates a TimelineEvent from an informer callback
 // createdAt is the resource's metadata.creationTimestamp (when K8s actually created it)
 func NewInformerEvent(kind, namespace, name, uid string, operation EventType, healthState HealthState, diff *DiffInfo, owner *OwnerInfo, labels map[string]string, createdAt *time.Time) TimelineEvent {
-	return TimelineEvent{
+	event := TimelineEvent{
 		ID:          uuid.New().String(),
 		Timestamp:   time.Now(),
 		Source:      SourceInformer,
@@ -29,6 +29,29 @@ func NewInformerEvent(kind, namespace, name, uid string, operation EventType, he
 		Owner:       owner,
 		Labels:      labels,
 	}
+	event.Severity = ComputeSeverity(&event, "")
+	return event
+}
+
+// NewLifecycleEvent creates a TimelineEvent for a cluster-level lifecycle change
+// observed by Radar itself (e.g. a CRD being installed or an APIService going
+// unavailable), carrying a reason and human-readable message.
+func NewLifecycleEvent(kind, namespace, name, uid string, eventType EventType, reason, message string, healthState HealthState) TimelineEvent {
+	event := TimelineEvent{
+		ID:          uuid.New().String(),
+		Timestamp:   time.Now(),
+		Source:      SourceInformer,
+		Kind:        kind,
+		Namespace:   namespace,
+		Name:        name,
+		UID:         uid,
+		EventType:   eventType,
+		Reason:      reason,
+		Message:     message,
+		HealthState: healthState,
+	}
+	event.Severity = ComputeSeverity(&event, "")
+	return event
 }
 
 // NewK8sEventTimelineEvent creates a TimelineEvent from a corev1.Event
@@ -47,7 +70,7 @@ func NewK8sEventTimelineEvent(event *corev1.Event, owner *OwnerInfo) TimelineEve
 		evtType = EventTypeWarning
 	}
 
-	return TimelineEvent{
+	e := TimelineEvent{
 		ID:        string(event.UID),
 		Timestamp: ts,
 		Source:    SourceK8sEvent,
@@ -60,6 +83,8 @@ func NewK8sEventTimelineEvent(event *corev1.Event, owner *OwnerInfo) TimelineEve
 		Owner:     owner,
 		Count:     event.Count,
 	}
+	e.Severity = ComputeSeverity(&e, "")
+	return e
 }
 
 // NewHistoricalEvent creates a historical TimelineEvent
@@ -70,7 +95,7 @@ func NewHistoricalEvent(kind, namespace, name string, ts time.Time, reason, mess
 	hash := sha256.Sum256([]byte(hashInput))
 	id := fmt.Sprintf("hist-%x", hash[:8]) // Use first 8 bytes for shorter ID
 
-	return TimelineEvent{
+	event := TimelineEvent{
 		ID:          id,
 		Timestamp:   ts,
 		Source:      SourceHistorical,
@@ -84,6 +109,8 @@ func NewHistoricalEvent(kind, namespace, name string, ts time.Time, reason, mess
 		Owner:       owner,
 		Labels:      labels,
 	}
+	event.Severity = ComputeSeverity(&event, "")
+	return event
 }
 
 // ExtractOwner gets the controller owner reference from an object
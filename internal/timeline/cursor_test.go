@@ -0,0 +1,90 @@
+package timeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// cursorTestEvents returns 30 Deployment events sharing timestamps in pairs,
+// appended out of timestamp order, interleaved with 30 managed Pod events
+func cursorTestEvents(base time.Time) []TimelineEvent {
+	var events []TimelineEvent
+	for i := 29; i >= 0; i-- {
+		ts := base.Add(time.Duration(i/2) * time.Second)
+		events = append(events,
+			TimelineEvent{ID: fmt.Sprintf("deploy-%02d", i), Timestamp: ts, Kind: "Deployment", Namespace: "default", Name: "api", EventType: EventTypeUpdate, Source: SourceInformer},
+			TimelineEvent{ID: fmt.Sprintf("pod-%02d", i), Timestamp: ts, Kind: "Pod", Namespace: "default", Name: "api-1", EventType: EventTypeUpdate, Source: SourceInformer},
+		)
+	}
+	events[0], events[10] = events[10], events[0]
+	return events
+}
+
+func testCursorPaging(t *testing.T, store EventStore) {
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := store.AppendBatch(ctx, cursorTestEvents(base)); err != nil {
+		t.Fatalf("AppendBatch failed: %v", err)
+	}
+
+	// Managed events are filtered after scanning, so pages must still fill up
+	opts := QueryOptions{Limit: 7, IncludeK8sEvents: true}
+	seen := make(map[string]bool)
+	var previous *TimelineEvent
+	for page := 0; ; page++ {
+		events, err := store.Query(ctx, opts)
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		if page < 4 && len(events) != 7 {
+			t.Fatalf("page %d: expected 7 events, got %d", page, len(events))
+		}
+		for i := range events {
+			e := &events[i]
+			if seen[e.ID] {
+				t.Fatalf("event %s returned twice", e.ID)
+			}
+			seen[e.ID] = true
+			if e.Kind != "Deployment" {
+				t.Fatalf("unexpected managed event %s", e.ID)
+			}
+			if previous != nil && !olderThan(e, previous.Timestamp, previous.ID) {
+				t.Fatalf("event %s isn't older than %s", e.ID, previous.ID)
+			}
+			previous = e
+		}
+		opts.Cursor = NextCursor(events, opts.Limit)
+		if opts.Cursor == "" {
+			break
+		}
+	}
+	if len(seen) != 30 {
+		t.Errorf("expected 30 events across pages, got %d", len(seen))
+	}
+
+	// Until bounds the newest event
+	events, err := store.Query(ctx, QueryOptions{Until: base.Add(4 * time.Second), Limit: 100, IncludeManaged: true, IncludeK8sEvents: true})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(events) != 20 || events[0].ID != "pod-09" {
+		t.Errorf("expected 20 events up to 4s, newest pod-09; got %d starting with %s", len(events), events[0].ID)
+	}
+
+	if _, err := store.Query(ctx, QueryOptions{Cursor: "bogus"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestMemoryStore_CursorPaging(t *testing.T) {
+	testCursorPaging(t, NewMemoryStore(100))
+}
+
+func TestSQLiteStore_CursorPaging(t *testing.T) {
+	store, cleanup := createTestSQLiteStore(t)
+	defer cleanup()
+	testCursorPaging(t, store)
+}
@@ -20,6 +20,13 @@ type StoreConfig struct {
 	Type    StoreType
 	Path    string // For SQLite: database file path
 	MaxSize int    // For Memory: ring buffer size
+	Context string // Kubeconfig context events are recorded under (SQLite keeps each context's history separate)
+
+	// PresetsPath is the JSON file custom filter presets are saved to ("" = keep in memory)
+	PresetsPath string
+
+	// SavedQueriesPath is the JSON file saved queries and digest settings are saved to ("" = keep in memory)
+	SavedQueriesPath string
 }
 
 // DefaultStoreConfig returns sensible defaults
@@ -47,6 +54,18 @@ func InitStore(cfg StoreConfig) error {
 	globalStoreOnce.Do(func() {
 		globalConfig = cfg
 
+		if cfg.PresetsPath != "" {
+			if err := LoadFilterPresets(cfg.PresetsPath); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		}
+		if cfg.SavedQueriesPath != "" {
+			if err := LoadSavedQueries(cfg.SavedQueriesPath); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		}
+		startDigestScheduler()
+
 		switch cfg.Type {
 		case StoreTypeSQLite:
 			if cfg.Path == "" {
@@ -58,8 +77,9 @@ func InitStore(cfg StoreConfig) error {
 				initErr = fmt.Errorf("failed to create SQLite store: %w", err)
 				return
 			}
+			store.contextName = cfg.Context
 			globalStore = store
-			log.Printf("Initialized SQLite event store at %s", cfg.Path)
+			log.Printf("Initialized SQLite event store at %s (context %q)", cfg.Path, cfg.Context)
 
 		case StoreTypeMemory:
 			fallthrough
@@ -68,7 +88,9 @@ func InitStore(cfg StoreConfig) error {
 			if maxSize <= 0 {
 				maxSize = 1000
 			}
-			globalStore = NewMemoryStore(maxSize)
+			store := NewMemoryStore(maxSize)
+			store.contextName = cfg.Context
+			globalStore = store
 			log.Printf("Initialized in-memory event store (max %d events)", maxSize)
 		}
 	})
@@ -95,6 +117,22 @@ func ResetStore() {
 	globalStoreOnce = sync.Once{}
 }
 
+// SetMaxSize resizes the in-memory store to keep up to maxSize events. It
+// reports false when the store is SQLite, which isn't capped by event count.
+func SetMaxSize(maxSize int) bool {
+	globalStoreMu.Lock()
+	defer globalStoreMu.Unlock()
+	globalConfig.MaxSize = maxSize
+	store, ok := globalStore.(*MemoryStore)
+	if !ok {
+		return globalConfig.Type != StoreTypeSQLite
+	}
+	if store.Resize(maxSize) {
+		log.Printf("Resized in-memory event store (max %d events)", maxSize)
+	}
+	return true
+}
+
 // ReinitStore reinitializes the event store after a context switch
 // Must call ResetStore first
 func ReinitStore(cfg StoreConfig) error {
@@ -2,6 +2,7 @@ package timeline
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"time"
 )
@@ -17,6 +18,8 @@ type MemoryStore struct {
 	seenResources map[string]bool
 	seenMu        sync.RWMutex
 	filterCache   map[string]*CompiledFilter
+	filterVersion uint64 // presetsVersion the cache was built against
+	contextName   string // Kubeconfig context new events are tagged with
 }
 
 // NewMemoryStore creates a new in-memory event store
@@ -34,6 +37,13 @@ func NewMemoryStore(maxSize int) *MemoryStore {
 
 // Append adds a single event to the store
 func (m *MemoryStore) Append(ctx context.Context, event TimelineEvent) error {
+	if event.Severity == "" {
+		event.Severity = ComputeSeverity(&event, "")
+	}
+	if event.Context == "" {
+		event.Context = m.contextName
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -51,6 +61,12 @@ func (m *MemoryStore) AppendBatch(ctx context.Context, events []TimelineEvent) e
 	defer m.mu.Unlock()
 
 	for _, event := range events {
+		if event.Severity == "" {
+			event.Severity = ComputeSeverity(&event, "")
+		}
+		if event.Context == "" {
+			event.Context = m.contextName
+		}
 		m.records[m.head] = event
 		m.head = (m.head + 1) % m.maxSize
 		if m.count < m.maxSize {
@@ -73,6 +89,15 @@ func (m *MemoryStore) Query(ctx context.Context, opts QueryOptions) ([]TimelineE
 		}
 	}
 
+	var cursorTS time.Time
+	var cursorID string
+	if opts.Cursor != "" {
+		var err error
+		if cursorTS, cursorID, err = parseCursor(opts.Cursor); err != nil {
+			return nil, err
+		}
+	}
+
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -84,11 +109,10 @@ func (m *MemoryStore) Query(ctx context.Context, opts QueryOptions) ([]TimelineE
 		limit = 10000
 	}
 
-	results := make([]TimelineEvent, 0, limit)
-	skipped := 0
-
-	// Iterate backwards from most recent
-	for i := 0; i < m.count && len(results) < limit; i++ {
+	// Events aren't necessarily appended in timestamp order, so matches are
+	// sorted for results (and cursors) to be stable across pages
+	var matches []TimelineEvent
+	for i := 0; i < m.count; i++ {
 		idx := (m.head - 1 - i + m.maxSize) % m.maxSize
 		event := m.records[idx]
 
@@ -101,17 +125,25 @@ func (m *MemoryStore) Query(ctx context.Context, opts QueryOptions) ([]TimelineE
 		if !m.matchesFilters(&event, opts, cf) {
 			continue
 		}
-
-		// Handle offset
-		if opts.Offset > 0 && skipped < opts.Offset {
-			skipped++
+		if cursorID != "" && !olderThan(&event, cursorTS, cursorID) {
 			continue
 		}
 
-		results = append(results, event)
+		matches = append(matches, event)
 	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return olderThan(&matches[j], matches[i].Timestamp, matches[i].ID)
+	})
 
-	return results, nil
+	// Handle offset
+	if opts.Offset >= len(matches) {
+		return []TimelineEvent{}, nil
+	}
+	matches = matches[max(opts.Offset, 0):]
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
 }
 
 // QueryGrouped retrieves events grouped according to the specified mode
@@ -122,9 +154,13 @@ func (m *MemoryStore) QueryGrouped(ctx context.Context, opts QueryOptions) (*Tim
 	events, err := m.Query(ctx, QueryOptions{
 		Namespaces:       opts.Namespaces,
 		Kinds:            opts.Kinds,
+		Name:             opts.Name,
 		Since:            opts.Since,
 		Until:            opts.Until,
+		Cursor:           opts.Cursor,
 		Sources:          opts.Sources,
+		Severities:       opts.Severities,
+		Context:          opts.Context,
 		FilterPreset:     opts.FilterPreset,
 		Limit:            opts.Limit * 10, // Get more events for grouping
 		IncludeManaged:   opts.IncludeManaged,
@@ -145,6 +181,7 @@ func (m *MemoryStore) QueryGrouped(ctx context.Context, opts QueryOptions) (*Tim
 				TotalEvents: len(events),
 				QueryTimeMs: time.Since(startTime).Milliseconds(),
 				HasMore:     len(events) == opts.Limit,
+				NextCursor:  NextCursor(events, opts.Limit),
 			},
 		}, nil
 	}
@@ -275,6 +312,31 @@ func (m *MemoryStore) Stats() StoreStats {
 	}
 }
 
+// Resize changes the ring buffer size, keeping the newest events that fit.
+// Reports whether the size changed.
+func (m *MemoryStore) Resize(maxSize int) bool {
+	if maxSize <= 0 {
+		maxSize = 1000
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if maxSize == m.maxSize {
+		return false
+	}
+
+	kept := min(m.count, maxSize)
+	records := make([]TimelineEvent, maxSize)
+	// Copy oldest-to-newest so the ring starts at index 0
+	for i := 0; i < kept; i++ {
+		records[i] = m.records[(m.head-kept+i+m.maxSize)%m.maxSize]
+	}
+	m.records = records
+	m.maxSize = maxSize
+	m.count = kept
+	m.head = kept % maxSize
+	return true
+}
+
 // Close releases any resources held by the store
 func (m *MemoryStore) Close() error {
 	return nil
@@ -287,6 +349,12 @@ func (m *MemoryStore) matchesFilters(event *TimelineEvent, opts QueryOptions, cf
 		return false
 	}
 
+	// The memory store is reset on context switch, so this only excludes
+	// events when another context is requested explicitly
+	if contextName := resolveContext(opts.Context, m.contextName); contextName != "" && event.Context != contextName {
+		return false
+	}
+
 	// Apply individual filters (these override preset if both specified)
 	if !opts.Since.IsZero() && event.Timestamp.Before(opts.Since) {
 		return false
@@ -322,6 +390,10 @@ func (m *MemoryStore) matchesFilters(event *TimelineEvent, opts QueryOptions, cf
 		}
 	}
 
+	if opts.Name != "" && event.Name != opts.Name {
+		return false
+	}
+
 	if len(opts.Sources) > 0 {
 		found := false
 		for _, s := range opts.Sources {
@@ -354,20 +426,20 @@ func (m *MemoryStore) matchesFilters(event *TimelineEvent, opts QueryOptions, cf
 		return false
 	}
 
-	return true
+	return event.MatchesSeverity(opts.Severities)
 }
 
 // getOrCompileFilter returns a cached compiled filter or compiles a new one
 func (m *MemoryStore) getOrCompileFilter(presetName string) (*CompiledFilter, error) {
+	version := presetsVersion()
 	m.mu.RLock()
-	if cf, ok := m.filterCache[presetName]; ok {
+	if cf, ok := m.filterCache[presetName]; ok && m.filterVersion == version {
 		m.mu.RUnlock()
 		return cf, nil
 	}
 	m.mu.RUnlock()
 
-	presets := DefaultFilterPresets()
-	preset, ok := presets[presetName]
+	preset, ok := LookupFilterPreset(presetName)
 	if !ok {
 		return nil, nil // Unknown preset - no filtering
 	}
@@ -378,6 +450,11 @@ func (m *MemoryStore) getOrCompileFilter(presetName string) (*CompiledFilter, er
 	}
 
 	m.mu.Lock()
+	if m.filterVersion != version {
+		// Custom presets changed since the cache was built
+		m.filterCache = make(map[string]*CompiledFilter)
+		m.filterVersion = version
+	}
 	m.filterCache[presetName] = cf
 	m.mu.Unlock()
 
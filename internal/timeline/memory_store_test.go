@@ -113,6 +113,15 @@ func TestMemoryStore_Query_Kinds(t *testing.T) {
 			t.Errorf("Expected kind 'Deployment', got '%s'", e.Kind)
 		}
 	}
+
+	// Narrow to one resource by name
+	result, err = store.Query(ctx, QueryOptions{Kinds: []string{"Deployment"}, Name: "deploy-2", Limit: 10, IncludeManaged: true})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(result) != 1 || result[0].Name != "deploy-2" {
+		t.Errorf("Expected only deploy-2, got %+v", result)
+	}
 }
 
 func TestMemoryStore_Query_Since(t *testing.T) {
@@ -400,3 +409,38 @@ func TestMemoryStore_FilterPreset(t *testing.T) {
 		t.Errorf("Expected 3 events with 'all' preset, got %d", len(result))
 	}
 }
+
+func TestMemoryStore_Resize(t *testing.T) {
+	store := NewMemoryStore(5)
+	ctx := context.Background()
+	base := time.Now()
+	for i := 0; i < 7; i++ {
+		store.Append(ctx, TimelineEvent{ID: string(rune('a' + i)), Timestamp: base.Add(time.Duration(i) * time.Second), Kind: "Pod", Namespace: "default", Name: "p", EventType: EventTypeUpdate, Source: SourceInformer})
+	}
+
+	// Shrinking keeps the newest events
+	store.Resize(3)
+	events, err := store.Query(ctx, QueryOptions{Limit: 10, IncludeManaged: true})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(events) != 3 || events[0].ID != "g" || events[2].ID != "e" {
+		t.Fatalf("after shrink got %v", eventIDs(events))
+	}
+
+	// Growing keeps everything and accepts more
+	store.Resize(10)
+	store.Append(ctx, TimelineEvent{ID: "h", Timestamp: base.Add(10 * time.Second), Kind: "Pod", Namespace: "default", Name: "p", EventType: EventTypeUpdate, Source: SourceInformer})
+	events, _ = store.Query(ctx, QueryOptions{Limit: 10, IncludeManaged: true})
+	if len(events) != 4 || events[0].ID != "h" || events[3].ID != "e" {
+		t.Fatalf("after grow got %v", eventIDs(events))
+	}
+}
+
+func eventIDs(events []TimelineEvent) []string {
+	ids := make([]string, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	return ids
+}
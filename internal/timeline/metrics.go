@@ -44,6 +44,7 @@ const (
 	DropReasonHistoryNil     = "history_nil"
 	DropReasonStoreFailed    = "store_failed"
 	DropReasonSubscriberFull = "subscriber_full"
+	DropReasonRelist         = "relist"
 )
 
 var (
@@ -332,6 +333,9 @@ func GetDiagnosis(kind, namespace, name string) DiagnoseResponse {
 			case DropReasonSubscriberFull:
 				resp.Recommendations = append(resp.Recommendations,
 					"SSE subscriber channel full - clients may not be keeping up with event stream")
+			case DropReasonRelist:
+				resp.Recommendations = append(resp.Recommendations,
+					"Resource was replayed by an informer relist after a watch failure - this is normal and not a real change")
 			}
 		}
 	}
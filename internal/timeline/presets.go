@@ -0,0 +1,169 @@
+package timeline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrInvalidPreset is returned when a custom preset fails validation
+	ErrInvalidPreset = errors.New("invalid filter preset")
+	// ErrBuiltinPreset is returned when trying to overwrite or delete a built-in preset
+	ErrBuiltinPreset = errors.New("built-in filter presets can't be modified")
+	// ErrPresetNotFound is returned when deleting a preset that doesn't exist
+	ErrPresetNotFound = errors.New("filter preset not found")
+)
+
+var presetNameRe = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// customPresets holds user-defined filter presets. They live outside the event
+// store so they survive context switches, which reset the store.
+var customPresets = struct {
+	mu      sync.RWMutex
+	presets map[string]FilterPreset
+	path    string // JSON file presets are persisted to ("" = memory only)
+	version uint64 // Bumped on every change so stores drop stale compiled filters
+}{presets: make(map[string]FilterPreset)}
+
+// LoadFilterPresets sets the file custom presets are persisted to and loads any
+// presets already saved there. A missing file is not an error.
+func LoadFilterPresets(path string) error {
+	customPresets.mu.Lock()
+	defer customPresets.mu.Unlock()
+
+	customPresets.path = path
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read filter presets: %w", err)
+	}
+
+	var saved []FilterPreset
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("failed to parse filter presets %s: %w", path, err)
+	}
+	presets := make(map[string]FilterPreset, len(saved))
+	for _, p := range saved {
+		if validatePreset(&p) == nil {
+			presets[p.Name] = p
+		}
+	}
+	customPresets.presets = presets
+	customPresets.version++
+	return nil
+}
+
+// LookupFilterPreset returns a built-in or custom preset by name
+func LookupFilterPreset(name string) (FilterPreset, bool) {
+	if p, ok := DefaultFilterPresets()[name]; ok {
+		return p, true
+	}
+	customPresets.mu.RLock()
+	defer customPresets.mu.RUnlock()
+	p, ok := customPresets.presets[name]
+	return p, ok
+}
+
+// ListFilterPresets returns built-in presets followed by custom presets, each sorted by name
+func ListFilterPresets() []FilterPreset {
+	var builtin, custom []FilterPreset
+	for _, p := range DefaultFilterPresets() {
+		builtin = append(builtin, p)
+	}
+	customPresets.mu.RLock()
+	for _, p := range customPresets.presets {
+		custom = append(custom, p)
+	}
+	customPresets.mu.RUnlock()
+
+	sort.Slice(builtin, func(i, j int) bool { return builtin[i].Name < builtin[j].Name })
+	sort.Slice(custom, func(i, j int) bool { return custom[i].Name < custom[j].Name })
+	return append(builtin, custom...)
+}
+
+// SaveFilterPreset creates or replaces a custom preset
+func SaveFilterPreset(p FilterPreset) error {
+	if _, ok := DefaultFilterPresets()[p.Name]; ok {
+		return ErrBuiltinPreset
+	}
+	if err := validatePreset(&p); err != nil {
+		return err
+	}
+
+	customPresets.mu.Lock()
+	defer customPresets.mu.Unlock()
+	customPresets.presets[p.Name] = p
+	customPresets.version++
+	return persistPresetsLocked()
+}
+
+// DeleteFilterPreset removes a custom preset
+func DeleteFilterPreset(name string) error {
+	if _, ok := DefaultFilterPresets()[name]; ok {
+		return ErrBuiltinPreset
+	}
+
+	customPresets.mu.Lock()
+	defer customPresets.mu.Unlock()
+	if _, ok := customPresets.presets[name]; !ok {
+		return ErrPresetNotFound
+	}
+	delete(customPresets.presets, name)
+	customPresets.version++
+	return persistPresetsLocked()
+}
+
+// presetsVersion returns a counter that changes whenever custom presets change
+func presetsVersion() uint64 {
+	customPresets.mu.RLock()
+	defer customPresets.mu.RUnlock()
+	return customPresets.version
+}
+
+func validatePreset(p *FilterPreset) error {
+	if !presetNameRe.MatchString(p.Name) {
+		return fmt.Errorf("%w: name must be lowercase alphanumeric or '-' (max 63 characters)", ErrInvalidPreset)
+	}
+	for _, s := range p.IncludeSeverities {
+		if _, ok := ParseSeverity(string(s)); !ok {
+			return fmt.Errorf("%w: unknown severity %q", ErrInvalidPreset, s)
+		}
+	}
+	if _, err := CompileFilter(p); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPreset, err)
+	}
+	p.Builtin = false
+	return nil
+}
+
+// persistPresetsLocked writes custom presets to disk. Caller must hold customPresets.mu.
+func persistPresetsLocked() error {
+	if customPresets.path == "" {
+		return nil
+	}
+	presets := make([]FilterPreset, 0, len(customPresets.presets))
+	for _, p := range customPresets.presets {
+		presets = append(presets, p)
+	}
+	sort.Slice(presets, func(i, j int) bool { return presets[i].Name < presets[j].Name })
+
+	data, err := json.MarshalIndent(presets, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(customPresets.path), 0755); err != nil {
+		return fmt.Errorf("failed to save filter presets: %w", err)
+	}
+	if err := os.WriteFile(customPresets.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to save filter presets: %w", err)
+	}
+	return nil
+}
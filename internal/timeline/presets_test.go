@@ -0,0 +1,127 @@
+package timeline
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// resetCustomPresets clears custom presets so tests don't leak state into each other
+func resetCustomPresets(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		customPresets.mu.Lock()
+		customPresets.presets = make(map[string]FilterPreset)
+		customPresets.path = ""
+		customPresets.version++
+		customPresets.mu.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestSaveFilterPreset_Validation(t *testing.T) {
+	resetCustomPresets(t)
+
+	tests := []struct {
+		name   string
+		preset FilterPreset
+		err    error
+	}{
+		{"builtin name", FilterPreset{Name: PresetDefault}, ErrBuiltinPreset},
+		{"empty name", FilterPreset{Name: ""}, ErrInvalidPreset},
+		{"uppercase name", FilterPreset{Name: "MyPreset"}, ErrInvalidPreset},
+		{"bad regex", FilterPreset{Name: "bad", ExcludeNamePatterns: []string{"[invalid"}}, ErrInvalidPreset},
+		{"bad severity", FilterPreset{Name: "bad", IncludeSeverities: []Severity{"urgent"}}, ErrInvalidPreset},
+		{"valid", FilterPreset{Name: "my-team", IncludeKinds: []string{"Deployment"}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SaveFilterPreset(tt.preset)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+
+	if err := DeleteFilterPreset(PresetWorkloads); !errors.Is(err, ErrBuiltinPreset) {
+		t.Errorf("expected ErrBuiltinPreset deleting a built-in, got %v", err)
+	}
+	if err := DeleteFilterPreset("missing"); !errors.Is(err, ErrPresetNotFound) {
+		t.Errorf("expected ErrPresetNotFound, got %v", err)
+	}
+}
+
+func TestListFilterPresets(t *testing.T) {
+	resetCustomPresets(t)
+	_ = SaveFilterPreset(FilterPreset{Name: "zz-custom", Builtin: true})
+
+	presets := ListFilterPresets()
+	builtins := len(DefaultFilterPresets())
+	if len(presets) != builtins+1 {
+		t.Fatalf("expected %d presets, got %d", builtins+1, len(presets))
+	}
+	for _, p := range presets[:builtins] {
+		if !p.Builtin {
+			t.Errorf("expected built-in preset first, got %s", p.Name)
+		}
+	}
+	if last := presets[builtins]; last.Name != "zz-custom" || last.Builtin {
+		t.Errorf("custom preset should be last and not marked built-in: %+v", last)
+	}
+}
+
+func TestFilterPresets_Persistence(t *testing.T) {
+	resetCustomPresets(t)
+	path := filepath.Join(t.TempDir(), "presets.json")
+
+	if err := LoadFilterPresets(path); err != nil {
+		t.Fatalf("loading a missing file should succeed: %v", err)
+	}
+	if err := SaveFilterPreset(FilterPreset{Name: "critical-prod", IncludeSeverities: []Severity{SeverityCritical}}); err != nil {
+		t.Fatalf("SaveFilterPreset failed: %v", err)
+	}
+
+	// Simulate a restart
+	customPresets.mu.Lock()
+	customPresets.presets = make(map[string]FilterPreset)
+	customPresets.mu.Unlock()
+
+	if err := LoadFilterPresets(path); err != nil {
+		t.Fatalf("LoadFilterPresets failed: %v", err)
+	}
+	p, ok := LookupFilterPreset("critical-prod")
+	if !ok || len(p.IncludeSeverities) != 1 || p.IncludeSeverities[0] != SeverityCritical {
+		t.Errorf("expected persisted preset, got %+v (found=%v)", p, ok)
+	}
+}
+
+func TestMemoryStore_CustomPreset(t *testing.T) {
+	resetCustomPresets(t)
+	store := NewMemoryStore(100)
+	ctx := context.Background()
+
+	_ = store.AppendBatch(ctx, []TimelineEvent{
+		{ID: "c-1", Timestamp: time.Now(), Kind: "Deployment", Namespace: "default", Name: "api", EventType: EventTypeAdd, Source: SourceInformer},
+		{ID: "c-2", Timestamp: time.Now(), Kind: "Service", Namespace: "default", Name: "api", EventType: EventTypeAdd, Source: SourceInformer},
+	})
+
+	if err := SaveFilterPreset(FilterPreset{Name: "services", IncludeKinds: []string{"Service"}}); err != nil {
+		t.Fatalf("SaveFilterPreset failed: %v", err)
+	}
+	result, _ := store.Query(ctx, QueryOptions{Limit: 10, FilterPreset: "services"})
+	if len(result) != 1 || result[0].Kind != "Service" {
+		t.Fatalf("Expected only the Service event, got %+v", result)
+	}
+
+	// Updating the preset must invalidate the store's compiled filter cache
+	if err := SaveFilterPreset(FilterPreset{Name: "services", IncludeKinds: []string{"Deployment"}}); err != nil {
+		t.Fatalf("SaveFilterPreset failed: %v", err)
+	}
+	result, _ = store.Query(ctx, QueryOptions{Limit: 10, FilterPreset: "services"})
+	if len(result) != 1 || result[0].Kind != "Deployment" {
+		t.Errorf("Expected updated preset to apply, got %+v", result)
+	}
+}
@@ -0,0 +1,419 @@
+package timeline
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/outbound"
+)
+
+var (
+	// ErrInvalidSavedQuery is returned when a saved query fails validation
+	ErrInvalidSavedQuery = errors.New("invalid saved query")
+	// ErrSavedQueryNotFound is returned for a saved query that doesn't exist
+	ErrSavedQueryNotFound = errors.New("saved query not found")
+	// ErrNoDigestWebhook is returned when sending a digest for a query without a webhook
+	ErrNoDigestWebhook = errors.New("saved query has no digest webhook")
+)
+
+// DigestSchedule is how often a saved query's digest is sent
+type DigestSchedule string
+
+const (
+	DigestNone   DigestSchedule = ""
+	DigestDaily  DigestSchedule = "daily"
+	DigestWeekly DigestSchedule = "weekly"
+)
+
+const (
+	digestCheckInterval = 15 * time.Minute
+	digestEventLimit    = 10000 // The stores' per-query cap
+	digestTopReasons    = 10
+	digestSendTimeout   = 15 * time.Second
+)
+
+// Period returns the window a digest covers
+func (d DigestSchedule) Period() time.Duration {
+	if d == DigestWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// SavedQuery is a named timeline query (filter preset, scope and severities),
+// optionally sent as a periodic digest to a webhook
+type SavedQuery struct {
+	Name         string         `json:"name"`
+	Filter       string         `json:"filter,omitempty"` // Filter preset name (default: "default")
+	Namespaces   []string       `json:"namespaces,omitempty"`
+	Kinds        []string       `json:"kinds,omitempty"`
+	Severities   []Severity     `json:"severities,omitempty"`
+	Digest       DigestSchedule `json:"digest,omitempty"`
+	WebhookURL   string         `json:"webhookUrl,omitempty"` // Receives the digest as JSON; Slack-compatible "text" field
+	LastDigestAt *time.Time     `json:"lastDigestAt,omitempty"`
+}
+
+// savedQueries holds saved timeline queries. Like custom presets they live
+// outside the event store, so they survive context switches.
+var savedQueries = struct {
+	mu      sync.RWMutex
+	queries map[string]SavedQuery
+	path    string // JSON file queries are persisted to ("" = memory only)
+}{queries: make(map[string]SavedQuery)}
+
+// LoadSavedQueries sets the file saved queries are persisted to and loads any
+// queries already saved there. A missing file is not an error.
+func LoadSavedQueries(path string) error {
+	savedQueries.mu.Lock()
+	defer savedQueries.mu.Unlock()
+
+	savedQueries.path = path
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read saved timeline queries: %w", err)
+	}
+
+	var saved []SavedQuery
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("failed to parse saved timeline queries %s: %w", path, err)
+	}
+	queries := make(map[string]SavedQuery, len(saved))
+	for _, q := range saved {
+		if validateSavedQuery(&q) == nil {
+			queries[q.Name] = q
+		}
+	}
+	savedQueries.queries = queries
+	return nil
+}
+
+// ListSavedQueries returns saved queries sorted by name
+func ListSavedQueries() []SavedQuery {
+	savedQueries.mu.RLock()
+	defer savedQueries.mu.RUnlock()
+	queries := make([]SavedQuery, 0, len(savedQueries.queries))
+	for _, q := range savedQueries.queries {
+		queries = append(queries, q)
+	}
+	sort.Slice(queries, func(i, j int) bool { return queries[i].Name < queries[j].Name })
+	return queries
+}
+
+// LookupSavedQuery returns a saved query by name
+func LookupSavedQuery(name string) (SavedQuery, bool) {
+	savedQueries.mu.RLock()
+	defer savedQueries.mu.RUnlock()
+	q, ok := savedQueries.queries[name]
+	return q, ok
+}
+
+// SaveSavedQuery creates or replaces a saved query. Enabling a digest starts
+// its first period now, so the first digest goes out one period later.
+func SaveSavedQuery(q SavedQuery) error {
+	if err := validateSavedQuery(&q); err != nil {
+		return err
+	}
+
+	savedQueries.mu.Lock()
+	defer savedQueries.mu.Unlock()
+	q.LastDigestAt = nil
+	if q.Digest != DigestNone {
+		if existing, ok := savedQueries.queries[q.Name]; ok && existing.LastDigestAt != nil {
+			q.LastDigestAt = existing.LastDigestAt
+		} else {
+			now := time.Now()
+			q.LastDigestAt = &now
+		}
+	}
+	savedQueries.queries[q.Name] = q
+	return persistSavedQueriesLocked()
+}
+
+// DeleteSavedQuery removes a saved query
+func DeleteSavedQuery(name string) error {
+	savedQueries.mu.Lock()
+	defer savedQueries.mu.Unlock()
+	if _, ok := savedQueries.queries[name]; !ok {
+		return ErrSavedQueryNotFound
+	}
+	delete(savedQueries.queries, name)
+	return persistSavedQueriesLocked()
+}
+
+func validateSavedQuery(q *SavedQuery) error {
+	if !presetNameRe.MatchString(q.Name) {
+		return fmt.Errorf("%w: name must be lowercase alphanumeric or '-' (max 63 characters)", ErrInvalidSavedQuery)
+	}
+	if q.Filter != "" {
+		if _, ok := LookupFilterPreset(q.Filter); !ok {
+			return fmt.Errorf("%w: unknown filter preset %q", ErrInvalidSavedQuery, q.Filter)
+		}
+	}
+	for _, s := range q.Severities {
+		if _, ok := ParseSeverity(string(s)); !ok {
+			return fmt.Errorf("%w: unknown severity %q", ErrInvalidSavedQuery, s)
+		}
+	}
+	switch q.Digest {
+	case DigestNone, DigestDaily, DigestWeekly:
+	default:
+		return fmt.Errorf("%w: digest must be daily or weekly", ErrInvalidSavedQuery)
+	}
+	if q.WebhookURL != "" {
+		u, err := url.Parse(q.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: webhookUrl must be an http(s) URL", ErrInvalidSavedQuery)
+		}
+	}
+	if q.Digest != DigestNone && q.WebhookURL == "" {
+		return fmt.Errorf("%w: a digest needs a webhookUrl", ErrInvalidSavedQuery)
+	}
+	return nil
+}
+
+// persistSavedQueriesLocked writes saved queries to disk. Caller must hold savedQueries.mu.
+func persistSavedQueriesLocked() error {
+	if savedQueries.path == "" {
+		return nil
+	}
+	queries := make([]SavedQuery, 0, len(savedQueries.queries))
+	for _, q := range savedQueries.queries {
+		queries = append(queries, q)
+	}
+	sort.Slice(queries, func(i, j int) bool { return queries[i].Name < queries[j].Name })
+
+	data, err := json.MarshalIndent(queries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(savedQueries.path), 0755); err != nil {
+		return fmt.Errorf("failed to save timeline queries: %w", err)
+	}
+	// Webhook URLs often embed a secret token, so keep the file private
+	if err := os.WriteFile(savedQueries.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save timeline queries: %w", err)
+	}
+	return nil
+}
+
+// ReasonCount is how often an event reason occurred in a digest period
+type ReasonCount struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// Digest summarizes a saved query's events over one period
+type Digest struct {
+	Query       string           `json:"query"`
+	Since       time.Time        `json:"since"`
+	Until       time.Time        `json:"until"`
+	TotalEvents int              `json:"totalEvents"`
+	BySeverity  map[Severity]int `json:"bySeverity"`
+	ByKind      map[string]int   `json:"byKind"`
+	TopReasons  []ReasonCount    `json:"topReasons"`  // Most frequent warning/critical reasons
+	NewProblems []string         `json:"newProblems"` // Warning/critical reasons absent from the previous period
+	Truncated   bool             `json:"truncated,omitempty"`
+}
+
+// queryOptions turns a saved query into store query options for [since, until)
+func (q SavedQuery) queryOptions(since, until time.Time) QueryOptions {
+	filter := q.Filter
+	if filter == "" {
+		filter = PresetDefault
+	}
+	return QueryOptions{
+		Namespaces:       q.Namespaces,
+		Kinds:            q.Kinds,
+		Severities:       q.Severities,
+		Since:            since,
+		Until:            until,
+		FilterPreset:     filter,
+		Limit:            digestEventLimit,
+		IncludeK8sEvents: true,
+	}
+}
+
+// BuildDigest summarizes a saved query's events in the period ending at until
+func BuildDigest(ctx context.Context, store EventStore, q SavedQuery, until time.Time) (*Digest, error) {
+	period := q.Digest.Period()
+	since := until.Add(-period)
+
+	events, err := store.Query(ctx, q.queryOptions(since, until))
+	if err != nil {
+		return nil, err
+	}
+	previous, err := store.Query(ctx, q.queryOptions(since.Add(-period), since))
+	if err != nil {
+		return nil, err
+	}
+
+	digest := &Digest{
+		Query:       q.Name,
+		Since:       since,
+		Until:       until,
+		TotalEvents: len(events),
+		BySeverity:  make(map[Severity]int),
+		ByKind:      make(map[string]int),
+		TopReasons:  []ReasonCount{},
+		NewProblems: []string{},
+		Truncated:   len(events) == digestEventLimit,
+	}
+	reasons := make(map[string]int)
+	for _, e := range events {
+		digest.BySeverity[e.Severity]++
+		digest.ByKind[e.Kind]++
+		if isProblem(&e) {
+			reasons[e.Reason]++
+		}
+	}
+	for reason, count := range reasons {
+		digest.TopReasons = append(digest.TopReasons, ReasonCount{reason, count})
+	}
+	sort.Slice(digest.TopReasons, func(i, j int) bool {
+		if digest.TopReasons[i].Count != digest.TopReasons[j].Count {
+			return digest.TopReasons[i].Count > digest.TopReasons[j].Count
+		}
+		return digest.TopReasons[i].Reason < digest.TopReasons[j].Reason
+	})
+	if len(digest.TopReasons) > digestTopReasons {
+		digest.TopReasons = digest.TopReasons[:digestTopReasons]
+	}
+
+	seenBefore := make(map[string]bool)
+	for _, e := range previous {
+		if isProblem(&e) {
+			seenBefore[e.Reason] = true
+		}
+	}
+	for reason := range reasons {
+		if !seenBefore[reason] {
+			digest.NewProblems = append(digest.NewProblems, reason)
+		}
+	}
+	sort.Strings(digest.NewProblems)
+	return digest, nil
+}
+
+func isProblem(e *TimelineEvent) bool {
+	return e.Reason != "" && (e.Severity == SeverityWarning || e.Severity == SeverityCritical)
+}
+
+// Text renders the digest as a short plain-text message
+func (d *Digest) Text(schedule DigestSchedule) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Radar %s digest for %q: %d events", schedule, d.Query, d.TotalEvents)
+	if d.Truncated {
+		b.WriteString("+")
+	}
+	fmt.Fprintf(&b, " (%d critical, %d warning)", d.BySeverity[SeverityCritical], d.BySeverity[SeverityWarning])
+	if len(d.NewProblems) > 0 {
+		fmt.Fprintf(&b, "\nNew problems: %s", strings.Join(d.NewProblems, ", "))
+	}
+	if len(d.TopReasons) > 0 {
+		parts := make([]string, 0, len(d.TopReasons))
+		for _, r := range d.TopReasons {
+			parts = append(parts, fmt.Sprintf("%s ×%d", r.Reason, r.Count))
+		}
+		fmt.Fprintf(&b, "\nTop problems: %s", strings.Join(parts, ", "))
+	}
+	return b.String()
+}
+
+// SendDigest posts a digest to the query's webhook as {"text", "digest"}
+func SendDigest(ctx context.Context, q SavedQuery, digest *Digest) error {
+	if q.WebhookURL == "" {
+		return ErrNoDigestWebhook
+	}
+	if outbound.Offline() {
+		return outbound.ErrOffline
+	}
+	body, err := json.Marshal(map[string]any{"text": digest.Text(q.Digest), "digest": digest})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, digestSendTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, q.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := outbound.NewClient(digestSendTimeout).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send digest: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("digest webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+var digestSchedulerOnce sync.Once
+
+// startDigestScheduler periodically sends digests that are due. It reads the
+// current store on every check, so it keeps working across context switches.
+func startDigestScheduler() {
+	digestSchedulerOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(digestCheckInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				sendDueDigests(time.Now())
+			}
+		}()
+	})
+}
+
+func sendDueDigests(now time.Time) {
+	store := GetStore()
+	if store == nil {
+		return
+	}
+	for _, q := range ListSavedQueries() {
+		if q.Digest == DigestNone || q.LastDigestAt == nil || now.Sub(*q.LastDigestAt) < q.Digest.Period() {
+			continue
+		}
+		digest, err := BuildDigest(context.Background(), store, q, now)
+		if err == nil {
+			err = SendDigest(context.Background(), q, digest)
+		}
+		if err != nil {
+			// Retried on the next check; offline mode just skips silently
+			if !errors.Is(err, outbound.ErrOffline) {
+				log.Printf("[timeline] Failed to send %s digest for %q: %v", q.Digest, q.Name, err)
+			}
+			continue
+		}
+		markDigestSent(q.Name, now)
+	}
+}
+
+func markDigestSent(name string, at time.Time) {
+	savedQueries.mu.Lock()
+	defer savedQueries.mu.Unlock()
+	q, ok := savedQueries.queries[name]
+	if !ok {
+		return
+	}
+	q.LastDigestAt = &at
+	savedQueries.queries[name] = q
+	if err := persistSavedQueriesLocked(); err != nil {
+		log.Printf("[timeline] %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+package timeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// resetSavedQueries clears saved queries so tests don't leak state into each other
+func resetSavedQueries(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		savedQueries.mu.Lock()
+		savedQueries.queries = make(map[string]SavedQuery)
+		savedQueries.path = ""
+		savedQueries.mu.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestSaveSavedQuery_Validation(t *testing.T) {
+	resetSavedQueries(t)
+
+	tests := []struct {
+		name  string
+		query SavedQuery
+		err   error
+	}{
+		{"bad name", SavedQuery{Name: "Team Leads"}, ErrInvalidSavedQuery},
+		{"unknown filter", SavedQuery{Name: "q", Filter: "nope"}, ErrInvalidSavedQuery},
+		{"bad severity", SavedQuery{Name: "q", Severities: []Severity{"urgent"}}, ErrInvalidSavedQuery},
+		{"bad schedule", SavedQuery{Name: "q", Digest: "hourly", WebhookURL: "https://hooks.example.com/x"}, ErrInvalidSavedQuery},
+		{"digest without webhook", SavedQuery{Name: "q", Digest: DigestDaily}, ErrInvalidSavedQuery},
+		{"bad webhook", SavedQuery{Name: "q", WebhookURL: "ftp://example.com"}, ErrInvalidSavedQuery},
+		{"valid", SavedQuery{Name: "payments", Filter: PresetWarningsOnly, Namespaces: []string{"payments"}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SaveSavedQuery(tt.query); !errors.Is(err, tt.err) {
+				t.Errorf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+	if err := DeleteSavedQuery("missing"); !errors.Is(err, ErrSavedQueryNotFound) {
+		t.Errorf("expected ErrSavedQueryNotFound, got %v", err)
+	}
+}
+
+func TestSavedQueries_Persist(t *testing.T) {
+	resetSavedQueries(t)
+	path := filepath.Join(t.TempDir(), "queries.json")
+	if err := LoadSavedQueries(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveSavedQuery(SavedQuery{Name: "weekly-ops", Digest: DigestWeekly, WebhookURL: "https://hooks.example.com/x"}); err != nil {
+		t.Fatal(err)
+	}
+	saved, _ := LookupSavedQuery("weekly-ops")
+	if saved.LastDigestAt == nil {
+		t.Fatal("expected enabling a digest to start its first period")
+	}
+
+	resetSavedQueries(t)
+	if err := LoadSavedQueries(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := ListSavedQueries(); len(got) != 1 || got[0].Name != "weekly-ops" || got[0].Digest != DigestWeekly {
+		t.Errorf("expected the query to be reloaded, got %+v", got)
+	}
+}
+
+func TestBuildDigest(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore(100)
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	event := func(id string, ago time.Duration, kind, reason string, severity Severity) TimelineEvent {
+		return TimelineEvent{
+			ID: id, Timestamp: now.Add(-ago), Source: SourceK8sEvent, Kind: kind, Namespace: "shop", Name: "api",
+			EventType: EventTypeWarning, Reason: reason, Severity: severity,
+		}
+	}
+	_ = store.AppendBatch(ctx, []TimelineEvent{
+		// Previous day: BackOff was already happening
+		event("old-1", 30*time.Hour, "Pod", "BackOff", SeverityCritical),
+		// Digest period
+		event("e1", 2*time.Hour, "Pod", "BackOff", SeverityCritical),
+		event("e2", 3*time.Hour, "Pod", "BackOff", SeverityCritical),
+		event("e3", 4*time.Hour, "Pod", "OOMKilled", SeverityCritical),
+		event("e4", 5*time.Hour, "Deployment", "FailedCreate", SeverityWarning),
+	})
+
+	q := SavedQuery{Name: "shop", Filter: PresetAll, Namespaces: []string{"shop"}, Digest: DigestDaily}
+	digest, err := BuildDigest(ctx, store, q, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest.TotalEvents != 4 || digest.BySeverity[SeverityCritical] != 3 || digest.ByKind["Pod"] != 3 {
+		t.Errorf("unexpected counts %+v", digest)
+	}
+	if want := []string{"FailedCreate", "OOMKilled"}; !reflect.DeepEqual(digest.NewProblems, want) {
+		t.Errorf("expected new problems %v, got %v", want, digest.NewProblems)
+	}
+	if digest.TopReasons[0] != (ReasonCount{"BackOff", 2}) {
+		t.Errorf("expected BackOff to top the reasons, got %v", digest.TopReasons)
+	}
+}
+
+func TestSendDueDigests(t *testing.T) {
+	resetSavedQueries(t)
+	var received struct {
+		Text   string `json:"text"`
+		Digest Digest `json:"digest"`
+	}
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer hook.Close()
+
+	ResetStore()
+	if err := InitStore(StoreConfig{Type: StoreTypeMemory, MaxSize: 10}); err != nil {
+		t.Fatal(err)
+	}
+	defer ResetStore()
+
+	if err := SaveSavedQuery(SavedQuery{Name: "leads", Filter: PresetAll, Digest: DigestDaily, WebhookURL: hook.URL}); err != nil {
+		t.Fatal(err)
+	}
+	start := *ListSavedQueries()[0].LastDigestAt
+
+	sendDueDigests(start.Add(time.Hour))
+	if received.Text != "" {
+		t.Fatalf("expected no digest before the period ends, got %q", received.Text)
+	}
+
+	sendDueDigests(start.Add(25 * time.Hour))
+	if received.Text != `Radar daily digest for "leads": 0 events (0 critical, 0 warning)` || received.Digest.Query != "leads" {
+		t.Errorf("unexpected digest %+v", received)
+	}
+	if last := ListSavedQueries()[0].LastDigestAt; !last.Equal(start.Add(25 * time.Hour)) {
+		t.Errorf("expected the digest to be marked sent, got %v", last)
+	}
+}
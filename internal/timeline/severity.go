@@ -0,0 +1,71 @@
+package timeline
+
+import "slices"
+
+// Severity classifies how much attention an event needs
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// criticalReasons are event reasons that mean a workload is down or being
+// killed, as opposed to warnings that often resolve on their own
+var criticalReasons = map[string]bool{
+	"OOMKilled":            true,
+	"OOMKilling":           true,
+	"SystemOOM":            true,
+	"CrashLoopBackOff":     true,
+	"BackOff":              true,
+	"Evicted":              true,
+	"NodeNotReady":         true,
+	"Rebooted":             true,
+	"BackoffLimitExceeded": true,
+	"DeadlineExceeded":     true,
+}
+
+// ComputeSeverity classifies an event from its reason, event type, and health.
+// previous is the resource's health before an update ("" if unknown): a
+// transition into unhealthy is critical, and healthy to degraded is a warning.
+func ComputeSeverity(e *TimelineEvent, previous HealthState) Severity {
+	if criticalReasons[e.Reason] {
+		return SeverityCritical
+	}
+	if e.EventType == EventTypeWarning {
+		return SeverityWarning
+	}
+	if e.EventType == EventTypeDelete {
+		return SeverityInfo
+	}
+
+	if previous != "" && previous != HealthUnknown && previous != e.HealthState {
+		switch {
+		case e.HealthState == HealthUnhealthy:
+			return SeverityCritical
+		case e.HealthState == HealthDegraded && previous == HealthHealthy:
+			return SeverityWarning
+		}
+	}
+	// Staying unhealthy is still worth surfacing; degraded without a transition
+	// is usually a rollout or a pod starting up
+	if e.HealthState == HealthUnhealthy {
+		return SeverityWarning
+	}
+	return SeverityInfo
+}
+
+// ParseSeverity converts a string to a Severity. Returns false for unknown values.
+func ParseSeverity(s string) (Severity, bool) {
+	switch Severity(s) {
+	case SeverityInfo, SeverityWarning, SeverityCritical:
+		return Severity(s), true
+	}
+	return "", false
+}
+
+// MatchesSeverity returns true if severities is empty or contains the event's severity
+func (e *TimelineEvent) MatchesSeverity(severities []Severity) bool {
+	return len(severities) == 0 || slices.Contains(severities, e.Severity)
+}
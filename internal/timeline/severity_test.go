@@ -0,0 +1,160 @@
+package timeline
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestComputeSeverity(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    TimelineEvent
+		previous HealthState
+		expected Severity
+	}{
+		{"normal add", TimelineEvent{EventType: EventTypeAdd, HealthState: HealthHealthy}, "", SeverityInfo},
+		{"warning event", TimelineEvent{EventType: EventTypeWarning, Reason: "FailedScheduling"}, "", SeverityWarning},
+		{"critical reason", TimelineEvent{EventType: EventTypeWarning, Reason: "BackOff"}, "", SeverityCritical},
+		{"oom on normal event", TimelineEvent{EventType: EventTypeNormal, Reason: "OOMKilled"}, "", SeverityCritical},
+		{"healthy to unhealthy", TimelineEvent{EventType: EventTypeUpdate, HealthState: HealthUnhealthy}, HealthHealthy, SeverityCritical},
+		{"degraded to unhealthy", TimelineEvent{EventType: EventTypeUpdate, HealthState: HealthUnhealthy}, HealthDegraded, SeverityCritical},
+		{"healthy to degraded", TimelineEvent{EventType: EventTypeUpdate, HealthState: HealthDegraded}, HealthHealthy, SeverityWarning},
+		{"still unhealthy", TimelineEvent{EventType: EventTypeUpdate, HealthState: HealthUnhealthy}, HealthUnhealthy, SeverityWarning},
+		{"unhealthy without history", TimelineEvent{EventType: EventTypeUpdate, HealthState: HealthUnhealthy}, "", SeverityWarning},
+		{"recovery", TimelineEvent{EventType: EventTypeUpdate, HealthState: HealthHealthy}, HealthUnhealthy, SeverityInfo},
+		{"degraded pod starting", TimelineEvent{EventType: EventTypeUpdate, HealthState: HealthDegraded}, "", SeverityInfo},
+		{"unknown previous", TimelineEvent{EventType: EventTypeUpdate, HealthState: HealthDegraded}, HealthUnknown, SeverityInfo},
+		{"delete of unhealthy resource", TimelineEvent{EventType: EventTypeDelete, HealthState: HealthUnhealthy}, HealthHealthy, SeverityInfo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComputeSeverity(&tt.event, tt.previous); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestConstructorsSetSeverity(t *testing.T) {
+	event := NewLifecycleEvent("APIService", "", "v1beta1.metrics.k8s.io", "", EventTypeWarning, "APIServiceUnavailable", "", HealthUnhealthy)
+	if event.Severity != SeverityWarning {
+		t.Errorf("expected warning, got %s", event.Severity)
+	}
+	event = NewInformerEvent("Pod", "default", "pod-1", "", EventTypeAdd, HealthHealthy, nil, nil, nil, nil)
+	if event.Severity != SeverityInfo {
+		t.Errorf("expected info, got %s", event.Severity)
+	}
+}
+
+func TestCompiledFilter_IncludeSeverities(t *testing.T) {
+	cf, err := CompileFilter(&FilterPreset{Name: "test", IncludeSeverities: []Severity{SeverityWarning, SeverityCritical}})
+	if err != nil {
+		t.Fatalf("CompileFilter failed: %v", err)
+	}
+
+	tests := []struct {
+		severity Severity
+		expected bool
+	}{
+		{SeverityInfo, false},
+		{SeverityWarning, true},
+		{SeverityCritical, true},
+	}
+	for _, tt := range tests {
+		if got := cf.Matches(&TimelineEvent{Kind: "Deployment", Severity: tt.severity}); got != tt.expected {
+			t.Errorf("Severity=%s: expected %v, got %v", tt.severity, tt.expected, got)
+		}
+	}
+}
+
+func severityTestEvents() []TimelineEvent {
+	return []TimelineEvent{
+		{ID: "sev-1", Timestamp: time.Now(), Kind: "Deployment", Namespace: "default", Name: "ok", EventType: EventTypeAdd, Source: SourceInformer},
+		{ID: "sev-2", Timestamp: time.Now(), Kind: "Deployment", Namespace: "default", Name: "down", EventType: EventTypeUpdate, Source: SourceInformer, HealthState: HealthUnhealthy, Severity: SeverityCritical},
+		// No severity set: computed on append (Warning event -> warning)
+		{ID: "sev-3", Timestamp: time.Now(), Kind: "Deployment", Namespace: "default", Name: "slow", EventType: EventTypeWarning, Source: SourceK8sEvent, Reason: "ProgressDeadlineExceeded"},
+	}
+}
+
+func TestMemoryStore_Query_Severities(t *testing.T) {
+	store := NewMemoryStore(100)
+	ctx := context.Background()
+	_ = store.AppendBatch(ctx, severityTestEvents())
+
+	result, err := store.Query(ctx, QueryOptions{Limit: 10, IncludeManaged: true, IncludeK8sEvents: true, Severities: []Severity{SeverityWarning, SeverityCritical}})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 warning/critical events, got %d", len(result))
+	}
+	for _, e := range result {
+		if e.Severity == SeverityInfo {
+			t.Errorf("Unexpected info event %s", e.ID)
+		}
+	}
+}
+
+func TestSQLiteStore_Query_Severities(t *testing.T) {
+	store, cleanup := createTestSQLiteStore(t)
+	defer cleanup()
+	ctx := context.Background()
+	_ = store.AppendBatch(ctx, severityTestEvents())
+
+	result, err := store.Query(ctx, QueryOptions{Limit: 10, IncludeManaged: true, IncludeK8sEvents: true, Severities: []Severity{SeverityCritical}})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(result) != 1 || result[0].ID != "sev-2" {
+		t.Fatalf("Expected only sev-2, got %+v", result)
+	}
+
+	event, err := store.GetEvent(ctx, "sev-3")
+	if err != nil || event == nil {
+		t.Fatalf("GetEvent failed: %v", err)
+	}
+	if event.Severity != SeverityWarning {
+		t.Errorf("Expected computed warning severity, got %s", event.Severity)
+	}
+}
+
+func TestSQLiteStore_MigratesSeverityColumn(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "legacy.db")
+
+	// Create a database with the schema from before severity existed
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	_, err = db.Exec(`CREATE TABLE events (
+		id TEXT PRIMARY KEY, timestamp TEXT NOT NULL, source TEXT NOT NULL, kind TEXT NOT NULL,
+		namespace TEXT, name TEXT NOT NULL, uid TEXT, event_type TEXT NOT NULL, reason TEXT,
+		message TEXT, diff_json TEXT, health_state TEXT, owner_kind TEXT, owner_name TEXT,
+		labels_json TEXT, count INTEGER DEFAULT 0, correlation_id TEXT, created_at TEXT DEFAULT (datetime('now')));
+		INSERT INTO events (id, timestamp, source, kind, namespace, name, event_type, reason, health_state)
+		VALUES ('old-1', '2025-01-01T00:00:00Z', 'k8s_event', 'Pod', 'default', 'pod-1', 'Warning', 'OOMKilling', ''),
+		       ('old-2', '2025-01-01T00:00:01Z', 'k8s_event', 'Pod', 'default', 'pod-1', 'Warning', 'FailedMount', '');`)
+	db.Close()
+	if err != nil {
+		t.Fatalf("Failed to create legacy schema: %v", err)
+	}
+
+	store, err := NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open legacy database: %v", err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	result, err := store.Query(ctx, QueryOptions{Limit: 10, IncludeManaged: true, IncludeK8sEvents: true, Severities: []Severity{SeverityCritical}})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(result) != 1 || result[0].ID != "old-1" {
+		t.Errorf("Expected backfilled critical event old-1, got %+v", result)
+	}
+}
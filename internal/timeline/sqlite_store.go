@@ -24,7 +24,9 @@ type SQLiteStore struct {
 	seenMu        sync.RWMutex
 	filterCache   map[string]*CompiledFilter
 	cacheMu       sync.RWMutex
+	filterVersion uint64 // presetsVersion the cache was built against
 	path          string
+	contextName   string // Kubeconfig context new events are tagged with and queries default to
 }
 
 // NewSQLiteStore creates a new SQLite-backed event store
@@ -102,6 +104,8 @@ func (s *SQLiteStore) initSchema() error {
 		labels_json TEXT,
 		count INTEGER DEFAULT 0,
 		correlation_id TEXT,
+		severity TEXT,
+		context TEXT,
 		created_at TEXT DEFAULT (datetime('now'))
 	);
 
@@ -119,7 +123,60 @@ func (s *SQLiteStore) initSchema() error {
 	);
 	`
 
-	_, err := s.db.Exec(schema)
+	if _, err := s.db.Exec(schema); err != nil {
+		return err
+	}
+	return s.migrateSchema()
+}
+
+// migrateSchema adds columns introduced after the initial schema to existing databases
+func (s *SQLiteStore) migrateSchema() error {
+	if err := s.migrateSeverity(); err != nil {
+		return err
+	}
+
+	// Events recorded before contexts were tracked keep an empty context: which
+	// cluster they came from is unknown, so they're only returned for AllContexts
+	if _, err := s.addColumnIfMissing("context"); err != nil {
+		return err
+	}
+	_, err := s.db.Exec("CREATE INDEX IF NOT EXISTS idx_events_context_timestamp ON events(context, timestamp DESC)")
+	return err
+}
+
+// addColumnIfMissing adds a TEXT column to the events table. Returns true if it was added.
+func (s *SQLiteStore) addColumnIfMissing(column string) (bool, error) {
+	var exists int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('events') WHERE name = ?", column).Scan(&exists); err != nil {
+		return false, err
+	}
+	if exists > 0 {
+		return false, nil
+	}
+	if _, err := s.db.Exec("ALTER TABLE events ADD COLUMN " + column + " TEXT"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *SQLiteStore) migrateSeverity() error {
+	added, err := s.addColumnIfMissing("severity")
+	if err != nil || !added {
+		return err
+	}
+
+	// Backfill with ComputeSeverity's rules; health transitions weren't recorded, so they can't be applied
+	reasons := make([]string, 0, len(criticalReasons))
+	args := make([]any, 0, len(criticalReasons))
+	for reason := range criticalReasons {
+		reasons = append(reasons, "?")
+		args = append(args, reason)
+	}
+	_, err = s.db.Exec(`UPDATE events SET severity = CASE
+		WHEN reason IN (`+strings.Join(reasons, ",")+`) THEN 'critical'
+		WHEN event_type = 'Warning' THEN 'warning'
+		WHEN event_type != 'delete' AND health_state = 'unhealthy' THEN 'warning'
+		ELSE 'info' END`, args...)
 	return err
 }
 
@@ -165,8 +222,8 @@ func (s *SQLiteStore) AppendBatch(ctx context.Context, events []TimelineEvent) e
 		INSERT OR IGNORE INTO events (
 			id, timestamp, source, kind, namespace, name, uid, event_type,
 			reason, message, diff_json, health_state, owner_kind, owner_name,
-			labels_json, count, correlation_id
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			labels_json, count, correlation_id, severity, context
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`)
 	if err != nil {
 		return fmt.Errorf("failed to prepare statement: %w", err)
@@ -198,6 +255,12 @@ func (s *SQLiteStore) AppendBatch(ctx context.Context, events []TimelineEvent) e
 			ownerKind = event.Owner.Kind
 			ownerName = event.Owner.Name
 		}
+		if event.Severity == "" {
+			event.Severity = ComputeSeverity(&event, "")
+		}
+		if event.Context == "" {
+			event.Context = s.contextName
+		}
 
 		_, err = stmt.ExecContext(ctx,
 			event.ID,
@@ -217,6 +280,8 @@ func (s *SQLiteStore) AppendBatch(ctx context.Context, events []TimelineEvent) e
 			string(labelsJSON),
 			event.Count,
 			event.CorrelationID,
+			string(event.Severity),
+			event.Context,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to insert event: %w", err)
@@ -232,10 +297,15 @@ func (s *SQLiteStore) Query(ctx context.Context, opts QueryOptions) ([]TimelineE
 	query := strings.Builder{}
 	query.WriteString("SELECT id, timestamp, source, kind, namespace, name, uid, event_type, ")
 	query.WriteString("reason, message, diff_json, health_state, owner_kind, owner_name, ")
-	query.WriteString("labels_json, count, correlation_id FROM events WHERE 1=1")
+	query.WriteString("labels_json, count, correlation_id, severity, context FROM events WHERE 1=1")
 
 	var args []any
 
+	if contextName := resolveContext(opts.Context, s.contextName); contextName != "" {
+		query.WriteString(" AND context = ?")
+		args = append(args, contextName)
+	}
+
 	// Apply filters
 	if len(opts.Namespaces) > 0 {
 		query.WriteString(" AND namespace IN (")
@@ -261,6 +331,11 @@ func (s *SQLiteStore) Query(ctx context.Context, opts QueryOptions) ([]TimelineE
 		query.WriteString(")")
 	}
 
+	if opts.Name != "" {
+		query.WriteString(" AND name = ?")
+		args = append(args, opts.Name)
+	}
+
 	if !opts.Since.IsZero() {
 		query.WriteString(" AND timestamp >= ?")
 		args = append(args, opts.Since.Format(time.RFC3339Nano))
@@ -283,8 +358,17 @@ func (s *SQLiteStore) Query(ctx context.Context, opts QueryOptions) ([]TimelineE
 		query.WriteString(")")
 	}
 
-	// Order by timestamp descending
-	query.WriteString(" ORDER BY timestamp DESC")
+	if len(opts.Severities) > 0 {
+		query.WriteString(" AND severity IN (")
+		for i, sev := range opts.Severities {
+			if i > 0 {
+				query.WriteString(",")
+			}
+			query.WriteString("?")
+			args = append(args, string(sev))
+		}
+		query.WriteString(")")
+	}
 
 	// Apply limit
 	limit := opts.Limit
@@ -294,18 +378,15 @@ func (s *SQLiteStore) Query(ctx context.Context, opts QueryOptions) ([]TimelineE
 	if limit > 10000 {
 		limit = 10000
 	}
-	query.WriteString(fmt.Sprintf(" LIMIT %d", limit))
-
-	if opts.Offset > 0 {
-		query.WriteString(fmt.Sprintf(" OFFSET %d", opts.Offset))
-	}
 
-	// Execute query
-	rows, err := s.db.QueryContext(ctx, query.String(), args...)
-	if err != nil {
-		return nil, fmt.Errorf("query failed: %w", err)
+	var cursorTS, cursorID string
+	if opts.Cursor != "" {
+		ts, id, err := parseCursor(opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursorTS, cursorID = ts.Format(time.RFC3339Nano), id
 	}
-	defer rows.Close()
 
 	// Get compiled filter for post-filtering
 	var cf *CompiledFilter
@@ -318,12 +399,98 @@ func (s *SQLiteStore) Query(ctx context.Context, opts QueryOptions) ([]TimelineE
 		}
 	}
 
-	var events []TimelineEvent
-	for rows.Next() {
-		event, err := s.scanEvent(rows)
+	// Push the preset's list filters into SQL so selective presets don't scan
+	// every row; name patterns are still matched after scanning
+	if cf != nil {
+		cf.writeSQLConditions(&query, &args)
+	}
+
+	// Post-filters can leave a page short, so keep reading from the last
+	// scanned row until the page is full or the rows run out
+	events := []TimelineEvent{}
+	offset := opts.Offset
+	for {
+		page := query.String()
+		pageArgs := append([]any{}, args...)
+		if cursorID != "" {
+			page += " AND (timestamp < ? OR (timestamp = ? AND id < ?))"
+			pageArgs = append(pageArgs, cursorTS, cursorTS, cursorID)
+		}
+		// Order by timestamp descending, with the ID as a tiebreaker for stable cursors
+		page += fmt.Sprintf(" ORDER BY timestamp DESC, id DESC LIMIT %d", limit)
+		if offset > 0 {
+			page += fmt.Sprintf(" OFFSET %d", offset)
+		}
+
+		var scanned int
+		var last TimelineEvent
+		var err error
+		events, scanned, last, err = s.queryPage(ctx, page, pageArgs, opts, cf, events, limit)
 		if err != nil {
 			return nil, err
 		}
+		if scanned < limit || len(events) >= limit {
+			return events, nil
+		}
+		cursorTS, cursorID, offset = last.Timestamp.Format(time.RFC3339Nano), last.ID, 0
+	}
+}
+
+// writeSQLConditions appends the filter's kind, event type, and severity
+// lists as SQL conditions. Matches still checks them, so this only narrows
+// the rows scanned.
+func (cf *CompiledFilter) writeSQLConditions(query *strings.Builder, args *[]any) {
+	in := func(column string, not bool, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		sort.Strings(values)
+		query.WriteString(" AND " + column)
+		if not {
+			query.WriteString(" NOT")
+		}
+		query.WriteString(" IN (" + strings.TrimSuffix(strings.Repeat("?,", len(values)), ",") + ")")
+		for _, v := range values {
+			*args = append(*args, v)
+		}
+	}
+	in("kind", false, filterMapKeys(cf.includeKindsMap))
+	in("kind", true, filterMapKeys(cf.excludeKindsMap))
+	in("event_type", false, filterMapKeys(cf.includeEventTypes))
+	in("event_type", true, filterMapKeys(cf.excludeOperations))
+	in("severity", false, filterMapKeys(cf.includeSeverities))
+}
+
+func filterMapKeys[K ~string](m map[K]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k, ok := range m {
+		if ok {
+			keys = append(keys, string(k))
+		}
+	}
+	return keys
+}
+
+// queryPage runs one page of a query, appending events that pass the
+// post-filters until there are limit of them. It returns the number of rows
+// scanned and the last one.
+func (s *SQLiteStore) queryPage(ctx context.Context, query string, args []any, opts QueryOptions, cf *CompiledFilter, events []TimelineEvent, limit int) ([]TimelineEvent, int, TimelineEvent, error) {
+	// Execute query
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, TimelineEvent{}, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	scanned := 0
+	var last TimelineEvent
+	for len(events) < limit && rows.Next() {
+		event, err := s.scanEvent(rows)
+		if err != nil {
+			return nil, 0, TimelineEvent{}, err
+		}
+		scanned++
+		last = event
 
 		// Apply post-filters (for complex filters not handled in SQL)
 		if cf != nil && !cf.Matches(&event) {
@@ -349,7 +516,7 @@ func (s *SQLiteStore) Query(ctx context.Context, opts QueryOptions) ([]TimelineE
 		events = append(events, event)
 	}
 
-	return events, rows.Err()
+	return events, scanned, last, rows.Err()
 }
 
 // QueryGrouped retrieves events grouped according to the specified mode
@@ -378,6 +545,7 @@ func (s *SQLiteStore) QueryGrouped(ctx context.Context, opts QueryOptions) (*Tim
 				TotalEvents: len(events),
 				QueryTimeMs: time.Since(startTime).Milliseconds(),
 				HasMore:     len(events) == opts.Limit,
+				NextCursor:  NextCursor(events, opts.Limit),
 			},
 		}, nil
 	}
@@ -409,7 +577,7 @@ func (s *SQLiteStore) QueryGrouped(ctx context.Context, opts QueryOptions) (*Tim
 func (s *SQLiteStore) GetEvent(ctx context.Context, id string) (*TimelineEvent, error) {
 	query := `SELECT id, timestamp, source, kind, namespace, name, uid, event_type,
 		reason, message, diff_json, health_state, owner_kind, owner_name,
-		labels_json, count, correlation_id FROM events WHERE id = ?`
+		labels_json, count, correlation_id, severity, context FROM events WHERE id = ?`
 
 	row := s.db.QueryRowContext(ctx, query, id)
 	event, err := s.scanEventRow(row)
@@ -430,11 +598,16 @@ func (s *SQLiteStore) GetChangesForOwner(ctx context.Context, ownerKind, ownerNa
 
 	query := `SELECT id, timestamp, source, kind, namespace, name, uid, event_type,
 		reason, message, diff_json, health_state, owner_kind, owner_name,
-		labels_json, count, correlation_id FROM events
+		labels_json, count, correlation_id, severity, context FROM events
 		WHERE owner_kind = ? AND owner_name = ? AND namespace = ?`
 
 	args := []any{ownerKind, ownerName, ownerNamespace}
 
+	if s.contextName != "" {
+		query += " AND context = ?"
+		args = append(args, s.contextName)
+	}
+
 	if !since.IsZero() {
 		query += " AND timestamp >= ?"
 		args = append(args, since.Format(time.RFC3339Nano))
@@ -543,7 +716,7 @@ func (s *SQLiteStore) scanEvent(rows *sql.Rows) (TimelineEvent, error) {
 	var timestamp string
 	var source, eventType, healthState string
 	var uid, reason, message, diffJSON, labelsJSON sql.NullString
-	var ownerKind, ownerName, correlationID sql.NullString
+	var ownerKind, ownerName, correlationID, severity, contextName sql.NullString
 
 	err := rows.Scan(
 		&event.ID,
@@ -563,6 +736,8 @@ func (s *SQLiteStore) scanEvent(rows *sql.Rows) (TimelineEvent, error) {
 		&labelsJSON,
 		&event.Count,
 		&correlationID,
+		&severity,
+		&contextName,
 	)
 	if err != nil {
 		return event, err
@@ -585,6 +760,14 @@ func (s *SQLiteStore) scanEvent(rows *sql.Rows) (TimelineEvent, error) {
 	if correlationID.Valid {
 		event.CorrelationID = correlationID.String
 	}
+	if contextName.Valid {
+		event.Context = contextName.String
+	}
+	if severity.Valid && severity.String != "" {
+		event.Severity = Severity(severity.String)
+	} else {
+		event.Severity = ComputeSeverity(&event, "")
+	}
 
 	if diffJSON.Valid && diffJSON.String != "" {
 		var diff DiffInfo
@@ -613,7 +796,7 @@ func (s *SQLiteStore) scanEventRow(row *sql.Row) (TimelineEvent, error) {
 	var timestamp string
 	var source, eventType, healthState string
 	var uid, reason, message, diffJSON, labelsJSON sql.NullString
-	var ownerKind, ownerName, correlationID sql.NullString
+	var ownerKind, ownerName, correlationID, severity, contextName sql.NullString
 
 	err := row.Scan(
 		&event.ID,
@@ -633,6 +816,8 @@ func (s *SQLiteStore) scanEventRow(row *sql.Row) (TimelineEvent, error) {
 		&labelsJSON,
 		&event.Count,
 		&correlationID,
+		&severity,
+		&contextName,
 	)
 	if err != nil {
 		return event, err
@@ -655,6 +840,14 @@ func (s *SQLiteStore) scanEventRow(row *sql.Row) (TimelineEvent, error) {
 	if correlationID.Valid {
 		event.CorrelationID = correlationID.String
 	}
+	if contextName.Valid {
+		event.Context = contextName.String
+	}
+	if severity.Valid && severity.String != "" {
+		event.Severity = Severity(severity.String)
+	} else {
+		event.Severity = ComputeSeverity(&event, "")
+	}
 
 	if diffJSON.Valid && diffJSON.String != "" {
 		var diff DiffInfo
@@ -679,15 +872,15 @@ func (s *SQLiteStore) scanEventRow(row *sql.Row) (TimelineEvent, error) {
 
 // getOrCompileFilter returns a cached compiled filter or compiles a new one
 func (s *SQLiteStore) getOrCompileFilter(presetName string) (*CompiledFilter, error) {
+	version := presetsVersion()
 	s.cacheMu.RLock()
-	if cf, ok := s.filterCache[presetName]; ok {
+	if cf, ok := s.filterCache[presetName]; ok && s.filterVersion == version {
 		s.cacheMu.RUnlock()
 		return cf, nil
 	}
 	s.cacheMu.RUnlock()
 
-	presets := DefaultFilterPresets()
-	preset, ok := presets[presetName]
+	preset, ok := LookupFilterPreset(presetName)
 	if !ok {
 		return nil, nil
 	}
@@ -698,6 +891,11 @@ func (s *SQLiteStore) getOrCompileFilter(presetName string) (*CompiledFilter, er
 	}
 
 	s.cacheMu.Lock()
+	if s.filterVersion != version {
+		// Custom presets changed since the cache was built
+		s.filterCache = make(map[string]*CompiledFilter)
+		s.filterVersion = version
+	}
 	s.filterCache[presetName] = cf
 	s.cacheMu.Unlock()
 
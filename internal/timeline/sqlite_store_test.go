@@ -455,3 +455,55 @@ func TestSQLiteStore_LabelsStorage(t *testing.T) {
 		t.Errorf("Expected GetAppLabel()='myapp', got '%s'", result.GetAppLabel())
 	}
 }
+
+func TestSQLiteStore_PerContextHistory(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "timeline.db")
+	ctx := context.Background()
+
+	// Record into cluster-a, then "switch" to cluster-b on the same database
+	storeA, err := NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create SQLite store: %v", err)
+	}
+	storeA.contextName = "cluster-a"
+	_ = storeA.Append(ctx, TimelineEvent{ID: "a-1", Timestamp: time.Now(), Kind: "Deployment", Namespace: "default", Name: "api", EventType: EventTypeAdd, Source: SourceInformer})
+	storeA.Close()
+
+	storeB, err := NewSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen SQLite store: %v", err)
+	}
+	defer storeB.Close()
+	storeB.contextName = "cluster-b"
+	_ = storeB.Append(ctx, TimelineEvent{ID: "b-1", Timestamp: time.Now(), Kind: "Deployment", Namespace: "default", Name: "api", EventType: EventTypeAdd, Source: SourceInformer})
+
+	tests := []struct {
+		context  string
+		expected []string
+	}{
+		{"", []string{"b-1"}}, // Defaults to the current context
+		{"cluster-a", []string{"a-1"}},
+		{AllContexts, []string{"b-1", "a-1"}},
+		{"cluster-c", nil},
+	}
+	for _, tt := range tests {
+		result, err := storeB.Query(ctx, QueryOptions{Limit: 10, Context: tt.context})
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		if len(result) != len(tt.expected) {
+			t.Errorf("Context=%q: expected %v, got %d events", tt.context, tt.expected, len(result))
+			continue
+		}
+		for i, id := range tt.expected {
+			if result[i].ID != id {
+				t.Errorf("Context=%q: expected %v at %d, got %s", tt.context, id, i, result[i].ID)
+			}
+		}
+	}
+
+	event, _ := storeB.GetEvent(ctx, "a-1")
+	if event == nil || event.Context != "cluster-a" {
+		t.Errorf("Expected a-1 to be tagged with cluster-a, got %+v", event)
+	}
+}
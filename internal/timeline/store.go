@@ -2,7 +2,10 @@ package timeline
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"regexp"
+	"strings"
 	"time"
 )
 
@@ -48,9 +51,15 @@ type QueryOptions struct {
 	// Filters
 	Namespaces []string     // Filter by namespaces (empty = all)
 	Kinds     []string      // Filter by resource kinds (empty = all)
+	Name      string        // Filter by resource name (empty = all)
 	Since     time.Time     // Filter events after this time
 	Until     time.Time     // Filter events before this time
 	Sources   []EventSource // Filter by event source (empty = all)
+	Severities []Severity   // Filter by severity (empty = all)
+
+	// Context scopes the query to one cluster's history: "" = the store's current
+	// context, AllContexts = every context, anything else = that context
+	Context string
 
 	// Filter preset (overrides individual filters if set)
 	FilterPreset string
@@ -58,7 +67,7 @@ type QueryOptions struct {
 	// Pagination
 	Limit  int    // Max results (default 200, max 1000)
 	Offset int    // Skip first N results
-	Cursor string // Cursor for keyset pagination (more efficient than offset)
+	Cursor string // Cursor for keyset pagination: events older than the one it was made from (see EventCursor)
 
 	// Grouping
 	GroupBy GroupingMode // How to group results
@@ -78,6 +87,47 @@ func DefaultQueryOptions() QueryOptions {
 	}
 }
 
+// ErrInvalidCursor is returned for cursors not made by EventCursor
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EventCursor returns an opaque cursor positioned at event. Queries return
+// events newest first, ordered by timestamp then ID, so paging with the last
+// event's cursor neither skips nor repeats events that share a timestamp.
+func EventCursor(event TimelineEvent) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(event.Timestamp.Format(time.RFC3339Nano) + "|" + event.ID))
+}
+
+// NextCursor returns the cursor for the page after events, or "" when the
+// page wasn't full and there's nothing more to fetch
+func NextCursor(events []TimelineEvent, limit int) string {
+	if limit <= 0 || len(events) < limit {
+		return ""
+	}
+	return EventCursor(events[len(events)-1])
+}
+
+// parseCursor decodes a cursor into the timestamp and ID it was made from
+func parseCursor(cursor string) (time.Time, string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	ts, id, ok := strings.Cut(string(data), "|")
+	if !ok || id == "" {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	return t, id, nil
+}
+
+// olderThan reports whether event sorts after the cursor position
+func olderThan(event *TimelineEvent, ts time.Time, id string) bool {
+	return event.Timestamp.Before(ts) || (event.Timestamp.Equal(ts) && event.ID < id)
+}
+
 // StoreStats contains statistics about the event store
 type StoreStats struct {
 	TotalEvents   int64     `json:"totalEvents"`
@@ -94,6 +144,7 @@ type CompiledFilter struct {
 	includeKindsMap   map[string]bool
 	excludePatterns   []*regexp.Regexp
 	includeEventTypes map[EventType]bool
+	includeSeverities map[Severity]bool
 	excludeOperations map[EventType]bool
 }
 
@@ -108,6 +159,7 @@ func CompileFilter(preset *FilterPreset) (*CompiledFilter, error) {
 		excludeKindsMap:   make(map[string]bool),
 		includeKindsMap:   make(map[string]bool),
 		includeEventTypes: make(map[EventType]bool),
+		includeSeverities: make(map[Severity]bool),
 		excludeOperations: make(map[EventType]bool),
 	}
 
@@ -129,6 +181,9 @@ func CompileFilter(preset *FilterPreset) (*CompiledFilter, error) {
 	for _, t := range preset.IncludeEventTypes {
 		cf.includeEventTypes[t] = true
 	}
+	for _, s := range preset.IncludeSeverities {
+		cf.includeSeverities[s] = true
+	}
 	for _, t := range preset.ExcludeOperations {
 		cf.excludeOperations[t] = true
 	}
@@ -164,6 +219,11 @@ func (cf *CompiledFilter) Matches(event *TimelineEvent) bool {
 		return false
 	}
 
+	// Check include severities (whitelist)
+	if len(cf.includeSeverities) > 0 && !cf.includeSeverities[event.Severity] {
+		return false
+	}
+
 	// Check exclude operations
 	if cf.excludeOperations[event.EventType] {
 		return false
@@ -173,6 +233,20 @@ func (cf *CompiledFilter) Matches(event *TimelineEvent) bool {
 	return true
 }
 
+// AllContexts is the QueryOptions.Context value that returns events from every context
+const AllContexts = "*"
+
+// resolveContext returns the context a query is scoped to ("" = no scoping)
+func resolveContext(requested, current string) string {
+	switch requested {
+	case "":
+		return current
+	case AllContexts:
+		return ""
+	}
+	return requested
+}
+
 // ResourceKey generates a unique key for a resource
 func ResourceKey(kind, namespace, name string) string {
 	return kind + "/" + namespace + "/" + name
@@ -4,6 +4,7 @@
 package timeline
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -74,6 +75,9 @@ type TimelineEvent struct {
 	Name      string `json:"name"`
 	UID       string `json:"uid,omitempty"`
 
+	// Kubeconfig context (cluster) the event was recorded in
+	Context string `json:"context,omitempty"`
+
 	// Resource metadata - when the resource was actually created in K8s
 	// This is different from Timestamp which is when we observed the event
 	CreatedAt *time.Time `json:"createdAt,omitempty"`
@@ -86,6 +90,7 @@ type TimelineEvent struct {
 	// Rich context (computed at write time)
 	Diff        *DiffInfo         `json:"diff,omitempty"`
 	HealthState HealthState       `json:"healthState,omitempty"`
+	Severity    Severity          `json:"severity,omitempty"` // info, warning, critical
 	Owner       *OwnerInfo        `json:"owner,omitempty"`
 	Labels      map[string]string `json:"labels,omitempty"` // For app-label grouping
 
@@ -96,6 +101,34 @@ type TimelineEvent struct {
 	CorrelationID string `json:"correlationId,omitempty"`
 }
 
+// MarshalJSON adds epoch-second twins of the timestamps, so every payload
+// carrying timeline events (API, SSE, agents) has both formats
+func (e TimelineEvent) MarshalJSON() ([]byte, error) {
+	type event TimelineEvent // Without the method, to avoid recursion
+	out := struct {
+		event
+		TimestampUnix int64  `json:"timestampUnix"`
+		CreatedAtUnix *int64 `json:"createdAtUnix,omitempty"`
+	}{event: event(e), TimestampUnix: e.Timestamp.Unix()}
+	if e.CreatedAt != nil {
+		createdAt := e.CreatedAt.Unix()
+		out.CreatedAtUnix = &createdAt
+	}
+	return json.Marshal(out)
+}
+
+// InZone renders events' timestamps in loc. Events are changed in place;
+// store queries return copies, so this doesn't touch stored events.
+func InZone(events []TimelineEvent, loc *time.Location) {
+	for i := range events {
+		events[i].Timestamp = events[i].Timestamp.In(loc)
+		if events[i].CreatedAt != nil {
+			createdAt := events[i].CreatedAt.In(loc)
+			events[i].CreatedAt = &createdAt
+		}
+	}
+}
+
 // OwnerInfo represents the owner/controller of a resource
 type OwnerInfo struct {
 	Kind string `json:"kind"`
@@ -183,14 +216,25 @@ type FilterPreset struct {
 	ExcludeNamePatterns []string    `json:"excludeNamePatterns,omitempty"`
 	ExcludeOperations   []EventType `json:"excludeOperations,omitempty"`
 	IncludeEventTypes   []EventType `json:"includeEventTypes,omitempty"`
+	IncludeSeverities   []Severity  `json:"includeSeverities,omitempty"`
 	IncludeManaged      bool        `json:"includeManaged"`
+	Builtin             bool        `json:"builtin"` // Built-in presets can't be changed or deleted
 }
 
+// Built-in filter preset names
+const (
+	PresetDefault      = "default"
+	PresetAll          = "all"
+	PresetWarningsOnly = "warnings-only"
+	PresetCritical     = "critical"
+	PresetWorkloads    = "workloads"
+)
+
 // DefaultFilterPresets returns the built-in filter presets
 func DefaultFilterPresets() map[string]FilterPreset {
-	return map[string]FilterPreset{
-		"default": {
-			Name:         "default",
+	presets := map[string]FilterPreset{
+		PresetDefault: {
+			Name:         PresetDefault,
 			ExcludeKinds: []string{"Lease", "Endpoints", "EndpointSlice"},
 			ExcludeNamePatterns: []string{
 				"-lock$", "-lease$", "-leader-election$", "-heartbeat$",
@@ -200,17 +244,22 @@ func DefaultFilterPresets() map[string]FilterPreset {
 			},
 			IncludeManaged: false,
 		},
-		"all": {
-			Name:           "all",
+		PresetAll: {
+			Name:           PresetAll,
 			IncludeManaged: true,
 		},
-		"warnings-only": {
-			Name:              "warnings-only",
+		PresetWarningsOnly: {
+			Name:              PresetWarningsOnly,
 			IncludeEventTypes: []EventType{EventTypeWarning},
 			IncludeManaged:    true,
 		},
-		"workloads": {
-			Name: "workloads",
+		PresetCritical: {
+			Name:              PresetCritical,
+			IncludeSeverities: []Severity{SeverityCritical},
+			IncludeManaged:    true,
+		},
+		PresetWorkloads: {
+			Name: PresetWorkloads,
 			IncludeKinds: []string{
 				"Deployment", "DaemonSet", "StatefulSet", "ReplicaSet",
 				"Job", "CronJob", "Pod",
@@ -218,4 +267,9 @@ func DefaultFilterPresets() map[string]FilterPreset {
 			IncludeManaged: true,
 		},
 	}
+	for name, p := range presets {
+		p.Builtin = true
+		presets[name] = p
+	}
+	return presets
 }
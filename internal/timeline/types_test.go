@@ -0,0 +1,39 @@
+package timeline
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimelineEventJSONTimestamps(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	created := ts.Add(-time.Hour)
+	events := []TimelineEvent{{ID: "a", Timestamp: ts, CreatedAt: &created, Kind: "Pod"}}
+
+	InZone(events, time.FixedZone("CET", 3600))
+	if created.Location() != time.UTC {
+		t.Error("InZone shouldn't change the CreatedAt the event pointed to")
+	}
+
+	data, err := json.Marshal(events[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["timestamp"] != "2026-03-01T13:00:00+01:00" || got["timestampUnix"] != float64(ts.Unix()) {
+		t.Errorf("unexpected timestamp fields: %v / %v", got["timestamp"], got["timestampUnix"])
+	}
+	if got["createdAtUnix"] != float64(created.Unix()) || got["kind"] != "Pod" {
+		t.Errorf("unexpected fields: %s", data)
+	}
+
+	// Round-trips, e.g. events sent by agents
+	var back TimelineEvent
+	if err := json.Unmarshal(data, &back); err != nil || !back.Timestamp.Equal(ts) || back.ID != "a" {
+		t.Errorf("round trip failed: %+v, %v", back, err)
+	}
+}
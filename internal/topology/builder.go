@@ -1554,6 +1554,7 @@ func (b *Builder) buildResourcesTopology(opts BuildOptions) (*Topology, error) {
 	}
 
 	// 11. Add HPA nodes
+	hpaIDs := make(map[string]string) // ns/name -> hpaID (for KEDA ScaledObject edges)
 	if hpaLister := b.cache.HorizontalPodAutoscalers(); hpaLister != nil {
 		hpas, hpaErr := hpaLister.List(labels.Everything())
 		if hpaErr != nil {
@@ -1566,6 +1567,7 @@ func (b *Builder) buildResourcesTopology(opts BuildOptions) (*Topology, error) {
 			}
 
 			hpaID := fmt.Sprintf("horizontalpodautoscaler/%s/%s", hpa.Namespace, hpa.Name)
+			hpaIDs[hpa.Namespace+"/"+hpa.Name] = hpaID
 
 			nodes = append(nodes, Node{
 				ID:     hpaID,
@@ -1611,6 +1613,109 @@ func (b *Builder) buildResourcesTopology(opts BuildOptions) (*Topology, error) {
 		warnings = append(warnings, "HorizontalPodAutoscalers not available (RBAC not granted)")
 	}
 
+	// 11b. Add KEDA ScaledObject/ScaledJob nodes (CRD - fetched via dynamic cache)
+	for _, kedaKind := range []NodeKind{KindScaledObject, KindScaledJob} {
+		if resourceDiscovery == nil || dynamicCache == nil {
+			break
+		}
+		kedaGVR, ok := resourceDiscovery.GetGVRWithGroup(string(kedaKind), k8s.KEDAGroup)
+		if !ok {
+			continue
+		}
+		scalers, err := dynamicCache.List(kedaGVR, opts.NamespaceFilter())
+		if err != nil {
+			log.Printf("WARNING [topology] Failed to list %ss: %v", kedaKind, err)
+			warnings = append(warnings, fmt.Sprintf("Failed to list %ss: %v", kedaKind, err))
+			continue
+		}
+		for _, resource := range scalers {
+			ns := resource.GetNamespace()
+			if !opts.MatchesNamespaceFilter(ns) {
+				continue
+			}
+			scaler := k8s.ParseKEDAScaler(resource)
+			scalerID := fmt.Sprintf("%s/%s/%s", strings.ToLower(string(kedaKind)), ns, scaler.Name)
+
+			triggerTypes := make([]string, 0, len(scaler.Triggers))
+			for _, t := range scaler.Triggers {
+				triggerTypes = append(triggerTypes, t.Type)
+			}
+
+			status := StatusHealthy
+			if !scaler.Ready {
+				status = StatusUnhealthy
+			} else if scaler.Fallback {
+				status = StatusDegraded
+			}
+
+			nodes = append(nodes, Node{
+				ID:     scalerID,
+				Kind:   kedaKind,
+				Name:   scaler.Name,
+				Status: status,
+				Data: map[string]any{
+					"namespace":   ns,
+					"minReplicas": scaler.MinReplicas,
+					"maxReplicas": scaler.MaxReplicas,
+					"active":      scaler.Active,
+					"paused":      scaler.Paused,
+					"triggers":    triggerTypes,
+					"labels":      resource.GetLabels(),
+				},
+			})
+
+			if kedaKind == KindScaledJob {
+				// ScaledJob owns the Jobs it spawns
+				for _, job := range jobs {
+					if job.Namespace != ns {
+						continue
+					}
+					for _, ref := range job.OwnerReferences {
+						if ref.Kind == string(KindScaledJob) && ref.Name == scaler.Name {
+							if jobID, ok := jobIDs[ns+"/"+job.Name]; ok {
+								edges = append(edges, Edge{
+									ID:     fmt.Sprintf("%s-to-%s", scalerID, jobID),
+									Source: scalerID,
+									Target: jobID,
+									Type:   EdgeManages,
+								})
+							}
+						}
+					}
+				}
+				continue
+			}
+
+			// ScaledObject scales its target through a KEDA-managed HPA
+			targetKey := ns + "/" + scaler.TargetName
+			var targetID string
+			switch scaler.TargetKind {
+			case "Deployment":
+				targetID = deploymentIDs[targetKey]
+			case "Rollout":
+				targetID = rolloutIDs[targetKey]
+			case "StatefulSet":
+				targetID = statefulSetIDs[targetKey]
+			}
+			if targetID != "" {
+				edges = append(edges, Edge{
+					ID:     fmt.Sprintf("%s-to-%s", scalerID, targetID),
+					Source: scalerID,
+					Target: targetID,
+					Type:   EdgeUses,
+				})
+			}
+			if hpaID, ok := hpaIDs[ns+"/"+scaler.HPAName]; ok && scaler.HPAName != "" {
+				edges = append(edges, Edge{
+					ID:     fmt.Sprintf("%s-to-%s", scalerID, hpaID),
+					Source: scalerID,
+					Target: hpaID,
+					Type:   EdgeManages,
+				})
+			}
+		}
+	}
+
 	// 12. Second pass: Create ArgoCD Application edges to managed resources
 	// This is done after all resource IDs are populated
 	for _, app := range applicationResources {
@@ -3039,6 +3144,7 @@ func (b *Builder) addGenericCRDNodes(nodes []Node, edges []Edge, opts BuildOptio
 		"replicaset": true, "pod": true, "service": true, "ingress": true,
 		"job": true, "cronjob": true, "configmap": true, "secret": true,
 		"persistentvolumeclaim": true, "horizontalpodautoscaler": true,
+		"scaledobject": true, "scaledjob": true,
 		// Also skip namespace (not typically owned)
 		"namespace": true,
 	}
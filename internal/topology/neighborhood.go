@@ -0,0 +1,62 @@
+package topology
+
+import "slices"
+
+// MaxNeighborhoodDepth bounds neighborhood queries; past a few hops the
+// subgraph approaches the full cluster graph anyway
+const MaxNeighborhoodDepth = 5
+
+// Neighborhood returns the subgraph of nodes within depth hops of the given
+// resource, following edges in either direction, plus the edges between them.
+// Returns nil if the resource is not in the topology.
+func Neighborhood(kind, namespace, name string, depth int, topo *Topology) *Topology {
+	if topo == nil {
+		return nil
+	}
+	depth = min(max(depth, 0), MaxNeighborhoodDepth)
+
+	rootID := buildNodeID(kind, namespace, name)
+	if !slices.ContainsFunc(topo.Nodes, func(n Node) bool { return n.ID == rootID }) {
+		return nil
+	}
+
+	adjacent := make(map[string][]string)
+	for _, edge := range topo.Edges {
+		adjacent[edge.Source] = append(adjacent[edge.Source], edge.Target)
+		adjacent[edge.Target] = append(adjacent[edge.Target], edge.Source)
+	}
+
+	// Breadth-first search from the root, one hop per round
+	included := map[string]bool{rootID: true}
+	frontier := []string{rootID}
+	for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+		var next []string
+		for _, id := range frontier {
+			for _, neighbor := range adjacent[id] {
+				if !included[neighbor] {
+					included[neighbor] = true
+					next = append(next, neighbor)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	result := &Topology{
+		Nodes:    make([]Node, 0, len(included)),
+		Edges:    []Edge{},
+		Warnings: topo.Warnings,
+	}
+	// Keep the full graph's node order so layouts stay stable between calls
+	for _, node := range topo.Nodes {
+		if included[node.ID] {
+			result.Nodes = append(result.Nodes, node)
+		}
+	}
+	for _, edge := range topo.Edges {
+		if included[edge.Source] && included[edge.Target] {
+			result.Edges = append(result.Edges, edge)
+		}
+	}
+	return result
+}
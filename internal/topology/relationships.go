@@ -122,8 +122,10 @@ func GetRelationships(kind, namespace, name string, topo *Topology) *Relationshi
 					rel.Services = append(rel.Services, *ref)
 				}
 			case EdgeUses:
-				// An HPA scales this resource
-				rel.HPA = ref
+				// An HPA (or KEDA ScaledObject) scales this resource; prefer the HPA itself
+				if rel.HPA == nil || ref.Kind == string(KindHPA) {
+					rel.HPA = ref
+				}
 			case EdgeConfigures:
 				// A ConfigMap/Secret is used by this resource
 				rel.ConfigRefs = append(rel.ConfigRefs, *ref)
@@ -167,6 +169,8 @@ func buildNodeID(kind, namespace, name string) string {
 		"configmaps":   "configmap",
 		"secrets":      "secret",
 		"horizontalpodautoscalers": "horizontalpodautoscaler",
+		"scaledobjects":            "scaledobject",
+		"scaledjobs":               "scaledjob",
 		"jobs":                    "job",
 		"cronjobs":                "cronjob",
 		"persistentvolumeclaims":  "persistentvolumeclaim",
@@ -226,6 +230,8 @@ func normalizeKind(kind string) string {
 		"configmap":                "ConfigMap",
 		"secret":                   "Secret",
 		"horizontalpodautoscaler":  "HorizontalPodAutoscaler",
+		"scaledobject":             "ScaledObject",
+		"scaledjob":                "ScaledJob",
 		"job":                      "Job",
 		"cronjob":                  "CronJob",
 		"persistentvolumeclaim":    "PersistentVolumeClaim",
@@ -0,0 +1,185 @@
+package topology
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+// historicalKinds are the kinds a historical topology restores from delete
+// events. Pods are left out: they churn too much to reconstruct meaningfully.
+var historicalKinds = map[string]NodeKind{
+	"Ingress":                 KindIngress,
+	"Service":                 KindService,
+	"Deployment":              KindDeployment,
+	"Rollout":                 KindRollout,
+	"DaemonSet":               KindDaemonSet,
+	"StatefulSet":             KindStatefulSet,
+	"ReplicaSet":              KindReplicaSet,
+	"ConfigMap":               KindConfigMap,
+	"Secret":                  KindSecret,
+	"HorizontalPodAutoscaler": KindHPA,
+	"Job":                     KindJob,
+	"CronJob":                 KindCronJob,
+	"PersistentVolumeClaim":   KindPVC,
+}
+
+// HistoricalKinds returns the timeline kinds AsOf reads, for querying the events it needs
+func HistoricalKinds() []string {
+	kinds := make([]string, 0, len(historicalKinds))
+	for kind := range historicalKinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// resourceHistory is what the timeline says about one resource since the target time
+type resourceHistory struct {
+	kind, namespace, name string
+	existedAt             bool
+	deletedAt             time.Time
+	owner                 *timeline.OwnerInfo
+	labels                map[string]string
+}
+
+// AsOf rewinds a current topology to the given time using the timeline's
+// create and delete events since then (events must be newest first, as the
+// stores return them). Resources created after at are removed; resources
+// deleted since are restored as historical nodes, connected to their owners.
+// Spec changes in between (e.g. a changed Service selector) aren't reversed.
+func AsOf(topo *Topology, at time.Time, events []timeline.TimelineEvent, opts BuildOptions) *Topology {
+	histories := resourceHistories(at, events)
+
+	result := &Topology{
+		Warnings:           append([]string(nil), topo.Warnings...),
+		LargeCluster:       topo.LargeCluster,
+		HiddenKinds:        topo.HiddenKinds,
+		CRDDiscoveryStatus: topo.CRDDiscoveryStatus,
+		At:                 &at,
+	}
+
+	present := make(map[string]bool, len(topo.Nodes))
+	for _, node := range topo.Nodes {
+		if node.Kind == KindPod || node.Kind == KindPodGroup {
+			continue
+		}
+		if h, ok := histories[node.ID]; ok && !h.existedAt {
+			continue
+		}
+		present[node.ID] = true
+		result.Nodes = append(result.Nodes, node)
+	}
+
+	ids := make([]string, 0, len(histories))
+	for id := range histories {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	restored := make(map[string]*resourceHistory)
+	for _, id := range ids {
+		h := histories[id]
+		kind, ok := historicalKinds[h.kind]
+		if !ok || !h.existedAt || present[id] || !showsHistoricalKind(kind, opts) || !opts.MatchesNamespaceFilter(h.namespace) {
+			continue
+		}
+		present[id] = true
+		restored[id] = h
+		result.Nodes = append(result.Nodes, Node{
+			ID:     id,
+			Kind:   kind,
+			Name:   h.name,
+			Status: StatusUnknown,
+			Data: map[string]any{
+				"namespace":  h.namespace,
+				"labels":     h.labels,
+				"historical": true,
+				"deletedAt":  h.deletedAt,
+			},
+		})
+	}
+
+	for _, edge := range topo.Edges {
+		if present[edge.Source] && present[edge.Target] {
+			result.Edges = append(result.Edges, edge)
+		}
+	}
+	for _, id := range ids {
+		h := restored[id]
+		if h == nil || h.owner == nil {
+			continue
+		}
+		ownerID := buildNodeID(h.owner.Kind, h.namespace, h.owner.Name)
+		if !present[ownerID] {
+			continue
+		}
+		result.Edges = append(result.Edges, Edge{
+			ID:     fmt.Sprintf("%s-to-%s", ownerID, id),
+			Source: ownerID,
+			Target: id,
+			Type:   EdgeManages,
+		})
+	}
+
+	if result.Nodes == nil {
+		result.Nodes = []Node{}
+	}
+	if result.Edges == nil {
+		result.Edges = []Edge{}
+	}
+	result.Warnings = append(result.Warnings,
+		"Historical view: pods are omitted, and resources deleted since are restored with ownership edges only")
+	return result
+}
+
+// resourceHistories works out, for each resource with a create or delete
+// event after at, whether it existed at that time. The earliest such event
+// decides: a create means it didn't exist yet, a delete means it did.
+func resourceHistories(at time.Time, events []timeline.TimelineEvent) map[string]*resourceHistory {
+	histories := make(map[string]*resourceHistory)
+	// Walk oldest first so the first event seen per resource is the earliest
+	for i := len(events) - 1; i >= 0; i-- {
+		e := &events[i]
+		if e.Source != timeline.SourceInformer || !e.Timestamp.After(at) {
+			continue
+		}
+		id := buildNodeID(e.Kind, e.Namespace, e.Name)
+		h, seen := histories[id]
+		if !seen {
+			h = &resourceHistory{kind: e.Kind, namespace: e.Namespace, name: e.Name, existedAt: true}
+			histories[id] = h
+			switch {
+			case e.EventType == timeline.EventTypeAdd:
+				h.existedAt = false
+			case e.CreatedAt != nil && e.CreatedAt.After(at): // Created while Radar wasn't watching
+				h.existedAt = false
+			}
+		}
+		if e.Owner != nil {
+			h.owner = e.Owner
+		}
+		if len(e.Labels) > 0 {
+			h.labels = e.Labels
+		}
+		if e.EventType == timeline.EventTypeDelete && h.deletedAt.IsZero() {
+			h.deletedAt = e.Timestamp
+		}
+	}
+	return histories
+}
+
+func showsHistoricalKind(kind NodeKind, opts BuildOptions) bool {
+	switch kind {
+	case KindSecret:
+		return opts.IncludeSecrets
+	case KindConfigMap:
+		return opts.IncludeConfigMaps
+	case KindPVC:
+		return opts.IncludePVCs
+	case KindReplicaSet:
+		return opts.IncludeReplicaSets
+	}
+	return true
+}
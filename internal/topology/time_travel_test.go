@@ -0,0 +1,96 @@
+package topology
+
+import (
+	"testing"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/timeline"
+)
+
+func TestResourceHistories(t *testing.T) {
+	at := time.Now().Add(-time.Hour)
+	event := func(minutes int, eventType timeline.EventType, kind, name string) timeline.TimelineEvent {
+		return timeline.TimelineEvent{
+			Timestamp: at.Add(time.Duration(minutes) * time.Minute),
+			Source:    timeline.SourceInformer,
+			EventType: eventType,
+			Kind:      kind,
+			Namespace: "shop",
+			Name:      name,
+		}
+	}
+	before := at.Add(-time.Minute)
+	createdLater := event(20, timeline.EventTypeUpdate, "Service", "late")
+	createdLater.CreatedAt = &createdLater.Timestamp
+	deleted := event(30, timeline.EventTypeDelete, "Deployment", "old")
+	deleted.Owner = &timeline.OwnerInfo{Kind: "Rollout", Name: "web"}
+
+	// Newest first, as the stores return them
+	events := []timeline.TimelineEvent{
+		deleted,
+		createdLater,
+		event(15, timeline.EventTypeUpdate, "Deployment", "old"),
+		event(10, timeline.EventTypeDelete, "Service", "recreated"),
+		event(5, timeline.EventTypeAdd, "Service", "recreated"),
+		{Timestamp: before, Source: timeline.SourceInformer, EventType: timeline.EventTypeAdd, Kind: "Service", Namespace: "shop", Name: "ancient"},
+		{Timestamp: at.Add(time.Minute), Source: timeline.SourceK8sEvent, EventType: timeline.EventTypeAdd, Kind: "Service", Namespace: "shop", Name: "k8s-event"},
+	}
+
+	histories := resourceHistories(at, events)
+	if len(histories) != 3 {
+		t.Fatalf("expected 3 histories, got %d: %v", len(histories), histories)
+	}
+	if h := histories["deployment/shop/old"]; h == nil || !h.existedAt || !h.deletedAt.Equal(deleted.Timestamp) || h.owner == nil {
+		t.Errorf("deleted deployment should have existed with its owner, got %+v", h)
+	}
+	if h := histories["service/shop/recreated"]; h == nil || h.existedAt {
+		t.Errorf("service created after at shouldn't have existed, got %+v", h)
+	}
+	if h := histories["service/shop/late"]; h == nil || h.existedAt {
+		t.Errorf("service with a later creation timestamp shouldn't have existed, got %+v", h)
+	}
+}
+
+func TestAsOf(t *testing.T) {
+	at := time.Now().Add(-time.Hour)
+	topo := &Topology{
+		Nodes: []Node{
+			{ID: "rollout/shop/web", Kind: KindRollout, Name: "web"},
+			{ID: "service/shop/new", Kind: KindService, Name: "new"},
+			{ID: "pod/shop/web-1", Kind: KindPod, Name: "web-1"},
+		},
+		Edges: []Edge{
+			{ID: "e1", Source: "service/shop/new", Target: "rollout/shop/web"},
+		},
+	}
+	events := []timeline.TimelineEvent{
+		{Timestamp: at.Add(30 * time.Minute), Source: timeline.SourceInformer, EventType: timeline.EventTypeDelete,
+			Kind: "Deployment", Namespace: "shop", Name: "old", Owner: &timeline.OwnerInfo{Kind: "Rollout", Name: "web"}},
+		{Timestamp: at.Add(10 * time.Minute), Source: timeline.SourceInformer, EventType: timeline.EventTypeAdd,
+			Kind: "Service", Namespace: "shop", Name: "new"},
+		{Timestamp: at.Add(5 * time.Minute), Source: timeline.SourceInformer, EventType: timeline.EventTypeDelete,
+			Kind: "Secret", Namespace: "shop", Name: "token"},
+	}
+
+	result := AsOf(topo, at, events, DefaultBuildOptions())
+	ids := make(map[string]Node)
+	for _, n := range result.Nodes {
+		ids[n.ID] = n
+	}
+	if len(ids) != 2 {
+		t.Fatalf("expected the rollout and restored deployment, got %+v", result.Nodes)
+	}
+	if _, ok := ids["rollout/shop/web"]; !ok {
+		t.Error("existing rollout should be kept")
+	}
+	if n, ok := ids["deployment/shop/old"]; !ok || n.Data["historical"] != true {
+		t.Errorf("deleted deployment should be restored as historical, got %+v", n)
+	}
+	// Secrets stay hidden unless requested; pods are always omitted
+	if len(result.Edges) != 1 || result.Edges[0].Source != "rollout/shop/web" || result.Edges[0].Target != "deployment/shop/old" {
+		t.Errorf("expected only the restored ownership edge, got %+v", result.Edges)
+	}
+	if result.At == nil || !result.At.Equal(at) {
+		t.Errorf("At = %v, want %v", result.At, at)
+	}
+}
@@ -1,5 +1,7 @@
 package topology
 
+import "time"
+
 // NodeKind represents the type of a topology node
 //
 // When adding a new NodeKind constant, also update:
@@ -35,6 +37,8 @@ const (
 	KindConfigMap     NodeKind = "ConfigMap"
 	KindSecret        NodeKind = "Secret"
 	KindHPA           NodeKind = "HorizontalPodAutoscaler"
+	KindScaledObject  NodeKind = "ScaledObject" // KEDA ScaledObject
+	KindScaledJob     NodeKind = "ScaledJob"    // KEDA ScaledJob
 	KindJob           NodeKind = "Job"
 	KindCronJob       NodeKind = "CronJob"
 	KindPVC           NodeKind = "PersistentVolumeClaim"
@@ -83,14 +87,15 @@ type Edge struct {
 
 // Topology represents the complete graph
 type Topology struct {
-	Nodes              []Node   `json:"nodes"`
-	Edges              []Edge   `json:"edges"`
-	Warnings           []string `json:"warnings,omitempty"`           // Warnings about resources that failed to load
-	Truncated          bool     `json:"truncated,omitempty"`          // True if topology was truncated due to size limit
-	TotalNodes         int      `json:"totalNodes,omitempty"`         // Total nodes before truncation (only set if truncated)
-	LargeCluster       bool     `json:"largeCluster,omitempty"`       // True if cluster exceeds large cluster threshold
-	HiddenKinds        []string `json:"hiddenKinds,omitempty"`        // Resource kinds auto-hidden for performance
-	CRDDiscoveryStatus string   `json:"crdDiscoveryStatus,omitempty"` // CRD discovery status: idle, discovering, ready
+	Nodes              []Node     `json:"nodes"`
+	Edges              []Edge     `json:"edges"`
+	Warnings           []string   `json:"warnings,omitempty"`           // Warnings about resources that failed to load
+	Truncated          bool       `json:"truncated,omitempty"`          // True if topology was truncated due to size limit
+	TotalNodes         int        `json:"totalNodes,omitempty"`         // Total nodes before truncation (only set if truncated)
+	LargeCluster       bool       `json:"largeCluster,omitempty"`       // True if cluster exceeds large cluster threshold
+	HiddenKinds        []string   `json:"hiddenKinds,omitempty"`        // Resource kinds auto-hidden for performance
+	CRDDiscoveryStatus string     `json:"crdDiscoveryStatus,omitempty"` // CRD discovery status: idle, discovering, ready
+	At                 *time.Time `json:"at,omitempty"`                 // Set for historical topologies (?at=)
 }
 
 // ViewMode determines how the topology is built
@@ -5,14 +5,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"net/url"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/skyhook-io/radar/internal/outbound"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -21,6 +24,9 @@ import (
 const (
 	carettaNamespace = "caretta"
 	carettaAppLabel  = "app.kubernetes.io/name=caretta"
+
+	// carettaQueryTimeout bounds each request to the metrics backend
+	carettaQueryTimeout = 10 * time.Second
 )
 
 // Known Prometheus/VictoriaMetrics service locations to check.
@@ -73,10 +79,8 @@ type CarettaSource struct {
 // NewCarettaSource creates a new Caretta traffic source
 func NewCarettaSource(client kubernetes.Interface) *CarettaSource {
 	return &CarettaSource{
-		k8sClient: client,
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		k8sClient:  client,
+		httpClient: outbound.NewClient(carettaQueryTimeout),
 	}
 }
 
@@ -310,41 +314,64 @@ func (c *CarettaSource) tryClusterAddrLocked(ctx context.Context, info *metricsS
 	return false
 }
 
-// queryPrometheusForFlows queries Prometheus for caretta_links_observed metrics
-func (c *CarettaSource) queryPrometheusForFlows(ctx context.Context, promAddr string, basePath string, opts FlowOptions) ([]Flow, error) {
-	// Build PromQL query for Caretta's link metric
-	// caretta_links_observed{client_name, client_namespace, server_name, server_namespace, server_port, ...}
-	query := "caretta_links_observed"
-	if opts.Namespace != "" {
-		// Filter by namespace (either client or server)
-		query = fmt.Sprintf(`caretta_links_observed{client_namespace="%s"} or caretta_links_observed{server_namespace="%s"}`,
-			opts.Namespace, opts.Namespace)
+// namespaceMatchers returns the PromQL label matchers for the namespace and
+// direction filter, one per side of the flow to match. Returns a single empty
+// matcher when there's no namespace filter.
+func namespaceMatchers(opts FlowOptions, sourceLabel, destLabel string) []string {
+	if len(opts.Namespaces) == 0 {
+		return []string{""}
+	}
+	var value string
+	if len(opts.Namespaces) == 1 {
+		value = fmt.Sprintf(`="%s"`, opts.Namespaces[0])
+	} else {
+		quoted := make([]string, len(opts.Namespaces))
+		for i, ns := range opts.Namespaces {
+			quoted[i] = regexp.QuoteMeta(ns)
+		}
+		value = fmt.Sprintf(`=~"%s"`, strings.Join(quoted, "|"))
 	}
-
-	queryURL := fmt.Sprintf("%s%s/api/v1/query?query=%s", promAddr, basePath, url.QueryEscape(query))
-
-	req, err := http.NewRequestWithContext(ctx, "GET", queryURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+	var matchers []string
+	if opts.matchSource() {
+		matchers = append(matchers, sourceLabel+value)
 	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("querying prometheus: %w", err)
+	if opts.matchDestination() {
+		matchers = append(matchers, destLabel+value)
 	}
-	defer resp.Body.Close()
+	return matchers
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("prometheus returned status %d", resp.StatusCode)
+// joinMatchers combines label matchers, skipping empty ones
+func joinMatchers(matchers ...string) string {
+	nonEmpty := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		if m != "" {
+			nonEmpty = append(nonEmpty, m)
+		}
 	}
+	return strings.Join(nonEmpty, ",")
+}
 
-	var promResp prometheusResponse
-	if err := json.NewDecoder(resp.Body).Decode(&promResp); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
+// promUnion renders a selector template once per matcher and joins them with "or"
+func promUnion(template string, matchers []string) string {
+	parts := make([]string, len(matchers))
+	for i, m := range matchers {
+		parts[i] = fmt.Sprintf(template, m)
 	}
+	return strings.Join(parts, " or ")
+}
 
-	if promResp.Status != "success" {
-		return nil, fmt.Errorf("prometheus query failed: %s", promResp.Status)
+// queryPrometheusForFlows queries Prometheus for caretta_links_observed metrics
+func (c *CarettaSource) queryPrometheusForFlows(ctx context.Context, promAddr string, basePath string, opts FlowOptions) ([]Flow, error) {
+	// Build PromQL query for Caretta's link metric
+	// caretta_links_observed{client_name, client_namespace, server_name, server_namespace, server_port, ...}
+	// Namespace filters select the client side for egress, the server side for
+	// ingress, and either side otherwise
+	query := promUnion("caretta_links_observed{%s}", namespaceMatchers(opts, "client_namespace", "server_namespace"))
+
+	promResp, err := c.queryPrometheus(ctx, promAddr, basePath, query)
+	if err != nil {
+		return nil, err
 	}
 
 	// Parse results into flows
@@ -409,9 +436,128 @@ func (c *CarettaSource) queryPrometheusForFlows(ctx context.Context, promAddr st
 	}
 
 	log.Printf("[caretta] Retrieved %d flows from Prometheus", len(flows))
+
+	// Enrich with L7 metrics when a service mesh exports them to the same backend
+	c.enrichWithMeshMetrics(ctx, promAddr, basePath, opts, flows)
+
 	return flows, nil
 }
 
+// queryPrometheus runs an instant PromQL query against the metrics backend
+func (c *CarettaSource) queryPrometheus(ctx context.Context, promAddr, basePath, query string) (*prometheusResponse, error) {
+	queryURL := fmt.Sprintf("%s%s/api/v1/query?query=%s", promAddr, basePath, url.QueryEscape(query))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", queryURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying prometheus: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("prometheus returned status %d", resp.StatusCode)
+	}
+
+	var promResp prometheusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&promResp); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	if promResp.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s", promResp.Status)
+	}
+
+	return &promResp, nil
+}
+
+// meshWorkloadLabels groups Istio standard metrics by workload pair
+const meshWorkloadLabels = "source_workload, source_workload_namespace, destination_workload, destination_workload_namespace"
+
+// enrichWithMeshMetrics adds request rate, 5xx ratio and p95 latency from Istio
+// standard metrics (istio_requests_total / istio_request_duration_milliseconds)
+// to flows whose workload pair matches. Missing metrics are silently ignored.
+func (c *CarettaSource) enrichWithMeshMetrics(ctx context.Context, promAddr, basePath string, opts FlowOptions, flows []Flow) {
+	if len(flows) == 0 {
+		return
+	}
+
+	window := opts.Since
+	if window < time.Minute {
+		window = 5 * time.Minute
+	}
+	rangeStr := fmt.Sprintf("%ds", int(window.Seconds()))
+
+	// Series are unioned before aggregating so a flow with both ends in the
+	// selection isn't counted twice
+	nsMatchers := namespaceMatchers(opts, "source_workload_namespace", "destination_workload_namespace")
+	errMatchers := make([]string, len(nsMatchers))
+	for i, m := range nsMatchers {
+		errMatchers[i] = joinMatchers(m, `response_code=~"5.."`)
+	}
+
+	rates := c.queryMeshValues(ctx, promAddr, basePath,
+		fmt.Sprintf(`sum by (%s) (%s)`, meshWorkloadLabels, promUnion("rate(istio_requests_total{%s}["+rangeStr+"])", nsMatchers)))
+	if len(rates) == 0 {
+		return
+	}
+
+	errors := c.queryMeshValues(ctx, promAddr, basePath,
+		fmt.Sprintf(`sum by (%s) (%s)`, meshWorkloadLabels, promUnion("rate(istio_requests_total{%s}["+rangeStr+"])", errMatchers)))
+	p95 := c.queryMeshValues(ctx, promAddr, basePath,
+		fmt.Sprintf(`histogram_quantile(0.95, sum by (le, %s) (%s))`, meshWorkloadLabels, promUnion("rate(istio_request_duration_milliseconds_bucket{%s}["+rangeStr+"])", nsMatchers)))
+
+	enriched := 0
+	for i := range flows {
+		f := &flows[i]
+		key := meshKey(f.Source.Namespace, f.Source.Workload, f.Destination.Namespace, f.Destination.Workload)
+		rate, ok := rates[key]
+		if !ok || rate <= 0 {
+			continue
+		}
+		f.RequestRate = rate
+		f.ErrorRate = errors[key] / rate
+		f.P95LatencyMs = p95[key]
+		f.L7Protocol = "HTTP"
+		enriched++
+	}
+	if enriched > 0 {
+		log.Printf("[caretta] Enriched %d flows with mesh L7 metrics", enriched)
+	}
+}
+
+// queryMeshValues runs a query grouped by meshWorkloadLabels and returns values by workload pair
+func (c *CarettaSource) queryMeshValues(ctx context.Context, promAddr, basePath, query string) map[string]float64 {
+	resp, err := c.queryPrometheus(ctx, promAddr, basePath, query)
+	if err != nil {
+		return nil
+	}
+	values := make(map[string]float64, len(resp.Data.Result))
+	for _, result := range resp.Data.Result {
+		if len(result.Value) < 2 {
+			continue
+		}
+		valStr, ok := result.Value[1].(string)
+		if !ok {
+			continue
+		}
+		val, err := strconv.ParseFloat(valStr, 64)
+		if err != nil || math.IsNaN(val) || math.IsInf(val, 0) {
+			continue
+		}
+		m := result.Metric
+		values[meshKey(m["source_workload_namespace"], m["source_workload"], m["destination_workload_namespace"], m["destination_workload"])] = val
+	}
+	return values
+}
+
+func meshKey(srcNs, src, dstNs, dst string) string {
+	return srcNs + "/" + src + "|" + dstNs + "/" + dst
+}
+
 // prometheusResponse represents the Prometheus API response structure
 type prometheusResponse struct {
 	Status string `json:"status"`
@@ -0,0 +1,388 @@
+package traffic
+
+import (
+	"context"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/k8s"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// External endpoint categories (Endpoint.Category)
+const (
+	CategoryNode     = "node"     // Cluster node IP
+	CategoryPod      = "pod"      // Inside a node's pod CIDR (pod no longer known to the source)
+	CategoryService  = "service"  // Service ClusterIP
+	CategoryCloud    = "cloud"    // Cloud provider service or metadata endpoint
+	CategoryPrivate  = "private"  // RFC1918 / link-local address outside the cluster
+	CategoryInternet = "internet" // Public address
+)
+
+const (
+	clusterRangesTTL  = 5 * time.Minute
+	dnsCacheTTL       = 30 * time.Minute
+	dnsNegativeTTL    = 5 * time.Minute
+	dnsLookupTimeout  = 500 * time.Millisecond
+	dnsMaxConcurrency = 8
+)
+
+// knownCIDR labels a well-known address range
+type knownCIDR struct {
+	cidr     string
+	label    string
+	category string
+}
+
+// knownCIDRs are stable, documented addresses for cloud platform services.
+// Larger, frequently changing ranges (e.g. AWS S3 prefixes) are identified by reverse DNS instead.
+var knownCIDRs = []knownCIDR{
+	{"169.254.169.254/32", "Cloud metadata", CategoryCloud},
+	{"169.254.169.253/32", "AWS DNS", CategoryCloud},
+	{"169.254.169.123/32", "AWS Time Sync", CategoryCloud},
+	{"168.63.129.16/32", "Azure platform", CategoryCloud},
+	{"199.36.153.8/30", "Google APIs (private)", CategoryCloud},
+	{"199.36.153.4/30", "Google APIs (restricted)", CategoryCloud},
+	{"10.0.0.0/8", "", CategoryPrivate},
+	{"172.16.0.0/12", "", CategoryPrivate},
+	{"192.168.0.0/16", "", CategoryPrivate},
+	{"100.64.0.0/10", "", CategoryPrivate},
+	{"169.254.0.0/16", "", CategoryPrivate},
+	{"fc00::/7", "", CategoryPrivate},
+	{"fe80::/10", "", CategoryPrivate},
+}
+
+type parsedCIDR struct {
+	knownCIDR
+	net *net.IPNet
+}
+
+var parsedKnownCIDRs = func() []parsedCIDR {
+	out := make([]parsedCIDR, 0, len(knownCIDRs))
+	for _, k := range knownCIDRs {
+		if _, n, err := net.ParseCIDR(k.cidr); err == nil {
+			out = append(out, parsedCIDR{knownCIDR: k, net: n})
+		}
+	}
+	return out
+}()
+
+// hostnameLabels maps reverse-DNS hostname patterns to friendly service names.
+// Checked in order; the first match wins.
+var hostnameLabels = []struct {
+	contains string
+	suffix   string
+	label    string
+}{
+	{"s3", ".amazonaws.com", "AWS S3"},
+	{".rds.", ".amazonaws.com", "AWS RDS"},
+	{"dynamodb", ".amazonaws.com", "AWS DynamoDB"},
+	{".elb.", ".amazonaws.com", "AWS ELB"},
+	{"", ".compute.amazonaws.com", "AWS EC2"},
+	{"", ".amazonaws.com", "AWS"},
+	{"", ".1e100.net", "Google"},
+	{"", ".googleusercontent.com", "Google Cloud"},
+	{"", ".cloudapp.azure.com", "Azure"},
+	{"", ".blob.core.windows.net", "Azure Blob Storage"},
+	{"", ".database.windows.net", "Azure SQL"},
+}
+
+// wellKnownPorts labels external destinations by port when nothing better is known
+var wellKnownPorts = map[int]string{
+	3307: "Cloud SQL",
+}
+
+type dnsEntry struct {
+	hostname string
+	expires  time.Time
+}
+
+// EndpointResolver labels External endpoints using cluster address ranges,
+// known cloud CIDRs, and cached reverse DNS lookups.
+type EndpointResolver struct {
+	mu            sync.RWMutex
+	nodeIPs       map[string]string // IP -> node name
+	serviceIPs    map[string]string // ClusterIP -> namespace/name
+	podCIDRs      []*net.IPNet
+	rangesExpires time.Time
+	refreshing    atomic.Bool
+	dnsCache      map[string]dnsEntry
+
+	lookupAddr func(ctx context.Context, addr string) ([]string, error)
+}
+
+// NewEndpointResolver creates a resolver that reads cluster ranges from the resource cache
+func NewEndpointResolver() *EndpointResolver {
+	return &EndpointResolver{
+		dnsCache:   make(map[string]dnsEntry),
+		lookupAddr: net.DefaultResolver.LookupAddr,
+	}
+}
+
+// EnrichFlows labels the External endpoints of the given flows in place
+func (r *EndpointResolver) EnrichFlows(ctx context.Context, flows []Flow) {
+	if r == nil || len(flows) == 0 {
+		return
+	}
+	r.refreshClusterRanges()
+
+	// Resolve all unique unknown IPs up front so lookups run concurrently
+	var pending []string
+	seen := make(map[string]bool)
+	for i := range flows {
+		for _, ep := range []*Endpoint{&flows[i].Source, &flows[i].Destination} {
+			ip := externalIP(ep)
+			if ip == "" || seen[ip] {
+				continue
+			}
+			seen[ip] = true
+			if label, _ := r.classifyStatic(ip); label == "" {
+				pending = append(pending, ip)
+			}
+		}
+	}
+	r.resolveAll(ctx, pending)
+
+	for i := range flows {
+		r.enrichEndpoint(&flows[i].Source, 0)
+		r.enrichEndpoint(&flows[i].Destination, flows[i].Port)
+	}
+}
+
+// EnrichFlow labels a single streamed flow, using only cached DNS results
+func (r *EndpointResolver) EnrichFlow(ctx context.Context, flow *Flow) {
+	if r == nil || flow == nil {
+		return
+	}
+	r.refreshClusterRanges()
+	r.enrichEndpoint(&flow.Source, 0)
+	r.enrichEndpoint(&flow.Destination, flow.Port)
+}
+
+func (r *EndpointResolver) enrichEndpoint(ep *Endpoint, port int) {
+	ip := externalIP(ep)
+	if ip == "" {
+		return
+	}
+	if ep.IP == "" {
+		ep.IP = ip
+	}
+
+	label, category := r.classifyStatic(ip)
+	if category == "" {
+		category = CategoryInternet
+	}
+	ep.Category = category
+	if label != "" {
+		ep.DisplayName = label
+		return
+	}
+
+	if host := r.cachedHostname(ip); host != "" {
+		ep.Hostname = host
+		if label := labelForHostname(host); label != "" {
+			ep.DisplayName = label
+			ep.Category = CategoryCloud
+		} else {
+			ep.DisplayName = host
+		}
+		return
+	}
+
+	if label, ok := wellKnownPorts[port]; ok {
+		ep.DisplayName = label
+		ep.Category = CategoryCloud
+	}
+}
+
+// externalIP returns the IP address of an External endpoint, or "" if the
+// endpoint is in-cluster or has no usable address.
+func externalIP(ep *Endpoint) string {
+	if ep.Kind != "External" {
+		return ""
+	}
+	if ep.IP != "" && net.ParseIP(ep.IP) != nil {
+		return ep.IP
+	}
+	if net.ParseIP(ep.Name) != nil {
+		return ep.Name
+	}
+	return ""
+}
+
+// classifyStatic matches an IP against cluster ranges and known CIDRs.
+// Private ranges return a category with an empty label so reverse DNS is still attempted.
+func (r *EndpointResolver) classifyStatic(ip string) (label, category string) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return "", ""
+	}
+
+	r.mu.RLock()
+	node, isNode := r.nodeIPs[ip]
+	svc, isSvc := r.serviceIPs[ip]
+	podCIDRs := r.podCIDRs
+	r.mu.RUnlock()
+
+	if isNode {
+		return "node/" + node, CategoryNode
+	}
+	if isSvc {
+		return "svc/" + svc, CategoryService
+	}
+	for _, n := range podCIDRs {
+		if n.Contains(parsed) {
+			return "pod network", CategoryPod
+		}
+	}
+	for _, k := range parsedKnownCIDRs {
+		if k.net.Contains(parsed) {
+			return k.label, k.category
+		}
+	}
+	return "", ""
+}
+
+// labelForHostname maps a reverse-DNS hostname to a friendly cloud service name
+func labelForHostname(host string) string {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, h := range hostnameLabels {
+		if !strings.HasSuffix(host, h.suffix) {
+			continue
+		}
+		if h.contains != "" && !strings.Contains(host, h.contains) {
+			continue
+		}
+		return h.label
+	}
+	return ""
+}
+
+func (r *EndpointResolver) cachedHostname(ip string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entry, ok := r.dnsCache[ip]
+	if !ok || time.Now().After(entry.expires) {
+		return ""
+	}
+	return entry.hostname
+}
+
+// resolveAll performs reverse DNS lookups for uncached IPs with bounded concurrency
+func (r *EndpointResolver) resolveAll(ctx context.Context, ips []string) {
+	now := time.Now()
+	var todo []string
+	r.mu.RLock()
+	for _, ip := range ips {
+		if entry, ok := r.dnsCache[ip]; ok && now.Before(entry.expires) {
+			continue
+		}
+		todo = append(todo, ip)
+	}
+	r.mu.RUnlock()
+	if len(todo) == 0 {
+		return
+	}
+
+	sem := make(chan struct{}, dnsMaxConcurrency)
+	var wg sync.WaitGroup
+	for _, ip := range todo {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(ip string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			lookupCtx, cancel := context.WithTimeout(ctx, dnsLookupTimeout)
+			names, err := r.lookupAddr(lookupCtx, ip)
+			cancel()
+
+			entry := dnsEntry{expires: time.Now().Add(dnsNegativeTTL)}
+			if err == nil && len(names) > 0 {
+				entry = dnsEntry{
+					hostname: strings.TrimSuffix(names[0], "."),
+					expires:  time.Now().Add(dnsCacheTTL),
+				}
+			}
+			r.mu.Lock()
+			r.dnsCache[ip] = entry
+			r.mu.Unlock()
+		}(ip)
+	}
+	wg.Wait()
+}
+
+// refreshClusterRanges reloads node IPs, pod CIDRs and Service ClusterIPs from
+// the resource cache when stale. The reload runs in the background so flows
+// are never held up; until the first one finishes only static ranges apply.
+func (r *EndpointResolver) refreshClusterRanges() {
+	r.mu.RLock()
+	fresh := time.Now().Before(r.rangesExpires)
+	r.mu.RUnlock()
+	if fresh || !r.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer r.refreshing.Store(false)
+		r.loadClusterRanges()
+	}()
+}
+
+func (r *EndpointResolver) loadClusterRanges() {
+	cache := k8s.GetResourceCache()
+	if cache == nil {
+		return
+	}
+
+	nodeIPs := make(map[string]string)
+	serviceIPs := make(map[string]string)
+	var podCIDRs []*net.IPNet
+
+	if lister := cache.Nodes(); lister != nil {
+		nodes, err := lister.List(labels.Everything())
+		if err != nil {
+			log.Printf("[traffic] Failed to list nodes for endpoint enrichment: %v", err)
+		}
+		for _, node := range nodes {
+			for _, addr := range node.Status.Addresses {
+				if net.ParseIP(addr.Address) != nil {
+					nodeIPs[addr.Address] = node.Name
+				}
+			}
+			cidrs := node.Spec.PodCIDRs
+			if len(cidrs) == 0 && node.Spec.PodCIDR != "" {
+				cidrs = []string{node.Spec.PodCIDR}
+			}
+			for _, c := range cidrs {
+				if _, n, err := net.ParseCIDR(c); err == nil {
+					podCIDRs = append(podCIDRs, n)
+				}
+			}
+		}
+	}
+
+	if lister := cache.Services(); lister != nil {
+		services, err := lister.List(labels.Everything())
+		if err != nil {
+			log.Printf("[traffic] Failed to list services for endpoint enrichment: %v", err)
+		}
+		for _, svc := range services {
+			for _, ip := range svc.Spec.ClusterIPs {
+				if ip != "" && ip != "None" {
+					serviceIPs[ip] = svc.Namespace + "/" + svc.Name
+				}
+			}
+		}
+	}
+
+	r.mu.Lock()
+	r.nodeIPs = nodeIPs
+	r.serviceIPs = serviceIPs
+	r.podCIDRs = podCIDRs
+	r.rangesExpires = time.Now().Add(clusterRangesTTL)
+	r.mu.Unlock()
+}
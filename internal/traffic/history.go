@@ -0,0 +1,372 @@
+package traffic
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite" // Pure Go SQLite driver
+)
+
+// HistoryConfig controls periodic recording of aggregated flows so the traffic
+// view can be replayed for a past time window.
+type HistoryConfig struct {
+	Enabled   bool
+	Interval  time.Duration // How often to snapshot flows (default 1m)
+	Retention time.Duration // How long to keep snapshots (default 24h)
+	DBPath    string        // SQLite path; empty = in-memory
+}
+
+// FlowSnapshot is a point-in-time recording of aggregated flows
+type FlowSnapshot struct {
+	Context   string           `json:"context"`
+	Source    string           `json:"source"`
+	Timestamp time.Time        `json:"timestamp"`
+	Window    time.Duration    `json:"window"` // Look-back period the snapshot covers
+	Flows     []AggregatedFlow `json:"flows"`
+}
+
+// SnapshotSummary describes a recorded snapshot without its flows
+type SnapshotSummary struct {
+	Timestamp time.Time `json:"timestamp"`
+	Source    string    `json:"source"`
+	FlowCount int       `json:"flowCount"`
+}
+
+// FlowHistoryStore persists flow snapshots
+type FlowHistoryStore interface {
+	// Save stores a snapshot
+	Save(ctx context.Context, snap FlowSnapshot) error
+
+	// List returns summaries for snapshots of a context within [since, until]
+	List(ctx context.Context, contextName string, since, until time.Time) ([]SnapshotSummary, error)
+
+	// At returns the latest snapshot taken at or before t, or nil if none
+	At(ctx context.Context, contextName string, t time.Time) (*FlowSnapshot, error)
+
+	// Prune deletes snapshots older than cutoff
+	Prune(ctx context.Context, cutoff time.Time) error
+
+	Close() error
+}
+
+var (
+	historyConfig   HistoryConfig
+	historyStore    FlowHistoryStore
+	historyMu       sync.Mutex
+	recorderCancel  context.CancelFunc
+	recorderStopped chan struct{}
+)
+
+// SetHistoryConfig configures flow history recording. Must be called before
+// InitializeWithConfig; the store itself survives context switches.
+func SetHistoryConfig(cfg HistoryConfig) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = 24 * time.Hour
+	}
+	historyMu.Lock()
+	historyConfig = cfg
+	historyMu.Unlock()
+}
+
+// GetHistoryConfig returns the current flow history configuration
+func GetHistoryConfig() HistoryConfig {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	return historyConfig
+}
+
+// GetHistoryStore returns the flow history store, or nil if recording is disabled
+func GetHistoryStore() FlowHistoryStore {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	return historyStore
+}
+
+// startRecorder opens the history store (once) and starts the snapshot loop
+// for the given manager. No-op if recording is disabled.
+func startRecorder(m *Manager) {
+	historyMu.Lock()
+	defer historyMu.Unlock()
+
+	if !historyConfig.Enabled || recorderCancel != nil {
+		return
+	}
+
+	if historyStore == nil {
+		store, err := openHistoryStore(historyConfig.DBPath)
+		if err != nil {
+			log.Printf("[traffic] Warning: flow history disabled: %v", err)
+			return
+		}
+		historyStore = store
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	recorderCancel = cancel
+	recorderStopped = make(chan struct{})
+	go runRecorder(ctx, m, historyStore, historyConfig, recorderStopped)
+}
+
+// stopRecorder stops the snapshot loop (the store stays open for playback)
+func stopRecorder() {
+	historyMu.Lock()
+	cancel, stopped := recorderCancel, recorderStopped
+	recorderCancel, recorderStopped = nil, nil
+	historyMu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-stopped
+	}
+}
+
+func openHistoryStore(path string) (FlowHistoryStore, error) {
+	if path == "" {
+		return newMemoryHistoryStore(), nil
+	}
+	store, err := newSQLiteHistoryStore(path)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[traffic] Recording flow history to %s", path)
+	return store, nil
+}
+
+func runRecorder(ctx context.Context, m *Manager, store FlowHistoryStore, cfg HistoryConfig, stopped chan struct{}) {
+	defer close(stopped)
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	lastPrune := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if m.GetActiveSourceName() == "" {
+			continue
+		}
+
+		queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		resp, err := m.GetFlows(queryCtx, FlowOptions{Since: cfg.Interval})
+		cancel()
+		if err != nil {
+			// Source not connected yet (e.g. metrics port-forward not started) - try again next tick
+			continue
+		}
+
+		snap := FlowSnapshot{
+			Context:   m.ContextName(),
+			Source:    resp.Source,
+			Timestamp: resp.Timestamp,
+			Window:    cfg.Interval,
+			Flows:     AggregateFlowsOverWindow(resp.Flows, cfg.Interval),
+		}
+		if snap.Timestamp.IsZero() {
+			snap.Timestamp = time.Now()
+		}
+		if err := store.Save(ctx, snap); err != nil {
+			log.Printf("[traffic] Failed to save flow snapshot: %v", err)
+		}
+
+		if time.Since(lastPrune) > time.Hour {
+			if err := store.Prune(ctx, time.Now().Add(-cfg.Retention)); err != nil {
+				log.Printf("[traffic] Failed to prune flow history: %v", err)
+			}
+			lastPrune = time.Now()
+		}
+	}
+}
+
+// memoryHistoryStore keeps snapshots in memory (lost on restart)
+type memoryHistoryStore struct {
+	mu        sync.RWMutex
+	snapshots []FlowSnapshot // Sorted by Timestamp
+}
+
+func newMemoryHistoryStore() *memoryHistoryStore {
+	return &memoryHistoryStore{}
+}
+
+func (s *memoryHistoryStore) Save(_ context.Context, snap FlowSnapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshots = append(s.snapshots, snap)
+	sort.SliceStable(s.snapshots, func(i, j int) bool {
+		return s.snapshots[i].Timestamp.Before(s.snapshots[j].Timestamp)
+	})
+	return nil
+}
+
+func (s *memoryHistoryStore) List(_ context.Context, contextName string, since, until time.Time) ([]SnapshotSummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := []SnapshotSummary{}
+	for _, snap := range s.snapshots {
+		if snap.Context != contextName || snap.Timestamp.Before(since) || snap.Timestamp.After(until) {
+			continue
+		}
+		result = append(result, SnapshotSummary{Timestamp: snap.Timestamp, Source: snap.Source, FlowCount: len(snap.Flows)})
+	}
+	return result, nil
+}
+
+func (s *memoryHistoryStore) At(_ context.Context, contextName string, t time.Time) (*FlowSnapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for i := len(s.snapshots) - 1; i >= 0; i-- {
+		snap := s.snapshots[i]
+		if snap.Context == contextName && !snap.Timestamp.After(t) {
+			return &snap, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *memoryHistoryStore) Prune(_ context.Context, cutoff time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := sort.Search(len(s.snapshots), func(i int) bool {
+		return !s.snapshots[i].Timestamp.Before(cutoff)
+	})
+	s.snapshots = append([]FlowSnapshot(nil), s.snapshots[i:]...)
+	return nil
+}
+
+func (s *memoryHistoryStore) Close() error {
+	return nil
+}
+
+// snapshotTimeFormat is fixed-width so timestamps sort correctly as text
+const snapshotTimeFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+// sqliteHistoryStore persists snapshots in SQLite, typically in the same
+// database file as the timeline store
+type sqliteHistoryStore struct {
+	db *sql.DB
+}
+
+func newSQLiteHistoryStore(dbPath string) (*sqliteHistoryStore, error) {
+	dir := filepath.Dir(dbPath)
+	if dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory: %w", err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+
+	for _, pragma := range []string{"PRAGMA journal_mode=WAL", "PRAGMA busy_timeout=10000"} {
+		if _, err := db.Exec(pragma); err != nil {
+			log.Printf("Warning: failed to set %s: %v", pragma, err)
+		}
+	}
+
+	schema := `
+	CREATE TABLE IF NOT EXISTS flow_snapshots (
+		context TEXT NOT NULL,
+		timestamp TEXT NOT NULL,
+		source TEXT,
+		window_ms INTEGER,
+		flow_count INTEGER,
+		flows_json TEXT NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_flow_snapshots_ctx_ts ON flow_snapshots(context, timestamp);
+	`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize schema: %w", err)
+	}
+
+	return &sqliteHistoryStore{db: db}, nil
+}
+
+func (s *sqliteHistoryStore) Save(ctx context.Context, snap FlowSnapshot) error {
+	data, err := json.Marshal(snap.Flows)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO flow_snapshots (context, timestamp, source, window_ms, flow_count, flows_json) VALUES (?, ?, ?, ?, ?, ?)`,
+		snap.Context, snap.Timestamp.UTC().Format(snapshotTimeFormat), snap.Source, snap.Window.Milliseconds(), len(snap.Flows), string(data))
+	return err
+}
+
+func (s *sqliteHistoryStore) List(ctx context.Context, contextName string, since, until time.Time) ([]SnapshotSummary, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT timestamp, source, flow_count FROM flow_snapshots WHERE context = ? AND timestamp >= ? AND timestamp <= ? ORDER BY timestamp`,
+		contextName, since.UTC().Format(snapshotTimeFormat), until.UTC().Format(snapshotTimeFormat))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []SnapshotSummary{}
+	for rows.Next() {
+		var ts string
+		var source sql.NullString
+		var sum SnapshotSummary
+		if err := rows.Scan(&ts, &source, &sum.FlowCount); err != nil {
+			return nil, err
+		}
+		sum.Timestamp, _ = time.Parse(snapshotTimeFormat, ts)
+		sum.Source = source.String
+		result = append(result, sum)
+	}
+	return result, rows.Err()
+}
+
+func (s *sqliteHistoryStore) At(ctx context.Context, contextName string, t time.Time) (*FlowSnapshot, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT timestamp, source, window_ms, flows_json FROM flow_snapshots WHERE context = ? AND timestamp <= ? ORDER BY timestamp DESC LIMIT 1`,
+		contextName, t.UTC().Format(snapshotTimeFormat))
+
+	var ts, flowsJSON string
+	var source sql.NullString
+	var windowMs int64
+	if err := row.Scan(&ts, &source, &windowMs, &flowsJSON); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	snap := &FlowSnapshot{
+		Context: contextName,
+		Source:  source.String,
+		Window:  time.Duration(windowMs) * time.Millisecond,
+	}
+	snap.Timestamp, _ = time.Parse(snapshotTimeFormat, ts)
+	if err := json.Unmarshal([]byte(flowsJSON), &snap.Flows); err != nil {
+		return nil, fmt.Errorf("corrupt flow snapshot: %w", err)
+	}
+	return snap, nil
+}
+
+func (s *sqliteHistoryStore) Prune(ctx context.Context, cutoff time.Time) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM flow_snapshots WHERE timestamp < ?`, cutoff.UTC().Format(snapshotTimeFormat))
+	return err
+}
+
+func (s *sqliteHistoryStore) Close() error {
+	return s.db.Close()
+}
@@ -500,12 +500,7 @@ func (h *HubbleSource) fetchFlowsViaGRPC(ctx context.Context, opts FlowOptions)
 	// Add namespace filter if specified
 	// Use separate filters for source OR destination (each filter is AND within itself,
 	// but multiple filters are OR'd together)
-	if opts.Namespace != "" {
-		req.Whitelist = []*flowpb.FlowFilter{
-			{SourcePod: []string{opts.Namespace + "/"}},
-			{DestinationPod: []string{opts.Namespace + "/"}},
-		}
-	}
+	req.Whitelist = hubbleNamespaceFilters(opts)
 
 	// Add time filter based on Since
 	if opts.Since > 0 {
@@ -594,6 +589,9 @@ func convertHubbleFlow(pbFlow *flowpb.Flow) Flow {
 			flow.HTTPMethod = http.GetMethod()
 			flow.HTTPPath = http.GetUrl()
 			flow.HTTPStatus = int(http.GetCode())
+			if ns := l7.GetLatencyNs(); ns > 0 {
+				flow.LatencyMs = float64(ns) / 1e6
+			}
 		} else if dns := l7.GetDns(); dns != nil {
 			flow.L7Protocol = "DNS"
 		}
@@ -694,12 +692,7 @@ func (h *HubbleSource) StreamFlows(ctx context.Context, opts FlowOptions) (<-cha
 			Follow: true,
 		}
 
-		if opts.Namespace != "" {
-			req.Whitelist = []*flowpb.FlowFilter{
-				{SourcePod: []string{opts.Namespace + "/"}},
-				{DestinationPod: []string{opts.Namespace + "/"}},
-			}
-		}
+		req.Whitelist = hubbleNamespaceFilters(opts)
 
 		stream, err := client.GetFlows(ctx, req)
 		if err != nil {
@@ -772,3 +765,24 @@ hubble observe --server localhost:4245
 kubectl -n %s port-forward svc/hubble-ui 12000:80
 # Then open http://localhost:12000`, namespace, namespace)
 }
+
+// hubbleNamespaceFilters builds the flow whitelist for the namespace and
+// direction filter. Pod prefixes within a filter are OR'd, as are the filters
+// themselves, so source and destination matches each get their own filter.
+func hubbleNamespaceFilters(opts FlowOptions) []*flowpb.FlowFilter {
+	if len(opts.Namespaces) == 0 {
+		return nil
+	}
+	prefixes := make([]string, len(opts.Namespaces))
+	for i, ns := range opts.Namespaces {
+		prefixes[i] = ns + "/"
+	}
+	var filters []*flowpb.FlowFilter
+	if opts.matchSource() {
+		filters = append(filters, &flowpb.FlowFilter{SourcePod: prefixes})
+	}
+	if opts.matchDestination() {
+		filters = append(filters, &flowpb.FlowFilter{DestinationPod: prefixes})
+	}
+	return filters
+}
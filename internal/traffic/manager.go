@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/skyhook-io/radar/internal/k8s"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -21,6 +22,7 @@ type Manager struct {
 	activeSource TrafficSource
 	clusterInfo  *ClusterInfo
 	contextName  string // current K8s context name
+	resolver     *EndpointResolver
 	mu           sync.RWMutex
 }
 
@@ -29,14 +31,14 @@ var (
 	initOnce sync.Once
 	initErr  error
 
-	// configuredMetricsURL is the user-provided --prometheus-url flag value.
+	// configuredMetrics holds the user-provided metrics URL and auth settings.
 	// Stored at package level so it persists across context-switch resets.
-	configuredMetricsURL string
+	configuredMetrics MetricsConfig
 )
 
-// SetMetricsURL sets a manual Prometheus/VictoriaMetrics URL, bypassing auto-discovery.
-func SetMetricsURL(url string) {
-	configuredMetricsURL = url
+// SetMetricsConfig sets the metrics URL, auth, and per-context overrides
+func SetMetricsConfig(cfg MetricsConfig) {
+	configuredMetrics = cfg
 }
 
 // Initialize sets up the traffic manager with the given K8s client
@@ -52,12 +54,20 @@ func InitializeWithConfig(client kubernetes.Interface, config *rest.Config, cont
 			k8sConfig:   config,
 			sources:     make(map[string]TrafficSource),
 			contextName: contextName,
+			resolver:    NewEndpointResolver(),
 		}
 		// Register available sources
 		manager.sources["hubble"] = NewHubbleSource(client)
 		caretta := NewCarettaSource(client)
-		if configuredMetricsURL != "" {
-			caretta.metricsURL = configuredMetricsURL
+		settings := configuredMetrics.ForContext(contextName)
+		caretta.metricsURL = settings.URL
+		if !settings.Auth.IsZero() {
+			httpClient, err := NewMetricsHTTPClient(settings.Auth, carettaQueryTimeout)
+			if err != nil {
+				log.Printf("[traffic] Failed to configure metrics auth for context %q: %v", contextName, err)
+			} else {
+				caretta.httpClient = httpClient
+			}
 		}
 		manager.sources["caretta"] = caretta
 
@@ -65,6 +75,8 @@ func InitializeWithConfig(client kubernetes.Interface, config *rest.Config, cont
 		if config != nil {
 			SetK8sClients(client, config)
 		}
+
+		startRecorder(manager)
 	})
 	return initErr
 }
@@ -375,7 +387,15 @@ func (m *Manager) GetFlows(ctx context.Context, opts FlowOptions) (*FlowsRespons
 		return nil, fmt.Errorf("no traffic source available")
 	}
 
-	return source.GetFlows(ctx, opts)
+	resp, err := source.GetFlows(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	// Sources filter upstream where they can; this enforces the direction
+	// filter for any that over-match
+	resp.Flows = opts.FilterFlows(resp.Flows)
+	m.resolver.EnrichFlows(ctx, resp.Flows)
+	return resp, nil
 }
 
 // StreamFlows returns a channel of flows from the active source
@@ -388,7 +408,28 @@ func (m *Manager) StreamFlows(ctx context.Context, opts FlowOptions) (<-chan Flo
 		return nil, fmt.Errorf("no traffic source available")
 	}
 
-	return source.StreamFlows(ctx, opts)
+	in, err := source.StreamFlows(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Label external endpoints as flows pass through (cached DNS only, no blocking lookups)
+	out := make(chan Flow, cap(in))
+	go func() {
+		defer close(out)
+		for flow := range in {
+			if !opts.Matches(flow) {
+				continue
+			}
+			m.resolver.EnrichFlow(ctx, &flow)
+			select {
+			case out <- flow:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
 }
 
 // SetActiveSource sets the active traffic source by name
@@ -416,10 +457,28 @@ func (m *Manager) GetActiveSourceName() string {
 	return m.activeSource.Name()
 }
 
+// Edge health thresholds for L7 metrics
+const (
+	errorRateDegraded  = 0.01 // 1% 5xx
+	errorRateUnhealthy = 0.05 // 5% 5xx
+	latencyDegradedMs  = 500
+	latencyUnhealthyMs = 2000
+)
+
 // AggregateFlows aggregates flows by service pair
 func AggregateFlows(flows []Flow) []AggregatedFlow {
+	return AggregateFlowsOverWindow(flows, 0)
+}
+
+// AggregateFlowsOverWindow aggregates flows by service pair and computes L7
+// request rate, 5xx ratio, and p95 latency. window is the look-back period the
+// flows cover; it's used to derive request rates from observed HTTP responses
+// (0 = don't derive rates).
+func AggregateFlowsOverWindow(flows []Flow, window time.Duration) []AggregatedFlow {
 	// Key: source-ns/source-name|dest-ns/dest-name|port
 	aggregated := make(map[string]*AggregatedFlow)
+	latencies := make(map[string][]float64)
+	var order []string
 
 	for _, f := range flows {
 		key := fmt.Sprintf("%s/%s|%s/%s|%d",
@@ -427,7 +486,8 @@ func AggregateFlows(flows []Flow) []AggregatedFlow {
 			f.Destination.Namespace, f.Destination.Name,
 			f.Port)
 
-		if agg, ok := aggregated[key]; ok {
+		agg, ok := aggregated[key]
+		if ok {
 			agg.FlowCount++
 			agg.BytesSent += f.BytesSent
 			agg.BytesRecv += f.BytesRecv
@@ -436,7 +496,7 @@ func AggregateFlows(flows []Flow) []AggregatedFlow {
 				agg.LastSeen = f.LastSeen
 			}
 		} else {
-			aggregated[key] = &AggregatedFlow{
+			agg = &AggregatedFlow{
 				Source:      f.Source,
 				Destination: f.Destination,
 				Protocol:    f.Protocol,
@@ -447,16 +507,76 @@ func AggregateFlows(flows []Flow) []AggregatedFlow {
 				Connections: f.Connections,
 				LastSeen:    f.LastSeen,
 			}
+			aggregated[key] = agg
+			order = append(order, key)
+		}
+
+		// Individual HTTP responses observed by the source (Hubble L7)
+		if f.L7Protocol == "HTTP" && f.HTTPStatus > 0 {
+			agg.RequestCount++
+			if f.HTTPStatus >= 500 {
+				agg.ErrorCount++
+			}
+			if f.LatencyMs > 0 {
+				latencies[key] = append(latencies[key], f.LatencyMs)
+			}
+		}
+
+		// Pre-aggregated metrics from a metrics backend: rates add up,
+		// error ratio is request-weighted, p95 takes the worst
+		if f.RequestRate > 0 {
+			prevRate := agg.RequestRate
+			agg.RequestRate += f.RequestRate
+			agg.ErrorRate = (agg.ErrorRate*prevRate + f.ErrorRate*f.RequestRate) / agg.RequestRate
+		}
+		if f.P95LatencyMs > agg.P95LatencyMs {
+			agg.P95LatencyMs = f.P95LatencyMs
 		}
 	}
 
 	result := make([]AggregatedFlow, 0, len(aggregated))
-	for _, agg := range aggregated {
+	for _, key := range order {
+		agg := aggregated[key]
+		if agg.RequestCount > 0 {
+			if agg.RequestRate == 0 {
+				agg.ErrorRate = float64(agg.ErrorCount) / float64(agg.RequestCount)
+				if window > 0 {
+					agg.RequestRate = float64(agg.RequestCount) / window.Seconds()
+				}
+			}
+			if l := latencies[key]; len(l) > 0 {
+				var sum float64
+				for _, v := range l {
+					sum += v
+				}
+				agg.AvgLatencyMs = sum / float64(len(l))
+				if agg.P95LatencyMs == 0 {
+					agg.P95LatencyMs = k8s.Percentile(l, 95)
+				}
+			}
+		}
+		agg.Health = edgeHealth(agg)
 		result = append(result, *agg)
 	}
 	return result
 }
 
+// edgeHealth classifies an aggregated flow for edge coloring. Returns "" when
+// the flow has no L7 data.
+func edgeHealth(agg *AggregatedFlow) string {
+	if agg.RequestCount == 0 && agg.RequestRate == 0 && agg.P95LatencyMs == 0 {
+		return ""
+	}
+	switch {
+	case agg.ErrorRate >= errorRateUnhealthy || agg.P95LatencyMs >= latencyUnhealthyMs:
+		return "unhealthy"
+	case agg.ErrorRate >= errorRateDegraded || agg.P95LatencyMs >= latencyDegradedMs:
+		return "degraded"
+	default:
+		return "healthy"
+	}
+}
+
 // Close cleans up all traffic sources
 func (m *Manager) Close() error {
 	m.mu.Lock()
@@ -479,6 +599,7 @@ func (m *Manager) Close() error {
 func Reset() {
 	// Stop any active metrics port-forward first
 	StopMetricsPortForward()
+	stopRecorder()
 
 	if manager != nil {
 		manager.Close()
@@ -529,6 +650,13 @@ func (m *Manager) Connect(ctx context.Context) (*MetricsConnectionInfo, error) {
 	}, nil
 }
 
+// ContextName returns the K8s context this manager is bound to
+func (m *Manager) ContextName() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.contextName
+}
+
 // GetConnectionInfo returns current connection status
 func (m *Manager) GetConnectionInfo() *MetricsConnectionInfo {
 	return GetConnectionInfo()
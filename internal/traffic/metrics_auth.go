@@ -0,0 +1,161 @@
+package traffic
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/skyhook-io/radar/internal/outbound"
+)
+
+// MetricsAuth configures authentication for a Prometheus-compatible metrics endpoint
+type MetricsAuth struct {
+	Username           string            `json:"username,omitempty"`
+	Password           string            `json:"password,omitempty"`
+	BearerToken        string            `json:"bearerToken,omitempty"`
+	BearerTokenFile    string            `json:"bearerTokenFile,omitempty"` // Re-read on every request so rotated tokens are picked up
+	Headers            map[string]string `json:"headers,omitempty"`
+	InsecureSkipVerify bool              `json:"insecureSkipVerify,omitempty"`
+	CAFile             string            `json:"caFile,omitempty"`
+}
+
+// MetricsSettings is the metrics endpoint and credentials for a cluster
+type MetricsSettings struct {
+	URL  string      `json:"url,omitempty"` // Manual URL, skips auto-discovery
+	Auth MetricsAuth `json:"auth,omitempty"`
+}
+
+// MetricsConfig holds default metrics settings plus per-context overrides
+type MetricsConfig struct {
+	MetricsSettings
+	Contexts map[string]MetricsSettings `json:"contexts,omitempty"`
+}
+
+// IsZero reports whether no auth is configured
+func (a MetricsAuth) IsZero() bool {
+	return a.Username == "" && a.Password == "" && a.BearerToken == "" && a.BearerTokenFile == "" &&
+		len(a.Headers) == 0 && !a.InsecureSkipVerify && a.CAFile == ""
+}
+
+// Validate checks that at most one credential type is set and files exist
+func (a MetricsAuth) Validate() error {
+	if a.Password != "" && a.Username == "" {
+		return fmt.Errorf("password set without a username")
+	}
+	if a.Username != "" && (a.BearerToken != "" || a.BearerTokenFile != "") {
+		return fmt.Errorf("basic auth and bearer token are mutually exclusive")
+	}
+	if a.BearerToken != "" && a.BearerTokenFile != "" {
+		return fmt.Errorf("bearer token and bearer token file are mutually exclusive")
+	}
+	for _, path := range []string{a.BearerTokenFile, a.CAFile} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks the default and per-context settings
+func (c MetricsConfig) Validate() error {
+	if err := c.Auth.Validate(); err != nil {
+		return err
+	}
+	for name, settings := range c.Contexts {
+		if err := settings.Auth.Validate(); err != nil {
+			return fmt.Errorf("context %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ForContext returns the settings for a kubeconfig context. A context entry
+// replaces the default URL and auth individually, so a cluster can override
+// just its credentials and keep auto-discovery.
+func (c MetricsConfig) ForContext(contextName string) MetricsSettings {
+	settings := c.MetricsSettings
+	override, ok := c.Contexts[contextName]
+	if !ok {
+		return settings
+	}
+	if override.URL != "" {
+		settings.URL = override.URL
+	}
+	if !override.Auth.IsZero() {
+		settings.Auth = override.Auth
+	}
+	return settings
+}
+
+// LoadMetricsConfig reads metrics settings from a JSON file
+func LoadMetricsConfig(path string) (MetricsConfig, error) {
+	var cfg MetricsConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// NewMetricsHTTPClient returns an HTTP client that applies auth to every
+// request. Use it for any Prometheus-compatible query so configured
+// credentials are honoured consistently.
+func NewMetricsHTTPClient(auth MetricsAuth, timeout time.Duration) (*http.Client, error) {
+	// Start from the global proxy/CA settings; a metrics CA file adds to the
+	// global CA bundle rather than replacing it
+	transport := outbound.NewTransport()
+	if auth.InsecureSkipVerify || auth.CAFile != "" {
+		tlsConfig := &tls.Config{InsecureSkipVerify: auth.InsecureSkipVerify, RootCAs: outbound.RootCAs()} //nolint:gosec // explicitly requested by the user
+		if auth.CAFile != "" {
+			pool, err := outbound.LoadCAFile(tlsConfig.RootCAs, auth.CAFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = pool
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	var rt http.RoundTripper = transport
+	if auth.Username != "" || auth.BearerToken != "" || auth.BearerTokenFile != "" || len(auth.Headers) > 0 {
+		rt = &metricsAuthTransport{auth: auth, next: transport}
+	}
+	return &http.Client{Timeout: timeout, Transport: rt}, nil
+}
+
+// metricsAuthTransport adds credentials and custom headers to requests
+type metricsAuthTransport struct {
+	auth MetricsAuth
+	next http.RoundTripper
+}
+
+func (t *metricsAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(req.Context())
+	for name, value := range t.auth.Headers {
+		req.Header.Set(name, value)
+	}
+	switch {
+	case t.auth.Username != "":
+		req.SetBasicAuth(t.auth.Username, t.auth.Password)
+	case t.auth.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+t.auth.BearerToken)
+	case t.auth.BearerTokenFile != "":
+		token, err := os.ReadFile(t.auth.BearerTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading bearer token file: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	return t.next.RoundTrip(req)
+}
@@ -3,6 +3,9 @@ package traffic
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -32,12 +35,68 @@ type DetectionResult struct {
 	Message   string `json:"message,omitempty"`
 }
 
+// FlowDirection filters flows relative to the selected namespaces
+type FlowDirection string
+
+const (
+	FlowDirectionBoth    FlowDirection = ""        // Flows with either end in the selection
+	FlowDirectionIngress FlowDirection = "ingress" // Flows whose destination is in the selection
+	FlowDirectionEgress  FlowDirection = "egress"  // Flows whose source is in the selection
+)
+
+// ParseFlowDirection validates a direction query value
+func ParseFlowDirection(s string) (FlowDirection, error) {
+	switch d := FlowDirection(strings.ToLower(s)); d {
+	case FlowDirectionBoth, FlowDirectionIngress, FlowDirectionEgress:
+		return d, nil
+	case "both":
+		return FlowDirectionBoth, nil
+	}
+	return "", fmt.Errorf("invalid direction %q: must be ingress, egress, or both", s)
+}
+
 // FlowOptions contains options for querying flows
 type FlowOptions struct {
-	Namespace string        // Filter by namespace (empty = all)
-	Since     time.Duration // Look back period (default: 5 minutes)
-	Follow    bool          // Stream new flows
-	Limit     int           // Max flows to return (0 = no limit)
+	Namespaces []string      // Filter by namespaces (empty = all)
+	Direction  FlowDirection // Which end of a flow must be in Namespaces
+	Since      time.Duration // Look back period (default: 5 minutes)
+	Follow     bool          // Stream new flows
+	Limit      int           // Max flows to return (0 = no limit)
+}
+
+// matchSource reports whether the source end is filtered by namespace
+func (o FlowOptions) matchSource() bool {
+	return o.Direction == FlowDirectionBoth || o.Direction == FlowDirectionEgress
+}
+
+// matchDestination reports whether the destination end is filtered by namespace
+func (o FlowOptions) matchDestination() bool {
+	return o.Direction == FlowDirectionBoth || o.Direction == FlowDirectionIngress
+}
+
+// Matches reports whether a flow passes the namespace and direction filter
+func (o FlowOptions) Matches(f Flow) bool {
+	if len(o.Namespaces) == 0 {
+		return true
+	}
+	if o.matchSource() && slices.Contains(o.Namespaces, f.Source.Namespace) {
+		return true
+	}
+	return o.matchDestination() && slices.Contains(o.Namespaces, f.Destination.Namespace)
+}
+
+// FilterFlows returns the flows that pass the namespace and direction filter
+func (o FlowOptions) FilterFlows(flows []Flow) []Flow {
+	if len(o.Namespaces) == 0 {
+		return flows
+	}
+	filtered := make([]Flow, 0, len(flows))
+	for _, f := range flows {
+		if o.Matches(f) {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
 }
 
 // Flow represents a single network flow between two endpoints
@@ -50,11 +109,18 @@ type Flow struct {
 	HTTPMethod  string    `json:"httpMethod,omitempty"`
 	HTTPPath    string    `json:"httpPath,omitempty"`
 	HTTPStatus  int       `json:"httpStatus,omitempty"`
+	LatencyMs   float64   `json:"latencyMs,omitempty"` // Per-request latency (Hubble L7 responses)
 	BytesSent   int64     `json:"bytesSent"`
 	BytesRecv   int64     `json:"bytesRecv"`
 	Connections int64     `json:"connections"`
 	Verdict     string    `json:"verdict"` // forwarded, dropped, error
 	LastSeen    time.Time `json:"lastSeen"`
+
+	// Pre-aggregated L7 metrics from a metrics backend (e.g. Istio metrics in Prometheus).
+	// Zero when the source only observes L4 connections.
+	RequestRate  float64 `json:"requestRate,omitempty"`  // Requests per second
+	ErrorRate    float64 `json:"errorRate,omitempty"`    // Fraction of requests with HTTP 5xx (0-1)
+	P95LatencyMs float64 `json:"p95LatencyMs,omitempty"` // 95th percentile request latency
 }
 
 // Endpoint represents a source or destination in a flow
@@ -66,6 +132,11 @@ type Endpoint struct {
 	Labels    map[string]string `json:"labels,omitempty"`   // K8s labels
 	Workload  string            `json:"workload,omitempty"` // Parent workload name (Deployment, etc.)
 	Port      int               `json:"port,omitempty"`     // Port number
+
+	// External endpoint enrichment (see EndpointResolver)
+	DisplayName string `json:"displayName,omitempty"` // Friendly label, e.g. "AWS S3", "node/ip-10-0-1-5"
+	Hostname    string `json:"hostname,omitempty"`    // Reverse DNS hostname
+	Category    string `json:"category,omitempty"`    // node, pod, service, cloud, private, internet
 }
 
 // FlowsResponse contains the flows and metadata
@@ -91,6 +162,12 @@ type AggregatedFlow struct {
 	RequestCount int64   `json:"requestCount,omitempty"`
 	ErrorCount   int64   `json:"errorCount,omitempty"`
 	AvgLatencyMs float64 `json:"avgLatencyMs,omitempty"`
+	RequestRate  float64 `json:"requestRate,omitempty"`  // Requests per second
+	ErrorRate    float64 `json:"errorRate,omitempty"`    // Fraction of requests with HTTP 5xx (0-1)
+	P95LatencyMs float64 `json:"p95LatencyMs,omitempty"` // 95th percentile request latency
+	// Health summarizes L7 metrics for edge coloring: healthy, degraded, unhealthy.
+	// Empty when no L7 data is available (edges fall back to connection counts).
+	Health string `json:"health,omitempty"`
 }
 
 // ClusterInfo contains cluster platform and CNI information
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/skyhook-io/radar/internal/outbound"
 	"github.com/skyhook-io/radar/internal/version"
 )
 
@@ -21,6 +22,7 @@ const (
 	StateReady       State = "ready"
 	StateApplying    State = "applying"
 	StateError       State = "error"
+	StateOffline     State = "offline" // Updates disabled by --offline
 )
 
 // Status contains the current update status for API consumers.
@@ -50,6 +52,10 @@ func New() *Updater {
 
 // Status returns the current update status.
 func (u *Updater) Status() Status {
+	if outbound.Offline() {
+		return Status{State: StateOffline}
+	}
+
 	u.mu.Lock()
 	defer u.mu.Unlock()
 
@@ -67,6 +73,9 @@ func (u *Updater) Status() Status {
 // StartDownload begins an async download of the latest desktop release.
 // Returns an error immediately if a download is already in progress or an update is being applied.
 func (u *Updater) StartDownload(parentCtx context.Context) error {
+	if outbound.Offline() {
+		return outbound.ErrOffline
+	}
 	u.mu.Lock()
 	if u.state != StateIdle && u.state != StateError {
 		st := u.state
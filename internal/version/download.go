@@ -15,6 +15,8 @@ import (
 	"runtime"
 	"strings"
 	"time"
+
+	"github.com/skyhook-io/radar/internal/outbound"
 )
 
 // githubAsset represents a single file attached to a GitHub release.
@@ -34,7 +36,10 @@ type githubReleaseWithAssets struct {
 
 // FetchRelease fetches the latest release from GitHub including assets.
 func FetchRelease(ctx context.Context) (*githubReleaseWithAssets, error) {
-	client := &http.Client{Timeout: 15 * time.Second}
+	if outbound.Offline() {
+		return nil, outbound.ErrOffline
+	}
+	client := outbound.NewClient(15 * time.Second)
 	req, err := http.NewRequestWithContext(ctx, "GET",
 		"https://api.github.com/repos/skyhook-io/radar/releases/latest", nil)
 	if err != nil {
@@ -91,7 +96,7 @@ func DownloadAsset(ctx context.Context, url string, dest string, progress Progre
 		return fmt.Errorf("create download dir: %w", err)
 	}
 
-	client := &http.Client{Timeout: 30 * time.Minute}
+	client := outbound.NewClient(30 * time.Minute)
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return fmt.Errorf("create request: %w", err)
@@ -168,7 +173,7 @@ func VerifyChecksum(ctx context.Context, release *githubReleaseWithAssets, asset
 	}
 
 	// Download checksums
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := outbound.NewClient(30 * time.Second)
 	req, err := http.NewRequestWithContext(ctx, "GET", checksumURL, nil)
 	if err != nil {
 		return fmt.Errorf("create checksum request: %w", err)
@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/Masterminds/semver/v3"
+
+	"github.com/skyhook-io/radar/internal/outbound"
 )
 
 var (
@@ -50,6 +52,7 @@ type UpdateInfo struct {
 	ReleaseNotes   string        `json:"releaseNotes,omitempty"`
 	InstallMethod  InstallMethod `json:"installMethod"`
 	UpdateCommand  string        `json:"updateCommand,omitempty"`
+	Offline        bool          `json:"offline,omitempty"` // Not checked: running with --offline
 	Error          string        `json:"error,omitempty"`
 }
 
@@ -79,6 +82,11 @@ func IsDesktop() bool {
 
 // CheckForUpdate checks GitHub for the latest release
 func CheckForUpdate(_ context.Context) *UpdateInfo {
+	if outbound.Offline() {
+		method := detectInstallMethod()
+		return &UpdateInfo{CurrentVersion: Current, InstallMethod: method, Offline: true}
+	}
+
 	mu.Lock()
 
 	// Use shorter TTL for cached errors so transient failures recover quickly
@@ -121,7 +129,7 @@ func fetchLatestRelease(ctx context.Context) *UpdateInfo {
 		return result
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := outbound.NewClient(10 * time.Second)
 	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.github.com/repos/skyhook-io/radar/releases/latest", nil)
 	if err != nil {
 		result.Error = fmt.Sprintf("failed to create request: %v", err)
@@ -1,7 +1,11 @@
 package version
 
 import (
+	"context"
+	"errors"
 	"testing"
+
+	"github.com/skyhook-io/radar/internal/outbound"
 )
 
 func TestIsNewerVersion(t *testing.T) {
@@ -114,3 +118,16 @@ func TestTruncateNotes(t *testing.T) {
 		})
 	}
 }
+
+func TestOfflineSkipsChecks(t *testing.T) {
+	outbound.SetOffline(true)
+	t.Cleanup(func() { outbound.SetOffline(false) })
+
+	info := CheckForUpdate(context.Background())
+	if !info.Offline || info.UpdateAvail || info.Error != "" {
+		t.Errorf("expected an offline status without an error, got %+v", info)
+	}
+	if _, err := FetchRelease(context.Background()); !errors.Is(err, outbound.ErrOffline) {
+		t.Errorf("expected FetchRelease to refuse in offline mode, got %v", err)
+	}
+}
@@ -23,8 +23,10 @@ import { CapabilitiesProvider } from './contexts/CapabilitiesContext'
 import { ErrorBoundary } from './components/ui/ErrorBoundary'
 import { NamespaceSelector } from './components/ui/NamespaceSelector'
 import { UpdateNotification } from './components/ui/UpdateNotification'
+import { CredentialExpiryBanner } from './components/ui/CredentialExpiryBanner'
+import { ConnectionFlappingBanner } from './components/ui/ConnectionFlappingBanner'
 import { useEventSource } from './hooks/useEventSource'
-import { useNamespaces } from './api/client'
+import { useNamespaces, fetchExecSessions } from './api/client'
 import { Loader2 } from 'lucide-react'
 import { RefreshCw, FolderTree, Network, List, Clock, Package, Sun, Moon, Activity, Home } from 'lucide-react'
 import { useTheme } from './context/ThemeContext'
@@ -36,7 +38,7 @@ import { kindToPlural } from './utils/navigation'
 const ALL_NODE_KINDS: NodeKind[] = [
   'Internet', 'Ingress', 'Gateway', 'HTTPRoute', 'GRPCRoute', 'TCPRoute', 'TLSRoute',
   'Service', 'Deployment', 'Rollout', 'DaemonSet', 'StatefulSet',
-  'ReplicaSet', 'Pod', 'PodGroup', 'ConfigMap', 'Secret', 'HorizontalPodAutoscaler', 'Job', 'CronJob', 'PersistentVolumeClaim', 'Namespace',
+  'ReplicaSet', 'Pod', 'PodGroup', 'ConfigMap', 'Secret', 'HorizontalPodAutoscaler', 'ScaledObject', 'ScaledJob', 'Job', 'CronJob', 'PersistentVolumeClaim', 'Namespace',
   'Application', 'Kustomization', 'HelmRelease', 'GitRepository'
 ]
 
@@ -61,6 +63,8 @@ function apiResourceToNodeIdPrefix(apiResource: string): string {
     'configmaps': 'configmap',
     'secrets': 'secret',
     'horizontalpodautoscalers': 'horizontalpodautoscaler',
+    'scaledobjects': 'scaledobject',
+    'scaledjobs': 'scaledjob',
     'jobs': 'job',
     'cronjobs': 'cronjob',
     'persistentvolumeclaims': 'persistentvolumeclaim',
@@ -569,6 +573,9 @@ function AppInner() {
         </div>
       )}
 
+      {!isSwitching && connection.state === 'connected' && <CredentialExpiryBanner />}
+      {!isSwitching && connection.state === 'connected' && connection.flapping && <ConnectionFlappingBanner />}
+
       {/* Main content - only show when connected */}
       {!isSwitching && connection.state === 'connected' && <div className="flex-1 flex overflow-hidden">
         <ErrorBoundary>
@@ -782,6 +789,38 @@ function AppInner() {
   )
 }
 
+// Opens a read-only terminal for ?observe=<exec session id> links from a
+// shared terminal, then drops the param
+function ObserveLinkHandler() {
+  const [searchParams, setSearchParams] = useSearchParams()
+  const { addTab } = useDock()
+  const sessionId = searchParams.get('observe')
+
+  useEffect(() => {
+    if (!sessionId) return
+    fetchExecSessions()
+      .then((sessions) => {
+        const session = sessions.find((s) => s.id === sessionId)
+        addTab({
+          type: 'terminal',
+          title: session ? `${session.pod} (watching)` : `${sessionId} (watching)`,
+          namespace: session?.namespace ?? '',
+          podName: session?.pod ?? sessionId,
+          containerName: session?.container ?? '',
+          containers: [],
+          observeSessionId: sessionId,
+        })
+      })
+      .catch(() => {})
+    const params = new URLSearchParams(searchParams)
+    params.delete('observe')
+    setSearchParams(params, { replace: true })
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [sessionId])
+
+  return null
+}
+
 // Spacer component that adds padding when dock is open
 function DockSpacer() {
   const { tabs, isExpanded } = useDock()
@@ -797,6 +836,7 @@ function App() {
         <ContextSwitchProvider>
           <DockProvider>
             <AppInner />
+            <ObserveLinkHandler />
           </DockProvider>
         </ContextSwitchProvider>
       </CapabilitiesProvider>
@@ -1,4 +1,4 @@
-import { useQuery } from '@tanstack/react-query'
+import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query'
 import type { APIResource } from '../types'
 
 const API_BASE = '/api'
@@ -21,6 +21,29 @@ export function useAPIResources() {
   })
 }
 
+// Start or stop Radar's dynamic informer for a resource (controls dynamic cache contents)
+export function useSetResourceWatch() {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async ({ group, version, resource, watch }: { group: string; version: string; resource: string; watch: boolean }) => {
+      const response = await fetch(`${API_BASE}/api-resources/${watch ? 'watch' : 'unwatch'}`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ group, version, resource }),
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['api-resources'] })
+    },
+  })
+}
+
 // Group resources by category for sidebar display
 export interface ResourceCategory {
   name: string
@@ -1,13 +1,17 @@
 import { useQuery, useMutation, useQueryClient, skipToken } from '@tanstack/react-query'
 import type {
   Topology,
+  HealthStatus,
   ClusterInfo,
   Capabilities,
   ContextInfo,
   Namespace,
   TimelineEvent,
   TimeRange,
+  Severity,
+  FilterPreset,
   ResourceWithRelationships,
+  Relationships,
   HelmRelease,
   HelmReleaseDetail,
   HelmValues,
@@ -15,26 +19,62 @@ import type {
   UpgradeInfo,
   BatchUpgradeInfo,
   ValuesPreviewResponse,
+  ValuesViolation,
+  ValuesValidation,
+  HelmValuesSchema,
   HelmRepository,
+  HelmRegistryLogins,
+  HelmRegistryLoginRequest,
   ChartSearchResult,
   ChartDetail,
   InstallChartRequest,
   ArtifactHubSearchResult,
   ArtifactHubChartDetail,
+  ResourceSummaryList,
+  FieldChange,
 } from '../types'
 import type { GitOpsOperationResponse } from '../types/gitops'
 
 const API_BASE = '/api'
 
-// ApiError preserves HTTP status code for callers to distinguish 403/404/500 etc.
+// Machine-readable error codes sent with every API error (see internal/apierror)
+export type ApiErrorCode =
+  | 'BAD_REQUEST'
+  | 'UNAUTHORIZED'
+  | 'RBAC_FORBIDDEN'
+  | 'PERMISSION_DENIED'
+  | 'NOT_FOUND'
+  | 'METHOD_NOT_ALLOWED'
+  | 'CONFLICT'
+  | 'PRECONDITION_FAILED'
+  | 'PAYLOAD_TOO_LARGE'
+  | 'UNSUPPORTED_MEDIA_TYPE'
+  | 'VALIDATION_FAILED'
+  | 'RATE_LIMITED'
+  | 'INTERNAL'
+  | 'NOT_IMPLEMENTED'
+  | 'UPSTREAM_ERROR'
+  | 'UNAVAILABLE'
+  | 'NOT_CONNECTED'
+  | 'OFFLINE'
+  | 'READ_ONLY'
+  | 'TIMEOUT'
+  | 'CROSS_ORIGIN'
+
+// ApiError preserves HTTP status code for callers to distinguish 403/404/500 etc.,
+// plus the structured error code and remediation hint when the server sent them
 export class ApiError extends Error {
   status: number
   data?: Record<string, unknown>
+  code?: ApiErrorCode
+  hint?: string
   constructor(message: string, status: number, data?: Record<string, unknown>) {
     super(message)
     this.name = 'ApiError'
     this.status = status
     this.data = data
+    if (typeof data?.code === 'string') this.code = data.code as ApiErrorCode
+    if (typeof data?.hint === 'string') this.hint = data.hint
   }
 }
 
@@ -42,6 +82,10 @@ export function isForbiddenError(error: unknown): boolean {
   return error instanceof ApiError && error.status === 403
 }
 
+export function isNotConnectedError(error: unknown): boolean {
+  return error instanceof ApiError && error.code === 'NOT_CONNECTED'
+}
+
 async function fetchJSON<T>(path: string): Promise<T> {
   const response = await fetch(`${API_BASE}${path}`)
   if (!response.ok) {
@@ -125,7 +169,8 @@ export interface DashboardEvent {
   message: string
   involvedObject: string
   namespace: string
-  timestamp: string
+  timestamp: string // RFC3339 in the browser's time zone
+  timestampUnix: number
 }
 
 export interface DashboardChange {
@@ -134,7 +179,8 @@ export interface DashboardChange {
   name: string
   changeType: string
   summary: string
-  timestamp: string
+  timestamp: string // RFC3339 in the browser's time zone
+  timestampUnix: number
 }
 
 export interface DashboardTopologySummary {
@@ -188,14 +234,43 @@ export interface DashboardResponse {
   trafficSummary: DashboardTrafficSummary | null
   helmReleases: DashboardHelmSummary
   metrics: DashboardMetrics | null
+  recommendations: DashboardRecommendations | null
+  placementWarnings: DashboardProblem[] // Anti-affinity/spread violations, replicas on one node or zone
+  sections?: Record<string, DashboardSectionStatus>
+}
+
+export interface DashboardSectionStatus {
+  durationMs: number
+  timedOut?: boolean // Section data left at its empty default
+}
+
+export interface DashboardRecommendations {
+  workloads: number
+  cpuSavingsCores: number
+  memorySavingsGiB: number
+  top: DashboardRecommendation[]
+}
+
+export interface DashboardRecommendation {
+  kind: string
+  namespace: string
+  name: string
+  cpuSavingsCores: number
+  memorySavingsGiB: number
 }
 
 export interface DashboardCRDsResponse {
   topCRDs: DashboardCRDCount[]
 }
 
+// Server-formatted timestamps are rendered in the browser's time zone
+const browserTimeZone = Intl.DateTimeFormat().resolvedOptions().timeZone
+
 export function useDashboard(namespaces: string[] = []) {
-  const params = namespaces.length > 0 ? `?namespaces=${namespaces.join(',')}` : ''
+  const search = new URLSearchParams()
+  if (namespaces.length > 0) search.set('namespaces', namespaces.join(','))
+  if (browserTimeZone) search.set('tz', browserTimeZone)
+  const params = search.toString() ? `?${search}` : ''
   return useQuery<DashboardResponse>({
     queryKey: ['dashboard', namespaces],
     queryFn: () => fetchJSON(`/dashboard${params}`),
@@ -215,63 +290,145 @@ export function useDashboardCRDs(namespaces: string[] = []) {
   })
 }
 
-// Cluster info
-export function useClusterInfo() {
-  const query = useQuery<ClusterInfo>({
-    queryKey: ['cluster-info'],
-    queryFn: () => fetchJSON('/cluster-info'),
-    staleTime: 60000, // 1 minute
-    // Poll faster when CRD discovery is in progress
-    refetchInterval: (query) => {
-      const status = query.state.data?.crdDiscoveryStatus
-      return status === 'discovering' ? 2000 : false
-    },
+// Policy violations (Gatekeeper constraints, Kyverno policy reports)
+export interface PolicyViolation {
+  kind: string
+  namespace?: string
+  name: string
+  rule?: string
+  message: string
+  result?: 'fail' | 'warn' | 'error'
+  severity?: string
+}
+
+export interface PolicyViolationGroup {
+  engine: 'Gatekeeper' | 'Kyverno'
+  policyKind: string
+  policy: string
+  action?: string
+  enforced: boolean
+  total: number
+  namespaces: Record<string, number>
+  violations: PolicyViolation[]
+}
+
+export interface PolicyViolationsSummary {
+  engines: string[]
+  total: number
+  byPolicy: PolicyViolationGroup[]
+  byNamespace: Array<{ namespace: string; count: number }>
+}
+
+export function usePolicyViolations(namespaces: string[] = []) {
+  const params = namespaces.length > 0 ? `?namespaces=${namespaces.join(',')}` : ''
+  return useQuery<PolicyViolationsSummary>({
+    queryKey: ['policy-violations', namespaces],
+    queryFn: () => fetchJSON(`/policy-violations${params}`),
+    staleTime: 30000,
+    refetchInterval: 60000,
   })
-  return query
 }
 
-// Version check
-export type InstallMethod = 'homebrew' | 'krew' | 'scoop' | 'direct' | 'desktop'
+// KEDA ScaledObjects and ScaledJobs
+export interface KEDATrigger {
+  type: string
+  name?: string
+  metadata?: Record<string, string>
+  metricType?: string
+  authRef?: string
+  healthStatus?: string
+  healthFailures?: number
+}
 
-export interface VersionInfo {
-  currentVersion: string
-  latestVersion?: string
-  updateAvailable: boolean
-  releaseUrl?: string
-  releaseNotes?: string
-  installMethod: InstallMethod
-  updateCommand?: string
-  error?: string
+export interface KEDAScaler {
+  kind: 'ScaledObject' | 'ScaledJob'
+  namespace: string
+  name: string
+  targetKind: string
+  targetName?: string
+  minReplicas: number
+  maxReplicas: number
+  current?: number
+  active: boolean
+  ready: boolean
+  paused: boolean
+  fallback?: boolean
+  hpaName?: string
+  message?: string
+  triggers: KEDATrigger[]
 }
 
-export function useVersionCheck() {
-  return useQuery<VersionInfo>({
-    queryKey: ['version-check'],
-    queryFn: () => fetchJSON('/version-check'),
-    staleTime: 60 * 60 * 1000, // 1 hour
-    retry: false, // Don't retry on failure
+export interface KEDAScalersResponse {
+  installed: boolean
+  scalers: KEDAScaler[]
+}
+
+export function useKEDAScalers(namespaces: string[] = []) {
+  const params = namespaces.length > 0 ? `?namespaces=${namespaces.join(',')}` : ''
+  return useQuery<KEDAScalersResponse>({
+    queryKey: ['keda-scalers', namespaces],
+    queryFn: () => fetchJSON(`/keda/scalers${params}`),
+    staleTime: 15000,
+    refetchInterval: 30000,
   })
 }
 
-// ============================================================================
-// Desktop Update API hooks
-// ============================================================================
+// Cluster API fleet (management cluster view of workload clusters)
+export interface CAPIMachineDeployment {
+  name: string
+  phase?: string
+  version?: string
+  replicas: number
+  readyReplicas: number
+  updatedReplicas: number
+}
 
-export type DesktopUpdateState = 'idle' | 'downloading' | 'ready' | 'applying' | 'error'
+export interface CAPIMachine {
+  name: string
+  phase?: string
+  version?: string
+  nodeName?: string
+  providerID?: string
+  controlPlane: boolean
+  deployment?: string
+}
 
-export interface DesktopUpdateStatus {
-  state: DesktopUpdateState
-  progress?: number // 0.0 - 1.0 during download
+export interface CAPICluster {
+  namespace: string
+  name: string
+  phase?: string
   version?: string
-  error?: string
+  className?: string
+  paused?: boolean
+  controlPlaneReady: boolean
+  infrastructureReady: boolean
+  controlPlaneKind?: string
+  infrastructureKind?: string
+  message?: string
+  machineDeployments: CAPIMachineDeployment[]
+  machines: CAPIMachine[]
 }
 
-export function useStartDesktopUpdate() {
-  return useMutation({
-    mutationFn: async () => {
-      const response = await fetch(`${API_BASE}/desktop/update`, {
-        method: 'POST',
-      })
+export interface CAPIClustersResponse {
+  installed: boolean
+  clusters: CAPICluster[]
+}
+
+export function useCAPIClusters(namespaces: string[] = []) {
+  const params = namespaces.length > 0 ? `?namespaces=${namespaces.join(',')}` : ''
+  return useQuery<CAPIClustersResponse>({
+    queryKey: ['capi-clusters', namespaces],
+    queryFn: () => fetchJSON(`/capi/clusters${params}`),
+    staleTime: 15000,
+    refetchInterval: 30000,
+  })
+}
+
+// Registers a workload cluster's kubeconfig as a context; switch to it with useSwitchContext
+export function useCAPIClusterContext() {
+  return useMutation<{ context: string }, Error, { namespace: string; name: string }>({
+    mutationFn: async ({ namespace, name }) => {
+      const response = await fetch(`${API_BASE}/capi/clusters/${namespace}/${name}/context`, { method: 'POST' })
       if (!response.ok) {
         const error = await response.json().catch(() => ({ error: 'Unknown error' }))
         throw new Error(error.error || `HTTP ${response.status}`)
@@ -279,27 +436,40 @@ export function useStartDesktopUpdate() {
       return response.json()
     },
     meta: {
-      errorMessage: 'Failed to start update',
+      errorMessage: 'Failed to load cluster kubeconfig',
     },
   })
 }
 
-export function useDesktopUpdateStatus(enabled: boolean) {
-  return useQuery<DesktopUpdateStatus>({
-    queryKey: ['desktop-update-status'],
-    queryFn: () => fetchJSON('/desktop/update/status'),
-    enabled,
-    refetchInterval: 500, // Poll every 500ms during active update
-    staleTime: 0, // Always refetch
+// vcluster instances running in this cluster
+export interface VCluster {
+  namespace: string
+  name: string
+  workloadKind: 'StatefulSet' | 'Deployment'
+  version?: string
+  distro?: string
+  replicas: number
+  readyReplicas: number
+  ready: boolean
+  context: string
+  connected: boolean
+}
+
+export function useVClusters(namespaces: string[] = []) {
+  const params = namespaces.length > 0 ? `?namespaces=${namespaces.join(',')}` : ''
+  return useQuery<{ vclusters: VCluster[] }>({
+    queryKey: ['vclusters', namespaces],
+    queryFn: () => fetchJSON(`/vclusters${params}`),
+    staleTime: 15000,
   })
 }
 
-export function useApplyDesktopUpdate() {
-  return useMutation({
-    mutationFn: async () => {
-      const response = await fetch(`${API_BASE}/desktop/update/apply`, {
-        method: 'POST',
-      })
+// Starts a tunnel to the vcluster and registers its context; switch to it with useSwitchContext
+export function useConnectVCluster() {
+  const queryClient = useQueryClient()
+  return useMutation<{ context: string; localPort: number; podName: string }, Error, { namespace: string; name: string }>({
+    mutationFn: async ({ namespace, name }) => {
+      const response = await fetch(`${API_BASE}/vclusters/${namespace}/${name}/connect`, { method: 'POST' })
       if (!response.ok) {
         const error = await response.json().catch(() => ({ error: 'Unknown error' }))
         throw new Error(error.error || `HTTP ${response.status}`)
@@ -307,127 +477,990 @@ export function useApplyDesktopUpdate() {
       return response.json()
     },
     meta: {
-      errorMessage: 'Failed to apply update',
-      successMessage: 'Update applied — restarting...',
+      errorMessage: 'Failed to connect to vcluster',
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['vclusters'] })
+      queryClient.invalidateQueries({ queryKey: ['contexts'] })
     },
   })
 }
 
-// Runtime stats for debug overlay
-export interface RuntimeStats {
-  heapMB: number
-  heapObjectsK: number
-  goroutines: number
-  uptimeSeconds: number
-  typedInformers?: number
-  dynamicInformers?: number
+export function useDisconnectVCluster() {
+  const queryClient = useQueryClient()
+  return useMutation<{ status: string }, Error, { namespace: string; name: string }>({
+    mutationFn: async ({ namespace, name }) => {
+      const response = await fetch(`${API_BASE}/vclusters/${namespace}/${name}/connect`, { method: 'DELETE' })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Failed to disconnect vcluster',
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['vclusters'] })
+      queryClient.invalidateQueries({ queryKey: ['contexts'] })
+    },
+  })
 }
 
-export interface HealthResponse {
-  status: string
-  resourceCount: number
-  runtime: RuntimeStats
+// Local development clusters (kind, minikube, k3d)
+export type LocalClusterTool = 'kind' | 'minikube' | 'k3d'
+export type LocalClusterAction = 'create' | 'start' | 'stop' | 'delete'
+
+export interface LocalClusterOperation {
+  action: LocalClusterAction
+  startedAt: string
+  finishedAt?: string
+  error?: string
 }
 
-export function useRuntimeStats(enabled: boolean = true) {
-  return useQuery<HealthResponse>({
-    queryKey: ['health'],
-    queryFn: () => fetchJSON('/health'),
-    staleTime: 2000, // 2 seconds
-    refetchInterval: enabled ? 3000 : false, // Refresh every 3 seconds when enabled
-    enabled,
-  })
+export interface LocalCluster {
+  tool: LocalClusterTool
+  name: string
+  context: string
+  status: 'running' | 'stopped' | 'unknown'
+  isCurrent: boolean
+  operation?: LocalClusterOperation
 }
 
-// Capabilities (RBAC-based feature flags)
-export function useCapabilities() {
-  return useQuery<Capabilities>({
-    queryKey: ['capabilities'],
-    queryFn: () => fetchJSON('/capabilities'),
-    staleTime: 60000, // 1 minute - cached on backend too
+export interface LocalClustersResponse {
+  tools: { tool: LocalClusterTool; available: boolean; path?: string }[]
+  clusters: LocalCluster[]
+  errors?: Partial<Record<LocalClusterTool, string>>
+}
+
+// Polls while an operation is running; operations run in the background on the server
+export function useLocalClusters() {
+  return useQuery<LocalClustersResponse>({
+    queryKey: ['local-clusters'],
+    queryFn: () => fetchJSON('/local-clusters'),
+    staleTime: 10000,
+    refetchInterval: (query) =>
+      query.state.data?.clusters.some((c) => c.operation && !c.operation.finishedAt) ? 3000 : false,
   })
 }
 
-// Namespaces
-export function useNamespaces() {
-  return useQuery<Namespace[]>({
-    queryKey: ['namespaces'],
-    queryFn: () => fetchJSON('/namespaces'),
-    staleTime: 30000, // 30 seconds
+export function useLocalClusterAction() {
+  const queryClient = useQueryClient()
+  return useMutation<LocalCluster, Error, { tool: LocalClusterTool; name: string; action: LocalClusterAction }>({
+    mutationFn: async ({ tool, name, action }) => {
+      let response: Response
+      if (action === 'create') {
+        response = await fetch(`${API_BASE}/local-clusters`, {
+          method: 'POST',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify({ tool, name }),
+        })
+      } else if (action === 'delete') {
+        response = await fetch(`${API_BASE}/local-clusters/${tool}/${name}`, { method: 'DELETE' })
+      } else {
+        response = await fetch(`${API_BASE}/local-clusters/${tool}/${name}/${action}`, { method: 'POST' })
+      }
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new ApiError(error.error || `HTTP ${response.status}`, response.status, error)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Local cluster action failed',
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['local-clusters'] })
+    },
   })
 }
 
-// Topology (for manual refresh)
-export function useTopology(namespaces: string[], viewMode: string = 'resources') {
-  const params = new URLSearchParams()
-  if (namespaces.length > 0) params.set('namespaces', namespaces.join(','))
-  if (viewMode) params.set('view', viewMode)
-  const queryString = params.toString()
+// Autoscaler scaling decisions (cluster-autoscaler status ConfigMap + Karpenter)
+export interface AutoscalerNodeGroupStatus {
+  name: string
+  health: string
+  scaleUp: string
+  scaleDown: string
+  ready: number
+  registered: number
+  target: number
+  minSize: number
+  maxSize: number
+  candidates: number
+}
 
-  return useQuery<Topology>({
-    queryKey: ['topology', namespaces, viewMode],
-    queryFn: () => fetchJSON(`/topology${queryString ? `?${queryString}` : ''}`),
-    staleTime: 5000, // 5 seconds
-  })
+export interface ClusterAutoscalerStatus {
+  namespace: string
+  updatedAt?: string
+  health: string
+  scaleUp: string
+  scaleDown: string
+  scaleDownCandidates: number
+  nodeGroups: AutoscalerNodeGroupStatus[]
+  pendingScaleUps: string[]
 }
 
-// Generic resource fetching - returns resource with relationships
-// Uses '_' as placeholder for cluster-scoped resources (empty namespace)
-export function useResource<T>(kind: string, namespace: string, name: string, group?: string) {
-  // For cluster-scoped resources, use '_' as namespace placeholder
-  const ns = namespace || '_'
-  const params = new URLSearchParams()
-  if (group) params.set('group', group)
-  const queryString = params.toString()
+export interface KarpenterNodeClaim {
+  name: string
+  nodePool?: string
+  phase: 'Launching' | 'Registering' | 'Initializing' | 'Ready' | 'Deleting'
+  instanceType?: string
+  capacityType?: string
+  zone?: string
+  nodeName?: string
+  message?: string
+  createdAt: string
+  launchedAt?: string
+  registeredAt?: string
+  readyAt?: string
+}
 
-  const query = useQuery<ResourceWithRelationships<T>>({
-    queryKey: ['resource', kind, namespace, name, group],
-    queryFn: () => fetchJSON(`/resources/${kind}/${ns}/${name}${queryString ? `?${queryString}` : ''}`),
-    enabled: Boolean(kind && name),  // namespace can be empty for cluster-scoped resources
-  })
+export interface PendingPodScaling {
+  namespace: string
+  name: string
+  pendingSince?: string
+  reason: string
+  decision: 'scale-up-triggered' | 'nodeclaim-launching' | 'no-scale-up' | 'waiting'
+  decisionMessage?: string
+  nodeClaim?: string
+}
 
-  // Extract resource and relationships from the response
-  return {
-    ...query,
-    data: query.data?.resource,
-    relationships: query.data?.relationships,
+export interface ScalingDecisions {
+  clusterAutoscaler?: ClusterAutoscalerStatus
+  karpenter?: {
+    pools: { kind: string; name: string; limits?: Record<string, string>; resources?: Record<string, string> }[]
+    nodeClaims: KarpenterNodeClaim[]
   }
+  pendingPods: PendingPodScaling[]
 }
 
-// Hook that returns full response with relationships explicitly
-export function useResourceWithRelationships<T>(kind: string, namespace: string, name: string, group?: string) {
-  const ns = namespace || '_'
-  const params = new URLSearchParams()
-  if (group) params.set('group', group)
-  const queryString = params.toString()
-
-  return useQuery<ResourceWithRelationships<T>>({
-    queryKey: ['resource', kind, namespace, name, group],
-    queryFn: () => fetchJSON(`/resources/${kind}/${ns}/${name}${queryString ? `?${queryString}` : ''}`),
-    enabled: Boolean(kind && name),
+export function useScalingDecisions(namespaces: string[] = []) {
+  const params = namespaces.length > 0 ? `?namespaces=${namespaces.join(',')}` : ''
+  return useQuery<ScalingDecisions>({
+    queryKey: ['autoscaling-decisions', namespaces],
+    queryFn: () => fetchJSON(`/autoscaling/decisions${params}`),
+    staleTime: 10000,
+    refetchInterval: 15000,
   })
 }
 
-// List resources - queryKey includes group for cache sharing with ResourcesView
-export function useResources<T>(kind: string, namespace?: string, group?: string) {
-  const params = new URLSearchParams()
-  if (namespace) params.set('namespace', namespace)
-  if (group) params.set('group', group)
-  const queryString = params.toString()
+// Right-sizing (CPU in millicores, memory in bytes; 0 = unset)
+export interface ContainerRecommendation {
+  container: string
+  samples: number
+  cpuP95: number
+  memoryP99: number
+  memoryMax: number
+  currentCpuRequest: number
+  currentCpuLimit: number
+  currentMemoryRequest: number
+  currentMemoryLimit: number
+  recommendedCpuRequest: number
+  recommendedCpuLimit?: number
+  recommendedMemoryRequest: number
+  recommendedMemoryLimit: number
+}
 
-  return useQuery<T[]>({
-    queryKey: ['resources', kind, group, namespace],
-    queryFn: () => fetchJSON(`/resources/${kind}${queryString ? `?${queryString}` : ''}`),
-    staleTime: 30000, // 30 seconds - matches refetchInterval in ResourcesView
-  })
+export interface WorkloadRecommendation {
+  kind: string
+  namespace: string
+  name: string
+  pods: number
+  containers: ContainerRecommendation[]
+  cpuSavingsCores: number   // Negative when under-provisioned
+  memorySavingsGiB: number
 }
 
-// Timeline changes (unified view of changes + K8s events)
+export interface RecommendationsReport {
+  window: string
+  hasMetrics: boolean
+  workloads: WorkloadRecommendation[]
+  totalCpuSavingsCores: number
+  totalMemorySavingsGiB: number
+}
+
+export function useRecommendations(namespaces: string[] = [], duration: MetricsHistoryDuration = '24h') {
+  const params = new URLSearchParams({ duration })
+  if (namespaces.length > 0) params.set('namespaces', namespaces.join(','))
+  return useQuery<RecommendationsReport>({
+    queryKey: ['recommendations', namespaces, duration],
+    queryFn: () => fetchJSON(`/recommendations?${params}`),
+    staleTime: 60000,
+    refetchInterval: 60000,
+  })
+}
+
+export interface FilesystemUsage {
+  usedBytes: number
+  capacityBytes: number
+  availableBytes: number
+  usedPercent: number
+}
+
+export interface NodeStorageUsage {
+  node: string
+  filesystem?: FilesystemUsage // nodefs: kubelet root, logs, emptyDirs
+  imageFs?: FilesystemUsage    // Container images and writable layers
+  diskPressure: boolean
+  evictedPods: number          // Pods evicted for disk pressure, still present
+  error?: string               // Kubelet summary unavailable for this node
+}
+
+export interface PodStorageUsage {
+  namespace: string
+  name: string
+  node: string
+  usedBytes: number
+  limitBytes?: number          // Sum of container ephemeral-storage limits
+  limitPercent?: number
+  nearLimit: boolean
+}
+
+export interface StorageUsage {
+  nodes: NodeStorageUsage[]
+  pods: PodStorageUsage[]
+}
+
+export function useStorageUsage(namespaces: string[] = []) {
+  const params = new URLSearchParams()
+  if (namespaces.length > 0) params.set('namespaces', namespaces.join(','))
+  return useQuery<StorageUsage>({
+    queryKey: ['storage-ephemeral', namespaces],
+    queryFn: () => fetchJSON(`/storage/ephemeral?${params}`),
+    staleTime: 30000,
+    refetchInterval: 60000,
+  })
+}
+
+export interface NodeSystemInfo {
+  kernelVersion: string
+  osImage: string
+  operatingSystem: string
+  architecture: string
+  containerRuntimeVersion: string // e.g. containerd://1.7.13
+  kubeletVersion: string
+  kubeProxyVersion?: string
+}
+
+export interface NodeResourceReservation {
+  resource: string
+  capacity: string
+  allocatable: string
+  reserved: string               // Capacity held back for system daemons and eviction thresholds
+  reservedPercent: number
+}
+
+export interface NodeConditionState {
+  type: string
+  status: string
+  reason?: string
+  message?: string
+  lastTransitionTime?: string
+  lastTransitionTimeUnix?: number
+  transitions: number            // Transitions recorded in the history window
+}
+
+export interface NodeConditionTransition {
+  time: string
+  timeUnix: number
+  condition: string
+  from: string
+  to: string
+}
+
+export interface NodeEvent {
+  type: string
+  reason: string
+  message: string
+  count: number
+  timestamp: string
+  timestampUnix: number
+  object?: string                // Set when the event is about another object (e.g. a pod the kubelet evicted)
+}
+
+export interface NodeDetail {
+  name: string
+  systemInfo: NodeSystemInfo
+  reservations: NodeResourceReservation[]
+  conditions: NodeConditionState[]
+  conditionHistory: NodeConditionTransition[] // Newest first
+  historySince: string
+  historySinceUnix: number
+  events: NodeEvent[]                         // Newest first
+}
+
+export function useNodeDetail(nodeName: string) {
+  return useQuery<NodeDetail>({
+    queryKey: ['node-detail', nodeName],
+    queryFn: () => fetchJSON(`/nodes/${nodeName}/detail`),
+    enabled: Boolean(nodeName),
+    staleTime: 15000,
+    refetchInterval: 30000,
+  })
+}
+
+export interface PlacementCell {
+  node?: string
+  zone?: string
+  namespace: string
+  pods: number
+  cpuRequests: number            // millicores
+  memoryRequests: number         // bytes
+}
+
+export interface PlacementTotals {
+  name: string                   // Node or zone ("" for nodes without a zone label)
+  zone?: string
+  nodes?: number                 // Zones only
+  pods: number
+  cpuRequests: number
+  memoryRequests: number
+  cpuAllocatable: number
+  memoryAllocatable: number
+}
+
+export interface PodPlacement {
+  namespaces: string[]
+  nodes: PlacementTotals[]
+  zones: PlacementTotals[]
+  nodeCells: PlacementCell[]     // Non-empty cells only
+  zoneCells: PlacementCell[]
+  unscheduled: number
+}
+
+export function usePodPlacement(namespaces: string[] = []) {
+  const params = new URLSearchParams()
+  if (namespaces.length > 0) params.set('namespaces', namespaces.join(','))
+  return useQuery<PodPlacement>({
+    queryKey: ['placement', namespaces],
+    queryFn: () => fetchJSON(`/placement?${params}`),
+    staleTime: 15000,
+    refetchInterval: 30000,
+  })
+}
+
+export interface PlacementViolation {
+  kind: string
+  namespace: string
+  name: string
+  type: 'AntiAffinity' | 'TopologySpread' | 'SingleNode' | 'SingleZone'
+  severity: 'violation' | 'risk' // violation = a required rule doesn't hold
+  topologyKey?: string
+  message: string
+}
+
+export function usePlacementViolations(namespaces: string[] = []) {
+  const params = new URLSearchParams()
+  if (namespaces.length > 0) params.set('namespaces', namespaces.join(','))
+  return useQuery<PlacementViolation[]>({
+    queryKey: ['placement-violations', namespaces],
+    queryFn: () => fetchJSON(`/placement/violations?${params}`),
+    staleTime: 15000,
+    refetchInterval: 30000,
+  })
+}
+
+export interface MaintenanceWorkload {
+  kind: string
+  namespace: string
+  name: string
+  replicas: number               // Running pods across the cluster
+  onTargetNodes: number
+  losesAllReplicas: boolean      // Every replica is on the target nodes (bare pods are never recreated)
+}
+
+export interface MaintenancePDB {
+  namespace: string
+  name: string
+  disruptionsAllowed: number
+  podsOnTargetNodes: number
+  maxPodsPerNode: number
+  blocking: boolean              // A node's drain waits on replacements (or stalls at 0)
+}
+
+export interface MaintenanceStep {
+  node: string
+  pods: number                   // Pods that will be evicted
+  daemonSetPods: number          // DaemonSet and static pods, left in place
+  localStoragePods?: number      // Pods whose emptyDir data is lost
+  atRiskWorkloads?: string[]     // kind/namespace/name losing all replicas
+  blockingPdbs?: string[]        // namespace/name
+}
+
+export interface MaintenancePlan {
+  nodes: string[]
+  order: MaintenanceStep[]
+  workloads: MaintenanceWorkload[]
+  pdbs: MaintenancePDB[]
+  warnings: string[]
+}
+
+export interface DrainProgress {
+  node: string
+  phase: 'cordoned' | 'evicting' | 'blocked' | 'evicted' | 'drained' | 'failed'
+  pod?: string
+  message?: string
+  remaining: number
+  time: string
+}
+
+export interface MaintenanceStatus {
+  state: 'idle' | 'running' | 'succeeded' | 'failed' | 'cancelled'
+  nodes: string[]                // In drain order
+  current?: string
+  completed: string[]
+  error?: string
+  startedAt?: string
+  finishedAt?: string
+  progress: DrainProgress[]      // Oldest first
+}
+
+export interface MaintenanceRequest {
+  nodes: string[]
+  gracePeriodSeconds?: number
+  timeoutSeconds?: number        // Per node (default 300)
+}
+
+async function postMaintenance<T>(path: string, body: MaintenanceRequest): Promise<T> {
+  const response = await fetch(`${API_BASE}/maintenance/${path}`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify(body),
+  })
+  if (!response.ok) {
+    const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+    throw new Error(error.error || `HTTP ${response.status}`)
+  }
+  return response.json()
+}
+
+export function useMaintenancePlan() {
+  return useMutation<MaintenancePlan, Error, MaintenanceRequest>({
+    mutationFn: (req) => postMaintenance('plan', req),
+    meta: {
+      errorMessage: 'Failed to plan maintenance',
+    },
+  })
+}
+
+export function useStartMaintenance() {
+  const queryClient = useQueryClient()
+  return useMutation<MaintenanceStatus, Error, MaintenanceRequest>({
+    mutationFn: (req) => postMaintenance('execute', req),
+    meta: {
+      errorMessage: 'Failed to start maintenance',
+      successMessage: 'Maintenance started',
+    },
+    onSuccess: (status) => {
+      queryClient.setQueryData(['maintenance-status'], status)
+    },
+  })
+}
+
+export function useCancelMaintenance() {
+  const queryClient = useQueryClient()
+  return useMutation({
+    mutationFn: async () => {
+      const response = await fetch(`${API_BASE}/maintenance/execute`, { method: 'DELETE' })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Failed to cancel maintenance',
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['maintenance-status'] })
+    },
+  })
+}
+
+export function useMaintenanceStatus() {
+  return useQuery<MaintenanceStatus>({
+    queryKey: ['maintenance-status'],
+    queryFn: () => fetchJSON('/maintenance/status'),
+    refetchInterval: (query) => (query.state.data?.state === 'running' ? 2000 : false),
+  })
+}
+
+// Cluster info
+export function useClusterInfo() {
+  const query = useQuery<ClusterInfo>({
+    queryKey: ['cluster-info'],
+    queryFn: () => fetchJSON('/cluster-info'),
+    staleTime: 60000, // 1 minute
+    // Poll faster when CRD discovery is in progress
+    refetchInterval: (query) => {
+      const status = query.state.data?.crdDiscoveryStatus
+      return status === 'discovering' ? 2000 : false
+    },
+  })
+  return query
+}
+
+// Version check
+export type InstallMethod = 'homebrew' | 'krew' | 'scoop' | 'direct' | 'desktop'
+
+export interface VersionInfo {
+  currentVersion: string
+  latestVersion?: string
+  updateAvailable: boolean
+  releaseUrl?: string
+  releaseNotes?: string
+  installMethod: InstallMethod
+  updateCommand?: string
+  offline?: boolean // Running with --offline, no check was made
+  error?: string
+}
+
+export function useVersionCheck() {
+  return useQuery<VersionInfo>({
+    queryKey: ['version-check'],
+    queryFn: () => fetchJSON('/version-check'),
+    staleTime: 60 * 60 * 1000, // 1 hour
+    retry: false, // Don't retry on failure
+  })
+}
+
+// ============================================================================
+// Desktop Update API hooks
+// ============================================================================
+
+export type DesktopUpdateState = 'idle' | 'downloading' | 'ready' | 'applying' | 'error' | 'offline'
+
+export interface DesktopUpdateStatus {
+  state: DesktopUpdateState
+  progress?: number // 0.0 - 1.0 during download
+  version?: string
+  error?: string
+}
+
+export function useStartDesktopUpdate() {
+  return useMutation({
+    mutationFn: async () => {
+      const response = await fetch(`${API_BASE}/desktop/update`, {
+        method: 'POST',
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Failed to start update',
+    },
+  })
+}
+
+export function useDesktopUpdateStatus(enabled: boolean) {
+  return useQuery<DesktopUpdateStatus>({
+    queryKey: ['desktop-update-status'],
+    queryFn: () => fetchJSON('/desktop/update/status'),
+    enabled,
+    refetchInterval: 500, // Poll every 500ms during active update
+    staleTime: 0, // Always refetch
+  })
+}
+
+export function useApplyDesktopUpdate() {
+  return useMutation({
+    mutationFn: async () => {
+      const response = await fetch(`${API_BASE}/desktop/update/apply`, {
+        method: 'POST',
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Failed to apply update',
+      successMessage: 'Update applied — restarting...',
+    },
+  })
+}
+
+// Runtime stats for debug overlay
+export interface RuntimeStats {
+  heapMB: number
+  heapObjectsK: number
+  goroutines: number
+  uptimeSeconds: number
+  typedInformers?: number
+  dynamicInformers?: number
+}
+
+export interface HealthResponse {
+  status: string
+  resourceCount: number
+  runtime: RuntimeStats
+}
+
+export function useRuntimeStats(enabled: boolean = true) {
+  return useQuery<HealthResponse>({
+    queryKey: ['health'],
+    queryFn: () => fetchJSON('/health'),
+    staleTime: 2000, // 2 seconds
+    refetchInterval: enabled ? 3000 : false, // Refresh every 3 seconds when enabled
+    enabled,
+  })
+}
+
+// Capabilities (RBAC-based feature flags)
+export function useCapabilities() {
+  return useQuery<Capabilities>({
+    queryKey: ['capabilities'],
+    queryFn: () => fetchJSON('/capabilities'),
+    staleTime: 60000, // 1 minute - cached on backend too
+  })
+}
+
+// Runtime settings (GET/PUT /api/config), applied without a restart
+export interface RuntimeConfig {
+  historyLimit: number
+  noisyKinds: string[]
+  logLevel: 'info' | 'debug'
+  readOnly: boolean
+  path?: string
+  warning?: string // Applied but not saved to the config file
+}
+
+export function useRuntimeConfig() {
+  return useQuery<RuntimeConfig>({
+    queryKey: ['runtime-config'],
+    queryFn: () => fetchJSON('/config'),
+    staleTime: 60000,
+  })
+}
+
+export function useUpdateRuntimeConfig() {
+  const queryClient = useQueryClient()
+  return useMutation<RuntimeConfig, Error, Partial<Omit<RuntimeConfig, 'path' | 'warning'>>>({
+    mutationFn: async (update) => {
+      const response = await fetch(`${API_BASE}/config`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(update),
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Failed to update settings',
+    },
+    onSuccess: (config) => {
+      queryClient.setQueryData(['runtime-config'], config)
+      // Read-only mode changes what the UI offers
+      queryClient.invalidateQueries({ queryKey: ['capabilities'] })
+    },
+  })
+}
+
+// Namespaces
+export function useNamespaces() {
+  return useQuery<Namespace[]>({
+    queryKey: ['namespaces'],
+    queryFn: () => fetchJSON('/namespaces'),
+    staleTime: 30000, // 30 seconds
+  })
+}
+
+// Topology (for manual refresh)
+// `at` (RFC3339) rewinds the graph to a past time using timeline create/delete events
+export function useTopology(namespaces: string[], viewMode: string = 'resources', at?: string) {
+  const params = new URLSearchParams()
+  if (namespaces.length > 0) params.set('namespaces', namespaces.join(','))
+  if (viewMode) params.set('view', viewMode)
+  if (at) params.set('at', at)
+  const queryString = params.toString()
+
+  return useQuery<Topology>({
+    queryKey: ['topology', namespaces, viewMode, at],
+    queryFn: () => fetchJSON(`/topology${queryString ? `?${queryString}` : ''}`),
+    staleTime: 5000, // 5 seconds
+  })
+}
+
+// Topology subgraph within `depth` hops of a resource (for focused mini-maps)
+export function useTopologyNeighborhood(kind: string, namespace: string, name: string, depth: number = 2) {
+  const ns = namespace || '_'
+  return useQuery<Topology>({
+    queryKey: ['topology-neighborhood', kind, namespace, name, depth],
+    queryFn: () => fetchJSON(`/topology/neighborhood/${kind}/${ns}/${name}?depth=${depth}`),
+    enabled: Boolean(kind && name),
+    staleTime: 5000, // 5 seconds
+  })
+}
+
+// Remote clusters reporting through radar-agent (server started with --agent-listen)
+export interface RemoteCluster {
+  name: string
+  context?: string
+  agentVersion?: string
+  connected: boolean
+  connectedAt: string
+  lastSeen: string
+  changes: number
+  events: number
+  nodes: number
+}
+
+export function useRemoteClusters() {
+  return useQuery<RemoteCluster[]>({
+    queryKey: ['agents'],
+    queryFn: () => fetchJSON('/agents'),
+    staleTime: 5000,
+    refetchInterval: 10000,
+  })
+}
+
+export function useRemoteTopology(cluster: string) {
+  return useQuery<Topology>({
+    queryKey: ['agents', cluster, 'topology'],
+    queryFn: () => fetchJSON(`/agents/${encodeURIComponent(cluster)}/topology`),
+    enabled: Boolean(cluster),
+    staleTime: 5000,
+    refetchInterval: 10000,
+  })
+}
+
+export function useRemoteTimeline(cluster: string, limit: number = 200) {
+  return useQuery<TimelineEvent[]>({
+    queryKey: ['agents', cluster, 'timeline', limit],
+    queryFn: () => fetchJSON(`/agents/${encodeURIComponent(cluster)}/timeline?limit=${limit}`),
+    enabled: Boolean(cluster),
+    staleTime: 5000,
+    refetchInterval: 10000,
+  })
+}
+
+// Fleet: the local cluster plus every agent-reported cluster
+export interface FleetClusterSummary {
+  platform?: string
+  kubernetesVersion?: string
+  nodes: number
+  readyNodes: number
+  kubeletVersions?: Record<string, number>
+  namespaces: number
+  pods: number
+}
+
+export interface FleetHealth {
+  healthy: number
+  degraded: number
+  unhealthy: number
+  unknown: number
+}
+
+export interface FleetCluster {
+  name: string
+  local?: boolean
+  connected: boolean
+  lastSeen?: string
+  summary?: FleetClusterSummary
+  health: FleetHealth
+  problems: number
+  warnings: number // Warning/critical timeline events in the last hour
+}
+
+export interface FleetProblem {
+  cluster: string
+  kind: string
+  namespace?: string
+  name: string
+  status: HealthStatus
+}
+
+export interface FleetDashboard {
+  clusters: FleetCluster[]
+  totals: { clusters: number; connected: number; nodes: number; readyNodes: number; health: FleetHealth }
+  kubernetesVersions: Record<string, number>
+  kubeletVersions: Record<string, number>
+  topProblems: FleetProblem[]
+  topReasons: { reason: string; count: number; clusters: string[] }[]
+}
+
+export interface FleetClusterDetail extends FleetCluster {
+  problemList: FleetProblem[]
+  recentWarnings: TimelineEvent[]
+}
+
+export interface FleetSearchResponse {
+  query: string
+  total: number
+  results: (FleetProblem & { score: number })[]
+  clusters: Record<string, number>
+}
+
+export function useFleetDashboard() {
+  return useQuery<FleetDashboard>({
+    queryKey: ['fleet'],
+    queryFn: () => fetchJSON('/fleet'),
+    staleTime: 10000,
+    refetchInterval: 15000,
+  })
+}
+
+export function useFleetCluster(cluster: string) {
+  return useQuery<FleetClusterDetail>({
+    queryKey: ['fleet', 'cluster', cluster],
+    queryFn: () => fetchJSON(`/fleet/clusters/${encodeURIComponent(cluster)}`),
+    enabled: Boolean(cluster),
+    staleTime: 10000,
+  })
+}
+
+export function useFleetSearch(query: string, kinds: string[] = [], limit: number = 50) {
+  const params = new URLSearchParams({ q: query, limit: String(limit) })
+  if (kinds.length > 0) params.set('kinds', kinds.join(','))
+  return useQuery<FleetSearchResponse>({
+    queryKey: ['fleet', 'search', query, kinds, limit],
+    queryFn: () => fetchJSON(`/fleet/search?${params}`),
+    enabled: query.trim().length > 0,
+    staleTime: 10000,
+  })
+}
+
+// Generic resource fetching - returns resource with relationships
+// Uses '_' as placeholder for cluster-scoped resources (empty namespace)
+export function useResource<T>(kind: string, namespace: string, name: string, group?: string) {
+  // For cluster-scoped resources, use '_' as namespace placeholder
+  const ns = namespace || '_'
+  const params = new URLSearchParams()
+  if (group) params.set('group', group)
+  const queryString = params.toString()
+
+  const query = useQuery<ResourceWithRelationships<T>>({
+    queryKey: ['resource', kind, namespace, name, group],
+    queryFn: () => fetchJSON(`/resources/${kind}/${ns}/${name}${queryString ? `?${queryString}` : ''}`),
+    enabled: Boolean(kind && name),  // namespace can be empty for cluster-scoped resources
+  })
+
+  // Extract resource and relationships from the response
+  return {
+    ...query,
+    data: query.data?.resource,
+    relationships: query.data?.relationships,
+  }
+}
+
+// Hook that returns full response with relationships explicitly
+export function useResourceWithRelationships<T>(kind: string, namespace: string, name: string, group?: string) {
+  const ns = namespace || '_'
+  const params = new URLSearchParams()
+  if (group) params.set('group', group)
+  const queryString = params.toString()
+
+  return useQuery<ResourceWithRelationships<T>>({
+    queryKey: ['resource', kind, namespace, name, group],
+    queryFn: () => fetchJSON(`/resources/${kind}/${ns}/${name}${queryString ? `?${queryString}` : ''}`),
+    enabled: Boolean(kind && name),
+  })
+}
+
+export interface BatchGetItem {
+  kind: string
+  namespace?: string
+  name: string
+  group?: string
+}
+
+export interface BatchGetResult<T = unknown> extends BatchGetItem {
+  resource?: T
+  relationships?: Relationships
+  status: number
+  error?: string
+}
+
+// Fetch several resources in one round trip (e.g. a detail pane's related resources).
+// Results are in request order; failed items carry their own status and error.
+export function useResourcesBatch<T = unknown>(items: BatchGetItem[]) {
+  return useQuery<BatchGetResult<T>[]>({
+    queryKey: ['resources-batch', items],
+    queryFn: async () => {
+      const response = await fetch(`${API_BASE}/resources/batch-get`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ items }),
+      })
+      if (!response.ok) {
+        const errorData = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new ApiError(errorData.error || `HTTP ${response.status}`, response.status, errorData)
+      }
+      const data: { items: BatchGetResult<T>[] } = await response.json()
+      return data.items
+    },
+    enabled: items.length > 0,
+  })
+}
+
+export interface OwnerLink {
+  kind: string
+  group?: string
+  namespace?: string
+  name: string
+  uid?: string
+  via?: 'ownerReference' | 'helm' | 'argocd' | 'flux'
+  missing?: boolean // Referenced but not in the cache; the chain stops here
+}
+
+// Ownership chain for breadcrumbs: the resource first, outermost owner last
+export function useOwnerChain(kind: string, namespace: string, name: string, group?: string) {
+  const ns = namespace || '_'
+  const params = new URLSearchParams()
+  if (group) params.set('group', group)
+  const queryString = params.toString()
+
+  return useQuery<{ chain: OwnerLink[] }>({
+    queryKey: ['owner-chain', kind, namespace, name, group],
+    queryFn: () => fetchJSON(`/resources/${kind}/${ns}/${name}/owners${queryString ? `?${queryString}` : ''}`),
+    enabled: Boolean(kind && name),
+    staleTime: 30000, // Ownership rarely changes
+  })
+}
+
+// List resources - queryKey includes group for cache sharing with ResourcesView
+export function useResources<T>(kind: string, namespace?: string, group?: string) {
+  const params = new URLSearchParams()
+  if (namespace) params.set('namespace', namespace)
+  if (group) params.set('group', group)
+  const queryString = params.toString()
+
+  return useQuery<T[]>({
+    queryKey: ['resources', kind, group, namespace],
+    queryFn: () => fetchJSON(`/resources/${kind}${queryString ? `?${queryString}` : ''}`),
+    staleTime: 30000, // 30 seconds - matches refetchInterval in ResourcesView
+  })
+}
+
+// List CRs with their kubectl printer columns evaluated server-side
+export function useResourceSummary(kind: string, namespace?: string, group?: string) {
+  const params = new URLSearchParams({ summary: 'true' })
+  if (namespace) params.set('namespace', namespace)
+  if (group) params.set('group', group)
+
+  return useQuery<ResourceSummaryList>({
+    queryKey: ['resources-summary', kind, group, namespace],
+    queryFn: () => fetchJSON(`/resources/${kind}?${params}`),
+    staleTime: 30000,
+  })
+}
+
+// Timeline changes (unified view of changes + K8s events)
 export interface UseChangesOptions {
   namespaces?: string[]
   kind?: string
   timeRange?: TimeRange
-  filter?: string // Filter preset name ('default', 'all', 'warnings-only', 'workloads')
+  filter?: string // Filter preset name (built-in: 'default', 'all', 'warnings-only', 'critical', 'workloads'; or a custom preset)
+  severities?: Severity[]
+  context?: string // Another context's history (SQLite storage only); '*' = all contexts
   includeK8sEvents?: boolean
   includeManaged?: boolean
   limit?: number
@@ -452,13 +1485,15 @@ function getTimeRangeDate(range: TimeRange): Date | null {
   }
 }
 
-export function useChanges(options: UseChangesOptions = {}) {
-  const { namespaces = [], kind, timeRange = '1h', filter = 'all', includeK8sEvents = true, includeManaged = false, limit = 200 } = options
+function changesParams(options: UseChangesOptions): URLSearchParams {
+  const { namespaces = [], kind, timeRange = '1h', filter = 'all', severities = [], context, includeK8sEvents = true, includeManaged = false, limit = 200 } = options
 
   const params = new URLSearchParams()
   if (namespaces.length > 0) params.set('namespaces', namespaces.join(','))
   if (kind) params.set('kind', kind)
   if (filter) params.set('filter', filter)
+  if (severities.length > 0) params.set('severity', severities.join(','))
+  if (context) params.set('context', context)
   if (!includeK8sEvents) params.set('include_k8s_events', 'false')
   if (includeManaged) params.set('include_managed', 'true')
   params.set('limit', String(limit))
@@ -467,17 +1502,225 @@ export function useChanges(options: UseChangesOptions = {}) {
   if (sinceDate) {
     params.set('since', sinceDate.toISOString())
   }
+  return params
+}
 
-  const queryString = params.toString()
+export function useChanges(options: UseChangesOptions = {}) {
+  const { namespaces = [], kind, timeRange = '1h', filter = 'all', severities = [], context, includeK8sEvents = true, includeManaged = false, limit = 200 } = options
+  const queryString = changesParams(options).toString()
 
   return useQuery<TimelineEvent[]>({
-    queryKey: ['changes', namespaces, kind, timeRange, filter, includeK8sEvents, includeManaged, limit],
+    queryKey: ['changes', namespaces, kind, timeRange, filter, severities, context, includeK8sEvents, includeManaged, limit],
     queryFn: () => fetchJSON(`/changes${queryString ? `?${queryString}` : ''}`),
     staleTime: 5000, // Consider data stale after 5 seconds to ensure fresh data on navigation
     refetchInterval: 60000, // SSE handles real-time updates; this is a fallback
   })
 }
 
+// Fetch the page of changes older than cursor (from a previous page's
+// nextCursor). nextCursor is null once there's nothing older.
+export async function fetchChangesPage(options: UseChangesOptions & { cursor?: string; until?: string } = {}): Promise<{ events: TimelineEvent[]; nextCursor: string | null }> {
+  const params = changesParams(options)
+  if (options.cursor) params.set('cursor', options.cursor)
+  if (options.until) params.set('until', options.until)
+  const response = await fetch(`${API_BASE}/changes?${params.toString()}`)
+  if (!response.ok) {
+    const errorData = await response.json().catch(() => ({ error: 'Unknown error' }))
+    throw new ApiError(errorData.error || `HTTP ${response.status}`, response.status, errorData)
+  }
+  return { events: await response.json(), nextCursor: response.headers.get('X-Next-Cursor') }
+}
+
+export interface ChangesAggregate {
+  since: string
+  sinceUnix: number
+  until: string
+  untilUnix: number
+  bucketSeconds: number
+  buckets: string[] // Bucket start times, oldest first
+  bucketsUnix: number[]
+  groupBy: string[]
+  series: { key: Record<string, string>; counts: number[]; total: number }[] // Largest first; "(other)" folds the rest with top
+  total: number
+  truncated?: boolean
+}
+
+// Event volume over time grouped by dimensions, e.g. by namespace over the last 6h
+export function useChangesAggregate(options: UseChangesOptions & { groupBy?: string[]; bucket?: string; top?: number } = {}) {
+  const { groupBy = [], bucket, top, timeRange = '6h' } = options
+  const params = changesParams({ ...options, timeRange })
+  params.delete('limit')
+  if (groupBy.length > 0) params.set('groupBy', groupBy.join(','))
+  if (bucket) params.set('bucket', bucket)
+  if (top) params.set('top', String(top))
+  const queryString = params.toString()
+
+  return useQuery<ChangesAggregate>({
+    queryKey: ['changes-aggregate', queryString],
+    queryFn: () => fetchJSON(`/changes/aggregate?${queryString}`),
+    staleTime: 30000,
+    refetchInterval: 60000,
+  })
+}
+
+// Timeline filter presets
+export function useFilterPresets() {
+  return useQuery<FilterPreset[]>({
+    queryKey: ['timeline-presets'],
+    queryFn: () => fetchJSON('/timeline/presets'),
+    staleTime: 60000,
+  })
+}
+
+export function useSaveFilterPreset() {
+  const queryClient = useQueryClient()
+  return useMutation<FilterPreset, Error, Omit<FilterPreset, 'builtin'>>({
+    mutationFn: async (preset) => {
+      const response = await fetch(`${API_BASE}/timeline/presets/${encodeURIComponent(preset.name)}`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(preset),
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Failed to save filter preset',
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['timeline-presets'] })
+      queryClient.invalidateQueries({ queryKey: ['changes'] })
+    },
+  })
+}
+
+export function useDeleteFilterPreset() {
+  const queryClient = useQueryClient()
+  return useMutation<{ status: string }, Error, string>({
+    mutationFn: async (name) => {
+      const response = await fetch(`${API_BASE}/timeline/presets/${encodeURIComponent(name)}`, { method: 'DELETE' })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Failed to delete filter preset',
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['timeline-presets'] })
+    },
+  })
+}
+
+export type DigestSchedule = '' | 'daily' | 'weekly'
+
+export interface SavedTimelineQuery {
+  name: string
+  filter?: string // Filter preset name
+  namespaces?: string[]
+  kinds?: string[]
+  severities?: Severity[]
+  digest?: DigestSchedule
+  webhookUrl?: string // Receives digests as JSON with a Slack-compatible "text" field
+  lastDigestAt?: string
+}
+
+export interface TimelineDigest {
+  query: string
+  since: string
+  until: string
+  totalEvents: number
+  bySeverity: Partial<Record<Severity, number>>
+  byKind: Record<string, number>
+  topReasons: { reason: string; count: number }[]
+  newProblems: string[] // Warning/critical reasons absent from the previous period
+  truncated?: boolean
+}
+
+export function useSavedTimelineQueries() {
+  return useQuery<SavedTimelineQuery[]>({
+    queryKey: ['timeline-queries'],
+    queryFn: () => fetchJSON('/timeline/queries'),
+    staleTime: 60000,
+  })
+}
+
+export function useSaveTimelineQuery() {
+  const queryClient = useQueryClient()
+  return useMutation<SavedTimelineQuery, Error, Omit<SavedTimelineQuery, 'lastDigestAt'>>({
+    mutationFn: async (query) => {
+      const response = await fetch(`${API_BASE}/timeline/queries/${encodeURIComponent(query.name)}`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(query),
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Failed to save timeline query',
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['timeline-queries'] })
+    },
+  })
+}
+
+export function useDeleteTimelineQuery() {
+  const queryClient = useQueryClient()
+  return useMutation<{ status: string }, Error, string>({
+    mutationFn: async (name) => {
+      const response = await fetch(`${API_BASE}/timeline/queries/${encodeURIComponent(name)}`, { method: 'DELETE' })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Failed to delete timeline query',
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['timeline-queries'] })
+    },
+  })
+}
+
+// Preview a saved query's digest for the period ending now
+export function useTimelineDigest(name: string, schedule?: DigestSchedule) {
+  return useQuery<{ digest: TimelineDigest; text: string }>({
+    queryKey: ['timeline-digest', name, schedule],
+    queryFn: () => fetchJSON(`/timeline/queries/${encodeURIComponent(name)}/digest${schedule ? `?schedule=${schedule}` : ''}`),
+    enabled: Boolean(name),
+    staleTime: 60000,
+  })
+}
+
+// Send a saved query's digest to its webhook now
+export function useSendTimelineDigest() {
+  return useMutation<TimelineDigest, Error, string>({
+    mutationFn: async (name) => {
+      const response = await fetch(`${API_BASE}/timeline/queries/${encodeURIComponent(name)}/digest`, { method: 'POST' })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Failed to send timeline digest',
+    },
+  })
+}
+
 // Children changes for a parent workload (e.g., ReplicaSets and Pods under a Deployment)
 export function useResourceChildren(kind: string, namespace: string, name: string, timeRange: TimeRange = '1h') {
   const sinceDate = getTimeRangeDate(timeRange)
@@ -538,6 +1781,7 @@ export interface PodMetrics {
   timestamp: string
   window: string
   containers: ContainerMetrics[]
+  source?: 'kubelet' // Set when derived from the kubelet summary (no metrics-server)
 }
 
 export interface NodeMetrics {
@@ -551,6 +1795,7 @@ export interface NodeMetrics {
     cpu: string
     memory: string
   }
+  source?: 'kubelet' // Set when derived from the kubelet summary (no metrics-server)
 }
 
 // Fetch metrics for a specific pod
@@ -583,43 +1828,395 @@ export interface MetricsDataPoint {
   timestamp: string
   cpu: number      // CPU in nanocores
   memory: number   // Memory in bytes
+  memoryPeak?: number // Highest memory in a 5-minute rollup; absent for raw samples
+}
+
+export interface ContainerMetricsHistory {
+  name: string
+  dataPoints: MetricsDataPoint[]
+}
+
+export interface PodMetricsHistory {
+  namespace: string
+  name: string
+  containers: ContainerMetricsHistory[]
+}
+
+export interface NodeMetricsHistory {
+  name: string
+  dataPoints: MetricsDataPoint[]
+}
+
+// Window of metrics history to fetch. Beyond the last hour, points are 5-minute averages.
+export type MetricsHistoryDuration = '1h' | '6h' | '24h'
+
+// Fetch historical metrics for a pod
+export function usePodMetricsHistory(namespace: string, podName: string, duration: MetricsHistoryDuration = '1h') {
+  return useQuery<PodMetricsHistory>({
+    queryKey: ['pod-metrics-history', namespace, podName, duration],
+    queryFn: () => fetchJSON(`/metrics/pods/${namespace}/${podName}/history?duration=${duration}`),
+    enabled: Boolean(namespace && podName),
+    staleTime: 25000, // Slightly less than poll interval
+    refetchInterval: 30000, // Match the backend poll interval
+  })
+}
+
+// Fetch historical metrics for a node
+export function useNodeMetricsHistory(nodeName: string, duration: MetricsHistoryDuration = '1h') {
+  return useQuery<NodeMetricsHistory>({
+    queryKey: ['node-metrics-history', nodeName, duration],
+    queryFn: () => fetchJSON(`/metrics/nodes/${nodeName}/history?duration=${duration}`),
+    enabled: Boolean(nodeName),
+    staleTime: 25000,
+    refetchInterval: 30000,
+  })
+}
+
+// Cluster-wide usage, requests, and capacity sample (CPU in millicores, memory in bytes)
+export interface ClusterMetricsDataPoint {
+  timestamp: string
+  cpuUsage: number
+  cpuRequests: number
+  cpuCapacity: number
+  memoryUsage: number
+  memoryRequests: number
+  memoryCapacity: number
+}
+
+export interface ClusterMetricsHistory {
+  step: string // Width of each point's bucket, e.g. "1m0s"
+  dataPoints: ClusterMetricsDataPoint[]
+}
+
+// Fetch a downsampled cluster metrics series for dashboard sparklines
+export function useClusterMetricsHistory(duration: MetricsHistoryDuration = '1h', points = 60) {
+  return useQuery<ClusterMetricsHistory>({
+    queryKey: ['cluster-metrics-history', duration, points],
+    queryFn: () => fetchJSON(`/metrics/cluster/history?duration=${duration}&points=${points}`),
+    staleTime: 25000,
+    refetchInterval: 30000,
+  })
+}
+
+// Pod usage averaged over the report window (CPU in millicores, memory in bytes)
+export interface PodUsage {
+  name: string
+  cpuUsage: number
+  cpuRequests: number
+  memoryUsage: number
+  memoryRequests: number
+}
+
+export interface NamespaceUsage {
+  namespace: string
+  pods: number
+  cpuUsage: number
+  cpuRequests: number
+  cpuLimits: number
+  memoryUsage: number
+  memoryRequests: number
+  memoryLimits: number
+  topCpu: PodUsage[]
+  topMemory: PodUsage[]
+}
+
+export interface NamespaceUsageReport {
+  window: string
+  hasMetrics: boolean
+  namespaces: NamespaceUsage[]
+}
+
+// Fetch CPU/memory usage and requests rolled up per namespace, with top consumers
+export function useNamespaceUsage(namespaces: string[] = [], duration: MetricsHistoryDuration = '1h', top = 5) {
+  const params = new URLSearchParams({ duration, top: String(top) })
+  if (namespaces.length > 0) params.set('namespaces', namespaces.join(','))
+  return useQuery<NamespaceUsageReport>({
+    queryKey: ['namespace-usage', namespaces, duration, top],
+    queryFn: () => fetchJSON(`/metrics/namespaces?${params}`),
+    staleTime: 25000,
+    refetchInterval: 30000,
+  })
+}
+
+// ============================================================================
+// Pod Startup Timing
+// ============================================================================
+
+// Durations are in milliseconds, each measured from the previous known milestone
+export interface PodLifecycleTiming {
+  namespace: string
+  name: string
+  createdAt?: string
+  scheduledAt?: string
+  pulledAt?: string
+  startedAt?: string
+  readyAt?: string
+  schedulingMs?: number
+  imagePullMs?: number
+  containerStartMs?: number
+  readinessMs?: number
+  totalMs?: number
+}
+
+export interface PhaseStats {
+  samples: number
+  p50Ms: number
+  p95Ms: number
+}
+
+export interface WorkloadStartupStats {
+  kind: string
+  namespace: string
+  name: string
+  pods: number
+  scheduling?: PhaseStats
+  imagePull?: PhaseStats
+  containerStart?: PhaseStats
+  readiness?: PhaseStats
+  total?: PhaseStats
+}
+
+export function usePodLifecycle(namespace: string, podName: string) {
+  return useQuery<PodLifecycleTiming>({
+    queryKey: ['pod-lifecycle', namespace, podName],
+    queryFn: () => fetchJSON(`/pods/${namespace}/${podName}/lifecycle`),
+    enabled: Boolean(namespace && podName),
+    staleTime: 10000,
+  })
+}
+
+export interface ConfigSource {
+  kind: string // Literal, ConfigMap, Secret, Field, ResourceField, EmptyDir, PersistentVolumeClaim, ...
+  name?: string
+  key?: string
+}
+
+export interface ResolvedEnvVar {
+  name: string
+  value: string
+  source: ConfigSource
+  secret?: boolean
+  redacted?: boolean
+  overridden?: boolean // Shadowed by a later definition
+  note?: string
+  error?: string // Missing source: the container won't start
+}
+
+export interface ConfigMount {
+  mountPath: string
+  volume: string
+  subPath?: string
+  readOnly?: boolean
+  sources: ConfigSource[]
+  files?: { path: string; source: ConfigSource; size?: number }[]
+  note?: string
+  errors?: string[]
+}
+
+export interface PodConfig {
+  namespace: string
+  pod: string
+  container: string
+  containers: string[]
+  env: ResolvedEnvVar[]
+  mounts: ConfigMount[]
+}
+
+// A container's effective environment and mounted config files
+export function usePodConfig(namespace: string, podName: string, container = '', reveal = false) {
+  return useQuery<PodConfig>({
+    queryKey: ['pod-config', namespace, podName, container, reveal],
+    queryFn: () => {
+      const params = new URLSearchParams()
+      if (container) params.set('container', container)
+      if (reveal) params.set('reveal', 'true')
+      const query = params.toString()
+      return fetchJSON(`/pods/${namespace}/${podName}/config${query ? `?${query}` : ''}`)
+    },
+    enabled: Boolean(namespace && podName),
+    staleTime: 10000,
+  })
+}
+
+// Recent data changes of a Secret; values are markers unless revealed
+export interface SecretValueChange {
+  time: string
+  fields: FieldChange[]
+  summary: string
+}
+
+export function useSecretChanges(namespace: string, name: string, reveal = false) {
+  return useQuery<SecretValueChange[]>({
+    queryKey: ['secret-changes', namespace, name, reveal],
+    queryFn: () => fetchJSON(`/secrets/${namespace}/${name}/changes${reveal ? '?reveal=true' : ''}`),
+    enabled: Boolean(namespace && name),
+    staleTime: 10000,
+  })
+}
+
+export interface ProjectedToken {
+  volume: string
+  path: string
+  mounts: string[] // container:/mount/path
+  audience?: string // Empty means the API server
+  expirationSeconds: number
+  refreshSeconds: number
+  default?: boolean // kube-api-access volume
+}
+
+export interface PodTokenReport {
+  namespace: string
+  pod: string
+  serviceAccount: string
+  automount: boolean
+  automountSource: 'pod' | 'serviceAccount' | 'default'
+  status: 'bound' | 'legacy' | 'mixed' | 'none'
+  projected?: ProjectedToken[]
+  legacy?: { secret: string; via: string; byName?: boolean }[]
+  issues?: string[]
+}
+
+export interface TokenClaims {
+  issuer?: string
+  audiences?: string[]
+  subject?: string
+  issuedAt?: string
+  expiresAt?: string // Absent for legacy tokens
+  warnAfter?: string
+  boundPod?: string
+  serviceAccount?: string
+}
+
+export interface LegacyTokenSecret {
+  namespace: string
+  name: string
+  serviceAccount: string
+  createdAt: string
+  lastUsed?: string
+  invalidSince?: string
+  usedBy: string[]
+}
+
+export interface ServiceAccountTokenReport {
+  pods: PodTokenReport[]
+  legacySecrets: LegacyTokenSecret[]
+  summary: Record<string, number>
+  warnings?: string[]
+}
+
+// A pod's service account tokens; live reads the mounted tokens' claims
+export function usePodTokens(namespace: string, podName: string, live = false) {
+  return useQuery<PodTokenReport | { report: PodTokenReport; live: { volume: string; container: string; path: string; claims?: TokenClaims; error?: string }[] }>({
+    queryKey: ['pod-tokens', namespace, podName, live],
+    queryFn: () => fetchJSON(`/pods/${namespace}/${podName}/tokens${live ? '?live=true' : ''}`),
+    enabled: Boolean(namespace && podName),
+  })
+}
+
+// Bound vs legacy token usage across pods, for migrating off token Secrets
+export function useServiceAccountTokens(namespace?: string) {
+  return useQuery<ServiceAccountTokenReport>({
+    queryKey: ['serviceaccount-tokens', namespace],
+    queryFn: () => fetchJSON(`/serviceaccount-tokens${namespace ? `?namespace=${encodeURIComponent(namespace)}` : ''}`),
+    staleTime: 30000,
+  })
+}
+
+// Per-workload startup percentiles from timeline history, slowest first
+export function useWorkloadStartupStats(namespaces: string[] = [], since?: string) {
+  const params = new URLSearchParams()
+  if (namespaces.length > 0) params.set('namespaces', namespaces.join(','))
+  if (since) params.set('since', since)
+  return useQuery<WorkloadStartupStats[]>({
+    queryKey: ['workload-startup', namespaces, since],
+    queryFn: () => fetchJSON(`/lifecycle/startup?${params}`),
+    staleTime: 60000,
+  })
+}
+
+export interface ImagePullStats {
+  image: string
+  registry: string
+  pulls: number
+  cacheHits: number
+  failures: number
+  failureRate: number
+  p50Ms: number
+  p95Ms: number
+  maxMs: number
+  pulledOn?: string[]
+  missingOnNodes?: string[]
+  lastFailure?: string
+}
+
+export interface RegistryPullStats {
+  registry: string
+  images: number
+  pulls: number
+  cacheHits: number
+  failures: number
+  failureRate: number
+  p50Ms: number
+  p95Ms: number
 }
 
-export interface ContainerMetricsHistory {
-  name: string
-  dataPoints: MetricsDataPoint[]
+export interface ImagePullReport {
+  since: string
+  totalPulls: number
+  totalFailures: number
+  images: ImagePullStats[]
+  registries: RegistryPullStats[]
 }
 
-export interface PodMetricsHistory {
+export function useImagePullAnalytics(namespaces: string[] = [], since?: string) {
+  const params = new URLSearchParams()
+  if (namespaces.length > 0) params.set('namespaces', namespaces.join(','))
+  if (since) params.set('since', since)
+  return useQuery<ImagePullReport>({
+    queryKey: ['image-pulls', namespaces, since],
+    queryFn: () => fetchJSON(`/images/pulls?${params}`),
+    staleTime: 60000,
+  })
+}
+
+export interface WorkloadContainer {
+  kind: string
   namespace: string
   name: string
-  containers: ContainerMetricsHistory[]
+  container: string
 }
 
-export interface NodeMetricsHistory {
-  name: string
-  dataPoints: MetricsDataPoint[]
+export interface ImageUpdateStatus {
+  image: string
+  tag?: string
+  runningDigests?: string[]
+  registryDigest?: string
+  digestDrift: boolean
+  newerTags?: string[]
+  latestTag?: string
+  behind: boolean
+  authMethod?: string
+  error?: string
+  checkedAt: string
+  workloads: WorkloadContainer[]
 }
 
-// Fetch historical metrics for a pod (last ~1 hour)
-export function usePodMetricsHistory(namespace: string, podName: string) {
-  return useQuery<PodMetricsHistory>({
-    queryKey: ['pod-metrics-history', namespace, podName],
-    queryFn: () => fetchJSON(`/metrics/pods/${namespace}/${podName}/history`),
-    enabled: Boolean(namespace && podName),
-    staleTime: 25000, // Slightly less than poll interval
-    refetchInterval: 30000, // Match the backend poll interval
-  })
+export interface ImageUpdatesReport {
+  images: ImageUpdateStatus[]
+  behind: number
+  errors: number
 }
 
-// Fetch historical metrics for a node (last ~1 hour)
-export function useNodeMetricsHistory(nodeName: string) {
-  return useQuery<NodeMetricsHistory>({
-    queryKey: ['node-metrics-history', nodeName],
-    queryFn: () => fetchJSON(`/metrics/nodes/${nodeName}/history`),
-    enabled: Boolean(nodeName),
-    staleTime: 25000,
-    refetchInterval: 30000,
+// Queries container registries, so it only runs when explicitly enabled
+export function useImageUpdates(namespaces: string[] = [], enabled = false, refresh = false) {
+  const params = new URLSearchParams()
+  if (namespaces.length > 0) params.set('namespaces', namespaces.join(','))
+  if (refresh) params.set('refresh', 'true')
+  return useQuery<ImageUpdatesReport>({
+    queryKey: ['image-updates', namespaces, refresh],
+    queryFn: () => fetchJSON(`/images/updates?${params}`),
+    enabled,
+    staleTime: 300000,
   })
 }
 
@@ -732,37 +2329,481 @@ export function useUpdateResource() {
       errorMessage: 'Failed to update resource',
       successMessage: 'Resource updated',
     },
-    onSuccess: (_, variables) => {
-      queryClient.invalidateQueries({ queryKey: ['resource', variables.kind, variables.namespace, variables.name] })
-      queryClient.invalidateQueries({ queryKey: ['resources', variables.kind] })
-      queryClient.invalidateQueries({ queryKey: ['topology'] })
+    onSuccess: (_, variables) => {
+      queryClient.invalidateQueries({ queryKey: ['resource', variables.kind, variables.namespace, variables.name] })
+      queryClient.invalidateQueries({ queryKey: ['resources', variables.kind] })
+      queryClient.invalidateQueries({ queryKey: ['topology'] })
+    },
+  })
+}
+
+export type PatchType = 'json' | 'merge' | 'strategic'
+
+const PATCH_CONTENT_TYPES: Record<PatchType, string> = {
+  json: 'application/json-patch+json',
+  merge: 'application/merge-patch+json',
+  strategic: 'application/strategic-merge-patch+json',
+}
+
+// Patch a resource without round-tripping the full YAML
+export function usePatchResource() {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async ({ kind, namespace, name, patchType, patch }: { kind: string; namespace: string; name: string; patchType: PatchType; patch: unknown }) => {
+      const response = await fetch(`${API_BASE}/resources/${kind}/${namespace}/${name}`, {
+        method: 'PATCH',
+        headers: { 'Content-Type': PATCH_CONTENT_TYPES[patchType] },
+        body: JSON.stringify(patch),
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Failed to patch resource',
+      successMessage: 'Resource updated',
+    },
+    onSuccess: (_, variables) => {
+      queryClient.invalidateQueries({ queryKey: ['resource', variables.kind, variables.namespace, variables.name] })
+      queryClient.invalidateQueries({ queryKey: ['resources', variables.kind] })
+      queryClient.invalidateQueries({ queryKey: ['topology'] })
+    },
+  })
+}
+
+export interface ValidationIssue {
+  field?: string
+  type?: string
+  message: string
+  webhook?: string
+}
+
+export type LintMode = 'warn' | 'strict'
+
+export interface LintFinding {
+  severity: 'error' | 'warning'
+  rule: string
+  field?: string
+  message: string
+}
+
+export interface ValidationResult {
+  valid: boolean
+  reason?: string
+  issues?: ValidationIssue[]
+  lint?: LintFinding[]
+}
+
+// Validate a resource via server-side dry-run (no changes are persisted).
+// With lint set, best-practice findings are included in the result.
+export function useValidateResource() {
+  return useMutation({
+    mutationFn: async ({ kind, namespace, name, yaml, lint }: { kind: string; namespace: string; name: string; yaml: string; lint?: LintMode }): Promise<ValidationResult> => {
+      const lintParam = lint ? `&lint=${lint}` : ''
+      const response = await fetch(`${API_BASE}/resources/${kind}/${namespace}/${name}?validateOnly=true${lintParam}`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'text/plain' },
+        body: yaml,
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+  })
+}
+
+// Delete a resource
+export type DeletePropagation = 'Background' | 'Foreground' | 'Orphan'
+
+export interface DependentResource {
+  kind: string
+  group?: string
+  namespace?: string
+  name: string
+  uid: string
+  owner: string // Kind/name it cascades from
+  depth: number // 1 for direct dependents
+  blocksOwnerDeletion?: boolean
+}
+
+export interface DeletePreview {
+  resource: OwnerLink
+  propagation: DeletePropagation // Effective policy (the kind's default when none was given)
+  deleted: DependentResource[]
+  orphaned: DependentResource[]
+  kept: DependentResource[] // Also owned by resources that aren't deleted
+  counts: Record<string, number> // Deleted dependents per kind
+  total: number
+  finalizers?: string[]
+  notes?: string[]
+}
+
+// What deleting a resource would cascade to, for the delete confirmation
+export function useDeletePreview(kind: string, namespace: string, name: string, propagation?: DeletePropagation, enabled = true) {
+  const params = new URLSearchParams()
+  if (propagation) params.set('propagation', propagation)
+  const queryString = params.toString()
+
+  return useQuery<DeletePreview>({
+    queryKey: ['delete-preview', kind, namespace, name, propagation],
+    queryFn: () => fetchJSON(`/resources/${kind}/${namespace || '_'}/${name}/delete-preview${queryString ? `?${queryString}` : ''}`),
+    enabled: enabled && Boolean(kind && name),
+  })
+}
+
+export function useDeleteResource() {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async ({ kind, namespace, name, propagation }: { kind: string; namespace: string; name: string; propagation?: DeletePropagation }) => {
+      const query = propagation ? `?propagation=${propagation}` : ''
+      const response = await fetch(`${API_BASE}/resources/${kind}/${namespace}/${name}${query}`, {
+        method: 'DELETE',
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      // DELETE returns 204 No Content, no body to parse
+      return { success: true }
+    },
+    meta: {
+      errorMessage: 'Failed to delete resource',
+      successMessage: 'Resource deleted',
+    },
+    onSuccess: (_, variables) => {
+      queryClient.invalidateQueries({ queryKey: ['resources', variables.kind] })
+      queryClient.invalidateQueries({ queryKey: ['topology'] })
+    },
+  })
+}
+
+// ============================================================================
+// Network diagnostics
+// ============================================================================
+
+export interface NetTestRequest {
+  namespace: string
+  pod: string
+  container?: string
+  useDebugContainer?: boolean
+  image?: string
+  type: 'tcp' | 'http' | 'dns'
+  host?: string
+  service?: string
+  serviceNamespace?: string
+  port?: number
+  path?: string
+  scheme?: 'http' | 'https'
+  timeoutSeconds?: number
+}
+
+export interface NetTestResult {
+  success: boolean
+  type: string
+  namespace: string
+  pod: string
+  container?: string
+  target: string
+  tool?: string
+  durationMs: number
+  exitCode: number
+  statusCode?: number
+  addresses?: string[]
+  output?: string
+  error?: string
+}
+
+// Run a connectivity probe (tcp connect / HTTP GET / DNS lookup) from inside a pod
+export function useNetTest() {
+  return useMutation({
+    mutationFn: async (req: NetTestRequest): Promise<NetTestResult> => {
+      const response = await fetch(`${API_BASE}/nettest`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(req),
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+  })
+}
+
+export interface PodRunRequest {
+  container?: string
+  command?: string[] // argv, no shell
+  script?: string // run with sh -c
+  stdin?: string
+  timeoutSeconds?: number // max 50
+}
+
+export interface PodRunResult {
+  command: string[]
+  container?: string
+  exitCode: number // -1 when timed out
+  stdout: string
+  stderr: string
+  durationMs: number
+  timedOut?: boolean
+  truncated?: boolean
+}
+
+// Run a one-shot command in a pod (no TTY), e.g. quick actions like "cat config"
+export function usePodRun() {
+  return useMutation({
+    mutationFn: async ({ namespace, name, ...req }: PodRunRequest & { namespace: string; name: string }): Promise<PodRunResult> => {
+      const response = await fetch(`${API_BASE}/pods/${namespace}/${name}/run`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(req),
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+  })
+}
+
+export interface JavaContainer {
+  name: string
+  image: string
+  imageHint: boolean // Image name or env look like a Java image
+  java: boolean
+  processes?: { pid: number; command: string }[]
+  tools?: string[] // jcmd, jstack, jmap
+  error?: string
+}
+
+export interface JavaDumpResult {
+  container: string
+  pid: number
+  tool: string
+  path?: string
+  sizeBytes?: number
+  downloadUrl?: string // Archive endpoint for the dump file
+  message?: string
+}
+
+// Detect JVMs in a pod's containers (for thread/heap dump actions)
+export function usePodJava(namespace: string, name: string, enabled = true) {
+  return useQuery<{ containers: JavaContainer[] }>({
+    queryKey: ['pod-java', namespace, name],
+    queryFn: () => fetchJSON(`/pods/${namespace}/${name}/java`),
+    enabled: enabled && Boolean(namespace && name),
+    staleTime: 60000,
+  })
+}
+
+// Capture a thread or heap dump into the pod filesystem
+export function useJavaDump() {
+  return useMutation({
+    mutationFn: async ({ namespace, name, type, ...req }: { namespace: string; name: string; type: 'thread-dump' | 'heap-dump'; container?: string; pid?: number; all?: boolean }): Promise<JavaDumpResult> => {
+      const response = await fetch(`${API_BASE}/pods/${namespace}/${name}/java/${type}`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(req),
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Failed to capture dump',
+    },
+  })
+}
+
+export interface PprofProfile {
+  id: string
+  namespace: string
+  pod: string
+  port: number
+  type: string // profile (CPU), heap, goroutine, trace, ...
+  capturedAt: string
+  sizeBytes: number
+}
+
+// Ports serving net/http/pprof in a pod
+export function usePodPprof(namespace: string, name: string, enabled = true) {
+  return useQuery<{ ports: number[]; probed: number[] }>({
+    queryKey: ['pod-pprof', namespace, name],
+    queryFn: () => fetchJSON(`/pods/${namespace}/${name}/pprof`),
+    enabled: enabled && Boolean(namespace && name),
+    staleTime: 60000,
+  })
+}
+
+// Proxied pprof index of a pod's port
+export function pprofProxyUrl(namespace: string, name: string, port: number): string {
+  return `${API_BASE}/pods/${namespace}/${name}/pprof/${port}/`
+}
+
+export function usePprofProfiles(namespace: string, pod: string) {
+  return useQuery<PprofProfile[]>({
+    queryKey: ['pprof-profiles', namespace, pod],
+    queryFn: () => fetchJSON(`/pprof/profiles?namespace=${encodeURIComponent(namespace)}&pod=${encodeURIComponent(pod)}`),
+    enabled: Boolean(namespace && pod),
+  })
+}
+
+export function pprofProfileUrl(id: string): string {
+  return `${API_BASE}/pprof/profiles/${encodeURIComponent(id)}`
+}
+
+// Capture a profile (default: 30s CPU profile) and save it for download
+export function useCapturePprof() {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async ({ namespace, name, port, type = 'profile', seconds }: { namespace: string; name: string; port: number; type?: string; seconds?: number }): Promise<PprofProfile> => {
+      const params = new URLSearchParams({ port: String(port), type })
+      if (seconds) params.set('seconds', String(seconds))
+      const response = await fetch(`${API_BASE}/pods/${namespace}/${name}/pprof/capture?${params}`, { method: 'POST' })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Failed to capture profile',
+      successMessage: 'Profile saved',
+    },
+    onSuccess: (_, variables) => {
+      queryClient.invalidateQueries({ queryKey: ['pprof-profiles', variables.namespace, variables.name] })
+    },
+  })
+}
+
+export interface LoggerLevel {
+  name: string
+  configuredLevel?: string
+  effectiveLevel: string
+}
+
+export interface PodLogLevel {
+  pod: string
+  type: 'spring' | 'zap' | ''
+  port: number
+  path: string
+  level?: string // zap level, or Spring's ROOT effective level
+  loggers?: LoggerLevel[]
+  levels?: string[]
+  revertAt?: string
+  error?: string
+}
+
+export interface LogLevelRequest {
+  logger?: string // Spring logger, default ROOT
+  level: string // Empty resets a Spring logger
+  revertAfterMinutes?: number
+}
+
+// Runtime log levels of a pod (annotated radar.skyhook.io/log-level)
+export function usePodLogLevel(namespace: string, name: string, logger = '', enabled = true) {
+  return useQuery<PodLogLevel>({
+    queryKey: ['pod-log-level', namespace, name, logger],
+    queryFn: () => fetchJSON(`/pods/${namespace}/${name}/log-level${logger ? `?logger=${encodeURIComponent(logger)}` : ''}`),
+    enabled: enabled && Boolean(namespace && name),
+  })
+}
+
+// Set the log level of a pod, or of every running pod of a workload when kind is given
+export function useSetLogLevel() {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async ({ kind, namespace, name, ...req }: LogLevelRequest & { kind?: string; namespace: string; name: string }): Promise<PodLogLevel | { pods: PodLogLevel[]; failed: number }> => {
+      const path = kind ? `/workloads/${kind}/${namespace}/${name}/log-level` : `/pods/${namespace}/${name}/log-level`
+      const response = await fetch(`${API_BASE}${path}`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(req),
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Failed to set log level',
+      successMessage: 'Log level updated',
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['pod-log-level'] })
     },
   })
 }
 
-// Delete a resource
-export function useDeleteResource() {
-  const queryClient = useQueryClient()
+// ============================================================================
+// Admission simulation
+// ============================================================================
+
+export interface AdmissionSimulation {
+  apiVersion: string
+  kind: string
+  namespace?: string
+  name: string
+  operation: 'CREATE' | 'UPDATE'
+  dryRun: ValidationResult
+  dryRunError?: string
+  mutations?: string[]
+  webhooks: Array<{
+    name: string
+    configuration: string
+    type: 'mutating' | 'validating'
+    failurePolicy?: string
+    sideEffects?: string
+    backend?: string
+    conditional?: boolean
+  }>
+  policies: Array<{
+    engine: 'ValidatingAdmissionPolicy' | 'Gatekeeper' | 'Kyverno'
+    kind: string
+    name: string
+    action?: string
+    rules?: string[]
+  }>
+  resourceQuotas: Array<{ name: string; resource: string; used: string; hard: string; exhausted: boolean }>
+  limitRanges: Array<{
+    name: string
+    type: string
+    default?: Record<string, string>
+    defaultRequest?: Record<string, string>
+    min?: Record<string, string>
+    max?: Record<string, string>
+  }>
+  warnings?: string[]
+}
 
+// Pre-flight a manifest: which webhooks, policies, quotas and limit ranges apply, plus a server-side dry-run
+export function useSimulateAdmission() {
   return useMutation({
-    mutationFn: async ({ kind, namespace, name }: { kind: string; namespace: string; name: string }) => {
-      const response = await fetch(`${API_BASE}/resources/${kind}/${namespace}/${name}`, {
-        method: 'DELETE',
+    mutationFn: async ({ manifest, namespace }: { manifest: string; namespace?: string }): Promise<AdmissionSimulation> => {
+      const params = namespace ? `?namespace=${encodeURIComponent(namespace)}` : ''
+      const response = await fetch(`${API_BASE}/admission/simulate${params}`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/yaml' },
+        body: manifest,
       })
       if (!response.ok) {
         const error = await response.json().catch(() => ({ error: 'Unknown error' }))
         throw new Error(error.error || `HTTP ${response.status}`)
       }
-      // DELETE returns 204 No Content, no body to parse
-      return { success: true }
-    },
-    meta: {
-      errorMessage: 'Failed to delete resource',
-      successMessage: 'Resource deleted',
-    },
-    onSuccess: (_, variables) => {
-      queryClient.invalidateQueries({ queryKey: ['resources', variables.kind] })
-      queryClient.invalidateQueries({ queryKey: ['topology'] })
+      return response.json()
     },
   })
 }
@@ -880,6 +2921,115 @@ export function useRestartWorkload() {
   })
 }
 
+export interface RestartTarget {
+  kind: string
+  namespace: string
+  name: string
+}
+
+export interface RestartPlan {
+  waves: RestartTarget[][]
+  dependencies?: { from: RestartTarget; to: RestartTarget; via: string }[]
+  notes?: string[]
+}
+
+export interface BulkRestartRequest {
+  items: RestartTarget[]
+  ordered: boolean // Restart dependencies first, waiting for each wave to roll out
+  timeoutSeconds?: number
+}
+
+export interface RestartProgressEvent {
+  wave: number
+  target: RestartTarget
+  phase: 'restarting' | 'restarted' | 'waiting' | 'ready' | 'failed'
+  message?: string
+}
+
+export interface BulkRestartResult {
+  total: number
+  succeeded: number
+  failed: number
+  results: (RestartTarget & { success: boolean; error?: string })[]
+}
+
+export function useBulkRestartPlan(req: BulkRestartRequest, enabled = true) {
+  return useQuery<RestartPlan>({
+    queryKey: ['restart-plan', req],
+    queryFn: async () => {
+      const response = await fetch(`${API_BASE}/workloads/restart/plan`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(req),
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+    enabled: enabled && req.items.length > 0,
+  })
+}
+
+// Restart several workloads, streaming the plan and per-workload progress via SSE
+export function restartWorkloadsWithProgress(
+  req: BulkRestartRequest,
+  handlers: { onPlan?: (plan: RestartPlan) => void; onProgress?: (event: RestartProgressEvent) => void }
+): Promise<BulkRestartResult> {
+  return new Promise((resolve, reject) => {
+    fetch(`${API_BASE}/workloads/restart`, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify(req),
+    })
+      .then(async (response) => {
+        if (!response.ok) {
+          const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+          reject(new Error(error.error || `HTTP ${response.status}`))
+          return
+        }
+
+        const reader = response.body?.getReader()
+        if (!reader) {
+          reject(new Error('No response body'))
+          return
+        }
+
+        const decoder = new TextDecoder()
+        let buffer = ''
+        let event = ''
+
+        while (true) {
+          const { done, value } = await reader.read()
+          if (done) break
+
+          buffer += decoder.decode(value, { stream: true })
+          const lines = buffer.split('\n')
+          buffer = lines.pop() || ''
+
+          for (const line of lines) {
+            if (line.startsWith('event: ')) {
+              event = line.slice(7)
+            } else if (line.startsWith('data: ')) {
+              try {
+                const data = JSON.parse(line.slice(6))
+                if (event === 'plan') handlers.onPlan?.(data)
+                else if (event === 'progress') handlers.onProgress?.(data)
+                else if (event === 'complete') resolve(data)
+                else if (event === 'error') reject(new Error(data.error || 'Restart failed'))
+              } catch {
+                // Ignore parse errors
+              }
+            }
+          }
+        }
+        reject(new Error('Restart stream ended unexpectedly'))
+      })
+      .catch(reject)
+  })
+}
+
 // Scale a workload (Deployment, StatefulSet)
 export function useScaleWorkload() {
   const queryClient = useQueryClient()
@@ -962,6 +3112,52 @@ export function useHelmValues(namespace: string, name: string, allValues?: boole
   })
 }
 
+// Get the values.schema.json of a release's chart, for building a values form
+export function useHelmValuesSchema(namespace: string, name: string) {
+  return useQuery<HelmValuesSchema>({
+    queryKey: ['helm-values-schema', namespace, name],
+    queryFn: () => fetchJSON(`/helm/releases/${namespace}/${name}/schema`),
+    enabled: Boolean(namespace && name),
+    staleTime: 60000,
+  })
+}
+
+// Thrown when values don't match the chart's values.schema.json (HTTP 422)
+export class HelmValuesError extends Error {
+  violations: ValuesViolation[]
+
+  constructor(message: string, violations: ValuesViolation[]) {
+    super(message)
+    this.name = 'HelmValuesError'
+    this.violations = violations
+  }
+}
+
+async function helmResponseError(response: Response): Promise<Error> {
+  const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+  if (response.status === 422 && Array.isArray(error.violations)) {
+    return new HelmValuesError(error.error, error.violations)
+  }
+  return new Error(error.error || `HTTP ${response.status}`)
+}
+
+// Validate values against a release's chart schema without applying them
+export function useHelmValidateValues() {
+  return useMutation<ValuesValidation, Error, { namespace: string; name: string; values: Record<string, unknown> }>({
+    mutationFn: async ({ namespace, name, values }) => {
+      const response = await fetch(`${API_BASE}/helm/releases/${namespace}/${name}/values/validate`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ values }),
+      })
+      if (!response.ok) {
+        throw await helmResponseError(response)
+      }
+      return response.json()
+    },
+  })
+}
+
 // Get diff between two revisions
 export function useHelmManifestDiff(
   namespace: string,
@@ -1062,13 +3258,15 @@ export function useHelmUpgrade() {
   const queryClient = useQueryClient()
 
   return useMutation({
-    mutationFn: async ({ namespace, name, version }: { namespace: string; name: string; version: string }) => {
-      const response = await fetch(`${API_BASE}/helm/releases/${namespace}/${name}/upgrade?version=${encodeURIComponent(version)}`, {
+    // chart: oci:// reference, required for releases installed from an OCI registry
+    mutationFn: async ({ namespace, name, version, chart }: { namespace: string; name: string; version: string; chart?: string }) => {
+      const params = new URLSearchParams({ version })
+      if (chart) params.set('chart', chart)
+      const response = await fetch(`${API_BASE}/helm/releases/${namespace}/${name}/upgrade?${params.toString()}`, {
         method: 'POST',
       })
       if (!response.ok) {
-        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-        throw new Error(error.error || `HTTP ${response.status}`)
+        throw await helmResponseError(response)
       }
       return response.json()
     },
@@ -1095,8 +3293,7 @@ export function useHelmPreviewValues() {
         body: JSON.stringify({ values }),
       })
       if (!response.ok) {
-        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-        throw new Error(error.error || `HTTP ${response.status}`)
+        throw await helmResponseError(response)
       }
       return response.json()
     },
@@ -1115,8 +3312,7 @@ export function useHelmApplyValues() {
         body: JSON.stringify({ values }),
       })
       if (!response.ok) {
-        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-        throw new Error(error.error || `HTTP ${response.status}`)
+        throw await helmResponseError(response)
       }
       return response.json()
     },
@@ -1198,6 +3394,88 @@ export function useChartDetail(repo: string, chart: string, version?: string, en
   })
 }
 
+// Get an OCI chart's detail (ref: oci://registry/path/chart)
+export function useOCIChartDetail(ref: string, version?: string, enabled = true) {
+  return useQuery<ChartDetail>({
+    queryKey: ['helm-oci-chart', ref, version],
+    queryFn: () => {
+      const params = new URLSearchParams({ ref })
+      if (version) params.set('version', version)
+      return fetchJSON(`/helm/oci/chart?${params.toString()}`)
+    },
+    enabled: enabled && ref.startsWith('oci://'),
+  })
+}
+
+// List an OCI chart's versions (semver tags, newest first)
+export function useOCIChartVersions(ref: string, enabled = true) {
+  return useQuery<{ ref: string; versions: string[] }>({
+    queryKey: ['helm-oci-versions', ref],
+    queryFn: () => fetchJSON(`/helm/oci/versions?ref=${encodeURIComponent(ref)}`),
+    enabled: enabled && ref.startsWith('oci://'),
+  })
+}
+
+// List OCI registries with stored credentials
+export function useHelmRegistries() {
+  return useQuery<HelmRegistryLogins>({
+    queryKey: ['helm-registries'],
+    queryFn: () => fetchJSON('/helm/registries'),
+  })
+}
+
+// Log in to an OCI registry; credentials are verified before being stored
+export function useHelmRegistryLogin() {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async (req: HelmRegistryLoginRequest) => {
+      const response = await fetch(`${API_BASE}/helm/registries/login`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(req),
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Registry login failed',
+      successMessage: 'Logged in to registry',
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['helm-registries'] })
+    },
+  })
+}
+
+// Remove stored credentials for an OCI registry
+export function useHelmRegistryLogout() {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: async (host: string) => {
+      const response = await fetch(`${API_BASE}/helm/registries/${encodeURIComponent(host)}`, {
+        method: 'DELETE',
+      })
+      if (!response.ok) {
+        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+        throw new Error(error.error || `HTTP ${response.status}`)
+      }
+      return response.json()
+    },
+    meta: {
+      errorMessage: 'Registry logout failed',
+      successMessage: 'Logged out of registry',
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['helm-registries'] })
+    },
+  })
+}
+
 // Install a new chart (non-streaming)
 export function useInstallChart() {
   const queryClient = useQueryClient()
@@ -1210,8 +3488,7 @@ export function useInstallChart() {
         body: JSON.stringify(req),
       })
       if (!response.ok) {
-        const error = await response.json().catch(() => ({ error: 'Unknown error' }))
-        throw new Error(error.error || `HTTP ${response.status}`)
+        throw await helmResponseError(response)
       }
       return response.json() as Promise<HelmRelease>
     },
@@ -1232,19 +3509,116 @@ export interface InstallProgressEvent {
   message?: string
   detail?: string
   release?: HelmRelease
+  violations?: ValuesViolation[] // Set on errors from values.schema.json validation
+}
+
+// Install a chart with progress streaming via SSE
+export function installChartWithProgress(
+  req: InstallChartRequest,
+  onProgress: (event: InstallProgressEvent) => void
+): Promise<HelmRelease> {
+  return new Promise((resolve, reject) => {
+    // Use fetch with streaming response since we need to POST
+    fetch(`${API_BASE}/helm/releases/install-stream`, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify(req),
+    })
+      .then(async (response) => {
+        if (!response.ok) {
+          const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+          reject(new Error(error.error || `HTTP ${response.status}`))
+          return
+        }
+
+        const reader = response.body?.getReader()
+        if (!reader) {
+          reject(new Error('No response body'))
+          return
+        }
+
+        const decoder = new TextDecoder()
+        let buffer = ''
+
+        while (true) {
+          const { done, value } = await reader.read()
+          if (done) break
+
+          buffer += decoder.decode(value, { stream: true })
+
+          // Parse SSE events from buffer
+          const lines = buffer.split('\n')
+          buffer = lines.pop() || '' // Keep incomplete line in buffer
+
+          for (const line of lines) {
+            if (line.startsWith('data: ')) {
+              try {
+                const data = JSON.parse(line.slice(6)) as InstallProgressEvent
+                onProgress(data)
+
+                if (data.type === 'complete' && data.release) {
+                  resolve(data.release)
+                } else if (data.type === 'error') {
+                  reject(data.violations
+                    ? new HelmValuesError(data.message || 'Invalid values', data.violations)
+                    : new Error(data.message || 'Install failed'))
+                }
+              } catch {
+                // Ignore parse errors
+              }
+            }
+          }
+        }
+      })
+      .catch(reject)
+  })
+}
+
+// Helm test hook result (hooks annotated helm.sh/hook: test)
+export interface HelmTestHookResult {
+  name: string
+  kind: string
+  phase: string // Running | Succeeded | Failed | Unknown
+  startedAt?: string
+  completedAt?: string
+}
+
+export interface HelmTestResult {
+  release: string
+  namespace: string
+  revision: number
+  passed: boolean
+  error?: string
+  hooks: HelmTestHookResult[]
+}
+
+// Helm test stream event types
+export interface HelmTestEvent {
+  type: 'progress' | 'log' | 'complete' | 'error'
+  phase?: string
+  message?: string
+  hook?: string
+  container?: string
+  content?: string
+  result?: HelmTestResult
 }
 
-// Install a chart with progress streaming via SSE
-export function installChartWithProgress(
-  req: InstallChartRequest,
-  onProgress: (event: InstallProgressEvent) => void
-): Promise<HelmRelease> {
+// Run a release's test hooks (helm test), streaming hook pod logs via SSE.
+// A failing test resolves with passed=false; only errors running it reject.
+export function runHelmTests(
+  namespace: string,
+  name: string,
+  onEvent: (event: HelmTestEvent) => void,
+  options?: { timeout?: number; filter?: string[] }
+): Promise<HelmTestResult> {
+  const params = new URLSearchParams()
+  if (options?.timeout) params.set('timeout', String(options.timeout))
+  if (options?.filter?.length) params.set('filter', options.filter.join(','))
+  const queryString = params.toString()
+
   return new Promise((resolve, reject) => {
-    // Use fetch with streaming response since we need to POST
-    fetch(`${API_BASE}/helm/releases/install-stream`, {
+    fetch(`${API_BASE}/helm/releases/${namespace}/${name}/test${queryString ? `?${queryString}` : ''}`, {
       method: 'POST',
-      headers: { 'Content-Type': 'application/json' },
-      body: JSON.stringify(req),
     })
       .then(async (response) => {
         if (!response.ok) {
@@ -1268,20 +3642,19 @@ export function installChartWithProgress(
 
           buffer += decoder.decode(value, { stream: true })
 
-          // Parse SSE events from buffer
           const lines = buffer.split('\n')
-          buffer = lines.pop() || '' // Keep incomplete line in buffer
+          buffer = lines.pop() || ''
 
           for (const line of lines) {
             if (line.startsWith('data: ')) {
               try {
-                const data = JSON.parse(line.slice(6)) as InstallProgressEvent
-                onProgress(data)
+                const data = JSON.parse(line.slice(6)) as HelmTestEvent
+                onEvent(data)
 
-                if (data.type === 'complete' && data.release) {
-                  resolve(data.release)
+                if (data.type === 'complete' && data.result) {
+                  resolve(data.result)
                 } else if (data.type === 'error') {
-                  reject(new Error(data.message || 'Install failed'))
+                  reject(new Error(data.message || 'Helm test failed'))
                 }
               } catch {
                 // Ignore parse errors
@@ -1490,6 +3863,91 @@ export function useArgoRefresh() {
   })
 }
 
+// ============================================================================
+// Argo Workflows API hooks
+// ============================================================================
+
+export interface WorkflowNode {
+  id: string
+  name: string
+  displayName: string
+  type: string // Pod, DAG, Steps, StepGroup, Retry, Skipped, Suspend, ...
+  templateName?: string
+  phase: string
+  message?: string
+  startedAt?: string
+  finishedAt?: string
+  durationSeconds?: number
+  progress?: string
+  podName?: string
+  boundaryId?: string
+  children?: string[]
+}
+
+export interface WorkflowDAG {
+  namespace: string
+  name: string
+  phase: string
+  message?: string
+  startedAt?: string
+  finishedAt?: string
+  durationSeconds?: number
+  progress?: string
+  shutdown?: string
+  nodes: WorkflowNode[]
+  edges: Array<{ from: string; to: string }>
+}
+
+type WorkflowVars = { namespace: string; name: string }
+
+const workflowInvalidateKeys = (v: WorkflowVars) => [
+  ['resources', 'workflows'],
+  ['resource', 'workflows', v.namespace, v.name],
+  ['workflow-dag', v.namespace, v.name],
+]
+
+export function useWorkflowDAG(namespace: string, name: string, enabled = true) {
+  return useQuery<WorkflowDAG>({
+    queryKey: ['workflow-dag', namespace, name],
+    queryFn: () => fetchJSON(`/argo/workflows/${namespace}/${name}/dag`),
+    enabled: enabled && Boolean(namespace && name),
+    staleTime: 2000,
+    refetchInterval: (query) => {
+      const phase = query.state.data?.phase
+      return phase === 'Running' || phase === 'Pending' ? 3000 : false
+    },
+  })
+}
+
+export const useWorkflowRetry = createGitOpsMutation<WorkflowVars>({
+  getPath: (v) => `/argo/workflows/${v.namespace}/${v.name}/retry`,
+  errorMessage: 'Failed to retry workflow',
+  successMessage: 'Workflow retry started',
+  getInvalidateKeys: workflowInvalidateKeys,
+})
+
+export const useWorkflowTerminate = createGitOpsMutation<WorkflowVars & { mode?: 'terminate' | 'stop' }>({
+  getPath: (v) => `/argo/workflows/${v.namespace}/${v.name}/terminate${v.mode ? `?mode=${v.mode}` : ''}`,
+  errorMessage: 'Failed to terminate workflow',
+  successMessage: 'Workflow shutdown requested',
+  getInvalidateKeys: workflowInvalidateKeys,
+})
+
+// Create SSE connection for streaming a workflow step's pod logs
+export function createWorkflowStepLogStream(
+  namespace: string,
+  name: string,
+  nodeId: string,
+  options?: { container?: string; tailLines?: number }
+): EventSource {
+  const params = new URLSearchParams()
+  if (options?.container) params.set('container', options.container)
+  if (options?.tailLines) params.set('tailLines', String(options.tailLines))
+  const queryString = params.toString()
+
+  return new EventSource(`${API_BASE}/argo/workflows/${namespace}/${name}/nodes/${encodeURIComponent(nodeId)}/logs/stream${queryString ? `?${queryString}` : ''}`)
+}
+
 // ============================================================================
 // Context Switching API hooks
 // ============================================================================
@@ -1503,6 +3961,37 @@ export function useContexts() {
   })
 }
 
+export interface NamespaceResource {
+  kind: string
+  name: string
+}
+
+export interface NamespaceDiff {
+  namespace: string
+  leftContext: string
+  rightContext: string
+  leftMissing?: boolean
+  rightMissing?: boolean
+  onlyLeft: NamespaceResource[]
+  onlyRight: NamespaceResource[]
+  imageDifferences: { kind: string; name: string; container: string; left: string; right: string }[]
+  replicaDrift: { kind: string; name: string; left: number; right: number; leftReady: number; rightReady: number }[]
+  skipped?: string[]
+  inParity: boolean
+}
+
+// Compare a namespace across two contexts (e.g. prod vs DR); left defaults to the current context
+export function useNamespaceDiff(namespace: string, rightContext: string, leftContext?: string) {
+  const params = new URLSearchParams({ right: rightContext })
+  if (leftContext) params.set('left', leftContext)
+  return useQuery<NamespaceDiff>({
+    queryKey: ['namespace-diff', namespace, leftContext ?? '', rightContext],
+    queryFn: () => fetchJSON(`/namespaces/${encodeURIComponent(namespace)}/compare?${params}`),
+    enabled: Boolean(namespace && rightContext),
+    staleTime: 30000,
+  })
+}
+
 // Session counts for context switch confirmation
 export interface SessionCounts {
   portForwards: number
@@ -1515,6 +4004,61 @@ export async function fetchSessionCounts(): Promise<SessionCounts> {
   return fetchJSON('/sessions')
 }
 
+// Active exec sessions; shared ones can be watched read-only with the owner's approval
+export interface ExecSessionInfo {
+  id: string
+  namespace: string
+  pod: string
+  container: string
+  startedAt: string
+  client: string
+  lastActivity: string
+  shared: boolean
+  observers: number
+}
+
+export async function fetchExecSessions(): Promise<ExecSessionInfo[]> {
+  return fetchJSON('/exec/sessions')
+}
+
+export interface ActivePortForward {
+  id: string
+  namespace: string
+  podName: string
+  podPort: number
+  localPort: number
+  serviceName?: string
+  startedAt: string
+  status: 'running' | 'stopped' | 'error'
+  client: string
+  lastActivity: string
+}
+
+// Interactive sessions with their owning client, plus the configured limits (0 = unlimited)
+export interface ActiveSessions {
+  exec: ExecSessionInfo[]
+  portForwards: ActivePortForward[]
+  limits: {
+    maxExecPerClient: number
+    maxPortForwardsPerClient: number
+    execIdleTimeout?: string
+    portForwardIdleTimeout?: string
+  }
+}
+
+export async function fetchActiveSessions(): Promise<ActiveSessions> {
+  return fetchJSON('/sessions/active')
+}
+
+// Force-close an exec session or port forward by ID
+export async function closeSession(id: string): Promise<void> {
+  const response = await fetch(`${API_BASE}/sessions/${encodeURIComponent(id)}`, { method: 'DELETE' })
+  if (!response.ok) {
+    const errorData = await response.json().catch(() => ({ error: 'Unknown error' }))
+    throw new ApiError(errorData.error || `HTTP ${response.status}`, response.status, errorData)
+  }
+}
+
 // Context switch timeout in milliseconds (should be longer than backend timeout)
 const CONTEXT_SWITCH_TIMEOUT = 45000 // 45 seconds
 
@@ -1688,16 +4232,48 @@ export async function downloadPodFile(
   return response.blob()
 }
 
+export interface PodFileInfo {
+  path: string
+  size: number
+  permissions: string
+  uid: string
+  gid: string
+  binary: boolean
+  editable: boolean
+  reason?: string
+  maxEditSize: number
+}
+
+export async function getPodFileInfo(
+  namespace: string,
+  podName: string,
+  container: string,
+  filePath: string
+): Promise<PodFileInfo> {
+  const params = new URLSearchParams()
+  if (container) params.set('container', container)
+  params.set('path', filePath)
+  return fetchJSON(`/pods/${namespace}/${podName}/filesystem/file/info?${params.toString()}`)
+}
+
+export interface SavePodFileOptions {
+  backup?: boolean // Copy the file to <path>.bak before overwriting
+  force?: boolean // Overwrite a file that looks binary
+}
+
 export async function savePodFile(
   namespace: string,
   podName: string,
   container: string,
   filePath: string,
-  content: string
-): Promise<void> {
+  content: string,
+  options: SavePodFileOptions = {}
+): Promise<{ ok: boolean; backup?: string }> {
   const params = new URLSearchParams()
   if (container) params.set('container', container)
   params.set('path', filePath)
+  if (options.backup) params.set('backup', 'true')
+  if (options.force) params.set('force', 'true')
 
   const response = await fetch(`${API_BASE}/pods/${namespace}/${podName}/filesystem/file?${params.toString()}`, {
     method: 'PUT',
@@ -1708,6 +4284,32 @@ export async function savePodFile(
     const errorData = await response.json().catch(() => ({ error: 'Unknown error' }))
     throw new ApiError(errorData.error || `HTTP ${response.status}`, response.status, errorData)
   }
+  return response.json()
+}
+
+// Creates a new file; fails with 409 if the path already exists
+export async function createPodFile(
+  namespace: string,
+  podName: string,
+  container: string,
+  filePath: string,
+  content: string,
+  mode?: string
+): Promise<void> {
+  const params = new URLSearchParams()
+  if (container) params.set('container', container)
+  params.set('path', filePath)
+  if (mode) params.set('mode', mode)
+
+  const response = await fetch(`${API_BASE}/pods/${namespace}/${podName}/filesystem/file?${params.toString()}`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'text/plain; charset=utf-8' },
+    body: content,
+  })
+  if (!response.ok) {
+    const errorData = await response.json().catch(() => ({ error: 'Unknown error' }))
+    throw new ApiError(errorData.error || `HTTP ${response.status}`, response.status, errorData)
+  }
 }
 
 export async function downloadPodArchive(
@@ -1753,6 +4355,101 @@ export async function uploadPodFile(
   }
 }
 
+// Files above this go through the chunked upload API instead of one request
+export const CHUNKED_UPLOAD_THRESHOLD = 16 * 1024 * 1024
+const UPLOAD_CHUNK_SIZE = 4 * 1024 * 1024
+const UPLOAD_CHUNK_RETRIES = 5
+
+export interface PodUploadState {
+  id: string
+  namespace: string
+  podName: string
+  container?: string
+  path: string
+  size: number
+  offset: number
+  done: boolean
+  error?: string
+  startedAt: string
+}
+
+export interface ChunkedUploadOptions {
+  onProgress?: (state: PodUploadState) => void
+  resume?: boolean // Continue from a partial file left by an interrupted upload
+  signal?: AbortSignal
+}
+
+async function uploadRequest(url: string, init: RequestInit): Promise<Response> {
+  const response = await fetch(url, init)
+  if (!response.ok && response.status !== 409) {
+    const errorData = await response.json().catch(() => ({ error: 'Unknown error' }))
+    throw new ApiError(errorData.error || `HTTP ${response.status}`, response.status, errorData)
+  }
+  return response
+}
+
+// Uploads a large file in chunks. A failed chunk is retried from the offset
+// the server reports, so flaky connections resume instead of starting over.
+export async function uploadPodFileChunked(
+  namespace: string,
+  podName: string,
+  container: string,
+  destinationPath: string,
+  file: File,
+  options: ChunkedUploadOptions = {}
+): Promise<PodUploadState> {
+  const { onProgress, resume = true, signal } = options
+  const startResponse = await uploadRequest(`${API_BASE}/pods/${namespace}/${podName}/filesystem/uploads`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ container, path: destinationPath, size: file.size, resume }),
+    signal,
+  })
+  if (startResponse.status === 409) {
+    const errorData = await startResponse.json().catch(() => ({ error: 'Unknown error' }))
+    throw new ApiError(errorData.error || 'HTTP 409', 409, errorData)
+  }
+  let state: PodUploadState = await startResponse.json()
+  onProgress?.(state)
+
+  let failures = 0
+  while (!state.done) {
+    const chunk = file.slice(state.offset, state.offset + UPLOAD_CHUNK_SIZE)
+    try {
+      const response = await uploadRequest(`${API_BASE}/uploads/${state.id}?offset=${state.offset}`, {
+        method: 'PUT',
+        headers: { 'Content-Type': 'application/octet-stream' },
+        body: chunk,
+        signal,
+      })
+      // 409 carries the server's offset to continue from
+      state = await response.json()
+      failures = 0
+    } catch (err) {
+      if (signal?.aborted || (err instanceof ApiError && err.status !== 500) || ++failures > UPLOAD_CHUNK_RETRIES) {
+        throw err
+      }
+      state = await fetchJSON<PodUploadState>(`/uploads/${state.id}`)
+    }
+    onProgress?.(state)
+  }
+  return state
+}
+
+export async function cancelPodUpload(uploadId: string): Promise<void> {
+  const response = await fetch(`${API_BASE}/uploads/${uploadId}`, { method: 'DELETE' })
+  if (!response.ok && response.status !== 404) {
+    const errorData = await response.json().catch(() => ({ error: 'Unknown error' }))
+    throw new ApiError(errorData.error || `HTTP ${response.status}`, response.status, errorData)
+  }
+}
+
+// Progress of an upload driven elsewhere (another tab or client), as SSE
+// 'progress' events carrying PodUploadState
+export function createUploadProgressStream(uploadId: string): EventSource {
+  return new EventSource(`${API_BASE}/uploads/${uploadId}/events`)
+}
+
 export async function mkdirPodPath(
   namespace: string,
   podName: string,
@@ -1806,6 +4503,34 @@ export async function deletePodPath(
   }
 }
 
+export interface PodCopyTarget {
+  namespace?: string // Defaults to the source pod's namespace
+  pod: string
+  container?: string
+  path: string // Directory the source is copied into
+}
+
+// Copies a file or directory from one pod into a directory of another,
+// streamed server-side
+export async function copyPodPath(
+  namespace: string,
+  podName: string,
+  container: string,
+  sourcePath: string,
+  target: PodCopyTarget
+): Promise<{ ok: boolean; bytes: number; destination: string }> {
+  const response = await fetch(`${API_BASE}/pods/${namespace}/${podName}/filesystem/copy`, {
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json' },
+    body: JSON.stringify({ container, path: sourcePath, target }),
+  })
+  if (!response.ok) {
+    const errorData = await response.json().catch(() => ({ error: 'Unknown error' }))
+    throw new ApiError(errorData.error || `HTTP ${response.status}`, response.status, errorData)
+  }
+  return response.json()
+}
+
 // ============================================================================
 // Workload Logs (aggregated from all pods)
 // ============================================================================
@@ -1836,6 +4561,77 @@ export function useWorkloadPods(kind: string, namespace: string, name: string) {
   })
 }
 
+export type ManifestExportFormat = 'yaml' | 'kustomize' | 'helm'
+
+export interface ManifestExport {
+  format: ManifestExportFormat
+  kind: string
+  namespace: string
+  name: string
+  resources: string[]
+  skipped?: string[]
+  files: { path: string; content: string }[]
+}
+
+// Export a workload with its Services/ConfigMaps/Ingresses as cleaned manifests
+export function useWorkloadExport(kind: string, namespace: string, name: string, format: ManifestExportFormat = 'yaml', enabled = true) {
+  return useQuery<ManifestExport>({
+    queryKey: ['workload-export', kind, namespace, name, format],
+    queryFn: () => fetchJSON(`/workloads/${kind}/${namespace}/${name}/export?format=${format}`),
+    enabled: enabled && Boolean(kind && namespace && name),
+    staleTime: 30000,
+  })
+}
+
+export type SBOMFormat = 'spdx' | 'cyclonedx'
+
+// Download URL of a workload's SBOM; withExec also queries package managers in a running pod
+export function workloadSBOMUrl(kind: string, namespace: string, name: string, format: SBOMFormat = 'spdx', withExec = false) {
+  const params = new URLSearchParams({ format })
+  if (withExec) params.set('exec', 'true')
+  return `${API_BASE}/workloads/${kind}/${namespace}/${name}/sbom?${params}`
+}
+
+export interface WorkloadRef {
+  kind: string
+  namespace: string
+  name: string
+}
+
+export interface WorkloadRuntime {
+  pods: number
+  readyPods: number
+  restarts: number
+  phases?: Record<string, number>
+  runningImages?: string[]
+}
+
+export interface WorkloadDifference {
+  category: 'spec' | 'labels' | 'container' | 'runtime'
+  path: string
+  left: unknown
+  right: unknown
+}
+
+export interface WorkloadComparison {
+  left: WorkloadRef
+  right: WorkloadRef
+  leftRuntime: WorkloadRuntime
+  rightRuntime: WorkloadRuntime
+  differences: WorkloadDifference[]
+  identical: boolean
+}
+
+// Compare two workloads field by field; refs are "kind/namespace/name"
+export function useWorkloadComparison(left: string, right: string) {
+  return useQuery<WorkloadComparison>({
+    queryKey: ['workload-compare', left, right],
+    queryFn: () => fetchJSON(`/workloads/compare?left=${encodeURIComponent(left)}&right=${encodeURIComponent(right)}`),
+    enabled: Boolean(left && right),
+    staleTime: 10000,
+  })
+}
+
 // Fetch logs for a workload (non-streaming)
 export function useWorkloadLogs(
   kind: string,
@@ -1876,3 +4672,98 @@ export function createWorkloadLogStream(
 
   return new EventSource(`${API_BASE}/workloads/${kind}/${namespace}/${name}/logs/stream${queryString ? `?${queryString}` : ''}`)
 }
+
+// ============================================================================
+// Scale schedules
+// ============================================================================
+
+export interface ScaleSchedule {
+  name: string
+  context: string
+  namespaces?: string[]
+  workloads?: WorkloadRef[]
+  scaleDown: string // cron
+  scaleUp: string // cron
+  timezone?: string
+  disabled?: boolean
+  asleep: boolean
+  overrideUntil?: string
+  lastTransition?: string
+  lastError?: string
+  nextScaleDown?: string
+  nextScaleUp?: string
+}
+
+export type ScaleScheduleInput = Pick<ScaleSchedule, 'name' | 'namespaces' | 'workloads' | 'scaleDown' | 'scaleUp' | 'timezone' | 'disabled'> & {
+  context?: string
+}
+
+export interface ScaleScheduleResponse extends ScaleSchedule {
+  warning?: string
+}
+
+async function scheduleRequest(path: string, method: string, body?: unknown): Promise<ScaleScheduleResponse> {
+  const response = await fetch(`${API_BASE}/schedules/${path}`, {
+    method,
+    headers: body ? { 'Content-Type': 'application/json' } : undefined,
+    body: body ? JSON.stringify(body) : undefined,
+  })
+  if (!response.ok) {
+    const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+    throw new Error(error.error || `HTTP ${response.status}`)
+  }
+  return response.json()
+}
+
+export function useScaleSchedules() {
+  return useQuery<ScaleSchedule[]>({
+    queryKey: ['scale-schedules'],
+    queryFn: () => fetchJSON('/schedules'),
+    refetchInterval: 60000,
+  })
+}
+
+export function useSaveScaleSchedule() {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: (schedule: ScaleScheduleInput) => scheduleRequest(encodeURIComponent(schedule.name), 'PUT', schedule),
+    meta: {
+      errorMessage: 'Failed to save schedule',
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['scale-schedules'] })
+    },
+  })
+}
+
+export function useDeleteScaleSchedule() {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: (name: string) => scheduleRequest(encodeURIComponent(name), 'DELETE'),
+    meta: {
+      errorMessage: 'Failed to delete schedule',
+      successMessage: 'Schedule deleted',
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['scale-schedules'] })
+    },
+  })
+}
+
+// Wake a schedule's workloads now and keep them up (by default through the sleep window)
+export function useScaleScheduleOverride() {
+  const queryClient = useQueryClient()
+
+  return useMutation({
+    mutationFn: ({ name, hours, end = false }: { name: string; hours?: number; end?: boolean }) =>
+      scheduleRequest(`${encodeURIComponent(name)}/override${hours ? `?hours=${hours}` : ''}`, end ? 'DELETE' : 'POST'),
+    meta: {
+      errorMessage: 'Failed to override schedule',
+    },
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['scale-schedules'] })
+    },
+  })
+}
@@ -34,23 +34,27 @@ export function useTrafficSources() {
 }
 
 // Get traffic flows
+// Which end of a flow must be in the selected namespaces (default: either)
+export type TrafficDirection = 'ingress' | 'egress' | 'both'
+
 export interface UseTrafficFlowsOptions {
   namespaces?: string[]
+  direction?: TrafficDirection
   since?: string // Duration like "5m", "1h"
   enabled?: boolean
 }
 
 export function useTrafficFlows(options: UseTrafficFlowsOptions = {}) {
-  const { namespaces = [], since, enabled = true } = options
+  const { namespaces = [], direction, since, enabled = true } = options
 
   const params = new URLSearchParams()
-  // Traffic backend only supports single namespace, use first if provided
-  if (namespaces.length === 1) params.set('namespace', namespaces[0])
+  if (namespaces.length > 0) params.set('namespaces', namespaces.join(','))
+  if (direction && direction !== 'both') params.set('direction', direction)
   if (since) params.set('since', since)
   const queryString = params.toString()
 
   return useQuery<TrafficFlowsResponse>({
-    queryKey: ['traffic-flows', namespaces, since],
+    queryKey: ['traffic-flows', namespaces, direction, since],
     queryFn: () => fetchJSON(`/traffic/flows${queryString ? `?${queryString}` : ''}`),
     staleTime: 5000, // 5 seconds
     enabled,
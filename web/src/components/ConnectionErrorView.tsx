@@ -94,6 +94,13 @@ export function ConnectionErrorView({ connection, onRetry, isRetrying }: Connect
             </ul>
           </div>
 
+          {connection.progressMessage && (
+            <p className="text-sm text-theme-text-secondary mb-4 flex items-center gap-2">
+              <Loader2 className="w-4 h-4 animate-spin" />
+              {connection.progressMessage}
+            </p>
+          )}
+
           {connection.error && (
             <div className="w-full bg-theme-elevated border border-theme-border rounded-lg p-3 mb-6 overflow-auto max-h-32">
               <code className="text-xs text-red-400 font-mono whitespace-pre-wrap break-all">
@@ -3,6 +3,7 @@ import { ChevronDown, Check, Loader2, Server, AlertTriangle, XCircle, Search, X
 import { useContexts, useSwitchContext, useClusterInfo, fetchSessionCounts, type SessionCounts } from '../api/client'
 import { useContextSwitch } from '../context/ContextSwitchContext'
 import { useDock } from '../components/dock'
+import { LocalClustersSection } from './LocalClustersSection'
 import type { ContextInfo } from '../types'
 
 interface SwitchError {
@@ -104,7 +105,7 @@ export function ContextSwitcher({ className = '' }: ContextSwitcherProps) {
   const dropdownRef = useRef<HTMLDivElement>(null)
   const searchInputRef = useRef<HTMLInputElement>(null)
 
-  const { data: contexts, isLoading: contextsLoading } = useContexts()
+  const { data: contexts, isLoading: contextsLoading, refetch: refetchContexts } = useContexts()
   const { data: clusterInfo } = useClusterInfo()
   const switchContext = useSwitchContext()
   const { startSwitch, endSwitch } = useContextSwitch()
@@ -281,6 +282,20 @@ export function ContextSwitcher({ className = '' }: ContextSwitcherProps) {
     performSwitch(parsed)
   }
 
+  // Switch to a local cluster's context; it may have just been added to the kubeconfig
+  const handleLocalClusterConnect = async (contextName: string) => {
+    let ctx = contexts?.find(c => c.name === contextName)
+    if (!ctx) {
+      const fresh = await refetchContexts()
+      ctx = fresh.data?.find(c => c.name === contextName)
+    }
+    if (!ctx) {
+      setSwitchError({ contextName, clusterName: contextName, message: `Context ${contextName} not found in the kubeconfig` })
+      return
+    }
+    handleContextSwitch({ context: ctx, ...parseContextName(ctx.name) })
+  }
+
   // Actually perform the context switch
   const performSwitch = async (parsed: ParsedContext) => {
     // Clear any previous error
@@ -491,6 +506,9 @@ export function ContextSwitcher({ className = '' }: ContextSwitcherProps) {
             </div>
           )}
 
+          {/* kind/minikube/k3d clusters on this machine */}
+          <LocalClustersSection onConnect={handleLocalClusterConnect} />
+
           {/* Error message if switch failed */}
           {switchContext.isError && (
             <div className="px-3 py-2 bg-red-500/10 border-t border-red-500/20">
@@ -0,0 +1,187 @@
+import { useState, useEffect, useRef } from 'react'
+import { useQueryClient } from '@tanstack/react-query'
+import { Loader2, Play, Square, Trash2, Plus, Plug } from 'lucide-react'
+import { useLocalClusters, useLocalClusterAction, type LocalCluster, type LocalClusterTool } from '../api/client'
+
+interface LocalClustersSectionProps {
+  // Called with the context name of a running cluster to switch to it
+  onConnect: (contextName: string) => void
+}
+
+// Lists kind/minikube/k3d clusters in the context switcher and manages them.
+// Renders nothing when none of the tools is installed.
+export function LocalClustersSection({ onConnect }: LocalClustersSectionProps) {
+  const queryClient = useQueryClient()
+  const { data } = useLocalClusters()
+  const action = useLocalClusterAction()
+  const [showCreate, setShowCreate] = useState(false)
+  const [newName, setNewName] = useState('')
+  const [newTool, setNewTool] = useState<LocalClusterTool | ''>('')
+
+  const availableTools = data?.tools.filter((t) => t.available).map((t) => t.tool) ?? []
+  const tool = newTool || availableTools[0]
+
+  // Operations add and remove kubeconfig contexts; refresh the list when they finish
+  const pending = data?.clusters.filter((c) => c.operation && !c.operation.finishedAt).length ?? 0
+  const prevPending = useRef(pending)
+  useEffect(() => {
+    if (pending < prevPending.current) {
+      queryClient.invalidateQueries({ queryKey: ['contexts'] })
+    }
+    prevPending.current = pending
+  }, [pending, queryClient])
+
+  if (!data || availableTools.length === 0) return null
+
+  const handleCreate = (e: React.FormEvent) => {
+    e.preventDefault()
+    if (!tool || !newName) return
+    action.mutate(
+      { tool, name: newName, action: 'create' },
+      {
+        onSuccess: () => {
+          setNewName('')
+          setShowCreate(false)
+        },
+      },
+    )
+  }
+
+  return (
+    <div className="border-t border-theme-border">
+      <div className="flex items-center justify-between px-3 py-1.5 bg-theme-elevated/30">
+        <span className="text-[10px] text-theme-text-tertiary font-medium">
+          Local clusters · {availableTools.join(', ')}
+        </span>
+        <button
+          type="button"
+          onClick={() => setShowCreate(!showCreate)}
+          className="text-theme-text-tertiary hover:text-theme-text-secondary"
+          title="Create a local cluster"
+        >
+          <Plus className="w-3.5 h-3.5" />
+        </button>
+      </div>
+
+      {showCreate && (
+        <form onSubmit={handleCreate} className="flex items-center gap-1.5 px-3 py-2">
+          <select
+            value={tool}
+            onChange={(e) => setNewTool(e.target.value as LocalClusterTool)}
+            className="bg-theme-base text-theme-text-primary text-xs rounded px-1.5 py-1 border border-theme-border-light"
+          >
+            {availableTools.map((t) => (
+              <option key={t} value={t}>{t}</option>
+            ))}
+          </select>
+          <input
+            type="text"
+            value={newName}
+            onChange={(e) => setNewName(e.target.value.toLowerCase())}
+            placeholder="cluster name"
+            pattern="[a-z0-9]([-a-z0-9]*[a-z0-9])?"
+            maxLength={32}
+            className="flex-1 min-w-0 bg-theme-base text-theme-text-primary text-xs rounded px-2 py-1 border border-theme-border-light focus:outline-none focus:ring-1 focus:ring-blue-500 placeholder:text-theme-text-tertiary"
+          />
+          <button
+            type="submit"
+            disabled={!newName || action.isPending}
+            className="px-2 py-1 text-xs rounded bg-blue-500 hover:bg-blue-600 text-white disabled:opacity-50"
+          >
+            Create
+          </button>
+        </form>
+      )}
+
+      {data.clusters.map((cluster) => (
+        <LocalClusterRow
+          key={`${cluster.tool}/${cluster.name}`}
+          cluster={cluster}
+          disabled={action.isPending}
+          onAction={(a) => {
+            if (a === 'delete' && !window.confirm(`Delete ${cluster.tool} cluster "${cluster.name}"? This can't be undone.`)) return
+            action.mutate({ tool: cluster.tool, name: cluster.name, action: a })
+          }}
+          onConnect={() => onConnect(cluster.context)}
+        />
+      ))}
+
+      {data.clusters.length === 0 && !showCreate && (
+        <div className="px-3 py-2 text-xs text-theme-text-tertiary">No local clusters yet</div>
+      )}
+
+      {Object.entries(data.errors ?? {}).map(([t, message]) => (
+        <div key={t} className="px-3 py-1 text-[10px] text-amber-400 truncate" title={message}>
+          {t}: {message}
+        </div>
+      ))}
+
+      {action.isError && (
+        <div className="px-3 py-1.5 text-xs text-red-400 bg-red-500/10">{action.error.message}</div>
+      )}
+    </div>
+  )
+}
+
+interface LocalClusterRowProps {
+  cluster: LocalCluster
+  disabled: boolean
+  onAction: (action: 'start' | 'stop' | 'delete') => void
+  onConnect: () => void
+}
+
+function LocalClusterRow({ cluster, disabled, onAction, onConnect }: LocalClusterRowProps) {
+  const running = cluster.operation && !cluster.operation.finishedAt
+  const failed = cluster.operation?.error
+  const busy = disabled || !!running
+  const dot = cluster.status === 'running' ? 'bg-green-500' : cluster.status === 'stopped' ? 'bg-theme-text-tertiary/50' : 'bg-amber-400'
+  const buttonClass = 'p-1 rounded text-theme-text-tertiary hover:text-theme-text-primary hover:bg-theme-hover disabled:opacity-40'
+
+  return (
+    <div className="px-3 py-1.5">
+      <div className="flex items-center gap-2">
+        <div className="shrink-0 w-4 h-4 flex items-center justify-center">
+          {running ? (
+            <Loader2 className="w-3.5 h-3.5 animate-spin text-blue-400" />
+          ) : (
+            <div className={`w-1.5 h-1.5 rounded-full ${dot}`} />
+          )}
+        </div>
+        <span className={`flex-1 min-w-0 text-sm truncate ${cluster.isCurrent ? 'text-blue-600 dark:text-blue-400' : 'text-theme-text-primary'}`}>
+          {cluster.name}
+        </span>
+        <span className="shrink-0 text-[10px] text-theme-text-tertiary bg-theme-elevated px-1 rounded">
+          {running ? `${cluster.operation!.action}…` : cluster.tool}
+        </span>
+        {cluster.status === 'running' && !cluster.isCurrent && (
+          <button type="button" onClick={onConnect} disabled={busy} className={buttonClass} title="Connect">
+            <Plug className="w-3.5 h-3.5" />
+          </button>
+        )}
+        {cluster.status === 'running' ? (
+          <button type="button" onClick={() => onAction('stop')} disabled={busy} className={buttonClass} title="Stop">
+            <Square className="w-3.5 h-3.5" />
+          </button>
+        ) : (
+          <button type="button" onClick={() => onAction('start')} disabled={busy} className={buttonClass} title="Start">
+            <Play className="w-3.5 h-3.5" />
+          </button>
+        )}
+        <button
+          type="button"
+          onClick={() => onAction('delete')}
+          disabled={busy || cluster.isCurrent}
+          className={buttonClass}
+          title={cluster.isCurrent ? 'Switch to another context to delete this cluster' : 'Delete'}
+        >
+          <Trash2 className="w-3.5 h-3.5" />
+        </button>
+      </div>
+      {failed && (
+        <div className="ml-6 mt-0.5 text-[10px] text-red-400 break-words">
+          {cluster.operation!.action} failed: {failed}
+        </div>
+      )}
+    </div>
+  )
+}
@@ -172,6 +172,7 @@ function TabContent({ tab, isActive }: { tab: DockTab; isActive: boolean }) {
         containerName={tab.containerName!}
         containers={tab.containers!}
         isActive={isActive}
+        observeSessionId={tab.observeSessionId}
       />
     )
   }
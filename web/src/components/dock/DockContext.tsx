@@ -11,6 +11,7 @@ export interface DockTab {
   podName?: string
   containerName?: string
   containers?: string[]
+  observeSessionId?: string // Watch a shared exec session read-only
   // Logs props
   // (namespace, podName, containers already covered)
   // Workload logs props
@@ -50,7 +51,8 @@ export function DockProvider({ children }: { children: ReactNode }) {
       }
       return t.namespace === tabData.namespace &&
              t.podName === tabData.podName &&
-             t.containerName === tabData.containerName
+             t.containerName === tabData.containerName &&
+             t.observeSessionId === tabData.observeSessionId
     })
 
     if (existingTab) {
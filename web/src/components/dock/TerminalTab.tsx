@@ -3,7 +3,7 @@ import { Terminal as XTerm } from '@xterm/xterm'
 import { FitAddon } from '@xterm/addon-fit'
 import { WebLinksAddon } from '@xterm/addon-web-links'
 import '@xterm/xterm/css/xterm.css'
-import { RefreshCw, ChevronDown, Bug } from 'lucide-react'
+import { RefreshCw, ChevronDown, Bug, Share2, Eye, Check, X } from 'lucide-react'
 import { clsx } from 'clsx'
 import { Tooltip } from '../ui/Tooltip'
 
@@ -13,14 +13,29 @@ interface TerminalTabProps {
   containerName: string
   containers: string[]
   isActive?: boolean
+  // Watch someone else's shared session read-only instead of starting a shell
+  observeSessionId?: string
+}
+
+interface ExecObserver {
+  id: string
+  name: string
+  approved: boolean
 }
 
 interface TerminalMessage {
-  type: 'input' | 'resize' | 'output' | 'error'
+  type:
+    | 'input' | 'resize' | 'output' | 'error'
+    // Session sharing, see internal/server/exec_share.go
+    | 'session' | 'share' | 'unshare' | 'approve' | 'deny' | 'revoke'
+    | 'observer_request' | 'observers' | 'pending' | 'approved' | 'denied' | 'ended'
   data?: string
-  errorType?: 'shell_not_found' | 'exec_error'
+  errorType?: 'shell_not_found' | 'exec_error' | 'not_shared' | 'session_limit' | 'idle_timeout' | 'closed_by_admin'
   rows?: number
   cols?: number
+  id?: string
+  name?: string
+  observers?: ExecObserver[]
 }
 
 export function TerminalTab({
@@ -29,7 +44,9 @@ export function TerminalTab({
   containerName,
   containers,
   isActive = true,
+  observeSessionId,
 }: TerminalTabProps) {
+  const isObserver = Boolean(observeSessionId)
   const terminalRef = useRef<HTMLDivElement>(null)
   const xtermRef = useRef<XTerm | null>(null)
   const fitAddonRef = useRef<FitAddon | null>(null)
@@ -40,6 +57,9 @@ export function TerminalTab({
   const [errorType, setErrorType] = useState<string | null>(null)
   const [isCreatingDebug, setIsCreatingDebug] = useState(false)
   const [selectedContainer, setSelectedContainer] = useState(containerName)
+  const [sessionId, setSessionId] = useState<string | null>(null)
+  const [isShared, setIsShared] = useState(false)
+  const [observers, setObservers] = useState<ExecObserver[]>([])
 
   const connect = useCallback(() => {
     if (!terminalRef.current) return
@@ -56,9 +76,14 @@ export function TerminalTab({
       wsRef.current.close()
     }
 
+    setSessionId(null)
+    setIsShared(false)
+    setObservers([])
+
     // Create terminal
     const xterm = new XTerm({
-      cursorBlink: true,
+      cursorBlink: !isObserver,
+      disableStdin: isObserver,
       fontFamily: 'JetBrains Mono, Menlo, Monaco, monospace',
       fontSize: 13,
       lineHeight: 1.2,
@@ -114,7 +139,9 @@ export function TerminalTab({
 
     // Connect WebSocket
     const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:'
-    const wsUrl = `${protocol}//${window.location.host}/api/pods/${namespace}/${podName}/exec?container=${selectedContainer}`
+    const wsUrl = observeSessionId
+      ? `${protocol}//${window.location.host}/api/exec/${encodeURIComponent(observeSessionId)}/observe`
+      : `${protocol}//${window.location.host}/api/pods/${namespace}/${podName}/exec?container=${selectedContainer}`
 
     const ws = new WebSocket(wsUrl)
     wsRef.current = ws
@@ -122,6 +149,7 @@ export function TerminalTab({
     ws.onopen = () => {
       setIsConnected(true)
       setIsConnecting(false)
+      if (isObserver) return
       xterm.focus()
 
       // Send initial size
@@ -138,6 +166,17 @@ export function TerminalTab({
         const msg: TerminalMessage = JSON.parse(event.data)
         if (msg.type === 'output' && msg.data) {
           xterm.write(msg.data)
+        } else if (msg.type === 'session' && msg.id) {
+          setSessionId(msg.id)
+        } else if (msg.type === 'observers') {
+          setObservers(msg.observers ?? [])
+        } else if (msg.type === 'pending') {
+          xterm.write('\x1b[33mWaiting for the session owner to approve...\x1b[0m\r\n')
+        } else if (msg.type === 'approved') {
+          xterm.write('\x1b[32mApproved, watching read-only\x1b[0m\r\n')
+        } else if (msg.type === 'denied' || msg.type === 'ended') {
+          const verb = msg.type === 'denied' ? 'Not approved' : 'Stopped watching'
+          xterm.write(`\r\n\x1b[31m${verb}${msg.data ? `: ${msg.data}` : ''}\x1b[0m\r\n`)
         } else if (msg.type === 'error' && msg.data) {
           setError(msg.data)
           setErrorType(msg.errorType || 'exec_error')
@@ -161,9 +200,9 @@ export function TerminalTab({
       xterm.write('\r\n\x1b[31mConnection closed\x1b[0m\r\n')
     }
 
-    // Handle input
+    // Handle input (observers are read-only)
     xterm.onData((data) => {
-      if (ws.readyState === WebSocket.OPEN) {
+      if (!isObserver && ws.readyState === WebSocket.OPEN) {
         const msg: TerminalMessage = { type: 'input', data }
         ws.send(JSON.stringify(msg))
       }
@@ -190,7 +229,7 @@ export function TerminalTab({
           if (dims) {
             xtermRef.current.resize(dims.cols, dims.rows)
           }
-          if (ws.readyState === WebSocket.OPEN) {
+          if (!isObserver && ws.readyState === WebSocket.OPEN) {
             const msg: TerminalMessage = {
               type: 'resize',
               rows: xtermRef.current.rows,
@@ -206,7 +245,24 @@ export function TerminalTab({
     return () => {
       resizeObserver.disconnect()
     }
-  }, [namespace, podName, selectedContainer])
+  }, [namespace, podName, selectedContainer, observeSessionId, isObserver])
+
+  const sendControl = useCallback((msg: TerminalMessage) => {
+    if (wsRef.current?.readyState === WebSocket.OPEN) {
+      wsRef.current.send(JSON.stringify(msg))
+    }
+  }, [])
+
+  // Sharing lets others watch this terminal read-only, each with the owner's approval
+  const toggleSharing = useCallback(() => {
+    const next = !isShared
+    sendControl({ type: next ? 'share' : 'unshare' })
+    setIsShared(next)
+    if (next && sessionId) {
+      const link = `${window.location.origin}${window.location.pathname}?observe=${encodeURIComponent(sessionId)}`
+      void navigator.clipboard?.writeText(link).catch(() => {})
+    }
+  }, [isShared, sendControl, sessionId])
 
   // Connect on mount and when container changes
   useEffect(() => {
@@ -284,8 +340,64 @@ export function TerminalTab({
         <span className="text-xs text-slate-400">
           {podName}
         </span>
+        {isObserver && (
+          <span className="flex items-center gap-1 text-xs text-amber-400">
+            <Eye className="w-3 h-3" />
+            Read-only
+          </span>
+        )}
+        {!isObserver && sessionId && isConnected && (
+          <Tooltip
+            content={isShared ? 'Stop sharing (disconnects observers)' : 'Share read-only: copies a link others can open to request access'}
+            position="bottom"
+          >
+            <button
+              onClick={toggleSharing}
+              className={clsx(
+                'flex items-center gap-1 px-2 py-0.5 text-xs rounded',
+                isShared ? 'text-blue-300 bg-blue-500/20 hover:bg-blue-500/30' : 'text-slate-400 hover:text-white hover:bg-slate-700'
+              )}
+            >
+              <Share2 className="w-3 h-3" />
+              {isShared ? `Sharing (${observers.filter((o) => o.approved).length})` : 'Share'}
+            </button>
+          </Tooltip>
+        )}
+        {observers.map((observer) =>
+          observer.approved ? (
+            <span key={observer.id} className="flex items-center gap-1 text-xs text-slate-400">
+              <Eye className="w-3 h-3" />
+              {observer.name}
+              <button
+                onClick={() => sendControl({ type: 'revoke', id: observer.id })}
+                className="hover:text-red-400"
+                title="Remove observer"
+              >
+                <X className="w-3 h-3" />
+              </button>
+            </span>
+          ) : (
+            <span key={observer.id} className="flex items-center gap-1 text-xs text-amber-300">
+              {observer.name} wants to watch
+              <button
+                onClick={() => sendControl({ type: 'approve', id: observer.id })}
+                className="p-0.5 rounded hover:bg-green-500/20 text-green-400"
+                title="Approve (read-only)"
+              >
+                <Check className="w-3 h-3" />
+              </button>
+              <button
+                onClick={() => sendControl({ type: 'deny', id: observer.id })}
+                className="p-0.5 rounded hover:bg-red-500/20 text-red-400"
+                title="Deny"
+              >
+                <X className="w-3 h-3" />
+              </button>
+            </span>
+          )
+        )}
 
-        {containers.length > 1 && (
+        {containers.length > 1 && !isObserver && (
           <div className="relative">
             <select
               value={selectedContainer}
@@ -352,7 +464,13 @@ export function TerminalTab({
             </>
           ) : (
             <>
-              <div className="text-red-400 mb-2 text-sm">Failed to connect</div>
+              <div className="text-red-400 mb-2 text-sm">
+                {errorType === 'idle_timeout' || errorType === 'closed_by_admin'
+                  ? 'Session closed'
+                  : errorType === 'session_limit'
+                    ? 'Too many terminal sessions'
+                    : 'Failed to connect'}
+              </div>
               <div className="text-xs text-slate-500 mb-3">{error}</div>
               <button
                 onClick={connect}
@@ -1,4 +1,4 @@
-import type { DashboardResponse, DashboardMetrics, DashboardCRDCount, DashboardProblem } from '../../api/client'
+import type { DashboardResponse, DashboardMetrics, DashboardRecommendations, DashboardCRDCount, DashboardProblem } from '../../api/client'
 import { HealthRing } from './HealthRing'
 import {
   AlertTriangle, CheckCircle, XCircle,
@@ -13,6 +13,7 @@ interface ClusterHealthCardProps {
   counts: DashboardResponse['resourceCounts']
   cluster: DashboardResponse['cluster']
   metrics: DashboardMetrics | null
+  recommendations?: DashboardRecommendations | null
   topCRDs?: DashboardCRDCount[] // Loaded lazily, may be undefined
   problems: DashboardProblem[]
   onNavigateToKind: (kind: string, group?: string) => void
@@ -59,6 +60,7 @@ export function ClusterHealthCard({
   counts,
   cluster,
   metrics,
+  recommendations,
   topCRDs: _topCRDs,
   problems,
   onNavigateToKind,
@@ -283,6 +285,17 @@ export function ClusterHealthCard({
               {!metrics?.cpu && !metrics?.memory && (
                 <span className="text-xs text-theme-text-tertiary">Metrics unavailable</span>
               )}
+              {recommendations && (recommendations.cpuSavingsCores > 0 || recommendations.memorySavingsGiB > 0) && (
+                <div
+                  className="text-xs text-theme-text-tertiary"
+                  title={recommendations.top.map(r => `${r.namespace}/${r.name}`).join(', ')}
+                >
+                  Right-sizing {recommendations.workloads} workload{recommendations.workloads === 1 ? '' : 's'} could free{' '}
+                  <span className="text-theme-text-secondary">
+                    {recommendations.cpuSavingsCores.toFixed(1)} cores / {recommendations.memorySavingsGiB.toFixed(1)} GiB
+                  </span>
+                </div>
+              )}
             </div>
 
           </div>
@@ -53,6 +53,7 @@ export function HomeView({ namespaces, topology, onNavigateToView, onNavigateToR
           counts={data.resourceCounts}
           cluster={data.cluster}
           metrics={data.metrics}
+          recommendations={data.recommendations}
           topCRDs={crdsData?.topCRDs}
           problems={data.problems ?? []}
           onNavigateToKind={onNavigateToResourceKind}
@@ -6,6 +6,7 @@ import {
   deletePodPath,
   downloadPodArchive,
   downloadPodFile,
+  getPodFileInfo,
   getPodFilesystem,
   isForbiddenError,
   mkdirPodPath,
@@ -13,6 +14,8 @@ import {
   savePodFile,
   searchPodFilesystem,
   uploadPodFile,
+  uploadPodFileChunked,
+  CHUNKED_UPLOAD_THRESHOLD,
   type PodFilesystemEntry,
 } from '../../api/client'
 
@@ -40,6 +43,7 @@ export function PodFilesystemModal({
   const [searching, setSearching] = useState(false)
   const [error, setError] = useState<string | null>(null)
   const [actionPath, setActionPath] = useState<string | null>(null)
+  const [uploadProgress, setUploadProgress] = useState<number | null>(null)
   const [searchQuery, setSearchQuery] = useState('')
   const [searchResults, setSearchResults] = useState<PodFilesystemEntry[]>([])
   const [editingEntry, setEditingEntry] = useState<PodFilesystemEntry | null>(null)
@@ -208,7 +212,13 @@ export function PodFilesystemModal({
     const destinationPath = currentPath === '/' ? `/${file.name}` : `${currentPath}/${file.name}`
     setActionPath(destinationPath)
     try {
-      await uploadPodFile(namespace, podName, containerName, destinationPath, file)
+      if (file.size > CHUNKED_UPLOAD_THRESHOLD) {
+        await uploadPodFileChunked(namespace, podName, containerName, destinationPath, file, {
+          onProgress: (state) => setUploadProgress(state.size > 0 ? Math.round((state.offset / state.size) * 100) : 100),
+        })
+      } else {
+        await uploadPodFile(namespace, podName, containerName, destinationPath, file)
+      }
       await loadPath(currentPath)
     } catch (err) {
       const message = err instanceof Error ? err.message : 'Upload failed'
@@ -220,6 +230,7 @@ export function PodFilesystemModal({
       }
     } finally {
       setActionPath(null)
+      setUploadProgress(null)
     }
   }
 
@@ -284,6 +295,10 @@ export function PodFilesystemModal({
     setEditingEntry(entry)
     setEditorLoading(true)
     try {
+      const info = await getPodFileInfo(namespace, podName, containerName, entry.path)
+      if (!info.editable) {
+        throw new Error(`Cannot edit ${entry.name}: ${info.reason}`)
+      }
       const blob = await downloadPodFile(namespace, podName, containerName, entry.path)
       const text = await blob.text()
       setEditorContent(text)
@@ -378,6 +393,9 @@ export function PodFilesystemModal({
           >
             <Upload className="w-4 h-4" />
           </button>
+          {uploadProgress !== null && (
+            <span className="text-xs text-theme-text-secondary tabular-nums">Uploading {uploadProgress}%</span>
+          )}
           <input
             ref={uploadInputRef}
             type="file"
@@ -1150,7 +1150,7 @@ function ResourceContent({ resource, data, relationships, onCopy, copied, onNavi
       {!isKnownKind && <GenericRenderer data={data} />}
 
       {/* Related Resources - clickable links to related items */}
-      <RelatedResourcesSection relationships={relationships} onNavigate={onNavigate} />
+      <RelatedResourcesSection relationships={relationships} onNavigate={onNavigate} getStatus={getResourceStatus} />
 
       {/* Related Events - valuable for debugging (skip for Event resources) */}
       {kind !== 'events' && <EventsSection events={events || []} isLoading={eventsLoading} />}
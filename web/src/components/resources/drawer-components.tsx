@@ -1,7 +1,7 @@
 import { useState } from 'react'
 import { ChevronDown, ChevronRight, Copy, Check, Tag, AlertTriangle } from 'lucide-react'
 import { clsx } from 'clsx'
-import { formatAge } from './resource-utils'
+import { formatAge, healthColors } from './resource-utils'
 
 // ============================================================================
 // UI COMPONENTS
@@ -328,13 +328,56 @@ export type CopyHandler = (text: string, key: string) => void
 import type { TimelineEvent, Relationships, ResourceRef } from '../../types'
 import { isChangeEvent, isK8sEvent } from '../../types'
 import { Link } from 'lucide-react'
+import { useResourcesBatch, type BatchGetResult } from '../../api/client'
+import { kindToPlural } from '../../utils/navigation'
+
+// Derives a status badge from a related resource's live object (plural kind)
+export type RelatedStatusFn = (kind: string, resource: unknown) => { text: string; color: string } | null
 
 interface RelatedResourcesSectionProps {
   relationships: Relationships | undefined
   onNavigate?: (ref: ResourceRef) => void
+  getStatus?: RelatedStatusFn
+}
+
+function relatedRefKey(ref: { kind: string; namespace?: string; name: string }): string {
+  return `${ref.kind}/${ref.namespace || ''}/${ref.name}`
+}
+
+function allRelatedRefs(relationships: Relationships | undefined): ResourceRef[] {
+  if (!relationships) return []
+  const refs: ResourceRef[] = [
+    ...(relationships.owner ? [relationships.owner] : []),
+    ...(relationships.children || []),
+    ...(relationships.services || []),
+    ...(relationships.ingresses || []),
+    ...(relationships.gateways || []),
+    ...(relationships.routes || []),
+    ...(relationships.pods || []),
+    ...(relationships.configRefs || []),
+    ...(relationships.hpa ? [relationships.hpa] : []),
+    ...(relationships.scaleTarget ? [relationships.scaleTarget] : []),
+  ]
+  const seen = new Set<string>()
+  return refs.filter((ref) => {
+    const key = relatedRefKey(ref)
+    if (seen.has(key)) return false
+    seen.add(key)
+    return true
+  })
 }
 
-export function RelatedResourcesSection({ relationships, onNavigate }: RelatedResourcesSectionProps) {
+export function RelatedResourcesSection({ relationships, onNavigate, getStatus }: RelatedResourcesSectionProps) {
+  // One batch request for every related resource instead of a GET per badge
+  const refs = getStatus ? allRelatedRefs(relationships) : []
+  const { data: batch } = useResourcesBatch(
+    refs.map((ref) => ({ kind: kindToPlural(ref.kind), namespace: ref.namespace, name: ref.name, group: ref.group }))
+  )
+  const results = new Map<string, BatchGetResult>()
+  batch?.forEach((result, i) => {
+    if (refs[i]) results.set(relatedRefKey(refs[i]), result)
+  })
+
   if (!relationships) return null
 
   const hasRelationships =
@@ -356,52 +399,52 @@ export function RelatedResourcesSection({ relationships, onNavigate }: RelatedRe
       <div className="space-y-3">
         {/* Owner (parent resource) */}
         {relationships.owner && (
-          <RelationshipGroup label="Owner" refs={[relationships.owner]} onNavigate={onNavigate} />
+          <RelationshipGroup label="Owner" refs={[relationships.owner]} onNavigate={onNavigate} results={results} getStatus={getStatus} />
         )}
 
         {/* Children (managed resources) */}
         {relationships.children && relationships.children.length > 0 && (
-          <RelationshipGroup label="Children" refs={relationships.children} onNavigate={onNavigate} />
+          <RelationshipGroup label="Children" refs={relationships.children} onNavigate={onNavigate} results={results} getStatus={getStatus} />
         )}
 
         {/* Services exposing this resource */}
         {relationships.services && relationships.services.length > 0 && (
-          <RelationshipGroup label="Services" refs={relationships.services} onNavigate={onNavigate} />
+          <RelationshipGroup label="Services" refs={relationships.services} onNavigate={onNavigate} results={results} getStatus={getStatus} />
         )}
 
         {/* Ingresses routing to this resource */}
         {relationships.ingresses && relationships.ingresses.length > 0 && (
-          <RelationshipGroup label="Ingresses" refs={relationships.ingresses} onNavigate={onNavigate} />
+          <RelationshipGroup label="Ingresses" refs={relationships.ingresses} onNavigate={onNavigate} results={results} getStatus={getStatus} />
         )}
 
         {/* Gateways routing to this resource */}
         {relationships.gateways && relationships.gateways.length > 0 && (
-          <RelationshipGroup label="Gateways" refs={relationships.gateways} onNavigate={onNavigate} />
+          <RelationshipGroup label="Gateways" refs={relationships.gateways} onNavigate={onNavigate} results={results} getStatus={getStatus} />
         )}
 
         {/* Routes attached to this Gateway */}
         {relationships.routes && relationships.routes.length > 0 && (
-          <RelationshipGroup label="Routes" refs={relationships.routes} onNavigate={onNavigate} />
+          <RelationshipGroup label="Routes" refs={relationships.routes} onNavigate={onNavigate} results={results} getStatus={getStatus} />
         )}
 
         {/* Pods selected/exposed by this Service */}
         {relationships.pods && relationships.pods.length > 0 && (
-          <RelationshipGroup label="Pods" refs={relationships.pods} onNavigate={onNavigate} />
+          <RelationshipGroup label="Pods" refs={relationships.pods} onNavigate={onNavigate} results={results} getStatus={getStatus} />
         )}
 
         {/* ConfigMaps/Secrets used */}
         {relationships.configRefs && relationships.configRefs.length > 0 && (
-          <RelationshipGroup label="Configuration" refs={relationships.configRefs} onNavigate={onNavigate} />
+          <RelationshipGroup label="Configuration" refs={relationships.configRefs} onNavigate={onNavigate} results={results} getStatus={getStatus} />
         )}
 
         {/* HPA scaling this workload */}
         {relationships.hpa && (
-          <RelationshipGroup label="Autoscaler" refs={[relationships.hpa]} onNavigate={onNavigate} />
+          <RelationshipGroup label="Autoscaler" refs={[relationships.hpa]} onNavigate={onNavigate} results={results} getStatus={getStatus} />
         )}
 
         {/* What this HPA scales */}
         {relationships.scaleTarget && (
-          <RelationshipGroup label="Scale Target" refs={[relationships.scaleTarget]} onNavigate={onNavigate} />
+          <RelationshipGroup label="Scale Target" refs={[relationships.scaleTarget]} onNavigate={onNavigate} results={results} getStatus={getStatus} />
         )}
       </div>
     </Section>
@@ -412,9 +455,11 @@ interface RelationshipGroupProps {
   label: string
   refs: ResourceRef[]
   onNavigate?: (ref: ResourceRef) => void
+  results?: Map<string, BatchGetResult>
+  getStatus?: RelatedStatusFn
 }
 
-function RelationshipGroup({ label, refs, onNavigate }: RelationshipGroupProps) {
+function RelationshipGroup({ label, refs, onNavigate, results, getStatus }: RelationshipGroupProps) {
   if (!refs || refs.length === 0) return null
 
   return (
@@ -422,13 +467,45 @@ function RelationshipGroup({ label, refs, onNavigate }: RelationshipGroupProps)
       <div className="text-xs text-theme-text-tertiary mb-1">{label}</div>
       <div className="flex flex-wrap gap-1">
         {refs.map((resourceRef, i) => (
-          <ResourceRefBadge key={`${resourceRef.kind}-${resourceRef.namespace}-${resourceRef.name}-${i}`} resourceRef={resourceRef} onClick={onNavigate} />
+          <RelatedRefBadge
+            key={`${resourceRef.kind}-${resourceRef.namespace}-${resourceRef.name}-${i}`}
+            resourceRef={resourceRef}
+            onClick={onNavigate}
+            result={results?.get(relatedRefKey(resourceRef))}
+            getStatus={getStatus}
+          />
         ))}
       </div>
     </div>
   )
 }
 
+interface RelatedRefBadgeProps {
+  resourceRef: ResourceRef
+  onClick?: (ref: ResourceRef) => void
+  result?: BatchGetResult
+  getStatus?: RelatedStatusFn
+}
+
+// A related resource badge followed by its live status, or "not found" when
+// the batch get says it no longer exists
+function RelatedRefBadge({ resourceRef, onClick, result, getStatus }: RelatedRefBadgeProps) {
+  let status: { text: string; color: string } | null = null
+  if (result?.status === 404) {
+    status = { text: 'Not found', color: healthColors.unknown }
+  } else if (result?.resource && getStatus) {
+    status = getStatus(kindToPlural(resourceRef.kind), result.resource)
+  }
+  if (!status) return <ResourceRefBadge resourceRef={resourceRef} onClick={onClick} />
+
+  return (
+    <span className="inline-flex items-center gap-1">
+      <ResourceRefBadge resourceRef={resourceRef} onClick={onClick} />
+      <span className={clsx('px-1.5 py-0.5 text-[10px] rounded', status.color)}>{status.text}</span>
+    </span>
+  )
+}
+
 export interface ResourceRefBadgeProps {
   resourceRef: ResourceRef
   onClick?: (ref: ResourceRef) => void
@@ -20,6 +20,22 @@ function formatStorage(value: string | undefined): string {
   return formatMemory(value)
 }
 
+// Mirrors ParseNodeCloudInfo in internal/k8s/cloud_metadata.go
+function getNodeCapacityType(labels: Record<string, string>): string | undefined {
+  if (labels['eks.amazonaws.com/capacityType']?.toUpperCase() === 'SPOT' ||
+      labels['karpenter.sh/capacity-type'] === 'spot' ||
+      labels['cloud.google.com/gke-spot'] === 'true' ||
+      labels['kubernetes.azure.com/scalesetpriority'] === 'spot') {
+    return 'Spot'
+  }
+  if (labels['cloud.google.com/gke-preemptible'] === 'true') return 'Preemptible'
+  if (labels['eks.amazonaws.com/capacityType'] || labels['karpenter.sh/capacity-type'] ||
+      labels['cloud.google.com/gke-nodepool'] || labels['kubernetes.azure.com/agentpool']) {
+    return 'On-demand'
+  }
+  return undefined
+}
+
 // Extract problems from node status and spec
 function getNodeProblems(data: any): string[] {
   const problems: string[] = []
@@ -81,9 +97,11 @@ export function NodeRenderer({ data }: NodeRendererProps) {
   const instanceType = labels['node.kubernetes.io/instance-type']
   const zone = labels['topology.kubernetes.io/zone']
   const region = labels['topology.kubernetes.io/region']
-  const nodePool = labels['cloud.google.com/gke-nodepool'] || labels['eks.amazonaws.com/nodegroup']
+  const nodePool = labels['cloud.google.com/gke-nodepool'] || labels['eks.amazonaws.com/nodegroup'] ||
+    labels['kubernetes.azure.com/agentpool'] || labels['karpenter.sh/nodepool']
   const machineFamily = labels['cloud.google.com/machine-family']
-  const hasPlatformInfo = instanceType || zone || region || nodePool || machineFamily
+  const capacityType = getNodeCapacityType(labels)
+  const hasPlatformInfo = instanceType || zone || region || nodePool || machineFamily || capacityType
 
   return (
     <>
@@ -244,6 +262,7 @@ export function NodeRenderer({ data }: NodeRendererProps) {
             <Property label="Region" value={region} />
             <Property label="Node Pool" value={nodePool} />
             <Property label="Machine Family" value={machineFamily} />
+            <Property label="Capacity" value={capacityType} />
           </PropertyList>
         </Section>
       )}
@@ -104,6 +104,8 @@ export const NODE_DIMENSIONS: Record<NodeKind, { width: number; height: number }
   ConfigMap: { width: 180, height: 48 },
   Secret: { width: 180, height: 48 },
   HorizontalPodAutoscaler: { width: 160, height: 48 },
+  ScaledObject: { width: 180, height: 56 },
+  ScaledJob: { width: 180, height: 56 },
   Job: { width: 180, height: 56 },
   CronJob: { width: 200, height: 56 },
   PersistentVolumeClaim: { width: 200, height: 48 },
@@ -207,6 +209,14 @@ function getSubtitle(kind: NodeKind, nodeData: Record<string, unknown>): string
       const current = nodeData.current ?? 0
       return `${current} (${min}-${max})`
     }
+    case 'ScaledObject':
+    case 'ScaledJob': {
+      const min = nodeData.minReplicas ?? 0
+      const max = nodeData.maxReplicas ?? 100
+      const state = nodeData.paused ? 'paused' : nodeData.active ? 'active' : 'idle'
+      const triggers = (nodeData.triggers as string[] | undefined) || []
+      return triggers.length ? `${state} (${min}-${max}) • ${triggers.join(', ')}` : `${state} (${min}-${max})`
+    }
     case 'ConfigMap':
       return `${nodeData.keys ?? 0} keys`
     case 'Secret':
@@ -52,6 +52,8 @@ const RESOURCE_KINDS: {
 
   // Scaling
   { kind: 'HorizontalPodAutoscaler', label: 'HPA', icon: getTopologyIcon('HorizontalPodAutoscaler'), color: 'text-pink-400', category: 'scaling' },
+  { kind: 'ScaledObject', label: 'ScaledObject', icon: getTopologyIcon('ScaledObject'), color: 'text-pink-400', category: 'scaling' },
+  { kind: 'ScaledJob', label: 'ScaledJob', icon: getTopologyIcon('ScaledJob'), color: 'text-pink-400', category: 'scaling' },
 ]
 
 const CATEGORIES = [
@@ -269,6 +269,8 @@ function pickGroupName(nodes: TopologyNode[]): string {
     'Secret': 10,
     'PersistentVolumeClaim': 10,
     'HorizontalPodAutoscaler': 10,
+    'ScaledObject': 10,
+    'ScaledJob': 10,
   }
 
   // Sort by priority and pick the first
@@ -0,0 +1,51 @@
+import { useQuery } from '@tanstack/react-query'
+import { Activity } from 'lucide-react'
+import { useConnection } from '../../context/ConnectionContext'
+
+interface ConnectionTransition {
+  time: string
+  timeUnix: number
+  context: string
+  from?: string
+  to: string
+  reason?: string
+  errorType?: string
+}
+
+interface ConnectionHistory {
+  context: string
+  flapping: boolean
+  transitions: ConnectionTransition[]
+}
+
+// ConnectionFlappingBanner says so explicitly when the API server keeps
+// dropping and coming back, instead of the UI just looking frozen at times
+export function ConnectionFlappingBanner() {
+  const { connection } = useConnection()
+  const { data } = useQuery<ConnectionHistory>({
+    queryKey: ['connection-history', connection.context],
+    queryFn: async () => {
+      const response = await fetch(`/api/connection/history?context=${encodeURIComponent(connection.context)}`)
+      if (!response.ok) throw new Error(`HTTP ${response.status}`)
+      return response.json()
+    },
+    refetchInterval: 30000,
+  })
+
+  const lastDrop = data?.transitions.filter(t => t.to === 'disconnected').at(-1)
+
+  return (
+    <div className="flex items-center gap-3 px-4 py-2 text-sm border-b bg-amber-500/10 border-amber-500/30 text-amber-400">
+      <Activity className="w-4 h-4 shrink-0" />
+      <span className="flex-1">
+        The connection to {connection.clusterName || connection.context} keeps dropping; data may lag behind while it
+        reconnects.
+        {lastDrop?.reason && (
+          <span className="text-theme-text-secondary">
+            {' '}Last drop at {new Date(lastDrop.time).toLocaleTimeString()}: {lastDrop.reason}
+          </span>
+        )}
+      </span>
+    </div>
+  )
+}
@@ -0,0 +1,54 @@
+import { useEffect, useState } from 'react'
+import { KeyRound, Loader2 } from 'lucide-react'
+import { useConnection } from '../../context/ConnectionContext'
+
+function formatRemaining(ms: number): string {
+  const total = Math.max(0, Math.round(ms / 1000))
+  const minutes = Math.floor(total / 60)
+  const seconds = total % 60
+  return minutes > 0 ? `${minutes}m ${seconds.toString().padStart(2, '0')}s` : `${seconds}s`
+}
+
+// CredentialExpiryBanner prompts for re-auth once the server reports the
+// cluster token is about to expire, counting down from tokenExpiry
+export function CredentialExpiryBanner() {
+  const { connection, refreshCredentials, isRefreshingCredentials } = useConnection()
+  const [now, setNow] = useState(() => Date.now())
+  const active = !!connection.tokenWarning && !!connection.tokenExpiry
+
+  useEffect(() => {
+    if (!active) return
+    const id = setInterval(() => setNow(Date.now()), 1000)
+    return () => clearInterval(id)
+  }, [active])
+
+  if (!active) return null
+
+  const remaining = new Date(connection.tokenExpiry!).getTime() - now
+  const expired = remaining <= 0
+
+  return (
+    <div
+      className={`flex items-center gap-3 px-4 py-2 text-sm border-b ${
+        expired
+          ? 'bg-red-500/10 border-red-500/30 text-red-400'
+          : 'bg-amber-500/10 border-amber-500/30 text-amber-400'
+      }`}
+    >
+      <KeyRound className="w-4 h-4 shrink-0" />
+      <span className="flex-1">
+        {expired
+          ? 'Cluster credentials have expired. Re-authenticate, then refresh credentials.'
+          : `Cluster credentials expire in ${formatRemaining(remaining)}. Re-authenticate if needed, then refresh credentials.`}
+      </span>
+      <button
+        onClick={refreshCredentials}
+        disabled={isRefreshingCredentials}
+        className="flex items-center gap-1.5 px-2.5 py-1 rounded bg-theme-elevated hover:bg-theme-hover text-theme-text-primary text-xs font-medium disabled:opacity-50"
+      >
+        {isRefreshingCredentials && <Loader2 className="w-3 h-3 animate-spin" />}
+        Refresh credentials
+      </button>
+    </div>
+  )
+}
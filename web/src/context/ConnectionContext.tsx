@@ -13,6 +13,9 @@ export interface ConnectionState {
   error?: string
   errorType?: string // auth, network, timeout, unknown
   progressMessage?: string
+  tokenExpiry?: string // RFC3339, when the cluster token expires (if known)
+  tokenWarning?: string // Set shortly before and after expiry
+  flapping?: boolean // The connection dropped repeatedly in the last few minutes
 }
 
 interface ConnectionStatusResponse extends ConnectionState {
@@ -24,6 +27,8 @@ interface ConnectionContextValue {
   contexts: ContextInfo[]
   retry: () => void
   isRetrying: boolean
+  refreshCredentials: () => void
+  isRefreshingCredentials: boolean
   updateFromSSE: (status: ConnectionState) => void
 }
 
@@ -46,6 +51,16 @@ async function retryConnection(): Promise<ConnectionState> {
   return response.json()
 }
 
+// Re-runs the kubeconfig exec plugin / reloads the token without reconnecting
+async function refreshCredentialsRequest(): Promise<{ connection: ConnectionState } | ConnectionState> {
+  const response = await fetch(`${API_BASE}/connection/refresh-credentials`, { method: 'POST' })
+  if (!response.ok) {
+    const error = await response.json().catch(() => ({ error: 'Unknown error' }))
+    throw new Error(error.error || `HTTP ${response.status}`)
+  }
+  return response.json()
+}
+
 export function ConnectionProvider({ children }: { children: ReactNode }) {
   const queryClient = useQueryClient()
   const [connection, setConnection] = useState<ConnectionState>({
@@ -82,6 +97,9 @@ export function ConnectionProvider({ children }: { children: ReactNode }) {
           error: data.error,
           errorType: data.errorType,
           progressMessage: data.progressMessage,
+          tokenExpiry: data.tokenExpiry,
+          tokenWarning: data.tokenWarning,
+          flapping: data.flapping,
         })
       }
     }
@@ -122,6 +140,19 @@ export function ConnectionProvider({ children }: { children: ReactNode }) {
     retryMutation.mutate()
   }, [retryMutation])
 
+  const refreshMutation = useMutation({
+    mutationFn: refreshCredentialsRequest,
+    onSuccess: (result) => {
+      // Falls back to a full reconnect when disconnected, which returns the status directly
+      setConnection('connection' in result ? result.connection : result)
+      queryClient.invalidateQueries()
+    },
+  })
+
+  const refreshCredentials = useCallback(() => {
+    refreshMutation.mutate()
+  }, [refreshMutation])
+
   // Handler for SSE connection_state events
   const updateFromSSE = useCallback((status: ConnectionState) => {
     // Mark SSE as active - it's now the authoritative source for connection state
@@ -139,6 +170,8 @@ export function ConnectionProvider({ children }: { children: ReactNode }) {
     contexts,
     retry,
     isRetrying: retryMutation.isPending,
+    refreshCredentials,
+    isRefreshingCredentials: refreshMutation.isPending,
     updateFromSSE,
   }
 
@@ -1,6 +1,7 @@
 import { useState, useEffect, useCallback, useRef } from 'react'
 import type { Topology, K8sEvent, ViewMode } from '../types'
 import type { ConnectionState } from '../context/ConnectionContext'
+import type { MaintenanceStatus } from '../api/client'
 
 interface UseEventSourceReturn {
   topology: Topology | null
@@ -14,6 +15,7 @@ interface UseEventSourceOptions {
   onContextSwitchProgress?: (message: string) => void
   onContextChanged?: (context: string) => void
   onConnectionStateChange?: (status: ConnectionState) => void
+  onMaintenanceProgress?: (status: MaintenanceStatus) => void
 }
 
 const MAX_EVENTS = 100 // Keep last 100 events
@@ -190,6 +192,16 @@ export function useEventSource(
       }
     })
 
+    // Handle node maintenance (cordon+drain) progress
+    es.addEventListener('maintenance_progress', (event) => {
+      try {
+        const data = JSON.parse(event.data) as MaintenanceStatus
+        optionsRef.current?.onMaintenanceProgress?.(data)
+      } catch (e) {
+        console.error('Failed to parse maintenance_progress event:', e)
+      }
+    })
+
     // Handle connection state events (for graceful startup)
     es.addEventListener('connection_state', (event) => {
       try {
@@ -29,6 +29,7 @@ export interface Capabilities {
   portForward: boolean // Port forwarding (pods/portforward)
   secrets: boolean     // List secrets
   helmWrite: boolean   // Helm write operations (install, upgrade, rollback, uninstall, apply values)
+  readOnly?: boolean   // Read-only mode (PUT /api/config); exec, port forward and Helm writes are off too
   resources?: ResourcePermissions // Per-resource-type permissions
 }
 
@@ -67,6 +68,8 @@ export type CoreNodeKind =
   | 'ConfigMap'
   | 'Secret'
   | 'HorizontalPodAutoscaler'
+  | 'ScaledObject' // KEDA ScaledObject
+  | 'ScaledJob' // KEDA ScaledJob
   | 'Job'
   | 'CronJob'
   | 'PersistentVolumeClaim'
@@ -114,6 +117,7 @@ export interface Topology {
   largeCluster?: boolean // True if cluster exceeds large cluster threshold
   hiddenKinds?: string[] // Resource kinds auto-hidden for performance
   crdDiscoveryStatus?: 'idle' | 'discovering' | 'ready' // CRD discovery status
+  at?: string // Set for historical topologies (?at=); restored nodes have data.historical
 }
 
 // K8s Event (from SSE stream)
@@ -150,11 +154,15 @@ export type EventSource = 'informer' | 'k8s_event' | 'historical'
 // Event types for the new timeline API
 export type EventType = 'add' | 'update' | 'delete' | 'Normal' | 'Warning'
 
+// Severity computed by the backend from event reasons and health transitions
+export type Severity = 'info' | 'warning' | 'critical'
+
 // Unified timeline event (from /api/changes and /api/timeline)
 // Uses the canonical format from timeline.TimelineEvent in the backend
 export interface TimelineEvent {
   id: string
   timestamp: string // ISO date string
+  timestampUnix: number // Epoch seconds
   source: EventSource // Where event originated: 'informer', 'k8s_event', 'historical'
 
   // Resource identity
@@ -162,10 +170,12 @@ export interface TimelineEvent {
   namespace: string
   name: string
   uid?: string
+  context?: string // Kubeconfig context the event was recorded in
 
   // Resource metadata - when the resource was actually created in K8s
   // This is different from timestamp which is when we observed the event
   createdAt?: string // ISO date string
+  createdAtUnix?: number
 
   // Event details
   eventType: EventType // 'add', 'update', 'delete', 'Normal', 'Warning'
@@ -175,6 +185,7 @@ export interface TimelineEvent {
   // Rich context
   diff?: DiffInfo
   healthState?: HealthStatus
+  severity?: Severity
   owner?: OwnerInfo
   labels?: Record<string, string> // For app-label grouping
 
@@ -185,6 +196,19 @@ export interface TimelineEvent {
   correlationId?: string
 }
 
+// Timeline filter preset (built-in or user-defined via /api/timeline/presets)
+export interface FilterPreset {
+  name: string
+  excludeKinds?: string[]
+  includeKinds?: string[]
+  excludeNamePatterns?: string[]
+  excludeOperations?: EventType[]
+  includeEventTypes?: EventType[]
+  includeSeverities?: Severity[]
+  includeManaged: boolean
+  builtin: boolean
+}
+
 // Helper to check if event is a change (vs K8s event)
 export function isChangeEvent(event: TimelineEvent): boolean {
   return event.source === 'informer' || event.source === 'historical'
@@ -245,6 +269,75 @@ export interface ClusterInfo {
   namespaceCount: number
   inCluster: boolean
   crdDiscoveryStatus?: 'idle' | 'discovering' | 'ready'
+  controlPlane?: ControlPlaneHealth
+  cloud?: CloudSummary
+}
+
+export type ControlPlaneStatus = 'healthy' | 'degraded' | 'unhealthy' | 'unknown'
+
+export interface ControlPlanePod {
+  name: string
+  node: string
+  ready: boolean
+  restarts: number
+}
+
+export interface ControlPlaneComponent {
+  name: 'apiserver' | 'etcd' | 'scheduler' | 'controller-manager'
+  status: ControlPlaneStatus
+  message?: string
+  sources: Array<'readyz' | 'pods' | 'componentstatus' | 'lease'>
+  pods?: ControlPlanePod[]
+  leader?: string
+}
+
+export interface ControlPlaneHealth {
+  selfManaged: boolean
+  components: ControlPlaneComponent[]
+}
+
+export type NodeCapacityType = 'on-demand' | 'spot' | 'preemptible'
+
+export interface NodeCloudInfo {
+  instanceType?: string
+  zone?: string
+  region?: string
+  nodeGroup?: string
+  capacityType?: NodeCapacityType
+}
+
+export interface NodeGroupSummary {
+  name: string // '' for nodes without a node group label
+  nodes: number
+  readyNodes: number
+  spotNodes: number
+  instanceTypes: Record<string, number>
+  zones: Record<string, number>
+}
+
+export interface AutoscalerEvent {
+  reason: string
+  message: string
+  kind: string
+  namespace?: string
+  name: string
+  count: number
+  lastSeen: string
+  warning: boolean
+}
+
+export interface CloudSummary {
+  nodeGroups: NodeGroupSummary[]
+  nodes: Record<string, NodeCloudInfo>
+  autoscaler: {
+    detected: boolean
+    scaleUps: number
+    scaleDowns: number
+    failures: number
+    lastScaleUp?: string
+    lastScaleDown?: string
+    recent: AutoscalerEvent[]
+  }
 }
 
 // Context info for context switching
@@ -328,6 +421,46 @@ export interface APIResource {
   namespaced: boolean
   isCrd: boolean
   verbs: string[]
+  shortNames?: string[]
+  watchable?: boolean // Supports list+watch
+  watched?: boolean // Radar has an informer running for this resource
+  watchSource?: 'typed' | 'dynamic'
+  synced?: boolean
+  count?: number // Cached instance count (only when synced)
+  printerColumns?: PrinterColumn[] // CRD additionalPrinterColumns (kubectl get columns)
+}
+
+// CRD additionalPrinterColumns entry
+export interface PrinterColumn {
+  name: string
+  type: 'integer' | 'number' | 'string' | 'boolean' | 'date'
+  format?: string
+  description?: string
+  jsonPath: string
+  priority?: number // > 0 only shown in wide output
+}
+
+// Summary list mode (?summary=true): printer column values computed server-side
+export interface ResourceSummaryList {
+  columns: PrinterColumn[]
+  rows: {
+    name: string
+    namespace?: string
+    createdAt: string
+    cells: unknown[] // One per column, null when the path is missing
+    managedBy?: ManagedBy
+  }[]
+}
+
+// The system managing a resource, resolved through its owners. List
+// endpoints add it to each item with ?managedBy=all, or filter by system
+// with e.g. ?managedBy=helm,unmanaged
+export interface ManagedBy {
+  system: 'helm' | 'argocd' | 'flux' | 'unmanaged'
+  kind?: string // HelmRelease, Application, Kustomization
+  group?: string
+  namespace?: string
+  name?: string
 }
 
 // Helm release types
@@ -436,6 +569,27 @@ export interface ApplyValuesRequest {
   values: Record<string, unknown>
 }
 
+// A values.schema.json violation; path is a JSON pointer into the values ("" = root)
+export interface ValuesViolation {
+  chart?: string
+  path: string
+  keyword: string // e.g. required, type, enum
+  message: string
+}
+
+export interface ValuesValidation {
+  valid: boolean
+  violations: ValuesViolation[]
+}
+
+// values.schema.json of a release's chart and its subcharts (keyed by values key)
+export interface HelmValuesSchema {
+  chart: string
+  version: string
+  schema?: Record<string, unknown>
+  subcharts?: Record<string, Record<string, unknown>>
+}
+
 // Response for previewing values changes
 export interface ValuesPreviewResponse {
   currentValues: Record<string, unknown>
@@ -454,6 +608,19 @@ export interface HelmRepository {
   lastUpdated?: string // ISO date string
 }
 
+// Stored OCI registry credentials (hosts and usernames only)
+export interface HelmRegistryLogins {
+  store: 'keychain' | 'file' // OS keychain or Helm's registry config
+  helper?: string // docker-credential helper, e.g. osxkeychain
+  registries: { host: string; username?: string }[]
+}
+
+export interface HelmRegistryLoginRequest {
+  host: string // Registry host or oci:// reference
+  username: string
+  password: string
+}
+
 // Basic chart information
 export interface ChartInfo {
   name: string
@@ -595,6 +762,9 @@ export interface TrafficEndpoint {
   labels?: Record<string, string>
   workload?: string
   port?: number
+  displayName?: string // Friendly label for external endpoints (e.g. "AWS S3", "node/ip-10-0-1-5")
+  hostname?: string // Reverse DNS hostname
+  category?: 'node' | 'pod' | 'service' | 'cloud' | 'private' | 'internet'
 }
 
 // Traffic flow between two endpoints
@@ -628,6 +798,10 @@ export interface AggregatedFlow {
   requestCount?: number
   errorCount?: number
   avgLatencyMs?: number
+  requestRate?: number
+  errorRate?: number
+  p95LatencyMs?: number
+  health?: 'healthy' | 'degraded' | 'unhealthy'
 }
 
 // Cluster info for traffic detection
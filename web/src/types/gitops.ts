@@ -5,10 +5,10 @@
 // ============================================================================
 
 /** GitOps tool identifier */
-export type GitOpsTool = 'argocd' | 'fluxcd'
+export type GitOpsTool = 'argocd' | 'fluxcd' | 'argo-workflows'
 
 /** GitOps operation types */
-export type GitOpsOperation = 'sync' | 'refresh' | 'terminate' | 'suspend' | 'resume' | 'reconcile'
+export type GitOpsOperation = 'sync' | 'refresh' | 'terminate' | 'suspend' | 'resume' | 'reconcile' | 'retry'
 
 /** Reference to a GitOps resource */
 export interface GitOpsResourceRef {
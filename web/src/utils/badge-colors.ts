@@ -29,6 +29,8 @@ export const KIND_BADGE_COLORS: Record<string, string> = {
 
   // Autoscaling & Storage
   HorizontalPodAutoscaler: 'bg-pink-500/15 text-pink-700 dark:bg-pink-900/50 dark:text-pink-300',
+  ScaledObject: 'bg-pink-500/15 text-pink-700 dark:bg-pink-900/50 dark:text-pink-300',
+  ScaledJob: 'bg-pink-500/15 text-pink-700 dark:bg-pink-900/50 dark:text-pink-300',
   PersistentVolumeClaim: 'bg-cyan-500/15 text-cyan-700 dark:bg-cyan-900/50 dark:text-cyan-300',
 
   // Special
@@ -66,6 +68,8 @@ export const KIND_BADGE_BORDERED: Record<string, string> = {
 
   // Autoscaling & Storage
   HorizontalPodAutoscaler: 'bg-pink-500/20 text-pink-700 dark:text-pink-300 border border-pink-500/30',
+  ScaledObject: 'bg-pink-500/20 text-pink-700 dark:text-pink-300 border border-pink-500/30',
+  ScaledJob: 'bg-pink-500/20 text-pink-700 dark:text-pink-300 border border-pink-500/30',
   PersistentVolumeClaim: 'bg-cyan-500/20 text-cyan-700 dark:text-cyan-300 border border-cyan-500/30',
 }
 
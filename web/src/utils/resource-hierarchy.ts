@@ -111,6 +111,7 @@ function nodeIdToLaneId(nodeId: string): string | null {
     persistentvolumeclaim: 'PersistentVolumeClaim',
     job: 'Job', cronjob: 'CronJob',
     horizontalpodautoscaler: 'HorizontalPodAutoscaler',
+    scaledobject: 'ScaledObject', scaledjob: 'ScaledJob',
     podgroup: 'PodGroup', rollout: 'Rollout', namespace: 'Namespace',
     application: 'Application', applicationset: 'ApplicationSet', appproject: 'AppProject',
     kustomization: 'Kustomization',
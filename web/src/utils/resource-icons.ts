@@ -90,6 +90,8 @@ const KIND_ICON_MAP: Record<string, LucideIcon> = {
   // Scaling
   horizontalpodautoscaler: Scaling,
   hpa: Scaling,
+  scaledobject: Scaling,
+  scaledjob: Scaling,
 
   // RBAC
   role: ShieldCheck,